@@ -6,21 +6,24 @@ import (
 	"time"
 
 	"github.com/getarcaneapp/arcane/backend/internal/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
 	ws "github.com/getarcaneapp/arcane/backend/internal/utils/ws"
 	"github.com/gin-gonic/gin"
 )
 
 type DiagnosticsHandler struct {
-	wsMetrics *WebSocketMetrics
+	wsMetrics    *WebSocketMetrics
+	eventService *services.EventService
 }
 
-func RegisterDiagnosticsRoutes(group *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware, wsMetrics *WebSocketMetrics) {
-	h := &DiagnosticsHandler{wsMetrics: wsMetrics}
+func RegisterDiagnosticsRoutes(group *gin.RouterGroup, authMiddleware *middleware.AuthMiddleware, wsMetrics *WebSocketMetrics, eventService *services.EventService) {
+	h := &DiagnosticsHandler{wsMetrics: wsMetrics, eventService: eventService}
 
 	diagnostics := group.Group("/diagnostics")
 	diagnostics.Use(authMiddleware.Add())
 	{
 		diagnostics.GET("/ws", h.WebSocketDiagnostics)
+		diagnostics.GET("/events", h.EventWriterDiagnostics)
 	}
 }
 
@@ -44,3 +47,21 @@ func (h *DiagnosticsHandler) WebSocketDiagnostics(c *gin.Context) {
 		"connections":       connections,
 	})
 }
+
+func (h *DiagnosticsHandler) EventWriterDiagnostics(c *gin.Context) {
+	isAdmin, _ := c.Get("userIsAdmin")
+	if admin, ok := isAdmin.(bool); !ok || !admin {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+		return
+	}
+
+	var stats services.EventWriterStats
+	if h.eventService != nil {
+		stats = h.eventService.AsyncWriterStats()
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"timestamp": time.Now().UTC().Format(time.RFC3339Nano),
+		"writer":    stats,
+	})
+}
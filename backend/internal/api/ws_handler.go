@@ -5,6 +5,7 @@ import (
 	"context"
 	"encoding/csv"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
@@ -21,6 +22,7 @@ import (
 	"github.com/getarcaneapp/arcane/backend/internal/common"
 	"github.com/getarcaneapp/arcane/backend/internal/config"
 	"github.com/getarcaneapp/arcane/backend/internal/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
 	"github.com/getarcaneapp/arcane/backend/internal/services"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/docker"
 	httputil "github.com/getarcaneapp/arcane/backend/internal/utils/http"
@@ -50,8 +52,14 @@ type WebSocketMetrics struct {
 	projectLogsActive   atomic.Int64
 	containerLogsActive atomic.Int64
 	containerStats      atomic.Int64
+	containerProcesses  atomic.Int64
 	containerExec       atomic.Int64
 	systemStats         atomic.Int64
+	backupProgress      atomic.Int64
+	projectWatch        atomic.Int64
+	mergedLogs          atomic.Int64
+	imageBuild          atomic.Int64
+	imageTransfer       atomic.Int64
 	seq                 atomic.Uint64
 	mu                  sync.RWMutex
 	connections         map[string]systemtypes.WebSocketConnectionInfo
@@ -70,8 +78,14 @@ func (m *WebSocketMetrics) Snapshot() systemtypes.WebSocketMetricsSnapshot {
 		ProjectLogsActive:   m.projectLogsActive.Load(),
 		ContainerLogsActive: m.containerLogsActive.Load(),
 		ContainerStats:      m.containerStats.Load(),
+		ContainerProcesses:  m.containerProcesses.Load(),
 		ContainerExec:       m.containerExec.Load(),
 		SystemStats:         m.systemStats.Load(),
+		BackupProgress:      m.backupProgress.Load(),
+		ProjectWatch:        m.projectWatch.Load(),
+		MergedLogs:          m.mergedLogs.Load(),
+		ImageBuild:          m.imageBuild.Load(),
+		ImageTransfer:       m.imageTransfer.Load(),
 	}
 }
 
@@ -128,10 +142,22 @@ func (m *WebSocketMetrics) applyDelta(kind string, delta int64) {
 		m.containerLogsActive.Add(delta)
 	case systemtypes.WSKindContainerStats:
 		m.containerStats.Add(delta)
+	case systemtypes.WSKindContainerProcesses:
+		m.containerProcesses.Add(delta)
 	case systemtypes.WSKindContainerExec:
 		m.containerExec.Add(delta)
 	case systemtypes.WSKindSystemStats:
 		m.systemStats.Add(delta)
+	case systemtypes.WSKindBackupProgress:
+		m.backupProgress.Add(delta)
+	case systemtypes.WSKindProjectWatch:
+		m.projectWatch.Add(delta)
+	case systemtypes.WSKindMergedLogs:
+		m.mergedLogs.Add(delta)
+	case systemtypes.WSKindImageBuild:
+		m.imageBuild.Add(delta)
+	case systemtypes.WSKindImageTransfer:
+		m.imageTransfer.Add(delta)
 	}
 }
 
@@ -152,6 +178,11 @@ type WebSocketHandler struct {
 	projectService    *services.ProjectService
 	containerService  *services.ContainerService
 	systemService     *services.SystemService
+	volumeService     *services.VolumeService
+	imageService      *services.ImageService
+	operationService  *services.OperationService
+	watchService      *services.ProjectWatchService
+	namespaceService  *services.NamespaceService
 	wsUpgrader        websocket.Upgrader
 	wsMetrics         *WebSocketMetrics
 	activeConnections sync.Map
@@ -195,6 +226,10 @@ func getContextUserIDInternal(c *gin.Context) string {
 }
 
 func buildWSConnectionInfoInternal(c *gin.Context, kind, resourceID string) systemtypes.WebSocketConnectionInfo {
+	transport := systemtypes.StreamTransportWebSocket
+	if c.GetBool(sseContextKey) {
+		transport = systemtypes.StreamTransportSSE
+	}
 	return systemtypes.WebSocketConnectionInfo{
 		Kind:       kind,
 		EnvID:      c.Param("id"),
@@ -202,7 +237,49 @@ func buildWSConnectionInfoInternal(c *gin.Context, kind, resourceID string) syst
 		ClientIP:   c.ClientIP(),
 		UserID:     getContextUserIDInternal(c),
 		UserAgent:  c.GetHeader("User-Agent"),
+		Transport:  transport,
+	}
+}
+
+// sseContextKey marks requests that came in on the server-sent events routes.
+const sseContextKey = "arcane.sse"
+
+// streamConnInternal is an accepted log or progress stream client: a
+// WebSocket, or a server-sent events response when conn is nil.
+type streamConnInternal struct {
+	c    *gin.Context
+	conn *websocket.Conn
+}
+
+// acceptStreamInternal upgrades the request to a WebSocket, or keeps it as a
+// server-sent events response when it came in on the /sse routes. It returns
+// false when the upgrade failed and the response has been written.
+func (h *WebSocketHandler) acceptStreamInternal(c *gin.Context) (*streamConnInternal, bool) {
+	if c.GetBool(sseContextKey) {
+		return &streamConnInternal{c: c}, true
+	}
+	conn, err := h.wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return nil, false
 	}
+	return &streamConnInternal{c: c, conn: conn}, true
+}
+
+// serve attaches the client to hub; onClose, if set, runs once the client
+// has left. WebSocket clients are served in the background with
+// context.Background() because they are long-lived and should not be tied to
+// the HTTP request context. SSE clients block until they leave, as the
+// response is only usable while the handler runs.
+func (s *streamConnInternal) serve(hub *ws.Hub, onClose func()) {
+	if s.conn == nil {
+		ws.ServeSSE(s.c.Request.Context(), hub, s.c.Writer, onClose)
+		return
+	}
+	if onClose == nil {
+		ws.ServeClient(context.Background(), hub, s.conn)
+		return
+	}
+	ws.ServeClientWithOnClose(context.Background(), hub, s.conn, onClose)
 }
 
 func NewWebSocketHandler(
@@ -210,6 +287,11 @@ func NewWebSocketHandler(
 	projectService *services.ProjectService,
 	containerService *services.ContainerService,
 	systemService *services.SystemService,
+	volumeService *services.VolumeService,
+	imageService *services.ImageService,
+	operationService *services.OperationService,
+	watchService *services.ProjectWatchService,
+	namespaceService *services.NamespaceService,
 	authMiddleware *middleware.AuthMiddleware,
 	cfg *config.Config,
 ) {
@@ -217,6 +299,11 @@ func NewWebSocketHandler(
 		projectService:       projectService,
 		containerService:     containerService,
 		systemService:        systemService,
+		volumeService:        volumeService,
+		imageService:         imageService,
+		operationService:     operationService,
+		watchService:         watchService,
+		namespaceService:     namespaceService,
 		wsMetrics:            defaultWebSocketMetrics,
 		gpuMonitoringEnabled: cfg.GPUMonitoringEnabled,
 		gpuType:              cfg.GPUType,
@@ -229,14 +316,80 @@ func NewWebSocketHandler(
 	}
 
 	wsGroup := group.Group("/environments/:id/ws")
-	wsGroup.Use(authMiddleware.WithAdminNotRequired().Add())
+	wsGroup.Use(authMiddleware.WithAdminNotRequired().Add(), handler.namespaceScope)
 	{
 		wsGroup.GET("/projects/:projectId/logs", handler.ProjectLogs)
+		wsGroup.GET("/projects/watch/progress", handler.ProjectWatchProgress)
 		wsGroup.GET("/containers/:containerId/logs", handler.ContainerLogs)
+		wsGroup.GET("/logs/merged", handler.MergedLogs)
 		wsGroup.GET("/containers/:containerId/stats", handler.ContainerStats)
+		wsGroup.GET("/containers/:containerId/top", handler.ContainerProcesses)
 		wsGroup.GET("/containers/:containerId/terminal", handler.ContainerExec)
 		wsGroup.GET("/system/stats", handler.SystemStats)
+		wsGroup.GET("/volumes/backups/progress", handler.VolumeBackupProgress)
+		wsGroup.GET("/images/build/progress", handler.ImageBuildProgress)
+		wsGroup.GET("/images/transfer/progress", handler.ImageTransferProgress)
+	}
+
+	// Server-sent events variants of the log and progress streams, for
+	// clients behind proxies that do not pass WebSockets through. They send
+	// the same payloads as their WebSocket counterparts.
+	sseGroup := group.Group("/environments/:id/sse")
+	sseGroup.Use(authMiddleware.WithAdminNotRequired().Add(), handler.namespaceScope, func(c *gin.Context) {
+		c.Set(sseContextKey, true)
+		c.Next()
+	})
+	{
+		sseGroup.GET("/projects/:projectId/logs", handler.ProjectLogs)
+		sseGroup.GET("/projects/watch/progress", handler.ProjectWatchProgress)
+		sseGroup.GET("/containers/:containerId/logs", handler.ContainerLogs)
+		sseGroup.GET("/logs/merged", handler.MergedLogs)
+		sseGroup.GET("/volumes/backups/progress", handler.VolumeBackupProgress)
+		sseGroup.GET("/images/build/progress", handler.ImageBuildProgress)
+		sseGroup.GET("/images/transfer/progress", handler.ImageTransferProgress)
+	}
+}
+
+// namespaceScope limits stream routes to the caller's namespaces, the same
+// way the Huma namespace middleware does for REST operations: merged streams
+// are filtered and single container or project streams outside the scope are
+// refused.
+func (h *WebSocketHandler) namespaceScope(c *gin.Context) {
+	userID := c.GetString("userID")
+	if h.namespaceService == nil || userID == "" {
+		c.Next()
+		return
+	}
+
+	ctx := c.Request.Context()
+	scope, err := h.namespaceService.ScopeForUser(ctx, userID, c.GetBool("userIsAdmin"))
+	if err != nil {
+		slog.ErrorContext(ctx, "Failed to resolve namespace scope", "user", userID, "error", err)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"success": false, "error": "failed to resolve namespace access"})
+		return
+	}
+	if scope == nil {
+		c.Next()
+		return
+	}
+
+	ctx = services.WithNamespaceScope(ctx, scope)
+	c.Request = c.Request.WithContext(ctx)
+	checks := []struct{ kind, id string }{
+		{services.NamespaceResourceContainer, c.Param("containerId")},
+		{services.NamespaceResourceProject, c.Param("projectId")},
+	}
+	for _, check := range checks {
+		if err := h.namespaceService.AuthorizeResource(ctx, check.kind, check.id); err != nil {
+			status := http.StatusInternalServerError
+			if errors.Is(err, services.ErrNamespaceForbidden) {
+				status = http.StatusForbidden
+			}
+			c.AbortWithStatusJSON(status, gin.H{"success": false, "error": err.Error()})
+			return
+		}
 	}
+	c.Next()
 }
 
 // ============================================================================
@@ -256,6 +409,13 @@ func NewWebSocketHandler(
 //	@Param			timestamps	query	bool	false	"Show timestamps"				default(false)
 //	@Param			format		query	string	false	"Output format (text or json)"	default(text)
 //	@Param			batched		query	bool	false	"Batch log messages"			default(false)
+//	@Param			search		query	string	false	"Only send lines containing this text"
+//	@Param			regex		query	bool	false	"Treat search as a regular expression"	default(false)
+//	@Param			caseSensitive	query	bool	false	"Match search case-sensitively"	default(false)
+//	@Param			levels		query	string	false	"Only send lines of these detected levels (comma separated: trace, debug, info, warn, error, fatal)"
+//	@Param			stream		query	string	false	"Only send stdout or stderr lines"
+//	@Param			detectLevel	query	bool	false	"Add the detected level to JSON messages as severity"	default(false)
+//	@Param			mode		query	string	false	"normalized trims messages, raw keeps ANSI codes and formatting, structured (JSON only) parses JSON messages into fields"	default(normalized)
 //	@Router			/api/environments/{id}/ws/projects/{projectId}/logs [get]
 func (h *WebSocketHandler) ProjectLogs(c *gin.Context) {
 	projectID := c.Param("projectId")
@@ -276,23 +436,57 @@ func (h *WebSocketHandler) ProjectLogs(c *gin.Context) {
 		format = "text"
 	}
 	batched := c.DefaultQuery("batched", "false") == "true"
-
-	conn, err := h.wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	filter, err := logFilterFromQueryInternal(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+	mode, err := ws.ParseLogMode(c.Query("mode"))
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+	if mode == ws.LogModeStructured {
+		format = "json"
+	}
+
+	stream, ok := h.acceptStreamInternal(c)
+	if !ok {
 		return
 	}
 
 	connID := h.wsMetrics.RegisterConnection(buildWSConnectionInfoInternal(c, systemtypes.WSKindProjectLogs, projectID))
-	hub := h.startProjectLogHub(projectID, format, batched, follow, tail, since, timestamps, func() {
+	hub := h.startProjectLogHub(projectID, format, mode, batched, follow, tail, since, timestamps, filter, func() {
 		h.wsMetrics.UnregisterConnection(connID)
 	})
-	// WebSocket connections use context.Background() because they are long-lived and should not
-	// be tied to the HTTP request context. Cleanup is handled via the hub's OnEmpty callback
-	// which triggers when all clients disconnect.
-	ws.ServeClient(context.Background(), hub, conn)
+	stream.serve(hub, nil)
 }
 
-func (h *WebSocketHandler) startProjectLogHub(projectID, format string, batched, follow bool, tail, since string, timestamps bool, onEmptyHook func()) *ws.Hub {
+// ProjectWatchProgress streams project watch events over WebSocket.
+//
+//	@Summary		Get project watch progress via WebSocket
+//	@Description	Stream file sync, restart and rebuild events of watched projects over WebSocket connection
+//	@Tags			WebSocket
+//	@Param			id	path	string	true	"Environment ID"
+//	@Router			/api/environments/{id}/ws/projects/watch/progress [get]
+func (h *WebSocketHandler) ProjectWatchProgress(c *gin.Context) {
+	if h.watchService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "service not available"})
+		return
+	}
+
+	stream, ok := h.acceptStreamInternal(c)
+	if !ok {
+		return
+	}
+
+	connID := h.wsMetrics.RegisterConnection(buildWSConnectionInfoInternal(c, systemtypes.WSKindProjectWatch, ""))
+	stream.serve(h.watchService.ProgressHub(), func() {
+		h.wsMetrics.UnregisterConnection(connID)
+	})
+}
+
+func (h *WebSocketHandler) startProjectLogHub(projectID, format, mode string, batched, follow bool, tail, since string, timestamps bool, filter *ws.LogFilter, onEmptyHook func()) *ws.Hub {
 	ls := &wsLogStream{
 		hub:    ws.NewHub(1024),
 		format: format,
@@ -322,19 +516,28 @@ func (h *WebSocketHandler) startProjectLogHub(projectID, format string, batched,
 		go func() {
 			defer close(msgs)
 			for line := range lines {
-				level, service, msg, ts := ws.NormalizeProjectLine(line)
+				level, service, msg, ts := ws.ParseProjectLine(line, mode)
+				severity, ok := filter.Match(level, msg)
+				if !ok {
+					continue
+				}
 				seq := ls.seq.Add(1)
 				timestamp := ts
 				if timestamp == "" {
 					timestamp = ws.NowRFC3339()
 				}
-				msgs <- ws.LogMessage{
+				m := ws.LogMessage{
 					Seq:       seq,
 					Level:     level,
+					Severity:  severity,
 					Message:   msg,
 					Service:   service,
 					Timestamp: timestamp,
 				}
+				if mode == ws.LogModeStructured {
+					m.Fields = ws.ParseLogFields(msg)
+				}
+				msgs <- m
 			}
 		}()
 		if batched {
@@ -347,7 +550,15 @@ func (h *WebSocketHandler) startProjectLogHub(projectID, format string, batched,
 		go func() {
 			defer close(cleanChan)
 			for line := range lines {
-				_, _, msg, _ := ws.NormalizeProjectLine(line)
+				level, _, msg, _ := ws.ParseProjectLine(line, mode)
+				if _, ok := filter.Match(level, msg); !ok {
+					continue
+				}
+				if mode == ws.LogModeRaw {
+					// Keep the service prefix as compose wrote it
+					cleanChan <- line
+					continue
+				}
 				cleanChan <- msg
 			}
 		}()
@@ -374,6 +585,13 @@ func (h *WebSocketHandler) startProjectLogHub(projectID, format string, batched,
 //	@Param			timestamps	query	bool	false	"Show timestamps"				default(false)
 //	@Param			format		query	string	false	"Output format (text or json)"	default(text)
 //	@Param			batched		query	bool	false	"Batch log messages"			default(false)
+//	@Param			search		query	string	false	"Only send lines containing this text"
+//	@Param			regex		query	bool	false	"Treat search as a regular expression"	default(false)
+//	@Param			caseSensitive	query	bool	false	"Match search case-sensitively"	default(false)
+//	@Param			levels		query	string	false	"Only send lines of these detected levels (comma separated: trace, debug, info, warn, error, fatal)"
+//	@Param			stream		query	string	false	"Only send stdout or stderr lines"
+//	@Param			detectLevel	query	bool	false	"Add the detected level to JSON messages as severity"	default(false)
+//	@Param			mode		query	string	false	"normalized trims messages, raw keeps ANSI codes and formatting, structured (JSON only) parses JSON messages into fields"	default(normalized)
 //	@Router			/api/environments/{id}/ws/containers/{containerId}/logs [get]
 func (h *WebSocketHandler) ContainerLogs(c *gin.Context) {
 	containerID := c.Param("containerId")
@@ -394,23 +612,33 @@ func (h *WebSocketHandler) ContainerLogs(c *gin.Context) {
 		format = "text"
 	}
 	batched := c.DefaultQuery("batched", "false") == "true"
-
-	conn, err := h.wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	filter, err := logFilterFromQueryInternal(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+	mode, err := ws.ParseLogMode(c.Query("mode"))
 	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+	if mode == ws.LogModeStructured {
+		format = "json"
+	}
+
+	stream, ok := h.acceptStreamInternal(c)
+	if !ok {
 		return
 	}
 
 	connID := h.wsMetrics.RegisterConnection(buildWSConnectionInfoInternal(c, systemtypes.WSKindContainerLogs, containerID))
-	hub := h.startContainerLogHub(containerID, format, batched, follow, tail, since, timestamps, func() {
+	hub := h.startContainerLogHub(containerID, format, mode, batched, follow, tail, since, timestamps, filter, func() {
 		h.wsMetrics.UnregisterConnection(connID)
 	})
-	// WebSocket connections use context.Background() because they are long-lived and should not
-	// be tied to the HTTP request context. Cleanup is handled via the hub's OnEmpty callback
-	// which triggers when all clients disconnect.
-	ws.ServeClient(context.Background(), hub, conn)
+	stream.serve(hub, nil)
 }
 
-func (h *WebSocketHandler) startContainerLogHub(containerID, format string, batched, follow bool, tail, since string, timestamps bool, onEmptyHook func()) *ws.Hub {
+func (h *WebSocketHandler) startContainerLogHub(containerID, format, mode string, batched, follow bool, tail, since string, timestamps bool, filter *ws.LogFilter, onEmptyHook func()) *ws.Hub {
 	ls := &wsLogStream{
 		hub:    ws.NewHub(1024),
 		format: format,
@@ -440,18 +668,27 @@ func (h *WebSocketHandler) startContainerLogHub(containerID, format string, batc
 		go func() {
 			defer close(msgs)
 			for line := range lines {
-				level, msg, ts := ws.NormalizeContainerLine(line)
+				level, msg, ts := ws.ParseContainerLine(line, mode)
+				severity, ok := filter.Match(level, msg)
+				if !ok {
+					continue
+				}
 				seq := ls.seq.Add(1)
 				timestamp := ts
 				if timestamp == "" {
 					timestamp = ws.NowRFC3339()
 				}
-				msgs <- ws.LogMessage{
+				m := ws.LogMessage{
 					Seq:       seq,
 					Level:     level,
+					Severity:  severity,
 					Message:   msg,
 					Timestamp: timestamp,
 				}
+				if mode == ws.LogModeStructured {
+					m.Fields = ws.ParseLogFields(msg)
+				}
+				msgs <- m
 			}
 		}()
 		if batched {
@@ -459,6 +696,23 @@ func (h *WebSocketHandler) startContainerLogHub(containerID, format string, batc
 		} else {
 			go ws.ForwardLogJSON(ctx, ls.hub, msgs)
 		}
+	} else if filter != nil || mode == ws.LogModeRaw {
+		filtered := make(chan string, 256)
+		go func() {
+			defer close(filtered)
+			for line := range lines {
+				level, msg, _ := ws.ParseContainerLine(line, mode)
+				if _, ok := filter.Match(level, msg); !ok {
+					continue
+				}
+				if mode == ws.LogModeRaw {
+					// Drop the stream prefix added while demultiplexing
+					line = strings.TrimPrefix(line, "[STDERR] ")
+				}
+				filtered <- line
+			}
+		}()
+		go ws.ForwardLines(ctx, ls.hub, filtered)
 	} else {
 		go ws.ForwardLines(ctx, ls.hub, lines)
 	}
@@ -466,6 +720,189 @@ func (h *WebSocketHandler) startContainerLogHub(containerID, format string, batc
 	return ls.hub
 }
 
+// logFilterFromQueryInternal reads the log filter parameters of a log
+// stream request. Lines are filtered before they are broadcast.
+func logFilterFromQueryInternal(c *gin.Context) (*ws.LogFilter, error) {
+	var levels []string
+	for _, v := range c.QueryArray("levels") {
+		levels = append(levels, strings.Split(v, ",")...)
+	}
+	return ws.NewLogFilter(ws.LogFilterOptions{
+		Search:        c.Query("search"),
+		Regex:         c.DefaultQuery("regex", "false") == "true",
+		CaseSensitive: c.DefaultQuery("caseSensitive", "false") == "true",
+		Levels:        levels,
+		Stream:        c.Query("stream"),
+		DetectLevel:   c.DefaultQuery("detectLevel", "false") == "true",
+	})
+}
+
+// maxMergedLogContainers caps the containers of one merged log stream.
+const maxMergedLogContainers = 25
+
+// mergedLogWindow is how long merged lines are held back for ordering.
+const mergedLogWindow = 500 * time.Millisecond
+
+// MergedLogs streams the logs of several containers merged over WebSocket.
+//
+//	@Summary		Get merged logs of several containers via WebSocket
+//	@Description	Stream the logs of selected containers, or of all containers of a project, as one JSON stream ordered by timestamp. Every message carries the container it came from.
+//	@Tags			WebSocket
+//	@Param			id			path	string	true	"Environment ID"
+//	@Param			containers	query	string	false	"Container IDs or names (comma separated)"
+//	@Param			project		query	string	false	"Project ID whose containers are merged"
+//	@Param			follow		query	bool	false	"Follow log output"						default(true)
+//	@Param			tail		query	string	false	"Number of lines to show from the end of each container"	default(100)
+//	@Param			since		query	string	false	"Show logs since timestamp"
+//	@Param			batched		query	bool	false	"Batch log messages"			default(false)
+//	@Param			search		query	string	false	"Only send lines containing this text"
+//	@Param			regex		query	bool	false	"Treat search as a regular expression"	default(false)
+//	@Param			caseSensitive	query	bool	false	"Match search case-sensitively"	default(false)
+//	@Param			levels		query	string	false	"Only send lines of these detected levels (comma separated: trace, debug, info, warn, error, fatal)"
+//	@Param			stream		query	string	false	"Only send stdout or stderr lines"
+//	@Param			detectLevel	query	bool	false	"Add the detected level to JSON messages as severity"	default(false)
+//	@Router			/api/environments/{id}/ws/logs/merged [get]
+func (h *WebSocketHandler) MergedLogs(c *gin.Context) {
+	follow := c.DefaultQuery("follow", "true") == "true"
+	tail, _ := httputil.GetQueryParam(c, "tail", false)
+	if tail == "" {
+		tail = "100"
+	}
+	since, _ := httputil.GetQueryParam(c, "since", false)
+	batched := c.DefaultQuery("batched", "false") == "true"
+	filter, err := logFilterFromQueryInternal(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	targets, status, err := h.resolveMergedLogTargetsInternal(c)
+	if err != nil {
+		c.JSON(status, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	stream, ok := h.acceptStreamInternal(c)
+	if !ok {
+		return
+	}
+
+	resourceIDs := make([]string, len(targets))
+	for i, t := range targets {
+		resourceIDs[i] = t.ContainerID
+	}
+	connID := h.wsMetrics.RegisterConnection(buildWSConnectionInfoInternal(c, systemtypes.WSKindMergedLogs, strings.Join(resourceIDs, ",")))
+	hub := h.startMergedLogHub(targets, batched, follow, tail, since, filter, func() {
+		h.wsMetrics.UnregisterConnection(connID)
+	})
+	stream.serve(hub, nil)
+}
+
+// resolveMergedLogTargetsInternal returns the containers of a merged log
+// stream, from the containers or the project query parameter, with the
+// HTTP status to answer with on error.
+func (h *WebSocketHandler) resolveMergedLogTargetsInternal(c *gin.Context) ([]ws.LogSource, int, error) {
+	ctx := c.Request.Context()
+	var refs []string
+	for _, v := range c.QueryArray("containers") {
+		for ref := range strings.SplitSeq(v, ",") {
+			if ref = strings.TrimSpace(ref); ref != "" {
+				refs = append(refs, ref)
+			}
+		}
+	}
+	projectID := strings.TrimSpace(c.Query("project"))
+
+	var targets []ws.LogSource
+	switch {
+	case projectID != "" && len(refs) > 0:
+		return nil, http.StatusBadRequest, errors.New("containers and project cannot be combined")
+	case projectID != "":
+		svcs, err := h.projectService.GetProjectServices(ctx, projectID)
+		if err != nil {
+			return nil, http.StatusNotFound, err
+		}
+		for _, svc := range svcs {
+			if svc.ContainerID == "" {
+				continue
+			}
+			targets = append(targets, ws.LogSource{
+				ContainerID: svc.ContainerID,
+				Container:   strings.TrimPrefix(svc.ContainerName, "/"),
+				Service:     svc.Name,
+			})
+		}
+		if len(targets) == 0 {
+			return nil, http.StatusNotFound, errors.New("project has no containers")
+		}
+	case len(refs) > 0:
+		seen := map[string]bool{}
+		for _, ref := range refs {
+			inspect, err := h.containerService.GetContainerByID(ctx, ref)
+			if err != nil {
+				return nil, http.StatusNotFound, err
+			}
+			if seen[inspect.ID] {
+				continue
+			}
+			seen[inspect.ID] = true
+			target := ws.LogSource{
+				ContainerID: inspect.ID,
+				Container:   strings.TrimPrefix(inspect.Name, "/"),
+			}
+			if inspect.Config != nil {
+				target.Service = inspect.Config.Labels["com.docker.compose.service"]
+			}
+			targets = append(targets, target)
+		}
+	default:
+		return nil, http.StatusBadRequest, errors.New("containers or project is required")
+	}
+
+	if len(targets) > maxMergedLogContainers {
+		return nil, http.StatusBadRequest, fmt.Errorf("at most %d containers can be merged", maxMergedLogContainers)
+	}
+	return targets, http.StatusOK, nil
+}
+
+func (h *WebSocketHandler) startMergedLogHub(targets []ws.LogSource, batched, follow bool, tail, since string, filter *ws.LogFilter, onEmptyHook func()) *ws.Hub {
+	hub := ws.NewHub(1024)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	hub.SetOnEmpty(func() {
+		if onEmptyHook != nil {
+			onEmptyHook()
+		}
+		slog.Debug("client disconnected, cleaning up merged log hub", "containers", len(targets))
+		cancel()
+	})
+
+	go hub.Run(ctx)
+
+	sources := make([]ws.LogSource, len(targets))
+	for i, target := range targets {
+		lines := make(chan string, 256)
+		go func(ctx context.Context, containerID string) {
+			defer close(lines)
+			// Timestamps are always requested; they are what lines are ordered by.
+			_ = h.containerService.StreamLogs(ctx, containerID, lines, follow, tail, since, true)
+		}(ctx, target.ContainerID)
+		target.Lines = lines
+		sources[i] = target
+	}
+
+	msgs := make(chan ws.LogMessage, 256)
+	go ws.MergeLogSources(ctx, sources, mergedLogWindow, filter, msgs)
+	if batched {
+		go ws.ForwardLogJSONBatched(ctx, hub, msgs, 50, 400*time.Millisecond)
+	} else {
+		go ws.ForwardLogJSON(ctx, hub, msgs)
+	}
+
+	return hub
+}
+
 // ContainerStats streams container stats over WebSocket.
 //
 //	@Summary		Get container stats via WebSocket
@@ -536,6 +973,104 @@ func (h *WebSocketHandler) startContainerStatsHub(containerID string, onEmptyHoo
 	return hub
 }
 
+// ContainerProcesses streams the process table of a container over WebSocket,
+// refreshed every interval seconds.
+//
+//	@Summary		Get container processes via WebSocket
+//	@Description	Stream the container process table (docker top) over WebSocket connection
+//	@Tags			WebSocket
+//	@Param			id			path	string	true	"Environment ID"
+//	@Param			containerId	path	string	true	"Container ID"
+//	@Param			interval	query	int		false	"Refresh interval in seconds (1-60)"	default(2)
+//	@Param			psArgs		query	string	false	"Arguments passed to ps on the Docker host"
+//	@Router			/api/environments/{id}/ws/containers/{containerId}/top [get]
+func (h *WebSocketHandler) ContainerProcesses(c *gin.Context) {
+	containerID := c.Param("containerId")
+	if strings.TrimSpace(containerID) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": (&common.ContainerIDRequiredError{}).Error()})
+		return
+	}
+
+	psArgs := c.Query("psArgs")
+	ctx := c.Request.Context()
+	if _, err := h.containerService.GetContainerProcesses(ctx, containerID, psArgs); errors.Is(err, services.ErrInvalidPsArgs) {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	interval, _ := httputil.GetIntQueryParam(c, "interval", false)
+	interval = min(max(interval, 0), 60)
+	if interval == 0 {
+		interval = 2
+	}
+
+	conn, err := h.wsUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		return
+	}
+
+	connID := h.wsMetrics.RegisterConnection(buildWSConnectionInfoInternal(c, systemtypes.WSKindContainerProcesses, containerID))
+	hub := h.startContainerProcessesHub(containerID, psArgs, time.Duration(interval)*time.Second, func() {
+		h.wsMetrics.UnregisterConnection(connID)
+	})
+	ws.ServeClient(context.Background(), hub, conn)
+}
+
+func (h *WebSocketHandler) startContainerProcessesHub(containerID, psArgs string, interval time.Duration, onEmptyHook func()) *ws.Hub {
+	hub := ws.NewHub(16)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	hub.SetOnEmpty(func() {
+		if onEmptyHook != nil {
+			onEmptyHook()
+		}
+		slog.Debug("client disconnected, cleaning up container processes hub", "containerID", containerID)
+		cancel()
+	})
+
+	go hub.Run(ctx)
+
+	go func() {
+		// Give the first client a moment to register so it gets the initial
+		// table instead of waiting a full interval.
+		for wait := 0; hub.ClientCount() == 0 && wait < 20; wait++ {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(50 * time.Millisecond):
+			}
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			// A stopped container reports an error until it runs again.
+			var payload any
+			processes, err := h.containerService.GetContainerProcesses(ctx, containerID, psArgs)
+			if err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				payload = gin.H{"error": err.Error()}
+			} else {
+				payload = processes
+			}
+			if b, err := json.Marshal(payload); err == nil {
+				hub.Broadcast(b)
+			}
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	return hub
+}
+
 // ContainerExec provides interactive terminal access to a container.
 //
 //	@Summary		Execute command in container via WebSocket
@@ -544,6 +1079,11 @@ func (h *WebSocketHandler) startContainerStatsHub(containerID string, onEmptyHoo
 //	@Param			id			path	string	true	"Environment ID"
 //	@Param			containerId	path	string	true	"Container ID"
 //	@Param			shell		query	string	false	"Shell to execute"	default(/bin/sh)
+//	@Param			rows		query	int		false	"Initial terminal rows"
+//	@Param			cols		query	int		false	"Initial terminal columns"
+//	@Param			user		query	string	false	"User (and optional group) to run the shell as"
+//	@Param			workdir		query	string	false	"Absolute directory to start the shell in"
+//	@Param			env			query	[]string	false	"Extra KEY=VALUE environment variables"	collectionFormat(multi)
 //	@Router			/api/environments/{id}/ws/containers/{containerId}/terminal [get]
 func (h *WebSocketHandler) ContainerExec(c *gin.Context) {
 	containerID := c.Param("containerId")
@@ -553,6 +1093,25 @@ func (h *WebSocketHandler) ContainerExec(c *gin.Context) {
 	}
 
 	shell := c.DefaultQuery("shell", "/bin/sh")
+	rows, _ := strconv.ParseUint(c.Query("rows"), 10, 16)
+	cols, _ := strconv.ParseUint(c.Query("cols"), 10, 16)
+	execOpts := services.ExecOptions{
+		User:       strings.TrimSpace(c.Query("user")),
+		WorkingDir: strings.TrimSpace(c.Query("workdir")),
+		Env:        c.QueryArray("env"),
+	}
+	if err := execOpts.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+
+	// Shutdown waits for open exec sessions, so refuse new ones while draining.
+	done, err := h.operationService.Track(models.OperationKindContainerExec, containerID)
+	if err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"success": false, "error": err.Error()})
+		return
+	}
+	defer done()
 
 	conn, err := h.wsUpgrader.Upgrade(c.Writer, c.Request, nil)
 	if err != nil {
@@ -562,15 +1121,34 @@ func (h *WebSocketHandler) ContainerExec(c *gin.Context) {
 	defer h.wsMetrics.UnregisterConnection(connID)
 	defer conn.Close()
 
+	var user models.User
+	if currentUser, ok := c.Get("currentUser"); ok {
+		if u, ok := currentUser.(*models.User); ok && u != nil {
+			user = *u
+		}
+	}
+
 	ctx, cancel := context.WithCancel(c.Request.Context())
 	defer cancel()
 
-	h.runContainerExecInternal(ctx, cancel, conn, containerID, shell)
+	h.runContainerExecInternal(ctx, cancel, conn, containerID, shell, uint(rows), uint(cols), execOpts, user)
 }
 
-func (h *WebSocketHandler) runContainerExecInternal(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn, containerID, shell string) {
+// execControlMessage is a JSON control message on the container terminal
+// WebSocket. The server sends {"type":"exec","execId":"..."} once the session
+// has started and clients send {"type":"resize","rows":24,"cols":80} whenever
+// the terminal size changes. Terminal input is sent as binary messages; text
+// messages that are not control messages are input from older clients.
+type execControlMessage struct {
+	Type   string `json:"type"`
+	ExecID string `json:"execId,omitempty"`
+	Rows   uint   `json:"rows,omitempty"`
+	Cols   uint   `json:"cols,omitempty"`
+}
+
+func (h *WebSocketHandler) runContainerExecInternal(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn, containerID, shell string, rows, cols uint, execOpts services.ExecOptions, user models.User) {
 	// Create exec instance
-	execID, err := h.containerService.CreateExec(ctx, containerID, []string{shell})
+	execID, err := h.containerService.CreateExec(ctx, containerID, []string{shell}, rows, cols, execOpts)
 	if err != nil {
 		h.writeExecErrorInternal(conn, &common.ExecCreationError{Err: err})
 		return
@@ -582,13 +1160,22 @@ func (h *WebSocketHandler) runContainerExecInternal(ctx context.Context, cancel
 		h.writeExecErrorInternal(conn, &common.ExecAttachError{Err: err})
 		return
 	}
+	audit := h.containerService.StartExecAudit(ctx, containerID, execID, []string{shell}, execOpts, user)
+	// The session usually ends because the request was canceled, so record
+	// its end without that cancellation.
+	defer audit.End(context.WithoutCancel(ctx))
 	cleanup := h.execCleanupFuncInternal(ctx, execSession, execID, containerID)
 	defer cleanup()
 	h.watchExecContextInternal(ctx, execID, containerID, cleanup)
 
+	if err := conn.WriteJSON(execControlMessage{Type: "exec", ExecID: execID}); err != nil {
+		slog.Debug("Exec websocket write error", "execID", execID, "containerID", containerID, "error", err)
+		return
+	}
+
 	done := make(chan struct{})
-	go h.pipeExecOutputInternal(ctx, conn, execSession.Stdout(), execID, containerID, done)
-	go h.pipeExecInputInternal(ctx, cancel, conn, execSession.Stdin(), execID, containerID)
+	go h.pipeExecOutputInternal(ctx, conn, io.TeeReader(execSession.Stdout(), audit.Output()), execID, containerID, done)
+	go h.pipeExecInputInternal(ctx, cancel, conn, execSession, audit, execID, containerID)
 
 	<-done
 }
@@ -641,7 +1228,7 @@ func (h *WebSocketHandler) pipeExecOutputInternal(ctx context.Context, conn *web
 	}
 }
 
-func (h *WebSocketHandler) pipeExecInputInternal(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn, stdin io.Writer, execID, containerID string) {
+func (h *WebSocketHandler) pipeExecInputInternal(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn, execSession *services.ExecSession, audit *services.ExecAudit, execID, containerID string) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -649,19 +1236,41 @@ func (h *WebSocketHandler) pipeExecInputInternal(ctx context.Context, cancel con
 		default:
 		}
 
-		_, data, err := conn.ReadMessage()
+		msgType, data, err := conn.ReadMessage()
 		if err != nil {
 			slog.Debug("Exec websocket read error", "execID", execID, "containerID", containerID, "error", err)
 			cancel()
 			return
 		}
-		if _, err := stdin.Write(data); err != nil {
+		if msgType == websocket.TextMessage {
+			if msg, ok := parseExecControlMessageInternal(data); ok {
+				if err := execSession.Resize(ctx, msg.Rows, msg.Cols); err != nil {
+					slog.Debug("Exec resize failed", "execID", execID, "containerID", containerID, "error", err)
+				}
+				continue
+			}
+		}
+		audit.RecordInput(data)
+		if _, err := execSession.Stdin().Write(data); err != nil {
 			slog.Debug("Exec stdin write error", "execID", execID, "containerID", containerID, "error", err)
 			return
 		}
 	}
 }
 
+// parseExecControlMessageInternal returns the resize message in data, if data
+// is one.
+func parseExecControlMessageInternal(data []byte) (execControlMessage, bool) {
+	var msg execControlMessage
+	if !bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		return msg, false
+	}
+	if err := json.Unmarshal(data, &msg); err != nil || msg.Type != "resize" {
+		return msg, false
+	}
+	return msg, true
+}
+
 // ============================================================================
 // System WebSocket Endpoints
 // ============================================================================
@@ -913,6 +1522,74 @@ func (h *WebSocketHandler) SystemStats(c *gin.Context) {
 
 // readSystemStatsPumpInternal is the single reader for the SystemStats websocket.
 // Do not add additional readers for this connection.
+// ============================================================================
+// Volume WebSocket Endpoints
+// ============================================================================
+
+// VolumeBackupProgress streams volume backup and restore progress over WebSocket.
+//
+//	@Summary		Get volume backup progress via WebSocket
+//	@Description	Stream progress events for running volume backups and restores over WebSocket connection
+//	@Tags			WebSocket
+//	@Param			id	path	string	true	"Environment ID"
+//	@Router			/api/environments/{id}/ws/volumes/backups/progress [get]
+func (h *WebSocketHandler) VolumeBackupProgress(c *gin.Context) {
+	if h.volumeService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "service not available"})
+		return
+	}
+
+	stream, ok := h.acceptStreamInternal(c)
+	if !ok {
+		return
+	}
+
+	connID := h.wsMetrics.RegisterConnection(buildWSConnectionInfoInternal(c, systemtypes.WSKindBackupProgress, ""))
+	// The progress hub is shared and outlives its clients, so the connection
+	// is unregistered when this client leaves rather than when the hub empties.
+	stream.serve(h.volumeService.BackupProgressHub(), func() {
+		h.wsMetrics.UnregisterConnection(connID)
+	})
+}
+
+// ImageBuildProgress streams the output of running image builds. Every
+// message carries the build ID it belongs to.
+func (h *WebSocketHandler) ImageBuildProgress(c *gin.Context) {
+	if h.imageService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "service not available"})
+		return
+	}
+
+	stream, ok := h.acceptStreamInternal(c)
+	if !ok {
+		return
+	}
+
+	connID := h.wsMetrics.RegisterConnection(buildWSConnectionInfoInternal(c, systemtypes.WSKindImageBuild, ""))
+	stream.serve(h.imageService.BuildProgressHub(), func() {
+		h.wsMetrics.UnregisterConnection(connID)
+	})
+}
+
+// ImageTransferProgress streams the progress of image exports and uploads.
+// Every message carries the transfer ID it belongs to.
+func (h *WebSocketHandler) ImageTransferProgress(c *gin.Context) {
+	if h.imageService == nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"success": false, "error": "service not available"})
+		return
+	}
+
+	stream, ok := h.acceptStreamInternal(c)
+	if !ok {
+		return
+	}
+
+	connID := h.wsMetrics.RegisterConnection(buildWSConnectionInfoInternal(c, systemtypes.WSKindImageTransfer, ""))
+	stream.serve(h.imageService.TransferProgressHub(), func() {
+		h.wsMetrics.UnregisterConnection(connID)
+	})
+}
+
 func (h *WebSocketHandler) readSystemStatsPumpInternal(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn) {
 	for {
 		select {
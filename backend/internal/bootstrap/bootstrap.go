@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -16,10 +17,12 @@ import (
 	"github.com/joho/godotenv"
 
 	"github.com/getarcaneapp/arcane/backend/internal/config"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
 	"github.com/getarcaneapp/arcane/backend/internal/utils"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/crypto"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/edge"
 	httputils "github.com/getarcaneapp/arcane/backend/internal/utils/http"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/ws"
 	"github.com/getarcaneapp/arcane/backend/pkg/scheduler"
 )
 
@@ -65,6 +68,16 @@ func Bootstrap(ctx context.Context) error {
 		}
 	}(appCtx)
 
+	// Queued events are flushed before the database is closed.
+	appServices.Event.StartAsyncWriter(appCtx, cfg.EventQueueSize)
+	defer func(ctx context.Context) {
+		shutdownCtx, shutdownCancel := context.WithTimeout(context.WithoutCancel(ctx), 10*time.Second)
+		defer shutdownCancel()
+		if err := appServices.Event.CloseAsyncWriter(shutdownCtx); err != nil {
+			slog.WarnContext(shutdownCtx, "Timed out writing queued events", "error", err)
+		}
+	}(appCtx)
+
 	utils.LoadAgentToken(appCtx, cfg, appServices.Settings.GetStringSetting)
 	utils.EnsureEncryptionKey(appCtx, cfg, appServices.Settings.EnsureEncryptionKey)
 	crypto.InitEncryption(cfg)
@@ -100,6 +113,12 @@ func Bootstrap(ctx context.Context) error {
 		return err
 	})
 
+	if n, err := appServices.Operation.RecoverInterrupted(appCtx); err != nil {
+		slog.WarnContext(appCtx, "Failed to recover interrupted operations", "error", err)
+	} else if n > 0 {
+		slog.WarnContext(appCtx, "Recovered operations interrupted by the last shutdown", "count", n)
+	}
+
 	utils.InitializeNonAgentFeatures(appCtx, cfg,
 		appServices.User.CreateDefaultAdmin,
 		appServices.Settings.MigrateOidcConfigToFields,
@@ -117,6 +136,9 @@ func Bootstrap(ctx context.Context) error {
 	appServices.JobSchedule.SetScheduler(scheduler)
 	registerJobs(appCtx, scheduler, appServices, cfg)
 
+	go appServices.HealthHistory.Run(appCtx)
+	go appServices.ContainerCrash.Run(appCtx)
+
 	router, tunnelServer := setupRouter(appCtx, cfg, appServices)
 
 	// Start edge tunnel client if running as an edge agent
@@ -135,7 +157,7 @@ func Bootstrap(ctx context.Context) error {
 		}
 	}
 
-	err = runServices(appCtx, cfg, router, tunnelServer, scheduler)
+	err = runServices(appCtx, cfg, router, tunnelServer, appServices.Operation, scheduler)
 	if err != nil {
 		return fmt.Errorf("failed to run services: %w", err)
 	}
@@ -188,7 +210,7 @@ func handleAgentBootstrapPairing(ctx context.Context, cfg *config.Config, httpCl
 	}
 }
 
-func runServices(appCtx context.Context, cfg *config.Config, router http.Handler, tunnelServer *edge.TunnelServer, schedulers ...interface{ Run(context.Context) error }) error {
+func runServices(appCtx context.Context, cfg *config.Config, router http.Handler, tunnelServer *edge.TunnelServer, operations *services.OperationService, schedulers ...interface{ Run(context.Context) error }) error {
 	for _, s := range schedulers {
 		scheduler := s
 		go func() {
@@ -202,19 +224,27 @@ func runServices(appCtx context.Context, cfg *config.Config, router http.Handler
 		}()
 	}
 
-	listenAddr := cfg.ListenAddr()
 	srv := &http.Server{
-		Addr:              listenAddr,
 		Handler:           router,
 		ReadHeaderTimeout: 5 * time.Second,
 	}
 
-	go func() {
-		slog.InfoContext(appCtx, "Starting HTTP server", "addr", listenAddr, "listen", cfg.Listen, "port", cfg.Port)
-		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
-			slog.ErrorContext(appCtx, "Failed to start server", "error", err)
+	// One listener per LISTEN address, all served by the same server so a
+	// single Shutdown closes them together.
+	for _, listenAddr := range cfg.ListenAddrs() {
+		network := config.ListenNetwork(listenAddr)
+		listener, err := net.Listen(network, listenAddr)
+		if err != nil {
+			slog.ErrorContext(appCtx, "Failed to start server", "addr", listenAddr, "network", network, "error", err)
+			continue
 		}
-	}()
+		go func() {
+			slog.InfoContext(appCtx, "Starting HTTP server", "addr", listenAddr, "network", network, "listen", cfg.Listen, "port", cfg.Port)
+			if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				slog.ErrorContext(appCtx, "HTTP server stopped", "addr", listenAddr, "error", err)
+			}
+		}()
+	}
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -226,6 +256,19 @@ func runServices(appCtx context.Context, cfg *config.Config, router http.Handler
 		slog.InfoContext(appCtx, "Context canceled")
 	}
 
+	// Stop accepting new mutations and let running backups, restores, deploys
+	// and exec sessions finish before the server goes away. Anything still
+	// running after the timeout keeps its recorded state for the next start.
+	drainCtx, drainCancel := context.WithTimeout(context.Background(), time.Duration(cfg.ShutdownTimeout)*time.Second) //nolint:contextcheck
+	defer drainCancel()
+	if active := operations.Active(); len(active) > 0 {
+		slog.InfoContext(drainCtx, "Waiting for in-flight operations to finish", "count", len(active), "timeout", cfg.ShutdownTimeout) //nolint:contextcheck
+	}
+	if err := operations.Drain(drainCtx); err != nil { //nolint:contextcheck
+		slog.WarnContext(drainCtx, "Shutting down with operations still running", "error", err) //nolint:contextcheck
+	}
+	ws.CloseAllHubs()
+
 	// Use background context for shutdown as appCtx is already canceled
 	shutdownCtx, shutdownCancel := context.WithTimeout(context.Background(), 10*time.Second) //nolint:contextcheck
 	defer shutdownCancel()
@@ -10,6 +10,14 @@ import (
 )
 
 func initializeDBAndMigrate(ctx context.Context, cfg *config.Config) (*database.DB, error) {
+	if err := database.SetSQLitePragmas(database.SQLitePragmas{
+		JournalMode: cfg.SqliteJournalMode,
+		BusyTimeout: cfg.SqliteBusyTimeout,
+		Synchronous: cfg.SqliteSynchronous,
+	}); err != nil {
+		return nil, err
+	}
+
 	db, err := database.Initialize(ctx, cfg.DatabaseURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize database: %w", err)
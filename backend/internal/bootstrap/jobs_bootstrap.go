@@ -11,10 +11,15 @@ import (
 )
 
 func registerJobs(appCtx context.Context, newScheduler *pkg_scheduler.JobScheduler, appServices *Services, appConfig *config.Config) {
-	autoUpdateJob := pkg_scheduler.NewAutoUpdateJob(appServices.Updater, appServices.Settings)
+	autoUpdateJob := pkg_scheduler.NewAutoUpdateJob(appServices.Updater, appServices.Settings, appServices.UpdateRollout)
 	newScheduler.RegisterJob(autoUpdateJob)
 
-	imagePollingJob := pkg_scheduler.NewImagePollingJob(appServices.ImageUpdate, appServices.Settings, appServices.Environment)
+	if !appConfig.AgentMode {
+		updateRolloutJob := pkg_scheduler.NewUpdateRolloutJob(appServices.UpdateRollout, appServices.Settings)
+		newScheduler.RegisterJob(updateRolloutJob)
+	}
+
+	imagePollingJob := pkg_scheduler.NewImagePollingJob(appServices.ImageUpdate, appServices.ContainerDigest, appServices.Settings, appServices.Environment)
 	newScheduler.RegisterJob(imagePollingJob)
 
 	environmentHealthJob := pkg_scheduler.NewEnvironmentHealthJob(appServices.Environment, appServices.Settings)
@@ -22,6 +27,20 @@ func registerJobs(appCtx context.Context, newScheduler *pkg_scheduler.JobSchedul
 		newScheduler.RegisterJob(environmentHealthJob)
 	}
 
+	if appConfig.AgentMode && appConfig.AgentToken != "" && appConfig.ManagerApiUrl != "" {
+		agentHeartbeatJob := pkg_scheduler.NewAgentHeartbeatJob(appServices.System, nil, appConfig)
+		newScheduler.RegisterJob(agentHeartbeatJob)
+		// Report right away so the manager shows metrics before the first tick.
+		go agentHeartbeatJob.Run(appCtx)
+	}
+
+	environmentPolicyJob := pkg_scheduler.NewEnvironmentPolicyJob(appServices.EnvironmentPolicy, appServices.Settings)
+	newScheduler.RegisterJob(environmentPolicyJob)
+	if appConfig.AgentMode {
+		// Fetch the policy right away so it is enforced before the first tick.
+		go environmentPolicyJob.Run(appCtx)
+	}
+
 	analyticsJob := pkg_scheduler.NewAnalyticsJob(appServices.Settings, nil, appConfig)
 	newScheduler.RegisterJob(analyticsJob)
 	// Send initial heartbeat on startup without blocking bootstrap.
@@ -30,7 +49,7 @@ func registerJobs(appCtx context.Context, newScheduler *pkg_scheduler.JobSchedul
 	eventCleanupJob := pkg_scheduler.NewEventCleanupJob(appServices.Event, appServices.Settings)
 	newScheduler.RegisterJob(eventCleanupJob)
 
-	scheduledPruneJob := pkg_scheduler.NewScheduledPruneJob(appServices.System, appServices.Settings, appServices.Notification)
+	scheduledPruneJob := pkg_scheduler.NewScheduledPruneJob(appServices.System, appServices.Settings, appServices.Notification, appServices.FeatureFlag)
 	newScheduler.RegisterJob(scheduledPruneJob)
 
 	fsWatcherJob, err := pkg_scheduler.RegisterFilesystemWatcherJob(appCtx, appServices.Project, appServices.Template, appServices.Settings)
@@ -44,6 +63,37 @@ func registerJobs(appCtx context.Context, newScheduler *pkg_scheduler.JobSchedul
 	vulnerabilityScanJob := pkg_scheduler.NewVulnerabilityScanJob(appServices.Vulnerability, appServices.Settings)
 	newScheduler.RegisterJob(vulnerabilityScanJob)
 
+	bootVerificationJob := pkg_scheduler.NewBootVerificationJob(appServices.BootVerification, appServices.Settings, appServices.Notification)
+	newScheduler.RegisterJob(bootVerificationJob)
+	// Verify right away so a reboot is handled without waiting for the first tick.
+	go bootVerificationJob.Run(appCtx)
+
+	vulnerabilityDigestJob := pkg_scheduler.NewVulnerabilityDigestJob(appServices.Vulnerability, appServices.Settings)
+	newScheduler.RegisterJob(vulnerabilityDigestJob)
+
+	helperReaperJob := pkg_scheduler.NewHelperReaperJob(appServices.Volume)
+	newScheduler.RegisterJob(helperReaperJob)
+
+	staleHelperReaperJob := pkg_scheduler.NewStaleHelperReaperJob(appServices.Volume)
+	newScheduler.RegisterJob(staleHelperReaperJob)
+	// Leftovers of a crash are most likely right after a restart.
+	go staleHelperReaperJob.Run(appCtx)
+
+	containerTrashPurgeJob := pkg_scheduler.NewContainerTrashPurgeJob(appServices.Container)
+	newScheduler.RegisterJob(containerTrashPurgeJob)
+
+	containerHealthcheckJob := pkg_scheduler.NewContainerHealthcheckJob(appServices.Healthcheck)
+	newScheduler.RegisterJob(containerHealthcheckJob)
+
+	uptimeSamplerJob := pkg_scheduler.NewUptimeSamplerJob(appServices.Uptime)
+	newScheduler.RegisterJob(uptimeSamplerJob)
+
+	endpointMonitorJob := pkg_scheduler.NewEndpointMonitorJob(appServices.Monitor)
+	newScheduler.RegisterJob(endpointMonitorJob)
+
+	statsAggregatorJob := pkg_scheduler.NewStatsAggregatorJob(appServices.StatsAggregator)
+	newScheduler.RegisterJob(statsAggregatorJob)
+
 	setupJobScheduleCallbacks(
 		appServices,
 		appConfig,
@@ -56,6 +106,8 @@ func registerJobs(appCtx context.Context, newScheduler *pkg_scheduler.JobSchedul
 		scheduledPruneJob,
 		gitOpsSyncJob,
 		vulnerabilityScanJob,
+		bootVerificationJob,
+		vulnerabilityDigestJob,
 	)
 	setupSettingsCallbacks(appServices, appConfig, newScheduler, imagePollingJob, autoUpdateJob, environmentHealthJob, fsWatcherJob, scheduledPruneJob, vulnerabilityScanJob)
 }
@@ -72,6 +124,8 @@ func setupJobScheduleCallbacks(
 	scheduledPruneJob *pkg_scheduler.ScheduledPruneJob,
 	gitOpsSyncJob *pkg_scheduler.GitOpsSyncJob,
 	vulnerabilityScanJob *pkg_scheduler.VulnerabilityScanJob,
+	bootVerificationJob *pkg_scheduler.BootVerificationJob,
+	vulnerabilityDigestJob *pkg_scheduler.VulnerabilityDigestJob,
 ) {
 	if appServices.JobSchedule == nil {
 		return
@@ -92,6 +146,8 @@ func setupJobScheduleCallbacks(
 				scheduledPruneJob,
 				gitOpsSyncJob,
 				vulnerabilityScanJob,
+				bootVerificationJob,
+				vulnerabilityDigestJob,
 			)
 		}
 	}
@@ -110,6 +166,8 @@ func handleJobScheduleChangeInternal(
 	scheduledPruneJob *pkg_scheduler.ScheduledPruneJob,
 	gitOpsSyncJob *pkg_scheduler.GitOpsSyncJob,
 	vulnerabilityScanJob *pkg_scheduler.VulnerabilityScanJob,
+	bootVerificationJob *pkg_scheduler.BootVerificationJob,
+	vulnerabilityDigestJob *pkg_scheduler.VulnerabilityDigestJob,
 ) {
 	switch key {
 	case "pollingInterval":
@@ -147,6 +205,14 @@ func handleJobScheduleChangeInternal(
 		if err := newScheduler.RescheduleJob(ctx, vulnerabilityScanJob); err != nil {
 			slog.WarnContext(ctx, "Failed to reschedule vulnerability-scan job", "error", err)
 		}
+	case "bootVerificationInterval":
+		if err := newScheduler.RescheduleJob(ctx, bootVerificationJob); err != nil {
+			slog.WarnContext(ctx, "Failed to reschedule boot-verification job", "error", err)
+		}
+	case "vulnerabilityDigestInterval":
+		if err := newScheduler.RescheduleJob(ctx, vulnerabilityDigestJob); err != nil {
+			slog.WarnContext(ctx, "Failed to reschedule vulnerability-digest job", "error", err)
+		}
 	}
 }
 
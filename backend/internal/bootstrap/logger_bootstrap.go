@@ -158,7 +158,9 @@ func BuildGormLogger(cfg *config.Config) logger.Interface {
 
 	opts := []slogGorm.Option{
 		slogGorm.WithHandler(filteredHandler),
-		slogGorm.WithSlowThreshold(200 * time.Millisecond),
+	}
+	if cfg.DBSlowQueryThreshold > 0 {
+		opts = append(opts, slogGorm.WithSlowThreshold(time.Duration(cfg.DBSlowQueryThreshold)*time.Millisecond))
 	}
 
 	var defaultTypeLevel slog.Level
@@ -185,5 +187,6 @@ func BuildGormLogger(cfg *config.Config) logger.Interface {
 }
 
 func ConfigureGormLogger(cfg *config.Config) {
-	database.SetGormLogger(BuildGormLogger(cfg))
+	threshold := time.Duration(cfg.DBSlowQueryThreshold) * time.Millisecond
+	database.SetGormLogger(database.NewSlowQueryLogger(BuildGormLogger(cfg), threshold))
 }
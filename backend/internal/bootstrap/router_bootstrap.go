@@ -14,8 +14,10 @@ import (
 	"github.com/getarcaneapp/arcane/backend/internal/config"
 	"github.com/getarcaneapp/arcane/backend/internal/huma"
 	"github.com/getarcaneapp/arcane/backend/internal/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/cookie"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/edge"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/timeouts"
 	"github.com/getarcaneapp/arcane/types"
 )
 
@@ -27,6 +29,8 @@ var loggerSkipPatterns = []string{
 	"GET /api/environments/*/ws/containers/*/terminal",
 	"GET /api/environments/*/ws/projects/*/logs",
 	"GET /api/environments/*/ws/system/stats",
+	"GET /api/environments/*/sse/containers/*/logs",
+	"GET /api/environments/*/sse/projects/*/logs",
 	"GET /_app/*",
 	"GET /img",
 	"GET /api/fonts/sans",
@@ -59,11 +63,23 @@ func shouldLogRequest(c *gin.Context) bool {
 }
 
 func createAuthValidator(appServices *Services) middleware.AuthValidator {
+	resolveUser := createUserResolver(appServices)
 	return func(ctx context.Context, c *gin.Context) bool {
+		return resolveUser(ctx, c) != nil
+	}
+}
+
+// createUserResolver authenticates a request by API key or bearer token and
+// returns the user, or nil if the request is not authenticated.
+func createUserResolver(appServices *Services) middleware.UserResolver {
+	return func(ctx context.Context, c *gin.Context) *models.User {
 		// Check for API key authentication
 		if apiKey := c.GetHeader("X-API-Key"); apiKey != "" {
 			user, err := appServices.ApiKey.ValidateApiKey(ctx, apiKey)
-			return err == nil && user != nil
+			if err != nil {
+				return nil
+			}
+			return user
 		}
 
 		// Check for Bearer token authentication
@@ -75,11 +91,14 @@ func createAuthValidator(appServices *Services) middleware.AuthValidator {
 		}
 
 		if token == "" {
-			return false
+			return nil
 		}
 
 		user, err := appServices.Auth.VerifyToken(ctx, token)
-		return err == nil && user != nil
+		if err != nil {
+			return nil
+		}
+		return user
 	}
 }
 
@@ -115,6 +134,11 @@ func setupRouter(ctx context.Context, cfg *config.Config, appServices *Services)
 		appServices.Environment,
 		createAuthValidator(appServices),
 	)
+	apiGroup.Use(middleware.NewRequestTimeoutMiddleware(timeouts.GetDuration(cfg.MaxRequestTimeout, timeouts.DefaultMaxRequestTimeout)))
+	apiGroup.Use(middleware.NewChaosMiddleware(appServices.Chaos))
+	apiGroup.Use(middleware.NewDrainMiddleware(appServices.Operation.IsDraining))
+	apiGroup.Use(middleware.NewFeatureFlagMiddleware(appServices.FeatureFlag.IsEnabled))
+	apiGroup.Use(middleware.NewApprovalMiddleware(appServices.Approval, createUserResolver(appServices)))
 	apiGroup.Use(envMiddleware)
 
 	_ = huma.SetupAPI(router, apiGroup, cfg, &huma.Services{
@@ -124,6 +148,7 @@ func setupRouter(ctx context.Context, cfg *config.Config, appServices *Services)
 		ApiKey:            appServices.ApiKey,
 		AppImages:         appServices.AppImages,
 		Font:              appServices.Font,
+		IconProxy:         appServices.IconProxy,
 		Project:           appServices.Project,
 		Event:             appServices.Event,
 		Version:           appServices.Version,
@@ -138,23 +163,41 @@ func setupRouter(ctx context.Context, cfg *config.Config, appServices *Services)
 		ImageUpdate:       appServices.ImageUpdate,
 		Volume:            appServices.Volume,
 		Container:         appServices.Container,
+		ContainerDigest:   appServices.ContainerDigest,
 		Network:           appServices.Network,
 		Notification:      appServices.Notification,
 		Apprise:           appServices.Apprise,
 		Updater:           appServices.Updater,
+		UpdateRollout:     appServices.UpdateRollout,
+		Chaos:             appServices.Chaos,
 		CustomizeSearch:   appServices.CustomizeSearch,
 		System:            appServices.System,
 		SystemUpgrade:     appServices.SystemUpgrade,
 		GitRepository:     appServices.GitRepository,
 		GitOpsSync:        appServices.GitOpsSync,
 		Vulnerability:     appServices.Vulnerability,
+		FeatureFlag:       appServices.FeatureFlag,
+		EnvironmentPolicy: appServices.EnvironmentPolicy,
+		Approval:          appServices.Approval,
+		VolumeTransfer:    appServices.VolumeTransfer,
+		BackupDownload:    appServices.BackupDownload,
+		Namespace:         appServices.Namespace,
+		Healthcheck:       appServices.Healthcheck,
+		HealthHistory:     appServices.HealthHistory,
+		Monitor:           appServices.Monitor,
+		Webhook:           appServices.Webhook,
+		StatsAggregator:   appServices.StatsAggregator,
+		ProjectHook:       appServices.ProjectHook,
+		ProjectWatch:      appServices.ProjectWatch,
+		Operation:         appServices.Operation,
+		Power:             appServices.Power,
 		Config:            cfg,
 	})
 
-	api.RegisterDiagnosticsRoutes(apiGroup, authMiddleware, api.DefaultWebSocketMetrics()) //nolint:contextcheck
+	api.RegisterDiagnosticsRoutes(apiGroup, authMiddleware, api.DefaultWebSocketMetrics(), appServices.Event) //nolint:contextcheck
 
 	// Remaining Gin handlers (WebSocket/streaming)
-	api.NewWebSocketHandler(apiGroup, appServices.Project, appServices.Container, appServices.System, authMiddleware, cfg) //nolint:contextcheck
+	api.NewWebSocketHandler(apiGroup, appServices.Project, appServices.Container, appServices.System, appServices.Volume, appServices.Image, appServices.Operation, appServices.ProjectWatch, appServices.Namespace, authMiddleware, cfg) //nolint:contextcheck
 
 	// Register edge tunnel endpoint for manager to accept agent connections
 	// This is only registered when NOT in agent mode (i.e., running as manager)
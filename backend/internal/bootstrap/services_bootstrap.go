@@ -3,11 +3,14 @@ package bootstrap
 import (
 	"context"
 	"fmt"
+	"log/slog"
 	"net/http"
+	"time"
 
 	"github.com/getarcaneapp/arcane/backend/internal/config"
 	"github.com/getarcaneapp/arcane/backend/internal/database"
 	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/chaos"
 	"github.com/getarcaneapp/arcane/backend/resources"
 )
 
@@ -21,6 +24,7 @@ type Services struct {
 	SettingsSearch    *services.SettingsSearchService
 	CustomizeSearch   *services.CustomizeSearchService
 	Container         *services.ContainerService
+	ContainerDigest   *services.ContainerDigestService
 	Image             *services.ImageService
 	Volume            *services.VolumeService
 	Network           *services.NetworkService
@@ -33,6 +37,7 @@ type Services struct {
 	System            *services.SystemService
 	SystemUpgrade     *services.SystemUpgradeService
 	Updater           *services.UpdaterService
+	UpdateRollout     *services.UpdateRolloutService
 	Event             *services.EventService
 	Version           *services.VersionService
 	Notification      *services.NotificationService
@@ -41,13 +46,34 @@ type Services struct {
 	GitRepository     *services.GitRepositoryService
 	GitOpsSync        *services.GitOpsSyncService
 	Font              *services.FontService
+	IconProxy         *services.IconProxyService
 	Vulnerability     *services.VulnerabilityService
+	BootVerification  *services.BootVerificationService
+	FeatureFlag       *services.FeatureFlagService
+	EnvironmentPolicy *services.EnvironmentPolicyService
+	Approval          *services.ApprovalService
+	VolumeTransfer    *services.VolumeTransferService
+	BackupDownload    *services.BackupDownloadService
+	Operation         *services.OperationService
+	Namespace         *services.NamespaceService
+	Healthcheck       *services.ContainerHealthcheckService
+	ProjectHook       *services.ProjectHookService
+	ProjectWatch      *services.ProjectWatchService
+	HealthHistory     *services.ContainerHealthHistoryService
+	ContainerCrash    *services.ContainerCrashService
+	Uptime            *services.UptimeService
+	Monitor           *services.EndpointMonitorService
+	Webhook           *services.WebhookService
+	StatsAggregator   *services.StatsAggregatorService
+	Power             *services.PowerService
+	Chaos             *chaos.Injector
 }
 
 func initializeServices(ctx context.Context, db *database.DB, cfg *config.Config, httpClient *http.Client) (svcs *Services, dockerSrvice *services.DockerClientService, err error) {
 	svcs = &Services{}
 
 	svcs.Event = services.NewEventService(db)
+	svcs.Operation = services.NewOperationService(db, svcs.Event)
 	svcs.Settings, err = services.NewSettingsService(ctx, db)
 	if err != nil {
 		return nil, nil, fmt.Errorf("failed to settings service: %w", err)
@@ -57,7 +83,9 @@ func initializeServices(ctx context.Context, db *database.DB, cfg *config.Config
 	svcs.CustomizeSearch = services.NewCustomizeSearchService()
 	svcs.AppImages = services.NewApplicationImagesService(resources.FS, svcs.Settings)
 	svcs.Font = services.NewFontService(resources.FS)
-	dockerClient := services.NewDockerClientService(db, cfg, svcs.Settings)
+	svcs.IconProxy = services.NewIconProxyService(httpClient)
+	svcs.Chaos = newChaosInjector(ctx, cfg)
+	dockerClient := services.NewDockerClientService(db, cfg, svcs.Settings, svcs.Chaos)
 	svcs.Docker = dockerClient
 	svcs.User = services.NewUserService(db)
 	svcs.ContainerRegistry = services.NewContainerRegistryService(db)
@@ -65,22 +93,78 @@ func initializeServices(ctx context.Context, db *database.DB, cfg *config.Config
 	svcs.Apprise = services.NewAppriseService(db, cfg)
 	svcs.Vulnerability = services.NewVulnerabilityService(db, svcs.Docker, svcs.Event, svcs.Settings, svcs.Notification)
 	svcs.ImageUpdate = services.NewImageUpdateService(db, svcs.Settings, svcs.ContainerRegistry, svcs.Docker, svcs.Event, svcs.Notification)
-	svcs.Image = services.NewImageService(db, svcs.Docker, svcs.ContainerRegistry, svcs.ImageUpdate, svcs.Vulnerability, svcs.Event)
-	svcs.Project = services.NewProjectService(db, svcs.Settings, svcs.Event, svcs.Image, svcs.Docker)
+	svcs.Namespace = services.NewNamespaceService(db, svcs.Docker)
+	svcs.Image = services.NewImageService(db, svcs.Docker, svcs.ContainerRegistry, svcs.ImageUpdate, svcs.Vulnerability, svcs.Event, svcs.Settings)
+	svcs.ProjectHook = services.NewProjectHookService(db, svcs.Docker, svcs.Image, svcs.Event, httpClient)
+	svcs.Project = services.NewProjectService(db, svcs.Settings, svcs.Event, svcs.Image, svcs.Docker, svcs.Operation, svcs.Namespace, svcs.ProjectHook)
+	svcs.ProjectWatch = services.NewProjectWatchService(svcs.Project, svcs.Event)
 	svcs.Environment = services.NewEnvironmentService(db, httpClient, svcs.Docker, svcs.Event, svcs.Settings)
-	svcs.Container = services.NewContainerService(db, svcs.Event, svcs.Docker, svcs.Image, svcs.Settings)
-	svcs.Volume = services.NewVolumeService(db, svcs.Docker, svcs.Event, svcs.Settings, svcs.Container, svcs.Image, cfg.BackupVolumeName)
+	svcs.Container = services.NewContainerService(db, svcs.Event, svcs.Docker, svcs.Image, svcs.Settings, svcs.Namespace)
+	svcs.ContainerDigest = services.NewContainerDigestService(db, svcs.Docker, svcs.ImageUpdate, svcs.Project, svcs.Event)
+	svcs.Volume = services.NewVolumeService(db, svcs.Docker, svcs.Event, svcs.Settings, svcs.Container, svcs.Image, svcs.Operation, svcs.Namespace, cfg.BackupVolumeName)
+	svcs.Healthcheck = services.NewContainerHealthcheckService(db, svcs.Docker, svcs.Event)
+	svcs.HealthHistory = services.NewContainerHealthHistoryService(db, svcs.Docker, svcs.Event, svcs.Notification, svcs.Settings)
+	svcs.ContainerCrash = services.NewContainerCrashService(svcs.Docker, svcs.Event, svcs.Notification, svcs.Settings)
+	svcs.Uptime = services.NewUptimeService(db, svcs.Docker)
+	svcs.StatsAggregator = services.NewStatsAggregatorService(svcs.Docker)
+	svcs.Monitor = services.NewEndpointMonitorService(db, svcs.Event, svcs.Notification)
 	svcs.Network = services.NewNetworkService(db, svcs.Docker, svcs.Event)
 	svcs.Template = services.NewTemplateService(ctx, db, httpClient, svcs.Settings)
 	svcs.Auth = services.NewAuthService(svcs.User, svcs.Settings, svcs.Event, cfg.JWTSecret, cfg)
 	svcs.Oidc = services.NewOidcService(svcs.Auth, cfg, httpClient)
 	svcs.ApiKey = services.NewApiKeyService(db, svcs.User)
 	svcs.System = services.NewSystemService(db, svcs.Docker, svcs.Container, svcs.Image, svcs.Volume, svcs.Network, svcs.Settings)
+	svcs.EnvironmentPolicy = services.NewEnvironmentPolicyService(db, svcs.Event, svcs.System, httpClient, cfg)
+	svcs.Image.SetPolicyService(svcs.EnvironmentPolicy)
+	svcs.Container.SetPolicyService(svcs.EnvironmentPolicy)
+	svcs.Project.SetPolicyService(svcs.EnvironmentPolicy)
 	svcs.Version = services.NewVersionService(httpClient, cfg.UpdateCheckDisabled, config.Version, config.Revision, svcs.ContainerRegistry, svcs.Docker)
 	svcs.SystemUpgrade = services.NewSystemUpgradeService(svcs.Docker, svcs.Version, svcs.Event, svcs.Settings)
 	svcs.Updater = services.NewUpdaterService(db, svcs.Settings, svcs.Docker, svcs.Project, svcs.ImageUpdate, svcs.ContainerRegistry, svcs.Event, svcs.Image, svcs.Notification, svcs.SystemUpgrade)
+	if !cfg.AgentMode {
+		svcs.UpdateRollout = services.NewUpdateRolloutService(db, svcs.Settings, svcs.Environment, svcs.Updater, svcs.Container, svcs.Event)
+	}
 	svcs.GitRepository = services.NewGitRepositoryService(db, cfg.GitWorkDir, svcs.Event, svcs.Settings)
 	svcs.GitOpsSync = services.NewGitOpsSyncService(db, svcs.GitRepository, svcs.Project, svcs.Event)
+	svcs.Webhook = services.NewWebhookService(db, svcs.Event, svcs.Project, svcs.ImageUpdate)
+	svcs.BootVerification = services.NewBootVerificationService(db, svcs.Docker, svcs.Container, svcs.Project, svcs.Event)
+	svcs.FeatureFlag = services.NewFeatureFlagService(svcs.Environment, svcs.Settings, svcs.Event)
+	svcs.Approval = services.NewApprovalService(db, svcs.Settings, svcs.Environment, svcs.Event)
+	svcs.VolumeTransfer = services.NewVolumeTransferService(svcs.Volume, svcs.Environment, svcs.Approval, svcs.Event)
+	svcs.BackupDownload = services.NewBackupDownloadService(svcs.Volume, svcs.Environment, svcs.User, svcs.Namespace)
+	svcs.Power = services.NewPowerService(cfg, svcs.Environment, svcs.Event)
 
 	return svcs, dockerClient, nil
 }
+
+// newChaosInjector returns the fault injector used by chaos mode, or nil in
+// production, where the CHAOS_* variables are ignored.
+func newChaosInjector(ctx context.Context, cfg *config.Config) *chaos.Injector {
+	chaosCfg := chaos.Config{
+		Docker: chaos.Rule{
+			Latency:     time.Duration(cfg.ChaosDockerLatency) * time.Millisecond,
+			FailureRate: cfg.ChaosDockerFailureRate,
+		},
+		API: chaos.Rule{
+			Latency:     time.Duration(cfg.ChaosAPILatency) * time.Millisecond,
+			FailureRate: cfg.ChaosAPIFailureRate,
+		},
+	}
+
+	if cfg.Environment.IsProdEnvironment() {
+		if chaosCfg.Enabled() {
+			slog.WarnContext(ctx, "Ignoring CHAOS_* settings in production")
+		}
+		return nil
+	}
+
+	injector := chaos.New(chaos.Config{})
+	if err := injector.SetConfig(chaosCfg); err != nil {
+		slog.WarnContext(ctx, "Ignoring invalid CHAOS_* settings", "error", err)
+	} else if chaosCfg.Enabled() {
+		slog.WarnContext(ctx, "Chaos mode is injecting Docker and API faults",
+			"dockerLatencyMs", cfg.ChaosDockerLatency, "dockerFailureRate", cfg.ChaosDockerFailureRate,
+			"apiLatencyMs", cfg.ChaosAPILatency, "apiFailureRate", cfg.ChaosAPIFailureRate)
+	}
+	return injector
+}
@@ -164,6 +164,22 @@ func (e *ContainerDeleteError) Error() string {
 	return fmt.Sprintf("Failed to delete container: %v", e.Err)
 }
 
+type ContainerRestartPolicyAuditError struct {
+	Err error
+}
+
+func (e *ContainerRestartPolicyAuditError) Error() string {
+	return fmt.Sprintf("Failed to audit restart policies: %v", e.Err)
+}
+
+type ContainerRestartPolicyUpdateError struct {
+	Err error
+}
+
+func (e *ContainerRestartPolicyUpdateError) Error() string {
+	return fmt.Sprintf("Failed to update restart policies: %v", e.Err)
+}
+
 type ContainerStatusCountsError struct {
 	Err error
 }
@@ -446,6 +462,22 @@ func (e *ImageLoadError) Error() string {
 	return fmt.Sprintf("Failed to load image: %v", e.Err)
 }
 
+type ImageSaveError struct {
+	Err error
+}
+
+func (e *ImageSaveError) Error() string {
+	return fmt.Sprintf("Failed to export images: %v", e.Err)
+}
+
+type ImageBuildError struct {
+	Err error
+}
+
+func (e *ImageBuildError) Error() string {
+	return fmt.Sprintf("Failed to build image: %v", e.Err)
+}
+
 type ImageRefRequiredError struct{}
 
 func (e *ImageRefRequiredError) Error() string {
@@ -1105,6 +1137,30 @@ func (e *VolumePruneError) Error() string {
 	return fmt.Sprintf("Failed to prune volumes: %v", e.Err)
 }
 
+type VolumeBulkOperationError struct {
+	Err error
+}
+
+func (e *VolumeBulkOperationError) Error() string {
+	return fmt.Sprintf("Failed to run bulk volume operation: %v", e.Err)
+}
+
+type VolumeCloneError struct {
+	Err error
+}
+
+func (e *VolumeCloneError) Error() string {
+	return fmt.Sprintf("Failed to clone volume: %v", e.Err)
+}
+
+type VolumeMigrateError struct {
+	Err error
+}
+
+func (e *VolumeMigrateError) Error() string {
+	return fmt.Sprintf("Failed to migrate volume: %v", e.Err)
+}
+
 type VolumeUsageError struct {
 	Err error
 }
@@ -26,9 +26,9 @@ const (
 // Available options: file, toLower, trimTrailingSlash
 type Config struct {
 	AppUrl        string         `env:"APP_URL" default:"http://localhost:3552"`
-	DatabaseURL   string         `env:"DATABASE_URL" default:"file:data/arcane.db?_pragma=journal_mode(WAL)&_pragma=busy_timeout(2500)&_txlock=immediate" options:"file"`
+	DatabaseURL   string         `env:"DATABASE_URL" default:"file:data/arcane.db" options:"file"`
 	Port          string         `env:"PORT" default:"3552"`
-	Listen        string         `env:"LISTEN" default:""`
+	Listen        string         `env:"LISTEN" default:""` // comma-separated bind addresses, e.g. "0.0.0.0,::"
 	Environment   AppEnvironment `env:"ENVIRONMENT" default:"production"`
 	JWTSecret     string         `env:"JWT_SECRET" default:"default-jwt-secret-change-me" options:"file"`
 	EncryptionKey string         `env:"ENCRYPTION_KEY" default:"arcane-dev-key-32-characters!!!" options:"file"`
@@ -59,6 +59,7 @@ type Config struct {
 	GPUType                 string `env:"GPU_TYPE" default:"auto"`
 	EdgeAgent               bool   `env:"EDGE_AGENT" default:"false"`
 	EdgeReconnectInterval   int    `env:"EDGE_RECONNECT_INTERVAL" default:"5"` // seconds
+	HostRebootCommand       string `env:"HOST_REBOOT_COMMAND" default:""`      // shell command run by the reboot power action; empty disables it
 
 	FilePerm   os.FileMode `env:"FILE_PERM" default:"0644"`
 	DirPerm    os.FileMode `env:"DIR_PERM" default:"0755"`
@@ -70,7 +71,24 @@ type Config struct {
 	HTTPClientTimeout      int    `env:"HTTP_CLIENT_TIMEOUT" default:"0"`
 	RegistryTimeout        int    `env:"REGISTRY_TIMEOUT" default:"0"`
 	ProxyRequestTimeout    int    `env:"PROXY_REQUEST_TIMEOUT" default:"0"`
+	HelperExecTimeout      int    `env:"HELPER_EXEC_TIMEOUT" default:"0"`
+	HelperScanTimeout      int    `env:"HELPER_SCAN_TIMEOUT" default:"0"`
+	HelperArchiveTimeout   int    `env:"HELPER_ARCHIVE_TIMEOUT" default:"0"`
+	MaxRequestTimeout      int    `env:"MAX_REQUEST_TIMEOUT" default:"0"` // seconds; upper bound for the X-Arcane-Timeout request header
 	BackupVolumeName       string `env:"ARCANE_BACKUP_VOLUME_NAME" default:"arcane-backups"`
+	ShutdownTimeout        int    `env:"SHUTDOWN_TIMEOUT" default:"60"`         // seconds to wait for in-flight operations
+	DBSlowQueryThreshold   int    `env:"DB_SLOW_QUERY_THRESHOLD" default:"200"` // milliseconds, 0 disables slow query logging
+	EventQueueSize         int    `env:"EVENT_QUEUE_SIZE" default:"1024"`       // events buffered for batched writes, 0 writes synchronously
+
+	// Chaos mode injects latency and failures outside production only.
+	ChaosDockerLatency     int `env:"CHAOS_DOCKER_LATENCY" default:"0"`      // maximum milliseconds added to each Docker API request
+	ChaosDockerFailureRate int `env:"CHAOS_DOCKER_FAILURE_RATE" default:"0"` // percent of Docker API requests that fail
+	ChaosAPILatency        int `env:"CHAOS_API_LATENCY" default:"0"`         // maximum milliseconds added to each HTTP API request
+	ChaosAPIFailureRate    int `env:"CHAOS_API_FAILURE_RATE" default:"0"`    // percent of HTTP API requests that fail
+
+	SqliteJournalMode string `env:"SQLITE_JOURNAL_MODE" default:"WAL"`
+	SqliteBusyTimeout int    `env:"SQLITE_BUSY_TIMEOUT" default:"5000"` // milliseconds to wait for a lock
+	SqliteSynchronous string `env:"SQLITE_SYNCHRONOUS" default:"NORMAL"`
 }
 
 func Load() *Config {
@@ -258,17 +276,67 @@ func (a AppEnvironment) IsTestEnvironment() bool {
 }
 
 // ListenAddr returns the effective address for the HTTP server to bind to.
-// It uses LISTEN as the host (if set) and PORT for the port.
+// It uses the first LISTEN host (if set) and PORT for the port.
 func (c *Config) ListenAddr() string {
-	host := strings.TrimSpace(c.Listen)
+	return c.ListenAddrs()[0]
+}
+
+// ListenAddrs returns every address the HTTP server binds to, one per LISTEN
+// host, all on PORT. An empty LISTEN binds all interfaces, dual-stack where
+// the host supports it.
+func (c *Config) ListenAddrs() []string {
 	port := c.Port
 	if port == "" {
 		port = "3552"
 	}
-	if host == "" {
-		return ":" + port
+
+	hosts := c.ListenHosts()
+	if len(hosts) == 0 {
+		return []string{":" + port}
+	}
+	addrs := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		addrs = append(addrs, net.JoinHostPort(host, port))
+	}
+	return addrs
+}
+
+// ListenHosts returns the hosts listed in LISTEN, with IPv6 brackets
+// removed and duplicates dropped.
+func (c *Config) ListenHosts() []string {
+	var hosts []string
+	seen := map[string]struct{}{}
+	for _, part := range strings.Split(c.Listen, ",") {
+		host := strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(part), "["), "]")
+		if host == "" {
+			continue
+		}
+		if _, ok := seen[host]; ok {
+			continue
+		}
+		seen[host] = struct{}{}
+		hosts = append(hosts, host)
+	}
+	return hosts
+}
+
+// ListenNetwork returns the network to listen on addr with. Literal IPv4 and
+// IPv6 hosts are bound to their own family only, so "0.0.0.0" and "::" can
+// be listed together; anything else uses "tcp".
+func ListenNetwork(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return "tcp"
+	}
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil:
+		return "tcp"
+	case ip.To4() != nil:
+		return "tcp4"
+	default:
+		return "tcp6"
 	}
-	return net.JoinHostPort(host, port)
 }
 
 // GetManagerBaseURL returns the base URL of the manager application.
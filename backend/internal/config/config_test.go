@@ -233,6 +233,12 @@ func TestConfig_ListenAddr(t *testing.T) {
 			port:     "",
 			expected: "127.0.0.1:3552",
 		},
+		{
+			name:     "list uses the first host",
+			listen:   "[::1], 127.0.0.1",
+			port:     "3553",
+			expected: "[::1]:3553",
+		},
 	}
 
 	for _, testCase := range tests {
@@ -246,6 +252,22 @@ func TestConfig_ListenAddr(t *testing.T) {
 	}
 }
 
+func TestConfig_ListenAddrs(t *testing.T) {
+	cfg := &Config{Listen: "0.0.0.0, ::,[::],  ,fe80::1%eth0", Port: "3553"}
+	assert.Equal(t, []string{"0.0.0.0:3553", "[::]:3553", "[fe80::1%eth0]:3553"}, cfg.ListenAddrs())
+
+	cfg = &Config{Listen: " ", Port: "3553"}
+	assert.Equal(t, []string{":3553"}, cfg.ListenAddrs())
+}
+
+func TestListenNetwork(t *testing.T) {
+	assert.Equal(t, "tcp4", ListenNetwork("0.0.0.0:3552"))
+	assert.Equal(t, "tcp6", ListenNetwork("[::]:3552"))
+	assert.Equal(t, "tcp6", ListenNetwork("[::1]:3552"))
+	assert.Equal(t, "tcp", ListenNetwork(":3552"))
+	assert.Equal(t, "tcp", ListenNetwork("localhost:3552"))
+}
+
 func restoreEnv(key, value string) {
 	if value == "" {
 		os.Unsetenv(key)
@@ -8,6 +8,7 @@ import (
 	"net/url"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 
@@ -30,12 +31,55 @@ type DB struct {
 
 var (
 	customGormLogger logger.Interface
+	sqlitePragmas    = DefaultSQLitePragmas()
 )
 
 func SetGormLogger(l logger.Interface) {
 	customGormLogger = l
 }
 
+// SQLitePragmas are applied to every SQLite connection unless the database
+// URL sets the same pragma itself.
+type SQLitePragmas struct {
+	// JournalMode is the journal_mode pragma, e.g. WAL or DELETE.
+	JournalMode string
+	// BusyTimeout is how long, in milliseconds, a connection waits for a
+	// lock held by another connection before failing with "database is
+	// locked".
+	BusyTimeout int
+	// Synchronous is the synchronous pragma, e.g. NORMAL or FULL.
+	Synchronous string
+}
+
+// DefaultSQLitePragmas returns the pragmas used when none are configured.
+// WAL lets readers run alongside a writer, and the busy timeout makes
+// concurrent writers wait for each other instead of failing.
+func DefaultSQLitePragmas() SQLitePragmas {
+	return SQLitePragmas{JournalMode: "WAL", BusyTimeout: 5000, Synchronous: "NORMAL"}
+}
+
+// SetSQLitePragmas sets the pragmas applied to SQLite connections opened
+// afterwards.
+func SetSQLitePragmas(p SQLitePragmas) error {
+	p.JournalMode = strings.ToUpper(strings.TrimSpace(p.JournalMode))
+	p.Synchronous = strings.ToUpper(strings.TrimSpace(p.Synchronous))
+	switch p.JournalMode {
+	case "", "WAL", "DELETE", "TRUNCATE", "PERSIST", "MEMORY", "OFF":
+	default:
+		return fmt.Errorf("invalid SQLite journal mode %q", p.JournalMode)
+	}
+	switch p.Synchronous {
+	case "", "OFF", "NORMAL", "FULL", "EXTRA":
+	default:
+		return fmt.Errorf("invalid SQLite synchronous mode %q", p.Synchronous)
+	}
+	if p.BusyTimeout < 0 {
+		return fmt.Errorf("invalid SQLite busy timeout %d", p.BusyTimeout)
+	}
+	sqlitePragmas = p
+	return nil
+}
+
 func Initialize(ctx context.Context, databaseURL string) (*DB, error) {
 	db, err := connectDatabase(ctx, databaseURL)
 	if err != nil {
@@ -97,7 +141,7 @@ func connectDatabase(ctx context.Context, databaseURL string) (*DB, error) {
 
 	switch {
 	case strings.HasPrefix(databaseURL, "file:"):
-		connString, err := parseSqliteConnectionString(databaseURL)
+		connString, err := parseSqliteConnectionString(databaseURL, sqlitePragmas)
 		if err != nil {
 			return nil, fmt.Errorf("failed to parse SQLite connection string: %w", err)
 		}
@@ -173,7 +217,7 @@ func migrateDatabase(driver database.Driver, dbProvider string) error {
 	return nil
 }
 
-func parseSqliteConnectionString(connString string) (string, error) {
+func parseSqliteConnectionString(connString string, pragmas SQLitePragmas) (string, error) {
 	if !strings.HasPrefix(connString, "file:") {
 		connString = "file:" + connString
 	}
@@ -209,6 +253,29 @@ func parseSqliteConnectionString(connString string) (string, error) {
 		}
 	}
 
+	// Configured pragmas fill in what the URL leaves unset.
+	set := map[string]bool{}
+	for _, p := range qs["_pragma"] {
+		name, _, _ := strings.Cut(p, "(")
+		set[strings.ToLower(strings.TrimSpace(name))] = true
+	}
+	// busy_timeout goes first so switching the journal mode already waits
+	// for locks held by other processes.
+	if pragmas.BusyTimeout > 0 && !set["busy_timeout"] {
+		qs.Add("_pragma", "busy_timeout("+strconv.Itoa(pragmas.BusyTimeout)+")")
+	}
+	if pragmas.JournalMode != "" && !set["journal_mode"] {
+		qs.Add("_pragma", "journal_mode("+pragmas.JournalMode+")")
+	}
+	if pragmas.Synchronous != "" && !set["synchronous"] {
+		qs.Add("_pragma", "synchronous("+pragmas.Synchronous+")")
+	}
+	// Take the write lock when a transaction starts so two transactions
+	// that read before writing cannot deadlock on the lock upgrade.
+	if qs.Get("_txlock") == "" {
+		qs.Set("_txlock", "immediate")
+	}
+
 	connStringUrl.RawQuery = qs.Encode()
 	return connStringUrl.String(), nil
 }
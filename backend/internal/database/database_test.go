@@ -0,0 +1,65 @@
+package database
+
+import (
+	"net/url"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSqliteConnectionString(t *testing.T) {
+	pragmas := SQLitePragmas{JournalMode: "WAL", BusyTimeout: 5000, Synchronous: "NORMAL"}
+
+	conn, err := parseSqliteConnectionString("file:data/arcane.db", pragmas)
+	require.NoError(t, err)
+	u, err := url.Parse(conn)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"busy_timeout(5000)", "journal_mode(WAL)", "synchronous(NORMAL)"}, u.Query()["_pragma"])
+	assert.Equal(t, "immediate", u.Query().Get("_txlock"))
+
+	// Pragmas and options in the URL win over the configured ones, and the
+	// legacy mattn-style options are translated.
+	conn, err = parseSqliteConnectionString("data/arcane.db?_pragma=journal_mode(DELETE)&_busy_timeout=100&_txlock=deferred", pragmas)
+	require.NoError(t, err)
+	u, err = url.Parse(conn)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"journal_mode(DELETE)", "busy_timeout(100)", "synchronous(NORMAL)"}, u.Query()["_pragma"])
+	assert.Equal(t, "deferred", u.Query().Get("_txlock"))
+
+	conn, err = parseSqliteConnectionString("file:data/arcane.db", SQLitePragmas{})
+	require.NoError(t, err)
+	u, err = url.Parse(conn)
+	require.NoError(t, err)
+	assert.Empty(t, u.Query()["_pragma"])
+}
+
+func TestSetSQLitePragmas(t *testing.T) {
+	defer func() { sqlitePragmas = DefaultSQLitePragmas() }()
+
+	require.NoError(t, SetSQLitePragmas(SQLitePragmas{JournalMode: "wal", BusyTimeout: 1000, Synchronous: "full"}))
+	assert.Equal(t, SQLitePragmas{JournalMode: "WAL", BusyTimeout: 1000, Synchronous: "FULL"}, sqlitePragmas)
+
+	require.Error(t, SetSQLitePragmas(SQLitePragmas{JournalMode: "WAL); DROP"}))
+	require.Error(t, SetSQLitePragmas(SQLitePragmas{Synchronous: "sometimes"}))
+	require.Error(t, SetSQLitePragmas(SQLitePragmas{BusyTimeout: -1}))
+}
+
+func TestConnectDatabaseAppliesSQLitePragmas(t *testing.T) {
+	defer func() { sqlitePragmas = DefaultSQLitePragmas() }()
+	require.NoError(t, SetSQLitePragmas(SQLitePragmas{JournalMode: "WAL", BusyTimeout: 1234, Synchronous: "FULL"}))
+
+	db, err := connectDatabase(t.Context(), "file:"+filepath.Join(t.TempDir(), "arcane.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	var journalMode string
+	var busyTimeout, synchronous int
+	require.NoError(t, db.Raw("PRAGMA journal_mode").Scan(&journalMode).Error)
+	require.NoError(t, db.Raw("PRAGMA busy_timeout").Scan(&busyTimeout).Error)
+	require.NoError(t, db.Raw("PRAGMA synchronous").Scan(&synchronous).Error)
+	assert.Equal(t, "wal", journalMode)
+	assert.Equal(t, 1234, busyTimeout)
+	assert.Equal(t, 2, synchronous, "FULL")
+}
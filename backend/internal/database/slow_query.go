@@ -0,0 +1,92 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"gorm.io/gorm/logger"
+)
+
+const (
+	slowQueryLogSize   = 100
+	slowQueryMaxSQLLen = 2000
+)
+
+// SlowQuery is a query that ran for longer than the slow query threshold.
+type SlowQuery struct {
+	SQL      string
+	Rows     int64
+	Duration time.Duration
+	At       time.Time
+	Error    string
+}
+
+type slowQueryLog struct {
+	mu        sync.Mutex
+	threshold time.Duration
+	queries   []SlowQuery
+}
+
+var slowQueries = &slowQueryLog{}
+
+// RecentSlowQueries returns the most recent slow queries, newest first, and
+// the threshold they were recorded with. Recording is off until
+// NewSlowQueryLogger is installed with a threshold above zero.
+func RecentSlowQueries() ([]SlowQuery, time.Duration) {
+	slowQueries.mu.Lock()
+	defer slowQueries.mu.Unlock()
+
+	out := make([]SlowQuery, len(slowQueries.queries))
+	for i, q := range slowQueries.queries {
+		out[len(out)-1-i] = q
+	}
+	return out, slowQueries.threshold
+}
+
+func (l *slowQueryLog) record(q SlowQuery) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if len(l.queries) == slowQueryLogSize {
+		l.queries = append(l.queries[:0], l.queries[1:]...)
+	}
+	l.queries = append(l.queries, q)
+}
+
+type slowQueryLogger struct {
+	logger.Interface
+	threshold time.Duration
+}
+
+// NewSlowQueryLogger wraps a GORM logger so queries that take at least
+// threshold are kept for RecentSlowQueries. A threshold of zero or less
+// turns recording off.
+func NewSlowQueryLogger(inner logger.Interface, threshold time.Duration) logger.Interface {
+	if inner == nil {
+		inner = logger.Default
+	}
+	slowQueries.mu.Lock()
+	slowQueries.threshold = max(threshold, 0)
+	slowQueries.mu.Unlock()
+	return &slowQueryLogger{Interface: inner, threshold: threshold}
+}
+
+func (l *slowQueryLogger) LogMode(level logger.LogLevel) logger.Interface {
+	return &slowQueryLogger{Interface: l.Interface.LogMode(level), threshold: l.threshold}
+}
+
+func (l *slowQueryLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if elapsed := time.Since(begin); l.threshold > 0 && elapsed >= l.threshold {
+		sql, rows := fc()
+		if len(sql) > slowQueryMaxSQLLen {
+			sql = sql[:slowQueryMaxSQLLen] + "..."
+		}
+		q := SlowQuery{SQL: sql, Rows: rows, Duration: elapsed, At: begin}
+		if err != nil {
+			q.Error = err.Error()
+		}
+		slowQueries.record(q)
+	}
+	l.Interface.Trace(ctx, begin, fc, err)
+}
@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/logger"
+)
+
+func TestSlowQueryLogger(t *testing.T) {
+	ctx := context.Background()
+	l := NewSlowQueryLogger(logger.Discard, 100*time.Millisecond)
+
+	l.Trace(ctx, time.Now(), func() (string, int64) { return "SELECT fast", 1 }, nil)
+	l.Trace(ctx, time.Now().Add(-200*time.Millisecond), func() (string, int64) { return "SELECT slow", 3 }, nil)
+	l.LogMode(logger.Silent).Trace(ctx, time.Now().Add(-time.Second), func() (string, int64) {
+		return "SELECT " + strings.Repeat("x", 3000), 0
+	}, errors.New("boom"))
+
+	queries, threshold := RecentSlowQueries()
+	assert.Equal(t, 100*time.Millisecond, threshold)
+	require.Len(t, queries, 2)
+	assert.Equal(t, "boom", queries[0].Error, "newest first")
+	assert.Len(t, queries[0].SQL, slowQueryMaxSQLLen+3)
+	assert.Equal(t, "SELECT slow", queries[1].SQL)
+	assert.Equal(t, int64(3), queries[1].Rows)
+	assert.GreaterOrEqual(t, queries[1].Duration, 200*time.Millisecond)
+
+	for range slowQueryLogSize + 5 {
+		l.Trace(ctx, time.Now().Add(-time.Second), func() (string, int64) { return "SELECT again", 0 }, nil)
+	}
+	queries, _ = RecentSlowQueries()
+	assert.Len(t, queries, slowQueryLogSize)
+}
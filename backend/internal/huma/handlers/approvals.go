@@ -0,0 +1,161 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/common"
+	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/types/approval"
+	"github.com/getarcaneapp/arcane/types/base"
+)
+
+// ApprovalHandler handles approval workflow endpoints.
+type ApprovalHandler struct {
+	approvalService *services.ApprovalService
+}
+
+// ============================================================================
+// Input/Output Types
+// ============================================================================
+
+type ListApprovalsInput struct {
+	Status string `query:"status" enum:"pending,approved,rejected,expired,consumed," doc:"Filter by status"`
+}
+
+type ListApprovalsOutput struct {
+	Body base.ApiResponse[[]approval.Request]
+}
+
+type DecideApprovalInput struct {
+	ApprovalID string `path:"approvalId" doc:"Approval request ID"`
+	Body       approval.Decision
+}
+
+type DecideApprovalOutput struct {
+	Body base.ApiResponse[approval.Request]
+}
+
+// ============================================================================
+// Registration
+// ============================================================================
+
+// RegisterApprovals registers the approval workflow endpoints.
+func RegisterApprovals(api huma.API, approvalService *services.ApprovalService) {
+	h := &ApprovalHandler{approvalService: approvalService}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "listApprovals",
+		Method:      "GET",
+		Path:        "/approvals",
+		Summary:     "List approval requests",
+		Description: "List approval requests for destructive actions, newest first",
+		Tags:        []string{"Approvals"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.ListApprovals)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "approveRequest",
+		Method:      "POST",
+		Path:        "/approvals/{approvalId}/approve",
+		Summary:     "Approve a request",
+		Description: "Approve a pending request. The approver must be a different admin than the requester.",
+		Tags:        []string{"Approvals"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.Approve)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "rejectRequest",
+		Method:      "POST",
+		Path:        "/approvals/{approvalId}/reject",
+		Summary:     "Reject a request",
+		Description: "Reject a pending request",
+		Tags:        []string{"Approvals"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.Reject)
+}
+
+// ============================================================================
+// Handler Methods
+// ============================================================================
+
+// ListApprovals returns approval requests.
+func (h *ApprovalHandler) ListApprovals(ctx context.Context, input *ListApprovalsInput) (*ListApprovalsOutput, error) {
+	if h.approvalService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	requests, err := h.approvalService.ListRequests(ctx, input.Status)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &ListApprovalsOutput{
+		Body: base.ApiResponse[[]approval.Request]{
+			Success: true,
+			Data:    requests,
+		},
+	}, nil
+}
+
+// Approve approves a pending request.
+func (h *ApprovalHandler) Approve(ctx context.Context, input *DecideApprovalInput) (*DecideApprovalOutput, error) {
+	return h.decide(ctx, input, h.approvalService.Approve)
+}
+
+// Reject rejects a pending request.
+func (h *ApprovalHandler) Reject(ctx context.Context, input *DecideApprovalInput) (*DecideApprovalOutput, error) {
+	return h.decide(ctx, input, h.approvalService.Reject)
+}
+
+type approvalDecisionFunc func(ctx context.Context, id string, approver models.User, reason *string) (*approval.Request, error)
+
+func (h *ApprovalHandler) decide(ctx context.Context, input *DecideApprovalInput, decide approvalDecisionFunc) (*DecideApprovalOutput, error) {
+	if h.approvalService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	req, err := decide(ctx, input.ApprovalID, *user, input.Body.Reason)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrApprovalNotFound):
+			return nil, huma.Error404NotFound(err.Error())
+		case errors.Is(err, services.ErrApprovalSelfApprove):
+			return nil, huma.Error403Forbidden(err.Error())
+		case errors.Is(err, services.ErrApprovalNotPending), errors.Is(err, services.ErrApprovalExpired):
+			return nil, huma.Error409Conflict(err.Error())
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	return &DecideApprovalOutput{
+		Body: base.ApiResponse[approval.Request]{
+			Success: true,
+			Data:    *req,
+		},
+	}, nil
+}
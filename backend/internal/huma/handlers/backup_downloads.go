@@ -0,0 +1,176 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strconv"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/common"
+	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	httputils "github.com/getarcaneapp/arcane/backend/internal/utils/http"
+	"github.com/getarcaneapp/arcane/types/base"
+	volumetypes "github.com/getarcaneapp/arcane/types/volume"
+)
+
+// BackupDownloadHandler handles tokenized, resumable backup downloads.
+type BackupDownloadHandler struct {
+	backupDownloadService *services.BackupDownloadService
+}
+
+// ============================================================================
+// Input/Output Types
+// ============================================================================
+
+type CreateBackupDownloadTokenInput struct {
+	Body volumetypes.BackupDownloadTokenRequest
+}
+
+type CreateBackupDownloadTokenOutput struct {
+	Body base.ApiResponse[volumetypes.BackupDownloadToken]
+}
+
+type DownloadBackupWithTokenInput struct {
+	Token   string `query:"token" required:"true" doc:"Download token from the download-tokens endpoint"`
+	Range   string `header:"Range" doc:"Byte range to resume an interrupted download, e.g. bytes=1048576-"`
+	IfRange string `header:"If-Range" doc:"Only honor Range while the backup still has this ETag"`
+}
+
+// ============================================================================
+// Registration
+// ============================================================================
+
+// RegisterBackupDownloads registers the download link routes. They live
+// outside /environments/{id} so the manager serves them for every environment.
+func RegisterBackupDownloads(api huma.API, backupDownloadService *services.BackupDownloadService) {
+	h := &BackupDownloadHandler{backupDownloadService: backupDownloadService}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "create-volume-backup-download-token",
+		Method:      http.MethodPost,
+		Path:        "/volume-backups/download-tokens",
+		Summary:     "Create a backup download link",
+		Description: "Create a short-lived link that downloads a backup without other credentials and can be resumed with Range requests",
+		Tags:        []string{"Volume Backup"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.CreateToken)
+
+	// The token is the credential, so the route is public.
+	huma.Register(api, huma.Operation{
+		OperationID: "download-volume-backup-with-token",
+		Method:      http.MethodGet,
+		Path:        "/volume-backups/download",
+		Summary:     "Download a backup with a download link",
+		Tags:        []string{"Volume Backup"},
+	}, h.Download)
+}
+
+// ============================================================================
+// Handler Methods
+// ============================================================================
+
+// CreateToken issues a download link for a backup.
+func (h *BackupDownloadHandler) CreateToken(ctx context.Context, input *CreateBackupDownloadTokenInput) (*CreateBackupDownloadTokenOutput, error) {
+	if h.backupDownloadService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	token, err := h.backupDownloadService.IssueToken(ctx, input.Body, *user)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrDownloadEdgeUnsupported):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, services.ErrDownloadBackupNotFound):
+			return nil, huma.Error404NotFound(err.Error())
+		case errors.Is(err, services.ErrNamespaceForbidden):
+			return nil, huma.Error403Forbidden(err.Error())
+		case errors.Is(err, services.ErrTransferEnvironmentFailed):
+			return nil, huma.NewError(http.StatusBadGateway, err.Error())
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	return &CreateBackupDownloadTokenOutput{
+		Body: base.ApiResponse[volumetypes.BackupDownloadToken]{
+			Success: true,
+			Data:    *token,
+		},
+	}, nil
+}
+
+// Download streams the backup granted by a download token.
+func (h *BackupDownloadHandler) Download(ctx context.Context, input *DownloadBackupWithTokenInput) (*huma.StreamResponse, error) {
+	if h.backupDownloadService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	backupID, dl, err := h.backupDownloadService.Open(ctx, input.Token, input.Range, input.IfRange)
+	if err != nil {
+		return nil, backupDownloadErrorInternal(err)
+	}
+	return streamBackupDownloadInternal(backupID, dl), nil
+}
+
+// backupDownloadErrorInternal maps errors from opening a backup download to
+// API errors.
+func backupDownloadErrorInternal(err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidDownloadToken), errors.Is(err, services.ErrNamespaceForbidden):
+		return huma.Error403Forbidden(err.Error())
+	case errors.Is(err, httputils.ErrRangeNotSatisfiable):
+		return huma.NewError(http.StatusRequestedRangeNotSatisfiable, err.Error())
+	case errors.Is(err, services.ErrSnapshotBackupUnsupported):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, services.ErrTransferEnvironmentFailed):
+		return huma.NewError(http.StatusBadGateway, err.Error())
+	default:
+		return huma.Error500InternalServerError(err.Error())
+	}
+}
+
+// streamBackupDownloadInternal writes a backup download, as a 206 partial
+// response when a range was requested.
+func streamBackupDownloadInternal(backupID string, dl *services.BackupDownload) *huma.StreamResponse {
+	return &huma.StreamResponse{
+		Body: func(humaCtx huma.Context) {
+			defer func() { _ = dl.Body.Close() }()
+
+			humaCtx.SetHeader("Content-Type", "application/x-gzip")
+			humaCtx.SetHeader("Content-Disposition", "attachment; filename="+backupID+".tar.gz")
+			if dl.ETag != "" {
+				humaCtx.SetHeader("ETag", dl.ETag)
+			}
+
+			status := http.StatusOK
+			length := dl.Size
+			if dl.Size >= 0 {
+				humaCtx.SetHeader("Accept-Ranges", "bytes")
+			}
+			if dl.Range != nil {
+				status = http.StatusPartialContent
+				length = dl.Range.Length()
+				humaCtx.SetHeader("Content-Range", dl.Range.ContentRange(dl.Size))
+			}
+			if length >= 0 {
+				humaCtx.SetHeader("Content-Length", strconv.FormatInt(length, 10))
+			}
+			humaCtx.SetStatus(status)
+
+			if _, err := io.Copy(humaCtx.BodyWriter(), dl.Body); err != nil {
+				slog.WarnContext(humaCtx.Context(), "Backup download interrupted", "backup_id", backupID, "error", err)
+			}
+		},
+	}
+}
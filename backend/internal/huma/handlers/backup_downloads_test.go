@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackupDownloadErrorInternal_ForeignNamespace(t *testing.T) {
+	err := backupDownloadErrorInternal(fmt.Errorf("%w: volume theirs", services.ErrNamespaceForbidden))
+
+	var statusErr huma.StatusError
+	require.True(t, errors.As(err, &statusErr))
+	assert.Equal(t, http.StatusForbidden, statusErr.GetStatus())
+}
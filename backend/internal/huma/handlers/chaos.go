@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"context"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/chaos"
+	"github.com/getarcaneapp/arcane/types/base"
+	"github.com/getarcaneapp/arcane/types/system"
+)
+
+// chaosUnavailableMessage is returned when chaos mode is off, as it always is
+// in production.
+const chaosUnavailableMessage = "chaos mode is only available when ENVIRONMENT is not production"
+
+// ChaosHandler handles the chaos mode endpoints.
+type ChaosHandler struct {
+	injector *chaos.Injector
+}
+
+// ============================================================================
+// Input/Output Types
+// ============================================================================
+
+type GetChaosInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type GetChaosOutput struct {
+	Body base.ApiResponse[system.ChaosConfig]
+}
+
+type UpdateChaosInput struct {
+	EnvironmentID string             `path:"id" doc:"Environment ID"`
+	Body          system.ChaosConfig `doc:"Fault injection rules"`
+}
+
+type UpdateChaosOutput struct {
+	Body base.ApiResponse[system.ChaosConfig]
+}
+
+// ============================================================================
+// Registration
+// ============================================================================
+
+// RegisterChaos registers the chaos mode endpoints.
+func RegisterChaos(api huma.API, injector *chaos.Injector) {
+	h := &ChaosHandler{injector: injector}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getChaos",
+		Method:      "GET",
+		Path:        "/environments/{id}/system/debug/chaos",
+		Summary:     "Get chaos mode rules",
+		Description: "Get the artificial latency and failure rates injected into Docker API and HTTP API requests. Only available outside production.",
+		Tags:        []string{"System"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.GetChaos)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "updateChaos",
+		Method:      "PUT",
+		Path:        "/environments/{id}/system/debug/chaos",
+		Summary:     "Update chaos mode rules",
+		Description: "Change the artificial latency and failure rates at runtime. Set every value to 0 to turn chaos mode off. Only available outside production.",
+		Tags:        []string{"System"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.UpdateChaos)
+}
+
+// ============================================================================
+// Handler Methods
+// ============================================================================
+
+// GetChaos returns the current chaos mode rules.
+func (h *ChaosHandler) GetChaos(ctx context.Context, input *GetChaosInput) (*GetChaosOutput, error) {
+	if h.injector == nil {
+		return nil, huma.Error404NotFound(chaosUnavailableMessage)
+	}
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	return &GetChaosOutput{
+		Body: base.ApiResponse[system.ChaosConfig]{
+			Success: true,
+			Data:    toChaosConfig(h.injector.Config()),
+		},
+	}, nil
+}
+
+// UpdateChaos replaces the chaos mode rules.
+func (h *ChaosHandler) UpdateChaos(ctx context.Context, input *UpdateChaosInput) (*UpdateChaosOutput, error) {
+	if h.injector == nil {
+		return nil, huma.Error404NotFound(chaosUnavailableMessage)
+	}
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := h.injector.SetConfig(fromChaosConfig(input.Body)); err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+
+	return &UpdateChaosOutput{
+		Body: base.ApiResponse[system.ChaosConfig]{
+			Success: true,
+			Data:    toChaosConfig(h.injector.Config()),
+		},
+	}, nil
+}
+
+func toChaosConfig(cfg chaos.Config) system.ChaosConfig {
+	return system.ChaosConfig{
+		Docker: system.ChaosRule{
+			LatencyMs:   int(cfg.Docker.Latency / time.Millisecond),
+			FailureRate: cfg.Docker.FailureRate,
+		},
+		Api: system.ChaosRule{
+			LatencyMs:   int(cfg.API.Latency / time.Millisecond),
+			FailureRate: cfg.API.FailureRate,
+		},
+	}
+}
+
+func fromChaosConfig(cfg system.ChaosConfig) chaos.Config {
+	return chaos.Config{
+		Docker: chaos.Rule{
+			Latency:     time.Duration(cfg.Docker.LatencyMs) * time.Millisecond,
+			FailureRate: cfg.Docker.FailureRate,
+		},
+		API: chaos.Rule{
+			Latency:     time.Duration(cfg.Api.LatencyMs) * time.Millisecond,
+			FailureRate: cfg.Api.FailureRate,
+		},
+	}
+}
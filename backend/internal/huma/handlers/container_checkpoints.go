@@ -0,0 +1,249 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/common"
+	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/types/base"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+)
+
+// ContainerCheckpointHandler handles the experimental CRIU checkpoint and
+// restore of containers. The containerCheckpoint feature flag gates every
+// endpoint under /checkpoints.
+type ContainerCheckpointHandler struct {
+	containerService *services.ContainerService
+}
+
+type GetCheckpointSupportInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+// CheckpointSupportResponse is a dedicated response type
+type CheckpointSupportResponse struct {
+	Success bool                             `json:"success"`
+	Data    containertypes.CheckpointSupport `json:"data"`
+}
+
+type GetCheckpointSupportOutput struct {
+	Body CheckpointSupportResponse
+}
+
+type ListCheckpointsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container name or ID"`
+}
+
+// CheckpointListResponse is a dedicated response type
+type CheckpointListResponse struct {
+	Success bool                        `json:"success"`
+	Data    []containertypes.Checkpoint `json:"data"`
+}
+
+type ListCheckpointsOutput struct {
+	Body CheckpointListResponse
+}
+
+type CreateCheckpointInput struct {
+	EnvironmentID string                                 `path:"id" doc:"Environment ID"`
+	ContainerID   string                                 `path:"containerId" doc:"Container name or ID"`
+	Body          containertypes.CheckpointCreateRequest `doc:"Checkpoint options"`
+}
+
+// CheckpointResponse is a dedicated response type
+type CheckpointResponse struct {
+	Success bool                      `json:"success"`
+	Data    containertypes.Checkpoint `json:"data"`
+}
+
+type CreateCheckpointOutput struct {
+	Body CheckpointResponse
+}
+
+type CheckpointInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container name or ID"`
+	CheckpointID  string `path:"checkpointId" doc:"Checkpoint name"`
+}
+
+type CheckpointActionOutput struct {
+	Body base.ApiResponse[base.MessageResponse]
+}
+
+// RegisterContainerCheckpoints registers the container checkpoint endpoints.
+func RegisterContainerCheckpoints(api huma.API, containerSvc *services.ContainerService) {
+	h := &ContainerCheckpointHandler{containerService: containerSvc}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-checkpoint-support",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/checkpoint-support",
+		Summary:     "Get checkpoint support",
+		Description: "Report whether the Docker daemon can checkpoint and restore containers (experimental)",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.GetCheckpointSupport)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-container-checkpoints",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/{containerId}/checkpoints",
+		Summary:     "List container checkpoints",
+		Description: "List the CRIU checkpoints of a container (experimental)",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.ListCheckpoints)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "create-container-checkpoint",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/{containerId}/checkpoints",
+		Summary:     "Checkpoint container",
+		Description: "Save the state of a running container with CRIU. The container stops unless leaveRunning is set (experimental)",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.CreateCheckpoint)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "restore-container-checkpoint",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/{containerId}/checkpoints/{checkpointId}/restore",
+		Summary:     "Restore container checkpoint",
+		Description: "Start a stopped container from one of its checkpoints (experimental)",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.RestoreCheckpoint)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-container-checkpoint",
+		Method:      http.MethodDelete,
+		Path:        "/environments/{id}/containers/{containerId}/checkpoints/{checkpointId}",
+		Summary:     "Delete container checkpoint",
+		Description: "Delete a checkpoint of a container (experimental)",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.DeleteCheckpoint)
+}
+
+// GetCheckpointSupport reports whether the daemon can checkpoint containers.
+func (h *ContainerCheckpointHandler) GetCheckpointSupport(ctx context.Context, input *GetCheckpointSupportInput) (*GetCheckpointSupportOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	support, err := h.containerService.CheckpointSupport(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &GetCheckpointSupportOutput{
+		Body: CheckpointSupportResponse{
+			Success: true,
+			Data:    *support,
+		},
+	}, nil
+}
+
+// ListCheckpoints lists the checkpoints of a container.
+func (h *ContainerCheckpointHandler) ListCheckpoints(ctx context.Context, input *ListCheckpointsInput) (*ListCheckpointsOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	checkpoints, err := h.containerService.ListCheckpoints(ctx, input.ContainerID)
+	if err != nil {
+		return nil, checkpointErrorInternal(err)
+	}
+
+	return &ListCheckpointsOutput{
+		Body: CheckpointListResponse{
+			Success: true,
+			Data:    checkpoints,
+		},
+	}, nil
+}
+
+// CreateCheckpoint checkpoints a running container.
+func (h *ContainerCheckpointHandler) CreateCheckpoint(ctx context.Context, input *CreateCheckpointInput) (*CreateCheckpointOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	checkpoint, err := h.containerService.CreateCheckpoint(ctx, input.ContainerID, input.Body, *user)
+	if err != nil {
+		return nil, checkpointErrorInternal(err)
+	}
+
+	return &CreateCheckpointOutput{
+		Body: CheckpointResponse{
+			Success: true,
+			Data:    *checkpoint,
+		},
+	}, nil
+}
+
+// RestoreCheckpoint starts a stopped container from a checkpoint.
+func (h *ContainerCheckpointHandler) RestoreCheckpoint(ctx context.Context, input *CheckpointInput) (*CheckpointActionOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	if err := h.containerService.RestoreCheckpoint(ctx, input.ContainerID, input.CheckpointID, *user); err != nil {
+		return nil, checkpointErrorInternal(err)
+	}
+
+	return &CheckpointActionOutput{
+		Body: base.ApiResponse[base.MessageResponse]{
+			Success: true,
+			Data:    base.MessageResponse{Message: "Container restored from checkpoint"},
+		},
+	}, nil
+}
+
+// DeleteCheckpoint deletes a checkpoint of a container.
+func (h *ContainerCheckpointHandler) DeleteCheckpoint(ctx context.Context, input *CheckpointInput) (*CheckpointActionOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	if err := h.containerService.DeleteCheckpoint(ctx, input.ContainerID, input.CheckpointID); err != nil {
+		return nil, checkpointErrorInternal(err)
+	}
+
+	return &CheckpointActionOutput{
+		Body: base.ApiResponse[base.MessageResponse]{
+			Success: true,
+			Data:    base.MessageResponse{Message: "Checkpoint deleted"},
+		},
+	}, nil
+}
+
+func checkpointErrorInternal(err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidCheckpoint):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, services.ErrDockerContainerNotFound), errors.Is(err, services.ErrCheckpointNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, services.ErrContainerNotRunning), errors.Is(err, services.ErrCheckpointContainerRunning):
+		return huma.Error409Conflict(err.Error())
+	case errors.Is(err, services.ErrCheckpointUnsupported):
+		return huma.Error501NotImplemented(err.Error())
+	default:
+		return huma.Error500InternalServerError(err.Error())
+	}
+}
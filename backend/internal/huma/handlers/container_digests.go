@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/common"
+	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/types/base"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+)
+
+// ContainerDigestHandler handles deployed digest records, tag drift checks
+// and container pins.
+type ContainerDigestHandler struct {
+	containerDigestService *services.ContainerDigestService
+}
+
+type ListContainerDigestsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+// ContainerDigestsResponse is a dedicated response type
+type ContainerDigestsResponse struct {
+	Success bool                          `json:"success"`
+	Data    []containertypes.DigestStatus `json:"data"`
+}
+
+type ListContainerDigestsOutput struct {
+	Body ContainerDigestsResponse
+}
+
+type CheckTagDriftInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+// TagDriftCheckResponse is a dedicated response type
+type TagDriftCheckResponse struct {
+	Success bool                            `json:"success"`
+	Data    containertypes.DriftCheckResult `json:"data"`
+}
+
+type CheckTagDriftOutput struct {
+	Body TagDriftCheckResponse
+}
+
+type GetContainerDigestInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container name or ID"`
+}
+
+// ContainerDigestResponse is a dedicated response type
+type ContainerDigestResponse struct {
+	Success bool                        `json:"success"`
+	Data    containertypes.DigestStatus `json:"data"`
+}
+
+type GetContainerDigestOutput struct {
+	Body ContainerDigestResponse
+}
+
+type PinContainerInput struct {
+	EnvironmentID string                          `path:"id" doc:"Environment ID"`
+	ContainerID   string                          `path:"containerId" doc:"Container name or ID"`
+	Body          containertypes.DigestPinRequest `doc:"Pin details"`
+}
+
+type PinContainerOutput struct {
+	Body ContainerDigestResponse
+}
+
+type UnpinContainerInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container name or ID"`
+}
+
+type UnpinContainerOutput struct {
+	Body ContainerActionResponse
+}
+
+// RegisterContainerDigests registers deployed digest, tag drift and container
+// pin endpoints.
+func RegisterContainerDigests(api huma.API, containerDigestSvc *services.ContainerDigestService) {
+	h := &ContainerDigestHandler{containerDigestService: containerDigestSvc}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-container-digests",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/digests",
+		Summary:     "List container digests",
+		Description: "List the digest each container was deployed from, the result of the last tag drift check and whether the container is pinned",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.ListContainerDigests)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "check-container-tag-drift",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/digests/check",
+		Summary:     "Check tag drift",
+		Description: "Look up the digest each container's tag points to in its registry and flag containers running a different digest",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.CheckTagDrift)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-container-digest",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/{containerId}/digest",
+		Summary:     "Get container digest",
+		Description: "Get the digest a container was deployed from, recording it if it was not yet tracked",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.GetContainerDigest)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "pin-container",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/{containerId}/pin",
+		Summary:     "Pin container",
+		Description: "Keep a container on the digest it runs; auto-update skips pinned containers and containers of Arcane projects are pinned in the compose file",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.PinContainer)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "unpin-container",
+		Method:      http.MethodDelete,
+		Path:        "/environments/{id}/containers/{containerId}/pin",
+		Summary:     "Unpin container",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.UnpinContainer)
+}
+
+// ListContainerDigests returns the recorded digests of all tracked
+// containers.
+func (h *ContainerDigestHandler) ListContainerDigests(ctx context.Context, _ *ListContainerDigestsInput) (*ListContainerDigestsOutput, error) {
+	if h.containerDigestService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	items, err := h.containerDigestService.ListContainerDigests(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &ListContainerDigestsOutput{
+		Body: ContainerDigestsResponse{
+			Success: true,
+			Data:    items,
+		},
+	}, nil
+}
+
+// CheckTagDrift runs a tag drift check over all containers.
+func (h *ContainerDigestHandler) CheckTagDrift(ctx context.Context, _ *CheckTagDriftInput) (*CheckTagDriftOutput, error) {
+	if h.containerDigestService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	if _, exists := humamw.GetCurrentUserFromContext(ctx); !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	result, err := h.containerDigestService.CheckTagDrift(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &CheckTagDriftOutput{
+		Body: TagDriftCheckResponse{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
+// GetContainerDigest returns the recorded digest of a container.
+func (h *ContainerDigestHandler) GetContainerDigest(ctx context.Context, input *GetContainerDigestInput) (*GetContainerDigestOutput, error) {
+	if h.containerDigestService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	status, err := h.containerDigestService.GetContainerDigest(ctx, input.ContainerID)
+	if err != nil {
+		if errors.Is(err, services.ErrDockerContainerNotFound) {
+			return nil, huma.Error404NotFound(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &GetContainerDigestOutput{
+		Body: ContainerDigestResponse{
+			Success: true,
+			Data:    *status,
+		},
+	}, nil
+}
+
+// PinContainer pins a container to the digest it runs.
+func (h *ContainerDigestHandler) PinContainer(ctx context.Context, input *PinContainerInput) (*PinContainerOutput, error) {
+	if h.containerDigestService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	status, err := h.containerDigestService.PinContainer(ctx, input.ContainerID, input.Body.Reason, *user)
+	if err != nil {
+		if errors.Is(err, services.ErrDockerContainerNotFound) {
+			return nil, huma.Error404NotFound(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &PinContainerOutput{
+		Body: ContainerDigestResponse{
+			Success: true,
+			Data:    *status,
+		},
+	}, nil
+}
+
+// UnpinContainer removes the pin from a container.
+func (h *ContainerDigestHandler) UnpinContainer(ctx context.Context, input *UnpinContainerInput) (*UnpinContainerOutput, error) {
+	if h.containerDigestService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	if err := h.containerDigestService.UnpinContainer(ctx, input.ContainerID, *user); err != nil {
+		if errors.Is(err, services.ErrContainerNotPinned) {
+			return nil, huma.Error404NotFound(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &UnpinContainerOutput{
+		Body: ContainerActionResponse{
+			Success: true,
+			Data: base.MessageResponse{
+				Message: "Container unpinned successfully",
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/common"
+	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/types/base"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+)
+
+// ContainerHealthcheckHandler handles Arcane-managed container healthchecks
+// and the recorded health history of containers.
+type ContainerHealthcheckHandler struct {
+	healthcheckService   *services.ContainerHealthcheckService
+	healthHistoryService *services.ContainerHealthHistoryService
+}
+
+type ListContainerHealthchecksInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+// ContainerHealthchecksResponse is a dedicated response type
+type ContainerHealthchecksResponse struct {
+	Success bool                         `json:"success"`
+	Data    []containertypes.Healthcheck `json:"data"`
+}
+
+type ListContainerHealthchecksOutput struct {
+	Body ContainerHealthchecksResponse
+}
+
+type SetContainerHealthcheckInput struct {
+	EnvironmentID string                            `path:"id" doc:"Environment ID"`
+	ContainerID   string                            `path:"containerId" doc:"Container name or ID"`
+	Body          containertypes.HealthcheckRequest `doc:"Healthcheck definition"`
+}
+
+// ContainerHealthcheckResponse is a dedicated response type
+type ContainerHealthcheckResponse struct {
+	Success bool                       `json:"success"`
+	Data    containertypes.Healthcheck `json:"data"`
+}
+
+type SetContainerHealthcheckOutput struct {
+	Body ContainerHealthcheckResponse
+}
+
+type DeleteContainerHealthcheckInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container name or ID"`
+}
+
+type DeleteContainerHealthcheckOutput struct {
+	Body ContainerActionResponse
+}
+
+type GetContainerHealthHistoryInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container name or ID"`
+	Hours         int    `query:"hours" default:"24" minimum:"1" maximum:"744" doc:"Number of hours of history to return"`
+}
+
+// ContainerHealthHistoryResponse is a dedicated response type
+type ContainerHealthHistoryResponse struct {
+	Success bool                         `json:"success"`
+	Data    containertypes.HealthHistory `json:"data"`
+}
+
+type GetContainerHealthHistoryOutput struct {
+	Body ContainerHealthHistoryResponse
+}
+
+// RegisterContainerHealthchecks registers Arcane-managed healthcheck and
+// health history endpoints.
+func RegisterContainerHealthchecks(api huma.API, healthcheckSvc *services.ContainerHealthcheckService, healthHistorySvc *services.ContainerHealthHistoryService) {
+	h := &ContainerHealthcheckHandler{healthcheckService: healthcheckSvc, healthHistoryService: healthHistorySvc}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-container-healthchecks",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/healthchecks",
+		Summary:     "List container healthchecks",
+		Description: "List the healthchecks Arcane runs against containers and their last results",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.ListHealthchecks)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "set-container-healthcheck",
+		Method:      http.MethodPut,
+		Path:        "/environments/{id}/containers/{containerId}/healthcheck",
+		Summary:     "Set container healthcheck",
+		Description: "Define an HTTP, TCP or command probe that Arcane runs against a container, keyed by container name or ID",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.SetHealthcheck)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-container-healthcheck",
+		Method:      http.MethodDelete,
+		Path:        "/environments/{id}/containers/{containerId}/healthcheck",
+		Summary:     "Delete container healthcheck",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.DeleteHealthcheck)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-container-health-history",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/{containerId}/health-history",
+		Summary:     "Get container health history",
+		Description: "List the health status changes reported by Docker for a container, newest first",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.GetHealthHistory)
+}
+
+// GetHealthHistory returns the recorded health transitions of a container.
+func (h *ContainerHealthcheckHandler) GetHealthHistory(ctx context.Context, input *GetContainerHealthHistoryInput) (*GetContainerHealthHistoryOutput, error) {
+	if h.healthHistoryService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	history, err := h.healthHistoryService.GetHistory(ctx, input.ContainerID, time.Duration(input.Hours)*time.Hour)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &GetContainerHealthHistoryOutput{
+		Body: ContainerHealthHistoryResponse{
+			Success: true,
+			Data:    *history,
+		},
+	}, nil
+}
+
+// ListHealthchecks returns all Arcane-managed healthchecks.
+func (h *ContainerHealthcheckHandler) ListHealthchecks(ctx context.Context, input *ListContainerHealthchecksInput) (*ListContainerHealthchecksOutput, error) {
+	if h.healthcheckService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	checks, err := h.healthcheckService.ListHealthchecks(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &ListContainerHealthchecksOutput{
+		Body: ContainerHealthchecksResponse{
+			Success: true,
+			Data:    checks,
+		},
+	}, nil
+}
+
+// SetHealthcheck creates or replaces the healthcheck for a container.
+func (h *ContainerHealthcheckHandler) SetHealthcheck(ctx context.Context, input *SetContainerHealthcheckInput) (*SetContainerHealthcheckOutput, error) {
+	if h.healthcheckService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	check, err := h.healthcheckService.SetHealthcheck(ctx, input.ContainerID, input.Body, *user)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidContainerHealthcheck) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &SetContainerHealthcheckOutput{
+		Body: ContainerHealthcheckResponse{
+			Success: true,
+			Data:    *check,
+		},
+	}, nil
+}
+
+// DeleteHealthcheck removes the healthcheck for a container.
+func (h *ContainerHealthcheckHandler) DeleteHealthcheck(ctx context.Context, input *DeleteContainerHealthcheckInput) (*DeleteContainerHealthcheckOutput, error) {
+	if h.healthcheckService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	if _, exists := humamw.GetCurrentUserFromContext(ctx); !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	if err := h.healthcheckService.DeleteHealthcheck(ctx, input.ContainerID); err != nil {
+		if errors.Is(err, services.ErrContainerHealthcheckNotFound) {
+			return nil, huma.Error404NotFound(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &DeleteContainerHealthcheckOutput{
+		Body: ContainerActionResponse{
+			Success: true,
+			Data: base.MessageResponse{
+				Message: "Container healthcheck removed successfully",
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,125 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/common"
+	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+)
+
+// ContainerRollbackHandler handles rolling containers back to the image they
+// ran before their last auto-update.
+type ContainerRollbackHandler struct {
+	updaterService *services.UpdaterService
+}
+
+type GetContainerRollbackInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container name or ID"`
+}
+
+// ContainerRollbackResponse is a dedicated response type
+type ContainerRollbackResponse struct {
+	Success bool                          `json:"success"`
+	Data    containertypes.RollbackStatus `json:"data"`
+}
+
+type GetContainerRollbackOutput struct {
+	Body ContainerRollbackResponse
+}
+
+type RollbackContainerInput struct {
+	EnvironmentID string                         `path:"id" doc:"Environment ID"`
+	ContainerID   string                         `path:"containerId" doc:"Container name or ID"`
+	Body          containertypes.RollbackRequest `doc:"Rollback options"`
+}
+
+type RollbackContainerOutput struct {
+	Body ContainerRollbackResponse
+}
+
+// RegisterContainerRollbacks registers the container rollback endpoints.
+func RegisterContainerRollbacks(api huma.API, updaterSvc *services.UpdaterService) {
+	h := &ContainerRollbackHandler{updaterService: updaterSvc}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-container-rollback",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/{containerId}/rollback",
+		Summary:     "Get container rollback",
+		Description: "Get the last auto-update of a container and whether it can still be rolled back",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.GetContainerRollback)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "rollback-container",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/{containerId}/rollback",
+		Summary:     "Roll back container",
+		Description: "Recreate a container on the image and configuration it had before its last auto-update. Only unhealthy containers are rolled back unless force is set",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.RollbackContainer)
+}
+
+// GetContainerRollback returns the rollback status of a container.
+func (h *ContainerRollbackHandler) GetContainerRollback(ctx context.Context, input *GetContainerRollbackInput) (*GetContainerRollbackOutput, error) {
+	if h.updaterService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	status, err := h.updaterService.GetRollback(ctx, input.ContainerID)
+	if err != nil {
+		return nil, rollbackErrorInternal(err)
+	}
+
+	return &GetContainerRollbackOutput{
+		Body: ContainerRollbackResponse{
+			Success: true,
+			Data:    *status,
+		},
+	}, nil
+}
+
+// RollbackContainer rolls a container back to its previous image.
+func (h *ContainerRollbackHandler) RollbackContainer(ctx context.Context, input *RollbackContainerInput) (*RollbackContainerOutput, error) {
+	if h.updaterService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	status, err := h.updaterService.RollbackContainer(ctx, input.ContainerID, input.Body.Force, *user)
+	if err != nil {
+		return nil, rollbackErrorInternal(err)
+	}
+
+	return &RollbackContainerOutput{
+		Body: ContainerRollbackResponse{
+			Success: true,
+			Data:    *status,
+		},
+	}, nil
+}
+
+func rollbackErrorInternal(err error) error {
+	switch {
+	case errors.Is(err, services.ErrDockerContainerNotFound), errors.Is(err, services.ErrNoRollback):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, services.ErrRollbackNotNeeded), errors.Is(err, services.ErrAlreadyRolledBack):
+		return huma.Error409Conflict(err.Error())
+	case errors.Is(err, services.ErrRollbackExpired), errors.Is(err, services.ErrRollbackImageMissing):
+		return huma.Error410Gone(err.Error())
+	default:
+		return huma.Error500InternalServerError(err.Error())
+	}
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+)
+
+// ContainerStatsHandler handles aggregated container stats.
+type ContainerStatsHandler struct {
+	statsService *services.StatsAggregatorService
+}
+
+type GetContainerStatsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+// ContainerStatsResponse is a dedicated response type
+type ContainerStatsResponse struct {
+	Success bool                          `json:"success"`
+	Data    containertypes.AggregateStats `json:"data"`
+}
+
+type GetContainerStatsOutput struct {
+	Body ContainerStatsResponse
+}
+
+// RegisterContainerStats registers the aggregated container stats endpoint.
+func RegisterContainerStats(api huma.API, statsSvc *services.StatsAggregatorService) {
+	h := &ContainerStatsHandler{statsService: statsSvc}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-container-stats",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/stats",
+		Summary:     "Get aggregated container stats",
+		Description: "Get CPU, memory, network and block I/O usage of all running containers and their totals for the host",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.GetStats)
+}
+
+// GetStats returns the latest aggregated stats snapshot.
+func (h *ContainerStatsHandler) GetStats(ctx context.Context, input *GetContainerStatsInput) (*GetContainerStatsOutput, error) {
+	if h.statsService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	stats, err := h.statsService.GetStats(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &GetContainerStatsOutput{
+		Body: ContainerStatsResponse{
+			Success: true,
+			Data:    *stats,
+		},
+	}, nil
+}
@@ -2,6 +2,9 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"io"
+	"log/slog"
 	"net/http"
 	"strings"
 
@@ -62,8 +65,9 @@ type GetContainerStatusCountsOutput struct {
 }
 
 type CreateContainerInput struct {
-	EnvironmentID string `path:"id" doc:"Environment ID"`
-	Body          containertypes.Create
+	EnvironmentID    string `path:"id" doc:"Environment ID"`
+	OverrideScanGate bool   `query:"overrideScanGate" default:"false" doc:"Create the container even if the vulnerability scan gate blocks its image (admin only, recorded as an event)"`
+	Body             containertypes.Create
 }
 
 // ContainerCreatedResponse is a dedicated response type
@@ -96,6 +100,12 @@ type ContainerActionInput struct {
 	ContainerID   string `path:"containerId" doc:"Container ID"`
 }
 
+type ContainerStopInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+	Timeout       int    `query:"timeout" default:"0" minimum:"0" maximum:"3600" doc:"Seconds to wait before killing the container; 0 uses the com.getarcaneapp.arcane.stop-timeout label or the container's stop timeout"`
+}
+
 // ContainerActionResponse is a dedicated response type
 type ContainerActionResponse struct {
 	Success bool                 `json:"success"`
@@ -106,17 +116,241 @@ type ContainerActionOutput struct {
 	Body ContainerActionResponse
 }
 
+type RenameContainerInput struct {
+	EnvironmentID string                       `path:"id" doc:"Environment ID"`
+	ContainerID   string                       `path:"containerId" doc:"Container ID"`
+	Body          containertypes.RenameRequest `doc:"New container name"`
+}
+
+type RecreateContainerInput struct {
+	EnvironmentID    string                         `path:"id" doc:"Environment ID"`
+	ContainerID      string                         `path:"containerId" doc:"Container ID"`
+	OverrideScanGate bool                           `query:"overrideScanGate" default:"false" doc:"Recreate the container even if the vulnerability scan gate blocks its new image (admin only, recorded as an event)"`
+	Body             containertypes.RecreateRequest `doc:"Configuration changes to apply"`
+}
+
+type SetContainerLabelsInput struct {
+	EnvironmentID string                       `path:"id" doc:"Environment ID"`
+	ContainerID   string                       `path:"containerId" doc:"Container ID"`
+	Body          containertypes.LabelsRequest `doc:"New label set"`
+}
+
+type UpdateContainerResourcesInput struct {
+	EnvironmentID string                         `path:"id" doc:"Environment ID"`
+	ContainerID   string                         `path:"containerId" doc:"Container ID"`
+	Body          containertypes.ResourcesUpdate `doc:"Resource changes to apply"`
+}
+
+// ContainerResourcesUpdateResponse is a dedicated response type
+type ContainerResourcesUpdateResponse struct {
+	Success bool                                 `json:"success"`
+	Data    containertypes.ResourcesUpdateResult `json:"data"`
+}
+
+type UpdateContainerResourcesOutput struct {
+	Body ContainerResourcesUpdateResponse
+}
+
+type GetContainerProcessesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+	PsArgs        string `query:"psArgs" doc:"Arguments passed to ps on the Docker host (default -ef)"`
+}
+
+// ContainerProcessesResponse is a dedicated response type
+type ContainerProcessesResponse struct {
+	Success bool                       `json:"success"`
+	Data    containertypes.ProcessList `json:"data"`
+}
+
+type GetContainerProcessesOutput struct {
+	Body ContainerProcessesResponse
+}
+
+type ResizeContainerExecInput struct {
+	EnvironmentID string                           `path:"id" doc:"Environment ID"`
+	ContainerID   string                           `path:"containerId" doc:"Container ID"`
+	ExecID        string                           `path:"execId" doc:"Exec session ID"`
+	Body          containertypes.ExecResizeRequest `doc:"New terminal size"`
+}
+
+type ResizeContainerExecOutput struct {
+	Body ContainerActionResponse
+}
+
+type GetContainerSnapshotInfoInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+}
+
+// ContainerSnapshotInfoResponse is a dedicated response type
+type ContainerSnapshotInfoResponse struct {
+	Success bool                        `json:"success"`
+	Data    containertypes.SnapshotInfo `json:"data"`
+}
+
+type GetContainerSnapshotInfoOutput struct {
+	Body ContainerSnapshotInfoResponse
+}
+
+type DiffContainersInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Left          string `query:"left" required:"true" doc:"ID or name of the container the changes are relative to"`
+	Right         string `query:"right" required:"true" doc:"ID or name of the container to compare"`
+}
+
+// ContainerDiffResponse is a dedicated response type
+type ContainerDiffResponse struct {
+	Success bool                `json:"success"`
+	Data    containertypes.Diff `json:"data"`
+}
+
+type DiffContainersOutput struct {
+	Body ContainerDiffResponse
+}
+
+type CommitContainerInput struct {
+	EnvironmentID string                       `path:"id" doc:"Environment ID"`
+	ContainerID   string                       `path:"containerId" doc:"Container ID"`
+	Body          containertypes.CommitRequest `doc:"Image to create from the container"`
+}
+
+// ContainerCommitResponse is a dedicated response type
+type ContainerCommitResponse struct {
+	Success bool                        `json:"success"`
+	Data    containertypes.CommitResult `json:"data"`
+}
+
+type CommitContainerOutput struct {
+	Body ContainerCommitResponse
+}
+
+type ExportContainerInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+}
+
+type DownloadContainerLogsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+	Since         string `query:"since" doc:"Only logs after this time (RFC 3339, Unix timestamp or duration such as 1h)"`
+	Until         string `query:"until" doc:"Only logs before this time (RFC 3339, Unix timestamp or duration such as 1h)"`
+	Stdout        bool   `query:"stdout" default:"true" doc:"Include stdout"`
+	Stderr        bool   `query:"stderr" default:"true" doc:"Include stderr"`
+	Timestamps    bool   `query:"timestamps" default:"false" doc:"Prefix lines with their timestamp"`
+	Format        string `query:"format" default:"text" enum:"text,gzip" doc:"File format"`
+	MaxBytes      int64  `query:"maxBytes" default:"0" minimum:"0" doc:"Size cap in bytes before compression (0 uses the default of 50 MiB)"`
+}
+
+type BulkContainerActionInput struct {
+	EnvironmentID string                           `path:"id" doc:"Environment ID"`
+	Body          containertypes.BulkActionRequest `doc:"Action and containers to act on"`
+}
+
+// ContainerBulkActionResponse is a dedicated response type
+type ContainerBulkActionResponse struct {
+	Success bool                            `json:"success"`
+	Data    containertypes.BulkActionResult `json:"data"`
+}
+
+type BulkContainerActionOutput struct {
+	Body ContainerBulkActionResponse
+}
+
 type DeleteContainerInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	ContainerID   string `path:"containerId" doc:"Container ID"`
 	Force         bool   `query:"force" default:"false" doc:"Force delete running container"`
 	RemoveVolumes bool   `query:"volumes" default:"false" doc:"Remove associated volumes"`
+	Permanent     bool   `query:"permanent" default:"false" doc:"Remove the container right away instead of moving it to the trash"`
 }
 
 type DeleteContainerOutput struct {
 	Body ContainerActionResponse
 }
 
+type ListContainerTrashInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type ListContainerTrashOutput struct {
+	Body base.ApiResponse[[]containertypes.TrashEntry]
+}
+
+type ContainerTrashEntryInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container ID"`
+}
+
+type AuditRestartPoliciesInput struct {
+	EnvironmentID   string `path:"id" doc:"Environment ID"`
+	IncludeInternal bool   `query:"includeInternal" default:"false" doc:"Include internal containers"`
+}
+
+// RestartPolicyAuditResponse is a dedicated response type
+type RestartPolicyAuditResponse struct {
+	Success bool                              `json:"success"`
+	Data    containertypes.RestartPolicyAudit `json:"data"`
+}
+
+type AuditRestartPoliciesOutput struct {
+	Body RestartPolicyAuditResponse
+}
+
+type UpdateRestartPoliciesInput struct {
+	EnvironmentID string                             `path:"id" doc:"Environment ID"`
+	Body          containertypes.RestartPolicyUpdate `doc:"Restart policy update request"`
+}
+
+// RestartPolicyUpdateResponse is a dedicated response type
+type RestartPolicyUpdateResponse struct {
+	Success bool                                     `json:"success"`
+	Data    containertypes.RestartPolicyUpdateResult `json:"data"`
+}
+
+type UpdateRestartPoliciesOutput struct {
+	Body RestartPolicyUpdateResponse
+}
+
+type ListContainerOverridesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+// ContainerOverridesResponse is a dedicated response type
+type ContainerOverridesResponse struct {
+	Success bool                      `json:"success"`
+	Data    []containertypes.Override `json:"data"`
+}
+
+type ListContainerOverridesOutput struct {
+	Body ContainerOverridesResponse
+}
+
+type SetContainerOverrideInput struct {
+	EnvironmentID string                         `path:"id" doc:"Environment ID"`
+	ContainerID   string                         `path:"containerId" doc:"Container name or ID"`
+	Body          containertypes.OverrideRequest `doc:"Display overrides for the container"`
+}
+
+// ContainerOverrideResponse is a dedicated response type
+type ContainerOverrideResponse struct {
+	Success bool                    `json:"success"`
+	Data    containertypes.Override `json:"data"`
+}
+
+type SetContainerOverrideOutput struct {
+	Body ContainerOverrideResponse
+}
+
+type DeleteContainerOverrideInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ContainerID   string `path:"containerId" doc:"Container name or ID"`
+}
+
+type DeleteContainerOverrideOutput struct {
+	Body ContainerActionResponse
+}
+
 // RegisterContainers registers container endpoints.
 func RegisterContainers(api huma.API, containerSvc *services.ContainerService, dockerSvc *services.DockerClientService) {
 	h := &ContainerHandler{
@@ -143,6 +377,85 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
 	}, h.GetContainerStatusCounts)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "audit-container-restart-policies",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/restart-policies",
+		Summary:     "Audit container restart policies",
+		Description: "List containers that will not come back after a reboot or that use 'always' instead of 'unless-stopped'",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.AuditRestartPolicies)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-container-restart-policies",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/restart-policies",
+		Summary:     "Update container restart policies",
+		Description: "Apply a restart policy to multiple containers by recreating them, with per-container results",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.UpdateRestartPolicies)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-container-overrides",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/overrides",
+		Summary:     "List container display overrides",
+		Description: "List the display names and icons set for containers",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.ListContainerOverrides)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-container-trash",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/trash",
+		Summary:     "List trashed containers",
+		Description: "List deleted containers that are kept in the trash until their retention ends",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.ListContainerTrash)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "restore-container-from-trash",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/trash/{containerId}/restore",
+		Summary:     "Restore trashed container",
+		Description: "Give a trashed container its name and restart policy back, and start it again if it was running when it was deleted",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.RestoreContainerFromTrash)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "purge-container-from-trash",
+		Method:      http.MethodDelete,
+		Path:        "/environments/{id}/containers/trash/{containerId}",
+		Summary:     "Purge trashed container",
+		Description: "Remove a trashed container for good without waiting for its retention to end",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.PurgeContainerFromTrash)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "set-container-override",
+		Method:      http.MethodPut,
+		Path:        "/environments/{id}/containers/{containerId}/override",
+		Summary:     "Set container display override",
+		Description: "Set the display name and icon shown for a container, keyed by container name or ID",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.SetContainerOverride)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-container-override",
+		Method:      http.MethodDelete,
+		Path:        "/environments/{id}/containers/{containerId}/override",
+		Summary:     "Delete container display override",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.DeleteContainerOverride)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "create-container",
 		Method:      http.MethodPost,
@@ -161,6 +474,86 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
 	}, h.GetContainer)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "get-container-processes",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/{containerId}/processes",
+		Summary:     "List container processes",
+		Description: "Get the process table of a running container, like docker top",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.GetContainerProcesses)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "resize-container-exec",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/{containerId}/exec/{execId}/resize",
+		Summary:     "Resize exec terminal",
+		Description: "Change the terminal size of a running exec session, such as the one behind the container terminal",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.ResizeContainerExec)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-container-snapshot-info",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/{containerId}/snapshot",
+		Summary:     "Get container snapshot info",
+		Description: "Estimate the size of committing or exporting a container and list the volumes a snapshot leaves out",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.GetContainerSnapshotInfo)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "diff-containers",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/diff",
+		Summary:     "Diff containers",
+		Description: "Compare the configuration of two containers, such as the old and new container of a service after an update",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.DiffContainers)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "commit-container",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/{containerId}/commit",
+		Summary:     "Commit container",
+		Description: "Snapshot a container into a new image tagged repository:tag",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.CommitContainer)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "export-container",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/{containerId}/export",
+		Summary:     "Export container filesystem",
+		Description: "Download the filesystem of a container as a tar archive",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.ExportContainer)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "download-container-logs",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/containers/{containerId}/logs/download",
+		Summary:     "Download container logs",
+		Description: "Download the logs of a container for a time range as a plain text or gzip file",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.DownloadContainerLogs)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "bulk-container-action",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/bulk",
+		Summary:     "Run an action on several containers",
+		Description: "Start, stop, restart or delete several containers in one call and report the outcome for each",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.BulkContainerAction)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "start-container",
 		Method:      http.MethodPost,
@@ -188,11 +581,52 @@ func RegisterContainers(api huma.API, containerSvc *services.ContainerService, d
 		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
 	}, h.RestartContainer)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "rename-container",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/{containerId}/rename",
+		Summary:     "Rename container",
+		Description: "Rename a container without recreating it",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.RenameContainer)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "recreate-container",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/containers/{containerId}/recreate",
+		Summary:     "Recreate container",
+		Description: "Replace a container with a copy that has the given image, environment, restart policy, port or label changes. The previous container is restored if the replacement fails to start.",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.RecreateContainer)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "set-container-labels",
+		Method:      http.MethodPut,
+		Path:        "/environments/{id}/containers/{containerId}/labels",
+		Summary:     "Replace container labels",
+		Description: "Replace a container's labels, for example to adopt it into a project or namespace. Docker cannot change labels in place, so the container is recreated with the rest of its configuration unchanged.",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.SetContainerLabels)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-container-resources",
+		Method:      http.MethodPut,
+		Path:        "/environments/{id}/containers/{containerId}/resources",
+		Summary:     "Update container resources",
+		Description: "Change CPU shares, memory limits and the restart policy of a container without recreating it",
+		Tags:        []string{"Containers"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.UpdateContainerResources)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "delete-container",
 		Method:      http.MethodDelete,
 		Path:        "/environments/{id}/containers/{containerId}",
 		Summary:     "Delete container",
+		Description: "Delete a container. When the container trash retention setting is above zero the container is stopped and kept in the trash until the retention ends, unless permanent is set.",
 		Tags:        []string{"Containers"},
 		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
 	}, h.DeleteContainer)
@@ -285,45 +719,159 @@ func (h *ContainerHandler) GetContainerStatusCounts(ctx context.Context, input *
 	}, nil
 }
 
-func parsePortSpec(spec string) (nat.Port, error) {
-	proto := "tcp"
-	port := spec
-	if strings.Contains(spec, "/") {
-		parts := strings.SplitN(spec, "/", 2)
-		port = parts[0]
-		if parts[1] != "" {
-			proto = parts[1]
-		}
+func (h *ContainerHandler) AuditRestartPolicies(ctx context.Context, input *AuditRestartPoliciesInput) (*AuditRestartPoliciesOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
 	}
 
-	return nat.NewPort(proto, port)
+	audit, err := h.containerService.AuditRestartPolicies(ctx, input.IncludeInternal)
+	if err != nil {
+		return nil, huma.Error500InternalServerError((&common.ContainerRestartPolicyAuditError{Err: err}).Error())
+	}
+
+	return &AuditRestartPoliciesOutput{
+		Body: RestartPolicyAuditResponse{
+			Success: true,
+			Data:    *audit,
+		},
+	}, nil
 }
 
-func resolveCreateCommand(body containertypes.Create) []string {
-	if len(body.Command) > 0 {
-		return body.Command
+func (h *ContainerHandler) UpdateRestartPolicies(ctx context.Context, input *UpdateRestartPoliciesInput) (*UpdateRestartPoliciesOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
 	}
 
-	return body.Cmd
-}
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized("not authenticated")
+	}
 
-func resolveCreateEnv(body containertypes.Create) []string {
-	if len(body.Environment) > 0 {
-		return body.Environment
+	result, err := h.containerService.UpdateRestartPolicies(ctx, input.Body, *user)
+	if err != nil {
+		return nil, huma.Error400BadRequest((&common.ContainerRestartPolicyUpdateError{Err: err}).Error())
 	}
 
-	return body.Env
+	return &UpdateRestartPoliciesOutput{
+		Body: RestartPolicyUpdateResponse{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
 }
 
-func buildCreateLabels(body containertypes.Create) map[string]string {
-	labels := map[string]string{
-		"com.arcane.created": "true",
+// ListContainerOverrides returns all container display overrides.
+func (h *ContainerHandler) ListContainerOverrides(ctx context.Context, input *ListContainerOverridesInput) (*ListContainerOverridesOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
 	}
-	for key, value := range body.Labels {
-		labels[key] = value
+
+	overrides, err := h.containerService.ListContainerOverrides(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
 	}
 
-	return labels
+	return &ListContainerOverridesOutput{
+		Body: ContainerOverridesResponse{
+			Success: true,
+			Data:    overrides,
+		},
+	}, nil
+}
+
+// SetContainerOverride sets the display name and icon for a container.
+func (h *ContainerHandler) SetContainerOverride(ctx context.Context, input *SetContainerOverrideInput) (*SetContainerOverrideOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	override, err := h.containerService.SetContainerOverride(ctx, input.ContainerID, input.Body, *user)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidContainerOverride) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &SetContainerOverrideOutput{
+		Body: ContainerOverrideResponse{
+			Success: true,
+			Data:    *override,
+		},
+	}, nil
+}
+
+// DeleteContainerOverride removes the display overrides for a container.
+func (h *ContainerHandler) DeleteContainerOverride(ctx context.Context, input *DeleteContainerOverrideInput) (*DeleteContainerOverrideOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	if _, exists := humamw.GetCurrentUserFromContext(ctx); !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	if err := h.containerService.DeleteContainerOverride(ctx, input.ContainerID); err != nil {
+		if errors.Is(err, services.ErrContainerOverrideNotFound) {
+			return nil, huma.Error404NotFound(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &DeleteContainerOverrideOutput{
+		Body: ContainerActionResponse{
+			Success: true,
+			Data: base.MessageResponse{
+				Message: "Container override removed successfully",
+			},
+		},
+	}, nil
+}
+
+func parsePortSpec(spec string) (nat.Port, error) {
+	proto := "tcp"
+	port := spec
+	if strings.Contains(spec, "/") {
+		parts := strings.SplitN(spec, "/", 2)
+		port = parts[0]
+		if parts[1] != "" {
+			proto = parts[1]
+		}
+	}
+
+	return nat.NewPort(proto, port)
+}
+
+func resolveCreateCommand(body containertypes.Create) []string {
+	if len(body.Command) > 0 {
+		return body.Command
+	}
+
+	return body.Cmd
+}
+
+func resolveCreateEnv(body containertypes.Create) []string {
+	if len(body.Environment) > 0 {
+		return body.Environment
+	}
+
+	return body.Env
+}
+
+func buildCreateLabels(body containertypes.Create) map[string]string {
+	labels := map[string]string{
+		"com.arcane.created": "true",
+	}
+	for key, value := range body.Labels {
+		labels[key] = value
+	}
+
+	return labels
 }
 
 func buildContainerConfig(body containertypes.Create) *dockercontainer.Config {
@@ -439,6 +987,15 @@ func applyHostConfigSettings(hostConfig *dockercontainer.HostConfig, input *cont
 	if input.CPUShares > 0 {
 		hostConfig.CPUShares = input.CPUShares
 	}
+	if len(input.DNS) > 0 {
+		hostConfig.DNS = input.DNS
+	}
+	if len(input.DNSSearch) > 0 {
+		hostConfig.DNSSearch = input.DNSSearch
+	}
+	if len(input.ExtraHosts) > 0 {
+		hostConfig.ExtraHosts = input.ExtraHosts
+	}
 }
 
 func applyHostConfigOverrides(body containertypes.Create, config *dockercontainer.Config, hostConfig *dockercontainer.HostConfig, portBindings nat.PortMap) error {
@@ -516,8 +1073,22 @@ func (h *ContainerHandler) CreateContainer(ctx context.Context, input *CreateCon
 
 	networkingConfig := buildNetworkingConfig(input.Body)
 
+	ctx, err := scanGateContextInternal(ctx, input.OverrideScanGate)
+	if err != nil {
+		return nil, err
+	}
+
 	containerJSON, err := h.containerService.CreateContainer(ctx, config, hostConfig, networkingConfig, input.Body.Name, *user, input.Body.Credentials)
 	if err != nil {
+		if isNamespaceErrorInternal(err) {
+			return nil, namespaceErrorInternal(err)
+		}
+		if errors.Is(err, services.ErrScanGateBlocked) {
+			return nil, huma.Error403Forbidden(err.Error())
+		}
+		if errors.Is(err, services.ErrInvalidDNSConfig) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
 		return nil, huma.Error500InternalServerError((&common.ContainerCreationError{Err: err}).Error())
 	}
 
@@ -557,6 +1128,34 @@ func (h *ContainerHandler) GetContainer(ctx context.Context, input *GetContainer
 	}, nil
 }
 
+// BulkContainerAction runs an action on several containers. Per-container
+// failures are reported in the result rather than as an error status.
+func (h *ContainerHandler) BulkContainerAction(ctx context.Context, input *BulkContainerActionInput) (*BulkContainerActionOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	result, err := h.containerService.BulkContainerAction(ctx, input.Body, *user)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBulkAction) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &BulkContainerActionOutput{
+		Body: ContainerBulkActionResponse{
+			Success: result.Failed == 0,
+			Data:    *result,
+		},
+	}, nil
+}
+
 func (h *ContainerHandler) StartContainer(ctx context.Context, input *ContainerActionInput) (*ContainerActionOutput, error) {
 	if h.containerService == nil {
 		return nil, huma.Error500InternalServerError("service not available")
@@ -579,7 +1178,7 @@ func (h *ContainerHandler) StartContainer(ctx context.Context, input *ContainerA
 	}, nil
 }
 
-func (h *ContainerHandler) StopContainer(ctx context.Context, input *ContainerActionInput) (*ContainerActionOutput, error) {
+func (h *ContainerHandler) StopContainer(ctx context.Context, input *ContainerStopInput) (*ContainerActionOutput, error) {
 	if h.containerService == nil {
 		return nil, huma.Error500InternalServerError("service not available")
 	}
@@ -589,7 +1188,7 @@ func (h *ContainerHandler) StopContainer(ctx context.Context, input *ContainerAc
 		return nil, huma.Error401Unauthorized("not authenticated")
 	}
 
-	if err := h.containerService.StopContainer(ctx, input.ContainerID, *user); err != nil {
+	if err := h.containerService.StopContainer(ctx, input.ContainerID, input.Timeout, *user); err != nil {
 		return nil, huma.Error500InternalServerError((&common.ContainerStopError{Err: err}).Error())
 	}
 
@@ -601,7 +1200,7 @@ func (h *ContainerHandler) StopContainer(ctx context.Context, input *ContainerAc
 	}, nil
 }
 
-func (h *ContainerHandler) RestartContainer(ctx context.Context, input *ContainerActionInput) (*ContainerActionOutput, error) {
+func (h *ContainerHandler) RestartContainer(ctx context.Context, input *ContainerStopInput) (*ContainerActionOutput, error) {
 	if h.containerService == nil {
 		return nil, huma.Error500InternalServerError("service not available")
 	}
@@ -611,7 +1210,7 @@ func (h *ContainerHandler) RestartContainer(ctx context.Context, input *Containe
 		return nil, huma.Error401Unauthorized("not authenticated")
 	}
 
-	if err := h.containerService.RestartContainer(ctx, input.ContainerID, *user); err != nil {
+	if err := h.containerService.RestartContainer(ctx, input.ContainerID, input.Timeout, *user); err != nil {
 		return nil, huma.Error500InternalServerError((&common.ContainerRestartError{Err: err}).Error())
 	}
 
@@ -623,6 +1222,354 @@ func (h *ContainerHandler) RestartContainer(ctx context.Context, input *Containe
 	}, nil
 }
 
+// RenameContainer renames a container in place.
+func (h *ContainerHandler) RenameContainer(ctx context.Context, input *RenameContainerInput) (*ContainerActionOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	if err := h.containerService.RenameContainer(ctx, input.ContainerID, input.Body.Name, *user); err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidContainerName):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, services.ErrDockerContainerNotFound):
+			return nil, huma.Error404NotFound(err.Error())
+		case errors.Is(err, services.ErrContainerNameInUse):
+			return nil, huma.Error409Conflict(err.Error())
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	return &ContainerActionOutput{
+		Body: ContainerActionResponse{
+			Success: true,
+			Data:    base.MessageResponse{Message: "Container renamed successfully"},
+		},
+	}, nil
+}
+
+// RecreateContainer replaces a container with a reconfigured copy.
+func (h *ContainerHandler) RecreateContainer(ctx context.Context, input *RecreateContainerInput) (*CreateContainerOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	ctx, err := scanGateContextInternal(ctx, input.OverrideScanGate)
+	if err != nil {
+		return nil, err
+	}
+
+	containerJSON, err := h.containerService.RecreateContainer(ctx, input.ContainerID, input.Body, *user)
+	return recreatedContainerOutputInternal(containerJSON, err)
+}
+
+// SetContainerLabels replaces a container's labels by recreating it.
+func (h *ContainerHandler) SetContainerLabels(ctx context.Context, input *SetContainerLabelsInput) (*CreateContainerOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	containerJSON, err := h.containerService.SetContainerLabels(ctx, input.ContainerID, input.Body.Labels, *user)
+	return recreatedContainerOutputInternal(containerJSON, err)
+}
+
+// GetContainerProcesses returns the process table of a running container.
+func (h *ContainerHandler) GetContainerProcesses(ctx context.Context, input *GetContainerProcessesInput) (*GetContainerProcessesOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	processes, err := h.containerService.GetContainerProcesses(ctx, input.ContainerID, input.PsArgs)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidPsArgs):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, services.ErrDockerContainerNotFound):
+			return nil, huma.Error404NotFound(err.Error())
+		case errors.Is(err, services.ErrContainerNotRunning):
+			return nil, huma.Error409Conflict(err.Error())
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	return &GetContainerProcessesOutput{
+		Body: ContainerProcessesResponse{
+			Success: true,
+			Data:    *processes,
+		},
+	}, nil
+}
+
+// ResizeContainerExec changes the terminal size of an exec session.
+func (h *ContainerHandler) ResizeContainerExec(ctx context.Context, input *ResizeContainerExecInput) (*ResizeContainerExecOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	err := h.containerService.ResizeExec(ctx, input.ContainerID, input.ExecID, input.Body.Rows, input.Body.Cols)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidTerminalSize):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, services.ErrExecNotFound):
+			return nil, huma.Error404NotFound(err.Error())
+		case errors.Is(err, services.ErrContainerNotRunning):
+			return nil, huma.Error409Conflict(err.Error())
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	return &ResizeContainerExecOutput{
+		Body: ContainerActionResponse{
+			Success: true,
+			Data:    base.MessageResponse{Message: "Terminal resized successfully"},
+		},
+	}, nil
+}
+
+// GetContainerSnapshotInfo estimates the size of a container snapshot.
+func (h *ContainerHandler) GetContainerSnapshotInfo(ctx context.Context, input *GetContainerSnapshotInfoInput) (*GetContainerSnapshotInfoOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	info, err := h.containerService.GetContainerSnapshotInfo(ctx, input.ContainerID)
+	if err != nil {
+		if errors.Is(err, services.ErrDockerContainerNotFound) {
+			return nil, huma.Error404NotFound(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &GetContainerSnapshotInfoOutput{
+		Body: ContainerSnapshotInfoResponse{
+			Success: true,
+			Data:    *info,
+		},
+	}, nil
+}
+
+// DiffContainers compares the configuration of two containers.
+func (h *ContainerHandler) DiffContainers(ctx context.Context, input *DiffContainersInput) (*DiffContainersOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	result, err := h.containerService.DiffContainers(ctx, input.Left, input.Right)
+	if err != nil {
+		if errors.Is(err, services.ErrDockerContainerNotFound) {
+			return nil, huma.Error404NotFound(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &DiffContainersOutput{
+		Body: ContainerDiffResponse{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
+// CommitContainer snapshots a container into a new image.
+func (h *ContainerHandler) CommitContainer(ctx context.Context, input *CommitContainerInput) (*CommitContainerOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	result, err := h.containerService.CommitContainer(ctx, input.ContainerID, input.Body, *user)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidCommit):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, services.ErrDockerContainerNotFound):
+			return nil, huma.Error404NotFound(err.Error())
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	return &CommitContainerOutput{
+		Body: ContainerCommitResponse{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
+// ExportContainer streams the filesystem of a container as a tar archive.
+func (h *ContainerHandler) ExportContainer(ctx context.Context, input *ExportContainerInput) (*huma.StreamResponse, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	stream, name, err := h.containerService.ExportContainer(ctx, input.ContainerID, *user)
+	if err != nil {
+		if errors.Is(err, services.ErrDockerContainerNotFound) {
+			return nil, huma.Error404NotFound(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &huma.StreamResponse{
+		Body: func(humaCtx huma.Context) {
+			defer func() { _ = stream.Close() }()
+
+			humaCtx.SetHeader("Content-Type", "application/x-tar")
+			humaCtx.SetHeader("Content-Disposition", "attachment; filename="+name+".tar")
+			humaCtx.SetStatus(http.StatusOK)
+
+			if _, err := io.Copy(humaCtx.BodyWriter(), stream); err != nil {
+				slog.WarnContext(humaCtx.Context(), "Container export interrupted", "container", name, "error", err)
+			}
+		},
+	}, nil
+}
+
+// DownloadContainerLogs streams the logs of a container as a file.
+func (h *ContainerHandler) DownloadContainerLogs(ctx context.Context, input *DownloadContainerLogsInput) (*huma.StreamResponse, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	opts := services.LogDownloadOptions{
+		Since:      input.Since,
+		Until:      input.Until,
+		Stdout:     input.Stdout,
+		Stderr:     input.Stderr,
+		Timestamps: input.Timestamps,
+		Gzip:       input.Format == "gzip",
+		MaxBytes:   input.MaxBytes,
+	}
+	stream, name, err := h.containerService.DownloadLogs(ctx, input.ContainerID, opts)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidLogDownload):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, services.ErrDockerContainerNotFound):
+			return nil, huma.Error404NotFound(err.Error())
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	contentType, fileName := "text/plain; charset=utf-8", name+".log"
+	if opts.Gzip {
+		contentType, fileName = "application/gzip", name+".log.gz"
+	}
+
+	return &huma.StreamResponse{
+		Body: func(humaCtx huma.Context) {
+			defer func() { _ = stream.Close() }()
+
+			humaCtx.SetHeader("Content-Type", contentType)
+			humaCtx.SetHeader("Content-Disposition", "attachment; filename="+fileName)
+			humaCtx.SetStatus(http.StatusOK)
+
+			if _, err := io.Copy(humaCtx.BodyWriter(), stream); err != nil {
+				slog.WarnContext(humaCtx.Context(), "Container log download interrupted", "container", name, "error", err)
+			}
+		},
+	}, nil
+}
+
+// UpdateContainerResources changes a container's resource limits in place.
+func (h *ContainerHandler) UpdateContainerResources(ctx context.Context, input *UpdateContainerResourcesInput) (*UpdateContainerResourcesOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	result, err := h.containerService.UpdateContainerResources(ctx, input.ContainerID, input.Body, *user)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidResourceUpdate):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, services.ErrDockerContainerNotFound):
+			return nil, huma.Error404NotFound(err.Error())
+		case isNamespaceErrorInternal(err):
+			return nil, namespaceErrorInternal(err)
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	return &UpdateContainerResourcesOutput{
+		Body: ContainerResourcesUpdateResponse{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
+func recreatedContainerOutputInternal(containerJSON *dockercontainer.InspectResponse, err error) (*CreateContainerOutput, error) {
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidRecreate):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, services.ErrDockerContainerNotFound):
+			return nil, huma.Error404NotFound(err.Error())
+		case errors.Is(err, services.ErrScanGateBlocked):
+			return nil, huma.Error403Forbidden(err.Error())
+		case isNamespaceErrorInternal(err):
+			return nil, namespaceErrorInternal(err)
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	out := containertypes.Created{
+		ID:      containerJSON.ID,
+		Name:    containerJSON.Name,
+		Created: containerJSON.Created,
+	}
+	if containerJSON.Config != nil {
+		out.Image = containerJSON.Config.Image
+	}
+	if containerJSON.State != nil {
+		out.Status = containerJSON.State.Status
+	}
+
+	return &CreateContainerOutput{
+		Body: ContainerCreatedResponse{
+			Success: true,
+			Data:    out,
+		},
+	}, nil
+}
+
 func (h *ContainerHandler) DeleteContainer(ctx context.Context, input *DeleteContainerInput) (*DeleteContainerOutput, error) {
 	if h.containerService == nil {
 		return nil, huma.Error500InternalServerError("service not available")
@@ -633,8 +1580,19 @@ func (h *ContainerHandler) DeleteContainer(ctx context.Context, input *DeleteCon
 		return nil, huma.Error401Unauthorized("not authenticated")
 	}
 
-	if err := h.containerService.DeleteContainer(ctx, input.ContainerID, input.Force, input.RemoveVolumes, *user); err != nil {
-		return nil, huma.Error500InternalServerError((&common.ContainerDeleteError{Err: err}).Error())
+	deleteContainer := h.containerService.DeleteContainer
+	if input.Permanent {
+		deleteContainer = h.containerService.RemoveContainer
+	}
+	if err := deleteContainer(ctx, input.ContainerID, input.Force, input.RemoveVolumes, *user); err != nil {
+		switch {
+		case errors.Is(err, services.ErrDockerContainerNotFound):
+			return nil, huma.Error404NotFound(err.Error())
+		case errors.Is(err, services.ErrContainerRunning):
+			return nil, huma.Error409Conflict(err.Error())
+		default:
+			return nil, huma.Error500InternalServerError((&common.ContainerDeleteError{Err: err}).Error())
+		}
 	}
 
 	return &DeleteContainerOutput{
@@ -644,3 +1602,75 @@ func (h *ContainerHandler) DeleteContainer(ctx context.Context, input *DeleteCon
 		},
 	}, nil
 }
+
+func (h *ContainerHandler) ListContainerTrash(ctx context.Context, input *ListContainerTrashInput) (*ListContainerTrashOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	entries, err := h.containerService.ListTrash(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &ListContainerTrashOutput{
+		Body: base.ApiResponse[[]containertypes.TrashEntry]{
+			Success: true,
+			Data:    entries,
+		},
+	}, nil
+}
+
+func (h *ContainerHandler) RestoreContainerFromTrash(ctx context.Context, input *ContainerTrashEntryInput) (*ContainerActionOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	if err := h.containerService.RestoreFromTrash(ctx, input.ContainerID, *user); err != nil {
+		switch {
+		case errors.Is(err, services.ErrTrashEntryNotFound), errors.Is(err, services.ErrDockerContainerNotFound):
+			return nil, huma.Error404NotFound(err.Error())
+		case errors.Is(err, services.ErrContainerNameInUse):
+			return nil, huma.Error409Conflict(err.Error())
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	return &ContainerActionOutput{
+		Body: ContainerActionResponse{
+			Success: true,
+			Data:    base.MessageResponse{Message: "Container restored successfully"},
+		},
+	}, nil
+}
+
+func (h *ContainerHandler) PurgeContainerFromTrash(ctx context.Context, input *ContainerTrashEntryInput) (*ContainerActionOutput, error) {
+	if h.containerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	if err := h.containerService.PurgeTrash(ctx, input.ContainerID, *user); err != nil {
+		if errors.Is(err, services.ErrTrashEntryNotFound) {
+			return nil, huma.Error404NotFound(err.Error())
+		}
+		return nil, huma.Error500InternalServerError((&common.ContainerDeleteError{Err: err}).Error())
+	}
+
+	return &ContainerActionOutput{
+		Body: ContainerActionResponse{
+			Success: true,
+			Data:    base.MessageResponse{Message: "Container removed successfully"},
+		},
+	}, nil
+}
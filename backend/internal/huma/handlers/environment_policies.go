@@ -0,0 +1,293 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/common"
+	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/types/base"
+	"github.com/getarcaneapp/arcane/types/environment"
+)
+
+// EnvironmentPolicyHandler handles environment policy endpoints, including
+// the ones agents use to fetch their policy and deliver reports.
+type EnvironmentPolicyHandler struct {
+	policyService      *services.EnvironmentPolicyService
+	environmentService *services.EnvironmentService
+	apiKeyService      *services.ApiKeyService
+}
+
+// ============================================================================
+// Input/Output Types
+// ============================================================================
+
+type GetEnvironmentPolicyInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type GetEnvironmentPolicyOutput struct {
+	Body base.ApiResponse[environment.Policy]
+}
+
+type UpdateEnvironmentPolicyInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Body          environment.PolicyUpdate
+}
+
+type UpdateEnvironmentPolicyOutput struct {
+	Body base.ApiResponse[environment.Policy]
+}
+
+type GetEnvironmentPolicyStatusInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type GetEnvironmentPolicyStatusOutput struct {
+	Body base.ApiResponse[environment.PolicyStatus]
+}
+
+type GetAgentPolicyInput struct {
+	XAPIKey string `header:"X-API-Key" doc:"API key the agent was paired with"`
+}
+
+type GetAgentPolicyOutput struct {
+	Body base.ApiResponse[environment.Policy]
+}
+
+type DeliverAgentPolicyReportsInput struct {
+	XAPIKey string `header:"X-API-Key" doc:"API key the agent was paired with"`
+	Body    environment.PolicyReportBatch
+}
+
+type DeliverAgentPolicyReportsOutput struct {
+	Body base.ApiResponse[base.MessageResponse]
+}
+
+// ============================================================================
+// Registration
+// ============================================================================
+
+// RegisterEnvironmentPolicies registers the environment policy endpoints.
+func RegisterEnvironmentPolicies(api huma.API, policyService *services.EnvironmentPolicyService, environmentService *services.EnvironmentService, apiKeyService *services.ApiKeyService) {
+	h := &EnvironmentPolicyHandler{
+		policyService:      policyService,
+		environmentService: environmentService,
+		apiKeyService:      apiKeyService,
+	}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getEnvironmentPolicy",
+		Method:      "GET",
+		Path:        "/environments/{id}/policy",
+		Summary:     "Get environment policy",
+		Description: "Get the policy the manager issues to an environment",
+		Tags:        []string{"Environments"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.GetPolicy)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "updateEnvironmentPolicy",
+		Method:      "PUT",
+		Path:        "/environments/{id}/policy",
+		Summary:     "Update environment policy",
+		Description: "Replace the policy of an environment. Agents pick up the new revision on their next sync.",
+		Tags:        []string{"Environments"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.UpdatePolicy)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getEnvironmentPolicyStatus",
+		Method:      "GET",
+		Path:        "/environments/{id}/policy/status",
+		Summary:     "Get environment policy status",
+		Description: "Get the policy an environment enforces, when it last synced with the manager and how many reports are waiting to be delivered",
+		Tags:        []string{"Environments"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.GetPolicyStatus)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getAgentPolicy",
+		Method:      "GET",
+		Path:        "/environments/agent-policy",
+		Summary:     "Fetch agent policy",
+		Description: "Agent sends its API key to fetch the policy of its environment",
+		Tags:        []string{"Environments"},
+	}, h.GetAgentPolicy)
+
+	huma.Register(api, huma.Operation{
+		OperationID:  "deliverAgentPolicyReports",
+		Method:       "POST",
+		Path:         "/environments/agent-policy-reports",
+		Summary:      "Deliver agent policy reports",
+		Description:  "Agent sends its API key and the policy reports it queued while the manager was unreachable",
+		Tags:         []string{"Environments"},
+		MaxBodyBytes: 1 << 20,
+	}, h.DeliverAgentPolicyReports)
+}
+
+// ============================================================================
+// Handler Methods
+// ============================================================================
+
+// GetPolicy returns the policy of an environment.
+func (h *EnvironmentPolicyHandler) GetPolicy(ctx context.Context, input *GetEnvironmentPolicyInput) (*GetEnvironmentPolicyOutput, error) {
+	if h.policyService == nil || h.environmentService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	if _, err := h.environmentService.GetEnvironmentByID(ctx, input.EnvironmentID); err != nil {
+		return nil, huma.Error404NotFound((&common.EnvironmentNotFoundError{}).Error())
+	}
+
+	policy, err := h.policyService.GetPolicy(ctx, input.EnvironmentID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &GetEnvironmentPolicyOutput{
+		Body: base.ApiResponse[environment.Policy]{
+			Success: true,
+			Data:    *policy,
+		},
+	}, nil
+}
+
+// UpdatePolicy replaces the policy of an environment.
+func (h *EnvironmentPolicyHandler) UpdatePolicy(ctx context.Context, input *UpdateEnvironmentPolicyInput) (*UpdateEnvironmentPolicyOutput, error) {
+	if h.policyService == nil || h.environmentService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+	if _, err := h.environmentService.GetEnvironmentByID(ctx, input.EnvironmentID); err != nil {
+		return nil, huma.Error404NotFound((&common.EnvironmentNotFoundError{}).Error())
+	}
+
+	policy, err := h.policyService.UpdatePolicy(ctx, input.EnvironmentID, input.Body, *user)
+	if err != nil {
+		return nil, policyErrorInternal(err)
+	}
+
+	return &UpdateEnvironmentPolicyOutput{
+		Body: base.ApiResponse[environment.Policy]{
+			Success: true,
+			Data:    *policy,
+		},
+	}, nil
+}
+
+// GetPolicyStatus returns the locally enforced policy and its sync state.
+// Requests for remote environments are proxied to the agent.
+func (h *EnvironmentPolicyHandler) GetPolicyStatus(ctx context.Context, _ *GetEnvironmentPolicyStatusInput) (*GetEnvironmentPolicyStatusOutput, error) {
+	if h.policyService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	status, err := h.policyService.GetStatus(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &GetEnvironmentPolicyStatusOutput{
+		Body: base.ApiResponse[environment.PolicyStatus]{
+			Success: true,
+			Data:    *status,
+		},
+	}, nil
+}
+
+// GetAgentPolicy returns the policy of the environment an agent's API key
+// belongs to.
+func (h *EnvironmentPolicyHandler) GetAgentPolicy(ctx context.Context, input *GetAgentPolicyInput) (*GetAgentPolicyOutput, error) {
+	if h.policyService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	envID, err := h.agentEnvironmentInternal(ctx, input.XAPIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	policy, err := h.policyService.GetPolicy(ctx, envID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &GetAgentPolicyOutput{
+		Body: base.ApiResponse[environment.Policy]{
+			Success: true,
+			Data:    *policy,
+		},
+	}, nil
+}
+
+// DeliverAgentPolicyReports records the reports an agent delivers.
+func (h *EnvironmentPolicyHandler) DeliverAgentPolicyReports(ctx context.Context, input *DeliverAgentPolicyReportsInput) (*DeliverAgentPolicyReportsOutput, error) {
+	if h.policyService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	envID, err := h.agentEnvironmentInternal(ctx, input.XAPIKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := h.policyService.RecordAgentReports(ctx, envID, input.Body.Reports); err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &DeliverAgentPolicyReportsOutput{
+		Body: base.ApiResponse[base.MessageResponse]{
+			Success: true,
+			Data: base.MessageResponse{
+				Message: "Policy reports recorded",
+			},
+		},
+	}, nil
+}
+
+// agentEnvironmentInternal resolves the environment an agent's API key is
+// linked to.
+func (h *EnvironmentPolicyHandler) agentEnvironmentInternal(ctx context.Context, apiKey string) (string, error) {
+	if h.apiKeyService == nil {
+		return "", huma.Error500InternalServerError("service not available")
+	}
+	if apiKey == "" {
+		return "", huma.Error400BadRequest("X-API-Key header is required")
+	}
+
+	envID, err := h.apiKeyService.GetEnvironmentByApiKey(ctx, apiKey)
+	if err != nil {
+		return "", huma.Error401Unauthorized("Invalid API key")
+	}
+	if envID == nil {
+		return "", huma.Error400BadRequest("API key is not linked to an environment")
+	}
+	return *envID, nil
+}
+
+func policyErrorInternal(err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidPolicy):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, services.ErrPolicyManagedByManager):
+		return huma.Error409Conflict(err.Error())
+	default:
+		return huma.Error500InternalServerError(err.Error())
+	}
+}
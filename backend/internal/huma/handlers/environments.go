@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -71,6 +72,14 @@ type CreateEnvironmentOutput struct {
 	Body base.ApiResponse[EnvironmentWithApiKey]
 }
 
+type ImportEnvironmentsInput struct {
+	Body environment.ImportRequest
+}
+
+type ImportEnvironmentsOutput struct {
+	Body base.ApiResponse[environment.ImportResult]
+}
+
 type GetEnvironmentInput struct {
 	ID string `path:"id" doc:"Environment ID"`
 }
@@ -138,6 +147,15 @@ type PairEnvironmentOutput struct {
 	Body base.ApiResponse[base.MessageResponse]
 }
 
+type AgentHeartbeatInput struct {
+	XAPIKey string `header:"X-API-Key" doc:"API key the agent was paired with"`
+	Body    environment.HeartbeatMetrics
+}
+
+type AgentHeartbeatOutput struct {
+	Body base.ApiResponse[base.MessageResponse]
+}
+
 type DeploymentSnippet struct {
 	DockerRun     string `json:"dockerRun" doc:"Docker run command snippet"`
 	DockerCompose string `json:"dockerCompose" doc:"Docker compose YAML snippet"`
@@ -199,6 +217,19 @@ func RegisterEnvironments(api huma.API, environmentService *services.Environment
 		},
 	}, h.CreateEnvironment)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "importEnvironments",
+		Method:      "POST",
+		Path:        "/environments/import",
+		Summary:     "Import environments",
+		Description: "Create and pair many environments from a YAML or CSV manifest, with a result per entry",
+		Tags:        []string{"Environments"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.ImportEnvironments)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "getEnvironment",
 		Method:      "GET",
@@ -300,6 +331,16 @@ func RegisterEnvironments(api huma.API, environmentService *services.Environment
 		MaxBodyBytes: 1024,
 	}, h.PairEnvironment)
 
+	huma.Register(api, huma.Operation{
+		OperationID:  "agentHeartbeat",
+		Method:       "POST",
+		Path:         "/environments/agent-heartbeat",
+		Summary:      "Push agent heartbeat",
+		Description:  "Agent sends its API key and lightweight metrics to report that it is online",
+		Tags:         []string{"Environments"},
+		MaxBodyBytes: 4096,
+	}, h.AgentHeartbeat)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "getDeploymentSnippets",
 		Method:      "GET",
@@ -399,6 +440,9 @@ func (h *EnvironmentHandler) CreateEnvironment(ctx context.Context, input *Creat
 	if input.Body.IsEdge != nil {
 		env.IsEdge = *input.Body.IsEdge
 	}
+	if input.Body.Tags != nil {
+		env.Tags = services.NormalizeEnvironmentTags(input.Body.Tags)
+	}
 
 	// Determine pairing method
 	useApiKey := input.Body.UseApiKey != nil && *input.Body.UseApiKey
@@ -411,19 +455,43 @@ func (h *EnvironmentHandler) CreateEnvironment(ctx context.Context, input *Creat
 }
 
 func (h *EnvironmentHandler) createEnvironmentWithApiKey(ctx context.Context, env *models.Environment, user *models.User) (*CreateEnvironmentOutput, error) {
+	created, apiKey, err := h.createEnvironmentWithApiKeyInternal(ctx, env, user)
+	if err != nil {
+		return nil, err
+	}
+
+	out, mapErr := mapper.MapOne[*models.Environment, environment.Environment](created)
+	if mapErr != nil {
+		return nil, huma.Error500InternalServerError((&common.EnvironmentMappingError{Err: mapErr}).Error())
+	}
+
+	return &CreateEnvironmentOutput{
+		Body: base.ApiResponse[EnvironmentWithApiKey]{
+			Success: true,
+			Data: EnvironmentWithApiKey{
+				Environment: out,
+				ApiKey:      &apiKey,
+			},
+		},
+	}, nil
+}
+
+// createEnvironmentWithApiKeyInternal creates a pending environment and the
+// API key the agent uses to pair with it, returning the plain key.
+func (h *EnvironmentHandler) createEnvironmentWithApiKeyInternal(ctx context.Context, env *models.Environment, user *models.User) (*models.Environment, string, error) {
 	// New API key-based pairing flow
 	env.Status = string(models.EnvironmentStatusPending)
 
 	created, err := h.environmentService.CreateEnvironment(ctx, env, &user.ID, &user.Username)
 	if err != nil {
-		return nil, huma.Error500InternalServerError((&common.EnvironmentCreationError{Err: err}).Error())
+		return nil, "", huma.Error500InternalServerError((&common.EnvironmentCreationError{Err: err}).Error())
 	}
 
 	// Generate API key for environment
 	apiKeyDto, err := h.apiKeyService.CreateEnvironmentApiKey(ctx, created.ID, user.ID)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to create environment API key", "environmentID", created.ID, "error", err.Error())
-		return nil, huma.Error500InternalServerError("Failed to create environment API key")
+		return nil, "", huma.Error500InternalServerError("Failed to create environment API key")
 	}
 
 	// Store the API key in AccessToken field (encrypted) for manager-to-agent auth
@@ -437,7 +505,16 @@ func (h *EnvironmentHandler) createEnvironmentWithApiKey(ctx context.Context, en
 	created, err = h.environmentService.UpdateEnvironment(ctx, created.ID, updates, &user.ID, &user.Username)
 	if err != nil {
 		slog.ErrorContext(ctx, "Failed to link API key to environment", "environmentID", created.ID, "error", err.Error())
-		return nil, huma.Error500InternalServerError("Failed to link API key")
+		return nil, "", huma.Error500InternalServerError("Failed to link API key")
+	}
+
+	return created, apiKeyDto.Key, nil
+}
+
+func (h *EnvironmentHandler) createEnvironmentLegacy(ctx context.Context, env *models.Environment, user *models.User, body environment.Create) (*CreateEnvironmentOutput, error) {
+	created, err := h.createEnvironmentLegacyInternal(ctx, env, user, body)
+	if err != nil {
+		return nil, err
 	}
 
 	out, mapErr := mapper.MapOne[*models.Environment, environment.Environment](created)
@@ -450,13 +527,14 @@ func (h *EnvironmentHandler) createEnvironmentWithApiKey(ctx context.Context, en
 			Success: true,
 			Data: EnvironmentWithApiKey{
 				Environment: out,
-				ApiKey:      &apiKeyDto.Key,
 			},
 		},
 	}, nil
 }
 
-func (h *EnvironmentHandler) createEnvironmentLegacy(ctx context.Context, env *models.Environment, user *models.User, body environment.Create) (*CreateEnvironmentOutput, error) {
+// createEnvironmentLegacyInternal creates an environment paired with an
+// access token or a bootstrap token exchanged with the agent.
+func (h *EnvironmentHandler) createEnvironmentLegacyInternal(ctx context.Context, env *models.Environment, user *models.User, body environment.Create) (*models.Environment, error) {
 	// Legacy pairing flows
 	if (body.AccessToken == nil || *body.AccessToken == "") && body.BootstrapToken != nil && *body.BootstrapToken != "" {
 		token, err := h.environmentService.PairAgentWithBootstrap(ctx, body.ApiUrl, *body.BootstrapToken)
@@ -492,21 +570,122 @@ func (h *EnvironmentHandler) createEnvironmentLegacy(ctx context.Context, env *m
 		}(created.ID, created.Name)
 	}
 
-	out, mapErr := mapper.MapOne[*models.Environment, environment.Environment](created)
-	if mapErr != nil {
-		return nil, huma.Error500InternalServerError((&common.EnvironmentMappingError{Err: mapErr}).Error())
+	return created, nil
+}
+
+// ImportEnvironments creates and pairs the environments described by a
+// manifest. Entries whose API URL is already registered are skipped, so an
+// import can be re-run after fixing failed entries.
+func (h *EnvironmentHandler) ImportEnvironments(ctx context.Context, input *ImportEnvironmentsInput) (*ImportEnvironmentsOutput, error) {
+	if h.environmentService == nil || h.apiKeyService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
 	}
 
-	return &CreateEnvironmentOutput{
-		Body: base.ApiResponse[EnvironmentWithApiKey]{
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	entries, err := services.ParseEnvironmentManifest(input.Body.Format, input.Body.Manifest)
+	if err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+
+	result := environment.ImportResult{Results: make([]environment.ImportResultEntry, 0, len(entries))}
+	seen := make(map[string]int, len(entries))
+	for i, entry := range entries {
+		res := h.importEnvironmentEntryInternal(ctx, i, entry, seen, user)
+		switch res.Status {
+		case environment.ImportStatusCreated:
+			result.Created++
+		case environment.ImportStatusSkipped:
+			result.Skipped++
+		default:
+			result.Failed++
+		}
+		result.Results = append(result.Results, res)
+	}
+
+	return &ImportEnvironmentsOutput{
+		Body: base.ApiResponse[environment.ImportResult]{
 			Success: true,
-			Data: EnvironmentWithApiKey{
-				Environment: out,
-			},
+			Data:    result,
 		},
 	}, nil
 }
 
+func (h *EnvironmentHandler) importEnvironmentEntryInternal(ctx context.Context, index int, entry environment.ManifestEntry, seen map[string]int, user *models.User) environment.ImportResultEntry {
+	res := environment.ImportResultEntry{Index: index, Name: entry.Name, ApiUrl: entry.ApiUrl}
+	fail := func(msg string) environment.ImportResultEntry {
+		res.Status = environment.ImportStatusFailed
+		res.Error = msg
+		return res
+	}
+
+	parsed, err := url.Parse(entry.ApiUrl)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		return fail("apiUrl must be an absolute http or https URL")
+	}
+	key := strings.TrimRight(entry.ApiUrl, "/")
+	if first, dup := seen[key]; dup {
+		res.Status = environment.ImportStatusSkipped
+		res.Error = fmt.Sprintf("duplicate of entry %d", first)
+		return res
+	}
+	seen[key] = index
+
+	existing, err := h.environmentService.GetEnvironmentByApiUrl(ctx, entry.ApiUrl)
+	if err != nil {
+		return fail(err.Error())
+	}
+	if existing != nil {
+		res.Status = environment.ImportStatusSkipped
+		res.EnvironmentID = existing.ID
+		res.Error = "an environment with this API URL already exists"
+		return res
+	}
+
+	env := &models.Environment{
+		Name:    entry.Name,
+		ApiUrl:  entry.ApiUrl,
+		Enabled: true,
+		Tags:    entry.Tags,
+	}
+	if env.Name == "" {
+		env.Name = parsed.Host
+	}
+	res.Name = env.Name
+
+	var created *models.Environment
+	if entry.AccessToken == "" && entry.BootstrapToken == "" {
+		var apiKey string
+		created, apiKey, err = h.createEnvironmentWithApiKeyInternal(ctx, env, user)
+		if err == nil {
+			res.ApiKey = &apiKey
+		}
+	} else {
+		body := environment.Create{ApiUrl: entry.ApiUrl}
+		if entry.AccessToken != "" {
+			body.AccessToken = &entry.AccessToken
+		}
+		if entry.BootstrapToken != "" {
+			body.BootstrapToken = &entry.BootstrapToken
+		}
+		created, err = h.createEnvironmentLegacyInternal(ctx, env, user, body)
+	}
+	if err != nil {
+		return fail(err.Error())
+	}
+
+	res.Status = environment.ImportStatusCreated
+	res.EnvironmentID = created.ID
+	return res
+}
+
 // GetEnvironment returns an environment by ID.
 func (h *EnvironmentHandler) GetEnvironment(ctx context.Context, input *GetEnvironmentInput) (*GetEnvironmentOutput, error) {
 	if h.environmentService == nil {
@@ -541,6 +720,10 @@ func (h *EnvironmentHandler) UpdateEnvironment(ctx context.Context, input *Updat
 		return nil, err
 	}
 
+	if err := services.ValidateEnvironmentWake(input.ID, &input.Body); err != nil {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
+
 	isLocalEnv := input.ID == localDockerEnvironmentID
 	updates := h.buildUpdateMap(&input.Body, isLocalEnv)
 
@@ -794,6 +977,37 @@ func (h *EnvironmentHandler) buildUpdateMap(req *environment.Update, isLocalEnv
 	if req.Name != nil {
 		updates["name"] = *req.Name
 	}
+	if req.RequireApproval != nil {
+		updates["require_approval"] = *req.RequireApproval
+	}
+	if req.Color != nil {
+		updates["color"] = strings.TrimSpace(*req.Color)
+	}
+	if req.Icon != nil {
+		updates["icon"] = strings.TrimSpace(*req.Icon)
+	}
+	if req.Description != nil {
+		updates["description"] = strings.TrimSpace(*req.Description)
+	}
+	if req.Production != nil {
+		updates["production"] = *req.Production
+	}
+	if req.Tags != nil {
+		updates["tags"] = models.StringSlice(services.NormalizeEnvironmentTags(req.Tags))
+	}
+	if req.MacAddress != nil {
+		updates["mac_address"] = services.NormalizeMACAddress(*req.MacAddress)
+	}
+	if req.WakeBroadcast != nil {
+		updates["wake_broadcast"] = strings.TrimSpace(*req.WakeBroadcast)
+	}
+	if req.WakeRelayID != nil {
+		if relayID := strings.TrimSpace(*req.WakeRelayID); relayID != "" {
+			updates["wake_relay_id"] = relayID
+		} else {
+			updates["wake_relay_id"] = nil
+		}
+	}
 
 	return updates
 }
@@ -908,6 +1122,38 @@ func (h *EnvironmentHandler) PairEnvironment(ctx context.Context, input *PairEnv
 	}, nil
 }
 
+// AgentHeartbeat records a heartbeat and metrics pushed by a paired agent.
+func (h *EnvironmentHandler) AgentHeartbeat(ctx context.Context, input *AgentHeartbeatInput) (*AgentHeartbeatOutput, error) {
+	if h.environmentService == nil || h.apiKeyService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	if input.XAPIKey == "" {
+		return nil, huma.Error400BadRequest("X-API-Key header is required")
+	}
+
+	envID, err := h.apiKeyService.GetEnvironmentByApiKey(ctx, input.XAPIKey)
+	if err != nil {
+		return nil, huma.Error401Unauthorized("Invalid API key")
+	}
+	if envID == nil {
+		return nil, huma.Error400BadRequest("API key is not linked to an environment")
+	}
+
+	if err := h.environmentService.RecordAgentHeartbeat(ctx, *envID, input.Body); err != nil {
+		return nil, huma.Error500InternalServerError((&common.HeartbeatUpdateError{Err: err}).Error())
+	}
+
+	return &AgentHeartbeatOutput{
+		Body: base.ApiResponse[base.MessageResponse]{
+			Success: true,
+			Data: base.MessageResponse{
+				Message: "Heartbeat recorded",
+			},
+		},
+	}, nil
+}
+
 // GetDeploymentSnippets returns deployment snippets for an environment.
 func (h *EnvironmentHandler) GetDeploymentSnippets(ctx context.Context, input *GetDeploymentSnippetsInput) (*GetDeploymentSnippetsOutput, error) {
 	if h.environmentService == nil {
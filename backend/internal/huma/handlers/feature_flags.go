@@ -0,0 +1,127 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/common"
+	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/types/base"
+	"github.com/getarcaneapp/arcane/types/environment"
+)
+
+// FeatureFlagHandler handles per-environment feature flag endpoints.
+type FeatureFlagHandler struct {
+	featureFlagService *services.FeatureFlagService
+}
+
+// ============================================================================
+// Input/Output Types
+// ============================================================================
+
+type GetFeatureFlagsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type GetFeatureFlagsOutput struct {
+	Body base.ApiResponse[[]environment.FeatureFlag]
+}
+
+type UpdateFeatureFlagsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Body          environment.FeatureFlagsUpdate
+}
+
+type UpdateFeatureFlagsOutput struct {
+	Body base.ApiResponse[[]environment.FeatureFlag]
+}
+
+// ============================================================================
+// Registration
+// ============================================================================
+
+// RegisterFeatureFlags registers the feature flag endpoints.
+func RegisterFeatureFlags(api huma.API, featureFlagService *services.FeatureFlagService) {
+	h := &FeatureFlagHandler{featureFlagService: featureFlagService}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getEnvironmentFeatureFlags",
+		Method:      "GET",
+		Path:        "/environments/{id}/features",
+		Summary:     "Get environment feature flags",
+		Description: "Get the effective feature flags for an environment",
+		Tags:        []string{"Environments"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.GetFeatureFlags)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "updateEnvironmentFeatureFlags",
+		Method:      "PUT",
+		Path:        "/environments/{id}/features",
+		Summary:     "Update environment feature flags",
+		Description: "Set or clear environment-specific feature flag overrides",
+		Tags:        []string{"Environments"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.UpdateFeatureFlags)
+}
+
+// ============================================================================
+// Handler Methods
+// ============================================================================
+
+// GetFeatureFlags returns the effective feature flags for an environment.
+func (h *FeatureFlagHandler) GetFeatureFlags(ctx context.Context, input *GetFeatureFlagsInput) (*GetFeatureFlagsOutput, error) {
+	if h.featureFlagService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	flags, err := h.featureFlagService.GetEnvironmentFeatureFlags(ctx, input.EnvironmentID)
+	if err != nil {
+		return nil, huma.Error404NotFound(err.Error())
+	}
+
+	return &GetFeatureFlagsOutput{
+		Body: base.ApiResponse[[]environment.FeatureFlag]{
+			Success: true,
+			Data:    flags,
+		},
+	}, nil
+}
+
+// UpdateFeatureFlags sets or clears feature flag overrides for an environment.
+func (h *FeatureFlagHandler) UpdateFeatureFlags(ctx context.Context, input *UpdateFeatureFlagsInput) (*UpdateFeatureFlagsOutput, error) {
+	if h.featureFlagService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	flags, err := h.featureFlagService.UpdateEnvironmentFeatureFlags(ctx, input.EnvironmentID, input.Body.Overrides, *user)
+	if err != nil {
+		if errors.Is(err, services.ErrUnknownFeature) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &UpdateFeatureFlagsOutput{
+		Body: base.ApiResponse[[]environment.FeatureFlag]{
+			Success: true,
+			Data:    flags,
+		},
+	}, nil
+}
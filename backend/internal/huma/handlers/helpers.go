@@ -5,6 +5,7 @@ import (
 
 	"github.com/danielgtaylor/huma/v2"
 	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/pagination"
 )
 
@@ -16,6 +17,18 @@ func checkAdmin(ctx context.Context) error {
 	return nil
 }
 
+// scanGateContextInternal returns ctx marked to override the vulnerability
+// scan gate when override is set. Only admins may override the gate.
+func scanGateContextInternal(ctx context.Context, override bool) (context.Context, error) {
+	if !override {
+		return ctx, nil
+	}
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return services.WithScanGateOverride(ctx), nil
+}
+
 // buildPaginationParams converts query parameters to pagination.QueryParams.
 // It supports both the legacy nested style (page/limit) and the standard style (start/limit).
 // A limit of -1 means "show all items" (no pagination).
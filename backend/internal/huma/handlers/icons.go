@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+)
+
+// IconsHandler serves project and container icons through the icon proxy.
+type IconsHandler struct {
+	iconProxyService *services.IconProxyService
+}
+
+type GetIconInput struct {
+	URL string `query:"url" required:"true" doc:"Icon URL to fetch"`
+}
+
+type GetIconOutput struct {
+	ContentType           string `header:"Content-Type"`
+	CacheControl          string `header:"Cache-Control"`
+	ContentSecurityPolicy string `header:"Content-Security-Policy"`
+	ContentTypeOptions    string `header:"X-Content-Type-Options"`
+	Body                  []byte
+}
+
+// RegisterIcons registers the icon proxy route using Huma.
+func RegisterIcons(api huma.API, iconProxyService *services.IconProxyService) {
+	h := &IconsHandler{iconProxyService: iconProxyService}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-icon",
+		Method:      http.MethodGet,
+		Path:        "/icons",
+		Summary:     "Get icon",
+		Description: "Fetch a project or container icon through the server so the browser never contacts the icon host",
+		Tags:        []string{"Icons"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.GetIcon)
+}
+
+// GetIcon returns the icon at the given URL.
+func (h *IconsHandler) GetIcon(ctx context.Context, input *GetIconInput) (*GetIconOutput, error) {
+	if h.iconProxyService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	icon, err := h.iconProxyService.GetIcon(ctx, input.URL)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidIconURL):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, services.ErrIconTooLarge):
+			return nil, huma.Error502BadGateway(services.ErrIconTooLarge.Error())
+		case errors.Is(err, services.ErrIconNotImage):
+			return nil, huma.Error502BadGateway(services.ErrIconNotImage.Error())
+		case errors.Is(err, services.ErrIconFetchFailed):
+			// Upstream details (status, addresses) stay in the log so the
+			// response does not reveal anything about the icon host.
+			slog.DebugContext(ctx, "Icon fetch failed", "url", input.URL, "error", err)
+			return nil, huma.Error502BadGateway(services.ErrIconFetchFailed.Error())
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	return &GetIconOutput{
+		ContentType:  icon.ContentType,
+		CacheControl: "private, max-age=86400",
+		// SVG icons may carry scripts; sandbox them when opened directly.
+		ContentSecurityPolicy: "default-src 'none'; style-src 'unsafe-inline'; sandbox",
+		ContentTypeOptions:    "nosniff",
+		Body:                  icon.Data,
+	}, nil
+}
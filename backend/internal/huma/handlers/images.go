@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"io"
+	"log/slog"
 	"mime/multipart"
 	"net/http"
 	"strings"
@@ -43,6 +45,7 @@ type ListImagesInput struct {
 	Limit         int    `query:"limit" default:"20" doc:"Number of items per page"`
 	InUse         string `query:"inUse" doc:"Filter by in-use status (true/false)"`
 	Updates       string `query:"updates" doc:"Filter by update availability (true/false)"`
+	Pinned        string `query:"pinned" doc:"Filter by pinned status (true/false)"`
 }
 
 type ListImagesOutput struct {
@@ -108,6 +111,86 @@ type UploadImageOutput struct {
 	Body base.ApiResponse[image.LoadResult]
 }
 
+type SaveImagesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Body          image.SaveRequest
+}
+
+type BuildImageInput struct {
+	EnvironmentID string         `path:"id" doc:"Environment ID"`
+	RawBody       multipart.Form `contentType:"multipart/form-data"`
+}
+
+type BuildImageOutput struct {
+	Body base.ApiResponse[image.BuildResult]
+}
+
+type ListPinnedImagesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type ListPinnedImagesOutput struct {
+	Body base.ApiResponse[[]image.PinnedImage]
+}
+
+type PinImageInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ImageID       string `path:"imageId" doc:"Image ID"`
+	Body          *image.PinRequest
+}
+
+type PinImageOutput struct {
+	Body base.ApiResponse[image.PinnedImage]
+}
+
+type UnpinImageInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ImageID       string `path:"imageId" doc:"Image ID or pinned digest"`
+}
+
+type UnpinImageOutput struct {
+	Body base.ApiResponse[base.MessageResponse]
+}
+
+type GetImageHistoryInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ImageID       string `path:"imageId" doc:"Image ID or reference"`
+}
+
+type GetImageHistoryOutput struct {
+	Body base.ApiResponse[image.History]
+}
+
+type DiffImagesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Left          string `query:"left" required:"true" doc:"ID or reference of the image the changes are relative to"`
+	Right         string `query:"right" required:"true" doc:"ID or reference of the image to compare"`
+}
+
+type DiffImagesOutput struct {
+	Body base.ApiResponse[image.Diff]
+}
+
+type TagImageInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ImageID       string `path:"imageId" doc:"Image ID or reference"`
+	Body          image.TagRequest
+}
+
+type TagImageOutput struct {
+	Body base.ApiResponse[image.TagResult]
+}
+
+type UntagImageInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ImageID       string `path:"imageId" doc:"Image ID or reference"`
+	Tag           string `query:"tag" required:"true" doc:"Tag to remove, e.g. myapp:1.0"`
+}
+
+type UntagImageOutput struct {
+	Body base.ApiResponse[image.TagResult]
+}
+
 // RegisterImages registers image management routes using Huma.
 func RegisterImages(api huma.API, dockerService *services.DockerClientService, imageService *services.ImageService, imageUpdateService *services.ImageUpdateService, settingsService *services.SettingsService) {
 	h := &ImageHandler{
@@ -217,6 +300,10 @@ func RegisterImages(api huma.API, dockerService *services.DockerClientService, i
 								Format:      "binary",
 								Description: "Docker image tar archive",
 							},
+							"transferId": {
+								Type:        "string",
+								Description: "ID to follow the upload by on the transfer progress WebSocket",
+							},
 						},
 						Required: []string{"file"},
 					},
@@ -224,6 +311,143 @@ func RegisterImages(api huma.API, dockerService *services.DockerClientService, i
 			},
 		},
 	}, h.UploadImage)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "save-images",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/images/save",
+		Summary:     "Export images",
+		Description: "Download one or more images as a tar archive that can be uploaded to another environment",
+		Tags:        []string{"Images"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.SaveImages)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "build-image",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/images/build",
+		Summary:     "Build an image",
+		Description: "Build and tag an image from an uploaded build context, a single Dockerfile or a Git repository. Build output is streamed on the image build WebSocket under the build ID.",
+		Tags:        []string{"Images"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+		RequestBody: &huma.RequestBody{
+			Content: map[string]*huma.MediaType{
+				"multipart/form-data": {
+					Schema: &huma.Schema{
+						Type: "object",
+						Properties: map[string]*huma.Schema{
+							"tags":           {Type: "string", Description: "Comma-separated image tags, e.g. myapp:1.0"},
+							"context":        {Type: "string", Format: "binary", Description: "Build context as a tar archive (.tar, .tar.gz, .tgz)"},
+							"dockerfile":     {Type: "string", Format: "binary", Description: "A single Dockerfile to build without other context files"},
+							"gitUrl":         {Type: "string", Description: "Git repository Docker clones as the build context"},
+							"dockerfilePath": {Type: "string", Description: "Path of the Dockerfile within the context (default: Dockerfile)"},
+							"buildArgs":      {Type: "string", Description: "Build arguments, one KEY=VALUE per line"},
+							"noCache":        {Type: "boolean", Description: "Do not use the layer cache"},
+							"pull":           {Type: "boolean", Description: "Always pull newer base images"},
+							"buildId":        {Type: "string", Description: "ID to tag build progress messages with; generated when empty"},
+						},
+						Required: []string{"tags"},
+					},
+				},
+			},
+		},
+	}, h.BuildImage)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-pinned-images",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/images/pinned",
+		Summary:     "List pinned images",
+		Description: "List images protected from prune and auto-update",
+		Tags:        []string{"Images"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.ListPinnedImages)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "pin-image",
+		Method:      http.MethodPut,
+		Path:        "/environments/{id}/images/{imageId}/pin",
+		Summary:     "Pin an image",
+		Description: "Protect an image from prune jobs and auto-update",
+		Tags:        []string{"Images"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.PinImage)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "unpin-image",
+		Method:      http.MethodDelete,
+		Path:        "/environments/{id}/images/{imageId}/pin",
+		Summary:     "Unpin an image",
+		Tags:        []string{"Images"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.UnpinImage)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-image-history",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/images/{imageId}/history",
+		Summary:     "Get image history",
+		Description: "Get the build steps, layer sizes and default configuration (entrypoint, env, exposed ports, labels) of an image",
+		Tags:        []string{"Images"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.GetImageHistory)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "diff-images",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/images/diff",
+		Summary:     "Diff images",
+		Description: "Compare the configuration, layers and build steps of two images, such as two versions of the same service",
+		Tags:        []string{"Images"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.DiffImages)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "tag-image",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/images/{imageId}/tags",
+		Summary:     "Tag an image",
+		Description: "Add a repository:tag reference to a local image, e.g. to prepare it for push or give a digest a friendly name",
+		Tags:        []string{"Images"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.TagImage)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "untag-image",
+		Method:      http.MethodDelete,
+		Path:        "/environments/{id}/images/{imageId}/tags",
+		Summary:     "Remove an image tag",
+		Description: "Remove a tag from a local image without deleting the image",
+		Tags:        []string{"Images"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.UntagImage)
 }
 
 // ListImages returns a paginated list of images.
@@ -239,6 +463,9 @@ func (h *ImageHandler) ListImages(ctx context.Context, input *ListImagesInput) (
 	if input.Updates != "" {
 		filters["updates"] = input.Updates
 	}
+	if input.Pinned != "" {
+		filters["pinned"] = input.Pinned
+	}
 
 	params := pagination.QueryParams{
 		SearchQuery: pagination.SearchQuery{
@@ -295,6 +522,7 @@ func (h *ImageHandler) GetImage(ctx context.Context, input *GetImageInput) (*Get
 	}
 
 	out := image.NewDetailSummary(img)
+	out.BaseImage = h.imageService.DetectBaseImage(ctx, img)
 
 	return &GetImageOutput{
 		Body: base.ApiResponse[image.DetailSummary]{
@@ -385,7 +613,12 @@ func (h *ImageHandler) PruneImages(ctx context.Context, input *PruneImagesInput)
 		}
 	}
 
-	report, err := h.imageService.PruneImages(ctx, dangling)
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	report, err := h.imageService.PruneImages(ctx, dangling, *user)
 	if err != nil {
 		return nil, huma.Error500InternalServerError((&common.ImagePruneError{Err: err}).Error())
 	}
@@ -485,8 +718,13 @@ func (h *ImageHandler) UploadImage(ctx context.Context, input *UploadImageInput)
 	}
 	defer file.Close()
 
+	transferID := ""
+	if v := input.RawBody.Value["transferId"]; len(v) > 0 {
+		transferID = strings.TrimSpace(v[0])
+	}
+
 	// Load the image
-	result, err := h.imageService.LoadImageFromReader(ctx, file, fileName, *user, maxSizeBytes)
+	result, err := h.imageService.LoadImageFromReader(ctx, file, fileHeader.Size, fileName, transferID, *user, maxSizeBytes)
 	if err != nil {
 		return nil, huma.Error500InternalServerError((&common.ImageLoadError{Err: err}).Error())
 	}
@@ -498,3 +736,321 @@ func (h *ImageHandler) UploadImage(ctx context.Context, input *UploadImageInput)
 		},
 	}, nil
 }
+
+// SaveImages streams one or more images as a tar archive.
+func (h *ImageHandler) SaveImages(ctx context.Context, input *SaveImagesInput) (*huma.StreamResponse, error) {
+	if h.imageService == nil || h.settingsService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	// Exports are held to the upload limit so that the archive can be
+	// uploaded to another environment with the same settings.
+	maxSizeMB := h.settingsService.GetIntSetting(ctx, "maxImageUploadSize", 500)
+	maxSizeBytes := int64(maxSizeMB) * 1024 * 1024
+
+	stream, fileName, err := h.imageService.SaveImages(ctx, input.Body, *user, maxSizeBytes)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidImageTransfer):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, services.ErrImageNotFound):
+			return nil, huma.Error404NotFound((&common.ImageNotFoundError{Err: err}).Error())
+		case errors.Is(err, services.ErrImageTransferTooLarge):
+			return nil, huma.NewError(http.StatusRequestEntityTooLarge, err.Error())
+		default:
+			return nil, huma.Error500InternalServerError((&common.ImageSaveError{Err: err}).Error())
+		}
+	}
+
+	return &huma.StreamResponse{
+		Body: func(humaCtx huma.Context) {
+			defer func() { _ = stream.Close() }()
+
+			humaCtx.SetHeader("Content-Type", "application/x-tar")
+			humaCtx.SetHeader("Content-Disposition", "attachment; filename="+fileName)
+			humaCtx.SetStatus(http.StatusOK)
+
+			if _, err := io.Copy(humaCtx.BodyWriter(), stream); err != nil {
+				slog.WarnContext(humaCtx.Context(), "Image export interrupted", "images", input.Body.Images, "error", err)
+			}
+		},
+	}, nil
+}
+
+// BuildImage builds an image from a build context, a Dockerfile or a Git URL.
+func (h *ImageHandler) BuildImage(ctx context.Context, input *BuildImageInput) (*BuildImageOutput, error) {
+	if h.imageService == nil || h.settingsService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	formValue := func(key string) string {
+		if v := input.RawBody.Value[key]; len(v) > 0 {
+			return strings.TrimSpace(v[0])
+		}
+		return ""
+	}
+
+	req := image.BuildRequest{
+		BuildID:    formValue("buildId"),
+		Tags:       strings.Split(formValue("tags"), ","),
+		Dockerfile: formValue("dockerfilePath"),
+		GitURL:     formValue("gitUrl"),
+		NoCache:    formValue("noCache") == "true",
+		Pull:       formValue("pull") == "true",
+	}
+	for _, line := range strings.Split(formValue("buildArgs"), "\n") {
+		key, value, ok := strings.Cut(strings.TrimSpace(line), "=")
+		if !ok || strings.TrimSpace(key) == "" {
+			continue
+		}
+		if req.BuildArgs == nil {
+			req.BuildArgs = map[string]string{}
+		}
+		req.BuildArgs[strings.TrimSpace(key)] = value
+	}
+
+	maxSizeMB := h.settingsService.GetIntSetting(ctx, "maxImageUploadSize", 500)
+	maxSizeBytes := int64(maxSizeMB) * 1024 * 1024
+
+	var buildContext io.Reader
+	if files := input.RawBody.File["dockerfile"]; len(files) > 0 && req.GitURL == "" {
+		if files[0].Size > 1024*1024 {
+			return nil, huma.NewError(http.StatusRequestEntityTooLarge, "Dockerfile exceeds maximum allowed size of 1 MB")
+		}
+		file, err := files[0].Open()
+		if err != nil {
+			return nil, huma.Error500InternalServerError((&common.FileUploadReadError{Err: err}).Error())
+		}
+		content, err := io.ReadAll(file)
+		_ = file.Close()
+		if err != nil {
+			return nil, huma.Error500InternalServerError((&common.FileUploadReadError{Err: err}).Error())
+		}
+		req.DockerfileContent = string(content)
+	} else if files := input.RawBody.File["context"]; len(files) > 0 && req.GitURL == "" {
+		lowerName := strings.ToLower(files[0].Filename)
+		if !strings.HasSuffix(lowerName, ".tar") && !strings.HasSuffix(lowerName, ".tar.gz") && !strings.HasSuffix(lowerName, ".tgz") {
+			return nil, huma.Error400BadRequest("Invalid build context. Only tar archives are allowed (.tar, .tar.gz, .tgz)")
+		}
+		if files[0].Size > maxSizeBytes {
+			return nil, huma.NewError(http.StatusRequestEntityTooLarge, fmt.Sprintf("file size exceeds maximum allowed size of %d MB", maxSizeMB))
+		}
+		file, err := files[0].Open()
+		if err != nil {
+			return nil, huma.Error500InternalServerError((&common.FileUploadReadError{Err: err}).Error())
+		}
+		defer file.Close()
+		buildContext = file
+	}
+
+	result, err := h.imageService.BuildImage(ctx, buildContext, req, *user)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidBuild) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		return nil, huma.Error500InternalServerError((&common.ImageBuildError{Err: err}).Error())
+	}
+
+	return &BuildImageOutput{
+		Body: base.ApiResponse[image.BuildResult]{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
+// ListPinnedImages returns all pinned images.
+func (h *ImageHandler) ListPinnedImages(ctx context.Context, input *ListPinnedImagesInput) (*ListPinnedImagesOutput, error) {
+	if h.imageService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	pins, err := h.imageService.ListPinnedImages(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &ListPinnedImagesOutput{
+		Body: base.ApiResponse[[]image.PinnedImage]{
+			Success: true,
+			Data:    pins,
+		},
+	}, nil
+}
+
+// PinImage protects an image from prune jobs and auto-update.
+func (h *ImageHandler) PinImage(ctx context.Context, input *PinImageInput) (*PinImageOutput, error) {
+	if h.imageService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	reason := ""
+	if input.Body != nil {
+		reason = strings.TrimSpace(input.Body.Reason)
+	}
+
+	pin, err := h.imageService.PinImage(ctx, input.ImageID, reason, *user)
+	if err != nil {
+		if errors.Is(err, services.ErrImageNotFound) {
+			return nil, huma.Error404NotFound((&common.ImageNotFoundError{Err: err}).Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &PinImageOutput{
+		Body: base.ApiResponse[image.PinnedImage]{
+			Success: true,
+			Data:    *pin,
+		},
+	}, nil
+}
+
+// UnpinImage removes the pin from an image.
+func (h *ImageHandler) UnpinImage(ctx context.Context, input *UnpinImageInput) (*UnpinImageOutput, error) {
+	if h.imageService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	if err := h.imageService.UnpinImage(ctx, input.ImageID, *user); err != nil {
+		if errors.Is(err, services.ErrImageNotPinned) {
+			return nil, huma.Error404NotFound(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &UnpinImageOutput{
+		Body: base.ApiResponse[base.MessageResponse]{
+			Success: true,
+			Data: base.MessageResponse{
+				Message: "Image unpinned successfully",
+			},
+		},
+	}, nil
+}
+
+// GetImageHistory returns the layers and configuration of an image.
+func (h *ImageHandler) GetImageHistory(ctx context.Context, input *GetImageHistoryInput) (*GetImageHistoryOutput, error) {
+	if h.imageService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	history, err := h.imageService.GetImageHistory(ctx, input.ImageID)
+	if err != nil {
+		if errors.Is(err, services.ErrImageNotFound) {
+			return nil, huma.Error404NotFound((&common.ImageNotFoundError{Err: err}).Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &GetImageHistoryOutput{
+		Body: base.ApiResponse[image.History]{
+			Success: true,
+			Data:    *history,
+		},
+	}, nil
+}
+
+// DiffImages compares two images.
+func (h *ImageHandler) DiffImages(ctx context.Context, input *DiffImagesInput) (*DiffImagesOutput, error) {
+	if h.imageService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	result, err := h.imageService.DiffImages(ctx, input.Left, input.Right)
+	if err != nil {
+		if errors.Is(err, services.ErrImageNotFound) {
+			return nil, huma.Error404NotFound((&common.ImageNotFoundError{Err: err}).Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &DiffImagesOutput{
+		Body: base.ApiResponse[image.Diff]{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
+// TagImage adds a tag to an image.
+func (h *ImageHandler) TagImage(ctx context.Context, input *TagImageInput) (*TagImageOutput, error) {
+	if h.imageService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	result, err := h.imageService.TagImage(ctx, input.ImageID, strings.TrimSpace(input.Body.Tag), input.Body.Force, *user)
+	if err != nil {
+		return nil, imageTagErrorInternal(err)
+	}
+
+	return &TagImageOutput{
+		Body: base.ApiResponse[image.TagResult]{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
+// UntagImage removes a tag from an image.
+func (h *ImageHandler) UntagImage(ctx context.Context, input *UntagImageInput) (*UntagImageOutput, error) {
+	if h.imageService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	result, err := h.imageService.UntagImage(ctx, input.ImageID, strings.TrimSpace(input.Tag), *user)
+	if err != nil {
+		return nil, imageTagErrorInternal(err)
+	}
+
+	return &UntagImageOutput{
+		Body: base.ApiResponse[image.TagResult]{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
+func imageTagErrorInternal(err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidImageTag):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, services.ErrImageNotFound):
+		return huma.Error404NotFound((&common.ImageNotFoundError{Err: err}).Error())
+	case errors.Is(err, services.ErrImageTagNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, services.ErrImageTagConflict), errors.Is(err, services.ErrImageLastTag):
+		return huma.Error409Conflict(err.Error())
+	default:
+		return huma.Error500InternalServerError(err.Error())
+	}
+}
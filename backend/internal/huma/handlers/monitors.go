@@ -0,0 +1,305 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/common"
+	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/types/base"
+	monitortypes "github.com/getarcaneapp/arcane/types/monitor"
+)
+
+// MonitorHandler handles endpoint monitors.
+type MonitorHandler struct {
+	monitorService *services.EndpointMonitorService
+}
+
+type ListMonitorsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ProjectID     string `query:"projectId" doc:"Only monitors attached to this project"`
+	ContainerKey  string `query:"containerKey" doc:"Only monitors attached to this container name or ID"`
+}
+
+// MonitorsResponse is a dedicated response type
+type MonitorsResponse struct {
+	Success bool                   `json:"success"`
+	Data    []monitortypes.Monitor `json:"data"`
+}
+
+type ListMonitorsOutput struct {
+	Body MonitorsResponse
+}
+
+type GetMonitorInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	MonitorID     string `path:"monitorId" doc:"Monitor ID"`
+}
+
+// MonitorResponse is a dedicated response type
+type MonitorResponse struct {
+	Success bool                 `json:"success"`
+	Data    monitortypes.Monitor `json:"data"`
+}
+
+type GetMonitorOutput struct {
+	Body MonitorResponse
+}
+
+type CreateMonitorInput struct {
+	EnvironmentID string               `path:"id" doc:"Environment ID"`
+	Body          monitortypes.Request `doc:"Monitor definition"`
+}
+
+type CreateMonitorOutput struct {
+	Body MonitorResponse
+}
+
+type UpdateMonitorInput struct {
+	EnvironmentID string               `path:"id" doc:"Environment ID"`
+	MonitorID     string               `path:"monitorId" doc:"Monitor ID"`
+	Body          monitortypes.Request `doc:"Monitor definition"`
+}
+
+type UpdateMonitorOutput struct {
+	Body MonitorResponse
+}
+
+type DeleteMonitorInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	MonitorID     string `path:"monitorId" doc:"Monitor ID"`
+}
+
+// DeleteMonitorResponse is a dedicated response type
+type DeleteMonitorResponse struct {
+	Success bool                 `json:"success"`
+	Data    base.MessageResponse `json:"data"`
+}
+
+type DeleteMonitorOutput struct {
+	Body DeleteMonitorResponse
+}
+
+type ListMonitorResultsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	MonitorID     string `path:"monitorId" doc:"Monitor ID"`
+	Limit         int    `query:"limit" default:"100" minimum:"1" maximum:"1000" doc:"Number of most recent results to return"`
+}
+
+// MonitorResultsResponse is a dedicated response type
+type MonitorResultsResponse struct {
+	Success bool                  `json:"success"`
+	Data    []monitortypes.Result `json:"data"`
+}
+
+type ListMonitorResultsOutput struct {
+	Body MonitorResultsResponse
+}
+
+// RegisterMonitors registers endpoint monitor endpoints.
+func RegisterMonitors(api huma.API, monitorSvc *services.EndpointMonitorService) {
+	h := &MonitorHandler{monitorService: monitorSvc}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-monitors",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/monitors",
+		Summary:     "List endpoint monitors",
+		Description: "List HTTP and TCP endpoint monitors and their last results",
+		Tags:        []string{"Monitors"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.ListMonitors)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "create-monitor",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/monitors",
+		Summary:     "Create endpoint monitor",
+		Description: "Add an HTTP or TCP monitor, optionally attached to a project or container",
+		Tags:        []string{"Monitors"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.CreateMonitor)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-monitor",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/monitors/{monitorId}",
+		Summary:     "Get endpoint monitor",
+		Tags:        []string{"Monitors"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.GetMonitor)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-monitor",
+		Method:      http.MethodPut,
+		Path:        "/environments/{id}/monitors/{monitorId}",
+		Summary:     "Update endpoint monitor",
+		Description: "Replace the definition of an endpoint monitor; its status starts over as pending",
+		Tags:        []string{"Monitors"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.UpdateMonitor)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-monitor",
+		Method:      http.MethodDelete,
+		Path:        "/environments/{id}/monitors/{monitorId}",
+		Summary:     "Delete endpoint monitor",
+		Tags:        []string{"Monitors"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.DeleteMonitor)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-monitor-results",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/monitors/{monitorId}/results",
+		Summary:     "List endpoint monitor results",
+		Description: "List the most recent probe results of an endpoint monitor, newest first",
+		Tags:        []string{"Monitors"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.ListResults)
+}
+
+// ListMonitors returns endpoint monitors.
+func (h *MonitorHandler) ListMonitors(ctx context.Context, input *ListMonitorsInput) (*ListMonitorsOutput, error) {
+	if h.monitorService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	monitors, err := h.monitorService.ListMonitors(ctx, input.ProjectID, input.ContainerKey)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &ListMonitorsOutput{
+		Body: MonitorsResponse{
+			Success: true,
+			Data:    monitors,
+		},
+	}, nil
+}
+
+// GetMonitor returns a single endpoint monitor.
+func (h *MonitorHandler) GetMonitor(ctx context.Context, input *GetMonitorInput) (*GetMonitorOutput, error) {
+	if h.monitorService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	monitor, err := h.monitorService.GetMonitor(ctx, input.MonitorID)
+	if err != nil {
+		return nil, monitorErrorInternal(err)
+	}
+
+	return &GetMonitorOutput{
+		Body: MonitorResponse{
+			Success: true,
+			Data:    *monitor,
+		},
+	}, nil
+}
+
+// CreateMonitor adds an endpoint monitor.
+func (h *MonitorHandler) CreateMonitor(ctx context.Context, input *CreateMonitorInput) (*CreateMonitorOutput, error) {
+	if h.monitorService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	monitor, err := h.monitorService.CreateMonitor(ctx, input.Body, *user)
+	if err != nil {
+		return nil, monitorErrorInternal(err)
+	}
+
+	return &CreateMonitorOutput{
+		Body: MonitorResponse{
+			Success: true,
+			Data:    *monitor,
+		},
+	}, nil
+}
+
+// UpdateMonitor replaces an endpoint monitor.
+func (h *MonitorHandler) UpdateMonitor(ctx context.Context, input *UpdateMonitorInput) (*UpdateMonitorOutput, error) {
+	if h.monitorService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	monitor, err := h.monitorService.UpdateMonitor(ctx, input.MonitorID, input.Body, *user)
+	if err != nil {
+		return nil, monitorErrorInternal(err)
+	}
+
+	return &UpdateMonitorOutput{
+		Body: MonitorResponse{
+			Success: true,
+			Data:    *monitor,
+		},
+	}, nil
+}
+
+// DeleteMonitor removes an endpoint monitor.
+func (h *MonitorHandler) DeleteMonitor(ctx context.Context, input *DeleteMonitorInput) (*DeleteMonitorOutput, error) {
+	if h.monitorService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	if _, exists := humamw.GetCurrentUserFromContext(ctx); !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	if err := h.monitorService.DeleteMonitor(ctx, input.MonitorID); err != nil {
+		return nil, monitorErrorInternal(err)
+	}
+
+	return &DeleteMonitorOutput{
+		Body: DeleteMonitorResponse{
+			Success: true,
+			Data: base.MessageResponse{
+				Message: "Monitor deleted successfully",
+			},
+		},
+	}, nil
+}
+
+// ListResults returns the result history of an endpoint monitor.
+func (h *MonitorHandler) ListResults(ctx context.Context, input *ListMonitorResultsInput) (*ListMonitorResultsOutput, error) {
+	if h.monitorService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	results, err := h.monitorService.ListResults(ctx, input.MonitorID, input.Limit)
+	if err != nil {
+		return nil, monitorErrorInternal(err)
+	}
+
+	return &ListMonitorResultsOutput{
+		Body: MonitorResultsResponse{
+			Success: true,
+			Data:    results,
+		},
+	}, nil
+}
+
+// monitorErrorInternal maps endpoint monitor errors to HTTP errors. It
+// returns a 500 for anything else.
+func monitorErrorInternal(err error) error {
+	switch {
+	case errors.Is(err, services.ErrEndpointMonitorNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, services.ErrInvalidEndpointMonitor):
+		return huma.Error400BadRequest(err.Error())
+	default:
+		return huma.Error500InternalServerError(err.Error())
+	}
+}
@@ -0,0 +1,333 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/types/base"
+	namespacetypes "github.com/getarcaneapp/arcane/types/namespace"
+)
+
+// NamespaceHandler handles namespace management endpoints.
+type NamespaceHandler struct {
+	namespaceService *services.NamespaceService
+}
+
+// ============================================================================
+// Input/Output Types
+// ============================================================================
+
+type ListNamespacesInput struct{}
+
+type ListNamespacesOutput struct {
+	Body base.ApiResponse[[]namespacetypes.Namespace]
+}
+
+type CreateNamespaceInput struct {
+	Body namespacetypes.Create
+}
+
+type GetNamespaceInput struct {
+	NamespaceID string `path:"namespaceId" doc:"Namespace ID"`
+}
+
+type UpdateNamespaceInput struct {
+	NamespaceID string `path:"namespaceId" doc:"Namespace ID"`
+	Body        namespacetypes.Update
+}
+
+type SetNamespaceMembersInput struct {
+	NamespaceID string `path:"namespaceId" doc:"Namespace ID"`
+	Body        namespacetypes.SetMembers
+}
+
+type SetNamespaceProjectsInput struct {
+	NamespaceID string `path:"namespaceId" doc:"Namespace ID"`
+	Body        namespacetypes.SetProjects
+}
+
+type NamespaceOutput struct {
+	Body base.ApiResponse[namespacetypes.Namespace]
+}
+
+type DeleteNamespaceOutput struct {
+	Body base.ApiResponse[base.MessageResponse]
+}
+
+type ListNamespaceUsageOutput struct {
+	Body base.ApiResponse[[]namespacetypes.Usage]
+}
+
+type NamespaceUsageOutput struct {
+	Body base.ApiResponse[namespacetypes.Usage]
+}
+
+// ============================================================================
+// Registration
+// ============================================================================
+
+// RegisterNamespaces registers the namespace management endpoints. Listing is
+// open to every user and returns their own namespaces; the rest is admin only.
+func RegisterNamespaces(api huma.API, namespaceService *services.NamespaceService) {
+	h := &NamespaceHandler{namespaceService: namespaceService}
+	security := []map[string][]string{
+		{"BearerAuth": {}},
+		{"ApiKeyAuth": {}},
+	}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "listNamespaces",
+		Method:      http.MethodGet,
+		Path:        "/namespaces",
+		Summary:     "List namespaces",
+		Description: "List all namespaces for admins, or the namespaces the current user belongs to",
+		Tags:        []string{"Namespaces"},
+		Security:    security,
+	}, h.ListNamespaces)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "listNamespaceUsage",
+		Method:      http.MethodGet,
+		Path:        "/namespaces/usage",
+		Summary:     "List namespace quota usage",
+		Description: "Report consumption against quotas on the local environment for every namespace visible to the current user",
+		Tags:        []string{"Namespaces"},
+		Security:    security,
+	}, h.ListUsage)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "createNamespace",
+		Method:      http.MethodPost,
+		Path:        "/namespaces",
+		Summary:     "Create a namespace",
+		Tags:        []string{"Namespaces"},
+		Security:    security,
+	}, h.CreateNamespace)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getNamespace",
+		Method:      http.MethodGet,
+		Path:        "/namespaces/{namespaceId}",
+		Summary:     "Get a namespace",
+		Tags:        []string{"Namespaces"},
+		Security:    security,
+	}, h.GetNamespace)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "updateNamespace",
+		Method:      http.MethodPut,
+		Path:        "/namespaces/{namespaceId}",
+		Summary:     "Update a namespace",
+		Description: "Change the description or quotas of a namespace",
+		Tags:        []string{"Namespaces"},
+		Security:    security,
+	}, h.UpdateNamespace)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "deleteNamespace",
+		Method:      http.MethodDelete,
+		Path:        "/namespaces/{namespaceId}",
+		Summary:     "Delete a namespace",
+		Description: "Delete a namespace and release its projects. Docker resources keep their namespace label.",
+		Tags:        []string{"Namespaces"},
+		Security:    security,
+	}, h.DeleteNamespace)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "setNamespaceMembers",
+		Method:      http.MethodPut,
+		Path:        "/namespaces/{namespaceId}/members",
+		Summary:     "Set namespace members",
+		Tags:        []string{"Namespaces"},
+		Security:    security,
+	}, h.SetMembers)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "setNamespaceProjects",
+		Method:      http.MethodPut,
+		Path:        "/namespaces/{namespaceId}/projects",
+		Summary:     "Set namespace projects",
+		Tags:        []string{"Namespaces"},
+		Security:    security,
+	}, h.SetProjects)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getNamespaceUsage",
+		Method:      http.MethodGet,
+		Path:        "/namespaces/{namespaceId}/usage",
+		Summary:     "Get namespace quota usage",
+		Description: "Report the containers, memory reservations and volume size the namespace uses on the local environment",
+		Tags:        []string{"Namespaces"},
+		Security:    security,
+	}, h.GetUsage)
+}
+
+// ============================================================================
+// Handler Methods
+// ============================================================================
+
+// ListNamespaces returns the namespaces visible to the current user.
+func (h *NamespaceHandler) ListNamespaces(ctx context.Context, _ *ListNamespacesInput) (*ListNamespacesOutput, error) {
+	if h.namespaceService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	namespaces, err := h.namespaceService.ListNamespaces(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &ListNamespacesOutput{
+		Body: base.ApiResponse[[]namespacetypes.Namespace]{
+			Success: true,
+			Data:    namespaces,
+		},
+	}, nil
+}
+
+// CreateNamespace creates a namespace.
+func (h *NamespaceHandler) CreateNamespace(ctx context.Context, input *CreateNamespaceInput) (*NamespaceOutput, error) {
+	return h.adminNamespaceInternal(ctx, func() (*namespacetypes.Namespace, error) {
+		return h.namespaceService.CreateNamespace(ctx, input.Body)
+	})
+}
+
+// GetNamespace returns a namespace.
+func (h *NamespaceHandler) GetNamespace(ctx context.Context, input *GetNamespaceInput) (*NamespaceOutput, error) {
+	return h.adminNamespaceInternal(ctx, func() (*namespacetypes.Namespace, error) {
+		return h.namespaceService.GetNamespace(ctx, input.NamespaceID)
+	})
+}
+
+// UpdateNamespace changes a namespace's description or quotas.
+func (h *NamespaceHandler) UpdateNamespace(ctx context.Context, input *UpdateNamespaceInput) (*NamespaceOutput, error) {
+	return h.adminNamespaceInternal(ctx, func() (*namespacetypes.Namespace, error) {
+		return h.namespaceService.UpdateNamespace(ctx, input.NamespaceID, input.Body)
+	})
+}
+
+// SetMembers replaces the members of a namespace.
+func (h *NamespaceHandler) SetMembers(ctx context.Context, input *SetNamespaceMembersInput) (*NamespaceOutput, error) {
+	return h.adminNamespaceInternal(ctx, func() (*namespacetypes.Namespace, error) {
+		return h.namespaceService.SetMembers(ctx, input.NamespaceID, input.Body.UserIDs)
+	})
+}
+
+// SetProjects replaces the projects owned by a namespace.
+func (h *NamespaceHandler) SetProjects(ctx context.Context, input *SetNamespaceProjectsInput) (*NamespaceOutput, error) {
+	return h.adminNamespaceInternal(ctx, func() (*namespacetypes.Namespace, error) {
+		return h.namespaceService.SetProjects(ctx, input.NamespaceID, input.Body.ProjectIDs)
+	})
+}
+
+// DeleteNamespace deletes a namespace.
+func (h *NamespaceHandler) DeleteNamespace(ctx context.Context, input *GetNamespaceInput) (*DeleteNamespaceOutput, error) {
+	if h.namespaceService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := h.namespaceService.DeleteNamespace(ctx, input.NamespaceID); err != nil {
+		return nil, namespaceErrorInternal(err)
+	}
+
+	return &DeleteNamespaceOutput{
+		Body: base.ApiResponse[base.MessageResponse]{
+			Success: true,
+			Data:    base.MessageResponse{Message: "Namespace deleted successfully"},
+		},
+	}, nil
+}
+
+// ListUsage reports the quota usage of the namespaces visible to the current
+// user.
+func (h *NamespaceHandler) ListUsage(ctx context.Context, _ *ListNamespacesInput) (*ListNamespaceUsageOutput, error) {
+	if h.namespaceService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	usage, err := h.namespaceService.ListUsage(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &ListNamespaceUsageOutput{
+		Body: base.ApiResponse[[]namespacetypes.Usage]{
+			Success: true,
+			Data:    usage,
+		},
+	}, nil
+}
+
+// GetUsage reports a namespace's quota usage.
+func (h *NamespaceHandler) GetUsage(ctx context.Context, input *GetNamespaceInput) (*NamespaceUsageOutput, error) {
+	if h.namespaceService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	usage, err := h.namespaceService.GetUsage(ctx, input.NamespaceID)
+	if err != nil {
+		return nil, namespaceErrorInternal(err)
+	}
+
+	return &NamespaceUsageOutput{
+		Body: base.ApiResponse[namespacetypes.Usage]{
+			Success: true,
+			Data:    *usage,
+		},
+	}, nil
+}
+
+func (h *NamespaceHandler) adminNamespaceInternal(ctx context.Context, fn func() (*namespacetypes.Namespace, error)) (*NamespaceOutput, error) {
+	if h.namespaceService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	ns, err := fn()
+	if err != nil {
+		return nil, namespaceErrorInternal(err)
+	}
+
+	return &NamespaceOutput{
+		Body: base.ApiResponse[namespacetypes.Namespace]{
+			Success: true,
+			Data:    *ns,
+		},
+	}, nil
+}
+
+// isNamespaceErrorInternal reports whether err is a namespace access or quota
+// error that create endpoints should surface as is.
+func isNamespaceErrorInternal(err error) bool {
+	return errors.Is(err, services.ErrNamespaceNotFound) ||
+		errors.Is(err, services.ErrNamespaceForbidden) ||
+		errors.Is(err, services.ErrNamespaceQuotaExceeded)
+}
+
+// namespaceErrorInternal maps namespace errors to HTTP errors. It returns a
+// 500 for anything else.
+func namespaceErrorInternal(err error) error {
+	switch {
+	case errors.Is(err, services.ErrNamespaceNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, services.ErrInvalidNamespace):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, services.ErrNamespaceExists), errors.Is(err, services.ErrNamespaceQuotaExceeded):
+		return huma.Error409Conflict(err.Error())
+	case errors.Is(err, services.ErrNamespaceForbidden):
+		return huma.Error403Forbidden(err.Error())
+	default:
+		return huma.Error500InternalServerError(err.Error())
+	}
+}
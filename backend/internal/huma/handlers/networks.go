@@ -3,6 +3,7 @@ package handlers
 import (
 	"bytes"
 	"context"
+	"errors"
 	"net"
 	"net/http"
 	"sort"
@@ -250,6 +251,9 @@ func (h *NetworkHandler) CreateNetwork(ctx context.Context, input *CreateNetwork
 	dockerOptions := input.Body.Options.ToDockerCreateOptions()
 
 	response, err := h.networkService.CreateNetwork(ctx, input.Body.Name, dockerOptions, *user)
+	if errors.Is(err, services.ErrInvalidNetworkOptions) {
+		return nil, huma.Error400BadRequest(err.Error())
+	}
 	if err != nil {
 		return nil, huma.Error500InternalServerError((&common.NetworkCreationError{Err: err}).Error())
 	}
@@ -366,7 +370,12 @@ func (h *NetworkHandler) DeleteNetwork(ctx context.Context, input *DeleteNetwork
 }
 
 func (h *NetworkHandler) PruneNetworks(ctx context.Context, input *PruneNetworksInput) (*PruneNetworksOutput, error) {
-	report, err := h.networkService.PruneNetworks(ctx)
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	report, err := h.networkService.PruneNetworks(ctx, *user)
 	if err != nil {
 		return nil, huma.Error500InternalServerError((&common.NetworkPruneError{Err: err}).Error())
 	}
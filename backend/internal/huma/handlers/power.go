@@ -0,0 +1,220 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/common"
+	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/types/base"
+	"github.com/getarcaneapp/arcane/types/system"
+)
+
+// PowerHandler handles Wake-on-LAN and host power endpoints.
+type PowerHandler struct {
+	powerService *services.PowerService
+}
+
+// ============================================================================
+// Input/Output Types
+// ============================================================================
+
+type WakeEnvironmentInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type WakeEnvironmentOutput struct {
+	Body base.ApiResponse[base.MessageResponse]
+}
+
+type GetPowerCapabilitiesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type GetPowerCapabilitiesOutput struct {
+	Body base.ApiResponse[system.PowerCapabilities]
+}
+
+type SendWakeOnLanInput struct {
+	EnvironmentID string                  `path:"id" doc:"Environment ID"`
+	Body          system.WakeOnLanRequest `doc:"Machine to wake"`
+}
+
+type SendWakeOnLanOutput struct {
+	Body base.ApiResponse[base.MessageResponse]
+}
+
+type RebootHostInput struct {
+	EnvironmentID string               `path:"id" doc:"Environment ID"`
+	Body          system.RebootRequest `doc:"Reboot confirmation"`
+}
+
+type RebootHostOutput struct {
+	Body base.ApiResponse[base.MessageResponse]
+}
+
+// ============================================================================
+// Registration
+// ============================================================================
+
+// RegisterPower registers the Wake-on-LAN and host power endpoints.
+func RegisterPower(api huma.API, powerService *services.PowerService) {
+	h := &PowerHandler{powerService: powerService}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "wakeEnvironment",
+		Method:      "POST",
+		Path:        "/environments/{id}/wake",
+		Summary:     "Wake an environment",
+		Description: "Send a Wake-on-LAN packet to the environment's host, from the manager or from the environment's wake relay.",
+		Tags:        []string{"Environments"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.WakeEnvironment)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "getPowerCapabilities",
+		Method:      "GET",
+		Path:        "/environments/{id}/system/power",
+		Summary:     "Get power capabilities",
+		Description: "Report which power actions the environment's host supports.",
+		Tags:        []string{"System"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.GetPowerCapabilities)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "sendWakeOnLan",
+		Method:      "POST",
+		Path:        "/environments/{id}/system/power/wake",
+		Summary:     "Send a Wake-on-LAN packet",
+		Description: "Send a Wake-on-LAN packet from the environment's host to another machine on its LAN.",
+		Tags:        []string{"System"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.SendWakeOnLan)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "rebootHost",
+		Method:      "POST",
+		Path:        "/environments/{id}/system/power/reboot",
+		Summary:     "Reboot the host",
+		Description: "Run the host's reboot hook. Requires HOST_REBOOT_COMMAND on the host and an explicit confirmation.",
+		Tags:        []string{"System"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.RebootHost)
+}
+
+// ============================================================================
+// Handler Methods
+// ============================================================================
+
+// WakeEnvironment sends a Wake-on-LAN packet to an environment's host.
+func (h *PowerHandler) WakeEnvironment(ctx context.Context, input *WakeEnvironmentInput) (*WakeEnvironmentOutput, error) {
+	if h.powerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	if err := h.powerService.WakeEnvironment(ctx, input.EnvironmentID, *user); err != nil {
+		return nil, powerErrorInternal(err)
+	}
+
+	return &WakeEnvironmentOutput{
+		Body: base.ApiResponse[base.MessageResponse]{
+			Success: true,
+			Data:    base.MessageResponse{Message: "Wake-on-LAN packet sent"},
+		},
+	}, nil
+}
+
+// GetPowerCapabilities reports which power actions the host supports.
+func (h *PowerHandler) GetPowerCapabilities(ctx context.Context, input *GetPowerCapabilitiesInput) (*GetPowerCapabilitiesOutput, error) {
+	if h.powerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	return &GetPowerCapabilitiesOutput{
+		Body: base.ApiResponse[system.PowerCapabilities]{
+			Success: true,
+			Data:    h.powerService.Capabilities(),
+		},
+	}, nil
+}
+
+// SendWakeOnLan sends a Wake-on-LAN packet from this host. The manager uses it
+// to wake machines through a relay environment on their LAN.
+func (h *PowerHandler) SendWakeOnLan(ctx context.Context, input *SendWakeOnLanInput) (*SendWakeOnLanOutput, error) {
+	if h.powerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if err := h.powerService.SendWakeOnLAN(ctx, input.Body.MacAddress, input.Body.BroadcastAddress); err != nil {
+		return nil, powerErrorInternal(err)
+	}
+
+	return &SendWakeOnLanOutput{
+		Body: base.ApiResponse[base.MessageResponse]{
+			Success: true,
+			Data:    base.MessageResponse{Message: "Wake-on-LAN packet sent"},
+		},
+	}, nil
+}
+
+// RebootHost runs the host's reboot hook.
+func (h *PowerHandler) RebootHost(ctx context.Context, input *RebootHostInput) (*RebootHostOutput, error) {
+	if h.powerService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	if err := h.powerService.RebootHost(ctx, input.Body, *user); err != nil {
+		return nil, powerErrorInternal(err)
+	}
+
+	return &RebootHostOutput{
+		Body: base.ApiResponse[base.MessageResponse]{
+			Success: true,
+			Data:    base.MessageResponse{Message: "Host reboot initiated"},
+		},
+	}, nil
+}
+
+func powerErrorInternal(err error) error {
+	switch {
+	case errors.Is(err, services.ErrInvalidWakeTarget),
+		errors.Is(err, services.ErrWakeNotConfigured),
+		errors.Is(err, services.ErrRebootNotConfirmed):
+		return huma.Error400BadRequest(err.Error())
+	case errors.Is(err, services.ErrRebootNotConfigured):
+		return huma.Error501NotImplemented(err.Error())
+	default:
+		return huma.Error500InternalServerError(err.Error())
+	}
+}
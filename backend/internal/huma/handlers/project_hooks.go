@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/common"
+	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	projecttypes "github.com/getarcaneapp/arcane/types/project"
+)
+
+// ProjectHookHandler handles the deploy hooks of projects.
+type ProjectHookHandler struct {
+	hookService *services.ProjectHookService
+}
+
+type ListProjectHooksInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ProjectID     string `path:"projectId" doc:"Project ID"`
+}
+
+// ProjectHooksResponse is a dedicated response type
+type ProjectHooksResponse struct {
+	Success bool                      `json:"success"`
+	Data    []projecttypes.DeployHook `json:"data"`
+}
+
+type ListProjectHooksOutput struct {
+	Body ProjectHooksResponse
+}
+
+type SetProjectHooksInput struct {
+	EnvironmentID string                          `path:"id" doc:"Environment ID"`
+	ProjectID     string                          `path:"projectId" doc:"Project ID"`
+	Body          projecttypes.DeployHooksRequest `doc:"Deploy hooks of the project"`
+}
+
+type SetProjectHooksOutput struct {
+	Body ProjectHooksResponse
+}
+
+// RegisterProjectHooks registers the project deploy hook endpoints.
+func RegisterProjectHooks(api huma.API, hookSvc *services.ProjectHookService) {
+	h := &ProjectHookHandler{hookService: hookSvc}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-project-hooks",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/projects/{projectId}/hooks",
+		Summary:     "List project deploy hooks",
+		Description: "List the commands and webhooks run before and after the project is deployed",
+		Tags:        []string{"Projects"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.ListHooks)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "set-project-hooks",
+		Method:      http.MethodPut,
+		Path:        "/environments/{id}/projects/{projectId}/hooks",
+		Summary:     "Set project deploy hooks",
+		Description: "Replace the pre- and post-deploy hooks of the project. Hooks run a command in a service container or a one-off helper container, or call a webhook",
+		Tags:        []string{"Projects"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.SetHooks)
+}
+
+// ListHooks returns the deploy hooks of a project.
+func (h *ProjectHookHandler) ListHooks(ctx context.Context, input *ListProjectHooksInput) (*ListProjectHooksOutput, error) {
+	if h.hookService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	hooks, err := h.hookService.ListHooks(ctx, input.ProjectID)
+	if err != nil {
+		if errors.Is(err, services.ErrProjectNotFound) {
+			return nil, huma.Error404NotFound(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &ListProjectHooksOutput{
+		Body: ProjectHooksResponse{
+			Success: true,
+			Data:    hooks,
+		},
+	}, nil
+}
+
+// SetHooks replaces the deploy hooks of a project.
+func (h *ProjectHookHandler) SetHooks(ctx context.Context, input *SetProjectHooksInput) (*SetProjectHooksOutput, error) {
+	if h.hookService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	hooks, err := h.hookService.SetHooks(ctx, input.ProjectID, input.Body, *user)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProjectNotFound):
+			return nil, huma.Error404NotFound(err.Error())
+		case errors.Is(err, services.ErrInvalidDeployHook):
+			return nil, huma.Error400BadRequest(err.Error())
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	return &SetProjectHooksOutput{
+		Body: ProjectHooksResponse{
+			Success: true,
+			Data:    hooks,
+		},
+	}, nil
+}
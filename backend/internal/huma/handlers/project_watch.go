@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/common"
+	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/types/base"
+	projecttypes "github.com/getarcaneapp/arcane/types/project"
+)
+
+// ProjectWatchHandler handles compose watch (development file sync) of
+// projects.
+type ProjectWatchHandler struct {
+	watchService *services.ProjectWatchService
+}
+
+type GetProjectWatchInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ProjectID     string `path:"projectId" doc:"Project ID"`
+}
+
+type GetProjectWatchOutput struct {
+	Body base.ApiResponse[projecttypes.WatchStatus]
+}
+
+type StartProjectWatchInput struct {
+	EnvironmentID string                          `path:"id" doc:"Environment ID"`
+	ProjectID     string                          `path:"projectId" doc:"Project ID"`
+	Body          *projecttypes.WatchStartRequest `required:"false"`
+}
+
+type StartProjectWatchOutput struct {
+	Body base.ApiResponse[projecttypes.WatchStatus]
+}
+
+type StopProjectWatchInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ProjectID     string `path:"projectId" doc:"Project ID"`
+}
+
+type StopProjectWatchOutput struct {
+	Body base.ApiResponse[projecttypes.WatchStatus]
+}
+
+// RegisterProjectWatch registers the project watch endpoints.
+func RegisterProjectWatch(api huma.API, watchSvc *services.ProjectWatchService) {
+	h := &ProjectWatchHandler{watchService: watchSvc}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-project-watch",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/projects/{projectId}/watch",
+		Summary:     "Get project watch status",
+		Description: "Report whether the project's files are being watched for changes",
+		Tags:        []string{"Projects"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.GetWatch)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "start-project-watch",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/projects/{projectId}/watch/start",
+		Summary:     "Start watching a project",
+		Description: "Sync, restart or rebuild services as files in the project change, following the develop.watch sections of the compose file. Progress is streamed on the project watch WebSocket",
+		Tags:        []string{"Projects"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.StartWatch)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "stop-project-watch",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/projects/{projectId}/watch/stop",
+		Summary:     "Stop watching a project",
+		Description: "Stop syncing file changes of the project",
+		Tags:        []string{"Projects"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.StopWatch)
+}
+
+// GetWatch returns the watch status of a project.
+func (h *ProjectWatchHandler) GetWatch(ctx context.Context, input *GetProjectWatchInput) (*GetProjectWatchOutput, error) {
+	if h.watchService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	return &GetProjectWatchOutput{
+		Body: base.ApiResponse[projecttypes.WatchStatus]{
+			Success: true,
+			Data:    h.watchService.Status(input.ProjectID),
+		},
+	}, nil
+}
+
+// StartWatch starts watching a project.
+func (h *ProjectWatchHandler) StartWatch(ctx context.Context, input *StartProjectWatchInput) (*StartProjectWatchOutput, error) {
+	if h.watchService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	var watchServices []string
+	if input.Body != nil {
+		watchServices = input.Body.Services
+	}
+	status, err := h.watchService.Start(ctx, input.ProjectID, watchServices, *user)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProjectWatchRunning):
+			return nil, huma.Error409Conflict(err.Error())
+		case errors.Is(err, services.ErrProjectServiceNotFound), errors.Is(err, services.ErrProjectWatchNotConfigured):
+			return nil, huma.Error400BadRequest(err.Error())
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	return &StartProjectWatchOutput{
+		Body: base.ApiResponse[projecttypes.WatchStatus]{
+			Success: true,
+			Data:    *status,
+		},
+	}, nil
+}
+
+// StopWatch stops watching a project.
+func (h *ProjectWatchHandler) StopWatch(ctx context.Context, input *StopProjectWatchInput) (*StopProjectWatchOutput, error) {
+	if h.watchService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	if err := h.watchService.Stop(ctx, input.ProjectID, *user); err != nil {
+		if errors.Is(err, services.ErrProjectWatchNotRunning) {
+			return nil, huma.Error409Conflict(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &StopProjectWatchOutput{
+		Body: base.ApiResponse[projecttypes.WatchStatus]{
+			Success: true,
+			Data:    h.watchService.Status(input.ProjectID),
+		},
+	}, nil
+}
@@ -57,8 +57,9 @@ type GetProjectStatusCountsOutput struct {
 }
 
 type DeployProjectInput struct {
-	EnvironmentID string `path:"id" doc:"Environment ID"`
-	ProjectID     string `path:"projectId" doc:"Project ID"`
+	EnvironmentID    string `path:"id" doc:"Environment ID"`
+	ProjectID        string `path:"projectId" doc:"Project ID"`
+	OverrideScanGate bool   `query:"overrideScanGate" default:"false" doc:"Deploy even if the vulnerability scan gate blocks an image (admin only, recorded as an event)"`
 }
 
 type DeployProjectOutput struct {
@@ -140,6 +141,44 @@ type RestartProjectOutput struct {
 	Body base.ApiResponse[base.MessageResponse]
 }
 
+type CanaryUpdateProjectInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ProjectID     string `path:"projectId" doc:"Project ID"`
+	Body          project.CanaryUpdateRequest
+}
+
+type CanaryUpdateProjectOutput struct {
+	Body base.ApiResponse[project.CanaryUpdateResult]
+}
+
+type GetProjectInputsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ProjectID     string `path:"projectId" doc:"Project ID"`
+}
+
+type GetProjectInputsOutput struct {
+	Body base.ApiResponse[[]project.InputState]
+}
+
+type GetProjectIncludeGraphInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ProjectID     string `path:"projectId" doc:"Project ID"`
+}
+
+type GetProjectIncludeGraphOutput struct {
+	Body base.ApiResponse[project.IncludeGraph]
+}
+
+type SetProjectInputsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ProjectID     string `path:"projectId" doc:"Project ID"`
+	Body          project.InputsRequest
+}
+
+type SetProjectInputsOutput struct {
+	Body base.ApiResponse[[]project.InputState]
+}
+
 type PullProjectImagesInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	ProjectID     string `path:"projectId" doc:"Project ID"`
@@ -319,6 +358,58 @@ func RegisterProjects(api huma.API, projectService *services.ProjectService) {
 			{"ApiKeyAuth": {}},
 		},
 	}, h.PullProjectImages)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "canary-update-project-service",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/projects/{projectId}/canary",
+		Summary:     "Canary update a project service",
+		Description: "Update one replica of a service first, check its health for the bake time, then update the remaining replicas or roll the canary back",
+		Tags:        []string{"Projects"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.CanaryUpdateProject)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-project-inputs",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/projects/{projectId}/inputs",
+		Summary:     "Get project inputs",
+		Description: "List the typed inputs declared in the x-arcane.inputs block of a project with their current values",
+		Tags:        []string{"Projects"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.GetProjectInputs)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-project-include-graph",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/projects/{projectId}/includes/graph",
+		Summary:     "Get project include graph",
+		Description: "Report how the compose files of a project include each other, flagging include cycles, missing files and files that fail to parse",
+		Tags:        []string{"Projects"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.GetProjectIncludeGraph)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "set-project-inputs",
+		Method:      http.MethodPut,
+		Path:        "/environments/{id}/projects/{projectId}/inputs",
+		Summary:     "Set project inputs",
+		Description: "Validate input values and render them into the .env file of a project",
+		Tags:        []string{"Projects"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.SetProjectInputs)
 }
 
 // ListProjects returns a paginated list of projects.
@@ -408,6 +499,9 @@ func (h *ProjectHandler) DeployProject(ctx context.Context, input *DeployProject
 	if !exists {
 		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
 	}
+	if _, err := scanGateContextInternal(ctx, input.OverrideScanGate); err != nil {
+		return nil, err
+	}
 
 	return &huma.StreamResponse{
 		Body: func(humaCtx huma.Context) { //nolint:contextcheck // context is obtained from humaCtx.Context()
@@ -424,6 +518,9 @@ func (h *ProjectHandler) DeployProject(ctx context.Context, input *DeployProject
 			}
 
 			deployCtx := context.WithValue(humaCtx.Context(), projects.ProgressWriterKey{}, writer)
+			if input.OverrideScanGate {
+				deployCtx = services.WithScanGateOverride(deployCtx)
+			}
 			if err := h.projectService.DeployProject(deployCtx, input.ProjectID, *user); err != nil {
 				_, _ = fmt.Fprintf(writer, `{"error":%q}`+"\n", err.Error())
 				if f, ok := writer.(http.Flusher); ok {
@@ -478,6 +575,9 @@ func (h *ProjectHandler) CreateProject(ctx context.Context, input *CreateProject
 
 	proj, err := h.projectService.CreateProject(ctx, input.Body.Name, input.Body.ComposeContent, input.Body.EnvContent, *user)
 	if err != nil {
+		if isNamespaceErrorInternal(err) {
+			return nil, namespaceErrorInternal(err)
+		}
 		return nil, huma.Error500InternalServerError((&common.ProjectCreationError{Err: err}).Error())
 	}
 
@@ -552,6 +652,129 @@ func (h *ProjectHandler) RedeployProject(ctx context.Context, input *RedeployPro
 	}, nil
 }
 
+// CanaryUpdateProject updates a service of a project one canary replica first.
+func (h *ProjectHandler) CanaryUpdateProject(ctx context.Context, input *CanaryUpdateProjectInput) (*CanaryUpdateProjectOutput, error) {
+	if h.projectService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	if input.ProjectID == "" {
+		return nil, huma.Error400BadRequest((&common.ProjectIDRequiredError{}).Error())
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	result, err := h.projectService.CanaryUpdateService(ctx, input.ProjectID, input.Body, *user)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrProjectServiceNotFound):
+			return nil, huma.Error404NotFound(err.Error())
+		case errors.Is(err, services.ErrCanaryNotEnoughReplicas):
+			return nil, huma.Error400BadRequest(err.Error())
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	return &CanaryUpdateProjectOutput{
+		Body: base.ApiResponse[project.CanaryUpdateResult]{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
+// GetProjectInputs lists the x-arcane inputs of a project.
+func (h *ProjectHandler) GetProjectInputs(ctx context.Context, input *GetProjectInputsInput) (*GetProjectInputsOutput, error) {
+	if h.projectService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	if input.ProjectID == "" {
+		return nil, huma.Error400BadRequest((&common.ProjectIDRequiredError{}).Error())
+	}
+
+	states, err := h.projectService.GetProjectInputs(ctx, input.ProjectID)
+	if err != nil {
+		return nil, projectInputsErrorInternal(err)
+	}
+
+	return &GetProjectInputsOutput{
+		Body: base.ApiResponse[[]project.InputState]{
+			Success: true,
+			Data:    states,
+		},
+	}, nil
+}
+
+// GetProjectIncludeGraph reports the include graph of a project.
+func (h *ProjectHandler) GetProjectIncludeGraph(ctx context.Context, input *GetProjectIncludeGraphInput) (*GetProjectIncludeGraphOutput, error) {
+	if h.projectService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	if input.ProjectID == "" {
+		return nil, huma.Error400BadRequest((&common.ProjectIDRequiredError{}).Error())
+	}
+
+	graph, err := h.projectService.GetProjectIncludeGraph(ctx, input.ProjectID)
+	if err != nil {
+		if errors.Is(err, services.ErrProjectNotFound) {
+			return nil, huma.Error404NotFound(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &GetProjectIncludeGraphOutput{
+		Body: base.ApiResponse[project.IncludeGraph]{
+			Success: true,
+			Data:    *graph,
+		},
+	}, nil
+}
+
+// SetProjectInputs saves the x-arcane input values of a project.
+func (h *ProjectHandler) SetProjectInputs(ctx context.Context, input *SetProjectInputsInput) (*SetProjectInputsOutput, error) {
+	if h.projectService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	if input.ProjectID == "" {
+		return nil, huma.Error400BadRequest((&common.ProjectIDRequiredError{}).Error())
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	states, err := h.projectService.SetProjectInputs(ctx, input.ProjectID, input.Body, *user)
+	if err != nil {
+		return nil, projectInputsErrorInternal(err)
+	}
+
+	return &SetProjectInputsOutput{
+		Body: base.ApiResponse[[]project.InputState]{
+			Success: true,
+			Data:    states,
+		},
+	}, nil
+}
+
+func projectInputsErrorInternal(err error) error {
+	switch {
+	case errors.Is(err, services.ErrProjectNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, services.ErrInvalidProjectInput):
+		return huma.Error400BadRequest(err.Error())
+	default:
+		return huma.Error500InternalServerError(err.Error())
+	}
+}
+
 // DestroyProject destroys a Docker Compose project.
 func (h *ProjectHandler) DestroyProject(ctx context.Context, input *DestroyProjectInput) (*DestroyProjectOutput, error) {
 	if h.projectService == nil {
@@ -26,6 +26,7 @@ type SettingsHandler struct {
 	settingsService       *services.SettingsService
 	settingsSearchService *services.SettingsSearchService
 	environmentService    *services.EnvironmentService
+	volumeService         *services.VolumeService
 	cfg                   *config.Config
 }
 
@@ -95,11 +96,12 @@ func validateProjectsDirectoryValue(path string) error {
 }
 
 // RegisterSettings registers settings management routes using Huma.
-func RegisterSettings(api huma.API, settingsService *services.SettingsService, settingsSearchService *services.SettingsSearchService, environmentService *services.EnvironmentService, cfg *config.Config) {
+func RegisterSettings(api huma.API, settingsService *services.SettingsService, settingsSearchService *services.SettingsSearchService, environmentService *services.EnvironmentService, volumeService *services.VolumeService, cfg *config.Config) {
 	h := &SettingsHandler{
 		settingsService:       settingsService,
 		settingsSearchService: settingsSearchService,
 		environmentService:    environmentService,
+		volumeService:         volumeService,
 		cfg:                   cfg,
 	}
 
@@ -339,6 +341,20 @@ func (h *SettingsHandler) UpdateSettings(ctx context.Context, input *UpdateSetti
 		return &UpdateSettingsOutput{Body: apiResp}, nil
 	}
 
+	// Validate a newly pinned helper image here rather than on the proxy path,
+	// since only the environment that runs the helpers can check the image.
+	if input.Body.HelperImage != nil && h.volumeService != nil {
+		helperImage := strings.TrimSpace(*input.Body.HelperImage)
+		if helperImage != "" && helperImage != h.settingsService.GetSettingsConfig().HelperImage.Value {
+			if err := h.volumeService.ValidateHelperImage(ctx, helperImage); err != nil {
+				if errors.Is(err, services.ErrInvalidHelperImage) {
+					return nil, huma.Error400BadRequest(err.Error())
+				}
+				return nil, huma.Error500InternalServerError(err.Error())
+			}
+		}
+	}
+
 	updatedSettings, err := h.settingsService.UpdateSettings(ctx, input.Body)
 	if err != nil {
 		return nil, huma.Error500InternalServerError((&common.SettingsUpdateError{Err: err}).Error())
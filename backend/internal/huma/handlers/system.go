@@ -9,6 +9,7 @@ import (
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/getarcaneapp/arcane/backend/internal/common"
 	"github.com/getarcaneapp/arcane/backend/internal/config"
+	"github.com/getarcaneapp/arcane/backend/internal/database"
 	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
 	"github.com/getarcaneapp/arcane/backend/internal/models"
 	"github.com/getarcaneapp/arcane/backend/internal/services"
@@ -21,10 +22,11 @@ import (
 
 // SystemHandler handles system management endpoints.
 type SystemHandler struct {
-	dockerService  *services.DockerClientService
-	systemService  *services.SystemService
-	upgradeService *services.SystemUpgradeService
-	cfg            *config.Config
+	dockerService    *services.DockerClientService
+	systemService    *services.SystemService
+	upgradeService   *services.SystemUpgradeService
+	operationService *services.OperationService
+	cfg              *config.Config
 }
 
 // --- Input/Output Types ---
@@ -54,6 +56,54 @@ type PruneAllOutput struct {
 	Body base.ApiResponse[system.PruneAllResult]
 }
 
+type ListPruneReportsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Limit         int    `query:"limit" default:"50" minimum:"1" maximum:"500" doc:"Maximum number of reports"`
+}
+
+// PruneReportListResponse is a dedicated response type to avoid schema name
+// collision with image.PruneReport
+type PruneReportListResponse struct {
+	Success bool                 `json:"success"`
+	Data    []system.PruneReport `json:"data"`
+}
+
+type ListPruneReportsOutput struct {
+	Body PruneReportListResponse
+}
+
+type GetPruneReportInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ReportID      string `path:"reportId" doc:"Prune report ID"`
+}
+
+// PruneReportResponse is a dedicated response type to avoid schema name
+// collision with image.PruneReport
+type PruneReportResponse struct {
+	Success bool               `json:"success"`
+	Data    system.PruneReport `json:"data"`
+}
+
+type GetPruneReportOutput struct {
+	Body PruneReportResponse
+}
+
+type ListOperationsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type ListOperationsOutput struct {
+	Body base.ApiResponse[[]system.Operation]
+}
+
+type ListSlowQueriesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type ListSlowQueriesOutput struct {
+	Body base.ApiResponse[system.SlowQueryReport]
+}
+
 type StartAllContainersInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 }
@@ -112,12 +162,13 @@ type TriggerUpgradeOutput struct {
 
 // RegisterSystem registers system management endpoints using Huma.
 // Note: WebSocket endpoints (stats) remain in the Gin handler.
-func RegisterSystem(api huma.API, dockerService *services.DockerClientService, systemService *services.SystemService, upgradeService *services.SystemUpgradeService, cfg *config.Config) {
+func RegisterSystem(api huma.API, dockerService *services.DockerClientService, systemService *services.SystemService, upgradeService *services.SystemUpgradeService, operationService *services.OperationService, cfg *config.Config) {
 	h := &SystemHandler{
-		dockerService:  dockerService,
-		systemService:  systemService,
-		upgradeService: upgradeService,
-		cfg:            cfg,
+		dockerService:    dockerService,
+		systemService:    systemService,
+		upgradeService:   upgradeService,
+		operationService: operationService,
+		cfg:              cfg,
 	}
 
 	huma.Register(api, huma.Operation{
@@ -160,6 +211,58 @@ func RegisterSystem(api huma.API, dockerService *services.DockerClientService, s
 		},
 	}, h.PruneAll)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "list-prune-reports",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/system/prune/reports",
+		Summary:     "List prune reports",
+		Description: "List past prune operations, newest first",
+		Tags:        []string{"System"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.ListPruneReports)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-prune-report",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/system/prune/reports/{reportId}",
+		Summary:     "Get prune report",
+		Description: "Get a prune report with every removed resource and the space it reclaimed",
+		Tags:        []string{"System"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.GetPruneReport)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-operations",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/system/operations",
+		Summary:     "List running operations",
+		Description: "List the long-running operations in progress, such as volume backups and restores, oldest first",
+		Tags:        []string{"System"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.ListOperations)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-slow-queries",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/system/debug/slow-queries",
+		Summary:     "List slow database queries",
+		Description: "List the most recent database queries that ran for longer than DB_SLOW_QUERY_THRESHOLD, newest first. Admin only.",
+		Tags:        []string{"System"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.ListSlowQueries)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "start-all-containers",
 		Method:      http.MethodPost,
@@ -331,7 +434,12 @@ func (h *SystemHandler) PruneAll(ctx context.Context, input *PruneAllInput) (*Pr
 		"build_cache", input.Body.BuildCache,
 		"dangling", input.Body.Dangling)
 
-	result, err := h.systemService.PruneAll(ctx, input.Body)
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	result, err := h.systemService.PruneAll(ctx, input.Body, models.PruneTriggerManual, *user)
 	if err != nil {
 		slog.ErrorContext(ctx, "System prune operation failed", "error", err)
 		return nil, huma.Error500InternalServerError((&common.SystemPruneError{Err: err}).Error())
@@ -352,6 +460,91 @@ func (h *SystemHandler) PruneAll(ctx context.Context, input *PruneAllInput) (*Pr
 	}, nil
 }
 
+// ListPruneReports returns past prune operations.
+func (h *SystemHandler) ListPruneReports(ctx context.Context, input *ListPruneReportsInput) (*ListPruneReportsOutput, error) {
+	if h.systemService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	reports, err := h.systemService.ListPruneReports(ctx, input.Limit)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &ListPruneReportsOutput{
+		Body: PruneReportListResponse{
+			Success: true,
+			Data:    reports,
+		},
+	}, nil
+}
+
+// GetPruneReport returns a single prune report with its items.
+func (h *SystemHandler) GetPruneReport(ctx context.Context, input *GetPruneReportInput) (*GetPruneReportOutput, error) {
+	if h.systemService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	report, err := h.systemService.GetPruneReport(ctx, input.ReportID)
+	if err != nil {
+		if errors.Is(err, services.ErrPruneReportNotFound) {
+			return nil, huma.Error404NotFound(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &GetPruneReportOutput{
+		Body: PruneReportResponse{
+			Success: true,
+			Data:    *report,
+		},
+	}, nil
+}
+
+// ListOperations returns the long-running operations in progress.
+func (h *SystemHandler) ListOperations(ctx context.Context, input *ListOperationsInput) (*ListOperationsOutput, error) {
+	active := h.operationService.Active()
+	ops := make([]system.Operation, 0, len(active))
+	for _, op := range active {
+		ops = append(ops, system.Operation{ID: op.ID, Kind: op.Kind, Resource: op.Resource, StartedAt: op.StartedAt})
+	}
+
+	return &ListOperationsOutput{
+		Body: base.ApiResponse[[]system.Operation]{
+			Success: true,
+			Data:    ops,
+		},
+	}, nil
+}
+
+func (h *SystemHandler) ListSlowQueries(ctx context.Context, input *ListSlowQueriesInput) (*ListSlowQueriesOutput, error) {
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	recent, threshold := database.RecentSlowQueries()
+	queries := make([]system.SlowQuery, 0, len(recent))
+	for _, q := range recent {
+		queries = append(queries, system.SlowQuery{
+			SQL:        q.SQL,
+			Rows:       q.Rows,
+			DurationMs: float64(q.Duration.Microseconds()) / 1000,
+			Timestamp:  q.At,
+			Error:      q.Error,
+		})
+	}
+
+	return &ListSlowQueriesOutput{
+		Body: base.ApiResponse[system.SlowQueryReport]{
+			Success: true,
+			Data: system.SlowQueryReport{
+				ThresholdMs: threshold.Milliseconds(),
+				Queries:     queries,
+			},
+		},
+	}, nil
+}
+
 // StartAllContainers starts all Docker containers.
 func (h *SystemHandler) StartAllContainers(ctx context.Context, input *StartAllContainersInput) (*StartAllContainersOutput, error) {
 	if h.systemService == nil {
@@ -0,0 +1,155 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/common"
+	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/types/base"
+	"github.com/getarcaneapp/arcane/types/updater"
+)
+
+// UpdateRolloutHandler handles the endpoints of staged auto-update rollouts.
+type UpdateRolloutHandler struct {
+	rolloutService *services.UpdateRolloutService
+}
+
+// ============================================================================
+// Input/Output Types
+// ============================================================================
+
+type ListUpdateRolloutsInput struct {
+	Limit int `query:"limit" default:"20" doc:"Number of rollouts to return"`
+}
+
+type ListUpdateRolloutsOutput struct {
+	Body base.ApiResponse[[]updater.Rollout]
+}
+
+type UpdateRolloutActionInput struct {
+	RolloutID string `path:"rolloutId" doc:"Rollout ID"`
+}
+
+type UpdateRolloutActionOutput struct {
+	Body base.ApiResponse[updater.Rollout]
+}
+
+// ============================================================================
+// Registration
+// ============================================================================
+
+// RegisterUpdateRollouts registers the staged auto-update rollout endpoints.
+func RegisterUpdateRollouts(api huma.API, rolloutService *services.UpdateRolloutService) {
+	h := &UpdateRolloutHandler{rolloutService: rolloutService}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "listUpdateRollouts",
+		Method:      "GET",
+		Path:        "/updater/rollouts",
+		Summary:     "List auto-update rollouts",
+		Description: "List staged auto-update rollouts across environments, newest first",
+		Tags:        []string{"Updater"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.ListRollouts)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "promoteUpdateRollout",
+		Method:      "POST",
+		Path:        "/updater/rollouts/{rolloutId}/promote",
+		Summary:     "Promote an auto-update rollout",
+		Description: "Roll the canary's image updates out to the other environments now, skipping the rest of the soak period. Halted rollouts can be promoted too.",
+		Tags:        []string{"Updater"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.PromoteRollout)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "haltUpdateRollout",
+		Method:      "POST",
+		Path:        "/updater/rollouts/{rolloutId}/halt",
+		Summary:     "Halt an auto-update rollout",
+		Description: "Stop a rollout so the canary's image updates never reach the other environments",
+		Tags:        []string{"Updater"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.HaltRollout)
+}
+
+// ============================================================================
+// Handler Methods
+// ============================================================================
+
+// ListRollouts returns recent rollouts.
+func (h *UpdateRolloutHandler) ListRollouts(ctx context.Context, input *ListUpdateRolloutsInput) (*ListUpdateRolloutsOutput, error) {
+	if h.rolloutService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	rollouts, err := h.rolloutService.ListRollouts(ctx, input.Limit)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &ListUpdateRolloutsOutput{
+		Body: base.ApiResponse[[]updater.Rollout]{
+			Success: true,
+			Data:    rollouts,
+		},
+	}, nil
+}
+
+// PromoteRollout rolls a rollout out to the other environments now.
+func (h *UpdateRolloutHandler) PromoteRollout(ctx context.Context, input *UpdateRolloutActionInput) (*UpdateRolloutActionOutput, error) {
+	return h.act(ctx, input, h.rolloutService.PromoteRollout)
+}
+
+// HaltRollout stops a rollout.
+func (h *UpdateRolloutHandler) HaltRollout(ctx context.Context, input *UpdateRolloutActionInput) (*UpdateRolloutActionOutput, error) {
+	return h.act(ctx, input, h.rolloutService.HaltRollout)
+}
+
+type rolloutActionFunc func(ctx context.Context, id string, user models.User) (*updater.Rollout, error)
+
+func (h *UpdateRolloutHandler) act(ctx context.Context, input *UpdateRolloutActionInput, action rolloutActionFunc) (*UpdateRolloutActionOutput, error) {
+	if h.rolloutService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	rollout, err := action(ctx, input.RolloutID, *user)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrRolloutNotFound):
+			return nil, huma.Error404NotFound(err.Error())
+		case errors.Is(err, services.ErrRolloutFinished), errors.Is(err, services.ErrRolloutInProgress):
+			return nil, huma.Error409Conflict(err.Error())
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	return &UpdateRolloutActionOutput{
+		Body: base.ApiResponse[updater.Rollout]{
+			Success: true,
+			Data:    *rollout,
+		},
+	}, nil
+}
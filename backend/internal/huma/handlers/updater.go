@@ -120,11 +120,13 @@ func (h *UpdaterHandler) RunUpdater(ctx context.Context, input *RunUpdaterInput)
 	}
 
 	dryRun := false
+	var images []string
 	if input.Body != nil {
 		dryRun = input.Body.DryRun
+		images = input.Body.Images
 	}
 
-	out, err := h.updaterService.ApplyPending(ctx, dryRun)
+	out, err := h.updaterService.ApplyPendingImages(ctx, dryRun, images)
 	if err != nil {
 		return nil, huma.Error500InternalServerError((&common.UpdaterRunError{Err: err}).Error())
 	}
@@ -0,0 +1,99 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/common"
+	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/types/base"
+	volumetypes "github.com/getarcaneapp/arcane/types/volume"
+)
+
+// VolumeTransferHandler handles moving volume backups between environments.
+type VolumeTransferHandler struct {
+	volumeTransferService *services.VolumeTransferService
+}
+
+// ============================================================================
+// Input/Output Types
+// ============================================================================
+
+type CrossEnvironmentRestoreInput struct {
+	Body volumetypes.CrossEnvironmentRestoreRequest
+}
+
+type CrossEnvironmentRestoreOutput struct {
+	Body base.ApiResponse[base.MessageResponse]
+}
+
+// ============================================================================
+// Registration
+// ============================================================================
+
+// RegisterVolumeTransfers registers the cross-environment volume backup routes.
+// They live outside /environments/{id} so the manager handles them instead of
+// proxying them to a single agent.
+func RegisterVolumeTransfers(api huma.API, volumeTransferService *services.VolumeTransferService) {
+	h := &VolumeTransferHandler{volumeTransferService: volumeTransferService}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "restore-volume-backup-cross-environment",
+		Method:      http.MethodPost,
+		Path:        "/volume-backups/cross-environment-restore",
+		Summary:     "Restore a backup from another environment",
+		Description: "Pull a backup archive from the source environment and restore it into a volume on the target environment",
+		Tags:        []string{"Volume Backup"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.RestoreFromEnvironment)
+}
+
+// ============================================================================
+// Handler Methods
+// ============================================================================
+
+// RestoreFromEnvironment restores a backup taken on one environment into a
+// volume on another.
+func (h *VolumeTransferHandler) RestoreFromEnvironment(ctx context.Context, input *CrossEnvironmentRestoreInput) (*CrossEnvironmentRestoreOutput, error) {
+	if h.volumeTransferService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	if err := checkAdmin(ctx); err != nil {
+		return nil, err
+	}
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	if err := h.volumeTransferService.RestoreFromEnvironment(ctx, input.Body, *user); err != nil {
+		switch {
+		case errors.Is(err, services.ErrTransferSameEnvironment),
+			errors.Is(err, services.ErrTransferEdgeUnsupported),
+			errors.Is(err, services.ErrInvalidBackupArchive),
+			errors.Is(err, services.ErrSnapshotBackupUnsupported):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, services.ErrTransferRequiresApproval):
+			return nil, huma.Error409Conflict(err.Error())
+		case errors.Is(err, services.ErrBackupUploadTooLarge):
+			return nil, huma.NewError(http.StatusRequestEntityTooLarge, err.Error())
+		case errors.Is(err, services.ErrTransferEnvironmentFailed):
+			return nil, huma.NewError(http.StatusBadGateway, err.Error())
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	return &CrossEnvironmentRestoreOutput{
+		Body: base.ApiResponse[base.MessageResponse]{
+			Success: true,
+			Data:    base.MessageResponse{Message: "Backup restored from source environment successfully"},
+		},
+	}, nil
+}
@@ -2,6 +2,8 @@ package handlers
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"path"
@@ -98,6 +100,35 @@ type PruneVolumesOutput struct {
 	Body base.ApiResponse[VolumePruneReportData]
 }
 
+type BulkVolumeOperationInput struct {
+	EnvironmentID string                  `path:"id" doc:"Environment ID"`
+	Body          volumetypes.BulkRequest `doc:"Bulk operation request"`
+}
+
+type BulkVolumeOperationOutput struct {
+	Body base.ApiResponse[volumetypes.BulkResult]
+}
+
+type CloneVolumeInput struct {
+	EnvironmentID string            `path:"id" doc:"Environment ID"`
+	VolumeName    string            `path:"volumeName" doc:"Source volume name"`
+	Body          volumetypes.Clone `doc:"Clone options"`
+}
+
+type CloneVolumeOutput struct {
+	Body base.ApiResponse[*volumetypes.Volume]
+}
+
+type MigrateVolumeInput struct {
+	EnvironmentID string              `path:"id" doc:"Environment ID"`
+	VolumeName    string              `path:"volumeName" doc:"Source volume name"`
+	Body          volumetypes.Migrate `doc:"Migration options"`
+}
+
+type MigrateVolumeOutput struct {
+	Body base.ApiResponse[volumetypes.MigrateResult]
+}
+
 type GetVolumeUsageInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	VolumeName    string `path:"volumeName" doc:"Volume name"`
@@ -152,16 +183,34 @@ type GetFileContentInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	VolumeName    string `path:"volumeName" doc:"Volume name"`
 	Path          string `query:"path" doc:"File path"`
-	MaxBytes      int64  `query:"maxBytes" default:"1048576" doc:"Maximum bytes to read (default 1MB)"`
+	MaxBytes      int64  `query:"maxBytes" default:"1048576" maximum:"10485760" doc:"Maximum bytes to read (default 1MB, max 10MB)"`
+	Offset        int64  `query:"offset" default:"0" minimum:"0" doc:"Byte offset to start reading from"`
+	Tail          bool   `query:"tail" default:"false" doc:"Read the last maxBytes of the file instead of starting at offset"`
 }
 
-type FileContentResponse struct {
-	Content  []byte `json:"content"`
-	MimeType string `json:"mimeType"`
+type GetFileContentOutput struct {
+	Body base.ApiResponse[volumetypes.FileContent]
 }
 
-type GetFileContentOutput struct {
-	Body base.ApiResponse[FileContentResponse]
+type PreviewFileInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	VolumeName    string `path:"volumeName" doc:"Volume name"`
+	Path          string `query:"path" doc:"File path"`
+}
+
+type PreviewFileOutput struct {
+	Body base.ApiResponse[volumetypes.FilePreview]
+}
+
+type GetDiskUsageInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	VolumeName    string `path:"volumeName" doc:"Volume name"`
+	Path          string `query:"path" default:"/" doc:"Directory path"`
+	Depth         int    `query:"depth" default:"1" minimum:"1" maximum:"5" doc:"How many directory levels to descend"`
+}
+
+type GetDiskUsageOutput struct {
+	Body base.ApiResponse[volumetypes.DiskUsageEntry]
 }
 
 type DownloadFileInput struct {
@@ -177,6 +226,20 @@ type DownloadFileOutput struct {
 	Body               io.ReadCloser
 }
 
+type DownloadDirectoryInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	VolumeName    string `path:"volumeName" doc:"Volume name"`
+	Path          string `query:"path" default:"/" doc:"Directory path"`
+	Format        string `query:"format" default:"tar.gz" enum:"tar.gz,zip" doc:"Archive format"`
+}
+
+type DownloadDirectoryOutput struct {
+	ContentType        string `header:"Content-Type"`
+	ContentDisposition string `header:"Content-Disposition"`
+	UncompressedSize   int64  `header:"X-Uncompressed-Size"`
+	Body               io.ReadCloser
+}
+
 type UploadFileInput struct {
 	EnvironmentID string        `path:"id" doc:"Environment ID"`
 	VolumeName    string        `path:"volumeName" doc:"Volume name"`
@@ -190,6 +253,24 @@ type CreateDirectoryInput struct {
 	Path          string `query:"path" doc:"Directory path to create"`
 }
 
+type CopyPathInput struct {
+	EnvironmentID string                      `path:"id" doc:"Environment ID"`
+	VolumeName    string                      `path:"volumeName" doc:"Source volume name"`
+	Body          volumetypes.CopyPathRequest `doc:"Copy request"`
+}
+
+type ChangeOwnershipInput struct {
+	EnvironmentID string                             `path:"id" doc:"Environment ID"`
+	VolumeName    string                             `path:"volumeName" doc:"Volume name"`
+	Body          volumetypes.ChangeOwnershipRequest `doc:"Ownership change"`
+}
+
+type ChangePermissionsInput struct {
+	EnvironmentID string                               `path:"id" doc:"Environment ID"`
+	VolumeName    string                               `path:"volumeName" doc:"Volume name"`
+	Body          volumetypes.ChangePermissionsRequest `doc:"Permission change"`
+}
+
 type DeleteFileInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	VolumeName    string `path:"volumeName" doc:"Volume name"`
@@ -227,13 +308,14 @@ type CreateBackupOutput struct {
 }
 
 type RestoreBackupInput struct {
-	EnvironmentID string `path:"id" doc:"Environment ID"`
-	VolumeName    string `path:"volumeName" doc:"Volume name"`
-	BackupID      string `path:"backupId" doc:"Backup ID"`
+	EnvironmentID  string `path:"id" doc:"Environment ID"`
+	VolumeName     string `path:"volumeName" doc:"Volume name"`
+	BackupID       string `path:"backupId" doc:"Backup ID"`
+	StopContainers bool   `query:"stopContainers" default:"false" doc:"Stop running containers using the volume during the restore and start them again afterwards"`
 }
 
 type RestoreBackupOutput struct {
-	Body base.ApiResponse[base.MessageResponse]
+	Body base.ApiResponse[*volumetypes.RestoreResult]
 }
 
 type RestoreBackupFilesInput struct {
@@ -272,6 +354,14 @@ type ListBackupFilesOutput struct {
 	Body base.ApiResponse[[]string]
 }
 
+type ScanBackupsInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type ScanBackupsOutput struct {
+	Body base.ApiResponse[volumetypes.BackupAdoptResult]
+}
+
 type DeleteBackupInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	BackupID      string `path:"backupId" doc:"Backup ID"`
@@ -284,13 +374,8 @@ type DeleteBackupOutput struct {
 type DownloadBackupInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	BackupID      string `path:"backupId" doc:"Backup ID"`
-}
-
-type DownloadBackupOutput struct {
-	ContentType        string `header:"Content-Type"`
-	ContentDisposition string `header:"Content-Disposition"`
-	ContentLength      int64  `header:"Content-Length"`
-	Body               io.ReadCloser
+	Range         string `header:"Range" doc:"Byte range to resume an interrupted download, e.g. bytes=1048576-"`
+	IfRange       string `header:"If-Range" doc:"Only honor Range while the backup still has this ETag"`
 }
 
 type UploadAndRestoreInput struct {
@@ -300,7 +385,7 @@ type UploadAndRestoreInput struct {
 }
 
 type UploadAndRestoreOutput struct {
-	Body base.ApiResponse[base.MessageResponse]
+	Body base.ApiResponse[*volumetypes.RestoreResult]
 }
 
 // RegisterVolumes registers volume management routes using Huma.
@@ -388,6 +473,45 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		},
 	}, h.PruneVolumes)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "bulk-volume-operation",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/volumes/bulk",
+		Summary:     "Bulk volume operation",
+		Description: "Delete, back up, or relabel multiple volumes in one request with per-volume results",
+		Tags:        []string{"Volumes"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.BulkVolumeOperation)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "clone-volume",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/volumes/{volumeName}/clone",
+		Summary:     "Clone a volume",
+		Description: "Create a new volume containing a copy of the source volume's data",
+		Tags:        []string{"Volumes"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.CloneVolume)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "migrate-volume",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/volumes/{volumeName}/migrate",
+		Summary:     "Migrate a volume",
+		Description: "Move a volume's data to a volume with a different name and/or driver, optionally repointing stopped containers and deleting the source",
+		Tags:        []string{"Volumes"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.MigrateVolume)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "get-volume-usage",
 		Method:      http.MethodGet,
@@ -440,6 +564,32 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		},
 	}, h.GetFileContent)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "preview-volume-file",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/volumes/{volumeName}/browse/preview",
+		Summary:     "Preview file from volume",
+		Description: "List the tables of a SQLite database or the entries of a tar/zip archive stored in a volume",
+		Tags:        []string{"Volume Browser"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.PreviewFile)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-volume-disk-usage",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/volumes/{volumeName}/browse/usage",
+		Summary:     "Get volume disk usage",
+		Description: "Get the disk usage of a directory in a volume broken down by file and subdirectory",
+		Tags:        []string{"Volume Browser"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.GetDiskUsage)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "download-volume-file",
 		Method:      http.MethodGet,
@@ -452,6 +602,19 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		},
 	}, h.DownloadFile)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "download-volume-directory",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/volumes/{volumeName}/browse/download-directory",
+		Summary:     "Download directory from volume",
+		Description: "Stream a directory of a volume as a tar.gz or zip archive. The X-Uncompressed-Size header carries the directory size for progress estimates.",
+		Tags:        []string{"Volume Browser"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.DownloadDirectory)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "upload-volume-file",
 		Method:      http.MethodPost,
@@ -476,6 +639,45 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		},
 	}, h.CreateDirectory)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "chown-volume-path",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/volumes/{volumeName}/browse/chown",
+		Summary:     "Change file ownership",
+		Description: "Change the owner and/or group of a file or directory, optionally recursively",
+		Tags:        []string{"Volume Browser"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.ChangeOwnership)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "chmod-volume-path",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/volumes/{volumeName}/browse/chmod",
+		Summary:     "Change file permissions",
+		Description: "Change the mode of a file or directory, optionally recursively",
+		Tags:        []string{"Volume Browser"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.ChangePermissions)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "copy-volume-path",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/volumes/{volumeName}/browse/copy",
+		Summary:     "Copy file or directory",
+		Description: "Copy a file or directory to another path in the same volume or to another volume",
+		Tags:        []string{"Volume Browser"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.CopyPath)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "delete-volume-file",
 		Method:      http.MethodDelete,
@@ -519,6 +721,7 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		Method:      http.MethodPost,
 		Path:        "/environments/{id}/volumes/{volumeName}/backups/{backupId}/restore",
 		Summary:     "Restore volume backup",
+		Description: "Replace the volume contents with a backup. The backup is extracted and verified inside the volume first and swapped in afterwards; a failed restore returns 409 and leaves the volume unchanged, or 500 when the swap could not be undone",
 		Tags:        []string{"Volume Backup"},
 		Security: []map[string][]string{
 			{"BearerAuth": {}},
@@ -538,6 +741,19 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		},
 	}, h.RestoreBackupFiles)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "scan-volume-backups",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/volumes/backups/scan",
+		Summary:     "Scan for unknown backup archives",
+		Description: "Record backup archives found in the backup volume that Arcane does not know about yet",
+		Tags:        []string{"Volume Backup"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.ScanAndAdoptBackups)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "delete-volume-backup",
 		Method:      http.MethodDelete,
@@ -591,6 +807,7 @@ func RegisterVolumes(api huma.API, dockerService *services.DockerClientService,
 		Method:      http.MethodPost,
 		Path:        "/environments/{id}/volumes/{volumeName}/backups/upload",
 		Summary:     "Upload and restore volume backup",
+		Description: "Replace the volume contents with an uploaded tar.gz archive. The archive is extracted and verified inside the volume first and swapped in afterwards; a failed restore returns 409 and leaves the volume unchanged, or 500 when the swap could not be undone",
 		Tags:        []string{"Volume Backup"},
 		Security: []map[string][]string{
 			{"BearerAuth": {}},
@@ -697,6 +914,9 @@ func (h *VolumeHandler) CreateVolume(ctx context.Context, input *CreateVolumeInp
 
 	response, err := h.volumeService.CreateVolume(ctx, options, *user)
 	if err != nil {
+		if isNamespaceErrorInternal(err) {
+			return nil, namespaceErrorInternal(err)
+		}
 		return nil, huma.Error500InternalServerError((&common.VolumeCreationError{Err: err}).Error())
 	}
 
@@ -739,7 +959,12 @@ func (h *VolumeHandler) PruneVolumes(ctx context.Context, input *PruneVolumesInp
 		return nil, huma.Error500InternalServerError("service not available")
 	}
 
-	report, err := h.volumeService.PruneVolumes(ctx)
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	report, err := h.volumeService.PruneVolumes(ctx, *user)
 	if err != nil {
 		return nil, huma.Error500InternalServerError((&common.VolumePruneError{Err: err}).Error())
 	}
@@ -755,6 +980,78 @@ func (h *VolumeHandler) PruneVolumes(ctx context.Context, input *PruneVolumesInp
 	}, nil
 }
 
+// BulkVolumeOperation applies one action to multiple volumes.
+func (h *VolumeHandler) BulkVolumeOperation(ctx context.Context, input *BulkVolumeOperationInput) (*BulkVolumeOperationOutput, error) {
+	if h.volumeService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	result, err := h.volumeService.BulkVolumeOperation(ctx, input.Body, *user)
+	if err != nil {
+		return nil, huma.Error400BadRequest((&common.VolumeBulkOperationError{Err: err}).Error())
+	}
+
+	return &BulkVolumeOperationOutput{
+		Body: base.ApiResponse[volumetypes.BulkResult]{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
+// CloneVolume creates a new volume with a copy of an existing volume's data.
+func (h *VolumeHandler) CloneVolume(ctx context.Context, input *CloneVolumeInput) (*CloneVolumeOutput, error) {
+	if h.volumeService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	response, err := h.volumeService.CloneVolume(ctx, input.VolumeName, input.Body, *user)
+	if err != nil {
+		return nil, huma.Error500InternalServerError((&common.VolumeCloneError{Err: err}).Error())
+	}
+
+	return &CloneVolumeOutput{
+		Body: base.ApiResponse[*volumetypes.Volume]{
+			Success: true,
+			Data:    response,
+		},
+	}, nil
+}
+
+// MigrateVolume moves a volume's data to a new name and/or driver.
+func (h *VolumeHandler) MigrateVolume(ctx context.Context, input *MigrateVolumeInput) (*MigrateVolumeOutput, error) {
+	if h.volumeService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	result, err := h.volumeService.MigrateVolume(ctx, input.VolumeName, input.Body, *user)
+	if err != nil {
+		return nil, huma.Error500InternalServerError((&common.VolumeMigrateError{Err: err}).Error())
+	}
+
+	return &MigrateVolumeOutput{
+		Body: base.ApiResponse[volumetypes.MigrateResult]{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
 // GetVolumeUsage returns containers using a specific volume.
 func (h *VolumeHandler) GetVolumeUsage(ctx context.Context, input *GetVolumeUsageInput) (*GetVolumeUsageOutput, error) {
 	if h.volumeService == nil {
@@ -851,17 +1148,56 @@ func (h *VolumeHandler) GetFileContent(ctx context.Context, input *GetFileConten
 	if h.volumeService == nil {
 		return nil, huma.Error500InternalServerError("service not available")
 	}
-	content, mimeType, err := h.volumeService.GetFileContent(ctx, input.VolumeName, input.Path, input.MaxBytes)
+	content, err := h.volumeService.GetFileContent(ctx, input.VolumeName, input.Path, volumetypes.FileContentOptions{
+		MaxBytes: input.MaxBytes,
+		Offset:   input.Offset,
+		Tail:     input.Tail,
+	})
 	if err != nil {
 		return nil, huma.Error500InternalServerError(err.Error())
 	}
 	return &GetFileContentOutput{
-		Body: base.ApiResponse[FileContentResponse]{
+		Body: base.ApiResponse[volumetypes.FileContent]{
 			Success: true,
-			Data: FileContentResponse{
-				Content:  content,
-				MimeType: mimeType,
-			},
+			Data:    *content,
+		},
+	}, nil
+}
+
+func (h *VolumeHandler) PreviewFile(ctx context.Context, input *PreviewFileInput) (*PreviewFileOutput, error) {
+	if h.volumeService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	preview, err := h.volumeService.PreviewFile(ctx, input.VolumeName, input.Path)
+	if err != nil {
+		if errors.Is(err, services.ErrPreviewUnsupported) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+	return &PreviewFileOutput{
+		Body: base.ApiResponse[volumetypes.FilePreview]{
+			Success: true,
+			Data:    *preview,
+		},
+	}, nil
+}
+
+func (h *VolumeHandler) GetDiskUsage(ctx context.Context, input *GetDiskUsageInput) (*GetDiskUsageOutput, error) {
+	if h.volumeService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	usage, err := h.volumeService.GetDiskUsage(ctx, input.VolumeName, input.Path, input.Depth)
+	if err != nil {
+		if errors.Is(err, services.ErrNotDirectory) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+	return &GetDiskUsageOutput{
+		Body: base.ApiResponse[volumetypes.DiskUsageEntry]{
+			Success: true,
+			Data:    *usage,
 		},
 	}, nil
 }
@@ -882,12 +1218,44 @@ func (h *VolumeHandler) DownloadFile(ctx context.Context, input *DownloadFileInp
 	}, nil
 }
 
+func (h *VolumeHandler) DownloadDirectory(ctx context.Context, input *DownloadDirectoryInput) (*DownloadDirectoryOutput, error) {
+	if h.volumeService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	reader, size, err := h.volumeService.DownloadDirectory(ctx, input.VolumeName, input.Path, input.Format)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrNotDirectory):
+			return nil, huma.Error400BadRequest(err.Error())
+		case errors.Is(err, services.ErrDirectoryTooLarge):
+			return nil, huma.NewError(http.StatusRequestEntityTooLarge, err.Error())
+		default:
+			return nil, huma.Error500InternalServerError(err.Error())
+		}
+	}
+
+	name := path.Base(path.Clean("/" + input.Path))
+	if name == "/" {
+		name = input.VolumeName
+	}
+	contentType := "application/gzip"
+	if input.Format == services.DirectoryArchiveZip {
+		contentType = "application/zip"
+	}
+	return &DownloadDirectoryOutput{
+		ContentType:        contentType,
+		ContentDisposition: fmt.Sprintf("attachment; filename=%q", name+"."+input.Format),
+		UncompressedSize:   size,
+		Body:               reader,
+	}, nil
+}
+
 func (h *VolumeHandler) UploadFile(ctx context.Context, input *UploadFileInput) (*base.ApiResponse[base.MessageResponse], error) {
 	if h.volumeService == nil {
 		return nil, huma.Error500InternalServerError("service not available")
 	}
 	user, _ := humamw.GetCurrentUserFromContext(ctx)
-	err := h.volumeService.UploadFile(ctx, input.VolumeName, input.Path, input.File, input.File.Filename, user)
+	err := h.volumeService.UploadFile(ctx, input.VolumeName, input.Path, input.File, input.File.Size, input.File.Filename, user)
 	if err != nil {
 		return nil, huma.Error500InternalServerError(err.Error())
 	}
@@ -912,6 +1280,49 @@ func (h *VolumeHandler) CreateDirectory(ctx context.Context, input *CreateDirect
 	}, nil
 }
 
+func (h *VolumeHandler) ChangeOwnership(ctx context.Context, input *ChangeOwnershipInput) (*base.ApiResponse[base.MessageResponse], error) {
+	if h.volumeService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	user, _ := humamw.GetCurrentUserFromContext(ctx)
+	if err := h.volumeService.ChangeOwnership(ctx, input.VolumeName, input.Body, user); err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+	return &base.ApiResponse[base.MessageResponse]{
+		Success: true,
+		Data:    base.MessageResponse{Message: "Ownership changed successfully"},
+	}, nil
+}
+
+func (h *VolumeHandler) ChangePermissions(ctx context.Context, input *ChangePermissionsInput) (*base.ApiResponse[base.MessageResponse], error) {
+	if h.volumeService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	user, _ := humamw.GetCurrentUserFromContext(ctx)
+	if err := h.volumeService.ChangePermissions(ctx, input.VolumeName, input.Body, user); err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+	return &base.ApiResponse[base.MessageResponse]{
+		Success: true,
+		Data:    base.MessageResponse{Message: "Permissions changed successfully"},
+	}, nil
+}
+
+func (h *VolumeHandler) CopyPath(ctx context.Context, input *CopyPathInput) (*base.ApiResponse[base.MessageResponse], error) {
+	if h.volumeService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	user, _ := humamw.GetCurrentUserFromContext(ctx)
+	err := h.volumeService.CopyPath(ctx, input.VolumeName, input.Body.Path, input.Body.TargetVolume, input.Body.TargetPath, input.Body.Overwrite, user)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+	return &base.ApiResponse[base.MessageResponse]{
+		Success: true,
+		Data:    base.MessageResponse{Message: "Copied successfully"},
+	}, nil
+}
+
 func (h *VolumeHandler) DeleteFile(ctx context.Context, input *DeleteFileInput) (*base.ApiResponse[base.MessageResponse], error) {
 	if h.volumeService == nil {
 		return nil, huma.Error500InternalServerError("service not available")
@@ -991,6 +1402,9 @@ func (h *VolumeHandler) CreateBackup(ctx context.Context, input *CreateBackupInp
 
 	backup, err := h.volumeService.CreateBackup(ctx, input.VolumeName, *user)
 	if err != nil {
+		if errors.Is(err, services.ErrOperationConflict) {
+			return nil, huma.Error409Conflict(err.Error())
+		}
 		return nil, huma.Error500InternalServerError(err.Error())
 	}
 	return &CreateBackupOutput{
@@ -1010,14 +1424,14 @@ func (h *VolumeHandler) RestoreBackup(ctx context.Context, input *RestoreBackupI
 		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
 	}
 
-	err := h.volumeService.RestoreBackup(ctx, input.VolumeName, input.BackupID, *user)
+	result, err := h.volumeService.RestoreBackup(ctx, input.VolumeName, input.BackupID, input.StopContainers, *user)
 	if err != nil {
-		return nil, huma.Error500InternalServerError(err.Error())
+		return nil, restoreErrorInternal(err)
 	}
 	return &RestoreBackupOutput{
-		Body: base.ApiResponse[base.MessageResponse]{
+		Body: base.ApiResponse[*volumetypes.RestoreResult]{
 			Success: true,
-			Data:    base.MessageResponse{Message: "Restore initiated successfully"},
+			Data:    result,
 		},
 	}, nil
 }
@@ -1037,6 +1451,12 @@ func (h *VolumeHandler) RestoreBackupFiles(ctx context.Context, input *RestoreBa
 	}
 
 	if err := h.volumeService.RestoreBackupFiles(ctx, input.VolumeName, input.BackupID, input.Body.Paths, *user); err != nil {
+		if errors.Is(err, services.ErrSnapshotBackupUnsupported) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		if errors.Is(err, services.ErrOperationConflict) {
+			return nil, huma.Error409Conflict(err.Error())
+		}
 		return nil, huma.Error500InternalServerError(err.Error())
 	}
 
@@ -1059,6 +1479,12 @@ func (h *VolumeHandler) BackupHasPath(ctx context.Context, input *BackupHasPathI
 
 	exists, err := h.volumeService.BackupHasPath(ctx, input.BackupID, input.Path)
 	if err != nil {
+		if errors.Is(err, services.ErrSnapshotBackupUnsupported) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		if errors.Is(err, services.ErrNamespaceForbidden) {
+			return nil, huma.Error403Forbidden(err.Error())
+		}
 		return nil, huma.Error500InternalServerError(err.Error())
 	}
 
@@ -1077,6 +1503,12 @@ func (h *VolumeHandler) ListBackupFiles(ctx context.Context, input *ListBackupFi
 
 	files, err := h.volumeService.ListBackupFiles(ctx, input.BackupID)
 	if err != nil {
+		if errors.Is(err, services.ErrSnapshotBackupUnsupported) {
+			return nil, huma.Error400BadRequest(err.Error())
+		}
+		if errors.Is(err, services.ErrNamespaceForbidden) {
+			return nil, huma.Error403Forbidden(err.Error())
+		}
 		return nil, huma.Error500InternalServerError(err.Error())
 	}
 
@@ -1088,6 +1520,27 @@ func (h *VolumeHandler) ListBackupFiles(ctx context.Context, input *ListBackupFi
 	}, nil
 }
 
+// ScanAndAdoptBackups records orphan archives in the backup volume.
+func (h *VolumeHandler) ScanAndAdoptBackups(ctx context.Context, input *ScanBackupsInput) (*ScanBackupsOutput, error) {
+	if h.volumeService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+	result, err := h.volumeService.ScanAndAdoptBackups(ctx, *user)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+	return &ScanBackupsOutput{
+		Body: base.ApiResponse[volumetypes.BackupAdoptResult]{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
 func (h *VolumeHandler) DeleteBackup(ctx context.Context, input *DeleteBackupInput) (*DeleteBackupOutput, error) {
 	if h.volumeService == nil {
 		return nil, huma.Error500InternalServerError("service not available")
@@ -1095,6 +1548,9 @@ func (h *VolumeHandler) DeleteBackup(ctx context.Context, input *DeleteBackupInp
 	user, _ := humamw.GetCurrentUserFromContext(ctx)
 	err := h.volumeService.DeleteBackup(ctx, input.BackupID, user)
 	if err != nil {
+		if errors.Is(err, services.ErrNamespaceForbidden) {
+			return nil, huma.Error403Forbidden(err.Error())
+		}
 		return nil, huma.Error500InternalServerError(err.Error())
 	}
 	return &DeleteBackupOutput{
@@ -1105,21 +1561,16 @@ func (h *VolumeHandler) DeleteBackup(ctx context.Context, input *DeleteBackupInp
 	}, nil
 }
 
-func (h *VolumeHandler) DownloadBackup(ctx context.Context, input *DownloadBackupInput) (*DownloadBackupOutput, error) {
+func (h *VolumeHandler) DownloadBackup(ctx context.Context, input *DownloadBackupInput) (*huma.StreamResponse, error) {
 	if h.volumeService == nil {
 		return nil, huma.Error500InternalServerError("service not available")
 	}
 	user, _ := humamw.GetCurrentUserFromContext(ctx)
-	reader, size, err := h.volumeService.DownloadBackup(ctx, input.BackupID, user)
+	dl, err := h.volumeService.OpenBackupDownload(ctx, input.BackupID, input.Range, input.IfRange, user)
 	if err != nil {
-		return nil, huma.Error500InternalServerError(err.Error())
+		return nil, backupDownloadErrorInternal(err)
 	}
-	return &DownloadBackupOutput{
-		ContentType:        "application/x-gzip",
-		ContentDisposition: "attachment; filename=" + input.BackupID + ".tar.gz",
-		ContentLength:      size,
-		Body:               reader,
-	}, nil
+	return streamBackupDownloadInternal(input.BackupID, dl), nil
 }
 
 func (h *VolumeHandler) UploadAndRestore(ctx context.Context, input *UploadAndRestoreInput) (*UploadAndRestoreOutput, error) {
@@ -1131,14 +1582,34 @@ func (h *VolumeHandler) UploadAndRestore(ctx context.Context, input *UploadAndRe
 		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
 	}
 
-	err := h.volumeService.UploadAndRestore(ctx, input.VolumeName, input.File, input.File.Filename, *user)
+	result, err := h.volumeService.UploadAndRestore(ctx, input.VolumeName, input.File, input.File.Size, input.File.Filename, *user)
 	if err != nil {
-		return nil, huma.Error500InternalServerError(err.Error())
+		switch {
+		case errors.Is(err, services.ErrBackupUploadTooLarge):
+			return nil, huma.NewError(http.StatusRequestEntityTooLarge, err.Error())
+		case errors.Is(err, services.ErrInvalidBackupArchive):
+			return nil, huma.Error400BadRequest(err.Error())
+		default:
+			return nil, restoreErrorInternal(err)
+		}
 	}
 	return &UploadAndRestoreOutput{
-		Body: base.ApiResponse[base.MessageResponse]{
+		Body: base.ApiResponse[*volumetypes.RestoreResult]{
 			Success: true,
-			Data:    base.MessageResponse{Message: "Backup uploaded and restored successfully"},
+			Data:    result,
 		},
 	}, nil
 }
+
+// restoreErrorInternal maps a failed full restore to a response. A restore
+// that left the volume unchanged, or was refused because the volume is busy,
+// is a conflict the client can retry.
+func restoreErrorInternal(err error) error {
+	if errors.Is(err, services.ErrOperationConflict) {
+		return huma.Error409Conflict(err.Error())
+	}
+	if errors.Is(err, services.ErrRestoreVolumeUnchanged) && !errors.Is(err, services.ErrRestoreIncomplete) {
+		return huma.Error409Conflict(err.Error())
+	}
+	return huma.Error500InternalServerError(err.Error())
+}
@@ -2,7 +2,11 @@ package handlers
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
 	"net/http"
+	"strings"
 
 	"github.com/danielgtaylor/huma/v2"
 	"github.com/getarcaneapp/arcane/backend/internal/common"
@@ -37,6 +41,34 @@ type GetScanResultOutput struct {
 	Body base.ApiResponse[vulnerability.ScanResult]
 }
 
+type ExportScanSARIFInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ImageID       string `path:"imageId" doc:"Image ID"`
+}
+
+type ExportScanSARIFOutput struct {
+	ContentType        string `header:"Content-Type"`
+	ContentDisposition string `header:"Content-Disposition"`
+	Body               []byte
+}
+
+type GetImageLicensesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	ImageID       string `path:"imageId" doc:"Image ID"`
+}
+
+type GetImageLicensesOutput struct {
+	Body base.ApiResponse[vulnerability.ImageLicenseReport]
+}
+
+type GetEnvironmentLicensesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+type GetEnvironmentLicensesOutput struct {
+	Body base.ApiResponse[vulnerability.EnvironmentLicenseReport]
+}
+
 type GetScanSummaryInput struct {
 	EnvironmentID string `path:"id" doc:"Environment ID"`
 	ImageID       string `path:"imageId" doc:"Image ID"`
@@ -143,6 +175,45 @@ func RegisterVulnerability(api huma.API, vulnerabilityService *services.Vulnerab
 		},
 	}, h.GetScanResult)
 
+	huma.Register(api, huma.Operation{
+		OperationID: "export-image-vulnerabilities-sarif",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/images/{imageId}/vulnerabilities/sarif",
+		Summary:     "Export vulnerability scan as SARIF",
+		Description: "Exports the most recent vulnerability scan of an image as SARIF 2.1.0, e.g. for GitHub code scanning",
+		Tags:        []string{"Vulnerabilities"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.ExportScanSARIF)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-image-licenses",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/images/{imageId}/licenses",
+		Summary:     "Get image licenses",
+		Description: "Lists the package licenses found by the most recent scan of an image, flagging licenses on the denylist",
+		Tags:        []string{"Vulnerabilities"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.GetImageLicenses)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-environment-licenses",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/licenses",
+		Summary:     "Get environment licenses",
+		Description: "Lists the package licenses found in all scanned images, flagging licenses on the denylist",
+		Tags:        []string{"Vulnerabilities"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.GetEnvironmentLicenses)
+
 	huma.Register(api, huma.Operation{
 		OperationID: "get-image-vulnerability-summary",
 		Method:      http.MethodGet,
@@ -226,7 +297,7 @@ func RegisterVulnerability(api huma.API, vulnerabilityService *services.Vulnerab
 		Method:      http.MethodPost,
 		Path:        "/environments/{id}/vulnerabilities/ignore",
 		Summary:     "Ignore a vulnerability",
-		Description: "Creates an ignore record for a specific vulnerability",
+		Description: "Creates an ignore rule for one finding, a CVE in every image or a package in every image, optionally expiring after a number of days",
 		Tags:        []string{"Vulnerabilities"},
 		Security: []map[string][]string{
 			{"BearerAuth": {}},
@@ -259,6 +330,19 @@ func RegisterVulnerability(api huma.API, vulnerabilityService *services.Vulnerab
 			{"ApiKeyAuth": {}},
 		},
 	}, h.ListIgnoredVulnerabilities)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-vulnerability-ignore-audit",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/vulnerabilities/ignored/audit",
+		Summary:     "List vulnerability ignore audit",
+		Description: "Retrieves who created and removed ignore rules, and which rules expired",
+		Tags:        []string{"Vulnerabilities"},
+		Security: []map[string][]string{
+			{"BearerAuth": {}},
+			{"ApiKeyAuth": {}},
+		},
+	}, h.ListIgnoreAudit)
 }
 
 // ScanImage initiates a vulnerability scan for an image.
@@ -341,6 +425,81 @@ func (h *VulnerabilityHandler) GetScanResult(ctx context.Context, input *GetScan
 	}, nil
 }
 
+// ExportScanSARIF returns the vulnerability scan result for an image as a
+// SARIF file.
+func (h *VulnerabilityHandler) ExportScanSARIF(ctx context.Context, input *ExportScanSARIFInput) (*ExportScanSARIFOutput, error) {
+	if h.vulnerabilityService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	sarif, err := h.vulnerabilityService.ExportScanSARIF(ctx, input.ImageID)
+	if err != nil {
+		if errors.Is(err, services.ErrVulnerabilityScanIncomplete) {
+			return nil, huma.Error409Conflict(err.Error())
+		}
+		return nil, huma.Error500InternalServerError((&common.VulnerabilityScanRetrievalError{Err: err}).Error())
+	}
+	if sarif == nil {
+		return nil, huma.Error404NotFound((&common.VulnerabilityScanNotFoundError{}).Error())
+	}
+
+	body, err := json.MarshalIndent(sarif, "", "  ")
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	name := strings.TrimPrefix(input.ImageID, "sha256:")
+	if len(name) > 12 {
+		name = name[:12]
+	}
+	return &ExportScanSARIFOutput{
+		ContentType:        "application/sarif+json",
+		ContentDisposition: fmt.Sprintf("attachment; filename=%q", "vulnerabilities-"+name+".sarif"),
+		Body:               body,
+	}, nil
+}
+
+// GetImageLicenses returns the license report of an image.
+func (h *VulnerabilityHandler) GetImageLicenses(ctx context.Context, input *GetImageLicensesInput) (*GetImageLicensesOutput, error) {
+	if h.vulnerabilityService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	report, err := h.vulnerabilityService.GetImageLicenseReport(ctx, input.ImageID)
+	if err != nil {
+		return nil, huma.Error500InternalServerError((&common.VulnerabilityScanRetrievalError{Err: err}).Error())
+	}
+	if report == nil {
+		return nil, huma.Error404NotFound((&common.VulnerabilityScanNotFoundError{}).Error())
+	}
+
+	return &GetImageLicensesOutput{
+		Body: base.ApiResponse[vulnerability.ImageLicenseReport]{
+			Success: true,
+			Data:    *report,
+		},
+	}, nil
+}
+
+// GetEnvironmentLicenses returns the license report of all scanned images.
+func (h *VulnerabilityHandler) GetEnvironmentLicenses(ctx context.Context, input *GetEnvironmentLicensesInput) (*GetEnvironmentLicensesOutput, error) {
+	if h.vulnerabilityService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	report, err := h.vulnerabilityService.GetEnvironmentLicenseReport(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError((&common.VulnerabilityScanRetrievalError{Err: err}).Error())
+	}
+
+	return &GetEnvironmentLicensesOutput{
+		Body: base.ApiResponse[vulnerability.EnvironmentLicenseReport]{
+			Success: true,
+			Data:    *report,
+		},
+	}, nil
+}
+
 // GetScanSummary retrieves just the vulnerability summary for an image.
 func (h *VulnerabilityHandler) GetScanSummary(ctx context.Context, input *GetScanSummaryInput) (*GetScanSummaryOutput, error) {
 	if h.vulnerabilityService == nil {
@@ -509,10 +668,13 @@ func (h *VulnerabilityHandler) IgnoreVulnerability(ctx context.Context, input *I
 	payload := &input.Body
 	payload.CreatedBy = user.ID
 
-	ignore, err := h.vulnerabilityService.IgnoreVulnerability(ctx, input.EnvironmentID, payload)
+	ignore, err := h.vulnerabilityService.IgnoreVulnerability(ctx, input.EnvironmentID, payload, *user)
 	if err != nil {
-		if err.Error() == "vulnerability is already ignored" {
+		switch {
+		case errors.Is(err, services.ErrVulnerabilityAlreadyIgnored):
 			return nil, huma.Error409Conflict(err.Error())
+		case errors.Is(err, services.ErrInvalidIgnoreRule):
+			return nil, huma.Error400BadRequest(err.Error())
 		}
 		return nil, huma.Error500InternalServerError(err.Error())
 	}
@@ -523,6 +685,7 @@ func (h *VulnerabilityHandler) IgnoreVulnerability(ctx context.Context, input *I
 			Data: vulnerability.IgnoredVulnerability{
 				ID:               ignore.ID,
 				EnvironmentID:    ignore.EnvironmentID,
+				Scope:            vulnerability.IgnoreScope(ignore.Scope),
 				ImageID:          ignore.ImageID,
 				VulnerabilityID:  ignore.VulnerabilityID,
 				PkgName:          ignore.PkgName,
@@ -530,6 +693,7 @@ func (h *VulnerabilityHandler) IgnoreVulnerability(ctx context.Context, input *I
 				Reason:           ignore.Reason,
 				CreatedBy:        ignore.CreatedBy,
 				CreatedAt:        ignore.CreatedAt,
+				ExpiresAt:        ignore.ExpiresAt,
 			},
 		},
 	}, nil
@@ -550,8 +714,13 @@ func (h *VulnerabilityHandler) UnignoreVulnerability(ctx context.Context, input
 		return nil, huma.Error500InternalServerError("service not available")
 	}
 
-	if err := h.vulnerabilityService.UnignoreVulnerability(ctx, input.EnvironmentID, input.IgnoreID); err != nil {
-		if err.Error() == "ignore record not found" {
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	if err := h.vulnerabilityService.UnignoreVulnerability(ctx, input.EnvironmentID, input.IgnoreID, *user); err != nil {
+		if errors.Is(err, services.ErrIgnoreRecordNotFound) {
 			return nil, huma.Error404NotFound(err.Error())
 		}
 		return nil, huma.Error500InternalServerError(err.Error())
@@ -612,3 +781,45 @@ func (h *VulnerabilityHandler) ListIgnoredVulnerabilities(ctx context.Context, i
 		},
 	}, nil
 }
+
+type ListIgnoreAuditInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	Start         int    `query:"start" doc:"Start offset"`
+	Limit         int    `query:"limit" doc:"Limit"`
+	Page          int    `query:"page" doc:"Page number"`
+}
+
+type ListIgnoreAuditOutput struct {
+	Body base.Paginated[vulnerability.IgnoreAuditEntry]
+}
+
+// ListIgnoreAudit returns the audit of ignore rule changes, newest first.
+func (h *VulnerabilityHandler) ListIgnoreAudit(ctx context.Context, input *ListIgnoreAuditInput) (*ListIgnoreAuditOutput, error) {
+	if h.vulnerabilityService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	params := buildPaginationParams(input.Page, input.Start, input.Limit, "", "", "")
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+
+	items, paginationResp, err := h.vulnerabilityService.ListIgnoreAudit(ctx, input.EnvironmentID, params)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &ListIgnoreAuditOutput{
+		Body: base.Paginated[vulnerability.IgnoreAuditEntry]{
+			Success: true,
+			Data:    items,
+			Pagination: base.PaginationResponse{
+				TotalPages:      paginationResp.TotalPages,
+				TotalItems:      paginationResp.TotalItems,
+				CurrentPage:     paginationResp.CurrentPage,
+				ItemsPerPage:    paginationResp.ItemsPerPage,
+				GrandTotalItems: paginationResp.GrandTotalItems,
+			},
+		},
+	}, nil
+}
@@ -0,0 +1,355 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/common"
+	humamw "github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/types/base"
+	webhooktypes "github.com/getarcaneapp/arcane/types/webhook"
+)
+
+// WebhookHandler handles inbound webhook sources and their events.
+type WebhookHandler struct {
+	webhookService *services.WebhookService
+}
+
+type ListWebhookSourcesInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+}
+
+// WebhookSourcesResponse is a dedicated response type
+type WebhookSourcesResponse struct {
+	Success bool                  `json:"success"`
+	Data    []webhooktypes.Source `json:"data"`
+}
+
+type ListWebhookSourcesOutput struct {
+	Body WebhookSourcesResponse
+}
+
+type GetWebhookSourceInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	SourceID      string `path:"sourceId" doc:"Webhook source ID"`
+}
+
+// WebhookSourceResponse is a dedicated response type
+type WebhookSourceResponse struct {
+	Success bool                `json:"success"`
+	Data    webhooktypes.Source `json:"data"`
+}
+
+type GetWebhookSourceOutput struct {
+	Body WebhookSourceResponse
+}
+
+type CreateWebhookSourceInput struct {
+	EnvironmentID string               `path:"id" doc:"Environment ID"`
+	Body          webhooktypes.Request `doc:"Webhook source definition"`
+}
+
+// WebhookSourceWithTokenResponse is a dedicated response type
+type WebhookSourceWithTokenResponse struct {
+	Success bool                         `json:"success"`
+	Data    webhooktypes.SourceWithToken `json:"data"`
+}
+
+type CreateWebhookSourceOutput struct {
+	Body WebhookSourceWithTokenResponse
+}
+
+type UpdateWebhookSourceInput struct {
+	EnvironmentID string               `path:"id" doc:"Environment ID"`
+	SourceID      string               `path:"sourceId" doc:"Webhook source ID"`
+	Body          webhooktypes.Request `doc:"Webhook source definition"`
+}
+
+type UpdateWebhookSourceOutput struct {
+	Body WebhookSourceResponse
+}
+
+type RotateWebhookTokenInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	SourceID      string `path:"sourceId" doc:"Webhook source ID"`
+}
+
+type RotateWebhookTokenOutput struct {
+	Body WebhookSourceWithTokenResponse
+}
+
+type DeleteWebhookSourceInput struct {
+	EnvironmentID string `path:"id" doc:"Environment ID"`
+	SourceID      string `path:"sourceId" doc:"Webhook source ID"`
+}
+
+// DeleteWebhookSourceResponse is a dedicated response type
+type DeleteWebhookSourceResponse struct {
+	Success bool                 `json:"success"`
+	Data    base.MessageResponse `json:"data"`
+}
+
+type DeleteWebhookSourceOutput struct {
+	Body DeleteWebhookSourceResponse
+}
+
+type IngestWebhookInput struct {
+	Token       string `path:"token" doc:"Webhook source token"`
+	GitHubEvent string `header:"X-GitHub-Event" doc:"GitHub event name, sent by GitHub webhooks"`
+	RawBody     []byte
+}
+
+// IngestWebhookResponse is a dedicated response type
+type IngestWebhookResponse struct {
+	Success bool                      `json:"success"`
+	Data    webhooktypes.IngestResult `json:"data"`
+}
+
+type IngestWebhookOutput struct {
+	Body IngestWebhookResponse
+}
+
+// RegisterWebhooks registers webhook source management endpoints and the
+// public endpoint that receives their events.
+func RegisterWebhooks(api huma.API, webhookSvc *services.WebhookService) {
+	h := &WebhookHandler{webhookService: webhookSvc}
+
+	huma.Register(api, huma.Operation{
+		OperationID: "list-webhook-sources",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/webhooks",
+		Summary:     "List webhook sources",
+		Description: "List the external systems allowed to post events to Arcane",
+		Tags:        []string{"Webhooks"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.ListSources)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "create-webhook-source",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/webhooks",
+		Summary:     "Create webhook source",
+		Description: "Add a webhook source; the response holds its token, which is not shown again",
+		Tags:        []string{"Webhooks"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.CreateSource)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "get-webhook-source",
+		Method:      http.MethodGet,
+		Path:        "/environments/{id}/webhooks/{sourceId}",
+		Summary:     "Get webhook source",
+		Tags:        []string{"Webhooks"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.GetSource)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "update-webhook-source",
+		Method:      http.MethodPut,
+		Path:        "/environments/{id}/webhooks/{sourceId}",
+		Summary:     "Update webhook source",
+		Description: "Replace the definition of a webhook source; its token is kept",
+		Tags:        []string{"Webhooks"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.UpdateSource)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "rotate-webhook-token",
+		Method:      http.MethodPost,
+		Path:        "/environments/{id}/webhooks/{sourceId}/rotate",
+		Summary:     "Rotate webhook token",
+		Description: "Issue a new token for a webhook source; the old token stops working",
+		Tags:        []string{"Webhooks"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.RotateToken)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "delete-webhook-source",
+		Method:      http.MethodDelete,
+		Path:        "/environments/{id}/webhooks/{sourceId}",
+		Summary:     "Delete webhook source",
+		Tags:        []string{"Webhooks"},
+		Security:    []map[string][]string{{"BearerAuth": {}}, {"ApiKeyAuth": {}}},
+	}, h.DeleteSource)
+
+	huma.Register(api, huma.Operation{
+		OperationID: "ingest-webhook",
+		Method:      http.MethodPost,
+		Path:        "/webhooks/{token}",
+		Summary:     "Receive webhook event",
+		Description: "Receive an event from a webhook source. The token in the path authenticates the source, " +
+			"so this endpoint needs no other credentials.",
+		Tags: []string{"Webhooks"},
+	}, h.Ingest)
+}
+
+// ListSources returns webhook sources.
+func (h *WebhookHandler) ListSources(ctx context.Context, input *ListWebhookSourcesInput) (*ListWebhookSourcesOutput, error) {
+	if h.webhookService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	sources, err := h.webhookService.ListSources(ctx)
+	if err != nil {
+		return nil, huma.Error500InternalServerError(err.Error())
+	}
+
+	return &ListWebhookSourcesOutput{
+		Body: WebhookSourcesResponse{
+			Success: true,
+			Data:    sources,
+		},
+	}, nil
+}
+
+// GetSource returns a single webhook source.
+func (h *WebhookHandler) GetSource(ctx context.Context, input *GetWebhookSourceInput) (*GetWebhookSourceOutput, error) {
+	if h.webhookService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	source, err := h.webhookService.GetSource(ctx, input.SourceID)
+	if err != nil {
+		return nil, webhookErrorInternal(err)
+	}
+
+	return &GetWebhookSourceOutput{
+		Body: WebhookSourceResponse{
+			Success: true,
+			Data:    *source,
+		},
+	}, nil
+}
+
+// CreateSource adds a webhook source.
+func (h *WebhookHandler) CreateSource(ctx context.Context, input *CreateWebhookSourceInput) (*CreateWebhookSourceOutput, error) {
+	if h.webhookService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	source, err := h.webhookService.CreateSource(ctx, input.Body, *user)
+	if err != nil {
+		return nil, webhookErrorInternal(err)
+	}
+
+	return &CreateWebhookSourceOutput{
+		Body: WebhookSourceWithTokenResponse{
+			Success: true,
+			Data:    *source,
+		},
+	}, nil
+}
+
+// UpdateSource replaces a webhook source.
+func (h *WebhookHandler) UpdateSource(ctx context.Context, input *UpdateWebhookSourceInput) (*UpdateWebhookSourceOutput, error) {
+	if h.webhookService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	source, err := h.webhookService.UpdateSource(ctx, input.SourceID, input.Body, *user)
+	if err != nil {
+		return nil, webhookErrorInternal(err)
+	}
+
+	return &UpdateWebhookSourceOutput{
+		Body: WebhookSourceResponse{
+			Success: true,
+			Data:    *source,
+		},
+	}, nil
+}
+
+// RotateToken issues a new token for a webhook source.
+func (h *WebhookHandler) RotateToken(ctx context.Context, input *RotateWebhookTokenInput) (*RotateWebhookTokenOutput, error) {
+	if h.webhookService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	user, exists := humamw.GetCurrentUserFromContext(ctx)
+	if !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	source, err := h.webhookService.RotateToken(ctx, input.SourceID, *user)
+	if err != nil {
+		return nil, webhookErrorInternal(err)
+	}
+
+	return &RotateWebhookTokenOutput{
+		Body: WebhookSourceWithTokenResponse{
+			Success: true,
+			Data:    *source,
+		},
+	}, nil
+}
+
+// DeleteSource removes a webhook source.
+func (h *WebhookHandler) DeleteSource(ctx context.Context, input *DeleteWebhookSourceInput) (*DeleteWebhookSourceOutput, error) {
+	if h.webhookService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	if _, exists := humamw.GetCurrentUserFromContext(ctx); !exists {
+		return nil, huma.Error401Unauthorized((&common.NotAuthenticatedError{}).Error())
+	}
+
+	if err := h.webhookService.DeleteSource(ctx, input.SourceID); err != nil {
+		return nil, webhookErrorInternal(err)
+	}
+
+	return &DeleteWebhookSourceOutput{
+		Body: DeleteWebhookSourceResponse{
+			Success: true,
+			Data: base.MessageResponse{
+				Message: "Webhook source deleted successfully",
+			},
+		},
+	}, nil
+}
+
+// Ingest receives an event from a webhook source.
+func (h *WebhookHandler) Ingest(ctx context.Context, input *IngestWebhookInput) (*IngestWebhookOutput, error) {
+	if h.webhookService == nil {
+		return nil, huma.Error500InternalServerError("service not available")
+	}
+
+	result, err := h.webhookService.Ingest(ctx, input.Token, input.GitHubEvent, input.RawBody)
+	if err != nil {
+		return nil, webhookErrorInternal(err)
+	}
+
+	return &IngestWebhookOutput{
+		Body: IngestWebhookResponse{
+			Success: true,
+			Data:    *result,
+		},
+	}, nil
+}
+
+// webhookErrorInternal maps webhook errors to HTTP errors. It returns a 500
+// for anything else.
+func webhookErrorInternal(err error) error {
+	switch {
+	case errors.Is(err, services.ErrWebhookSourceNotFound):
+		return huma.Error404NotFound(err.Error())
+	case errors.Is(err, services.ErrInvalidWebhookToken):
+		return huma.Error401Unauthorized(err.Error())
+	case errors.Is(err, services.ErrInvalidWebhookSource), errors.Is(err, services.ErrInvalidWebhookPayload):
+		return huma.Error400BadRequest(err.Error())
+	default:
+		return huma.Error500InternalServerError(err.Error())
+	}
+}
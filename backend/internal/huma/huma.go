@@ -10,6 +10,7 @@ import (
 	"github.com/getarcaneapp/arcane/backend/internal/huma/handlers"
 	"github.com/getarcaneapp/arcane/backend/internal/huma/middleware"
 	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/chaos"
 	"github.com/gin-gonic/gin"
 )
 
@@ -120,6 +121,7 @@ type Services struct {
 	ApiKey            *services.ApiKeyService
 	AppImages         *services.ApplicationImagesService
 	Font              *services.FontService
+	IconProxy         *services.IconProxyService
 	Project           *services.ProjectService
 	Event             *services.EventService
 	Version           *services.VersionService
@@ -134,16 +136,34 @@ type Services struct {
 	ImageUpdate       *services.ImageUpdateService
 	Volume            *services.VolumeService
 	Container         *services.ContainerService
+	ContainerDigest   *services.ContainerDigestService
 	Network           *services.NetworkService
 	Notification      *services.NotificationService
 	Apprise           *services.AppriseService //nolint:staticcheck // Apprise still functional, deprecated in favor of Shoutrrr
 	Updater           *services.UpdaterService
+	UpdateRollout     *services.UpdateRolloutService
+	Chaos             *chaos.Injector
 	CustomizeSearch   *services.CustomizeSearchService
 	System            *services.SystemService
 	SystemUpgrade     *services.SystemUpgradeService
 	GitRepository     *services.GitRepositoryService
 	GitOpsSync        *services.GitOpsSyncService
 	Vulnerability     *services.VulnerabilityService
+	FeatureFlag       *services.FeatureFlagService
+	EnvironmentPolicy *services.EnvironmentPolicyService
+	Approval          *services.ApprovalService
+	VolumeTransfer    *services.VolumeTransferService
+	BackupDownload    *services.BackupDownloadService
+	Namespace         *services.NamespaceService
+	Healthcheck       *services.ContainerHealthcheckService
+	HealthHistory     *services.ContainerHealthHistoryService
+	Monitor           *services.EndpointMonitorService
+	Webhook           *services.WebhookService
+	StatsAggregator   *services.StatsAggregatorService
+	ProjectHook       *services.ProjectHookService
+	ProjectWatch      *services.ProjectWatchService
+	Operation         *services.OperationService
+	Power             *services.PowerService
 	Config            *config.Config
 }
 
@@ -191,6 +211,7 @@ func SetupAPI(router *gin.Engine, apiGroup *gin.RouterGroup, cfg *config.Config,
 
 	// Add authentication middleware
 	api.UseMiddleware(middleware.NewAuthBridge(api, svc.Auth, svc.ApiKey, cfg))
+	api.UseMiddleware(middleware.NewNamespaceScope(api, svc.Namespace))
 
 	// Register all Huma handlers
 	registerHandlers(api, svc)
@@ -279,6 +300,7 @@ func registerHandlers(api huma.API, svc *Services) {
 	var apiKeySvc *services.ApiKeyService
 	var appImagesSvc *services.ApplicationImagesService
 	var fontSvc *services.FontService
+	var iconProxySvc *services.IconProxyService
 	var projectSvc *services.ProjectService
 	var eventSvc *services.EventService
 	var versionSvc *services.VersionService
@@ -293,16 +315,34 @@ func registerHandlers(api huma.API, svc *Services) {
 	var imageUpdateSvc *services.ImageUpdateService
 	var volumeSvc *services.VolumeService
 	var containerSvc *services.ContainerService
+	var containerDigestSvc *services.ContainerDigestService
 	var networkSvc *services.NetworkService
 	var notificationSvc *services.NotificationService
 	var appriseSvc *services.AppriseService //nolint:staticcheck // Apprise still functional, deprecated in favor of Shoutrrr
 	var updaterSvc *services.UpdaterService
+	var updateRolloutSvc *services.UpdateRolloutService
+	var chaosInjector *chaos.Injector
 	var customizeSearchSvc *services.CustomizeSearchService
 	var systemSvc *services.SystemService
 	var systemUpgradeSvc *services.SystemUpgradeService
 	var gitRepositorySvc *services.GitRepositoryService
 	var gitOpsSyncSvc *services.GitOpsSyncService
 	var vulnerabilitySvc *services.VulnerabilityService
+	var featureFlagSvc *services.FeatureFlagService
+	var environmentPolicySvc *services.EnvironmentPolicyService
+	var approvalSvc *services.ApprovalService
+	var volumeTransferSvc *services.VolumeTransferService
+	var backupDownloadSvc *services.BackupDownloadService
+	var namespaceSvc *services.NamespaceService
+	var healthcheckSvc *services.ContainerHealthcheckService
+	var healthHistorySvc *services.ContainerHealthHistoryService
+	var monitorSvc *services.EndpointMonitorService
+	var webhookSvc *services.WebhookService
+	var statsAggregatorSvc *services.StatsAggregatorService
+	var projectHookSvc *services.ProjectHookService
+	var projectWatchSvc *services.ProjectWatchService
+	var operationSvc *services.OperationService
+	var powerSvc *services.PowerService
 	var cfg *config.Config
 
 	if svc != nil {
@@ -312,6 +352,7 @@ func registerHandlers(api huma.API, svc *Services) {
 		apiKeySvc = svc.ApiKey
 		appImagesSvc = svc.AppImages
 		fontSvc = svc.Font
+		iconProxySvc = svc.IconProxy
 		projectSvc = svc.Project
 		eventSvc = svc.Event
 		versionSvc = svc.Version
@@ -326,16 +367,34 @@ func registerHandlers(api huma.API, svc *Services) {
 		imageUpdateSvc = svc.ImageUpdate
 		volumeSvc = svc.Volume
 		containerSvc = svc.Container
+		containerDigestSvc = svc.ContainerDigest
 		networkSvc = svc.Network
 		notificationSvc = svc.Notification
 		appriseSvc = svc.Apprise
 		updaterSvc = svc.Updater
+		updateRolloutSvc = svc.UpdateRollout
+		chaosInjector = svc.Chaos
 		customizeSearchSvc = svc.CustomizeSearch
 		systemSvc = svc.System
 		systemUpgradeSvc = svc.SystemUpgrade
 		gitRepositorySvc = svc.GitRepository
 		gitOpsSyncSvc = svc.GitOpsSync
 		vulnerabilitySvc = svc.Vulnerability
+		featureFlagSvc = svc.FeatureFlag
+		environmentPolicySvc = svc.EnvironmentPolicy
+		approvalSvc = svc.Approval
+		volumeTransferSvc = svc.VolumeTransfer
+		backupDownloadSvc = svc.BackupDownload
+		namespaceSvc = svc.Namespace
+		healthcheckSvc = svc.Healthcheck
+		healthHistorySvc = svc.HealthHistory
+		monitorSvc = svc.Monitor
+		webhookSvc = svc.Webhook
+		statsAggregatorSvc = svc.StatsAggregator
+		projectHookSvc = svc.ProjectHook
+		projectWatchSvc = svc.ProjectWatch
+		operationSvc = svc.Operation
+		powerSvc = svc.Power
 		cfg = svc.Config
 	}
 	handlers.RegisterHealth(api)
@@ -343,6 +402,7 @@ func registerHandlers(api huma.API, svc *Services) {
 	handlers.RegisterApiKeys(api, apiKeySvc)
 	handlers.RegisterAppImages(api, appImagesSvc)
 	handlers.RegisterFonts(api, fontSvc)
+	handlers.RegisterIcons(api, iconProxySvc)
 	handlers.RegisterProjects(api, projectSvc)
 	handlers.RegisterUsers(api, userSvc)
 	handlers.RegisterVersion(api, versionSvc)
@@ -353,16 +413,34 @@ func registerHandlers(api huma.API, svc *Services) {
 	handlers.RegisterTemplates(api, templateSvc)
 	handlers.RegisterImages(api, dockerSvc, imageSvc, imageUpdateSvc, settingsSvc)
 	handlers.RegisterImageUpdates(api, imageUpdateSvc)
-	handlers.RegisterSettings(api, settingsSvc, settingsSearchSvc, environmentSvc, cfg)
+	handlers.RegisterSettings(api, settingsSvc, settingsSearchSvc, environmentSvc, volumeSvc, cfg)
 	handlers.RegisterJobSchedules(api, jobScheduleSvc, environmentSvc)
 	handlers.RegisterVolumes(api, dockerSvc, volumeSvc)
 	handlers.RegisterContainers(api, containerSvc, dockerSvc)
 	handlers.RegisterNetworks(api, networkSvc, dockerSvc)
 	handlers.RegisterNotifications(api, notificationSvc, appriseSvc)
 	handlers.RegisterUpdater(api, updaterSvc)
+	handlers.RegisterUpdateRollouts(api, updateRolloutSvc)
+	handlers.RegisterChaos(api, chaosInjector)
 	handlers.RegisterCustomize(api, customizeSearchSvc)
-	handlers.RegisterSystem(api, dockerSvc, systemSvc, systemUpgradeSvc, cfg)
+	handlers.RegisterSystem(api, dockerSvc, systemSvc, systemUpgradeSvc, operationSvc, cfg)
+	handlers.RegisterPower(api, powerSvc)
 	handlers.RegisterGitRepositories(api, gitRepositorySvc)
 	handlers.RegisterGitOpsSyncs(api, gitOpsSyncSvc)
 	handlers.RegisterVulnerability(api, vulnerabilitySvc)
+	handlers.RegisterFeatureFlags(api, featureFlagSvc)
+	handlers.RegisterEnvironmentPolicies(api, environmentPolicySvc, environmentSvc, apiKeySvc)
+	handlers.RegisterApprovals(api, approvalSvc)
+	handlers.RegisterVolumeTransfers(api, volumeTransferSvc)
+	handlers.RegisterBackupDownloads(api, backupDownloadSvc)
+	handlers.RegisterNamespaces(api, namespaceSvc)
+	handlers.RegisterContainerHealthchecks(api, healthcheckSvc, healthHistorySvc)
+	handlers.RegisterContainerDigests(api, containerDigestSvc)
+	handlers.RegisterContainerRollbacks(api, updaterSvc)
+	handlers.RegisterContainerCheckpoints(api, containerSvc)
+	handlers.RegisterMonitors(api, monitorSvc)
+	handlers.RegisterWebhooks(api, webhookSvc)
+	handlers.RegisterContainerStats(api, statsAggregatorSvc)
+	handlers.RegisterProjectHooks(api, projectHookSvc)
+	handlers.RegisterProjectWatch(api, projectWatchSvc)
 }
@@ -0,0 +1,16 @@
+package huma
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Registering every handler panics on duplicate schema names, so this guards
+// against response types that collide in the OpenAPI spec.
+func TestSetupAPIForSpec(t *testing.T) {
+	require.NotPanics(t, func() {
+		api := SetupAPIForSpec()
+		require.NotNil(t, api.OpenAPI())
+	})
+}
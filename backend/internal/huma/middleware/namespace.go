@@ -0,0 +1,67 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/danielgtaylor/huma/v2"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+)
+
+// namespaceResourceParams maps the path parameters that address a single
+// namespaced resource to its kind.
+var namespaceResourceParams = []struct {
+	param string
+	kind  string
+}{
+	{"containerId", services.NamespaceResourceContainer},
+	{"volumeName", services.NamespaceResourceVolume},
+	{"projectId", services.NamespaceResourceProject},
+}
+
+// NewNamespaceScope creates a Huma middleware that limits what the
+// authenticated user sees to the namespaces they belong to. It must run after
+// NewAuthBridge. Admins, agents and installs without namespaces are not
+// restricted. Operations on a single container, volume or project are refused
+// unless the resource belongs to one of the user's namespaces.
+func NewNamespaceScope(api huma.API, namespaceService *services.NamespaceService) func(ctx huma.Context, next func(huma.Context)) {
+	return func(ctx huma.Context, next func(huma.Context)) {
+		user, ok := GetCurrentUserFromContext(ctx.Context())
+		if namespaceService == nil || !ok || user == nil {
+			next(ctx)
+			return
+		}
+
+		scope, err := namespaceService.ScopeForUser(ctx.Context(), user.ID, IsAdminFromContext(ctx.Context()))
+		if err != nil {
+			slog.ErrorContext(ctx.Context(), "Failed to resolve namespace scope", "user", user.ID, "error", err)
+			_ = huma.WriteErr(api, ctx, http.StatusInternalServerError, "failed to resolve namespace access")
+			return
+		}
+		if scope == nil {
+			next(ctx)
+			return
+		}
+
+		ctx = huma.WithContext(ctx, services.WithNamespaceScope(ctx.Context(), scope))
+		if op := ctx.Operation(); op != nil {
+			for _, rp := range namespaceResourceParams {
+				if !strings.Contains(op.Path, "{"+rp.param+"}") {
+					continue
+				}
+				if err := namespaceService.AuthorizeResource(ctx.Context(), rp.kind, ctx.Param(rp.param)); err != nil {
+					if errors.Is(err, services.ErrNamespaceForbidden) {
+						_ = huma.WriteErr(api, ctx, http.StatusForbidden, err.Error())
+						return
+					}
+					slog.ErrorContext(ctx.Context(), "Failed to check namespace access", "user", user.ID, "error", err)
+					_ = huma.WriteErr(api, ctx, http.StatusInternalServerError, "failed to resolve namespace access")
+					return
+				}
+			}
+		}
+		next(ctx)
+	}
+}
@@ -0,0 +1,146 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/types/approval"
+	"github.com/gin-gonic/gin"
+)
+
+// UserResolver returns the authenticated user for a request, or nil.
+type UserResolver func(ctx context.Context, c *gin.Context) *models.User
+
+// maxApprovalBodyBytes caps the body of a gated request. Gated actions take
+// small JSON option bodies, which are stored for approvers to review.
+const maxApprovalBodyBytes = 1 << 20
+
+// NewApprovalMiddleware holds back destructive requests on environments that
+// require approval. Without an approval ID header it records a pending
+// approval request and answers 428; with an approved ID for the same
+// operation, including the same query and body, it consumes the approval and
+// lets the request through. Like the
+// feature flag middleware it runs before the environment proxy.
+func NewApprovalMiddleware(approvals *services.ApprovalService, resolveUser UserResolver) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		envID, action := approvalActionForRequest(c.Request.Method, c.Request.URL.Path)
+		if action == "" || approvals == nil || !approvals.RequiresApproval(c.Request.Context(), envID) {
+			c.Next()
+			return
+		}
+
+		user := resolveUser(c.Request.Context(), c)
+		if user == nil {
+			c.JSON(http.StatusUnauthorized, gin.H{
+				"success": false,
+				"data":    gin.H{"error": "Authentication required"},
+			})
+			c.Abort()
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(c.Request.Body, maxApprovalBodyBytes+1))
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"data":    gin.H{"error": "Failed to read request body"},
+			})
+			c.Abort()
+			return
+		}
+		if len(body) > maxApprovalBodyBytes {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{
+				"success": false,
+				"data":    gin.H{"error": "Request body is too large for an approval request"},
+			})
+			c.Abort()
+			return
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		op := services.ApprovalOperation{
+			Method: c.Request.Method,
+			Path:   c.Request.URL.Path,
+			Query:  c.Request.URL.RawQuery,
+			Body:   body,
+		}
+		if approvalID := c.GetHeader(approval.HeaderApprovalID); approvalID != "" {
+			if err := approvals.Consume(c.Request.Context(), approvalID, envID, op, *user); err != nil {
+				status := http.StatusForbidden
+				if errors.Is(err, services.ErrApprovalNotFound) {
+					status = http.StatusNotFound
+				}
+				c.JSON(status, gin.H{
+					"success": false,
+					"data":    gin.H{"error": err.Error()},
+				})
+				c.Abort()
+				return
+			}
+			c.Next()
+			return
+		}
+
+		req, err := approvals.CreateRequest(c.Request.Context(), action, envID, op, *user)
+		if err != nil {
+			slog.ErrorContext(c.Request.Context(), "failed to create approval request", "action", action, "environment", envID, "error", err)
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"success": false,
+				"data":    gin.H{"error": "Failed to create approval request"},
+			})
+			c.Abort()
+			return
+		}
+
+		c.JSON(http.StatusPreconditionRequired, gin.H{
+			"success": false,
+			"data": gin.H{
+				"error":         "This action requires approval by another admin",
+				"approvalId":    req.ID,
+				"approvalState": req.Status,
+				"expiresAt":     req.ExpiresAt,
+			},
+		})
+		c.Abort()
+	}
+}
+
+// approvalActionForRequest returns the environment ID and the approval action
+// for a request, or an empty action if the request is not gated.
+//
+//	POST /api/environments/{id}/.../prune                                  -> prune
+//	POST /api/environments/{id}/volumes/{name}/backups/{bid}/restore       -> volume_restore
+//	POST /api/environments/{id}/volumes/{name}/backups/{bid}/restore-files -> volume_restore
+//	POST /api/environments/{id}/system/power/reboot                        -> host_reboot
+func approvalActionForRequest(method, requestPath string) (string, string) {
+	if method != http.MethodPost {
+		return "", ""
+	}
+	rest, ok := strings.CutPrefix(requestPath, apiEnvironmentsPrefix)
+	if !ok {
+		return "", ""
+	}
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" {
+		return "", ""
+	}
+	envID, segments := parts[0], parts[1:]
+
+	switch {
+	case segments[len(segments)-1] == "prune":
+		return envID, models.ApprovalActionPrune
+	case len(segments) == 5 && segments[0] == "volumes" && segments[2] == "backups" &&
+		(segments[4] == "restore" || segments[4] == "restore-files"):
+		return envID, models.ApprovalActionVolumeRestore
+	case len(segments) == 3 && segments[0] == "system" && segments[1] == "power" && segments[2] == "reboot":
+		return envID, models.ApprovalActionHostReboot
+	}
+	return envID, ""
+}
@@ -0,0 +1,33 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApprovalActionForRequest(t *testing.T) {
+	tests := []struct {
+		method string
+		path   string
+		action string
+	}{
+		{http.MethodPost, "/api/environments/0/system/prune", "prune"},
+		{http.MethodPost, "/api/environments/0/images/prune", "prune"},
+		{http.MethodPost, "/api/environments/1/volumes/data/backups/b1/restore", "volume_restore"},
+		{http.MethodPost, "/api/environments/1/volumes/data/backups/b1/restore-files", "volume_restore"},
+		{http.MethodPost, "/api/environments/2/system/power/reboot", "host_reboot"},
+		{http.MethodGet, "/api/environments/0/system/prune", ""},
+		{http.MethodPost, "/api/environments/2/system/power/wake", ""},
+		{http.MethodPost, "/api/environments/0/volumes/data/backups", ""},
+		{http.MethodPost, "/api/settings", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			_, action := approvalActionForRequest(tt.method, tt.path)
+			assert.Equal(t, tt.action, action)
+		})
+	}
+}
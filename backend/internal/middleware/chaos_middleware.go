@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/getarcaneapp/arcane/backend/internal/utils/chaos"
+	"github.com/gin-gonic/gin"
+)
+
+// NewChaosMiddleware delays and fails API requests according to the
+// injector's API rule, so the UI and the manager's calls to agents can be
+// tested against a slow or flaky backend. The chaos and health endpoints are
+// left alone so chaos mode can always be turned off again. A nil injector
+// disables the middleware.
+func NewChaosMiddleware(injector *chaos.Injector) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if injector == nil || isChaosExemptPath(c.Request.URL.Path) {
+			c.Next()
+			return
+		}
+
+		fail, err := injector.Inject(c.Request.Context(), chaos.TargetAPI)
+		if err != nil {
+			c.Abort()
+			return
+		}
+		if !fail {
+			c.Next()
+			return
+		}
+
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"success": false,
+			"data":    gin.H{"error": chaos.ErrInjected.Error()},
+		})
+		c.Abort()
+	}
+}
+
+func isChaosExemptPath(path string) bool {
+	return strings.HasSuffix(path, "/chaos") || strings.HasSuffix(path, "/health")
+}
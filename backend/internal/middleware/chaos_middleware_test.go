@@ -0,0 +1,48 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/getarcaneapp/arcane/backend/internal/utils/chaos"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChaosMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	injector := chaos.New(chaos.Config{API: chaos.Rule{FailureRate: 100}})
+	router := gin.New()
+	router.Use(NewChaosMiddleware(injector))
+	router.GET("/api/volumes", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.GET("/api/environments/0/system/debug/chaos", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/volumes", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Contains(t, w.Body.String(), chaos.ErrInjected.Error())
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/environments/0/system/debug/chaos", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	require.NoError(t, injector.SetConfig(chaos.Config{}))
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/volumes", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
+
+func TestChaosMiddleware_NilInjector(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(NewChaosMiddleware(nil))
+	router.GET("/api/volumes", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/volumes", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
@@ -37,6 +37,7 @@ func (m *CORSMiddleware) Add() gin.HandlerFunc {
 		"Origin",
 		"Referer",
 		"X-Arcane-Agent-Token",
+		"X-Arcane-Timeout",
 	}
 	conf.ExposeHeaders = []string{
 		"Content-Length",
@@ -44,6 +45,7 @@ func (m *CORSMiddleware) Add() gin.HandlerFunc {
 		"X-Total-Count",
 		"X-Page",
 		"X-Per-Page",
+		"X-Arcane-Timeout",
 	}
 	conf.MaxAge = 300
 
@@ -0,0 +1,40 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// drainRetryAfterSeconds is the Retry-After hint sent while the server drains.
+const drainRetryAfterSeconds = "30"
+
+// DrainChecker reports whether the server is draining before it exits.
+type DrainChecker func() bool
+
+// NewDrainMiddleware rejects mutating requests once shutdown has begun, so no
+// new backups, restores or deploys start while in-flight ones are finishing.
+// Reads keep working until the server stops.
+func NewDrainMiddleware(isDraining DrainChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if isDraining == nil || !isMutatingMethod(c.Request.Method) || !isDraining() {
+			c.Next()
+			return
+		}
+
+		c.Header("Retry-After", drainRetryAfterSeconds)
+		c.JSON(http.StatusServiceUnavailable, gin.H{
+			"success": false,
+			"data":    gin.H{"error": "Arcane is shutting down; try again after it restarts"},
+		})
+		c.Abort()
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case http.MethodPost, http.MethodPut, http.MethodPatch, http.MethodDelete:
+		return true
+	}
+	return false
+}
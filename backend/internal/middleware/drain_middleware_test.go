@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrainMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	draining := false
+	router := gin.New()
+	router.Use(NewDrainMiddleware(func() bool { return draining }))
+	router.GET("/api/volumes", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/api/volumes", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/volumes", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	draining = true
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/volumes", nil))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, drainRetryAfterSeconds, w.Header().Get("Retry-After"))
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/volumes", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+}
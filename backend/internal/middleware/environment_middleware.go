@@ -32,6 +32,9 @@ const (
 	managementEndpointSettings       = "/settings"
 	managementEndpointJobSchedules   = "/job-schedules"
 	managementEndpointJobs           = "/jobs"
+	managementEndpointFeatures       = "/features"
+	managementEndpointPolicy         = "/policy"
+	managementEndpointWake           = "/wake"
 
 	errEnvironmentNotFound      = "Environment not found"
 	errEnvironmentDisabled      = "Environment is disabled"
@@ -42,6 +45,10 @@ const (
 	// proxyTimeout is intentionally generous because some proxied operations
 	// (e.g., image pulls with progress streaming) can take multiple minutes.
 	proxyTimeout = 30 * time.Minute
+
+	// maxLoggedProxyBodySize caps the request bodies buffered for debug logging;
+	// larger bodies are streamed to the remote environment.
+	maxLoggedProxyBodySize = 64 * 1024
 )
 
 // EnvResolver resolves an environment ID to its connection details.
@@ -194,6 +201,9 @@ func (m *EnvironmentMiddleware) hasResourcePath(c *gin.Context, envID string) bo
 		managementEndpointSettings,
 		managementEndpointJobSchedules,
 		managementEndpointJobs,
+		managementEndpointFeatures,
+		managementEndpointPolicy,
+		managementEndpointWake,
 	}
 
 	for _, endpoint := range managementEndpoints {
@@ -310,21 +320,27 @@ func (m *EnvironmentMiddleware) proxyHTTP(c *gin.Context, target string, accessT
 
 // createProxyRequest builds the HTTP request to forward to the remote environment.
 func (m *EnvironmentMiddleware) createProxyRequest(c *gin.Context, target string, accessToken *string) (*http.Request, error) {
-	// Read the body to log it and then restore it for forwarding
+	// Small bodies are read so they can be logged; anything larger or of
+	// unknown length (e.g. file uploads) is streamed through untouched.
+	var body io.Reader = http.NoBody
 	var bodyBytes []byte
-	var err error
-	if c.Request.Body != nil {
-		bodyBytes, err = io.ReadAll(c.Request.Body)
-		if err != nil {
-			return nil, fmt.Errorf("failed to read request body: %w", err)
+	if c.Request.Body != nil && c.Request.Body != http.NoBody {
+		body = c.Request.Body
+		if c.Request.ContentLength >= 0 && c.Request.ContentLength <= maxLoggedProxyBodySize {
+			var err error
+			bodyBytes, err = io.ReadAll(c.Request.Body)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read request body: %w", err)
+			}
+			// Restore the body for forwarding
+			c.Request.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+			body = bytes.NewReader(bodyBytes)
 		}
-		// Restore the body for forwarding
-		c.Request.Body = io.NopCloser(bytes.NewBuffer(bodyBytes))
 	}
 
 	slog.DebugContext(c.Request.Context(), "Creating proxy request", "method", c.Request.Method, "target", target, "contentLength", c.Request.ContentLength, "contentType", c.GetHeader("Content-Type"), "bodyLength", len(bodyBytes), "body", string(bodyBytes))
 
-	req, err := http.NewRequestWithContext(c.Request.Context(), c.Request.Method, target, bytes.NewBuffer(bodyBytes))
+	req, err := http.NewRequestWithContext(c.Request.Context(), c.Request.Method, target, body)
 	if err != nil {
 		return nil, err
 	}
@@ -335,9 +351,12 @@ func (m *EnvironmentMiddleware) createProxyRequest(c *gin.Context, target string
 	remenv.SetAgentToken(req, accessToken)
 	remenv.SetForwardedHeaders(req, c.ClientIP(), c.Request.Host)
 
-	// Set Content-Length based on actual body size
+	// Set Content-Length based on the incoming request; streamed bodies keep
+	// the client's declared length (or -1 for chunked transfers).
 	if len(bodyBytes) > 0 {
 		req.ContentLength = int64(len(bodyBytes))
+	} else if body != http.NoBody {
+		req.ContentLength = c.Request.ContentLength
 	}
 
 	return req, nil
@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/getarcaneapp/arcane/types/environment"
+	volumetypes "github.com/getarcaneapp/arcane/types/volume"
+	"github.com/gin-gonic/gin"
+)
+
+// featureVolumeBulk marks the bulk volume endpoint, whose feature depends on
+// the action in the request body.
+const featureVolumeBulk = "volumeBulk"
+
+// maxFeatureBodyBytes caps how much of a body is read to find its action.
+const maxFeatureBodyBytes = 1 << 20
+
+// FeatureChecker reports whether a feature is enabled for an environment.
+type FeatureChecker func(ctx context.Context, environmentID, feature string) bool
+
+// NewFeatureFlagMiddleware rejects requests for features that are disabled for
+// the target environment. It must run before the environment proxy so remote
+// environments are covered as well.
+func NewFeatureFlagMiddleware(checker FeatureChecker) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		envID, feature := featureForPath(c.Request.URL.Path)
+		if feature == featureVolumeBulk {
+			feature = volumeBulkFeatureInternal(c)
+		}
+		if feature == "" || checker == nil || checker(c.Request.Context(), envID, feature) {
+			c.Next()
+			return
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{
+			"success": false,
+			"data":    gin.H{"error": fmt.Sprintf("Feature %q is disabled for this environment", feature)},
+		})
+		c.Abort()
+	}
+}
+
+// featureForPath returns the environment ID and the feature guarding the
+// request path, or an empty feature if the path is not feature-gated.
+//
+//	/api/environments/{id}/volumes/{name}/browse...        -> volumeBrowser
+//	/api/environments/{id}/ws/containers/{cid}/terminal    -> containerExec
+//	/api/environments/{id}/containers/{cid}/exec/...       -> containerExec
+//	/api/environments/{id}/.../prune                       -> prune
+//	/api/environments/{id}/volumes/bulk                    -> prune for deletes
+//	/api/environments/{id}/containers/{cid}/checkpoints... -> containerCheckpoint
+func featureForPath(requestPath string) (string, string) {
+	rest, ok := strings.CutPrefix(requestPath, apiEnvironmentsPrefix)
+	if !ok {
+		return "", ""
+	}
+	parts := strings.Split(strings.Trim(rest, "/"), "/")
+	if len(parts) < 2 || parts[0] == "" {
+		return "", ""
+	}
+	envID, segments := parts[0], parts[1:]
+
+	switch {
+	case len(segments) >= 3 && segments[0] == "volumes" && segments[2] == "browse":
+		return envID, environment.FeatureVolumeBrowser
+	case len(segments) == 4 && segments[0] == "ws" && segments[1] == "containers" && segments[3] == "terminal":
+		return envID, environment.FeatureContainerExec
+	case len(segments) >= 3 && segments[0] == "containers" && segments[2] == "exec":
+		return envID, environment.FeatureContainerExec
+	case len(segments) == 2 && segments[0] == "volumes" && segments[1] == "bulk":
+		return envID, featureVolumeBulk
+	case len(segments) >= 3 && segments[0] == "containers" && segments[2] == "checkpoints":
+		return envID, environment.FeatureContainerCheckpoint
+	case segments[len(segments)-1] == "prune":
+		return envID, environment.FeaturePrune
+	}
+	return envID, ""
+}
+
+// volumeBulkFeatureInternal gates bulk volume deletes like a prune. The body
+// is put back for the handler; a body whose action cannot be read is treated
+// as a delete.
+func volumeBulkFeatureInternal(c *gin.Context) string {
+	if c.Request.Method != http.MethodPost || c.Request.Body == nil {
+		return ""
+	}
+
+	peek, err := io.ReadAll(io.LimitReader(c.Request.Body, maxFeatureBodyBytes))
+	c.Request.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(peek), c.Request.Body), c.Request.Body}
+	if err != nil {
+		return environment.FeaturePrune
+	}
+
+	var req volumetypes.BulkRequest
+	if err := json.Unmarshal(peek, &req); err != nil || req.Action == volumetypes.BulkActionDelete {
+		return environment.FeaturePrune
+	}
+	return ""
+}
@@ -0,0 +1,80 @@
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/getarcaneapp/arcane/types/environment"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFeatureForPath(t *testing.T) {
+	tests := []struct {
+		path    string
+		envID   string
+		feature string
+	}{
+		{"/api/environments/0/volumes/data/browse", "0", environment.FeatureVolumeBrowser},
+		{"/api/environments/0/volumes/data/browse/content", "0", environment.FeatureVolumeBrowser},
+		{"/api/environments/abc/ws/containers/c1/terminal", "abc", environment.FeatureContainerExec},
+		{"/api/environments/0/images/prune", "0", environment.FeaturePrune},
+		{"/api/environments/0/system/prune", "0", environment.FeaturePrune},
+		{"/api/environments/0/containers/c1/exec/e1/resize", "0", environment.FeatureContainerExec},
+		{"/api/environments/0/volumes/bulk", "0", featureVolumeBulk},
+		{"/api/environments/0/containers/c1/checkpoints", "0", environment.FeatureContainerCheckpoint},
+		{"/api/environments/0/containers/c1/checkpoints/cp1/restore", "0", environment.FeatureContainerCheckpoint},
+		{"/api/environments/0/volumes/data", "0", ""},
+		{"/api/environments/0/volumes/data/backups", "0", ""},
+		{"/api/environments/0/ws/containers/c1/logs", "0", ""},
+		{"/api/environments/0/containers/checkpoint-support", "0", ""},
+		{"/api/settings", "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			envID, feature := featureForPath(tt.path)
+			assert.Equal(t, tt.feature, feature)
+			if tt.feature != "" {
+				assert.Equal(t, tt.envID, envID)
+			}
+		})
+	}
+}
+
+func TestFeatureFlagMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(NewFeatureFlagMiddleware(func(_ context.Context, envID, feature string) bool {
+		return !(envID == "0" && feature == environment.FeaturePrune)
+	}))
+	router.POST("/api/environments/:id/images/prune", func(c *gin.Context) { c.Status(http.StatusOK) })
+	router.POST("/api/environments/:id/volumes/bulk", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.String(http.StatusOK, string(body))
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/environments/0/images/prune", nil))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/environments/1/images/prune", nil))
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Bulk volume deletes count as a prune; other bulk actions do not.
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/environments/0/volumes/bulk", strings.NewReader(`{"action":"delete","names":["a"]}`)))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	labelBody := `{"action":"label","names":["a"],"labels":{"k":"v"}}`
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/api/environments/0/volumes/bulk", strings.NewReader(labelBody)))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, labelBody, w.Body.String(), "body is passed on intact")
+}
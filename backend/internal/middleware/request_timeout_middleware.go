@@ -0,0 +1,42 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/internal/utils/timeouts"
+	"github.com/gin-gonic/gin"
+)
+
+// NewRequestTimeoutMiddleware applies the deadline a client asks for in the
+// X-Arcane-Timeout header, capped at maxTimeout. The deadline covers the whole
+// request and replaces the configured Docker, proxy and git timeouts, so a
+// dashboard can fail fast while a bulk operation waits as long as it needs.
+// The header is forwarded to remote environments, which apply their own cap.
+// The effective timeout is echoed back in the same header.
+func NewRequestTimeoutMiddleware(maxTimeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		value := c.GetHeader(timeouts.HeaderRequestTimeout)
+		if value == "" {
+			c.Next()
+			return
+		}
+
+		timeout, err := timeouts.ParseRequestTimeout(value, maxTimeout)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"success": false,
+				"data":    gin.H{"error": err.Error()},
+			})
+			c.Abort()
+			return
+		}
+
+		ctx, cancel := timeouts.WithRequestTimeout(c.Request.Context(), timeout)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Header(timeouts.HeaderRequestTimeout, timeout.String())
+		c.Next()
+	}
+}
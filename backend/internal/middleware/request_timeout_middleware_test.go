@@ -0,0 +1,87 @@
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/internal/utils/timeouts"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestTimeoutMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var remaining time.Duration
+	var operation time.Duration
+	var detached time.Duration
+	var hasDeadline bool
+	router := gin.New()
+	router.Use(NewRequestTimeoutMiddleware(time.Minute))
+	router.GET("/api/containers", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		var deadline time.Time
+		deadline, hasDeadline = ctx.Deadline()
+		remaining = time.Until(deadline)
+
+		opCtx, cancel := timeouts.WithTimeout(ctx, 0, timeouts.DefaultDockerAPI)
+		defer cancel()
+		opDeadline, _ := opCtx.Deadline()
+		operation = time.Until(opDeadline)
+
+		// Background work detached from the request keeps its own timeout.
+		detachedCtx, detachedCancel := timeouts.WithTimeout(context.WithoutCancel(ctx), 0, timeouts.DefaultDockerAPI)
+		defer detachedCancel()
+		detachedDeadline, _ := detachedCtx.Deadline()
+		detached = time.Until(detachedDeadline)
+		c.Status(http.StatusOK)
+	})
+
+	request := func(value string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/containers", nil)
+		if value != "" {
+			req.Header.Set(timeouts.HeaderRequestTimeout, value)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("no header keeps configured timeouts", func(t *testing.T) {
+		w := request("")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.False(t, hasDeadline)
+		assert.InDelta(t, timeouts.DefaultDockerAPI.Seconds(), operation.Seconds(), 1)
+		assert.Empty(t, w.Header().Get(timeouts.HeaderRequestTimeout))
+	})
+
+	t.Run("short deadline replaces the Docker timeout", func(t *testing.T) {
+		w := request("2s")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.True(t, hasDeadline)
+		assert.InDelta(t, 2, remaining.Seconds(), 0.5)
+		assert.InDelta(t, 2, operation.Seconds(), 0.5)
+		assert.InDelta(t, timeouts.DefaultDockerAPI.Seconds(), detached.Seconds(), 1)
+		assert.Equal(t, "2s", w.Header().Get(timeouts.HeaderRequestTimeout))
+	})
+
+	t.Run("long deadline outlasts the Docker timeout and is capped", func(t *testing.T) {
+		w := request("3600")
+		require.Equal(t, http.StatusOK, w.Code)
+		assert.InDelta(t, 60, remaining.Seconds(), 1)
+		assert.InDelta(t, 60, operation.Seconds(), 1)
+		assert.InDelta(t, timeouts.DefaultDockerAPI.Seconds(), detached.Seconds(), 1)
+		assert.Equal(t, "1m0s", w.Header().Get(timeouts.HeaderRequestTimeout))
+	})
+
+	t.Run("invalid values are rejected", func(t *testing.T) {
+		for _, value := range []string{"soon", "0", "-5s"} {
+			w := request(value)
+			assert.Equal(t, http.StatusBadRequest, w.Code, value)
+		}
+	})
+}
@@ -0,0 +1,59 @@
+package models
+
+import "time"
+
+type ApprovalStatus string
+
+const (
+	ApprovalStatusPending  ApprovalStatus = "pending"
+	ApprovalStatusApproved ApprovalStatus = "approved"
+	ApprovalStatusRejected ApprovalStatus = "rejected"
+	ApprovalStatusExpired  ApprovalStatus = "expired"
+	ApprovalStatusConsumed ApprovalStatus = "consumed"
+)
+
+// Actions that can require approval.
+const (
+	ApprovalActionPrune         = "prune"
+	ApprovalActionVolumeRestore = "volume_restore"
+	ApprovalActionHostReboot    = "host_reboot"
+)
+
+// ApprovalRequest is a pending or decided request to run a destructive
+// action. An approved request can be used exactly once by the requester to
+// execute the action it was created for.
+type ApprovalRequest struct {
+	Action        string         `json:"action" gorm:"column:action" sortable:"true"`
+	EnvironmentID string         `json:"environmentId" gorm:"column:environment_id"`
+	Method        string         `json:"method" gorm:"column:method"`
+	Path          string         `json:"path" gorm:"column:path"`
+	Query         string         `json:"query" gorm:"column:query"`
+	Status        ApprovalStatus `json:"status" gorm:"column:status" sortable:"true"`
+
+	// Body is the request body the approver sees, and PayloadHash covers the
+	// query and body so the approval cannot be reused with other options.
+	Body        string `json:"body" gorm:"column:body"`
+	PayloadHash string `json:"-" gorm:"column:payload_hash"`
+
+	RequestedBy     string `json:"requestedBy" gorm:"column:requested_by"`
+	RequestedByName string `json:"requestedByName" gorm:"column:requested_by_name"`
+
+	DecidedBy     *string    `json:"decidedBy,omitempty" gorm:"column:decided_by"`
+	DecidedByName *string    `json:"decidedByName,omitempty" gorm:"column:decided_by_name"`
+	DecidedAt     *time.Time `json:"decidedAt,omitempty" gorm:"column:decided_at"`
+	Reason        *string    `json:"reason,omitempty" gorm:"column:reason"`
+
+	// ExpiresAt is the deadline for approving a pending request, or for
+	// executing an approved one.
+	ExpiresAt  time.Time  `json:"expiresAt" gorm:"column:expires_at" sortable:"true"`
+	ConsumedAt *time.Time `json:"consumedAt,omitempty" gorm:"column:consumed_at"`
+
+	BaseModel
+}
+
+func (ApprovalRequest) TableName() string { return "approval_requests" }
+
+// IsExpired reports whether the request's deadline has passed.
+func (r *ApprovalRequest) IsExpired(now time.Time) bool {
+	return now.After(r.ExpiresAt)
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ContainerDigest records the registry digest a container was deployed from,
+// keyed by container name so the record survives the container being
+// recreated. Tag drift checks compare it with the digest the tag currently
+// points to, and a pinned container is left alone by auto-update.
+type ContainerDigest struct {
+	ContainerName  string     `json:"containerName" gorm:"column:container_name;uniqueIndex"`
+	ContainerID    string     `json:"containerId" gorm:"column:container_id"`
+	ImageRef       string     `json:"imageRef" gorm:"column:image_ref"`
+	ImageID        string     `json:"imageId" gorm:"column:image_id"`
+	Digest         string     `json:"digest" gorm:"column:digest"`
+	RecordedAt     time.Time  `json:"recordedAt" gorm:"column:recorded_at"`
+	RemoteDigest   *string    `json:"remoteDigest,omitempty" gorm:"column:remote_digest"`
+	Drifted        bool       `json:"drifted" gorm:"column:drifted;index"`
+	DriftCheckedAt *time.Time `json:"driftCheckedAt,omitempty" gorm:"column:drift_checked_at"`
+	LastError      *string    `json:"lastError,omitempty" gorm:"column:last_error"`
+	Pinned         bool       `json:"pinned" gorm:"column:pinned;index"`
+	PinReason      *string    `json:"pinReason,omitempty" gorm:"column:pin_reason"`
+	PinnedBy       *string    `json:"pinnedBy,omitempty" gorm:"column:pinned_by"`
+	BaseModel
+}
+
+func (ContainerDigest) TableName() string {
+	return "container_digests"
+}
@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// ContainerHealthTransition records that a container's health status changed
+// at a point in time, as reported by the Docker daemon. Containers are keyed
+// by name so a recreated container keeps its history.
+type ContainerHealthTransition struct {
+	ContainerID   string    `json:"containerId" gorm:"column:container_id"`
+	ContainerName string    `json:"containerName" gorm:"column:container_name"`
+	Status        string    `json:"status" gorm:"column:status"`
+	At            time.Time `json:"at" gorm:"column:at"`
+	BaseModel
+}
+
+func (ContainerHealthTransition) TableName() string {
+	return "container_health_transitions"
+}
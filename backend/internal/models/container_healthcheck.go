@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// ContainerHealthcheck is a probe Arcane runs against a container whose image
+// has no healthcheck of its own, keyed by container name or ID. The probe
+// state is stored alongside the definition.
+type ContainerHealthcheck struct {
+	ContainerKey    string      `json:"containerKey" gorm:"column:container_key;uniqueIndex"`
+	Type            string      `json:"type" gorm:"column:type"`
+	Port            int         `json:"port" gorm:"column:port"`
+	Path            string      `json:"path" gorm:"column:path"`
+	Command         StringSlice `json:"command" gorm:"column:command;type:text"`
+	IntervalSeconds int         `json:"intervalSeconds" gorm:"column:interval_seconds"`
+	TimeoutSeconds  int         `json:"timeoutSeconds" gorm:"column:timeout_seconds"`
+	Retries         int         `json:"retries" gorm:"column:retries"`
+	AutoRestart     bool        `json:"autoRestart" gorm:"column:auto_restart"`
+	Status          string      `json:"status" gorm:"column:status"`
+	FailingStreak   int         `json:"failingStreak" gorm:"column:failing_streak"`
+	LastOutput      string      `json:"lastOutput" gorm:"column:last_output"`
+	LastCheckedAt   *time.Time  `json:"lastCheckedAt,omitempty" gorm:"column:last_checked_at"`
+	UpdatedBy       string      `json:"updatedBy" gorm:"column:updated_by"`
+	BaseModel
+}
+
+func (ContainerHealthcheck) TableName() string {
+	return "container_healthchecks"
+}
@@ -0,0 +1,16 @@
+package models
+
+// ContainerOverride stores a display name and icon for a container, keyed by
+// container name or ID, so containers outside compose projects can be
+// labelled in the UI without changing the container itself.
+type ContainerOverride struct {
+	ContainerKey string  `json:"containerKey" gorm:"column:container_key;uniqueIndex"`
+	DisplayName  *string `json:"displayName,omitempty" gorm:"column:display_name"`
+	IconURL      *string `json:"iconUrl,omitempty" gorm:"column:icon_url"`
+	UpdatedBy    string  `json:"updatedBy" gorm:"column:updated_by"`
+	BaseModel
+}
+
+func (ContainerOverride) TableName() string {
+	return "container_overrides"
+}
@@ -0,0 +1,26 @@
+package models
+
+import "time"
+
+// ContainerRollback records what a container looked like before auto-update
+// recreated it on a new image, keyed by container name so it follows the
+// recreated container. Until ObserveUntil has passed the container can be
+// recreated on the previous image, and that image is kept from being pruned.
+type ContainerRollback struct {
+	ContainerName       string     `json:"containerName" gorm:"column:container_name;uniqueIndex"`
+	ContainerID         string     `json:"containerId" gorm:"column:container_id"`
+	PreviousContainerID string     `json:"previousContainerId" gorm:"column:previous_container_id"`
+	PreviousImageRef    string     `json:"previousImageRef" gorm:"column:previous_image_ref"`
+	PreviousImageID     string     `json:"previousImageId" gorm:"column:previous_image_id"`
+	NewImageRef         string     `json:"newImageRef" gorm:"column:new_image_ref"`
+	Snapshot            string     `json:"-" gorm:"column:snapshot;type:text"`
+	AppliedAt           time.Time  `json:"appliedAt" gorm:"column:applied_at"`
+	ObserveUntil        time.Time  `json:"observeUntil" gorm:"column:observe_until;index"`
+	RolledBackAt        *time.Time `json:"rolledBackAt,omitempty" gorm:"column:rolled_back_at"`
+	RolledBackBy        *string    `json:"rolledBackBy,omitempty" gorm:"column:rolled_back_by"`
+	BaseModel
+}
+
+func (ContainerRollback) TableName() string {
+	return "container_rollbacks"
+}
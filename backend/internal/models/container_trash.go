@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// ContainerTrashEntry records a container that was deleted with a grace
+// period. The container is stopped and renamed instead of removed, and the
+// trash purge job removes it once PurgeAt has passed. Until then it can be
+// restored under its original name.
+type ContainerTrashEntry struct {
+	ContainerID    string    `json:"containerId" gorm:"column:container_id;uniqueIndex"`
+	OriginalName   string    `json:"originalName" gorm:"column:original_name"`
+	TrashName      string    `json:"trashName" gorm:"column:trash_name"`
+	RestartPolicy  string    `json:"restartPolicy" gorm:"column:restart_policy"`
+	RestartRetries int       `json:"restartRetries" gorm:"column:restart_retries"`
+	WasRunning     bool      `json:"wasRunning" gorm:"column:was_running"`
+	RemoveVolumes  bool      `json:"removeVolumes" gorm:"column:remove_volumes"`
+	DeletedBy      string    `json:"deletedBy" gorm:"column:deleted_by"`
+	PurgeAt        time.Time `json:"purgeAt" gorm:"column:purge_at;index"`
+	BaseModel
+}
+
+func (ContainerTrashEntry) TableName() string {
+	return "container_trash"
+}
@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+// EndpointMonitor is an external HTTP or TCP check that Arcane runs against
+// an application endpoint, optionally tied to a project or container. The
+// probe state is stored alongside the definition.
+type EndpointMonitor struct {
+	Name            string     `json:"name" gorm:"column:name"`
+	Type            string     `json:"type" gorm:"column:type"`
+	Target          string     `json:"target" gorm:"column:target"`
+	ExpectedStatus  int        `json:"expectedStatus" gorm:"column:expected_status"`
+	IntervalSeconds int        `json:"intervalSeconds" gorm:"column:interval_seconds"`
+	TimeoutSeconds  int        `json:"timeoutSeconds" gorm:"column:timeout_seconds"`
+	Retries         int        `json:"retries" gorm:"column:retries"`
+	ProjectID       *string    `json:"projectId,omitempty" gorm:"column:project_id;index"`
+	ContainerKey    *string    `json:"containerKey,omitempty" gorm:"column:container_key"`
+	Enabled         bool       `json:"enabled" gorm:"column:enabled"`
+	Notify          bool       `json:"notify" gorm:"column:notify"`
+	Status          string     `json:"status" gorm:"column:status"`
+	FailingStreak   int        `json:"failingStreak" gorm:"column:failing_streak"`
+	LastStatusCode  int        `json:"lastStatusCode" gorm:"column:last_status_code"`
+	LastLatencyMs   int64      `json:"lastLatencyMs" gorm:"column:last_latency_ms"`
+	LastError       string     `json:"lastError" gorm:"column:last_error"`
+	LastCheckedAt   *time.Time `json:"lastCheckedAt,omitempty" gorm:"column:last_checked_at"`
+	UpdatedBy       string     `json:"updatedBy" gorm:"column:updated_by"`
+	BaseModel
+}
+
+func (EndpointMonitor) TableName() string {
+	return "endpoint_monitors"
+}
+
+// EndpointMonitorResult is the outcome of a single endpoint monitor probe.
+type EndpointMonitorResult struct {
+	MonitorID  string    `json:"monitorId" gorm:"column:monitor_id;index"`
+	Up         bool      `json:"up" gorm:"column:up"`
+	StatusCode int       `json:"statusCode" gorm:"column:status_code"`
+	LatencyMs  int64     `json:"latencyMs" gorm:"column:latency_ms"`
+	Error      string    `json:"error" gorm:"column:error"`
+	CheckedAt  time.Time `json:"checkedAt" gorm:"column:checked_at"`
+	BaseModel
+}
+
+func (EndpointMonitorResult) TableName() string {
+	return "endpoint_monitor_results"
+}
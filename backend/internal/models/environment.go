@@ -1,6 +1,12 @@
 package models
 
-import "time"
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+
+	"github.com/getarcaneapp/arcane/types/environment"
+)
 
 type Environment struct {
 	Name        string     `json:"name" sortable:"true"`
@@ -12,11 +18,72 @@ type Environment struct {
 	AccessToken *string    `json:"-" gorm:"column:access_token"`
 	ApiKeyID    *string    `json:"-" gorm:"column:api_key_id"`
 
+	// FeatureOverrides enables or disables features for this environment,
+	// taking precedence over the global settings.
+	FeatureOverrides FeatureOverrides `json:"featureOverrides,omitempty" gorm:"column:feature_overrides;type:text"`
+
+	// RequireApproval makes destructive actions on this environment go
+	// through the approval workflow.
+	RequireApproval bool `json:"requireApproval" gorm:"column:require_approval;default:false"`
+
+	// Display metadata used to tell environments apart in the UI.
+	Color       string `json:"color" gorm:"column:color"`
+	Icon        string `json:"icon" gorm:"column:icon"`
+	Description string `json:"description" gorm:"column:description"`
+
+	// Production marks the environment as production; destructive actions
+	// on it are treated like RequireApproval.
+	Production bool `json:"production" gorm:"column:production;default:false"`
+
+	// Tags are free-form labels used to group and filter environments.
+	Tags StringSlice `json:"tags,omitempty" gorm:"column:tags;type:text"`
+
+	// Metrics is the status the agent pushed with its last heartbeat.
+	Metrics *EnvironmentMetrics `json:"metrics,omitempty" gorm:"column:heartbeat_metrics;type:text"`
+
+	// Power integration for environments on physical hosts. MacAddress is
+	// the host NIC woken by Wake-on-LAN, WakeBroadcast the address the magic
+	// packet is sent to, and WakeRelayID an environment on the same LAN that
+	// sends it instead of the manager.
+	MacAddress    string  `json:"macAddress" gorm:"column:mac_address"`
+	WakeBroadcast string  `json:"wakeBroadcast" gorm:"column:wake_broadcast"`
+	WakeRelayID   *string `json:"wakeRelayId,omitempty" gorm:"column:wake_relay_id"`
+
 	BaseModel
 }
 
 func (Environment) TableName() string { return "environments" }
 
+// ProductionGuardrails reports whether destructive actions on the environment
+// must go through the approval workflow.
+func (e Environment) ProductionGuardrails() bool {
+	return e.RequireApproval || e.Production
+}
+
+// EnvironmentMetrics stores the metrics an agent reports with its heartbeat.
+//
+// nolint:recvcheck
+type EnvironmentMetrics environment.HeartbeatMetrics
+
+func (m EnvironmentMetrics) Value() (driver.Value, error) {
+	return json.Marshal(m)
+}
+
+func (m *EnvironmentMetrics) Scan(value interface{}) error {
+	if value == nil {
+		*m = EnvironmentMetrics{}
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, m)
+	case string:
+		return json.Unmarshal([]byte(v), m)
+	default:
+		return json.Unmarshal(nil, m)
+	}
+}
+
 type EnvironmentStatus string
 
 const (
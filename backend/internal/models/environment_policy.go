@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// EnvironmentPolicy is the policy issued to an environment. On the manager
+// there is one row per environment; on an agent the row of environment "0" is
+// the local copy of the policy the manager issued to it.
+type EnvironmentPolicy struct {
+	EnvironmentID   string      `json:"environmentId" gorm:"column:environment_id;uniqueIndex"`
+	Revision        int64       `json:"revision" gorm:"column:revision"`
+	AllowedImages   StringSlice `json:"allowedImages" gorm:"column:allowed_images;type:text"`
+	DenyPrivileged  bool        `json:"denyPrivileged" gorm:"column:deny_privileged"`
+	DenyHostNetwork bool        `json:"denyHostNetwork" gorm:"column:deny_host_network"`
+	PruneSchedule   string      `json:"pruneSchedule" gorm:"column:prune_schedule"`
+	PruneMode       string      `json:"pruneMode" gorm:"column:prune_mode"`
+	UpdatedBy       string      `json:"updatedBy" gorm:"column:updated_by"`
+	SyncedAt        *time.Time  `json:"syncedAt,omitempty" gorm:"column:synced_at"`
+	LastPruneAt     *time.Time  `json:"lastPruneAt,omitempty" gorm:"column:last_prune_at"`
+	BaseModel
+}
+
+func (EnvironmentPolicy) TableName() string {
+	return "environment_policies"
+}
+
+// EnvironmentPolicyReport is a policy report an agent has not yet delivered
+// to its manager.
+type EnvironmentPolicyReport struct {
+	Kind       string    `json:"kind" gorm:"column:kind"`
+	Revision   int64     `json:"revision" gorm:"column:revision"`
+	Message    string    `json:"message" gorm:"column:message"`
+	Details    JSON      `json:"details,omitempty" gorm:"column:details;type:text"`
+	OccurredAt time.Time `json:"occurredAt" gorm:"column:occurred_at;index"`
+	BaseModel
+}
+
+func (EnvironmentPolicyReport) TableName() string {
+	return "environment_policy_reports"
+}
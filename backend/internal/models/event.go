@@ -9,21 +9,47 @@ type EventSeverity string
 
 const (
 	// Event types
-	EventTypeContainerStart   EventType = "container.start"
-	EventTypeContainerStop    EventType = "container.stop"
-	EventTypeContainerRestart EventType = "container.restart"
-	EventTypeContainerDelete  EventType = "container.delete"
-	EventTypeContainerCreate  EventType = "container.create"
-	EventTypeContainerScan    EventType = "container.scan"
-	EventTypeContainerUpdate  EventType = "container.update"
-	EventTypeContainerError   EventType = "container.error"
+	EventTypeContainerStart     EventType = "container.start"
+	EventTypeContainerStop      EventType = "container.stop"
+	EventTypeContainerRestart   EventType = "container.restart"
+	EventTypeContainerDelete    EventType = "container.delete"
+	EventTypeContainerRestore   EventType = "container.restore"
+	EventTypeContainerCreate    EventType = "container.create"
+	EventTypeContainerScan      EventType = "container.scan"
+	EventTypeContainerUpdate    EventType = "container.update"
+	EventTypeContainerError     EventType = "container.error"
+	EventTypeContainerRename    EventType = "container.rename"
+	EventTypeContainerRecreate  EventType = "container.recreate"
+	EventTypeContainerHealthy   EventType = "container.healthy"
+	EventTypeContainerUnhealthy EventType = "container.unhealthy"
+	EventTypeContainerCommit    EventType = "container.commit"
+	EventTypeContainerExport    EventType = "container.export"
+	EventTypeContainerFlapping  EventType = "container.flapping"
+	EventTypeContainerOOMKilled EventType = "container.oom_killed"
+	EventTypeContainerCrashLoop EventType = "container.crash_loop"
+	EventTypeContainerExecStart EventType = "container.exec.start"
+	EventTypeContainerExecEnd   EventType = "container.exec.end"
+	EventTypeContainerTagDrift  EventType = "container.tag_drift"
+	EventTypeContainerPin       EventType = "container.pin"
+	EventTypeContainerUnpin     EventType = "container.unpin"
+	EventTypeContainerRollback  EventType = "container.rollback"
+
+	EventTypeContainerCheckpoint        EventType = "container.checkpoint"
+	EventTypeContainerCheckpointRestore EventType = "container.checkpoint.restore"
 
 	EventTypeImagePull              EventType = "image.pull"
 	EventTypeImageLoad              EventType = "image.load"
+	EventTypeImageSave              EventType = "image.save"
+	EventTypeImageBuild             EventType = "image.build"
 	EventTypeImageDelete            EventType = "image.delete"
 	EventTypeImageScan              EventType = "image.scan"
 	EventTypeImageError             EventType = "image.error"
 	EventTypeImageVulnerabilityScan EventType = "image.vulnerability_scan"
+	EventTypeImagePin               EventType = "image.pin"
+	EventTypeImageUnpin             EventType = "image.unpin"
+	EventTypeImageTag               EventType = "image.tag"
+	EventTypeImageUntag             EventType = "image.untag"
+	EventTypeImageScanGateOverride  EventType = "image.scan_gate.override"
 
 	EventTypeProjectDeploy EventType = "project.deploy"
 	EventTypeProjectDelete EventType = "project.delete"
@@ -47,17 +73,22 @@ const (
 
 	EventTypeVolumeCreate EventType = "volume.create"
 	EventTypeVolumeDelete EventType = "volume.delete"
+	EventTypeVolumeUpdate EventType = "volume.update"
 	EventTypeVolumeError  EventType = "volume.error"
 
-	EventTypeVolumeFileCreate EventType = "volume.file.create"
-	EventTypeVolumeFileDelete EventType = "volume.file.delete"
-	EventTypeVolumeFileUpload EventType = "volume.file.upload"
+	EventTypeVolumeFileCreate      EventType = "volume.file.create"
+	EventTypeVolumeFileDelete      EventType = "volume.file.delete"
+	EventTypeVolumeFileUpload      EventType = "volume.file.upload"
+	EventTypeVolumeFileOwnership   EventType = "volume.file.ownership"
+	EventTypeVolumeFilePermissions EventType = "volume.file.permissions"
 
 	EventTypeVolumeBackupCreate       EventType = "volume.backup.create"
 	EventTypeVolumeBackupDelete       EventType = "volume.backup.delete"
 	EventTypeVolumeBackupRestore      EventType = "volume.backup.restore"
 	EventTypeVolumeBackupRestoreFiles EventType = "volume.backup.restore_files"
 	EventTypeVolumeBackupDownload     EventType = "volume.backup.download"
+	EventTypeVolumeBackupAdopt        EventType = "volume.backup.adopt"
+	EventTypeVolumeBackupTransfer     EventType = "volume.backup.transfer"
 
 	EventTypeNetworkCreate EventType = "network.create"
 	EventTypeNetworkDelete EventType = "network.delete"
@@ -67,12 +98,32 @@ const (
 	EventTypeUserLogin        EventType = "user.login"
 	EventTypeUserLogout       EventType = "user.logout"
 	EventTypeSystemAutoUpdate EventType = "system.auto_update"
+	EventTypeSystemRollout    EventType = "system.auto_update.rollout"
 	EventTypeSystemUpgrade    EventType = "system.upgrade"
 
+	EventTypeSystemBootVerification     EventType = "system.boot_verification"
+	EventTypeSystemOperationInterrupted EventType = "system.operation_interrupted"
+	EventTypeSystemHelperReap           EventType = "system.helper_reap"
+	EventTypeSystemHostReboot           EventType = "system.host_reboot"
+
 	EventTypeEnvironmentCreate            EventType = "environment.create"
 	EventTypeEnvironmentUpdate            EventType = "environment.update"
 	EventTypeEnvironmentDelete            EventType = "environment.delete"
 	EventTypeEnvironmentApiKeyRegenerated EventType = "environment.api_key.regenerated"
+	EventTypeEnvironmentPolicyViolation   EventType = "environment.policy.violation"
+	EventTypeEnvironmentPolicyPrune       EventType = "environment.policy.prune"
+	EventTypeEnvironmentWake              EventType = "environment.wake"
+
+	EventTypeApprovalRequested EventType = "approval.requested"
+	EventTypeApprovalApproved  EventType = "approval.approved"
+	EventTypeApprovalRejected  EventType = "approval.rejected"
+	EventTypeApprovalExecuted  EventType = "approval.executed"
+
+	EventTypeMonitorDown EventType = "monitor.down"
+	EventTypeMonitorUp   EventType = "monitor.up"
+
+	EventTypeWebhookReceived  EventType = "webhook.received"
+	EventTypeWebhookTriggered EventType = "webhook.triggered"
 
 	// Event severities
 	EventSeverityInfo    EventSeverity = "info"
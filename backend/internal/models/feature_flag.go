@@ -0,0 +1,33 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+)
+
+// FeatureOverrides maps feature names to an environment-specific enabled state.
+//
+// nolint:recvcheck
+type FeatureOverrides map[string]bool
+
+func (f FeatureOverrides) Value() (driver.Value, error) {
+	if f == nil {
+		return nil, nil
+	}
+	return json.Marshal(f)
+}
+
+func (f *FeatureOverrides) Scan(value interface{}) error {
+	if value == nil {
+		*f = nil
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, f)
+	case string:
+		return json.Unmarshal([]byte(v), f)
+	default:
+		return json.Unmarshal(nil, f)
+	}
+}
@@ -0,0 +1,19 @@
+package models
+
+// ImageLicense records a license of a package found by a scan of an image.
+// Repository is the image name without tag or digest, so licenses can be
+// compared across tags of the same image.
+type ImageLicense struct {
+	ImageID    string `json:"imageId" gorm:"column:image_id"`
+	ImageName  string `json:"imageName" gorm:"column:image_name"`
+	Repository string `json:"repository" gorm:"column:repository"`
+	Name       string `json:"name" gorm:"column:name"`
+	Category   string `json:"category" gorm:"column:category"`
+	PkgName    string `json:"pkgName" gorm:"column:pkg_name"`
+	FilePath   string `json:"filePath" gorm:"column:file_path"`
+	BaseModel
+}
+
+func (ImageLicense) TableName() string {
+	return "image_licenses"
+}
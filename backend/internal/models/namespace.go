@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+// Namespace is a team that owns projects, volumes and containers on a shared
+// host. Docker resources join a namespace through the namespace label; compose
+// projects are assigned by name. Quotas of 0 are unlimited.
+type Namespace struct {
+	Name          string  `json:"name" gorm:"column:name;uniqueIndex" sortable:"true"`
+	Description   *string `json:"description,omitempty" gorm:"column:description"`
+	MaxContainers int     `json:"maxContainers" gorm:"column:max_containers"`
+	MaxMemoryMB   int     `json:"maxMemoryMb" gorm:"column:max_memory_mb"`
+	MaxVolumeGB   int     `json:"maxVolumeGb" gorm:"column:max_volume_gb"`
+
+	BaseModel
+}
+
+func (Namespace) TableName() string { return "namespaces" }
+
+// NamespaceMember grants a user access to the resources of a namespace.
+type NamespaceMember struct {
+	NamespaceID string    `json:"namespaceId" gorm:"column:namespace_id;primaryKey"`
+	UserID      string    `json:"userId" gorm:"column:user_id;primaryKey"`
+	CreatedAt   time.Time `json:"createdAt" gorm:"column:created_at"`
+}
+
+func (NamespaceMember) TableName() string { return "namespace_members" }
@@ -40,10 +40,17 @@ func IsValidNotificationProvider(provider NotificationProvider) bool {
 type NotificationEventType string
 
 const (
-	NotificationEventImageUpdate        NotificationEventType = "image_update"
-	NotificationEventContainerUpdate    NotificationEventType = "container_update"
-	NotificationEventVulnerabilityFound NotificationEventType = "vulnerability_found"
-	NotificationEventPruneReport        NotificationEventType = "prune_report"
+	NotificationEventImageUpdate         NotificationEventType = "image_update"
+	NotificationEventContainerUpdate     NotificationEventType = "container_update"
+	NotificationEventVulnerabilityFound  NotificationEventType = "vulnerability_found"
+	NotificationEventPruneReport         NotificationEventType = "prune_report"
+	NotificationEventBootVerification    NotificationEventType = "boot_verification"
+	NotificationEventMonitorDown         NotificationEventType = "monitor_down"
+	NotificationEventContainerFlapping   NotificationEventType = "container_flapping"
+	NotificationEventLicenseDenied       NotificationEventType = "license_denied"
+	NotificationEventContainerOOMKilled  NotificationEventType = "container_oom_killed"
+	NotificationEventContainerCrashLoop  NotificationEventType = "container_crash_loop"
+	NotificationEventVulnerabilityDigest NotificationEventType = "vulnerability_digest"
 )
 
 type EmailTLSMode string
@@ -0,0 +1,22 @@
+package models
+
+// Kinds of long-running operations tracked for graceful shutdown.
+const (
+	OperationKindVolumeBackup  = "volume.backup"
+	OperationKindVolumeRestore = "volume.restore"
+	OperationKindProjectDeploy = "project.deploy"
+	OperationKindContainerExec = "container.exec"
+)
+
+// OperationState records an operation that was in flight. Rows are removed
+// when the operation ends, so any row found at startup belongs to an
+// operation that was cut off by a restart.
+type OperationState struct {
+	Kind     string `json:"kind" gorm:"column:kind"`
+	Resource string `json:"resource" gorm:"column:resource"`
+	Metadata JSON   `json:"metadata,omitempty" gorm:"column:metadata;type:text"`
+
+	BaseModel
+}
+
+func (OperationState) TableName() string { return "operation_states" }
@@ -0,0 +1,15 @@
+package models
+
+// PinnedImage marks an image, identified by its content digest, as protected
+// from prune jobs and auto-update.
+type PinnedImage struct {
+	Digest   string  `json:"digest" gorm:"column:digest;uniqueIndex"`
+	ImageRef string  `json:"imageRef" gorm:"column:image_ref"`
+	Reason   *string `json:"reason,omitempty" gorm:"column:reason"`
+	PinnedBy string  `json:"pinnedBy" gorm:"column:pinned_by"`
+	BaseModel
+}
+
+func (PinnedImage) TableName() string {
+	return "pinned_images"
+}
@@ -0,0 +1,23 @@
+package models
+
+// ProjectDeployHook is a command or webhook run before or after a project is
+// deployed. Hooks of a phase run in Position order.
+type ProjectDeployHook struct {
+	ProjectID      string `json:"projectId" gorm:"column:project_id;index"`
+	Phase          string `json:"phase" gorm:"column:phase"`
+	Position       int    `json:"position" gorm:"column:position"`
+	Type           string `json:"type" gorm:"column:type"`
+	Service        string `json:"service" gorm:"column:service"`
+	Image          string `json:"image" gorm:"column:image"`
+	Command        string `json:"command" gorm:"column:command"`
+	URL            string `json:"url" gorm:"column:url"`
+	TimeoutSeconds int    `json:"timeoutSeconds" gorm:"column:timeout_seconds"`
+	OnFailure      string `json:"onFailure" gorm:"column:on_failure"`
+	Enabled        bool   `json:"enabled" gorm:"column:enabled"`
+	UpdatedBy      string `json:"updatedBy" gorm:"column:updated_by"`
+	BaseModel
+}
+
+func (ProjectDeployHook) TableName() string {
+	return "project_deploy_hooks"
+}
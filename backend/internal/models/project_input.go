@@ -0,0 +1,16 @@
+package models
+
+// ProjectInput records the definition of an x-arcane input the last time
+// values were saved for it, so that only new or changed inputs are asked for
+// again after the compose file is upgraded.
+type ProjectInput struct {
+	ProjectID   string `json:"projectId" gorm:"column:project_id;index"`
+	Name        string `json:"name" gorm:"column:name"`
+	Fingerprint string `json:"fingerprint" gorm:"column:fingerprint"`
+	UpdatedBy   string `json:"updatedBy" gorm:"column:updated_by"`
+	BaseModel
+}
+
+func (ProjectInput) TableName() string {
+	return "project_inputs"
+}
@@ -0,0 +1,56 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+
+	"github.com/getarcaneapp/arcane/types/system"
+)
+
+const (
+	PruneTriggerManual    = "manual"
+	PruneTriggerScheduled = "scheduled"
+)
+
+// nolint:recvcheck
+type PrunedItems []system.PrunedItem
+
+func (p PrunedItems) Value() (driver.Value, error) {
+	if p == nil {
+		return "[]", nil
+	}
+	return json.Marshal(p)
+}
+
+func (p *PrunedItems) Scan(value interface{}) error {
+	if value == nil {
+		*p = nil
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, p)
+	case string:
+		return json.Unmarshal([]byte(v), p)
+	default:
+		return json.Unmarshal(nil, p)
+	}
+}
+
+// PruneReport records which resources a prune removed and how much space
+// each of them freed.
+type PruneReport struct {
+	EnvironmentID  string      `json:"environmentId" gorm:"column:environment_id"`
+	Trigger        string      `json:"trigger" gorm:"column:trigger_type" sortable:"true"`
+	UserID         *string     `json:"userId,omitempty" gorm:"column:user_id"`
+	Username       string      `json:"username" gorm:"column:username"`
+	Success        bool        `json:"success" gorm:"column:success"`
+	SpaceReclaimed int64       `json:"spaceReclaimed" gorm:"column:space_reclaimed" sortable:"true"`
+	ItemCount      int         `json:"itemCount" gorm:"column:item_count"`
+	Items          PrunedItems `json:"items" gorm:"column:items;type:text"`
+	Errors         StringSlice `json:"errors" gorm:"column:errors;type:text"`
+
+	BaseModel
+}
+
+func (PruneReport) TableName() string { return "prune_reports" }
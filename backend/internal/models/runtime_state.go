@@ -0,0 +1,70 @@
+package models
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"time"
+)
+
+// RuntimeStateSnapshotID is the ID of the single snapshot row kept for the
+// local Docker daemon.
+const RuntimeStateSnapshotID = "local"
+
+// RuntimeStateContainer is a container that was running when the snapshot was taken.
+type RuntimeStateContainer struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Project       string    `json:"project,omitempty"`
+	Service       string    `json:"service,omitempty"`
+	RestartPolicy string    `json:"restartPolicy,omitempty"`
+	StartedAt     time.Time `json:"startedAt"`
+}
+
+// nolint:recvcheck
+type RuntimeStateContainers []RuntimeStateContainer
+
+func (c RuntimeStateContainers) Value() (driver.Value, error) {
+	if c == nil {
+		return nil, nil
+	}
+	return json.Marshal(c)
+}
+
+func (c *RuntimeStateContainers) Scan(value interface{}) error {
+	if value == nil {
+		*c = nil
+		return nil
+	}
+	switch v := value.(type) {
+	case []byte:
+		return json.Unmarshal(v, c)
+	case string:
+		return json.Unmarshal([]byte(v), c)
+	default:
+		return json.Unmarshal(nil, c)
+	}
+}
+
+// RuntimeStateSnapshot records which containers are expected to be running so
+// they can be verified after a Docker daemon restart or host reboot.
+type RuntimeStateSnapshot struct {
+	// ID identifies the snapshot; only RuntimeStateSnapshotID is used.
+	ID string `json:"id" gorm:"primaryKey;type:text"`
+
+	// HostBootTime is the host boot time at snapshot time, if it could be read.
+	HostBootTime *time.Time `json:"hostBootTime,omitempty" gorm:"column:host_boot_time"`
+
+	// DaemonStartTime is when the Docker daemon had last started at snapshot
+	// time, if it could be determined.
+	DaemonStartTime *time.Time `json:"daemonStartTime,omitempty" gorm:"column:daemon_start_time"`
+
+	// Containers are the containers that were running.
+	Containers RuntimeStateContainers `json:"containers" gorm:"column:containers;type:text"`
+
+	// TakenAt is when the snapshot was taken.
+	TakenAt time.Time `json:"takenAt" gorm:"column:taken_at"`
+}
+
+func (RuntimeStateSnapshot) TableName() string {
+	return "runtime_state_snapshots"
+}
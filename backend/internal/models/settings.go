@@ -56,8 +56,15 @@ type Settings struct {
 	AutoUpdate                   SettingVariable `key:"autoUpdate" meta:"label=Auto Update;type=boolean;keywords=auto,update,automatic,upgrade,refresh,restart,deploy;category=internal;description=Automatically update containers when new images are available"`
 	AutoUpdateInterval           SettingVariable `key:"autoUpdateInterval" meta:"label=Auto Update Interval;type=cron;keywords=auto,update,interval,frequency,schedule,automatic,timing;category=internal;description=How often to check for automatic updates (cron expression)"`
 	AutoUpdateExcludedContainers SettingVariable `key:"autoUpdateExcludedContainers" meta:"label=Excluded Containers;type=text;keywords=exclude,containers,ignore,skip;category=internal;description=Comma-separated list of containers to exclude from auto-update"`
+	AutoUpdateRollbackWindow     SettingVariable `key:"autoUpdateRollbackWindow" meta:"label=Rollback Window;type=number;keywords=rollback,revert,undo,previous,image,auto,update,unhealthy,observe;category=internal;description=Minutes after an auto-update during which a container can be rolled back to its previous image, 0 disables rollback (default: 60)"`
+	AutoUpdateCanaryEnvironment  SettingVariable `key:"autoUpdateCanaryEnvironment" meta:"label=Canary Environment;type=text;keywords=canary,staged,rollout,auto,update,environment,soak;category=internal;description=Environment the auto-update rolls out to first; the other environments receive the same image updates after the soak period. Empty disables staged rollouts"`
+	AutoUpdateSoakPeriod         SettingVariable `key:"autoUpdateSoakPeriod" meta:"label=Soak Period;type=number;keywords=canary,soak,staged,rollout,health,wait,auto,update;category=internal;description=Minutes the updated canary containers must stay healthy before the rollout continues to the other environments (default: 30)"`
 	PollingEnabled               SettingVariable `key:"pollingEnabled" meta:"label=Enable Polling;type=boolean;keywords=polling,check,monitor,watch,scan,detection,automatic;category=internal;description=Enable automatic checking for image updates"`
 	PollingInterval              SettingVariable `key:"pollingInterval" meta:"label=Polling Interval;type=cron;keywords=interval,frequency,schedule,time,minutes,period,delay;category=internal;description=How often to check for image updates (cron expression)"`
+	TagDriftDetectionEnabled     SettingVariable `key:"tagDriftDetectionEnabled" meta:"label=Tag Drift Detection;type=boolean;keywords=tag,drift,digest,pin,registry,deployed,running,update;category=internal;description=Record the digest each container was deployed from and flag containers whose tag now points to a different digest during image polling"`
+	UpdateCheckCacheTTL          SettingVariable `key:"updateCheckCacheTtl" meta:"label=Update Check Cache TTL;type=number;keywords=update,check,cache,ttl,minutes,registry,rate,limit;category=internal;description=How long registry digest lookups are cached in minutes, 0 disables caching (default: 15)"`
+	RegistryRequestBudget        SettingVariable `key:"registryRequestBudget" meta:"label=Registry Request Budget;type=number;keywords=registry,rate,limit,budget,requests,docker,hub,update;category=internal;description=Maximum update check requests per minute to each registry, 0 for unlimited (default: 30)"`
+	RegistryMirrors              SettingVariable `key:"registryMirrors" meta:"label=Registry Mirrors;type=text;keywords=registry,mirror,fallback,pull,outage,rate,limit,docker,hub,proxy;category=internal;description=Mirrors to retry image pulls against when a registry is unavailable or rate limited, one registry=mirror pair per line (e.g. docker.io=mirror.gcr.io)"`
 	EventCleanupInterval         SettingVariable `key:"eventCleanupInterval" meta:"label=Event Cleanup Interval;type=cron;keywords=events,cleanup,retention,interval,frequency,schedule,history,logs,jobs;description=How often to delete old events (cron expression)"`
 	AnalyticsHeartbeatInterval   SettingVariable `key:"analyticsHeartbeatInterval" meta:"label=Analytics Heartbeat Interval;type=cron;keywords=analytics,heartbeat,interval,frequency,schedule,telemetry,jobs;description=How often to send the anonymous analytics heartbeat (cron expression)"`
 	AutoInjectEnv                SettingVariable `key:"autoInjectEnv" meta:"label=Auto Inject Env Variables;type=boolean;keywords=auto,inject,env,environment,variables,interpolation;category=internal;description=Automatically inject project .env variables into all containers (default: false)"`
@@ -69,7 +76,30 @@ type Settings struct {
 	ScheduledPruneVolumes        SettingVariable `key:"scheduledPruneVolumes" meta:"label=Scheduled Prune Volumes;type=boolean;keywords=prune,volumes,cleanup,maintenance;category=internal;description=Remove unused volumes during scheduled prune"`
 	ScheduledPruneNetworks       SettingVariable `key:"scheduledPruneNetworks" meta:"label=Scheduled Prune Networks;type=boolean;keywords=prune,networks,cleanup,maintenance;category=internal;description=Remove unused networks during scheduled prune"`
 	ScheduledPruneBuildCache     SettingVariable `key:"scheduledPruneBuildCache" meta:"label=Scheduled Prune Build Cache;type=boolean;keywords=prune,build cache,cleanup,maintenance;category=internal;description=Remove Docker build cache during scheduled prune"`
+	MaintenanceWindows           SettingVariable `key:"maintenanceWindows" meta:"label=Maintenance Windows;type=text;keywords=maintenance,window,schedule,hours,days,quiet,business,auto,update,prune,scan;category=internal;description=When auto-update, scheduled prune and vulnerability scans may run, one window per line in server local time (e.g. mon-fri 01:00-05:00); empty allows any time"`
+	MaintenanceWindowOverride    SettingVariable `key:"maintenanceWindowOverride" meta:"label=Maintenance Window Override;type=boolean;keywords=maintenance,window,override,bypass,ignore,emergency;category=internal;description=Let scheduled jobs run outside the maintenance windows until turned off again (default: false)"`
+	BootVerificationEnabled      SettingVariable `key:"bootVerificationEnabled" meta:"label=Post-Restart Verification;type=boolean;keywords=boot,reboot,restart,daemon,verify,recover,start,containers,snapshot;category=internal;description=Start containers that were running before a Docker daemon restart or host reboot but did not come back (default: false)"`
+	BootVerificationInterval     SettingVariable `key:"bootVerificationInterval" meta:"label=Post-Restart Verification Interval;type=cron;keywords=boot,reboot,restart,verify,snapshot,interval,schedule;category=internal;description=How often to snapshot running containers and check for a Docker restart (cron expression)"`
+	HealthFlapThreshold          SettingVariable `key:"healthFlapThreshold" meta:"label=Health Flap Threshold;type=number;keywords=health,healthcheck,flap,flapping,unhealthy,alert,notification,transitions;category=internal;description=Alert when a container changes health status more than this many times in an hour, 0 to disable (default: 5)"`
+	CrashLoopThreshold           SettingVariable `key:"crashLoopThreshold" meta:"label=Crash Loop Threshold;type=number;keywords=crash,loop,restart,exit,oom,alert,notification;category=internal;description=Alert when a container exits with an error more than this many times within the crash loop window, 0 to disable (default: 5)"`
+	CrashLoopWindow              SettingVariable `key:"crashLoopWindow" meta:"label=Crash Loop Window;type=number;keywords=crash,loop,restart,window,minutes,alert;category=internal;description=Window in minutes in which container crashes are counted for crash loop alerts (default: 10)"`
+	ContainerTrashRetention      SettingVariable `key:"containerTrashRetention" meta:"label=Container Trash Retention;type=number;keywords=container,delete,trash,undo,restore,retention,grace,hours;category=internal;description=Hours a deleted container stays stopped in the trash before it is removed, 0 to remove right away (default: 0)"`
+	ContainerDNSServers          SettingVariable `key:"containerDnsServers" meta:"label=Default DNS Servers;type=text;keywords=dns,nameserver,resolver,server,container,internal,homelab,override;category=internal;description=DNS servers given to new containers that set none of their own, separated by commas (e.g. 192.168.1.53, fd00::53)"`
+	ContainerDNSSearch           SettingVariable `key:"containerDnsSearch" meta:"label=Default DNS Search Domains;type=text;keywords=dns,search,domain,suffix,container,internal,homelab;category=internal;description=DNS search domains given to new containers that set none of their own, separated by commas (e.g. home.lan)"`
+	ContainerExtraHosts          SettingVariable `key:"containerExtraHosts" meta:"label=Default Extra Hosts;type=text;keywords=extra,hosts,etc,hosts,dns,override,container,internal,homelab;category=internal;description=Entries added to /etc/hosts of new containers, one hostname:IP pair per line (e.g. nas.home.lan:192.168.1.10); a container's own entry for the same hostname wins"`
+	VolumeBackupDriver           SettingVariable `key:"volumeBackupDriver" meta:"label=Volume Backup Driver;type=select;keywords=volume,backup,snapshot,zfs,btrfs,tar,driver;category=internal;description=Use tar archives or ZFS/Btrfs snapshots for volume backups; snapshot falls back to tar when unsupported (default: tar)"`
+	HelperImage                  SettingVariable `key:"helperImage,envOverride" meta:"label=Helper Image;type=text;keywords=helper,image,busybox,mirror,pin,volume,backup,restore,browse;category=internal;description=Pin the image used for volume backup, restore and browse helpers; it must provide sh, tar, find and stat (default: detected automatically)"`
+	HelperCpuLimit               SettingVariable `key:"helperCpuLimit,envOverride" meta:"label=Helper CPU Limit;type=number;keywords=helper,cpu,limit,cores,resources,volume,backup,restore,browse;category=internal;description=Maximum CPU cores a volume helper container may use, 0 for unlimited (default: 0)"`
+	HelperMemoryLimit            SettingVariable `key:"helperMemoryLimit,envOverride" meta:"label=Helper Memory Limit;type=number;keywords=helper,memory,ram,limit,megabytes,mb,resources,volume,backup,restore,browse;category=internal;description=Maximum memory in MB a volume helper container may use, 0 for unlimited (default: 0)"`
+	HelperIdleTtl                SettingVariable `key:"helperIdleTtl,envOverride" meta:"label=Helper Idle TTL;type=number;keywords=helper,idle,ttl,timeout,reaper,cleanup,minutes,volume,browse;category=internal;description=Minutes an unused read-only volume helper container is kept before it is removed, 0 to keep it until shutdown (default: 10)"`
+	StaleHelperMaxAge            SettingVariable `key:"staleHelperMaxAge,envOverride" meta:"label=Stale Helper Max Age;type=number;keywords=helper,stale,leak,orphan,reaper,cleanup,hours,restore,temp,crash;category=internal;description=Hours after which running helper containers and restore leftovers that Arcane no longer tracks are removed, 0 to disable (default: 6)"`
+	DockerMaxConcurrentRequests  SettingVariable `key:"dockerMaxConcurrentRequests,envOverride" meta:"label=Max Concurrent Docker Requests;type=number;keywords=docker,api,concurrency,limit,requests,parallel,daemon,performance;category=internal;description=Maximum Docker API requests Arcane sends at once, 0 for unlimited (default: 0)"`
+	DockerMaxConcurrentDiskUsage SettingVariable `key:"dockerMaxConcurrentDiskUsage,envOverride" meta:"label=Max Concurrent Disk Usage Calls;type=number;keywords=docker,disk,usage,df,concurrency,limit,daemon,performance;category=internal;description=Maximum Docker disk usage calls running at once, 0 for unlimited (default: 1)"`
+	DockerMaxConcurrentExecs     SettingVariable `key:"dockerMaxConcurrentExecs,envOverride" meta:"label=Max Concurrent Docker Execs;type=number;keywords=docker,exec,concurrency,limit,helper,scan,daemon,performance;category=internal;description=Maximum background exec sessions such as volume helpers and scans running at once, 0 for unlimited (default: 0)"`
+	DockerMaxConcurrentPulls     SettingVariable `key:"dockerMaxConcurrentPulls,envOverride" meta:"label=Max Concurrent Image Pulls;type=number;keywords=docker,image,pull,concurrency,limit,download,daemon,performance;category=internal;description=Maximum image pulls running at once, 0 for unlimited (default: 0)"`
 	MaxImageUploadSize           SettingVariable `key:"maxImageUploadSize" meta:"label=Max Image Upload Size;type=number;keywords=upload,size,limit,maximum,image,tar,file,megabytes,mb,storage;category=internal;description=Maximum size in MB for image archive uploads (default: 500)"`
+	MaxVolumeDownloadSize        SettingVariable `key:"maxVolumeDownloadSize" meta:"label=Max Volume Download Size;type=number;keywords=download,directory,folder,archive,zip,tar,volume,size,limit,megabytes,mb;category=internal;description=Maximum size in MB of a volume directory that can be downloaded as an archive, 0 for unlimited (default: 2048)"`
+	MaxBackupUploadSize          SettingVariable `key:"maxBackupUploadSize" meta:"label=Max Backup Upload Size;type=number;keywords=upload,restore,backup,volume,archive,size,limit,megabytes,mb;category=internal;description=Maximum size in MB of an uploaded volume backup archive, 0 for unlimited (default: 10240)"`
 	DockerHost                   SettingVariable `key:"dockerHost,public,envOverride" meta:"label=Docker Host;type=text;keywords=docker,host,daemon,socket,unix,remote;category=internal;description=URI for Docker daemon"`
 
 	// Security category
@@ -81,6 +111,16 @@ type Settings struct {
 	TrivyImage                      SettingVariable `key:"trivyImage,envOverride" meta:"label=Trivy Image;type=text;keywords=trivy,scanner,vulnerability,security,image;category=security;description=Override the Trivy image used for vulnerability scans"`
 	TrivyConfig                     SettingVariable `key:"trivyConfig" meta:"label=Trivy Config (YAML);type=textarea;keywords=trivy,config,yaml,configuration,scanner,settings;category=security;description=Trivy configuration file content in YAML format"`
 	TrivyIgnore                     SettingVariable `key:"trivyIgnore" meta:"label=.trivyignore;type=textarea;keywords=trivy,ignore,ignorefile,vulnerabilities,exceptions,exclusions;category=security;description=Trivy ignore file content - one vulnerability ID per line"`
+	LicenseDenylist                 SettingVariable `key:"licenseDenylist" meta:"label=License Denylist;type=text;keywords=license,licence,denylist,blocklist,compliance,agpl,gpl,sbom,trivy;category=security;description=Comma-separated license patterns to flag in scanned images, e.g. AGPL-*,SSPL-1.0"`
+	VulnerabilityScanOnPull         SettingVariable `key:"vulnerabilityScanOnPull" meta:"label=Scan Images on Pull;type=boolean;keywords=vulnerability,scan,pull,trivy,automatic,cve,security;category=security;description=Scan every image for vulnerabilities after it is pulled, by a user or the auto-updater"`
+	VulnerabilityScanGateSeverity   SettingVariable `key:"vulnerabilityScanGateSeverity" meta:"label=Scan Gate Severity;type=select;keywords=vulnerability,scan,gate,block,severity,critical,high,trivy,cve,security;category=security;description=Block creating containers from images with vulnerabilities of this severity or higher (CRITICAL, HIGH, MEDIUM or LOW); empty turns the gate off"`
+	VulnerabilityScanGateThreshold  SettingVariable `key:"vulnerabilityScanGateThreshold" meta:"label=Scan Gate Threshold;type=number;keywords=vulnerability,scan,gate,block,threshold,count,trivy,cve,security;category=security;description=How many vulnerabilities at or above the gate severity an image may have before the gate blocks it"`
+	VulnerabilityDigestEnabled      SettingVariable `key:"vulnerabilityDigestEnabled" meta:"label=Vulnerability Digest;type=boolean;keywords=vulnerability,digest,report,summary,weekly,notification,cve,security;category=security;description=Periodically send a digest of new critical vulnerabilities, the most affected images and fixable counts through the notification providers (default: false)"`
+	VulnerabilityDigestInterval     SettingVariable `key:"vulnerabilityDigestInterval" meta:"label=Vulnerability Digest Interval;type=cron;keywords=vulnerability,digest,report,interval,schedule,weekly;category=security;description=How often to send the vulnerability digest (cron expression, default: Mondays at 08:00)"`
+	VulnerabilityDigestCritical     SettingVariable `key:"vulnerabilityDigestCriticalThreshold" meta:"label=Digest Critical Threshold;type=number;keywords=vulnerability,digest,threshold,critical;category=security;description=Send the digest when at least this many critical vulnerabilities are open, 0 to ignore critical counts (default: 1)"`
+	VulnerabilityDigestHigh         SettingVariable `key:"vulnerabilityDigestHighThreshold" meta:"label=Digest High Threshold;type=number;keywords=vulnerability,digest,threshold,high;category=security;description=Send the digest when at least this many high vulnerabilities are open, 0 to ignore high counts (default: 0)"`
+	VulnerabilityDigestMedium       SettingVariable `key:"vulnerabilityDigestMediumThreshold" meta:"label=Digest Medium Threshold;type=number;keywords=vulnerability,digest,threshold,medium;category=security;description=Send the digest when at least this many medium vulnerabilities are open, 0 to ignore medium counts (default: 0)"`
+	VulnerabilityDigestLow          SettingVariable `key:"vulnerabilityDigestLowThreshold" meta:"label=Digest Low Threshold;type=number;keywords=vulnerability,digest,threshold,low;category=security;description=Send the digest when at least this many low vulnerabilities are open, 0 to ignore low counts; with every threshold at 0 the digest is always sent (default: 0)"`
 	AuthOidcConfig                  SettingVariable `key:"authOidcConfig,sensitive,deprecated" meta:"label=OIDC Config;type=text;keywords=oidc,config,client,id,issuer,secret,oauth;category=security;description=OIDC provider configuration (deprecated - use individual fields)"`
 	OidcEnabled                     SettingVariable `key:"oidcEnabled,public,envOverride" meta:"label=OIDC Authentication;type=boolean;keywords=oidc,openid,connect,sso,oauth,external,provider,federation;category=security;description=Enable OpenID Connect (OIDC) authentication"`
 	OidcClientId                    SettingVariable `key:"oidcClientId,public,envOverride" meta:"label=OIDC Client ID;type=text;keywords=oidc,client,id,oauth,openid;category=security;description=OIDC provider client ID"`
@@ -99,6 +139,14 @@ type Settings struct {
 	OidcMergeAccounts               SettingVariable `key:"oidcMergeAccounts,public,envOverride" meta:"label=OIDC Account Merging;type=boolean;keywords=oidc,merge,link,accounts,email,match,existing,users,combine;category=security;description=Allow OIDC logins to merge with existing accounts by email"`
 	OidcProviderName                SettingVariable `key:"oidcProviderName,public,envOverride" meta:"label=OIDC Provider Name;type=text;keywords=oidc,provider,name,display,label,sso;category=security;description=Custom name for the OIDC provider (e.g., Authentik, Keycloak)"`
 	OidcProviderLogoUrl             SettingVariable `key:"oidcProviderLogoUrl,public,envOverride" meta:"label=OIDC Provider Logo URL;type=text;keywords=oidc,provider,logo,url,image,icon,sso;category=security;description=Custom logo URL for the OIDC provider"`
+	FeatureVolumeBrowserEnabled     SettingVariable `key:"featureVolumeBrowserEnabled,public" meta:"label=Volume File Browser;type=boolean;keywords=feature,flag,volume,browser,files,disable,security;category=security;description=Allow browsing and editing files in volumes; can be overridden per environment (default: true)"`
+	FeatureContainerExecEnabled     SettingVariable `key:"featureContainerExecEnabled,public" meta:"label=Container Terminal;type=boolean;keywords=feature,flag,exec,terminal,shell,console,disable,security;category=security;description=Allow opening exec terminals in containers; can be overridden per environment (default: true)"`
+	FeaturePruneEnabled             SettingVariable `key:"featurePruneEnabled,public" meta:"label=Pruning;type=boolean;keywords=feature,flag,prune,cleanup,delete,disable,security;category=security;description=Allow manual and scheduled pruning of Docker resources; can be overridden per environment (default: true)"`
+	FeatureCheckpointEnabled        SettingVariable `key:"featureCheckpointEnabled,public" meta:"label=Container Checkpoints (Experimental);type=boolean;keywords=feature,flag,checkpoint,restore,criu,migrate,experimental;category=security;description=Allow checkpointing and restoring containers with CRIU; needs an experimental Docker daemon and can be overridden per environment (default: false)"`
+	ApprovalWorkflowEnabled         SettingVariable `key:"approvalWorkflowEnabled,public" meta:"label=Require Approvals;type=boolean;keywords=approval,two-person,four-eyes,review,production,prune,restore,security;category=security;description=Require a second admin to approve prunes and volume restores on environments marked as requiring approval (default: false)"`
+	ApprovalRequestTTL              SettingVariable `key:"approvalRequestTtl" meta:"label=Approval Request TTL;type=number;keywords=approval,ttl,expiry,timeout,minutes,security;category=security;description=Minutes an approval request stays valid, both for approval and for executing the approved action (default: 60)"`
+	ExecAuditTranscriptEnabled      SettingVariable `key:"execAuditTranscriptEnabled" meta:"label=Record Terminal Transcripts;type=boolean;keywords=exec,terminal,shell,audit,transcript,keystroke,recording,compliance,security;category=security;description=Record the keystrokes and output of exec terminal sessions in their audit events (default: false)"`
+	ExecAuditTranscriptMaxKB        SettingVariable `key:"execAuditTranscriptMaxKb" meta:"label=Terminal Transcript Limit;type=number;keywords=exec,terminal,audit,transcript,size,limit,kb,compliance,security;category=security;description=Maximum size of a recorded terminal transcript in KB; anything beyond it is dropped (default: 64)"`
 
 	// Appearance category
 	MobileNavigationMode       SettingVariable `key:"mobileNavigationMode,public,local" meta:"label=Mobile Navigation Mode;type=select;keywords=mode,style,type,floating,docked,position,layout,design,appearance,bottom;category=appearance;description=Choose between floating or docked navigation on mobile" catmeta:"id=appearance;title=Appearance;icon=appearance;url=/settings/appearance;description=Customize navigation, theme, and interface behavior"`
@@ -125,6 +173,9 @@ type Settings struct {
 	HTTPClientTimeout      SettingVariable `key:"httpClientTimeout,envOverride" meta:"label=HTTP Client Timeout;type=number;keywords=http,client,timeout,seconds,api,request;category=timeouts;description=Default timeout for HTTP requests in seconds (default: 30)"`
 	RegistryTimeout        SettingVariable `key:"registryTimeout,envOverride" meta:"label=Registry Timeout;type=number;keywords=registry,timeout,seconds,docker,auth;category=timeouts;description=Timeout for container registry operations in seconds (default: 30)"`
 	ProxyRequestTimeout    SettingVariable `key:"proxyRequestTimeout,envOverride" meta:"label=Proxy Request Timeout;type=number;keywords=proxy,request,timeout,seconds,forward;category=timeouts;description=Timeout for proxied requests in seconds (default: 60)"`
+	HelperExecTimeout      SettingVariable `key:"helperExecTimeout,envOverride" meta:"label=Helper Exec Timeout;type=number;keywords=helper,exec,volume,file,timeout,seconds;category=timeouts;description=Timeout for quick file operations in volume helper containers in seconds (default: 60)"`
+	HelperScanTimeout      SettingVariable `key:"helperScanTimeout,envOverride" meta:"label=Helper Scan Timeout;type=number;keywords=helper,find,du,scan,volume,timeout,seconds;category=timeouts;description=Timeout for directory listings and disk usage scans in volume helper containers in seconds (default: 600 = 10 minutes)"`
+	HelperArchiveTimeout   SettingVariable `key:"helperArchiveTimeout,envOverride" meta:"label=Helper Archive Timeout;type=number;keywords=helper,tar,archive,restore,volume,timeout,seconds;category=timeouts;description=Timeout for archive extraction and restores in volume helper containers in seconds (default: 7200 = 2 hours)"`
 }
 
 func (SettingVariable) TableName() string {
@@ -0,0 +1,25 @@
+package models
+
+import (
+	"time"
+
+	"github.com/getarcaneapp/arcane/types/updater"
+)
+
+// UpdateRollout is a staged auto-update run by the manager across its
+// environments, starting with the canary environment.
+type UpdateRollout struct {
+	Status              string                  `json:"status" gorm:"column:status;index"`
+	CanaryEnvironmentID string                  `json:"canaryEnvironmentId" gorm:"column:canary_environment_id"`
+	Images              StringSlice             `json:"images" gorm:"column:images;type:text"`
+	CanaryContainers    StringSlice             `json:"canaryContainers" gorm:"column:canary_containers;type:text"`
+	SoakUntil           *time.Time              `json:"soakUntil,omitempty" gorm:"column:soak_until"`
+	Targets             []updater.RolloutTarget `json:"targets,omitempty" gorm:"column:targets;type:text;serializer:json"`
+	Error               string                  `json:"error,omitempty" gorm:"column:error"`
+	CompletedAt         *time.Time              `json:"completedAt,omitempty" gorm:"column:completed_at"`
+	BaseModel
+}
+
+func (UpdateRollout) TableName() string {
+	return "update_rollouts"
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+// UptimeTransition records that a container or project went up or down at a
+// point in time. Uptime is derived from consecutive transitions.
+type UptimeTransition struct {
+	ResourceType string    `json:"resourceType" gorm:"column:resource_type"`
+	ResourceKey  string    `json:"resourceKey" gorm:"column:resource_key"`
+	Up           bool      `json:"up" gorm:"column:up"`
+	At           time.Time `json:"at" gorm:"column:at"`
+	BaseModel
+}
+
+func (UptimeTransition) TableName() string {
+	return "uptime_transitions"
+}
@@ -6,22 +6,46 @@ import (
 	"github.com/getarcaneapp/arcane/types/volume"
 )
 
+const (
+	// VolumeBackupDriverTar stores the volume as a tar.gz archive in the backup volume.
+	VolumeBackupDriverTar = "tar"
+	// VolumeBackupDriverZFS stores the volume as a ZFS snapshot of its dataset.
+	VolumeBackupDriverZFS = "zfs"
+	// VolumeBackupDriverBtrfs stores the volume as a read-only Btrfs subvolume snapshot.
+	VolumeBackupDriverBtrfs = "btrfs"
+)
+
 type VolumeBackup struct {
 	BaseModel
-	VolumeName string    `json:"volumeName" gorm:"column:volume_name;index"`
-	Size       int64     `json:"size" gorm:"column:size"`
-	CreatedAt  time.Time `json:"createdAt" gorm:"column:created_at"`
+	VolumeName string `json:"volumeName" gorm:"column:volume_name;index"`
+	Size       int64  `json:"size" gorm:"column:size"`
+	Driver     string `json:"driver" gorm:"column:driver;default:tar"`
+	// SnapshotRef is the ZFS snapshot name or Btrfs snapshot subvolume path.
+	SnapshotRef *string `json:"snapshotRef,omitempty" gorm:"column:snapshot_ref"`
+	// SnapshotPath is the host path holding the snapshot's copy of the volume data.
+	SnapshotPath *string   `json:"snapshotPath,omitempty" gorm:"column:snapshot_path"`
+	CreatedAt    time.Time `json:"createdAt" gorm:"column:created_at"`
 }
 
 func (*VolumeBackup) TableName() string {
 	return "volume_backups"
 }
 
+// IsSnapshot reports whether the backup is a filesystem snapshot rather than a tar archive.
+func (b *VolumeBackup) IsSnapshot() bool {
+	return b.Driver == VolumeBackupDriverZFS || b.Driver == VolumeBackupDriverBtrfs
+}
+
 func (b *VolumeBackup) ToDTO() volume.BackupEntry {
+	driver := b.Driver
+	if driver == "" {
+		driver = VolumeBackupDriverTar
+	}
 	return volume.BackupEntry{
 		ID:         b.ID,
 		VolumeName: b.VolumeName,
 		Size:       b.Size,
+		Driver:     driver,
 		CreatedAt:  b.CreatedAt.Format(time.RFC3339),
 	}
 }
@@ -0,0 +1,19 @@
+package models
+
+// VulnerabilityDigest records a vulnerability digest that was sent, so the
+// next digest can report the critical vulnerabilities found since.
+type VulnerabilityDigest struct {
+	// CriticalKeys identifies every critical vulnerability the digest
+	// covered, as "imageID:vulnerabilityID:package:version".
+	CriticalKeys StringSlice `json:"criticalKeys" gorm:"column:critical_keys;type:text"`
+	Critical     int         `json:"critical" gorm:"column:critical"`
+	High         int         `json:"high" gorm:"column:high"`
+	Medium       int         `json:"medium" gorm:"column:medium"`
+	Low          int         `json:"low" gorm:"column:low"`
+	Fixable      int         `json:"fixable" gorm:"column:fixable"`
+	BaseModel
+}
+
+func (VulnerabilityDigest) TableName() string {
+	return "vulnerability_digests"
+}
@@ -15,7 +15,11 @@ type VulnerabilityIgnore struct {
 	// EnvironmentID is the environment where this ignore applies
 	EnvironmentID string `json:"environmentId" gorm:"column:environment_id;index"`
 
-	// ImageID is the Docker image ID
+	// Scope is the kind of rule: exact, cve or package. An empty scope is
+	// treated as exact.
+	Scope string `json:"scope" gorm:"column:scope;default:exact"`
+
+	// ImageID is the Docker image ID, empty for cve and package rules
 	ImageID string `json:"imageId" gorm:"column:image_id;index"`
 
 	// VulnerabilityID is the CVE or vulnerability identifier (e.g., CVE-2023-1234)
@@ -35,6 +39,9 @@ type VulnerabilityIgnore struct {
 
 	// CreatedAt is when this ignore record was created
 	CreatedAt time.Time `json:"createdAt" gorm:"column:created_at"`
+
+	// ExpiresAt is when this ignore record stops applying, if ever
+	ExpiresAt *time.Time `json:"expiresAt,omitempty" gorm:"column:expires_at;index"`
 }
 
 func (v *VulnerabilityIgnore) TableName() string {
@@ -55,5 +62,39 @@ func (v *VulnerabilityIgnore) BeforeCreate(db *gorm.DB) error {
 // VulnerabilityIgnoreCompositeKey generates a composite key for deduplication
 // This helps prevent duplicate ignore records for the same vulnerability
 func (v *VulnerabilityIgnore) CompositeKey() string {
-	return v.EnvironmentID + ":" + v.ImageID + ":" + v.VulnerabilityID + ":" + v.PkgName + ":" + v.InstalledVersion
+	return v.EnvironmentID + ":" + v.Scope + ":" + v.ImageID + ":" + v.VulnerabilityID + ":" + v.PkgName + ":" + v.InstalledVersion
+}
+
+// VulnerabilityIgnoreAudit records the creation, removal or expiry of an
+// ignore record. Entries outlive the record they describe.
+type VulnerabilityIgnoreAudit struct {
+	ID               string     `json:"id" gorm:"primaryKey;type:text"`
+	IgnoreID         string     `json:"ignoreId" gorm:"column:ignore_id;index"`
+	EnvironmentID    string     `json:"environmentId" gorm:"column:environment_id;index"`
+	Action           string     `json:"action" gorm:"column:action"`
+	Scope            string     `json:"scope" gorm:"column:scope"`
+	ImageID          string     `json:"imageId" gorm:"column:image_id"`
+	VulnerabilityID  string     `json:"vulnerabilityId" gorm:"column:vulnerability_id"`
+	PkgName          string     `json:"pkgName" gorm:"column:pkg_name"`
+	InstalledVersion string     `json:"installedVersion" gorm:"column:installed_version"`
+	Reason           *string    `json:"reason,omitempty" gorm:"column:reason"`
+	ExpiresAt        *time.Time `json:"expiresAt,omitempty" gorm:"column:expires_at"`
+	UserID           string     `json:"userId" gorm:"column:user_id"`
+	Username         string     `json:"username" gorm:"column:username"`
+	CreatedAt        time.Time  `json:"createdAt" gorm:"column:created_at"`
+}
+
+func (v *VulnerabilityIgnoreAudit) TableName() string {
+	return "vulnerability_ignore_audits"
+}
+
+// BeforeCreate sets the ID and CreatedAt before inserting
+func (v *VulnerabilityIgnoreAudit) BeforeCreate(db *gorm.DB) error {
+	if v.ID == "" {
+		v.ID = uuid.New().String()
+	}
+	if v.CreatedAt.IsZero() {
+		v.CreatedAt = time.Now()
+	}
+	return nil
 }
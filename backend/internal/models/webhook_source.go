@@ -0,0 +1,25 @@
+package models
+
+import "time"
+
+// WebhookSource is an external system, such as a CI pipeline or a container
+// registry, that posts events to Arcane with its own token. A source can
+// carry a rule that runs when one of its events matches.
+type WebhookSource struct {
+	Name           string     `json:"name" gorm:"column:name"`
+	Type           string     `json:"type" gorm:"column:type"`
+	TokenHash      string     `json:"-" gorm:"column:token_hash;uniqueIndex"`
+	TokenPrefix    string     `json:"tokenPrefix" gorm:"column:token_prefix"`
+	Action         string     `json:"action" gorm:"column:action"`
+	ProjectID      *string    `json:"projectId,omitempty" gorm:"column:project_id"`
+	ImageFilter    string     `json:"imageFilter" gorm:"column:image_filter"`
+	Enabled        bool       `json:"enabled" gorm:"column:enabled"`
+	LastEvent      string     `json:"lastEvent" gorm:"column:last_event"`
+	LastReceivedAt *time.Time `json:"lastReceivedAt,omitempty" gorm:"column:last_received_at"`
+	UpdatedBy      string     `json:"updatedBy" gorm:"column:updated_by"`
+	BaseModel
+}
+
+func (WebhookSource) TableName() string {
+	return "webhook_sources"
+}
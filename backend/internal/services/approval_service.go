@@ -0,0 +1,338 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/types/approval"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrApprovalNotFound    = errors.New("approval request not found")
+	ErrApprovalNotPending  = errors.New("approval request is not pending")
+	ErrApprovalExpired     = errors.New("approval request has expired")
+	ErrApprovalSelfApprove = errors.New("approval requests must be decided by a different admin")
+	ErrApprovalInvalid     = errors.New("approval is not valid for this action")
+)
+
+const defaultApprovalTTL = 60 * time.Minute
+
+// ApprovalService implements the two-person approval workflow. A gated action
+// creates a pending request; once a different admin approves it, the
+// requester can execute the action exactly once before the request expires.
+type ApprovalService struct {
+	db                 *database.DB
+	settingsService    *SettingsService
+	environmentService *EnvironmentService
+	eventService       *EventService
+	now                func() time.Time
+}
+
+func NewApprovalService(db *database.DB, settingsService *SettingsService, environmentService *EnvironmentService, eventService *EventService) *ApprovalService {
+	return &ApprovalService{
+		db:                 db,
+		settingsService:    settingsService,
+		environmentService: environmentService,
+		eventService:       eventService,
+		now:                time.Now,
+	}
+}
+
+// RequiresApproval reports whether destructive actions on the environment
+// must be approved first.
+func (s *ApprovalService) RequiresApproval(ctx context.Context, environmentID string) bool {
+	if !s.settingsService.GetBoolSetting(ctx, "approvalWorkflowEnabled", false) {
+		return false
+	}
+	env, err := s.environmentService.GetEnvironmentByID(ctx, environmentID)
+	if err != nil || env == nil {
+		return false
+	}
+	return env.ProductionGuardrails()
+}
+
+func (s *ApprovalService) ttlInternal(ctx context.Context) time.Duration {
+	minutes := s.settingsService.GetIntSetting(ctx, "approvalRequestTtl", 60)
+	if minutes <= 0 {
+		return defaultApprovalTTL
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// ApprovalOperation is the exact request an approval covers.
+type ApprovalOperation struct {
+	Method string
+	Path   string
+	Query  string
+	Body   []byte
+}
+
+// payloadHash covers the query and body, so an approval for one set of
+// options cannot be replayed with another.
+func (op ApprovalOperation) payloadHash() string {
+	h := sha256.New()
+	h.Write([]byte(op.Query))
+	h.Write([]byte{0})
+	h.Write(op.Body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CreateRequest records a pending approval request for the action. An
+// existing pending request from the same user for the same operation is
+// returned instead of creating a duplicate.
+func (s *ApprovalService) CreateRequest(ctx context.Context, action, environmentID string, op ApprovalOperation, user models.User) (*models.ApprovalRequest, error) {
+	hash := op.payloadHash()
+
+	var existing models.ApprovalRequest
+	err := s.db.WithContext(ctx).
+		Where("status = ? AND requested_by = ? AND environment_id = ? AND method = ? AND path = ? AND payload_hash = ? AND expires_at > ?",
+			models.ApprovalStatusPending, user.ID, environmentID, op.Method, op.Path, hash, s.now()).
+		First(&existing).Error
+	if err == nil {
+		return &existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to look up approval requests: %w", err)
+	}
+
+	req := &models.ApprovalRequest{
+		Action:          action,
+		EnvironmentID:   environmentID,
+		Method:          op.Method,
+		Path:            op.Path,
+		Query:           op.Query,
+		Body:            string(op.Body),
+		PayloadHash:     hash,
+		Status:          models.ApprovalStatusPending,
+		RequestedBy:     user.ID,
+		RequestedByName: user.Username,
+		ExpiresAt:       s.now().Add(s.ttlInternal(ctx)),
+	}
+	if err := s.db.WithContext(ctx).Create(req).Error; err != nil {
+		return nil, fmt.Errorf("failed to create approval request: %w", err)
+	}
+
+	s.logEventInternal(ctx, models.EventTypeApprovalRequested, req, user.ID, user.Username)
+	return req, nil
+}
+
+// ListRequests returns approval requests, newest first, optionally filtered
+// by status. Pending requests past their deadline are marked expired first.
+func (s *ApprovalService) ListRequests(ctx context.Context, status string) ([]approval.Request, error) {
+	if err := s.expireStaleInternal(ctx); err != nil {
+		return nil, err
+	}
+
+	q := s.db.WithContext(ctx).Order("created_at DESC")
+	if status != "" {
+		q = q.Where("status = ?", status)
+	}
+
+	var rows []models.ApprovalRequest
+	if err := q.Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list approval requests: %w", err)
+	}
+
+	out := make([]approval.Request, 0, len(rows))
+	for i := range rows {
+		out = append(out, toApprovalDto(&rows[i]))
+	}
+	return out, nil
+}
+
+// Approve approves a pending request. The approver must not be the requester.
+// The approved request stays valid for one TTL period from now.
+func (s *ApprovalService) Approve(ctx context.Context, id string, approver models.User, reason *string) (*approval.Request, error) {
+	return s.decideInternal(ctx, id, approver, reason, models.ApprovalStatusApproved)
+}
+
+// Reject rejects a pending request.
+func (s *ApprovalService) Reject(ctx context.Context, id string, approver models.User, reason *string) (*approval.Request, error) {
+	return s.decideInternal(ctx, id, approver, reason, models.ApprovalStatusRejected)
+}
+
+func (s *ApprovalService) decideInternal(ctx context.Context, id string, approver models.User, reason *string, status models.ApprovalStatus) (*approval.Request, error) {
+	req, err := s.getInternal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if req.Status != models.ApprovalStatusPending {
+		return nil, ErrApprovalNotPending
+	}
+	now := s.now()
+	if req.IsExpired(now) {
+		_ = s.setStatusInternal(ctx, req.ID, models.ApprovalStatusPending, map[string]any{"status": models.ApprovalStatusExpired})
+		return nil, ErrApprovalExpired
+	}
+	if req.RequestedBy == approver.ID {
+		return nil, ErrApprovalSelfApprove
+	}
+
+	updates := map[string]any{
+		"status":          status,
+		"decided_by":      approver.ID,
+		"decided_by_name": approver.Username,
+		"decided_at":      now,
+		"reason":          reason,
+	}
+	if status == models.ApprovalStatusApproved {
+		updates["expires_at"] = now.Add(s.ttlInternal(ctx))
+	}
+	if err := s.setStatusInternal(ctx, req.ID, models.ApprovalStatusPending, updates); err != nil {
+		return nil, err
+	}
+
+	req, err = s.getInternal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	eventType := models.EventTypeApprovalApproved
+	if status == models.ApprovalStatusRejected {
+		eventType = models.EventTypeApprovalRejected
+	}
+	s.logEventInternal(ctx, eventType, req, approver.ID, approver.Username)
+
+	dto := toApprovalDto(req)
+	return &dto, nil
+}
+
+// Consume marks an approved request as executed. It fails unless the request
+// was approved for exactly this operation, including its query and body, by
+// someone else, for this user, and has not expired or been used before.
+func (s *ApprovalService) Consume(ctx context.Context, id, environmentID string, op ApprovalOperation, user models.User) error {
+	req, err := s.getInternal(ctx, id)
+	if err != nil {
+		return err
+	}
+	if req.Status != models.ApprovalStatusApproved ||
+		req.RequestedBy != user.ID ||
+		req.EnvironmentID != environmentID ||
+		req.Method != op.Method ||
+		req.Path != op.Path ||
+		req.PayloadHash != op.payloadHash() {
+		return ErrApprovalInvalid
+	}
+	now := s.now()
+	if req.IsExpired(now) {
+		_ = s.setStatusInternal(ctx, req.ID, models.ApprovalStatusApproved, map[string]any{"status": models.ApprovalStatusExpired})
+		return ErrApprovalExpired
+	}
+
+	if err := s.setStatusInternal(ctx, req.ID, models.ApprovalStatusApproved, map[string]any{
+		"status":      models.ApprovalStatusConsumed,
+		"consumed_at": now,
+	}); err != nil {
+		return err
+	}
+
+	s.logEventInternal(ctx, models.EventTypeApprovalExecuted, req, user.ID, user.Username)
+	return nil
+}
+
+func (s *ApprovalService) getInternal(ctx context.Context, id string) (*models.ApprovalRequest, error) {
+	var req models.ApprovalRequest
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&req).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrApprovalNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load approval request: %w", err)
+	}
+	return &req, nil
+}
+
+// setStatusInternal applies updates only if the request is still in the
+// expected status, so concurrent decisions or executions cannot both succeed.
+func (s *ApprovalService) setStatusInternal(ctx context.Context, id string, expected models.ApprovalStatus, updates map[string]any) error {
+	updates["updated_at"] = s.now()
+	result := s.db.WithContext(ctx).Model(&models.ApprovalRequest{}).
+		Where("id = ? AND status = ?", id, expected).
+		Updates(updates)
+	if result.Error != nil {
+		return fmt.Errorf("failed to update approval request: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrApprovalNotPending
+	}
+	return nil
+}
+
+func (s *ApprovalService) expireStaleInternal(ctx context.Context) error {
+	now := s.now()
+	err := s.db.WithContext(ctx).Model(&models.ApprovalRequest{}).
+		Where("status IN ? AND expires_at < ?", []models.ApprovalStatus{models.ApprovalStatusPending, models.ApprovalStatusApproved}, now).
+		Updates(map[string]any{"status": models.ApprovalStatusExpired, "updated_at": now}).Error
+	if err != nil {
+		return fmt.Errorf("failed to expire approval requests: %w", err)
+	}
+	return nil
+}
+
+func (s *ApprovalService) logEventInternal(ctx context.Context, eventType models.EventType, req *models.ApprovalRequest, userID, username string) {
+	if s.eventService == nil {
+		return
+	}
+
+	resourceType := "approval"
+	title := s.eventService.generateEventTitle(eventType, req.Action)
+	description := s.eventService.generateEventDescription(eventType, resourceType, req.Action)
+	metadata := models.JSON{
+		"action":      req.Action,
+		"method":      req.Method,
+		"path":        req.Path,
+		"query":       req.Query,
+		"body":        req.Body,
+		"requestedBy": req.RequestedByName,
+		"status":      string(req.Status),
+	}
+	if req.DecidedByName != nil {
+		metadata["decidedBy"] = *req.DecidedByName
+	}
+	if req.Reason != nil {
+		metadata["reason"] = *req.Reason
+	}
+
+	_, _ = s.eventService.CreateEvent(ctx, CreateEventRequest{
+		Type:          eventType,
+		Severity:      s.eventService.getEventSeverity(eventType),
+		Title:         title,
+		Description:   description,
+		ResourceType:  &resourceType,
+		ResourceID:    &req.ID,
+		ResourceName:  &req.Action,
+		UserID:        &userID,
+		Username:      &username,
+		EnvironmentID: &req.EnvironmentID,
+		Metadata:      metadata,
+	})
+}
+
+func toApprovalDto(r *models.ApprovalRequest) approval.Request {
+	return approval.Request{
+		ID:              r.ID,
+		Action:          r.Action,
+		EnvironmentID:   r.EnvironmentID,
+		Method:          r.Method,
+		Path:            r.Path,
+		Query:           r.Query,
+		Body:            r.Body,
+		Status:          string(r.Status),
+		RequestedBy:     r.RequestedBy,
+		RequestedByName: r.RequestedByName,
+		DecidedBy:       r.DecidedBy,
+		DecidedByName:   r.DecidedByName,
+		DecidedAt:       r.DecidedAt,
+		Reason:          r.Reason,
+		ExpiresAt:       r.ExpiresAt,
+		ConsumedAt:      r.ConsumedAt,
+		CreatedAt:       r.CreatedAt,
+	}
+}
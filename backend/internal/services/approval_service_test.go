@@ -0,0 +1,110 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+func setupApprovalTestService(t *testing.T) *ApprovalService {
+	t.Helper()
+	ctx := context.Background()
+
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.SettingVariable{}, &models.Environment{}, &models.ApprovalRequest{}))
+	db := &database.DB{DB: gdb}
+
+	settingsService, err := NewSettingsService(ctx, db)
+	require.NoError(t, err)
+	require.NoError(t, settingsService.EnsureDefaultSettings(ctx))
+	require.NoError(t, settingsService.SetBoolSetting(ctx, "approvalWorkflowEnabled", true))
+
+	require.NoError(t, gdb.Create(&models.Environment{BaseModel: models.BaseModel{ID: "0"}, Name: "local", RequireApproval: true}).Error)
+	require.NoError(t, gdb.Create(&models.Environment{BaseModel: models.BaseModel{ID: "1"}, Name: "dev"}).Error)
+	require.NoError(t, gdb.Create(&models.Environment{BaseModel: models.BaseModel{ID: "2"}, Name: "prod", Production: true}).Error)
+
+	environmentService := NewEnvironmentService(db, nil, nil, nil, settingsService)
+	return NewApprovalService(db, settingsService, environmentService, nil)
+}
+
+func TestApprovalService_RequiresApproval(t *testing.T) {
+	svc := setupApprovalTestService(t)
+	ctx := context.Background()
+
+	assert.True(t, svc.RequiresApproval(ctx, "0"))
+	assert.False(t, svc.RequiresApproval(ctx, "1"))
+	assert.True(t, svc.RequiresApproval(ctx, "2"), "production environments get approval guardrails")
+	assert.False(t, svc.RequiresApproval(ctx, "missing"))
+}
+
+func TestApprovalService_Workflow(t *testing.T) {
+	svc := setupApprovalTestService(t)
+	ctx := context.Background()
+
+	requester := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "alice"}
+	approver := models.User{BaseModel: models.BaseModel{ID: "u2"}, Username: "bob"}
+	op := ApprovalOperation{Method: "POST", Path: "/api/environments/0/system/prune", Body: []byte(`{"types":["images"]}`)}
+
+	req, err := svc.CreateRequest(ctx, models.ApprovalActionPrune, "0", op, requester)
+	require.NoError(t, err)
+	assert.Equal(t, models.ApprovalStatusPending, req.Status)
+	assert.JSONEq(t, `{"types":["images"]}`, req.Body, "approvers see the body they approve")
+
+	again, err := svc.CreateRequest(ctx, models.ApprovalActionPrune, "0", op, requester)
+	require.NoError(t, err)
+	assert.Equal(t, req.ID, again.ID, "pending request should be reused")
+
+	require.ErrorIs(t, svc.Consume(ctx, req.ID, "0", op, requester), ErrApprovalInvalid)
+
+	_, err = svc.Approve(ctx, req.ID, requester, nil)
+	require.ErrorIs(t, err, ErrApprovalSelfApprove)
+
+	approved, err := svc.Approve(ctx, req.ID, approver, nil)
+	require.NoError(t, err)
+	assert.Equal(t, string(models.ApprovalStatusApproved), approved.Status)
+	assert.Equal(t, "bob", *approved.DecidedByName)
+
+	otherPath := op
+	otherPath.Path = "/api/environments/0/images/prune"
+	otherBody := op
+	otherBody.Body = []byte(`{"types":["images","volumes"]}`)
+	otherQuery := op
+	otherQuery.Query = "all=true"
+
+	require.ErrorIs(t, svc.Consume(ctx, req.ID, "0", otherPath, requester), ErrApprovalInvalid)
+	require.ErrorIs(t, svc.Consume(ctx, req.ID, "0", otherBody, requester), ErrApprovalInvalid, "approval is bound to the body")
+	require.ErrorIs(t, svc.Consume(ctx, req.ID, "0", otherQuery, requester), ErrApprovalInvalid, "approval is bound to the query")
+	require.ErrorIs(t, svc.Consume(ctx, req.ID, "0", op, approver), ErrApprovalInvalid)
+	require.NoError(t, svc.Consume(ctx, req.ID, "0", op, requester))
+	require.ErrorIs(t, svc.Consume(ctx, req.ID, "0", op, requester), ErrApprovalInvalid, "approval is single use")
+}
+
+func TestApprovalService_Expiry(t *testing.T) {
+	svc := setupApprovalTestService(t)
+	ctx := context.Background()
+
+	requester := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "alice"}
+	approver := models.User{BaseModel: models.BaseModel{ID: "u2"}, Username: "bob"}
+
+	req, err := svc.CreateRequest(ctx, models.ApprovalActionVolumeRestore, "0", ApprovalOperation{Method: "POST", Path: "/api/environments/0/volumes/v/backups/b/restore"}, requester)
+	require.NoError(t, err)
+
+	svc.now = func() time.Time { return time.Now().Add(2 * time.Hour) }
+
+	_, err = svc.Approve(ctx, req.ID, approver, nil)
+	require.ErrorIs(t, err, ErrApprovalExpired)
+
+	list, err := svc.ListRequests(ctx, string(models.ApprovalStatusExpired))
+	require.NoError(t, err)
+	require.Len(t, list, 1)
+	assert.Equal(t, req.ID, list[0].ID)
+}
@@ -0,0 +1,243 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/crypto"
+	httputils "github.com/getarcaneapp/arcane/backend/internal/utils/http"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/remenv"
+	volumetypes "github.com/getarcaneapp/arcane/types/volume"
+)
+
+var (
+	ErrInvalidDownloadToken    = errors.New("download link is invalid or has expired")
+	ErrDownloadEdgeUnsupported = errors.New("edge environments do not support download links")
+	ErrDownloadBackupNotFound  = errors.New("backup not found")
+)
+
+// backupDownloadTokenTTL is how long a download link works. A link cannot be
+// revoked other than by removing its user, so it is kept short; a download
+// that has to resume later needs a new link.
+const backupDownloadTokenTTL = time.Hour
+
+// backupDownloadClaims is the payload sealed into a download token.
+type backupDownloadClaims struct {
+	EnvironmentID string `json:"e"`
+	BackupID      string `json:"b"`
+	UserID        string `json:"u"`
+	ExpiresAt     int64  `json:"x"`
+}
+
+// BackupDownloadService issues download links for volume backups and serves
+// them. Tokens are sealed with the instance encryption key, so they need no
+// storage and stop working once they expire or their user is removed. The
+// manager serves links for
+// remote environments by fetching the backup from the agent, forwarding any
+// Range request so resumed downloads stay partial end to end.
+type BackupDownloadService struct {
+	volumeService      *VolumeService
+	environmentService *EnvironmentService
+	userService        *UserService
+	namespaceService   *NamespaceService
+	httpClient         *http.Client
+}
+
+func NewBackupDownloadService(volumeService *VolumeService, environmentService *EnvironmentService, userService *UserService, namespaceService *NamespaceService) *BackupDownloadService {
+	return &BackupDownloadService{
+		volumeService:      volumeService,
+		environmentService: environmentService,
+		userService:        userService,
+		namespaceService:   namespaceService,
+		// Archives can be large; the request context bounds the download
+		// instead of a client timeout.
+		httpClient: httputils.NewHTTPClientWithTimeout(0),
+	}
+}
+
+// IssueToken returns a download link for a backup on the given environment.
+// Local backups must belong to a volume within the caller's namespace scope.
+func (s *BackupDownloadService) IssueToken(ctx context.Context, req volumetypes.BackupDownloadTokenRequest, user models.User) (*volumetypes.BackupDownloadToken, error) {
+	env, err := s.environmentService.GetEnvironmentByID(ctx, req.EnvironmentID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get environment: %w", err)
+	}
+	if env.IsEdge {
+		return nil, ErrDownloadEdgeUnsupported
+	}
+	if err := s.checkBackupInternal(ctx, env, req.BackupID); err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(backupDownloadTokenTTL).UTC().Truncate(time.Second)
+	payload, err := json.Marshal(backupDownloadClaims{
+		EnvironmentID: env.ID,
+		BackupID:      req.BackupID,
+		UserID:        user.ID,
+		ExpiresAt:     expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode download token: %w", err)
+	}
+	token, err := crypto.Encrypt(string(payload))
+	if err != nil {
+		return nil, fmt.Errorf("failed to seal download token: %w", err)
+	}
+
+	slog.InfoContext(ctx, "Issued backup download link", "environment", env.ID, "backup_id", req.BackupID, "user", user.ID, "expires_at", expiresAt)
+	return &volumetypes.BackupDownloadToken{
+		Token:     token,
+		URL:       "/volume-backups/download?token=" + url.QueryEscape(token),
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// Open resolves a download token and opens the backup it grants, honoring
+// rangeHeader and ifRange like the environment download endpoint does.
+func (s *BackupDownloadService) Open(ctx context.Context, token, rangeHeader, ifRange string) (string, *BackupDownload, error) {
+	claims, err := s.parseTokenInternal(token)
+	if err != nil {
+		return "", nil, err
+	}
+
+	// The link only works while the user who created it still exists.
+	user, err := s.userService.GetUserByID(ctx, claims.UserID)
+	if errors.Is(err, ErrUserNotFound) {
+		return "", nil, ErrInvalidDownloadToken
+	}
+	if err != nil {
+		return "", nil, err
+	}
+
+	if claims.EnvironmentID == "0" {
+		// The link is opened without a session, so the namespace scope of the
+		// user who created it is applied here.
+		scope, err := s.namespaceService.ScopeForUser(ctx, user.ID, hasRole(user.Roles, "admin"))
+		if err != nil {
+			return "", nil, err
+		}
+		ctx = WithNamespaceScope(ctx, scope)
+		dl, err := s.volumeService.OpenBackupDownload(ctx, claims.BackupID, rangeHeader, ifRange, user)
+		return claims.BackupID, dl, err
+	}
+
+	env, err := s.environmentService.GetEnvironmentByID(ctx, claims.EnvironmentID)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get environment: %w", err)
+	}
+	dl, err := s.openRemoteInternal(ctx, env, claims.BackupID, rangeHeader, ifRange)
+	return claims.BackupID, dl, err
+}
+
+// checkBackupInternal makes sure the backup exists before a link is issued
+// for it. Remote backups are probed by fetching their first byte.
+func (s *BackupDownloadService) checkBackupInternal(ctx context.Context, env *models.Environment, backupID string) error {
+	if env.ID == "0" {
+		exists, err := s.volumeService.BackupExists(ctx, backupID)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return fmt.Errorf("%w: %s", ErrDownloadBackupNotFound, backupID)
+		}
+		return nil
+	}
+
+	dl, err := s.openRemoteInternal(ctx, env, backupID, "bytes=0-0", "")
+	if err != nil {
+		return err
+	}
+	return dl.Body.Close()
+}
+
+func (s *BackupDownloadService) parseTokenInternal(token string) (*backupDownloadClaims, error) {
+	if strings.TrimSpace(token) == "" {
+		return nil, ErrInvalidDownloadToken
+	}
+	payload, err := crypto.Decrypt(token)
+	if err != nil {
+		return nil, ErrInvalidDownloadToken
+	}
+
+	var claims backupDownloadClaims
+	if err := json.Unmarshal([]byte(payload), &claims); err != nil || claims.BackupID == "" || claims.EnvironmentID == "" {
+		return nil, ErrInvalidDownloadToken
+	}
+	if time.Now().Unix() > claims.ExpiresAt {
+		return nil, ErrInvalidDownloadToken
+	}
+	return &claims, nil
+}
+
+func (s *BackupDownloadService) openRemoteInternal(ctx context.Context, env *models.Environment, backupID, rangeHeader, ifRange string) (*BackupDownload, error) {
+	targetURL := strings.TrimRight(env.ApiUrl, "/") + "/api/environments/0/volumes/backups/" + url.PathEscape(backupID) + "/download"
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create download request: %w", err)
+	}
+	if rangeHeader != "" {
+		req.Header.Set("Range", rangeHeader)
+	}
+	if ifRange != "" {
+		req.Header.Set("If-Range", ifRange)
+	}
+	remenv.SetAgentToken(req, env.AccessToken)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download backup from %s: %w", env.Name, err)
+	}
+
+	dl := &BackupDownload{Body: resp.Body, Size: resp.ContentLength, ETag: resp.Header.Get("ETag")}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return dl, nil
+	case http.StatusPartialContent:
+		byteRange, size, ok := parseContentRangeInternal(resp.Header.Get("Content-Range"))
+		if !ok {
+			_ = resp.Body.Close()
+			return nil, fmt.Errorf("%w: %s returned an invalid Content-Range", ErrTransferEnvironmentFailed, env.Name)
+		}
+		dl.Range, dl.Size = byteRange, size
+		return dl, nil
+	case http.StatusRequestedRangeNotSatisfiable:
+		_ = resp.Body.Close()
+		return nil, httputils.ErrRangeNotSatisfiable
+	default:
+		defer func() { _ = resp.Body.Close() }()
+		return nil, transferResponseErrorInternal("download backup from "+env.Name, resp)
+	}
+}
+
+// parseContentRangeInternal parses a "bytes start-end/size" Content-Range
+// header.
+func parseContentRangeInternal(header string) (*httputils.ByteRange, int64, bool) {
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return nil, 0, false
+	}
+	rangePart, sizePart, ok := strings.Cut(spec, "/")
+	if !ok {
+		return nil, 0, false
+	}
+	startStr, endStr, ok := strings.Cut(rangePart, "-")
+	if !ok {
+		return nil, 0, false
+	}
+	start, err1 := strconv.ParseInt(startStr, 10, 64)
+	end, err2 := strconv.ParseInt(endStr, 10, 64)
+	size, err3 := strconv.ParseInt(sizePart, 10, 64)
+	if err1 != nil || err2 != nil || err3 != nil || start > end || end >= size {
+		return nil, 0, false
+	}
+	return &httputils.ByteRange{Start: start, End: end}, size, true
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/config"
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/crypto"
+	httputils "github.com/getarcaneapp/arcane/backend/internal/utils/http"
+	namespacetypes "github.com/getarcaneapp/arcane/types/namespace"
+	volumetypes "github.com/getarcaneapp/arcane/types/volume"
+)
+
+func setupBackupDownloadTestService(t *testing.T, envs ...models.Environment) *BackupDownloadService {
+	t.Helper()
+	crypto.InitEncryption(&config.Config{
+		EncryptionKey: "test-encryption-key-for-testing-32bytes-min",
+		Environment:   "test",
+	})
+
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Environment{}, &models.User{}, &models.VolumeBackup{}))
+	for i := range envs {
+		require.NoError(t, gdb.Create(&envs[i]).Error)
+	}
+	require.NoError(t, gdb.Create(&models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "alice"}).Error)
+	db := &database.DB{DB: gdb}
+
+	volumeService := &VolumeService{db: db}
+	return NewBackupDownloadService(volumeService, NewEnvironmentService(db, nil, nil, nil, nil), NewUserService(db), nil)
+}
+
+func TestBackupDownloadService_RemoteRangeDownload(t *testing.T) {
+	agent := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/environments/0/volumes/backups/b1/download", r.URL.Path)
+		assert.Equal(t, "agent-token", r.Header.Get("X-Arcane-Agent-Token"))
+		w.Header().Set("ETag", `"b1-10"`)
+		if r.Header.Get("Range") == "bytes=0-0" {
+			// Existence probe while the link is issued.
+			w.Header().Set("Content-Range", "bytes 0-0/10")
+			w.WriteHeader(http.StatusPartialContent)
+			_, _ = io.WriteString(w, "0")
+			return
+		}
+		assert.Equal(t, "bytes=4-", r.Header.Get("Range"))
+		w.Header().Set("Content-Range", "bytes 4-9/10")
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = io.WriteString(w, "456789")
+	}))
+	defer agent.Close()
+
+	agentToken := "agent-token"
+	svc := setupBackupDownloadTestService(t, models.Environment{BaseModel: models.BaseModel{ID: "2"}, Name: "remote", ApiUrl: agent.URL, AccessToken: &agentToken})
+	ctx := context.Background()
+
+	token, err := svc.IssueToken(ctx, volumetypes.BackupDownloadTokenRequest{EnvironmentID: "2", BackupID: "b1"}, models.User{BaseModel: models.BaseModel{ID: "u1"}})
+	require.NoError(t, err)
+	assert.Equal(t, "/volume-backups/download?token="+url.QueryEscape(token.Token), token.URL)
+	assert.WithinDuration(t, time.Now().Add(backupDownloadTokenTTL), token.ExpiresAt, time.Minute)
+
+	backupID, dl, err := svc.Open(ctx, token.Token, "bytes=4-", "")
+	require.NoError(t, err)
+	defer dl.Body.Close()
+
+	assert.Equal(t, "b1", backupID)
+	assert.Equal(t, int64(10), dl.Size)
+	assert.Equal(t, &httputils.ByteRange{Start: 4, End: 9}, dl.Range)
+	assert.Equal(t, `"b1-10"`, dl.ETag)
+	body, err := io.ReadAll(dl.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "456789", string(body))
+}
+
+func TestBackupDownloadService_RejectsBadTokens(t *testing.T) {
+	svc := setupBackupDownloadTestService(t,
+		models.Environment{BaseModel: models.BaseModel{ID: "0"}, Name: "local", ApiUrl: "http://localhost"},
+		models.Environment{BaseModel: models.BaseModel{ID: "3"}, Name: "edge", ApiUrl: "http://edge", IsEdge: true},
+	)
+	ctx := context.Background()
+
+	_, err := svc.IssueToken(ctx, volumetypes.BackupDownloadTokenRequest{EnvironmentID: "3", BackupID: "b1"}, models.User{})
+	require.ErrorIs(t, err, ErrDownloadEdgeUnsupported)
+
+	_, err = svc.IssueToken(ctx, volumetypes.BackupDownloadTokenRequest{EnvironmentID: "0", BackupID: "missing"}, models.User{BaseModel: models.BaseModel{ID: "u1"}})
+	require.ErrorIs(t, err, ErrDownloadBackupNotFound)
+
+	_, _, err = svc.Open(ctx, "not-a-token", "", "")
+	require.ErrorIs(t, err, ErrInvalidDownloadToken)
+
+	payload, err := json.Marshal(backupDownloadClaims{EnvironmentID: "0", BackupID: "b1", ExpiresAt: time.Now().Add(-time.Minute).Unix()})
+	require.NoError(t, err)
+	expired, err := crypto.Encrypt(string(payload))
+	require.NoError(t, err)
+	_, _, err = svc.Open(ctx, expired, "", "")
+	require.ErrorIs(t, err, ErrInvalidDownloadToken)
+
+	// Links stop working once their user is removed.
+	payload, err = json.Marshal(backupDownloadClaims{EnvironmentID: "0", BackupID: "b1", UserID: "gone", ExpiresAt: time.Now().Add(time.Minute).Unix()})
+	require.NoError(t, err)
+	orphaned, err := crypto.Encrypt(string(payload))
+	require.NoError(t, err)
+	_, _, err = svc.Open(ctx, orphaned, "", "")
+	require.ErrorIs(t, err, ErrInvalidDownloadToken)
+}
+
+func TestParseContentRangeInternal(t *testing.T) {
+	r, size, ok := parseContentRangeInternal("bytes 100-199/1000")
+	require.True(t, ok)
+	assert.Equal(t, &httputils.ByteRange{Start: 100, End: 199}, r)
+	assert.Equal(t, int64(1000), size)
+
+	for _, header := range []string{"", "bytes */1000", "bytes 5-2/10", "bytes 0-10/10", "items 0-1/2"} {
+		_, _, ok := parseContentRangeInternal(header)
+		assert.False(t, ok, header)
+	}
+}
+
+func TestBackupDownloadService_ForeignNamespaceBackup(t *testing.T) {
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.TrimPrefix(r.URL.Path, "/v1.44") == "/volumes/theirs" {
+			_, _ = io.WriteString(w, `{"Name":"theirs","Labels":{"com.getarcaneapp.namespace":"team-b"}}`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+
+	svc := setupBackupDownloadTestService(t, models.Environment{BaseModel: models.BaseModel{ID: "0"}, Name: "local", ApiUrl: "http://localhost"})
+	db := svc.volumeService.db
+	require.NoError(t, db.AutoMigrate(&models.Namespace{}, &models.NamespaceMember{}, &models.Project{}))
+	require.NoError(t, db.Create(&models.VolumeBackup{BaseModel: models.BaseModel{ID: "b1"}, VolumeName: "theirs"}).Error)
+
+	namespaceService := NewNamespaceService(db, &DockerClientService{client: cli})
+	svc.namespaceService = namespaceService
+	svc.volumeService.namespaceService = namespaceService
+
+	ctx := context.Background()
+	team, err := namespaceService.CreateNamespace(ctx, namespacetypes.Create{Name: "team-a"})
+	require.NoError(t, err)
+	_, err = namespaceService.CreateNamespace(ctx, namespacetypes.Create{Name: "team-b"})
+	require.NoError(t, err)
+	_, err = namespaceService.SetMembers(ctx, team.ID, []string{"u1"})
+	require.NoError(t, err)
+
+	scope, err := namespaceService.ScopeForUser(ctx, "u1", false)
+	require.NoError(t, err)
+	scoped := WithNamespaceScope(ctx, scope)
+	user := models.User{BaseModel: models.BaseModel{ID: "u1"}}
+
+	_, err = svc.IssueToken(scoped, volumetypes.BackupDownloadTokenRequest{EnvironmentID: "0", BackupID: "b1"}, user)
+	require.ErrorIs(t, err, ErrNamespaceForbidden)
+	require.ErrorIs(t, svc.volumeService.DeleteBackup(scoped, "b1", &user), ErrNamespaceForbidden)
+
+	// A link sealed before the user lost access is checked when opened.
+	payload, err := json.Marshal(backupDownloadClaims{EnvironmentID: "0", BackupID: "b1", UserID: "u1", ExpiresAt: time.Now().Add(time.Minute).Unix()})
+	require.NoError(t, err)
+	token, err := crypto.Encrypt(string(payload))
+	require.NoError(t, err)
+	_, _, err = svc.Open(ctx, token, "", "")
+	require.ErrorIs(t, err, ErrNamespaceForbidden)
+
+	var count int64
+	require.NoError(t, db.Model(&models.VolumeBackup{}).Where("id = ?", "b1").Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
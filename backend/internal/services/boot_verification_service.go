@@ -0,0 +1,369 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/pkg/libarcane"
+	"github.com/getarcaneapp/arcane/types/system"
+	"gorm.io/gorm"
+)
+
+// hostBootTimeTolerance absorbs clock adjustments that shift the reported
+// boot time slightly without an actual reboot.
+const hostBootTimeTolerance = 30 * time.Second
+
+// BootVerificationService records which containers are expected to be running
+// and, after a Docker daemon restart or host reboot, brings back any that did
+// not come up on their own.
+type BootVerificationService struct {
+	db               *database.DB
+	dockerService    *DockerClientService
+	containerService *ContainerService
+	projectService   *ProjectService
+	eventService     *EventService
+
+	mu sync.Mutex
+}
+
+func NewBootVerificationService(db *database.DB, dockerService *DockerClientService, containerService *ContainerService, projectService *ProjectService, eventService *EventService) *BootVerificationService {
+	return &BootVerificationService{
+		db:               db,
+		dockerService:    dockerService,
+		containerService: containerService,
+		projectService:   projectService,
+		eventService:     eventService,
+	}
+}
+
+// GetSnapshot returns the last recorded runtime state, or nil if none exists.
+func (s *BootVerificationService) GetSnapshot(ctx context.Context) (*models.RuntimeStateSnapshot, error) {
+	var snapshot models.RuntimeStateSnapshot
+	err := s.db.WithContext(ctx).Where("id = ?", models.RuntimeStateSnapshotID).First(&snapshot).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load runtime state snapshot: %w", err)
+	}
+	return &snapshot, nil
+}
+
+// TakeSnapshot records the containers that are currently running.
+func (s *BootVerificationService) TakeSnapshot(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.takeSnapshotInternal(ctx)
+}
+
+func (s *BootVerificationService) takeSnapshotInternal(ctx context.Context) error {
+	current, err := s.inspectContainersInternal(ctx)
+	if err != nil {
+		return err
+	}
+
+	running := make(models.RuntimeStateContainers, 0, len(current))
+	for _, c := range current {
+		if c.running {
+			running = append(running, c.RuntimeStateContainer)
+		}
+	}
+
+	snapshot := models.RuntimeStateSnapshot{
+		ID:              models.RuntimeStateSnapshotID,
+		HostBootTime:    readHostBootTimeInternal(),
+		DaemonStartTime: s.readDaemonStartTimeInternal(ctx),
+		Containers:      running,
+		TakenAt:         time.Now(),
+	}
+	if err := s.db.WithContext(ctx).Save(&snapshot).Error; err != nil {
+		return fmt.Errorf("failed to save runtime state snapshot: %w", err)
+	}
+
+	slog.DebugContext(ctx, "boot verification: snapshot taken", "containers", len(running))
+	return nil
+}
+
+// VerifyAndSnapshot checks whether the Docker daemon restarted since the last
+// snapshot and, if so, starts the containers that should be running. A fresh
+// snapshot is taken afterwards. The returned report is nil when no restart was
+// detected.
+func (s *BootVerificationService) VerifyAndSnapshot(ctx context.Context) (*system.BootVerificationReport, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	snapshot, err := s.GetSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var report *system.BootVerificationReport
+	if snapshot != nil && len(snapshot.Containers) > 0 {
+		current, err := s.inspectContainersInternal(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if reason, restarted := detectDaemonRestart(snapshot, current, readHostBootTimeInternal(), s.readDaemonStartTimeInternal(ctx)); restarted {
+			slog.InfoContext(ctx, "boot verification: restart detected", "reason", reason, "snapshotTakenAt", snapshot.TakenAt)
+			report = s.restoreExpectedStateInternal(ctx, snapshot, current)
+			report.Reason = reason
+			s.logReportEventInternal(ctx, report)
+		}
+	}
+
+	if err := s.takeSnapshotInternal(ctx); err != nil {
+		return report, err
+	}
+	return report, nil
+}
+
+type observedContainer struct {
+	models.RuntimeStateContainer
+	running bool
+}
+
+// inspectContainersInternal returns all non-internal containers keyed by ID.
+func (s *BootVerificationService) inspectContainersInternal(ctx context.Context) (map[string]observedContainer, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	result := make(map[string]observedContainer, len(containers))
+	for _, c := range containers {
+		if libarcane.IsInternalContainer(c.Labels) {
+			continue
+		}
+
+		inspect, err := dockerClient.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			slog.WarnContext(ctx, "boot verification: failed to inspect container", "container", c.ID, "error", err)
+			continue
+		}
+		if inspect.HostConfig != nil && inspect.HostConfig.AutoRemove {
+			// One-shot containers are not expected to come back
+			continue
+		}
+
+		oc := observedContainer{
+			RuntimeStateContainer: models.RuntimeStateContainer{
+				ID:      inspect.ID,
+				Name:    strings.TrimPrefix(inspect.Name, "/"),
+				Project: c.Labels["com.docker.compose.project"],
+				Service: c.Labels["com.docker.compose.service"],
+			},
+		}
+		if inspect.HostConfig != nil {
+			oc.RestartPolicy = string(inspect.HostConfig.RestartPolicy.Name)
+		}
+		if inspect.State != nil {
+			oc.running = inspect.State.Running
+			if t, err := time.Parse(time.RFC3339Nano, inspect.State.StartedAt); err == nil {
+				oc.StartedAt = t
+			}
+		}
+		result[oc.ID] = oc
+	}
+
+	return result, nil
+}
+
+// detectDaemonRestart compares the snapshot with the current containers. A
+// restart is assumed when the host boot time or the daemon start time changed,
+// or when none of the previously running containers kept running and at least
+// one of them was started again by the daemon (restart policy). The last check
+// avoids treating containers that were stopped by hand as a restart; the daemon
+// start time catches restarts where no container has a restart policy.
+func detectDaemonRestart(snapshot *models.RuntimeStateSnapshot, current map[string]observedContainer, bootTime, daemonStart *time.Time) (string, bool) {
+	if snapshot.HostBootTime != nil && bootTime != nil && bootTime.Sub(*snapshot.HostBootTime) > hostBootTimeTolerance {
+		return "host reboot detected", true
+	}
+	if snapshot.DaemonStartTime != nil && daemonStart != nil && daemonStart.After(*snapshot.DaemonStartTime) {
+		return "Docker daemon restart detected", true
+	}
+
+	unchanged, restarted := 0, 0
+	for _, expected := range snapshot.Containers {
+		c, ok := current[expected.ID]
+		if !ok || !c.running {
+			continue
+		}
+		if c.StartedAt.Equal(expected.StartedAt) {
+			unchanged++
+		} else if c.StartedAt.After(snapshot.TakenAt) {
+			restarted++
+		}
+	}
+
+	if unchanged == 0 && restarted > 0 {
+		return "Docker daemon restart detected", true
+	}
+	return "", false
+}
+
+func (s *BootVerificationService) restoreExpectedStateInternal(ctx context.Context, snapshot *models.RuntimeStateSnapshot, current map[string]observedContainer) *system.BootVerificationReport {
+	report := &system.BootVerificationReport{
+		SnapshotTakenAt: snapshot.TakenAt,
+		Expected:        len(snapshot.Containers),
+		Started:         []string{},
+		Redeployed:      []string{},
+		Failed:          []system.BootVerificationFailure{},
+	}
+
+	missingProjects := map[string][]models.RuntimeStateContainer{}
+	var missingProjectOrder []string
+
+	for _, expected := range snapshot.Containers {
+		c, ok := current[expected.ID]
+		switch {
+		case ok && c.running:
+			report.Running++
+		case ok:
+			if err := s.containerService.StartContainer(ctx, c.ID, systemUser); err != nil {
+				report.Failed = append(report.Failed, system.BootVerificationFailure{Name: expected.Name, Project: expected.Project, Error: err.Error()})
+				continue
+			}
+			report.Started = append(report.Started, expected.Name)
+		case expected.Project != "":
+			if _, seen := missingProjects[expected.Project]; !seen {
+				missingProjectOrder = append(missingProjectOrder, expected.Project)
+			}
+			missingProjects[expected.Project] = append(missingProjects[expected.Project], expected)
+		default:
+			report.Failed = append(report.Failed, system.BootVerificationFailure{Name: expected.Name, Error: "container no longer exists"})
+		}
+	}
+
+	for _, projectName := range missingProjectOrder {
+		if err := s.redeployProjectInternal(ctx, projectName); err != nil {
+			for _, expected := range missingProjects[projectName] {
+				report.Failed = append(report.Failed, system.BootVerificationFailure{Name: expected.Name, Project: projectName, Error: err.Error()})
+			}
+			continue
+		}
+		report.Redeployed = append(report.Redeployed, projectName)
+	}
+
+	return report
+}
+
+// redeployProjectInternal deploys the Arcane project whose compose project
+// name matches projectName.
+func (s *BootVerificationService) redeployProjectInternal(ctx context.Context, projectName string) error {
+	if s.projectService == nil {
+		return fmt.Errorf("container no longer exists")
+	}
+
+	projects, err := s.projectService.ListAllProjects(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list projects: %w", err)
+	}
+	for _, p := range projects {
+		if normalizeComposeProjectName(p.Name) == projectName {
+			return s.projectService.DeployProject(ctx, p.ID, systemUser)
+		}
+	}
+	return fmt.Errorf("container no longer exists and project %s is not managed by Arcane", projectName)
+}
+
+func (s *BootVerificationService) logReportEventInternal(ctx context.Context, report *system.BootVerificationReport) {
+	if s.eventService == nil {
+		return
+	}
+
+	severity := models.EventSeveritySuccess
+	if len(report.Failed) > 0 {
+		severity = models.EventSeverityError
+	}
+
+	failed := make([]string, 0, len(report.Failed))
+	for _, f := range report.Failed {
+		failed = append(failed, f.Name)
+	}
+
+	resourceType := "system"
+	resourceName := "boot_verification"
+	environmentID := "0"
+	_, _ = s.eventService.CreateEvent(ctx, CreateEventRequest{
+		Type:     models.EventTypeSystemBootVerification,
+		Severity: severity,
+		Title:    "Post-restart verification completed",
+		Description: fmt.Sprintf("%s: %d of %d containers running, %d started, %d failed",
+			report.Reason, report.Running, report.Expected, len(report.Started), len(report.Failed)),
+		ResourceType:  &resourceType,
+		ResourceName:  &resourceName,
+		EnvironmentID: &environmentID,
+		Metadata: models.JSON{
+			"reason":     report.Reason,
+			"expected":   report.Expected,
+			"running":    report.Running,
+			"started":    report.Started,
+			"redeployed": report.Redeployed,
+			"failed":     failed,
+		},
+	})
+}
+
+// readDaemonStartTimeInternal returns when the Docker daemon last started. The
+// Engine API does not report this directly, but the daemon deletes and
+// recreates its default bridge network on every start, so that network's
+// creation time is used. It returns nil when the time is unavailable, e.g. on
+// hosts without the default bridge network.
+func (s *BootVerificationService) readDaemonStartTimeInternal(ctx context.Context) *time.Time {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil
+	}
+
+	bridge, err := dockerClient.NetworkInspect(ctx, network.NetworkBridge, network.InspectOptions{})
+	if err != nil || bridge.Created.IsZero() {
+		slog.DebugContext(ctx, "boot verification: daemon start time unavailable", "error", err)
+		return nil
+	}
+	started := bridge.Created
+	return &started
+}
+
+// readHostBootTimeInternal reads the kernel boot time from /proc/stat. Arcane
+// usually runs in a container on the Docker host, which shares the host
+// kernel. It returns nil when the boot time is unavailable.
+func readHostBootTimeInternal() *time.Time {
+	f, err := os.Open("/proc/stat")
+	if err != nil {
+		return nil
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		value, ok := strings.CutPrefix(scanner.Text(), "btime ")
+		if !ok {
+			continue
+		}
+		secs, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			return nil
+		}
+		t := time.Unix(secs, 0)
+		return &t
+	}
+	return nil
+}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectDaemonRestart(t *testing.T) {
+	takenAt := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	startedAt := takenAt.Add(-time.Hour)
+	bootTime := takenAt.Add(-24 * time.Hour)
+
+	snapshot := &models.RuntimeStateSnapshot{
+		HostBootTime: &bootTime,
+		TakenAt:      takenAt,
+		Containers: models.RuntimeStateContainers{
+			{ID: "a", Name: "web", StartedAt: startedAt},
+			{ID: "b", Name: "db", StartedAt: startedAt},
+		},
+	}
+
+	observed := func(id string, running bool, started time.Time) observedContainer {
+		return observedContainer{RuntimeStateContainer: models.RuntimeStateContainer{ID: id, StartedAt: started}, running: running}
+	}
+
+	t.Run("unchanged", func(t *testing.T) {
+		current := map[string]observedContainer{
+			"a": observed("a", true, startedAt),
+			"b": observed("b", true, startedAt),
+		}
+		_, restarted := detectDaemonRestart(snapshot, current, &bootTime, nil)
+		assert.False(t, restarted)
+	})
+
+	t.Run("host reboot", func(t *testing.T) {
+		newBoot := takenAt.Add(10 * time.Minute)
+		reason, restarted := detectDaemonRestart(snapshot, map[string]observedContainer{}, &newBoot, nil)
+		assert.True(t, restarted)
+		assert.Equal(t, "host reboot detected", reason)
+	})
+
+	t.Run("daemon restart", func(t *testing.T) {
+		current := map[string]observedContainer{
+			"a": observed("a", true, takenAt.Add(5*time.Minute)),
+			"b": observed("b", false, startedAt),
+		}
+		_, restarted := detectDaemonRestart(snapshot, current, &bootTime, nil)
+		assert.True(t, restarted)
+	})
+
+	t.Run("daemon restart without restart policies", func(t *testing.T) {
+		daemonStart := takenAt.Add(-48 * time.Hour)
+		withDaemon := *snapshot
+		withDaemon.DaemonStartTime = &daemonStart
+		current := map[string]observedContainer{
+			"a": observed("a", false, startedAt),
+			"b": observed("b", false, startedAt),
+		}
+
+		_, restarted := detectDaemonRestart(&withDaemon, current, &bootTime, &daemonStart)
+		assert.False(t, restarted)
+
+		newStart := takenAt.Add(5 * time.Minute)
+		reason, restarted := detectDaemonRestart(&withDaemon, current, &bootTime, &newStart)
+		assert.True(t, restarted)
+		assert.Equal(t, "Docker daemon restart detected", reason)
+	})
+
+	t.Run("manual stop is not a restart", func(t *testing.T) {
+		current := map[string]observedContainer{
+			"a": observed("a", false, startedAt),
+			"b": observed("b", false, startedAt),
+		}
+		_, restarted := detectDaemonRestart(snapshot, current, &bootTime, nil)
+		assert.False(t, restarted)
+	})
+
+	t.Run("one container still running", func(t *testing.T) {
+		current := map[string]observedContainer{
+			"a": observed("a", true, startedAt),
+			"b": observed("b", true, takenAt.Add(5*time.Minute)),
+		}
+		_, restarted := detectDaemonRestart(snapshot, current, nil, nil)
+		assert.False(t, restarted)
+	})
+}
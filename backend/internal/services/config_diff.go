@@ -0,0 +1,336 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+	"github.com/getarcaneapp/arcane/types/diff"
+	imagetypes "github.com/getarcaneapp/arcane/types/image"
+)
+
+// DiffContainers compares the configuration of two containers. Fields that
+// always differ between two containers, such as the ID, hostname and
+// runtime state, are left out.
+func (s *ContainerService) DiffContainers(ctx context.Context, leftID, rightID string) (*containertypes.Diff, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	inspects := make([]container.InspectResponse, 0, 2)
+	for _, id := range []string{leftID, rightID} {
+		inspect, err := dockerClient.ContainerInspect(ctx, id)
+		if err != nil {
+			if cerrdefs.IsNotFound(err) {
+				return nil, fmt.Errorf("%w: %s", ErrDockerContainerNotFound, id)
+			}
+			return nil, fmt.Errorf("failed to inspect container: %w", err)
+		}
+		inspects = append(inspects, inspect)
+	}
+
+	changes := diffConfigInternal(containerDiffViewInternal(inspects[0]), containerDiffViewInternal(inspects[1]))
+	return &containertypes.Diff{
+		Left:      containerDiffSubjectInternal(inspects[0]),
+		Right:     containerDiffSubjectInternal(inspects[1]),
+		Identical: len(changes) == 0,
+		Changes:   changes,
+	}, nil
+}
+
+// DiffImages compares the configuration, layers and build steps of two
+// images.
+func (s *ImageService) DiffImages(ctx context.Context, left, right string) (*imagetypes.Diff, error) {
+	lh, err := s.GetImageHistory(ctx, left)
+	if err != nil {
+		return nil, err
+	}
+	rh, err := s.GetImageHistory(ctx, right)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &imagetypes.Diff{
+		Left:    imagetypes.DiffSubject{ID: lh.ImageID, RepoTags: lh.RepoTags, Size: lh.Size},
+		Right:   imagetypes.DiffSubject{ID: rh.ImageID, RepoTags: rh.RepoTags, Size: rh.Size},
+		Changes: diffConfigInternal(imageDiffViewInternal(lh.Config), imageDiffViewInternal(rh.Config)),
+	}
+
+	out.RemovedLayers, out.SharedLayers = subtractOrderedInternal(lh.LayerDigests, rh.LayerDigests)
+	out.AddedLayers, _ = subtractOrderedInternal(rh.LayerDigests, lh.LayerDigests)
+	out.RemovedSteps, out.AddedSteps = diffBuildStepsInternal(lh.Layers, rh.Layers)
+
+	out.Identical = len(out.Changes) == 0 && len(out.AddedLayers) == 0 && len(out.RemovedLayers) == 0 &&
+		len(out.AddedSteps) == 0 && len(out.RemovedSteps) == 0
+	return out, nil
+}
+
+func containerDiffSubjectInternal(inspect container.InspectResponse) containertypes.DiffSubject {
+	subject := containertypes.DiffSubject{
+		ID:      inspect.ID,
+		Name:    strings.TrimPrefix(inspect.Name, "/"),
+		ImageID: inspect.Image,
+	}
+	if inspect.Config != nil {
+		subject.Image = inspect.Config.Image
+	}
+	return subject
+}
+
+// containerDiffViewInternal reduces a container to the settings a user
+// chooses. Lists whose order carries no meaning are turned into maps so
+// that they are compared by key.
+func containerDiffViewInternal(inspect container.InspectResponse) map[string]any {
+	view := map[string]any{}
+	if cfg := inspect.Config; cfg != nil {
+		view["image"] = cfg.Image
+		view["user"] = cfg.User
+		view["workingDir"] = cfg.WorkingDir
+		view["entrypoint"] = []string(cfg.Entrypoint)
+		view["cmd"] = []string(cfg.Cmd)
+		view["env"] = envMapInternal(cfg.Env)
+		view["labels"] = stringMapInternal(cfg.Labels)
+		view["stopSignal"] = cfg.StopSignal
+		if cfg.Healthcheck != nil {
+			view["healthcheck"] = cfg.Healthcheck.Test
+		}
+	}
+
+	if hc := inspect.HostConfig; hc != nil {
+		view["networkMode"] = string(hc.NetworkMode)
+		view["privileged"] = hc.Privileged
+		view["readOnlyRootfs"] = hc.ReadonlyRootfs
+		view["capAdd"] = []string(hc.CapAdd)
+		view["capDrop"] = []string(hc.CapDrop)
+		view["restartPolicy"] = restartPolicyDiffValueInternal(hc.RestartPolicy)
+
+		ports := map[string]any{}
+		for port, bindings := range hc.PortBindings {
+			hostPorts := make([]string, 0, len(bindings))
+			for _, b := range bindings {
+				if b.HostIP != "" {
+					hostPorts = append(hostPorts, b.HostIP+":"+b.HostPort)
+				} else {
+					hostPorts = append(hostPorts, b.HostPort)
+				}
+			}
+			slices.Sort(hostPorts)
+			ports[string(port)] = hostPorts
+		}
+		view["ports"] = ports
+
+		view["resources"] = map[string]any{
+			"memory":     hc.Memory,
+			"memorySwap": hc.MemorySwap,
+			"nanoCpus":   hc.NanoCPUs,
+			"cpuShares":  hc.CPUShares,
+			"cpusetCpus": hc.CpusetCpus,
+			"pidsLimit":  hc.PidsLimit,
+		}
+	}
+
+	mounts := map[string]any{}
+	for _, m := range inspect.Mounts {
+		source := m.Source
+		if m.Name != "" {
+			source = m.Name
+		}
+		value := string(m.Type) + ":" + source
+		if !m.RW {
+			value += ":ro"
+		}
+		mounts[m.Destination] = value
+	}
+	view["mounts"] = mounts
+
+	if inspect.NetworkSettings != nil {
+		networks := map[string]any{}
+		for name, ep := range inspect.NetworkSettings.Networks {
+			var aliases []string
+			if ep != nil {
+				// Docker adds the short container ID as an alias, which
+				// differs for every container.
+				for _, a := range ep.Aliases {
+					if !strings.HasPrefix(inspect.ID, a) {
+						aliases = append(aliases, a)
+					}
+				}
+				slices.Sort(aliases)
+			}
+			networks[name] = map[string]any{"aliases": aliases}
+		}
+		view["networks"] = networks
+	}
+	return view
+}
+
+func restartPolicyDiffValueInternal(p container.RestartPolicy) string {
+	if p.Name == container.RestartPolicyOnFailure && p.MaximumRetryCount > 0 {
+		return string(p.Name) + ":" + strconv.Itoa(p.MaximumRetryCount)
+	}
+	return string(p.Name)
+}
+
+func imageDiffViewInternal(cfg imagetypes.ConfigDetails) map[string]any {
+	return map[string]any{
+		"user":         cfg.User,
+		"workingDir":   cfg.WorkingDir,
+		"entrypoint":   cfg.Entrypoint,
+		"cmd":          cfg.Cmd,
+		"env":          envMapInternal(cfg.Env),
+		"labels":       stringMapInternal(cfg.Labels),
+		"exposedPorts": cfg.ExposedPorts,
+		"volumes":      cfg.Volumes,
+		"stopSignal":   cfg.StopSignal,
+		"healthcheck":  cfg.Healthcheck,
+	}
+}
+
+func envMapInternal(env []string) map[string]any {
+	out := make(map[string]any, len(env))
+	for _, kv := range env {
+		k, v, _ := strings.Cut(kv, "=")
+		out[k] = v
+	}
+	return out
+}
+
+func stringMapInternal(m map[string]string) map[string]any {
+	out := make(map[string]any, len(m))
+	for k, v := range m {
+		out[k] = v
+	}
+	return out
+}
+
+// diffConfigInternal compares two nested maps and returns their differing
+// leaves sorted by path. Empty values count as unset, so a field that is
+// empty on one side and missing on the other is not a change.
+func diffConfigInternal(left, right map[string]any) []diff.Change {
+	changes := []diff.Change{}
+	diffConfigNodeInternal("", left, right, &changes)
+	slices.SortFunc(changes, func(a, b diff.Change) int { return strings.Compare(a.Path, b.Path) })
+	return changes
+}
+
+func diffConfigNodeInternal(prefix string, left, right map[string]any, changes *[]diff.Change) {
+	keys := make(map[string]struct{}, len(left)+len(right))
+	for k := range left {
+		keys[k] = struct{}{}
+	}
+	for k := range right {
+		keys[k] = struct{}{}
+	}
+
+	for k := range keys {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		l, r := left[k], right[k]
+		lm, lok := l.(map[string]any)
+		rm, rok := r.(map[string]any)
+		if lok || rok {
+			diffConfigNodeInternal(path, lm, rm, changes)
+			continue
+		}
+
+		lEmpty, rEmpty := isEmptyDiffValueInternal(l), isEmptyDiffValueInternal(r)
+		switch {
+		case lEmpty && rEmpty:
+		case lEmpty:
+			*changes = append(*changes, diff.Change{Path: path, Kind: diff.ChangeAdded, New: r})
+		case rEmpty:
+			*changes = append(*changes, diff.Change{Path: path, Kind: diff.ChangeRemoved, Old: l})
+		case !reflect.DeepEqual(l, r):
+			*changes = append(*changes, diff.Change{Path: path, Kind: diff.ChangeModified, Old: l, New: r})
+		}
+	}
+}
+
+func isEmptyDiffValueInternal(v any) bool {
+	if v == nil {
+		return true
+	}
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String:
+		return rv.Len() == 0
+	case reflect.Pointer:
+		return rv.IsNil()
+	default:
+		return false
+	}
+}
+
+// subtractOrderedInternal returns the items of a missing from b, in the
+// order of a, and how many items of a are in b.
+func subtractOrderedInternal(a, b []string) ([]string, int) {
+	inB := make(map[string]struct{}, len(b))
+	for _, v := range b {
+		inB[v] = struct{}{}
+	}
+	missing := []string{}
+	shared := 0
+	for _, v := range a {
+		if _, ok := inB[v]; ok {
+			shared++
+		} else {
+			missing = append(missing, v)
+		}
+	}
+	return missing, shared
+}
+
+// diffBuildStepsInternal matches the build steps of two images by their
+// command and returns the unmatched steps of each side, oldest first. The
+// histories are given newest first, as Docker reports them.
+func diffBuildStepsInternal(left, right []imagetypes.HistoryLayer) (removed, added []imagetypes.HistoryLayer) {
+	l := slices.Clone(left)
+	r := slices.Clone(right)
+	slices.Reverse(l)
+	slices.Reverse(r)
+
+	// Longest common subsequence of the commands, so that a step inserted in
+	// the middle of a Dockerfile does not mark every later step as changed.
+	lcs := make([][]int, len(l)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(r)+1)
+	}
+	for i := len(l) - 1; i >= 0; i-- {
+		for j := len(r) - 1; j >= 0; j-- {
+			if l[i].CreatedBy == r[j].CreatedBy {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	removed, added = []imagetypes.HistoryLayer{}, []imagetypes.HistoryLayer{}
+	i, j := 0, 0
+	for i < len(l) && j < len(r) {
+		switch {
+		case l[i].CreatedBy == r[j].CreatedBy:
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			removed = append(removed, l[i])
+			i++
+		default:
+			added = append(added, r[j])
+			j++
+		}
+	}
+	removed = append(removed, l[i:]...)
+	added = append(added, r[j:]...)
+	return removed, added
+}
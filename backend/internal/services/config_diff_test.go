@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/getarcaneapp/arcane/types/diff"
+	imagetypes "github.com/getarcaneapp/arcane/types/image"
+)
+
+func TestDiffConfig(t *testing.T) {
+	left := map[string]any{
+		"image": "nginx:1.0",
+		"cmd":   []string{"serve"},
+		"env":   map[string]any{"A": "1", "B": "2"},
+		"user":  "",
+	}
+	right := map[string]any{
+		"image": "nginx:1.1",
+		"cmd":   []string{"serve"},
+		"env":   map[string]any{"A": "1", "C": "3"},
+	}
+
+	assert.Equal(t, []diff.Change{
+		{Path: "env.B", Kind: diff.ChangeRemoved, Old: "2"},
+		{Path: "env.C", Kind: diff.ChangeAdded, New: "3"},
+		{Path: "image", Kind: diff.ChangeModified, Old: "nginx:1.0", New: "nginx:1.1"},
+	}, diffConfigInternal(left, right))
+	assert.Empty(t, diffConfigInternal(left, left))
+}
+
+func TestDiffBuildSteps(t *testing.T) {
+	steps := func(cmds ...string) []imagetypes.HistoryLayer {
+		out := make([]imagetypes.HistoryLayer, 0, len(cmds))
+		// Histories are newest first.
+		for i := len(cmds) - 1; i >= 0; i-- {
+			out = append(out, imagetypes.HistoryLayer{CreatedBy: cmds[i]})
+		}
+		return out
+	}
+
+	removed, added := diffBuildStepsInternal(steps("FROM", "RUN a", "COPY", "CMD"), steps("FROM", "RUN a", "RUN b", "COPY", "CMD x"))
+	require.Len(t, removed, 1)
+	assert.Equal(t, "CMD", removed[0].CreatedBy)
+	require.Len(t, added, 2)
+	assert.Equal(t, "RUN b", added[0].CreatedBy)
+	assert.Equal(t, "CMD x", added[1].CreatedBy)
+}
+
+func TestContainerService_DiffContainers(t *testing.T) {
+	ctx := context.Background()
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		switch path {
+		case "/containers/old/json":
+			_, _ = io.WriteString(w, `{"Id":"old0123456789","Name":"/web_old","Image":"sha256:1",
+				"Config":{"Image":"nginx:1.0","Env":["A=1","B=2"],"Hostname":"old012345678"},
+				"HostConfig":{"RestartPolicy":{"Name":"always"},"PortBindings":{"80/tcp":[{"HostPort":"8080"}]}},
+				"NetworkSettings":{"Networks":{"appnet":{"Aliases":["old012345678","web"]}}}}`)
+		case "/containers/new/json":
+			_, _ = io.WriteString(w, `{"Id":"new0123456789","Name":"/web","Image":"sha256:2",
+				"Config":{"Image":"nginx:1.1","Env":["B=2","A=1"],"Hostname":"new012345678"},
+				"HostConfig":{"RestartPolicy":{"Name":"always"},"PortBindings":{"80/tcp":[{"HostPort":"9090"}]}},
+				"NetworkSettings":{"Networks":{"appnet":{"Aliases":["web","new012345678"]}}}}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message":"No such container"}`)
+		}
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	svc := &ContainerService{dockerService: &DockerClientService{client: cli}}
+
+	result, err := svc.DiffContainers(ctx, "old", "new")
+	require.NoError(t, err)
+	assert.Equal(t, "web_old", result.Left.Name)
+	assert.Equal(t, "sha256:2", result.Right.ImageID)
+	assert.False(t, result.Identical)
+	assert.Equal(t, []diff.Change{
+		{Path: "image", Kind: diff.ChangeModified, Old: "nginx:1.0", New: "nginx:1.1"},
+		{Path: "ports.80/tcp", Kind: diff.ChangeModified, Old: []string{"8080"}, New: []string{"9090"}},
+	}, result.Changes)
+
+	same, err := svc.DiffContainers(ctx, "old", "old")
+	require.NoError(t, err)
+	assert.True(t, same.Identical)
+
+	_, err = svc.DiffContainers(ctx, "old", "missing")
+	require.ErrorIs(t, err, ErrDockerContainerNotFound)
+}
+
+func TestImageService_DiffImages(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setupImageTransferTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/images/app:1/json"):
+			_, _ = io.WriteString(w, `{"Id":"sha256:a","RepoTags":["app:1"],"Config":{"Env":["V=1"]},"RootFS":{"Layers":["l1","l2"]}}`)
+		case strings.HasSuffix(r.URL.Path, "/images/app:2/json"):
+			_, _ = io.WriteString(w, `{"Id":"sha256:b","RepoTags":["app:2"],"Config":{"Env":["V=2"]},"RootFS":{"Layers":["l1","l3"]}}`)
+		case strings.HasSuffix(r.URL.Path, "/images/sha256:a/history"):
+			_, _ = io.WriteString(w, `[{"Id":"sha256:a","CreatedBy":"COPY app"},{"Id":"<missing>","CreatedBy":"FROM base"}]`)
+		case strings.HasSuffix(r.URL.Path, "/images/sha256:b/history"):
+			_, _ = io.WriteString(w, `[{"Id":"sha256:b","CreatedBy":"COPY app2"},{"Id":"<missing>","CreatedBy":"FROM base"}]`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message":"No such image"}`)
+		}
+	})
+
+	result, err := svc.DiffImages(ctx, "app:1", "app:2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"app:1"}, result.Left.RepoTags)
+	assert.Equal(t, []diff.Change{{Path: "env.V", Kind: diff.ChangeModified, Old: "1", New: "2"}}, result.Changes)
+	assert.Equal(t, 1, result.SharedLayers)
+	assert.Equal(t, []string{"l3"}, result.AddedLayers)
+	assert.Equal(t, []string{"l2"}, result.RemovedLayers)
+	require.Len(t, result.AddedSteps, 1)
+	assert.Equal(t, "COPY app2", result.AddedSteps[0].CreatedBy)
+	assert.False(t, result.Identical)
+
+	_, err = svc.DiffImages(ctx, "app:1", "missing:1")
+	require.ErrorIs(t, err, ErrImageNotFound)
+}
@@ -0,0 +1,215 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/checkpoint"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+)
+
+var (
+	// ErrCheckpointUnsupported is returned when the Docker daemon cannot
+	// checkpoint containers.
+	ErrCheckpointUnsupported = errors.New("container checkpoints are not supported by this Docker daemon")
+	// ErrInvalidCheckpoint is returned for invalid checkpoint names.
+	ErrInvalidCheckpoint = errors.New("invalid checkpoint")
+	// ErrCheckpointNotFound is returned for checkpoints the container does not
+	// have.
+	ErrCheckpointNotFound = errors.New("checkpoint not found")
+	// ErrCheckpointContainerRunning is returned when restoring a checkpoint
+	// into a running container.
+	ErrCheckpointContainerRunning = errors.New("container is running; stop it before restoring a checkpoint")
+)
+
+// checkpointNamePattern matches the checkpoint names Docker accepts.
+var checkpointNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,127}$`)
+
+// checkpointSupportInternal reports whether the daemon exposes the
+// checkpoint API, which needs experimental features on Linux.
+func checkpointSupportInternal(ctx context.Context, dockerClient *client.Client) (*containertypes.CheckpointSupport, error) {
+	info, err := dockerClient.Info(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Docker info: %w", err)
+	}
+
+	support := &containertypes.CheckpointSupport{
+		Experimental: info.ExperimentalBuild,
+		OSType:       info.OSType,
+	}
+	switch {
+	case info.OSType != "linux":
+		support.Reason = "checkpoints need a Linux Docker daemon"
+	case !info.ExperimentalBuild:
+		support.Reason = "checkpoints need experimental features enabled on the Docker daemon (\"experimental\": true in daemon.json) and CRIU installed on the host"
+	default:
+		support.Supported = true
+	}
+	return support, nil
+}
+
+// CheckpointSupport reports whether the Docker daemon can checkpoint and
+// restore containers.
+func (s *ContainerService) CheckpointSupport(ctx context.Context) (*containertypes.CheckpointSupport, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	return checkpointSupportInternal(ctx, dockerClient)
+}
+
+// checkpointTargetInternal connects to Docker, checks checkpoint support and
+// inspects the container.
+func (s *ContainerService) checkpointTargetInternal(ctx context.Context, containerID string) (*client.Client, container.InspectResponse, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, container.InspectResponse{}, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	support, err := checkpointSupportInternal(ctx, dockerClient)
+	if err != nil {
+		return nil, container.InspectResponse{}, err
+	}
+	if !support.Supported {
+		return nil, container.InspectResponse{}, fmt.Errorf("%w: %s", ErrCheckpointUnsupported, support.Reason)
+	}
+
+	inspect, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, container.InspectResponse{}, fmt.Errorf("%w: %s", ErrDockerContainerNotFound, containerID)
+		}
+		return nil, container.InspectResponse{}, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	return dockerClient, inspect, nil
+}
+
+func validateCheckpointNameInternal(name string) error {
+	if !checkpointNamePattern.MatchString(name) {
+		return fmt.Errorf("%w: name %q must start with a letter or digit and contain only letters, digits, '_', '.' and '-'", ErrInvalidCheckpoint, name)
+	}
+	return nil
+}
+
+// ListCheckpoints returns the checkpoints of a container.
+func (s *ContainerService) ListCheckpoints(ctx context.Context, containerID string) ([]containertypes.Checkpoint, error) {
+	dockerClient, inspect, err := s.checkpointTargetInternal(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries, err := dockerClient.CheckpointList(ctx, inspect.ID, checkpoint.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list checkpoints: %w", err)
+	}
+
+	checkpoints := make([]containertypes.Checkpoint, 0, len(summaries))
+	for _, summary := range summaries {
+		checkpoints = append(checkpoints, containertypes.Checkpoint{Name: summary.Name})
+	}
+	return checkpoints, nil
+}
+
+// CreateCheckpoint saves the state of a running container with CRIU. Unless
+// LeaveRunning is set the container stops, so it can be restored later.
+func (s *ContainerService) CreateCheckpoint(ctx context.Context, containerID string, req containertypes.CheckpointCreateRequest, user models.User) (*containertypes.Checkpoint, error) {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		name = "checkpoint-" + time.Now().UTC().Format("20060102-150405")
+	}
+	if err := validateCheckpointNameInternal(name); err != nil {
+		return nil, err
+	}
+
+	dockerClient, inspect, err := s.checkpointTargetInternal(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	containerName := strings.TrimPrefix(inspect.Name, "/")
+	if inspect.State == nil || !inspect.State.Running {
+		return nil, fmt.Errorf("%w: %s", ErrContainerNotRunning, containerName)
+	}
+
+	metadata := models.JSON{
+		"action":       "checkpoint",
+		"checkpoint":   name,
+		"leaveRunning": req.LeaveRunning,
+	}
+
+	if err := dockerClient.CheckpointCreate(ctx, inspect.ID, checkpoint.CreateOptions{
+		CheckpointID: name,
+		Exit:         !req.LeaveRunning,
+	}); err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", inspect.ID, containerName, user.ID, user.Username, "0", err, metadata)
+		return nil, fmt.Errorf("failed to create checkpoint: %w", err)
+	}
+
+	if err := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerCheckpoint, inspect.ID, containerName, user.ID, user.Username, "0", metadata); err != nil {
+		slog.WarnContext(ctx, "Could not log container checkpoint action", "container", containerName, "error", err)
+	}
+
+	return &containertypes.Checkpoint{Name: name}, nil
+}
+
+// RestoreCheckpoint starts a stopped container from one of its checkpoints.
+func (s *ContainerService) RestoreCheckpoint(ctx context.Context, containerID, name string, user models.User) error {
+	if err := validateCheckpointNameInternal(name); err != nil {
+		return err
+	}
+
+	dockerClient, inspect, err := s.checkpointTargetInternal(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	containerName := strings.TrimPrefix(inspect.Name, "/")
+	if inspect.State != nil && inspect.State.Running {
+		return fmt.Errorf("%w: %s", ErrCheckpointContainerRunning, containerName)
+	}
+
+	metadata := models.JSON{
+		"action":     "checkpoint_restore",
+		"checkpoint": name,
+	}
+
+	if err := dockerClient.ContainerStart(ctx, inspect.ID, container.StartOptions{CheckpointID: name}); err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return fmt.Errorf("%w: %s", ErrCheckpointNotFound, name)
+		}
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", inspect.ID, containerName, user.ID, user.Username, "0", err, metadata)
+		return fmt.Errorf("failed to restore checkpoint: %w", err)
+	}
+
+	if err := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerCheckpointRestore, inspect.ID, containerName, user.ID, user.Username, "0", metadata); err != nil {
+		slog.WarnContext(ctx, "Could not log container checkpoint restore action", "container", containerName, "error", err)
+	}
+	return nil
+}
+
+// DeleteCheckpoint removes a checkpoint of a container.
+func (s *ContainerService) DeleteCheckpoint(ctx context.Context, containerID, name string) error {
+	if err := validateCheckpointNameInternal(name); err != nil {
+		return err
+	}
+
+	dockerClient, inspect, err := s.checkpointTargetInternal(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	if err := dockerClient.CheckpointDelete(ctx, inspect.ID, checkpoint.DeleteOptions{CheckpointID: name}); err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return fmt.Errorf("%w: %s", ErrCheckpointNotFound, name)
+		}
+		return fmt.Errorf("failed to delete checkpoint: %w", err)
+	}
+	return nil
+}
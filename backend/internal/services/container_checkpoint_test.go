@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+)
+
+func TestContainerService_Checkpoints(t *testing.T) {
+	ctx := context.Background()
+	experimental, running := false, true
+	var created map[string]any
+	var startQuery string
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case path == "/info":
+			_ = json.NewEncoder(w).Encode(map[string]any{"OSType": "linux", "ExperimentalBuild": experimental})
+		case path == "/containers/web/json":
+			_ = json.NewEncoder(w).Encode(map[string]any{"Id": "abc123", "Name": "/web", "State": map[string]any{"Running": running}})
+		case path == "/containers/abc123/checkpoints" && r.Method == http.MethodGet:
+			_, _ = io.WriteString(w, `[{"Name":"cp1"}]`)
+		case path == "/containers/abc123/checkpoints" && r.Method == http.MethodPost:
+			_ = json.NewDecoder(r.Body).Decode(&created)
+			w.WriteHeader(http.StatusCreated)
+		case path == "/containers/abc123/checkpoints/cp1" && r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case path == "/containers/abc123/checkpoints/missing" && r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message":"checkpoint missing does not exist"}`)
+		case path == "/containers/abc123/start":
+			startQuery = r.URL.RawQuery
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Event{}))
+	svc := NewContainerService(nil, NewEventService(&database.DB{DB: gdb}), &DockerClientService{client: cli}, nil, nil, nil)
+	user := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "admin"}
+
+	support, err := svc.CheckpointSupport(ctx)
+	require.NoError(t, err)
+	assert.False(t, support.Supported)
+	assert.Contains(t, support.Reason, "experimental")
+	_, err = svc.ListCheckpoints(ctx, "web")
+	require.ErrorIs(t, err, ErrCheckpointUnsupported)
+
+	experimental = true
+	checkpoints, err := svc.ListCheckpoints(ctx, "web")
+	require.NoError(t, err)
+	assert.Equal(t, []containertypes.Checkpoint{{Name: "cp1"}}, checkpoints)
+
+	_, err = svc.CreateCheckpoint(ctx, "web", containertypes.CheckpointCreateRequest{Name: "../etc"}, user)
+	require.ErrorIs(t, err, ErrInvalidCheckpoint)
+	checkpoint, err := svc.CreateCheckpoint(ctx, "web", containertypes.CheckpointCreateRequest{}, user)
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(checkpoint.Name, "checkpoint-"))
+	assert.Equal(t, checkpoint.Name, created["CheckpointID"])
+	assert.Equal(t, true, created["Exit"])
+
+	// Restoring needs a stopped container.
+	require.ErrorIs(t, svc.RestoreCheckpoint(ctx, "web", "cp1", user), ErrCheckpointContainerRunning)
+	running = false
+	_, err = svc.CreateCheckpoint(ctx, "web", containertypes.CheckpointCreateRequest{Name: "cp2"}, user)
+	require.ErrorIs(t, err, ErrContainerNotRunning)
+	require.NoError(t, svc.RestoreCheckpoint(ctx, "web", "cp1", user))
+	assert.Contains(t, startQuery, "checkpoint=cp1")
+
+	var events []models.Event
+	require.NoError(t, gdb.Find(&events).Error)
+	require.Len(t, events, 2)
+	assert.Equal(t, models.EventTypeContainerCheckpoint, events[0].Type)
+	assert.Equal(t, models.EventTypeContainerCheckpointRestore, events[1].Type)
+
+	require.NoError(t, svc.DeleteCheckpoint(ctx, "web", "cp1"))
+	require.ErrorIs(t, svc.DeleteCheckpoint(ctx, "web", "missing"), ErrCheckpointNotFound)
+}
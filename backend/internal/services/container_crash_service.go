@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+const (
+	defaultCrashLoopThreshold = 5
+	defaultCrashLoopWindow    = 10 * time.Minute
+	// oomExitGrace is how close to an oom event a kill exit (137) must be to
+	// count as the same failure.
+	oomExitGrace = 30 * time.Second
+	oomExitCode  = 137
+)
+
+// ContainerCrashService follows Docker die and oom events. A container that
+// is killed for running out of memory, or that exits with a non-zero code
+// more often than the crash loop threshold within the crash loop window, is
+// logged and reported through notifications. Each container is reported at
+// most once per window for each kind of alert. The die event that follows an
+// oom kill is not counted as a crash, since the oom is already reported.
+type ContainerCrashService struct {
+	dockerService       *DockerClientService
+	eventService        *EventService
+	notificationService *NotificationService
+	settingsService     *SettingsService
+
+	mu         sync.Mutex
+	crashes    map[string][]time.Time
+	lastOOMs   map[string]time.Time
+	lastAlerts map[string]time.Time
+}
+
+func NewContainerCrashService(dockerService *DockerClientService, eventService *EventService, notificationService *NotificationService, settingsService *SettingsService) *ContainerCrashService {
+	return &ContainerCrashService{
+		dockerService:       dockerService,
+		eventService:        eventService,
+		notificationService: notificationService,
+		settingsService:     settingsService,
+		crashes:             map[string][]time.Time{},
+		lastOOMs:            map[string]time.Time{},
+		lastAlerts:          map[string]time.Time{},
+	}
+}
+
+// crashEventActions are the Docker events the service follows.
+var crashEventActions = []events.Action{events.ActionDie, events.ActionOOM}
+
+// Run follows the Docker event stream until ctx is done.
+func (s *ContainerCrashService) Run(ctx context.Context) {
+	followContainerEventsInternal(ctx, s.dockerService, "crash", crashEventActions, func(msg events.Message) {
+		s.handleEventInternal(ctx, msg)
+	})
+}
+
+func (s *ContainerCrashService) handleEventInternal(ctx context.Context, msg events.Message) {
+	name := msg.Actor.Attributes["name"]
+	at := time.Unix(0, msg.TimeNano)
+	switch msg.Action {
+	case events.ActionOOM:
+		s.RecordOOM(ctx, msg.Actor.ID, name, at)
+	case events.ActionDie:
+		exitCode, _ := strconv.Atoi(msg.Actor.Attributes["exitCode"])
+		s.RecordExit(ctx, msg.Actor.ID, name, exitCode, at)
+	}
+}
+
+// RecordOOM reports a container that was killed for running out of memory.
+func (s *ContainerCrashService) RecordOOM(ctx context.Context, containerID, containerName string, at time.Time) {
+	containerName = crashContainerNameInternal(containerID, containerName)
+	window := s.windowInternal(ctx)
+
+	s.mu.Lock()
+	s.lastOOMs[containerName] = at
+	alert := s.shouldAlertInternal("oom:"+containerName, at, window)
+	s.mu.Unlock()
+	if !alert {
+		return
+	}
+
+	s.reportInternal(ctx, containerID, ContainerCrashNotificationPayload{
+		Container: containerName,
+		OOMKilled: true,
+		ExitCode:  oomExitCode,
+		Window:    window,
+	})
+}
+
+// RecordExit records a container exit and reports the container as crash
+// looping once it exited with a non-zero code more often than the threshold
+// within the window. Clean exits are not counted.
+func (s *ContainerCrashService) RecordExit(ctx context.Context, containerID, containerName string, exitCode int, at time.Time) {
+	if exitCode == 0 {
+		return
+	}
+	containerName = crashContainerNameInternal(containerID, containerName)
+	threshold := s.thresholdInternal(ctx)
+	if threshold <= 0 {
+		return
+	}
+	window := s.windowInternal(ctx)
+
+	s.mu.Lock()
+	if exitCode == oomExitCode {
+		if oomAt, ok := s.lastOOMs[containerName]; ok && at.Sub(oomAt).Abs() < oomExitGrace {
+			s.mu.Unlock()
+			return
+		}
+	}
+	recent := s.crashes[containerName][:0]
+	for _, t := range s.crashes[containerName] {
+		if at.Sub(t) < window {
+			recent = append(recent, t)
+		}
+	}
+	recent = append(recent, at)
+	s.crashes[containerName] = recent
+	count := len(recent)
+	alert := count > threshold && s.shouldAlertInternal("crash:"+containerName, at, window)
+	s.mu.Unlock()
+	if !alert {
+		return
+	}
+
+	s.reportInternal(ctx, containerID, ContainerCrashNotificationPayload{
+		Container: containerName,
+		ExitCode:  exitCode,
+		Crashes:   count,
+		Window:    window,
+	})
+}
+
+// shouldAlertInternal reports whether the alert key was not raised within
+// window and marks it raised. s.mu must be held.
+func (s *ContainerCrashService) shouldAlertInternal(key string, at time.Time, window time.Duration) bool {
+	if last, ok := s.lastAlerts[key]; ok && at.Sub(last) < window {
+		return false
+	}
+	s.lastAlerts[key] = at
+	return true
+}
+
+func (s *ContainerCrashService) reportInternal(ctx context.Context, containerID string, payload ContainerCrashNotificationPayload) {
+	eventType := models.EventTypeContainerCrashLoop
+	metadata := models.JSON{
+		"exitCode":      payload.ExitCode,
+		"crashes":       payload.Crashes,
+		"windowMinutes": int(payload.Window.Minutes()),
+	}
+	if payload.OOMKilled {
+		eventType = models.EventTypeContainerOOMKilled
+		metadata = models.JSON{"exitCode": payload.ExitCode}
+	}
+
+	if s.eventService != nil {
+		resourceType := "container"
+		environmentID := "0"
+		_, err := s.eventService.CreateEvent(ctx, CreateEventRequest{
+			Type:          eventType,
+			Severity:      s.eventService.getEventSeverity(eventType),
+			Title:         s.eventService.generateEventTitle(eventType, payload.Container),
+			Description:   s.eventService.generateEventDescription(eventType, resourceType, payload.Container),
+			ResourceType:  &resourceType,
+			ResourceID:    &containerID,
+			ResourceName:  &payload.Container,
+			UserID:        &systemUser.ID,
+			Username:      &systemUser.Username,
+			EnvironmentID: &environmentID,
+			Metadata:      metadata,
+		})
+		if err != nil {
+			slog.WarnContext(ctx, "Could not log container crash event", "container", payload.Container, "type", eventType, "error", err)
+		}
+	}
+
+	if s.notificationService != nil {
+		if err := s.notificationService.SendContainerCrashNotification(ctx, payload); err != nil {
+			slog.WarnContext(ctx, "Failed to send container crash notification", "container", payload.Container, "type", eventType, "error", err)
+		}
+	}
+}
+
+func crashContainerNameInternal(containerID, containerName string) string {
+	containerName = strings.TrimPrefix(containerName, "/")
+	if containerName == "" {
+		return containerID
+	}
+	return containerName
+}
+
+func (s *ContainerCrashService) thresholdInternal(ctx context.Context) int {
+	if s.settingsService == nil {
+		return defaultCrashLoopThreshold
+	}
+	return s.settingsService.GetIntSetting(ctx, "crashLoopThreshold", defaultCrashLoopThreshold)
+}
+
+func (s *ContainerCrashService) windowInternal(ctx context.Context) time.Duration {
+	if s.settingsService == nil {
+		return defaultCrashLoopWindow
+	}
+	minutes := s.settingsService.GetIntSetting(ctx, "crashLoopWindow", int(defaultCrashLoopWindow.Minutes()))
+	if minutes <= 0 {
+		return defaultCrashLoopWindow
+	}
+	return time.Duration(minutes) * time.Minute
+}
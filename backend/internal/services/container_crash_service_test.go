@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+func newCrashTestService(t *testing.T) (*ContainerCrashService, *gorm.DB) {
+	t.Helper()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Event{}))
+	db := &database.DB{DB: gdb}
+	return NewContainerCrashService(nil, NewEventService(db), nil, nil), gdb
+}
+
+func TestContainerCrashService_RecordExitDetectsCrashLoop(t *testing.T) {
+	ctx := context.Background()
+	svc, gdb := newCrashTestService(t)
+	start := time.Now()
+
+	// Clean exits and crashes outside the window are not counted.
+	svc.RecordExit(ctx, "abc123", "/web", 0, start)
+	svc.RecordExit(ctx, "abc123", "/web", 1, start.Add(-time.Hour))
+	for i := range 5 {
+		svc.RecordExit(ctx, "abc123", "/web", 1, start.Add(time.Duration(i)*time.Minute))
+	}
+	var count int64
+	require.NoError(t, gdb.Model(&models.Event{}).Count(&count).Error)
+	assert.Zero(t, count)
+
+	// The sixth crash within ten minutes is above the default threshold.
+	svc.RecordExit(ctx, "abc123", "/web", 2, start.Add(5*time.Minute))
+	// Further crashes within the window are not reported again.
+	svc.RecordExit(ctx, "abc123", "/web", 2, start.Add(6*time.Minute))
+
+	var events []models.Event
+	require.NoError(t, gdb.Find(&events).Error)
+	require.Len(t, events, 1)
+	assert.Equal(t, models.EventTypeContainerCrashLoop, events[0].Type)
+	assert.Equal(t, "Container crash loop: web", events[0].Title)
+	assert.EqualValues(t, 6, events[0].Metadata["crashes"])
+	assert.EqualValues(t, 2, events[0].Metadata["exitCode"])
+}
+
+func TestContainerCrashService_RecordOOM(t *testing.T) {
+	ctx := context.Background()
+	svc, gdb := newCrashTestService(t)
+	start := time.Now()
+
+	svc.RecordOOM(ctx, "abc123", "/db", start)
+	svc.RecordOOM(ctx, "abc123", "/db", start.Add(time.Minute))
+	svc.RecordOOM(ctx, "abc123", "/db", start.Add(11*time.Minute))
+
+	var events []models.Event
+	require.NoError(t, gdb.Order("timestamp ASC").Find(&events).Error)
+	require.Len(t, events, 2)
+	assert.Equal(t, models.EventTypeContainerOOMKilled, events[0].Type)
+	assert.Equal(t, "Container out of memory: db", events[0].Title)
+	require.NotNil(t, events[0].ResourceID)
+	assert.Equal(t, "abc123", *events[0].ResourceID)
+}
+
+func TestContainerCrashService_OOMKillIsNotACrash(t *testing.T) {
+	ctx := context.Background()
+	svc, gdb := newCrashTestService(t)
+	start := time.Now()
+
+	// Each oom kill is followed by a die event with exit code 137, which must
+	// not add up to a crash loop on top of the oom alert.
+	for i := range 8 {
+		at := start.Add(time.Duration(i) * time.Minute)
+		svc.handleEventInternal(ctx, events.Message{Action: events.ActionOOM, TimeNano: at.UnixNano(), Actor: events.Actor{ID: "abc123", Attributes: map[string]string{"name": "db"}}})
+		svc.handleEventInternal(ctx, events.Message{Action: events.ActionDie, TimeNano: at.Add(time.Second).UnixNano(), Actor: events.Actor{ID: "abc123", Attributes: map[string]string{"name": "db", "exitCode": "137"}}})
+	}
+
+	var logged []models.Event
+	require.NoError(t, gdb.Find(&logged).Error)
+	require.Len(t, logged, 1)
+	assert.Equal(t, models.EventTypeContainerOOMKilled, logged[0].Type)
+
+	// Kills without an oom still count.
+	for i := range 6 {
+		svc.RecordExit(ctx, "abc123", "/db", 137, start.Add(time.Duration(30+i)*time.Minute))
+	}
+	require.NoError(t, gdb.Where("type = ?", models.EventTypeContainerCrashLoop).Find(&logged).Error)
+	assert.Len(t, logged, 1)
+}
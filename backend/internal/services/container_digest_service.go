@@ -0,0 +1,457 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	ref "go.podman.io/image/v5/docker/reference"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/pkg/projects"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+)
+
+var ErrContainerNotPinned = errors.New("container is not pinned")
+
+// ContainerDigestService records the registry digest each container was
+// deployed from and detects tag drift: the container's tag pointing to a
+// different digest in the registry than the one the container runs. Unlike
+// the image update check, which compares the local copy of a tag, drift is
+// judged per container, so a container still running an old image after a
+// pull is reported too. Pinned containers are skipped by auto-update, and
+// containers of Arcane projects are pinned in the compose file as well.
+type ContainerDigestService struct {
+	db                 *database.DB
+	dockerService      *DockerClientService
+	imageUpdateService *ImageUpdateService
+	projectService     *ProjectService
+	eventService       *EventService
+}
+
+func NewContainerDigestService(db *database.DB, dockerService *DockerClientService, imageUpdateService *ImageUpdateService, projectService *ProjectService, eventService *EventService) *ContainerDigestService {
+	return &ContainerDigestService{
+		db:                 db,
+		dockerService:      dockerService,
+		imageUpdateService: imageUpdateService,
+		projectService:     projectService,
+		eventService:       eventService,
+	}
+}
+
+// ListContainerDigests returns the recorded digest of every tracked
+// container, drifted containers first.
+func (s *ContainerDigestService) ListContainerDigests(ctx context.Context) ([]containertypes.DigestStatus, error) {
+	var records []models.ContainerDigest
+	if err := s.db.WithContext(ctx).Order("drifted DESC, container_name ASC").Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to list container digests: %w", err)
+	}
+
+	out := make([]containertypes.DigestStatus, 0, len(records))
+	for _, r := range records {
+		out = append(out, toDigestStatusDTO(r))
+	}
+	return out, nil
+}
+
+// GetContainerDigest records the digest of a container if needed and returns
+// it.
+func (s *ContainerDigestService) GetContainerDigest(ctx context.Context, containerID string) (*containertypes.DigestStatus, error) {
+	inspect, err := s.inspectContainerInternal(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	record, err := s.recordInternal(ctx, inspect)
+	if err != nil {
+		return nil, err
+	}
+	out := toDigestStatusDTO(*record)
+	return &out, nil
+}
+
+// PinContainer keeps a container on the digest it runs: auto-update skips
+// it until it is unpinned. The pin follows the container name, so it still
+// applies after the container is recreated. When the container belongs to an
+// Arcane project, its service image in the compose file is pinned to the
+// digest too, so redeploying the project keeps it.
+func (s *ContainerDigestService) PinContainer(ctx context.Context, containerID, reason string, user models.User) (*containertypes.DigestStatus, error) {
+	inspect, err := s.inspectContainerInternal(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	record, err := s.recordInternal(ctx, inspect)
+	if err != nil {
+		return nil, err
+	}
+
+	record.Pinned = true
+	record.PinnedBy = &user.Username
+	record.PinReason = nil
+	if reason = strings.TrimSpace(reason); reason != "" {
+		record.PinReason = &reason
+	}
+	if err := s.db.WithContext(ctx).Save(record).Error; err != nil {
+		return nil, fmt.Errorf("failed to pin container: %w", err)
+	}
+	digest := record.Digest
+	s.editComposeImageInternal(ctx, inspect, func(image string) (string, bool) {
+		return pinnedComposeImageInternal(image, digest)
+	})
+
+	metadata := models.JSON{
+		"action":   "pin",
+		"imageRef": record.ImageRef,
+		"digest":   record.Digest,
+		"reason":   reason,
+	}
+	if logErr := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerPin, inspect.ID, record.ContainerName, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log container pin action", "container", record.ContainerName, "error", logErr)
+	}
+
+	out := toDigestStatusDTO(*record)
+	return &out, nil
+}
+
+// UnpinContainer lets auto-update replace a container again. containerID may
+// be a container ID or name; pins of containers that no longer exist can be
+// removed by name. A digest pin added to the compose file is removed again.
+func (s *ContainerDigestService) UnpinContainer(ctx context.Context, containerID string, user models.User) error {
+	name := strings.TrimPrefix(strings.TrimSpace(containerID), "/")
+	inspect, inspectErr := s.inspectContainerInternal(ctx, containerID)
+	if inspectErr == nil {
+		name = strings.TrimPrefix(inspect.Name, "/")
+	}
+
+	var record models.ContainerDigest
+	if err := s.db.WithContext(ctx).Where("container_name = ? AND pinned = ?", name, true).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrContainerNotPinned
+		}
+		return fmt.Errorf("failed to load container pin: %w", err)
+	}
+
+	updates := map[string]any{"pinned": false, "pin_reason": nil, "pinned_by": nil}
+	if err := s.db.WithContext(ctx).Model(&record).Updates(updates).Error; err != nil {
+		return fmt.Errorf("failed to unpin container: %w", err)
+	}
+	if inspectErr == nil {
+		s.editComposeImageInternal(ctx, inspect, func(image string) (string, bool) {
+			return unpinnedComposeImageInternal(image, record.Digest)
+		})
+	}
+
+	metadata := models.JSON{
+		"action":   "unpin",
+		"imageRef": record.ImageRef,
+		"digest":   record.Digest,
+	}
+	if logErr := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerUnpin, record.ContainerID, record.ContainerName, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log container unpin action", "container", record.ContainerName, "error", logErr)
+	}
+	return nil
+}
+
+// CheckTagDrift records the digest of every container and looks up the digest
+// each container's tag points to now. Containers created from a digest
+// reference or from a local build cannot drift and are not looked up. A
+// container that starts drifting is logged as an event once.
+func (s *ContainerDigestService) CheckTagDrift(ctx context.Context) (*containertypes.DriftCheckResult, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	result := &containertypes.DriftCheckResult{Items: []containertypes.DigestStatus{}}
+	seen := make(map[string]struct{}, len(containers))
+	// Several containers usually share a tag; look each one up once.
+	remoteByRef := map[string]string{}
+	errByRef := map[string]error{}
+
+	for _, c := range containers {
+		inspect, err := dockerClient.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			slog.DebugContext(ctx, "tag drift: failed to inspect container", "containerId", c.ID, "error", err)
+			continue
+		}
+		record, err := s.recordInternal(ctx, inspect)
+		if err != nil {
+			return nil, err
+		}
+		seen[record.ContainerName] = struct{}{}
+
+		if record.Digest == "" || isDigestReferenceInternal(record.ImageRef) {
+			result.Items = append(result.Items, toDigestStatusDTO(*record))
+			continue
+		}
+
+		remote, looked := remoteByRef[record.ImageRef]
+		lookupErr := errByRef[record.ImageRef]
+		if !looked && lookupErr == nil {
+			remote, lookupErr = s.imageUpdateService.ResolveRemoteDigest(ctx, record.ImageRef)
+			if lookupErr != nil {
+				errByRef[record.ImageRef] = lookupErr
+			} else {
+				remoteByRef[record.ImageRef] = remote
+			}
+		}
+
+		now := time.Now()
+		record.DriftCheckedAt = &now
+		result.Checked++
+		if lookupErr != nil {
+			msg := lookupErr.Error()
+			record.LastError = &msg
+			result.Failed++
+		} else {
+			wasDrifted := record.Drifted
+			record.LastError = nil
+			record.RemoteDigest = &remote
+			record.Drifted = remote != record.Digest
+			if record.Drifted {
+				result.Drifted++
+				if !wasDrifted {
+					s.logDriftInternal(ctx, *record)
+				}
+			}
+		}
+		if err := s.db.WithContext(ctx).Save(record).Error; err != nil {
+			return nil, fmt.Errorf("failed to save tag drift result: %w", err)
+		}
+		result.Items = append(result.Items, toDigestStatusDTO(*record))
+	}
+
+	// Forget containers that are gone, unless they are pinned: a pinned
+	// service that is recreated under the same name should stay pinned.
+	q := s.db.WithContext(ctx).Where("pinned = ?", false)
+	if len(seen) > 0 {
+		names := make([]string, 0, len(seen))
+		for n := range seen {
+			names = append(names, n)
+		}
+		q = q.Where("container_name NOT IN ?", names)
+	}
+	if err := q.Delete(&models.ContainerDigest{}).Error; err != nil {
+		slog.WarnContext(ctx, "failed to remove digest records of removed containers", "error", err)
+	}
+
+	return result, nil
+}
+
+// PinnedContainerNames returns the names of all pinned containers.
+func (s *ContainerDigestService) PinnedContainerNames(ctx context.Context) (map[string]struct{}, error) {
+	if s == nil {
+		return map[string]struct{}{}, nil
+	}
+	return loadPinnedContainerNamesInternal(ctx, s.db)
+}
+
+func loadPinnedContainerNamesInternal(ctx context.Context, db *database.DB) (map[string]struct{}, error) {
+	out := map[string]struct{}{}
+	if db == nil {
+		return out, nil
+	}
+	var names []string
+	if err := db.WithContext(ctx).Model(&models.ContainerDigest{}).Where("pinned = ?", true).Pluck("container_name", &names).Error; err != nil {
+		return nil, fmt.Errorf("failed to load pinned containers: %w", err)
+	}
+	for _, n := range names {
+		out[n] = struct{}{}
+	}
+	return out, nil
+}
+
+func (s *ContainerDigestService) inspectContainerInternal(ctx context.Context, containerID string) (container.InspectResponse, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return container.InspectResponse{}, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	inspect, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return container.InspectResponse{}, fmt.Errorf("%w: %s", ErrDockerContainerNotFound, containerID)
+		}
+		return container.InspectResponse{}, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	return inspect, nil
+}
+
+// recordInternal stores the digest a container runs. The record is left as
+// it is while the container runs the same image; when the image changed, the
+// new digest is recorded and the previous drift result is dropped.
+func (s *ContainerDigestService) recordInternal(ctx context.Context, inspect container.InspectResponse) (*models.ContainerDigest, error) {
+	name := strings.TrimPrefix(inspect.Name, "/")
+	imageRef := ""
+	if inspect.Config != nil {
+		imageRef = inspect.Config.Image
+	}
+
+	var record models.ContainerDigest
+	err := s.db.WithContext(ctx).Where("container_name = ?", name).First(&record).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		record = models.ContainerDigest{ContainerName: name}
+	case err != nil:
+		return nil, fmt.Errorf("failed to load container digest: %w", err)
+	case record.ImageID == inspect.Image && record.ImageRef == imageRef:
+		if record.ContainerID != inspect.ID {
+			record.ContainerID = inspect.ID
+			if err := s.db.WithContext(ctx).Model(&record).Update("container_id", inspect.ID).Error; err != nil {
+				return nil, fmt.Errorf("failed to update container digest: %w", err)
+			}
+		}
+		return &record, nil
+	}
+
+	digest, err := s.deployedDigestInternal(ctx, imageRef, inspect.Image)
+	if err != nil {
+		return nil, err
+	}
+	record.ContainerID = inspect.ID
+	record.ImageRef = imageRef
+	record.ImageID = inspect.Image
+	record.Digest = digest
+	record.RecordedAt = time.Now()
+	record.RemoteDigest = nil
+	record.Drifted = false
+	record.DriftCheckedAt = nil
+	record.LastError = nil
+	if err := s.db.WithContext(ctx).Save(&record).Error; err != nil {
+		return nil, fmt.Errorf("failed to record container digest: %w", err)
+	}
+	return &record, nil
+}
+
+// deployedDigestInternal picks the repo digest of imageID that belongs to the
+// repository of imageRef.
+func (s *ContainerDigestService) deployedDigestInternal(ctx context.Context, imageRef, imageID string) (string, error) {
+	if named, err := ref.ParseNormalizedNamed(imageRef); err == nil {
+		if digested, ok := named.(ref.Digested); ok {
+			return digested.Digest().String(), nil
+		}
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	inspect, err := dockerClient.ImageInspect(ctx, imageID)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return "", nil
+		}
+		return "", fmt.Errorf("failed to inspect image: %w", err)
+	}
+	return repoDigestForRefInternal(imageRef, inspect.RepoDigests), nil
+}
+
+// repoDigestForRefInternal returns the digest of the entry in repoDigests
+// for the repository of imageRef, or "" when there is none.
+func repoDigestForRefInternal(imageRef string, repoDigests []string) string {
+	named, err := ref.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return ""
+	}
+	for _, rd := range repoDigests {
+		candidate, err := ref.ParseNormalizedNamed(rd)
+		if err != nil {
+			continue
+		}
+		digested, ok := candidate.(ref.Digested)
+		if ok && candidate.Name() == named.Name() {
+			return digested.Digest().String()
+		}
+	}
+	return ""
+}
+
+// editComposeImageInternal rewrites the image of the container's service in
+// the compose file of its Arcane project. rewrite gets the image as written
+// and reports whether to change it. Containers outside Arcane projects are
+// skipped; failures are logged since the pin itself is already recorded.
+func (s *ContainerDigestService) editComposeImageInternal(ctx context.Context, inspect container.InspectResponse, rewrite func(image string) (string, bool)) {
+	if s.projectService == nil || inspect.Config == nil {
+		return
+	}
+	composeProject := inspect.Config.Labels["com.docker.compose.project"]
+	service := inspect.Config.Labels["com.docker.compose.service"]
+	if composeProject == "" || service == "" {
+		return
+	}
+
+	_, err := s.projectService.EditComposeFile(ctx, composeProject, func(e *projects.ComposeEditor) error {
+		image, err := e.ServiceImage(service)
+		if err != nil {
+			return err
+		}
+		if updated, ok := rewrite(image); ok {
+			return e.SetServiceImage(service, updated)
+		}
+		return nil
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "could not update compose file image pin", "project", composeProject, "service", service, "error", err)
+	}
+}
+
+// pinnedComposeImageInternal appends digest to a compose image. Images that
+// use variables or already name a digest are left as written.
+func pinnedComposeImageInternal(image, digest string) (string, bool) {
+	if image == "" || digest == "" || strings.ContainsAny(image, "$@") {
+		return "", false
+	}
+	return image + "@" + digest, true
+}
+
+// unpinnedComposeImageInternal removes a digest added by
+// pinnedComposeImageInternal.
+func unpinnedComposeImageInternal(image, digest string) (string, bool) {
+	if digest == "" {
+		return "", false
+	}
+	return strings.CutSuffix(image, "@"+digest)
+}
+
+func isDigestReferenceInternal(imageRef string) bool {
+	return strings.Contains(imageRef, "@sha256:")
+}
+
+func (s *ContainerDigestService) logDriftInternal(ctx context.Context, record models.ContainerDigest) {
+	metadata := models.JSON{
+		"imageRef":     record.ImageRef,
+		"digest":       record.Digest,
+		"remoteDigest": stringPtrToString(record.RemoteDigest),
+		"pinned":       record.Pinned,
+	}
+	if logErr := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerTagDrift, record.ContainerID, record.ContainerName, systemUser.ID, systemUser.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log tag drift", "container", record.ContainerName, "error", logErr)
+	}
+}
+
+func toDigestStatusDTO(r models.ContainerDigest) containertypes.DigestStatus {
+	return containertypes.DigestStatus{
+		ContainerName:  r.ContainerName,
+		ContainerID:    r.ContainerID,
+		ImageRef:       r.ImageRef,
+		ImageID:        r.ImageID,
+		Digest:         r.Digest,
+		RemoteDigest:   stringPtrToString(r.RemoteDigest),
+		Drifted:        r.Drifted,
+		DriftCheckedAt: r.DriftCheckedAt,
+		LastError:      stringPtrToString(r.LastError),
+		Pinned:         r.Pinned,
+		PinReason:      stringPtrToString(r.PinReason),
+		PinnedBy:       stringPtrToString(r.PinnedBy),
+		RecordedAt:     r.RecordedAt,
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+var (
+	testDigestA = "sha256:" + strings.Repeat("a", 64)
+	testDigestB = "sha256:" + strings.Repeat("b", 64)
+)
+
+func TestRepoDigestForRef(t *testing.T) {
+	digests := []string{"ghcr.io/acme/nginx@" + testDigestA, "nginx@" + testDigestB}
+	assert.Equal(t, testDigestB, repoDigestForRefInternal("nginx:1.0", digests))
+	assert.Equal(t, testDigestB, repoDigestForRefInternal("docker.io/library/nginx", digests))
+	assert.Equal(t, testDigestA, repoDigestForRefInternal("ghcr.io/acme/nginx:latest", digests))
+	assert.Empty(t, repoDigestForRefInternal("redis:7", digests))
+}
+
+func setupContainerDigestTest(t *testing.T) (*ContainerDigestService, *gorm.DB) {
+	t.Helper()
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		switch path {
+		case "/containers/json":
+			_, _ = io.WriteString(w, `[{"Id":"c1","Names":["/web"]},{"Id":"c2","Names":["/api"]},{"Id":"c3","Names":["/dev"]}]`)
+		case "/containers/c1/json", "/containers/web/json":
+			_, _ = io.WriteString(w, `{"Id":"c1","Name":"/web","Image":"sha256:i1","Config":{"Image":"nginx:1.0","Labels":{"com.docker.compose.project":"media","com.docker.compose.service":"web"}}}`)
+		case "/containers/c2/json":
+			_, _ = io.WriteString(w, `{"Id":"c2","Name":"/api","Image":"sha256:i2","Config":{"Image":"app@sha256:fixed"}}`)
+		case "/containers/c3/json":
+			_, _ = io.WriteString(w, `{"Id":"c3","Name":"/dev","Image":"sha256:i3","Config":{"Image":"local:dev"}}`)
+		case "/images/sha256:i1/json":
+			_, _ = io.WriteString(w, `{"Id":"sha256:i1","RepoDigests":["nginx@`+testDigestA+`"]}`)
+		case "/images/sha256:i3/json":
+			_, _ = io.WriteString(w, `{"Id":"sha256:i3","RepoDigests":[]}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message":"not found"}`)
+		}
+	}))
+	t.Cleanup(docker.Close)
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.ContainerDigest{}, &models.ContainerRegistry{}, &models.Event{}))
+	db := &database.DB{DB: gdb}
+
+	dockerService := &DockerClientService{client: cli}
+	imageUpdates := NewImageUpdateService(db, nil, NewContainerRegistryService(db), dockerService, NewEventService(db), nil)
+	imageUpdates.remoteDigestCache[remoteDigestCacheKey("docker.io", "library/nginx", "1.0")] = remoteDigestEntry{digest: testDigestB, fetchedAt: time.Now()}
+
+	return NewContainerDigestService(db, dockerService, imageUpdates, nil, NewEventService(db)), gdb
+}
+
+func TestContainerDigestService_CheckTagDrift(t *testing.T) {
+	ctx := context.Background()
+	svc, gdb := setupContainerDigestTest(t)
+	require.NoError(t, gdb.Create(&models.ContainerDigest{ContainerName: "gone"}).Error)
+	require.NoError(t, gdb.Create(&models.ContainerDigest{ContainerName: "kept", Pinned: true}).Error)
+
+	result, err := svc.CheckTagDrift(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Checked)
+	assert.Equal(t, 1, result.Drifted)
+	assert.Len(t, result.Items, 3)
+
+	web, err := svc.GetContainerDigest(ctx, "web")
+	require.NoError(t, err)
+	assert.Equal(t, testDigestA, web.Digest)
+	assert.Equal(t, testDigestB, web.RemoteDigest)
+	assert.True(t, web.Drifted)
+
+	var names []string
+	require.NoError(t, gdb.Model(&models.ContainerDigest{}).Order("container_name").Pluck("container_name", &names).Error)
+	assert.Equal(t, []string{"api", "dev", "kept", "web"}, names)
+
+	// A container that keeps drifting is only reported once.
+	_, err = svc.CheckTagDrift(ctx)
+	require.NoError(t, err)
+	var drifts int64
+	require.NoError(t, gdb.Model(&models.Event{}).Where("type = ?", models.EventTypeContainerTagDrift).Count(&drifts).Error)
+	assert.Equal(t, int64(1), drifts)
+
+	list, err := svc.ListContainerDigests(ctx)
+	require.NoError(t, err)
+	require.NotEmpty(t, list)
+	assert.Equal(t, "web", list[0].ContainerName)
+}
+
+func TestContainerDigestService_PinContainer(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setupContainerDigestTest(t)
+	user := models.User{Username: "admin"}
+
+	status, err := svc.PinContainer(ctx, "c1", " hold ", user)
+	require.NoError(t, err)
+	assert.True(t, status.Pinned)
+	assert.Equal(t, "hold", status.PinReason)
+	assert.Equal(t, testDigestA, status.Digest)
+
+	pinned, err := svc.PinnedContainerNames(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, pinned, "web")
+
+	require.NoError(t, svc.UnpinContainer(ctx, "web", user))
+	require.ErrorIs(t, svc.UnpinContainer(ctx, "web", user), ErrContainerNotPinned)
+
+	_, err = svc.PinContainer(ctx, "missing", "", user)
+	require.ErrorIs(t, err, ErrDockerContainerNotFound)
+}
+
+func TestContainerDigestService_PinContainerPinsComposeFile(t *testing.T) {
+	ctx := context.Background()
+	svc, gdb := setupContainerDigestTest(t)
+	require.NoError(t, gdb.AutoMigrate(&models.Project{}))
+	svc.projectService = &ProjectService{db: svc.db}
+	user := models.User{Username: "admin"}
+
+	dir := t.TempDir()
+	const compose = `x-common: &common
+  restart: unless-stopped # keep running
+
+services:
+  web:
+    <<: *common
+    image: nginx:1.0 # front end
+`
+	composePath := filepath.Join(dir, "compose.yaml")
+	require.NoError(t, os.WriteFile(composePath, []byte(compose), 0o600))
+	require.NoError(t, gdb.Create(&models.Project{BaseModel: models.BaseModel{ID: "p1"}, Name: "media", Path: dir}).Error)
+
+	_, err := svc.PinContainer(ctx, "c1", "", user)
+	require.NoError(t, err)
+	content, err := os.ReadFile(composePath)
+	require.NoError(t, err)
+	assert.Equal(t, strings.Replace(compose, "nginx:1.0 #", "nginx:1.0@"+testDigestA+" #", 1), string(content))
+
+	require.NoError(t, svc.UnpinContainer(ctx, "web", user))
+	content, err = os.ReadFile(composePath)
+	require.NoError(t, err)
+	assert.Equal(t, compose, string(content))
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/netip"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/docker/docker/api/types/container"
+)
+
+// ErrInvalidDNSConfig is returned for DNS servers, search domains or extra
+// hosts that Docker would reject.
+var ErrInvalidDNSConfig = errors.New("invalid DNS configuration")
+
+// hostGatewayIP is the special extra_hosts address Docker resolves to the
+// host's gateway.
+const hostGatewayIP = "host-gateway"
+
+// containerDNSDefaults holds the environment-wide DNS settings injected into
+// containers created through Arcane.
+type containerDNSDefaults struct {
+	Servers    []string
+	Search     []string
+	ExtraHosts []string
+}
+
+// ValidateContainerDNS checks that servers are IP addresses, search domains
+// are plain domain names and extra hosts are "hostname:IP" pairs.
+func ValidateContainerDNS(servers, search, extraHosts []string) error {
+	for _, server := range servers {
+		if _, err := netip.ParseAddr(server); err != nil {
+			return fmt.Errorf("%w: DNS server %q is not an IP address", ErrInvalidDNSConfig, server)
+		}
+	}
+	for _, domain := range search {
+		if domain == "" || len(domain) > 253 || strings.ContainsFunc(domain, unicode.IsSpace) || strings.Contains(domain, ",") {
+			return fmt.Errorf("%w: search domain %q is not a domain name", ErrInvalidDNSConfig, domain)
+		}
+	}
+	for _, entry := range extraHosts {
+		if _, _, err := splitExtraHostInternal(entry); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// splitExtraHostInternal splits an extra_hosts entry into its hostname and
+// address. Docker accepts both "host:ip" and "host=ip"; IPv6 addresses keep
+// their colons because only the first separator counts.
+func splitExtraHostInternal(entry string) (string, string, error) {
+	sep := ":"
+	if strings.Contains(entry, "=") {
+		sep = "="
+	}
+	host, ip, ok := strings.Cut(entry, sep)
+	host = strings.TrimSpace(host)
+	ip = strings.Trim(strings.TrimSpace(ip), "[]")
+	if !ok || host == "" || strings.ContainsFunc(host, unicode.IsSpace) {
+		return "", "", fmt.Errorf("%w: extra host %q must be hostname:IP", ErrInvalidDNSConfig, entry)
+	}
+	if ip != hostGatewayIP {
+		if _, err := netip.ParseAddr(ip); err != nil {
+			return "", "", fmt.Errorf("%w: extra host %q has an invalid IP address", ErrInvalidDNSConfig, entry)
+		}
+	}
+	return host, ip, nil
+}
+
+// parseSettingListInternal splits a setting holding a list separated by
+// commas, spaces or newlines.
+func parseSettingListInternal(value string) []string {
+	return strings.FieldsFunc(value, func(r rune) bool {
+		return r == ',' || unicode.IsSpace(r)
+	})
+}
+
+// validateContainerDNSSettingInternal validates one of the container DNS
+// default settings.
+func validateContainerDNSSettingInternal(key, value string) error {
+	list := parseSettingListInternal(value)
+	switch key {
+	case "containerDnsServers":
+		return ValidateContainerDNS(list, nil, nil)
+	case "containerDnsSearch":
+		return ValidateContainerDNS(nil, list, nil)
+	case "containerExtraHosts":
+		return ValidateContainerDNS(nil, nil, list)
+	}
+	return nil
+}
+
+// containerDNSDefaultsInternal reads the environment's container DNS
+// defaults from the settings.
+func (s *ContainerService) containerDNSDefaultsInternal(ctx context.Context) containerDNSDefaults {
+	if s.settingsService == nil {
+		return containerDNSDefaults{}
+	}
+	return containerDNSDefaults{
+		Servers:    parseSettingListInternal(s.settingsService.GetStringSetting(ctx, "containerDnsServers", "")),
+		Search:     parseSettingListInternal(s.settingsService.GetStringSetting(ctx, "containerDnsSearch", "")),
+		ExtraHosts: parseSettingListInternal(s.settingsService.GetStringSetting(ctx, "containerExtraHosts", "")),
+	}
+}
+
+// applyContainerDNSDefaultsInternal fills in the DNS servers and search
+// domains hostConfig leaves empty and adds the default extra hosts whose
+// hostname it does not already map. Containers sharing another container's
+// network stack are left alone, and host or none networking keeps the
+// daemon's resolver.
+func applyContainerDNSDefaultsInternal(hostConfig *container.HostConfig, defaults containerDNSDefaults) {
+	mode := hostConfig.NetworkMode
+	if mode.IsContainer() {
+		return
+	}
+
+	if !mode.IsHost() && !mode.IsNone() {
+		if len(hostConfig.DNS) == 0 && len(defaults.Servers) > 0 {
+			hostConfig.DNS = slices.Clone(defaults.Servers)
+		}
+		if len(hostConfig.DNSSearch) == 0 && len(defaults.Search) > 0 {
+			hostConfig.DNSSearch = slices.Clone(defaults.Search)
+		}
+	}
+
+	mapped := map[string]bool{}
+	for _, entry := range hostConfig.ExtraHosts {
+		if host, _, err := splitExtraHostInternal(entry); err == nil {
+			mapped[host] = true
+		}
+	}
+	for _, entry := range defaults.ExtraHosts {
+		host, _, err := splitExtraHostInternal(entry)
+		if err != nil || mapped[host] {
+			continue
+		}
+		mapped[host] = true
+		hostConfig.ExtraHosts = append(hostConfig.ExtraHosts, entry)
+	}
+}
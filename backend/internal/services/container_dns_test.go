@@ -0,0 +1,67 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateContainerDNS(t *testing.T) {
+	require.NoError(t, ValidateContainerDNS(
+		[]string{"192.168.1.53", "fd00::53"},
+		[]string{"home.lan", "corp.example.com"},
+		[]string{"nas.home.lan:192.168.1.10", "v6.home.lan:fd00::10", "host.docker.internal:host-gateway", "eq.home.lan=10.0.0.1"},
+	))
+
+	require.ErrorIs(t, ValidateContainerDNS([]string{"dns.home.lan"}, nil, nil), ErrInvalidDNSConfig)
+	require.ErrorIs(t, ValidateContainerDNS(nil, []string{"home lan"}, nil), ErrInvalidDNSConfig)
+	require.ErrorIs(t, ValidateContainerDNS(nil, nil, []string{"nas.home.lan"}), ErrInvalidDNSConfig)
+	require.ErrorIs(t, ValidateContainerDNS(nil, nil, []string{"nas.home.lan:not-an-ip"}), ErrInvalidDNSConfig)
+	require.ErrorIs(t, ValidateContainerDNS(nil, nil, []string{":10.0.0.1"}), ErrInvalidDNSConfig)
+}
+
+func TestValidateContainerDNSSetting(t *testing.T) {
+	require.NoError(t, validateContainerDNSSettingInternal("containerDnsServers", "1.1.1.1, 9.9.9.9\n"))
+	require.NoError(t, validateContainerDNSSettingInternal("containerExtraHosts", "a.lan:10.0.0.1\nb.lan:10.0.0.2"))
+	require.NoError(t, validateContainerDNSSettingInternal("containerDnsSearch", ""))
+	require.ErrorIs(t, validateContainerDNSSettingInternal("containerDnsServers", "1.1.1.1,nope"), ErrInvalidDNSConfig)
+}
+
+func TestApplyContainerDNSDefaults(t *testing.T) {
+	defaults := containerDNSDefaults{
+		Servers:    []string{"192.168.1.53"},
+		Search:     []string{"home.lan"},
+		ExtraHosts: []string{"nas.home.lan:192.168.1.10", "printer.home.lan:192.168.1.20"},
+	}
+
+	hostConfig := &container.HostConfig{}
+	applyContainerDNSDefaultsInternal(hostConfig, defaults)
+	assert.Equal(t, []string{"192.168.1.53"}, hostConfig.DNS)
+	assert.Equal(t, []string{"home.lan"}, hostConfig.DNSSearch)
+	assert.Equal(t, defaults.ExtraHosts, hostConfig.ExtraHosts)
+
+	// The container's own settings win.
+	hostConfig = &container.HostConfig{
+		DNS:        []string{"1.1.1.1"},
+		ExtraHosts: []string{"nas.home.lan:10.0.0.5"},
+	}
+	applyContainerDNSDefaultsInternal(hostConfig, defaults)
+	assert.Equal(t, []string{"1.1.1.1"}, hostConfig.DNS)
+	assert.Equal(t, []string{"home.lan"}, hostConfig.DNSSearch)
+	assert.Equal(t, []string{"nas.home.lan:10.0.0.5", "printer.home.lan:192.168.1.20"}, hostConfig.ExtraHosts)
+
+	// Host networking keeps the daemon's resolver but still gets extra hosts.
+	hostConfig = &container.HostConfig{NetworkMode: "host"}
+	applyContainerDNSDefaultsInternal(hostConfig, defaults)
+	assert.Empty(t, hostConfig.DNS)
+	assert.Empty(t, hostConfig.DNSSearch)
+	assert.Len(t, hostConfig.ExtraHosts, 2)
+
+	// Containers sharing another container's network are left alone.
+	hostConfig = &container.HostConfig{NetworkMode: "container:db"}
+	applyContainerDNSDefaultsInternal(hostConfig, defaults)
+	assert.Empty(t, hostConfig.DNS)
+	assert.Empty(t, hostConfig.ExtraHosts)
+}
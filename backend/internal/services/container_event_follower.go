@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/filters"
+)
+
+const containerEventsReconnectDelay = 5 * time.Second
+
+// followContainerEventsInternal passes every Docker container event with one
+// of the given actions to handle until ctx is done, reconnecting when the
+// stream breaks, for example while the daemon restarts. stream names the
+// follower in logs.
+func followContainerEventsInternal(ctx context.Context, dockerService *DockerClientService, stream string, actions []events.Action, handle func(events.Message)) {
+	for {
+		err := watchContainerEventsInternal(ctx, dockerService, actions, handle)
+		if ctx.Err() != nil {
+			return
+		}
+		slog.WarnContext(ctx, "Docker event stream ended, reconnecting", "stream", stream, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(containerEventsReconnectDelay):
+		}
+	}
+}
+
+func watchContainerEventsInternal(ctx context.Context, dockerService *DockerClientService, actions []events.Action, handle func(events.Message)) error {
+	dockerClient, err := dockerService.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	args := filters.NewArgs(filters.Arg("type", string(events.ContainerEventType)))
+	for _, action := range actions {
+		args.Add("event", string(action))
+	}
+	msgs, errs := dockerClient.Events(ctx, events.ListOptions{Filters: args})
+	for {
+		select {
+		case msg := <-msgs:
+			handle(msg)
+		case err := <-errs:
+			return err
+		}
+	}
+}
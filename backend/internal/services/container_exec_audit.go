@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+const defaultExecTranscriptMaxKB = 64
+
+// ExecAudit records an interactive exec session as a pair of container
+// events: one when the session starts and one when it ends. When transcript
+// recording is enabled, the end event also holds the keystrokes sent to the
+// session and the output it produced, up to the configured size.
+type ExecAudit struct {
+	eventService  *EventService
+	containerID   string
+	containerName string
+	execID        string
+	command       string
+	user          models.User
+	startedAt     time.Time
+
+	transcript *execTranscript
+	endOnce    sync.Once
+}
+
+// StartExecAudit records the start of an exec session and returns the audit
+// that records its end.
+func (s *ContainerService) StartExecAudit(ctx context.Context, containerID, execID string, cmd []string, opts ExecOptions, user models.User) *ExecAudit {
+	audit := &ExecAudit{
+		eventService:  s.eventService,
+		containerID:   containerID,
+		containerName: containerID,
+		execID:        execID,
+		command:       strings.Join(cmd, " "),
+		user:          user,
+		startedAt:     time.Now().UTC(),
+	}
+
+	if dockerClient, err := s.dockerService.GetClient(); err == nil {
+		if inspect, err := dockerClient.ContainerInspect(ctx, containerID); err == nil && inspect.ContainerJSONBase != nil {
+			audit.containerName = strings.TrimPrefix(inspect.Name, "/")
+		}
+	}
+
+	if s.settingsService != nil && s.settingsService.GetBoolSetting(ctx, "execAuditTranscriptEnabled", false) {
+		maxKB := s.settingsService.GetIntSetting(ctx, "execAuditTranscriptMaxKb", defaultExecTranscriptMaxKB)
+		if maxKB <= 0 {
+			maxKB = defaultExecTranscriptMaxKB
+		}
+		audit.transcript = newExecTranscriptInternal(maxKB * 1024)
+	}
+
+	metadata := models.JSON{
+		"execId":     execID,
+		"command":    audit.command,
+		"startedAt":  audit.startedAt,
+		"transcript": audit.transcript != nil,
+	}
+	if opts.User != "" {
+		metadata["execUser"] = opts.User
+	}
+	if opts.WorkingDir != "" {
+		metadata["workingDir"] = opts.WorkingDir
+	}
+	if len(opts.Env) > 0 {
+		// Values may hold secrets, so only the variable names are recorded.
+		keys := make([]string, 0, len(opts.Env))
+		for _, kv := range opts.Env {
+			key, _, _ := strings.Cut(kv, "=")
+			keys = append(keys, key)
+		}
+		metadata["envKeys"] = keys
+	}
+	audit.logInternal(ctx, models.EventTypeContainerExecStart, metadata)
+	return audit
+}
+
+// RecordInput adds keystrokes sent to the session to the transcript.
+func (a *ExecAudit) RecordInput(p []byte) {
+	if a != nil && a.transcript != nil {
+		a.transcript.write(&a.transcript.input, p)
+	}
+}
+
+// Output returns a writer that adds session output to the transcript.
+func (a *ExecAudit) Output() io.Writer {
+	return execOutputRecorder{audit: a}
+}
+
+// End records the end of the session. Only the first call has an effect.
+func (a *ExecAudit) End(ctx context.Context) {
+	if a == nil {
+		return
+	}
+	a.endOnce.Do(func() {
+		endedAt := time.Now().UTC()
+		metadata := models.JSON{
+			"execId":          a.execID,
+			"command":         a.command,
+			"startedAt":       a.startedAt,
+			"endedAt":         endedAt,
+			"durationSeconds": int(endedAt.Sub(a.startedAt).Seconds()),
+		}
+		if a.transcript != nil {
+			input, output, truncated := a.transcript.snapshot()
+			metadata["input"] = input
+			metadata["output"] = output
+			metadata["transcriptTruncated"] = truncated
+			metadata["transcriptLimitKb"] = a.transcript.limit / 1024
+		}
+		a.logInternal(ctx, models.EventTypeContainerExecEnd, metadata)
+	})
+}
+
+func (a *ExecAudit) logInternal(ctx context.Context, eventType models.EventType, metadata models.JSON) {
+	if a.eventService == nil {
+		return
+	}
+	if err := a.eventService.LogContainerEvent(ctx, eventType, a.containerID, a.containerName, a.user.ID, a.user.Username, "0", metadata); err != nil {
+		slog.WarnContext(ctx, "Could not log exec session event", "execID", a.execID, "containerID", a.containerID, "error", err)
+	}
+}
+
+// execOutputRecorder is the io.Writer returned by ExecAudit.Output.
+type execOutputRecorder struct{ audit *ExecAudit }
+
+func (w execOutputRecorder) Write(p []byte) (int, error) {
+	if w.audit != nil && w.audit.transcript != nil {
+		w.audit.transcript.write(&w.audit.transcript.output, p)
+	}
+	return len(p), nil
+}
+
+// execTranscript keeps the input and output of a session. Both share one
+// size limit; whatever arrives after it is reached is dropped.
+type execTranscript struct {
+	mu        sync.Mutex
+	limit     int
+	input     strings.Builder
+	output    strings.Builder
+	truncated bool
+}
+
+func newExecTranscriptInternal(limit int) *execTranscript {
+	return &execTranscript{limit: limit}
+}
+
+func (t *execTranscript) write(buf *strings.Builder, p []byte) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	remaining := t.limit - t.input.Len() - t.output.Len()
+	if len(p) > remaining {
+		p = p[:max(remaining, 0)]
+		t.truncated = true
+	}
+	buf.Write(p)
+}
+
+// snapshot returns the recorded input and output as valid UTF-8.
+func (t *execTranscript) snapshot() (input, output string, truncated bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return strings.ToValidUTF8(t.input.String(), "�"), strings.ToValidUTF8(t.output.String(), "�"), t.truncated
+}
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+func TestExecTranscript_Limit(t *testing.T) {
+	transcript := newExecTranscriptInternal(10)
+	transcript.write(&transcript.input, []byte("ls\r"))
+	transcript.write(&transcript.output, []byte("bin etc usr"))
+	transcript.write(&transcript.input, []byte("exit\r"))
+
+	input, output, truncated := transcript.snapshot()
+	assert.Equal(t, "ls\r", input)
+	assert.Equal(t, "bin etc", output)
+	assert.True(t, truncated)
+}
+
+func TestExecAudit_End(t *testing.T) {
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Event{}))
+	db := &database.DB{DB: gdb}
+
+	audit := &ExecAudit{
+		eventService:  NewEventService(db),
+		containerID:   "abc123",
+		containerName: "web",
+		execID:        "exec1",
+		command:       "/bin/sh",
+		user:          models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "alice"},
+		startedAt:     time.Now().Add(-5 * time.Second).UTC(),
+		transcript:    newExecTranscriptInternal(1024),
+	}
+	audit.RecordInput([]byte("id\r"))
+	_, err = audit.Output().Write([]byte("uid=0(root)\r\n"))
+	require.NoError(t, err)
+
+	audit.End(context.Background())
+	audit.End(context.Background())
+
+	var events []models.Event
+	require.NoError(t, gdb.Find(&events).Error)
+	require.Len(t, events, 1)
+	assert.Equal(t, models.EventTypeContainerExecEnd, events[0].Type)
+	assert.Equal(t, "Terminal closed: web", events[0].Title)
+	assert.Equal(t, "alice", *events[0].Username)
+	assert.Equal(t, "/bin/sh", events[0].Metadata["command"])
+	assert.Equal(t, "id\r", events[0].Metadata["input"])
+	assert.Equal(t, "uid=0(root)\r\n", events[0].Metadata["output"])
+	assert.Equal(t, false, events[0].Metadata["transcriptTruncated"])
+	assert.EqualValues(t, 5, events[0].Metadata["durationSeconds"])
+}
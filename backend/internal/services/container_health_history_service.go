@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+)
+
+const (
+	defaultHealthFlapThreshold   = 5
+	healthFlapWindow             = time.Hour
+	healthHistoryRetention       = 31 * 24 * time.Hour
+	healthHistoryPruneInterval   = time.Hour
+	defaultHealthHistoryDuration = 24 * time.Hour
+)
+
+// ContainerHealthHistoryService follows Docker health_status events and
+// records every health transition of a container. A container whose health
+// changes more often than the flap threshold within an hour is logged as
+// flapping and reported through notifications.
+type ContainerHealthHistoryService struct {
+	db                  *database.DB
+	dockerService       *DockerClientService
+	eventService        *EventService
+	notificationService *NotificationService
+	settingsService     *SettingsService
+
+	mu         sync.Mutex
+	last       map[string]string
+	lastAlerts map[string]time.Time
+	lastPrune  time.Time
+}
+
+func NewContainerHealthHistoryService(db *database.DB, dockerService *DockerClientService, eventService *EventService, notificationService *NotificationService, settingsService *SettingsService) *ContainerHealthHistoryService {
+	return &ContainerHealthHistoryService{
+		db:                  db,
+		dockerService:       dockerService,
+		eventService:        eventService,
+		notificationService: notificationService,
+		settingsService:     settingsService,
+		last:                map[string]string{},
+		lastAlerts:          map[string]time.Time{},
+	}
+}
+
+// healthHistoryEventActions are the Docker events the service follows.
+var healthHistoryEventActions = []events.Action{events.ActionHealthStatus}
+
+// Run follows the Docker event stream until ctx is done.
+func (s *ContainerHealthHistoryService) Run(ctx context.Context) {
+	followContainerEventsInternal(ctx, s.dockerService, "health", healthHistoryEventActions, func(msg events.Message) {
+		s.handleEventInternal(ctx, msg)
+	})
+}
+
+func (s *ContainerHealthHistoryService) handleEventInternal(ctx context.Context, msg events.Message) {
+	status := strings.TrimSpace(strings.TrimPrefix(string(msg.Action), string(events.ActionHealthStatus)+":"))
+	at := time.Unix(0, msg.TimeNano)
+	if err := s.RecordTransition(ctx, msg.Actor.ID, msg.Actor.Attributes["name"], status, at); err != nil {
+		slog.WarnContext(ctx, "Failed to record container health transition", "container", msg.Actor.ID, "error", err)
+	}
+}
+
+// RecordTransition stores a health status reported for a container. Reports
+// that repeat the last known status of the container are ignored.
+func (s *ContainerHealthHistoryService) RecordTransition(ctx context.Context, containerID, containerName, status string, at time.Time) error {
+	containerName = strings.TrimPrefix(containerName, "/")
+	if containerName == "" {
+		containerName = containerID
+	}
+	switch status {
+	case HealthStatusStarting, HealthStatusHealthy, HealthStatusUnhealthy:
+	case "running":
+		// Older daemons report the starting state as running.
+		status = HealthStatusStarting
+	default:
+		return fmt.Errorf("unknown health status %q", status)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.last[containerName] == status {
+		return nil
+	}
+
+	transition := models.ContainerHealthTransition{
+		ContainerID:   containerID,
+		ContainerName: containerName,
+		Status:        status,
+		At:            at.UTC(),
+	}
+	if err := s.db.WithContext(ctx).Create(&transition).Error; err != nil {
+		return fmt.Errorf("failed to save container health transition: %w", err)
+	}
+	s.last[containerName] = status
+
+	s.checkFlappingInternal(ctx, &transition)
+
+	if at.Sub(s.lastPrune) >= healthHistoryPruneInterval {
+		s.lastPrune = at
+		if err := s.db.WithContext(ctx).Where("at < ?", at.Add(-healthHistoryRetention).UTC()).Delete(&models.ContainerHealthTransition{}).Error; err != nil {
+			slog.WarnContext(ctx, "Failed to prune container health history", "error", err)
+		}
+	}
+	return nil
+}
+
+// checkFlappingInternal reports the container of transition as flapping when
+// it changed health more often than the threshold in the last hour. A
+// container is reported at most once per hour.
+func (s *ContainerHealthHistoryService) checkFlappingInternal(ctx context.Context, transition *models.ContainerHealthTransition) {
+	threshold := s.flapThresholdInternal(ctx)
+	if threshold <= 0 {
+		return
+	}
+	if last, ok := s.lastAlerts[transition.ContainerName]; ok && transition.At.Sub(last) < healthFlapWindow {
+		return
+	}
+
+	count, err := s.countTransitionsInternal(ctx, transition.ContainerName, transition.At.Add(-healthFlapWindow))
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to count container health transitions", "container", transition.ContainerName, "error", err)
+		return
+	}
+	if count <= threshold {
+		return
+	}
+	s.lastAlerts[transition.ContainerName] = transition.At
+
+	if s.eventService != nil {
+		resourceType := "container"
+		environmentID := "0"
+		_, err := s.eventService.CreateEvent(ctx, CreateEventRequest{
+			Type:          models.EventTypeContainerFlapping,
+			Severity:      s.eventService.getEventSeverity(models.EventTypeContainerFlapping),
+			Title:         s.eventService.generateEventTitle(models.EventTypeContainerFlapping, transition.ContainerName),
+			Description:   s.eventService.generateEventDescription(models.EventTypeContainerFlapping, resourceType, transition.ContainerName),
+			ResourceType:  &resourceType,
+			ResourceID:    &transition.ContainerID,
+			ResourceName:  &transition.ContainerName,
+			UserID:        &systemUser.ID,
+			Username:      &systemUser.Username,
+			EnvironmentID: &environmentID,
+			Metadata: models.JSON{
+				"transitions": count,
+				"threshold":   threshold,
+				"status":      transition.Status,
+			},
+		})
+		if err != nil {
+			slog.WarnContext(ctx, "Could not log container flapping event", "container", transition.ContainerName, "error", err)
+		}
+	}
+
+	if s.notificationService != nil {
+		err := s.notificationService.SendHealthFlapNotification(ctx, HealthFlapNotificationPayload{
+			Container:   transition.ContainerName,
+			Status:      transition.Status,
+			Transitions: count,
+			Threshold:   threshold,
+		})
+		if err != nil {
+			slog.WarnContext(ctx, "Failed to send container flapping notification", "container", transition.ContainerName, "error", err)
+		}
+	}
+}
+
+// GetHistory returns the health transitions of a container recorded within
+// the given duration, newest first. The container is looked up by name or
+// by the ID it had when a transition was recorded. A zero duration returns
+// the last 24 hours.
+func (s *ContainerHealthHistoryService) GetHistory(ctx context.Context, containerKey string, since time.Duration) (*containertypes.HealthHistory, error) {
+	containerKey = strings.TrimPrefix(strings.TrimSpace(containerKey), "/")
+	if since <= 0 {
+		since = defaultHealthHistoryDuration
+	}
+
+	name := containerKey
+	var latest models.ContainerHealthTransition
+	err := s.db.WithContext(ctx).
+		Where("container_id = ? OR container_id LIKE ?", containerKey, containerKey+"%").
+		Order("at DESC").
+		Limit(1).
+		Find(&latest).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up container health history: %w", err)
+	}
+	if latest.ContainerName != "" {
+		name = latest.ContainerName
+	}
+
+	now := time.Now().UTC()
+	var transitions []models.ContainerHealthTransition
+	err = s.db.WithContext(ctx).
+		Where("container_name = ? AND at >= ?", name, now.Add(-since)).
+		Order("at DESC").
+		Find(&transitions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load container health history: %w", err)
+	}
+
+	out := &containertypes.HealthHistory{
+		ContainerName: name,
+		Transitions:   make([]containertypes.HealthTransition, 0, len(transitions)),
+	}
+	for _, t := range transitions {
+		out.Transitions = append(out.Transitions, containertypes.HealthTransition{
+			ContainerID: t.ContainerID,
+			Status:      t.Status,
+			At:          t.At,
+		})
+	}
+	out.TransitionsLastHour, err = s.countTransitionsInternal(ctx, name, now.Add(-healthFlapWindow))
+	if err != nil {
+		return nil, err
+	}
+	threshold := s.flapThresholdInternal(ctx)
+	out.Flapping = threshold > 0 && out.TransitionsLastHour > threshold
+	return out, nil
+}
+
+func (s *ContainerHealthHistoryService) countTransitionsInternal(ctx context.Context, containerName string, since time.Time) (int, error) {
+	var count int64
+	err := s.db.WithContext(ctx).Model(&models.ContainerHealthTransition{}).
+		Where("container_name = ? AND at >= ?", containerName, since.UTC()).
+		Count(&count).Error
+	if err != nil {
+		return 0, fmt.Errorf("failed to count container health transitions: %w", err)
+	}
+	return int(count), nil
+}
+
+func (s *ContainerHealthHistoryService) flapThresholdInternal(ctx context.Context) int {
+	if s.settingsService == nil {
+		return defaultHealthFlapThreshold
+	}
+	return s.settingsService.GetIntSetting(ctx, "healthFlapThreshold", defaultHealthFlapThreshold)
+}
@@ -0,0 +1,117 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+func newHealthHistoryTestService(t *testing.T, docker *DockerClientService) (*ContainerHealthHistoryService, *gorm.DB) {
+	t.Helper()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.ContainerHealthTransition{}, &models.Event{}))
+	db := &database.DB{DB: gdb}
+	return NewContainerHealthHistoryService(db, docker, NewEventService(db), nil, nil), gdb
+}
+
+func TestContainerHealthHistoryService_RecordTransition(t *testing.T) {
+	ctx := context.Background()
+	svc, gdb := newHealthHistoryTestService(t, nil)
+	start := time.Now().Add(-30 * time.Minute)
+
+	require.NoError(t, svc.RecordTransition(ctx, "abc123", "/web", "running", start))
+	require.NoError(t, svc.RecordTransition(ctx, "abc123", "/web", HealthStatusStarting, start.Add(time.Second)))
+	require.Error(t, svc.RecordTransition(ctx, "abc123", "/web", "sideways", start))
+
+	// Six transitions in an hour are above the default threshold of five.
+	status := HealthStatusHealthy
+	for i := range 6 {
+		require.NoError(t, svc.RecordTransition(ctx, "abc123", "/web", status, start.Add(time.Duration(i+1)*time.Minute)))
+		if status == HealthStatusHealthy {
+			status = HealthStatusUnhealthy
+		} else {
+			status = HealthStatusHealthy
+		}
+	}
+	// Further flapping within the hour is not reported again.
+	require.NoError(t, svc.RecordTransition(ctx, "abc123", "/web", status, start.Add(10*time.Minute)))
+
+	var events []models.Event
+	require.NoError(t, gdb.Find(&events).Error)
+	require.Len(t, events, 1)
+	assert.Equal(t, models.EventTypeContainerFlapping, events[0].Type)
+	assert.Equal(t, "Container flapping: web", events[0].Title)
+	assert.EqualValues(t, 6, events[0].Metadata["transitions"])
+
+	history, err := svc.GetHistory(ctx, "abc", 0)
+	require.NoError(t, err)
+	assert.Equal(t, "web", history.ContainerName)
+	require.Len(t, history.Transitions, 8)
+	assert.Equal(t, HealthStatusStarting, history.Transitions[7].Status)
+	assert.Equal(t, status, history.Transitions[0].Status)
+	assert.Equal(t, 8, history.TransitionsLastHour)
+	assert.True(t, history.Flapping)
+
+	// A recreated container keeps the history of its name.
+	require.NoError(t, svc.RecordTransition(ctx, "def456", "web", HealthStatusStarting, start.Add(11*time.Minute)))
+	history, err = svc.GetHistory(ctx, "web", time.Hour)
+	require.NoError(t, err)
+	require.Len(t, history.Transitions, 9)
+	assert.Equal(t, "def456", history.Transitions[0].ContainerID)
+
+	history, err = svc.GetHistory(ctx, "other", 0)
+	require.NoError(t, err)
+	assert.Empty(t, history.Transitions)
+	assert.False(t, history.Flapping)
+}
+
+func TestContainerHealthHistoryService_WatchEvents(t *testing.T) {
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		if path != "/events" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		assert.Contains(t, r.URL.Query().Get("filters"), "health_status")
+		w.Header().Set("Content-Type", "application/json")
+		for i, status := range []string{"healthy", "unhealthy"} {
+			at := strconv.FormatInt(time.Now().Add(time.Duration(i-2)*time.Minute).UnixNano(), 10)
+			_, _ = io.WriteString(w, `{"Type":"container","Action":"health_status: `+status+
+				`","Actor":{"ID":"abc","Attributes":{"name":"web"}},"timeNano":`+at+"}\n")
+		}
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	svc, gdb := newHealthHistoryTestService(t, &DockerClientService{client: cli})
+
+	// The stream ends when the fake daemon closes the connection.
+	ctx := context.Background()
+	require.Error(t, watchContainerEventsInternal(ctx, svc.dockerService, healthHistoryEventActions, func(msg events.Message) {
+		svc.handleEventInternal(ctx, msg)
+	}))
+
+	var transitions []models.ContainerHealthTransition
+	require.NoError(t, gdb.Order("at ASC").Find(&transitions).Error)
+	require.Len(t, transitions, 2)
+	assert.Equal(t, HealthStatusHealthy, transitions[0].Status)
+	assert.Equal(t, "web", transitions[0].ContainerName)
+	assert.Equal(t, HealthStatusUnhealthy, transitions[1].Status)
+}
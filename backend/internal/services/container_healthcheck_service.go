@@ -0,0 +1,459 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+)
+
+const (
+	HealthcheckTypeHTTP    = "http"
+	HealthcheckTypeTCP     = "tcp"
+	HealthcheckTypeCommand = "command"
+
+	HealthStatusStarting  = "starting"
+	HealthStatusHealthy   = "healthy"
+	HealthStatusUnhealthy = "unhealthy"
+
+	defaultHealthcheckInterval = 30
+	defaultHealthcheckTimeout  = 5
+	defaultHealthcheckRetries  = 3
+	minHealthcheckInterval     = 5
+	maxHealthcheckOutput       = 512
+	maxParallelHealthchecks    = 8
+)
+
+var (
+	ErrContainerHealthcheckNotFound = errors.New("container healthcheck not found")
+	ErrInvalidContainerHealthcheck  = errors.New("invalid container healthcheck")
+)
+
+// healthcheckSystemUser is the actor for restarts triggered by a failing
+// healthcheck.
+var healthcheckSystemUser = models.User{Username: "System"}
+
+// ContainerHealthcheckService runs healthchecks defined in Arcane against
+// containers whose images do not provide one. Results are stored with the
+// definition and surfaced as the container's health in listings.
+type ContainerHealthcheckService struct {
+	db            *database.DB
+	dockerService *DockerClientService
+	eventService  *EventService
+	httpClient    *http.Client
+	running       atomic.Bool
+}
+
+func NewContainerHealthcheckService(db *database.DB, dockerService *DockerClientService, eventService *EventService) *ContainerHealthcheckService {
+	return &ContainerHealthcheckService{
+		db:            db,
+		dockerService: dockerService,
+		eventService:  eventService,
+		httpClient: &http.Client{
+			// A redirect is a response from a live service; don't follow it.
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+	}
+}
+
+// ListHealthchecks returns all Arcane-managed healthchecks.
+func (s *ContainerHealthcheckService) ListHealthchecks(ctx context.Context) ([]containertypes.Healthcheck, error) {
+	var checks []models.ContainerHealthcheck
+	if err := s.db.WithContext(ctx).Order("container_key ASC").Find(&checks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list container healthchecks: %w", err)
+	}
+
+	out := make([]containertypes.Healthcheck, 0, len(checks))
+	for _, c := range checks {
+		out = append(out, toContainerHealthcheckDTO(c))
+	}
+	return out, nil
+}
+
+// SetHealthcheck creates or replaces the healthcheck for the container
+// identified by key, which may be a container name or ID. Replacing a
+// healthcheck resets its status to starting.
+func (s *ContainerHealthcheckService) SetHealthcheck(ctx context.Context, key string, req containertypes.HealthcheckRequest, user models.User) (*containertypes.Healthcheck, error) {
+	key = strings.TrimPrefix(strings.TrimSpace(key), "/")
+	if key == "" {
+		return nil, fmt.Errorf("%w: container name or ID is required", ErrInvalidContainerHealthcheck)
+	}
+	req, err := normalizeHealthcheckRequestInternal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	var check models.ContainerHealthcheck
+	err = s.db.WithContext(ctx).Where("container_key = ?", key).First(&check).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to load container healthcheck: %w", err)
+	}
+
+	check.ContainerKey = key
+	check.Type = req.Type
+	check.Port = req.Port
+	check.Path = req.Path
+	check.Command = req.Command
+	check.IntervalSeconds = req.IntervalSeconds
+	check.TimeoutSeconds = req.TimeoutSeconds
+	check.Retries = req.Retries
+	check.AutoRestart = req.AutoRestart
+	check.Status = HealthStatusStarting
+	check.FailingStreak = 0
+	check.LastOutput = ""
+	check.LastCheckedAt = nil
+	check.UpdatedBy = user.Username
+
+	if check.ID == "" {
+		err = s.db.WithContext(ctx).Create(&check).Error
+	} else {
+		now := time.Now()
+		check.UpdatedAt = &now
+		err = s.db.WithContext(ctx).Save(&check).Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save container healthcheck: %w", err)
+	}
+
+	out := toContainerHealthcheckDTO(check)
+	return &out, nil
+}
+
+// DeleteHealthcheck removes the healthcheck for key.
+func (s *ContainerHealthcheckService) DeleteHealthcheck(ctx context.Context, key string) error {
+	key = strings.TrimPrefix(strings.TrimSpace(key), "/")
+	result := s.db.WithContext(ctx).Where("container_key = ?", key).Delete(&models.ContainerHealthcheck{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete container healthcheck: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrContainerHealthcheckNotFound
+	}
+	return nil
+}
+
+// RunDueChecks probes every container whose healthcheck interval has
+// elapsed and returns the number of probes run. Calls made while a previous
+// run is still in progress return immediately.
+func (s *ContainerHealthcheckService) RunDueChecks(ctx context.Context) int {
+	if !s.running.CompareAndSwap(false, true) {
+		return 0
+	}
+	defer s.running.Store(false)
+
+	var checks []models.ContainerHealthcheck
+	if err := s.db.WithContext(ctx).Find(&checks).Error; err != nil {
+		slog.WarnContext(ctx, "Failed to load container healthchecks", "error", err)
+		return 0
+	}
+
+	now := time.Now()
+	due := make([]models.ContainerHealthcheck, 0, len(checks))
+	for _, c := range checks {
+		if c.LastCheckedAt == nil || now.Sub(*c.LastCheckedAt) >= time.Duration(c.IntervalSeconds)*time.Second {
+			due = append(due, c)
+		}
+	}
+	if len(due) == 0 {
+		return 0
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		slog.WarnContext(ctx, "Skipping container healthchecks, Docker is unavailable", "error", err)
+		return 0
+	}
+
+	sem := make(chan struct{}, maxParallelHealthchecks)
+	var wg sync.WaitGroup
+	for i := range due {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(check *models.ContainerHealthcheck) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.runCheckInternal(ctx, dockerClient, check)
+		}(&due[i])
+	}
+	wg.Wait()
+	return len(due)
+}
+
+func (s *ContainerHealthcheckService) runCheckInternal(ctx context.Context, dockerClient *client.Client, check *models.ContainerHealthcheck) {
+	inspect, err := dockerClient.ContainerInspect(ctx, check.ContainerKey)
+	if err != nil {
+		if !cerrdefs.IsNotFound(err) {
+			slog.WarnContext(ctx, "Failed to inspect container for healthcheck", "container", check.ContainerKey, "error", err)
+		}
+		return
+	}
+	if inspect.ContainerJSONBase == nil || inspect.State == nil || !inspect.State.Running {
+		// A stopped container starts over once it runs again.
+		if check.Status != HealthStatusStarting || check.FailingStreak != 0 {
+			check.Status = HealthStatusStarting
+			check.FailingStreak = 0
+			s.saveResultInternal(ctx, check)
+		}
+		return
+	}
+	name := strings.TrimPrefix(inspect.Name, "/")
+
+	probeCtx, cancel := context.WithTimeout(ctx, time.Duration(check.TimeoutSeconds)*time.Second)
+	output, probeErr := s.probeInternal(probeCtx, dockerClient, check, inspect)
+	cancel()
+
+	now := time.Now()
+	check.LastCheckedAt = &now
+	previous := check.Status
+	if probeErr == nil {
+		check.LastOutput = truncateHealthOutputInternal(output)
+		check.FailingStreak = 0
+		check.Status = HealthStatusHealthy
+	} else {
+		check.LastOutput = truncateHealthOutputInternal(probeErr.Error())
+		check.FailingStreak++
+		if check.FailingStreak >= check.Retries {
+			check.Status = HealthStatusUnhealthy
+		}
+	}
+
+	restart := check.Status == HealthStatusUnhealthy && previous != HealthStatusUnhealthy && check.AutoRestart
+	if restart {
+		// The restarted container gets a fresh start period.
+		check.Status = HealthStatusStarting
+		check.FailingStreak = 0
+	}
+	s.saveResultInternal(ctx, check)
+
+	metadata := models.JSON{
+		"action":        "healthcheck",
+		"containerKey":  check.ContainerKey,
+		"type":          check.Type,
+		"failingStreak": check.FailingStreak,
+		"output":        check.LastOutput,
+	}
+	switch {
+	case restart || (check.Status == HealthStatusUnhealthy && previous != HealthStatusUnhealthy):
+		metadata["autoRestart"] = restart
+		if err := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerUnhealthy, inspect.ID, name, healthcheckSystemUser.ID, healthcheckSystemUser.Username, "0", metadata); err != nil {
+			slog.WarnContext(ctx, "Could not log container healthcheck event", "container", name, "error", err)
+		}
+	case check.Status == HealthStatusHealthy && previous == HealthStatusUnhealthy:
+		if err := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerHealthy, inspect.ID, name, healthcheckSystemUser.ID, healthcheckSystemUser.Username, "0", metadata); err != nil {
+			slog.WarnContext(ctx, "Could not log container healthcheck event", "container", name, "error", err)
+		}
+	}
+
+	if restart {
+		slog.InfoContext(ctx, "Restarting unhealthy container", "container", name)
+		if err := dockerClient.ContainerRestart(ctx, inspect.ID, container.StopOptions{}); err != nil {
+			s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", inspect.ID, name, healthcheckSystemUser.ID, healthcheckSystemUser.Username, "0", err, models.JSON{"action": "healthcheck_restart"})
+			return
+		}
+		if err := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerRestart, inspect.ID, name, healthcheckSystemUser.ID, healthcheckSystemUser.Username, "0", models.JSON{"action": "healthcheck_restart"}); err != nil {
+			slog.WarnContext(ctx, "Could not log container restart", "container", name, "error", err)
+		}
+	}
+}
+
+func (s *ContainerHealthcheckService) saveResultInternal(ctx context.Context, check *models.ContainerHealthcheck) {
+	err := s.db.WithContext(ctx).Model(check).
+		Select("status", "failing_streak", "last_output", "last_checked_at").
+		Updates(check).Error
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to save container healthcheck result", "container", check.ContainerKey, "error", err)
+	}
+}
+
+// probeInternal runs a single probe and returns its output. A nil error
+// means the container is healthy.
+func (s *ContainerHealthcheckService) probeInternal(ctx context.Context, dockerClient *client.Client, check *models.ContainerHealthcheck, inspect container.InspectResponse) (string, error) {
+	if check.Type == HealthcheckTypeCommand {
+		return execHealthcheckInternal(ctx, dockerClient, inspect.ID, check.Command)
+	}
+
+	host, err := healthcheckHostInternal(inspect)
+	if err != nil {
+		return "", err
+	}
+	address := net.JoinHostPort(host, strconv.Itoa(check.Port))
+
+	if check.Type == HealthcheckTypeTCP {
+		var dialer net.Dialer
+		conn, err := dialer.DialContext(ctx, "tcp", address)
+		if err != nil {
+			return "", err
+		}
+		_ = conn.Close()
+		return "connected to " + address, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://"+address+check.Path, nil)
+	if err != nil {
+		return "", err
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		return "", fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return fmt.Sprintf("HTTP %d", resp.StatusCode), nil
+}
+
+// healthcheckHostInternal returns the address Arcane uses to reach the
+// container: loopback for host networking, otherwise the IP on its first
+// network by name.
+func healthcheckHostInternal(inspect container.InspectResponse) (string, error) {
+	if inspect.HostConfig != nil && inspect.HostConfig.NetworkMode.IsHost() {
+		return "127.0.0.1", nil
+	}
+	if inspect.NetworkSettings != nil {
+		names := make([]string, 0, len(inspect.NetworkSettings.Networks))
+		for name := range inspect.NetworkSettings.Networks {
+			names = append(names, name)
+		}
+		slices.Sort(names)
+		for _, name := range names {
+			if endpoint := inspect.NetworkSettings.Networks[name]; endpoint != nil && endpoint.IPAddress != "" {
+				return endpoint.IPAddress, nil
+			}
+		}
+	}
+	return "", errors.New("container has no IP address")
+}
+
+func execHealthcheckInternal(ctx context.Context, dockerClient *client.Client, containerID string, cmd []string) (string, error) {
+	execResp, err := dockerClient.ContainerExecCreate(ctx, containerID, container.ExecOptions{
+		Cmd:          cmd,
+		AttachStdout: true,
+		AttachStderr: true,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create exec: %w", err)
+	}
+
+	attachResp, err := dockerClient.ContainerExecAttach(ctx, execResp.ID, container.ExecAttachOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to attach to exec: %w", err)
+	}
+	defer attachResp.Close()
+
+	var output bytes.Buffer
+	if _, err := stdcopy.StdCopy(&output, &output, io.LimitReader(attachResp.Reader, 64*1024)); err != nil && ctx.Err() != nil {
+		return "", ctx.Err()
+	}
+
+	execInspect, err := dockerClient.ContainerExecInspect(ctx, execResp.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to inspect exec: %w", err)
+	}
+	text := strings.TrimSpace(output.String())
+	if execInspect.ExitCode != 0 {
+		if text == "" {
+			text = fmt.Sprintf("exit status %d", execInspect.ExitCode)
+		}
+		return "", errors.New(text)
+	}
+	return text, nil
+}
+
+func normalizeHealthcheckRequestInternal(req containertypes.HealthcheckRequest) (containertypes.HealthcheckRequest, error) {
+	req.Type = strings.ToLower(strings.TrimSpace(req.Type))
+	switch req.Type {
+	case HealthcheckTypeHTTP, HealthcheckTypeTCP:
+		if req.Port < 1 || req.Port > 65535 {
+			return req, fmt.Errorf("%w: %s checks need a port between 1 and 65535", ErrInvalidContainerHealthcheck, req.Type)
+		}
+		req.Command = nil
+		if req.Type == HealthcheckTypeTCP {
+			req.Path = ""
+			break
+		}
+		req.Path = strings.TrimSpace(req.Path)
+		if req.Path == "" {
+			req.Path = "/"
+		}
+		if !strings.HasPrefix(req.Path, "/") {
+			return req, fmt.Errorf("%w: path must start with '/'", ErrInvalidContainerHealthcheck)
+		}
+	case HealthcheckTypeCommand:
+		if len(req.Command) == 0 || strings.TrimSpace(req.Command[0]) == "" {
+			return req, fmt.Errorf("%w: command checks need a command", ErrInvalidContainerHealthcheck)
+		}
+		req.Port = 0
+		req.Path = ""
+	default:
+		return req, fmt.Errorf("%w: type must be http, tcp or command", ErrInvalidContainerHealthcheck)
+	}
+
+	if req.IntervalSeconds == 0 {
+		req.IntervalSeconds = defaultHealthcheckInterval
+	}
+	if req.IntervalSeconds < minHealthcheckInterval {
+		return req, fmt.Errorf("%w: interval must be at least %d seconds", ErrInvalidContainerHealthcheck, minHealthcheckInterval)
+	}
+	if req.TimeoutSeconds == 0 {
+		req.TimeoutSeconds = min(defaultHealthcheckTimeout, req.IntervalSeconds)
+	}
+	if req.TimeoutSeconds > req.IntervalSeconds {
+		return req, fmt.Errorf("%w: timeout must not exceed the interval", ErrInvalidContainerHealthcheck)
+	}
+	if req.Retries == 0 {
+		req.Retries = defaultHealthcheckRetries
+	}
+	return req, nil
+}
+
+func truncateHealthOutputInternal(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) > maxHealthcheckOutput {
+		return output[:maxHealthcheckOutput]
+	}
+	return output
+}
+
+func toContainerHealthcheckDTO(c models.ContainerHealthcheck) containertypes.Healthcheck {
+	return containertypes.Healthcheck{
+		ContainerKey:    c.ContainerKey,
+		Type:            c.Type,
+		Port:            c.Port,
+		Path:            c.Path,
+		Command:         c.Command,
+		IntervalSeconds: c.IntervalSeconds,
+		TimeoutSeconds:  c.TimeoutSeconds,
+		Retries:         c.Retries,
+		AutoRestart:     c.AutoRestart,
+		Status:          c.Status,
+		FailingStreak:   c.FailingStreak,
+		LastOutput:      c.LastOutput,
+		LastCheckedAt:   c.LastCheckedAt,
+		UpdatedBy:       c.UpdatedBy,
+		CreatedAt:       c.CreatedAt,
+		UpdatedAt:       c.UpdatedAt,
+	}
+}
@@ -0,0 +1,145 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+)
+
+func TestNormalizeHealthcheckRequest(t *testing.T) {
+	req, err := normalizeHealthcheckRequestInternal(containertypes.HealthcheckRequest{Type: "HTTP", Port: 8080, Command: []string{"x"}})
+	require.NoError(t, err)
+	assert.Equal(t, "http", req.Type)
+	assert.Equal(t, "/", req.Path)
+	assert.Nil(t, req.Command)
+	assert.Equal(t, 30, req.IntervalSeconds)
+	assert.Equal(t, 5, req.TimeoutSeconds)
+	assert.Equal(t, 3, req.Retries)
+
+	invalid := []containertypes.HealthcheckRequest{
+		{Type: "grpc", Port: 80},
+		{Type: "tcp"},
+		{Type: "http", Port: 80, Path: "health"},
+		{Type: "command"},
+		{Type: "tcp", Port: 80, IntervalSeconds: 2},
+		{Type: "tcp", Port: 80, IntervalSeconds: 10, TimeoutSeconds: 20},
+	}
+	for _, r := range invalid {
+		_, err := normalizeHealthcheckRequestInternal(r)
+		require.ErrorIs(t, err, ErrInvalidContainerHealthcheck, "%+v", r)
+	}
+}
+
+func TestContainerHealthcheckService_RunDueChecks(t *testing.T) {
+	ctx := context.Background()
+	appStatus := http.StatusOK
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(appStatus)
+	}))
+	defer app.Close()
+	appURL, err := url.Parse(app.URL)
+	require.NoError(t, err)
+	appPort, err := strconv.Atoi(appURL.Port())
+	require.NoError(t, err)
+
+	var restarts int
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && path == "/containers/web/json":
+			_, _ = io.WriteString(w, `{"Id":"abc","Name":"/web","State":{"Running":true},"HostConfig":{"NetworkMode":"bridge"},
+				"NetworkSettings":{"Networks":{"bridge":{"IPAddress":"127.0.0.1"}}}}`)
+		case r.Method == http.MethodGet && path == "/containers/stopped/json":
+			_, _ = io.WriteString(w, `{"Id":"def","Name":"/stopped","State":{"Running":false}}`)
+		case r.Method == http.MethodPost && path == "/containers/abc/restart":
+			restarts++
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.ContainerHealthcheck{}, &models.Event{}))
+	db := &database.DB{DB: gdb}
+	svc := NewContainerHealthcheckService(db, &DockerClientService{client: cli}, NewEventService(db))
+	user := models.User{Username: "admin"}
+
+	_, err = svc.SetHealthcheck(ctx, "/web", containertypes.HealthcheckRequest{Type: "http", Port: appPort, Path: "/health", Retries: 2, AutoRestart: true}, user)
+	require.NoError(t, err)
+	_, err = svc.SetHealthcheck(ctx, "stopped", containertypes.HealthcheckRequest{Type: "tcp", Port: appPort}, user)
+	require.NoError(t, err)
+
+	run := func() map[string]containertypes.Healthcheck {
+		t.Helper()
+		// Make every check due again.
+		require.NoError(t, gdb.Model(&models.ContainerHealthcheck{}).Where("1 = 1").Update("last_checked_at", nil).Error)
+		assert.Equal(t, 2, svc.RunDueChecks(ctx))
+		checks, err := svc.ListHealthchecks(ctx)
+		require.NoError(t, err)
+		out := map[string]containertypes.Healthcheck{}
+		for _, c := range checks {
+			out[c.ContainerKey] = c
+		}
+		return out
+	}
+
+	checks := run()
+	assert.Equal(t, HealthStatusHealthy, checks["web"].Status)
+	assert.Equal(t, "HTTP 200", checks["web"].LastOutput)
+	assert.Equal(t, HealthStatusStarting, checks["stopped"].Status)
+	assert.Nil(t, checks["stopped"].LastCheckedAt)
+
+	// Checks that are not due are skipped.
+	assert.Equal(t, 1, svc.RunDueChecks(ctx))
+
+	appStatus = http.StatusServiceUnavailable
+	checks = run()
+	assert.Equal(t, HealthStatusHealthy, checks["web"].Status)
+	assert.Equal(t, 1, checks["web"].FailingStreak)
+	assert.Equal(t, "HTTP 503", checks["web"].LastOutput)
+
+	// The second failure marks the container unhealthy and restarts it.
+	checks = run()
+	assert.Equal(t, 1, restarts)
+	assert.Equal(t, HealthStatusStarting, checks["web"].Status)
+	var unhealthy models.Event
+	require.NoError(t, gdb.Where("type = ?", models.EventTypeContainerUnhealthy).First(&unhealthy).Error)
+	assert.Equal(t, true, unhealthy.Metadata["autoRestart"])
+
+	_, err = svc.SetHealthcheck(ctx, "web", containertypes.HealthcheckRequest{Type: "http", Port: appPort, Retries: 1}, user)
+	require.NoError(t, err)
+	checks = run()
+	assert.Equal(t, HealthStatusUnhealthy, checks["web"].Status)
+	assert.Equal(t, 1, restarts)
+
+	appStatus = http.StatusOK
+	checks = run()
+	assert.Equal(t, HealthStatusHealthy, checks["web"].Status)
+	var healthy int64
+	require.NoError(t, gdb.Model(&models.Event{}).Where("type = ?", models.EventTypeContainerHealthy).Count(&healthy).Error)
+	assert.Equal(t, int64(1), healthy)
+
+	require.NoError(t, svc.DeleteHealthcheck(ctx, "web"))
+	require.ErrorIs(t, svc.DeleteHealthcheck(ctx, "web"), ErrContainerHealthcheckNotFound)
+}
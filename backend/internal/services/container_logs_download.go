@@ -0,0 +1,164 @@
+package services
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/pkg/stdcopy"
+)
+
+const (
+	// DefaultLogDownloadMaxBytes caps a log download when no cap is given.
+	DefaultLogDownloadMaxBytes int64 = 50 << 20
+	// MaxLogDownloadMaxBytes is the largest cap a download may ask for.
+	MaxLogDownloadMaxBytes int64 = 500 << 20
+
+	logDownloadTruncatedNote = "\n[arcane] log download truncated: size limit of %d bytes reached\n"
+)
+
+var errLogDownloadCapReached = errors.New("log download size limit reached")
+
+// LogDownloadOptions selects the logs written by DownloadLogs.
+type LogDownloadOptions struct {
+	// Since and Until bound the time range. Both accept what the Docker API
+	// does: RFC 3339 timestamps, Unix timestamps or relative durations (1h).
+	Since string
+	Until string
+	// Stdout and Stderr select the streams to include.
+	Stdout bool
+	Stderr bool
+	// Timestamps prefixes every line with its timestamp.
+	Timestamps bool
+	// Gzip compresses the download.
+	Gzip bool
+	// MaxBytes caps the uncompressed size; 0 uses DefaultLogDownloadMaxBytes.
+	MaxBytes int64
+}
+
+// Validate checks the options and fills in the default size cap.
+func (o *LogDownloadOptions) Validate() error {
+	if !o.Stdout && !o.Stderr {
+		return fmt.Errorf("%w: select stdout, stderr or both", ErrInvalidLogDownload)
+	}
+	switch {
+	case o.MaxBytes < 0 || o.MaxBytes > MaxLogDownloadMaxBytes:
+		return fmt.Errorf("%w: size limit must be between 1 and %d bytes", ErrInvalidLogDownload, MaxLogDownloadMaxBytes)
+	case o.MaxBytes == 0:
+		o.MaxBytes = DefaultLogDownloadMaxBytes
+	}
+	if o.Since != "" && o.Until != "" {
+		since, sinceErr := time.Parse(time.RFC3339Nano, o.Since)
+		until, untilErr := time.Parse(time.RFC3339Nano, o.Until)
+		if sinceErr == nil && untilErr == nil && until.Before(since) {
+			return fmt.Errorf("%w: until must not be before since", ErrInvalidLogDownload)
+		}
+	}
+	return nil
+}
+
+// DownloadLogs returns the logs of a container as a file stream, along with
+// the container name. The stream is plain text, or gzip when requested, and
+// ends with a note when the size cap cut it short.
+func (s *ContainerService) DownloadLogs(ctx context.Context, containerID string, opts LogDownloadOptions) (io.ReadCloser, string, error) {
+	if err := opts.Validate(); err != nil {
+		return nil, "", err
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	inspect, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, "", fmt.Errorf("%w: %s", ErrDockerContainerNotFound, containerID)
+		}
+		return nil, "", fmt.Errorf("failed to inspect container: %w", err)
+	}
+	name := strings.TrimPrefix(inspect.Name, "/")
+	// Logs of a container with a TTY are a single raw stream.
+	tty := inspect.Config != nil && inspect.Config.Tty
+
+	logs, err := dockerClient.ContainerLogs(ctx, inspect.ID, container.LogsOptions{
+		ShowStdout: opts.Stdout,
+		ShowStderr: opts.Stderr,
+		Since:      opts.Since,
+		Until:      opts.Until,
+		Timestamps: opts.Timestamps,
+	})
+	if err != nil {
+		if strings.Contains(err.Error(), "invalid value for") {
+			return nil, "", fmt.Errorf("%w: %w", ErrInvalidLogDownload, err)
+		}
+		return nil, "", fmt.Errorf("failed to get container logs: %w", err)
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		defer func() { _ = logs.Close() }()
+		pw.CloseWithError(writeLogDownloadInternal(pw, logs, tty, opts))
+	}()
+
+	slog.DebugContext(ctx, "Downloading container logs", "container", name, "since", opts.Since, "until", opts.Until, "gzip", opts.Gzip)
+	return pr, name, nil
+}
+
+// writeLogDownloadInternal demultiplexes logs into w, compressing and
+// capping the output as set in opts.
+func writeLogDownloadInternal(w io.Writer, logs io.Reader, tty bool, opts LogDownloadOptions) error {
+	out := w
+	var gz *gzip.Writer
+	if opts.Gzip {
+		gz = gzip.NewWriter(w)
+		out = gz
+	}
+
+	capped := &logCapWriter{w: out, remaining: opts.MaxBytes}
+	var err error
+	if tty {
+		_, err = io.Copy(capped, logs)
+	} else {
+		// The daemon only sends the streams asked for, so both go to one file.
+		_, err = stdcopy.StdCopy(capped, capped, logs)
+	}
+	if errors.Is(err, errLogDownloadCapReached) {
+		_, err = fmt.Fprintf(out, logDownloadTruncatedNote, opts.MaxBytes)
+	}
+	if err != nil {
+		return err
+	}
+	if gz != nil {
+		return gz.Close()
+	}
+	return nil
+}
+
+// logCapWriter writes up to remaining bytes and then fails with
+// errLogDownloadCapReached.
+type logCapWriter struct {
+	w         io.Writer
+	remaining int64
+}
+
+func (c *logCapWriter) Write(p []byte) (int, error) {
+	if int64(len(p)) <= c.remaining {
+		n, err := c.w.Write(p)
+		c.remaining -= int64(n)
+		return n, err
+	}
+	n, err := c.w.Write(p[:c.remaining])
+	c.remaining -= int64(n)
+	if err != nil {
+		return n, err
+	}
+	return n, errLogDownloadCapReached
+}
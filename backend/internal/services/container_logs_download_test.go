@@ -0,0 +1,89 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestContainerService_DownloadLogs(t *testing.T) {
+	ctx := context.Background()
+	var query url.Values
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		switch path {
+		case "/containers/web/json":
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"Id":"abc123","Name":"/web","Config":{"Tty":false}}`)
+		case "/containers/abc123/logs":
+			query = r.URL.Query()
+			_, _ = stdcopy.NewStdWriter(w, stdcopy.Stdout).Write([]byte("out line 1\nout line 2\n"))
+			_, _ = stdcopy.NewStdWriter(w, stdcopy.Stderr).Write([]byte("err line\n"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	svc := NewContainerService(nil, nil, &DockerClientService{client: cli}, nil, nil, nil)
+
+	read := func(opts LogDownloadOptions) string {
+		stream, name, err := svc.DownloadLogs(ctx, "web", opts)
+		require.NoError(t, err)
+		defer stream.Close()
+		assert.Equal(t, "web", name)
+		var r io.Reader = stream
+		if opts.Gzip {
+			gz, err := gzip.NewReader(stream)
+			require.NoError(t, err)
+			r = gz
+		}
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		return string(b)
+	}
+
+	out := read(LogDownloadOptions{Stdout: true, Stderr: true, Since: "2024-01-01T00:00:00Z", Until: "2024-01-02T00:00:00Z"})
+	assert.Equal(t, "out line 1\nout line 2\nerr line\n", out)
+	assert.Equal(t, "1", query.Get("stdout"))
+	assert.Equal(t, "1", query.Get("stderr"))
+	assert.NotEmpty(t, query.Get("since"))
+	assert.NotEmpty(t, query.Get("until"))
+
+	read(LogDownloadOptions{Stdout: true})
+	assert.Equal(t, "1", query.Get("stdout"))
+	assert.Empty(t, query.Get("stderr"))
+
+	out = read(LogDownloadOptions{Stdout: true, Stderr: true, Gzip: true, MaxBytes: 12})
+	assert.True(t, strings.HasPrefix(out, "out line 1\no\n[arcane] log download truncated"), out)
+
+	for _, bad := range []LogDownloadOptions{
+		{},
+		{Stdout: true, MaxBytes: -1},
+		{Stdout: true, MaxBytes: MaxLogDownloadMaxBytes + 1},
+		{Stdout: true, Since: "2024-01-02T00:00:00Z", Until: "2024-01-01T00:00:00Z"},
+	} {
+		_, _, err := svc.DownloadLogs(ctx, "web", bad)
+		require.ErrorIs(t, err, ErrInvalidLogDownload, "%+v", bad)
+	}
+}
+
+func TestWriteLogDownload_TTY(t *testing.T) {
+	var buf bytes.Buffer
+	err := writeLogDownloadInternal(&buf, strings.NewReader("raw tty output\n"), true, LogDownloadOptions{Stdout: true, MaxBytes: DefaultLogDownloadMaxBytes})
+	require.NoError(t, err)
+	assert.Equal(t, "raw tty output\n", buf.String())
+}
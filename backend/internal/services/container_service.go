@@ -8,254 +8,1372 @@ import (
 	"fmt"
 	"io"
 	"log/slog"
+	"maps"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
 	"strings"
 	"sync"
 	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/mount"
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/client"
 	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
 	"github.com/getarcaneapp/arcane/backend/internal/database"
 	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/arcaneupdater"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/pagination"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/timeouts"
 	"github.com/getarcaneapp/arcane/backend/pkg/libarcane"
 	containertypes "github.com/getarcaneapp/arcane/types/container"
 	"github.com/getarcaneapp/arcane/types/containerregistry"
 	imagetypes "github.com/getarcaneapp/arcane/types/image"
+	ref "go.podman.io/image/v5/docker/reference"
+	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
 )
 
+// defaultStopTimeout is how many seconds a stop waits before killing a
+// container that configures no stop timeout.
+const defaultStopTimeout = 30
+
+var (
+	ErrContainerOverrideNotFound = errors.New("container override not found")
+	ErrInvalidContainerOverride  = errors.New("invalid container override")
+	ErrInvalidContainerName      = errors.New("invalid container name")
+	ErrContainerNameInUse        = errors.New("container name is already in use")
+	ErrDockerContainerNotFound   = errors.New("container not found")
+	ErrInvalidRecreate           = errors.New("invalid recreate request")
+	ErrInvalidResourceUpdate     = errors.New("invalid resource update")
+	ErrContainerNotRunning       = errors.New("container is not running")
+	ErrContainerRunning          = errors.New("container is running; stop it first or force the deletion")
+	ErrTrashEntryNotFound        = errors.New("container is not in the trash")
+	ErrInvalidPsArgs             = errors.New("invalid ps arguments")
+	ErrExecNotFound              = errors.New("exec session not found")
+	ErrInvalidTerminalSize       = errors.New("invalid terminal size")
+	ErrInvalidExecOptions        = errors.New("invalid exec options")
+	ErrInvalidCommit             = errors.New("invalid commit request")
+	ErrInvalidBulkAction         = errors.New("invalid bulk action")
+	ErrInvalidLogDownload        = errors.New("invalid log download request")
+)
+
+// defaultBulkActionConcurrency is how many containers a bulk action handles
+// at once when the request does not say.
+const defaultBulkActionConcurrency = 5
+
+// largeSnapshotBytes is the size from which committing or exporting a
+// container comes with a warning.
+const largeSnapshotBytes = 2 << 30
+
+// commitChangeInstructions are the Dockerfile instructions Docker accepts
+// when committing a container.
+var commitChangeInstructions = []string{"CMD", "ENTRYPOINT", "ENV", "EXPOSE", "LABEL", "ONBUILD", "USER", "VOLUME", "WORKDIR"}
+
+// psArgsPattern limits the ps options passed through to the Docker host.
+var psArgsPattern = regexp.MustCompile(`^[A-Za-z0-9 ,=_-]*$`)
+
+// containerNamePattern mirrors the Docker daemon's rule for container names.
+var containerNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]+$`)
+
 type ContainerService struct {
-	db              *database.DB
-	dockerService   *DockerClientService
-	eventService    *EventService
-	imageService    *ImageService
-	settingsService *SettingsService
+	db               *database.DB
+	dockerService    *DockerClientService
+	eventService     *EventService
+	imageService     *ImageService
+	settingsService  *SettingsService
+	namespaceService *NamespaceService
+	policyService    *EnvironmentPolicyService
+}
+
+func NewContainerService(db *database.DB, eventService *EventService, dockerService *DockerClientService, imageService *ImageService, settingsService *SettingsService, namespaceService *NamespaceService) *ContainerService {
+	return &ContainerService{
+		db:               db,
+		eventService:     eventService,
+		dockerService:    dockerService,
+		imageService:     imageService,
+		settingsService:  settingsService,
+		namespaceService: namespaceService,
+	}
+}
+
+func (s *ContainerService) StartContainer(ctx context.Context, containerID string, user models.User) error {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "start"})
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	metadata := models.JSON{
+		"action":      "start",
+		"containerId": containerID,
+	}
+
+	err = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerStart, containerID, "name", user.ID, user.Username, "0", metadata)
+
+	if err != nil {
+		fmt.Printf("Could not log container start action: %s\n", err)
+	}
+
+	err = dockerClient.ContainerStart(ctx, containerID, container.StartOptions{})
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "start"})
+	}
+	return err
+}
+
+// StopContainer stops a container, waiting timeout seconds before it is
+// killed. A timeout of 0 uses the container's stop-timeout label, then its
+// configured stop timeout, then defaultStopTimeout.
+func (s *ContainerService) StopContainer(ctx context.Context, containerID string, timeout int, user models.User) error {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "stop"})
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	metadata := models.JSON{
+		"action":      "stop",
+		"containerId": containerID,
+	}
+
+	err = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerStop, containerID, "name", user.ID, user.Username, "0", metadata)
+	if err != nil {
+		return fmt.Errorf("failed to log action: %w", err)
+	}
+
+	fallback := defaultStopTimeout
+	err = dockerClient.ContainerStop(ctx, containerID, s.stopOptionsInternal(ctx, dockerClient, containerID, timeout, &fallback))
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "stop"})
+	}
+	return err
+}
+
+// RestartContainer restarts a container. The timeout is resolved like in
+// StopContainer, except that the daemon default applies when nothing is set.
+func (s *ContainerService) RestartContainer(ctx context.Context, containerID string, timeout int, user models.User) error {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "restart"})
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	metadata := models.JSON{
+		"action":      "restart",
+		"containerId": containerID,
+	}
+
+	err = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerRestart, containerID, "name", user.ID, user.Username, "0", metadata)
+	if err != nil {
+		return fmt.Errorf("failed to log action: %w", err)
+	}
+
+	err = dockerClient.ContainerRestart(ctx, containerID, s.stopOptionsInternal(ctx, dockerClient, containerID, timeout, nil))
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "restart"})
+	}
+	return err
+}
+
+// stopOptionsInternal resolves how long to wait before a container is killed:
+// the requested timeout, then the stop-timeout label, then the container's own
+// stop timeout, then fallback. A nil timeout leaves the choice to the daemon.
+func (s *ContainerService) stopOptionsInternal(ctx context.Context, dockerClient *client.Client, containerID string, requested int, fallback *int) container.StopOptions {
+	if requested > 0 {
+		return container.StopOptions{Timeout: &requested}
+	}
+	inspect, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return container.StopOptions{Timeout: fallback}
+	}
+	return stopOptionsForConfigInternal(inspect.Config, fallback)
+}
+
+func stopOptionsForConfigInternal(cfg *container.Config, fallback *int) container.StopOptions {
+	if cfg == nil {
+		return container.StopOptions{Timeout: fallback}
+	}
+	if seconds, ok := arcaneupdater.GetStopTimeout(cfg.Labels); ok {
+		return container.StopOptions{Timeout: &seconds}
+	}
+	if cfg.StopTimeout != nil {
+		// The daemon applies the container's stop timeout when none is given.
+		return container.StopOptions{}
+	}
+	return container.StopOptions{Timeout: fallback}
+}
+
+func (s *ContainerService) GetContainerByID(ctx context.Context, id string) (*container.InspectResponse, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	container, err := dockerClient.ContainerInspect(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("container not found: %w", err)
+	}
+
+	return &container, nil
+}
+
+// DeleteContainer deletes a container. When the containerTrashRetention
+// setting is above zero the container is moved to the trash instead, from
+// where it can be restored until the retention ends.
+func (s *ContainerService) DeleteContainer(ctx context.Context, containerID string, force bool, removeVolumes bool, user models.User) error {
+	if s.trashRetentionInternal(ctx) > 0 {
+		return s.trashContainerInternal(ctx, containerID, force, removeVolumes, user)
+	}
+	return s.RemoveContainer(ctx, containerID, force, removeVolumes, user)
+}
+
+// RemoveContainer removes a container right away, bypassing the trash.
+func (s *ContainerService) RemoveContainer(ctx context.Context, containerID string, force bool, removeVolumes bool, user models.User) error {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "delete", "force": force, "removeVolumes": removeVolumes})
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	// Get container mounts before deletion if we need to remove volumes
+	var volumesToRemove []string
+	if removeVolumes {
+		containerJSON, inspectErr := dockerClient.ContainerInspect(ctx, containerID)
+		if inspectErr == nil {
+			for _, mount := range containerJSON.Mounts {
+				// Only collect named volumes (not bind mounts or tmpfs)
+				if mount.Type == "volume" && mount.Name != "" {
+					volumesToRemove = append(volumesToRemove, mount.Name)
+				}
+			}
+		}
+	}
+
+	err = dockerClient.ContainerRemove(ctx, containerID, container.RemoveOptions{
+		Force:         force,
+		RemoveVolumes: removeVolumes,
+		RemoveLinks:   false,
+	})
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "delete", "force": force, "removeVolumes": removeVolumes})
+		return fmt.Errorf("failed to delete container: %w", err)
+	}
+
+	// Remove named volumes if requested
+	if removeVolumes && len(volumesToRemove) > 0 {
+		for _, volumeName := range volumesToRemove {
+			if removeErr := dockerClient.VolumeRemove(ctx, volumeName, false); removeErr != nil {
+				// Log but don't fail if volume removal fails (might be in use by another container)
+				s.eventService.LogErrorEvent(ctx, models.EventTypeVolumeError, "volume", volumeName, "", user.ID, user.Username, "0", removeErr, models.JSON{"action": "delete", "container": containerID})
+			}
+		}
+	}
+
+	metadata := models.JSON{
+		"action":      "delete",
+		"containerId": containerID,
+	}
+
+	err = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerDelete, containerID, "name", user.ID, user.Username, "0", metadata)
+	if err != nil {
+		return fmt.Errorf("failed to log action: %w", err)
+	}
+
+	return nil
 }
 
-func NewContainerService(db *database.DB, eventService *EventService, dockerService *DockerClientService, imageService *ImageService, settingsService *SettingsService) *ContainerService {
-	return &ContainerService{
-		db:              db,
-		eventService:    eventService,
-		dockerService:   dockerService,
-		imageService:    imageService,
-		settingsService: settingsService,
+// BulkContainerAction runs start, stop, restart or delete on several
+// containers with limited concurrency. A failure on one container does not
+// stop the others; each outcome is reported in request order.
+func (s *ContainerService) BulkContainerAction(ctx context.Context, req containertypes.BulkActionRequest, user models.User) (*containertypes.BulkActionResult, error) {
+	var action func(context.Context, string) error
+	switch req.Action {
+	case "start":
+		action = func(ctx context.Context, id string) error { return s.StartContainer(ctx, id, user) }
+	case "stop":
+		action = func(ctx context.Context, id string) error { return s.StopContainer(ctx, id, req.Timeout, user) }
+	case "restart":
+		action = func(ctx context.Context, id string) error { return s.RestartContainer(ctx, id, req.Timeout, user) }
+	case "delete":
+		action = func(ctx context.Context, id string) error {
+			return s.DeleteContainer(ctx, id, req.Force, req.RemoveVolumes, user)
+		}
+	default:
+		return nil, fmt.Errorf("%w: action must be start, stop, restart or delete", ErrInvalidBulkAction)
+	}
+
+	ids := make([]string, 0, len(req.ContainerIDs))
+	seen := make(map[string]struct{}, len(req.ContainerIDs))
+	for _, id := range req.ContainerIDs {
+		id = strings.TrimSpace(id)
+		if id == "" {
+			continue
+		}
+		if _, dup := seen[id]; dup {
+			continue
+		}
+		seen[id] = struct{}{}
+		ids = append(ids, id)
+	}
+	if len(ids) == 0 {
+		return nil, fmt.Errorf("%w: no containers given", ErrInvalidBulkAction)
+	}
+
+	concurrency := req.Concurrency
+	if concurrency <= 0 {
+		concurrency = defaultBulkActionConcurrency
+	}
+
+	results := make([]containertypes.BulkActionItem, len(ids))
+	var g errgroup.Group
+	g.SetLimit(concurrency)
+	for i, id := range ids {
+		g.Go(func() error {
+			results[i] = containertypes.BulkActionItem{ContainerID: id, Success: true}
+			err := s.namespaceService.AuthorizeResource(ctx, NamespaceResourceContainer, id)
+			if err == nil {
+				err = action(ctx, id)
+			}
+			if err != nil {
+				results[i].Success = false
+				results[i].Error = err.Error()
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	out := &containertypes.BulkActionResult{Action: req.Action, Results: results}
+	for _, r := range results {
+		if r.Success {
+			out.Succeeded++
+		} else {
+			out.Failed++
+		}
+	}
+	return out, nil
+}
+
+// RenameContainer renames a container in place. Display overrides stored
+// under the old name follow the container to its new name.
+func (s *ContainerService) RenameContainer(ctx context.Context, containerID, newName string, user models.User) error {
+	newName = strings.TrimPrefix(strings.TrimSpace(newName), "/")
+	if !containerNamePattern.MatchString(newName) {
+		return fmt.Errorf("%w: %q must start with a letter or digit and contain only letters, digits, '_', '.' and '-'", ErrInvalidContainerName, newName)
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	inspect, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return fmt.Errorf("%w: %s", ErrDockerContainerNotFound, containerID)
+		}
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+	oldName := strings.TrimPrefix(inspect.Name, "/")
+	if oldName == newName {
+		return nil
+	}
+
+	if err := dockerClient.ContainerRename(ctx, inspect.ID, newName); err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", inspect.ID, oldName, user.ID, user.Username, "0", err, models.JSON{"action": "rename", "newName": newName})
+		if cerrdefs.IsConflict(err) {
+			return fmt.Errorf("%w: %s", ErrContainerNameInUse, newName)
+		}
+		return fmt.Errorf("failed to rename container: %w", err)
+	}
+
+	if s.db != nil && oldName != "" {
+		if err := s.db.WithContext(ctx).Model(&models.ContainerOverride{}).Where("container_key = ?", oldName).Update("container_key", newName).Error; err != nil {
+			slog.WarnContext(ctx, "Failed to move container override to new name", "old_name", oldName, "new_name", newName, "error", err)
+		}
+		if err := s.db.WithContext(ctx).Model(&models.ContainerHealthcheck{}).Where("container_key = ?", oldName).Update("container_key", newName).Error; err != nil {
+			slog.WarnContext(ctx, "Failed to move container healthcheck to new name", "old_name", oldName, "new_name", newName, "error", err)
+		}
+	}
+
+	metadata := models.JSON{
+		"action":      "rename",
+		"containerId": inspect.ID,
+		"oldName":     oldName,
+		"newName":     newName,
+	}
+	if err := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerRename, inspect.ID, newName, user.ID, user.Username, "0", metadata); err != nil {
+		slog.WarnContext(ctx, "Could not log container rename action", "container", newName, "error", err)
+	}
+
+	return nil
+}
+
+// AuditRestartPolicies reports containers whose restart policy will not bring
+// them back after a host reboot (`no`) or that use `always` where
+// `unless-stopped` is usually intended.
+func (s *ContainerService) AuditRestartPolicies(ctx context.Context, includeInternal bool) (*containertypes.RestartPolicyAudit, error) {
+	slog.DebugContext(ctx, "container service: audit restart policies", "includeInternal", includeInternal)
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+	containers = filterInternalContainers(containers, includeInternal)
+
+	audit := &containertypes.RestartPolicyAudit{
+		TotalContainers: len(containers),
+		Flagged:         make([]containertypes.RestartPolicyAuditEntry, 0),
+	}
+
+	for _, c := range containers {
+		inspect, inspectErr := dockerClient.ContainerInspect(ctx, c.ID)
+		if inspectErr != nil {
+			slog.WarnContext(ctx, "failed to inspect container for restart policy audit", "container", c.ID, "error", inspectErr.Error())
+			continue
+		}
+		if inspect.HostConfig == nil || inspect.HostConfig.AutoRemove {
+			// Auto-removed containers are one-shot by design
+			continue
+		}
+
+		policy := inspect.HostConfig.RestartPolicy.Name
+		var issue containertypes.RestartPolicyIssue
+		switch policy {
+		case container.RestartPolicyDisabled, "":
+			issue = containertypes.RestartPolicyIssueNone
+			policy = container.RestartPolicyDisabled
+		case container.RestartPolicyAlways:
+			issue = containertypes.RestartPolicyIssueAlways
+		default:
+			continue
+		}
+
+		audit.Flagged = append(audit.Flagged, containertypes.RestartPolicyAuditEntry{
+			ID:            inspect.ID,
+			Name:          strings.TrimPrefix(inspect.Name, "/"),
+			Image:         c.Image,
+			State:         c.State,
+			RestartPolicy: string(policy),
+			Project:       c.Labels["com.docker.compose.project"],
+			Issue:         issue,
+			Recommended:   string(container.RestartPolicyUnlessStopped),
+		})
+	}
+
+	return audit, nil
+}
+
+// UpdateRestartPolicies applies a restart policy to multiple containers by
+// recreating each one with the rest of its configuration unchanged, so a
+// running container restarts briefly and a failed recreate restores the
+// original. Containers that already have the policy are left alone.
+func (s *ContainerService) UpdateRestartPolicies(ctx context.Context, req containertypes.RestartPolicyUpdate, user models.User) (*containertypes.RestartPolicyUpdateResult, error) {
+	slog.DebugContext(ctx, "container service: update restart policies", "policy", req.Policy, "count", len(req.ContainerIDs), "user", user.ID)
+
+	policy := container.RestartPolicy{Name: container.RestartPolicyMode(req.Policy)}
+	if policy.Name == container.RestartPolicyOnFailure {
+		policy.MaximumRetryCount = req.MaximumRetryCount
+	}
+	if err := container.ValidateRestartPolicy(policy); err != nil {
+		return nil, fmt.Errorf("invalid restart policy: %w", err)
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	result := &containertypes.RestartPolicyUpdateResult{
+		Results: make([]containertypes.RestartPolicyUpdateItem, 0, len(req.ContainerIDs)),
+	}
+
+	for _, containerID := range req.ContainerIDs {
+		item := containertypes.RestartPolicyUpdateItem{ContainerID: containerID}
+
+		updateErr := s.namespaceService.AuthorizeResource(ctx, NamespaceResourceContainer, containerID)
+		if updateErr == nil {
+			updateErr = s.updateRestartPolicyInternal(ctx, dockerClient, containerID, policy, user)
+		}
+		if updateErr != nil {
+			item.Error = updateErr.Error()
+			result.Failed++
+			result.Results = append(result.Results, item)
+			continue
+		}
+
+		item.Success = true
+		result.Succeeded++
+		result.Results = append(result.Results, item)
+	}
+
+	return result, nil
+}
+
+func (s *ContainerService) updateRestartPolicyInternal(ctx context.Context, dockerClient *client.Client, containerID string, policy container.RestartPolicy, user models.User) error {
+	inspect, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return fmt.Errorf("%w: %s", ErrDockerContainerNotFound, containerID)
+		}
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if inspect.HostConfig != nil && inspect.HostConfig.RestartPolicy == policy {
+		return nil
+	}
+
+	policyName := string(policy.Name)
+	recreated, err := s.RecreateContainer(ctx, inspect.ID, containertypes.RecreateRequest{
+		RestartPolicy:     &policyName,
+		MaximumRetryCount: &policy.MaximumRetryCount,
+	}, user)
+	if err != nil {
+		return err
+	}
+
+	name := strings.TrimPrefix(recreated.Name, "/")
+	metadata := models.JSON{
+		"action":         "update_restart_policy",
+		"containerId":    inspect.ID,
+		"newContainerId": recreated.ID,
+		"policy":         policyName,
+	}
+	if policy.Name == container.RestartPolicyOnFailure {
+		metadata["maximumRetryCount"] = policy.MaximumRetryCount
+	}
+	if logErr := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerUpdate, recreated.ID, name, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log restart policy update", "container", name, "error", logErr.Error())
+	}
+	return nil
+}
+
+// UpdateContainerResources changes CPU shares, memory limits and the restart
+// policy of a container in place. The settings before and after the change
+// are returned and recorded in the event metadata.
+func (s *ContainerService) UpdateContainerResources(ctx context.Context, containerID string, req containertypes.ResourcesUpdate, user models.User) (*containertypes.ResourcesUpdateResult, error) {
+	if req.CPUShares == nil && req.MemoryBytes == nil && req.MemoryReservationBytes == nil && req.RestartPolicy == nil {
+		return nil, fmt.Errorf("%w: nothing to update", ErrInvalidResourceUpdate)
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	inspect, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrDockerContainerNotFound, containerID)
+		}
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if inspect.ContainerJSONBase == nil || inspect.HostConfig == nil {
+		return nil, fmt.Errorf("failed to inspect container: incomplete configuration for %s", containerID)
+	}
+	name := strings.TrimPrefix(inspect.Name, "/")
+
+	update, afterHost, err := buildResourceUpdateInternal(inspect.HostConfig, req)
+	if err != nil {
+		return nil, err
+	}
+
+	// Raising the memory reservation counts against the namespace quota.
+	if grown := ContainerMemoryReservation(afterHost) - ContainerMemoryReservation(inspect.HostConfig); grown > 0 && inspect.Config != nil {
+		namespace := ResourceNamespace(inspect.Config.Labels, s.namespaceService.ProjectNamespaces(ctx))
+		if err := s.namespaceService.CheckContainerQuota(ctx, namespace, "", ContainerQuotaRequest{MemoryBytes: grown}); err != nil {
+			return nil, err
+		}
+	}
+
+	result := &containertypes.ResourcesUpdateResult{
+		Before: resourceSettingsInternal(inspect.HostConfig),
+		After:  resourceSettingsInternal(afterHost),
+	}
+	metadata := models.JSON{
+		"action":      "update_resources",
+		"containerId": inspect.ID,
+		"before":      result.Before,
+		"after":       result.After,
+	}
+
+	resp, err := dockerClient.ContainerUpdate(ctx, inspect.ID, update)
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", inspect.ID, name, user.ID, user.Username, "0", err, metadata)
+		if cerrdefs.IsInvalidArgument(err) {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidResourceUpdate, err)
+		}
+		return nil, fmt.Errorf("failed to update container resources: %w", err)
+	}
+	result.Warnings = resp.Warnings
+
+	if err := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerUpdate, inspect.ID, name, user.ID, user.Username, "0", metadata); err != nil {
+		slog.WarnContext(ctx, "Could not log container resource update", "container", name, "error", err)
+	}
+
+	return result, nil
+}
+
+// buildResourceUpdateInternal turns req into a Docker update and returns the
+// host config as it will be after the update.
+func buildResourceUpdateInternal(current *container.HostConfig, req containertypes.ResourcesUpdate) (container.UpdateConfig, *container.HostConfig, error) {
+	var update container.UpdateConfig
+	after := *current
+
+	if req.CPUShares != nil {
+		update.CPUShares = *req.CPUShares
+		after.CPUShares = *req.CPUShares
+	}
+
+	if req.MemoryBytes != nil {
+		update.Memory = *req.MemoryBytes
+		after.Memory = *req.MemoryBytes
+		// Docker refuses a limit above the current swap limit unless swap is
+		// updated too. Keep the same amount of swap on top of the new limit,
+		// or leave swap unlimited when there was no limit before.
+		switch {
+		case current.MemorySwap > 0 && current.Memory > 0:
+			update.MemorySwap = *req.MemoryBytes + (current.MemorySwap - current.Memory)
+		case current.MemorySwap == 0 && current.Memory == 0:
+			update.MemorySwap = -1
+		}
+		if update.MemorySwap != 0 {
+			after.MemorySwap = update.MemorySwap
+		}
+	}
+
+	if req.MemoryReservationBytes != nil {
+		update.MemoryReservation = *req.MemoryReservationBytes
+		after.MemoryReservation = *req.MemoryReservationBytes
+	}
+	if after.Memory > 0 && after.MemoryReservation > after.Memory {
+		return update, nil, fmt.Errorf("%w: memory reservation must not exceed the memory limit", ErrInvalidResourceUpdate)
+	}
+
+	if req.RestartPolicy != nil {
+		policy := container.RestartPolicy{Name: container.RestartPolicyMode(*req.RestartPolicy)}
+		if policy.Name == container.RestartPolicyOnFailure {
+			policy.MaximumRetryCount = req.MaximumRetryCount
+		}
+		if err := container.ValidateRestartPolicy(policy); err != nil {
+			return update, nil, fmt.Errorf("%w: %w", ErrInvalidResourceUpdate, err)
+		}
+		update.RestartPolicy = policy
+		after.RestartPolicy = policy
+	}
+
+	return update, &after, nil
+}
+
+func resourceSettingsInternal(hostConfig *container.HostConfig) containertypes.ResourceSettings {
+	return containertypes.ResourceSettings{
+		CPUShares:              hostConfig.CPUShares,
+		MemoryBytes:            hostConfig.Memory,
+		MemoryReservationBytes: hostConfig.MemoryReservation,
+		RestartPolicy:          string(hostConfig.RestartPolicy.Name),
+		MaximumRetryCount:      hostConfig.RestartPolicy.MaximumRetryCount,
+	}
+}
+
+// SetPolicyService makes container creation subject to the local environment
+// policy.
+func (s *ContainerService) SetPolicyService(policyService *EnvironmentPolicyService) {
+	s.policyService = policyService
+}
+
+func (s *ContainerService) CreateContainer(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, containerName string, user models.User, credentials []containerregistry.Credential) (*container.InspectResponse, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", err, models.JSON{"action": "create", "image": config.Image})
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	if hostConfig == nil {
+		hostConfig = &container.HostConfig{}
+	}
+	if err := ValidateContainerDNS(hostConfig.DNS, hostConfig.DNSSearch, hostConfig.ExtraHosts); err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", err, models.JSON{"action": "create", "image": config.Image, "step": "dns"})
+		return nil, err
+	}
+	applyContainerDNSDefaultsInternal(hostConfig, s.containerDNSDefaultsInternal(ctx))
+
+	if err := s.policyService.CheckContainer(ctx, containerName, config.Image, hostConfig); err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", err, models.JSON{"action": "create", "image": config.Image, "step": "policy"})
+		return nil, err
+	}
+
+	namespace, err := s.namespaceService.ResolveNamespace(ctx, libarcane.NamespaceFromLabels(config.Labels))
+	if err == nil {
+		quota := ContainerQuotaRequest{Containers: 1, MemoryBytes: ContainerMemoryReservation(hostConfig)}
+		if quota.MemoryBytes == 0 {
+			quota.Unreserved = 1
+		}
+		err = s.namespaceService.CheckContainerQuota(ctx, namespace, "", quota)
+	}
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", err, models.JSON{"action": "create", "image": config.Image, "step": "namespace"})
+		return nil, err
+	}
+	if namespace != "" {
+		if config.Labels == nil {
+			config.Labels = map[string]string{}
+		}
+		config.Labels[libarcane.NamespaceLabel] = namespace
+	}
+
+	if step, err := s.pullImageIfMissingInternal(ctx, dockerClient, config.Image, credentials); err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", err, models.JSON{"action": "create", "image": config.Image, "step": step})
+		return nil, err
+	}
+
+	if err := s.imageService.CheckScanGate(ctx, config.Image, user); err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", err, models.JSON{"action": "create", "image": config.Image, "step": "scan_gate"})
+		return nil, err
+	}
+
+	resp, err := dockerClient.ContainerCreate(ctx, config, hostConfig, networkingConfig, nil, containerName)
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", err, models.JSON{"action": "create", "image": config.Image, "step": "create"})
+		return nil, fmt.Errorf("failed to create container: %w", err)
+	}
+
+	metadata := models.JSON{
+		"action":      "create",
+		"containerId": resp.ID,
+	}
+
+	if logErr := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerCreate, resp.ID, "name", user.ID, user.Username, "0", metadata); logErr != nil {
+		fmt.Printf("Could not log container stop action: %s\n", logErr)
+	}
+
+	if err := dockerClient.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		_ = dockerClient.ContainerRemove(ctx, resp.ID, container.RemoveOptions{Force: true})
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", resp.ID, containerName, user.ID, user.Username, "0", err, models.JSON{"action": "create", "image": config.Image, "step": "start"})
+		return nil, fmt.Errorf("failed to start container: %w", err)
+	}
+
+	containerJSON, err := dockerClient.ContainerInspect(ctx, resp.ID)
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", resp.ID, containerName, user.ID, user.Username, "0", err, models.JSON{"action": "create", "image": config.Image, "step": "inspect"})
+		return nil, fmt.Errorf("failed to inspect created container: %w", err)
+	}
+
+	return &containerJSON, nil
+}
+
+// RecreateContainer replaces a container with a copy that has req applied.
+// The new image is pulled before the old container is stopped. The old
+// container is renamed aside rather than removed until its replacement has
+// started, so any failure restores it under its original name and state.
+func (s *ContainerService) RecreateContainer(ctx context.Context, containerID string, req containertypes.RecreateRequest, user models.User) (*container.InspectResponse, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	old, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrDockerContainerNotFound, containerID)
+		}
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if old.Config == nil || old.HostConfig == nil {
+		return nil, fmt.Errorf("failed to inspect container: incomplete configuration for %s", containerID)
+	}
+	name := strings.TrimPrefix(old.Name, "/")
+
+	config, hostConfig := cloneContainerConfigInternal(old)
+	if err := applyRecreateRequestInternal(config, hostConfig, req); err != nil {
+		return nil, err
+	}
+	if ns := libarcane.NamespaceFromLabels(config.Labels); ns != libarcane.NamespaceFromLabels(old.Config.Labels) {
+		if _, err := s.namespaceService.ResolveNamespace(ctx, ns); err != nil {
+			return nil, err
+		}
+	}
+	networkingConfig := recreateNetworkingConfigInternal(old, hostConfig)
+
+	metadata := models.JSON{
+		"action":      "recreate",
+		"containerId": old.ID,
+		"image":       config.Image,
+		"oldImage":    old.Config.Image,
+	}
+	fail := func(step string, err error) (*container.InspectResponse, error) {
+		meta := models.JSON{"step": step}
+		for k, v := range metadata {
+			meta[k] = v
+		}
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", old.ID, name, user.ID, user.Username, "0", err, meta)
+		return nil, err
+	}
+
+	if err := s.policyService.CheckContainer(ctx, name, config.Image, hostConfig); err != nil {
+		return fail("policy", err)
+	}
+
+	if config.Image != old.Config.Image {
+		if step, err := s.pullImageIfMissingInternal(ctx, dockerClient, config.Image, nil); err != nil {
+			return fail(step, err)
+		}
+		if err := s.imageService.CheckScanGate(ctx, config.Image, user); err != nil {
+			return fail("scan_gate", err)
+		}
+	}
+
+	wasRunning := old.State != nil && old.State.Running
+	if wasRunning {
+		if err := dockerClient.ContainerStop(ctx, old.ID, container.StopOptions{}); err != nil {
+			return fail("stop", fmt.Errorf("failed to stop container: %w", err))
+		}
+	}
+
+	// Free the name for the replacement while keeping the old container
+	// around for rollback.
+	asideName := fmt.Sprintf("%s_arcane_old_%d", name, time.Now().Unix())
+	if err := dockerClient.ContainerRename(ctx, old.ID, asideName); err != nil {
+		s.restoreContainerInternal(ctx, dockerClient, old.ID, "", wasRunning)
+		return fail("rename", fmt.Errorf("failed to rename container aside: %w", err))
+	}
+
+	created, err := dockerClient.ContainerCreate(ctx, config, hostConfig, networkingConfig, nil, name)
+	if err != nil {
+		s.restoreContainerInternal(ctx, dockerClient, old.ID, name, wasRunning)
+		return fail("create", fmt.Errorf("failed to create replacement container (previous container restored): %w", err))
+	}
+	if err := dockerClient.ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+		_ = dockerClient.ContainerRemove(ctx, created.ID, container.RemoveOptions{Force: true})
+		s.restoreContainerInternal(ctx, dockerClient, old.ID, name, wasRunning)
+		return fail("start", fmt.Errorf("failed to start replacement container (previous container restored): %w", err))
+	}
+
+	// Volumes are kept: anonymous ones were carried over to the replacement.
+	if err := dockerClient.ContainerRemove(ctx, old.ID, container.RemoveOptions{Force: true}); err != nil {
+		slog.WarnContext(ctx, "Failed to remove replaced container", "container", asideName, "error", err)
+	}
+
+	inspect, err := dockerClient.ContainerInspect(ctx, created.ID)
+	if err != nil {
+		return fail("inspect", fmt.Errorf("failed to inspect replacement container: %w", err))
+	}
+
+	metadata["newContainerId"] = created.ID
+	if err := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerRecreate, created.ID, name, user.ID, user.Username, "0", metadata); err != nil {
+		slog.WarnContext(ctx, "Could not log container recreate action", "container", name, "error", err)
+	}
+
+	return &inspect, nil
+}
+
+// SetContainerLabels replaces a container's labels by recreating it with the
+// rest of its configuration unchanged. Docker cannot change labels in place.
+// It is a no-op when the labels already match.
+func (s *ContainerService) SetContainerLabels(ctx context.Context, containerID string, labels map[string]string, user models.User) (*container.InspectResponse, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	inspect, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrDockerContainerNotFound, containerID)
+		}
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if inspect.Config == nil {
+		return nil, fmt.Errorf("failed to inspect container: incomplete configuration for %s", containerID)
+	}
+
+	changes := labelChangesInternal(inspect.Config.Labels, labels)
+	if len(changes) == 0 {
+		return &inspect, nil
+	}
+	return s.RecreateContainer(ctx, inspect.ID, containertypes.RecreateRequest{Labels: changes}, user)
+}
+
+// labelChangesInternal returns the label updates that turn current into
+// desired, with nil marking a removal.
+func labelChangesInternal(current, desired map[string]string) map[string]*string {
+	changes := map[string]*string{}
+	for key := range current {
+		if _, ok := desired[key]; !ok {
+			changes[key] = nil
+		}
+	}
+	for key, value := range desired {
+		if old, ok := current[key]; !ok || old != value {
+			changes[key] = &value
+		}
+	}
+	return changes
+}
+
+// restoreContainerInternal puts a container renamed aside by
+// RecreateContainer back under its name and restarts it if it was running.
+// An empty name skips the rename.
+func (s *ContainerService) restoreContainerInternal(ctx context.Context, dockerClient *client.Client, containerID, name string, start bool) {
+	// Roll back even if the request was canceled midway.
+	ctx = context.WithoutCancel(ctx)
+	if name != "" {
+		if err := dockerClient.ContainerRename(ctx, containerID, name); err != nil {
+			slog.ErrorContext(ctx, "Failed to restore container name after failed recreate", "container", containerID, "name", name, "error", err)
+		}
+	}
+	if start {
+		if err := dockerClient.ContainerStart(ctx, containerID, container.StartOptions{}); err != nil {
+			slog.ErrorContext(ctx, "Failed to restart container after failed recreate", "container", containerID, "error", err)
+		}
+	}
+}
+
+// cloneContainerConfigInternal copies the configuration of an inspected
+// container so it can be changed without touching the inspect result.
+func cloneContainerConfigInternal(inspect container.InspectResponse) (*container.Config, *container.HostConfig) {
+	config := *inspect.Config
+	config.Env = slices.Clone(inspect.Config.Env)
+	config.Labels = maps.Clone(inspect.Config.Labels)
+	config.ExposedPorts = maps.Clone(inspect.Config.ExposedPorts)
+	// Docker defaults the hostname to the short container ID; let the
+	// replacement get its own.
+	if len(inspect.ID) >= 12 && config.Hostname == inspect.ID[:12] {
+		config.Hostname = ""
+	}
+
+	hostConfig := *inspect.HostConfig
+	hostConfig.PortBindings = maps.Clone(inspect.HostConfig.PortBindings)
+	hostConfig.Mounts = slices.Clone(inspect.HostConfig.Mounts)
+
+	// Anonymous volumes would be created afresh; mount the existing ones by
+	// name so their data survives.
+	for _, m := range inspect.Mounts {
+		if m.Type != mount.TypeVolume || m.Name == "" || containerTargetMountedInternal(&hostConfig, m.Destination) {
+			continue
+		}
+		hostConfig.Mounts = append(hostConfig.Mounts, mount.Mount{
+			Type:     mount.TypeVolume,
+			Source:   m.Name,
+			Target:   m.Destination,
+			ReadOnly: !m.RW,
+		})
+	}
+	return &config, &hostConfig
+}
+
+func containerTargetMountedInternal(hostConfig *container.HostConfig, target string) bool {
+	for _, m := range hostConfig.Mounts {
+		if m.Target == target {
+			return true
+		}
+	}
+	for _, bind := range hostConfig.Binds {
+		parts := strings.Split(bind, ":")
+		if len(parts) >= 2 && parts[1] == target {
+			return true
+		}
+	}
+	return false
+}
+
+// applyRecreateRequestInternal applies the changes in req to a cloned
+// configuration.
+func applyRecreateRequestInternal(config *container.Config, hostConfig *container.HostConfig, req containertypes.RecreateRequest) error {
+	switch {
+	case req.Image != nil && req.Tag != nil:
+		return fmt.Errorf("%w: image and tag cannot both be set", ErrInvalidRecreate)
+	case req.Image != nil:
+		config.Image = strings.TrimSpace(*req.Image)
+	case req.Tag != nil:
+		config.Image = imageWithTagInternal(config.Image, strings.TrimSpace(*req.Tag))
+	}
+	if config.Image == "" {
+		return fmt.Errorf("%w: image cannot be empty", ErrInvalidRecreate)
+	}
+
+	for key, value := range req.Env {
+		if key == "" || strings.Contains(key, "=") {
+			return fmt.Errorf("%w: invalid environment variable name %q", ErrInvalidRecreate, key)
+		}
+		config.Env = slices.DeleteFunc(config.Env, func(e string) bool {
+			return e == key || strings.HasPrefix(e, key+"=")
+		})
+		if value != nil {
+			config.Env = append(config.Env, key+"="+*value)
+		}
+	}
+
+	for key, value := range req.Labels {
+		if key == "" {
+			return fmt.Errorf("%w: label key cannot be empty", ErrInvalidRecreate)
+		}
+		if config.Labels == nil {
+			config.Labels = map[string]string{}
+		}
+		if value == nil {
+			delete(config.Labels, key)
+		} else {
+			config.Labels[key] = *value
+		}
+	}
+
+	if req.RestartPolicy != nil {
+		policy := container.RestartPolicy{Name: container.RestartPolicyMode(*req.RestartPolicy)}
+		if policy.Name == container.RestartPolicyOnFailure && req.MaximumRetryCount != nil {
+			policy.MaximumRetryCount = *req.MaximumRetryCount
+		}
+		if err := container.ValidateRestartPolicy(policy); err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidRecreate, err)
+		}
+		hostConfig.RestartPolicy = policy
+	}
+
+	if err := ValidateContainerDNS(req.DNS, req.DNSSearch, req.ExtraHosts); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidRecreate, err)
+	}
+	if req.DNS != nil {
+		hostConfig.DNS = req.DNS
+	}
+	if req.DNSSearch != nil {
+		hostConfig.DNSSearch = req.DNSSearch
+	}
+	if req.ExtraHosts != nil {
+		hostConfig.ExtraHosts = req.ExtraHosts
+	}
+
+	if req.PortBindings != nil {
+		hostConfig.PortBindings = nat.PortMap{}
+		if config.ExposedPorts == nil {
+			config.ExposedPorts = nat.PortSet{}
+		}
+		for spec, bindings := range req.PortBindings {
+			proto, port := "tcp", spec
+			if p, pr, ok := strings.Cut(spec, "/"); ok {
+				port = p
+				if pr != "" {
+					proto = pr
+				}
+			}
+			natPort, err := nat.NewPort(proto, port)
+			if err != nil {
+				return fmt.Errorf("%w: invalid port %q: %w", ErrInvalidRecreate, spec, err)
+			}
+			config.ExposedPorts[natPort] = struct{}{}
+			for _, b := range bindings {
+				hostConfig.PortBindings[natPort] = append(hostConfig.PortBindings[natPort], nat.PortBinding{HostIP: b.HostIP, HostPort: b.HostPort})
+			}
+		}
+	}
+	return nil
+}
+
+// imageWithTagInternal replaces the tag or digest of ref with tag.
+func imageWithTagInternal(ref, tag string) string {
+	if i := strings.Index(ref, "@"); i >= 0 {
+		ref = ref[:i]
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		ref = ref[:i]
+	}
+	return ref + ":" + tag
+}
+
+// recreateNetworkingConfigInternal reconnects the replacement to the old
+// container's networks with the same aliases and static addresses.
+func recreateNetworkingConfigInternal(inspect container.InspectResponse, hostConfig *container.HostConfig) *network.NetworkingConfig {
+	mode := hostConfig.NetworkMode
+	if inspect.NetworkSettings == nil || mode.IsHost() || mode.IsNone() || mode.IsContainer() {
+		return nil
+	}
+
+	shortID := ""
+	if len(inspect.ID) >= 12 {
+		shortID = inspect.ID[:12]
+	}
+	endpoints := make(map[string]*network.EndpointSettings, len(inspect.NetworkSettings.Networks))
+	for name, ep := range inspect.NetworkSettings.Networks {
+		if ep == nil {
+			continue
+		}
+		endpoints[name] = &network.EndpointSettings{
+			IPAMConfig: ep.IPAMConfig,
+			Links:      slices.Clone(ep.Links),
+			Aliases:    slices.DeleteFunc(slices.Clone(ep.Aliases), func(a string) bool { return a == shortID }),
+			DriverOpts: maps.Clone(ep.DriverOpts),
+		}
 	}
+	return &network.NetworkingConfig{EndpointsConfig: endpoints}
 }
 
-func (s *ContainerService) StartContainer(ctx context.Context, containerID string, user models.User) error {
-	dockerClient, err := s.dockerService.GetClient()
-	if err != nil {
-		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "start"})
-		return fmt.Errorf("failed to connect to Docker: %w", err)
+// pullImageIfMissingInternal pulls imageRef unless it is already present. On
+// failure it also returns the step that failed, for event metadata.
+func (s *ContainerService) pullImageIfMissingInternal(ctx context.Context, dockerClient *client.Client, imageRef string, credentials []containerregistry.Credential) (string, error) {
+	if _, err := dockerClient.ImageInspect(ctx, imageRef); err == nil {
+		return "", nil
 	}
 
-	metadata := models.JSON{
-		"action":      "start",
-		"containerId": containerID,
+	pullOptions, authErr := s.imageService.getPullOptionsWithAuth(ctx, imageRef, credentials)
+	if authErr != nil {
+		slog.WarnContext(ctx, "Failed to get registry authentication for container image; proceeding without auth",
+			"image", imageRef,
+			"error", authErr.Error())
+		pullOptions = image.PullOptions{}
 	}
 
-	err = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerStart, containerID, "name", user.ID, user.Username, "0", metadata)
+	settings := s.settingsService.GetSettingsConfig()
+	pullCtx, pullCancel := timeouts.WithTimeout(ctx, settings.DockerImagePullTimeout.AsInt(), timeouts.DefaultDockerImagePull)
+	defer pullCancel()
 
+	reader, err := dockerClient.ImagePull(pullCtx, imageRef, pullOptions)
 	if err != nil {
-		fmt.Printf("Could not log container start action: %s\n", err)
+		if errors.Is(pullCtx.Err(), context.DeadlineExceeded) {
+			return "pull_image_timeout", fmt.Errorf("image pull timed out for %s (increase DOCKER_IMAGE_PULL_TIMEOUT or setting)", imageRef)
+		}
+		return "pull_image", fmt.Errorf("failed to pull image %s: %w", imageRef, err)
 	}
+	defer reader.Close()
 
-	err = dockerClient.ContainerStart(ctx, containerID, container.StartOptions{})
-	if err != nil {
-		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "start"})
+	if _, err := io.Copy(io.Discard, reader); err != nil {
+		return "complete_pull", fmt.Errorf("failed to complete image pull: %w", err)
 	}
-	return err
+	return "", nil
 }
 
-func (s *ContainerService) StopContainer(ctx context.Context, containerID string, user models.User) error {
+// GetContainerProcesses returns the process table of a running container.
+// psArgs are passed to ps on the Docker host; empty uses Docker's default of
+// "-ef".
+func (s *ContainerService) GetContainerProcesses(ctx context.Context, containerID, psArgs string) (*containertypes.ProcessList, error) {
+	psArgs = strings.TrimSpace(psArgs)
+	if !psArgsPattern.MatchString(psArgs) || len(psArgs) > 128 {
+		return nil, fmt.Errorf("%w: only letters, digits, spaces and ,=_- are allowed", ErrInvalidPsArgs)
+	}
+
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
-		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "stop"})
-		return fmt.Errorf("failed to connect to Docker: %w", err)
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
 	}
 
-	metadata := models.JSON{
-		"action":      "stop",
-		"containerId": containerID,
+	var args []string
+	if psArgs != "" {
+		args = strings.Fields(psArgs)
 	}
 
-	err = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerStop, containerID, "name", user.ID, user.Username, "0", metadata)
+	top, err := dockerClient.ContainerTop(ctx, containerID, args)
 	if err != nil {
-		return fmt.Errorf("failed to log action: %w", err)
+		switch {
+		case cerrdefs.IsNotFound(err):
+			return nil, fmt.Errorf("%w: %s", ErrDockerContainerNotFound, containerID)
+		case cerrdefs.IsConflict(err):
+			return nil, fmt.Errorf("%w: %s", ErrContainerNotRunning, containerID)
+		case cerrdefs.IsInvalidArgument(err):
+			return nil, fmt.Errorf("%w: %w", ErrInvalidPsArgs, err)
+		}
+		return nil, fmt.Errorf("failed to list container processes: %w", err)
 	}
 
-	timeout := 30
-	err = dockerClient.ContainerStop(ctx, containerID, container.StopOptions{Timeout: &timeout})
-	if err != nil {
-		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "stop"})
+	processes := top.Processes
+	if processes == nil {
+		processes = [][]string{}
 	}
-	return err
+	return &containertypes.ProcessList{
+		ContainerID: containerID,
+		Titles:      top.Titles,
+		Processes:   processes,
+		Timestamp:   time.Now().UTC(),
+	}, nil
 }
 
-func (s *ContainerService) RestartContainer(ctx context.Context, containerID string, user models.User) error {
+// GetContainerSnapshotInfo estimates how large a commit or export of the
+// container would be and what it would leave out.
+func (s *ContainerService) GetContainerSnapshotInfo(ctx context.Context, containerID string) (*containertypes.SnapshotInfo, error) {
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
-		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "restart"})
-		return fmt.Errorf("failed to connect to Docker: %w", err)
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
 	}
 
-	metadata := models.JSON{
-		"action":      "restart",
-		"containerId": containerID,
+	inspect, _, err := dockerClient.ContainerInspectWithRaw(ctx, containerID, true)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrDockerContainerNotFound, containerID)
+		}
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
 	}
 
-	err = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerRestart, containerID, "name", user.ID, user.Username, "0", metadata)
-	if err != nil {
-		return fmt.Errorf("failed to log action: %w", err)
+	info := snapshotInfoInternal(inspect)
+	if info.SizeRootFs >= largeSnapshotBytes {
+		info.Warnings = append(info.Warnings, fmt.Sprintf("The container filesystem is %s; an export needs as much free space and may take a while", formatSnapshotSizeInternal(info.SizeRootFs)))
 	}
+	return info, nil
+}
 
-	err = dockerClient.ContainerRestart(ctx, containerID, container.StopOptions{})
+// CommitContainer snapshots a container into a new image tagged
+// repository:tag. Data in mounted volumes is not part of the image.
+func (s *ContainerService) CommitContainer(ctx context.Context, containerID string, req containertypes.CommitRequest, user models.User) (*containertypes.CommitResult, error) {
+	reference, changes, err := normalizeCommitRequestInternal(req)
 	if err != nil {
-		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "restart"})
+		return nil, err
 	}
-	return err
-}
 
-func (s *ContainerService) GetContainerByID(ctx context.Context, id string) (*container.InspectResponse, error) {
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
 	}
 
-	container, err := dockerClient.ContainerInspect(ctx, id)
+	inspect, err := dockerClient.ContainerInspect(ctx, containerID)
 	if err != nil {
-		return nil, fmt.Errorf("container not found: %w", err)
+		if cerrdefs.IsNotFound(err) {
+			return nil, fmt.Errorf("%w: %s", ErrDockerContainerNotFound, containerID)
+		}
+		return nil, fmt.Errorf("failed to inspect container: %w", err)
 	}
+	name := strings.TrimPrefix(inspect.Name, "/")
 
-	return &container, nil
-}
-
-func (s *ContainerService) DeleteContainer(ctx context.Context, containerID string, force bool, removeVolumes bool, user models.User) error {
-	dockerClient, err := s.dockerService.GetClient()
-	if err != nil {
-		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "delete", "force": force, "removeVolumes": removeVolumes})
-		return fmt.Errorf("failed to connect to Docker: %w", err)
+	author := strings.TrimSpace(req.Author)
+	if author == "" {
+		author = user.Username
 	}
-
-	// Get container mounts before deletion if we need to remove volumes
-	var volumesToRemove []string
-	if removeVolumes {
-		containerJSON, inspectErr := dockerClient.ContainerInspect(ctx, containerID)
-		if inspectErr == nil {
-			for _, mount := range containerJSON.Mounts {
-				// Only collect named volumes (not bind mounts or tmpfs)
-				if mount.Type == "volume" && mount.Name != "" {
-					volumesToRemove = append(volumesToRemove, mount.Name)
-				}
-			}
-		}
+	metadata := models.JSON{
+		"action":    "commit",
+		"reference": reference,
 	}
 
-	err = dockerClient.ContainerRemove(ctx, containerID, container.RemoveOptions{
-		Force:         force,
-		RemoveVolumes: removeVolumes,
-		RemoveLinks:   false,
+	resp, err := dockerClient.ContainerCommit(ctx, inspect.ID, container.CommitOptions{
+		Reference: reference,
+		Comment:   strings.TrimSpace(req.Comment),
+		Author:    author,
+		Changes:   changes,
+		Pause:     req.Pause == nil || *req.Pause,
 	})
 	if err != nil {
-		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", containerID, "", user.ID, user.Username, "0", err, models.JSON{"action": "delete", "force": force, "removeVolumes": removeVolumes})
-		return fmt.Errorf("failed to delete container: %w", err)
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", inspect.ID, name, user.ID, user.Username, "0", err, metadata)
+		return nil, fmt.Errorf("failed to commit container: %w", err)
 	}
 
-	// Remove named volumes if requested
-	if removeVolumes && len(volumesToRemove) > 0 {
-		for _, volumeName := range volumesToRemove {
-			if removeErr := dockerClient.VolumeRemove(ctx, volumeName, false); removeErr != nil {
-				// Log but don't fail if volume removal fails (might be in use by another container)
-				s.eventService.LogErrorEvent(ctx, models.EventTypeVolumeError, "volume", volumeName, "", user.ID, user.Username, "0", removeErr, models.JSON{"action": "delete", "container": containerID})
-			}
-		}
+	result := &containertypes.CommitResult{
+		ImageID:   resp.ID,
+		Reference: reference,
 	}
-
-	metadata := models.JSON{
-		"action":      "delete",
-		"containerId": containerID,
+	if img, err := dockerClient.ImageInspect(ctx, resp.ID); err == nil {
+		result.Size = img.Size
+	}
+	result.Warnings = snapshotInfoInternal(inspect).Warnings
+	if result.Size >= largeSnapshotBytes {
+		result.Warnings = append(result.Warnings, fmt.Sprintf("The new image is %s", formatSnapshotSizeInternal(result.Size)))
 	}
 
-	err = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerDelete, containerID, "name", user.ID, user.Username, "0", metadata)
-	if err != nil {
-		return fmt.Errorf("failed to log action: %w", err)
+	metadata["imageId"] = resp.ID
+	metadata["size"] = result.Size
+	if err := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerCommit, inspect.ID, name, user.ID, user.Username, "0", metadata); err != nil {
+		slog.WarnContext(ctx, "Could not log container commit action", "container", name, "error", err)
 	}
 
-	return nil
+	return result, nil
 }
 
-func (s *ContainerService) CreateContainer(ctx context.Context, config *container.Config, hostConfig *container.HostConfig, networkingConfig *network.NetworkingConfig, containerName string, user models.User, credentials []containerregistry.Credential) (*container.InspectResponse, error) {
+// ExportContainer opens the container's filesystem as a tar stream and
+// returns it with the container name. The caller must close the stream.
+func (s *ContainerService) ExportContainer(ctx context.Context, containerID string, user models.User) (io.ReadCloser, string, error) {
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
-		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", err, models.JSON{"action": "create", "image": config.Image})
-		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+		return nil, "", fmt.Errorf("failed to connect to Docker: %w", err)
 	}
 
-	_, err = dockerClient.ImageInspect(ctx, config.Image)
+	inspect, _, err := dockerClient.ContainerInspectWithRaw(ctx, containerID, true)
 	if err != nil {
-		// Image not found locally, need to pull it
-		pullOptions, authErr := s.imageService.getPullOptionsWithAuth(ctx, config.Image, credentials)
-		if authErr != nil {
-			slog.WarnContext(ctx, "Failed to get registry authentication for container image; proceeding without auth",
-				"image", config.Image,
-				"error", authErr.Error())
-			pullOptions = image.PullOptions{}
-		}
-
-		settings := s.settingsService.GetSettingsConfig()
-		pullCtx, pullCancel := timeouts.WithTimeout(ctx, settings.DockerImagePullTimeout.AsInt(), timeouts.DefaultDockerImagePull)
-		defer pullCancel()
-
-		reader, pullErr := dockerClient.ImagePull(pullCtx, config.Image, pullOptions)
-		if pullErr != nil {
-			if errors.Is(pullCtx.Err(), context.DeadlineExceeded) {
-				s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", pullErr, models.JSON{"action": "create", "image": config.Image, "step": "pull_image_timeout"})
-				return nil, fmt.Errorf("image pull timed out for %s (increase DOCKER_IMAGE_PULL_TIMEOUT or setting)", config.Image)
-			}
-			s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", pullErr, models.JSON{"action": "create", "image": config.Image, "step": "pull_image"})
-			return nil, fmt.Errorf("failed to pull image %s: %w", config.Image, pullErr)
-		}
-		defer reader.Close()
-
-		_, copyErr := io.Copy(io.Discard, reader)
-		if copyErr != nil {
-			s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", copyErr, models.JSON{"action": "create", "image": config.Image, "step": "complete_pull"})
-			return nil, fmt.Errorf("failed to complete image pull: %w", copyErr)
+		if cerrdefs.IsNotFound(err) {
+			return nil, "", fmt.Errorf("%w: %s", ErrDockerContainerNotFound, containerID)
 		}
+		return nil, "", fmt.Errorf("failed to inspect container: %w", err)
+	}
+	name := strings.TrimPrefix(inspect.Name, "/")
+	info := snapshotInfoInternal(inspect)
+	metadata := models.JSON{
+		"action":     "export",
+		"sizeRootFs": info.SizeRootFs,
 	}
 
-	resp, err := dockerClient.ContainerCreate(ctx, config, hostConfig, networkingConfig, nil, containerName)
+	stream, err := dockerClient.ContainerExport(ctx, inspect.ID)
 	if err != nil {
-		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", "", containerName, user.ID, user.Username, "0", err, models.JSON{"action": "create", "image": config.Image, "step": "create"})
-		return nil, fmt.Errorf("failed to create container: %w", err)
+		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", inspect.ID, name, user.ID, user.Username, "0", err, metadata)
+		return nil, "", fmt.Errorf("failed to export container: %w", err)
 	}
 
-	metadata := models.JSON{
-		"action":      "create",
-		"containerId": resp.ID,
+	if err := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerExport, inspect.ID, name, user.ID, user.Username, "0", metadata); err != nil {
+		slog.WarnContext(ctx, "Could not log container export action", "container", name, "error", err)
 	}
+	return stream, name, nil
+}
 
-	if logErr := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerCreate, resp.ID, "name", user.ID, user.Username, "0", metadata); logErr != nil {
-		fmt.Printf("Could not log container stop action: %s\n", logErr)
+// snapshotInfoInternal collects the sizes and volumes of an inspected
+// container. Sizes are only known when it was inspected with size.
+func snapshotInfoInternal(inspect container.InspectResponse) *containertypes.SnapshotInfo {
+	info := &containertypes.SnapshotInfo{}
+	if inspect.ContainerJSONBase != nil {
+		info.ContainerID = inspect.ID
+		if inspect.SizeRootFs != nil {
+			info.SizeRootFs = *inspect.SizeRootFs
+		}
+		if inspect.SizeRw != nil {
+			info.SizeRw = *inspect.SizeRw
+		}
+	}
+	for _, m := range inspect.Mounts {
+		info.Volumes = append(info.Volumes, m.Destination)
 	}
+	if len(info.Volumes) > 0 {
+		slices.Sort(info.Volumes)
+		info.Warnings = append(info.Warnings, fmt.Sprintf("Data in mounted volumes is not included: %s", strings.Join(info.Volumes, ", ")))
+	}
+	return info
+}
 
-	if err := dockerClient.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
-		_ = dockerClient.ContainerRemove(ctx, resp.ID, container.RemoveOptions{Force: true})
-		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", resp.ID, containerName, user.ID, user.Username, "0", err, models.JSON{"action": "create", "image": config.Image, "step": "start"})
-		return nil, fmt.Errorf("failed to start container: %w", err)
+func normalizeCommitRequestInternal(req containertypes.CommitRequest) (string, []string, error) {
+	repository := strings.TrimSpace(req.Repository)
+	tag := strings.TrimSpace(req.Tag)
+	if tag == "" {
+		tag = "latest"
 	}
 
-	containerJSON, err := dockerClient.ContainerInspect(ctx, resp.ID)
+	named, err := ref.ParseNormalizedNamed(repository)
 	if err != nil {
-		s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", resp.ID, containerName, user.ID, user.Username, "0", err, models.JSON{"action": "create", "image": config.Image, "step": "inspect"})
-		return nil, fmt.Errorf("failed to inspect created container: %w", err)
+		return "", nil, fmt.Errorf("%w: invalid repository %q: %w", ErrInvalidCommit, repository, err)
+	}
+	if !ref.IsNameOnly(named) {
+		return "", nil, fmt.Errorf("%w: repository must not include a tag or digest", ErrInvalidCommit)
+	}
+	tagged, err := ref.WithTag(named, tag)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: invalid tag %q", ErrInvalidCommit, tag)
 	}
 
-	return &containerJSON, nil
+	changes := make([]string, 0, len(req.Changes))
+	for _, change := range req.Changes {
+		change = strings.TrimSpace(change)
+		if change == "" {
+			continue
+		}
+		instruction, _, _ := strings.Cut(change, " ")
+		if !slices.Contains(commitChangeInstructions, strings.ToUpper(instruction)) {
+			return "", nil, fmt.Errorf("%w: unsupported instruction %q", ErrInvalidCommit, instruction)
+		}
+		changes = append(changes, change)
+	}
+
+	return ref.FamiliarString(tagged), changes, nil
+}
+
+func formatSnapshotSizeInternal(size int64) string {
+	return fmt.Sprintf("%.1f GB", float64(size)/(1<<30))
 }
 
 func (s *ContainerService) StreamStats(ctx context.Context, containerID string, statsChan chan<- interface{}) error {
@@ -443,14 +1561,19 @@ func (s *ContainerService) ListContainersPaginated(ctx context.Context, params p
 	}
 
 	dockerContainers = filterInternalContainers(dockerContainers, includeInternal)
+	dockerContainers = filterTrashedContainersInternal(dockerContainers)
+	dockerContainers = filterByNamespaceInternal(ctx, s.namespaceService, dockerContainers, func(c container.Summary) map[string]string { return c.Labels })
 	imageIDs := collectImageIDs(dockerContainers)
 	updateInfoMap := s.getUpdateInfoMap(ctx, imageIDs)
 	items := s.buildContainerSummaries(dockerContainers, updateInfoMap)
+	s.applyContainerOverrides(ctx, items)
+	s.applyContainerHealthchecks(ctx, items)
 
 	config := s.buildContainerPaginationConfig()
 	result := pagination.SearchOrderAndPaginate(items, params, config)
 	counts := s.calculateContainerStatusCounts(items)
 	paginationResp := pagination.BuildResponseFromFilterResult(result, params)
+	s.applyContainerUptime(ctx, result.Items)
 
 	return result.Items, paginationResp, counts, nil
 }
@@ -512,6 +1635,207 @@ func (s *ContainerService) buildContainerSummaries(containers []container.Summar
 	return items
 }
 
+// applyContainerOverrides sets the display name and icon of each summary
+// from the stored overrides, matching by container ID first and then by name.
+func (s *ContainerService) applyContainerOverrides(ctx context.Context, items []containertypes.Summary) {
+	if s.db == nil || len(items) == 0 {
+		return
+	}
+
+	var overrides []models.ContainerOverride
+	if err := s.db.WithContext(ctx).Find(&overrides).Error; err != nil {
+		// Overrides are cosmetic; keep listing containers without them
+		slog.WarnContext(ctx, "Failed to fetch container overrides", "error", err)
+		return
+	}
+	if len(overrides) == 0 {
+		return
+	}
+
+	byKey := make(map[string]models.ContainerOverride, len(overrides))
+	for _, o := range overrides {
+		byKey[o.ContainerKey] = o
+	}
+
+	for i := range items {
+		o, ok := findContainerOverride(byKey, items[i].ID, items[i].Names)
+		if !ok {
+			continue
+		}
+		items[i].DisplayName = stringPtrValue(o.DisplayName)
+		items[i].IconURL = stringPtrValue(o.IconURL)
+	}
+}
+
+// applyContainerHealthchecks sets the health of each summary that has an
+// Arcane-managed healthcheck.
+func (s *ContainerService) applyContainerHealthchecks(ctx context.Context, items []containertypes.Summary) {
+	if s.db == nil || len(items) == 0 {
+		return
+	}
+
+	var checks []models.ContainerHealthcheck
+	if err := s.db.WithContext(ctx).Select("container_key", "status").Find(&checks).Error; err != nil {
+		slog.WarnContext(ctx, "Failed to fetch container healthchecks", "error", err)
+		return
+	}
+	if len(checks) == 0 {
+		return
+	}
+
+	byKey := make(map[string]string, len(checks))
+	for _, c := range checks {
+		byKey[c.ContainerKey] = c.Status
+	}
+
+	for i := range items {
+		if status, ok := byKey[items[i].ID]; ok {
+			items[i].Health = status
+			continue
+		}
+		for _, name := range items[i].Names {
+			if status, ok := byKey[strings.TrimPrefix(name, "/")]; ok {
+				items[i].Health = status
+				break
+			}
+		}
+	}
+}
+
+// applyContainerUptime sets the uptime of each summary from the recorded
+// state transitions of its name.
+func (s *ContainerService) applyContainerUptime(ctx context.Context, items []containertypes.Summary) {
+	keys := make([]string, 0, len(items))
+	for _, item := range items {
+		if len(item.Names) > 0 {
+			keys = append(keys, item.Names[0])
+		}
+	}
+
+	stats, err := loadUptimeStatsInternal(ctx, s.db, UptimeResourceContainer, keys, time.Now().UTC())
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to load container uptime", "error", err)
+		return
+	}
+	for i := range items {
+		if len(items[i].Names) == 0 {
+			continue
+		}
+		if st, ok := stats[items[i].Names[0]]; ok {
+			items[i].Uptime = &st
+		}
+	}
+}
+
+func findContainerOverride(byKey map[string]models.ContainerOverride, id string, names []string) (models.ContainerOverride, bool) {
+	if o, ok := byKey[id]; ok {
+		return o, true
+	}
+	for _, name := range names {
+		if o, ok := byKey[strings.TrimPrefix(name, "/")]; ok {
+			return o, true
+		}
+	}
+	return models.ContainerOverride{}, false
+}
+
+// ListContainerOverrides returns all stored container display overrides.
+func (s *ContainerService) ListContainerOverrides(ctx context.Context) ([]containertypes.Override, error) {
+	var overrides []models.ContainerOverride
+	if err := s.db.WithContext(ctx).Order("container_key ASC").Find(&overrides).Error; err != nil {
+		return nil, fmt.Errorf("failed to list container overrides: %w", err)
+	}
+
+	out := make([]containertypes.Override, 0, len(overrides))
+	for _, o := range overrides {
+		out = append(out, toContainerOverrideDTO(o))
+	}
+	return out, nil
+}
+
+// SetContainerOverride creates or replaces the display overrides for the
+// container identified by key, which may be a container name or ID.
+func (s *ContainerService) SetContainerOverride(ctx context.Context, key string, req containertypes.OverrideRequest, user models.User) (*containertypes.Override, error) {
+	key = strings.TrimPrefix(strings.TrimSpace(key), "/")
+	displayName := strings.TrimSpace(req.DisplayName)
+	iconURL := strings.TrimSpace(req.IconURL)
+
+	if key == "" {
+		return nil, fmt.Errorf("%w: container name or ID is required", ErrInvalidContainerOverride)
+	}
+	if displayName == "" && iconURL == "" {
+		return nil, fmt.Errorf("%w: a display name or icon URL is required", ErrInvalidContainerOverride)
+	}
+	if iconURL != "" {
+		if err := validateIconURL(iconURL); err != nil {
+			return nil, err
+		}
+	}
+
+	var override models.ContainerOverride
+	err := s.db.WithContext(ctx).Where("container_key = ?", key).First(&override).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, fmt.Errorf("failed to load container override: %w", err)
+	}
+
+	override.ContainerKey = key
+	override.DisplayName = nil
+	override.IconURL = nil
+	override.UpdatedBy = user.Username
+	if displayName != "" {
+		override.DisplayName = &displayName
+	}
+	if iconURL != "" {
+		override.IconURL = &iconURL
+	}
+
+	if override.ID == "" {
+		err = s.db.WithContext(ctx).Create(&override).Error
+	} else {
+		now := time.Now()
+		override.UpdatedAt = &now
+		err = s.db.WithContext(ctx).Save(&override).Error
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to save container override: %w", err)
+	}
+
+	out := toContainerOverrideDTO(override)
+	return &out, nil
+}
+
+// DeleteContainerOverride removes the display overrides for key.
+func (s *ContainerService) DeleteContainerOverride(ctx context.Context, key string) error {
+	key = strings.TrimPrefix(strings.TrimSpace(key), "/")
+	result := s.db.WithContext(ctx).Where("container_key = ?", key).Delete(&models.ContainerOverride{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete container override: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrContainerOverrideNotFound
+	}
+	return nil
+}
+
+func validateIconURL(raw string) error {
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("%w: icon URL must be an absolute http or https URL", ErrInvalidContainerOverride)
+	}
+	return nil
+}
+
+func toContainerOverrideDTO(o models.ContainerOverride) containertypes.Override {
+	return containertypes.Override{
+		ContainerKey: o.ContainerKey,
+		DisplayName:  stringPtrValue(o.DisplayName),
+		IconURL:      stringPtrValue(o.IconURL),
+		UpdatedBy:    o.UpdatedBy,
+		CreatedAt:    o.CreatedAt,
+		UpdatedAt:    o.UpdatedAt,
+	}
+}
+
 func (s *ContainerService) buildContainerPaginationConfig() pagination.Config[containertypes.Summary] {
 	return pagination.Config[containertypes.Summary]{
 		SearchAccessors: []pagination.SearchAccessor[containertypes.Summary]{
@@ -521,6 +1845,7 @@ func (s *ContainerService) buildContainerPaginationConfig() pagination.Config[co
 				}
 				return "", nil
 			},
+			func(c containertypes.Summary) (string, error) { return c.DisplayName, nil },
 			func(c containertypes.Summary) (string, error) { return c.Image, nil },
 			func(c containertypes.Summary) (string, error) { return c.State, nil },
 			func(c containertypes.Summary) (string, error) { return c.Status, nil },
@@ -614,8 +1939,43 @@ func (s *ContainerService) calculateContainerStatusCounts(items []containertypes
 	return counts
 }
 
-// CreateExec creates an exec instance in the container
-func (s *ContainerService) CreateExec(ctx context.Context, containerID string, cmd []string) (string, error) {
+// ExecOptions overrides how an exec process runs. Empty fields keep the
+// container's defaults.
+type ExecOptions struct {
+	// User is the user, and optionally group, to run as (e.g. root, 1000:1000).
+	User string
+	// WorkingDir is the absolute directory the process starts in.
+	WorkingDir string
+	// Env holds extra KEY=VALUE environment variables.
+	Env []string
+}
+
+var execUserRe = regexp.MustCompile(`^[A-Za-z0-9_][A-Za-z0-9_.-]*(:[A-Za-z0-9_][A-Za-z0-9_.-]*)?$`)
+
+// Validate checks the options before they are passed to the daemon.
+func (o ExecOptions) Validate() error {
+	if o.User != "" && (len(o.User) > 256 || !execUserRe.MatchString(o.User)) {
+		return fmt.Errorf("%w: user must be a name or ID, optionally followed by :group", ErrInvalidExecOptions)
+	}
+	if o.WorkingDir != "" && !path.IsAbs(o.WorkingDir) {
+		return fmt.Errorf("%w: working directory must be an absolute path", ErrInvalidExecOptions)
+	}
+	for _, kv := range o.Env {
+		key, _, ok := strings.Cut(kv, "=")
+		if !ok || key == "" || strings.ContainsAny(key, " \t\n") {
+			return fmt.Errorf("%w: environment variables must be KEY=VALUE", ErrInvalidExecOptions)
+		}
+	}
+	return nil
+}
+
+// CreateExec creates an exec instance in the container. rows and cols set the
+// initial terminal size; 0 leaves it to the daemon.
+func (s *ContainerService) CreateExec(ctx context.Context, containerID string, cmd []string, rows, cols uint, opts ExecOptions) (string, error) {
+	if err := opts.Validate(); err != nil {
+		return "", err
+	}
+
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
 		return "", fmt.Errorf("failed to connect to Docker: %w", err)
@@ -627,6 +1987,12 @@ func (s *ContainerService) CreateExec(ctx context.Context, containerID string, c
 		AttachStderr: true,
 		Tty:          true,
 		Cmd:          cmd,
+		User:         opts.User,
+		WorkingDir:   opts.WorkingDir,
+		Env:          opts.Env,
+	}
+	if rows > 0 && cols > 0 {
+		execConfig.ConsoleSize = &[2]uint{rows, cols}
 	}
 
 	execResp, err := dockerClient.ContainerExecCreate(ctx, containerID, execConfig)
@@ -649,6 +2015,14 @@ type ExecSession struct {
 func (e *ExecSession) Stdin() io.WriteCloser { return e.hijackedResp.Conn }
 func (e *ExecSession) Stdout() io.Reader     { return e.hijackedResp.Reader }
 
+// Resize changes the terminal size of the exec session.
+func (e *ExecSession) Resize(ctx context.Context, rows, cols uint) error {
+	if err := validateTerminalSizeInternal(rows, cols); err != nil {
+		return err
+	}
+	return e.dockerClient.ContainerExecResize(ctx, e.execID, container.ResizeOptions{Height: rows, Width: cols})
+}
+
 // Close terminates the exec session and kills the process if still running.
 func (e *ExecSession) Close(ctx context.Context) error {
 	var closeErr error
@@ -688,3 +2062,45 @@ func (s *ContainerService) AttachExec(ctx context.Context, containerID, execID s
 		dockerClient: dockerClient,
 	}, nil
 }
+
+// ResizeExec changes the terminal size of an exec session of a container.
+func (s *ContainerService) ResizeExec(ctx context.Context, containerID, execID string, rows, cols uint) error {
+	if err := validateTerminalSizeInternal(rows, cols); err != nil {
+		return err
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	execInspect, err := dockerClient.ContainerExecInspect(ctx, execID)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return fmt.Errorf("%w: %s", ErrExecNotFound, execID)
+		}
+		return fmt.Errorf("failed to inspect exec: %w", err)
+	}
+	// The container may be given by name, so compare resolved IDs.
+	if execInspect.ContainerID != containerID {
+		inspect, err := dockerClient.ContainerInspect(ctx, containerID)
+		if err != nil || inspect.ID != execInspect.ContainerID {
+			return fmt.Errorf("%w: %s", ErrExecNotFound, execID)
+		}
+	}
+	if !execInspect.Running {
+		return fmt.Errorf("%w: exec %s has exited", ErrContainerNotRunning, execID)
+	}
+
+	if err := dockerClient.ContainerExecResize(ctx, execID, container.ResizeOptions{Height: rows, Width: cols}); err != nil {
+		return fmt.Errorf("failed to resize exec: %w", err)
+	}
+	return nil
+}
+
+func validateTerminalSizeInternal(rows, cols uint) error {
+	if rows == 0 || cols == 0 || rows > 1000 || cols > 1000 {
+		return fmt.Errorf("%w: rows and columns must be between 1 and 1000", ErrInvalidTerminalSize)
+	}
+	return nil
+}
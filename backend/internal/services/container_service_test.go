@@ -0,0 +1,700 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+)
+
+func TestContainerService_ContainerOverrides(t *testing.T) {
+	ctx := context.Background()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.ContainerOverride{}))
+	svc := &ContainerService{db: &database.DB{DB: gdb}}
+	user := models.User{Username: "admin"}
+
+	_, err = svc.SetContainerOverride(ctx, "web", containertypes.OverrideRequest{}, user)
+	require.ErrorIs(t, err, ErrInvalidContainerOverride)
+	_, err = svc.SetContainerOverride(ctx, "web", containertypes.OverrideRequest{IconURL: "javascript:alert(1)"}, user)
+	require.ErrorIs(t, err, ErrInvalidContainerOverride)
+
+	_, err = svc.SetContainerOverride(ctx, "/web", containertypes.OverrideRequest{DisplayName: "Website", IconURL: "https://example.com/web.png"}, user)
+	require.NoError(t, err)
+	_, err = svc.SetContainerOverride(ctx, "abc123", containertypes.OverrideRequest{DisplayName: "Worker"}, user)
+	require.NoError(t, err)
+
+	updated, err := svc.SetContainerOverride(ctx, "web", containertypes.OverrideRequest{DisplayName: "Site"}, user)
+	require.NoError(t, err)
+	assert.Equal(t, "Site", updated.DisplayName)
+	assert.Empty(t, updated.IconURL)
+
+	items := []containertypes.Summary{
+		{ID: "def456", Names: []string{"web"}},
+		{ID: "abc123", Names: []string{"worker"}},
+		{ID: "ghi789", Names: []string{"db"}},
+	}
+	svc.applyContainerOverrides(ctx, items)
+	assert.Equal(t, "Site", items[0].DisplayName)
+	assert.Equal(t, "Worker", items[1].DisplayName)
+	assert.Empty(t, items[2].DisplayName)
+
+	overrides, err := svc.ListContainerOverrides(ctx)
+	require.NoError(t, err)
+	assert.Len(t, overrides, 2)
+
+	require.NoError(t, svc.DeleteContainerOverride(ctx, "web"))
+	require.ErrorIs(t, svc.DeleteContainerOverride(ctx, "web"), ErrContainerOverrideNotFound)
+}
+
+func TestContainerService_RenameContainer(t *testing.T) {
+	ctx := context.Background()
+	var renamedTo string
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/containers/missing/json"):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message":"No such container: missing"}`)
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/json"):
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"Id":"abc123","Name":"/web"}`)
+		case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/containers/abc123/rename"):
+			if r.URL.Query().Get("name") == "taken" {
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusConflict)
+				_, _ = io.WriteString(w, `{"message":"Conflict. The container name \"/taken\" is already in use"}`)
+				return
+			}
+			renamedTo = r.URL.Query().Get("name")
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.ContainerOverride{}, &models.Event{}))
+	db := &database.DB{DB: gdb}
+	svc := NewContainerService(db, NewEventService(db), &DockerClientService{client: cli}, nil, nil, nil)
+	user := models.User{Username: "admin"}
+
+	_, err = svc.SetContainerOverride(ctx, "web", containertypes.OverrideRequest{DisplayName: "Website"}, user)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, svc.RenameContainer(ctx, "abc123", "-bad", user), ErrInvalidContainerName)
+	require.ErrorIs(t, svc.RenameContainer(ctx, "abc123", "a", user), ErrInvalidContainerName)
+	require.ErrorIs(t, svc.RenameContainer(ctx, "abc123", "has space", user), ErrInvalidContainerName)
+	require.ErrorIs(t, svc.RenameContainer(ctx, "missing", "site", user), ErrDockerContainerNotFound)
+	require.ErrorIs(t, svc.RenameContainer(ctx, "abc123", "taken", user), ErrContainerNameInUse)
+
+	require.NoError(t, svc.RenameContainer(ctx, "abc123", "/site_v2.1", user))
+	assert.Equal(t, "site_v2.1", renamedTo)
+
+	overrides, err := svc.ListContainerOverrides(ctx)
+	require.NoError(t, err)
+	require.Len(t, overrides, 1)
+	assert.Equal(t, "site_v2.1", overrides[0].ContainerKey)
+
+	var event models.Event
+	require.NoError(t, gdb.Where("type = ?", models.EventTypeContainerRename).First(&event).Error)
+	assert.Equal(t, "web", event.Metadata["oldName"])
+}
+
+func TestApplyRecreateRequest(t *testing.T) {
+	inspect := container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{
+			ID:         "0123456789abcdef",
+			HostConfig: &container.HostConfig{Binds: []string{"/srv/conf:/etc/app:ro"}},
+		},
+		Config: &container.Config{
+			Image:    "registry.example.com:5000/team/app@sha256:abc",
+			Hostname: "0123456789ab",
+			Env:      []string{"KEEP=1", "DROP=2", "CHANGE=old"},
+			Labels:   map[string]string{"keep": "1", "drop": "2"},
+		},
+		Mounts: []container.MountPoint{
+			{Type: mount.TypeVolume, Name: "3f1e", Destination: "/data", RW: true},
+			{Type: mount.TypeBind, Source: "/srv/conf", Destination: "/etc/app"},
+		},
+	}
+	config, hostConfig := cloneContainerConfigInternal(inspect)
+	assert.Empty(t, config.Hostname)
+	require.Len(t, hostConfig.Mounts, 1)
+	assert.Equal(t, mount.Mount{Type: mount.TypeVolume, Source: "3f1e", Target: "/data"}, hostConfig.Mounts[0])
+
+	tag, changed, policy, label := "2.0", "new", "unless-stopped", "v"
+	err := applyRecreateRequestInternal(config, hostConfig, containertypes.RecreateRequest{
+		Tag:           &tag,
+		Env:           map[string]*string{"DROP": nil, "CHANGE": &changed, "ADD": &changed},
+		RestartPolicy: &policy,
+		PortBindings:  map[string][]containertypes.PortBindingCreate{"8080": {{HostPort: "80"}}},
+		Labels:        map[string]*string{"drop": nil, "add": &label},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "registry.example.com:5000/team/app:2.0", config.Image)
+	assert.ElementsMatch(t, []string{"KEEP=1", "CHANGE=new", "ADD=new"}, config.Env)
+	assert.Equal(t, map[string]string{"keep": "1", "add": "v"}, config.Labels)
+	assert.Equal(t, container.RestartPolicyMode("unless-stopped"), hostConfig.RestartPolicy.Name)
+	assert.Equal(t, "80", hostConfig.PortBindings["8080/tcp"][0].HostPort)
+	// The inspect result is left untouched.
+	assert.Len(t, inspect.Config.Env, 3)
+	assert.Contains(t, inspect.Config.Labels, "drop")
+
+	image := "nginx"
+	err = applyRecreateRequestInternal(config, hostConfig, containertypes.RecreateRequest{Image: &image, Tag: &tag})
+	require.ErrorIs(t, err, ErrInvalidRecreate)
+	err = applyRecreateRequestInternal(config, hostConfig, containertypes.RecreateRequest{Env: map[string]*string{"A=B": nil}})
+	require.ErrorIs(t, err, ErrInvalidRecreate)
+
+	err = applyRecreateRequestInternal(config, hostConfig, containertypes.RecreateRequest{DNS: []string{"192.168.1.53"}, ExtraHosts: []string{"nas.lan:10.0.0.1"}})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"192.168.1.53"}, hostConfig.DNS)
+	assert.Equal(t, []string{"nas.lan:10.0.0.1"}, hostConfig.ExtraHosts)
+	err = applyRecreateRequestInternal(config, hostConfig, containertypes.RecreateRequest{DNS: []string{}})
+	require.NoError(t, err)
+	assert.Empty(t, hostConfig.DNS)
+	assert.Equal(t, []string{"nas.lan:10.0.0.1"}, hostConfig.ExtraHosts)
+	err = applyRecreateRequestInternal(config, hostConfig, containertypes.RecreateRequest{ExtraHosts: []string{"nas.lan"}})
+	require.ErrorIs(t, err, ErrInvalidRecreate)
+	require.ErrorIs(t, err, ErrInvalidDNSConfig)
+}
+
+func TestContainerService_RecreateContainer(t *testing.T) {
+	ctx := context.Background()
+	var calls []string
+	var createdBody map[string]any
+	failStart := false
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && path == "/containers/old/json":
+			_, _ = io.WriteString(w, `{"Id":"old0123456789","Name":"/web","State":{"Running":true},
+				"Config":{"Image":"nginx:1.0","Env":["A=1"]},"HostConfig":{"NetworkMode":"appnet"},
+				"NetworkSettings":{"Networks":{"appnet":{"Aliases":["old012345678","web"]}}}}`)
+		case r.Method == http.MethodGet && path == "/containers/new/json":
+			_, _ = io.WriteString(w, `{"Id":"new","Name":"/web","State":{"Status":"running"},"Config":{"Image":"nginx:1.1"}}`)
+		case r.Method == http.MethodGet && path == "/images/nginx:1.1/json":
+			_, _ = io.WriteString(w, `{"Id":"sha256:1"}`)
+		case r.Method == http.MethodPost && path == "/containers/create":
+			calls = append(calls, "create "+r.URL.Query().Get("name"))
+			_ = json.NewDecoder(r.Body).Decode(&createdBody)
+			_, _ = io.WriteString(w, `{"Id":"new"}`)
+		case r.Method == http.MethodPost && path == "/containers/new/start" && failStart:
+			calls = append(calls, "start new")
+			w.WriteHeader(http.StatusInternalServerError)
+			_, _ = io.WriteString(w, `{"message":"port is already allocated"}`)
+		case r.Method == http.MethodPost && strings.HasSuffix(path, "/rename"):
+			calls = append(calls, "rename "+strings.Split(path, "/")[2]+" "+r.URL.Query().Get("name"))
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodPost:
+			calls = append(calls, path[strings.LastIndex(path, "/")+1:]+" "+strings.Split(path, "/")[2])
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodDelete:
+			calls = append(calls, "remove "+strings.Split(path, "/")[2])
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Event{}))
+	svc := NewContainerService(&database.DB{DB: gdb}, NewEventService(&database.DB{DB: gdb}), &DockerClientService{client: cli}, nil, nil, nil)
+	user := models.User{Username: "admin"}
+	tag := "1.1"
+
+	inspect, err := svc.RecreateContainer(ctx, "old", containertypes.RecreateRequest{Tag: &tag}, user)
+	require.NoError(t, err)
+	assert.Equal(t, "new", inspect.ID)
+	require.Len(t, calls, 5)
+	assert.Equal(t, "stop old0123456789", calls[0])
+	assert.True(t, strings.HasPrefix(calls[1], "rename old0123456789 web_arcane_old_"))
+	assert.Equal(t, []string{"create web", "start new", "remove old0123456789"}, calls[2:])
+	assert.Equal(t, "nginx:1.1", createdBody["Image"])
+	endpoint := createdBody["NetworkingConfig"].(map[string]any)["EndpointsConfig"].(map[string]any)["appnet"].(map[string]any)
+	assert.Equal(t, []any{"web"}, endpoint["Aliases"])
+
+	// A replacement that fails to start is removed and the old container is
+	// restored under its name.
+	calls, failStart = nil, true
+	_, err = svc.RecreateContainer(ctx, "old", containertypes.RecreateRequest{}, user)
+	require.Error(t, err)
+	assert.Equal(t, []string{"create web", "start new", "remove new", "rename old0123456789 web", "start old0123456789"}, calls[2:])
+}
+
+func TestLabelChanges(t *testing.T) {
+	changes := labelChangesInternal(
+		map[string]string{"keep": "1", "change": "old", "drop": "x"},
+		map[string]string{"keep": "1", "change": "new", "add": "y"},
+	)
+	require.Len(t, changes, 3)
+	assert.Nil(t, changes["drop"])
+	assert.Equal(t, "new", *changes["change"])
+	assert.Equal(t, "y", *changes["add"])
+	assert.Empty(t, labelChangesInternal(map[string]string{"a": "1"}, map[string]string{"a": "1"}))
+}
+
+func TestContainerService_UpdateContainerResources(t *testing.T) {
+	ctx := context.Background()
+	var updateBody map[string]any
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && path == "/containers/web/json":
+			_, _ = io.WriteString(w, `{"Id":"abc","Name":"/web","Config":{"Labels":{}},
+				"HostConfig":{"CpuShares":512,"Memory":268435456,"MemorySwap":536870912,"RestartPolicy":{"Name":"no"}}}`)
+		case r.Method == http.MethodPost && path == "/containers/abc/update":
+			_ = json.NewDecoder(r.Body).Decode(&updateBody)
+			_, _ = io.WriteString(w, `{"Warnings":["swap limit not supported"]}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Event{}))
+	svc := NewContainerService(&database.DB{DB: gdb}, NewEventService(&database.DB{DB: gdb}), &DockerClientService{client: cli}, nil, nil, nil)
+	user := models.User{Username: "admin"}
+
+	_, err = svc.UpdateContainerResources(ctx, "web", containertypes.ResourcesUpdate{}, user)
+	require.ErrorIs(t, err, ErrInvalidResourceUpdate)
+
+	reservation := int64(1 << 30)
+	_, err = svc.UpdateContainerResources(ctx, "web", containertypes.ResourcesUpdate{MemoryReservationBytes: &reservation}, user)
+	require.ErrorIs(t, err, ErrInvalidResourceUpdate)
+
+	_, err = svc.UpdateContainerResources(ctx, "missing", containertypes.ResourcesUpdate{MemoryReservationBytes: &reservation}, user)
+	require.ErrorIs(t, err, ErrDockerContainerNotFound)
+
+	memory := int64(512 << 20)
+	policy := "on-failure"
+	result, err := svc.UpdateContainerResources(ctx, "web", containertypes.ResourcesUpdate{MemoryBytes: &memory, RestartPolicy: &policy, MaximumRetryCount: 3}, user)
+	require.NoError(t, err)
+	assert.Equal(t, int64(256<<20), result.Before.MemoryBytes)
+	assert.Equal(t, memory, result.After.MemoryBytes)
+	assert.Equal(t, int64(512), result.After.CPUShares)
+	assert.Equal(t, "on-failure", result.After.RestartPolicy)
+	assert.Equal(t, 3, result.After.MaximumRetryCount)
+	assert.Equal(t, []string{"swap limit not supported"}, result.Warnings)
+
+	// The swap headroom above the old limit is kept.
+	assert.InDelta(t, float64(768<<20), updateBody["MemorySwap"], 0)
+	assert.Equal(t, map[string]any{"Name": "on-failure", "MaximumRetryCount": float64(3)}, updateBody["RestartPolicy"])
+
+	var event models.Event
+	require.NoError(t, gdb.Where("type = ?", models.EventTypeContainerUpdate).First(&event).Error)
+	assert.Equal(t, "update_resources", event.Metadata["action"])
+	assert.Equal(t, float64(256<<20), event.Metadata["before"].(map[string]any)["memoryBytes"])
+	assert.Equal(t, float64(512<<20), event.Metadata["after"].(map[string]any)["memoryBytes"])
+}
+
+func TestContainerService_GetContainerProcesses(t *testing.T) {
+	ctx := context.Background()
+	var psArgs string
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		switch path {
+		case "/containers/web/top":
+			psArgs = r.URL.Query().Get("ps_args")
+			_, _ = io.WriteString(w, `{"Titles":["PID","CMD"],"Processes":[["1","nginx"]]}`)
+		case "/containers/stopped/top":
+			w.WriteHeader(http.StatusConflict)
+			_, _ = io.WriteString(w, `{"message":"container stopped is not running"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message":"No such container"}`)
+		}
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	svc := NewContainerService(nil, nil, &DockerClientService{client: cli}, nil, nil, nil)
+
+	list, err := svc.GetContainerProcesses(ctx, "web", " aux ")
+	require.NoError(t, err)
+	assert.Equal(t, "aux", psArgs)
+	assert.Equal(t, []string{"PID", "CMD"}, list.Titles)
+	assert.Equal(t, [][]string{{"1", "nginx"}}, list.Processes)
+
+	_, err = svc.GetContainerProcesses(ctx, "web", "-ef; rm -rf /")
+	require.ErrorIs(t, err, ErrInvalidPsArgs)
+	_, err = svc.GetContainerProcesses(ctx, "stopped", "")
+	require.ErrorIs(t, err, ErrContainerNotRunning)
+	_, err = svc.GetContainerProcesses(ctx, "missing", "")
+	require.ErrorIs(t, err, ErrDockerContainerNotFound)
+}
+
+func TestContainerService_ResizeExec(t *testing.T) {
+	ctx := context.Background()
+	var resized string
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		switch path {
+		case "/exec/shell/json":
+			_, _ = io.WriteString(w, `{"ID":"shell","ContainerID":"abc123","Running":true}`)
+		case "/exec/done/json":
+			_, _ = io.WriteString(w, `{"ID":"done","ContainerID":"abc123","Running":false}`)
+		case "/containers/web/json":
+			_, _ = io.WriteString(w, `{"Id":"abc123","Name":"/web"}`)
+		case "/containers/other/json":
+			_, _ = io.WriteString(w, `{"Id":"def456","Name":"/other"}`)
+		case "/exec/shell/resize":
+			resized = r.URL.Query().Get("h") + "x" + r.URL.Query().Get("w")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message":"No such exec instance"}`)
+		}
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	svc := NewContainerService(nil, nil, &DockerClientService{client: cli}, nil, nil, nil)
+
+	require.NoError(t, svc.ResizeExec(ctx, "web", "shell", 40, 120))
+	assert.Equal(t, "40x120", resized)
+
+	require.ErrorIs(t, svc.ResizeExec(ctx, "web", "shell", 0, 120), ErrInvalidTerminalSize)
+	require.ErrorIs(t, svc.ResizeExec(ctx, "other", "shell", 40, 120), ErrExecNotFound)
+	require.ErrorIs(t, svc.ResizeExec(ctx, "web", "missing", 40, 120), ErrExecNotFound)
+	require.ErrorIs(t, svc.ResizeExec(ctx, "web", "done", 40, 120), ErrContainerNotRunning)
+}
+
+func TestContainerService_CreateExecOptions(t *testing.T) {
+	ctx := context.Background()
+	var created container.ExecOptions
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		if path != "/containers/web/exec" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewDecoder(r.Body).Decode(&created)
+		w.WriteHeader(http.StatusCreated)
+		_, _ = io.WriteString(w, `{"Id":"shell"}`)
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	svc := NewContainerService(nil, nil, &DockerClientService{client: cli}, nil, nil, nil)
+
+	opts := ExecOptions{User: "root", WorkingDir: "/var/www", Env: []string{"TERM=xterm-256color", "EMPTY="}}
+	execID, err := svc.CreateExec(ctx, "web", []string{"/bin/bash"}, 24, 80, opts)
+	require.NoError(t, err)
+	assert.Equal(t, "shell", execID)
+	assert.Equal(t, "root", created.User)
+	assert.Equal(t, "/var/www", created.WorkingDir)
+	assert.Equal(t, opts.Env, created.Env)
+	assert.Equal(t, []string{"/bin/bash"}, created.Cmd)
+
+	for _, bad := range []ExecOptions{
+		{User: "root; id"},
+		{User: "1000:"},
+		{WorkingDir: "relative/dir"},
+		{Env: []string{"NOVALUE"}},
+		{Env: []string{"=value"}},
+	} {
+		_, err := svc.CreateExec(ctx, "web", []string{"/bin/sh"}, 0, 0, bad)
+		require.ErrorIs(t, err, ErrInvalidExecOptions, "%+v", bad)
+	}
+	require.NoError(t, ExecOptions{User: "1000:1000"}.Validate())
+}
+
+func TestNormalizeCommitRequest(t *testing.T) {
+	reference, changes, err := normalizeCommitRequestInternal(containertypes.CommitRequest{
+		Repository: " web ",
+		Changes:    []string{"env DEBUG=1", " ", "EXPOSE 8080"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "web:latest", reference)
+	assert.Equal(t, []string{"env DEBUG=1", "EXPOSE 8080"}, changes)
+
+	reference, _, err = normalizeCommitRequestInternal(containertypes.CommitRequest{Repository: "registry.local:5000/team/web", Tag: "snap-1"})
+	require.NoError(t, err)
+	assert.Equal(t, "registry.local:5000/team/web:snap-1", reference)
+
+	invalid := []containertypes.CommitRequest{
+		{Repository: "Web"},
+		{Repository: "web:1"},
+		{Repository: "web", Tag: "bad tag"},
+		{Repository: "web", Changes: []string{"RUN rm -rf /"}},
+	}
+	for _, r := range invalid {
+		_, _, err := normalizeCommitRequestInternal(r)
+		require.ErrorIs(t, err, ErrInvalidCommit, "%+v", r)
+	}
+}
+
+func TestContainerService_CommitAndExportContainer(t *testing.T) {
+	ctx := context.Background()
+	var commitQuery map[string][]string
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && path == "/containers/web/json":
+			_, _ = io.WriteString(w, `{"Id":"abc","Name":"/web","SizeRw":1024,"SizeRootFs":3221225472,
+				"Mounts":[{"Type":"volume","Destination":"/var/lib/data"}]}`)
+		case r.Method == http.MethodPost && path == "/commit":
+			commitQuery = r.URL.Query()
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{"Id":"sha256:new"}`)
+		case r.Method == http.MethodGet && path == "/images/sha256:new/json":
+			_, _ = io.WriteString(w, `{"Id":"sha256:new","Size":3221225472}`)
+		case r.Method == http.MethodGet && path == "/containers/abc/export":
+			w.Header().Set("Content-Type", "application/x-tar")
+			_, _ = io.WriteString(w, "tar-data")
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message":"No such container"}`)
+		}
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Event{}))
+	svc := NewContainerService(&database.DB{DB: gdb}, NewEventService(&database.DB{DB: gdb}), &DockerClientService{client: cli}, nil, nil, nil)
+	user := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "admin"}
+
+	info, err := svc.GetContainerSnapshotInfo(ctx, "web")
+	require.NoError(t, err)
+	assert.Equal(t, int64(1024), info.SizeRw)
+	assert.Equal(t, []string{"/var/lib/data"}, info.Volumes)
+	require.Len(t, info.Warnings, 2)
+	assert.Contains(t, info.Warnings[1], "3.0 GB")
+
+	pause := false
+	result, err := svc.CommitContainer(ctx, "web", containertypes.CommitRequest{Repository: "web", Tag: "snap", Comment: "before upgrade", Pause: &pause, Changes: []string{"ENV DEBUG=1"}}, user)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:new", result.ImageID)
+	assert.Equal(t, "web:snap", result.Reference)
+	assert.Equal(t, int64(3221225472), result.Size)
+	assert.Len(t, result.Warnings, 2)
+	assert.Equal(t, []string{"abc"}, commitQuery["container"])
+	assert.Equal(t, []string{"docker.io/library/web"}, commitQuery["repo"])
+	assert.Equal(t, []string{"snap"}, commitQuery["tag"])
+	assert.Equal(t, []string{"admin"}, commitQuery["author"])
+	assert.Equal(t, []string{"0"}, commitQuery["pause"])
+	assert.Equal(t, []string{"ENV DEBUG=1"}, commitQuery["changes"])
+
+	stream, name, err := svc.ExportContainer(ctx, "web", user)
+	require.NoError(t, err)
+	data, err := io.ReadAll(stream)
+	require.NoError(t, err)
+	require.NoError(t, stream.Close())
+	assert.Equal(t, "web", name)
+	assert.Equal(t, "tar-data", string(data))
+
+	_, err = svc.CommitContainer(ctx, "missing", containertypes.CommitRequest{Repository: "web"}, user)
+	require.ErrorIs(t, err, ErrDockerContainerNotFound)
+	_, _, err = svc.ExportContainer(ctx, "missing", user)
+	require.ErrorIs(t, err, ErrDockerContainerNotFound)
+
+	var events []models.Event
+	require.NoError(t, gdb.Order("timestamp ASC").Find(&events).Error)
+	require.Len(t, events, 2)
+	assert.Equal(t, models.EventTypeContainerCommit, events[0].Type)
+	assert.Equal(t, "web:snap", events[0].Metadata["reference"])
+	assert.Equal(t, models.EventTypeContainerExport, events[1].Type)
+}
+
+func TestContainerService_BulkContainerAction(t *testing.T) {
+	ctx := context.Background()
+	var mu sync.Mutex
+	started := map[string]int{}
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		if r.Method == http.MethodPost && (path == "/containers/web/start" || path == "/containers/db/start") {
+			mu.Lock()
+			started[strings.Split(path, "/")[2]]++
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"message":"No such container"}`)
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Event{}))
+	svc := NewContainerService(&database.DB{DB: gdb}, NewEventService(&database.DB{DB: gdb}), &DockerClientService{client: cli}, nil, nil, nil)
+	user := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "admin"}
+
+	_, err = svc.BulkContainerAction(ctx, containertypes.BulkActionRequest{Action: "pause", ContainerIDs: []string{"web"}}, user)
+	require.ErrorIs(t, err, ErrInvalidBulkAction)
+	_, err = svc.BulkContainerAction(ctx, containertypes.BulkActionRequest{Action: "start", ContainerIDs: []string{" ", ""}}, user)
+	require.ErrorIs(t, err, ErrInvalidBulkAction)
+
+	result, err := svc.BulkContainerAction(ctx, containertypes.BulkActionRequest{
+		Action:       "start",
+		ContainerIDs: []string{"web", "missing", " web ", "db"},
+		Concurrency:  2,
+	}, user)
+	require.NoError(t, err)
+	assert.Equal(t, "start", result.Action)
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Results, 3)
+	assert.Equal(t, "web", result.Results[0].ContainerID)
+	assert.True(t, result.Results[0].Success)
+	assert.Equal(t, "missing", result.Results[1].ContainerID)
+	assert.False(t, result.Results[1].Success)
+	assert.Contains(t, result.Results[1].Error, "No such container")
+	assert.Equal(t, "db", result.Results[2].ContainerID)
+	assert.True(t, result.Results[2].Success)
+	assert.Equal(t, map[string]int{"web": 1, "db": 1}, started)
+}
+
+func TestStopOptionsForConfig(t *testing.T) {
+	fallback := defaultStopTimeout
+	configured := 60
+
+	opts := stopOptionsForConfigInternal(&container.Config{
+		Labels:      map[string]string{"com.getarcaneapp.arcane.stop-timeout": "2m"},
+		StopTimeout: &configured,
+	}, &fallback)
+	require.NotNil(t, opts.Timeout)
+	assert.Equal(t, 120, *opts.Timeout)
+
+	// The container's own stop timeout is left to the daemon.
+	opts = stopOptionsForConfigInternal(&container.Config{StopTimeout: &configured}, &fallback)
+	assert.Nil(t, opts.Timeout)
+
+	opts = stopOptionsForConfigInternal(&container.Config{Labels: map[string]string{"com.getarcaneapp.arcane.stop-timeout": "soon"}}, &fallback)
+	require.NotNil(t, opts.Timeout)
+	assert.Equal(t, defaultStopTimeout, *opts.Timeout)
+
+	assert.Nil(t, stopOptionsForConfigInternal(nil, nil).Timeout)
+}
+
+func newRestartPolicyTestDocker(t *testing.T, created *[]map[string]any) *ContainerService {
+	t.Helper()
+	inspects := map[string]string{
+		"a":   `{"Id":"a","Name":"/web","State":{"Running":true},"Config":{"Image":"nginx"},"HostConfig":{"RestartPolicy":{"Name":"no"}}}`,
+		"b":   `{"Id":"b","Name":"/db","State":{"Running":true},"Config":{"Image":"postgres"},"HostConfig":{"RestartPolicy":{"Name":"always"}}}`,
+		"c":   `{"Id":"c","Name":"/cache","Config":{"Image":"redis"},"HostConfig":{"RestartPolicy":{"Name":"unless-stopped"}}}`,
+		"d":   `{"Id":"d","Name":"/job","Config":{"Image":"busybox"},"HostConfig":{"AutoRemove":true}}`,
+		"new": `{"Id":"new","Name":"/web","State":{"Running":true},"Config":{"Image":"nginx"},"HostConfig":{"RestartPolicy":{"Name":"unless-stopped"}}}`,
+	}
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodGet && path == "/containers/json":
+			_, _ = io.WriteString(w, `[{"Id":"a","Image":"nginx","State":"running"},{"Id":"b","Image":"postgres","State":"running","Labels":{"com.docker.compose.project":"shop"}},{"Id":"c","Image":"redis"},{"Id":"d","Image":"busybox"}]`)
+		case r.Method == http.MethodGet && strings.HasSuffix(path, "/json"):
+			body, ok := inspects[strings.Split(path, "/")[2]]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = io.WriteString(w, `{"message":"No such container"}`)
+				return
+			}
+			_, _ = io.WriteString(w, body)
+		case r.Method == http.MethodPost && path == "/containers/create":
+			var body map[string]any
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			*created = append(*created, body)
+			_, _ = io.WriteString(w, `{"Id":"new"}`)
+		case r.Method == http.MethodPost, r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(docker.Close)
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Event{}))
+	db := &database.DB{DB: gdb}
+	return NewContainerService(db, NewEventService(db), &DockerClientService{client: cli}, nil, nil, nil)
+}
+
+func TestContainerService_AuditRestartPolicies(t *testing.T) {
+	var created []map[string]any
+	svc := newRestartPolicyTestDocker(t, &created)
+
+	audit, err := svc.AuditRestartPolicies(context.Background(), false)
+	require.NoError(t, err)
+	assert.Equal(t, 4, audit.TotalContainers)
+	require.Len(t, audit.Flagged, 2)
+	assert.Equal(t, "web", audit.Flagged[0].Name)
+	assert.Equal(t, containertypes.RestartPolicyIssueNone, audit.Flagged[0].Issue)
+	assert.Equal(t, "db", audit.Flagged[1].Name)
+	assert.Equal(t, containertypes.RestartPolicyIssueAlways, audit.Flagged[1].Issue)
+	assert.Equal(t, "shop", audit.Flagged[1].Project)
+	assert.Equal(t, "unless-stopped", audit.Flagged[1].Recommended)
+}
+
+func TestContainerService_UpdateRestartPolicies(t *testing.T) {
+	ctx := context.Background()
+	var created []map[string]any
+	svc := newRestartPolicyTestDocker(t, &created)
+
+	_, err := svc.UpdateRestartPolicies(ctx, containertypes.RestartPolicyUpdate{ContainerIDs: []string{"a"}, Policy: "sometimes"}, models.User{})
+	require.Error(t, err)
+
+	result, err := svc.UpdateRestartPolicies(ctx, containertypes.RestartPolicyUpdate{
+		ContainerIDs: []string{"a", "c", "missing"},
+		Policy:       "unless-stopped",
+	}, models.User{Username: "admin"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Succeeded)
+	assert.Equal(t, 1, result.Failed)
+	assert.True(t, result.Results[0].Success)
+	assert.True(t, result.Results[1].Success)
+	assert.False(t, result.Results[2].Success)
+	assert.NotEmpty(t, result.Results[2].Error)
+
+	// Only the container whose policy differs is recreated.
+	require.Len(t, created, 1)
+	hostConfig := created[0]["HostConfig"].(map[string]any)
+	assert.Equal(t, "unless-stopped", hostConfig["RestartPolicy"].(map[string]any)["Name"])
+}
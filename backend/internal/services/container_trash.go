@@ -0,0 +1,309 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/pkg/libarcane"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+)
+
+// containerTrashPrefix starts the name of every trashed container. Docker
+// does not allow labels to change after a container is created, so the name
+// is what marks a container as trashed outside of the database.
+const containerTrashPrefix = "arcane-trash-"
+
+// trashContainerInternal stops a container, disables its restart policy and
+// renames it out of the way instead of removing it. The trash purge job
+// removes it once the retention has passed.
+func (s *ContainerService) trashContainerInternal(ctx context.Context, containerID string, force bool, removeVolumes bool, user models.User) error {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	inspect, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return fmt.Errorf("%w: %s", ErrDockerContainerNotFound, containerID)
+		}
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	name := strings.TrimPrefix(inspect.Name, "/")
+	// Arcane's own containers and containers that are already trashed are
+	// removed right away.
+	if strings.HasPrefix(name, containerTrashPrefix) || (inspect.Config != nil && libarcane.IsInternalContainer(inspect.Config.Labels)) {
+		return s.RemoveContainer(ctx, inspect.ID, force, removeVolumes, user)
+	}
+
+	wasRunning := inspect.State != nil && inspect.State.Running
+	if wasRunning && !force {
+		return fmt.Errorf("%w: %s", ErrContainerRunning, name)
+	}
+
+	var policy container.RestartPolicy
+	if inspect.HostConfig != nil {
+		policy = inspect.HostConfig.RestartPolicy
+	}
+
+	if wasRunning {
+		if err := dockerClient.ContainerStop(ctx, inspect.ID, container.StopOptions{}); err != nil {
+			s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", inspect.ID, name, user.ID, user.Username, "0", err, models.JSON{"action": "delete", "trash": true})
+			return fmt.Errorf("failed to stop container: %w", err)
+		}
+	}
+
+	// Keep the Docker daemon from starting the container again on restart.
+	if policy.Name != "" && policy.Name != container.RestartPolicyDisabled {
+		if _, err := dockerClient.ContainerUpdate(ctx, inspect.ID, container.UpdateConfig{RestartPolicy: container.RestartPolicy{Name: container.RestartPolicyDisabled}}); err != nil {
+			return fmt.Errorf("failed to disable restart policy: %w", err)
+		}
+	}
+
+	trashName := containerTrashPrefix + name + "-" + shortContainerIDInternal(inspect.ID)
+	if err := dockerClient.ContainerRename(ctx, inspect.ID, trashName); err != nil {
+		s.restoreRestartPolicyInternal(ctx, inspect.ID, policy)
+		return fmt.Errorf("failed to rename container: %w", err)
+	}
+
+	retention := s.trashRetentionInternal(ctx)
+	entry := models.ContainerTrashEntry{
+		ContainerID:    inspect.ID,
+		OriginalName:   name,
+		TrashName:      trashName,
+		RestartPolicy:  string(policy.Name),
+		RestartRetries: policy.MaximumRetryCount,
+		WasRunning:     wasRunning,
+		RemoveVolumes:  removeVolumes,
+		DeletedBy:      user.Username,
+		PurgeAt:        time.Now().Add(retention),
+	}
+	if err := s.db.WithContext(ctx).Create(&entry).Error; err != nil {
+		// Without an entry the container could never be purged or restored,
+		// so undo the rename and leave it where it was.
+		if renameErr := dockerClient.ContainerRename(ctx, inspect.ID, name); renameErr != nil {
+			slog.WarnContext(ctx, "Failed to rename container back after trash failure", "container", name, "error", renameErr)
+		}
+		s.restoreRestartPolicyInternal(ctx, inspect.ID, policy)
+		return fmt.Errorf("failed to record trashed container: %w", err)
+	}
+
+	metadata := models.JSON{
+		"action":      "delete",
+		"containerId": inspect.ID,
+		"trashed":     true,
+		"purgeAt":     entry.PurgeAt,
+	}
+	if err := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerDelete, inspect.ID, name, user.ID, user.Username, "0", metadata); err != nil {
+		slog.WarnContext(ctx, "Could not log container delete action", "container", name, "error", err)
+	}
+
+	return nil
+}
+
+// ListTrash returns the containers that are in the trash, oldest first.
+func (s *ContainerService) ListTrash(ctx context.Context) ([]containertypes.TrashEntry, error) {
+	var entries []models.ContainerTrashEntry
+	if err := s.db.WithContext(ctx).Order("created_at ASC").Find(&entries).Error; err != nil {
+		return nil, fmt.Errorf("failed to list trashed containers: %w", err)
+	}
+
+	out := make([]containertypes.TrashEntry, 0, len(entries))
+	for _, entry := range entries {
+		out = append(out, containertypes.TrashEntry{
+			ContainerID:   entry.ContainerID,
+			OriginalName:  entry.OriginalName,
+			TrashName:     entry.TrashName,
+			WasRunning:    entry.WasRunning,
+			RemoveVolumes: entry.RemoveVolumes,
+			DeletedBy:     entry.DeletedBy,
+			DeletedAt:     entry.CreatedAt,
+			PurgeAt:       entry.PurgeAt,
+		})
+	}
+	return out, nil
+}
+
+// RestoreFromTrash gives a trashed container its name and restart policy
+// back and starts it again if it was running when it was deleted.
+func (s *ContainerService) RestoreFromTrash(ctx context.Context, containerID string, user models.User) error {
+	entry, err := s.getTrashEntryInternal(ctx, containerID)
+	if err != nil {
+		return err
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	if err := dockerClient.ContainerRename(ctx, entry.ContainerID, entry.OriginalName); err != nil {
+		if cerrdefs.IsNotFound(err) {
+			s.deleteTrashEntryInternal(ctx, entry.ContainerID)
+			return fmt.Errorf("%w: %s", ErrDockerContainerNotFound, entry.ContainerID)
+		}
+		if cerrdefs.IsConflict(err) {
+			return fmt.Errorf("%w: %s", ErrContainerNameInUse, entry.OriginalName)
+		}
+		return fmt.Errorf("failed to rename container: %w", err)
+	}
+
+	s.restoreRestartPolicyInternal(ctx, entry.ContainerID, container.RestartPolicy{
+		Name:              container.RestartPolicyMode(entry.RestartPolicy),
+		MaximumRetryCount: entry.RestartRetries,
+	})
+	s.deleteTrashEntryInternal(ctx, entry.ContainerID)
+
+	if entry.WasRunning {
+		if err := dockerClient.ContainerStart(ctx, entry.ContainerID, container.StartOptions{}); err != nil {
+			s.eventService.LogErrorEvent(ctx, models.EventTypeContainerError, "container", entry.ContainerID, entry.OriginalName, user.ID, user.Username, "0", err, models.JSON{"action": "restore"})
+			return fmt.Errorf("container restored but failed to start: %w", err)
+		}
+	}
+
+	metadata := models.JSON{
+		"action":      "restore",
+		"containerId": entry.ContainerID,
+	}
+	if err := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerRestore, entry.ContainerID, entry.OriginalName, user.ID, user.Username, "0", metadata); err != nil {
+		slog.WarnContext(ctx, "Could not log container restore action", "container", entry.OriginalName, "error", err)
+	}
+
+	return nil
+}
+
+// PurgeTrash removes a trashed container for good without waiting for its
+// retention to pass.
+func (s *ContainerService) PurgeTrash(ctx context.Context, containerID string, user models.User) error {
+	entry, err := s.getTrashEntryInternal(ctx, containerID)
+	if err != nil {
+		return err
+	}
+	_, err = s.purgeTrashEntryInternal(ctx, entry, user)
+	return err
+}
+
+// PurgeExpiredTrash removes every trashed container whose retention has
+// passed. It returns the number of containers removed.
+func (s *ContainerService) PurgeExpiredTrash(ctx context.Context) (int, error) {
+	var entries []models.ContainerTrashEntry
+	if err := s.db.WithContext(ctx).Where("purge_at <= ?", time.Now()).Find(&entries).Error; err != nil {
+		return 0, fmt.Errorf("failed to list expired trashed containers: %w", err)
+	}
+
+	purged := 0
+	var errs []error
+	for i := range entries {
+		removed, err := s.purgeTrashEntryInternal(ctx, &entries[i], systemUser)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", entries[i].OriginalName, err))
+			continue
+		}
+		if removed {
+			purged++
+		}
+	}
+	return purged, errors.Join(errs...)
+}
+
+// purgeTrashEntryInternal removes a trashed container and its entry. It
+// reports whether a container was actually removed.
+func (s *ContainerService) purgeTrashEntryInternal(ctx context.Context, entry *models.ContainerTrashEntry, user models.User) (bool, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return false, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	inspect, err := dockerClient.ContainerInspect(ctx, entry.ContainerID)
+	if err != nil {
+		if cerrdefs.IsNotFound(err) {
+			s.deleteTrashEntryInternal(ctx, entry.ContainerID)
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to inspect container: %w", err)
+	}
+
+	// Something started the container again, for example a compose project
+	// that picked it up by its labels. It is in use, so leave it alone.
+	if inspect.State != nil && inspect.State.Running {
+		slog.InfoContext(ctx, "Trashed container is running again, keeping it", "container", entry.OriginalName, "id", entry.ContainerID)
+		s.deleteTrashEntryInternal(ctx, entry.ContainerID)
+		return false, nil
+	}
+
+	if err := s.RemoveContainer(ctx, entry.ContainerID, true, entry.RemoveVolumes, user); err != nil {
+		return false, err
+	}
+	s.deleteTrashEntryInternal(ctx, entry.ContainerID)
+	return true, nil
+}
+
+func (s *ContainerService) getTrashEntryInternal(ctx context.Context, containerID string) (*models.ContainerTrashEntry, error) {
+	var entry models.ContainerTrashEntry
+	err := s.db.WithContext(ctx).Where("container_id = ? OR container_id LIKE ?", containerID, containerID+"%").First(&entry).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrTrashEntryNotFound, containerID)
+		}
+		return nil, fmt.Errorf("failed to load trashed container: %w", err)
+	}
+	return &entry, nil
+}
+
+func (s *ContainerService) deleteTrashEntryInternal(ctx context.Context, containerID string) {
+	if err := s.db.WithContext(ctx).Where("container_id = ?", containerID).Delete(&models.ContainerTrashEntry{}).Error; err != nil {
+		slog.WarnContext(ctx, "Failed to delete container trash entry", "id", containerID, "error", err)
+	}
+}
+
+func (s *ContainerService) restoreRestartPolicyInternal(ctx context.Context, containerID string, policy container.RestartPolicy) {
+	if policy.Name == "" || policy.Name == container.RestartPolicyDisabled {
+		return
+	}
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return
+	}
+	if _, err := dockerClient.ContainerUpdate(ctx, containerID, container.UpdateConfig{RestartPolicy: policy}); err != nil {
+		slog.WarnContext(ctx, "Failed to restore container restart policy", "id", containerID, "policy", policy.Name, "error", err)
+	}
+}
+
+func (s *ContainerService) trashRetentionInternal(ctx context.Context) time.Duration {
+	if s.settingsService == nil || s.db == nil {
+		return 0
+	}
+	hours := s.settingsService.GetIntSetting(ctx, "containerTrashRetention", 0)
+	if hours <= 0 {
+		return 0
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+func filterTrashedContainersInternal(containers []container.Summary) []container.Summary {
+	filtered := make([]container.Summary, 0, len(containers))
+	for _, dc := range containers {
+		if len(dc.Names) > 0 && strings.HasPrefix(strings.TrimPrefix(dc.Names[0], "/"), containerTrashPrefix) {
+			continue
+		}
+		filtered = append(filtered, dc)
+	}
+	return filtered
+}
+
+func shortContainerIDInternal(id string) string {
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
+}
@@ -0,0 +1,176 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+// fakeTrashDocker serves a single container and records what the trash
+// does to it.
+type fakeTrashDocker struct {
+	mu       sync.Mutex
+	id       string
+	name     string
+	running  bool
+	policy   string
+	removed  bool
+	policies []string
+}
+
+func (f *fakeTrashDocker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.removed && !strings.HasSuffix(r.URL.Path, "/_ping") {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		_, _ = io.WriteString(w, `{"message":"No such container"}`)
+		return
+	}
+
+	switch {
+	case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/json"):
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = fmt.Fprintf(w, `{"Id":%q,"Name":"/%s","State":{"Running":%t},"HostConfig":{"RestartPolicy":{"Name":%q}},"Config":{"Labels":{}}}`, f.id, f.name, f.running, f.policy)
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/stop"):
+		f.running = false
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/start"):
+		f.running = true
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/update"):
+		var body struct {
+			RestartPolicy struct{ Name string }
+		}
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		f.policy = body.RestartPolicy.Name
+		f.policies = append(f.policies, body.RestartPolicy.Name)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"Warnings":[]}`)
+	case r.Method == http.MethodPost && strings.HasSuffix(r.URL.Path, "/rename"):
+		f.name = r.URL.Query().Get("name")
+		w.WriteHeader(http.StatusNoContent)
+	case r.Method == http.MethodDelete:
+		f.removed = true
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func setupContainerTrashTest(t *testing.T, fake *fakeTrashDocker) (*ContainerService, *gorm.DB) {
+	t.Helper()
+	ctx := context.Background()
+
+	docker := httptest.NewServer(fake)
+	t.Cleanup(docker.Close)
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.SettingVariable{}, &models.Event{}, &models.ContainerTrashEntry{}))
+	db := &database.DB{DB: gdb}
+
+	settingsService, err := NewSettingsService(ctx, db)
+	require.NoError(t, err)
+	require.NoError(t, settingsService.EnsureDefaultSettings(ctx))
+	require.NoError(t, settingsService.SetIntSetting(ctx, "containerTrashRetention", 24))
+
+	return NewContainerService(db, NewEventService(db), &DockerClientService{client: cli}, nil, settingsService, nil), gdb
+}
+
+func TestContainerService_TrashAndRestore(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeTrashDocker{id: "0123456789abcdef", name: "web", running: true, policy: "unless-stopped"}
+	svc, _ := setupContainerTrashTest(t, fake)
+	user := models.User{Username: "admin"}
+
+	require.ErrorIs(t, svc.DeleteContainer(ctx, fake.id, false, false, user), ErrContainerRunning)
+
+	require.NoError(t, svc.DeleteContainer(ctx, fake.id, true, false, user))
+	assert.False(t, fake.running)
+	assert.False(t, fake.removed)
+	assert.Equal(t, "no", fake.policy)
+	assert.Equal(t, "arcane-trash-web-0123456789ab", fake.name)
+
+	entries, err := svc.ListTrash(ctx)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "web", entries[0].OriginalName)
+	assert.True(t, entries[0].WasRunning)
+	assert.WithinDuration(t, time.Now().Add(24*time.Hour), entries[0].PurgeAt, time.Minute)
+
+	require.NoError(t, svc.RestoreFromTrash(ctx, "0123", user))
+	assert.Equal(t, "web", fake.name)
+	assert.Equal(t, "unless-stopped", fake.policy)
+	assert.True(t, fake.running)
+
+	entries, err = svc.ListTrash(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+	require.ErrorIs(t, svc.RestoreFromTrash(ctx, fake.id, user), ErrTrashEntryNotFound)
+}
+
+func TestContainerService_PurgeExpiredTrash(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeTrashDocker{id: "0123456789abcdef", name: "web", policy: "no"}
+	svc, gdb := setupContainerTrashTest(t, fake)
+
+	require.NoError(t, svc.DeleteContainer(ctx, fake.id, false, false, models.User{Username: "admin"}))
+	assert.Empty(t, fake.policies, "a disabled restart policy is left alone")
+
+	purged, err := svc.PurgeExpiredTrash(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, purged, "retention has not passed yet")
+	assert.False(t, fake.removed)
+
+	require.NoError(t, gdb.Model(&models.ContainerTrashEntry{}).Where("container_id = ?", fake.id).Update("purge_at", time.Now().Add(-time.Minute)).Error)
+	purged, err = svc.PurgeExpiredTrash(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+	assert.True(t, fake.removed)
+
+	var count int64
+	require.NoError(t, gdb.Model(&models.ContainerTrashEntry{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestContainerService_PurgeExpiredTrashKeepsAdoptedContainer(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeTrashDocker{id: "0123456789abcdef", name: "web"}
+	svc, gdb := setupContainerTrashTest(t, fake)
+
+	require.NoError(t, svc.DeleteContainer(ctx, fake.id, false, false, models.User{Username: "admin"}))
+	require.NoError(t, gdb.Model(&models.ContainerTrashEntry{}).Where("container_id = ?", fake.id).Update("purge_at", time.Now().Add(-time.Minute)).Error)
+
+	fake.mu.Lock()
+	fake.running = true
+	fake.mu.Unlock()
+
+	purged, err := svc.PurgeExpiredTrash(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, purged)
+	assert.False(t, fake.removed)
+
+	entries, err := svc.ListTrash(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
@@ -3,7 +3,9 @@ package services
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"sync"
+	"time"
 
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/image"
@@ -11,8 +13,10 @@ import (
 	"github.com/docker/docker/api/types/network"
 	"github.com/docker/docker/api/types/volume"
 	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/sockets"
 	"github.com/getarcaneapp/arcane/backend/internal/config"
 	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/chaos"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/docker"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/timeouts"
 )
@@ -22,15 +26,22 @@ type DockerClientService struct {
 	config          *config.Config
 	settingsService *SettingsService
 	client          *client.Client
+	limiter         *docker.APILimiter
+	chaos           *chaos.Injector
 	mu              sync.Mutex
 }
 
-func NewDockerClientService(db *database.DB, cfg *config.Config, settingsService *SettingsService) *DockerClientService {
-	return &DockerClientService{
+// NewDockerClientService creates the service that owns the Docker client. A
+// non-nil injector delays and fails Docker API requests for chaos testing.
+func NewDockerClientService(db *database.DB, cfg *config.Config, settingsService *SettingsService, injector *chaos.Injector) *DockerClientService {
+	s := &DockerClientService{
 		db:              db,
 		config:          cfg,
 		settingsService: settingsService,
+		chaos:           injector,
 	}
+	s.limiter = docker.NewAPILimiter(s.apiLimitsInternal)
+	return s
 }
 
 // GetClient returns a singleton Docker client instance.
@@ -48,8 +59,14 @@ func (s *DockerClientService) GetClient() (*client.Client, error) {
 		return s.client, nil
 	}
 
+	httpClient, err := s.newLimitedHTTPClientInternal()
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Docker client: %w", err)
+	}
+
 	cli, err := client.NewClientWithOpts(
 		client.WithHost(s.config.DockerHost),
+		client.WithHTTPClient(httpClient),
 		client.WithAPIVersionNegotiation(),
 	)
 	if err != nil {
@@ -60,6 +77,52 @@ func (s *DockerClientService) GetClient() (*client.Client, error) {
 	return s.client, nil
 }
 
+// newLimitedHTTPClientInternal builds the HTTP client the Docker client would
+// use by default, with its transport wrapped by the API limiter and, in
+// chaos mode, by the fault injector.
+func (s *DockerClientService) newLimitedHTTPClientInternal() (*http.Client, error) {
+	hostURL, err := client.ParseHostURL(s.config.DockerHost)
+	if err != nil {
+		return nil, err
+	}
+
+	transport := &http.Transport{
+		MaxIdleConns:    6,
+		IdleConnTimeout: 30 * time.Second,
+	}
+	if err := sockets.ConfigureTransport(transport, hostURL.Scheme, hostURL.Host); err != nil {
+		return nil, err
+	}
+
+	return &http.Client{
+		Transport:     s.limiter.Transport(s.chaos.Transport(transport)),
+		CheckRedirect: client.CheckRedirect,
+	}, nil
+}
+
+// AcquireExec waits until another exec session may run under the
+// dockerMaxConcurrentExecs setting and returns the func that ends it.
+// Interactive terminals are not limited.
+func (s *DockerClientService) AcquireExec(ctx context.Context) (func(), error) {
+	return s.limiter.Acquire(ctx, docker.APILimitExec)
+}
+
+func (s *DockerClientService) apiLimitsInternal() docker.APILimits {
+	if s.settingsService == nil {
+		return docker.APILimits{}
+	}
+	settings := s.settingsService.GetSettingsConfig()
+	if settings == nil {
+		return docker.APILimits{}
+	}
+	return docker.APILimits{
+		Requests:  settings.DockerMaxConcurrentRequests.AsInt(),
+		DiskUsage: settings.DockerMaxConcurrentDiskUsage.AsInt(),
+		Exec:      settings.DockerMaxConcurrentExecs.AsInt(),
+		Pulls:     settings.DockerMaxConcurrentPulls.AsInt(),
+	}
+}
+
 func (s *DockerClientService) GetAllContainers(ctx context.Context) ([]container.Summary, int, int, int, error) {
 	dockerClient, err := s.GetClient()
 	if err != nil {
@@ -0,0 +1,510 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	monitortypes "github.com/getarcaneapp/arcane/types/monitor"
+)
+
+const (
+	MonitorTypeHTTP = "http"
+	MonitorTypeTCP  = "tcp"
+
+	MonitorStatusPending = "pending"
+	MonitorStatusUp      = "up"
+	MonitorStatusDown    = "down"
+
+	defaultMonitorInterval     = 60
+	defaultMonitorTimeout      = 10
+	defaultMonitorRetries      = 2
+	minMonitorInterval         = 10
+	defaultMonitorResultsLimit = 100
+	maxMonitorResultsLimit     = 1000
+	maxParallelMonitors        = 8
+	monitorResultRetention     = 7 * 24 * time.Hour
+	monitorPruneInterval       = time.Hour
+)
+
+var (
+	ErrEndpointMonitorNotFound = errors.New("endpoint monitor not found")
+	ErrInvalidEndpointMonitor  = errors.New("invalid endpoint monitor")
+)
+
+// EndpointMonitorService probes application endpoints over HTTP or TCP so
+// that a container which is running but not serving is noticed. Every probe
+// is kept as history, and a monitor going down is logged as an event and
+// sent as a notification.
+type EndpointMonitorService struct {
+	db                  *database.DB
+	eventService        *EventService
+	notificationService *NotificationService
+	httpClient          *http.Client
+	running             atomic.Bool
+	lastPrune           time.Time
+}
+
+func NewEndpointMonitorService(db *database.DB, eventService *EventService, notificationService *NotificationService) *EndpointMonitorService {
+	return &EndpointMonitorService{
+		db:                  db,
+		eventService:        eventService,
+		notificationService: notificationService,
+		httpClient: &http.Client{
+			// The expected status is matched against the endpoint itself.
+			CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse },
+		},
+	}
+}
+
+// ListMonitors returns all endpoint monitors, optionally only those attached
+// to a project or container.
+func (s *EndpointMonitorService) ListMonitors(ctx context.Context, projectID, containerKey string) ([]monitortypes.Monitor, error) {
+	q := s.db.WithContext(ctx).Order("name ASC")
+	if projectID != "" {
+		q = q.Where("project_id = ?", projectID)
+	}
+	if containerKey != "" {
+		q = q.Where("container_key = ?", strings.TrimPrefix(containerKey, "/"))
+	}
+
+	var monitors []models.EndpointMonitor
+	if err := q.Find(&monitors).Error; err != nil {
+		return nil, fmt.Errorf("failed to list endpoint monitors: %w", err)
+	}
+
+	out := make([]monitortypes.Monitor, 0, len(monitors))
+	for _, m := range monitors {
+		out = append(out, toEndpointMonitorDTO(m))
+	}
+	return out, nil
+}
+
+// GetMonitor returns a single endpoint monitor.
+func (s *EndpointMonitorService) GetMonitor(ctx context.Context, id string) (*monitortypes.Monitor, error) {
+	monitor, err := s.loadMonitorInternal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	out := toEndpointMonitorDTO(*monitor)
+	return &out, nil
+}
+
+// CreateMonitor adds an endpoint monitor.
+func (s *EndpointMonitorService) CreateMonitor(ctx context.Context, req monitortypes.Request, user models.User) (*monitortypes.Monitor, error) {
+	var monitor models.EndpointMonitor
+	if err := s.applyRequestInternal(ctx, &monitor, req, user); err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Create(&monitor).Error; err != nil {
+		return nil, fmt.Errorf("failed to create endpoint monitor: %w", err)
+	}
+	out := toEndpointMonitorDTO(monitor)
+	return &out, nil
+}
+
+// UpdateMonitor replaces the definition of an endpoint monitor. Its status
+// starts over as pending; the result history is kept.
+func (s *EndpointMonitorService) UpdateMonitor(ctx context.Context, id string, req monitortypes.Request, user models.User) (*monitortypes.Monitor, error) {
+	monitor, err := s.loadMonitorInternal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyRequestInternal(ctx, monitor, req, user); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	monitor.UpdatedAt = &now
+	if err := s.db.WithContext(ctx).Save(monitor).Error; err != nil {
+		return nil, fmt.Errorf("failed to update endpoint monitor: %w", err)
+	}
+	out := toEndpointMonitorDTO(*monitor)
+	return &out, nil
+}
+
+// DeleteMonitor removes an endpoint monitor and its result history.
+func (s *EndpointMonitorService) DeleteMonitor(ctx context.Context, id string) error {
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&models.EndpointMonitor{})
+		if result.Error != nil {
+			return fmt.Errorf("failed to delete endpoint monitor: %w", result.Error)
+		}
+		if result.RowsAffected == 0 {
+			return ErrEndpointMonitorNotFound
+		}
+		if err := tx.Where("monitor_id = ?", id).Delete(&models.EndpointMonitorResult{}).Error; err != nil {
+			return fmt.Errorf("failed to delete endpoint monitor results: %w", err)
+		}
+		return nil
+	})
+}
+
+// ListResults returns the most recent probe results of a monitor, newest
+// first. A limit of 0 returns the default number of results.
+func (s *EndpointMonitorService) ListResults(ctx context.Context, id string, limit int) ([]monitortypes.Result, error) {
+	if _, err := s.loadMonitorInternal(ctx, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = defaultMonitorResultsLimit
+	}
+	limit = min(limit, maxMonitorResultsLimit)
+
+	var results []models.EndpointMonitorResult
+	err := s.db.WithContext(ctx).
+		Where("monitor_id = ?", id).
+		Order("checked_at DESC").
+		Limit(limit).
+		Find(&results).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to list endpoint monitor results: %w", err)
+	}
+
+	out := make([]monitortypes.Result, 0, len(results))
+	for _, r := range results {
+		out = append(out, monitortypes.Result{
+			Up:         r.Up,
+			StatusCode: r.StatusCode,
+			LatencyMs:  r.LatencyMs,
+			Error:      r.Error,
+			CheckedAt:  r.CheckedAt,
+		})
+	}
+	return out, nil
+}
+
+// RunDueChecks probes every enabled monitor whose interval has elapsed and
+// returns the number of probes run. Calls made while a previous run is
+// still in progress return immediately.
+func (s *EndpointMonitorService) RunDueChecks(ctx context.Context) int {
+	if !s.running.CompareAndSwap(false, true) {
+		return 0
+	}
+	defer s.running.Store(false)
+
+	var monitors []models.EndpointMonitor
+	if err := s.db.WithContext(ctx).Where("enabled = ?", true).Find(&monitors).Error; err != nil {
+		slog.WarnContext(ctx, "Failed to load endpoint monitors", "error", err)
+		return 0
+	}
+
+	now := time.Now()
+	due := make([]models.EndpointMonitor, 0, len(monitors))
+	for _, m := range monitors {
+		if m.LastCheckedAt == nil || now.Sub(*m.LastCheckedAt) >= time.Duration(m.IntervalSeconds)*time.Second {
+			due = append(due, m)
+		}
+	}
+
+	sem := make(chan struct{}, maxParallelMonitors)
+	var wg sync.WaitGroup
+	for i := range due {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(monitor *models.EndpointMonitor) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			s.runCheckInternal(ctx, monitor)
+		}(&due[i])
+	}
+	wg.Wait()
+
+	if now.Sub(s.lastPrune) >= monitorPruneInterval {
+		s.lastPrune = now
+		if err := s.db.WithContext(ctx).Where("checked_at < ?", now.Add(-monitorResultRetention)).Delete(&models.EndpointMonitorResult{}).Error; err != nil {
+			slog.WarnContext(ctx, "Failed to prune endpoint monitor results", "error", err)
+		}
+	}
+	return len(due)
+}
+
+func (s *EndpointMonitorService) runCheckInternal(ctx context.Context, monitor *models.EndpointMonitor) {
+	probeCtx, cancel := context.WithTimeout(ctx, time.Duration(monitor.TimeoutSeconds)*time.Second)
+	started := time.Now()
+	statusCode, probeErr := s.probeInternal(probeCtx, monitor)
+	latency := time.Since(started).Milliseconds()
+	cancel()
+
+	result := models.EndpointMonitorResult{
+		MonitorID:  monitor.ID,
+		Up:         probeErr == nil,
+		StatusCode: statusCode,
+		LatencyMs:  latency,
+		CheckedAt:  started,
+	}
+	if probeErr != nil {
+		result.Error = truncateHealthOutputInternal(probeErr.Error())
+	}
+	if err := s.db.WithContext(ctx).Create(&result).Error; err != nil {
+		slog.WarnContext(ctx, "Failed to save endpoint monitor result", "monitor", monitor.Name, "error", err)
+	}
+
+	previous := monitor.Status
+	monitor.LastCheckedAt = &started
+	monitor.LastStatusCode = statusCode
+	monitor.LastLatencyMs = latency
+	monitor.LastError = result.Error
+	if result.Up {
+		monitor.FailingStreak = 0
+		monitor.Status = MonitorStatusUp
+	} else {
+		monitor.FailingStreak++
+		if monitor.FailingStreak >= monitor.Retries {
+			monitor.Status = MonitorStatusDown
+		}
+	}
+
+	err := s.db.WithContext(ctx).Model(monitor).
+		Select("status", "failing_streak", "last_status_code", "last_latency_ms", "last_error", "last_checked_at").
+		Updates(monitor).Error
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to save endpoint monitor status", "monitor", monitor.Name, "error", err)
+	}
+
+	switch {
+	case monitor.Status == MonitorStatusDown && previous != MonitorStatusDown:
+		s.logEventInternal(ctx, models.EventTypeMonitorDown, monitor)
+		if monitor.Notify && s.notificationService != nil {
+			if err := s.notificationService.SendMonitorDownNotification(ctx, s.notificationPayloadInternal(ctx, monitor)); err != nil {
+				slog.WarnContext(ctx, "Failed to send endpoint monitor notification", "monitor", monitor.Name, "error", err)
+			}
+		}
+	case monitor.Status == MonitorStatusUp && previous == MonitorStatusDown:
+		s.logEventInternal(ctx, models.EventTypeMonitorUp, monitor)
+	}
+}
+
+// probeInternal runs a single probe and returns the HTTP status, if any. A
+// nil error means the endpoint is up.
+func (s *EndpointMonitorService) probeInternal(ctx context.Context, monitor *models.EndpointMonitor) (int, error) {
+	if monitor.Type == MonitorTypeTCP {
+		conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", monitor.Target)
+		if err != nil {
+			return 0, err
+		}
+		_ = conn.Close()
+		return 0, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, monitor.Target, nil)
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("User-Agent", "Arcane-Monitor")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	_ = resp.Body.Close()
+
+	if monitor.ExpectedStatus != 0 {
+		if resp.StatusCode != monitor.ExpectedStatus {
+			return resp.StatusCode, fmt.Errorf("HTTP %d, expected %d", resp.StatusCode, monitor.ExpectedStatus)
+		}
+	} else if resp.StatusCode < 200 || resp.StatusCode >= 400 {
+		return resp.StatusCode, fmt.Errorf("HTTP %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+func (s *EndpointMonitorService) logEventInternal(ctx context.Context, eventType models.EventType, monitor *models.EndpointMonitor) {
+	if s.eventService == nil {
+		return
+	}
+
+	resourceType := "monitor"
+	metadata := models.JSON{
+		"type":          monitor.Type,
+		"target":        monitor.Target,
+		"failingStreak": monitor.FailingStreak,
+	}
+	if monitor.LastError != "" {
+		metadata["error"] = monitor.LastError
+	}
+	if monitor.ProjectID != nil {
+		metadata["projectId"] = *monitor.ProjectID
+	}
+	if monitor.ContainerKey != nil {
+		metadata["containerKey"] = *monitor.ContainerKey
+	}
+
+	environmentID := "0"
+	_, err := s.eventService.CreateEvent(ctx, CreateEventRequest{
+		Type:          eventType,
+		Severity:      s.eventService.getEventSeverity(eventType),
+		Title:         s.eventService.generateEventTitle(eventType, monitor.Name),
+		Description:   s.eventService.generateEventDescription(eventType, resourceType, monitor.Name),
+		ResourceType:  &resourceType,
+		ResourceID:    &monitor.ID,
+		ResourceName:  &monitor.Name,
+		UserID:        &systemUser.ID,
+		Username:      &systemUser.Username,
+		EnvironmentID: &environmentID,
+		Metadata:      metadata,
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "Could not log endpoint monitor event", "monitor", monitor.Name, "error", err)
+	}
+}
+
+func (s *EndpointMonitorService) notificationPayloadInternal(ctx context.Context, monitor *models.EndpointMonitor) MonitorNotificationPayload {
+	payload := MonitorNotificationPayload{
+		Name:   monitor.Name,
+		Target: monitor.Target,
+		Error:  monitor.LastError,
+	}
+	if monitor.ContainerKey != nil {
+		payload.Container = *monitor.ContainerKey
+	}
+	if monitor.ProjectID != nil {
+		var project models.Project
+		if err := s.db.WithContext(ctx).Select("name").Where("id = ?", *monitor.ProjectID).First(&project).Error; err == nil {
+			payload.Project = project.Name
+		}
+	}
+	return payload
+}
+
+func (s *EndpointMonitorService) loadMonitorInternal(ctx context.Context, id string) (*models.EndpointMonitor, error) {
+	var monitor models.EndpointMonitor
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&monitor).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrEndpointMonitorNotFound
+		}
+		return nil, fmt.Errorf("failed to load endpoint monitor: %w", err)
+	}
+	return &monitor, nil
+}
+
+// applyRequestInternal validates req and copies it onto monitor, resetting
+// the probe state.
+func (s *EndpointMonitorService) applyRequestInternal(ctx context.Context, monitor *models.EndpointMonitor, req monitortypes.Request, user models.User) error {
+	req, err := normalizeMonitorRequestInternal(req)
+	if err != nil {
+		return err
+	}
+
+	var projectID, containerKey *string
+	if req.ProjectID != "" {
+		var count int64
+		if err := s.db.WithContext(ctx).Model(&models.Project{}).Where("id = ?", req.ProjectID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to look up project: %w", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("%w: project %s does not exist", ErrInvalidEndpointMonitor, req.ProjectID)
+		}
+		projectID = &req.ProjectID
+	}
+	if req.ContainerKey != "" {
+		containerKey = &req.ContainerKey
+	}
+
+	monitor.Name = req.Name
+	monitor.Type = req.Type
+	monitor.Target = req.Target
+	monitor.ExpectedStatus = req.ExpectedStatus
+	monitor.IntervalSeconds = req.IntervalSeconds
+	monitor.TimeoutSeconds = req.TimeoutSeconds
+	monitor.Retries = req.Retries
+	monitor.ProjectID = projectID
+	monitor.ContainerKey = containerKey
+	monitor.Enabled = req.Enabled == nil || *req.Enabled
+	monitor.Notify = req.Notify == nil || *req.Notify
+	monitor.Status = MonitorStatusPending
+	monitor.FailingStreak = 0
+	monitor.LastStatusCode = 0
+	monitor.LastLatencyMs = 0
+	monitor.LastError = ""
+	monitor.LastCheckedAt = nil
+	monitor.UpdatedBy = user.Username
+	return nil
+}
+
+func normalizeMonitorRequestInternal(req monitortypes.Request) (monitortypes.Request, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return req, fmt.Errorf("%w: name is required", ErrInvalidEndpointMonitor)
+	}
+	req.Target = strings.TrimSpace(req.Target)
+	req.ProjectID = strings.TrimSpace(req.ProjectID)
+	req.ContainerKey = strings.TrimPrefix(strings.TrimSpace(req.ContainerKey), "/")
+
+	req.Type = strings.ToLower(strings.TrimSpace(req.Type))
+	switch req.Type {
+	case MonitorTypeHTTP:
+		u, err := url.Parse(req.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return req, fmt.Errorf("%w: http monitors need an http or https URL", ErrInvalidEndpointMonitor)
+		}
+		if req.ExpectedStatus != 0 && (req.ExpectedStatus < 100 || req.ExpectedStatus > 599) {
+			return req, fmt.Errorf("%w: expected status must be a valid HTTP status", ErrInvalidEndpointMonitor)
+		}
+	case MonitorTypeTCP:
+		host, port, err := net.SplitHostPort(req.Target)
+		if err != nil || host == "" {
+			return req, fmt.Errorf("%w: tcp monitors need a host:port target", ErrInvalidEndpointMonitor)
+		}
+		if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+			return req, fmt.Errorf("%w: port must be between 1 and 65535", ErrInvalidEndpointMonitor)
+		}
+		req.ExpectedStatus = 0
+	default:
+		return req, fmt.Errorf("%w: type must be http or tcp", ErrInvalidEndpointMonitor)
+	}
+
+	if req.IntervalSeconds == 0 {
+		req.IntervalSeconds = defaultMonitorInterval
+	}
+	if req.IntervalSeconds < minMonitorInterval {
+		return req, fmt.Errorf("%w: interval must be at least %d seconds", ErrInvalidEndpointMonitor, minMonitorInterval)
+	}
+	if req.TimeoutSeconds == 0 {
+		req.TimeoutSeconds = min(defaultMonitorTimeout, req.IntervalSeconds)
+	}
+	if req.TimeoutSeconds > req.IntervalSeconds {
+		return req, fmt.Errorf("%w: timeout must not exceed the interval", ErrInvalidEndpointMonitor)
+	}
+	if req.Retries == 0 {
+		req.Retries = defaultMonitorRetries
+	}
+	return req, nil
+}
+
+func toEndpointMonitorDTO(m models.EndpointMonitor) monitortypes.Monitor {
+	return monitortypes.Monitor{
+		ID:              m.ID,
+		Name:            m.Name,
+		Type:            m.Type,
+		Target:          m.Target,
+		ExpectedStatus:  m.ExpectedStatus,
+		IntervalSeconds: m.IntervalSeconds,
+		TimeoutSeconds:  m.TimeoutSeconds,
+		Retries:         m.Retries,
+		ProjectID:       m.ProjectID,
+		ContainerKey:    m.ContainerKey,
+		Enabled:         m.Enabled,
+		Notify:          m.Notify,
+		Status:          m.Status,
+		FailingStreak:   m.FailingStreak,
+		LastStatusCode:  m.LastStatusCode,
+		LastLatencyMs:   m.LastLatencyMs,
+		LastError:       m.LastError,
+		LastCheckedAt:   m.LastCheckedAt,
+		UpdatedBy:       m.UpdatedBy,
+		CreatedAt:       m.CreatedAt,
+		UpdatedAt:       m.UpdatedAt,
+	}
+}
@@ -0,0 +1,148 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	monitortypes "github.com/getarcaneapp/arcane/types/monitor"
+)
+
+func TestNormalizeMonitorRequest(t *testing.T) {
+	req, err := normalizeMonitorRequestInternal(monitortypes.Request{Name: " api ", Type: "HTTP", Target: "https://example.com/health", ContainerKey: "/web"})
+	require.NoError(t, err)
+	assert.Equal(t, "api", req.Name)
+	assert.Equal(t, "http", req.Type)
+	assert.Equal(t, "web", req.ContainerKey)
+	assert.Equal(t, 60, req.IntervalSeconds)
+	assert.Equal(t, 10, req.TimeoutSeconds)
+	assert.Equal(t, 2, req.Retries)
+
+	invalid := []monitortypes.Request{
+		{Name: "", Type: "http", Target: "https://example.com"},
+		{Name: "x", Type: "icmp", Target: "example.com"},
+		{Name: "x", Type: "http", Target: "ftp://example.com"},
+		{Name: "x", Type: "http", Target: "example.com"},
+		{Name: "x", Type: "http", Target: "https://example.com", ExpectedStatus: 42},
+		{Name: "x", Type: "tcp", Target: "example.com"},
+		{Name: "x", Type: "tcp", Target: "example.com:0"},
+		{Name: "x", Type: "tcp", Target: "example.com:80", IntervalSeconds: 5},
+		{Name: "x", Type: "tcp", Target: "example.com:80", IntervalSeconds: 20, TimeoutSeconds: 30},
+	}
+	for _, r := range invalid {
+		_, err := normalizeMonitorRequestInternal(r)
+		require.ErrorIs(t, err, ErrInvalidEndpointMonitor, "%+v", r)
+	}
+}
+
+func TestEndpointMonitorService_RunDueChecks(t *testing.T) {
+	ctx := context.Background()
+	appStatus := http.StatusOK
+	app := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(appStatus)
+	}))
+	defer app.Close()
+
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.EndpointMonitor{}, &models.EndpointMonitorResult{}, &models.Project{}, &models.Event{}))
+	db := &database.DB{DB: gdb}
+	svc := NewEndpointMonitorService(db, NewEventService(db), nil)
+	user := models.User{Username: "admin"}
+
+	_, err = svc.CreateMonitor(ctx, monitortypes.Request{Name: "api", Type: "http", Target: app.URL, ProjectID: "missing"}, user)
+	require.ErrorIs(t, err, ErrInvalidEndpointMonitor)
+
+	require.NoError(t, gdb.Create(&models.Project{BaseModel: models.BaseModel{ID: "p1"}, Name: "shop"}).Error)
+	api, err := svc.CreateMonitor(ctx, monitortypes.Request{Name: "api", Type: "http", Target: app.URL, ProjectID: "p1"}, user)
+	require.NoError(t, err)
+	assert.Equal(t, MonitorStatusPending, api.Status)
+	assert.True(t, api.Enabled)
+	assert.True(t, api.Notify)
+	port, err := svc.CreateMonitor(ctx, monitortypes.Request{Name: "port", Type: "tcp", Target: strings.TrimPrefix(app.URL, "http://"), ContainerKey: "web"}, user)
+	require.NoError(t, err)
+	disabled := false
+	_, err = svc.CreateMonitor(ctx, monitortypes.Request{Name: "off", Type: "tcp", Target: "127.0.0.1:1", Enabled: &disabled}, user)
+	require.NoError(t, err)
+
+	run := func() {
+		t.Helper()
+		// Make every monitor due again.
+		require.NoError(t, gdb.Model(&models.EndpointMonitor{}).Where("1 = 1").Update("last_checked_at", nil).Error)
+		assert.Equal(t, 2, svc.RunDueChecks(ctx))
+	}
+
+	run()
+	got, err := svc.GetMonitor(ctx, api.ID)
+	require.NoError(t, err)
+	assert.Equal(t, MonitorStatusUp, got.Status)
+	assert.Equal(t, http.StatusOK, got.LastStatusCode)
+	got, err = svc.GetMonitor(ctx, port.ID)
+	require.NoError(t, err)
+	assert.Equal(t, MonitorStatusUp, got.Status)
+
+	// Monitors that are not due are skipped.
+	assert.Equal(t, 0, svc.RunDueChecks(ctx))
+
+	appStatus = http.StatusBadGateway
+	run()
+	got, err = svc.GetMonitor(ctx, api.ID)
+	require.NoError(t, err)
+	assert.Equal(t, MonitorStatusUp, got.Status)
+	assert.Equal(t, 1, got.FailingStreak)
+	assert.Equal(t, "HTTP 502", got.LastError)
+
+	run()
+	got, err = svc.GetMonitor(ctx, api.ID)
+	require.NoError(t, err)
+	assert.Equal(t, MonitorStatusDown, got.Status)
+
+	appStatus = http.StatusOK
+	run()
+
+	var events []models.Event
+	require.NoError(t, gdb.Order("timestamp ASC").Find(&events).Error)
+	require.Len(t, events, 2)
+	assert.Equal(t, models.EventTypeMonitorDown, events[0].Type)
+	assert.Equal(t, "Monitor down: api", events[0].Title)
+	assert.Equal(t, "p1", events[0].Metadata["projectId"])
+	assert.Equal(t, models.EventTypeMonitorUp, events[1].Type)
+
+	results, err := svc.ListResults(ctx, api.ID, 0)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+	assert.True(t, results[0].Up)
+	assert.False(t, results[1].Up)
+	assert.Equal(t, http.StatusBadGateway, results[1].StatusCode)
+
+	monitors, err := svc.ListMonitors(ctx, "p1", "")
+	require.NoError(t, err)
+	require.Len(t, monitors, 1)
+	assert.Equal(t, "api", monitors[0].Name)
+	monitors, err = svc.ListMonitors(ctx, "", "/web")
+	require.NoError(t, err)
+	require.Len(t, monitors, 1)
+	assert.Equal(t, "port", monitors[0].Name)
+
+	updated, err := svc.UpdateMonitor(ctx, api.ID, monitortypes.Request{Name: "api", Type: "http", Target: app.URL, ExpectedStatus: http.StatusNoContent}, user)
+	require.NoError(t, err)
+	assert.Equal(t, MonitorStatusPending, updated.Status)
+	assert.Nil(t, updated.ProjectID)
+
+	require.NoError(t, svc.DeleteMonitor(ctx, api.ID))
+	require.ErrorIs(t, svc.DeleteMonitor(ctx, api.ID), ErrEndpointMonitorNotFound)
+	_, err = svc.ListResults(ctx, api.ID, 0)
+	require.ErrorIs(t, err, ErrEndpointMonitorNotFound)
+	var remaining int64
+	require.NoError(t, gdb.Model(&models.EndpointMonitorResult{}).Where("monitor_id = ?", api.ID).Count(&remaining).Error)
+	assert.Zero(t, remaining)
+}
@@ -0,0 +1,626 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/docker/api/types/container"
+	"github.com/robfig/cron/v3"
+	ref "go.podman.io/image/v5/docker/reference"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/config"
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/types/base"
+	"github.com/getarcaneapp/arcane/types/environment"
+	"github.com/getarcaneapp/arcane/types/system"
+)
+
+var (
+	ErrPolicyViolation        = errors.New("blocked by environment policy")
+	ErrInvalidPolicy          = errors.New("invalid environment policy")
+	ErrPolicyManagedByManager = errors.New("the policy of an agent is managed by its manager")
+)
+
+const (
+	localPolicyEnvironmentID = "0"
+	agentPolicyPath          = "/api/environments/agent-policy"
+	agentPolicyReportsPath   = "/api/environments/agent-policy-reports"
+	agentPolicyHTTPTimeout   = 15 * time.Second
+	// maxPendingPolicyReports bounds the reports an agent keeps while its
+	// manager is unreachable; the oldest are dropped first.
+	maxPendingPolicyReports = 1000
+	policyReportBatchSize   = 500
+)
+
+var policyCronParser = cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+
+// policySystemUser is recorded as the actor of policy prunes.
+var policySystemUser = models.User{
+	Username: "System",
+}
+
+// EnvironmentPolicyService manages the policies the manager issues to
+// environments. The policy of environment "0" is the one enforced locally; on
+// an agent it is a copy of what the manager issued, refreshed by Sync, so
+// enforcement and scheduled prunes keep working while the manager is
+// unreachable. Reports of what the agent blocked or pruned are queued and
+// delivered on the next successful sync.
+type EnvironmentPolicyService struct {
+	db            *database.DB
+	eventService  *EventService
+	systemService *SystemService
+	httpClient    *http.Client
+	cfg           *config.Config
+
+	mu            sync.Mutex
+	lastSyncError string
+}
+
+func NewEnvironmentPolicyService(db *database.DB, eventService *EventService, systemService *SystemService, httpClient *http.Client, cfg *config.Config) *EnvironmentPolicyService {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: agentPolicyHTTPTimeout}
+	}
+	return &EnvironmentPolicyService{
+		db:            db,
+		eventService:  eventService,
+		systemService: systemService,
+		httpClient:    httpClient,
+		cfg:           cfg,
+	}
+}
+
+// managedInternal reports whether this instance is an agent that takes its
+// policy from a manager.
+func (s *EnvironmentPolicyService) managedInternal() bool {
+	return s.cfg != nil && s.cfg.AgentMode && s.cfg.AgentToken != "" && s.cfg.GetManagerBaseURL() != ""
+}
+
+// GetPolicy returns the policy of an environment. An environment without a
+// policy gets the empty policy with revision 0.
+func (s *EnvironmentPolicyService) GetPolicy(ctx context.Context, environmentID string) (*environment.Policy, error) {
+	row, err := s.loadInternal(ctx, environmentID)
+	if err != nil {
+		return nil, err
+	}
+	policy := toPolicyInternal(row)
+	return &policy, nil
+}
+
+// UpdatePolicy replaces the policy of an environment and bumps its revision.
+// Agents pick up the new revision on their next sync.
+func (s *EnvironmentPolicyService) UpdatePolicy(ctx context.Context, environmentID string, req environment.PolicyUpdate, user models.User) (*environment.Policy, error) {
+	if environmentID == localPolicyEnvironmentID && s.managedInternal() {
+		return nil, ErrPolicyManagedByManager
+	}
+	if err := validatePolicyInternal(req); err != nil {
+		return nil, err
+	}
+
+	allowed := make(models.StringSlice, 0, len(req.AllowedImages))
+	for _, pattern := range req.AllowedImages {
+		if pattern = strings.TrimSpace(pattern); pattern != "" {
+			allowed = append(allowed, pattern)
+		}
+	}
+
+	var saved models.EnvironmentPolicy
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("environment_id = ?", environmentID).First(&saved).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+		saved.EnvironmentID = environmentID
+		saved.Revision++
+		saved.AllowedImages = allowed
+		saved.DenyPrivileged = req.DenyPrivileged
+		saved.DenyHostNetwork = req.DenyHostNetwork
+		saved.PruneSchedule = strings.TrimSpace(req.PruneSchedule)
+		saved.PruneMode = req.PruneMode
+		saved.UpdatedBy = user.Username
+		return tx.Save(&saved).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("save environment policy: %w", err)
+	}
+
+	if s.eventService != nil {
+		resourceType := "environment"
+		_, _ = s.eventService.CreateEvent(ctx, CreateEventRequest{
+			Type:          models.EventTypeEnvironmentUpdate,
+			Severity:      models.EventSeverityInfo,
+			Title:         "Environment policy updated",
+			Description:   fmt.Sprintf("The policy of environment '%s' was updated to revision %d", environmentID, saved.Revision),
+			ResourceType:  &resourceType,
+			ResourceID:    &environmentID,
+			ResourceName:  &environmentID,
+			UserID:        &user.ID,
+			Username:      &user.Username,
+			EnvironmentID: &environmentID,
+			Metadata:      models.JSON{"action": "policy_update", "revision": saved.Revision},
+		})
+	}
+
+	policy := toPolicyInternal(&saved)
+	return &policy, nil
+}
+
+// GetStatus returns the locally enforced policy and its sync state.
+func (s *EnvironmentPolicyService) GetStatus(ctx context.Context) (*environment.PolicyStatus, error) {
+	row, err := s.loadInternal(ctx, localPolicyEnvironmentID)
+	if err != nil {
+		return nil, err
+	}
+
+	status := environment.PolicyStatus{
+		Policy:      toPolicyInternal(row),
+		SyncedAt:    row.SyncedAt,
+		LastPruneAt: row.LastPruneAt,
+	}
+	s.mu.Lock()
+	status.LastSyncError = s.lastSyncError
+	s.mu.Unlock()
+
+	if err := s.db.WithContext(ctx).Model(&models.EnvironmentPolicyReport{}).Count(&status.PendingReports).Error; err != nil {
+		return nil, fmt.Errorf("count policy reports: %w", err)
+	}
+	return &status, nil
+}
+
+// CheckImage returns ErrPolicyViolation when the local policy does not allow
+// imageRef.
+func (s *EnvironmentPolicyService) CheckImage(ctx context.Context, imageRef string) error {
+	if s == nil {
+		return nil
+	}
+	row, err := s.loadInternal(ctx, localPolicyEnvironmentID)
+	if err != nil {
+		slog.WarnContext(ctx, "could not load environment policy; not enforcing it", "error", err)
+		return nil
+	}
+	return s.violationInternal(ctx, row, checkImageInternal(row, imageRef), models.JSON{"image": imageRef})
+}
+
+// CheckContainer returns ErrPolicyViolation when the local policy does not
+// allow a container with this image and host configuration.
+func (s *EnvironmentPolicyService) CheckContainer(ctx context.Context, name, imageRef string, hostConfig *container.HostConfig) error {
+	if s == nil {
+		return nil
+	}
+	row, err := s.loadInternal(ctx, localPolicyEnvironmentID)
+	if err != nil {
+		slog.WarnContext(ctx, "could not load environment policy; not enforcing it", "error", err)
+		return nil
+	}
+
+	reason := checkImageInternal(row, imageRef)
+	if reason == "" && hostConfig != nil {
+		reason = checkRuntimeInternal(row, hostConfig.Privileged, string(hostConfig.NetworkMode))
+	}
+	return s.violationInternal(ctx, row, reason, models.JSON{"container": name, "image": imageRef})
+}
+
+// CheckComposeProject returns ErrPolicyViolation when any service of project
+// is not allowed by the local policy.
+func (s *EnvironmentPolicyService) CheckComposeProject(ctx context.Context, project *composetypes.Project) error {
+	if s == nil || project == nil {
+		return nil
+	}
+	row, err := s.loadInternal(ctx, localPolicyEnvironmentID)
+	if err != nil {
+		slog.WarnContext(ctx, "could not load environment policy; not enforcing it", "error", err)
+		return nil
+	}
+
+	for name, svc := range project.Services {
+		reason := ""
+		if svc.Image != "" {
+			reason = checkImageInternal(row, svc.Image)
+		}
+		if reason == "" {
+			reason = checkRuntimeInternal(row, svc.Privileged, svc.NetworkMode)
+		}
+		if reason != "" {
+			reason = fmt.Sprintf("service %s: %s", name, reason)
+			return s.violationInternal(ctx, row, reason, models.JSON{"project": project.Name, "service": name, "image": svc.Image})
+		}
+	}
+	return nil
+}
+
+// Sync fetches the current policy from the manager and delivers queued
+// reports. It does nothing unless this instance is a paired agent. When the
+// manager is unreachable the cached policy stays in force.
+func (s *EnvironmentPolicyService) Sync(ctx context.Context) error {
+	if !s.managedInternal() {
+		return nil
+	}
+
+	err := s.syncInternal(ctx)
+	s.mu.Lock()
+	s.lastSyncError = ""
+	if err != nil {
+		s.lastSyncError = err.Error()
+	}
+	s.mu.Unlock()
+	return err
+}
+
+func (s *EnvironmentPolicyService) syncInternal(ctx context.Context) error {
+	var resp base.ApiResponse[environment.Policy]
+	if err := s.managerRequestInternal(ctx, http.MethodGet, agentPolicyPath, nil, &resp); err != nil {
+		return fmt.Errorf("fetch policy: %w", err)
+	}
+	if err := s.storeManagedPolicyInternal(ctx, resp.Data); err != nil {
+		return err
+	}
+	return s.deliverReportsInternal(ctx)
+}
+
+// storeManagedPolicyInternal replaces the local copy of the policy with the
+// one the manager issued, keeping when the last prune ran.
+func (s *EnvironmentPolicyService) storeManagedPolicyInternal(ctx context.Context, policy environment.Policy) error {
+	now := time.Now()
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var row models.EnvironmentPolicy
+		err := tx.Where("environment_id = ?", localPolicyEnvironmentID).First(&row).Error
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return fmt.Errorf("load cached policy: %w", err)
+		}
+		if row.ID != "" && row.Revision == policy.Revision {
+			return tx.Model(&row).UpdateColumn("synced_at", now).Error
+		}
+
+		slog.InfoContext(ctx, "environment policy changed", "from", row.Revision, "to", policy.Revision)
+		row.EnvironmentID = localPolicyEnvironmentID
+		row.Revision = policy.Revision
+		row.AllowedImages = policy.AllowedImages
+		row.DenyPrivileged = policy.DenyPrivileged
+		row.DenyHostNetwork = policy.DenyHostNetwork
+		row.PruneSchedule = policy.PruneSchedule
+		row.PruneMode = policy.PruneMode
+		row.SyncedAt = &now
+		if err := tx.Save(&row).Error; err != nil {
+			return fmt.Errorf("cache policy: %w", err)
+		}
+		return nil
+	})
+}
+
+// deliverReportsInternal sends queued reports to the manager oldest first and
+// drops the ones it accepted.
+func (s *EnvironmentPolicyService) deliverReportsInternal(ctx context.Context) error {
+	for {
+		var rows []models.EnvironmentPolicyReport
+		if err := s.db.WithContext(ctx).Order("occurred_at ASC").Limit(policyReportBatchSize).Find(&rows).Error; err != nil {
+			return fmt.Errorf("load policy reports: %w", err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+
+		batch := environment.PolicyReportBatch{Reports: make([]environment.PolicyReport, 0, len(rows))}
+		ids := make([]string, 0, len(rows))
+		for _, r := range rows {
+			batch.Reports = append(batch.Reports, environment.PolicyReport{
+				ID:         r.ID,
+				Kind:       r.Kind,
+				Revision:   r.Revision,
+				Message:    r.Message,
+				Details:    r.Details,
+				OccurredAt: r.OccurredAt,
+			})
+			ids = append(ids, r.ID)
+		}
+		if err := s.managerRequestInternal(ctx, http.MethodPost, agentPolicyReportsPath, batch, nil); err != nil {
+			return fmt.Errorf("deliver policy reports: %w", err)
+		}
+		if err := s.db.WithContext(ctx).Where("id IN ?", ids).Delete(&models.EnvironmentPolicyReport{}).Error; err != nil {
+			return fmt.Errorf("drop delivered policy reports: %w", err)
+		}
+		if len(rows) < policyReportBatchSize {
+			return nil
+		}
+	}
+}
+
+func (s *EnvironmentPolicyService) managerRequestInternal(ctx context.Context, method, apiPath string, body, out any) error {
+	var reader io.Reader
+	if body != nil {
+		raw, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("encode request: %w", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, agentPolicyHTTPTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, method, strings.TrimRight(s.cfg.GetManagerBaseURL(), "/")+apiPath, reader)
+	if err != nil {
+		return fmt.Errorf("create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("X-API-Key", s.cfg.AgentToken)
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("manager returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+	return nil
+}
+
+// RecordAgentReports records reports delivered by the agent of an
+// environment as events on the manager.
+func (s *EnvironmentPolicyService) RecordAgentReports(ctx context.Context, environmentID string, reports []environment.PolicyReport) error {
+	for _, r := range reports {
+		if err := s.logReportInternal(ctx, environmentID, r); err != nil {
+			return fmt.Errorf("record policy report %s: %w", r.ID, err)
+		}
+	}
+	return nil
+}
+
+// PruneDue reports whether the local policy's prune schedule is due.
+func (s *EnvironmentPolicyService) PruneDue(ctx context.Context) bool {
+	row, err := s.loadInternal(ctx, localPolicyEnvironmentID)
+	return err == nil && pruneDueInternal(row, time.Now())
+}
+
+// RunScheduledPrune prunes when the local policy's prune schedule is due.
+// It reads only the local copy of the policy, so it runs while the manager
+// is unreachable.
+func (s *EnvironmentPolicyService) RunScheduledPrune(ctx context.Context) {
+	row, err := s.loadInternal(ctx, localPolicyEnvironmentID)
+	if err != nil {
+		slog.WarnContext(ctx, "could not load environment policy", "error", err)
+		return
+	}
+	now := time.Now()
+	if !pruneDueInternal(row, now) || s.systemService == nil {
+		return
+	}
+
+	// Record the run before pruning so a failing prune is not retried every
+	// minute.
+	if err := s.db.WithContext(ctx).Model(row).UpdateColumn("last_prune_at", now).Error; err != nil {
+		slog.WarnContext(ctx, "could not record policy prune", "error", err)
+		return
+	}
+
+	req := system.PruneAllRequest{
+		Containers: true,
+		Images:     true,
+		Networks:   true,
+		Dangling:   row.PruneMode != "all",
+	}
+	result, err := s.systemService.PruneAll(ctx, req, models.PruneTriggerScheduled, policySystemUser)
+	details := models.JSON{"pruneMode": row.PruneMode, "schedule": row.PruneSchedule}
+	message := "Scheduled prune completed"
+	if err != nil {
+		message = "Scheduled prune failed: " + err.Error()
+	} else {
+		details["spaceReclaimed"] = result.SpaceReclaimed
+		details["containersPruned"] = len(result.ContainersPruned)
+		details["imagesDeleted"] = len(result.ImagesDeleted)
+		details["networksDeleted"] = len(result.NetworksDeleted)
+	}
+	s.reportInternal(ctx, row, environment.PolicyReportPrune, message, details)
+}
+
+// violationInternal reports and returns a policy violation; an empty reason
+// means the action is allowed.
+func (s *EnvironmentPolicyService) violationInternal(ctx context.Context, row *models.EnvironmentPolicy, reason string, details models.JSON) error {
+	if reason == "" {
+		return nil
+	}
+	s.reportInternal(ctx, row, environment.PolicyReportViolation, reason, details)
+	return fmt.Errorf("%w: %s", ErrPolicyViolation, reason)
+}
+
+// reportInternal queues a report for the manager on an agent, and records it
+// as an event everywhere else.
+func (s *EnvironmentPolicyService) reportInternal(ctx context.Context, row *models.EnvironmentPolicy, kind, message string, details models.JSON) {
+	if !s.managedInternal() {
+		report := environment.PolicyReport{Kind: kind, Revision: row.Revision, Message: message, Details: details, OccurredAt: time.Now()}
+		if err := s.logReportInternal(ctx, localPolicyEnvironmentID, report); err != nil {
+			slog.WarnContext(ctx, "could not log policy report", "kind", kind, "error", err)
+		}
+		return
+	}
+
+	record := models.EnvironmentPolicyReport{Kind: kind, Revision: row.Revision, Message: message, Details: details, OccurredAt: time.Now()}
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&record).Error; err != nil {
+			return err
+		}
+		var count int64
+		if err := tx.Model(&models.EnvironmentPolicyReport{}).Count(&count).Error; err != nil {
+			return err
+		}
+		if over := count - maxPendingPolicyReports; over > 0 {
+			oldest := tx.Model(&models.EnvironmentPolicyReport{}).Select("id").Order("occurred_at ASC").Limit(int(over))
+			return tx.Where("id IN (?)", oldest).Delete(&models.EnvironmentPolicyReport{}).Error
+		}
+		return nil
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "could not queue policy report", "kind", kind, "error", err)
+	}
+}
+
+func (s *EnvironmentPolicyService) logReportInternal(ctx context.Context, environmentID string, r environment.PolicyReport) error {
+	if s.eventService == nil {
+		return nil
+	}
+	eventType := models.EventTypeEnvironmentPolicyViolation
+	severity := models.EventSeverityWarning
+	title := "Policy violation: " + r.Message
+	if r.Kind == environment.PolicyReportPrune {
+		eventType = models.EventTypeEnvironmentPolicyPrune
+		severity = models.EventSeverityInfo
+		title = r.Message
+	}
+
+	metadata := models.JSON{"reportId": r.ID, "revision": r.Revision, "occurredAt": r.OccurredAt}
+	for k, v := range r.Details {
+		metadata[k] = v
+	}
+	resourceType := "environment"
+	systemUser := policySystemUser.Username
+	_, err := s.eventService.CreateEvent(ctx, CreateEventRequest{
+		Type:          eventType,
+		Severity:      severity,
+		Title:         title,
+		Description:   r.Message,
+		ResourceType:  &resourceType,
+		ResourceID:    &environmentID,
+		ResourceName:  &environmentID,
+		Username:      &systemUser,
+		EnvironmentID: &environmentID,
+		Metadata:      metadata,
+	})
+	return err
+}
+
+func (s *EnvironmentPolicyService) loadInternal(ctx context.Context, environmentID string) (*models.EnvironmentPolicy, error) {
+	var row models.EnvironmentPolicy
+	err := s.db.WithContext(ctx).Where("environment_id = ?", environmentID).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &models.EnvironmentPolicy{EnvironmentID: environmentID}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load environment policy: %w", err)
+	}
+	return &row, nil
+}
+
+func toPolicyInternal(row *models.EnvironmentPolicy) environment.Policy {
+	policy := environment.Policy{
+		Revision:        row.Revision,
+		AllowedImages:   row.AllowedImages,
+		DenyPrivileged:  row.DenyPrivileged,
+		DenyHostNetwork: row.DenyHostNetwork,
+		PruneSchedule:   row.PruneSchedule,
+		PruneMode:       row.PruneMode,
+		UpdatedAt:       row.UpdatedAt,
+	}
+	if policy.UpdatedAt == nil && !row.CreatedAt.IsZero() {
+		policy.UpdatedAt = &row.CreatedAt
+	}
+	return policy
+}
+
+func validatePolicyInternal(req environment.PolicyUpdate) error {
+	if schedule := strings.TrimSpace(req.PruneSchedule); schedule != "" {
+		if _, err := policyCronParser.Parse(schedule); err != nil {
+			return fmt.Errorf("%w: prune schedule: %w", ErrInvalidPolicy, err)
+		}
+	}
+	switch req.PruneMode {
+	case "", "dangling", "all":
+	default:
+		return fmt.Errorf("%w: prune mode must be dangling or all", ErrInvalidPolicy)
+	}
+	for _, pattern := range req.AllowedImages {
+		if _, err := path.Match(strings.TrimSuffix(strings.TrimSpace(pattern), "/**"), ""); err != nil {
+			return fmt.Errorf("%w: image pattern %q: %w", ErrInvalidPolicy, pattern, err)
+		}
+	}
+	return nil
+}
+
+// checkImageInternal returns why the policy does not allow imageRef, or "".
+func checkImageInternal(row *models.EnvironmentPolicy, imageRef string) string {
+	if len(row.AllowedImages) == 0 {
+		return ""
+	}
+	named, err := ref.ParseNormalizedNamed(imageRef)
+	if err != nil {
+		return fmt.Sprintf("image %s is not a valid reference", imageRef)
+	}
+	names := []string{named.Name(), ref.FamiliarName(named)}
+	for _, pattern := range row.AllowedImages {
+		for _, name := range names {
+			if matchImagePatternInternal(pattern, name) {
+				return ""
+			}
+		}
+	}
+	return fmt.Sprintf("image %s is not in the allowed images", imageRef)
+}
+
+// matchImagePatternInternal matches a repository name against a pattern in
+// which "*" does not cross a "/" and a trailing "/**" matches every
+// repository below the prefix.
+func matchImagePatternInternal(pattern, name string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "/**"); ok {
+		if !strings.Contains(name, "/") {
+			return false
+		}
+		for i := strings.LastIndex(name, "/"); i > 0; i = strings.LastIndex(name[:i], "/") {
+			if ok, _ := path.Match(prefix, name[:i]); ok {
+				return true
+			}
+		}
+		return false
+	}
+	ok, _ := path.Match(pattern, name)
+	return ok
+}
+
+// checkRuntimeInternal returns why the policy does not allow a container with
+// these runtime options, or "".
+func checkRuntimeInternal(row *models.EnvironmentPolicy, privileged bool, networkMode string) string {
+	if row.DenyPrivileged && privileged {
+		return "privileged containers are not allowed"
+	}
+	if row.DenyHostNetwork && networkMode == "host" {
+		return "the host network is not allowed"
+	}
+	return ""
+}
+
+// pruneDueInternal reports whether the prune schedule of the policy has come
+// round since it last ran, or since the policy was stored if it never ran.
+func pruneDueInternal(row *models.EnvironmentPolicy, now time.Time) bool {
+	if row.PruneSchedule == "" || row.CreatedAt.IsZero() {
+		return false
+	}
+	schedule, err := policyCronParser.Parse(row.PruneSchedule)
+	if err != nil {
+		return false
+	}
+	last := row.CreatedAt
+	if row.LastPruneAt != nil {
+		last = *row.LastPruneAt
+	}
+	return !schedule.Next(last).After(now)
+}
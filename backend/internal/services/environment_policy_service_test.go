@@ -0,0 +1,150 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/config"
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/types/base"
+	"github.com/getarcaneapp/arcane/types/environment"
+)
+
+func newPolicyTestDBInternal(t *testing.T) *database.DB {
+	t.Helper()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Event{}, &models.EnvironmentPolicy{}, &models.EnvironmentPolicyReport{}))
+	return &database.DB{DB: gdb}
+}
+
+func TestMatchImagePattern(t *testing.T) {
+	row := &models.EnvironmentPolicy{AllowedImages: models.StringSlice{"nginx", "ghcr.io/acme/*", "registry.local/**"}}
+
+	assert.Empty(t, checkImageInternal(row, "nginx:1.27"))
+	assert.Empty(t, checkImageInternal(row, "docker.io/library/nginx@sha256:"+"0123456789abcdef0123456789abcdef0123456789abcdef0123456789abcdef"))
+	assert.Empty(t, checkImageInternal(row, "ghcr.io/acme/api:v2"))
+	assert.Empty(t, checkImageInternal(row, "registry.local/team/app/worker"))
+	assert.NotEmpty(t, checkImageInternal(row, "ghcr.io/acme/team/api"))
+	assert.NotEmpty(t, checkImageInternal(row, "redis"))
+	assert.NotEmpty(t, checkImageInternal(row, "registry.local"))
+
+	assert.Empty(t, checkImageInternal(&models.EnvironmentPolicy{}, "anything:latest"))
+}
+
+func TestPruneDue(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	row := &models.EnvironmentPolicy{PruneSchedule: "0 0 3 * * *", BaseModel: models.BaseModel{CreatedAt: created}}
+
+	assert.False(t, pruneDueInternal(row, created.Add(2*time.Hour)))
+	assert.True(t, pruneDueInternal(row, created.Add(3*time.Hour)))
+
+	last := created.Add(3 * time.Hour)
+	row.LastPruneAt = &last
+	assert.False(t, pruneDueInternal(row, created.Add(20*time.Hour)))
+	assert.True(t, pruneDueInternal(row, created.Add(27*time.Hour)))
+
+	row.PruneSchedule = ""
+	assert.False(t, pruneDueInternal(row, created.Add(100*time.Hour)))
+}
+
+func TestEnvironmentPolicyService_UpdatePolicy(t *testing.T) {
+	ctx := context.Background()
+	svc := NewEnvironmentPolicyService(newPolicyTestDBInternal(t), nil, nil, nil, &config.Config{})
+	admin := models.User{Username: "admin"}
+
+	_, err := svc.UpdatePolicy(ctx, "env-1", environment.PolicyUpdate{PruneSchedule: "not cron"}, admin)
+	require.ErrorIs(t, err, ErrInvalidPolicy)
+
+	policy, err := svc.UpdatePolicy(ctx, "env-1", environment.PolicyUpdate{AllowedImages: []string{" nginx ", ""}, DenyPrivileged: true}, admin)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), policy.Revision)
+	assert.Equal(t, []string{"nginx"}, policy.AllowedImages)
+
+	policy, err = svc.UpdatePolicy(ctx, "env-1", environment.PolicyUpdate{DenyHostNetwork: true}, admin)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), policy.Revision)
+	assert.False(t, policy.DenyPrivileged)
+
+	agent := NewEnvironmentPolicyService(newPolicyTestDBInternal(t), nil, nil, nil, &config.Config{AgentMode: true, AgentToken: "t", ManagerApiUrl: "http://manager"})
+	_, err = agent.UpdatePolicy(ctx, "0", environment.PolicyUpdate{}, admin)
+	require.ErrorIs(t, err, ErrPolicyManagedByManager)
+}
+
+func TestEnvironmentPolicyService_OfflineEnforcement(t *testing.T) {
+	ctx := context.Background()
+	var online atomic.Bool
+	online.Store(true)
+	var delivered []environment.PolicyReport
+
+	manager := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !online.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		assert.Equal(t, "agent-key", r.Header.Get("X-API-Key"))
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case agentPolicyPath:
+			_ = json.NewEncoder(w).Encode(base.ApiResponse[environment.Policy]{Success: true, Data: environment.Policy{
+				Revision:       3,
+				AllowedImages:  []string{"ghcr.io/acme/*"},
+				DenyPrivileged: true,
+			}})
+		case agentPolicyReportsPath:
+			var batch environment.PolicyReportBatch
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&batch))
+			delivered = append(delivered, batch.Reports...)
+			_, _ = w.Write([]byte(`{"success":true}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(manager.Close)
+
+	cfg := &config.Config{AgentMode: true, AgentToken: "agent-key", ManagerApiUrl: manager.URL + "/api"}
+	svc := NewEnvironmentPolicyService(newPolicyTestDBInternal(t), nil, nil, manager.Client(), cfg)
+
+	require.NoError(t, svc.Sync(ctx))
+	status, err := svc.GetStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), status.Policy.Revision)
+	assert.NotNil(t, status.SyncedAt)
+
+	online.Store(false)
+	require.Error(t, svc.Sync(ctx))
+
+	require.NoError(t, svc.CheckImage(ctx, "ghcr.io/acme/api:1"))
+	require.ErrorIs(t, svc.CheckImage(ctx, "redis:7"), ErrPolicyViolation)
+	err = svc.CheckContainer(ctx, "api", "ghcr.io/acme/api:1", &container.HostConfig{Privileged: true})
+	require.ErrorIs(t, err, ErrPolicyViolation)
+
+	status, err = svc.GetStatus(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), status.Policy.Revision)
+	assert.Equal(t, int64(2), status.PendingReports)
+	assert.NotEmpty(t, status.LastSyncError)
+
+	online.Store(true)
+	require.NoError(t, svc.Sync(ctx))
+	require.Len(t, delivered, 2)
+	assert.Equal(t, environment.PolicyReportViolation, delivered[0].Kind)
+	assert.Equal(t, int64(3), delivered[0].Revision)
+
+	status, err = svc.GetStatus(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, status.PendingReports)
+	assert.Empty(t, status.LastSyncError)
+}
@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/csv"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -21,6 +22,7 @@ import (
 	"github.com/getarcaneapp/arcane/types/containerregistry"
 	"github.com/getarcaneapp/arcane/types/environment"
 	"github.com/getarcaneapp/arcane/types/gitops"
+	"github.com/goccy/go-yaml"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -129,8 +131,8 @@ func (s *EnvironmentService) ListEnvironmentsPaginated(ctx context.Context, para
 	if term := strings.TrimSpace(params.Search); term != "" {
 		searchPattern := "%" + term + "%"
 		q = q.Where(
-			"name LIKE ? OR api_url LIKE ?",
-			searchPattern, searchPattern,
+			"name LIKE ? OR api_url LIKE ? OR tags LIKE ?",
+			searchPattern, searchPattern, searchPattern,
 		)
 	}
 
@@ -345,6 +347,28 @@ func (s *EnvironmentService) UpdateEnvironmentHeartbeat(ctx context.Context, id
 
 	return nil
 }
+
+// RecordAgentHeartbeat stores the metrics an agent pushed with its heartbeat
+// and marks the environment online. Unlike UpdateEnvironmentHeartbeat it is
+// not throttled, so the metrics shown in the environment list stay current.
+func (s *EnvironmentService) RecordAgentHeartbeat(ctx context.Context, id string, metrics environment.HeartbeatMetrics) error {
+	now := time.Now()
+	metrics.ReportedAt = &now
+	stored := models.EnvironmentMetrics(metrics)
+
+	result := s.db.WithContext(ctx).Model(&models.Environment{}).Where("id = ?", id).Updates(map[string]any{
+		"last_seen":         &now,
+		"status":            string(models.EnvironmentStatusOnline),
+		"heartbeat_metrics": stored,
+	})
+	if result.Error != nil {
+		return fmt.Errorf("failed to record agent heartbeat: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("environment not found")
+	}
+	return nil
+}
 func (s *EnvironmentService) createEnvironmentEvent(ctx context.Context, envID, envName string, eventType models.EventType, title, description string, severity models.EventSeverity, userID, username *string) {
 	resourceType := "environment"
 	resourceID := envID
@@ -819,3 +843,156 @@ func (s *EnvironmentService) ProxyRequest(ctx context.Context, envID string, met
 
 	return resp.Body, resp.StatusCode, nil
 }
+
+// MaxEnvironmentManifestEntries caps how many environments one import may
+// create.
+const MaxEnvironmentManifestEntries = 500
+
+// ErrInvalidEnvironmentManifest is returned when an import manifest cannot be
+// parsed.
+var ErrInvalidEnvironmentManifest = errors.New("invalid environment manifest")
+
+// ParseEnvironmentManifest parses a YAML or CSV import manifest. An empty
+// format is detected from the content.
+func ParseEnvironmentManifest(format, manifest string) ([]environment.ManifestEntry, error) {
+	if format == "" {
+		format = detectManifestFormatInternal(manifest)
+	}
+
+	var entries []environment.ManifestEntry
+	var err error
+	switch format {
+	case environment.ManifestFormatYAML:
+		entries, err = parseYAMLManifestInternal(manifest)
+	case environment.ManifestFormatCSV:
+		entries, err = parseCSVManifestInternal(manifest)
+	default:
+		return nil, fmt.Errorf("%w: unsupported format %q", ErrInvalidEnvironmentManifest, format)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("%w: no environments found", ErrInvalidEnvironmentManifest)
+	}
+	if len(entries) > MaxEnvironmentManifestEntries {
+		return nil, fmt.Errorf("%w: %d environments exceeds the limit of %d", ErrInvalidEnvironmentManifest, len(entries), MaxEnvironmentManifestEntries)
+	}
+
+	for i := range entries {
+		entries[i].Name = strings.TrimSpace(entries[i].Name)
+		entries[i].ApiUrl = strings.TrimSpace(entries[i].ApiUrl)
+		entries[i].AccessToken = strings.TrimSpace(entries[i].AccessToken)
+		entries[i].BootstrapToken = strings.TrimSpace(entries[i].BootstrapToken)
+		entries[i].Tags = NormalizeEnvironmentTags(entries[i].Tags)
+	}
+	return entries, nil
+}
+
+func detectManifestFormatInternal(manifest string) string {
+	trimmed := strings.TrimSpace(manifest)
+	firstLine, _, _ := strings.Cut(trimmed, "\n")
+	if strings.HasPrefix(trimmed, "-") || strings.Contains(firstLine, ":") || !strings.Contains(firstLine, ",") {
+		return environment.ManifestFormatYAML
+	}
+	return environment.ManifestFormatCSV
+}
+
+func parseYAMLManifestInternal(manifest string) ([]environment.ManifestEntry, error) {
+	var list []environment.ManifestEntry
+	if err := yaml.Unmarshal([]byte(manifest), &list); err == nil {
+		return list, nil
+	}
+
+	var doc struct {
+		Environments []environment.ManifestEntry `yaml:"environments"`
+	}
+	if err := yaml.Unmarshal([]byte(manifest), &doc); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEnvironmentManifest, err)
+	}
+	return doc.Environments, nil
+}
+
+func parseCSVManifestInternal(manifest string) ([]environment.ManifestEntry, error) {
+	r := csv.NewReader(strings.NewReader(manifest))
+	r.TrimLeadingSpace = true
+	r.FieldsPerRecord = -1
+	records, err := r.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidEnvironmentManifest, err)
+	}
+	if len(records) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		key := strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+		switch key {
+		case "name", "apiurl", "accesstoken", "bootstraptoken", "tags":
+			columns[key] = i
+		default:
+			return nil, fmt.Errorf("%w: unknown column %q", ErrInvalidEnvironmentManifest, name)
+		}
+	}
+	if _, ok := columns["apiurl"]; !ok {
+		return nil, fmt.Errorf("%w: missing apiUrl column", ErrInvalidEnvironmentManifest)
+	}
+
+	field := func(record []string, key string) string {
+		i, ok := columns[key]
+		if !ok || i >= len(record) {
+			return ""
+		}
+		return record[i]
+	}
+
+	entries := make([]environment.ManifestEntry, 0, len(records)-1)
+	for _, record := range records[1:] {
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+		entries = append(entries, environment.ManifestEntry{
+			Name:           field(record, "name"),
+			ApiUrl:         field(record, "apiurl"),
+			AccessToken:    field(record, "accesstoken"),
+			BootstrapToken: field(record, "bootstraptoken"),
+			Tags:           strings.Split(field(record, "tags"), ";"),
+		})
+	}
+	return entries, nil
+}
+
+// NormalizeEnvironmentTags trims tags and drops empty and duplicate ones,
+// keeping their order.
+func NormalizeEnvironmentTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	seen := make(map[string]struct{}, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if _, ok := seen[tag]; ok {
+			continue
+		}
+		seen[tag] = struct{}{}
+		out = append(out, tag)
+	}
+	return out
+}
+
+// GetEnvironmentByApiUrl returns the environment using apiUrl, or nil if
+// there is none.
+func (s *EnvironmentService) GetEnvironmentByApiUrl(ctx context.Context, apiUrl string) (*models.Environment, error) {
+	var env models.Environment
+	err := s.db.WithContext(ctx).Where("api_url = ?", strings.TrimRight(apiUrl, "/")).Or("api_url = ?", strings.TrimRight(apiUrl, "/")+"/").First(&env).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up environment: %w", err)
+	}
+	return &env, nil
+}
@@ -0,0 +1,94 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/mapper"
+	"github.com/getarcaneapp/arcane/types/environment"
+)
+
+func TestParseEnvironmentManifest(t *testing.T) {
+	yamlList := `
+- name: edge-01
+  apiUrl: http://10.0.0.1:3553
+  bootstrapToken: boot
+  tags: [edge, " lab ", edge]
+- apiUrl: http://10.0.0.2:3553
+`
+	entries, err := ParseEnvironmentManifest("", yamlList)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, "edge-01", entries[0].Name)
+	assert.Equal(t, "boot", entries[0].BootstrapToken)
+	assert.Equal(t, []string{"edge", "lab"}, entries[0].Tags)
+	assert.Equal(t, "http://10.0.0.2:3553", entries[1].ApiUrl)
+
+	yamlDoc := "environments:\n  - name: prod\n    apiUrl: https://prod:3553\n    accessToken: tok\n"
+	entries, err = ParseEnvironmentManifest(environment.ManifestFormatYAML, yamlDoc)
+	require.NoError(t, err)
+	require.Len(t, entries, 1)
+	assert.Equal(t, "tok", entries[0].AccessToken)
+
+	csvDoc := "name,api_url,bootstrapToken,tags\nweb,http://web:3553,b1,prod;eu\n\ndb, http://db:3553,,\n"
+	entries, err = ParseEnvironmentManifest("", csvDoc)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, []string{"prod", "eu"}, entries[0].Tags)
+	assert.Equal(t, "http://db:3553", entries[1].ApiUrl)
+	assert.Empty(t, entries[1].Tags)
+
+	_, err = ParseEnvironmentManifest(environment.ManifestFormatCSV, "name,url\nx,y\n")
+	require.ErrorIs(t, err, ErrInvalidEnvironmentManifest)
+	_, err = ParseEnvironmentManifest(environment.ManifestFormatYAML, "environments: []\n")
+	require.ErrorIs(t, err, ErrInvalidEnvironmentManifest)
+	_, err = ParseEnvironmentManifest("toml", "x")
+	require.ErrorIs(t, err, ErrInvalidEnvironmentManifest)
+}
+
+func TestEnvironmentService_RecordAgentHeartbeat(t *testing.T) {
+	ctx := context.Background()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Environment{}))
+	require.NoError(t, gdb.Create(&models.Environment{BaseModel: models.BaseModel{ID: "1"}, Name: "agent", Status: string(models.EnvironmentStatusOffline)}).Error)
+	svc := NewEnvironmentService(&database.DB{DB: gdb}, nil, nil, nil, nil)
+
+	env, err := svc.GetEnvironmentByID(ctx, "1")
+	require.NoError(t, err)
+	assert.Nil(t, env.Metrics, "no heartbeat recorded yet")
+
+	free := uint64(1 << 30)
+	require.NoError(t, svc.RecordAgentHeartbeat(ctx, "1", environment.HeartbeatMetrics{
+		AgentVersion:      "1.2.3",
+		ContainersRunning: 3,
+		ContainersTotal:   5,
+		PendingUpdates:    2,
+		DiskFreeBytes:     &free,
+	}))
+
+	env, err = svc.GetEnvironmentByID(ctx, "1")
+	require.NoError(t, err)
+	assert.Equal(t, string(models.EnvironmentStatusOnline), env.Status)
+	require.NotNil(t, env.LastSeen)
+	require.NotNil(t, env.Metrics)
+	assert.Equal(t, "1.2.3", env.Metrics.AgentVersion)
+	assert.Equal(t, 3, env.Metrics.ContainersRunning)
+	require.NotNil(t, env.Metrics.ReportedAt)
+
+	out, err := mapper.MapOne[*models.Environment, environment.Environment](env)
+	require.NoError(t, err)
+	require.NotNil(t, out.Metrics)
+	assert.Equal(t, 5, out.Metrics.ContainersTotal)
+	assert.Equal(t, 2, out.Metrics.PendingUpdates)
+	assert.Equal(t, free, *out.Metrics.DiskFreeBytes)
+
+	require.Error(t, svc.RecordAgentHeartbeat(ctx, "missing", environment.HeartbeatMetrics{}))
+}
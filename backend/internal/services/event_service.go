@@ -12,19 +12,52 @@ import (
 	"github.com/getarcaneapp/arcane/backend/internal/utils/mapper"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/pagination"
 	"github.com/getarcaneapp/arcane/types/event"
+	"github.com/google/uuid"
 	"golang.org/x/text/cases"
 	"golang.org/x/text/language"
 	"gorm.io/gorm"
 )
 
 type EventService struct {
-	db *database.DB
+	db     *database.DB
+	writer *eventWriterInternal
 }
 
 func NewEventService(db *database.DB) *EventService {
 	return &EventService{db: db}
 }
 
+// StartAsyncWriter makes CreateEvent queue events and write them in batches
+// in the background instead of inserting them while the caller waits. At most
+// queueSize events are held; further events are dropped until the queue has
+// room again. It must be called once, before the service is used
+// concurrently.
+func (s *EventService) StartAsyncWriter(ctx context.Context, queueSize int) {
+	if queueSize <= 0 || s.writer != nil {
+		return
+	}
+	s.writer = newEventWriterInternal(s.db, queueSize)
+	go s.writer.run(ctx)
+}
+
+// CloseAsyncWriter writes the events still queued and stops the async
+// writer. Later events are written synchronously.
+func (s *EventService) CloseAsyncWriter(ctx context.Context) error {
+	if s.writer == nil {
+		return nil
+	}
+	return s.writer.close(ctx)
+}
+
+// AsyncWriterStats reports the queue depth and how many events the async
+// writer has written and dropped.
+func (s *EventService) AsyncWriterStats() EventWriterStats {
+	if s.writer == nil {
+		return EventWriterStats{}
+	}
+	return s.writer.stats()
+}
+
 type CreateEventRequest struct {
 	Type          models.EventType     `json:"type"`
 	Severity      models.EventSeverity `json:"severity,omitempty"`
@@ -45,6 +78,7 @@ func (s *EventService) CreateEvent(ctx context.Context, req CreateEventRequest)
 		severity = models.EventSeverityInfo
 	}
 
+	now := time.Now()
 	event := &models.Event{
 		Type:          req.Type,
 		Severity:      severity,
@@ -57,12 +91,17 @@ func (s *EventService) CreateEvent(ctx context.Context, req CreateEventRequest)
 		Username:      req.Username,
 		EnvironmentID: req.EnvironmentID,
 		Metadata:      req.Metadata,
-		Timestamp:     time.Now(),
+		Timestamp:     now,
 		BaseModel: models.BaseModel{
-			CreatedAt: time.Now(),
+			ID:        uuid.NewString(),
+			CreatedAt: now,
 		},
 	}
 
+	if s.writer != nil && s.writer.enqueue(event) {
+		return event, nil
+	}
+
 	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
 		if err := tx.Create(event).Error; err != nil {
 			return fmt.Errorf("failed to create event: %w", err)
@@ -372,20 +411,46 @@ var eventDefinitions = map[models.EventType]struct {
 	DescriptionFormat string
 	Severity          models.EventSeverity
 }{
-	models.EventTypeContainerStart:   {"Container started: %s", "Container '%s' has been started", models.EventSeveritySuccess},
-	models.EventTypeContainerStop:    {"Container stopped: %s", "Container '%s' has been stopped", models.EventSeverityInfo},
-	models.EventTypeContainerRestart: {"Container restarted: %s", "Container '%s' has been restarted", models.EventSeverityInfo},
-	models.EventTypeContainerDelete:  {"Container deleted: %s", "Container '%s' has been deleted", models.EventSeverityWarning},
-	models.EventTypeContainerCreate:  {"Container created: %s", "Container '%s' has been created", models.EventSeveritySuccess},
-	models.EventTypeContainerScan:    {"Container scanned: %s", "Security scan completed for container '%s'", models.EventSeverityInfo},
-	models.EventTypeContainerUpdate:  {"Container updated: %s", "Container '%s' has been updated", models.EventSeverityInfo},
-	models.EventTypeContainerError:   {"Container error: %s", "An error occurred with container '%s'", models.EventSeverityError},
-
-	models.EventTypeImagePull:   {"Image pulled: %s", "Image '%s' has been pulled", models.EventSeveritySuccess},
-	models.EventTypeImageLoad:   {"Image loaded: %s", "Image '%s' has been loaded from archive", models.EventSeveritySuccess},
-	models.EventTypeImageDelete: {"Image deleted: %s", "Image '%s' has been deleted", models.EventSeverityWarning},
-	models.EventTypeImageScan:   {"Image scanned: %s", "Security scan completed for image '%s'", models.EventSeverityInfo},
-	models.EventTypeImageError:  {"Image error: %s", "An error occurred with image '%s'", models.EventSeverityError},
+	models.EventTypeContainerStart:     {"Container started: %s", "Container '%s' has been started", models.EventSeveritySuccess},
+	models.EventTypeContainerStop:      {"Container stopped: %s", "Container '%s' has been stopped", models.EventSeverityInfo},
+	models.EventTypeContainerRestart:   {"Container restarted: %s", "Container '%s' has been restarted", models.EventSeverityInfo},
+	models.EventTypeContainerDelete:    {"Container deleted: %s", "Container '%s' has been deleted", models.EventSeverityWarning},
+	models.EventTypeContainerRestore:   {"Container restored: %s", "Container '%s' has been restored from the trash", models.EventSeverityInfo},
+	models.EventTypeContainerCreate:    {"Container created: %s", "Container '%s' has been created", models.EventSeveritySuccess},
+	models.EventTypeContainerScan:      {"Container scanned: %s", "Security scan completed for container '%s'", models.EventSeverityInfo},
+	models.EventTypeContainerUpdate:    {"Container updated: %s", "Container '%s' has been updated", models.EventSeverityInfo},
+	models.EventTypeContainerError:     {"Container error: %s", "An error occurred with container '%s'", models.EventSeverityError},
+	models.EventTypeContainerRename:    {"Container renamed: %s", "Container '%s' has been renamed", models.EventSeverityInfo},
+	models.EventTypeContainerRecreate:  {"Container recreated: %s", "Container '%s' has been recreated with a new configuration", models.EventSeverityInfo},
+	models.EventTypeContainerHealthy:   {"Container healthy: %s", "Arcane healthcheck for container '%s' is passing", models.EventSeveritySuccess},
+	models.EventTypeContainerUnhealthy: {"Container unhealthy: %s", "Arcane healthcheck for container '%s' is failing", models.EventSeverityWarning},
+	models.EventTypeContainerCommit:    {"Container committed: %s", "Container '%s' has been saved as a new image", models.EventSeveritySuccess},
+	models.EventTypeContainerExport:    {"Container exported: %s", "The filesystem of container '%s' has been exported", models.EventSeverityInfo},
+	models.EventTypeContainerFlapping:  {"Container flapping: %s", "The health of container '%s' keeps changing", models.EventSeverityWarning},
+	models.EventTypeContainerOOMKilled: {"Container out of memory: %s", "Container '%s' was killed because it ran out of memory", models.EventSeverityWarning},
+	models.EventTypeContainerCrashLoop: {"Container crash loop: %s", "Container '%s' keeps exiting with an error", models.EventSeverityWarning},
+	models.EventTypeContainerExecStart: {"Terminal opened: %s", "A terminal session was opened in container '%s'", models.EventSeverityInfo},
+	models.EventTypeContainerExecEnd:   {"Terminal closed: %s", "A terminal session in container '%s' has ended", models.EventSeverityInfo},
+	models.EventTypeContainerTagDrift:  {"Tag drift: %s", "The image tag of container '%s' now points to a different digest than the one it runs", models.EventSeverityWarning},
+	models.EventTypeContainerPin:       {"Container pinned: %s", "Container '%s' has been pinned to its current image digest", models.EventSeverityInfo},
+	models.EventTypeContainerUnpin:     {"Container unpinned: %s", "Container '%s' has been unpinned", models.EventSeverityInfo},
+	models.EventTypeContainerRollback:  {"Container rolled back: %s", "Container '%s' has been recreated on the image it ran before its last update", models.EventSeverityWarning},
+
+	models.EventTypeContainerCheckpoint:        {"Container checkpointed: %s", "A checkpoint of container '%s' has been created", models.EventSeveritySuccess},
+	models.EventTypeContainerCheckpointRestore: {"Container restored from checkpoint: %s", "Container '%s' has been started from a checkpoint", models.EventSeverityInfo},
+
+	models.EventTypeImagePull:             {"Image pulled: %s", "Image '%s' has been pulled", models.EventSeveritySuccess},
+	models.EventTypeImageLoad:             {"Image loaded: %s", "Image '%s' has been loaded from archive", models.EventSeveritySuccess},
+	models.EventTypeImageSave:             {"Image exported: %s", "Image '%s' has been exported to an archive", models.EventSeverityInfo},
+	models.EventTypeImageBuild:            {"Image built: %s", "Image '%s' has been built", models.EventSeveritySuccess},
+	models.EventTypeImageDelete:           {"Image deleted: %s", "Image '%s' has been deleted", models.EventSeverityWarning},
+	models.EventTypeImageScan:             {"Image scanned: %s", "Security scan completed for image '%s'", models.EventSeverityInfo},
+	models.EventTypeImageError:            {"Image error: %s", "An error occurred with image '%s'", models.EventSeverityError},
+	models.EventTypeImagePin:              {"Image pinned: %s", "Image '%s' has been pinned", models.EventSeverityInfo},
+	models.EventTypeImageUnpin:            {"Image unpinned: %s", "Image '%s' has been unpinned", models.EventSeverityInfo},
+	models.EventTypeImageTag:              {"Image tagged: %s", "Image has been tagged as '%s'", models.EventSeverityInfo},
+	models.EventTypeImageUntag:            {"Image untagged: %s", "Tag '%s' has been removed from the image", models.EventSeverityInfo},
+	models.EventTypeImageScanGateOverride: {"Scan gate overridden: %s", "A container was created from '%s' despite the vulnerability scan gate", models.EventSeverityWarning},
 
 	models.EventTypeProjectDeploy: {"Project deployed: %s", "Project '%s' has been deployed", models.EventSeveritySuccess},
 	models.EventTypeProjectDelete: {"Project deleted: %s", "Project '%s' has been deleted", models.EventSeverityWarning},
@@ -397,15 +462,20 @@ var eventDefinitions = map[models.EventType]struct {
 
 	models.EventTypeVolumeCreate:             {"Volume created: %s", "Volume '%s' has been created", models.EventSeveritySuccess},
 	models.EventTypeVolumeDelete:             {"Volume deleted: %s", "Volume '%s' has been deleted", models.EventSeverityWarning},
+	models.EventTypeVolumeUpdate:             {"Volume updated: %s", "Volume '%s' has been updated", models.EventSeverityInfo},
 	models.EventTypeVolumeError:              {"Volume error: %s", "An error occurred with volume '%s'", models.EventSeverityError},
 	models.EventTypeVolumeFileCreate:         {"Volume file created: %s", "A file or directory was created in volume '%s'", models.EventSeveritySuccess},
 	models.EventTypeVolumeFileDelete:         {"Volume file deleted: %s", "A file or directory was deleted in volume '%s'", models.EventSeverityWarning},
 	models.EventTypeVolumeFileUpload:         {"Volume file uploaded: %s", "A file was uploaded to volume '%s'", models.EventSeveritySuccess},
+	models.EventTypeVolumeFileOwnership:      {"Volume file ownership changed: %s", "File ownership was changed in volume '%s'", models.EventSeverityInfo},
+	models.EventTypeVolumeFilePermissions:    {"Volume file permissions changed: %s", "File permissions were changed in volume '%s'", models.EventSeverityInfo},
 	models.EventTypeVolumeBackupCreate:       {"Volume backup created: %s", "A backup was created for volume '%s'", models.EventSeveritySuccess},
 	models.EventTypeVolumeBackupDelete:       {"Volume backup deleted: %s", "A backup was deleted for volume '%s'", models.EventSeverityWarning},
 	models.EventTypeVolumeBackupRestore:      {"Volume backup restored: %s", "A backup was restored for volume '%s'", models.EventSeverityWarning},
 	models.EventTypeVolumeBackupRestoreFiles: {"Volume backup files restored: %s", "Selected files were restored for volume '%s'", models.EventSeverityWarning},
 	models.EventTypeVolumeBackupDownload:     {"Volume backup downloaded: %s", "A backup was downloaded for volume '%s'", models.EventSeverityInfo},
+	models.EventTypeVolumeBackupAdopt:        {"Volume backup adopted: %s", "An existing backup archive was adopted for volume '%s'", models.EventSeverityInfo},
+	models.EventTypeVolumeBackupTransfer:     {"Volume backup transferred: %s", "A backup from another environment was restored into volume '%s'", models.EventSeverityWarning},
 
 	models.EventTypeNetworkCreate: {"Network created: %s", "Network '%s' has been created", models.EventSeveritySuccess},
 	models.EventTypeNetworkDelete: {"Network deleted: %s", "Network '%s' has been deleted", models.EventSeverityWarning},
@@ -413,8 +483,27 @@ var eventDefinitions = map[models.EventType]struct {
 
 	models.EventTypeSystemPrune:      {"System prune completed", "System resources have been pruned", models.EventSeverityInfo},
 	models.EventTypeSystemAutoUpdate: {"System auto-update completed", "System auto-update process has completed", models.EventSeverityInfo},
+	models.EventTypeSystemRollout:    {"Auto-update rollout: %s", "A staged auto-update rollout changed state on '%s'", models.EventSeverityInfo},
 	models.EventTypeSystemUpgrade:    {"System upgrade completed", "System upgrade process has completed", models.EventSeverityInfo},
 
+	models.EventTypeSystemBootVerification:     {"Post-restart verification completed", "Expected containers were verified after a Docker restart", models.EventSeverityInfo},
+	models.EventTypeSystemOperationInterrupted: {"Operation interrupted: %s", "An operation on '%s' was cut off by a restart", models.EventSeverityWarning},
+	models.EventTypeSystemHelperReap:           {"Stale helpers removed", "Leftover helper containers and restore files were removed", models.EventSeverityInfo},
+
+	models.EventTypeEnvironmentPolicyViolation: {"Policy violation: %s", "An action on environment '%s' was blocked by its policy", models.EventSeverityWarning},
+	models.EventTypeEnvironmentPolicyPrune:     {"Policy prune completed: %s", "Environment '%s' pruned resources on its policy schedule", models.EventSeverityInfo},
+
+	models.EventTypeApprovalRequested: {"Approval requested: %s", "Approval was requested for '%s'", models.EventSeverityWarning},
+	models.EventTypeApprovalApproved:  {"Approval granted: %s", "Approval was granted for '%s'", models.EventSeverityInfo},
+	models.EventTypeApprovalRejected:  {"Approval rejected: %s", "Approval was rejected for '%s'", models.EventSeverityInfo},
+	models.EventTypeApprovalExecuted:  {"Approved action executed: %s", "The approved action '%s' was executed", models.EventSeverityWarning},
+
+	models.EventTypeMonitorDown: {"Monitor down: %s", "Endpoint monitor '%s' is failing", models.EventSeverityError},
+	models.EventTypeMonitorUp:   {"Monitor up: %s", "Endpoint monitor '%s' has recovered", models.EventSeveritySuccess},
+
+	models.EventTypeWebhookReceived:  {"Webhook received: %s", "An external event was received from webhook source '%s'", models.EventSeverityInfo},
+	models.EventTypeWebhookTriggered: {"Webhook rule triggered: %s", "Webhook source '%s' triggered its rule", models.EventSeverityInfo},
+
 	models.EventTypeUserLogin:  {"User logged in: %s", "User '%s' has logged in", models.EventSeverityInfo},
 	models.EventTypeUserLogout: {"User logged out: %s", "User '%s' has logged out", models.EventSeverityInfo},
 }
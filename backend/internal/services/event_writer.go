@@ -0,0 +1,189 @@
+package services
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+const (
+	eventWriterBatchSize     = 100
+	eventWriterFlushInterval = 500 * time.Millisecond
+	eventWriterDropLogEvery  = 10 * time.Second
+)
+
+// EventWriterStats describes the state of the async event writer.
+type EventWriterStats struct {
+	Enabled  bool   `json:"enabled"`
+	Queued   int    `json:"queued"`
+	Capacity int    `json:"capacity"`
+	Written  uint64 `json:"written"`
+	Dropped  uint64 `json:"dropped"`
+}
+
+// eventWriterInternal buffers events in a bounded queue and inserts them in
+// batches, so that request handling does not wait on the database. When the
+// queue is full new events are dropped and counted rather than blocking the
+// caller.
+type eventWriterInternal struct {
+	db    *database.DB
+	queue chan *models.Event
+
+	// mu guards stopped against concurrent enqueues so nothing is sent after
+	// the writer has drained the queue for the last time.
+	mu      sync.RWMutex
+	stopped bool
+	stop    chan struct{}
+	done    chan struct{}
+
+	written     atomic.Uint64
+	dropped     atomic.Uint64
+	lastDropLog atomic.Int64
+}
+
+func newEventWriterInternal(db *database.DB, queueSize int) *eventWriterInternal {
+	return &eventWriterInternal{
+		db:    db,
+		queue: make(chan *models.Event, queueSize),
+		stop:  make(chan struct{}),
+		done:  make(chan struct{}),
+	}
+}
+
+// enqueue hands the event to the writer. It returns false when the writer has
+// stopped and the caller should write the event itself. A full queue drops
+// the event and still returns true.
+func (w *eventWriterInternal) enqueue(event *models.Event) bool {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.stopped {
+		return false
+	}
+
+	select {
+	case w.queue <- event:
+	default:
+		dropped := w.dropped.Add(1)
+		now := time.Now().UnixNano()
+		last := w.lastDropLog.Load()
+		if now-last >= int64(eventWriterDropLogEvery) && w.lastDropLog.CompareAndSwap(last, now) {
+			slog.Warn("event queue is full, dropping events", "type", event.Type, "dropped_total", dropped, "capacity", cap(w.queue))
+		}
+	}
+	return true
+}
+
+func (w *eventWriterInternal) run(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(eventWriterFlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*models.Event, 0, eventWriterBatchSize)
+	for {
+		select {
+		case event := <-w.queue:
+			batch = append(batch, event)
+			if len(batch) >= eventWriterBatchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ctx.Done():
+			w.drain(batch)
+			return
+		case <-w.stop:
+			w.drain(batch)
+			return
+		}
+	}
+}
+
+// drain stops accepting events and writes whatever is still queued.
+func (w *eventWriterInternal) drain(batch []*models.Event) {
+	w.mu.Lock()
+	w.stopped = true
+	w.mu.Unlock()
+
+	for {
+		select {
+		case event := <-w.queue:
+			batch = append(batch, event)
+			if len(batch) >= eventWriterBatchSize {
+				w.flush(batch)
+				batch = batch[:0]
+			}
+		default:
+			if len(batch) > 0 {
+				w.flush(batch)
+			}
+			return
+		}
+	}
+}
+
+func (w *eventWriterInternal) flush(batch []*models.Event) {
+	// Event writes must not be cut short by the request that produced them.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	err := w.db.WithContext(ctx).CreateInBatches(batch, len(batch)).Error
+	if err == nil {
+		w.written.Add(uint64(len(batch)))
+		return
+	}
+	if len(batch) == 1 {
+		w.dropped.Add(1)
+		slog.Warn("failed to write event", "type", batch[0].Type, "error", err)
+		return
+	}
+	slog.Warn("failed to write event batch, retrying one by one", "size", len(batch), "error", err)
+
+	// One bad row fails the whole insert; write the rest individually.
+	for _, event := range batch {
+		if err := w.db.WithContext(ctx).Create(event).Error; err != nil {
+			w.dropped.Add(1)
+			slog.Warn("failed to write event", "type", event.Type, "error", err)
+			continue
+		}
+		w.written.Add(1)
+	}
+}
+
+func (w *eventWriterInternal) close(ctx context.Context) error {
+	w.mu.Lock()
+	if !w.stopped {
+		select {
+		case <-w.stop:
+		default:
+			close(w.stop)
+		}
+	}
+	w.mu.Unlock()
+
+	select {
+	case <-w.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (w *eventWriterInternal) stats() EventWriterStats {
+	return EventWriterStats{
+		Enabled:  true,
+		Queued:   len(w.queue),
+		Capacity: cap(w.queue),
+		Written:  w.written.Load(),
+		Dropped:  w.dropped.Load(),
+	}
+}
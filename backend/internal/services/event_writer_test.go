@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+func setupEventWriterTest(t *testing.T) (*EventService, *gorm.DB) {
+	t.Helper()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Event{}))
+	// Every connection to :memory: is a separate database; the writer's
+	// goroutine has to see the same one.
+	sqlDB, err := gdb.DB()
+	require.NoError(t, err)
+	sqlDB.SetMaxOpenConns(1)
+	return NewEventService(&database.DB{DB: gdb}), gdb
+}
+
+func TestEventService_AsyncWriterFlushesOnClose(t *testing.T) {
+	ctx := context.Background()
+	svc, gdb := setupEventWriterTest(t)
+	svc.StartAsyncWriter(ctx, 512)
+
+	for range 250 {
+		event, err := svc.CreateEvent(ctx, CreateEventRequest{Type: models.EventTypeContainerStart, Title: "started"})
+		require.NoError(t, err)
+		assert.NotEmpty(t, event.ID)
+	}
+
+	closeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, svc.CloseAsyncWriter(closeCtx))
+
+	var count int64
+	require.NoError(t, gdb.Model(&models.Event{}).Count(&count).Error)
+	assert.Equal(t, int64(250), count)
+	stats := svc.AsyncWriterStats()
+	assert.Equal(t, uint64(250), stats.Written)
+	assert.Zero(t, stats.Dropped)
+
+	// Once closed, events are written before CreateEvent returns.
+	_, err := svc.CreateEvent(ctx, CreateEventRequest{Type: models.EventTypeContainerStop, Title: "stopped"})
+	require.NoError(t, err)
+	require.NoError(t, gdb.Model(&models.Event{}).Count(&count).Error)
+	assert.Equal(t, int64(251), count)
+}
+
+func TestEventService_AsyncWriterDropsWhenFull(t *testing.T) {
+	ctx := context.Background()
+	svc, gdb := setupEventWriterTest(t)
+
+	// Build the writer without starting it so nothing drains the queue.
+	svc.writer = newEventWriterInternal(svc.db, 2)
+	for range 5 {
+		_, err := svc.CreateEvent(ctx, CreateEventRequest{Type: models.EventTypeContainerStart, Title: "started"})
+		require.NoError(t, err)
+	}
+
+	stats := svc.AsyncWriterStats()
+	assert.Equal(t, 2, stats.Queued)
+	assert.Equal(t, uint64(3), stats.Dropped)
+
+	go svc.writer.run(ctx)
+	closeCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	require.NoError(t, svc.CloseAsyncWriter(closeCtx))
+
+	var count int64
+	require.NoError(t, gdb.Model(&models.Event{}).Count(&count).Error)
+	assert.Equal(t, int64(2), count)
+}
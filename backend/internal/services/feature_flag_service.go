@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/types/environment"
+)
+
+// featureSettingKeys maps each feature flag to the setting holding its global state.
+var featureSettingKeys = map[string]string{
+	environment.FeatureVolumeBrowser: "featureVolumeBrowserEnabled",
+	environment.FeatureContainerExec: "featureContainerExecEnabled",
+	environment.FeaturePrune:         "featurePruneEnabled",
+
+	environment.FeatureContainerCheckpoint: "featureCheckpointEnabled",
+}
+
+// experimentalFeatures are off unless enabled in the settings.
+var experimentalFeatures = []string{environment.FeatureContainerCheckpoint}
+
+// ErrUnknownFeature is returned when an override names a feature that does not exist.
+var ErrUnknownFeature = errors.New("unknown feature")
+
+// FeatureFlagService resolves which capabilities are enabled for an
+// environment. Flags are configured globally in the settings and can be
+// overridden per environment.
+type FeatureFlagService struct {
+	environmentService *EnvironmentService
+	settingsService    *SettingsService
+	eventService       *EventService
+}
+
+func NewFeatureFlagService(environmentService *EnvironmentService, settingsService *SettingsService, eventService *EventService) *FeatureFlagService {
+	return &FeatureFlagService{
+		environmentService: environmentService,
+		settingsService:    settingsService,
+		eventService:       eventService,
+	}
+}
+
+// IsEnabled reports whether a feature is enabled for the environment. Unknown
+// environments fall back to the global setting.
+func (s *FeatureFlagService) IsEnabled(ctx context.Context, environmentID, feature string) bool {
+	env, err := s.environmentService.GetEnvironmentByID(ctx, environmentID)
+	if err != nil {
+		slog.DebugContext(ctx, "feature flags: environment lookup failed, using global setting", "environment", environmentID, "error", err)
+		env = nil
+	}
+	return s.resolveInternal(ctx, env, feature).Enabled
+}
+
+// GetEnvironmentFeatureFlags returns the effective state of every feature for
+// the environment.
+func (s *FeatureFlagService) GetEnvironmentFeatureFlags(ctx context.Context, environmentID string) ([]environment.FeatureFlag, error) {
+	env, err := s.environmentService.GetEnvironmentByID(ctx, environmentID)
+	if err != nil {
+		return nil, fmt.Errorf("environment not found: %w", err)
+	}
+
+	flags := make([]environment.FeatureFlag, 0, len(environment.Features))
+	for _, feature := range environment.Features {
+		flags = append(flags, s.resolveInternal(ctx, env, feature))
+	}
+	return flags, nil
+}
+
+// UpdateEnvironmentFeatureFlags sets or clears environment overrides and
+// returns the resulting flags.
+func (s *FeatureFlagService) UpdateEnvironmentFeatureFlags(ctx context.Context, environmentID string, overrides map[string]*bool, user models.User) ([]environment.FeatureFlag, error) {
+	for feature := range overrides {
+		if !slices.Contains(environment.Features, feature) {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownFeature, feature)
+		}
+	}
+
+	env, err := s.environmentService.GetEnvironmentByID(ctx, environmentID)
+	if err != nil {
+		return nil, fmt.Errorf("environment not found: %w", err)
+	}
+
+	updated := models.FeatureOverrides{}
+	for feature, enabled := range env.FeatureOverrides {
+		updated[feature] = enabled
+	}
+	for feature, enabled := range overrides {
+		if enabled == nil {
+			delete(updated, feature)
+		} else {
+			updated[feature] = *enabled
+		}
+	}
+
+	if err := s.environmentService.GetDB().WithContext(ctx).Model(&models.Environment{}).Where("id = ?", environmentID).Update("feature_overrides", updated).Error; err != nil {
+		return nil, fmt.Errorf("failed to update feature flags: %w", err)
+	}
+	env.FeatureOverrides = updated
+
+	if s.eventService != nil {
+		resourceType := "environment"
+		_, _ = s.eventService.CreateEvent(ctx, CreateEventRequest{
+			Type:          models.EventTypeEnvironmentUpdate,
+			Severity:      models.EventSeverityInfo,
+			Title:         "Environment feature flags updated",
+			Description:   fmt.Sprintf("Feature flags for environment '%s' were updated", env.Name),
+			ResourceType:  &resourceType,
+			ResourceID:    &env.ID,
+			ResourceName:  &env.Name,
+			UserID:        &user.ID,
+			Username:      &user.Username,
+			EnvironmentID: &env.ID,
+			Metadata:      models.JSON{"action": "feature_flags_update", "overrides": updated},
+		})
+	}
+
+	flags := make([]environment.FeatureFlag, 0, len(environment.Features))
+	for _, feature := range environment.Features {
+		flags = append(flags, s.resolveInternal(ctx, env, feature))
+	}
+	return flags, nil
+}
+
+func (s *FeatureFlagService) resolveInternal(ctx context.Context, env *models.Environment, feature string) environment.FeatureFlag {
+	defaultEnabled := !slices.Contains(experimentalFeatures, feature)
+	flag := environment.FeatureFlag{Name: feature, GlobalEnabled: defaultEnabled}
+	if key, ok := featureSettingKeys[feature]; ok && s.settingsService != nil {
+		flag.GlobalEnabled = s.settingsService.GetBoolSetting(ctx, key, defaultEnabled)
+	}
+	flag.Enabled = flag.GlobalEnabled
+
+	if env != nil {
+		if enabled, ok := env.FeatureOverrides[feature]; ok {
+			flag.Override = &enabled
+			flag.Enabled = enabled
+		}
+	}
+	return flag
+}
@@ -14,6 +14,7 @@ import (
 	bootstraputils "github.com/getarcaneapp/arcane/backend/internal/utils"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/mapper"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/pagination"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/timeouts"
 	"github.com/getarcaneapp/arcane/types/gitops"
 	"gorm.io/gorm"
 )
@@ -281,7 +282,7 @@ func (s *GitOpsSyncService) DeleteSync(ctx context.Context, environmentID, id st
 }
 
 func (s *GitOpsSyncService) PerformSync(ctx context.Context, environmentID, id string) (*gitops.SyncResult, error) {
-	syncCtx, cancel := context.WithTimeout(ctx, defaultGitSyncTimeout)
+	syncCtx, cancel := timeouts.WithTimeout(ctx, 0, defaultGitSyncTimeout)
 	defer cancel()
 
 	sync, err := s.GetSyncByID(syncCtx, environmentID, id)
@@ -460,7 +461,7 @@ func (s *GitOpsSyncService) SyncAllEnabled(ctx context.Context) error {
 }
 
 func (s *GitOpsSyncService) BrowseFiles(ctx context.Context, environmentID, id string, path string) (*gitops.BrowseResponse, error) {
-	browseCtx, cancel := context.WithTimeout(ctx, defaultGitSyncTimeout)
+	browseCtx, cancel := timeouts.WithTimeout(ctx, 0, defaultGitSyncTimeout)
 	defer cancel()
 
 	sync, err := s.GetSyncByID(browseCtx, environmentID, id)
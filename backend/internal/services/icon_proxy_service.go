@@ -0,0 +1,324 @@
+package services
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	iconMaxBytes      = 1 << 20
+	iconCacheMaxBytes = 32 << 20
+	iconCacheTTL      = 24 * time.Hour
+	iconFailureTTL    = 5 * time.Minute
+)
+
+var (
+	ErrInvalidIconURL  = errors.New("invalid icon URL")
+	ErrIconTooLarge    = errors.New("icon exceeds the maximum size")
+	ErrIconNotImage    = errors.New("icon is not a supported image")
+	ErrIconFetchFailed = errors.New("failed to fetch icon")
+	ErrIconHostBlocked = errors.New("icon host is not a public address")
+)
+
+// iconContentTypes lists the image types the proxy serves. SVG cannot be
+// sniffed reliably, so it is trusted by its declared type and served with a
+// restrictive content security policy by the handler.
+var iconContentTypes = map[string]bool{
+	"image/png":                true,
+	"image/jpeg":               true,
+	"image/gif":                true,
+	"image/webp":               true,
+	"image/avif":               true,
+	"image/svg+xml":            true,
+	"image/x-icon":             true,
+	"image/vnd.microsoft.icon": true,
+}
+
+// Icon is a fetched icon.
+type Icon struct {
+	ContentType string
+	Data        []byte
+}
+
+type iconCacheEntry struct {
+	key       string
+	icon      *Icon
+	err       error
+	expiresAt time.Time
+}
+
+// IconProxyService fetches project and container icons on behalf of the
+// browser, so that icon hosts only ever see the Arcane server, and keeps
+// them in a size-bounded in-memory cache. Icons are only fetched from public
+// addresses so the proxy cannot be used to reach the server's own network.
+type IconProxyService struct {
+	httpClient *http.Client
+	// allowPrivate lifts the public address check; tests use it to reach
+	// local servers.
+	allowPrivate bool
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	order   *list.List
+	size    int
+	sf      singleflight.Group
+}
+
+func NewIconProxyService(httpClient *http.Client) *IconProxyService {
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	s := &IconProxyService{
+		entries: map[string]*list.Element{},
+		order:   list.New(),
+	}
+
+	transport, ok := httpClient.Transport.(*http.Transport)
+	if !ok {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+	transport = transport.Clone()
+	// The address check runs on the dialed address, which covers redirects
+	// and DNS answers that change between lookups. A proxy would hide the
+	// real target, so icons are always fetched directly.
+	transport.Proxy = nil
+	transport.DialContext = (&net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control:   s.dialControlInternal,
+	}).DialContext
+
+	client := *httpClient
+	client.Transport = transport
+	s.httpClient = &client
+	return s
+}
+
+func (s *IconProxyService) dialControlInternal(_, address string, _ syscall.RawConn) error {
+	if s.allowPrivate {
+		return nil
+	}
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrIconHostBlocked, err)
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || !isPublicIPInternal(ip) {
+		return fmt.Errorf("%w: %s", ErrIconHostBlocked, host)
+	}
+	return nil
+}
+
+// iconBlockedPrefixes lists the address ranges the icon proxy never dials:
+// the IANA IPv4 and IPv6 special-purpose registries plus multicast and
+// reserved space. IPv4-mapped IPv6 addresses are unmapped before matching.
+var iconBlockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("10.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("127.0.0.0/8"),
+	netip.MustParsePrefix("169.254.0.0/16"),
+	netip.MustParsePrefix("172.16.0.0/12"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("192.0.2.0/24"),
+	netip.MustParsePrefix("192.88.99.0/24"),
+	netip.MustParsePrefix("192.168.0.0/16"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("198.51.100.0/24"),
+	netip.MustParsePrefix("203.0.113.0/24"),
+	netip.MustParsePrefix("224.0.0.0/4"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("::/128"),
+	netip.MustParsePrefix("::1/128"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+	netip.MustParsePrefix("64:ff9b:1::/48"),
+	netip.MustParsePrefix("100::/64"),
+	netip.MustParsePrefix("2001::/23"),
+	netip.MustParsePrefix("2001:db8::/32"),
+	netip.MustParsePrefix("2002::/16"),
+	netip.MustParsePrefix("fc00::/7"),
+	netip.MustParsePrefix("fe80::/10"),
+	netip.MustParsePrefix("ff00::/8"),
+}
+
+// isPublicIPInternal reports whether ip is routable on the internet, as
+// opposed to loopback, private, link-local (including cloud metadata),
+// shared, translated or otherwise special-purpose addresses.
+func isPublicIPInternal(ip net.IP) bool {
+	addr, ok := netip.AddrFromSlice(ip)
+	if !ok {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range iconBlockedPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// GetIcon returns the icon at rawURL, fetching it if it is not cached.
+// Failed fetches are remembered for a few minutes so that a broken icon URL
+// is not requested on every page load.
+func (s *IconProxyService) GetIcon(ctx context.Context, rawURL string) (*Icon, error) {
+	iconURL, err := normalizeIconURLInternal(rawURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if entry, ok := s.lookupInternal(iconURL); ok {
+		return entry.icon, entry.err
+	}
+
+	v, err, _ := s.sf.Do(iconURL, func() (any, error) {
+		// The fetch is shared by every waiting request, so it must not be
+		// cancelled when the first caller goes away.
+		icon, fetchErr := s.fetchInternal(context.WithoutCancel(ctx), iconURL)
+		if fetchErr != nil && ctx.Err() == nil {
+			s.storeInternal(iconURL, nil, fetchErr)
+		} else if fetchErr == nil {
+			s.storeInternal(iconURL, icon, nil)
+		}
+		return icon, fetchErr
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.(*Icon), nil
+}
+
+func normalizeIconURLInternal(rawURL string) (string, error) {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrInvalidIconURL, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("%w: only http and https URLs are supported", ErrInvalidIconURL)
+	}
+	if u.Host == "" {
+		return "", fmt.Errorf("%w: missing host", ErrInvalidIconURL)
+	}
+	u.Fragment = ""
+	return u.String(), nil
+}
+
+func (s *IconProxyService) fetchInternal(ctx context.Context, iconURL string) (*Icon, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, iconURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidIconURL, err)
+	}
+	req.Header.Set("Accept", "image/*")
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrIconFetchFailed, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%w: unexpected status %d", ErrIconFetchFailed, resp.StatusCode)
+	}
+	if resp.ContentLength > iconMaxBytes {
+		return nil, ErrIconTooLarge
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, iconMaxBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrIconFetchFailed, err)
+	}
+	if len(data) > iconMaxBytes {
+		return nil, ErrIconTooLarge
+	}
+
+	contentType, err := iconContentTypeInternal(resp.Header.Get("Content-Type"), data)
+	if err != nil {
+		return nil, err
+	}
+	return &Icon{ContentType: contentType, Data: data}, nil
+}
+
+// iconContentTypeInternal checks the declared content type against the
+// allow list and, for raster formats, against the sniffed type so that a
+// server cannot pass off HTML as an image.
+func iconContentTypeInternal(declared string, data []byte) (string, error) {
+	mediaType, _, err := mime.ParseMediaType(declared)
+	if err != nil || !iconContentTypes[mediaType] {
+		return "", fmt.Errorf("%w: %q", ErrIconNotImage, declared)
+	}
+	if mediaType == "image/svg+xml" {
+		return mediaType, nil
+	}
+
+	sniffed := http.DetectContentType(data)
+	if !strings.HasPrefix(sniffed, "image/") && sniffed != "application/octet-stream" {
+		return "", fmt.Errorf("%w: content looks like %s", ErrIconNotImage, sniffed)
+	}
+	return mediaType, nil
+}
+
+func (s *IconProxyService) lookupInternal(key string) (*iconCacheEntry, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	el, ok := s.entries[key]
+	if !ok {
+		return nil, false
+	}
+	entry := el.Value.(*iconCacheEntry)
+	if time.Now().After(entry.expiresAt) {
+		s.removeInternal(el)
+		return nil, false
+	}
+	s.order.MoveToFront(el)
+	return entry, true
+}
+
+func (s *IconProxyService) storeInternal(key string, icon *Icon, err error) {
+	entry := &iconCacheEntry{key: key, icon: icon, err: err, expiresAt: time.Now().Add(iconCacheTTL)}
+	if err != nil {
+		entry.expiresAt = time.Now().Add(iconFailureTTL)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if el, ok := s.entries[key]; ok {
+		s.removeInternal(el)
+	}
+	s.entries[key] = s.order.PushFront(entry)
+	s.size += entrySizeInternal(entry)
+
+	for s.size > iconCacheMaxBytes && s.order.Len() > 1 {
+		s.removeInternal(s.order.Back())
+	}
+}
+
+func (s *IconProxyService) removeInternal(el *list.Element) {
+	entry := el.Value.(*iconCacheEntry)
+	s.order.Remove(el)
+	delete(s.entries, entry.key)
+	s.size -= entrySizeInternal(entry)
+}
+
+func entrySizeInternal(entry *iconCacheEntry) int {
+	size := len(entry.key)
+	if entry.icon != nil {
+		size += len(entry.icon.Data)
+	}
+	return size
+}
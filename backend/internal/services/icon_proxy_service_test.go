@@ -0,0 +1,122 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPNG = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+
+func TestIconProxyService_GetIcon(t *testing.T) {
+	ctx := context.Background()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		switch r.URL.Path {
+		case "/icon.png":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(testPNG)
+		case "/icon.svg":
+			w.Header().Set("Content-Type", "image/svg+xml; charset=utf-8")
+			_, _ = io.WriteString(w, `<svg xmlns="http://www.w3.org/2000/svg"/>`)
+		case "/fake.png":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = io.WriteString(w, "<html><body>hello</body></html>")
+		case "/page":
+			w.Header().Set("Content-Type", "text/html")
+			_, _ = io.WriteString(w, "<html></html>")
+		case "/huge.png":
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write(append(testPNG, bytes.Repeat([]byte{0}, iconMaxBytes)...))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	svc := NewIconProxyService(server.Client())
+	svc.allowPrivate = true
+
+	icon, err := svc.GetIcon(ctx, server.URL+"/icon.png")
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", icon.ContentType)
+	assert.Equal(t, testPNG, icon.Data)
+
+	_, err = svc.GetIcon(ctx, server.URL+"/icon.png#fragment")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), hits.Load(), "cached icon should not be fetched again")
+
+	svg, err := svc.GetIcon(ctx, server.URL+"/icon.svg")
+	require.NoError(t, err)
+	assert.Equal(t, "image/svg+xml", svg.ContentType)
+
+	_, err = svc.GetIcon(ctx, server.URL+"/fake.png")
+	require.ErrorIs(t, err, ErrIconNotImage)
+	_, err = svc.GetIcon(ctx, server.URL+"/page")
+	require.ErrorIs(t, err, ErrIconNotImage)
+	_, err = svc.GetIcon(ctx, server.URL+"/huge.png")
+	require.ErrorIs(t, err, ErrIconTooLarge)
+
+	before := hits.Load()
+	_, err = svc.GetIcon(ctx, server.URL+"/missing.png")
+	require.ErrorIs(t, err, ErrIconFetchFailed)
+	_, err = svc.GetIcon(ctx, server.URL+"/missing.png")
+	require.ErrorIs(t, err, ErrIconFetchFailed)
+	assert.Equal(t, before+1, hits.Load(), "failures should be cached")
+
+	_, err = svc.GetIcon(ctx, "file:///etc/passwd")
+	require.ErrorIs(t, err, ErrInvalidIconURL)
+	_, err = svc.GetIcon(ctx, "/relative.png")
+	require.ErrorIs(t, err, ErrInvalidIconURL)
+}
+
+func TestIconProxyService_BlocksPrivateAddresses(t *testing.T) {
+	ctx := context.Background()
+	var hits atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits.Add(1)
+		w.Header().Set("Content-Type", "image/png")
+		_, _ = w.Write(testPNG)
+	}))
+	defer server.Close()
+
+	svc := NewIconProxyService(server.Client())
+	_, err := svc.GetIcon(ctx, server.URL+"/icon.png")
+	require.ErrorIs(t, err, ErrIconFetchFailed)
+	require.ErrorIs(t, err, ErrIconHostBlocked)
+	assert.Zero(t, hits.Load())
+
+	for _, addr := range []string{"127.0.0.1", "::1", "10.1.2.3", "192.168.1.1", "172.16.0.1", "169.254.169.254", "fe80::1", "0.0.0.0", "::ffff:127.0.0.1",
+		"100.64.0.1", "100.100.100.100", "::ffff:10.0.0.1", "198.18.0.1", "192.0.0.1", "0.1.2.3", "64:ff9b::a00:1", "255.255.255.255"} {
+		assert.False(t, isPublicIPInternal(net.ParseIP(addr)), addr)
+	}
+	for _, addr := range []string{"1.1.1.1", "2606:4700:4700::1111", "::ffff:8.8.8.8"} {
+		assert.True(t, isPublicIPInternal(net.ParseIP(addr)), addr)
+	}
+}
+
+func TestIconProxyService_EvictsLeastRecentlyUsed(t *testing.T) {
+	svc := NewIconProxyService(nil)
+	big := &Icon{ContentType: "image/png", Data: make([]byte, iconCacheMaxBytes/3)}
+
+	svc.storeInternal("a", big, nil)
+	svc.storeInternal("b", big, nil)
+	_, ok := svc.lookupInternal("a")
+	require.True(t, ok)
+
+	svc.storeInternal("c", big, nil)
+	_, ok = svc.lookupInternal("b")
+	assert.False(t, ok)
+	_, ok = svc.lookupInternal("a")
+	assert.True(t, ok)
+	assert.LessOrEqual(t, svc.size, iconCacheMaxBytes)
+}
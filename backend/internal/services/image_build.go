@@ -0,0 +1,235 @@
+package services
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/build"
+	"github.com/google/uuid"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/ws"
+	imagetypes "github.com/getarcaneapp/arcane/types/image"
+)
+
+// buildMessageInternal is one line of the JSON stream Docker returns while
+// building an image.
+type buildMessageInternal struct {
+	Stream      string `json:"stream"`
+	Status      string `json:"status"`
+	Error       string `json:"error"`
+	ErrorDetail *struct {
+		Message string `json:"message"`
+	} `json:"errorDetail"`
+	Aux *struct {
+		ID string `json:"ID"`
+	} `json:"aux"`
+}
+
+// BuildProgressHub returns the hub on which image build output is
+// broadcast, starting it on first use.
+func (s *ImageService) BuildProgressHub() *ws.Hub {
+	s.buildProgressOnce.Do(func() {
+		s.buildProgressHub = ws.NewHub(256)
+		go s.buildProgressHub.Run(context.Background())
+	})
+	return s.buildProgressHub
+}
+
+func (s *ImageService) publishBuildProgressInternal(p imagetypes.BuildProgress) {
+	hub := s.BuildProgressHub()
+	if hub.ClientCount() == 0 {
+		return
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		slog.Warn("failed to encode build progress", "error", err)
+		return
+	}
+	hub.Broadcast(b)
+}
+
+// BuildImage builds an image and tags it. The build context is read from
+// buildContext as a tar archive, unless the request gives the Dockerfile
+// content or a Git URL instead. Output is broadcast on BuildProgressHub
+// under the build ID while the build runs.
+func (s *ImageService) BuildImage(ctx context.Context, buildContext io.Reader, req imagetypes.BuildRequest, user models.User) (*imagetypes.BuildResult, error) {
+	tags, err := normalizeBuildTagsInternal(req.Tags)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := build.ImageBuildOptions{
+		Tags:        tags,
+		Dockerfile:  strings.TrimSpace(req.Dockerfile),
+		NoCache:     req.NoCache,
+		PullParent:  req.Pull,
+		Remove:      true,
+		ForceRemove: true,
+		BuildArgs:   make(map[string]*string, len(req.BuildArgs)),
+	}
+	for k, v := range req.BuildArgs {
+		opts.BuildArgs[k] = &v
+	}
+
+	switch {
+	case strings.TrimSpace(req.GitURL) != "":
+		gitURL := strings.TrimSpace(req.GitURL)
+		if !isGitBuildURLInternal(gitURL) {
+			return nil, fmt.Errorf("%w: %q is not a Git URL", ErrInvalidBuild, gitURL)
+		}
+		opts.RemoteContext = gitURL
+		buildContext = nil
+	case req.DockerfileContent != "":
+		buildContext, err = dockerfileBuildContextInternal(req.DockerfileContent)
+		if err != nil {
+			return nil, err
+		}
+		opts.Dockerfile = "Dockerfile"
+	case buildContext == nil:
+		return nil, fmt.Errorf("%w: a build context, Dockerfile or Git URL is required", ErrInvalidBuild)
+	}
+
+	buildID := strings.TrimSpace(req.BuildID)
+	if buildID == "" {
+		buildID = uuid.NewString()
+	}
+	imageName := tags[0]
+
+	fail := func(step string, err error) (*imagetypes.BuildResult, error) {
+		s.publishBuildProgressInternal(imagetypes.BuildProgress{BuildID: buildID, Status: imagetypes.BuildStatusFailed, Error: err.Error()})
+		s.eventService.LogErrorEvent(ctx, models.EventTypeImageError, "image", "", imageName, user.ID, user.Username, "0", err, models.JSON{"action": "build", "step": step, "buildId": buildID})
+		return nil, err
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return fail("connect", fmt.Errorf("failed to connect to Docker: %w", err))
+	}
+
+	s.publishBuildProgressInternal(imagetypes.BuildProgress{BuildID: buildID, Status: imagetypes.BuildStatusRunning})
+	started := time.Now()
+
+	resp, err := dockerClient.ImageBuild(ctx, buildContext, opts)
+	if err != nil {
+		return fail("start", fmt.Errorf("failed to start image build: %w", err))
+	}
+	defer resp.Body.Close()
+
+	imageID, err := s.readBuildOutputInternal(resp.Body, buildID)
+	if err != nil {
+		return fail("build", err)
+	}
+	if imageID == "" {
+		// Older daemons do not report the ID; the tag points at the result.
+		inspect, inspectErr := dockerClient.ImageInspect(ctx, imageName)
+		if inspectErr != nil {
+			return fail("inspect", fmt.Errorf("build finished but the image could not be found: %w", inspectErr))
+		}
+		imageID = inspect.ID
+	}
+
+	s.publishBuildProgressInternal(imagetypes.BuildProgress{BuildID: buildID, Status: imagetypes.BuildStatusSucceeded, ImageID: imageID})
+
+	metadata := models.JSON{
+		"action":   "build",
+		"buildId":  buildID,
+		"tags":     tags,
+		"duration": time.Since(started).Round(time.Second).String(),
+	}
+	if opts.RemoteContext != "" {
+		metadata["gitUrl"] = opts.RemoteContext
+	}
+	if logErr := s.eventService.LogImageEvent(ctx, models.EventTypeImageBuild, imageID, imageName, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.Warn("could not log image build action", "err", logErr, "image", imageName)
+	}
+
+	return &imagetypes.BuildResult{BuildID: buildID, ImageID: imageID, Tags: tags}, nil
+}
+
+// readBuildOutputInternal forwards the build output stream to the progress
+// hub and returns the built image ID, or the error the build failed with.
+func (s *ImageService) readBuildOutputInternal(r io.Reader, buildID string) (string, error) {
+	var imageID string
+	dec := json.NewDecoder(r)
+	for {
+		var msg buildMessageInternal
+		if err := dec.Decode(&msg); err != nil {
+			if errors.Is(err, io.EOF) {
+				return imageID, nil
+			}
+			return "", fmt.Errorf("error reading build output: %w", err)
+		}
+
+		if msg.ErrorDetail != nil && msg.ErrorDetail.Message != "" {
+			return "", fmt.Errorf("image build failed: %s", msg.ErrorDetail.Message)
+		}
+		if msg.Error != "" {
+			return "", fmt.Errorf("image build failed: %s", msg.Error)
+		}
+		if msg.Aux != nil && msg.Aux.ID != "" {
+			imageID = msg.Aux.ID
+		}
+
+		output := msg.Stream
+		if output == "" && msg.Status != "" {
+			output = msg.Status + "\n"
+		}
+		if output != "" {
+			s.publishBuildProgressInternal(imagetypes.BuildProgress{BuildID: buildID, Status: imagetypes.BuildStatusRunning, Output: output})
+		}
+	}
+}
+
+func normalizeBuildTagsInternal(tags []string) ([]string, error) {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		normalized, err := normalizeImageTagInternal(tag)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidBuild, err)
+		}
+		out = append(out, normalized)
+	}
+	if len(out) == 0 {
+		return nil, fmt.Errorf("%w: at least one tag is required", ErrInvalidBuild)
+	}
+	return out, nil
+}
+
+func isGitBuildURLInternal(u string) bool {
+	for _, prefix := range []string{"https://", "http://", "git://", "git@"} {
+		if strings.HasPrefix(u, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// dockerfileBuildContextInternal wraps a single Dockerfile in a tar archive
+// so it can be sent as a build context.
+func dockerfileBuildContextInternal(content string) (io.Reader, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	if err := tw.WriteHeader(&tar.Header{Name: "Dockerfile", Mode: 0o644, Size: int64(len(content)), ModTime: time.Now()}); err != nil {
+		return nil, fmt.Errorf("failed to create build context: %w", err)
+	}
+	if _, err := tw.Write([]byte(content)); err != nil {
+		return nil, fmt.Errorf("failed to create build context: %w", err)
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("failed to create build context: %w", err)
+	}
+	return &buf, nil
+}
@@ -0,0 +1,109 @@
+package services
+
+import (
+	"archive/tar"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	imagetypes "github.com/getarcaneapp/arcane/types/image"
+)
+
+func TestNormalizeBuildTags(t *testing.T) {
+	tags, err := normalizeBuildTagsInternal([]string{" myapp ", "", "registry.example.com/team/app:1.0"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"myapp:latest", "registry.example.com/team/app:1.0"}, tags)
+
+	_, err = normalizeBuildTagsInternal([]string{""})
+	require.ErrorIs(t, err, ErrInvalidBuild)
+	_, err = normalizeBuildTagsInternal([]string{"Bad Tag"})
+	require.ErrorIs(t, err, ErrInvalidBuild)
+	_, err = normalizeBuildTagsInternal([]string{"app@sha256:" + strings.Repeat("a", 64)})
+	require.ErrorIs(t, err, ErrInvalidBuild)
+}
+
+func TestImageService_BuildImage(t *testing.T) {
+	ctx := context.Background()
+
+	var query url.Values
+	var contextFiles map[string]string
+	fail := false
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/build") {
+			http.NotFound(w, r)
+			return
+		}
+		query = r.URL.Query()
+		contextFiles = map[string]string{}
+		tr := tar.NewReader(r.Body)
+		for {
+			hdr, err := tr.Next()
+			if err != nil {
+				break
+			}
+			b, _ := io.ReadAll(tr)
+			contextFiles[hdr.Name] = string(b)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"stream":"Step 1/1 : FROM alpine\n"}`+"\n")
+		if fail {
+			_, _ = io.WriteString(w, `{"errorDetail":{"message":"pull access denied"},"error":"pull access denied"}`+"\n")
+			return
+		}
+		_, _ = io.WriteString(w, `{"aux":{"ID":"sha256:built"}}`+"\n")
+		_, _ = io.WriteString(w, `{"stream":"Successfully tagged myapp:1.0\n"}`+"\n")
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Event{}))
+	db := &database.DB{DB: gdb}
+	svc := &ImageService{db: db, dockerService: &DockerClientService{client: cli}, eventService: NewEventService(db)}
+
+	result, err := svc.BuildImage(ctx, nil, imagetypes.BuildRequest{
+		BuildID:           "b1",
+		Tags:              []string{"myapp:1.0"},
+		DockerfileContent: "FROM alpine\n",
+		BuildArgs:         map[string]string{"VERSION": "1"},
+		NoCache:           true,
+	}, systemUser)
+	require.NoError(t, err)
+	assert.Equal(t, &imagetypes.BuildResult{BuildID: "b1", ImageID: "sha256:built", Tags: []string{"myapp:1.0"}}, result)
+	assert.Equal(t, map[string]string{"Dockerfile": "FROM alpine\n"}, contextFiles)
+	assert.Equal(t, []string{"myapp:1.0"}, query["t"])
+	assert.Equal(t, "1", query.Get("nocache"))
+	assert.JSONEq(t, `{"VERSION":"1"}`, query.Get("buildargs"))
+
+	var event models.Event
+	require.NoError(t, gdb.Where("type = ?", models.EventTypeImageBuild).First(&event).Error)
+	assert.Equal(t, "b1", event.Metadata["buildId"])
+
+	_, err = svc.BuildImage(ctx, nil, imagetypes.BuildRequest{Tags: []string{"myapp"}, GitURL: "https://github.com/acme/app.git#main"}, systemUser)
+	require.NoError(t, err)
+	assert.Equal(t, "https://github.com/acme/app.git#main", query.Get("remote"))
+
+	_, err = svc.BuildImage(ctx, nil, imagetypes.BuildRequest{Tags: []string{"myapp"}, GitURL: "/etc/passwd"}, systemUser)
+	require.ErrorIs(t, err, ErrInvalidBuild)
+	_, err = svc.BuildImage(ctx, nil, imagetypes.BuildRequest{Tags: []string{"myapp"}}, systemUser)
+	require.ErrorIs(t, err, ErrInvalidBuild)
+
+	fail = true
+	_, err = svc.BuildImage(ctx, nil, imagetypes.BuildRequest{Tags: []string{"myapp"}, DockerfileContent: "FROM private/base\n"}, systemUser)
+	require.ErrorContains(t, err, "pull access denied")
+}
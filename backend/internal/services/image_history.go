@@ -0,0 +1,82 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/docker/docker/api/types/image"
+
+	imagetypes "github.com/getarcaneapp/arcane/types/image"
+)
+
+// GetImageHistory returns the build steps and default configuration of an
+// image, so it can be audited before a container is started from it.
+func (s *ImageService) GetImageHistory(ctx context.Context, id string) (*imagetypes.History, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	inspect, err := dockerClient.ImageInspect(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrImageNotFound, err)
+	}
+
+	history, err := dockerClient.ImageHistory(ctx, inspect.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read image history: %w", err)
+	}
+
+	out := &imagetypes.History{
+		ImageID:      inspect.ID,
+		RepoTags:     inspect.RepoTags,
+		Layers:       make([]imagetypes.HistoryLayer, 0, len(history)),
+		LayerDigests: []string{},
+		Size:         inspect.Size,
+		Config:       imageConfigDetailsInternal(inspect),
+	}
+	if inspect.RootFS.Layers != nil {
+		out.LayerDigests = inspect.RootFS.Layers
+	}
+	for _, h := range history {
+		out.Layers = append(out.Layers, imagetypes.HistoryLayer{
+			ID:        h.ID,
+			Created:   time.Unix(h.Created, 0).UTC(),
+			CreatedBy: h.CreatedBy,
+			Size:      h.Size,
+			Comment:   h.Comment,
+			Tags:      h.Tags,
+		})
+	}
+	return out, nil
+}
+
+func imageConfigDetailsInternal(inspect image.InspectResponse) imagetypes.ConfigDetails {
+	if inspect.Config == nil {
+		return imagetypes.ConfigDetails{}
+	}
+	cfg := inspect.Config
+	details := imagetypes.ConfigDetails{
+		User:       cfg.User,
+		Entrypoint: cfg.Entrypoint,
+		Cmd:        cfg.Cmd,
+		WorkingDir: cfg.WorkingDir,
+		Env:        cfg.Env,
+		Labels:     cfg.Labels,
+		StopSignal: cfg.StopSignal,
+	}
+	for port := range cfg.ExposedPorts {
+		details.ExposedPorts = append(details.ExposedPorts, port)
+	}
+	slices.Sort(details.ExposedPorts)
+	for path := range cfg.Volumes {
+		details.Volumes = append(details.Volumes, path)
+	}
+	slices.Sort(details.Volumes)
+	if cfg.Healthcheck != nil {
+		details.Healthcheck = cfg.Healthcheck.Test
+	}
+	return details
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImageService_GetImageHistory(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setupImageTransferTest(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/images/missing:1.0/json"):
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message":"No such image"}`)
+		case strings.HasSuffix(r.URL.Path, "/json"):
+			_, _ = io.WriteString(w, `{"Id":"sha256:abc","Size":2048,
+				"Config":{"User":"app","Entrypoint":["/entrypoint.sh"],"Cmd":["serve"],"WorkingDir":"/srv",
+					"Env":["PATH=/usr/bin"],"ExposedPorts":{"8080/tcp":{},"443/tcp":{}},"Volumes":{"/data":{}},
+					"Labels":{"maintainer":"team"},"Healthcheck":{"Test":["CMD","true"]}},
+				"RootFS":{"Type":"layers","Layers":["sha256:l1","sha256:l2"]}}`)
+		case strings.HasSuffix(r.URL.Path, "/images/sha256:abc/history"):
+			_, _ = io.WriteString(w, `[
+				{"Id":"sha256:abc","Created":1700000100,"CreatedBy":"/bin/sh -c #(nop) CMD [\"serve\"]","Size":0,"Tags":["myapp:1.0"]},
+				{"Id":"<missing>","Created":1700000000,"CreatedBy":"/bin/sh -c #(nop) ADD file:x in /","Size":2048,"Comment":"base"}]`)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+
+	history, err := svc.GetImageHistory(ctx, "myapp:1.0")
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:abc", history.ImageID)
+	assert.Equal(t, int64(2048), history.Size)
+	assert.Equal(t, []string{"sha256:l1", "sha256:l2"}, history.LayerDigests)
+	require.Len(t, history.Layers, 2)
+	assert.Equal(t, []string{"myapp:1.0"}, history.Layers[0].Tags)
+	assert.Equal(t, time.Unix(1700000000, 0).UTC(), history.Layers[1].Created)
+	assert.Equal(t, "base", history.Layers[1].Comment)
+
+	cfg := history.Config
+	assert.Equal(t, "app", cfg.User)
+	assert.Equal(t, []string{"/entrypoint.sh"}, cfg.Entrypoint)
+	assert.Equal(t, []string{"serve"}, cfg.Cmd)
+	assert.Equal(t, []string{"443/tcp", "8080/tcp"}, cfg.ExposedPorts)
+	assert.Equal(t, []string{"/data"}, cfg.Volumes)
+	assert.Equal(t, map[string]string{"maintainer": "team"}, cfg.Labels)
+	assert.Equal(t, []string{"CMD", "true"}, cfg.Healthcheck)
+
+	_, err = svc.GetImageHistory(ctx, "missing:1.0")
+	require.ErrorIs(t, err, ErrImageNotFound)
+}
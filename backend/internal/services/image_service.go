@@ -10,23 +10,48 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
+	"sync"
 
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/image"
 	"github.com/docker/docker/api/types/registry"
+	"github.com/docker/docker/client"
 	"github.com/getarcaneapp/arcane/backend/internal/database"
 	"github.com/getarcaneapp/arcane/backend/internal/models"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/pagination"
+	arcRegistry "github.com/getarcaneapp/arcane/backend/internal/utils/registry"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/ws"
 	"github.com/getarcaneapp/arcane/types/containerregistry"
 	imagetypes "github.com/getarcaneapp/arcane/types/image"
+	"github.com/getarcaneapp/arcane/types/system"
 	"github.com/getarcaneapp/arcane/types/vulnerability"
+	"github.com/google/uuid"
 	ref "go.podman.io/image/v5/docker/reference"
 	"golang.org/x/sync/errgroup"
 	"gorm.io/gorm"
 )
 
+var (
+	ErrImageNotFound  = errors.New("image not found")
+	ErrImageNotPinned = errors.New("image is not pinned")
+	ErrInvalidBuild   = errors.New("invalid image build request")
+
+	ErrInvalidImageTag  = errors.New("invalid image tag")
+	ErrImageTagConflict = errors.New("tag already points at another image")
+	ErrImageTagNotFound = errors.New("image does not have this tag")
+	ErrImageLastTag     = errors.New("cannot remove the only tag of an image")
+
+	ErrInvalidImageTransfer  = errors.New("invalid image transfer request")
+	ErrImageTransferTooLarge = errors.New("image archive is too large")
+)
+
 type ImageService struct {
 	db                   *database.DB
 	dockerService        *DockerClientService
@@ -34,9 +59,17 @@ type ImageService struct {
 	registryService      *ContainerRegistryService
 	vulnerabilityService *VulnerabilityService
 	eventService         *EventService
+	settingsService      *SettingsService
+	policyService        *EnvironmentPolicyService
+
+	buildProgressOnce sync.Once
+	buildProgressHub  *ws.Hub
+
+	transferProgressOnce sync.Once
+	transferProgressHub  *ws.Hub
 }
 
-func NewImageService(db *database.DB, dockerService *DockerClientService, registryService *ContainerRegistryService, imageUpdateService *ImageUpdateService, vulnerabilityService *VulnerabilityService, eventService *EventService) *ImageService {
+func NewImageService(db *database.DB, dockerService *DockerClientService, registryService *ContainerRegistryService, imageUpdateService *ImageUpdateService, vulnerabilityService *VulnerabilityService, eventService *EventService, settingsService *SettingsService) *ImageService {
 	return &ImageService{
 		db:                   db,
 		dockerService:        dockerService,
@@ -44,6 +77,7 @@ func NewImageService(db *database.DB, dockerService *DockerClientService, regist
 		imageUpdateService:   imageUpdateService,
 		vulnerabilityService: vulnerabilityService,
 		eventService:         eventService,
+		settingsService:      settingsService,
 	}
 }
 
@@ -61,6 +95,190 @@ func (s *ImageService) GetImageByID(ctx context.Context, id string) (*image.Insp
 	return &inspect, nil
 }
 
+const (
+	baseImageSourceAnnotation = "annotation"
+	baseImageSourceHistory    = "history"
+	baseImageSourceLabels     = "labels"
+)
+
+// baseImageRelease is a release of a distribution commonly used as a base image.
+type baseImageRelease struct {
+	version  string
+	codename string
+}
+
+// knownBaseImageReleases lists the releases of common base image distributions,
+// oldest first. Only shipped releases are listed so an image is never pointed
+// at a release that is not out yet; ubuntu lists LTS releases only.
+var knownBaseImageReleases = map[string][]baseImageRelease{
+	"debian": {{"9", "stretch"}, {"10", "buster"}, {"11", "bullseye"}, {"12", "bookworm"}, {"13", "trixie"}},
+	"ubuntu": {{"18.04", "bionic"}, {"20.04", "focal"}, {"22.04", "jammy"}, {"24.04", "noble"}},
+	"alpine": {{"3.18", ""}, {"3.19", ""}, {"3.20", ""}, {"3.21", ""}, {"3.22", ""}},
+}
+
+var (
+	// debianHistoryRe matches the rootfs step of the official debian images,
+	// e.g. "# debian.sh --arch 'amd64' out/ 'bookworm' '@1717372800'".
+	debianHistoryRe = regexp.MustCompile(`debian\.sh --arch '[^']*' out/ '([a-z]+)'`)
+	// alpineHistoryRe matches the rootfs step of the official alpine images,
+	// e.g. "ADD alpine-minirootfs-3.20.0-x86_64.tar.gz / # buildkit".
+	alpineHistoryRe = regexp.MustCompile(`alpine-minirootfs-(\d+\.\d+)`)
+	ubuntuVersionRe = regexp.MustCompile(`^\d{2}\.\d{2}$`)
+)
+
+// DetectBaseImage identifies the base image of an image and reports whether a
+// newer release of its distribution exists. It returns nil when the base image
+// cannot be determined.
+func (s *ImageService) DetectBaseImage(ctx context.Context, inspect *image.InspectResponse) *imagetypes.BaseImage {
+	var labels map[string]string
+	if inspect.Config != nil {
+		labels = inspect.Config.Labels
+	}
+
+	var history []image.HistoryResponseItem
+	if labels["org.opencontainers.image.base.name"] == "" {
+		dockerClient, err := s.dockerService.GetClient()
+		if err == nil {
+			history, err = dockerClient.ImageHistory(ctx, inspect.ID)
+		}
+		if err != nil {
+			slog.DebugContext(ctx, "Could not read image history for base image detection", "image", inspect.ID, "error", err)
+		}
+	}
+
+	return detectBaseImageInternal(labels, history)
+}
+
+// detectBaseImageInternal prefers the OCI base image annotation, then the
+// rootfs step of the layer history, then the labels ubuntu images carry.
+func detectBaseImageInternal(labels map[string]string, history []image.HistoryResponseItem) *imagetypes.BaseImage {
+	var base *imagetypes.BaseImage
+	if name := labels["org.opencontainers.image.base.name"]; name != "" {
+		base = &imagetypes.BaseImage{
+			Name:   name,
+			Digest: labels["org.opencontainers.image.base.digest"],
+			Source: baseImageSourceAnnotation,
+		}
+		base.Distribution, base.Release = baseImageReleaseFromRefInternal(name)
+	} else {
+		base = baseImageFromHistoryInternal(history)
+	}
+	if base == nil && strings.EqualFold(labels["org.opencontainers.image.ref.name"], "ubuntu") {
+		// Images built on ubuntu inherit these labels. A child image may set its
+		// own version, so only ubuntu-shaped versions are trusted.
+		if version := labels["org.opencontainers.image.version"]; ubuntuVersionRe.MatchString(version) {
+			base = &imagetypes.BaseImage{
+				Name:         "ubuntu:" + version,
+				Source:       baseImageSourceLabels,
+				Distribution: "ubuntu",
+				Release:      version,
+			}
+		}
+	}
+	if base == nil {
+		return nil
+	}
+
+	evaluateBaseImageInternal(base)
+	return base
+}
+
+// baseImageFromHistoryInternal walks the history from the oldest layer, since
+// the rootfs of the base image is the first layer of an image.
+func baseImageFromHistoryInternal(history []image.HistoryResponseItem) *imagetypes.BaseImage {
+	for i := len(history) - 1; i >= 0; i-- {
+		createdBy := history[i].CreatedBy
+		if m := debianHistoryRe.FindStringSubmatch(createdBy); m != nil {
+			return &imagetypes.BaseImage{Name: "debian:" + m[1], Source: baseImageSourceHistory, Distribution: "debian", Release: m[1]}
+		}
+		if m := alpineHistoryRe.FindStringSubmatch(createdBy); m != nil {
+			return &imagetypes.BaseImage{Name: "alpine:" + m[1], Source: baseImageSourceHistory, Distribution: "alpine", Release: m[1]}
+		}
+	}
+	return nil
+}
+
+// baseImageReleaseFromRefInternal returns the distribution and release of an
+// official distribution image reference such as docker.io/library/debian:12-slim.
+// Both are empty for other images and for floating tags like latest.
+func baseImageReleaseFromRefInternal(name string) (distribution, release string) {
+	repo, tag := splitBaseImageRefInternal(name)
+	repo = strings.TrimPrefix(strings.TrimPrefix(repo, "docker.io/"), "index.docker.io/")
+	repo = strings.TrimPrefix(repo, "library/")
+	if _, ok := knownBaseImageReleases[repo]; !ok || tag == "" {
+		return "", ""
+	}
+
+	release, _, _ = strings.Cut(tag, "-")
+	if arcRegistry.IsVersionTag(release) {
+		// debian releases are major versions, ubuntu and alpine major.minor.
+		parts := strings.Split(release, ".")
+		keep := 2
+		if repo == "debian" {
+			keep = 1
+		}
+		return repo, strings.Join(parts[:min(keep, len(parts))], ".")
+	}
+	for _, r := range knownBaseImageReleases[repo] {
+		if r.codename != "" && r.codename == release {
+			return repo, release
+		}
+	}
+	return repo, ""
+}
+
+// splitBaseImageRefInternal splits a reference into repository and tag,
+// dropping any digest.
+func splitBaseImageRefInternal(name string) (repo, tag string) {
+	name, _, _ = strings.Cut(name, "@")
+	if i := strings.LastIndex(name, ":"); i > strings.LastIndex(name, "/") {
+		return name[:i], name[i+1:]
+	}
+	return name, ""
+}
+
+// evaluateBaseImageInternal marks the base image outdated when its release is
+// older than the latest known release and fills in the recommended base image.
+// Releases missing from knownBaseImageReleases are compared by version only,
+// so unknown codenames and newer releases are never flagged.
+func evaluateBaseImageInternal(base *imagetypes.BaseImage) {
+	releases := knownBaseImageReleases[base.Distribution]
+	if len(releases) == 0 || base.Release == "" {
+		return
+	}
+	latest := releases[len(releases)-1]
+
+	byCodename := !arcRegistry.IsVersionTag(base.Release)
+	base.LatestRelease = latest.version
+	if byCodename {
+		base.LatestRelease = latest.codename
+	}
+
+	idx := slices.IndexFunc(releases, func(r baseImageRelease) bool {
+		return r.version == base.Release || r.codename == base.Release
+	})
+	switch {
+	case idx >= 0:
+		base.Outdated = idx < len(releases)-1
+	case !byCodename:
+		newer := arcRegistry.LatestVersionTag(base.Release, []string{latest.version})
+		base.Outdated = newer != "" && newer != base.Release
+	}
+	if !base.Outdated {
+		return
+	}
+
+	// Keep the repository and tag variant (e.g. -slim) of the current base,
+	// dropping snapshot dates such as bookworm-20240513.
+	repo, tag := splitBaseImageRefInternal(base.Name)
+	base.Recommendation = repo + ":" + base.LatestRelease
+	for _, part := range strings.Split(tag, "-")[1:] {
+		if strings.Trim(part, "0123456789") != "" {
+			base.Recommendation += "-" + part
+		}
+	}
+}
+
 func (s *ImageService) RemoveImage(ctx context.Context, id string, force bool, user models.User) error {
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
@@ -114,7 +332,17 @@ func (s *ImageService) RemoveImage(ctx context.Context, id string, force bool, u
 	return nil
 }
 
+// SetPolicyService makes image pulls subject to the local environment policy.
+func (s *ImageService) SetPolicyService(policyService *EnvironmentPolicyService) {
+	s.policyService = policyService
+}
+
 func (s *ImageService) PullImage(ctx context.Context, imageName string, progressWriter io.Writer, user models.User, externalCreds []containerregistry.Credential) error {
+	if err := s.policyService.CheckImage(ctx, imageName); err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeImageError, "image", "", imageName, user.ID, user.Username, "0", err, models.JSON{"action": "pull", "step": "policy"})
+		return err
+	}
+
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
 		s.eventService.LogErrorEvent(ctx, models.EventTypeImageError, "image", "", imageName, user.ID, user.Username, "0", err, models.JSON{"action": "pull"})
@@ -130,6 +358,20 @@ func (s *ImageService) PullImage(ctx context.Context, imageName string, progress
 	}
 
 	reader, err := dockerClient.ImagePull(ctx, imageName, pullOptions)
+	var mirrorRef string
+	var upstreamErr error
+	if err != nil && ctx.Err() == nil && isRegistryUnavailableErrorInternal(err) {
+		upstreamErr = err
+		var mirrorsTried []string
+		mirrorRef, reader, mirrorsTried = s.pullFromMirrorsInternal(ctx, dockerClient, imageName, externalCreds)
+		if reader == nil && len(mirrorsTried) > 0 {
+			s.eventService.LogErrorEvent(ctx, models.EventTypeImageError, "image", "", imageName, user.ID, user.Username, "0", err, models.JSON{"action": "pull", "mirrorsTried": mirrorsTried})
+			return fmt.Errorf("failed to initiate image pull for %s (mirrors tried: %s): %w", imageName, strings.Join(mirrorsTried, ", "), err)
+		}
+		if reader != nil {
+			err = nil
+		}
+	}
 	if err != nil {
 		slog.ErrorContext(ctx, "Docker ImagePull failed", "image", imageName, "hasAuth", pullOptions.RegistryAuth != "", "error", err.Error())
 		s.eventService.LogErrorEvent(ctx, models.EventTypeImageError, "image", "", imageName, user.ID, user.Username, "0", err, models.JSON{"action": "pull"})
@@ -171,32 +413,64 @@ func (s *ImageService) PullImage(ctx context.Context, imageName string, progress
 		"action":    "pull",
 		"imageName": imageName,
 	}
+	if mirrorRef != "" {
+		s.retagMirroredImageInternal(ctx, dockerClient, mirrorRef, imageName)
+		metadata["mirror"] = mirrorRef
+		metadata["fallbackReason"] = upstreamErr.Error()
+	}
 	if logErr := s.eventService.LogImageEvent(ctx, models.EventTypeImagePull, "", imageName, user.ID, user.Username, "0", metadata); logErr != nil {
 		slog.Warn("could not log image pull action", "err", logErr, "image", imageName)
 	}
+	s.vulnerabilityService.ScanPulledImage(ctx, imageName, user)
 
 	return nil
 }
 
-func (s *ImageService) LoadImageFromReader(ctx context.Context, reader io.Reader, fileName string, user models.User, maxSizeBytes int64) (*imagetypes.LoadResult, error) {
+// CheckScanGate returns ErrScanGateBlocked when the vulnerability scan gate
+// does not allow containers to be created from imageRef.
+func (s *ImageService) CheckScanGate(ctx context.Context, imageRef string, user models.User) error {
+	if s == nil {
+		return nil
+	}
+	return s.vulnerabilityService.CheckScanGate(ctx, imageRef, user)
+}
+
+// LoadImageFromReader imports the images in a tar archive, like `docker
+// load`. size is the archive size when known and is only used for progress
+// reporting; progress is broadcast on TransferProgressHub under transferID.
+func (s *ImageService) LoadImageFromReader(ctx context.Context, reader io.Reader, size int64, fileName, transferID string, user models.User, maxSizeBytes int64) (*imagetypes.LoadResult, error) {
+	if transferID == "" {
+		transferID = uuid.NewString()
+	}
+	progress := imagetypes.TransferProgress{TransferID: transferID, Operation: imagetypes.TransferOperationLoad, TotalBytes: size}
+	fail := func(n int64, err error) (*imagetypes.LoadResult, error) {
+		s.publishTransferProgressInternal(withTransferStatusInternal(progress, imagetypes.TransferStatusFailed, n, err))
+		return nil, err
+	}
+
 	// Wrap reader with size limit enforcement
 	limitedReader := io.LimitReader(reader, maxSizeBytes+1)
+	counter := newTransferReaderInternal(limitedReader, func(n int64) {
+		s.publishTransferProgressInternal(withTransferStatusInternal(progress, imagetypes.TransferStatusRunning, n, nil))
+	})
 
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
 		s.eventService.LogErrorEvent(ctx, models.EventTypeImageError, "image", "", fileName, user.ID, user.Username, "0", err, models.JSON{"action": "load"})
-		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+		return fail(0, fmt.Errorf("failed to connect to Docker: %w", err))
 	}
 
+	s.publishTransferProgressInternal(withTransferStatusInternal(progress, imagetypes.TransferStatusRunning, 0, nil))
+
 	// ImageLoad accepts a tar archive reader and optional load options
-	loadResp, err := dockerClient.ImageLoad(ctx, limitedReader)
+	loadResp, err := dockerClient.ImageLoad(ctx, counter)
 	if err != nil {
 		// Check if error is due to size limit being exceeded
 		if err.Error() == "unexpected EOF" || strings.Contains(err.Error(), "unexpected EOF") {
-			return nil, fmt.Errorf("file size exceeds maximum allowed size of %d MB", maxSizeBytes/(1024*1024))
+			return fail(counter.n, fmt.Errorf("file size exceeds maximum allowed size of %d MB", maxSizeBytes/(1024*1024)))
 		}
 		s.eventService.LogErrorEvent(ctx, models.EventTypeImageError, "image", "", fileName, user.ID, user.Username, "0", err, models.JSON{"action": "load", "file": fileName})
-		return nil, fmt.Errorf("failed to load image from tar: %w", err)
+		return fail(counter.n, fmt.Errorf("failed to load image from tar: %w", err))
 	}
 	defer loadResp.Body.Close()
 
@@ -204,15 +478,21 @@ func (s *ImageService) LoadImageFromReader(ctx context.Context, reader io.Reader
 	responseBytes, err := io.ReadAll(loadResp.Body)
 	if err != nil {
 		s.eventService.LogErrorEvent(ctx, models.EventTypeImageError, "image", "", fileName, user.ID, user.Username, "0", err, models.JSON{"action": "load", "file": fileName, "step": "read_response"})
-		return nil, fmt.Errorf("failed to read load response: %w", err)
+		return fail(counter.n, fmt.Errorf("failed to read load response: %w", err))
 	}
 
 	responseStr := string(responseBytes)
 	result.Stream = responseStr
+	result.Images = parseLoadedImagesInternal(responseStr)
+
+	progress.Images = result.Images
+	s.publishTransferProgressInternal(withTransferStatusInternal(progress, imagetypes.TransferStatusSucceeded, counter.n, nil))
 
 	metadata := models.JSON{
-		"action":   "load",
-		"fileName": fileName,
+		"action":     "load",
+		"fileName":   fileName,
+		"images":     result.Images,
+		"transferId": transferID,
 	}
 	if logErr := s.eventService.LogImageEvent(ctx, models.EventTypeImageLoad, "", fileName, user.ID, user.Username, "0", metadata); logErr != nil {
 		slog.Warn("could not log image load action", "err", logErr, "file", fileName)
@@ -239,6 +519,141 @@ func (s *ImageService) ImageExistsLocally(ctx context.Context, imageName string)
 	return false, fmt.Errorf("failed to inspect image %s: %w", imageName, err)
 }
 
+// registryUnavailableMarkers are fragments of the daemon's pull errors for
+// registry outages and rate limits. Only these are retried on a mirror; a
+// missing image or bad credentials would fail there too.
+var registryUnavailableMarkers = []string{
+	"toomanyrequests",
+	"too many requests",
+	"rate limit",
+	"unexpected http status: 5",
+	"bad gateway",
+	"service unavailable",
+	"gateway timeout",
+	"connection refused",
+	"connection reset",
+	"no such host",
+	"i/o timeout",
+	"tls handshake timeout",
+	"client.timeout exceeded",
+	"unexpected eof",
+}
+
+func isRegistryUnavailableErrorInternal(err error) bool {
+	if cerrdefs.IsUnavailable(err) {
+		return true
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range registryUnavailableMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// pullFromMirrorsInternal retries a failed pull against the mirrors configured
+// for the image's registry, in order. It returns the reference and stream of
+// the first mirror that accepted the pull, and every mirror reference tried.
+func (s *ImageService) pullFromMirrorsInternal(ctx context.Context, dockerClient *client.Client, imageName string, externalCreds []containerregistry.Credential) (string, io.ReadCloser, []string) {
+	if s.settingsService == nil {
+		return "", nil, nil
+	}
+	mirrorRefs := registryMirrorRefsInternal(imageName, s.settingsService.GetStringSetting(ctx, "registryMirrors", ""))
+
+	tried := make([]string, 0, len(mirrorRefs))
+	for _, mirrorRef := range mirrorRefs {
+		tried = append(tried, mirrorRef)
+		pullOptions, err := s.getPullOptionsWithAuth(ctx, mirrorRef, externalCreds)
+		if err != nil {
+			pullOptions = image.PullOptions{}
+		}
+		reader, err := dockerClient.ImagePull(ctx, mirrorRef, pullOptions)
+		if err != nil {
+			slog.WarnContext(ctx, "Registry mirror pull failed", "image", imageName, "mirror", mirrorRef, "error", err)
+			continue
+		}
+		slog.InfoContext(ctx, "Pulling image from registry mirror", "image", imageName, "mirror", mirrorRef)
+		return mirrorRef, reader, tried
+	}
+	return "", nil, tried
+}
+
+// registryMirrorRefsInternal rewrites imageName onto each mirror configured for
+// its registry, keeping the repository path. config holds registry=mirror
+// pairs separated by commas or newlines; a mirror may include a path prefix.
+func registryMirrorRefsInternal(imageName, config string) []string {
+	named, err := ref.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return nil
+	}
+	named = ref.TagNameOnly(named)
+	suffix := ref.Path(named)
+	if tagged, ok := named.(ref.NamedTagged); ok {
+		suffix += ":" + tagged.Tag()
+	}
+	if digested, ok := named.(ref.Digested); ok {
+		suffix += "@" + digested.Digest().String()
+	}
+
+	var refs []string
+	for _, entry := range strings.FieldsFunc(config, func(r rune) bool { return r == ',' || r == '\n' }) {
+		upstream, mirror, ok := strings.Cut(strings.TrimSpace(entry), "=")
+		if !ok || normalizeMirrorHostInternal(upstream) != ref.Domain(named) {
+			continue
+		}
+		if mirror = normalizeMirrorHostInternal(mirror); mirror != "" {
+			refs = append(refs, mirror+"/"+suffix)
+		}
+	}
+	return refs
+}
+
+func normalizeMirrorHostInternal(host string) string {
+	host = strings.TrimSpace(host)
+	host = strings.TrimPrefix(strings.TrimPrefix(host, "https://"), "http://")
+	host = strings.TrimSuffix(host, "/")
+	switch host {
+	case "index.docker.io", "registry-1.docker.io":
+		return "docker.io"
+	}
+	return host
+}
+
+// retagMirroredImageInternal tags an image pulled from a mirror with the
+// reference that was asked for and drops the mirror tag, so the image is found
+// under its usual name. Digest-only references cannot be tagged and keep the
+// mirror name.
+func (s *ImageService) retagMirroredImageInternal(ctx context.Context, dockerClient *client.Client, mirrorRef, imageName string) {
+	named, err := ref.ParseNormalizedNamed(imageName)
+	if err != nil {
+		return
+	}
+	if _, digestOnly := named.(ref.Digested); digestOnly {
+		if _, tagged := named.(ref.NamedTagged); !tagged {
+			slog.WarnContext(ctx, "Image pulled from mirror by digest keeps the mirror name", "image", imageName, "mirror", mirrorRef)
+			return
+		}
+	}
+	target, ok := ref.TagNameOnly(named).(ref.NamedTagged)
+	if !ok {
+		return
+	}
+	source := mirrorRef
+	if i := strings.LastIndex(source, "@"); i >= 0 {
+		// name:tag@digest was pulled; the local tag is name:tag.
+		source = source[:i]
+	}
+	targetRef := ref.FamiliarName(target) + ":" + target.Tag()
+	if err := dockerClient.ImageTag(ctx, source, targetRef); err != nil {
+		slog.WarnContext(ctx, "Failed to tag image pulled from mirror", "image", imageName, "mirror", mirrorRef, "error", err)
+		return
+	}
+	if _, err := dockerClient.ImageRemove(ctx, source, image.RemoveOptions{}); err != nil {
+		slog.DebugContext(ctx, "Failed to remove mirror tag", "mirror", source, "error", err)
+	}
+}
+
 func (s *ImageService) getPullOptionsWithAuth(ctx context.Context, imageRef string, externalCreds []containerregistry.Credential) (image.PullOptions, error) {
 	pullOptions := image.PullOptions{}
 
@@ -359,24 +774,34 @@ func (s *ImageService) normalizeRegistryURL(url string) string {
 	return result
 }
 
-func (s *ImageService) PruneImages(ctx context.Context, dangling bool) (*image.PruneReport, error) {
+// PruneImages removes unused images and records a prune report for user.
+func (s *ImageService) PruneImages(ctx context.Context, dangling bool, user models.User) (*image.PruneReport, error) {
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
 	}
 
-	filterArgs := filters.NewArgs()
-	if dangling {
-		filterArgs.Add("dangling", "true")
+	candidates := map[string]image.Summary{}
+	if images, err := dockerClient.ImageList(ctx, image.ListOptions{}); err == nil {
+		for _, img := range images {
+			candidates[img.ID] = img
+		}
 	} else {
-		filterArgs.Add("dangling", "false")
+		slog.WarnContext(ctx, "failed to list images before prune; sizes will be missing", "error", err)
 	}
 
-	report, err := dockerClient.ImagesPrune(ctx, filterArgs)
+	report, err := s.pruneUnpinnedImagesInternal(ctx, dockerClient, dangling)
 	if err != nil {
-		return nil, fmt.Errorf("failed to prune images: %w", err)
+		return nil, err
 	}
 
+	recordPruneReportInternal(ctx, s.db, &system.PruneAllResult{
+		Success:             true,
+		Items:               prunedImageItemsInternal(candidates, report),
+		SpaceReclaimed:      report.SpaceReclaimed,
+		ImageSpaceReclaimed: report.SpaceReclaimed,
+	}, models.PruneTriggerManual, user)
+
 	// Clean up database records for deleted images
 	if s.db != nil && len(report.ImagesDeleted) > 0 {
 		var idsToDelete []string
@@ -419,6 +844,258 @@ func (s *ImageService) PruneImages(ctx context.Context, dangling bool) (*image.P
 	return &report, nil
 }
 
+// pruneUnpinnedImagesInternal prunes unused images while leaving pinned ones
+// in place. Docker's prune API cannot exclude individual images, so when any
+// image is pinned the unused images are removed one by one instead.
+func (s *ImageService) pruneUnpinnedImagesInternal(ctx context.Context, dockerClient *client.Client, danglingOnly bool) (image.PruneReport, error) {
+	pinned, err := s.GetPinnedImageDigests(ctx)
+	if err != nil {
+		return image.PruneReport{}, err
+	}
+
+	if len(pinned) == 0 {
+		filterArgs := filters.NewArgs(filters.Arg("dangling", strconv.FormatBool(danglingOnly)))
+		report, err := dockerClient.ImagesPrune(ctx, filterArgs)
+		if err != nil {
+			return image.PruneReport{}, fmt.Errorf("failed to prune images: %w", err)
+		}
+		return report, nil
+	}
+
+	images, err := dockerClient.ImageList(ctx, image.ListOptions{})
+	if err != nil {
+		return image.PruneReport{}, fmt.Errorf("failed to list images: %w", err)
+	}
+	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return image.PruneReport{}, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	// Removed images share layers, so their sizes do not add up to the
+	// space freed. The daemon's layer usage before and after tells.
+	layersBefore, usageErr := imageLayersSizeInternal(ctx, dockerClient)
+
+	var report image.PruneReport
+	for _, img := range selectPrunableImages(images, buildInUseMap(containers), pinned, danglingOnly) {
+		refs := realRepoTags(img.RepoTags)
+		if len(refs) == 0 {
+			refs = []string{img.ID}
+		}
+
+		for _, r := range refs {
+			deleted, err := dockerClient.ImageRemove(ctx, r, image.RemoveOptions{PruneChildren: true})
+			if err != nil {
+				// Typically an image with dependent children; docker prune skips these too
+				slog.DebugContext(ctx, "skipping image during prune", "image", r, "error", err)
+				break
+			}
+			report.ImagesDeleted = append(report.ImagesDeleted, deleted...)
+		}
+	}
+
+	if usageErr == nil {
+		layersAfter, err := imageLayersSizeInternal(ctx, dockerClient)
+		if err == nil && layersAfter < layersBefore {
+			report.SpaceReclaimed = uint64(layersBefore - layersAfter)
+		} else if err != nil {
+			usageErr = err
+		}
+	}
+	if usageErr != nil {
+		slog.WarnContext(ctx, "failed to read image disk usage; reclaimed space is unknown", "error", usageErr)
+	}
+
+	slog.InfoContext(ctx, "pruned images excluding pinned images", "pinned", len(pinned), "images_deleted", len(report.ImagesDeleted))
+	return report, nil
+}
+
+// imageLayersSizeInternal returns the disk space used by image layers as
+// reported by the daemon.
+func imageLayersSizeInternal(ctx context.Context, dockerClient *client.Client) (int64, error) {
+	usage, err := dockerClient.DiskUsage(ctx, types.DiskUsageOptions{Types: []types.DiskUsageObject{types.ImageObject}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get disk usage: %w", err)
+	}
+	return usage.LayersSize, nil
+}
+
+// prunedImageItemsInternal lists the whole images in a prune report, named
+// and sized from the images listed before the prune.
+func prunedImageItemsInternal(candidates map[string]image.Summary, report image.PruneReport) []system.PrunedItem {
+	items := make([]system.PrunedItem, 0, len(report.ImagesDeleted))
+	for _, deleted := range report.ImagesDeleted {
+		// Deleted also lists removed layers; only whole images are reported
+		img, ok := candidates[deleted.Deleted]
+		if !ok {
+			continue
+		}
+		item := system.PrunedItem{Type: system.PrunedItemImage, ID: img.ID, Size: img.Size}
+		if len(img.RepoTags) > 0 && img.RepoTags[0] != "<none>:<none>" {
+			item.Name = img.RepoTags[0]
+		}
+		items = append(items, item)
+	}
+	return items
+}
+
+// selectPrunableImages returns the images a prune may remove: unused,
+// unpinned and, when danglingOnly is set, untagged.
+func selectPrunableImages(images []image.Summary, inUse map[string]bool, pinned map[string]struct{}, danglingOnly bool) []image.Summary {
+	out := make([]image.Summary, 0, len(images))
+	for _, img := range images {
+		if inUse[img.ID] {
+			continue
+		}
+		if _, ok := pinned[img.ID]; ok {
+			continue
+		}
+		if danglingOnly && len(realRepoTags(img.RepoTags)) > 0 {
+			continue
+		}
+		out = append(out, img)
+	}
+	return out
+}
+
+func realRepoTags(tags []string) []string {
+	out := make([]string, 0, len(tags))
+	for _, t := range tags {
+		if t != "" && t != "<none>:<none>" {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// PinImage protects an image from prune jobs and auto-update. The pin is
+// stored against the image ID (its content digest), so it stays with that
+// exact image rather than following a tag.
+func (s *ImageService) PinImage(ctx context.Context, id, reason string, user models.User) (*imagetypes.PinnedImage, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	inspect, err := dockerClient.ImageInspect(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrImageNotFound, err)
+	}
+
+	imageRef := inspect.ID
+	if tags := realRepoTags(inspect.RepoTags); len(tags) > 0 {
+		imageRef = tags[0]
+	}
+
+	var pin models.PinnedImage
+	err = s.db.WithContext(ctx).Where("digest = ?", inspect.ID).First(&pin).Error
+	switch {
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		pin = models.PinnedImage{Digest: inspect.ID, ImageRef: imageRef, PinnedBy: user.Username}
+		if reason != "" {
+			pin.Reason = &reason
+		}
+		if err := s.db.WithContext(ctx).Create(&pin).Error; err != nil {
+			return nil, fmt.Errorf("failed to pin image: %w", err)
+		}
+	case err != nil:
+		return nil, fmt.Errorf("failed to load image pin: %w", err)
+	default:
+		pin.ImageRef = imageRef
+		pin.Reason = nil
+		if reason != "" {
+			pin.Reason = &reason
+		}
+		if err := s.db.WithContext(ctx).Save(&pin).Error; err != nil {
+			return nil, fmt.Errorf("failed to update image pin: %w", err)
+		}
+	}
+
+	metadata := models.JSON{
+		"action":  "pin",
+		"imageId": inspect.ID,
+		"reason":  reason,
+	}
+	if logErr := s.eventService.LogImageEvent(ctx, models.EventTypeImagePin, inspect.ID, imageRef, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log image pin action", "image", imageRef, "error", logErr)
+	}
+
+	out := toPinnedImageDTO(pin)
+	return &out, nil
+}
+
+// UnpinImage removes the pin from an image. id may be an image ID or a
+// reference; pins for images that no longer exist can be removed by digest.
+func (s *ImageService) UnpinImage(ctx context.Context, id string, user models.User) error {
+	digest := id
+	if dockerClient, err := s.dockerService.GetClient(); err == nil {
+		if inspect, err := dockerClient.ImageInspect(ctx, id); err == nil {
+			digest = inspect.ID
+		}
+	}
+
+	var pin models.PinnedImage
+	if err := s.db.WithContext(ctx).Where("digest = ?", digest).First(&pin).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrImageNotPinned
+		}
+		return fmt.Errorf("failed to load image pin: %w", err)
+	}
+	if err := s.db.WithContext(ctx).Delete(&pin).Error; err != nil {
+		return fmt.Errorf("failed to unpin image: %w", err)
+	}
+
+	metadata := models.JSON{
+		"action":  "unpin",
+		"imageId": digest,
+	}
+	if logErr := s.eventService.LogImageEvent(ctx, models.EventTypeImageUnpin, digest, pin.ImageRef, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log image unpin action", "image", pin.ImageRef, "error", logErr)
+	}
+
+	return nil
+}
+
+// ListPinnedImages returns all pinned images, newest first.
+func (s *ImageService) ListPinnedImages(ctx context.Context) ([]imagetypes.PinnedImage, error) {
+	var pins []models.PinnedImage
+	if err := s.db.WithContext(ctx).Order("created_at DESC").Find(&pins).Error; err != nil {
+		return nil, fmt.Errorf("failed to list pinned images: %w", err)
+	}
+
+	out := make([]imagetypes.PinnedImage, 0, len(pins))
+	for _, p := range pins {
+		out = append(out, toPinnedImageDTO(p))
+	}
+	return out, nil
+}
+
+// GetPinnedImageDigests returns the set of pinned image IDs.
+func (s *ImageService) GetPinnedImageDigests(ctx context.Context) (map[string]struct{}, error) {
+	out := map[string]struct{}{}
+	if s == nil || s.db == nil {
+		return out, nil
+	}
+
+	var digests []string
+	if err := s.db.WithContext(ctx).Model(&models.PinnedImage{}).Pluck("digest", &digests).Error; err != nil {
+		return nil, fmt.Errorf("failed to load pinned images: %w", err)
+	}
+	for _, d := range digests {
+		out[d] = struct{}{}
+	}
+	return out, nil
+}
+
+func toPinnedImageDTO(p models.PinnedImage) imagetypes.PinnedImage {
+	return imagetypes.PinnedImage{
+		Digest:    p.Digest,
+		ImageRef:  p.ImageRef,
+		Reason:    stringPtrValue(p.Reason),
+		PinnedBy:  p.PinnedBy,
+		CreatedAt: p.CreatedAt,
+	}
+}
+
 // GetUpdateInfoByImageIDs returns a map of image ID to UpdateInfo for the given image IDs.
 // This is used by the container service to populate update info for containers.
 func (s *ImageService) GetUpdateInfoByImageIDs(ctx context.Context, imageIDs []string) (map[string]*imagetypes.UpdateInfo, error) {
@@ -449,6 +1126,7 @@ func (s *ImageService) ListImagesPaginated(ctx context.Context, params paginatio
 		dockerImages     []image.Summary
 		containers       []container.Summary
 		updateRecords    []models.ImageUpdateRecord
+		pinnedMap        map[string]struct{}
 		vulnerabilityMap map[string]*vulnerability.ScanSummary
 	)
 
@@ -482,6 +1160,13 @@ func (s *ImageService) ListImagesPaginated(ctx context.Context, params paginatio
 		return nil
 	})
 
+	// Fetch pinned images from DB
+	g.Go(func() error {
+		var err error
+		pinnedMap, err = s.GetPinnedImageDigests(groupCtx)
+		return err
+	})
+
 	if err := g.Wait(); err != nil {
 		return nil, pagination.Response{}, err
 	}
@@ -501,7 +1186,7 @@ func (s *ImageService) ListImagesPaginated(ctx context.Context, params paginatio
 	inUseMap := buildInUseMap(containers)
 	updateMap := buildUpdateMap(updateRecords)
 
-	items := mapDockerImagesToDTOs(dockerImages, inUseMap, updateMap, vulnerabilityMap)
+	items := mapDockerImagesToDTOs(dockerImages, inUseMap, updateMap, pinnedMap, vulnerabilityMap)
 
 	config := s.getImagePaginationConfig()
 
@@ -628,7 +1313,7 @@ func buildUpdateInfo(updateRecord *models.ImageUpdateRecord) *imagetypes.UpdateI
 	}
 }
 
-func mapDockerImagesToDTOs(dockerImages []image.Summary, inUseMap map[string]bool, updateMap map[string]*models.ImageUpdateRecord, vulnerabilityMap map[string]*vulnerability.ScanSummary) []imagetypes.Summary {
+func mapDockerImagesToDTOs(dockerImages []image.Summary, inUseMap map[string]bool, updateMap map[string]*models.ImageUpdateRecord, pinnedMap map[string]struct{}, vulnerabilityMap map[string]*vulnerability.ScanSummary) []imagetypes.Summary {
 	items := make([]imagetypes.Summary, 0, len(dockerImages))
 	for _, di := range dockerImages {
 		repo, tag := determineRepoAndTag(di)
@@ -646,6 +1331,10 @@ func mapDockerImagesToDTOs(dockerImages []image.Summary, inUseMap map[string]boo
 			InUse:       inUseMap[di.ID],
 		}
 
+		if _, pinned := pinnedMap[di.ID]; pinned {
+			imageDto.Pinned = true
+		}
+
 		if updateRecord, exists := updateMap[di.ID]; exists {
 			imageDto.UpdateInfo = buildUpdateInfo(updateRecord)
 		}
@@ -723,6 +1412,18 @@ func (s *ImageService) getImagePaginationConfig() pagination.Config[imagetypes.S
 					return 1
 				},
 			},
+			{
+				Key: "pinned",
+				Fn: func(a, b imagetypes.Summary) int {
+					if a.Pinned == b.Pinned {
+						return 0
+					}
+					if a.Pinned {
+						return -1
+					}
+					return 1
+				},
+			},
 		},
 		FilterAccessors: []pagination.FilterAccessor[imagetypes.Summary]{
 			{
@@ -737,6 +1438,18 @@ func (s *ImageService) getImagePaginationConfig() pagination.Config[imagetypes.S
 					return true
 				},
 			},
+			{
+				Key: "pinned",
+				Fn: func(i imagetypes.Summary, filterValue string) bool {
+					if filterValue == "true" {
+						return i.Pinned
+					}
+					if filterValue == "false" {
+						return !i.Pinned
+					}
+					return true
+				},
+			},
 			{
 				Key: "updates",
 				Fn: func(i imagetypes.Summary, filterValue string) bool {
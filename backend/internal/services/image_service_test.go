@@ -0,0 +1,242 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+func TestSelectPrunableImages(t *testing.T) {
+	images := []image.Summary{
+		{ID: "sha256:used", RepoTags: []string{"nginx:1.25"}},
+		{ID: "sha256:pinned", RepoTags: []string{"golden:1.0"}},
+		{ID: "sha256:tagged", RepoTags: []string{"redis:7"}},
+		{ID: "sha256:dangling", RepoTags: []string{"<none>:<none>"}},
+		{ID: "sha256:pinned-dangling"},
+	}
+	inUse := map[string]bool{"sha256:used": true}
+	pinned := map[string]struct{}{"sha256:pinned": {}, "sha256:pinned-dangling": {}}
+
+	ids := func(imgs []image.Summary) []string {
+		out := make([]string, 0, len(imgs))
+		for _, img := range imgs {
+			out = append(out, img.ID)
+		}
+		return out
+	}
+
+	assert.Equal(t, []string{"sha256:tagged", "sha256:dangling"}, ids(selectPrunableImages(images, inUse, pinned, false)))
+	assert.Equal(t, []string{"sha256:dangling"}, ids(selectPrunableImages(images, inUse, pinned, true)))
+}
+
+func TestImageService_PinnedImages(t *testing.T) {
+	ctx := context.Background()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.PinnedImage{}))
+	svc := &ImageService{db: &database.DB{DB: gdb}}
+
+	digests, err := svc.GetPinnedImageDigests(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, digests)
+
+	reason := "base image"
+	require.NoError(t, gdb.Create(&models.PinnedImage{Digest: "sha256:abc", ImageRef: "golden:1.0", Reason: &reason, PinnedBy: "alice"}).Error)
+
+	digests, err = svc.GetPinnedImageDigests(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, digests, "sha256:abc")
+
+	pins, err := svc.ListPinnedImages(ctx)
+	require.NoError(t, err)
+	require.Len(t, pins, 1)
+	assert.Equal(t, "golden:1.0", pins[0].ImageRef)
+	assert.Equal(t, "base image", pins[0].Reason)
+
+	var nilSvc *ImageService
+	digests, err = nilSvc.GetPinnedImageDigests(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, digests)
+}
+
+func TestDetectBaseImage(t *testing.T) {
+	t.Run("annotation", func(t *testing.T) {
+		base := detectBaseImageInternal(map[string]string{
+			"org.opencontainers.image.base.name":   "docker.io/library/debian:bullseye-20240513-slim",
+			"org.opencontainers.image.base.digest": "sha256:abc",
+		}, nil)
+		require.NotNil(t, base)
+		assert.Equal(t, "annotation", base.Source)
+		assert.Equal(t, "sha256:abc", base.Digest)
+		assert.Equal(t, "debian", base.Distribution)
+		assert.Equal(t, "bullseye", base.Release)
+		assert.True(t, base.Outdated)
+		assert.Equal(t, "trixie", base.LatestRelease)
+		assert.Equal(t, "docker.io/library/debian:trixie-slim", base.Recommendation)
+	})
+
+	t.Run("history", func(t *testing.T) {
+		history := []image.HistoryResponseItem{
+			{CreatedBy: "CMD [\"app\"]"},
+			{CreatedBy: "ADD alpine-minirootfs-3.19.1-x86_64.tar.gz / # buildkit"},
+		}
+		base := detectBaseImageInternal(nil, history)
+		require.NotNil(t, base)
+		assert.Equal(t, "alpine:3.19", base.Name)
+		assert.True(t, base.Outdated)
+		assert.Equal(t, "alpine:3.22", base.Recommendation)
+
+		history = []image.HistoryResponseItem{{CreatedBy: "# debian.sh --arch 'amd64' out/ 'trixie' '@1717372800'"}}
+		base = detectBaseImageInternal(nil, history)
+		require.NotNil(t, base)
+		assert.Equal(t, "debian:trixie", base.Name)
+		assert.False(t, base.Outdated)
+		assert.Empty(t, base.Recommendation)
+	})
+
+	t.Run("ubuntu labels", func(t *testing.T) {
+		base := detectBaseImageInternal(map[string]string{
+			"org.opencontainers.image.ref.name": "ubuntu",
+			"org.opencontainers.image.version":  "22.04",
+		}, nil)
+		require.NotNil(t, base)
+		assert.Equal(t, "labels", base.Source)
+		assert.True(t, base.Outdated)
+		assert.Equal(t, "ubuntu:24.04", base.Recommendation)
+
+		// A child image overriding the version label is not mistaken for ubuntu 1.2.
+		assert.Nil(t, detectBaseImageInternal(map[string]string{
+			"org.opencontainers.image.ref.name": "ubuntu",
+			"org.opencontainers.image.version":  "1.2.3",
+		}, nil))
+	})
+
+	t.Run("unknown and newer releases are not outdated", func(t *testing.T) {
+		for _, name := range []string{"ubuntu:26.04", "debian:forky", "debian:latest", "alpine:edge", "nginx:1.20"} {
+			base := detectBaseImageInternal(map[string]string{"org.opencontainers.image.base.name": name}, nil)
+			require.NotNil(t, base, name)
+			assert.False(t, base.Outdated, name)
+		}
+
+		base := detectBaseImageInternal(map[string]string{"org.opencontainers.image.base.name": "ubuntu:23.10"}, nil)
+		require.NotNil(t, base)
+		assert.True(t, base.Outdated)
+		assert.Equal(t, "ubuntu:24.04", base.Recommendation)
+	})
+
+	assert.Nil(t, detectBaseImageInternal(nil, []image.HistoryResponseItem{{CreatedBy: "ADD file:abc in /"}}))
+}
+
+func TestRegistryMirrorRefs(t *testing.T) {
+	config := "docker.io=mirror.gcr.io\n https://index.docker.io = https://cache.example.com/hub/ ,ghcr.io=ghcr-mirror.example.com\ninvalid"
+
+	assert.Equal(t, []string{
+		"mirror.gcr.io/library/nginx:latest",
+		"cache.example.com/hub/library/nginx:latest",
+	}, registryMirrorRefsInternal("nginx", config))
+	assert.Equal(t, []string{"ghcr-mirror.example.com/acme/app:1.2"}, registryMirrorRefsInternal("ghcr.io/acme/app:1.2", config))
+	assert.Equal(t,
+		"mirror.gcr.io/library/redis@sha256:"+strings.Repeat("a", 64),
+		registryMirrorRefsInternal("redis@sha256:"+strings.Repeat("a", 64), config)[0])
+	assert.Empty(t, registryMirrorRefsInternal("quay.io/acme/app:1", config))
+}
+
+func TestIsRegistryUnavailableError(t *testing.T) {
+	assert.True(t, isRegistryUnavailableErrorInternal(errors.New("toomanyrequests: You have reached your pull rate limit")))
+	assert.True(t, isRegistryUnavailableErrorInternal(errors.New("received unexpected HTTP status: 503 Service Unavailable")))
+	assert.True(t, isRegistryUnavailableErrorInternal(errors.New("dial tcp: lookup registry-1.docker.io: no such host")))
+	assert.False(t, isRegistryUnavailableErrorInternal(errors.New("manifest for nginx:nope not found: manifest unknown")))
+	assert.False(t, isRegistryUnavailableErrorInternal(errors.New("pull access denied for acme/private")))
+}
+
+func TestImageService_PullImageMirrorFallback(t *testing.T) {
+	ctx := context.Background()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.SettingVariable{}, &models.Event{}))
+	db := &database.DB{DB: gdb}
+	settingsService, err := NewSettingsService(ctx, db)
+	require.NoError(t, err)
+	require.NoError(t, settingsService.EnsureDefaultSettings(ctx))
+	require.NoError(t, settingsService.SetStringSetting(ctx, "registryMirrors", "docker.io=down.example.com\ndocker.io=mirror.example.com"))
+
+	var mu sync.Mutex
+	var pulls, tags, removes []string
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case path == "/images/create":
+			from := r.URL.Query().Get("fromImage")
+			pulls = append(pulls, from)
+			switch from {
+			case "docker.io/library/nginx":
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = io.WriteString(w, `{"message":"toomanyrequests: You have reached your pull rate limit"}`)
+			case "docker.io/acme/missing":
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = io.WriteString(w, `{"message":"manifest for acme/missing:1 not found: manifest unknown"}`)
+			case "down.example.com/library/nginx":
+				w.Header().Set("Content-Type", "application/json")
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = io.WriteString(w, `{"message":"dial tcp: lookup down.example.com: no such host"}`)
+			default:
+				_, _ = io.WriteString(w, `{"status":"Pull complete"}`)
+			}
+		case strings.HasSuffix(path, "/tag") && r.Method == http.MethodPost:
+			tags = append(tags, strings.TrimSuffix(strings.TrimPrefix(path, "/images/"), "/tag")+" -> "+r.URL.Query().Get("repo")+":"+r.URL.Query().Get("tag"))
+			w.WriteHeader(http.StatusCreated)
+		case strings.HasPrefix(path, "/images/") && r.Method == http.MethodDelete:
+			removes = append(removes, strings.TrimPrefix(path, "/images/"))
+			_, _ = io.WriteString(w, `[]`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	svc := &ImageService{
+		db:              db,
+		dockerService:   &DockerClientService{client: cli},
+		eventService:    NewEventService(db),
+		settingsService: settingsService,
+	}
+
+	require.NoError(t, svc.PullImage(ctx, "nginx:latest", io.Discard, systemUser, nil))
+	assert.Equal(t, []string{"docker.io/library/nginx", "down.example.com/library/nginx", "mirror.example.com/library/nginx"}, pulls)
+	assert.Equal(t, []string{"mirror.example.com/library/nginx:latest -> docker.io/library/nginx:latest"}, tags)
+	assert.Equal(t, []string{"mirror.example.com/library/nginx:latest"}, removes)
+
+	var event models.Event
+	require.NoError(t, gdb.Where("type = ?", models.EventTypeImagePull).First(&event).Error)
+	assert.Equal(t, "mirror.example.com/library/nginx:latest", event.Metadata["mirror"])
+	assert.Contains(t, event.Metadata["fallbackReason"], "toomanyrequests")
+
+	// A missing image is not retried on the mirrors, and without mirrors the
+	// upstream error is returned.
+	pulls = nil
+	require.Error(t, svc.PullImage(ctx, "acme/missing:1", io.Discard, systemUser, nil))
+	require.NoError(t, settingsService.SetStringSetting(ctx, "registryMirrors", ""))
+	require.Error(t, svc.PullImage(ctx, "nginx:latest", io.Discard, systemUser, nil))
+	assert.Equal(t, []string{"docker.io/acme/missing", "docker.io/library/nginx"}, pulls)
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/docker/docker/api/types/image"
+	ref "go.podman.io/image/v5/docker/reference"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	imagetypes "github.com/getarcaneapp/arcane/types/image"
+)
+
+// TagImage adds tag to the image id refers to, like `docker tag`. id may be
+// an image ID, a tag or a digest reference. A tag that already points at a
+// different image is only moved when force is set.
+func (s *ImageService) TagImage(ctx context.Context, id, tag string, force bool, user models.User) (*imagetypes.TagResult, error) {
+	normalized, err := normalizeImageTagInternal(tag)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImageTag, err)
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	inspect, err := dockerClient.ImageInspect(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrImageNotFound, err)
+	}
+
+	if existing, err := dockerClient.ImageInspect(ctx, normalized); err == nil {
+		if existing.ID == inspect.ID {
+			return &imagetypes.TagResult{ImageID: inspect.ID, Tag: normalized, RepoTags: realRepoTags(inspect.RepoTags)}, nil
+		}
+		if !force {
+			return nil, fmt.Errorf("%w: %s is %s", ErrImageTagConflict, normalized, existing.ID)
+		}
+	}
+
+	if err := dockerClient.ImageTag(ctx, inspect.ID, normalized); err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeImageError, "image", inspect.ID, normalized, user.ID, user.Username, "0", err, models.JSON{"action": "tag", "tag": normalized})
+		return nil, fmt.Errorf("failed to tag image: %w", err)
+	}
+
+	metadata := models.JSON{
+		"action":  "tag",
+		"imageId": inspect.ID,
+		"tag":     normalized,
+		"force":   force,
+	}
+	if logErr := s.eventService.LogImageEvent(ctx, models.EventTypeImageTag, inspect.ID, normalized, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log image tag action", "image", inspect.ID, "tag", normalized, "error", logErr)
+	}
+
+	return s.tagResultInternal(ctx, inspect.ID, normalized, append(realRepoTags(inspect.RepoTags), normalized)), nil
+}
+
+// UntagImage removes tag from the image id refers to. The image itself is
+// kept, so its only remaining tag cannot be removed; delete the image
+// instead.
+func (s *ImageService) UntagImage(ctx context.Context, id, tag string, user models.User) (*imagetypes.TagResult, error) {
+	normalized, err := normalizeImageTagInternal(tag)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidImageTag, err)
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	inspect, err := dockerClient.ImageInspect(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrImageNotFound, err)
+	}
+
+	tags := realRepoTags(inspect.RepoTags)
+	if !slices.Contains(tags, normalized) {
+		return nil, fmt.Errorf("%w: %s", ErrImageTagNotFound, normalized)
+	}
+	if len(tags) == 1 {
+		return nil, ErrImageLastTag
+	}
+
+	// With other tags still referencing the image, removing one by name
+	// only drops that reference.
+	if _, err := dockerClient.ImageRemove(ctx, normalized, image.RemoveOptions{}); err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeImageError, "image", inspect.ID, normalized, user.ID, user.Username, "0", err, models.JSON{"action": "untag", "tag": normalized})
+		return nil, fmt.Errorf("failed to remove tag: %w", err)
+	}
+
+	metadata := models.JSON{
+		"action":  "untag",
+		"imageId": inspect.ID,
+		"tag":     normalized,
+	}
+	if logErr := s.eventService.LogImageEvent(ctx, models.EventTypeImageUntag, inspect.ID, normalized, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log image untag action", "image", inspect.ID, "tag", normalized, "error", logErr)
+	}
+
+	remaining := slices.DeleteFunc(slices.Clone(tags), func(t string) bool { return t == normalized })
+	return s.tagResultInternal(ctx, inspect.ID, normalized, remaining), nil
+}
+
+// tagResultInternal re-reads the image's tags after a change, falling back
+// to the expected tags if the image cannot be inspected.
+func (s *ImageService) tagResultInternal(ctx context.Context, imageID, tag string, expected []string) *imagetypes.TagResult {
+	result := &imagetypes.TagResult{ImageID: imageID, Tag: tag, RepoTags: expected}
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return result
+	}
+	if inspect, err := dockerClient.ImageInspect(ctx, imageID); err == nil {
+		result.RepoTags = realRepoTags(inspect.RepoTags)
+	}
+	return result
+}
+
+// normalizeImageTagInternal validates a repository:tag reference and returns
+// it in its familiar form with the tag defaulted to "latest". Digest
+// references are rejected because a digest cannot be assigned to an image.
+func normalizeImageTagInternal(tag string) (string, error) {
+	named, err := ref.ParseNormalizedNamed(tag)
+	if err != nil {
+		return "", fmt.Errorf("invalid tag %q: %w", tag, err)
+	}
+	if _, ok := named.(ref.Digested); ok {
+		return "", fmt.Errorf("tag %q must not include a digest", tag)
+	}
+	return ref.FamiliarString(ref.TagNameOnly(named)), nil
+}
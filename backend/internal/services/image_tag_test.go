@@ -0,0 +1,156 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	ref "go.podman.io/image/v5/docker/reference"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+// fakeTagDocker keeps a tag -> image ID table and serves the image inspect,
+// tag and remove endpoints from it.
+type fakeTagDocker struct {
+	mu   sync.Mutex
+	tags map[string]string
+}
+
+func (f *fakeTagDocker) repoTags(id string) []string {
+	out := []string{}
+	for tag, imageID := range f.tags {
+		if imageID == id {
+			out = append(out, tag)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func (f *fakeTagDocker) resolve(name string) (string, bool) {
+	if strings.HasPrefix(name, "sha256:") {
+		for _, id := range f.tags {
+			if id == name {
+				return id, true
+			}
+		}
+		return "", false
+	}
+	id, ok := f.tags[name]
+	return id, ok
+}
+
+func (f *fakeTagDocker) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	path := r.URL.Path[strings.Index(r.URL.Path, "/images/")+len("/images/"):]
+	switch {
+	case r.Method == http.MethodGet && strings.HasSuffix(path, "/json"):
+		id, ok := f.resolve(strings.TrimSuffix(path, "/json"))
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"No such image"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]any{"Id": id, "RepoTags": f.repoTags(id)})
+	case r.Method == http.MethodPost && strings.HasSuffix(path, "/tag"):
+		id, ok := f.resolve(strings.TrimSuffix(path, "/tag"))
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		// The client sends the fully qualified repository name.
+		named, err := ref.ParseNormalizedNamed(r.URL.Query().Get("repo") + ":" + r.URL.Query().Get("tag"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		f.tags[ref.FamiliarString(named)] = id
+		w.WriteHeader(http.StatusCreated)
+	case r.Method == http.MethodDelete:
+		delete(f.tags, path)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`[{"Untagged":"` + path + `"}]`))
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func setupImageTagTest(t *testing.T, fake *fakeTagDocker) (*ImageService, *gorm.DB) {
+	t.Helper()
+	docker := httptest.NewServer(fake)
+	t.Cleanup(docker.Close)
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Event{}))
+	db := &database.DB{DB: gdb}
+	return &ImageService{db: db, dockerService: &DockerClientService{client: cli}, eventService: NewEventService(db)}, gdb
+}
+
+func TestImageService_TagImage(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeTagDocker{tags: map[string]string{"myapp:1.0": "sha256:aaa", "other:latest": "sha256:bbb"}}
+	svc, gdb := setupImageTagTest(t, fake)
+	user := models.User{Username: "admin"}
+
+	result, err := svc.TagImage(ctx, "myapp:1.0", "registry.example.com/team/myapp", false, user)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:aaa", result.ImageID)
+	assert.Equal(t, "registry.example.com/team/myapp:latest", result.Tag)
+	assert.Equal(t, []string{"myapp:1.0", "registry.example.com/team/myapp:latest"}, result.RepoTags)
+
+	var event models.Event
+	require.NoError(t, gdb.Where("type = ?", models.EventTypeImageTag).First(&event).Error)
+	assert.Equal(t, "registry.example.com/team/myapp:latest", event.Metadata["tag"])
+
+	_, err = svc.TagImage(ctx, "sha256:aaa", "other", false, user)
+	require.ErrorIs(t, err, ErrImageTagConflict)
+	assert.Equal(t, "sha256:bbb", fake.tags["other:latest"])
+
+	_, err = svc.TagImage(ctx, "sha256:aaa", "other", true, user)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:aaa", fake.tags["other:latest"])
+
+	for _, bad := range []string{"", "Bad Tag", "myapp@sha256:" + strings.Repeat("a", 64)} {
+		_, err = svc.TagImage(ctx, "sha256:aaa", bad, false, user)
+		require.ErrorIs(t, err, ErrInvalidImageTag, bad)
+	}
+
+	_, err = svc.TagImage(ctx, "missing:1.0", "myapp:2.0", false, user)
+	require.ErrorIs(t, err, ErrImageNotFound)
+}
+
+func TestImageService_UntagImage(t *testing.T) {
+	ctx := context.Background()
+	fake := &fakeTagDocker{tags: map[string]string{"myapp:1.0": "sha256:aaa", "myapp:latest": "sha256:aaa"}}
+	svc, _ := setupImageTagTest(t, fake)
+	user := models.User{Username: "admin"}
+
+	_, err := svc.UntagImage(ctx, "sha256:aaa", "myapp:2.0", user)
+	require.ErrorIs(t, err, ErrImageTagNotFound)
+
+	result, err := svc.UntagImage(ctx, "sha256:aaa", "myapp", user)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"myapp:1.0"}, result.RepoTags)
+
+	_, err = svc.UntagImage(ctx, "sha256:aaa", "myapp:1.0", user)
+	require.ErrorIs(t, err, ErrImageLastTag)
+	assert.Contains(t, fake.tags, "myapp:1.0")
+}
@@ -0,0 +1,218 @@
+package services
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/ws"
+	imagetypes "github.com/getarcaneapp/arcane/types/image"
+)
+
+const transferProgressInterval = time.Second
+
+var unsafeArchiveNameCharsRe = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// TransferProgressHub returns the hub on which image save and load progress
+// is broadcast, starting it on first use.
+func (s *ImageService) TransferProgressHub() *ws.Hub {
+	s.transferProgressOnce.Do(func() {
+		s.transferProgressHub = ws.NewHub(256)
+		go s.transferProgressHub.Run(context.Background())
+	})
+	return s.transferProgressHub
+}
+
+func (s *ImageService) publishTransferProgressInternal(p imagetypes.TransferProgress) {
+	hub := s.TransferProgressHub()
+	if hub.ClientCount() == 0 {
+		return
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		slog.Warn("failed to encode image transfer progress", "error", err)
+		return
+	}
+	hub.Broadcast(b)
+}
+
+// SaveImages exports the given images as a single tar archive that `docker
+// load` or LoadImageFromReader can import. It returns the archive stream and
+// a file name for it; the caller must close the stream. Exports whose
+// estimated size is above maxSizeBytes are refused; 0 means no limit.
+func (s *ImageService) SaveImages(ctx context.Context, req imagetypes.SaveRequest, user models.User, maxSizeBytes int64) (io.ReadCloser, string, error) {
+	refs := make([]string, 0, len(req.Images))
+	for _, r := range req.Images {
+		if r = strings.TrimSpace(r); r != "" {
+			refs = append(refs, r)
+		}
+	}
+	if len(refs) == 0 {
+		return nil, "", fmt.Errorf("%w: at least one image is required", ErrInvalidImageTransfer)
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	// Layers shared between images are counted once per image, so the
+	// estimate errs on the large side.
+	var estimated int64
+	seen := make(map[string]struct{}, len(refs))
+	for _, r := range refs {
+		inspect, err := dockerClient.ImageInspect(ctx, r)
+		if err != nil {
+			return nil, "", fmt.Errorf("%w: %s: %w", ErrImageNotFound, r, err)
+		}
+		if _, ok := seen[inspect.ID]; !ok {
+			seen[inspect.ID] = struct{}{}
+			estimated += inspect.Size
+		}
+	}
+	if maxSizeBytes > 0 && estimated > maxSizeBytes {
+		return nil, "", fmt.Errorf("%w: the images are about %d MB, the limit is %d MB", ErrImageTransferTooLarge, estimated/(1024*1024), maxSizeBytes/(1024*1024))
+	}
+
+	transferID := strings.TrimSpace(req.TransferID)
+	if transferID == "" {
+		transferID = uuid.NewString()
+	}
+	metadata := models.JSON{
+		"action":     "save",
+		"images":     refs,
+		"transferId": transferID,
+	}
+	imageName := strings.Join(refs, ", ")
+
+	stream, err := dockerClient.ImageSave(ctx, refs)
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeImageError, "image", "", imageName, user.ID, user.Username, "0", err, metadata)
+		return nil, "", fmt.Errorf("failed to save images: %w", err)
+	}
+
+	if logErr := s.eventService.LogImageEvent(ctx, models.EventTypeImageSave, "", imageName, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log image save action", "images", refs, "error", logErr)
+	}
+
+	progress := imagetypes.TransferProgress{TransferID: transferID, Operation: imagetypes.TransferOperationSave, TotalBytes: estimated}
+	s.publishTransferProgressInternal(withTransferStatusInternal(progress, imagetypes.TransferStatusRunning, 0, nil))
+	return &transferStreamInternal{
+		transferReaderInternal: newTransferReaderInternal(stream, func(n int64) {
+			s.publishTransferProgressInternal(withTransferStatusInternal(progress, imagetypes.TransferStatusRunning, n, nil))
+		}),
+		closer: stream,
+		finish: func(n int64, err error) {
+			status := imagetypes.TransferStatusSucceeded
+			if err != nil {
+				status = imagetypes.TransferStatusFailed
+			}
+			s.publishTransferProgressInternal(withTransferStatusInternal(progress, status, n, err))
+		},
+	}, saveArchiveNameInternal(refs), nil
+}
+
+func withTransferStatusInternal(p imagetypes.TransferProgress, status imagetypes.TransferStatus, n int64, err error) imagetypes.TransferProgress {
+	p.Status = status
+	p.Bytes = n
+	if err != nil {
+		p.Error = err.Error()
+	}
+	return p
+}
+
+// saveArchiveNameInternal names the archive after the image when a single
+// image is exported.
+func saveArchiveNameInternal(refs []string) string {
+	if len(refs) != 1 {
+		return "images-" + time.Now().UTC().Format("20060102-150405") + ".tar"
+	}
+	name := strings.TrimPrefix(refs[0], "sha256:")
+	name = strings.Trim(unsafeArchiveNameCharsRe.ReplaceAllString(name, "_"), "_.")
+	if name == "" {
+		name = "image"
+	}
+	return name + ".tar"
+}
+
+// parseLoadedImagesInternal picks the loaded image names out of the output
+// of an image load.
+func parseLoadedImagesInternal(output string) []string {
+	var images []string
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		var msg struct {
+			Stream string `json:"stream"`
+		}
+		if json.Unmarshal([]byte(line), &msg) == nil && msg.Stream != "" {
+			line = msg.Stream
+		}
+		for _, prefix := range []string{"Loaded image: ", "Loaded image ID: "} {
+			if name, ok := strings.CutPrefix(strings.TrimSpace(line), prefix); ok {
+				images = append(images, strings.TrimSpace(name))
+			}
+		}
+	}
+	return images
+}
+
+// transferReaderInternal counts the bytes read through it and reports them
+// at most once per transferProgressInterval.
+type transferReaderInternal struct {
+	r          io.Reader
+	n          int64
+	lastReport time.Time
+	report     func(n int64)
+}
+
+func newTransferReaderInternal(r io.Reader, report func(n int64)) *transferReaderInternal {
+	return &transferReaderInternal{r: r, lastReport: time.Now(), report: report}
+}
+
+func (t *transferReaderInternal) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	t.n += int64(n)
+	if time.Since(t.lastReport) >= transferProgressInterval {
+		t.lastReport = time.Now()
+		t.report(t.n)
+	}
+	return n, err
+}
+
+// transferStreamInternal reports the outcome of a streamed export once: it
+// succeeded if the archive was read to the end and failed otherwise.
+type transferStreamInternal struct {
+	*transferReaderInternal
+	closer io.Closer
+	finish func(n int64, err error)
+	once   sync.Once
+}
+
+func (t *transferStreamInternal) Read(p []byte) (int, error) {
+	n, err := t.transferReaderInternal.Read(p)
+	switch {
+	case errors.Is(err, io.EOF):
+		t.once.Do(func() { t.finish(t.n, nil) })
+	case err != nil:
+		t.once.Do(func() { t.finish(t.n, err) })
+	}
+	return n, err
+}
+
+func (t *transferStreamInternal) Close() error {
+	t.once.Do(func() { t.finish(t.n, errors.New("export was interrupted")) })
+	return t.closer.Close()
+}
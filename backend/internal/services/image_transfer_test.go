@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	imagetypes "github.com/getarcaneapp/arcane/types/image"
+)
+
+func TestSaveArchiveName(t *testing.T) {
+	assert.Equal(t, "registry.example.com_team_app_1.0.tar", saveArchiveNameInternal([]string{"registry.example.com/team/app:1.0"}))
+	assert.Equal(t, "abc123.tar", saveArchiveNameInternal([]string{"sha256:abc123"}))
+	assert.True(t, strings.HasPrefix(saveArchiveNameInternal([]string{"a", "b"}), "images-"))
+}
+
+func TestParseLoadedImages(t *testing.T) {
+	output := `{"stream":"Loaded image: myapp:1.0\n"}` + "\n" +
+		`{"stream":"Loaded image ID: sha256:abc\n"}` + "\n" +
+		`{"status":"Loading layer","progressDetail":{"current":1,"total":2}}` + "\n"
+	assert.Equal(t, []string{"myapp:1.0", "sha256:abc"}, parseLoadedImagesInternal(output))
+	assert.Empty(t, parseLoadedImagesInternal(""))
+}
+
+func setupImageTransferTest(t *testing.T, handler http.HandlerFunc) (*ImageService, *gorm.DB) {
+	t.Helper()
+	docker := httptest.NewServer(handler)
+	t.Cleanup(docker.Close)
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Event{}))
+	db := &database.DB{DB: gdb}
+	return &ImageService{db: db, dockerService: &DockerClientService{client: cli}, eventService: NewEventService(db)}, gdb
+}
+
+func TestImageService_SaveImages(t *testing.T) {
+	ctx := context.Background()
+	var saved []string
+	svc, gdb := setupImageTransferTest(t, func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/json"):
+			name := strings.TrimSuffix(r.URL.Path[strings.Index(r.URL.Path, "/images/")+len("/images/"):], "/json")
+			if name == "missing:1.0" {
+				w.WriteHeader(http.StatusNotFound)
+				_, _ = io.WriteString(w, `{"message":"No such image"}`)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = fmt.Fprintf(w, `{"Id":"sha256:%s","Size":%d}`, name, 3*1024*1024)
+		case r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/images/get"):
+			saved = r.URL.Query()["names"]
+			_, _ = io.WriteString(w, "archive")
+		default:
+			http.NotFound(w, r)
+		}
+	})
+	user := models.User{Username: "admin"}
+
+	stream, name, err := svc.SaveImages(ctx, imagetypes.SaveRequest{Images: []string{"myapp:1.0", " "}}, user, 0)
+	require.NoError(t, err)
+	content, err := io.ReadAll(stream)
+	require.NoError(t, err)
+	require.NoError(t, stream.Close())
+	assert.Equal(t, "archive", string(content))
+	assert.Equal(t, "myapp_1.0.tar", name)
+	assert.Equal(t, []string{"myapp:1.0"}, saved)
+
+	var event models.Event
+	require.NoError(t, gdb.Where("type = ?", models.EventTypeImageSave).First(&event).Error)
+
+	_, _, err = svc.SaveImages(ctx, imagetypes.SaveRequest{Images: []string{"a:1", "b:1"}}, user, 5*1024*1024)
+	require.ErrorIs(t, err, ErrImageTransferTooLarge)
+	_, _, err = svc.SaveImages(ctx, imagetypes.SaveRequest{Images: []string{"missing:1.0"}}, user, 0)
+	require.ErrorIs(t, err, ErrImageNotFound)
+	_, _, err = svc.SaveImages(ctx, imagetypes.SaveRequest{}, user, 0)
+	require.ErrorIs(t, err, ErrInvalidImageTransfer)
+}
+
+func TestImageService_LoadImageFromReader(t *testing.T) {
+	ctx := context.Background()
+	var received string
+	svc, _ := setupImageTransferTest(t, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || !strings.HasSuffix(r.URL.Path, "/images/load") {
+			http.NotFound(w, r)
+			return
+		}
+		b, _ := io.ReadAll(r.Body)
+		received = string(b)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"stream":"Loaded image: myapp:1.0\n"}`+"\n")
+	})
+
+	result, err := svc.LoadImageFromReader(ctx, strings.NewReader("archive"), 7, "myapp.tar", "t1", models.User{Username: "admin"}, 1024)
+	require.NoError(t, err)
+	assert.Equal(t, "archive", received)
+	assert.Equal(t, []string{"myapp:1.0"}, result.Images)
+}
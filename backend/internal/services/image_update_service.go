@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"strings"
@@ -27,8 +28,29 @@ type ImageUpdateService struct {
 	dockerService       *DockerClientService
 	eventService        *EventService
 	notificationService *NotificationService
+
+	registryLimiterMu sync.Mutex
+	registryLimiter   *registry.Limiter
+
+	remoteDigestMu      sync.Mutex
+	remoteDigestCache   map[string]remoteDigestEntry
+	remoteDigestSweptAt time.Time
+}
+
+// remoteDigestEntry is a cached remote lookup for a repo+tag, including how
+// the registry was authenticated so cached results report it too.
+type remoteDigestEntry struct {
+	digest      string
+	publishedAt time.Time
+	latestTag   string
+	versionTags []string
+	backend     string
+	auth        authDetails
+	fetchedAt   time.Time
 }
 
+const defaultUpdateCheckCacheTTL = 15 * time.Minute
+
 type ImageParts struct {
 	Registry   string
 	Repository string
@@ -43,6 +65,7 @@ func NewImageUpdateService(db *database.DB, settingsService *SettingsService, re
 		dockerService:       dockerService,
 		eventService:        eventService,
 		notificationService: notificationService,
+		remoteDigestCache:   make(map[string]remoteDigestEntry),
 	}
 }
 
@@ -67,6 +90,7 @@ func (s *ImageUpdateService) CheckImageUpdate(ctx context.Context, imageRef stri
 			CheckTime:      time.Now(),
 			ResponseTimeMs: int(time.Since(startTime).Milliseconds()),
 		}
+		applyRateLimitInfo(result, err)
 		metadata := models.JSON{
 			"action":    "check_update",
 			"imageRef":  imageRef,
@@ -168,30 +192,75 @@ func (s *ImageUpdateService) getRegistryToken(ctx context.Context, regHost, repo
 	return "", nil, fmt.Errorf("failed to get registry token")
 }
 
-func (s *ImageUpdateService) checkDigestUpdate(ctx context.Context, parts *ImageParts, registries []models.ContainerRegistry) (*imageupdate.Response, error) {
-	rc := registry.NewClient()
+// ResolveRemoteDigest returns the digest the tag of imageRef currently
+// points to in its registry. Lookups share the cache and per-registry budget
+// of the update check.
+func (s *ImageUpdateService) ResolveRemoteDigest(ctx context.Context, imageRef string) (string, error) {
+	parts := s.parseImageReference(imageRef)
+	if parts == nil {
+		return "", fmt.Errorf("invalid image reference: %s", imageRef)
+	}
+	remote, _, _, err := s.resolveRemoteDigestInternal(ctx, parts, s.getRegistriesForImage(ctx, parts.Registry))
+	if err != nil {
+		return "", err
+	}
+	return remote.digest, nil
+}
 
-	token, auth, err := s.getRegistryToken(ctx, parts.Registry, parts.Repository, registries)
+// VersionTags returns the remote tags that are version-compatible with the
+// tag of imageRef. It is empty for registries without an update backend,
+// which cannot list tags.
+func (s *ImageUpdateService) VersionTags(ctx context.Context, imageRef string) ([]string, error) {
+	parts := s.parseImageReference(imageRef)
+	if parts == nil {
+		return nil, fmt.Errorf("invalid image reference: %s", imageRef)
+	}
+	remote, _, _, err := s.resolveRemoteDigestInternal(ctx, parts, s.getRegistriesForImage(ctx, parts.Registry))
 	if err != nil {
-		return nil, fmt.Errorf("failed to get registry token: %w", err)
+		return nil, err
 	}
+	return remote.versionTags, nil
+}
 
+// resolveRemoteDigestInternal looks up the remote digest of parts, from the
+// cache when possible, and reports how the registry was authenticated.
+func (s *ImageUpdateService) resolveRemoteDigestInternal(ctx context.Context, parts *ImageParts, registries []models.ContainerRegistry) (remoteDigestEntry, *authDetails, bool, error) {
 	normalizedRepo := s.normalizeRepository(parts.Registry, parts.Repository)
+	if remote, cached := s.getCachedRemoteDigestInternal(parts.Registry, normalizedRepo, parts.Tag); cached {
+		auth := remote.auth
+		return remote, &auth, true, nil
+	}
 
-	start := time.Now()
-	remoteDigest, _, err := rc.GetLatestDigestTimed(ctx, parts.Registry, normalizedRepo, parts.Tag, token)
+	token, tokenAuth, err := s.getRegistryToken(ctx, parts.Registry, parts.Repository, registries)
+	if err != nil {
+		return remoteDigestEntry{}, nil, false, fmt.Errorf("failed to get registry token: %w", err)
+	}
+
+	rc := registry.NewClient()
+	remote, err := s.fetchRemoteDigestInternal(ctx, rc, parts.Registry, normalizedRepo, parts.Tag, token, tokenAuth)
 	if err != nil && strings.Contains(strings.ToLower(err.Error()), "unauthorized") {
 		// Attempt to resolve auth header via registry helpers and retry once
 		enabledRegs, _ := s.registryService.GetEnabledRegistries(ctx)
-		authHeader, _, _, resolveErr := registry.ResolveAuthHeaderForRepository(ctx, parts.Registry, normalizedRepo, parts.Tag, enabledRegs)
+		authHeader, method, username, resolveErr := registry.ResolveAuthHeaderForRepository(ctx, parts.Registry, normalizedRepo, parts.Tag, enabledRegs)
 		if resolveErr == nil && authHeader != "" {
-			remoteDigest, _, err = rc.GetLatestDigestTimed(ctx, parts.Registry, normalizedRepo, parts.Tag, authHeader)
+			remote, err = s.fetchRemoteDigestInternal(ctx, rc, parts.Registry, normalizedRepo, parts.Tag, authHeader, &authDetails{Method: method, Username: username, Registry: parts.Registry})
 		}
 	}
-	elapsed := time.Since(start)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get remote digest: %w", err)
+		return remoteDigestEntry{}, nil, false, fmt.Errorf("failed to get remote digest: %w", err)
+	}
+	auth := remote.auth
+	return remote, &auth, false, nil
+}
+
+func (s *ImageUpdateService) checkDigestUpdate(ctx context.Context, parts *ImageParts, registries []models.ContainerRegistry) (*imageupdate.Response, error) {
+	start := time.Now()
+	remote, auth, cached, err := s.resolveRemoteDigestInternal(ctx, parts, registries)
+	if err != nil {
+		return nil, err
 	}
+	elapsed := time.Since(start)
+	remoteDigest := remote.digest
 
 	// Get local image and all its digests
 	localDigest, allLocalDigests, err := s.getLocalImageDigestWithAll(ctx, fmt.Sprintf("%s/%s:%s", parts.Registry, parts.Repository, parts.Tag))
@@ -226,9 +295,169 @@ func (s *ImageUpdateService) checkDigestUpdate(ctx context.Context, parts *Image
 		AuthUsername:   auth.Username,
 		AuthRegistry:   auth.Registry,
 		UsedCredential: auth.Method == "credential",
+		Cached:         cached,
+		LatestVersion:  remote.latestTag,
+		PublishedAt:    remote.publishedAtPtr(),
+		Backend:        remote.backend,
 	}, nil
 }
 
+// registryLimiterInternal returns the shared per-registry limiter, creating it
+// on first use and keeping its budget in sync with the current settings.
+func (s *ImageUpdateService) registryLimiterInternal() *registry.Limiter {
+	budget := 0
+	if s.settingsService != nil {
+		budget = s.settingsService.GetSettingsConfig().RegistryRequestBudget.AsInt()
+	}
+
+	s.registryLimiterMu.Lock()
+	defer s.registryLimiterMu.Unlock()
+	if s.registryLimiter == nil {
+		s.registryLimiter = registry.NewLimiter(budget)
+	} else {
+		s.registryLimiter.SetRate(budget)
+	}
+	return s.registryLimiter
+}
+
+func (s *ImageUpdateService) updateCheckCacheTTLInternal() time.Duration {
+	if s.settingsService == nil {
+		return defaultUpdateCheckCacheTTL
+	}
+	v := s.settingsService.GetSettingsConfig().UpdateCheckCacheTTL
+	if strings.TrimSpace(v.Value) == "" {
+		return defaultUpdateCheckCacheTTL
+	}
+	minutes := v.AsInt()
+	if minutes <= 0 {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func remoteDigestCacheKey(regHost, repository, tag string) string {
+	return regHost + "/" + repository + ":" + tag
+}
+
+// getCachedRemoteDigestInternal returns a cached remote digest if it is still
+// within the configured TTL.
+func (s *ImageUpdateService) getCachedRemoteDigestInternal(regHost, repository, tag string) (remoteDigestEntry, bool) {
+	ttl := s.updateCheckCacheTTLInternal()
+	if ttl <= 0 {
+		return remoteDigestEntry{}, false
+	}
+
+	s.remoteDigestMu.Lock()
+	defer s.remoteDigestMu.Unlock()
+	key := remoteDigestCacheKey(regHost, repository, tag)
+	entry, ok := s.remoteDigestCache[key]
+	if !ok {
+		return remoteDigestEntry{}, false
+	}
+	if time.Since(entry.fetchedAt) > ttl {
+		delete(s.remoteDigestCache, key)
+		return remoteDigestEntry{}, false
+	}
+	return entry, true
+}
+
+// storeRemoteDigestInternal caches entry and, at most once per TTL, drops
+// expired entries so images that are no longer checked do not pile up.
+func (s *ImageUpdateService) storeRemoteDigestInternal(regHost, repository, tag string, entry remoteDigestEntry) {
+	ttl := s.updateCheckCacheTTLInternal()
+
+	s.remoteDigestMu.Lock()
+	defer s.remoteDigestMu.Unlock()
+	if ttl <= 0 {
+		s.remoteDigestCache = nil
+		return
+	}
+	if s.remoteDigestCache == nil {
+		s.remoteDigestCache = make(map[string]remoteDigestEntry)
+	}
+	if now := time.Now(); now.Sub(s.remoteDigestSweptAt) > ttl {
+		s.remoteDigestSweptAt = now
+		for key, cached := range s.remoteDigestCache {
+			if now.Sub(cached.fetchedAt) > ttl {
+				delete(s.remoteDigestCache, key)
+			}
+		}
+	}
+	s.remoteDigestCache[remoteDigestCacheKey(regHost, repository, tag)] = entry
+}
+
+func (e remoteDigestEntry) publishedAtPtr() *time.Time {
+	if e.publishedAt.IsZero() {
+		return nil
+	}
+	t := e.publishedAt
+	return &t
+}
+
+// fetchRemoteDigestInternal resolves the remote digest for a repo+tag while
+// honouring the per-registry request budget and any active rate limit backoff.
+// Registries with a dedicated update backend are queried through it first so
+// publish dates and newer version tags can be reported; the generic v2 digest
+// check is used as the fallback. Successful lookups are cached together with
+// auth, the way token was obtained.
+func (s *ImageUpdateService) fetchRemoteDigestInternal(ctx context.Context, rc *registry.Client, regHost, repository, tag, token string, auth *authDetails) (remoteDigestEntry, error) {
+	limiter := s.registryLimiterInternal()
+	if err := limiter.Wait(ctx, regHost); err != nil {
+		return remoteDigestEntry{}, err
+	}
+
+	entry := remoteDigestEntry{backend: "registry", auth: authDetails{Registry: regHost}}
+	if auth != nil {
+		entry.auth = *auth
+	}
+	if backend := registry.BackendFor(regHost); backend != nil {
+		info, err := backend.Resolve(ctx, rc.WithLimiter(limiter), regHost, repository, tag, token)
+		var rlErr *registry.RateLimitedError
+		switch {
+		case errors.As(err, &rlErr):
+			// The backend has already put the registry into backoff.
+			slog.WarnContext(ctx, "Registry rate limit reached", "backend", backend.Name(), "registry", regHost, "until", rlErr.Until)
+			return remoteDigestEntry{}, err
+		case err != nil:
+			slog.DebugContext(ctx, "Update backend lookup failed, falling back to digest check", "backend", backend.Name(), "registry", regHost, "repository", repository, "tag", tag, "error", err.Error())
+		default:
+			entry.backend = backend.Name()
+			entry.digest = info.Digest
+			entry.publishedAt = info.PublishedAt
+			entry.latestTag = info.LatestTag
+			entry.versionTags = info.VersionTags
+		}
+	}
+
+	if entry.digest == "" {
+		digest, _, err := rc.GetLatestDigestTimed(ctx, regHost, repository, tag, token)
+		if err != nil {
+			var rlErr *registry.RateLimitedError
+			if errors.As(err, &rlErr) {
+				rlErr.Until = limiter.Backoff(regHost, time.Until(rlErr.Until))
+				slog.WarnContext(ctx, "Registry rate limit reached", "registry", regHost, "until", rlErr.Until)
+			}
+			return remoteDigestEntry{}, err
+		}
+		entry.digest = digest
+	}
+	limiter.Success(regHost)
+
+	entry.fetchedAt = time.Now()
+	s.storeRemoteDigestInternal(regHost, repository, tag, entry)
+
+	return entry, nil
+}
+
+// applyRateLimitInfo surfaces the rate limit expiry on a failed check result.
+func applyRateLimitInfo(result *imageupdate.Response, err error) {
+	var rlErr *registry.RateLimitedError
+	if errors.As(err, &rlErr) && !rlErr.Until.IsZero() {
+		until := rlErr.Until
+		result.RateLimitedUntil = &until
+	}
+}
+
 func (s *ImageUpdateService) parseImageReference(imageRef string) *ImageParts {
 	// Use the official Docker reference parser to handle all edge cases
 	named, err := ref.ParseNormalizedNamed(imageRef)
@@ -497,6 +726,73 @@ func (s *ImageUpdateService) CheckImageUpdateByID(ctx context.Context, imageID s
 	return result, nil
 }
 
+// CheckPushedImage re-checks the local images affected by a push that a
+// registry reported for pushedRef, so their update status does not wait for
+// the next poll. The cached remote digest of each affected tag is dropped
+// first. A reference without a tag re-checks every local tag of the
+// repository. It returns the references that were checked.
+func (s *ImageUpdateService) CheckPushedImage(ctx context.Context, pushedRef string) ([]string, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	images, err := dockerClient.ImageList(ctx, image.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Docker images: %w", err)
+	}
+
+	refs, err := matchPushedImageRefsInternal(pushedRef, images)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, imageRef := range refs {
+		if parts := s.parseImageReference(imageRef); parts != nil {
+			s.remoteDigestMu.Lock()
+			delete(s.remoteDigestCache, remoteDigestCacheKey(parts.Registry, s.normalizeRepository(parts.Registry, parts.Repository), parts.Tag))
+			s.remoteDigestMu.Unlock()
+		}
+		if _, err := s.CheckImageUpdate(ctx, imageRef); err != nil {
+			slog.WarnContext(ctx, "Failed to check pushed image for updates", "imageRef", imageRef, "error", err)
+		}
+	}
+	return refs, nil
+}
+
+// matchPushedImageRefsInternal returns the local image tags that refer to the
+// repository, and tag if given, of pushedRef.
+func matchPushedImageRefsInternal(pushedRef string, images []image.Summary) ([]string, error) {
+	pushed, err := ref.ParseNormalizedNamed(strings.TrimSpace(pushedRef))
+	if err != nil {
+		return nil, fmt.Errorf("invalid image reference %q: %w", pushedRef, err)
+	}
+	pushedTag := ""
+	if tagged, ok := pushed.(ref.NamedTagged); ok {
+		pushedTag = tagged.Tag()
+	}
+
+	var refs []string
+	seen := make(map[string]struct{})
+	for _, img := range images {
+		for _, repoTag := range img.RepoTags {
+			local, err := ref.ParseNormalizedNamed(repoTag)
+			if err != nil || local.Name() != pushed.Name() {
+				continue
+			}
+			tagged, ok := local.(ref.NamedTagged)
+			if !ok || (pushedTag != "" && tagged.Tag() != pushedTag) {
+				continue
+			}
+			if _, dup := seen[repoTag]; dup {
+				continue
+			}
+			seen[repoTag] = struct{}{}
+			refs = append(refs, repoTag)
+		}
+	}
+	return refs, nil
+}
+
 func (s *ImageUpdateService) saveUpdateResult(ctx context.Context, imageRef string, result *imageupdate.Response) error {
 	parts := s.parseImageReference(imageRef)
 	if parts == nil {
@@ -872,18 +1168,25 @@ func (s *ImageUpdateService) checkSingleImageInBatch(
 	auth := authInfo.auth
 	normalizedRepo := s.normalizeRepository(parts.Registry, parts.Repository)
 
-	remoteDigest, _, digestErr := rc.GetLatestDigestTimed(ctx, parts.Registry, normalizedRepo, parts.Tag, token)
+	var digestErr error
+	remote, cached := s.getCachedRemoteDigestInternal(parts.Registry, normalizedRepo, parts.Tag)
+	if cached {
+		auth = &remote.auth
+	} else {
+		remote, digestErr = s.fetchRemoteDigestInternal(ctx, rc, parts.Registry, normalizedRepo, parts.Tag, token, auth)
+	}
 	if digestErr != nil && strings.Contains(strings.ToLower(digestErr.Error()), "unauthorized") {
 		authHeader, method, username, resolveErr := registry.ResolveAuthHeaderForRepository(ctx, parts.Registry, normalizedRepo, parts.Tag, enabledRegs)
 		if resolveErr == nil && authHeader != "" {
-			remoteDigest, _, digestErr = rc.GetLatestDigestTimed(ctx, parts.Registry, normalizedRepo, parts.Tag, authHeader)
+			retryAuth := &authDetails{Method: method, Username: username, Registry: parts.Registry}
+			remote, digestErr = s.fetchRemoteDigestInternal(ctx, rc, parts.Registry, normalizedRepo, parts.Tag, authHeader, retryAuth)
 			if digestErr == nil {
-				auth = &authDetails{Method: method, Username: username, Registry: parts.Registry}
+				auth = retryAuth
 			}
 		}
 	}
 	if digestErr != nil {
-		return &imageupdate.Response{
+		result := &imageupdate.Response{
 			Error:          digestErr.Error(),
 			CheckTime:      time.Now(),
 			ResponseTimeMs: int(time.Since(start).Milliseconds()),
@@ -892,7 +1195,10 @@ func (s *ImageUpdateService) checkSingleImageInBatch(
 			AuthRegistry:   auth.Registry,
 			UsedCredential: auth.Method == "credential",
 		}
+		applyRateLimitInfo(result, digestErr)
+		return result
 	}
+	remoteDigest := remote.digest
 
 	localDigest, allLocalDigests, ldErr := s.getLocalImageDigestWithAll(ctx, fmt.Sprintf("%s/%s:%s", parts.Registry, parts.Repository, parts.Tag))
 	if ldErr != nil {
@@ -927,6 +1233,10 @@ func (s *ImageUpdateService) checkSingleImageInBatch(
 		AuthUsername:   auth.Username,
 		AuthRegistry:   auth.Registry,
 		UsedCredential: auth.Method == "credential",
+		Cached:         cached,
+		LatestVersion:  remote.latestTag,
+		PublishedAt:    remote.publishedAtPtr(),
+		Backend:        remote.backend,
 	}
 }
 
@@ -2,11 +2,14 @@ package services
 
 import (
 	"context"
+	"fmt"
 	"testing"
 	"time"
 
+	"github.com/docker/docker/api/types/image"
 	"github.com/getarcaneapp/arcane/backend/internal/database"
 	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/registry"
 	"github.com/getarcaneapp/arcane/types/imageupdate"
 	glsqlite "github.com/glebarez/sqlite"
 	"github.com/stretchr/testify/assert"
@@ -656,3 +659,79 @@ func TestImageUpdateService_MarkUpdatesAsNotified_EmptyList(t *testing.T) {
 	err = svc.MarkUpdatesAsNotified(ctx, nil)
 	require.NoError(t, err)
 }
+
+// TestImageUpdateService_RemoteDigestCache verifies cached digests are served
+// within the TTL and ignored once they expire.
+func TestImageUpdateService_RemoteDigestCache(t *testing.T) {
+	svc := &ImageUpdateService{}
+
+	_, ok := svc.getCachedRemoteDigestInternal("registry-1.docker.io", "library/redis", "latest")
+	assert.False(t, ok, "empty cache should miss")
+
+	svc.remoteDigestCache = map[string]remoteDigestEntry{
+		remoteDigestCacheKey("registry-1.docker.io", "library/redis", "latest"): {digest: "sha256:fresh", fetchedAt: time.Now()},
+		remoteDigestCacheKey("registry-1.docker.io", "library/redis", "7"):      {digest: "sha256:old", fetchedAt: time.Now().Add(-2 * defaultUpdateCheckCacheTTL)},
+	}
+
+	entry, ok := svc.getCachedRemoteDigestInternal("registry-1.docker.io", "library/redis", "latest")
+	require.True(t, ok)
+	assert.Equal(t, "sha256:fresh", entry.digest)
+
+	_, ok = svc.getCachedRemoteDigestInternal("registry-1.docker.io", "library/redis", "7")
+	assert.False(t, ok, "expired entry should miss")
+	assert.NotContains(t, svc.remoteDigestCache, remoteDigestCacheKey("registry-1.docker.io", "library/redis", "7"), "expired entry should be evicted")
+
+	// Storing sweeps out expired entries nobody asks for again and keeps the
+	// auth details for cached results.
+	svc.remoteDigestCache[remoteDigestCacheKey("ghcr.io", "org/app", "1")] = remoteDigestEntry{digest: "sha256:stale", fetchedAt: time.Now().Add(-time.Hour)}
+	svc.storeRemoteDigestInternal("ghcr.io", "org/app", "2", remoteDigestEntry{
+		digest:    "sha256:new",
+		auth:      authDetails{Method: "credential", Username: "bot", Registry: "ghcr.io"},
+		fetchedAt: time.Now(),
+	})
+	assert.NotContains(t, svc.remoteDigestCache, remoteDigestCacheKey("ghcr.io", "org/app", "1"))
+	entry, ok = svc.getCachedRemoteDigestInternal("ghcr.io", "org/app", "2")
+	require.True(t, ok)
+	assert.Equal(t, "credential", entry.auth.Method)
+	assert.Equal(t, "bot", entry.auth.Username)
+}
+
+// TestApplyRateLimitInfo verifies rate limit expiry is surfaced on check results.
+func TestApplyRateLimitInfo(t *testing.T) {
+	until := time.Now().Add(time.Hour)
+	result := &imageupdate.Response{}
+	applyRateLimitInfo(result, fmt.Errorf("failed to get remote digest: %w", &registry.RateLimitedError{Registry: "docker.io", Until: until}))
+	require.NotNil(t, result.RateLimitedUntil)
+	assert.True(t, result.RateLimitedUntil.Equal(until))
+
+	other := &imageupdate.Response{}
+	applyRateLimitInfo(other, fmt.Errorf("boom"))
+	assert.Nil(t, other.RateLimitedUntil)
+}
+
+func TestMatchPushedImageRefs(t *testing.T) {
+	images := []image.Summary{
+		{ID: "sha256:a", RepoTags: []string{"ghcr.io/acme/shop:latest", "ghcr.io/acme/shop:1.2"}},
+		{ID: "sha256:b", RepoTags: []string{"ghcr.io/acme/shop:1.1", "<none>:<none>"}},
+		{ID: "sha256:c", RepoTags: []string{"ghcr.io/acme/shop-api:latest", "nginx:1.27"}},
+	}
+
+	refs, err := matchPushedImageRefsInternal("ghcr.io/acme/shop:latest", images)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ghcr.io/acme/shop:latest"}, refs)
+
+	refs, err = matchPushedImageRefsInternal("ghcr.io/acme/shop@sha256:0123456789012345678901234567890123456789012345678901234567890123", images)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ghcr.io/acme/shop:latest", "ghcr.io/acme/shop:1.2", "ghcr.io/acme/shop:1.1"}, refs)
+
+	refs, err = matchPushedImageRefsInternal("docker.io/library/nginx:1.27", images)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"nginx:1.27"}, refs)
+
+	refs, err = matchPushedImageRefsInternal("registry.local/other:1", images)
+	require.NoError(t, err)
+	assert.Empty(t, refs)
+
+	_, err = matchPushedImageRefsInternal("Not A Reference", images)
+	require.Error(t, err)
+}
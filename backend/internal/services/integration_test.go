@@ -0,0 +1,140 @@
+//go:build integration
+
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/config"
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/pkg/dockertest"
+)
+
+// These tests run the services against a disposable Docker-in-Docker daemon:
+//
+//	go test -tags integration ./internal/services -run Integration
+
+type integrationServices struct {
+	volumes  *VolumeService
+	projects *ProjectService
+}
+
+func newIntegrationServicesInternal(t *testing.T, d *dockertest.Daemon) *integrationServices {
+	t.Helper()
+	ctx := context.Background()
+
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(
+		&models.SettingVariable{},
+		&models.Event{},
+		&models.OperationState{},
+		&models.VolumeBackup{},
+		&models.Project{},
+	))
+	db := &database.DB{DB: gdb}
+
+	settingsService, err := NewSettingsService(ctx, db)
+	require.NoError(t, err)
+	require.NoError(t, settingsService.UpdateSetting(ctx, "projectsDirectory", t.TempDir()))
+	require.NoError(t, settingsService.LoadDatabaseSettings(ctx))
+
+	// Compose builds its own client from DOCKER_HOST.
+	d.Setenv(t)
+
+	dockerService := NewDockerClientService(db, &config.Config{DockerHost: d.Host}, settingsService, nil)
+	eventService := NewEventService(db)
+	operationService := NewOperationService(db, eventService)
+	imageService := NewImageService(db, dockerService, nil, nil, nil, eventService, settingsService)
+	containerService := NewContainerService(db, eventService, dockerService, imageService, settingsService, nil)
+
+	return &integrationServices{
+		volumes:  NewVolumeService(db, dockerService, eventService, settingsService, containerService, imageService, operationService, nil, ""),
+		projects: NewProjectService(db, settingsService, eventService, imageService, dockerService, operationService, nil, nil),
+	}
+}
+
+func TestIntegration_VolumeBackupRestore(t *testing.T) {
+	d := dockertest.Start(t)
+	svcs := newIntegrationServicesInternal(t, d)
+	ctx := context.Background()
+	user := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "admin"}
+
+	d.CreateVolume(t, "app-data", map[string]string{
+		"config.yaml":    "mode: original\n",
+		"nested/data.db": "rows",
+	})
+	d.Run(t, dockertest.Container{
+		Name:    "app",
+		Image:   dockertest.HelperImage,
+		Cmd:     []string{"sleep", "3600"},
+		Volumes: map[string]string{"app-data": "/data"},
+	})
+
+	backup, err := svcs.volumes.CreateBackup(ctx, "app-data", user)
+	require.NoError(t, err)
+	assert.Positive(t, backup.Size)
+
+	d.CreateVolume(t, "app-data", map[string]string{"config.yaml": "mode: changed\n"})
+	require.Equal(t, "mode: changed\n", d.ReadVolumeFile(t, "app-data", "config.yaml"))
+
+	result, err := svcs.volumes.RestoreBackup(ctx, "app-data", backup.ID, true, user)
+	require.NoError(t, err)
+	assert.Equal(t, backup.ID, result.BackupID)
+
+	assert.Equal(t, "mode: original\n", d.ReadVolumeFile(t, "app-data", "config.yaml"))
+	assert.Equal(t, "rows", d.ReadVolumeFile(t, "app-data", "nested/data.db"))
+
+	inspect, err := d.Client.ContainerInspect(ctx, "app")
+	require.NoError(t, err)
+	assert.True(t, inspect.State.Running, "stopped containers are started again after the restore")
+}
+
+func TestIntegration_ProjectDeploy(t *testing.T) {
+	d := dockertest.Start(t)
+	svcs := newIntegrationServicesInternal(t, d)
+	ctx := context.Background()
+	user := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "admin"}
+
+	d.Pull(t, dockertest.HelperImage)
+	proj, err := svcs.projects.CreateProject(ctx, "web", `services:
+  app:
+    image: `+dockertest.HelperImage+`
+    command: ["sleep", "3600"]
+    volumes:
+      - data:/data
+volumes:
+  data:
+`, nil, user)
+	require.NoError(t, err)
+
+	require.NoError(t, svcs.projects.DeployProject(ctx, proj.ID, user))
+
+	running, err := d.Client.ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.project=web")),
+	})
+	require.NoError(t, err)
+	require.Len(t, running, 1)
+	assert.Equal(t, "running", running[0].State)
+
+	deployed, err := svcs.projects.GetProjectFromDatabaseByID(ctx, proj.ID)
+	require.NoError(t, err)
+	assert.Equal(t, models.ProjectStatusRunning, deployed.Status)
+
+	require.NoError(t, svcs.projects.DownProject(ctx, proj.ID, user))
+	running, err = d.Client.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", "com.docker.compose.project=web")),
+	})
+	require.NoError(t, err)
+	assert.Empty(t, running)
+}
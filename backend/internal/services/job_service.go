@@ -48,14 +48,16 @@ func (s *JobService) SetScheduler(scheduler JobRunner) {
 func (s *JobService) GetJobSchedules(ctx context.Context) jobschedule.Config {
 	// Use SettingsService cache for fast reads.
 	return jobschedule.Config{
-		EnvironmentHealthInterval:  s.settings.GetStringSetting(ctx, "environmentHealthInterval", "0 */2 * * * *"),
-		EventCleanupInterval:       s.settings.GetStringSetting(ctx, "eventCleanupInterval", "0 0 */6 * * *"),
-		AnalyticsHeartbeatInterval: s.settings.GetStringSetting(ctx, "analyticsHeartbeatInterval", "0 0 0 * * *"),
-		AutoUpdateInterval:         s.settings.GetStringSetting(ctx, "autoUpdateInterval", "0 0 0 * * *"),
-		PollingInterval:            s.settings.GetStringSetting(ctx, "pollingInterval", "0 */15 * * * *"),
-		ScheduledPruneInterval:     s.settings.GetStringSetting(ctx, "scheduledPruneInterval", "0 0 0 * * *"),
-		GitopsSyncInterval:         s.settings.GetStringSetting(ctx, "gitopsSyncInterval", "0 */5 * * * *"),
-		VulnerabilityScanInterval:  s.settings.GetStringSetting(ctx, "vulnerabilityScanInterval", "0 0 0 * * *"),
+		EnvironmentHealthInterval:   s.settings.GetStringSetting(ctx, "environmentHealthInterval", "0 */2 * * * *"),
+		EventCleanupInterval:        s.settings.GetStringSetting(ctx, "eventCleanupInterval", "0 0 */6 * * *"),
+		AnalyticsHeartbeatInterval:  s.settings.GetStringSetting(ctx, "analyticsHeartbeatInterval", "0 0 0 * * *"),
+		AutoUpdateInterval:          s.settings.GetStringSetting(ctx, "autoUpdateInterval", "0 0 0 * * *"),
+		PollingInterval:             s.settings.GetStringSetting(ctx, "pollingInterval", "0 */15 * * * *"),
+		ScheduledPruneInterval:      s.settings.GetStringSetting(ctx, "scheduledPruneInterval", "0 0 0 * * *"),
+		GitopsSyncInterval:          s.settings.GetStringSetting(ctx, "gitopsSyncInterval", "0 */5 * * * *"),
+		VulnerabilityScanInterval:   s.settings.GetStringSetting(ctx, "vulnerabilityScanInterval", "0 0 0 * * *"),
+		BootVerificationInterval:    s.settings.GetStringSetting(ctx, "bootVerificationInterval", "0 */5 * * * *"),
+		VulnerabilityDigestInterval: s.settings.GetStringSetting(ctx, "vulnerabilityDigestInterval", "0 0 8 * * 1"),
 	}
 }
 
@@ -82,6 +84,8 @@ func (s *JobService) UpdateJobSchedules(ctx context.Context, updates jobschedule
 		{key: "scheduledPruneInterval", current: current.ScheduledPruneInterval, update: updates.ScheduledPruneInterval},
 		{key: "gitopsSyncInterval", current: current.GitopsSyncInterval, update: updates.GitopsSyncInterval},
 		{key: "vulnerabilityScanInterval", current: current.VulnerabilityScanInterval, update: updates.VulnerabilityScanInterval},
+		{key: "bootVerificationInterval", current: current.BootVerificationInterval, update: updates.BootVerificationInterval},
+		{key: "vulnerabilityDigestInterval", current: current.VulnerabilityDigestInterval, update: updates.VulnerabilityDigestInterval},
 	}
 
 	// Validate inputs (cron expressions)
@@ -96,7 +100,7 @@ func (s *JobService) UpdateJobSchedules(ctx context.Context, updates jobschedule
 	}
 
 	changed := false
-	changedKeys := make([]string, 0, 7)
+	changedKeys := make([]string, 0, len(fields))
 	upsert := func(tx *gorm.DB, key string, v *string, currentVal string) error {
 		if v == nil {
 			return nil
@@ -166,13 +170,27 @@ func (s *JobService) ListJobs(ctx context.Context) (*jobschedule.JobListResponse
 	}, nil
 }
 
+type manualJobRunKey struct{}
+
+// WithManualJobRun marks ctx as a run a user started, which is not held to
+// the maintenance windows.
+func WithManualJobRun(ctx context.Context) context.Context {
+	return context.WithValue(ctx, manualJobRunKey{}, true)
+}
+
+// IsManualJobRun reports whether ctx belongs to a run a user started.
+func IsManualJobRun(ctx context.Context) bool {
+	manual, _ := ctx.Value(manualJobRunKey{}).(bool)
+	return manual
+}
+
 func (s *JobService) RunJobNowInline(ctx context.Context, jobID string) error {
 	job, err := s.getRunnableJobInternal(jobID)
 	if err != nil {
 		return err
 	}
 
-	runCtx := context.WithoutCancel(ctx)
+	runCtx := WithManualJobRun(context.WithoutCancel(ctx))
 	job.Run(runCtx)
 
 	return nil
@@ -214,14 +232,16 @@ func (s *JobService) getJobScheduleInternal(ctx context.Context, meta meta.JobMe
 	}
 
 	defaultSchedules := map[string]string{
-		"environmentHealthInterval":  "0 */2 * * * *",
-		"eventCleanupInterval":       "0 0 */6 * * *",
-		"analyticsHeartbeatInterval": "0 0 0 * * *",
-		"autoUpdateInterval":         "0 0 0 * * *",
-		"pollingInterval":            "0 */15 * * * *",
-		"scheduledPruneInterval":     "0 0 0 * * *",
-		"gitopsSyncInterval":         "0 */5 * * * *",
-		"vulnerabilityScanInterval":  "0 0 0 * * *",
+		"environmentHealthInterval":   "0 */2 * * * *",
+		"eventCleanupInterval":        "0 0 */6 * * *",
+		"analyticsHeartbeatInterval":  "0 0 0 * * *",
+		"autoUpdateInterval":          "0 0 0 * * *",
+		"pollingInterval":             "0 */15 * * * *",
+		"scheduledPruneInterval":      "0 0 0 * * *",
+		"gitopsSyncInterval":          "0 */5 * * * *",
+		"vulnerabilityScanInterval":   "0 0 0 * * *",
+		"bootVerificationInterval":    "0 */5 * * * *",
+		"vulnerabilityDigestInterval": "0 0 8 * * 1",
 	}
 
 	defaultSchedule := defaultSchedules[meta.SettingsKey]
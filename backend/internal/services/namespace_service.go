@@ -0,0 +1,713 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/docker"
+	"github.com/getarcaneapp/arcane/backend/pkg/libarcane"
+	namespacetypes "github.com/getarcaneapp/arcane/types/namespace"
+	"gorm.io/gorm"
+)
+
+var (
+	ErrNamespaceNotFound      = errors.New("namespace not found")
+	ErrNamespaceExists        = errors.New("namespace already exists")
+	ErrInvalidNamespace       = errors.New("invalid namespace")
+	ErrNamespaceForbidden     = errors.New("not a member of the namespace")
+	ErrNamespaceQuotaExceeded = errors.New("namespace quota exceeded")
+)
+
+// namespaceNamePattern keeps namespace names usable as label values.
+var namespaceNamePattern = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.-]{0,62}$`)
+
+const composeProjectLabel = "com.docker.compose.project"
+
+// NamespaceScope lists the namespaces whose resources the caller may see. A
+// nil scope is unrestricted; it is used for admins, agents and while no
+// namespaces exist, so installs that do not use namespaces are unaffected.
+type NamespaceScope struct {
+	namespaces []string
+}
+
+type namespaceScopeKey struct{}
+
+// WithNamespaceScope returns a context whose listings are filtered to scope.
+func WithNamespaceScope(ctx context.Context, scope *NamespaceScope) context.Context {
+	return context.WithValue(ctx, namespaceScopeKey{}, scope)
+}
+
+// NamespaceScopeFromContext returns the scope stored by WithNamespaceScope, or
+// nil when listings are unrestricted.
+func NamespaceScopeFromContext(ctx context.Context) *NamespaceScope {
+	scope, _ := ctx.Value(namespaceScopeKey{}).(*NamespaceScope)
+	return scope
+}
+
+// Allows reports whether resources in namespace are visible. Resources
+// without a namespace are only visible to unrestricted callers.
+func (s *NamespaceScope) Allows(namespace string) bool {
+	if s == nil {
+		return true
+	}
+	return namespace != "" && slices.Contains(s.namespaces, namespace)
+}
+
+// Namespaces returns the namespaces the scope allows, or nil when it is
+// unrestricted.
+func (s *NamespaceScope) Namespaces() []string {
+	if s == nil {
+		return nil
+	}
+	return s.namespaces
+}
+
+// NamespaceService manages namespaces, their members and their quotas.
+type NamespaceService struct {
+	db            *database.DB
+	dockerService *DockerClientService
+}
+
+func NewNamespaceService(db *database.DB, dockerService *DockerClientService) *NamespaceService {
+	return &NamespaceService{db: db, dockerService: dockerService}
+}
+
+// ScopeForUser returns the scope listings are filtered to for user.
+func (s *NamespaceService) ScopeForUser(ctx context.Context, userID string, isAdmin bool) (*NamespaceScope, error) {
+	if s == nil || s.db == nil || isAdmin {
+		return nil, nil
+	}
+
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.Namespace{}).Count(&count).Error; err != nil {
+		return nil, fmt.Errorf("failed to count namespaces: %w", err)
+	}
+	if count == 0 {
+		return nil, nil
+	}
+
+	var names []string
+	err := s.db.WithContext(ctx).Model(&models.Namespace{}).
+		Joins("JOIN namespace_members ON namespace_members.namespace_id = namespaces.id").
+		Where("namespace_members.user_id = ?", userID).
+		Order("namespaces.name ASC").
+		Pluck("namespaces.name", &names).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load namespace memberships: %w", err)
+	}
+	return &NamespaceScope{namespaces: names}, nil
+}
+
+// ListNamespaces returns every namespace, or only those the caller belongs to
+// when ctx carries a scope.
+func (s *NamespaceService) ListNamespaces(ctx context.Context) ([]namespacetypes.Namespace, error) {
+	query := s.db.WithContext(ctx).Order("name ASC")
+	if scope := NamespaceScopeFromContext(ctx); scope != nil {
+		query = query.Where("name IN ?", scope.Namespaces())
+	}
+
+	var namespaces []models.Namespace
+	if err := query.Find(&namespaces).Error; err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	out := make([]namespacetypes.Namespace, 0, len(namespaces))
+	for _, ns := range namespaces {
+		dto, err := s.toDTOInternal(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, dto)
+	}
+	return out, nil
+}
+
+// GetNamespace returns the namespace with id.
+func (s *NamespaceService) GetNamespace(ctx context.Context, id string) (*namespacetypes.Namespace, error) {
+	ns, err := s.getNamespaceInternal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	dto, err := s.toDTOInternal(ctx, *ns)
+	if err != nil {
+		return nil, err
+	}
+	return &dto, nil
+}
+
+// CreateNamespace creates a namespace. Its name is immutable because
+// resources reference it by label.
+func (s *NamespaceService) CreateNamespace(ctx context.Context, req namespacetypes.Create) (*namespacetypes.Namespace, error) {
+	name := strings.TrimSpace(req.Name)
+	if !namespaceNamePattern.MatchString(name) {
+		return nil, fmt.Errorf("%w: name must start with a letter or digit and contain only letters, digits, '_', '.' and '-'", ErrInvalidNamespace)
+	}
+	if req.MaxContainers < 0 || req.MaxMemoryMB < 0 || req.MaxVolumeGB < 0 {
+		return nil, fmt.Errorf("%w: quotas cannot be negative", ErrInvalidNamespace)
+	}
+
+	var existing int64
+	if err := s.db.WithContext(ctx).Model(&models.Namespace{}).Where("name = ?", name).Count(&existing).Error; err != nil {
+		return nil, fmt.Errorf("failed to check namespace: %w", err)
+	}
+	if existing > 0 {
+		return nil, fmt.Errorf("%w: %s", ErrNamespaceExists, name)
+	}
+
+	ns := models.Namespace{
+		Name:          name,
+		MaxContainers: req.MaxContainers,
+		MaxMemoryMB:   req.MaxMemoryMB,
+		MaxVolumeGB:   req.MaxVolumeGB,
+	}
+	if description := strings.TrimSpace(req.Description); description != "" {
+		ns.Description = &description
+	}
+	if err := s.db.WithContext(ctx).Create(&ns).Error; err != nil {
+		return nil, fmt.Errorf("failed to create namespace: %w", err)
+	}
+	return s.GetNamespace(ctx, ns.ID)
+}
+
+// UpdateNamespace changes the description or quotas of a namespace.
+func (s *NamespaceService) UpdateNamespace(ctx context.Context, id string, req namespacetypes.Update) (*namespacetypes.Namespace, error) {
+	ns, err := s.getNamespaceInternal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Description != nil {
+		ns.Description = nil
+		if description := strings.TrimSpace(*req.Description); description != "" {
+			ns.Description = &description
+		}
+	}
+	if req.MaxContainers != nil {
+		if *req.MaxContainers < 0 {
+			return nil, fmt.Errorf("%w: quotas cannot be negative", ErrInvalidNamespace)
+		}
+		ns.MaxContainers = *req.MaxContainers
+	}
+	if req.MaxMemoryMB != nil {
+		if *req.MaxMemoryMB < 0 {
+			return nil, fmt.Errorf("%w: quotas cannot be negative", ErrInvalidNamespace)
+		}
+		ns.MaxMemoryMB = *req.MaxMemoryMB
+	}
+	if req.MaxVolumeGB != nil {
+		if *req.MaxVolumeGB < 0 {
+			return nil, fmt.Errorf("%w: quotas cannot be negative", ErrInvalidNamespace)
+		}
+		ns.MaxVolumeGB = *req.MaxVolumeGB
+	}
+
+	if err := s.db.WithContext(ctx).Save(ns).Error; err != nil {
+		return nil, fmt.Errorf("failed to update namespace: %w", err)
+	}
+	return s.GetNamespace(ctx, ns.ID)
+}
+
+// DeleteNamespace removes a namespace and releases its projects. Containers
+// and volumes keep their label and become visible to admins only.
+func (s *NamespaceService) DeleteNamespace(ctx context.Context, id string) error {
+	ns, err := s.getNamespaceInternal(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	return s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Project{}).Where("namespace = ?", ns.Name).Update("namespace", nil).Error; err != nil {
+			return fmt.Errorf("failed to release namespace projects: %w", err)
+		}
+		if err := tx.Where("namespace_id = ?", ns.ID).Delete(&models.NamespaceMember{}).Error; err != nil {
+			return fmt.Errorf("failed to remove namespace members: %w", err)
+		}
+		if err := tx.Delete(&models.Namespace{}, "id = ?", ns.ID).Error; err != nil {
+			return fmt.Errorf("failed to delete namespace: %w", err)
+		}
+		return nil
+	})
+}
+
+// SetMembers replaces the users in a namespace.
+func (s *NamespaceService) SetMembers(ctx context.Context, id string, userIDs []string) (*namespacetypes.Namespace, error) {
+	ns, err := s.getNamespaceInternal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs = uniqueNonEmptyInternal(userIDs)
+	if len(userIDs) > 0 {
+		var found int64
+		if err := s.db.WithContext(ctx).Model(&models.User{}).Where("id IN ?", userIDs).Count(&found).Error; err != nil {
+			return nil, fmt.Errorf("failed to check users: %w", err)
+		}
+		if int(found) != len(userIDs) {
+			return nil, fmt.Errorf("%w: unknown user ID", ErrInvalidNamespace)
+		}
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("namespace_id = ?", ns.ID).Delete(&models.NamespaceMember{}).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, userID := range userIDs {
+			if err := tx.Create(&models.NamespaceMember{NamespaceID: ns.ID, UserID: userID, CreatedAt: now}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update namespace members: %w", err)
+	}
+	return s.GetNamespace(ctx, ns.ID)
+}
+
+// SetProjects replaces the projects owned by a namespace. Containers of those
+// compose projects belong to the namespace without needing the label.
+func (s *NamespaceService) SetProjects(ctx context.Context, id string, projectIDs []string) (*namespacetypes.Namespace, error) {
+	ns, err := s.getNamespaceInternal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	projectIDs = uniqueNonEmptyInternal(projectIDs)
+	if len(projectIDs) > 0 {
+		var found int64
+		if err := s.db.WithContext(ctx).Model(&models.Project{}).Where("id IN ?", projectIDs).Count(&found).Error; err != nil {
+			return nil, fmt.Errorf("failed to check projects: %w", err)
+		}
+		if int(found) != len(projectIDs) {
+			return nil, fmt.Errorf("%w: unknown project ID", ErrInvalidNamespace)
+		}
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Project{}).Where("namespace = ?", ns.Name).Update("namespace", nil).Error; err != nil {
+			return err
+		}
+		if len(projectIDs) == 0 {
+			return nil
+		}
+		return tx.Model(&models.Project{}).Where("id IN ?", projectIDs).Update("namespace", ns.Name).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update namespace projects: %w", err)
+	}
+	return s.GetNamespace(ctx, ns.ID)
+}
+
+// ResolveNamespace picks the namespace a new resource is created in.
+// Restricted callers must name a namespace they belong to and default to the
+// first of theirs; unrestricted callers may leave it empty.
+func (s *NamespaceService) ResolveNamespace(ctx context.Context, requested string) (string, error) {
+	requested = strings.TrimSpace(requested)
+	scope := NamespaceScopeFromContext(ctx)
+
+	if requested == "" {
+		if scope == nil {
+			return "", nil
+		}
+		if len(scope.namespaces) == 0 {
+			return "", fmt.Errorf("%w: you are not a member of any namespace", ErrNamespaceForbidden)
+		}
+		return scope.namespaces[0], nil
+	}
+
+	if !scope.Allows(requested) {
+		return "", fmt.Errorf("%w: %s", ErrNamespaceForbidden, requested)
+	}
+	if s == nil || s.db == nil {
+		return requested, nil
+	}
+	if _, err := s.getNamespaceByNameInternal(ctx, requested); err != nil {
+		return "", err
+	}
+	return requested, nil
+}
+
+// ProjectNamespaces maps compose project names to the namespace that owns
+// them.
+func (s *NamespaceService) ProjectNamespaces(ctx context.Context) map[string]string {
+	out := map[string]string{}
+	if s == nil || s.db == nil {
+		return out
+	}
+
+	var projects []models.Project
+	if err := s.db.WithContext(ctx).Select("name", "namespace").Where("namespace IS NOT NULL AND namespace <> ''").Find(&projects).Error; err != nil {
+		return out
+	}
+	for _, p := range projects {
+		out[p.Name] = *p.Namespace
+	}
+	return out
+}
+
+// ResourceNamespace returns the namespace of a container or volume: its
+// namespace label, or else the namespace of the compose project it belongs to.
+func ResourceNamespace(labels map[string]string, projectNamespaces map[string]string) string {
+	if ns := libarcane.NamespaceFromLabels(labels); ns != "" {
+		return ns
+	}
+	if project := labels[composeProjectLabel]; project != "" {
+		return projectNamespaces[project]
+	}
+	return ""
+}
+
+// filterByNamespaceInternal drops the items the scope in ctx does not allow.
+func filterByNamespaceInternal[T any](ctx context.Context, namespaceService *NamespaceService, items []T, labelsOf func(T) map[string]string) []T {
+	scope := NamespaceScopeFromContext(ctx)
+	if scope == nil {
+		return items
+	}
+
+	projectNamespaces := namespaceService.ProjectNamespaces(ctx)
+	filtered := make([]T, 0, len(items))
+	for _, item := range items {
+		if scope.Allows(ResourceNamespace(labelsOf(item), projectNamespaces)) {
+			filtered = append(filtered, item)
+		}
+	}
+	return filtered
+}
+
+// Kinds of resources that belong to a namespace.
+const (
+	NamespaceResourceContainer = "container"
+	NamespaceResourceVolume    = "volume"
+	NamespaceResourceProject   = "project"
+)
+
+// AuthorizeResource checks that the scope in ctx allows acting on a container,
+// volume or project by ID, so restricted callers cannot reach resources of
+// other namespaces that listings hide from them. Resources that do not exist
+// are let through for the caller to report as not found.
+func (s *NamespaceService) AuthorizeResource(ctx context.Context, kind, id string) error {
+	scope := NamespaceScopeFromContext(ctx)
+	if s == nil || scope == nil || id == "" {
+		return nil
+	}
+
+	namespace, found, err := s.resourceNamespaceInternal(ctx, kind, id)
+	if err != nil {
+		return err
+	}
+	if !found || scope.Allows(namespace) {
+		return nil
+	}
+	return fmt.Errorf("%w: %s %s", ErrNamespaceForbidden, kind, id)
+}
+
+func (s *NamespaceService) resourceNamespaceInternal(ctx context.Context, kind, id string) (string, bool, error) {
+	if kind == NamespaceResourceProject {
+		var project models.Project
+		err := s.db.WithContext(ctx).Select("id", "namespace").Where("id = ?", id).First(&project).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return "", false, nil
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("failed to load project: %w", err)
+		}
+		if project.Namespace == nil {
+			return "", true, nil
+		}
+		return *project.Namespace, true, nil
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	var labels map[string]string
+	switch kind {
+	case NamespaceResourceContainer:
+		inspect, err := dockerClient.ContainerInspect(ctx, id)
+		if cerrdefs.IsNotFound(err) {
+			return "", false, nil
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("failed to inspect container: %w", err)
+		}
+		if inspect.Config != nil {
+			labels = inspect.Config.Labels
+		}
+	case NamespaceResourceVolume:
+		vol, err := dockerClient.VolumeInspect(ctx, id)
+		if cerrdefs.IsNotFound(err) {
+			return "", false, nil
+		}
+		if err != nil {
+			return "", false, fmt.Errorf("failed to inspect volume: %w", err)
+		}
+		labels = vol.Labels
+	default:
+		return "", false, fmt.Errorf("unknown namespace resource kind %q", kind)
+	}
+	return ResourceNamespace(labels, s.ProjectNamespaces(ctx)), true, nil
+}
+
+// ContainerQuotaRequest describes containers about to be started in a
+// namespace.
+type ContainerQuotaRequest struct {
+	// Containers is the number of new containers.
+	Containers int
+	// MemoryBytes is the memory they reserve in total.
+	MemoryBytes int64
+	// Unreserved counts those that reserve no memory. They are refused when
+	// the namespace has a memory quota, since they could use any amount.
+	Unreserved int
+}
+
+// ContainerMemoryReservation returns the memory a container reserves: its
+// memory reservation, or else its hard memory limit.
+func ContainerMemoryReservation(hostConfig *container.HostConfig) int64 {
+	if hostConfig == nil {
+		return 0
+	}
+	if hostConfig.MemoryReservation > 0 {
+		return hostConfig.MemoryReservation
+	}
+	return max(hostConfig.Memory, 0)
+}
+
+// CheckContainerQuota returns ErrNamespaceQuotaExceeded when starting the
+// containers in req would take namespace over its container or memory quota.
+// Containers of excludeProject are not counted, so redeploying a project is
+// not blocked by its own containers.
+func (s *NamespaceService) CheckContainerQuota(ctx context.Context, namespace, excludeProject string, req ContainerQuotaRequest) error {
+	if s == nil || s.db == nil || namespace == "" {
+		return nil
+	}
+	ns, err := s.getNamespaceByNameInternal(ctx, namespace)
+	if err != nil || (ns.MaxContainers <= 0 && ns.MaxMemoryMB <= 0) {
+		return err
+	}
+	if ns.MaxMemoryMB > 0 && req.Unreserved > 0 {
+		return fmt.Errorf("%w: %s has a memory quota, so every container must set a memory reservation or limit", ErrNamespaceQuotaExceeded, namespace)
+	}
+
+	usage, err := s.containerUsageInternal(ctx, namespace, excludeProject, ns.MaxMemoryMB > 0)
+	if err != nil {
+		return err
+	}
+	if ns.MaxContainers > 0 && usage.containers+req.Containers > ns.MaxContainers {
+		return fmt.Errorf("%w: %s may run at most %d containers (has %d)", ErrNamespaceQuotaExceeded, namespace, ns.MaxContainers, usage.containers)
+	}
+	if limit := int64(ns.MaxMemoryMB) << 20; ns.MaxMemoryMB > 0 && usage.memoryBytes+req.MemoryBytes > limit {
+		return fmt.Errorf("%w: %s may reserve at most %d MB of memory (has %d MB, requested %d MB)", ErrNamespaceQuotaExceeded, namespace, ns.MaxMemoryMB, usage.memoryBytes>>20, req.MemoryBytes>>20)
+	}
+	return nil
+}
+
+// CheckVolumeQuota returns ErrNamespaceQuotaExceeded when the namespace's
+// volumes already use its whole volume quota.
+func (s *NamespaceService) CheckVolumeQuota(ctx context.Context, namespace string) error {
+	if s == nil || s.db == nil || namespace == "" {
+		return nil
+	}
+	ns, err := s.getNamespaceByNameInternal(ctx, namespace)
+	if err != nil || ns.MaxVolumeGB <= 0 {
+		return err
+	}
+
+	used, err := s.volumeBytesInternal(ctx, namespace)
+	if err != nil {
+		return err
+	}
+	if limit := int64(ns.MaxVolumeGB) << 30; used >= limit {
+		return fmt.Errorf("%w: %s volumes use %d of %d GB", ErrNamespaceQuotaExceeded, namespace, used>>30, ns.MaxVolumeGB)
+	}
+	return nil
+}
+
+// GetUsage reports the namespace's consumption against its quotas on the
+// local environment.
+func (s *NamespaceService) GetUsage(ctx context.Context, id string) (*namespacetypes.Usage, error) {
+	ns, err := s.getNamespaceInternal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	return s.usageInternal(ctx, *ns)
+}
+
+// ListUsage reports the consumption of every namespace visible to the
+// caller.
+func (s *NamespaceService) ListUsage(ctx context.Context) ([]namespacetypes.Usage, error) {
+	query := s.db.WithContext(ctx).Order("name ASC")
+	if scope := NamespaceScopeFromContext(ctx); scope != nil {
+		query = query.Where("name IN ?", scope.Namespaces())
+	}
+
+	var namespaces []models.Namespace
+	if err := query.Find(&namespaces).Error; err != nil {
+		return nil, fmt.Errorf("failed to list namespaces: %w", err)
+	}
+
+	out := make([]namespacetypes.Usage, 0, len(namespaces))
+	for _, ns := range namespaces {
+		usage, err := s.usageInternal(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *usage)
+	}
+	return out, nil
+}
+
+func (s *NamespaceService) usageInternal(ctx context.Context, ns models.Namespace) (*namespacetypes.Usage, error) {
+	containers, err := s.containerUsageInternal(ctx, ns.Name, "", true)
+	if err != nil {
+		return nil, err
+	}
+	volumeBytes, err := s.volumeBytesInternal(ctx, ns.Name)
+	if err != nil {
+		return nil, err
+	}
+	return &namespacetypes.Usage{
+		NamespaceID:   ns.ID,
+		Name:          ns.Name,
+		Containers:    containers.containers,
+		MaxContainers: ns.MaxContainers,
+		MemoryBytes:   containers.memoryBytes,
+		MaxMemoryMB:   ns.MaxMemoryMB,
+		VolumeBytes:   volumeBytes,
+		MaxVolumeGB:   ns.MaxVolumeGB,
+	}, nil
+}
+
+type namespaceContainerUsage struct {
+	containers  int
+	memoryBytes int64
+}
+
+// containerUsageInternal counts the namespace's containers and, when
+// withMemory is set, the memory its running containers reserve. The list
+// endpoint does not report memory settings, so that needs an inspect per
+// running container.
+func (s *NamespaceService) containerUsageInternal(ctx context.Context, namespace, excludeProject string, withMemory bool) (namespaceContainerUsage, error) {
+	var usage namespaceContainerUsage
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return usage, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return usage, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	projectNamespaces := s.ProjectNamespaces(ctx)
+	for _, c := range containers {
+		if libarcane.IsInternalContainer(c.Labels) {
+			continue
+		}
+		if excludeProject != "" && c.Labels[composeProjectLabel] == excludeProject {
+			continue
+		}
+		if ResourceNamespace(c.Labels, projectNamespaces) != namespace {
+			continue
+		}
+		usage.containers++
+
+		if !withMemory || c.State != container.StateRunning {
+			continue
+		}
+		inspect, err := dockerClient.ContainerInspect(ctx, c.ID)
+		if err != nil {
+			return usage, fmt.Errorf("failed to inspect container %s: %w", c.ID, err)
+		}
+		if inspect.ContainerJSONBase != nil {
+			usage.memoryBytes += ContainerMemoryReservation(inspect.HostConfig)
+		}
+	}
+	return usage, nil
+}
+
+func (s *NamespaceService) volumeBytesInternal(ctx context.Context, namespace string) (int64, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	volumes, err := docker.GetVolumeUsageData(ctx, dockerClient)
+	if err != nil {
+		return 0, err
+	}
+
+	projectNamespaces := s.ProjectNamespaces(ctx)
+	var total int64
+	for _, v := range volumes {
+		if ResourceNamespace(v.Labels, projectNamespaces) != namespace || v.UsageData == nil || v.UsageData.Size < 0 {
+			continue
+		}
+		total += v.UsageData.Size
+	}
+	return total, nil
+}
+
+func (s *NamespaceService) getNamespaceInternal(ctx context.Context, id string) (*models.Namespace, error) {
+	var ns models.Namespace
+	if err := s.db.WithContext(ctx).First(&ns, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNamespaceNotFound
+		}
+		return nil, fmt.Errorf("failed to get namespace: %w", err)
+	}
+	return &ns, nil
+}
+
+func (s *NamespaceService) getNamespaceByNameInternal(ctx context.Context, name string) (*models.Namespace, error) {
+	var ns models.Namespace
+	if err := s.db.WithContext(ctx).First(&ns, "name = ?", name).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, fmt.Errorf("%w: %s", ErrNamespaceNotFound, name)
+		}
+		return nil, fmt.Errorf("failed to get namespace: %w", err)
+	}
+	return &ns, nil
+}
+
+func (s *NamespaceService) toDTOInternal(ctx context.Context, ns models.Namespace) (namespacetypes.Namespace, error) {
+	dto := namespacetypes.Namespace{
+		ID:            ns.ID,
+		Name:          ns.Name,
+		Description:   stringPtrValue(ns.Description),
+		MaxContainers: ns.MaxContainers,
+		MaxMemoryMB:   ns.MaxMemoryMB,
+		MaxVolumeGB:   ns.MaxVolumeGB,
+		MemberIDs:     []string{},
+		Projects:      []string{},
+		CreatedAt:     ns.CreatedAt,
+		UpdatedAt:     ns.UpdatedAt,
+	}
+	if err := s.db.WithContext(ctx).Model(&models.NamespaceMember{}).Where("namespace_id = ?", ns.ID).Order("user_id ASC").Pluck("user_id", &dto.MemberIDs).Error; err != nil {
+		return dto, fmt.Errorf("failed to load namespace members: %w", err)
+	}
+	if err := s.db.WithContext(ctx).Model(&models.Project{}).Where("namespace = ?", ns.Name).Order("name ASC").Pluck("name", &dto.Projects).Error; err != nil {
+		return dto, fmt.Errorf("failed to load namespace projects: %w", err)
+	}
+	return dto, nil
+}
+
+func uniqueNonEmptyInternal(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		v = strings.TrimSpace(v)
+		if v != "" && !slices.Contains(out, v) {
+			out = append(out, v)
+		}
+	}
+	return out
+}
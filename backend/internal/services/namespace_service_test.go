@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/pkg/libarcane"
+	namespacetypes "github.com/getarcaneapp/arcane/types/namespace"
+)
+
+func setupNamespaceTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	db, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&models.Namespace{}, &models.NamespaceMember{}, &models.Project{}, &models.User{}))
+	return &database.DB{DB: db}
+}
+
+func TestNamespaceService_MembershipAndScope(t *testing.T) {
+	ctx := context.Background()
+	db := setupNamespaceTestDB(t)
+	svc := NewNamespaceService(db, nil)
+
+	// Without namespaces nobody is restricted.
+	scope, err := svc.ScopeForUser(ctx, "u1", false)
+	require.NoError(t, err)
+	assert.Nil(t, scope)
+
+	_, err = svc.CreateNamespace(ctx, namespacetypes.Create{Name: "bad name"})
+	require.ErrorIs(t, err, ErrInvalidNamespace)
+
+	team, err := svc.CreateNamespace(ctx, namespacetypes.Create{Name: "team-a", MaxContainers: 2})
+	require.NoError(t, err)
+	_, err = svc.CreateNamespace(ctx, namespacetypes.Create{Name: "team-a"})
+	require.ErrorIs(t, err, ErrNamespaceExists)
+	_, err = svc.CreateNamespace(ctx, namespacetypes.Create{Name: "team-b"})
+	require.NoError(t, err)
+
+	require.NoError(t, db.Create(&models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "alice"}).Error)
+	require.NoError(t, db.Create(&models.Project{BaseModel: models.BaseModel{ID: "p1"}, Name: "web", Path: "/p/web"}).Error)
+	require.NoError(t, db.Create(&models.Project{BaseModel: models.BaseModel{ID: "p2"}, Name: "db", Path: "/p/db"}).Error)
+
+	_, err = svc.SetMembers(ctx, team.ID, []string{"missing"})
+	require.ErrorIs(t, err, ErrInvalidNamespace)
+	team, err = svc.SetMembers(ctx, team.ID, []string{"u1", "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1"}, team.MemberIDs)
+	team, err = svc.SetProjects(ctx, team.ID, []string{"p1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web"}, team.Projects)
+
+	admin, err := svc.ScopeForUser(ctx, "u1", true)
+	require.NoError(t, err)
+	assert.Nil(t, admin)
+
+	scope, err = svc.ScopeForUser(ctx, "u1", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"team-a"}, scope.Namespaces())
+	assert.True(t, scope.Allows("team-a"))
+	assert.False(t, scope.Allows("team-b"))
+	assert.False(t, scope.Allows(""))
+
+	scoped := WithNamespaceScope(ctx, scope)
+	listed, err := svc.ListNamespaces(scoped)
+	require.NoError(t, err)
+	require.Len(t, listed, 1)
+	assert.Equal(t, "team-a", listed[0].Name)
+
+	ns, err := svc.ResolveNamespace(scoped, "")
+	require.NoError(t, err)
+	assert.Equal(t, "team-a", ns)
+	_, err = svc.ResolveNamespace(scoped, "team-b")
+	require.ErrorIs(t, err, ErrNamespaceForbidden)
+	_, err = svc.ResolveNamespace(ctx, "nope")
+	require.ErrorIs(t, err, ErrNamespaceNotFound)
+
+	containers := []container.Summary{
+		{ID: "1", Labels: map[string]string{libarcane.NamespaceLabel: "team-a"}},
+		{ID: "2", Labels: map[string]string{composeProjectLabel: "web"}},
+		{ID: "3", Labels: map[string]string{composeProjectLabel: "db"}},
+		{ID: "4", Labels: map[string]string{libarcane.NamespaceLabel: "team-b"}},
+	}
+	visible := filterByNamespaceInternal(scoped, svc, containers, func(c container.Summary) map[string]string { return c.Labels })
+	require.Len(t, visible, 2)
+	assert.Equal(t, "1", visible[0].ID)
+	assert.Equal(t, "2", visible[1].ID)
+	assert.Len(t, filterByNamespaceInternal(ctx, svc, containers, func(c container.Summary) map[string]string { return c.Labels }), 4)
+
+	require.NoError(t, svc.DeleteNamespace(ctx, team.ID))
+	var project models.Project
+	require.NoError(t, db.First(&project, "id = ?", "p1").Error)
+	assert.Nil(t, project.Namespace)
+	_, err = svc.GetNamespace(ctx, team.ID)
+	require.ErrorIs(t, err, ErrNamespaceNotFound)
+}
+
+func TestNamespaceService_CheckContainerQuota(t *testing.T) {
+	ctx := context.Background()
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/containers/json") {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `[
+				{"Id":"1","State":"running","Labels":{"com.getarcaneapp.namespace":"team-a"}},
+				{"Id":"2","State":"exited","Labels":{"com.docker.compose.project":"web"}},
+				{"Id":"3","Labels":{"com.getarcaneapp.namespace":"team-a","com.getarcaneapp.internal.container":"true"}},
+				{"Id":"4","Labels":{}}
+			]`)
+			return
+		}
+		if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/containers/1/json") {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"Id":"1","HostConfig":{"Memory":536870912}}`)
+			return
+		}
+		if r.Method == http.MethodGet && strings.HasSuffix(r.URL.Path, "/system/df") {
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `{"Volumes":[]}`)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	db := setupNamespaceTestDB(t)
+	svc := NewNamespaceService(db, &DockerClientService{client: cli})
+
+	team, err := svc.CreateNamespace(ctx, namespacetypes.Create{Name: "team-a", MaxContainers: 3, MaxMemoryMB: 1024})
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&models.Project{BaseModel: models.BaseModel{ID: "p1"}, Name: "web", Path: "/p/web"}).Error)
+	_, err = svc.SetProjects(ctx, team.ID, []string{"p1"})
+	require.NoError(t, err)
+
+	const mb = 1 << 20
+	require.NoError(t, svc.CheckContainerQuota(ctx, "team-a", "", ContainerQuotaRequest{Containers: 1, MemoryBytes: 512 * mb}))
+	require.ErrorIs(t, svc.CheckContainerQuota(ctx, "team-a", "", ContainerQuotaRequest{Containers: 2, MemoryBytes: 2 * mb}), ErrNamespaceQuotaExceeded)
+	// Redeploying web does not count its own containers.
+	require.NoError(t, svc.CheckContainerQuota(ctx, "team-a", "web", ContainerQuotaRequest{Containers: 2, MemoryBytes: 2 * mb}))
+	require.NoError(t, svc.CheckContainerQuota(ctx, "", "", ContainerQuotaRequest{Containers: 100}))
+
+	// The running container reserves 512 MB through its memory limit.
+	require.ErrorIs(t, svc.CheckContainerQuota(ctx, "team-a", "", ContainerQuotaRequest{Containers: 1, MemoryBytes: 513 * mb}), ErrNamespaceQuotaExceeded)
+	require.ErrorIs(t, svc.CheckContainerQuota(ctx, "team-a", "", ContainerQuotaRequest{Containers: 1, Unreserved: 1}), ErrNamespaceQuotaExceeded)
+
+	usage, err := svc.GetUsage(ctx, team.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, usage.Containers)
+	assert.Equal(t, int64(512*mb), usage.MemoryBytes)
+	assert.Equal(t, 1024, usage.MaxMemoryMB)
+
+	all, err := svc.ListUsage(ctx)
+	require.NoError(t, err)
+	require.Len(t, all, 1)
+	assert.Equal(t, "team-a", all[0].Name)
+}
+
+func TestNamespaceService_AuthorizeResource(t *testing.T) {
+	ctx := context.Background()
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch strings.TrimPrefix(r.URL.Path, "/v1.44") {
+		case "/containers/mine/json":
+			_, _ = io.WriteString(w, `{"Id":"mine","Config":{"Labels":{"com.docker.compose.project":"web"}}}`)
+		case "/containers/theirs/json":
+			_, _ = io.WriteString(w, `{"Id":"theirs","Config":{"Labels":{"com.getarcaneapp.namespace":"team-b"}}}`)
+		case "/volumes/shared":
+			_, _ = io.WriteString(w, `{"Name":"shared","Labels":{}}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	db := setupNamespaceTestDB(t)
+	svc := NewNamespaceService(db, &DockerClientService{client: cli})
+
+	team, err := svc.CreateNamespace(ctx, namespacetypes.Create{Name: "team-a"})
+	require.NoError(t, err)
+	require.NoError(t, db.Create(&models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "alice"}).Error)
+	require.NoError(t, db.Create(&models.Project{BaseModel: models.BaseModel{ID: "p1"}, Name: "web", Path: "/p/web"}).Error)
+	require.NoError(t, db.Create(&models.Project{BaseModel: models.BaseModel{ID: "p2"}, Name: "db", Path: "/p/db"}).Error)
+	_, err = svc.SetMembers(ctx, team.ID, []string{"u1"})
+	require.NoError(t, err)
+	_, err = svc.SetProjects(ctx, team.ID, []string{"p1"})
+	require.NoError(t, err)
+
+	// Unrestricted callers are never refused.
+	require.NoError(t, svc.AuthorizeResource(ctx, NamespaceResourceContainer, "theirs"))
+
+	scope, err := svc.ScopeForUser(ctx, "u1", false)
+	require.NoError(t, err)
+	scoped := WithNamespaceScope(ctx, scope)
+
+	require.NoError(t, svc.AuthorizeResource(scoped, NamespaceResourceContainer, "mine"))
+	require.NoError(t, svc.AuthorizeResource(scoped, NamespaceResourceProject, "p1"))
+	require.ErrorIs(t, svc.AuthorizeResource(scoped, NamespaceResourceContainer, "theirs"), ErrNamespaceForbidden)
+	require.ErrorIs(t, svc.AuthorizeResource(scoped, NamespaceResourceVolume, "shared"), ErrNamespaceForbidden)
+	require.ErrorIs(t, svc.AuthorizeResource(scoped, NamespaceResourceProject, "p2"), ErrNamespaceForbidden)
+	// Missing resources are left to the handler's own not-found error.
+	require.NoError(t, svc.AuthorizeResource(scoped, NamespaceResourceContainer, "gone"))
+}
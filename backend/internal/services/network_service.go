@@ -2,7 +2,9 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/netip"
 	"strings"
 
 	"github.com/docker/docker/api/types/container"
@@ -13,8 +15,13 @@ import (
 	dockerutil "github.com/getarcaneapp/arcane/backend/internal/utils/docker"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/pagination"
 	networktypes "github.com/getarcaneapp/arcane/types/network"
+	"github.com/getarcaneapp/arcane/types/system"
 )
 
+// ErrInvalidNetworkOptions is returned when network create options are
+// rejected before they reach the Docker daemon.
+var ErrInvalidNetworkOptions = errors.New("invalid network options")
+
 type NetworkService struct {
 	db            *database.DB
 	dockerService *DockerClientService
@@ -44,6 +51,10 @@ func (s *NetworkService) GetNetworkByID(ctx context.Context, id string) (*networ
 }
 
 func (s *NetworkService) CreateNetwork(ctx context.Context, name string, options network.CreateOptions, user models.User) (*network.CreateResponse, error) {
+	if err := validateNetworkCreateOptionsInternal(options); err != nil {
+		return nil, err
+	}
+
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
 		s.eventService.LogErrorEvent(ctx, models.EventTypeNetworkError, "network", "", name, user.ID, user.Username, "0", err, models.JSON{"action": "create", "driver": options.Driver})
@@ -68,6 +79,54 @@ func (s *NetworkService) CreateNetwork(ctx context.Context, name string, options
 	return &response, nil
 }
 
+// validateNetworkCreateOptionsInternal checks the IPAM subnets of options
+// against the address families enabled on the network, so a mistyped subnet
+// or a missing enableIPv6 is reported clearly instead of by the daemon.
+func validateNetworkCreateOptionsInternal(options network.CreateOptions) error {
+	ipv4 := options.EnableIPv4 == nil || *options.EnableIPv4
+	ipv6 := options.EnableIPv6 != nil && *options.EnableIPv6
+	if !ipv4 && !ipv6 {
+		return fmt.Errorf("%w: at least one of IPv4 and IPv6 must be enabled", ErrInvalidNetworkOptions)
+	}
+	if options.IPAM == nil {
+		return nil
+	}
+
+	for _, cfg := range options.IPAM.Config {
+		if cfg.Subnet == "" {
+			if cfg.Gateway != "" || cfg.IPRange != "" {
+				return fmt.Errorf("%w: a gateway or IP range needs a subnet", ErrInvalidNetworkOptions)
+			}
+			continue
+		}
+
+		subnet, err := netip.ParsePrefix(cfg.Subnet)
+		if err != nil {
+			return fmt.Errorf("%w: subnet %q is not a valid CIDR", ErrInvalidNetworkOptions, cfg.Subnet)
+		}
+		if subnet.Addr().Is6() && !ipv6 {
+			return fmt.Errorf("%w: IPv6 subnet %s requires enableIPv6", ErrInvalidNetworkOptions, cfg.Subnet)
+		}
+		if subnet.Addr().Is4() && !ipv4 {
+			return fmt.Errorf("%w: IPv4 subnet %s is set but IPv4 is disabled", ErrInvalidNetworkOptions, cfg.Subnet)
+		}
+
+		if cfg.Gateway != "" {
+			gateway, err := netip.ParseAddr(cfg.Gateway)
+			if err != nil || !subnet.Contains(gateway) {
+				return fmt.Errorf("%w: gateway %q is not an address in subnet %s", ErrInvalidNetworkOptions, cfg.Gateway, cfg.Subnet)
+			}
+		}
+		if cfg.IPRange != "" {
+			ipRange, err := netip.ParsePrefix(cfg.IPRange)
+			if err != nil || ipRange.Bits() < subnet.Bits() || !subnet.Contains(ipRange.Addr()) {
+				return fmt.Errorf("%w: IP range %q is not inside subnet %s", ErrInvalidNetworkOptions, cfg.IPRange, cfg.Subnet)
+			}
+		}
+	}
+	return nil
+}
+
 func (s *NetworkService) RemoveNetwork(ctx context.Context, id string, user models.User) error {
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
@@ -99,7 +158,29 @@ func (s *NetworkService) RemoveNetwork(ctx context.Context, id string, user mode
 	return nil
 }
 
-func (s *NetworkService) PruneNetworks(ctx context.Context) (*network.PruneReport, error) {
+// PruneNetworks removes unused networks and records a prune report for user.
+func (s *NetworkService) PruneNetworks(ctx context.Context, user models.User) (*network.PruneReport, error) {
+	report, err := s.pruneNetworksInternal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	recordPruneReportInternal(ctx, s.db, &system.PruneAllResult{
+		Success: true,
+		Items:   prunedNetworkItemsInternal(report.NetworksDeleted),
+	}, models.PruneTriggerManual, user)
+	return report, nil
+}
+
+func prunedNetworkItemsInternal(names []string) []system.PrunedItem {
+	items := make([]system.PrunedItem, 0, len(names))
+	for _, name := range names {
+		items = append(items, system.PrunedItem{Type: system.PrunedItemNetwork, ID: name, Name: name})
+	}
+	return items
+}
+
+func (s *NetworkService) pruneNetworksInternal(ctx context.Context) (*network.PruneReport, error) {
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
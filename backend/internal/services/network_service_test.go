@@ -0,0 +1,43 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/docker/docker/api/types/network"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateNetworkCreateOptions(t *testing.T) {
+	on, off := true, false
+	ipam := func(configs ...network.IPAMConfig) *network.IPAM {
+		return &network.IPAM{Config: configs}
+	}
+
+	valid := []network.CreateOptions{
+		{},
+		{IPAM: ipam(network.IPAMConfig{Subnet: "172.30.0.0/16", Gateway: "172.30.0.1", IPRange: "172.30.5.0/24"})},
+		{EnableIPv6: &on, IPAM: ipam(
+			network.IPAMConfig{Subnet: "172.30.0.0/16"},
+			network.IPAMConfig{Subnet: "fd00:db8::/64", Gateway: "fd00:db8::1"},
+		)},
+		{EnableIPv4: &off, EnableIPv6: &on, IPAM: ipam(network.IPAMConfig{Subnet: "fd00:db8::/64"})},
+	}
+	for _, opts := range valid {
+		assert.NoError(t, validateNetworkCreateOptionsInternal(opts))
+	}
+
+	invalid := map[string]network.CreateOptions{
+		"no family":           {EnableIPv4: &off},
+		"bad subnet":          {IPAM: ipam(network.IPAMConfig{Subnet: "172.30.0.0"})},
+		"ipv6 not enabled":    {IPAM: ipam(network.IPAMConfig{Subnet: "fd00:db8::/64"})},
+		"ipv4 disabled":       {EnableIPv4: &off, EnableIPv6: &on, IPAM: ipam(network.IPAMConfig{Subnet: "172.30.0.0/16"})},
+		"gateway outside":     {IPAM: ipam(network.IPAMConfig{Subnet: "172.30.0.0/16", Gateway: "10.0.0.1"})},
+		"range too wide":      {IPAM: ipam(network.IPAMConfig{Subnet: "172.30.0.0/16", IPRange: "172.0.0.0/8"})},
+		"gateway sans subnet": {IPAM: ipam(network.IPAMConfig{Gateway: "172.30.0.1"})},
+	}
+	for name, opts := range invalid {
+		err := validateNetworkCreateOptionsInternal(opts)
+		require.ErrorIs(t, err, ErrInvalidNetworkOptions, name)
+	}
+}
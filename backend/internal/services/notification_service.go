@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"html"
 	"log/slog"
 	"net/mail"
 	"strings"
@@ -38,6 +39,72 @@ type VulnerabilityNotificationPayload struct {
 	InstalledVersion string // optional
 }
 
+// MonitorNotificationPayload is the data sent to all providers for
+// monitor_down events.
+type MonitorNotificationPayload struct {
+	Name      string
+	Target    string
+	Error     string
+	Project   string // optional
+	Container string // optional
+}
+
+// HealthFlapNotificationPayload is the data sent to all providers for
+// container_flapping events.
+type HealthFlapNotificationPayload struct {
+	Container   string
+	Status      string
+	Transitions int
+	Threshold   int
+}
+
+// ContainerCrashNotificationPayload is the data sent to all providers for
+// container_oom_killed and container_crash_loop events.
+type ContainerCrashNotificationPayload struct {
+	Container string
+	// OOMKilled is set for an out-of-memory kill; otherwise the container
+	// is crash looping.
+	OOMKilled bool
+	ExitCode  int
+	Crashes   int // failed exits within Window
+	Window    time.Duration
+}
+
+// VulnerabilityDigestNotificationPayload is the data sent to all providers for
+// vulnerability_digest events. Counts leave out ignored vulnerabilities.
+type VulnerabilityDigestNotificationPayload struct {
+	Since         *time.Time // when the previous digest was sent, nil for the first
+	ScannedImages int
+	Critical      int
+	High          int
+	Medium        int
+	Low           int
+	Fixable       int // vulnerabilities with a fixed version available
+	// NewCriticalCount is how many critical vulnerabilities appeared since
+	// the previous digest; NewCriticals lists the first of them.
+	NewCriticalCount int
+	NewCriticals     []VulnerabilityNotificationPayload
+	TopImages        []VulnerabilityDigestImage
+}
+
+// VulnerabilityDigestImage is one of the most affected images in a
+// vulnerability digest.
+type VulnerabilityDigestImage struct {
+	ImageName string
+	Critical  int
+	High      int
+	Total     int
+	Fixable   int
+}
+
+// LicenseNotificationPayload is the data sent to all providers for
+// license_denied events.
+type LicenseNotificationPayload struct {
+	ImageName string
+	Licenses  []string // denied licenses the image introduced
+	Packages  []string // packages using them, as "package (license)"
+}
+
 type NotificationService struct {
 	db             *database.DB
 	config         *config.Config
@@ -3148,3 +3215,718 @@ func (s *NotificationService) renderTemplatesInternal(name string, data interfac
 
 	return htmlBuf.String(), "", nil
 }
+
+// SendBootVerificationNotification notifies all enabled providers that have the
+// boot_verification event enabled about containers that could not be brought
+// back after a Docker daemon restart.
+func (s *NotificationService) SendBootVerificationNotification(ctx context.Context, report *system.BootVerificationReport) error {
+	settings, err := s.GetAllSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get notification settings: %w", err)
+	}
+
+	const title = "Post-Restart Verification"
+	message := s.formatBootVerificationMessageInternal(report)
+
+	var errors []string
+	for _, setting := range settings {
+		if !setting.Enabled {
+			continue
+		}
+
+		if !s.isEventEnabled(setting.Config, models.NotificationEventBootVerification) {
+			continue
+		}
+
+		var sendErr error
+		if setting.Provider == models.NotificationProviderEmail {
+			sendErr = s.sendEmailBootVerificationNotification(ctx, report, setting.Config)
+		} else if known, err := s.sendTextNotificationInternal(ctx, setting.Provider, title, message, setting.Config); known {
+			sendErr = err
+		} else {
+			slog.WarnContext(ctx, "Unknown notification provider", "provider", setting.Provider)
+			continue
+		}
+
+		status := "success"
+		var errMsg *string
+		if sendErr != nil {
+			status = "failed"
+			msg := sendErr.Error()
+			errMsg = &msg
+			errors = append(errors, fmt.Sprintf("%s: %s", setting.Provider, msg))
+		}
+
+		s.logNotification(ctx, setting.Provider, title, status, errMsg, models.JSON{
+			"failed":    len(report.Failed),
+			"eventType": string(models.NotificationEventBootVerification),
+		})
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("notification errors: %s", strings.Join(errors, "; "))
+	}
+
+	return nil
+}
+
+func (s *NotificationService) formatBootVerificationMessageInternal(report *system.BootVerificationReport) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s.\n", report.Reason)
+	fmt.Fprintf(&b, "Expected running: %d\n", report.Expected)
+	fmt.Fprintf(&b, "Came back on their own: %d\n", report.Running)
+	fmt.Fprintf(&b, "Started by Arcane: %d\n", len(report.Started))
+	if len(report.Redeployed) > 0 {
+		fmt.Fprintf(&b, "Redeployed projects: %s\n", strings.Join(report.Redeployed, ", "))
+	}
+	fmt.Fprintf(&b, "Failed: %d\n", len(report.Failed))
+	for _, f := range report.Failed {
+		name := f.Name
+		if f.Project != "" {
+			name = f.Project + "/" + f.Name
+		}
+		fmt.Fprintf(&b, "- %s: %s\n", name, f.Error)
+	}
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+// sendTextNotificationInternal sends a plain title and message to any
+// provider except email, which needs a rendered template. It reports false
+// for providers it does not know.
+func (s *NotificationService) sendTextNotificationInternal(ctx context.Context, provider models.NotificationProvider, title, message string, config models.JSON) (bool, error) {
+	switch provider {
+	case models.NotificationProviderDiscord:
+		return true, s.sendDiscordTextNotification(ctx, title, message, config)
+	case models.NotificationProviderTelegram:
+		return true, s.sendTelegramTextNotification(ctx, title, message, config)
+	case models.NotificationProviderSignal:
+		var cfg models.SignalConfig
+		if err := s.unmarshalConfigInternal(config, &cfg); err != nil {
+			return true, err
+		}
+		return true, notifications.SendSignal(ctx, cfg, title+"\n\n"+message)
+	case models.NotificationProviderSlack:
+		var cfg models.SlackConfig
+		if err := s.unmarshalConfigInternal(config, &cfg); err != nil {
+			return true, err
+		}
+		return true, notifications.SendSlack(ctx, cfg, "*"+title+"*\n\n"+message)
+	case models.NotificationProviderNtfy:
+		var cfg models.NtfyConfig
+		if err := s.unmarshalConfigInternal(config, &cfg); err != nil {
+			return true, err
+		}
+		return true, notifications.SendNtfy(ctx, cfg, message)
+	case models.NotificationProviderPushover:
+		var cfg models.PushoverConfig
+		if err := s.unmarshalConfigInternal(config, &cfg); err != nil {
+			return true, err
+		}
+		if cfg.Title == "" {
+			cfg.Title = title
+		}
+		return true, notifications.SendPushover(ctx, cfg, message)
+	case models.NotificationProviderGotify:
+		var cfg models.GotifyConfig
+		if err := s.unmarshalConfigInternal(config, &cfg); err != nil {
+			return true, err
+		}
+		if cfg.Title == "" {
+			cfg.Title = title
+		}
+		return true, notifications.SendGotify(ctx, cfg, message)
+	case models.NotificationProviderMatrix:
+		var cfg models.MatrixConfig
+		if err := s.unmarshalConfigInternal(config, &cfg); err != nil {
+			return true, err
+		}
+		return true, notifications.SendMatrix(ctx, cfg, title+"\n"+message)
+	case models.NotificationProviderGeneric:
+		var cfg models.GenericConfig
+		if err := s.unmarshalConfigInternal(config, &cfg); err != nil {
+			return true, err
+		}
+		return true, notifications.SendGenericWithTitle(ctx, cfg, title, message)
+	default:
+		return false, nil
+	}
+}
+
+func (s *NotificationService) sendDiscordTextNotification(ctx context.Context, title, message string, config models.JSON) error {
+	var discordConfig models.DiscordConfig
+	if err := s.unmarshalConfigInternal(config, &discordConfig); err != nil {
+		return err
+	}
+
+	if discordConfig.WebhookID == "" || discordConfig.Token == "" {
+		return fmt.Errorf("discord webhook ID or token not configured")
+	}
+
+	s.decryptDiscordTokenInternal(&discordConfig)
+
+	if err := notifications.SendDiscord(ctx, discordConfig, fmt.Sprintf("**⚠️ %s**\n\n%s", title, message)); err != nil {
+		return fmt.Errorf("failed to send Discord notification: %w", err)
+	}
+
+	return nil
+}
+
+func (s *NotificationService) sendTelegramTextNotification(ctx context.Context, title, message string, config models.JSON) error {
+	var telegramConfig models.TelegramConfig
+	if err := s.unmarshalConfigInternal(config, &telegramConfig); err != nil {
+		return err
+	}
+
+	if telegramConfig.BotToken == "" || len(telegramConfig.ChatIDs) == 0 {
+		return fmt.Errorf("telegram bot token or chat IDs not configured")
+	}
+
+	s.decryptTelegramTokenInternal(&telegramConfig)
+
+	telegramConfig.ParseMode = "HTML"
+	text := fmt.Sprintf("⚠️ <b>%s</b>\n\n%s", html.EscapeString(title), html.EscapeString(message))
+	if err := notifications.SendTelegram(ctx, telegramConfig, text); err != nil {
+		return fmt.Errorf("failed to send Telegram notification: %w", err)
+	}
+
+	return nil
+}
+
+func (s *NotificationService) sendEmailBootVerificationNotification(ctx context.Context, report *system.BootVerificationReport, config models.JSON) error {
+	var emailConfig models.EmailConfig
+	if err := s.unmarshalConfigInternal(config, &emailConfig); err != nil {
+		return err
+	}
+
+	if err := s.validateEmailConfigInternal(&emailConfig); err != nil {
+		return err
+	}
+
+	s.decryptEmailPasswordInternal(&emailConfig)
+
+	appURL := s.config.GetAppURL()
+	htmlBody, _, err := s.renderTemplatesInternal("boot-verification", map[string]interface{}{
+		"LogoURL":    appURL + logoURLPath,
+		"AppURL":     appURL,
+		"Reason":     report.Reason,
+		"Expected":   report.Expected,
+		"Running":    report.Running,
+		"Started":    report.Started,
+		"Redeployed": report.Redeployed,
+		"Failed":     report.Failed,
+		"Time":       time.Now().Format(time.RFC1123),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email template: %w", err)
+	}
+
+	subject := fmt.Sprintf("Post-Restart Verification: %d Container(s) Failed to Start", len(report.Failed))
+	if err := notifications.SendEmail(ctx, emailConfig, subject, htmlBody); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// SendMonitorDownNotification notifies all enabled providers that have the
+// monitor_down event enabled that an endpoint monitor started failing.
+func (s *NotificationService) SendMonitorDownNotification(ctx context.Context, payload MonitorNotificationPayload) error {
+	settings, err := s.GetAllSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get notification settings: %w", err)
+	}
+
+	title := "Monitor Down: " + payload.Name
+	message := formatMonitorDownMessageInternal(payload)
+
+	var errors []string
+	for _, setting := range settings {
+		if !setting.Enabled {
+			continue
+		}
+
+		if !s.isEventEnabled(setting.Config, models.NotificationEventMonitorDown) {
+			continue
+		}
+
+		var sendErr error
+		if setting.Provider == models.NotificationProviderEmail {
+			sendErr = s.sendEmailMonitorDownNotification(ctx, payload, setting.Config)
+		} else if known, err := s.sendTextNotificationInternal(ctx, setting.Provider, title, message, setting.Config); known {
+			sendErr = err
+		} else {
+			slog.WarnContext(ctx, "Unknown notification provider", "provider", setting.Provider)
+			continue
+		}
+
+		status := "success"
+		var errMsg *string
+		if sendErr != nil {
+			status = "failed"
+			msg := sendErr.Error()
+			errMsg = &msg
+			errors = append(errors, fmt.Sprintf("%s: %s", setting.Provider, msg))
+		}
+
+		s.logNotification(ctx, setting.Provider, payload.Name, status, errMsg, models.JSON{
+			"target":    payload.Target,
+			"eventType": string(models.NotificationEventMonitorDown),
+		})
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("notification errors: %s", strings.Join(errors, "; "))
+	}
+
+	return nil
+}
+
+func formatMonitorDownMessageInternal(payload MonitorNotificationPayload) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Target: %s\n", payload.Target)
+	if payload.Project != "" {
+		fmt.Fprintf(&b, "Project: %s\n", payload.Project)
+	}
+	if payload.Container != "" {
+		fmt.Fprintf(&b, "Container: %s\n", payload.Container)
+	}
+	fmt.Fprintf(&b, "Error: %s", payload.Error)
+	return b.String()
+}
+
+// SendHealthFlapNotification notifies all enabled providers that have the
+// container_flapping event enabled that a container's health keeps changing.
+func (s *NotificationService) SendHealthFlapNotification(ctx context.Context, payload HealthFlapNotificationPayload) error {
+	settings, err := s.GetAllSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get notification settings: %w", err)
+	}
+
+	title := "Container Flapping: " + payload.Container
+	message := fmt.Sprintf("Health changed %d times in the last hour (threshold %d).\nCurrent status: %s",
+		payload.Transitions, payload.Threshold, payload.Status)
+
+	var errors []string
+	for _, setting := range settings {
+		if !setting.Enabled {
+			continue
+		}
+
+		if !s.isEventEnabled(setting.Config, models.NotificationEventContainerFlapping) {
+			continue
+		}
+
+		var sendErr error
+		if setting.Provider == models.NotificationProviderEmail {
+			sendErr = s.sendEmailHealthFlapNotification(ctx, payload, setting.Config)
+		} else if known, err := s.sendTextNotificationInternal(ctx, setting.Provider, title, message, setting.Config); known {
+			sendErr = err
+		} else {
+			slog.WarnContext(ctx, "Unknown notification provider", "provider", setting.Provider)
+			continue
+		}
+
+		status := "success"
+		var errMsg *string
+		if sendErr != nil {
+			status = "failed"
+			msg := sendErr.Error()
+			errMsg = &msg
+			errors = append(errors, fmt.Sprintf("%s: %s", setting.Provider, msg))
+		}
+
+		s.logNotification(ctx, setting.Provider, payload.Container, status, errMsg, models.JSON{
+			"transitions": payload.Transitions,
+			"eventType":   string(models.NotificationEventContainerFlapping),
+		})
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("notification errors: %s", strings.Join(errors, "; "))
+	}
+
+	return nil
+}
+
+func (s *NotificationService) sendEmailHealthFlapNotification(ctx context.Context, payload HealthFlapNotificationPayload, config models.JSON) error {
+	var emailConfig models.EmailConfig
+	if err := s.unmarshalConfigInternal(config, &emailConfig); err != nil {
+		return err
+	}
+
+	if err := s.validateEmailConfigInternal(&emailConfig); err != nil {
+		return err
+	}
+
+	s.decryptEmailPasswordInternal(&emailConfig)
+
+	appURL := s.config.GetAppURL()
+	htmlBody, _, err := s.renderTemplatesInternal("health-flapping", map[string]interface{}{
+		"LogoURL":     appURL + logoURLPath,
+		"AppURL":      appURL,
+		"Container":   payload.Container,
+		"Status":      payload.Status,
+		"Transitions": payload.Transitions,
+		"Threshold":   payload.Threshold,
+		"Time":        time.Now().Format(time.RFC1123),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email template: %w", err)
+	}
+
+	subject := fmt.Sprintf("Container Flapping: %s", notifications.SanitizeForEmail(payload.Container))
+	if err := notifications.SendEmail(ctx, emailConfig, subject, htmlBody); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// SendContainerCrashNotification notifies all enabled providers that have the
+// container_oom_killed or container_crash_loop event enabled that a container
+// ran out of memory or keeps crashing.
+func (s *NotificationService) SendContainerCrashNotification(ctx context.Context, payload ContainerCrashNotificationPayload) error {
+	settings, err := s.GetAllSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get notification settings: %w", err)
+	}
+
+	eventType := models.NotificationEventContainerCrashLoop
+	title := "Container Crash Loop: " + payload.Container
+	message := fmt.Sprintf("Exited with an error %d times in the last %s.\nLast exit code: %d",
+		payload.Crashes, payload.Window, payload.ExitCode)
+	if payload.OOMKilled {
+		eventType = models.NotificationEventContainerOOMKilled
+		title = "Container Out of Memory: " + payload.Container
+		message = "The container was killed because it ran out of memory."
+	}
+
+	var errors []string
+	for _, setting := range settings {
+		if !setting.Enabled {
+			continue
+		}
+
+		if !s.isEventEnabled(setting.Config, eventType) {
+			continue
+		}
+
+		var sendErr error
+		if setting.Provider == models.NotificationProviderEmail {
+			sendErr = s.sendEmailContainerCrashNotification(ctx, payload, title, setting.Config)
+		} else if known, err := s.sendTextNotificationInternal(ctx, setting.Provider, title, message, setting.Config); known {
+			sendErr = err
+		} else {
+			slog.WarnContext(ctx, "Unknown notification provider", "provider", setting.Provider)
+			continue
+		}
+
+		status := "success"
+		var errMsg *string
+		if sendErr != nil {
+			status = "failed"
+			msg := sendErr.Error()
+			errMsg = &msg
+			errors = append(errors, fmt.Sprintf("%s: %s", setting.Provider, msg))
+		}
+
+		s.logNotification(ctx, setting.Provider, payload.Container, status, errMsg, models.JSON{
+			"exitCode":  payload.ExitCode,
+			"crashes":   payload.Crashes,
+			"eventType": string(eventType),
+		})
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("notification errors: %s", strings.Join(errors, "; "))
+	}
+
+	return nil
+}
+
+func (s *NotificationService) sendEmailContainerCrashNotification(ctx context.Context, payload ContainerCrashNotificationPayload, title string, config models.JSON) error {
+	var emailConfig models.EmailConfig
+	if err := s.unmarshalConfigInternal(config, &emailConfig); err != nil {
+		return err
+	}
+
+	if err := s.validateEmailConfigInternal(&emailConfig); err != nil {
+		return err
+	}
+
+	s.decryptEmailPasswordInternal(&emailConfig)
+
+	appURL := s.config.GetAppURL()
+	htmlBody, _, err := s.renderTemplatesInternal("container-crash", map[string]interface{}{
+		"LogoURL":   appURL + logoURLPath,
+		"AppURL":    appURL,
+		"Title":     strings.TrimSuffix(title, ": "+payload.Container),
+		"Container": payload.Container,
+		"OOMKilled": payload.OOMKilled,
+		"ExitCode":  payload.ExitCode,
+		"Crashes":   payload.Crashes,
+		"Window":    payload.Window.String(),
+		"Time":      time.Now().Format(time.RFC1123),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email template: %w", err)
+	}
+
+	subject := notifications.SanitizeForEmail(title)
+	if err := notifications.SendEmail(ctx, emailConfig, subject, htmlBody); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// SendLicenseNotification notifies all enabled providers that have the
+// license_denied event enabled that an image introduced denied licenses.
+func (s *NotificationService) SendLicenseNotification(ctx context.Context, payload LicenseNotificationPayload) error {
+	settings, err := s.GetAllSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get notification settings: %w", err)
+	}
+
+	title := "Denied License: " + payload.ImageName
+	message := fmt.Sprintf("Licenses: %s\nPackages: %s", strings.Join(payload.Licenses, ", "), strings.Join(payload.Packages, ", "))
+
+	var errors []string
+	for _, setting := range settings {
+		if !setting.Enabled {
+			continue
+		}
+
+		if !s.isEventEnabled(setting.Config, models.NotificationEventLicenseDenied) {
+			continue
+		}
+
+		var sendErr error
+		if setting.Provider == models.NotificationProviderEmail {
+			sendErr = s.sendEmailLicenseNotification(ctx, payload, setting.Config)
+		} else if known, err := s.sendTextNotificationInternal(ctx, setting.Provider, title, message, setting.Config); known {
+			sendErr = err
+		} else {
+			slog.WarnContext(ctx, "Unknown notification provider", "provider", setting.Provider)
+			continue
+		}
+
+		status := "success"
+		var errMsg *string
+		if sendErr != nil {
+			status = "failed"
+			msg := sendErr.Error()
+			errMsg = &msg
+			errors = append(errors, fmt.Sprintf("%s: %s", setting.Provider, msg))
+		}
+
+		s.logNotification(ctx, setting.Provider, payload.ImageName, status, errMsg, models.JSON{
+			"licenses":  payload.Licenses,
+			"eventType": string(models.NotificationEventLicenseDenied),
+		})
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("notification errors: %s", strings.Join(errors, "; "))
+	}
+
+	return nil
+}
+
+func (s *NotificationService) sendEmailLicenseNotification(ctx context.Context, payload LicenseNotificationPayload, config models.JSON) error {
+	var emailConfig models.EmailConfig
+	if err := s.unmarshalConfigInternal(config, &emailConfig); err != nil {
+		return err
+	}
+
+	if err := s.validateEmailConfigInternal(&emailConfig); err != nil {
+		return err
+	}
+
+	s.decryptEmailPasswordInternal(&emailConfig)
+
+	appURL := s.config.GetAppURL()
+	htmlBody, _, err := s.renderTemplatesInternal("license-denied", map[string]interface{}{
+		"LogoURL":   appURL + logoURLPath,
+		"AppURL":    appURL,
+		"ImageName": payload.ImageName,
+		"Licenses":  payload.Licenses,
+		"Packages":  payload.Packages,
+		"Time":      time.Now().Format(time.RFC1123),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email template: %w", err)
+	}
+
+	subject := fmt.Sprintf("Denied License: %s", notifications.SanitizeForEmail(payload.ImageName))
+	if err := notifications.SendEmail(ctx, emailConfig, subject, htmlBody); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+func (s *NotificationService) sendEmailMonitorDownNotification(ctx context.Context, payload MonitorNotificationPayload, config models.JSON) error {
+	var emailConfig models.EmailConfig
+	if err := s.unmarshalConfigInternal(config, &emailConfig); err != nil {
+		return err
+	}
+
+	if err := s.validateEmailConfigInternal(&emailConfig); err != nil {
+		return err
+	}
+
+	s.decryptEmailPasswordInternal(&emailConfig)
+
+	appURL := s.config.GetAppURL()
+	htmlBody, _, err := s.renderTemplatesInternal("monitor-down", map[string]interface{}{
+		"LogoURL":   appURL + logoURLPath,
+		"AppURL":    appURL,
+		"Name":      payload.Name,
+		"Target":    payload.Target,
+		"Error":     payload.Error,
+		"Project":   payload.Project,
+		"Container": payload.Container,
+		"Time":      time.Now().Format(time.RFC1123),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email template: %w", err)
+	}
+
+	subject := fmt.Sprintf("Monitor Down: %s", notifications.SanitizeForEmail(payload.Name))
+	if err := notifications.SendEmail(ctx, emailConfig, subject, htmlBody); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
+
+// SendVulnerabilityDigestNotification notifies all enabled providers that
+// have the vulnerability_digest event enabled with a summary of the
+// environment's open vulnerabilities.
+func (s *NotificationService) SendVulnerabilityDigestNotification(ctx context.Context, payload VulnerabilityDigestNotificationPayload) error {
+	settings, err := s.GetAllSettings(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get notification settings: %w", err)
+	}
+
+	title := fmt.Sprintf("Vulnerability Digest: %d New Critical", payload.NewCriticalCount)
+	message := formatVulnerabilityDigestMessageInternal(payload)
+
+	var errors []string
+	for _, setting := range settings {
+		if !setting.Enabled {
+			continue
+		}
+
+		if !s.isEventEnabled(setting.Config, models.NotificationEventVulnerabilityDigest) {
+			continue
+		}
+
+		var sendErr error
+		if setting.Provider == models.NotificationProviderEmail {
+			sendErr = s.sendEmailVulnerabilityDigestNotification(ctx, payload, title, setting.Config)
+		} else if known, err := s.sendTextNotificationInternal(ctx, setting.Provider, title, message, setting.Config); known {
+			sendErr = err
+		} else {
+			slog.WarnContext(ctx, "Unknown notification provider", "provider", setting.Provider)
+			continue
+		}
+
+		status := "success"
+		var errMsg *string
+		if sendErr != nil {
+			status = "failed"
+			msg := sendErr.Error()
+			errMsg = &msg
+			errors = append(errors, fmt.Sprintf("%s: %s", setting.Provider, msg))
+		}
+
+		s.logNotification(ctx, setting.Provider, "vulnerability-digest", status, errMsg, models.JSON{
+			"newCriticals": payload.NewCriticalCount,
+			"critical":     payload.Critical,
+			"fixable":      payload.Fixable,
+			"eventType":    string(models.NotificationEventVulnerabilityDigest),
+		})
+	}
+
+	if len(errors) > 0 {
+		return fmt.Errorf("notification errors: %s", strings.Join(errors, "; "))
+	}
+
+	return nil
+}
+
+func formatVulnerabilityDigestMessageInternal(payload VulnerabilityDigestNotificationPayload) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Open: %d critical, %d high, %d medium, %d low across %d scanned images\n",
+		payload.Critical, payload.High, payload.Medium, payload.Low, payload.ScannedImages)
+	fmt.Fprintf(&b, "Fixable: %d\n", payload.Fixable)
+
+	if len(payload.NewCriticals) > 0 {
+		b.WriteString("\nNew critical vulnerabilities:\n")
+		for _, v := range payload.NewCriticals {
+			fmt.Fprintf(&b, "- %s in %s (%s)", v.CVEID, v.ImageName, v.PkgName)
+			if v.FixedVersion != "" {
+				fmt.Fprintf(&b, ", fixed in %s", v.FixedVersion)
+			}
+			b.WriteString("\n")
+		}
+		if more := payload.NewCriticalCount - len(payload.NewCriticals); more > 0 {
+			fmt.Fprintf(&b, "- and %d more\n", more)
+		}
+	}
+
+	if len(payload.TopImages) > 0 {
+		b.WriteString("\nMost affected images:\n")
+		for _, img := range payload.TopImages {
+			fmt.Fprintf(&b, "- %s: %d critical, %d high, %d total, %d fixable\n", img.ImageName, img.Critical, img.High, img.Total, img.Fixable)
+		}
+	}
+
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func (s *NotificationService) sendEmailVulnerabilityDigestNotification(ctx context.Context, payload VulnerabilityDigestNotificationPayload, title string, config models.JSON) error {
+	var emailConfig models.EmailConfig
+	if err := s.unmarshalConfigInternal(config, &emailConfig); err != nil {
+		return err
+	}
+
+	if err := s.validateEmailConfigInternal(&emailConfig); err != nil {
+		return err
+	}
+
+	s.decryptEmailPasswordInternal(&emailConfig)
+
+	since := ""
+	if payload.Since != nil {
+		since = payload.Since.Format(time.RFC1123)
+	}
+
+	appURL := s.config.GetAppURL()
+	htmlBody, _, err := s.renderTemplatesInternal("vulnerability-digest", map[string]interface{}{
+		"LogoURL":          appURL + logoURLPath,
+		"AppURL":           appURL,
+		"Since":            since,
+		"ScannedImages":    payload.ScannedImages,
+		"Critical":         payload.Critical,
+		"High":             payload.High,
+		"Medium":           payload.Medium,
+		"Low":              payload.Low,
+		"Fixable":          payload.Fixable,
+		"NewCriticalCount": payload.NewCriticalCount,
+		"NewCriticals":     payload.NewCriticals,
+		"TopImages":        payload.TopImages,
+		"Time":             time.Now().Format(time.RFC1123),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to render email template: %w", err)
+	}
+
+	if err := notifications.SendEmail(ctx, emailConfig, title, htmlBody); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+
+	return nil
+}
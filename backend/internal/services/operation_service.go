@@ -0,0 +1,313 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrShuttingDown is returned when an operation is started while Arcane is
+	// draining in-flight work before exiting.
+	ErrShuttingDown = errors.New("arcane is shutting down; try again after it restarts")
+	// ErrOperationNotResumable is returned by a resumer when the recorded
+	// state is not enough to finish the operation.
+	ErrOperationNotResumable = errors.New("operation cannot be resumed")
+	// ErrOperationConflict is returned when an operation is started on a
+	// resource that a conflicting operation is already working on.
+	ErrOperationConflict = errors.New("another operation is already running on this resource")
+)
+
+// operationLockGroups maps the kinds that must not run at the same time on
+// one resource to a shared group. Kinds without a group never conflict.
+var operationLockGroups = map[string]string{
+	models.OperationKindVolumeBackup:  "volume",
+	models.OperationKindVolumeRestore: "volume",
+}
+
+// OperationResumer finishes an operation that was cut off by a restart.
+type OperationResumer func(ctx context.Context, op models.OperationState) error
+
+// InFlightOperation describes an operation that is currently running.
+type InFlightOperation struct {
+	ID        string
+	Kind      string
+	Resource  string
+	StartedAt time.Time
+}
+
+// OperationService coordinates graceful shutdown. Long-running operations
+// register while they run; Drain stops new ones from starting and waits for
+// the running ones. Operations started with Begin are also recorded in the
+// database so the ones cut off by a hard stop are reported, and resumed where
+// a resumer is registered, on the next start.
+type OperationService struct {
+	db           *database.DB
+	eventService *EventService
+
+	mu       sync.Mutex
+	draining bool
+	active   map[string]InFlightOperation
+	idle     *sync.Cond
+	resumers map[string]OperationResumer
+}
+
+func NewOperationService(db *database.DB, eventService *EventService) *OperationService {
+	s := &OperationService{
+		db:           db,
+		eventService: eventService,
+		active:       make(map[string]InFlightOperation),
+		resumers:     make(map[string]OperationResumer),
+	}
+	s.idle = sync.NewCond(&s.mu)
+	return s
+}
+
+// Begin registers a resumable operation and records it in the database. The
+// returned func must be called when the operation ends. It fails fast with
+// ErrOperationConflict while a conflicting operation, such as a backup and a
+// restore of the same volume, is running. A nil service tracks nothing, so
+// callers do not need to guard against it.
+func (s *OperationService) Begin(ctx context.Context, kind, resource string, metadata models.JSON) (func(), error) {
+	return s.startInternal(ctx, kind, resource, metadata, true)
+}
+
+// Track registers an operation that only needs to be waited for on shutdown,
+// such as an interactive exec session. It is not recorded in the database.
+func (s *OperationService) Track(kind, resource string) (func(), error) {
+	return s.startInternal(context.Background(), kind, resource, nil, false)
+}
+
+func (s *OperationService) startInternal(ctx context.Context, kind, resource string, metadata models.JSON, persist bool) (func(), error) {
+	if s == nil {
+		return func() {}, nil
+	}
+
+	op := InFlightOperation{ID: uuid.NewString(), Kind: kind, Resource: resource, StartedAt: time.Now()}
+
+	s.mu.Lock()
+	if s.draining {
+		s.mu.Unlock()
+		return nil, ErrShuttingDown
+	}
+	if running, ok := s.conflictInternal(op); ok {
+		s.mu.Unlock()
+		return nil, fmt.Errorf("%w: %s of '%s' has been running since %s", ErrOperationConflict, running.Kind, running.Resource, running.StartedAt.UTC().Format(time.RFC3339))
+	}
+	s.active[op.ID] = op
+	s.mu.Unlock()
+
+	persist = persist && s.db != nil
+	if persist {
+		state := models.OperationState{Kind: kind, Resource: resource, Metadata: metadata, BaseModel: models.BaseModel{ID: op.ID, CreatedAt: op.StartedAt}}
+		if err := s.db.WithContext(ctx).Create(&state).Error; err != nil {
+			slog.WarnContext(ctx, "Failed to record operation state", "kind", kind, "resource", resource, "error", err)
+			persist = false
+		}
+	}
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { s.finishInternal(op, persist) })
+	}, nil
+}
+
+// conflictInternal returns the running operation that op conflicts with.
+// s.mu must be held.
+func (s *OperationService) conflictInternal(op InFlightOperation) (InFlightOperation, bool) {
+	group, ok := operationLockGroups[op.Kind]
+	if !ok {
+		return InFlightOperation{}, false
+	}
+	for _, running := range s.active {
+		if running.Resource == op.Resource && operationLockGroups[running.Kind] == group {
+			return running, true
+		}
+	}
+	return InFlightOperation{}, false
+}
+
+func (s *OperationService) finishInternal(op InFlightOperation, persisted bool) {
+	if persisted {
+		// The operation may have ended because its request was canceled, so do
+		// not tie the cleanup to that context.
+		if err := s.db.WithContext(context.Background()).Delete(&models.OperationState{}, "id = ?", op.ID).Error; err != nil {
+			slog.Warn("Failed to clear operation state", "kind", op.Kind, "resource", op.Resource, "error", err)
+		}
+	}
+
+	s.mu.Lock()
+	delete(s.active, op.ID)
+	if len(s.active) == 0 {
+		s.idle.Broadcast()
+	}
+	s.mu.Unlock()
+}
+
+// IsDraining reports whether Drain has been called.
+func (s *OperationService) IsDraining() bool {
+	if s == nil {
+		return false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.draining
+}
+
+// Active returns the operations currently running, oldest first.
+func (s *OperationService) Active() []InFlightOperation {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	ops := make([]InFlightOperation, 0, len(s.active))
+	for _, op := range s.active {
+		ops = append(ops, op)
+	}
+	s.mu.Unlock()
+
+	sort.Slice(ops, func(i, j int) bool { return ops[i].StartedAt.Before(ops[j].StartedAt) })
+	return ops
+}
+
+// Drain stops new operations from starting and waits until the running ones
+// finish or ctx is done. Operations still running when ctx ends keep their
+// database record so they are reported on the next start.
+func (s *OperationService) Drain(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	s.draining = true
+	s.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		s.mu.Lock()
+		for len(s.active) > 0 && ctx.Err() == nil {
+			s.idle.Wait()
+		}
+		s.mu.Unlock()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-ctx.Done():
+		// Wake the waiter so it notices the deadline and exits.
+		s.mu.Lock()
+		s.idle.Broadcast()
+		s.mu.Unlock()
+		<-done
+	}
+
+	if remaining := s.Active(); len(remaining) > 0 {
+		for _, op := range remaining {
+			slog.Warn("Operation still running at shutdown", "kind", op.Kind, "resource", op.Resource, "running_for", time.Since(op.StartedAt).Round(time.Second))
+		}
+		return fmt.Errorf("%d operation(s) still running: %w", len(remaining), ctx.Err())
+	}
+	return nil
+}
+
+// RegisterResumer sets the function used to finish interrupted operations of
+// the given kind.
+func (s *OperationService) RegisterResumer(kind string, resumer OperationResumer) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	s.resumers[kind] = resumer
+	s.mu.Unlock()
+}
+
+// RecoverInterrupted reports operations recorded by a previous run that never
+// finished, and resumes those with a registered resumer in the background. It
+// must run at startup before new operations begin.
+func (s *OperationService) RecoverInterrupted(ctx context.Context) (int, error) {
+	if s == nil || s.db == nil {
+		return 0, nil
+	}
+
+	var states []models.OperationState
+	if err := s.db.WithContext(ctx).Order("created_at ASC").Find(&states).Error; err != nil {
+		return 0, fmt.Errorf("failed to load operation states: %w", err)
+	}
+	if len(states) == 0 {
+		return 0, nil
+	}
+	ids := make([]string, len(states))
+	for i, state := range states {
+		ids[i] = state.ID
+	}
+	if err := s.db.WithContext(ctx).Delete(&models.OperationState{}, "id IN ?", ids).Error; err != nil {
+		return 0, fmt.Errorf("failed to clear operation states: %w", err)
+	}
+
+	for _, state := range states {
+		s.mu.Lock()
+		resumer := s.resumers[state.Kind]
+		s.mu.Unlock()
+
+		slog.WarnContext(ctx, "Found operation interrupted by a restart", "kind", state.Kind, "resource", state.Resource, "started_at", state.CreatedAt, "resumable", resumer != nil)
+		if resumer == nil {
+			s.logInterruptedInternal(ctx, state, "not resumable")
+			continue
+		}
+
+		go func(state models.OperationState) {
+			resumeCtx := context.WithoutCancel(ctx)
+			if err := resumer(resumeCtx, state); err != nil {
+				slog.ErrorContext(resumeCtx, "Failed to resume interrupted operation", "kind", state.Kind, "resource", state.Resource, "error", err)
+				s.logInterruptedInternal(resumeCtx, state, "resume failed: "+err.Error())
+				return
+			}
+			slog.InfoContext(resumeCtx, "Resumed interrupted operation", "kind", state.Kind, "resource", state.Resource)
+			s.logInterruptedInternal(resumeCtx, state, "resumed")
+		}(state)
+	}
+
+	return len(states), nil
+}
+
+func (s *OperationService) logInterruptedInternal(ctx context.Context, state models.OperationState, outcome string) {
+	if s.eventService == nil {
+		return
+	}
+
+	severity := models.EventSeverityWarning
+	if outcome == "resumed" {
+		severity = models.EventSeverityInfo
+	}
+	resourceType := "system"
+	environmentID := "0"
+	metadata := models.JSON{
+		"kind":       state.Kind,
+		"started_at": state.CreatedAt,
+		"outcome":    outcome,
+	}
+	for k, v := range state.Metadata {
+		metadata[k] = v
+	}
+	if _, err := s.eventService.CreateEvent(ctx, CreateEventRequest{
+		Type:          models.EventTypeSystemOperationInterrupted,
+		Severity:      severity,
+		Title:         fmt.Sprintf("Operation interrupted: %s", state.Kind),
+		Description:   fmt.Sprintf("%s on '%s' was cut off by a restart (%s)", state.Kind, state.Resource, outcome),
+		ResourceType:  &resourceType,
+		ResourceName:  &state.Resource,
+		EnvironmentID: &environmentID,
+		Metadata:      metadata,
+	}); err != nil {
+		slog.WarnContext(ctx, "Failed to log interrupted operation event", "kind", state.Kind, "error", err)
+	}
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+func setupOperationTestDB(t *testing.T) *database.DB {
+	t.Helper()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.OperationState{}))
+	return &database.DB{DB: gdb}
+}
+
+func TestOperationService_DrainWaitsForRunningOperations(t *testing.T) {
+	db := setupOperationTestDB(t)
+	svc := NewOperationService(db, nil)
+	ctx := context.Background()
+
+	done, err := svc.Begin(ctx, models.OperationKindVolumeRestore, "data", models.JSON{"backup_id": "b1"})
+	require.NoError(t, err)
+
+	var count int64
+	require.NoError(t, db.Model(&models.OperationState{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+
+	drained := make(chan error, 1)
+	go func() { drained <- svc.Drain(ctx) }()
+
+	require.Eventually(t, svc.IsDraining, time.Second, 10*time.Millisecond)
+	_, err = svc.Begin(ctx, models.OperationKindProjectDeploy, "web", nil)
+	require.ErrorIs(t, err, ErrShuttingDown)
+
+	select {
+	case <-drained:
+		t.Fatal("drain returned while an operation was still running")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	done()
+	require.NoError(t, <-drained)
+	require.NoError(t, db.Model(&models.OperationState{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestOperationService_DrainTimeoutKeepsState(t *testing.T) {
+	db := setupOperationTestDB(t)
+	svc := NewOperationService(db, nil)
+
+	_, err := svc.Begin(context.Background(), models.OperationKindVolumeBackup, "data", nil)
+	require.NoError(t, err)
+	_, err = svc.Track(models.OperationKindContainerExec, "c1")
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	err = svc.Drain(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Len(t, svc.Active(), 2)
+
+	var count int64
+	require.NoError(t, db.Model(&models.OperationState{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
+
+func TestOperationService_RecoverInterrupted(t *testing.T) {
+	db := setupOperationTestDB(t)
+	ctx := context.Background()
+	require.NoError(t, db.Create(&models.OperationState{Kind: models.OperationKindVolumeRestore, Resource: "data", Metadata: models.JSON{"backup_id": "b1"}, BaseModel: models.BaseModel{ID: "op1"}}).Error)
+	require.NoError(t, db.Create(&models.OperationState{Kind: models.OperationKindVolumeBackup, Resource: "logs", BaseModel: models.BaseModel{ID: "op2"}}).Error)
+
+	svc := NewOperationService(db, nil)
+	resumed := make(chan models.OperationState, 1)
+	svc.RegisterResumer(models.OperationKindVolumeRestore, func(_ context.Context, op models.OperationState) error {
+		resumed <- op
+		return nil
+	})
+
+	n, err := svc.RecoverInterrupted(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, n)
+
+	select {
+	case op := <-resumed:
+		assert.Equal(t, "data", op.Resource)
+		assert.Equal(t, "b1", op.Metadata["backup_id"])
+	case <-time.After(time.Second):
+		t.Fatal("resumer was not called")
+	}
+
+	var count int64
+	require.NoError(t, db.Model(&models.OperationState{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestOperationService_NilIsNoop(t *testing.T) {
+	var svc *OperationService
+	done, err := svc.Begin(context.Background(), models.OperationKindProjectDeploy, "web", nil)
+	require.NoError(t, err)
+	done()
+	assert.False(t, svc.IsDraining())
+	require.NoError(t, svc.Drain(context.Background()))
+}
+
+func TestOperationService_BeginRejectsConflictingVolumeOperations(t *testing.T) {
+	svc := NewOperationService(nil, nil)
+	ctx := context.Background()
+
+	done, err := svc.Begin(ctx, models.OperationKindVolumeBackup, "data", nil)
+	require.NoError(t, err)
+
+	_, err = svc.Begin(ctx, models.OperationKindVolumeRestore, "data", nil)
+	require.ErrorIs(t, err, ErrOperationConflict)
+	assert.Contains(t, err.Error(), models.OperationKindVolumeBackup)
+	_, err = svc.Begin(ctx, models.OperationKindVolumeBackup, "data", nil)
+	require.ErrorIs(t, err, ErrOperationConflict)
+
+	other, err := svc.Begin(ctx, models.OperationKindVolumeRestore, "other", nil)
+	require.NoError(t, err)
+	other()
+	deploy, err := svc.Begin(ctx, models.OperationKindProjectDeploy, "data", nil)
+	require.NoError(t, err)
+	deploy()
+
+	done()
+	done, err = svc.Begin(ctx, models.OperationKindVolumeRestore, "data", nil)
+	require.NoError(t, err)
+	done()
+	assert.Empty(t, svc.Active())
+}
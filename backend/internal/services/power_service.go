@@ -0,0 +1,284 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/internal/config"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/types/environment"
+	"github.com/getarcaneapp/arcane/types/system"
+)
+
+var (
+	// ErrInvalidWakeTarget is returned for MAC addresses, broadcast
+	// addresses or relays that cannot be used for Wake-on-LAN.
+	ErrInvalidWakeTarget = errors.New("invalid Wake-on-LAN target")
+	// ErrWakeNotConfigured is returned when waking an environment without a
+	// MAC address.
+	ErrWakeNotConfigured = errors.New("wake-on-LAN is not configured for this environment")
+	// ErrRebootNotConfigured is returned when the host has no reboot hook.
+	ErrRebootNotConfigured = errors.New("host reboot is not configured; set HOST_REBOOT_COMMAND on the host")
+	// ErrRebootNotConfirmed is returned when a reboot request is not confirmed.
+	ErrRebootNotConfirmed = errors.New("host reboot must be confirmed")
+)
+
+const (
+	defaultWakePort      = "9"
+	defaultWakeBroadcast = "255.255.255.255:" + defaultWakePort
+
+	// hostRebootDelay leaves time for the reboot response to reach the
+	// caller before the hook runs.
+	hostRebootDelay = 2 * time.Second
+	// hostRebootTimeout bounds how long the reboot hook may run.
+	hostRebootTimeout = 2 * time.Minute
+)
+
+// PowerService sends Wake-on-LAN packets and runs the host reboot hook for
+// environments on physical hosts.
+type PowerService struct {
+	cfg                *config.Config
+	environmentService *EnvironmentService
+	eventService       *EventService
+}
+
+func NewPowerService(cfg *config.Config, environmentService *EnvironmentService, eventService *EventService) *PowerService {
+	return &PowerService{
+		cfg:                cfg,
+		environmentService: environmentService,
+		eventService:       eventService,
+	}
+}
+
+// Capabilities reports which power actions this host supports.
+func (s *PowerService) Capabilities() system.PowerCapabilities {
+	return system.PowerCapabilities{RebootEnabled: s.rebootCommandInternal() != ""}
+}
+
+// ParseMACAddress parses a 48-bit MAC address in any notation net.ParseMAC
+// accepts.
+func ParseMACAddress(mac string) (net.HardwareAddr, error) {
+	hw, err := net.ParseMAC(strings.TrimSpace(mac))
+	if err != nil || len(hw) != 6 {
+		return nil, fmt.Errorf("%w: %q is not a MAC address", ErrInvalidWakeTarget, mac)
+	}
+	return hw, nil
+}
+
+// NormalizeMACAddress returns mac in lowercase colon notation, or mac
+// trimmed if it does not parse.
+func NormalizeMACAddress(mac string) string {
+	hw, err := ParseMACAddress(mac)
+	if err != nil {
+		return strings.TrimSpace(mac)
+	}
+	return hw.String()
+}
+
+// ResolveWakeBroadcast returns the host:port the magic packet is sent to,
+// defaulting to the limited broadcast address and the discard port.
+func ResolveWakeBroadcast(addr string) (string, error) {
+	addr = strings.TrimSpace(addr)
+	if addr == "" {
+		return defaultWakeBroadcast, nil
+	}
+
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		host, port = strings.Trim(addr, "[]"), defaultWakePort
+	}
+	if host == "" || strings.ContainsAny(host, " /") {
+		return "", fmt.Errorf("%w: broadcast address %q has no host", ErrInvalidWakeTarget, addr)
+	}
+	if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+		return "", fmt.Errorf("%w: broadcast address %q has an invalid port", ErrInvalidWakeTarget, addr)
+	}
+	return net.JoinHostPort(host, port), nil
+}
+
+// ValidateEnvironmentWake checks the Wake-on-LAN fields of an environment
+// update.
+func ValidateEnvironmentWake(envID string, req *environment.Update) error {
+	if req.MacAddress != nil && strings.TrimSpace(*req.MacAddress) != "" {
+		if _, err := ParseMACAddress(*req.MacAddress); err != nil {
+			return err
+		}
+	}
+	if req.WakeBroadcast != nil {
+		if _, err := ResolveWakeBroadcast(*req.WakeBroadcast); err != nil {
+			return err
+		}
+	}
+	if req.WakeRelayID != nil && strings.TrimSpace(*req.WakeRelayID) == envID {
+		return fmt.Errorf("%w: an environment cannot wake itself", ErrInvalidWakeTarget)
+	}
+	return nil
+}
+
+// magicPacketInternal builds a Wake-on-LAN magic packet: six 0xFF bytes
+// followed by the MAC address repeated sixteen times.
+func magicPacketInternal(hw net.HardwareAddr) []byte {
+	packet := make([]byte, 0, 6+16*len(hw))
+	packet = append(packet, bytes.Repeat([]byte{0xFF}, 6)...)
+	for range 16 {
+		packet = append(packet, hw...)
+	}
+	return packet
+}
+
+// SendWakeOnLAN sends a magic packet for mac from this host.
+func (s *PowerService) SendWakeOnLAN(ctx context.Context, mac, broadcast string) error {
+	hw, err := ParseMACAddress(mac)
+	if err != nil {
+		return err
+	}
+	target, err := ResolveWakeBroadcast(broadcast)
+	if err != nil {
+		return err
+	}
+
+	dialer := net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: func(_, _ string, c syscall.RawConn) error {
+			var sockErr error
+			if err := c.Control(func(fd uintptr) {
+				sockErr = syscall.SetsockoptInt(int(fd), syscall.SOL_SOCKET, syscall.SO_BROADCAST, 1)
+			}); err != nil {
+				return err
+			}
+			return sockErr
+		},
+	}
+	conn, err := dialer.DialContext(ctx, "udp", target)
+	if err != nil {
+		return fmt.Errorf("failed to open Wake-on-LAN socket: %w", err)
+	}
+	defer func() { _ = conn.Close() }()
+
+	if _, err := conn.Write(magicPacketInternal(hw)); err != nil {
+		return fmt.Errorf("failed to send Wake-on-LAN packet: %w", err)
+	}
+	slog.InfoContext(ctx, "Sent Wake-on-LAN packet", "mac", hw.String(), "target", target)
+	return nil
+}
+
+// WakeEnvironment wakes an environment's host, sending the magic packet from
+// its relay environment when one is set and from this host otherwise.
+func (s *PowerService) WakeEnvironment(ctx context.Context, envID string, user models.User) error {
+	env, err := s.environmentService.GetEnvironmentByID(ctx, envID)
+	if err != nil {
+		return err
+	}
+	if env.MacAddress == "" {
+		return ErrWakeNotConfigured
+	}
+
+	relayID := ""
+	if env.WakeRelayID != nil {
+		relayID = *env.WakeRelayID
+	}
+
+	if relayID == "" || relayID == "0" {
+		err = s.SendWakeOnLAN(ctx, env.MacAddress, env.WakeBroadcast)
+	} else {
+		err = s.wakeViaRelayInternal(ctx, relayID, env)
+	}
+	if err != nil {
+		return err
+	}
+
+	if s.eventService != nil {
+		resourceType := "environment"
+		metadata := models.JSON{"macAddress": env.MacAddress}
+		if relayID != "" {
+			metadata["relayId"] = relayID
+		}
+		_, _ = s.eventService.CreateEvent(ctx, CreateEventRequest{
+			Type:          models.EventTypeEnvironmentWake,
+			Title:         "Environment woken",
+			Description:   fmt.Sprintf("Sent Wake-on-LAN packet to %s", env.Name),
+			ResourceType:  &resourceType,
+			ResourceID:    &env.ID,
+			ResourceName:  &env.Name,
+			UserID:        &user.ID,
+			Username:      &user.Username,
+			EnvironmentID: &env.ID,
+			Metadata:      metadata,
+		})
+	}
+	return nil
+}
+
+// wakeViaRelayInternal asks the relay environment's agent to send the magic
+// packet on its LAN.
+func (s *PowerService) wakeViaRelayInternal(ctx context.Context, relayID string, env *models.Environment) error {
+	body, err := json.Marshal(system.WakeOnLanRequest{
+		MacAddress:       env.MacAddress,
+		BroadcastAddress: env.WakeBroadcast,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode Wake-on-LAN request: %w", err)
+	}
+
+	resp, status, err := s.environmentService.ProxyRequest(ctx, relayID, http.MethodPost, "/api/environments/0/system/power/wake", body)
+	if err != nil {
+		return fmt.Errorf("failed to reach Wake-on-LAN relay: %w", err)
+	}
+	if status < 200 || status >= 300 {
+		return fmt.Errorf("wake-on-LAN relay returned status %d: %s", status, strings.TrimSpace(string(resp)))
+	}
+	return nil
+}
+
+// RebootHost runs the configured reboot hook shortly after returning, so the
+// caller gets a response before the host goes down.
+func (s *PowerService) RebootHost(ctx context.Context, req system.RebootRequest, user models.User) error {
+	command := s.rebootCommandInternal()
+	if command == "" {
+		return ErrRebootNotConfigured
+	}
+	if !req.Confirm {
+		return ErrRebootNotConfirmed
+	}
+
+	slog.WarnContext(ctx, "Host reboot requested", "user", user.Username, "userId", user.ID)
+	if s.eventService != nil {
+		_, _ = s.eventService.CreateEvent(ctx, CreateEventRequest{
+			Type:        models.EventTypeSystemHostReboot,
+			Severity:    models.EventSeverityWarning,
+			Title:       "Host reboot",
+			Description: fmt.Sprintf("%s rebooted the host", user.Username),
+			UserID:      &user.ID,
+			Username:    &user.Username,
+		})
+	}
+
+	runCtx := context.WithoutCancel(ctx)
+	time.AfterFunc(hostRebootDelay, func() {
+		cmdCtx, cancel := context.WithTimeout(runCtx, hostRebootTimeout)
+		defer cancel()
+		out, err := exec.CommandContext(cmdCtx, "sh", "-c", command).CombinedOutput() //nolint:gosec // the command comes from the host's own configuration
+		if err != nil {
+			slog.ErrorContext(runCtx, "Host reboot command failed", "error", err, "output", strings.TrimSpace(string(out)))
+		}
+	})
+	return nil
+}
+
+func (s *PowerService) rebootCommandInternal() string {
+	if s.cfg == nil {
+		return ""
+	}
+	return strings.TrimSpace(s.cfg.HostRebootCommand)
+}
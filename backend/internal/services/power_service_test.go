@@ -0,0 +1,129 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/config"
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/types/environment"
+	"github.com/getarcaneapp/arcane/types/system"
+)
+
+func TestParseMACAddress(t *testing.T) {
+	for _, mac := range []string{"AA:BB:CC:DD:EE:FF", "aa-bb-cc-dd-ee-ff", "aabb.ccdd.eeff"} {
+		hw, err := ParseMACAddress(mac)
+		require.NoError(t, err, mac)
+		assert.Equal(t, "aa:bb:cc:dd:ee:ff", hw.String())
+	}
+
+	_, err := ParseMACAddress("aa:bb:cc")
+	require.ErrorIs(t, err, ErrInvalidWakeTarget)
+	// 64-bit addresses are not used by Wake-on-LAN.
+	_, err = ParseMACAddress("00:00:00:00:fe:80:00:00")
+	require.ErrorIs(t, err, ErrInvalidWakeTarget)
+}
+
+func TestResolveWakeBroadcast(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{"", "255.255.255.255:9"},
+		{"192.168.1.255", "192.168.1.255:9"},
+		{"192.168.1.255:7", "192.168.1.255:7"},
+		{"lan.example.com", "lan.example.com:9"},
+		{"[ff02::1]:9", "[ff02::1]:9"},
+	}
+	for _, tt := range tests {
+		got, err := ResolveWakeBroadcast(tt.in)
+		require.NoError(t, err, tt.in)
+		assert.Equal(t, tt.want, got)
+	}
+
+	_, err := ResolveWakeBroadcast("192.168.1.255:70000")
+	require.ErrorIs(t, err, ErrInvalidWakeTarget)
+	_, err = ResolveWakeBroadcast(":9")
+	require.ErrorIs(t, err, ErrInvalidWakeTarget)
+}
+
+func TestValidateEnvironmentWake(t *testing.T) {
+	mac, broadcast, relay, empty := "aa:bb:cc:dd:ee:ff", "10.0.0.255", "2", ""
+	require.NoError(t, ValidateEnvironmentWake("1", &environment.Update{MacAddress: &mac, WakeBroadcast: &broadcast, WakeRelayID: &relay}))
+	require.NoError(t, ValidateEnvironmentWake("1", &environment.Update{MacAddress: &empty}))
+
+	bad := "nope"
+	require.ErrorIs(t, ValidateEnvironmentWake("1", &environment.Update{MacAddress: &bad}), ErrInvalidWakeTarget)
+	require.ErrorIs(t, ValidateEnvironmentWake("2", &environment.Update{WakeRelayID: &relay}), ErrInvalidWakeTarget)
+}
+
+func TestPowerService_SendWakeOnLAN(t *testing.T) {
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	svc := NewPowerService(&config.Config{}, nil, nil)
+	require.NoError(t, svc.SendWakeOnLAN(context.Background(), "aa:bb:cc:dd:ee:ff", listener.LocalAddr().String()))
+
+	require.NoError(t, listener.SetReadDeadline(time.Now().Add(2*time.Second)))
+	buf := make([]byte, 256)
+	n, _, err := listener.ReadFrom(buf)
+	require.NoError(t, err)
+	require.Equal(t, 102, n)
+	assert.Equal(t, bytes.Repeat([]byte{0xFF}, 6), buf[:6])
+	assert.Equal(t, []byte{0xaa, 0xbb, 0xcc, 0xdd, 0xee, 0xff}, buf[96:102])
+}
+
+func TestPowerService_WakeEnvironment(t *testing.T) {
+	ctx := context.Background()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Environment{}))
+	db := &database.DB{DB: gdb}
+
+	listener, err := net.ListenPacket("udp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer func() { _ = listener.Close() }()
+
+	require.NoError(t, gdb.Create(&models.Environment{Name: "nas", BaseModel: models.BaseModel{ID: "nas"}}).Error)
+	require.NoError(t, gdb.Create(&models.Environment{
+		Name:          "server",
+		MacAddress:    "aa:bb:cc:dd:ee:ff",
+		WakeBroadcast: listener.LocalAddr().String(),
+		BaseModel:     models.BaseModel{ID: "server"},
+	}).Error)
+
+	svc := NewPowerService(&config.Config{}, NewEnvironmentService(db, nil, nil, nil, nil), nil)
+	user := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "admin"}
+
+	require.ErrorIs(t, svc.WakeEnvironment(ctx, "nas", user), ErrWakeNotConfigured)
+	require.NoError(t, svc.WakeEnvironment(ctx, "server", user))
+
+	require.NoError(t, listener.SetReadDeadline(time.Now().Add(2*time.Second)))
+	buf := make([]byte, 256)
+	n, _, err := listener.ReadFrom(buf)
+	require.NoError(t, err)
+	assert.Equal(t, 102, n)
+}
+
+func TestPowerService_RebootHostGuards(t *testing.T) {
+	ctx := context.Background()
+	user := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "admin"}
+
+	svc := NewPowerService(&config.Config{}, nil, nil)
+	assert.False(t, svc.Capabilities().RebootEnabled)
+	require.ErrorIs(t, svc.RebootHost(ctx, system.RebootRequest{Confirm: true}, user), ErrRebootNotConfigured)
+
+	svc = NewPowerService(&config.Config{HostRebootCommand: "true"}, nil, nil)
+	assert.True(t, svc.Capabilities().RebootEnabled)
+	require.ErrorIs(t, svc.RebootHost(ctx, system.RebootRequest{}, user), ErrRebootNotConfirmed)
+}
@@ -0,0 +1,416 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/client"
+	"github.com/docker/docker/pkg/stdcopy"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/timeouts"
+	projecttypes "github.com/getarcaneapp/arcane/types/project"
+)
+
+const (
+	DeployHookPhasePre  = "pre"
+	DeployHookPhasePost = "post"
+
+	DeployHookTypeExec    = "exec"
+	DeployHookTypeHelper  = "helper"
+	DeployHookTypeWebhook = "webhook"
+
+	DeployHookOnFailureAbort = "abort"
+	DeployHookOnFailureWarn  = "warn"
+
+	defaultDeployHookTimeout = 60
+	maxDeployHookOutput      = 1024
+
+	// deployHookLabel marks the one-off containers of helper hooks.
+	deployHookLabel = "com.getarcaneapp.arcane.deploy-hook"
+)
+
+var (
+	ErrProjectNotFound   = errors.New("project not found")
+	ErrInvalidDeployHook = errors.New("invalid deploy hook")
+	ErrDeployHookFailed  = errors.New("deploy hook failed")
+)
+
+// ProjectHookService stores the deploy hooks of projects and runs them
+// around compose up.
+type ProjectHookService struct {
+	db            *database.DB
+	dockerService *DockerClientService
+	imageService  *ImageService
+	eventService  *EventService
+	httpClient    *http.Client
+}
+
+func NewProjectHookService(db *database.DB, dockerService *DockerClientService, imageService *ImageService, eventService *EventService, httpClient *http.Client) *ProjectHookService {
+	return &ProjectHookService{
+		db:            db,
+		dockerService: dockerService,
+		imageService:  imageService,
+		eventService:  eventService,
+		httpClient:    httpClient,
+	}
+}
+
+// ListHooks returns the deploy hooks of a project, pre hooks first.
+func (s *ProjectHookService) ListHooks(ctx context.Context, projectID string) ([]projecttypes.DeployHook, error) {
+	if err := s.ensureProjectInternal(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	var hooks []models.ProjectDeployHook
+	if err := s.db.WithContext(ctx).
+		Where("project_id = ?", projectID).
+		Order("CASE WHEN phase = 'pre' THEN 0 ELSE 1 END, position ASC").
+		Find(&hooks).Error; err != nil {
+		return nil, fmt.Errorf("failed to list deploy hooks: %w", err)
+	}
+
+	out := make([]projecttypes.DeployHook, 0, len(hooks))
+	for _, h := range hooks {
+		out = append(out, toDeployHookDTO(h))
+	}
+	return out, nil
+}
+
+// SetHooks replaces all deploy hooks of a project.
+func (s *ProjectHookService) SetHooks(ctx context.Context, projectID string, req projecttypes.DeployHooksRequest, user models.User) ([]projecttypes.DeployHook, error) {
+	if err := s.ensureProjectInternal(ctx, projectID); err != nil {
+		return nil, err
+	}
+
+	hooks := make([]models.ProjectDeployHook, 0, len(req.Hooks))
+	positions := map[string]int{}
+	for i, r := range req.Hooks {
+		r, err := normalizeDeployHookRequestInternal(r)
+		if err != nil {
+			return nil, fmt.Errorf("hook %d: %w", i+1, err)
+		}
+		hooks = append(hooks, models.ProjectDeployHook{
+			ProjectID:      projectID,
+			Phase:          r.Phase,
+			Position:       positions[r.Phase],
+			Type:           r.Type,
+			Service:        r.Service,
+			Image:          r.Image,
+			Command:        r.Command,
+			URL:            r.URL,
+			TimeoutSeconds: r.TimeoutSeconds,
+			OnFailure:      r.OnFailure,
+			Enabled:        !r.Disabled,
+			UpdatedBy:      user.Username,
+		})
+		positions[r.Phase]++
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ?", projectID).Delete(&models.ProjectDeployHook{}).Error; err != nil {
+			return err
+		}
+		if len(hooks) == 0 {
+			return nil
+		}
+		return tx.Create(&hooks).Error
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save deploy hooks: %w", err)
+	}
+
+	return s.ListHooks(ctx, projectID)
+}
+
+// DeleteProjectHooks removes the deploy hooks of a deleted project.
+func (s *ProjectHookService) DeleteProjectHooks(ctx context.Context, projectID string) error {
+	if err := s.db.WithContext(ctx).Where("project_id = ?", projectID).Delete(&models.ProjectDeployHook{}).Error; err != nil {
+		return fmt.Errorf("failed to delete deploy hooks: %w", err)
+	}
+	return nil
+}
+
+// RunHooks runs the enabled hooks of a phase in order. A failing abort hook
+// stops the run and its error wraps ErrDeployHookFailed; a failing warn hook
+// is recorded as a project error event and the run continues.
+func (s *ProjectHookService) RunHooks(ctx context.Context, project *models.Project, composeProjectName, phase string, user models.User) error {
+	var hooks []models.ProjectDeployHook
+	if err := s.db.WithContext(ctx).
+		Where("project_id = ? AND phase = ? AND enabled = ?", project.ID, phase, true).
+		Order("position ASC").
+		Find(&hooks).Error; err != nil {
+		return fmt.Errorf("failed to load deploy hooks: %w", err)
+	}
+
+	for i := range hooks {
+		hook := &hooks[i]
+		start := time.Now()
+		output, err := s.runHookInternal(ctx, project, composeProjectName, hook, user)
+		if err == nil {
+			slog.InfoContext(ctx, "Deploy hook succeeded", "project", project.Name, "phase", phase, "type", hook.Type, "duration", time.Since(start))
+			continue
+		}
+
+		metadata := models.JSON{
+			"action":    "deploy_hook",
+			"phase":     phase,
+			"hookId":    hook.ID,
+			"hookType":  hook.Type,
+			"onFailure": hook.OnFailure,
+		}
+		if output != "" {
+			metadata["output"] = output
+		}
+		if s.eventService != nil {
+			s.eventService.LogErrorEvent(ctx, models.EventTypeProjectError, "project", project.ID, project.Name, user.ID, user.Username, "0", err, metadata)
+		}
+		if hook.OnFailure == DeployHookOnFailureWarn {
+			slog.WarnContext(ctx, "Deploy hook failed, continuing", "project", project.Name, "phase", phase, "type", hook.Type, "error", err)
+			continue
+		}
+		return fmt.Errorf("%w: %s-deploy %s hook %d: %w", ErrDeployHookFailed, phase, hook.Type, hook.Position+1, err)
+	}
+	return nil
+}
+
+func (s *ProjectHookService) runHookInternal(ctx context.Context, project *models.Project, composeProjectName string, hook *models.ProjectDeployHook, user models.User) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(hook.TimeoutSeconds)*time.Second)
+	defer cancel()
+
+	if hook.Type == DeployHookTypeWebhook {
+		return "", s.callWebhookHookInternal(ctx, project, hook)
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return "", fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	switch hook.Type {
+	case DeployHookTypeExec:
+		containerID, err := serviceContainerInternal(ctx, dockerClient, composeProjectName, hook.Service)
+		if err != nil {
+			return "", err
+		}
+		output, err := execHealthcheckInternal(ctx, dockerClient, containerID, []string{"sh", "-c", hook.Command})
+		return truncateHookOutputInternal(output), err
+	case DeployHookTypeHelper:
+		return s.runHelperHookInternal(ctx, dockerClient, project, composeProjectName, hook, user)
+	default:
+		return "", fmt.Errorf("%w: unknown type %q", ErrInvalidDeployHook, hook.Type)
+	}
+}
+
+// serviceContainerInternal returns a running container of a compose service.
+func serviceContainerInternal(ctx context.Context, dockerClient *client.Client, composeProjectName, service string) (string, error) {
+	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{
+		Filters: filters.NewArgs(
+			filters.Arg("label", "com.docker.compose.project="+composeProjectName),
+			filters.Arg("label", "com.docker.compose.service="+service),
+			filters.Arg("status", "running"),
+		),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list containers of service %s: %w", service, err)
+	}
+	if len(containers) == 0 {
+		return "", fmt.Errorf("service %s has no running container", service)
+	}
+	return containers[0].ID, nil
+}
+
+// runHelperHookInternal runs the command in a one-off container, attached to
+// the project's default network when it exists, and removes it afterwards.
+func (s *ProjectHookService) runHelperHookInternal(ctx context.Context, dockerClient *client.Client, project *models.Project, composeProjectName string, hook *models.ProjectDeployHook, user models.User) (string, error) {
+	if _, err := dockerClient.ImageInspect(ctx, hook.Image); err != nil {
+		if !cerrdefs.IsNotFound(err) || s.imageService == nil {
+			return "", fmt.Errorf("failed to inspect helper image %s: %w", hook.Image, err)
+		}
+		if err := s.imageService.PullImage(ctx, hook.Image, io.Discard, user, nil); err != nil {
+			return "", err
+		}
+	}
+
+	hostConfig := &container.HostConfig{}
+	networkName := composeProjectName + "_default"
+	if _, err := dockerClient.NetworkInspect(ctx, networkName, network.InspectOptions{}); err == nil {
+		hostConfig.NetworkMode = container.NetworkMode(networkName)
+	}
+
+	created, err := dockerClient.ContainerCreate(ctx, &container.Config{
+		Image:      hook.Image,
+		Entrypoint: []string{"sh", "-c"},
+		Cmd:        []string{hook.Command},
+		Labels:     map[string]string{deployHookLabel: project.ID},
+	}, hostConfig, nil, nil, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to create helper container: %w", err)
+	}
+	defer func() {
+		// Remove the container even when the hook timed out.
+		removeCtx, cancel := timeouts.WithTimeout(context.WithoutCancel(ctx), 0, timeouts.DefaultDockerAPI)
+		defer cancel()
+		if err := dockerClient.ContainerRemove(removeCtx, created.ID, container.RemoveOptions{Force: true}); err != nil {
+			slog.WarnContext(ctx, "Failed to remove deploy hook container", "container", created.ID, "error", err)
+		}
+	}()
+
+	if err := dockerClient.ContainerStart(ctx, created.ID, container.StartOptions{}); err != nil {
+		return "", fmt.Errorf("failed to start helper container: %w", err)
+	}
+
+	var exitCode int64
+	statusCh, errCh := dockerClient.ContainerWait(ctx, created.ID, container.WaitConditionNotRunning)
+	select {
+	case status := <-statusCh:
+		exitCode = status.StatusCode
+	case err := <-errCh:
+		return "", fmt.Errorf("failed to wait for helper container: %w", err)
+	}
+
+	var output bytes.Buffer
+	if logs, err := dockerClient.ContainerLogs(ctx, created.ID, container.LogsOptions{ShowStdout: true, ShowStderr: true, Tail: "20"}); err == nil {
+		_, _ = stdcopy.StdCopy(&output, &output, io.LimitReader(logs, 64*1024))
+		logs.Close()
+	}
+	text := truncateHookOutputInternal(strings.TrimSpace(output.String()))
+	if exitCode != 0 {
+		return text, fmt.Errorf("helper container exited with status %d", exitCode)
+	}
+	return text, nil
+}
+
+func (s *ProjectHookService) callWebhookHookInternal(ctx context.Context, project *models.Project, hook *models.ProjectDeployHook) error {
+	body, err := json.Marshal(map[string]string{
+		"event":       "deploy",
+		"phase":       hook.Phase,
+		"projectId":   project.ID,
+		"projectName": project.Name,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	httpClient := s.httpClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook returned status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+func (s *ProjectHookService) ensureProjectInternal(ctx context.Context, projectID string) error {
+	var count int64
+	if err := s.db.WithContext(ctx).Model(&models.Project{}).Where("id = ?", projectID).Count(&count).Error; err != nil {
+		return fmt.Errorf("failed to load project: %w", err)
+	}
+	if count == 0 {
+		return ErrProjectNotFound
+	}
+	return nil
+}
+
+func normalizeDeployHookRequestInternal(req projecttypes.DeployHookRequest) (projecttypes.DeployHookRequest, error) {
+	req.Phase = strings.ToLower(strings.TrimSpace(req.Phase))
+	if req.Phase != DeployHookPhasePre && req.Phase != DeployHookPhasePost {
+		return req, fmt.Errorf("%w: phase must be pre or post", ErrInvalidDeployHook)
+	}
+
+	req.Type = strings.ToLower(strings.TrimSpace(req.Type))
+	req.Service = strings.TrimSpace(req.Service)
+	req.Image = strings.TrimSpace(req.Image)
+	req.Command = strings.TrimSpace(req.Command)
+	req.URL = strings.TrimSpace(req.URL)
+	switch req.Type {
+	case DeployHookTypeExec:
+		if req.Service == "" || req.Command == "" {
+			return req, fmt.Errorf("%w: exec hooks need a service and a command", ErrInvalidDeployHook)
+		}
+		req.Image, req.URL = "", ""
+	case DeployHookTypeHelper:
+		if req.Image == "" || req.Command == "" {
+			return req, fmt.Errorf("%w: helper hooks need an image and a command", ErrInvalidDeployHook)
+		}
+		req.Service, req.URL = "", ""
+	case DeployHookTypeWebhook:
+		u, err := url.Parse(req.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return req, fmt.Errorf("%w: webhook hooks need an http or https URL", ErrInvalidDeployHook)
+		}
+		req.Service, req.Image, req.Command = "", "", ""
+	default:
+		return req, fmt.Errorf("%w: type must be exec, helper or webhook", ErrInvalidDeployHook)
+	}
+
+	if req.TimeoutSeconds == 0 {
+		req.TimeoutSeconds = defaultDeployHookTimeout
+	}
+	if req.TimeoutSeconds < 1 || req.TimeoutSeconds > 3600 {
+		return req, fmt.Errorf("%w: timeout must be between 1 and 3600 seconds", ErrInvalidDeployHook)
+	}
+
+	req.OnFailure = strings.ToLower(strings.TrimSpace(req.OnFailure))
+	switch req.OnFailure {
+	case "":
+		req.OnFailure = DeployHookOnFailureAbort
+	case DeployHookOnFailureAbort, DeployHookOnFailureWarn:
+	default:
+		return req, fmt.Errorf("%w: onFailure must be abort or warn", ErrInvalidDeployHook)
+	}
+	return req, nil
+}
+
+// truncateHookOutputInternal keeps the end of the output, where errors are.
+func truncateHookOutputInternal(output string) string {
+	if len(output) <= maxDeployHookOutput {
+		return output
+	}
+	return "…" + output[len(output)-maxDeployHookOutput:]
+}
+
+func toDeployHookDTO(h models.ProjectDeployHook) projecttypes.DeployHook {
+	return projecttypes.DeployHook{
+		DeployHookRequest: projecttypes.DeployHookRequest{
+			Phase:          h.Phase,
+			Type:           h.Type,
+			Service:        h.Service,
+			Image:          h.Image,
+			Command:        h.Command,
+			URL:            h.URL,
+			TimeoutSeconds: h.TimeoutSeconds,
+			OnFailure:      h.OnFailure,
+			Disabled:       !h.Enabled,
+		},
+		ID:        h.ID,
+		ProjectID: h.ProjectID,
+		UpdatedBy: h.UpdatedBy,
+	}
+}
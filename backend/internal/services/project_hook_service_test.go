@@ -0,0 +1,104 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	projecttypes "github.com/getarcaneapp/arcane/types/project"
+)
+
+func TestNormalizeDeployHookRequest(t *testing.T) {
+	req, err := normalizeDeployHookRequestInternal(projecttypes.DeployHookRequest{
+		Phase: " PRE ", Type: "exec", Service: "db", Command: " pg_dump app > /backup.sql ", URL: "http://ignored",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "pre", req.Phase)
+	assert.Equal(t, "pg_dump app > /backup.sql", req.Command)
+	assert.Empty(t, req.URL)
+	assert.Equal(t, defaultDeployHookTimeout, req.TimeoutSeconds)
+	assert.Equal(t, DeployHookOnFailureAbort, req.OnFailure)
+
+	for _, bad := range []projecttypes.DeployHookRequest{
+		{Phase: "during", Type: "exec", Service: "db", Command: "true"},
+		{Phase: "pre", Type: "exec", Command: "true"},
+		{Phase: "pre", Type: "helper", Command: "true"},
+		{Phase: "post", Type: "webhook", URL: "ftp://example.com"},
+		{Phase: "post", Type: "webhook", URL: "https://example.com", OnFailure: "ignore"},
+		{Phase: "post", Type: "webhook", URL: "https://example.com", TimeoutSeconds: 7200},
+		{Phase: "post", Type: "script", Command: "true"},
+	} {
+		_, err := normalizeDeployHookRequestInternal(bad)
+		require.ErrorIs(t, err, ErrInvalidDeployHook, "%+v", bad)
+	}
+}
+
+func TestProjectHookService_SetAndRunHooks(t *testing.T) {
+	ctx := context.Background()
+	db := setupProjectTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.ProjectDeployHook{}, &models.Event{}))
+	project := &models.Project{Name: "app", Path: "/tmp/app", Status: models.ProjectStatusStopped}
+	require.NoError(t, db.Create(project).Error)
+
+	var calls []map[string]string
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		_ = json.NewDecoder(r.Body).Decode(&body)
+		calls = append(calls, body)
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer webhook.Close()
+
+	svc := NewProjectHookService(db, nil, nil, NewEventService(db), webhook.Client())
+	user := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "alice"}
+
+	_, err := svc.ListHooks(ctx, "missing")
+	require.ErrorIs(t, err, ErrProjectNotFound)
+
+	hooks, err := svc.SetHooks(ctx, project.ID, projecttypes.DeployHooksRequest{Hooks: []projecttypes.DeployHookRequest{
+		{Phase: "post", Type: "webhook", URL: webhook.URL + "/notify"},
+		{Phase: "pre", Type: "webhook", URL: webhook.URL + "/fail", OnFailure: "warn"},
+		{Phase: "pre", Type: "webhook", URL: webhook.URL + "/ready"},
+		{Phase: "pre", Type: "webhook", URL: webhook.URL + "/disabled", Disabled: true},
+	}}, user)
+	require.NoError(t, err)
+	require.Len(t, hooks, 4)
+	assert.Equal(t, "pre", hooks[0].Phase)
+	assert.Equal(t, webhook.URL+"/fail", hooks[0].URL)
+	assert.Equal(t, "post", hooks[3].Phase)
+	assert.Equal(t, "alice", hooks[0].UpdatedBy)
+
+	// The failing warn hook is recorded and the next hook still runs.
+	require.NoError(t, svc.RunHooks(ctx, project, "app", DeployHookPhasePre, user))
+	require.Len(t, calls, 2)
+	assert.Equal(t, "pre", calls[1]["phase"])
+	assert.Equal(t, project.ID, calls[1]["projectId"])
+	var failures int64
+	require.NoError(t, db.Model(&models.Event{}).Where("type = ?", models.EventTypeProjectError).Count(&failures).Error)
+	assert.Equal(t, int64(1), failures)
+
+	// An aborting hook stops the run.
+	_, err = svc.SetHooks(ctx, project.ID, projecttypes.DeployHooksRequest{Hooks: []projecttypes.DeployHookRequest{
+		{Phase: "post", Type: "webhook", URL: webhook.URL + "/fail"},
+		{Phase: "post", Type: "webhook", URL: webhook.URL + "/notify"},
+	}}, user)
+	require.NoError(t, err)
+	calls = nil
+	err = svc.RunHooks(ctx, project, "app", DeployHookPhasePost, user)
+	require.ErrorIs(t, err, ErrDeployHookFailed)
+	assert.Contains(t, err.Error(), "status 502")
+	assert.Len(t, calls, 1)
+
+	require.NoError(t, svc.DeleteProjectHooks(ctx, project.ID))
+	hooks, err = svc.ListHooks(ctx, project.ID)
+	require.NoError(t, err)
+	assert.Empty(t, hooks)
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/getarcaneapp/arcane/backend/pkg/projects"
+	"github.com/getarcaneapp/arcane/types/project"
+)
+
+var ErrInvalidIncludeGraph = errors.New("invalid compose include graph")
+
+// GetProjectIncludeGraph returns the include graph of a project's compose
+// files with its cycles and missing files.
+func (s *ProjectService) GetProjectIncludeGraph(ctx context.Context, projectID string) (*project.IncludeGraph, error) {
+	proj, err := s.projectForInputsInternal(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	composeFile, err := projects.DetectComposeFile(proj.Path)
+	if err != nil {
+		return nil, fmt.Errorf("no compose file found in project directory: %s", proj.Path)
+	}
+
+	graph, err := projects.BuildIncludeGraph(composeFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build include graph: %w", err)
+	}
+	return &graph, nil
+}
+
+// validateIncludeGraphInternal rejects a compose file whose includes form a
+// cycle, point at missing files or fail to parse.
+func validateIncludeGraphInternal(composeFile string) error {
+	graph, err := projects.BuildIncludeGraph(composeFile)
+	if err != nil {
+		return fmt.Errorf("failed to build include graph: %w", err)
+	}
+	if graph.Valid {
+		return nil
+	}
+
+	var problems []string
+	for _, cycle := range graph.Cycles {
+		problems = append(problems, "include cycle "+strings.Join(cycle, " -> "))
+	}
+	for _, missing := range graph.Missing {
+		problems = append(problems, "missing include file "+missing)
+	}
+	for _, node := range graph.Nodes {
+		if node.Error != "" {
+			problems = append(problems, node.Path+": "+node.Error)
+		}
+	}
+	return fmt.Errorf("%w: %s", ErrInvalidIncludeGraph, strings.Join(problems, "; "))
+}
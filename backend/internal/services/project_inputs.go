@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/dotenv"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/fs"
+	"github.com/getarcaneapp/arcane/backend/pkg/projects"
+	"github.com/getarcaneapp/arcane/types/project"
+)
+
+var ErrInvalidProjectInput = errors.New("invalid project input")
+
+// GetProjectInputs returns the x-arcane inputs of a project with their
+// current values from its .env file. Secret values are never returned.
+func (s *ProjectService) GetProjectInputs(ctx context.Context, projectID string) ([]project.InputState, error) {
+	proj, err := s.projectForInputsInternal(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs, envValues, err := s.readProjectInputsInternal(proj)
+	if err != nil {
+		return nil, err
+	}
+	applied, err := s.appliedInputFingerprintsInternal(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	return inputStatesInternal(inputs, envValues, applied), nil
+}
+
+// SetProjectInputs validates input values and renders them into the .env
+// file of a project. Inputs without a given value keep their current value
+// or get their default. Afterwards no input is pending.
+func (s *ProjectService) SetProjectInputs(ctx context.Context, projectID string, req project.InputsRequest, user models.User) ([]project.InputState, error) {
+	proj, err := s.projectForInputsInternal(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	inputs, envValues, err := s.readProjectInputsInternal(proj)
+	if err != nil {
+		return nil, err
+	}
+	for name := range req.Values {
+		if !slices.ContainsFunc(inputs, func(in project.Input) bool { return in.Name == name }) {
+			return nil, fmt.Errorf("%w: %s is not declared in x-arcane.inputs", ErrInvalidProjectInput, name)
+		}
+	}
+
+	values, err := resolveInputValuesInternal(inputs, envValues, req.Values)
+	if err != nil {
+		return nil, err
+	}
+
+	projectsDirectory, err := fs.GetProjectsDirectory(ctx, s.settingsService.GetStringSetting(ctx, "projectsDirectory", "/app/data/projects"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get projects directory: %w", err)
+	}
+	_, envContent, err := fs.ReadProjectFiles(proj.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read project files: %w", err)
+	}
+	if err := fs.WriteEnvFile(projectsDirectory, proj.Path, projects.SetEnvValues(envContent, values)); err != nil {
+		return nil, err
+	}
+
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("project_id = ?", projectID).Delete(&models.ProjectInput{}).Error; err != nil {
+			return err
+		}
+		for _, in := range inputs {
+			record := models.ProjectInput{
+				ProjectID:   projectID,
+				Name:        in.Name,
+				Fingerprint: projects.InputFingerprint(in),
+				UpdatedBy:   user.Username,
+			}
+			if err := tx.Create(&record).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to save project inputs: %w", err)
+	}
+
+	// Only names are logged; values may be secrets.
+	names := make([]string, 0, len(req.Values))
+	for name := range req.Values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	metadata := models.JSON{"action": "set_inputs", "projectID": projectID, "projectName": proj.Name, "inputs": names}
+	if logErr := s.eventService.LogProjectEvent(ctx, models.EventTypeProjectUpdate, projectID, proj.Name, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.ErrorContext(ctx, "could not log project inputs update", "error", logErr)
+	}
+
+	for name, value := range values {
+		envValues[name] = value
+	}
+	applied := make(map[string]string, len(inputs))
+	for _, in := range inputs {
+		applied[in.Name] = projects.InputFingerprint(in)
+	}
+	return inputStatesInternal(inputs, envValues, applied), nil
+}
+
+// DeleteProjectInputs removes the input records of a deleted project.
+func (s *ProjectService) DeleteProjectInputs(ctx context.Context, projectID string) error {
+	if err := s.db.WithContext(ctx).Where("project_id = ?", projectID).Delete(&models.ProjectInput{}).Error; err != nil {
+		return fmt.Errorf("failed to delete project inputs: %w", err)
+	}
+	return nil
+}
+
+func (s *ProjectService) projectForInputsInternal(ctx context.Context, projectID string) (*models.Project, error) {
+	var proj models.Project
+	if err := s.db.WithContext(ctx).Where("id = ?", projectID).First(&proj).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrProjectNotFound
+		}
+		return nil, fmt.Errorf("failed to get project: %w", err)
+	}
+	return &proj, nil
+}
+
+// readProjectInputsInternal parses the inputs of a project's compose file
+// and the literal values of its .env file.
+func (s *ProjectService) readProjectInputsInternal(proj *models.Project) ([]project.Input, map[string]string, error) {
+	composeContent, envContent, err := fs.ReadProjectFiles(proj.Path)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read project files: %w", err)
+	}
+	inputs, err := projects.ParseComposeInputs(composeContent)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %w", ErrInvalidProjectInput, err)
+	}
+	envValues, err := dotenv.UnmarshalWithLookup(envContent, func(string) (string, bool) { return "", false })
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse .env file: %w", err)
+	}
+	return inputs, envValues, nil
+}
+
+func (s *ProjectService) appliedInputFingerprintsInternal(ctx context.Context, projectID string) (map[string]string, error) {
+	var records []models.ProjectInput
+	if err := s.db.WithContext(ctx).Where("project_id = ?", projectID).Find(&records).Error; err != nil {
+		return nil, fmt.Errorf("failed to get project inputs: %w", err)
+	}
+	applied := make(map[string]string, len(records))
+	for _, r := range records {
+		applied[r.Name] = r.Fingerprint
+	}
+	return applied, nil
+}
+
+// resolveInputValuesInternal picks the value of every input, from the
+// request, the current .env or the default in that order, and validates it.
+func resolveInputValuesInternal(inputs []project.Input, envValues, requested map[string]string) (map[string]string, error) {
+	values := make(map[string]string, len(inputs))
+	for _, in := range inputs {
+		value, given := requested[in.Name]
+		if !given {
+			value = envValues[in.Name]
+			if value == "" {
+				value = in.Default
+			}
+		}
+		value = strings.TrimSpace(value)
+		if err := projects.ValidateInputValue(in, value); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidProjectInput, err)
+		}
+		values[in.Name] = value
+	}
+	return values, nil
+}
+
+func inputStatesInternal(inputs []project.Input, envValues, applied map[string]string) []project.InputState {
+	states := make([]project.InputState, 0, len(inputs))
+	for _, in := range inputs {
+		value, hasValue := envValues[in.Name]
+		hasValue = hasValue && value != ""
+		state := project.InputState{
+			Input:    in,
+			HasValue: hasValue,
+			Pending:  applied[in.Name] != projects.InputFingerprint(in) || (in.Required && !hasValue),
+		}
+		if in.Type != project.InputTypeSecret {
+			state.Value = value
+		}
+		states = append(states, state)
+	}
+	return states
+}
@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/types/project"
+)
+
+const inputsComposeV1 = `services:
+  db:
+    image: postgres
+x-arcane:
+  inputs:
+    - name: DB_PASSWORD
+      type: secret
+      required: true
+    - name: HTTP_PORT
+      type: port
+      default: 8080
+`
+
+func TestProjectService_ProjectInputs(t *testing.T) {
+	ctx := context.Background()
+	db := setupProjectTestDB(t)
+	require.NoError(t, db.AutoMigrate(&models.ProjectInput{}, &models.Event{}))
+	settingsService, err := NewSettingsService(ctx, db)
+	require.NoError(t, err)
+	root := t.TempDir()
+	require.NoError(t, settingsService.UpdateSetting(ctx, "projectsDirectory", root))
+	require.NoError(t, settingsService.LoadDatabaseSettings(ctx))
+	svc := NewProjectService(db, settingsService, NewEventService(db), nil, nil, nil, nil, nil)
+
+	dir := filepath.Join(root, "app")
+	require.NoError(t, os.MkdirAll(dir, 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte(inputsComposeV1), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, ".env"), []byte("# app\nKEEP=me\n"), 0o600))
+	require.NoError(t, db.Create(&models.Project{BaseModel: models.BaseModel{ID: "p1"}, Name: "app", Path: dir}).Error)
+	user := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "admin"}
+
+	states, err := svc.GetProjectInputs(ctx, "p1")
+	require.NoError(t, err)
+	require.Len(t, states, 2)
+	assert.True(t, states[0].Pending)
+	assert.True(t, states[1].Pending)
+
+	_, err = svc.SetProjectInputs(ctx, "p1", project.InputsRequest{Values: map[string]string{}}, user)
+	require.ErrorIs(t, err, ErrInvalidProjectInput, "required secret has no value")
+	_, err = svc.SetProjectInputs(ctx, "p1", project.InputsRequest{Values: map[string]string{"DB_PASSWORD": "s3cr$t", "HTTP_PORT": "99999"}}, user)
+	require.ErrorIs(t, err, ErrInvalidProjectInput)
+	_, err = svc.SetProjectInputs(ctx, "p1", project.InputsRequest{Values: map[string]string{"UNKNOWN": "x"}}, user)
+	require.ErrorIs(t, err, ErrInvalidProjectInput)
+
+	states, err = svc.SetProjectInputs(ctx, "p1", project.InputsRequest{Values: map[string]string{"DB_PASSWORD": "s3cr$t"}}, user)
+	require.NoError(t, err)
+	assert.Empty(t, states[0].Value, "secret values are not returned")
+	assert.True(t, states[0].HasValue)
+	assert.Equal(t, "8080", states[1].Value)
+	assert.False(t, states[0].Pending)
+	assert.False(t, states[1].Pending)
+
+	env, err := os.ReadFile(filepath.Join(dir, ".env"))
+	require.NoError(t, err)
+	assert.Equal(t, "# app\nKEEP=me\nDB_PASSWORD=\"s3cr\\$t\"\nHTTP_PORT=8080\n", string(env))
+
+	// Upgrading the compose file re-prompts only the changed and new inputs.
+	upgraded := inputsComposeV1 + "    - name: LOG_LEVEL\n      type: enum\n      options: [info, debug]\n"
+	upgraded = strings.Replace(upgraded, "default: 8080", "default: 9090", 1)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte(upgraded), 0o600))
+
+	states, err = svc.GetProjectInputs(ctx, "p1")
+	require.NoError(t, err)
+	require.Len(t, states, 3)
+	assert.False(t, states[0].Pending)
+	assert.True(t, states[1].Pending)
+	assert.True(t, states[2].Pending)
+
+	states, err = svc.SetProjectInputs(ctx, "p1", project.InputsRequest{Values: map[string]string{"LOG_LEVEL": "debug"}}, user)
+	require.NoError(t, err)
+	assert.Equal(t, "8080", states[1].Value, "existing values are kept")
+	assert.Equal(t, "debug", states[2].Value)
+
+	require.NoError(t, svc.DeleteProjectInputs(ctx, "p1"))
+	_, err = svc.GetProjectInputs(ctx, "missing")
+	require.ErrorIs(t, err, ErrProjectNotFound)
+}
@@ -33,21 +33,36 @@ import (
 )
 
 type ProjectService struct {
-	db              *database.DB
-	settingsService *SettingsService
-	eventService    *EventService
-	imageService    *ImageService
-	dockerService   *DockerClientService
+	db               *database.DB
+	settingsService  *SettingsService
+	eventService     *EventService
+	imageService     *ImageService
+	dockerService    *DockerClientService
+	operationService *OperationService
+	namespaceService *NamespaceService
+	hookService      *ProjectHookService
+	policyService    *EnvironmentPolicyService
 }
 
-func NewProjectService(db *database.DB, settingsService *SettingsService, eventService *EventService, imageService *ImageService, dockerService *DockerClientService) *ProjectService {
-	return &ProjectService{
-		db:              db,
-		settingsService: settingsService,
-		eventService:    eventService,
-		imageService:    imageService,
-		dockerService:   dockerService,
-	}
+func NewProjectService(db *database.DB, settingsService *SettingsService, eventService *EventService, imageService *ImageService, dockerService *DockerClientService, operationService *OperationService, namespaceService *NamespaceService, hookService *ProjectHookService) *ProjectService {
+	s := &ProjectService{
+		db:               db,
+		settingsService:  settingsService,
+		eventService:     eventService,
+		imageService:     imageService,
+		dockerService:    dockerService,
+		operationService: operationService,
+		namespaceService: namespaceService,
+		hookService:      hookService,
+	}
+	operationService.RegisterResumer(models.OperationKindProjectDeploy, s.resumeDeployInternal)
+	return s
+}
+
+// SetPolicyService makes project deploys subject to the local environment
+// policy.
+func (s *ProjectService) SetPolicyService(policyService *EnvironmentPolicyService) {
+	s.policyService = policyService
 }
 
 func (s *ProjectService) getPathMapper(ctx context.Context) (*pathmapper.PathMapper, error) {
@@ -532,6 +547,28 @@ func (s *ProjectService) cleanupDBProjects(ctx context.Context, seen map[string]
 	return nil
 }
 
+// EditComposeFile applies edit to the compose file of the Arcane project
+// deployed under the compose project name composeProject. The editor keeps
+// comments, anchors and x- blocks as written. It reports false when no Arcane
+// project matches.
+func (s *ProjectService) EditComposeFile(ctx context.Context, composeProject string, edit func(*projects.ComposeEditor) error) (bool, error) {
+	items, err := s.ListAllProjects(ctx)
+	if err != nil {
+		return false, err
+	}
+	for _, p := range items {
+		if normalizeComposeProjectName(p.Name) != composeProject {
+			continue
+		}
+		composeFile, err := projects.DetectComposeFile(p.Path)
+		if err != nil {
+			return true, err
+		}
+		return true, projects.EditComposeFile(composeFile, edit)
+	}
+	return false, nil
+}
+
 func (s *ProjectService) ListAllProjects(ctx context.Context) ([]models.Project, error) {
 	var items []models.Project
 	if err := s.db.WithContext(ctx).Find(&items).Error; err != nil {
@@ -699,6 +736,12 @@ func (s *ProjectService) GetProjectStatusCounts(ctx context.Context) (folderCoun
 // Project Actions
 
 func (s *ProjectService) DeployProject(ctx context.Context, projectID string, user models.User) error {
+	done, err := s.operationService.Begin(ctx, models.OperationKindProjectDeploy, projectID, models.JSON{"project_id": projectID})
+	if err != nil {
+		return err
+	}
+	defer done()
+
 	projectFromDb, err := s.GetProjectFromDatabaseByID(ctx, projectID)
 	if err != nil {
 		return fmt.Errorf("failed to get project: %w", err)
@@ -709,6 +752,10 @@ func (s *ProjectService) DeployProject(ctx context.Context, projectID string, us
 		return fmt.Errorf("no compose file found in project directory: %s", projectFromDb.Path)
 	}
 
+	if err := validateIncludeGraphInternal(composeFileFullPath); err != nil {
+		return err
+	}
+
 	// Get configured projects directory from settings
 	projectsDirSetting := s.settingsService.GetStringSetting(ctx, "projectsDirectory", "/app/data/projects")
 	projectsDirectory, pdErr := fs.GetProjectsDirectory(ctx, strings.TrimSpace(projectsDirSetting))
@@ -728,6 +775,22 @@ func (s *ProjectService) DeployProject(ctx context.Context, projectID string, us
 		return fmt.Errorf("failed to load compose project from %s: %w", projectFromDb.Path, loadErr)
 	}
 
+	if projectFromDb.Namespace != nil {
+		if err := s.namespaceService.CheckContainerQuota(ctx, *projectFromDb.Namespace, project.Name, composeQuotaRequestInternal(project)); err != nil {
+			return err
+		}
+	}
+
+	if err := s.policyService.CheckComposeProject(ctx, project); err != nil {
+		return err
+	}
+
+	if s.hookService != nil {
+		if err := s.hookService.RunHooks(ctx, projectFromDb, project.Name, DeployHookPhasePre, user); err != nil {
+			return err
+		}
+	}
+
 	if err := s.updateProjectStatusInternal(ctx, projectID, models.ProjectStatusDeploying); err != nil {
 		return fmt.Errorf("failed to update project status to deploying: %w", err)
 	}
@@ -736,6 +799,11 @@ func (s *ProjectService) DeployProject(ctx context.Context, projectID string, us
 		slog.Warn("ensure images present failed (continuing to compose up)", "projectID", projectID, "error", perr)
 	}
 
+	if err := s.checkScanGateInternal(ctx, project, user); err != nil {
+		_ = s.updateProjectStatusandCountsInternal(ctx, projectID, models.ProjectStatusStopped)
+		return err
+	}
+
 	removeOrphans := projectFromDb.GitOpsManagedBy != nil && *projectFromDb.GitOpsManagedBy != ""
 
 	slog.Info("starting compose up with health check support", "projectID", projectID, "projectName", project.Name, "services", len(project.Services), "removeOrphans", removeOrphans)
@@ -765,6 +833,14 @@ func (s *ProjectService) DeployProject(ctx context.Context, projectID string, us
 	if err != nil {
 		slog.Error("failed to update project status and counts after deploy", "projectID", projectID, "error", err)
 	}
+
+	// Post-deploy hooks run against the deployed services; an aborting hook
+	// fails the deploy but leaves the services running.
+	if s.hookService != nil {
+		if hookErr := s.hookService.RunHooks(ctx, projectFromDb, project.Name, DeployHookPhasePost, user); hookErr != nil {
+			return hookErr
+		}
+	}
 	return err
 }
 
@@ -815,9 +891,41 @@ func (s *ProjectService) DownProject(ctx context.Context, projectID string, user
 	return s.updateProjectStatusandCountsInternal(ctx, projectID, models.ProjectStatusStopped)
 }
 
+// composeQuotaRequestInternal sums the containers and memory reservations of
+// a compose project's services for namespace quota checks.
+func composeQuotaRequestInternal(project *composetypes.Project) ContainerQuotaRequest {
+	var req ContainerQuotaRequest
+	for _, svc := range project.Services {
+		replicas := svc.GetScale()
+		req.Containers += replicas
+
+		memory := int64(svc.MemReservation)
+		if memory <= 0 && svc.Deploy != nil && svc.Deploy.Resources.Reservations != nil {
+			memory = int64(svc.Deploy.Resources.Reservations.MemoryBytes)
+		}
+		if memory <= 0 {
+			memory = int64(svc.MemLimit)
+		}
+		if memory <= 0 && svc.Deploy != nil && svc.Deploy.Resources.Limits != nil {
+			memory = int64(svc.Deploy.Resources.Limits.MemoryBytes)
+		}
+		if memory <= 0 {
+			req.Unreserved += replicas
+			continue
+		}
+		req.MemoryBytes += memory * int64(replicas)
+	}
+	return req
+}
+
 func (s *ProjectService) CreateProject(ctx context.Context, name, composeContent string, envContent *string, user models.User) (*models.Project, error) {
 	sanitized := fs.SanitizeProjectName(name)
 
+	namespace, err := s.namespaceService.ResolveNamespace(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
 	projectsDirectory, err := fs.GetProjectsDirectory(ctx, s.settingsService.GetStringSetting(ctx, "projectsDirectory", "/app/data/projects"))
 	if err != nil {
 		return nil, fmt.Errorf("failed to get projects directory: %w", err)
@@ -837,6 +945,9 @@ func (s *ProjectService) CreateProject(ctx context.Context, name, composeContent
 		ServiceCount: 0,
 		RunningCount: 0,
 	}
+	if namespace != "" {
+		proj.Namespace = &namespace
+	}
 
 	if err := s.db.WithContext(ctx).Create(proj).Error; err != nil {
 		return nil, fmt.Errorf("failed to create project: %w", err)
@@ -915,6 +1026,14 @@ func (s *ProjectService) DestroyProject(ctx context.Context, projectID string, r
 	if err := s.db.WithContext(ctx).Delete(proj).Error; err != nil {
 		return fmt.Errorf("failed to delete project from database: %w", err)
 	}
+	if s.hookService != nil {
+		if err := s.hookService.DeleteProjectHooks(ctx, projectID); err != nil {
+			slog.WarnContext(ctx, "Failed to delete project deploy hooks", "projectID", projectID, "error", err)
+		}
+	}
+	if err := s.DeleteProjectInputs(ctx, projectID); err != nil {
+		slog.WarnContext(ctx, "Failed to delete project inputs", "projectID", projectID, "error", err)
+	}
 
 	metadata := models.JSON{"action": "destroy", "projectID": projectID, "projectName": proj.Name, "removeFiles": removeFiles, "removeVolumes": removeVolumes}
 	if logErr := s.eventService.LogProjectEvent(ctx, models.EventTypeProjectDelete, projectID, proj.Name, user.ID, user.Username, "0", metadata); logErr != nil {
@@ -924,6 +1043,16 @@ func (s *ProjectService) DestroyProject(ctx context.Context, projectID string, r
 	return nil
 }
 
+// resumeDeployInternal re-runs a deploy that was cut off by a restart. A
+// compose up converges on the desired state, so running it again is safe.
+func (s *ProjectService) resumeDeployInternal(ctx context.Context, op models.OperationState) error {
+	projectID, _ := op.Metadata["project_id"].(string)
+	if projectID == "" {
+		return ErrOperationNotResumable
+	}
+	return s.DeployProject(ctx, projectID, systemUser)
+}
+
 func (s *ProjectService) RedeployProject(ctx context.Context, projectID string, user models.User) error {
 	proj, err := s.GetProjectFromDatabaseByID(ctx, projectID)
 	if err != nil {
@@ -997,6 +1126,27 @@ func (s *ProjectService) PullProjectImages(ctx context.Context, projectID string
 	return nil
 }
 
+// checkScanGateInternal returns ErrScanGateBlocked when the vulnerability
+// scan gate does not allow an image of project. Images the project builds
+// itself are not pulled and are left to compose.
+func (s *ProjectService) checkScanGateInternal(ctx context.Context, project *composetypes.Project, user models.User) error {
+	checked := map[string]struct{}{}
+	for _, svc := range project.Services {
+		img := strings.TrimSpace(svc.Image)
+		if img == "" || svc.Build != nil {
+			continue
+		}
+		if _, ok := checked[img]; ok {
+			continue
+		}
+		checked[img] = struct{}{}
+		if err := s.imageService.CheckScanGate(ctx, img, user); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // EnsureProjectImagesPresent checks all compose service images for the project and
 // only pulls images that are not already available locally.
 func (s *ProjectService) EnsureProjectImagesPresent(ctx context.Context, projectID string, progressWriter io.Writer, credentials []containerregistry.Credential) error {
@@ -1110,6 +1260,254 @@ func (s *ProjectService) RestartProject(ctx context.Context, projectID string, u
 	return s.updateProjectStatusandCountsInternal(ctx, projectID, models.ProjectStatusRunning)
 }
 
+// LoadComposeProject loads a project and its compose project with the
+// configured projects directory, path mapping and env injection applied.
+func (s *ProjectService) LoadComposeProject(ctx context.Context, projectID string) (*models.Project, *composetypes.Project, error) {
+	projectFromDb, err := s.GetProjectFromDatabaseByID(ctx, projectID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get project: %w", err)
+	}
+
+	projectsDirSetting := s.settingsService.GetStringSetting(ctx, "projectsDirectory", "/app/data/projects")
+	projectsDirectory, pdErr := fs.GetProjectsDirectory(ctx, strings.TrimSpace(projectsDirSetting))
+	if pdErr != nil {
+		slog.WarnContext(ctx, "unable to determine projects directory; using default", "error", pdErr)
+		projectsDirectory = "/app/data/projects"
+	}
+
+	pathMapper, pmErr := s.getPathMapper(ctx)
+	if pmErr != nil {
+		slog.WarnContext(ctx, "failed to create path mapper, continuing without translation", "error", pmErr)
+	}
+
+	autoInjectEnv := s.settingsService.GetBoolSetting(ctx, "autoInjectEnv", false)
+	compProj, _, lerr := projects.LoadComposeProjectFromDir(ctx, projectFromDb.Path, normalizeComposeProjectName(projectFromDb.Name), projectsDirectory, autoInjectEnv, pathMapper)
+	if lerr != nil {
+		return nil, nil, fmt.Errorf("failed to load compose project: %w", lerr)
+	}
+	return projectFromDb, compProj, nil
+}
+
+const (
+	defaultCanaryBakeSeconds = 60
+	canaryPollInterval       = 2 * time.Second
+	// canaryHealthTimeout is how long a canary that is still starting at the
+	// end of its bake time may take to report a health status.
+	canaryHealthTimeout = 2 * time.Minute
+
+	CanaryOutcomePromoted   = "promoted"
+	CanaryOutcomeRolledBack = "rolled_back"
+)
+
+var (
+	ErrProjectServiceNotFound  = errors.New("service not found in project")
+	ErrCanaryNotEnoughReplicas = errors.New("canary updates need a service with at least two replicas")
+)
+
+// CanaryUpdateService updates one service of a project replica by replica.
+// A single replica is first created from the current configuration next to
+// the existing ones and watched for the bake time. If it stays running and
+// healthy, the remaining replicas are updated; otherwise the canary is
+// removed and the existing replicas are left untouched.
+func (s *ProjectService) CanaryUpdateService(ctx context.Context, projectID string, req project.CanaryUpdateRequest, user models.User) (*project.CanaryUpdateResult, error) {
+	done, err := s.operationService.Track(models.OperationKindProjectDeploy, projectID)
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	projectFromDb, compProj, err := s.LoadComposeProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+
+	svc, ok := compProj.Services[req.Service]
+	if !ok {
+		return nil, ErrProjectServiceNotFound
+	}
+	replicas := svc.GetScale()
+	if replicas < 2 {
+		return nil, ErrCanaryNotEnoughReplicas
+	}
+
+	bakeSeconds := req.BakeSeconds
+	if bakeSeconds <= 0 {
+		bakeSeconds = defaultCanaryBakeSeconds
+	}
+	result := &project.CanaryUpdateResult{
+		Service:     req.Service,
+		Replicas:    replicas,
+		BakeSeconds: bakeSeconds,
+		StartedAt:   time.Now().UTC(),
+	}
+
+	if req.Pull && svc.Image != "" {
+		if err := s.imageService.PullImage(ctx, svc.Image, io.Discard, user, nil); err != nil {
+			return nil, fmt.Errorf("failed to pull image %s: %w", svc.Image, err)
+		}
+	}
+
+	existing, err := s.serviceContainerIDsInternal(ctx, compProj, req.Service)
+	if err != nil {
+		return nil, err
+	}
+
+	// Adding one replica without recreating keeps the existing replicas on
+	// their current configuration while the new one gets the updated one.
+	if err := projects.ComposeScaleService(ctx, compProj, req.Service, replicas+1, api.RecreateNever); err != nil {
+		return nil, fmt.Errorf("failed to create canary replica: %w", err)
+	}
+
+	current, err := s.serviceContainerIDsInternal(ctx, compProj, req.Service)
+	if err != nil {
+		return nil, err
+	}
+	for id := range current {
+		if _, old := existing[id]; !old {
+			result.CanaryContainerID = id
+			break
+		}
+	}
+	if result.CanaryContainerID == "" {
+		return nil, fmt.Errorf("canary replica of service %s was not created", req.Service)
+	}
+
+	if bakeErr := s.bakeCanaryInternal(ctx, result.CanaryContainerID, time.Duration(bakeSeconds)*time.Second); bakeErr != nil {
+		// The canary has the highest container number, so scaling back down
+		// without recreating removes exactly that replica.
+		if err := projects.ComposeScaleService(ctx, compProj, req.Service, replicas, api.RecreateNever); err != nil {
+			return nil, fmt.Errorf("canary failed (%v) and could not be rolled back: %w", bakeErr, err)
+		}
+		result.Outcome = CanaryOutcomeRolledBack
+		result.Reason = bakeErr.Error()
+	} else {
+		// Scaling back down with recreation removes an outdated replica first
+		// and recreates the others, so the canary is kept.
+		if err := projects.ComposeScaleService(ctx, compProj, req.Service, replicas, api.RecreateDiverged); err != nil {
+			return nil, fmt.Errorf("failed to update remaining replicas: %w", err)
+		}
+		result.Outcome = CanaryOutcomePromoted
+	}
+	result.FinishedAt = time.Now().UTC()
+
+	eventType := models.EventTypeProjectDeploy
+	if result.Outcome == CanaryOutcomeRolledBack {
+		eventType = models.EventTypeProjectError
+	}
+	metadata := models.JSON{
+		"action":      "canary",
+		"projectID":   projectID,
+		"projectName": projectFromDb.Name,
+		"service":     req.Service,
+		"outcome":     result.Outcome,
+		"bakeSeconds": bakeSeconds,
+	}
+	if result.Reason != "" {
+		metadata["reason"] = result.Reason
+	}
+	if logErr := s.eventService.LogProjectEvent(ctx, eventType, projectID, projectFromDb.Name, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.ErrorContext(ctx, "could not log project canary update", "error", logErr)
+	}
+
+	if err := s.updateProjectStatusandCountsInternal(ctx, projectID, models.ProjectStatusRunning); err != nil {
+		slog.WarnContext(ctx, "failed to update project status after canary update", "projectID", projectID, "error", err)
+	}
+	return result, nil
+}
+
+func (s *ProjectService) serviceContainerIDsInternal(ctx context.Context, compProj *composetypes.Project, service string) (map[string]struct{}, error) {
+	containers, err := projects.ComposePs(ctx, compProj, []string{service}, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers of service %s: %w", service, err)
+	}
+	ids := make(map[string]struct{}, len(containers))
+	for _, c := range containers {
+		if c.Service == service {
+			ids[c.ID] = struct{}{}
+		}
+	}
+	return ids, nil
+}
+
+// bakeCanaryInternal watches the canary for the bake time and returns why it
+// failed, if it did. A canary with a healthcheck that is still starting when
+// the bake time ends gets up to canaryHealthTimeout to become healthy.
+func (s *ProjectService) bakeCanaryInternal(ctx context.Context, containerID string, bake time.Duration) error {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	inspect, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return fmt.Errorf("failed to inspect canary: %w", err)
+	}
+	restarts := inspect.RestartCount
+
+	bakeEnd := time.Now().Add(bake)
+	ticker := time.NewTicker(canaryPollInterval)
+	defer ticker.Stop()
+	for {
+		if reason := evaluateCanaryInternal(inspect, restarts); reason != "" {
+			return errors.New(reason)
+		}
+		if time.Now().After(bakeEnd) {
+			healthy, pending := canaryHealthInternal(inspect)
+			if healthy {
+				return nil
+			}
+			if !pending || time.Now().After(bakeEnd.Add(canaryHealthTimeout)) {
+				return errors.New("canary did not become healthy")
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+		if inspect, err = dockerClient.ContainerInspect(ctx, containerID); err != nil {
+			return fmt.Errorf("failed to inspect canary: %w", err)
+		}
+	}
+}
+
+// evaluateCanaryInternal returns why the canary is failing, or "" while it
+// is running without restarts and is not reported unhealthy.
+func evaluateCanaryInternal(inspect container.InspectResponse, initialRestarts int) string {
+	if inspect.ContainerJSONBase == nil || inspect.State == nil {
+		return "canary state is unavailable"
+	}
+	state := inspect.State
+	if !state.Running {
+		if state.ExitCode != 0 {
+			return fmt.Sprintf("canary exited with code %d", state.ExitCode)
+		}
+		return fmt.Sprintf("canary is %s", state.Status)
+	}
+	if inspect.RestartCount > initialRestarts {
+		return "canary restarted"
+	}
+	if state.Health != nil && state.Health.Status == container.Unhealthy {
+		return "canary is unhealthy"
+	}
+	return ""
+}
+
+// canaryHealthInternal reports whether the canary counts as healthy, and
+// whether its health is still pending. Containers without a healthcheck are
+// healthy while they run.
+func canaryHealthInternal(inspect container.InspectResponse) (healthy, pending bool) {
+	if inspect.ContainerJSONBase == nil || inspect.State == nil {
+		return false, false
+	}
+	health := inspect.State.Health
+	if health == nil || health.Status == "" || health.Status == container.NoHealthcheck {
+		return true, false
+	}
+	return health.Status == container.Healthy, health.Status == container.Starting
+}
+
 func (s *ProjectService) UpdateProject(ctx context.Context, projectID string, name *string, composeContent, envContent *string) (*models.Project, error) {
 	var proj models.Project
 	if err := s.db.WithContext(ctx).First(&proj, "id = ?", projectID).Error; err != nil {
@@ -1264,6 +1662,9 @@ func (s *ProjectService) StreamProjectLogs(ctx context.Context, projectID string
 
 func (s *ProjectService) ListProjects(ctx context.Context, params pagination.QueryParams) ([]project.Details, pagination.Response, error) {
 	query := s.db.WithContext(ctx).Model(&models.Project{})
+	if scope := NamespaceScopeFromContext(ctx); scope != nil {
+		query = query.Where("namespace IN ?", scope.Namespaces())
+	}
 	statusFilter := ""
 	if params.Filters != nil {
 		statusFilter = strings.TrimSpace(params.Filters["status"])
@@ -1420,8 +1821,22 @@ func (s *ProjectService) fetchProjectStatusConcurrently(ctx context.Context, pro
 
 	// 3. Map to DTOs
 	results := make([]project.Details, len(projectsList))
+	keys := make([]string, len(projectsList))
 	for i, p := range projectsList {
 		results[i] = s.mapProjectToDto(ctx, p, containersByProject)
+		keys[i] = normalizeComposeProjectName(p.Name)
+	}
+
+	// 4. Attach uptime recorded under the compose project name
+	uptime, err := loadUptimeStatsInternal(ctx, s.db, UptimeResourceProject, keys, time.Now().UTC())
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to load project uptime", "error", err)
+		return results
+	}
+	for i, key := range keys {
+		if st, ok := uptime[key]; ok {
+			results[i].Uptime = &st
+		}
 	}
 
 	return results
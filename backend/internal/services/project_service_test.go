@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	composetypes "github.com/compose-spec/compose-go/v2/types"
 	"github.com/docker/docker/api/types/container"
 	glsqlite "github.com/glebarez/sqlite"
 	"github.com/stretchr/testify/assert"
@@ -29,7 +30,7 @@ func TestProjectService_GetProjectFromDatabaseByID(t *testing.T) {
 
 	// Setup dependencies
 	settingsService, _ := NewSettingsService(ctx, db)
-	svc := NewProjectService(db, settingsService, nil, nil, nil)
+	svc := NewProjectService(db, settingsService, nil, nil, nil, nil, nil, nil)
 
 	// Create test project
 	proj := &models.Project{
@@ -146,7 +147,7 @@ func TestProjectService_CalculateProjectStatus(t *testing.T) {
 func TestProjectService_UpdateProjectStatusInternal(t *testing.T) {
 	db := setupProjectTestDB(t)
 	ctx := context.Background()
-	svc := NewProjectService(db, nil, nil, nil, nil)
+	svc := NewProjectService(db, nil, nil, nil, nil, nil, nil, nil)
 
 	proj := &models.Project{
 		BaseModel: models.BaseModel{
@@ -252,3 +253,48 @@ func TestProjectService_NormalizeComposeProjectName(t *testing.T) {
 		})
 	}
 }
+
+func TestComposeQuotaRequest(t *testing.T) {
+	replicas := 2
+	project := &composetypes.Project{Services: composetypes.Services{
+		"web":    {Name: "web", MemReservation: 64 << 20, MemLimit: 256 << 20, Scale: &replicas},
+		"worker": {Name: "worker", Deploy: &composetypes.DeployConfig{Resources: composetypes.Resources{Limits: &composetypes.Resource{MemoryBytes: 128 << 20}}}},
+		"cron":   {Name: "cron"},
+	}}
+
+	req := composeQuotaRequestInternal(project)
+	assert.Equal(t, 4, req.Containers)
+	assert.Equal(t, int64(256<<20), req.MemoryBytes)
+	assert.Equal(t, 1, req.Unreserved)
+}
+
+func TestEvaluateCanary(t *testing.T) {
+	inspect := func(state *container.State, restarts int) container.InspectResponse {
+		return container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{State: state, RestartCount: restarts}}
+	}
+	running := func(health string) *container.State {
+		state := &container.State{Status: "running", Running: true}
+		if health != "" {
+			state.Health = &container.Health{Status: health}
+		}
+		return state
+	}
+
+	assert.Empty(t, evaluateCanaryInternal(inspect(running(""), 0), 0))
+	assert.Empty(t, evaluateCanaryInternal(inspect(running(container.Starting), 1), 1))
+	assert.Equal(t, "canary restarted", evaluateCanaryInternal(inspect(running(""), 2), 1))
+	assert.Equal(t, "canary is unhealthy", evaluateCanaryInternal(inspect(running(container.Unhealthy), 0), 0))
+	assert.Equal(t, "canary exited with code 137", evaluateCanaryInternal(inspect(&container.State{Status: "exited", ExitCode: 137}, 0), 0))
+	assert.Equal(t, "canary is created", evaluateCanaryInternal(inspect(&container.State{Status: "created"}, 0), 0))
+	assert.Equal(t, "canary state is unavailable", evaluateCanaryInternal(container.InspectResponse{}, 0))
+
+	healthy, pending := canaryHealthInternal(inspect(running(""), 0))
+	assert.True(t, healthy)
+	assert.False(t, pending)
+	healthy, pending = canaryHealthInternal(inspect(running(container.Healthy), 0))
+	assert.True(t, healthy)
+	assert.False(t, pending)
+	healthy, pending = canaryHealthInternal(inspect(running(container.Starting), 0))
+	assert.False(t, healthy)
+	assert.True(t, pending)
+}
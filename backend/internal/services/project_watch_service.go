@@ -0,0 +1,248 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v5/pkg/api"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/ws"
+	"github.com/getarcaneapp/arcane/backend/pkg/projects"
+	"github.com/getarcaneapp/arcane/types/project"
+)
+
+var (
+	ErrProjectWatchRunning       = errors.New("project is already being watched")
+	ErrProjectWatchNotRunning    = errors.New("project is not being watched")
+	ErrProjectWatchNotConfigured = errors.New("none of the selected services has a develop.watch section")
+)
+
+// projectWatchFunc runs a compose watch until ctx is canceled.
+type projectWatchFunc func(ctx context.Context, proj *composetypes.Project, services []string, consumer api.LogConsumer) error
+
+// ProjectWatchService runs compose watch (the development file sync of
+// `docker compose watch`) for projects. Watches run in the background until
+// they are stopped; their progress is broadcast on a WebSocket hub.
+type ProjectWatchService struct {
+	projectService *ProjectService
+	eventService   *EventService
+	watch          projectWatchFunc
+
+	mu       sync.Mutex
+	sessions map[string]*projectWatchSession
+
+	hubOnce sync.Once
+	hub     *ws.Hub
+}
+
+type projectWatchSession struct {
+	cancel      context.CancelFunc
+	done        chan struct{}
+	projectName string
+	status      project.WatchStatus
+}
+
+func NewProjectWatchService(projectService *ProjectService, eventService *EventService) *ProjectWatchService {
+	return &ProjectWatchService{
+		projectService: projectService,
+		eventService:   eventService,
+		watch:          projects.ComposeWatch,
+		sessions:       map[string]*projectWatchSession{},
+	}
+}
+
+// ProgressHub returns the hub on which watch events of all projects are
+// broadcast, starting it on first use.
+func (s *ProjectWatchService) ProgressHub() *ws.Hub {
+	s.hubOnce.Do(func() {
+		s.hub = ws.NewHub(256)
+		go s.hub.Run(context.Background())
+	})
+	return s.hub
+}
+
+// Start begins watching a project. services limits the watch to those
+// services; empty watches all services with a develop.watch section.
+func (s *ProjectWatchService) Start(ctx context.Context, projectID string, services []string, user models.User) (*project.WatchStatus, error) {
+	projectFromDb, compProj, err := s.projectService.LoadComposeProject(ctx, projectID)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range services {
+		if _, ok := compProj.Services[name]; !ok {
+			return nil, fmt.Errorf("%w: %s", ErrProjectServiceNotFound, name)
+		}
+	}
+	if !hasWatchConfigInternal(compProj, services) {
+		return nil, ErrProjectWatchNotConfigured
+	}
+
+	status, err := s.startSessionInternal(projectID, projectFromDb.Name, compProj, services, user)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := models.JSON{"action": "watch_start", "projectID": projectID, "projectName": projectFromDb.Name, "services": services}
+	if logErr := s.eventService.LogProjectEvent(ctx, models.EventTypeProjectStart, projectID, projectFromDb.Name, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.ErrorContext(ctx, "could not log project watch start", "error", logErr)
+	}
+	return status, nil
+}
+
+func (s *ProjectWatchService) startSessionInternal(projectID, projectName string, compProj *composetypes.Project, services []string, user models.User) (*project.WatchStatus, error) {
+	s.mu.Lock()
+	if existing, ok := s.sessions[projectID]; ok && existing.status.Running {
+		s.mu.Unlock()
+		return nil, ErrProjectWatchRunning
+	}
+
+	// The watch outlives the request that started it.
+	runCtx, cancel := context.WithCancel(context.Background())
+	now := time.Now().UTC()
+	session := &projectWatchSession{
+		cancel:      cancel,
+		done:        make(chan struct{}),
+		projectName: projectName,
+		status: project.WatchStatus{
+			ProjectID: projectID,
+			Running:   true,
+			Services:  services,
+			StartedAt: &now,
+			StartedBy: user.Username,
+		},
+	}
+	s.sessions[projectID] = session
+	status := session.status
+	s.mu.Unlock()
+
+	s.publishInternal(projectID, project.WatchEventStarted, "", "Watch started")
+
+	go func() {
+		defer close(session.done)
+		err := s.watch(runCtx, compProj, services, &projectWatchConsumer{service: s, projectID: projectID})
+
+		s.mu.Lock()
+		session.status.Running = false
+		if err != nil && runCtx.Err() == nil {
+			session.status.Error = err.Error()
+		}
+		s.mu.Unlock()
+
+		if err != nil && runCtx.Err() == nil {
+			slog.Warn("Project watch stopped with an error", "projectID", projectID, "error", err)
+			s.publishInternal(projectID, project.WatchEventError, "", err.Error())
+		}
+		s.publishInternal(projectID, project.WatchEventStopped, "", "Watch stopped")
+	}()
+
+	return &status, nil
+}
+
+// Stop stops watching a project and waits for the watch to end.
+func (s *ProjectWatchService) Stop(ctx context.Context, projectID string, user models.User) error {
+	s.mu.Lock()
+	session, ok := s.sessions[projectID]
+	if !ok || !session.status.Running {
+		s.mu.Unlock()
+		return ErrProjectWatchNotRunning
+	}
+	s.mu.Unlock()
+
+	session.cancel()
+	select {
+	case <-session.done:
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+
+	metadata := models.JSON{"action": "watch_stop", "projectID": projectID, "projectName": session.projectName}
+	if logErr := s.eventService.LogProjectEvent(ctx, models.EventTypeProjectStop, projectID, session.projectName, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.ErrorContext(ctx, "could not log project watch stop", "error", logErr)
+	}
+	return nil
+}
+
+// Status returns the watch state of a project.
+func (s *ProjectWatchService) Status(projectID string) project.WatchStatus {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if session, ok := s.sessions[projectID]; ok {
+		return session.status
+	}
+	return project.WatchStatus{ProjectID: projectID}
+}
+
+func (s *ProjectWatchService) publishInternal(projectID, eventType, service, message string) {
+	message = strings.TrimRight(message, "\r\n")
+	if eventType != project.WatchEventLog {
+		s.mu.Lock()
+		if session, ok := s.sessions[projectID]; ok {
+			session.status.LastMessage = message
+		}
+		s.mu.Unlock()
+	}
+
+	hub := s.ProgressHub()
+	if hub.ClientCount() == 0 {
+		return
+	}
+	b, err := json.Marshal(project.WatchEvent{
+		ProjectID: projectID,
+		Type:      eventType,
+		Service:   service,
+		Message:   message,
+		Time:      time.Now().UTC(),
+	})
+	if err != nil {
+		slog.Warn("failed to encode project watch event", "error", err)
+		return
+	}
+	hub.Broadcast(b)
+}
+
+// hasWatchConfigInternal reports whether any of the selected services (all
+// services when empty) has watch rules.
+func hasWatchConfigInternal(proj *composetypes.Project, services []string) bool {
+	for name, svc := range proj.Services {
+		if len(services) > 0 && !slices.Contains(services, name) {
+			continue
+		}
+		if svc.Develop != nil && len(svc.Develop.Watch) > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// projectWatchConsumer turns compose watch output into watch events.
+type projectWatchConsumer struct {
+	service   *ProjectWatchService
+	projectID string
+}
+
+func (c *projectWatchConsumer) Log(containerName, message string) {
+	if containerName == api.WatchLogger {
+		c.service.publishInternal(c.projectID, project.WatchEventWatch, "", message)
+		return
+	}
+	c.service.publishInternal(c.projectID, project.WatchEventLog, containerName, message)
+}
+
+func (c *projectWatchConsumer) Err(containerName, message string) {
+	if containerName == api.WatchLogger {
+		containerName = ""
+	}
+	c.service.publishInternal(c.projectID, project.WatchEventError, containerName, message)
+}
+
+func (c *projectWatchConsumer) Status(containerName, message string) {
+	c.service.publishInternal(c.projectID, project.WatchEventWatch, containerName, message)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	composetypes "github.com/compose-spec/compose-go/v2/types"
+	"github.com/docker/compose/v5/pkg/api"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+func TestHasWatchConfig(t *testing.T) {
+	proj := &composetypes.Project{Services: composetypes.Services{
+		"web": {Name: "web", Develop: &composetypes.DevelopConfig{Watch: []composetypes.Trigger{{Path: "./src", Action: composetypes.WatchActionSync, Target: "/app"}}}},
+		"db":  {Name: "db"},
+	}}
+
+	assert.True(t, hasWatchConfigInternal(proj, nil))
+	assert.True(t, hasWatchConfigInternal(proj, []string{"web"}))
+	assert.False(t, hasWatchConfigInternal(proj, []string{"db"}))
+}
+
+func TestProjectWatchService_Session(t *testing.T) {
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Event{}))
+
+	svc := NewProjectWatchService(nil, NewEventService(&database.DB{DB: gdb}))
+	started := make(chan struct{})
+	svc.watch = func(ctx context.Context, proj *composetypes.Project, services []string, consumer api.LogConsumer) error {
+		consumer.Log(api.WatchLogger, "Watch enabled")
+		close(started)
+		<-ctx.Done()
+		return ctx.Err()
+	}
+	user := models.User{Username: "alice"}
+
+	status, err := svc.startSessionInternal("p1", "shop", &composetypes.Project{}, []string{"web"}, user)
+	require.NoError(t, err)
+	assert.True(t, status.Running)
+	assert.Equal(t, "alice", status.StartedBy)
+	<-started
+	assert.Equal(t, "Watch enabled", svc.Status("p1").LastMessage)
+
+	_, err = svc.startSessionInternal("p1", "shop", &composetypes.Project{}, nil, user)
+	require.ErrorIs(t, err, ErrProjectWatchRunning)
+
+	require.NoError(t, svc.Stop(context.Background(), "p1", user))
+	assert.False(t, svc.Status("p1").Running)
+	assert.Empty(t, svc.Status("p1").Error)
+	require.ErrorIs(t, svc.Stop(context.Background(), "p1", user), ErrProjectWatchNotRunning)
+
+	// A watch that fails on its own records why it stopped.
+	svc.watch = func(ctx context.Context, proj *composetypes.Project, services []string, consumer api.LogConsumer) error {
+		return errors.New("can't watch service \"web\" without a build context")
+	}
+	_, err = svc.startSessionInternal("p1", "shop", &composetypes.Project{}, nil, user)
+	require.NoError(t, err)
+	require.Eventually(t, func() bool { return !svc.Status("p1").Running }, time.Second, 10*time.Millisecond)
+	assert.Contains(t, svc.Status("p1").Error, "without a build context")
+	assert.False(t, svc.Status("p2").Running)
+}
@@ -24,9 +24,11 @@ import (
 	"github.com/getarcaneapp/arcane/backend/internal/config"
 	"github.com/getarcaneapp/arcane/backend/internal/database"
 	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/maintenance"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/pathmapper"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/stringutils"
 	"github.com/getarcaneapp/arcane/types/settings"
+	"github.com/getarcaneapp/arcane/types/vulnerability"
 )
 
 type SettingsService struct {
@@ -85,31 +87,67 @@ func (s *SettingsService) LoadDatabaseSettings(ctx context.Context) (err error)
 
 func (s *SettingsService) getDefaultSettings() *models.Settings {
 	return &models.Settings{
-		ProjectsDirectory:          models.SettingVariable{Value: "/app/data/projects"},
-		DiskUsagePath:              models.SettingVariable{Value: "/app/data/projects"},
-		AutoUpdate:                 models.SettingVariable{Value: "false"},
-		AutoUpdateInterval:         models.SettingVariable{Value: "0 0 0 * * *"},
-		PollingEnabled:             models.SettingVariable{Value: "true"},
-		PollingInterval:            models.SettingVariable{Value: "0 0 * * * *"},
-		EventCleanupInterval:       models.SettingVariable{Value: "0 0 */6 * * *"},
-		AnalyticsHeartbeatInterval: models.SettingVariable{Value: "0 0 0 * * *"},
-		AutoInjectEnv:              models.SettingVariable{Value: "false"},
-		PruneMode:                  models.SettingVariable{Value: "dangling"},
-		ScheduledPruneEnabled:      models.SettingVariable{Value: "false"},
-		ScheduledPruneInterval:     models.SettingVariable{Value: "0 0 0 * * *"},
-		ScheduledPruneContainers:   models.SettingVariable{Value: "true"},
-		ScheduledPruneImages:       models.SettingVariable{Value: "true"},
-		ScheduledPruneVolumes:      models.SettingVariable{Value: "false"},
-		ScheduledPruneNetworks:     models.SettingVariable{Value: "true"},
-		ScheduledPruneBuildCache:   models.SettingVariable{Value: "false"},
-		BaseServerURL:              models.SettingVariable{Value: "http://localhost"},
-		EnableGravatar:             models.SettingVariable{Value: "true"},
-		DefaultShell:               models.SettingVariable{Value: "/bin/sh"},
-		DockerHost:                 models.SettingVariable{Value: "unix:///var/run/docker.sock"},
-		AuthLocalEnabled:           models.SettingVariable{Value: "true"},
-		AuthSessionTimeout:         models.SettingVariable{Value: "1440"},
-		AuthPasswordPolicy:         models.SettingVariable{Value: "strong"},
-		TrivyImage:                 models.SettingVariable{Value: "ghcr.io/aquasecurity/trivy:latest"},
+		ProjectsDirectory:              models.SettingVariable{Value: "/app/data/projects"},
+		DiskUsagePath:                  models.SettingVariable{Value: "/app/data/projects"},
+		AutoUpdate:                     models.SettingVariable{Value: "false"},
+		AutoUpdateInterval:             models.SettingVariable{Value: "0 0 0 * * *"},
+		AutoUpdateRollbackWindow:       models.SettingVariable{Value: "60"},
+		AutoUpdateCanaryEnvironment:    models.SettingVariable{Value: ""},
+		AutoUpdateSoakPeriod:           models.SettingVariable{Value: "30"},
+		PollingEnabled:                 models.SettingVariable{Value: "true"},
+		PollingInterval:                models.SettingVariable{Value: "0 0 * * * *"},
+		EventCleanupInterval:           models.SettingVariable{Value: "0 0 */6 * * *"},
+		AnalyticsHeartbeatInterval:     models.SettingVariable{Value: "0 0 0 * * *"},
+		AutoInjectEnv:                  models.SettingVariable{Value: "false"},
+		PruneMode:                      models.SettingVariable{Value: "dangling"},
+		ScheduledPruneEnabled:          models.SettingVariable{Value: "false"},
+		ScheduledPruneInterval:         models.SettingVariable{Value: "0 0 0 * * *"},
+		ScheduledPruneContainers:       models.SettingVariable{Value: "true"},
+		ScheduledPruneImages:           models.SettingVariable{Value: "true"},
+		ScheduledPruneVolumes:          models.SettingVariable{Value: "false"},
+		ScheduledPruneNetworks:         models.SettingVariable{Value: "true"},
+		ScheduledPruneBuildCache:       models.SettingVariable{Value: "false"},
+		MaintenanceWindows:             models.SettingVariable{Value: ""},
+		MaintenanceWindowOverride:      models.SettingVariable{Value: "false"},
+		BootVerificationEnabled:        models.SettingVariable{Value: "false"},
+		BootVerificationInterval:       models.SettingVariable{Value: "0 */5 * * * *"},
+		HealthFlapThreshold:            models.SettingVariable{Value: "5"},
+		CrashLoopThreshold:             models.SettingVariable{Value: "5"},
+		CrashLoopWindow:                models.SettingVariable{Value: "10"},
+		ContainerTrashRetention:        models.SettingVariable{Value: "0"},
+		ContainerDNSServers:            models.SettingVariable{Value: ""},
+		ContainerDNSSearch:             models.SettingVariable{Value: ""},
+		ContainerExtraHosts:            models.SettingVariable{Value: ""},
+		VolumeBackupDriver:             models.SettingVariable{Value: "tar"},
+		HelperImage:                    models.SettingVariable{Value: ""},
+		HelperCpuLimit:                 models.SettingVariable{Value: "0"},
+		HelperMemoryLimit:              models.SettingVariable{Value: "0"},
+		HelperIdleTtl:                  models.SettingVariable{Value: "10"},
+		StaleHelperMaxAge:              models.SettingVariable{Value: "6"},
+		DockerMaxConcurrentRequests:    models.SettingVariable{Value: "0"},
+		DockerMaxConcurrentDiskUsage:   models.SettingVariable{Value: "1"},
+		DockerMaxConcurrentExecs:       models.SettingVariable{Value: "0"},
+		DockerMaxConcurrentPulls:       models.SettingVariable{Value: "0"},
+		MaxVolumeDownloadSize:          models.SettingVariable{Value: "2048"},
+		MaxBackupUploadSize:            models.SettingVariable{Value: "10240"},
+		BaseServerURL:                  models.SettingVariable{Value: "http://localhost"},
+		EnableGravatar:                 models.SettingVariable{Value: "true"},
+		DefaultShell:                   models.SettingVariable{Value: "/bin/sh"},
+		DockerHost:                     models.SettingVariable{Value: "unix:///var/run/docker.sock"},
+		AuthLocalEnabled:               models.SettingVariable{Value: "true"},
+		AuthSessionTimeout:             models.SettingVariable{Value: "1440"},
+		AuthPasswordPolicy:             models.SettingVariable{Value: "strong"},
+		TrivyImage:                     models.SettingVariable{Value: "ghcr.io/aquasecurity/trivy:latest"},
+		LicenseDenylist:                models.SettingVariable{Value: ""},
+		VulnerabilityScanOnPull:        models.SettingVariable{Value: "false"},
+		VulnerabilityScanGateSeverity:  models.SettingVariable{Value: ""},
+		VulnerabilityScanGateThreshold: models.SettingVariable{Value: "0"},
+		VulnerabilityDigestEnabled:     models.SettingVariable{Value: "false"},
+		VulnerabilityDigestInterval:    models.SettingVariable{Value: "0 0 8 * * 1"},
+		VulnerabilityDigestCritical:    models.SettingVariable{Value: "1"},
+		VulnerabilityDigestHigh:        models.SettingVariable{Value: "0"},
+		VulnerabilityDigestMedium:      models.SettingVariable{Value: "0"},
+		VulnerabilityDigestLow:         models.SettingVariable{Value: "0"},
 		// AuthOidcConfig DEPRECATED will be removed in a future release
 		AuthOidcConfig:             models.SettingVariable{Value: "{}"},
 		OidcEnabled:                models.SettingVariable{Value: "false"},
@@ -134,14 +172,31 @@ func (s *SettingsService) getDefaultSettings() *models.Settings {
 		KeyboardShortcutsEnabled:   models.SettingVariable{Value: "true"},
 		AccentColor:                models.SettingVariable{Value: "oklch(0.606 0.25 292.717)"},
 		MaxImageUploadSize:         models.SettingVariable{Value: "500"},
+		UpdateCheckCacheTTL:        models.SettingVariable{Value: "15"},
+		TagDriftDetectionEnabled:   models.SettingVariable{Value: "false"},
+		RegistryRequestBudget:      models.SettingVariable{Value: "30"},
+		RegistryMirrors:            models.SettingVariable{Value: ""},
 		EnvironmentHealthInterval:  models.SettingVariable{Value: "0 */2 * * * *"},
 
+		// Feature flags, overridable per environment
+		FeatureVolumeBrowserEnabled: models.SettingVariable{Value: "true"},
+		FeatureContainerExecEnabled: models.SettingVariable{Value: "true"},
+		FeaturePruneEnabled:         models.SettingVariable{Value: "true"},
+		FeatureCheckpointEnabled:    models.SettingVariable{Value: "false"},
+		ApprovalWorkflowEnabled:     models.SettingVariable{Value: "false"},
+		ApprovalRequestTTL:          models.SettingVariable{Value: "60"},
+		ExecAuditTranscriptEnabled:  models.SettingVariable{Value: "false"},
+		ExecAuditTranscriptMaxKB:    models.SettingVariable{Value: "64"},
+
 		DockerAPITimeout:       models.SettingVariable{Value: "30"},
 		DockerImagePullTimeout: models.SettingVariable{Value: "600"},
 		GitOperationTimeout:    models.SettingVariable{Value: "300"},
 		HTTPClientTimeout:      models.SettingVariable{Value: "30"},
 		RegistryTimeout:        models.SettingVariable{Value: "30"},
 		ProxyRequestTimeout:    models.SettingVariable{Value: "60"},
+		HelperExecTimeout:      models.SettingVariable{Value: "60"},
+		HelperScanTimeout:      models.SettingVariable{Value: "600"},
+		HelperArchiveTimeout:   models.SettingVariable{Value: "7200"},
 
 		InstanceID: models.SettingVariable{Value: ""},
 	}
@@ -485,6 +540,9 @@ var timeoutSettingKeys = []string{
 	"httpClientTimeout",
 	"registryTimeout",
 	"proxyRequestTimeout",
+	"helperExecTimeout",
+	"helperScanTimeout",
+	"helperArchiveTimeout",
 }
 
 func (s *SettingsService) prepareUpdateValues(updates settings.Update, cfg, defaultCfg *models.Settings) ([]models.SettingVariable, bool, bool, bool, bool, map[string]string, error) {
@@ -513,13 +571,49 @@ func (s *SettingsService) prepareUpdateValues(updates settings.Update, cfg, defa
 		}
 
 		// Validate cron settings
-		cronFields := []string{"scheduledPruneInterval", "autoUpdateInterval", "pollingInterval", "environmentHealthInterval", "eventCleanupInterval", "analyticsHeartbeatInterval", "vulnerabilityScanInterval"}
+		cronFields := []string{"scheduledPruneInterval", "autoUpdateInterval", "pollingInterval", "environmentHealthInterval", "eventCleanupInterval", "analyticsHeartbeatInterval", "vulnerabilityScanInterval", "bootVerificationInterval", "vulnerabilityDigestInterval"}
 		if slices.Contains(cronFields, key) && value != "" {
 			if _, err := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow).Parse(value); err != nil {
 				return nil, false, false, false, false, nil, fmt.Errorf("invalid cron expression for %s: %w", key, err)
 			}
 		}
 
+		if key == "maintenanceWindows" && value != "" {
+			if _, err := maintenance.Parse(value); err != nil {
+				return nil, false, false, false, false, nil, fmt.Errorf("invalid maintenance windows: %w", err)
+			}
+		}
+
+		if key == "vulnerabilityScanGateSeverity" && value != "" {
+			if severityRankInternal(vulnerability.Severity(strings.ToUpper(value))) == 0 {
+				return nil, false, false, false, false, nil, fmt.Errorf("invalid scan gate severity %q: must be CRITICAL, HIGH, MEDIUM or LOW", value)
+			}
+		}
+
+		if key == "vulnerabilityScanGateThreshold" && value != "" {
+			if count, err := strconv.Atoi(value); err != nil || count < 0 {
+				return nil, false, false, false, false, nil, fmt.Errorf("invalid scan gate threshold %q: must be a whole number of vulnerabilities", value)
+			}
+		}
+
+		if key == "containerDnsServers" || key == "containerDnsSearch" || key == "containerExtraHosts" {
+			if err := validateContainerDNSSettingInternal(key, value); err != nil {
+				return nil, false, false, false, false, nil, err
+			}
+		}
+
+		if strings.HasPrefix(key, "vulnerabilityDigest") && strings.HasSuffix(key, "Threshold") && value != "" {
+			if count, err := strconv.Atoi(value); err != nil || count < 0 {
+				return nil, false, false, false, false, nil, fmt.Errorf("invalid digest threshold %q: must be a whole number of vulnerabilities", value)
+			}
+		}
+
+		if key == "autoUpdateSoakPeriod" && value != "" {
+			if minutes, err := strconv.Atoi(value); err != nil || minutes < 0 {
+				return nil, false, false, false, false, nil, fmt.Errorf("invalid soak period %q: must be a whole number of minutes", value)
+			}
+		}
+
 		var valueToSave string
 		var err error
 
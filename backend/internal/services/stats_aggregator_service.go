@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"golang.org/x/sync/errgroup"
+
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+)
+
+const (
+	statsSampleConcurrency = 8
+	statsSnapshotMaxAge    = 30 * time.Second
+	statsDemandWindow      = 2 * time.Minute
+)
+
+// StatsAggregatorService samples the resource usage of all running
+// containers into a single snapshot, so clients do not need to open a stats
+// stream per container. Sampling only happens while the snapshot is being
+// requested; an idle host is not polled.
+type StatsAggregatorService struct {
+	dockerService *DockerClientService
+
+	// sampleMu serializes sampling so concurrent requests share one sample.
+	sampleMu sync.Mutex
+
+	mu            sync.Mutex
+	snapshot      *containertypes.AggregateStats
+	lastRequested time.Time
+}
+
+func NewStatsAggregatorService(dockerService *DockerClientService) *StatsAggregatorService {
+	return &StatsAggregatorService{dockerService: dockerService}
+}
+
+// GetStats returns the latest snapshot. A new one is taken when there is no
+// snapshot yet or the last one is older than statsSnapshotMaxAge.
+func (s *StatsAggregatorService) GetStats(ctx context.Context) (*containertypes.AggregateStats, error) {
+	s.mu.Lock()
+	s.lastRequested = time.Now()
+	snapshot := s.freshSnapshotInternal()
+	s.mu.Unlock()
+	if snapshot != nil {
+		return snapshot, nil
+	}
+
+	s.sampleMu.Lock()
+	defer s.sampleMu.Unlock()
+
+	// Another request may have sampled while we waited.
+	s.mu.Lock()
+	snapshot = s.freshSnapshotInternal()
+	s.mu.Unlock()
+	if snapshot != nil {
+		return snapshot, nil
+	}
+	return s.sampleInternal(ctx)
+}
+
+// SampleIfRequested takes a new snapshot when stats were requested within
+// the last statsDemandWindow. It reports whether a sample was taken.
+func (s *StatsAggregatorService) SampleIfRequested(ctx context.Context) (bool, error) {
+	s.mu.Lock()
+	wanted := !s.lastRequested.IsZero() && time.Since(s.lastRequested) <= statsDemandWindow
+	s.mu.Unlock()
+	if !wanted {
+		return false, nil
+	}
+
+	s.sampleMu.Lock()
+	defer s.sampleMu.Unlock()
+	if _, err := s.sampleInternal(ctx); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (s *StatsAggregatorService) freshSnapshotInternal() *containertypes.AggregateStats {
+	if s.snapshot == nil || time.Since(s.snapshot.SampledAt) > statsSnapshotMaxAge {
+		return nil
+	}
+	return s.snapshot
+}
+
+// sampleInternal reads the stats of every running container and stores the
+// result as the latest snapshot. Callers must hold sampleMu.
+func (s *StatsAggregatorService) sampleInternal(ctx context.Context) (*containertypes.AggregateStats, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	samples := make([]*containertypes.StatsSample, len(containers))
+	var g errgroup.Group
+	g.SetLimit(statsSampleConcurrency)
+	for i, c := range containers {
+		g.Go(func() error {
+			// A non-streaming read makes the daemon fill in the previous CPU
+			// sample, so the CPU usage is known from a single call.
+			resp, err := dockerClient.ContainerStats(ctx, c.ID, false)
+			if err != nil {
+				slog.DebugContext(ctx, "Skipping container stats", "container", c.ID, "error", err)
+				return nil
+			}
+			defer resp.Body.Close()
+
+			var stats container.StatsResponse
+			if err := json.NewDecoder(resp.Body).Decode(&stats); err != nil {
+				slog.DebugContext(ctx, "Skipping unreadable container stats", "container", c.ID, "error", err)
+				return nil
+			}
+			name := c.ID
+			if len(c.Names) > 0 {
+				name = strings.TrimPrefix(c.Names[0], "/")
+			}
+			sample := statsSampleInternal(c.ID, name, &stats)
+			samples[i] = &sample
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	out := &containertypes.AggregateStats{
+		SampledAt:  time.Now().UTC(),
+		Containers: make([]containertypes.StatsSample, 0, len(samples)),
+	}
+	for _, sample := range samples {
+		if sample != nil {
+			out.Containers = append(out.Containers, *sample)
+		}
+	}
+	sort.Slice(out.Containers, func(i, j int) bool { return out.Containers[i].Name < out.Containers[j].Name })
+	out.Host = sumHostStatsInternal(out.Containers)
+
+	if info, err := dockerClient.Info(ctx); err == nil {
+		out.Host.CPUCount = info.NCPU
+		out.Host.MemoryTotal = uint64(max(info.MemTotal, 0)) //nolint:gosec // clamped to non-negative
+	} else {
+		slog.DebugContext(ctx, "Could not read Docker host info for stats", "error", err)
+	}
+
+	s.mu.Lock()
+	s.snapshot = out
+	s.mu.Unlock()
+	return out, nil
+}
+
+// statsSampleInternal converts a Docker stats response the way the docker
+// CLI does: memory excludes the inactive page cache and CPU usage is
+// relative to one core.
+func statsSampleInternal(id, name string, stats *container.StatsResponse) containertypes.StatsSample {
+	sample := containertypes.StatsSample{
+		ID:          id,
+		Name:        name,
+		MemoryLimit: stats.MemoryStats.Limit,
+		PIDs:        stats.PidsStats.Current,
+	}
+
+	cpuDelta := float64(stats.CPUStats.CPUUsage.TotalUsage) - float64(stats.PreCPUStats.CPUUsage.TotalUsage)
+	systemDelta := float64(stats.CPUStats.SystemUsage) - float64(stats.PreCPUStats.SystemUsage)
+	onlineCPUs := float64(stats.CPUStats.OnlineCPUs)
+	if onlineCPUs == 0 {
+		onlineCPUs = float64(len(stats.CPUStats.CPUUsage.PercpuUsage))
+	}
+	if cpuDelta > 0 && systemDelta > 0 {
+		sample.CPUPercent = cpuDelta / systemDelta * onlineCPUs * 100
+	}
+
+	sample.MemoryUsage = stats.MemoryStats.Usage
+	cache := stats.MemoryStats.Stats["inactive_file"]
+	if v, ok := stats.MemoryStats.Stats["total_inactive_file"]; ok {
+		// cgroup v1
+		cache = v
+	}
+	if cache < sample.MemoryUsage {
+		sample.MemoryUsage -= cache
+	}
+	if sample.MemoryLimit > 0 {
+		sample.MemoryPercent = float64(sample.MemoryUsage) / float64(sample.MemoryLimit) * 100
+	}
+
+	for _, network := range stats.Networks {
+		sample.NetworkRx += network.RxBytes
+		sample.NetworkTx += network.TxBytes
+	}
+	for _, entry := range stats.BlkioStats.IoServiceBytesRecursive {
+		switch strings.ToLower(entry.Op) {
+		case "read":
+			sample.BlockRead += entry.Value
+		case "write":
+			sample.BlockWrite += entry.Value
+		}
+	}
+	return sample
+}
+
+func sumHostStatsInternal(samples []containertypes.StatsSample) containertypes.HostStats {
+	host := containertypes.HostStats{Running: len(samples)}
+	for _, sample := range samples {
+		host.CPUPercent += sample.CPUPercent
+		host.MemoryUsage += sample.MemoryUsage
+		host.NetworkRx += sample.NetworkRx
+		host.NetworkTx += sample.NetworkTx
+		host.BlockRead += sample.BlockRead
+		host.BlockWrite += sample.BlockWrite
+	}
+	return host
+}
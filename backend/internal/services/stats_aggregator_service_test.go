@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/docker/docker/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStatsAggregatorService_GetStats(t *testing.T) {
+	ctx := context.Background()
+	var statsCalls atomic.Int32
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		switch path {
+		case "/containers/json":
+			_, _ = io.WriteString(w, `[{"Id":"b1","Names":["/web"]},{"Id":"a1","Names":["/db"]},{"Id":"c1","Names":["/gone"]}]`)
+		case "/info":
+			_, _ = io.WriteString(w, `{"NCPU":4,"MemTotal":8000}`)
+		case "/containers/b1/stats":
+			statsCalls.Add(1)
+			assert.Equal(t, "0", r.URL.Query().Get("stream"))
+			_, _ = io.WriteString(w, `{
+				"cpu_stats":{"cpu_usage":{"total_usage":300},"system_cpu_usage":2000,"online_cpus":2},
+				"precpu_stats":{"cpu_usage":{"total_usage":100},"system_cpu_usage":1000},
+				"memory_stats":{"usage":600,"limit":1000,"stats":{"inactive_file":100}},
+				"networks":{"eth0":{"rx_bytes":10,"tx_bytes":20},"eth1":{"rx_bytes":1,"tx_bytes":2}},
+				"blkio_stats":{"io_service_bytes_recursive":[{"op":"read","value":5},{"op":"write","value":7},{"op":"Read","value":1}]},
+				"pids_stats":{"current":3}
+			}`)
+		case "/containers/a1/stats":
+			statsCalls.Add(1)
+			_, _ = io.WriteString(w, `{
+				"cpu_stats":{"cpu_usage":{"total_usage":50,"percpu_usage":[25,25]},"system_cpu_usage":1000},
+				"precpu_stats":{"cpu_usage":{"total_usage":0},"system_cpu_usage":0},
+				"memory_stats":{"usage":400,"limit":0,"stats":{"total_inactive_file":50}},
+				"networks":{"eth0":{"rx_bytes":100,"tx_bytes":200}}
+			}`)
+		default:
+			// c1 stopped between listing and sampling.
+			statsCalls.Add(1)
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message":"No such container"}`)
+		}
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	svc := NewStatsAggregatorService(&DockerClientService{client: cli})
+
+	// Nothing is sampled until stats are requested.
+	sampled, err := svc.SampleIfRequested(ctx)
+	require.NoError(t, err)
+	assert.False(t, sampled)
+	assert.Zero(t, statsCalls.Load())
+
+	stats, err := svc.GetStats(ctx)
+	require.NoError(t, err)
+	require.Len(t, stats.Containers, 2)
+
+	db := stats.Containers[0]
+	assert.Equal(t, "db", db.Name)
+	assert.InDelta(t, 10.0, db.CPUPercent, 0.001)
+	assert.Equal(t, uint64(350), db.MemoryUsage)
+	assert.Zero(t, db.MemoryPercent)
+
+	web := stats.Containers[1]
+	assert.Equal(t, "b1", web.ID)
+	assert.InDelta(t, 40.0, web.CPUPercent, 0.001)
+	assert.Equal(t, uint64(500), web.MemoryUsage)
+	assert.InDelta(t, 50.0, web.MemoryPercent, 0.001)
+	assert.Equal(t, uint64(11), web.NetworkRx)
+	assert.Equal(t, uint64(22), web.NetworkTx)
+	assert.Equal(t, uint64(6), web.BlockRead)
+	assert.Equal(t, uint64(7), web.BlockWrite)
+	assert.Equal(t, uint64(3), web.PIDs)
+
+	assert.Equal(t, 2, stats.Host.Running)
+	assert.Equal(t, 4, stats.Host.CPUCount)
+	assert.Equal(t, uint64(8000), stats.Host.MemoryTotal)
+	assert.InDelta(t, 50.0, stats.Host.CPUPercent, 0.001)
+	assert.Equal(t, uint64(850), stats.Host.MemoryUsage)
+	assert.Equal(t, uint64(111), stats.Host.NetworkRx)
+	assert.Equal(t, uint64(222), stats.Host.NetworkTx)
+
+	// A fresh snapshot is served from cache.
+	calls := statsCalls.Load()
+	_, err = svc.GetStats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, calls, statsCalls.Load())
+
+	// Once requested, the job keeps sampling.
+	sampled, err = svc.SampleIfRequested(ctx)
+	require.NoError(t, err)
+	assert.True(t, sampled)
+	assert.Equal(t, 2*calls, statsCalls.Load())
+}
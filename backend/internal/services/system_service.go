@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log/slog"
 	"regexp"
@@ -11,14 +12,19 @@ import (
 	"github.com/docker/docker/api/types/build"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/image"
+	"github.com/getarcaneapp/arcane/backend/internal/config"
 	"github.com/getarcaneapp/arcane/backend/internal/database"
 	"github.com/getarcaneapp/arcane/backend/internal/models"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/arcaneupdater"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/converter"
 	containertypes "github.com/getarcaneapp/arcane/types/container"
+	"github.com/getarcaneapp/arcane/types/environment"
 	"github.com/getarcaneapp/arcane/types/system"
 	"github.com/goccy/go-yaml"
+	"github.com/shirou/gopsutil/v4/disk"
 	"golang.org/x/sync/errgroup"
+	"gorm.io/gorm"
 )
 
 type SystemService struct {
@@ -55,7 +61,9 @@ var systemUser = models.User{
 	Username: "System",
 }
 
-func (s *SystemService) PruneAll(ctx context.Context, req system.PruneAllRequest) (*system.PruneAllResult, error) {
+// PruneAll prunes the selected resource types and records a prune report
+// listing every removed resource. trigger is stored with the report.
+func (s *SystemService) PruneAll(ctx context.Context, req system.PruneAllRequest, trigger string, user models.User) (*system.PruneAllResult, error) {
 	slog.InfoContext(ctx, "Starting selective prune operation", "containers", req.Containers, "images", req.Images, "volumes", req.Volumes, "networks", req.Networks, "build_cache", req.BuildCache, "dangling", req.Dangling)
 
 	result := &system.PruneAllResult{Success: true}
@@ -90,6 +98,7 @@ func (s *SystemService) PruneAll(ctx context.Context, req system.PruneAllRequest
 			} else {
 				mu.Lock()
 				result.ImagesDeleted = append(result.ImagesDeleted, localResult.ImagesDeleted...)
+				result.Items = append(result.Items, localResult.Items...)
 				result.SpaceReclaimed += localResult.SpaceReclaimed
 				result.ImageSpaceReclaimed += localResult.ImageSpaceReclaimed
 				mu.Unlock()
@@ -109,6 +118,7 @@ func (s *SystemService) PruneAll(ctx context.Context, req system.PruneAllRequest
 				mu.Lock()
 				result.SpaceReclaimed += localResult.SpaceReclaimed
 				result.BuildCacheSpaceReclaimed += localResult.BuildCacheSpaceReclaimed
+				result.Items = append(result.Items, localResult.Items...)
 				mu.Unlock()
 			}
 			return nil
@@ -127,6 +137,7 @@ func (s *SystemService) PruneAll(ctx context.Context, req system.PruneAllRequest
 			} else {
 				mu.Lock()
 				result.VolumesDeleted = append(result.VolumesDeleted, localResult.VolumesDeleted...)
+				result.Items = append(result.Items, localResult.Items...)
 				result.SpaceReclaimed += localResult.SpaceReclaimed
 				result.VolumeSpaceReclaimed += localResult.VolumeSpaceReclaimed
 				mu.Unlock()
@@ -147,6 +158,7 @@ func (s *SystemService) PruneAll(ctx context.Context, req system.PruneAllRequest
 			} else {
 				mu.Lock()
 				result.NetworksDeleted = append(result.NetworksDeleted, localResult.NetworksDeleted...)
+				result.Items = append(result.Items, localResult.Items...)
 				mu.Unlock()
 			}
 			return nil
@@ -157,11 +169,94 @@ func (s *SystemService) PruneAll(ctx context.Context, req system.PruneAllRequest
 		slog.ErrorContext(ctx, "Prune operations failed", "error", err)
 	}
 
+	recordPruneReportInternal(ctx, s.db, result, trigger, user)
+
 	slog.InfoContext(ctx, "Selective prune operation completed", "success", result.Success, "containers_pruned", len(result.ContainersPruned), "images_deleted", len(result.ImagesDeleted), "volumes_deleted", len(result.VolumesDeleted), "networks_deleted", len(result.NetworksDeleted), "space_reclaimed", result.SpaceReclaimed, "error_count", len(result.Errors))
 
 	return result, nil
 }
 
+// recordPruneReportInternal stores result as a prune report and sets its ID
+// on result. Full prunes and the per-resource prune endpoints share it, so
+// every prune shows up in the report history.
+func recordPruneReportInternal(ctx context.Context, db *database.DB, result *system.PruneAllResult, trigger string, user models.User) {
+	if db == nil {
+		return
+	}
+
+	report := &models.PruneReport{
+		EnvironmentID:  "0",
+		Trigger:        trigger,
+		Username:       user.Username,
+		Success:        result.Success,
+		SpaceReclaimed: int64(result.SpaceReclaimed), //nolint:gosec // reclaimed bytes fit in int64
+		ItemCount:      len(result.Items),
+		Items:          result.Items,
+		Errors:         result.Errors,
+	}
+	if user.ID != "" {
+		report.UserID = &user.ID
+	}
+
+	if err := db.WithContext(ctx).Create(report).Error; err != nil {
+		slog.WarnContext(ctx, "Failed to record prune report", "error", err)
+		return
+	}
+	result.ReportID = report.ID
+}
+
+// ListPruneReports returns stored prune reports, newest first, without their
+// item lists.
+func (s *SystemService) ListPruneReports(ctx context.Context, limit int) ([]system.PruneReport, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+
+	var rows []models.PruneReport
+	if err := s.db.WithContext(ctx).Omit("items").Order("created_at DESC").Limit(limit).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to list prune reports: %w", err)
+	}
+
+	out := make([]system.PruneReport, 0, len(rows))
+	for i := range rows {
+		out = append(out, toPruneReportDto(&rows[i], false))
+	}
+	return out, nil
+}
+
+// GetPruneReport returns a prune report including every removed resource.
+func (s *SystemService) GetPruneReport(ctx context.Context, id string) (*system.PruneReport, error) {
+	var row models.PruneReport
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&row).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPruneReportNotFound
+		}
+		return nil, fmt.Errorf("failed to load prune report: %w", err)
+	}
+	dto := toPruneReportDto(&row, true)
+	return &dto, nil
+}
+
+// ErrPruneReportNotFound is returned when a prune report does not exist.
+var ErrPruneReportNotFound = errors.New("prune report not found")
+
+func toPruneReportDto(r *models.PruneReport, withItems bool) system.PruneReport {
+	dto := system.PruneReport{
+		ID:             r.ID,
+		Trigger:        r.Trigger,
+		Username:       r.Username,
+		Success:        r.Success,
+		SpaceReclaimed: r.SpaceReclaimed,
+		ItemCount:      r.ItemCount,
+		Errors:         r.Errors,
+		CreatedAt:      r.CreatedAt,
+	}
+	if withItems {
+		dto.Items = r.Items
+	}
+	return dto
+}
+
 func (s *SystemService) getDanglingModeFromSettings(ctx context.Context) (bool, error) {
 	pruneMode := s.settingsService.GetStringSetting(ctx, "dockerPruneMode", "dangling")
 
@@ -261,7 +356,7 @@ func (s *SystemService) StopAllContainers(ctx context.Context) (*containertypes.
 			return !arcaneupdater.IsArcaneContainer(c.Labels)
 		},
 		func(ctx context.Context, id string) error {
-			return s.containerService.StopContainer(ctx, id, systemUser)
+			return s.containerService.StopContainer(ctx, id, 0, systemUser)
 		}), nil
 }
 
@@ -271,6 +366,20 @@ func (s *SystemService) pruneContainers(ctx context.Context, result *system.Prun
 		return fmt.Errorf("failed to connect to Docker: %w", err)
 	}
 
+	// Sizes are only known before the containers are gone
+	candidates := map[string]container.Summary{}
+	if stopped, err := dockerClient.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Size:    true,
+		Filters: filters.NewArgs(filters.Arg("status", "exited"), filters.Arg("status", "created"), filters.Arg("status", "dead")),
+	}); err == nil {
+		for _, c := range stopped {
+			candidates[c.ID] = c
+		}
+	} else {
+		slog.WarnContext(ctx, "Failed to list containers before prune; sizes will be missing", "error", err)
+	}
+
 	filterArgs := filters.NewArgs()
 
 	report, err := dockerClient.ContainersPrune(ctx, filterArgs)
@@ -278,6 +387,15 @@ func (s *SystemService) pruneContainers(ctx context.Context, result *system.Prun
 		return fmt.Errorf("failed to prune containers: %w", err)
 	}
 
+	for _, id := range report.ContainersDeleted {
+		item := system.PrunedItem{Type: system.PrunedItemContainer, ID: id}
+		if c, ok := candidates[id]; ok {
+			item.Name = containerDisplayName(c.Names, id)
+			item.Size = c.SizeRw
+		}
+		result.Items = append(result.Items, item)
+	}
+
 	result.ContainersPruned = report.ContainersDeleted
 	result.SpaceReclaimed += report.SpaceReclaimed
 	result.ContainerSpaceReclaimed += report.SpaceReclaimed
@@ -292,21 +410,29 @@ func (s *SystemService) pruneImages(ctx context.Context, danglingOnly bool, resu
 		return fmt.Errorf("failed to connect to Docker: %w", err)
 	}
 
-	var filterArgs filters.Args
-
 	if danglingOnly {
 		slog.DebugContext(ctx, "Configured to prune only dangling images")
-		filterArgs = filters.NewArgs(filters.Arg("dangling", "true"))
 	} else {
 		slog.DebugContext(ctx, "Configured to prune all unused images (including non-dangling)")
-		filterArgs = filters.NewArgs(filters.Arg("dangling", "false"))
 	}
 
-	report, err := dockerClient.ImagesPrune(ctx, filterArgs)
+	candidates := map[string]image.Summary{}
+	if images, err := dockerClient.ImageList(ctx, image.ListOptions{}); err == nil {
+		for _, img := range images {
+			candidates[img.ID] = img
+		}
+	} else {
+		slog.WarnContext(ctx, "Failed to list images before prune; sizes will be missing", "error", err)
+	}
+
+	// Pinned images are skipped by the image service
+	report, err := s.imageService.pruneUnpinnedImagesInternal(ctx, dockerClient, danglingOnly)
 	if err != nil {
-		return fmt.Errorf("failed to prune images: %w", err)
+		return err
 	}
 
+	result.Items = append(result.Items, prunedImageItemsInternal(candidates, report)...)
+
 	slog.InfoContext(ctx, "Image pruning completed", "images_deleted", len(report.ImagesDeleted), "bytes_reclaimed", report.SpaceReclaimed)
 
 	// Collect IDs to delete from DB
@@ -354,6 +480,16 @@ func (s *SystemService) pruneBuildCache(ctx context.Context, result *system.Prun
 
 	slog.InfoContext(ctx, "build cache pruning completed", "cache_entries_deleted", len(report.CachesDeleted), "bytes_reclaimed", report.SpaceReclaimed)
 
+	if len(report.CachesDeleted) > 0 {
+		// Docker only reports the total, so the cache is attributed as a whole
+		result.Items = append(result.Items, system.PrunedItem{
+			Type: system.PrunedItemBuildCache,
+			ID:   "build-cache",
+			Name: fmt.Sprintf("%d build cache entries", len(report.CachesDeleted)),
+			Size: int64(report.SpaceReclaimed), //nolint:gosec // reclaimed bytes fit in int64
+		})
+	}
+
 	result.SpaceReclaimed += report.SpaceReclaimed
 	result.BuildCacheSpaceReclaimed += report.SpaceReclaimed
 	return nil
@@ -365,11 +501,19 @@ func (s *SystemService) pruneVolumes(ctx context.Context, result *system.PruneAl
 	// With all=true, it will remove both named and anonymous unused volumes
 	// With all=false, it only removes anonymous (unnamed) unused volumes
 	allVolumes := true
+
+	sizes, sizeErr := s.volumeService.GetVolumeSizes(ctx)
+	if sizeErr != nil {
+		slog.WarnContext(ctx, "Failed to read volume sizes before prune; sizes will be missing", "error", sizeErr)
+	}
+
 	report, err := s.volumeService.PruneVolumesWithOptions(ctx, allVolumes)
 	if err != nil {
 		return err
 	}
 
+	result.Items = append(result.Items, prunedVolumeItemsInternal(report.VolumesDeleted, sizes)...)
+
 	slog.InfoContext(ctx, "Volume prune completed", "volumes_deleted", len(report.VolumesDeleted), "space_reclaimed", report.SpaceReclaimed)
 
 	result.VolumesDeleted = report.VolumesDeleted
@@ -380,13 +524,15 @@ func (s *SystemService) pruneVolumes(ctx context.Context, result *system.PruneAl
 
 func (s *SystemService) pruneNetworks(ctx context.Context, result *system.PruneAllResult) error {
 	// Note: Docker API only prunes networks that are NOT in use by any containers
-	report, err := s.networkService.PruneNetworks(ctx)
+	report, err := s.networkService.pruneNetworksInternal(ctx)
 	if err != nil {
 		return err
 	}
 
 	slog.InfoContext(ctx, "Network prune completed", "networks_deleted", len(report.NetworksDeleted))
 
+	result.Items = append(result.Items, prunedNetworkItemsInternal(report.NetworksDeleted)...)
+
 	result.NetworksDeleted = report.NetworksDeleted
 	return nil
 }
@@ -529,6 +675,35 @@ func (s *SystemService) ConvertToDockerCompose(parsed *models.DockerRunCommand)
 	return string(yamlData), envVars, serviceName, nil
 }
 
+// CollectHeartbeatMetrics gathers the metrics an agent pushes to the manager
+// with its heartbeat. Metrics that cannot be read are left at their zero value
+// so a single failing source does not stop the heartbeat.
+func (s *SystemService) CollectHeartbeatMetrics(ctx context.Context) environment.HeartbeatMetrics {
+	metrics := environment.HeartbeatMetrics{AgentVersion: config.Version}
+
+	if _, running, _, total, err := s.dockerService.GetAllContainers(ctx); err != nil {
+		slog.WarnContext(ctx, "Failed to count containers for heartbeat", "error", err)
+	} else {
+		metrics.ContainersRunning = running
+		metrics.ContainersTotal = total
+	}
+
+	var pending int64
+	if err := s.db.WithContext(ctx).Model(&models.ImageUpdateRecord{}).Where("has_update = ?", true).Count(&pending).Error; err != nil {
+		slog.WarnContext(ctx, "Failed to count pending image updates for heartbeat", "error", err)
+	} else {
+		metrics.PendingUpdates = int(pending)
+	}
+
+	if usage, err := disk.UsageWithContext(ctx, s.GetDiskUsagePath(ctx)); err == nil {
+		metrics.DiskFreeBytes = &usage.Free
+	} else if usage, err := disk.UsageWithContext(ctx, "/"); err == nil {
+		metrics.DiskFreeBytes = &usage.Free
+	}
+
+	return metrics
+}
+
 func (s *SystemService) GetDiskUsagePath(ctx context.Context) string {
 	cfg := s.settingsService.GetSettingsConfig()
 	if cfg == nil {
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/types/system"
+)
+
+func TestSystemService_PruneReports(t *testing.T) {
+	ctx := context.Background()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.PruneReport{}))
+	svc := &SystemService{db: &database.DB{DB: gdb}}
+
+	result := &system.PruneAllResult{
+		Success:        true,
+		SpaceReclaimed: 3072,
+		Items: []system.PrunedItem{
+			{Type: system.PrunedItemContainer, ID: "c1", Name: "old-web", Size: 1024},
+			{Type: system.PrunedItemImage, ID: "sha256:abc", Name: "nginx:1.25", Size: 2048},
+		},
+	}
+	recordPruneReportInternal(ctx, svc.db, result, models.PruneTriggerManual, models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "alice"})
+	require.NotEmpty(t, result.ReportID)
+
+	reports, err := svc.ListPruneReports(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, reports, 1)
+	assert.Equal(t, 2, reports[0].ItemCount)
+	assert.Empty(t, reports[0].Items, "list omits items")
+	assert.Equal(t, "alice", reports[0].Username)
+
+	report, err := svc.GetPruneReport(ctx, result.ReportID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3072), report.SpaceReclaimed)
+	require.Len(t, report.Items, 2)
+	assert.Equal(t, "nginx:1.25", report.Items[1].Name)
+
+	_, err = svc.GetPruneReport(ctx, "missing")
+	require.ErrorIs(t, err, ErrPruneReportNotFound)
+}
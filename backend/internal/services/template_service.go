@@ -24,6 +24,7 @@ import (
 	"github.com/getarcaneapp/arcane/backend/internal/utils/mapper"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/pagination"
 	templateutil "github.com/getarcaneapp/arcane/backend/internal/utils/template"
+	"github.com/getarcaneapp/arcane/backend/pkg/projects"
 	"github.com/getarcaneapp/arcane/types/env"
 	tmpl "github.com/getarcaneapp/arcane/types/template"
 	"github.com/google/uuid"
@@ -1075,12 +1076,19 @@ func (s *TemplateService) GetTemplateContentWithParsedData(ctx context.Context,
 		envVars[i] = env.Variable{Key: v.Key, Value: v.Value}
 	}
 
+	// A template with broken inputs can still be deployed by hand.
+	inputs, err := projects.ParseComposeInputs(composeContent)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to parse template inputs", "template", id, "error", err)
+	}
+
 	return &tmpl.TemplateContent{
 		Template:     outTemplate,
 		Content:      composeContent,
 		EnvContent:   envContent,
 		Services:     services,
 		EnvVariables: envVars,
+		Inputs:       inputs,
 	}, nil
 }
 
@@ -0,0 +1,483 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/types/base"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+	"github.com/getarcaneapp/arcane/types/updater"
+)
+
+var (
+	// ErrRolloutNotFound is returned when a rollout does not exist.
+	ErrRolloutNotFound = errors.New("rollout not found")
+	// ErrRolloutInProgress is returned when a rollout is started while
+	// another one has not finished.
+	ErrRolloutInProgress = errors.New("another rollout is in progress")
+	// ErrRolloutFinished is returned when a completed rollout is promoted or
+	// halted.
+	ErrRolloutFinished = errors.New("rollout has already finished")
+)
+
+// UpdateRolloutService runs staged auto-updates across environments. The
+// canary environment is updated first; once the containers it updated have
+// stayed healthy for the soak period, the same image updates are applied to
+// every other enabled environment.
+type UpdateRolloutService struct {
+	db                 *database.DB
+	settingsService    *SettingsService
+	environmentService *EnvironmentService
+	updaterService     *UpdaterService
+	containerService   *ContainerService
+	eventService       *EventService
+
+	// mu serializes rollout steps so a promotion never overlaps a start.
+	mu sync.Mutex
+}
+
+func NewUpdateRolloutService(db *database.DB, settingsService *SettingsService, environmentService *EnvironmentService, updaterService *UpdaterService, containerService *ContainerService, eventService *EventService) *UpdateRolloutService {
+	return &UpdateRolloutService{
+		db:                 db,
+		settingsService:    settingsService,
+		environmentService: environmentService,
+		updaterService:     updaterService,
+		containerService:   containerService,
+		eventService:       eventService,
+	}
+}
+
+// CanaryEnvironment returns the environment staged rollouts start on, or ""
+// when auto-updates are not staged.
+func (s *UpdateRolloutService) CanaryEnvironment(ctx context.Context) string {
+	if s == nil {
+		return ""
+	}
+	return strings.TrimSpace(s.settingsService.GetStringSetting(ctx, "autoUpdateCanaryEnvironment", ""))
+}
+
+// Start updates the canary environment and starts a rollout of the images it
+// updated. It returns nil when the canary had nothing to update.
+func (s *UpdateRolloutService) Start(ctx context.Context) (*updater.Rollout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	canaryID := s.CanaryEnvironment(ctx)
+	if canaryID == "" {
+		return nil, fmt.Errorf("no canary environment configured")
+	}
+	if active, err := s.activeInternal(ctx); err != nil {
+		return nil, err
+	} else if active != nil {
+		return nil, fmt.Errorf("%w: %s since %s", ErrRolloutInProgress, active.Status, active.CreatedAt.UTC().Format(time.RFC3339))
+	}
+
+	result, err := s.runUpdateInternal(ctx, canaryID, nil)
+	if err != nil {
+		return nil, fmt.Errorf("update canary environment: %w", err)
+	}
+	images, containers := rolloutChangesInternal(result)
+	if len(images) == 0 {
+		slog.InfoContext(ctx, "canary environment had nothing to update; no rollout started", "environmentID", canaryID)
+		return nil, nil
+	}
+
+	now := time.Now()
+	soakUntil := now.Add(time.Duration(s.settingsService.GetIntSetting(ctx, "autoUpdateSoakPeriod", 30)) * time.Minute)
+	row := &models.UpdateRollout{
+		Status:              updater.RolloutStatusSoaking,
+		CanaryEnvironmentID: canaryID,
+		Images:              images,
+		CanaryContainers:    containers,
+		SoakUntil:           &soakUntil,
+	}
+	if result.Failed > 0 {
+		row.Status = updater.RolloutStatusHalted
+		row.Error = fmt.Sprintf("%d resources failed to update on the canary environment", result.Failed)
+		row.CompletedAt = &now
+	}
+	if err := s.db.WithContext(ctx).Create(row).Error; err != nil {
+		return nil, fmt.Errorf("record rollout: %w", err)
+	}
+
+	if row.Status == updater.RolloutStatusHalted {
+		s.logEventInternal(ctx, row, models.EventSeverityError, "halted, canary update failed")
+	} else {
+		s.logEventInternal(ctx, row, models.EventSeverityInfo, "canary updated, soaking")
+	}
+	return toRolloutInternal(row), nil
+}
+
+// CheckCanary checks the health of the canary containers of the soaking
+// rollout, halting it when one is unhealthy. It reports whether the rollout
+// has soaked long enough to be promoted.
+func (s *UpdateRolloutService) CheckCanary(ctx context.Context, now time.Time) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, err := s.activeInternal(ctx)
+	if err != nil || row == nil || row.Status != updater.RolloutStatusSoaking {
+		return false, err
+	}
+
+	healthy, reason, err := s.canaryHealthInternal(ctx, row)
+	if err != nil {
+		// An unreachable canary is not proof of a bad update; check again
+		// on the next run.
+		slog.WarnContext(ctx, "could not check canary health", "rolloutID", row.ID, "environmentID", row.CanaryEnvironmentID, "error", err)
+		return false, nil
+	}
+	if reason != "" {
+		row.Status = updater.RolloutStatusHalted
+		row.Error = reason
+		row.CompletedAt = &now
+		if err := s.db.WithContext(ctx).Save(row).Error; err != nil {
+			return false, fmt.Errorf("halt rollout: %w", err)
+		}
+		s.logEventInternal(ctx, row, models.EventSeverityError, "halted, "+reason)
+		return false, nil
+	}
+
+	return healthy && row.SoakUntil != nil && !now.Before(*row.SoakUntil), nil
+}
+
+// Promote rolls the image updates of the soaking rollout out to the other
+// environments.
+func (s *UpdateRolloutService) Promote(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, err := s.activeInternal(ctx)
+	if err != nil || row == nil || row.Status != updater.RolloutStatusSoaking {
+		return err
+	}
+	return s.promoteInternal(ctx, row)
+}
+
+// PromoteRollout rolls a soaking or halted rollout out to the other
+// environments right away, without waiting for the soak period.
+func (s *UpdateRolloutService) PromoteRollout(ctx context.Context, id string, user models.User) (*updater.Rollout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, err := s.loadInternal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if row.Status != updater.RolloutStatusSoaking && row.Status != updater.RolloutStatusHalted {
+		return nil, ErrRolloutFinished
+	}
+	if row.Status == updater.RolloutStatusHalted {
+		if active, err := s.activeInternal(ctx); err != nil {
+			return nil, err
+		} else if active != nil {
+			return nil, ErrRolloutInProgress
+		}
+	}
+
+	slog.InfoContext(ctx, "rollout promoted manually", "rolloutID", row.ID, "user", user.Username)
+	row.Error = ""
+	row.CompletedAt = nil
+	if err := s.promoteInternal(ctx, row); err != nil {
+		return nil, err
+	}
+	return toRolloutInternal(row), nil
+}
+
+// HaltRollout stops a soaking rollout so its updates never reach the other
+// environments. A rollout left rolling out by a restart can be halted too;
+// one that is actually rolling out holds the lock until it completes.
+func (s *UpdateRolloutService) HaltRollout(ctx context.Context, id string, user models.User) (*updater.Rollout, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	row, err := s.loadInternal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if row.Status != updater.RolloutStatusSoaking && row.Status != updater.RolloutStatusRollingOut {
+		return nil, ErrRolloutFinished
+	}
+
+	now := time.Now()
+	row.Status = updater.RolloutStatusHalted
+	row.Error = "halted by " + user.Username
+	row.CompletedAt = &now
+	if err := s.db.WithContext(ctx).Save(row).Error; err != nil {
+		return nil, fmt.Errorf("halt rollout: %w", err)
+	}
+	s.logEventInternal(ctx, row, models.EventSeverityWarning, row.Error)
+	return toRolloutInternal(row), nil
+}
+
+// ListRollouts returns the most recent rollouts, newest first.
+func (s *UpdateRolloutService) ListRollouts(ctx context.Context, limit int) ([]updater.Rollout, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	var rows []models.UpdateRollout
+	if err := s.db.WithContext(ctx).Order("created_at DESC").Limit(limit).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("list rollouts: %w", err)
+	}
+	out := make([]updater.Rollout, 0, len(rows))
+	for i := range rows {
+		out = append(out, *toRolloutInternal(&rows[i]))
+	}
+	return out, nil
+}
+
+func (s *UpdateRolloutService) promoteInternal(ctx context.Context, row *models.UpdateRollout) error {
+	row.Status = updater.RolloutStatusRollingOut
+	if err := s.db.WithContext(ctx).Save(row).Error; err != nil {
+		return fmt.Errorf("update rollout: %w", err)
+	}
+	s.logEventInternal(ctx, row, models.EventSeverityInfo, "rolling out")
+
+	var envs []models.Environment
+	if err := s.db.WithContext(ctx).
+		Where("enabled = ?", true).
+		Where("id != ?", row.CanaryEnvironmentID).
+		Order("name ASC").
+		Find(&envs).Error; err != nil {
+		return fmt.Errorf("list environments: %w", err)
+	}
+
+	failed := 0
+	row.Targets = make([]updater.RolloutTarget, 0, len(envs))
+	for _, env := range envs {
+		target := updater.RolloutTarget{EnvironmentID: env.ID, EnvironmentName: env.Name, Status: updater.RolloutTargetUpdated}
+		result, err := s.runUpdateInternal(ctx, env.ID, row.Images)
+		switch {
+		case err != nil:
+			target.Status = updater.RolloutTargetFailed
+			target.Error = err.Error()
+		case result.Failed > 0:
+			target.Status = updater.RolloutTargetFailed
+			target.Updated, target.Failed = result.Updated, result.Failed
+			target.Error = fmt.Sprintf("%d resources failed to update", result.Failed)
+		default:
+			target.Updated = result.Updated
+		}
+		if target.Status == updater.RolloutTargetFailed {
+			failed++
+			slog.WarnContext(ctx, "rollout failed on environment", "rolloutID", row.ID, "environmentID", env.ID, "error", target.Error)
+		}
+		row.Targets = append(row.Targets, target)
+	}
+
+	now := time.Now()
+	row.Status = updater.RolloutStatusCompleted
+	row.CompletedAt = &now
+	if failed > 0 {
+		row.Error = fmt.Sprintf("update failed on %d of %d environments", failed, len(envs))
+	}
+	if err := s.db.WithContext(ctx).Save(row).Error; err != nil {
+		return fmt.Errorf("update rollout: %w", err)
+	}
+
+	if failed > 0 {
+		s.logEventInternal(ctx, row, models.EventSeverityWarning, "completed, "+row.Error)
+	} else {
+		s.logEventInternal(ctx, row, models.EventSeveritySuccess, fmt.Sprintf("completed on %d environments", len(envs)))
+	}
+	return nil
+}
+
+// canaryHealthInternal checks the canary containers. It returns a reason when
+// one is down or unhealthy, and healthy false while a healthcheck is still
+// starting.
+func (s *UpdateRolloutService) canaryHealthInternal(ctx context.Context, row *models.UpdateRollout) (bool, string, error) {
+	healthy := true
+	for _, name := range row.CanaryContainers {
+		state, found, err := s.containerStateInternal(ctx, row.CanaryEnvironmentID, name)
+		if err != nil {
+			return false, "", err
+		}
+		if reason := canaryProblemInternal(name, state, found); reason != "" {
+			return false, reason, nil
+		}
+		if state.Health != nil && state.Health.Status == "starting" {
+			healthy = false
+		}
+	}
+	return healthy, "", nil
+}
+
+func canaryProblemInternal(name string, state containertypes.State, found bool) string {
+	switch {
+	case !found:
+		return fmt.Sprintf("canary container %s no longer exists", name)
+	case !state.Running || state.Status == "restarting":
+		return fmt.Sprintf("canary container %s is %s", name, state.Status)
+	case state.Health != nil && state.Health.Status == "unhealthy":
+		return fmt.Sprintf("canary container %s is unhealthy", name)
+	}
+	return ""
+}
+
+func (s *UpdateRolloutService) containerStateInternal(ctx context.Context, envID, name string) (containertypes.State, bool, error) {
+	if envID == "0" {
+		inspect, err := s.containerService.GetContainerByID(ctx, name)
+		if err != nil {
+			if cerrdefs.IsNotFound(err) {
+				return containertypes.State{}, false, nil
+			}
+			return containertypes.State{}, false, err
+		}
+		return containertypes.NewDetails(inspect).State, true, nil
+	}
+
+	body, status, err := s.environmentService.ProxyRequest(ctx, envID, http.MethodGet, "/api/environments/0/containers/"+url.PathEscape(name), nil)
+	if err != nil {
+		return containertypes.State{}, false, err
+	}
+	if status == http.StatusNotFound {
+		return containertypes.State{}, false, nil
+	}
+	if status != http.StatusOK {
+		return containertypes.State{}, false, fmt.Errorf("environment returned status %d", status)
+	}
+	var resp base.ApiResponse[containertypes.Details]
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return containertypes.State{}, false, fmt.Errorf("decode container: %w", err)
+	}
+	return resp.Data.State, true, nil
+}
+
+// runUpdateInternal applies pending updates on an environment, limited to
+// images when any are given.
+func (s *UpdateRolloutService) runUpdateInternal(ctx context.Context, envID string, images []string) (*updater.Result, error) {
+	if envID == "0" {
+		return s.updaterService.ApplyPendingImages(ctx, false, images)
+	}
+
+	payload, err := json.Marshal(updater.Options{Images: images})
+	if err != nil {
+		return nil, err
+	}
+	body, status, err := s.environmentService.ProxyRequest(ctx, envID, http.MethodPost, "/api/environments/0/updater/run", payload)
+	if err != nil {
+		return nil, err
+	}
+	if status != http.StatusOK {
+		return nil, fmt.Errorf("environment returned status %d: %s", status, strings.TrimSpace(string(body)))
+	}
+	var resp base.ApiResponse[*updater.Result]
+	if err := json.Unmarshal(body, &resp); err != nil {
+		return nil, fmt.Errorf("decode updater result: %w", err)
+	}
+	if resp.Data == nil {
+		return &updater.Result{}, nil
+	}
+	return resp.Data, nil
+}
+
+// rolloutChangesInternal returns the image references an update run changed,
+// as they were before the update, and the containers it recreated.
+func rolloutChangesInternal(result *updater.Result) ([]string, []string) {
+	var images, containers []string
+	seenImages := map[string]bool{}
+	addImage := func(ref string) {
+		if ref != "" && !seenImages[ref] {
+			seenImages[ref] = true
+			images = append(images, ref)
+		}
+	}
+	for _, item := range result.Items {
+		if !item.UpdateApplied {
+			continue
+		}
+		switch item.ResourceType {
+		case "image":
+			addImage(item.ResourceID)
+		case "container":
+			addImage(item.OldImages["main"])
+			if item.ResourceName != "" {
+				containers = append(containers, strings.TrimPrefix(item.ResourceName, "/"))
+			}
+		}
+	}
+	return images, containers
+}
+
+func (s *UpdateRolloutService) activeInternal(ctx context.Context) (*models.UpdateRollout, error) {
+	var row models.UpdateRollout
+	err := s.db.WithContext(ctx).
+		Where("status IN ?", []string{updater.RolloutStatusSoaking, updater.RolloutStatusRollingOut}).
+		Order("created_at DESC").
+		First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load active rollout: %w", err)
+	}
+	return &row, nil
+}
+
+func (s *UpdateRolloutService) loadInternal(ctx context.Context, id string) (*models.UpdateRollout, error) {
+	var row models.UpdateRollout
+	err := s.db.WithContext(ctx).Where("id = ?", id).First(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrRolloutNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("load rollout: %w", err)
+	}
+	return &row, nil
+}
+
+func (s *UpdateRolloutService) logEventInternal(ctx context.Context, row *models.UpdateRollout, severity models.EventSeverity, state string) {
+	if s.eventService == nil {
+		return
+	}
+	resourceType := "environment"
+	_, err := s.eventService.CreateEvent(ctx, CreateEventRequest{
+		Type:          models.EventTypeSystemRollout,
+		Severity:      severity,
+		Title:         "Auto-update rollout " + state,
+		Description:   fmt.Sprintf("Rollout of %d images from canary environment %s", len(row.Images), row.CanaryEnvironmentID),
+		ResourceType:  &resourceType,
+		ResourceID:    &row.CanaryEnvironmentID,
+		ResourceName:  &row.CanaryEnvironmentID,
+		EnvironmentID: &row.CanaryEnvironmentID,
+		Metadata: models.JSON{
+			"rolloutId": row.ID,
+			"status":    row.Status,
+			"images":    []string(row.Images),
+			"error":     row.Error,
+		},
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "could not log rollout event", "rolloutID", row.ID, "error", err)
+	}
+}
+
+func toRolloutInternal(row *models.UpdateRollout) *updater.Rollout {
+	out := &updater.Rollout{
+		ID:                  row.ID,
+		Status:              row.Status,
+		CanaryEnvironmentID: row.CanaryEnvironmentID,
+		Images:              append([]string{}, row.Images...),
+		CanaryContainers:    append([]string{}, row.CanaryContainers...),
+		SoakUntil:           row.SoakUntil,
+		Targets:             row.Targets,
+		Error:               row.Error,
+		CompletedAt:         row.CompletedAt,
+	}
+	out.StartedAt = row.CreatedAt
+	return out
+}
@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/types/base"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+	"github.com/getarcaneapp/arcane/types/updater"
+)
+
+// fakeRolloutAgent serves the updater and container endpoints of an agent.
+type fakeRolloutAgent struct {
+	result  updater.Result
+	health  atomic.Value // string
+	running atomic.Bool
+	runs    []updater.Options
+}
+
+func newFakeRolloutAgentInternal(t *testing.T, agent *fakeRolloutAgent) *httptest.Server {
+	t.Helper()
+	agent.health.Store("healthy")
+	agent.running.Store(true)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/api/environments/0/updater/run":
+			var opts updater.Options
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&opts))
+			agent.runs = append(agent.runs, opts)
+			_ = json.NewEncoder(w).Encode(base.ApiResponse[*updater.Result]{Success: true, Data: &agent.result})
+		case r.Method == http.MethodGet && r.URL.Path == "/api/environments/0/containers/web":
+			status := "exited"
+			if agent.running.Load() {
+				status = "running"
+			}
+			_ = json.NewEncoder(w).Encode(base.ApiResponse[containertypes.Details]{Success: true, Data: containertypes.Details{
+				Name:  "web",
+				State: containertypes.State{Status: status, Running: agent.running.Load(), Health: &containertypes.HealthState{Status: agent.health.Load().(string)}},
+			}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func setupRolloutTestServiceInternal(t *testing.T, canaryURL, targetURL string) (*UpdateRolloutService, *database.DB) {
+	t.Helper()
+	ctx := context.Background()
+
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.SettingVariable{}, &models.Environment{}, &models.Event{}, &models.UpdateRollout{}))
+	db := &database.DB{DB: gdb}
+
+	settingsService, err := NewSettingsService(ctx, db)
+	require.NoError(t, err)
+	require.NoError(t, settingsService.EnsureDefaultSettings(ctx))
+	require.NoError(t, settingsService.UpdateSetting(ctx, "autoUpdateCanaryEnvironment", "canary"))
+	require.NoError(t, settingsService.UpdateSetting(ctx, "autoUpdateSoakPeriod", "30"))
+	require.NoError(t, settingsService.LoadDatabaseSettings(ctx))
+
+	envs := []models.Environment{
+		{BaseModel: models.BaseModel{ID: "0"}, Name: "local", Enabled: false},
+		{BaseModel: models.BaseModel{ID: "canary"}, Name: "canary", ApiUrl: canaryURL, Enabled: true},
+		{BaseModel: models.BaseModel{ID: "prod"}, Name: "prod", ApiUrl: targetURL, Enabled: true},
+	}
+	for i := range envs {
+		require.NoError(t, gdb.Create(&envs[i]).Error)
+	}
+
+	environmentService := NewEnvironmentService(db, nil, nil, nil, settingsService)
+	return NewUpdateRolloutService(db, settingsService, environmentService, nil, nil, NewEventService(db)), db
+}
+
+func canaryUpdateResultInternal() updater.Result {
+	return updater.Result{Checked: 2, Updated: 2, Items: []updater.ResourceResult{
+		{ResourceID: "nginx:1.27", ResourceType: "image", Status: "updated", UpdateApplied: true},
+		{ResourceID: "c1", ResourceName: "web", ResourceType: "container", Status: "updated", UpdateApplied: true, OldImages: map[string]string{"main": "nginx:1.27"}},
+		{ResourceID: "redis:7", ResourceType: "image", Status: "skipped", Error: "image is pinned"},
+	}}
+}
+
+func TestUpdateRolloutService_SoakAndPromote(t *testing.T) {
+	ctx := context.Background()
+	canary := &fakeRolloutAgent{result: canaryUpdateResultInternal()}
+	target := &fakeRolloutAgent{result: updater.Result{Checked: 1, Updated: 1}}
+	svc, _ := setupRolloutTestServiceInternal(t, newFakeRolloutAgentInternal(t, canary).URL, newFakeRolloutAgentInternal(t, target).URL)
+
+	rollout, err := svc.Start(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, rollout)
+	assert.Equal(t, updater.RolloutStatusSoaking, rollout.Status)
+	assert.Equal(t, []string{"nginx:1.27"}, rollout.Images)
+	assert.Equal(t, []string{"web"}, rollout.CanaryContainers)
+	require.Len(t, canary.runs, 1)
+	assert.Empty(t, canary.runs[0].Images, "the canary applies every pending update")
+
+	_, err = svc.Start(ctx)
+	require.ErrorIs(t, err, ErrRolloutInProgress)
+
+	due, err := svc.CheckCanary(ctx, time.Now())
+	require.NoError(t, err)
+	assert.False(t, due, "soak period has not passed")
+
+	canary.health.Store("starting")
+	due, err = svc.CheckCanary(ctx, time.Now().Add(31*time.Minute))
+	require.NoError(t, err)
+	assert.False(t, due, "a starting healthcheck is not healthy yet")
+
+	canary.health.Store("healthy")
+	due, err = svc.CheckCanary(ctx, time.Now().Add(31*time.Minute))
+	require.NoError(t, err)
+	assert.True(t, due)
+
+	require.Empty(t, target.runs)
+	require.NoError(t, svc.Promote(ctx))
+	require.Len(t, target.runs, 1)
+	assert.Equal(t, []string{"nginx:1.27"}, target.runs[0].Images, "other environments only get the canary's updates")
+
+	rollouts, err := svc.ListRollouts(ctx, 0)
+	require.NoError(t, err)
+	require.Len(t, rollouts, 1)
+	assert.Equal(t, updater.RolloutStatusCompleted, rollouts[0].Status)
+	require.Len(t, rollouts[0].Targets, 1)
+	assert.Equal(t, updater.RolloutTarget{EnvironmentID: "prod", EnvironmentName: "prod", Status: updater.RolloutTargetUpdated, Updated: 1}, rollouts[0].Targets[0])
+	assert.NotNil(t, rollouts[0].CompletedAt)
+}
+
+func TestUpdateRolloutService_HaltsOnUnhealthyCanary(t *testing.T) {
+	ctx := context.Background()
+	canary := &fakeRolloutAgent{result: canaryUpdateResultInternal()}
+	target := &fakeRolloutAgent{}
+	svc, _ := setupRolloutTestServiceInternal(t, newFakeRolloutAgentInternal(t, canary).URL, newFakeRolloutAgentInternal(t, target).URL)
+
+	rollout, err := svc.Start(ctx)
+	require.NoError(t, err)
+
+	canary.health.Store("unhealthy")
+	due, err := svc.CheckCanary(ctx, time.Now())
+	require.NoError(t, err)
+	assert.False(t, due)
+
+	rollouts, err := svc.ListRollouts(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, updater.RolloutStatusHalted, rollouts[0].Status)
+	assert.Contains(t, rollouts[0].Error, "web is unhealthy")
+
+	require.NoError(t, svc.Promote(ctx))
+	assert.Empty(t, target.runs, "a halted rollout is not promoted on schedule")
+
+	// A halted rollout no longer blocks the next one, and an admin can still
+	// push it out by hand.
+	_, err = svc.HaltRollout(ctx, rollout.ID, models.User{Username: "admin"})
+	require.ErrorIs(t, err, ErrRolloutFinished)
+	promoted, err := svc.PromoteRollout(ctx, rollout.ID, models.User{Username: "admin"})
+	require.NoError(t, err)
+	assert.Equal(t, updater.RolloutStatusCompleted, promoted.Status)
+	assert.Empty(t, promoted.Error)
+	assert.Len(t, target.runs, 1)
+}
+
+func TestUpdateRolloutService_NothingToRollOut(t *testing.T) {
+	ctx := context.Background()
+	canary := &fakeRolloutAgent{result: updater.Result{Checked: 1, Skipped: 1, Items: []updater.ResourceResult{
+		{ResourceID: "redis:7", ResourceType: "image", Status: "skipped", Error: "image is pinned"},
+	}}}
+	svc, db := setupRolloutTestServiceInternal(t, newFakeRolloutAgentInternal(t, canary).URL, "http://unused")
+
+	rollout, err := svc.Start(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, rollout)
+
+	var count int64
+	require.NoError(t, db.Model(&models.UpdateRollout{}).Count(&count).Error)
+	assert.Zero(t, count)
+}
+
+func TestCanaryProblem(t *testing.T) {
+	assert.Empty(t, canaryProblemInternal("web", containertypes.State{Status: "running", Running: true}, true))
+	assert.Empty(t, canaryProblemInternal("web", containertypes.State{Status: "running", Running: true, Health: &containertypes.HealthState{Status: "starting"}}, true))
+	assert.Contains(t, canaryProblemInternal("web", containertypes.State{}, false), "no longer exists")
+	assert.Contains(t, canaryProblemInternal("web", containertypes.State{Status: "exited"}, true), "is exited")
+	assert.Contains(t, canaryProblemInternal("web", containertypes.State{Status: "restarting", Running: true}, true), "is restarting")
+}
@@ -0,0 +1,269 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	cerrdefs "github.com/containerd/errdefs"
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/network"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	containertypes "github.com/getarcaneapp/arcane/types/container"
+)
+
+var (
+	ErrNoRollback           = errors.New("container has no update to roll back")
+	ErrRollbackExpired      = errors.New("rollback window has passed")
+	ErrRollbackImageMissing = errors.New("previous image is no longer available")
+	ErrRollbackNotNeeded    = errors.New("container is healthy; force the rollback to roll back anyway")
+	ErrAlreadyRolledBack    = errors.New("container has already been rolled back")
+)
+
+const (
+	rollbackHealthHealthy   = "healthy"
+	rollbackHealthStarting  = "starting"
+	rollbackHealthUnhealthy = "unhealthy"
+)
+
+// rollbackSnapshot is the container configuration auto-update replaced.
+type rollbackSnapshot struct {
+	Name       string                               `json:"name"`
+	Config     *container.Config                    `json:"config"`
+	HostConfig *container.HostConfig                `json:"hostConfig"`
+	Networks   map[string]*network.EndpointSettings `json:"networks,omitempty"`
+}
+
+func newRollbackSnapshotInternal(inspect container.InspectResponse) (string, error) {
+	if inspect.Config == nil || inspect.HostConfig == nil {
+		return "", errors.New("container has no configuration")
+	}
+	snap := rollbackSnapshot{Name: inspect.Name, Config: inspect.Config, HostConfig: inspect.HostConfig}
+	if inspect.NetworkSettings != nil {
+		snap.Networks = inspect.NetworkSettings.Networks
+	}
+	raw, err := json.Marshal(snap)
+	if err != nil {
+		return "", fmt.Errorf("encode rollback snapshot: %w", err)
+	}
+	return string(raw), nil
+}
+
+// rollbackWindowInternal returns how long after an update it can be rolled
+// back; zero disables rollback.
+func (s *UpdaterService) rollbackWindowInternal(ctx context.Context) time.Duration {
+	if s.settingsService == nil {
+		return 0
+	}
+	minutes := s.settingsService.GetIntSetting(ctx, "autoUpdateRollbackWindow", 60)
+	if minutes <= 0 {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// recordRollbackInternal keeps what an update replaced, replacing the record
+// of any earlier update of the same container.
+func (s *UpdaterService) recordRollbackInternal(ctx context.Context, record models.ContainerRollback) {
+	window := s.rollbackWindowInternal(ctx)
+	if window == 0 || s.db == nil {
+		return
+	}
+
+	record.AppliedAt = time.Now()
+	record.ObserveUntil = record.AppliedAt.Add(window)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("container_name = ?", record.ContainerName).Delete(&models.ContainerRollback{}).Error; err != nil {
+			return err
+		}
+		return tx.Create(&record).Error
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "could not record container rollback", "container", record.ContainerName, "err", err)
+	}
+}
+
+// rollbackImageIDsInternal returns the previous images of updates that can
+// still be rolled back.
+func (s *UpdaterService) rollbackImageIDsInternal(ctx context.Context) (map[string]struct{}, error) {
+	out := map[string]struct{}{}
+	if s.db == nil {
+		return out, nil
+	}
+	var ids []string
+	err := s.db.WithContext(ctx).Model(&models.ContainerRollback{}).
+		Where("rolled_back_at IS NULL AND observe_until > ?", time.Now()).
+		Pluck("previous_image_id", &ids).Error
+	if err != nil {
+		return nil, fmt.Errorf("load rollback images: %w", err)
+	}
+	for _, id := range ids {
+		out[id] = struct{}{}
+	}
+	return out, nil
+}
+
+// GetRollback returns the last auto-update of a container and whether it can
+// be rolled back. containerID may be a container ID or name.
+func (s *UpdaterService) GetRollback(ctx context.Context, containerID string) (*containertypes.RollbackStatus, error) {
+	record, inspect, err := s.loadRollbackInternal(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+	status := s.rollbackStatusInternal(ctx, *record, inspect)
+	return &status, nil
+}
+
+// RollbackContainer recreates a container with the configuration and image
+// it had before its last auto-update. The update must be within the rollback
+// window, and unless force is set the updated container must be unhealthy.
+// The container is created from the previous image ID, because the tag it
+// used now points to the new image.
+func (s *UpdaterService) RollbackContainer(ctx context.Context, containerID string, force bool, user models.User) (*containertypes.RollbackStatus, error) {
+	record, inspect, err := s.loadRollbackInternal(ctx, containerID)
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case record.RolledBackAt != nil:
+		return nil, ErrAlreadyRolledBack
+	case time.Now().After(record.ObserveUntil):
+		return nil, ErrRollbackExpired
+	case !force && inspect != nil && containerHealthInternal(*inspect) != rollbackHealthUnhealthy:
+		return nil, ErrRollbackNotNeeded
+	}
+
+	dcli, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("docker connect: %w", err)
+	}
+	if _, err := dcli.ImageInspect(ctx, record.PreviousImageID); err != nil {
+		if cerrdefs.IsNotFound(err) {
+			return nil, ErrRollbackImageMissing
+		}
+		return nil, fmt.Errorf("inspect previous image: %w", err)
+	}
+
+	var snap rollbackSnapshot
+	if err := json.Unmarshal([]byte(record.Snapshot), &snap); err != nil || snap.Config == nil || snap.HostConfig == nil {
+		return nil, fmt.Errorf("rollback snapshot of %s is unreadable", record.ContainerName)
+	}
+
+	oldID := ""
+	if inspect != nil {
+		oldID = inspect.ID
+	}
+	target := container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{Name: snap.Name, HostConfig: snap.HostConfig},
+		Config:            snap.Config,
+		NetworkSettings:   &container.NetworkSettings{Networks: snap.Networks},
+	}
+	newID, err := s.recreateContainerInternal(ctx, dcli, oldID, record.ContainerName, target, record.PreviousImageID)
+	if err != nil {
+		return nil, fmt.Errorf("rollback %s: %w", record.ContainerName, err)
+	}
+
+	now := time.Now()
+	updates := map[string]any{"container_id": newID, "rolled_back_at": now, "rolled_back_by": user.Username}
+	if err := s.db.WithContext(ctx).Model(record).Updates(updates).Error; err != nil {
+		slog.WarnContext(ctx, "could not mark container rolled back", "container", record.ContainerName, "err", err)
+	}
+
+	metadata := models.JSON{
+		"action":           "rollback",
+		"oldContainerId":   oldID,
+		"newContainerId":   newID,
+		"previousImageRef": record.PreviousImageRef,
+		"previousImageId":  record.PreviousImageID,
+		"newImageRef":      record.NewImageRef,
+		"force":            force,
+	}
+	if logErr := s.eventService.LogContainerEvent(ctx, models.EventTypeContainerRollback, newID, record.ContainerName, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log container rollback", "container", record.ContainerName, "error", logErr)
+	}
+
+	return s.GetRollback(ctx, newID)
+}
+
+// loadRollbackInternal returns the rollback record of a container and the
+// container itself, which is nil when it no longer exists.
+func (s *UpdaterService) loadRollbackInternal(ctx context.Context, containerID string) (*models.ContainerRollback, *container.InspectResponse, error) {
+	dcli, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, nil, fmt.Errorf("docker connect: %w", err)
+	}
+
+	name := strings.TrimPrefix(strings.TrimSpace(containerID), "/")
+	var inspect *container.InspectResponse
+	if resp, err := dcli.ContainerInspect(ctx, containerID); err == nil {
+		inspect = &resp
+		name = strings.TrimPrefix(resp.Name, "/")
+	} else if !cerrdefs.IsNotFound(err) {
+		return nil, nil, fmt.Errorf("inspect container: %w", err)
+	}
+
+	var record models.ContainerRollback
+	if err := s.db.WithContext(ctx).Where("container_name = ?", name).First(&record).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			if inspect == nil {
+				return nil, nil, fmt.Errorf("%w: %s", ErrDockerContainerNotFound, containerID)
+			}
+			return nil, nil, ErrNoRollback
+		}
+		return nil, nil, fmt.Errorf("load container rollback: %w", err)
+	}
+	return &record, inspect, nil
+}
+
+func (s *UpdaterService) rollbackStatusInternal(ctx context.Context, r models.ContainerRollback, inspect *container.InspectResponse) containertypes.RollbackStatus {
+	status := containertypes.RollbackStatus{
+		ContainerName:    r.ContainerName,
+		ContainerID:      r.ContainerID,
+		PreviousImageRef: r.PreviousImageRef,
+		PreviousImageID:  r.PreviousImageID,
+		NewImageRef:      r.NewImageRef,
+		AppliedAt:        r.AppliedAt,
+		ObserveUntil:     r.ObserveUntil,
+		RolledBackAt:     r.RolledBackAt,
+		RolledBackBy:     stringPtrToString(r.RolledBackBy),
+	}
+	if inspect != nil {
+		status.Health = containerHealthInternal(*inspect)
+	}
+
+	status.Available = r.RolledBackAt == nil && time.Now().Before(r.ObserveUntil)
+	if status.Available {
+		if dcli, err := s.dockerService.GetClient(); err == nil {
+			if _, err := dcli.ImageInspect(ctx, r.PreviousImageID); err != nil {
+				status.Available = false
+			}
+		}
+	}
+	return status
+}
+
+// containerHealthInternal reduces the state of a container to healthy,
+// starting or unhealthy. A container that is not running is unhealthy.
+func containerHealthInternal(inspect container.InspectResponse) string {
+	if inspect.ContainerJSONBase == nil || inspect.State == nil || !inspect.State.Running || inspect.State.Restarting {
+		return rollbackHealthUnhealthy
+	}
+	if inspect.State.Health == nil {
+		return rollbackHealthHealthy
+	}
+	switch inspect.State.Health.Status {
+	case container.Unhealthy:
+		return rollbackHealthUnhealthy
+	case container.Starting:
+		return rollbackHealthStarting
+	default:
+		return rollbackHealthHealthy
+	}
+}
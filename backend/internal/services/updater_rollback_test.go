@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+func TestContainerHealth(t *testing.T) {
+	running := &container.State{Running: true}
+	assert.Equal(t, rollbackHealthHealthy, containerHealthInternal(container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{State: running}}))
+
+	exited := &container.State{Running: false}
+	assert.Equal(t, rollbackHealthUnhealthy, containerHealthInternal(container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{State: exited}}))
+
+	starting := &container.State{Running: true, Health: &container.Health{Status: container.Starting}}
+	assert.Equal(t, rollbackHealthStarting, containerHealthInternal(container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{State: starting}}))
+
+	failing := &container.State{Running: true, Health: &container.Health{Status: container.Unhealthy}}
+	assert.Equal(t, rollbackHealthUnhealthy, containerHealthInternal(container.InspectResponse{ContainerJSONBase: &container.ContainerJSONBase{State: failing}}))
+}
+
+func TestUpdaterService_RollbackContainer(t *testing.T) {
+	ctx := context.Background()
+	health := "healthy"
+	var createdImage string
+
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path[strings.Index(r.URL.Path[1:], "/")+1:]
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case path == "/containers/new/json" || path == "/containers/web/json":
+			_, _ = io.WriteString(w, `{"Id":"new","Name":"/web","Image":"sha256:next","State":{"Running":true,"Health":{"Status":"`+health+`"}},"Config":{"Image":"nginx:1"},"HostConfig":{}}`)
+		case path == "/containers/restored/json":
+			_, _ = io.WriteString(w, `{"Id":"restored","Name":"/web","Image":"sha256:prev","State":{"Running":true},"Config":{"Image":"sha256:prev"},"HostConfig":{}}`)
+		case path == "/images/sha256:prev/json":
+			_, _ = io.WriteString(w, `{"Id":"sha256:prev"}`)
+		case path == "/containers/new/stop", path == "/containers/restored/start":
+			w.WriteHeader(http.StatusNoContent)
+		case path == "/containers/new" && r.Method == http.MethodDelete:
+			w.WriteHeader(http.StatusNoContent)
+		case path == "/containers/create":
+			var body container.Config
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			createdImage = body.Image
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{"Id":"restored"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = io.WriteString(w, `{"message":"not found"}`)
+		}
+	}))
+	t.Cleanup(docker.Close)
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.SettingVariable{}, &models.Event{}, &models.ContainerRollback{}))
+	db := &database.DB{DB: gdb}
+
+	settingsService, err := NewSettingsService(ctx, db)
+	require.NoError(t, err)
+	require.NoError(t, settingsService.EnsureDefaultSettings(ctx))
+
+	svc := &UpdaterService{db: db, settingsService: settingsService, dockerService: &DockerClientService{client: cli}, eventService: NewEventService(db)}
+
+	previous := container.InspectResponse{
+		ContainerJSONBase: &container.ContainerJSONBase{ID: "old", Name: "/web", Image: "sha256:prev", HostConfig: &container.HostConfig{}},
+		Config:            &container.Config{Image: "nginx:1", Env: []string{"A=1"}},
+	}
+	snapshot, err := newRollbackSnapshotInternal(previous)
+	require.NoError(t, err)
+	svc.recordRollbackInternal(ctx, models.ContainerRollback{
+		ContainerName:       "web",
+		ContainerID:         "new",
+		PreviousContainerID: "old",
+		PreviousImageRef:    "nginx:1",
+		PreviousImageID:     "sha256:prev",
+		NewImageRef:         "nginx:1",
+		Snapshot:            snapshot,
+	})
+
+	images, err := svc.rollbackImageIDsInternal(ctx)
+	require.NoError(t, err)
+	assert.Contains(t, images, "sha256:prev", "the previous image is kept from pruning")
+
+	status, err := svc.GetRollback(ctx, "web")
+	require.NoError(t, err)
+	assert.True(t, status.Available)
+	assert.Equal(t, rollbackHealthHealthy, status.Health)
+
+	user := models.User{Username: "admin"}
+	_, err = svc.RollbackContainer(ctx, "new", false, user)
+	require.ErrorIs(t, err, ErrRollbackNotNeeded)
+
+	health = "unhealthy"
+	status, err = svc.RollbackContainer(ctx, "new", false, user)
+	require.NoError(t, err)
+	assert.Equal(t, "sha256:prev", createdImage)
+	assert.Equal(t, "restored", status.ContainerID)
+	assert.Equal(t, "admin", status.RolledBackBy)
+	assert.False(t, status.Available)
+
+	_, err = svc.RollbackContainer(ctx, "restored", true, user)
+	require.ErrorIs(t, err, ErrAlreadyRolledBack)
+
+	images, err = svc.rollbackImageIDsInternal(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, images)
+}
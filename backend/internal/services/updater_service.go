@@ -65,11 +65,27 @@ func NewUpdaterService(
 	}
 }
 
-//nolint:gocognit
 func (s *UpdaterService) ApplyPending(ctx context.Context, dryRun bool) (*updater.Result, error) {
+	return s.ApplyPendingImages(ctx, dryRun, nil)
+}
+
+// ApplyPendingImages applies the pending updates of the given image
+// references only, matched against the reference the resources run now. No
+// images means all pending updates.
+//
+//nolint:gocognit
+func (s *UpdaterService) ApplyPendingImages(ctx context.Context, dryRun bool, images []string) (*updater.Result, error) {
 	start := time.Now()
 	out := &updater.Result{Items: []updater.ResourceResult{}}
 
+	var onlyImages map[string]struct{}
+	if len(images) > 0 {
+		onlyImages = make(map[string]struct{}, len(images))
+		for _, ref := range images {
+			onlyImages[s.normalizeRef(ref)] = struct{}{}
+		}
+	}
+
 	var records []models.ImageUpdateRecord
 	if err := s.db.WithContext(ctx).Where("has_update = ?", true).Find(&records).Error; err != nil {
 		return nil, fmt.Errorf("query pending image updates: %w", err)
@@ -77,7 +93,16 @@ func (s *UpdaterService) ApplyPending(ctx context.Context, dryRun bool) (*update
 	// debug: how many pending records and dryRun flag
 	slog.DebugContext(ctx, "ApplyPending: found pending image update records", "records", len(records), "dryRun", dryRun)
 
-	if len(records) == 0 {
+	// Newer version tags allowed by container update policies
+	tagUpdates, policySkipped := s.planPolicyTagUpdatesInternal(ctx)
+	for _, item := range policySkipped {
+		out.Checked++
+		out.Skipped++
+		out.Items = append(out.Items, item)
+		_ = s.recordRun(ctx, item)
+	}
+
+	if len(records) == 0 && len(tagUpdates) == 0 {
 		out.Duration = time.Since(start).String()
 		return out, nil
 	}
@@ -89,12 +114,20 @@ func (s *UpdaterService) ApplyPending(ctx context.Context, dryRun bool) (*update
 		usedImages = map[string]struct{}{}
 	}
 
+	// Pinned images are never auto-updated
+	pinned, err := s.imageService.GetPinnedImageDigests(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load pinned images: %w", err)
+	}
+
 	// Plan updates and capture OLD image digests before pull
 	type updatePlan struct {
 		oldRef string
 		newRef string
 		oldIDs []string // sha256:... image IDs that currently back oldRef
 		pulled bool
+		// containerIDs are set for policy tag updates, which only move these containers to newRef
+		containerIDs []string
 	}
 	var plans []updatePlan
 
@@ -110,6 +143,11 @@ func (s *UpdaterService) ApplyPending(ctx context.Context, dryRun bool) (*update
 				continue
 			}
 		}
+		if onlyImages != nil {
+			if _, ok := onlyImages[oldNorm]; !ok {
+				continue
+			}
+		}
 
 		newRef := oldRef
 		if r.IsTagUpdate() && r.LatestVersion != nil && *r.LatestVersion != "" {
@@ -117,9 +155,45 @@ func (s *UpdaterService) ApplyPending(ctx context.Context, dryRun bool) (*update
 		}
 
 		oldIDs, _ := s.resolveLocalImageIDsForRef(ctx, oldRef)
+		if isAnyImagePinned(pinned, append([]string{r.ID}, oldIDs...)) {
+			item := updater.ResourceResult{
+				ResourceID:   oldRef,
+				ResourceType: "image",
+				ResourceName: oldRef,
+				Status:       "skipped",
+				Error:        "image is pinned",
+				OldImages:    map[string]string{"main": oldRef},
+				NewImages:    map[string]string{"main": newRef},
+			}
+			out.Checked++
+			out.Skipped++
+			out.Items = append(out.Items, item)
+			_ = s.recordRun(ctx, item)
+			continue
+		}
 		plans = append(plans, updatePlan{oldRef: oldRef, newRef: newRef, oldIDs: oldIDs})
 	}
 
+	tagPlans := map[[2]string]int{}
+	for containerID, u := range tagUpdates {
+		if onlyImages != nil {
+			if _, ok := onlyImages[s.normalizeRef(u.oldRef)]; !ok {
+				continue
+			}
+		}
+		key := [2]string{u.oldRef, u.newRef}
+		if i, ok := tagPlans[key]; ok {
+			plans[i].containerIDs = append(plans[i].containerIDs, containerID)
+			continue
+		}
+		oldIDs, _ := s.resolveLocalImageIDsForRef(ctx, u.oldRef)
+		if isAnyImagePinned(pinned, oldIDs) {
+			continue
+		}
+		tagPlans[key] = len(plans)
+		plans = append(plans, updatePlan{oldRef: u.oldRef, newRef: u.newRef, containerIDs: []string{containerID}})
+	}
+
 	if len(plans) == 0 {
 		out.Duration = time.Since(start).String()
 		return out, nil
@@ -244,10 +318,17 @@ func (s *UpdaterService) ApplyPending(ctx context.Context, dryRun bool) (*update
 	// Build maps for fast matching later (only for successfully pulled updates)
 	oldRefToNewRef := map[string]string{}
 	oldIDToNewRef := map[string]string{} // sha256 -> newRef
+	containerToNewRef := map[string]string{}
 	for _, p := range plans {
 		if !p.pulled {
 			continue
 		}
+		if len(p.containerIDs) > 0 {
+			for _, id := range p.containerIDs {
+				containerToNewRef[id] = p.newRef
+			}
+			continue
+		}
 		oldRefToNewRef[p.oldRef] = p.newRef
 		for _, id := range p.oldIDs {
 			if id != "" {
@@ -256,8 +337,8 @@ func (s *UpdaterService) ApplyPending(ctx context.Context, dryRun bool) (*update
 		}
 	}
 
-	if !dryRun && (len(oldIDToNewRef) > 0 || len(oldRefToNewRef) > 0) {
-		results, err := s.restartContainersUsingOldIDs(ctx, oldIDToNewRef, oldRefToNewRef)
+	if !dryRun && (len(oldIDToNewRef) > 0 || len(oldRefToNewRef) > 0 || len(containerToNewRef) > 0) {
+		results, err := s.restartContainersUsingOldIDs(ctx, oldIDToNewRef, oldRefToNewRef, containerToNewRef)
 		if err != nil {
 			slog.Warn("container restarts had errors", "err", err)
 		}
@@ -424,6 +505,59 @@ func (s *UpdaterService) UpdateSingleContainer(ctx context.Context, containerID
 		return out, nil
 	}
 
+	if arcaneupdater.GetUpdatePolicy(labels) == arcaneupdater.UpdatePolicyNone {
+		slog.InfoContext(ctx, "UpdateSingleContainer: update policy is none", "containerID", containerID)
+		out.Items = append(out.Items, updater.ResourceResult{
+			ResourceID:   targetContainer.ID,
+			ResourceType: "container",
+			ResourceName: containerName,
+			Status:       "skipped",
+			Error:        "update policy is none",
+		})
+		out.Skipped++
+		out.Checked = 1
+		out.Duration = time.Since(start).String()
+		return out, nil
+	}
+
+	pinned, err := s.imageService.GetPinnedImageDigests(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("load pinned images: %w", err)
+	}
+	if isAnyImagePinned(pinned, []string{inspectBefore.Image}) {
+		slog.InfoContext(ctx, "UpdateSingleContainer: image is pinned", "containerID", containerID, "imageID", inspectBefore.Image)
+		out.Items = append(out.Items, updater.ResourceResult{
+			ResourceID:   targetContainer.ID,
+			ResourceType: "container",
+			ResourceName: containerName,
+			Status:       "skipped",
+			Error:        "image is pinned",
+		})
+		out.Skipped++
+		out.Checked = 1
+		out.Duration = time.Since(start).String()
+		return out, nil
+	}
+
+	pinnedContainers, err := loadPinnedContainerNamesInternal(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("load pinned containers: %w", err)
+	}
+	if _, ok := pinnedContainers[containerName]; ok {
+		slog.InfoContext(ctx, "UpdateSingleContainer: container is pinned", "containerID", containerID, "name", containerName)
+		out.Items = append(out.Items, updater.ResourceResult{
+			ResourceID:   targetContainer.ID,
+			ResourceType: "container",
+			ResourceName: containerName,
+			Status:       "skipped",
+			Error:        "container is pinned",
+		})
+		out.Skipped++
+		out.Checked = 1
+		out.Duration = time.Since(start).String()
+		return out, nil
+	}
+
 	// Get the image reference
 	imageRef := targetContainer.Image
 	normalizedRef := s.normalizeRef(imageRef)
@@ -442,6 +576,17 @@ func (s *UpdaterService) UpdateSingleContainer(ctx context.Context, containerID
 		return out, nil
 	}
 
+	// Move to the newest tag the update policy allows, if any
+	if policy := arcaneupdater.GetUpdatePolicy(labels); policy.AllowsTagUpdates() {
+		if tags, err := s.imageUpdateService.VersionTags(ctx, normalizedRef); err == nil {
+			if allowed, _ := arcaneupdater.SelectPolicyTag(policy, tag, tags); allowed != "" {
+				normalizedRef = repo + ":" + allowed
+			}
+		} else {
+			slog.WarnContext(ctx, "UpdateSingleContainer: list version tags failed", "containerID", containerID, "image", normalizedRef, "err", err)
+		}
+	}
+
 	slog.InfoContext(ctx, "UpdateSingleContainer: pulling new image", "containerID", containerID, "image", normalizedRef)
 
 	// Pull the latest image using the image service
@@ -568,10 +713,29 @@ func (s *UpdaterService) pruneImageIDs(ctx context.Context, ids []string) error
 		return fmt.Errorf("docker connect: %w", err)
 	}
 
+	pinned, err := s.imageService.GetPinnedImageDigests(ctx)
+	if err != nil {
+		return fmt.Errorf("load pinned images: %w", err)
+	}
+
+	// Images a container can still be rolled back to are kept
+	rollbackImages, err := s.rollbackImageIDsInternal(ctx)
+	if err != nil {
+		return err
+	}
+
 	for _, id := range ids {
 		if id == "" {
 			continue
 		}
+		if isAnyImagePinned(pinned, []string{id}) {
+			slog.DebugContext(ctx, "pruneImageIDs: image is pinned, skipping", "imageId", id)
+			continue
+		}
+		if _, ok := rollbackImages[id]; ok {
+			slog.DebugContext(ctx, "pruneImageIDs: image is kept for rollback, skipping", "imageId", id)
+			continue
+		}
 
 		slog.DebugContext(ctx, "pruneImageIDs: checking image id", "imageId", id)
 
@@ -642,8 +806,7 @@ func (s *UpdaterService) updateContainer(ctx context.Context, cnt container.Summ
 	}
 
 	name := s.getContainerName(cnt)
-	labels := inspect.Config.Labels
-	isArcane := arcaneupdater.IsArcaneContainer(labels)
+	isArcane := arcaneupdater.IsArcaneContainer(inspect.Config.Labels)
 
 	// Arcane containers should always use CLI upgrade, not inline update
 	// This method should not be called for Arcane containers
@@ -654,7 +817,57 @@ func (s *UpdaterService) updateContainer(ctx context.Context, cnt container.Summ
 
 	slog.DebugContext(ctx, "updateContainer: starting update", "containerId", cnt.ID, "containerName", name, "newRef", newRef, "isArcane", isArcane)
 
+	if err := s.imageService.CheckScanGate(ctx, newRef, systemUser); err != nil {
+		return err
+	}
+
+	// Keep the container as it is now so the update can be rolled back
+	snapshot, snapErr := newRollbackSnapshotInternal(inspect)
+	if snapErr != nil {
+		slog.WarnContext(ctx, "updateContainer: could not snapshot container for rollback", "containerId", cnt.ID, "err", snapErr)
+	}
+	previousImageRef := ""
+	if inspect.Config != nil {
+		previousImageRef = inspect.Config.Image
+	}
+	previousImageID := inspect.Image
+
+	newID, err := s.recreateContainerInternal(ctx, dcli, cnt.ID, name, inspect, newRef)
+	if err != nil {
+		return err
+	}
+
+	_ = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerUpdate, newID, name, systemUser.ID, systemUser.Username, "0", models.JSON{
+		"oldContainerId": cnt.ID,
+		"newContainerId": newID,
+		"newImage":       newRef,
+	})
+
+	if snapErr == nil {
+		s.recordRollbackInternal(ctx, models.ContainerRollback{
+			ContainerName:       name,
+			ContainerID:         newID,
+			PreviousContainerID: cnt.ID,
+			PreviousImageRef:    previousImageRef,
+			PreviousImageID:     previousImageID,
+			NewImageRef:         newRef,
+			Snapshot:            snapshot,
+		})
+	}
+
+	slog.DebugContext(ctx, "updateContainer: update complete", "oldContainerId", cnt.ID, "newContainerId", newID)
+	return nil
+}
+
+// recreateContainerInternal stops and removes the container oldID, when set,
+// and creates and starts a container with the configuration of inspect on
+// imageRef under the same name. It returns the ID of the new container.
+func (s *UpdaterService) recreateContainerInternal(ctx context.Context, dcli *client.Client, oldID, name string, inspect container.InspectResponse, imageRef string) (string, error) {
 	originalName := inspect.Name
+	labels := map[string]string{}
+	if inspect.Config != nil && inspect.Config.Labels != nil {
+		labels = inspect.Config.Labels
+	}
 
 	// Get custom stop signal if configured
 	stopSignal := arcaneupdater.GetStopSignal(labels)
@@ -664,23 +877,25 @@ func (s *UpdaterService) updateContainer(ctx context.Context, cnt container.Summ
 		slog.DebugContext(ctx, "updateContainer: using custom stop signal", "signal", stopSignal)
 	}
 
-	// Stop the container
-	if err := dcli.ContainerStop(ctx, cnt.ID, stopOpts); err != nil {
-		slog.DebugContext(ctx, "updateContainer: stop failed", "containerId", cnt.ID, "err", err)
-		return fmt.Errorf("stop: %w", err)
-	}
-	_ = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerStop, cnt.ID, name, systemUser.ID, systemUser.Username, "0", models.JSON{"action": "updater_stop"})
+	if oldID != "" {
+		// Stop the container
+		if err := dcli.ContainerStop(ctx, oldID, stopOpts); err != nil {
+			slog.DebugContext(ctx, "updateContainer: stop failed", "containerId", oldID, "err", err)
+			return "", fmt.Errorf("stop: %w", err)
+		}
+		_ = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerStop, oldID, name, systemUser.ID, systemUser.Username, "0", models.JSON{"action": "updater_stop"})
 
-	// Remove the container
-	if err := dcli.ContainerRemove(ctx, cnt.ID, container.RemoveOptions{}); err != nil {
-		slog.DebugContext(ctx, "updateContainer: remove failed", "containerId", cnt.ID, "err", err)
-		return fmt.Errorf("remove: %w", err)
+		// Remove the container
+		if err := dcli.ContainerRemove(ctx, oldID, container.RemoveOptions{}); err != nil {
+			slog.DebugContext(ctx, "updateContainer: remove failed", "containerId", oldID, "err", err)
+			return "", fmt.Errorf("remove: %w", err)
+		}
+		_ = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerDelete, oldID, name, systemUser.ID, systemUser.Username, "0", models.JSON{"action": "updater_delete"})
 	}
-	_ = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerDelete, cnt.ID, name, systemUser.ID, systemUser.Username, "0", models.JSON{"action": "updater_delete"})
 
 	// recreate with new image ref
 	cfg := inspect.Config
-	cfg.Image = newRef
+	cfg.Image = imageRef
 
 	// Fix for "conflicting options: hostname and the network mode"
 	// When network mode is "host" or "container:...", Hostname must be empty
@@ -699,7 +914,7 @@ func (s *UpdaterService) updateContainer(ctx context.Context, cnt container.Summ
 	}
 
 	var networkingConfig *network.NetworkingConfig
-	if !nm.IsContainer() {
+	if !nm.IsContainer() && inspect.NetworkSettings != nil {
 		networkingConfig = &network.NetworkingConfig{EndpointsConfig: inspect.NetworkSettings.Networks}
 	}
 
@@ -709,24 +924,17 @@ func (s *UpdaterService) updateContainer(ctx context.Context, cnt container.Summ
 	resp, err := dcli.ContainerCreate(ctx, cfg, inspect.HostConfig, networkingConfig, nil, containerName)
 	if err != nil {
 		slog.DebugContext(ctx, "updateContainer: create failed", "containerName", containerName, "err", err)
-		return fmt.Errorf("create: %w", err)
+		return "", fmt.Errorf("create: %w", err)
 	}
 	_ = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerCreate, resp.ID, name, systemUser.ID, systemUser.Username, "0", models.JSON{"action": "updater_create", "newImageId": resp.ID})
 
 	if err := dcli.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
 		slog.DebugContext(ctx, "updateContainer: start failed", "newContainerId", resp.ID, "err", err)
-		return fmt.Errorf("start: %w", err)
+		return "", fmt.Errorf("start: %w", err)
 	}
 	_ = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerStart, resp.ID, name, systemUser.ID, systemUser.Username, "0", models.JSON{"action": "updater_start"})
 
-	_ = s.eventService.LogContainerEvent(ctx, models.EventTypeContainerUpdate, resp.ID, name, systemUser.ID, systemUser.Username, "0", models.JSON{
-		"oldContainerId": cnt.ID,
-		"newContainerId": resp.ID,
-		"newImage":       newRef,
-	})
-
-	slog.DebugContext(ctx, "updateContainer: update complete", "oldContainerId", cnt.ID, "newContainerId", resp.ID)
-	return nil
+	return resp.ID, nil
 }
 
 // normalizeRef returns a canonical "registry/repository:tag" without digest.
@@ -953,7 +1161,7 @@ func (s *UpdaterService) resolveLocalImageIDsForRef(ctx context.Context, ref str
 }
 
 //nolint:gocognit
-func (s *UpdaterService) restartContainersUsingOldIDs(ctx context.Context, oldIDToNewRef map[string]string, oldRefToNewRef map[string]string) ([]updater.ResourceResult, error) {
+func (s *UpdaterService) restartContainersUsingOldIDs(ctx context.Context, oldIDToNewRef map[string]string, oldRefToNewRef map[string]string, containerToNewRef map[string]string) ([]updater.ResourceResult, error) {
 	dcli, err := s.dockerService.GetClient()
 	if err != nil {
 		return nil, fmt.Errorf("docker connect: %w", err)
@@ -975,6 +1183,12 @@ func (s *UpdaterService) restartContainersUsingOldIDs(ctx context.Context, oldID
 		}
 	}
 
+	// Pinned containers stay on the digest they run
+	pinnedContainers, err := loadPinnedContainerNamesInternal(ctx, s.db)
+	if err != nil {
+		return nil, fmt.Errorf("load pinned containers: %w", err)
+	}
+
 	updatedNorm := map[string]string{}
 	for oldRef, nr := range oldRefToNewRef {
 		updatedNorm[s.normalizeRef(oldRef)] = nr
@@ -995,6 +1209,7 @@ func (s *UpdaterService) restartContainersUsingOldIDs(ctx context.Context, oldID
 
 	// Cache resolved IDs for newRefs to avoid repeated API calls
 	targetImageIDs := map[string][]string{}
+	var policySkipped []updater.ResourceResult
 
 	for _, c := range list {
 		// Check exclusions first by container name(s)
@@ -1010,6 +1225,10 @@ func (s *UpdaterService) restartContainersUsingOldIDs(ctx context.Context, oldID
 			slog.DebugContext(ctx, "restartContainersUsingOldIDs: skipping excluded container", "containerId", c.ID, "names", c.Names)
 			continue
 		}
+		if _, ok := pinnedContainers[s.getContainerName(c)]; ok {
+			slog.InfoContext(ctx, "restartContainersUsingOldIDs: skipping pinned container", "containerId", c.ID, "names", c.Names)
+			continue
+		}
 
 		inspect, err := dcli.ContainerInspect(ctx, c.ID)
 		if err != nil {
@@ -1054,6 +1273,31 @@ func (s *UpdaterService) restartContainersUsingOldIDs(ctx context.Context, oldID
 			}
 		}
 
+		// A newer tag allowed by the update policy wins over a new digest of the current tag
+		if nr, ok := containerToNewRef[c.ID]; ok {
+			newRef = nr
+			match = s.normalizeRef(c.Image)
+		}
+
+		if newRef != "" {
+			policy := arcaneupdater.GetUpdatePolicy(labels)
+			_, curTag := s.parseRepoAndTag(s.normalizeRef(c.Image))
+			_, newTag := s.parseRepoAndTag(s.normalizeRef(newRef))
+			if !policy.Allows(curTag, newTag) {
+				slog.InfoContext(ctx, "restartContainersUsingOldIDs: update excluded by policy", "containerId", c.ID, "containerName", dep.Name, "policy", policy, "newRef", newRef)
+				policySkipped = append(policySkipped, updater.ResourceResult{
+					ResourceID:   c.ID,
+					ResourceName: dep.Name,
+					ResourceType: "container",
+					Status:       "skipped",
+					Error:        fmt.Sprintf("update policy %s excludes %s", policy, s.normalizeRef(newRef)),
+					OldImages:    map[string]string{"main": match},
+					NewImages:    map[string]string{"main": s.normalizeRef(newRef)},
+				})
+				newRef = ""
+			}
+		}
+
 		if newRef != "" {
 			// Check if container is already on the target image
 			tids, cached := targetImageIDs[newRef]
@@ -1118,7 +1362,7 @@ func (s *UpdaterService) restartContainersUsingOldIDs(ctx context.Context, oldID
 		sorted = candidates
 	}
 
-	var results []updater.ResourceResult
+	results := policySkipped
 	for _, cd := range sorted {
 		p := plansByName[cd.Name]
 		if p == nil {
@@ -1186,6 +1430,65 @@ func (s *UpdaterService) restartContainersUsingOldIDs(ctx context.Context, oldID
 	return results, nil
 }
 
+// policyTagUpdate moves a container from oldRef to the newer version tag newRef.
+type policyTagUpdate struct {
+	oldRef string
+	newRef string
+}
+
+// planPolicyTagUpdatesInternal finds the newest version tag each running
+// container may move to under its update policy, keyed by container ID. Newer
+// tags the policy rules out are returned as skipped results.
+func (s *UpdaterService) planPolicyTagUpdatesInternal(ctx context.Context) (map[string]policyTagUpdate, []updater.ResourceResult) {
+	out := map[string]policyTagUpdate{}
+	if s.imageUpdateService == nil {
+		return out, nil
+	}
+
+	dcli, err := s.dockerService.GetClient()
+	if err != nil {
+		return out, nil
+	}
+	list, err := dcli.ContainerList(ctx, container.ListOptions{All: false})
+	if err != nil {
+		slog.WarnContext(ctx, "planPolicyTagUpdates: list containers failed", "err", err)
+		return out, nil
+	}
+
+	var skipped []updater.ResourceResult
+	for _, c := range list {
+		policy := arcaneupdater.GetUpdatePolicy(c.Labels)
+		if !policy.AllowsTagUpdates() || arcaneupdater.IsUpdateDisabled(c.Labels) || strings.HasPrefix(c.Image, "sha256:") {
+			continue
+		}
+
+		oldRef := s.normalizeRef(c.Image)
+		repo, tag := s.parseRepoAndTag(oldRef)
+		tags, err := s.imageUpdateService.VersionTags(ctx, oldRef)
+		if err != nil {
+			slog.DebugContext(ctx, "planPolicyTagUpdates: list version tags failed", "containerId", c.ID, "image", oldRef, "err", err)
+			continue
+		}
+
+		allowed, latest := arcaneupdater.SelectPolicyTag(policy, tag, tags)
+		switch {
+		case allowed != "":
+			out[c.ID] = policyTagUpdate{oldRef: oldRef, newRef: repo + ":" + allowed}
+		case latest != "":
+			skipped = append(skipped, updater.ResourceResult{
+				ResourceID:   c.ID,
+				ResourceName: s.getContainerName(c),
+				ResourceType: "container",
+				Status:       "skipped",
+				Error:        fmt.Sprintf("update policy %s excludes %s", policy, repo+":"+latest),
+				OldImages:    map[string]string{"main": oldRef},
+				NewImages:    map[string]string{"main": repo + ":" + latest},
+			})
+		}
+	}
+	return out, skipped
+}
+
 // parseNormalizedRef expects a normalized ref in the form "host/repository:tag".
 func (s *UpdaterService) parseNormalizedRef(ref string) (host, repository, tag string) {
 	// host/repo:tag
@@ -1338,3 +1641,12 @@ func (s *UpdaterService) parseRepoAndTag(ref string) (string, string) {
 	// Keep registry in repository as stored in records (they store Repository without tag)
 	return ref, tag
 }
+
+func isAnyImagePinned(pinned map[string]struct{}, ids []string) bool {
+	for _, id := range ids {
+		if _, ok := pinned[id]; ok {
+			return true
+		}
+	}
+	return false
+}
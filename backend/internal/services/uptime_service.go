@@ -0,0 +1,233 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/pkg/libarcane"
+	uptimetypes "github.com/getarcaneapp/arcane/types/uptime"
+)
+
+const (
+	UptimeResourceContainer = "container"
+	UptimeResourceProject   = "project"
+
+	uptimeRetention     = 31 * 24 * time.Hour
+	uptimePruneInterval = time.Hour
+)
+
+// latestUptimeTransitionsQuery selects the newest transition of every
+// resource recorded before the given time.
+const latestUptimeTransitionsQuery = `at = (SELECT MAX(i.at) FROM uptime_transitions i
+	WHERE i.resource_type = uptime_transitions.resource_type AND i.resource_key = uptime_transitions.resource_key AND i.at < ?)`
+
+// UptimeService records when containers and projects go up or down and
+// computes their uptime. Containers are tracked by name so a recreated
+// container keeps its history; projects are up while none of their
+// containers is stopped.
+type UptimeService struct {
+	db            *database.DB
+	dockerService *DockerClientService
+
+	mu        sync.Mutex
+	states    map[string]bool
+	lastPrune time.Time
+}
+
+func NewUptimeService(db *database.DB, dockerService *DockerClientService) *UptimeService {
+	return &UptimeService{
+		db:            db,
+		dockerService: dockerService,
+	}
+}
+
+// RecordSample compares the current state of all containers and projects
+// with the last recorded one and stores a transition for each change.
+// Resources that disappeared are recorded as down.
+func (s *UptimeService) RecordSample(ctx context.Context) error {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{All: true})
+	if err != nil {
+		return fmt.Errorf("failed to list containers: %w", err)
+	}
+
+	observed := observeUptimeStatesInternal(containers)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.states == nil {
+		states, err := s.loadStatesInternal(ctx)
+		if err != nil {
+			return err
+		}
+		s.states = states
+	}
+
+	now := time.Now().UTC()
+	var changes []models.UptimeTransition
+	for key, up := range observed {
+		if previous, ok := s.states[key]; !ok || previous != up {
+			changes = append(changes, newUptimeTransitionInternal(key, up, now))
+		}
+	}
+	for key, up := range s.states {
+		if _, ok := observed[key]; !ok && up {
+			changes = append(changes, newUptimeTransitionInternal(key, false, now))
+		}
+	}
+
+	if len(changes) > 0 {
+		if err := s.db.WithContext(ctx).Create(&changes).Error; err != nil {
+			return fmt.Errorf("failed to record uptime transitions: %w", err)
+		}
+		for _, c := range changes {
+			s.states[uptimeStateKeyInternal(c.ResourceType, c.ResourceKey)] = c.Up
+		}
+	}
+
+	if now.Sub(s.lastPrune) >= uptimePruneInterval {
+		s.lastPrune = now
+		cutoff := now.Add(-uptimeRetention)
+		// Keep the newest transition before the cutoff so the state at the
+		// start of the 30 day window stays known.
+		err := s.db.WithContext(ctx).
+			Where("at < ?", cutoff).
+			Where("id NOT IN (?)", s.db.Model(&models.UptimeTransition{}).Select("id").Where(latestUptimeTransitionsQuery, cutoff)).
+			Delete(&models.UptimeTransition{}).Error
+		if err != nil {
+			return fmt.Errorf("failed to prune uptime transitions: %w", err)
+		}
+	}
+	return nil
+}
+
+// GetStats returns the uptime of the given containers or projects, keyed by
+// container name or project name. Resources without history are omitted.
+func (s *UptimeService) GetStats(ctx context.Context, resourceType string, keys []string) (map[string]uptimetypes.Stats, error) {
+	return loadUptimeStatsInternal(ctx, s.db, resourceType, keys, time.Now().UTC())
+}
+
+func (s *UptimeService) loadStatesInternal(ctx context.Context) (map[string]bool, error) {
+	var latest []models.UptimeTransition
+	if err := s.db.WithContext(ctx).Where(latestUptimeTransitionsQuery, time.Now().UTC().Add(time.Minute)).Find(&latest).Error; err != nil {
+		return nil, fmt.Errorf("failed to load uptime states: %w", err)
+	}
+	states := make(map[string]bool, len(latest))
+	for _, t := range latest {
+		states[uptimeStateKeyInternal(t.ResourceType, t.ResourceKey)] = t.Up
+	}
+	return states, nil
+}
+
+// observeUptimeStatesInternal maps each container and compose project to
+// whether it is up. Arcane's own helper containers are ignored.
+func observeUptimeStatesInternal(containers []container.Summary) map[string]bool {
+	observed := make(map[string]bool, len(containers))
+	for _, c := range containers {
+		if libarcane.IsInternalContainer(c.Labels) || len(c.Names) == 0 {
+			continue
+		}
+		running := c.State == "running"
+		observed[uptimeStateKeyInternal(UptimeResourceContainer, strings.TrimPrefix(c.Names[0], "/"))] = running
+
+		if project := c.Labels[composeProjectLabel]; project != "" {
+			// One-off services such as migrations exit cleanly and do not
+			// take the project down.
+			serviceUp := running || strings.HasPrefix(c.Status, "Exited (0)")
+			key := uptimeStateKeyInternal(UptimeResourceProject, project)
+			if up, seen := observed[key]; seen {
+				observed[key] = up && serviceUp
+			} else {
+				observed[key] = serviceUp
+			}
+		}
+	}
+	return observed
+}
+
+func uptimeStateKeyInternal(resourceType, key string) string {
+	return resourceType + "/" + key
+}
+
+func newUptimeTransitionInternal(stateKey string, up bool, at time.Time) models.UptimeTransition {
+	resourceType, key, _ := strings.Cut(stateKey, "/")
+	return models.UptimeTransition{ResourceType: resourceType, ResourceKey: key, Up: up, At: at}
+}
+
+// loadUptimeStatsInternal computes uptime over the last 24 hours, 7 days and
+// 30 days for each key with recorded history.
+func loadUptimeStatsInternal(ctx context.Context, db *database.DB, resourceType string, keys []string, now time.Time) (map[string]uptimetypes.Stats, error) {
+	out := map[string]uptimetypes.Stats{}
+	if db == nil || len(keys) == 0 {
+		return out, nil
+	}
+
+	from := now.Add(-30 * 24 * time.Hour)
+	var transitions []models.UptimeTransition
+	err := db.WithContext(ctx).
+		Where("resource_type = ? AND resource_key IN ?", resourceType, keys).
+		Where(db.Where("at >= ?", from).Or(latestUptimeTransitionsQuery, from)).
+		Order("at ASC").
+		Find(&transitions).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load uptime transitions: %w", err)
+	}
+
+	byKey := make(map[string][]models.UptimeTransition)
+	for _, t := range transitions {
+		byKey[t.ResourceKey] = append(byKey[t.ResourceKey], t)
+	}
+	for key, history := range byKey {
+		out[key] = uptimetypes.Stats{
+			Up:      history[len(history)-1].Up,
+			Last24h: uptimePercentInternal(history, now.Add(-24*time.Hour), now),
+			Last7d:  uptimePercentInternal(history, now.Add(-7*24*time.Hour), now),
+			Last30d: uptimePercentInternal(history, from, now),
+		}
+	}
+	return out, nil
+}
+
+// uptimePercentInternal returns the share of [from, to] the resource was up,
+// counting only time after its first transition. history must be sorted by
+// time. It returns nil when no time in the window is tracked.
+func uptimePercentInternal(history []models.UptimeTransition, from, to time.Time) *float64 {
+	var tracked, up time.Duration
+	for i, t := range history {
+		start := t.At
+		end := to
+		if i+1 < len(history) {
+			end = history[i+1].At
+		}
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(to) {
+			end = to
+		}
+		if !end.After(start) {
+			continue
+		}
+		tracked += end.Sub(start)
+		if t.Up {
+			up += end.Sub(start)
+		}
+	}
+	if tracked <= 0 {
+		return nil
+	}
+	percent := math.Round(float64(up)/float64(tracked)*10000) / 100
+	return &percent
+}
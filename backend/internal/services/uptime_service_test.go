@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+)
+
+func TestUptimePercent(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+	history := []models.UptimeTransition{
+		{Up: true, At: now.Add(-48 * time.Hour)},
+		{Up: false, At: now.Add(-6 * time.Hour)},
+		{Up: true, At: now.Add(-3 * time.Hour)},
+	}
+
+	day := uptimePercentInternal(history, now.Add(-24*time.Hour), now)
+	require.NotNil(t, day)
+	assert.InDelta(t, 87.5, *day, 0.001)
+
+	// Only the 48 tracked hours count towards the week.
+	week := uptimePercentInternal(history, now.Add(-7*24*time.Hour), now)
+	require.NotNil(t, week)
+	assert.InDelta(t, 93.75, *week, 0.001)
+
+	assert.Nil(t, uptimePercentInternal(nil, now.Add(-time.Hour), now))
+	assert.Nil(t, uptimePercentInternal(history, now.Add(-time.Hour), now.Add(-time.Hour)))
+}
+
+func TestUptimeService_RecordSample(t *testing.T) {
+	ctx := context.Background()
+	list := `[
+		{"Id":"1","Names":["/web"],"State":"running","Labels":{"com.docker.compose.project":"shop"}},
+		{"Id":"2","Names":["/migrate"],"State":"exited","Status":"Exited (0) 1 hour ago","Labels":{"com.docker.compose.project":"shop"}},
+		{"Id":"3","Names":["/helper"],"State":"running","Labels":{"com.getarcaneapp.internal.container":"true"}}
+	]`
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, list)
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.UptimeTransition{}))
+	db := &database.DB{DB: gdb}
+	svc := NewUptimeService(db, &DockerClientService{client: cli})
+
+	require.NoError(t, svc.RecordSample(ctx))
+	var count int64
+	require.NoError(t, gdb.Model(&models.UptimeTransition{}).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+
+	// Unchanged states record nothing.
+	require.NoError(t, svc.RecordSample(ctx))
+	require.NoError(t, gdb.Model(&models.UptimeTransition{}).Count(&count).Error)
+	assert.Equal(t, int64(3), count)
+
+	// web crashing takes the project down. migrate was already down, so its
+	// removal records nothing.
+	list = `[{"Id":"1","Names":["/web"],"State":"exited","Status":"Exited (137) 1 second ago","Labels":{"com.docker.compose.project":"shop"}}]`
+	svc = NewUptimeService(db, &DockerClientService{client: cli})
+	require.NoError(t, svc.RecordSample(ctx))
+	require.NoError(t, svc.RecordSample(ctx))
+	require.NoError(t, gdb.Model(&models.UptimeTransition{}).Count(&count).Error)
+	assert.Equal(t, int64(5), count)
+
+	stats, err := svc.GetStats(ctx, UptimeResourceContainer, []string{"web", "migrate", "unknown"})
+	require.NoError(t, err)
+	require.Len(t, stats, 2)
+	assert.False(t, stats["web"].Up)
+	assert.False(t, stats["migrate"].Up)
+
+	projects, err := svc.GetStats(ctx, UptimeResourceProject, []string{"shop"})
+	require.NoError(t, err)
+	assert.False(t, projects["shop"].Up)
+
+	// Old history is pruned except the last state before the window.
+	old := time.Now().UTC().Add(-40 * 24 * time.Hour)
+	require.NoError(t, gdb.Create(&[]models.UptimeTransition{
+		{ResourceType: UptimeResourceContainer, ResourceKey: "db", Up: true, At: old},
+		{ResourceType: UptimeResourceContainer, ResourceKey: "db", Up: false, At: old.Add(time.Hour)},
+	}).Error)
+	svc.lastPrune = time.Time{}
+	require.NoError(t, svc.RecordSample(ctx))
+	var db1 []models.UptimeTransition
+	require.NoError(t, gdb.Where("resource_key = ?", "db").Find(&db1).Error)
+	require.Len(t, db1, 1)
+	stats, err = svc.GetStats(ctx, UptimeResourceContainer, []string{"db"})
+	require.NoError(t, err)
+	require.NotNil(t, stats["db"].Last30d)
+	assert.InDelta(t, 0, *stats["db"].Last30d, 0.001)
+}
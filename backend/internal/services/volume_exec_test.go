@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/timeouts"
+)
+
+func TestHelperExecScript_KillScriptStopsCommand(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	pidFile := filepath.Join(t.TempDir(), "exec.pid")
+	cmd := exec.Command("sh", "-c", helperExecScript, pidFile, "sleep", "30")
+	require.NoError(t, cmd.Start())
+	exited := make(chan error, 1)
+	go func() { exited <- cmd.Wait() }()
+
+	require.Eventually(t, func() bool {
+		b, err := os.ReadFile(pidFile)
+		return err == nil && len(b) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	out, err := exec.Command("sh", "-c", helperExecKillScript, "sh", pidFile).CombinedOutput()
+	require.NoError(t, err, string(out))
+
+	select {
+	case <-exited:
+	case <-time.After(5 * time.Second):
+		_ = cmd.Process.Kill()
+		t.Fatal("command still running after the kill script")
+	}
+	assert.NoFileExists(t, pidFile)
+}
+
+func TestHelperExecScript_KeepsExitCodeAndRemovesPidFile(t *testing.T) {
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	pidFile := filepath.Join(t.TempDir(), "exec.pid")
+	out, err := exec.Command("sh", "-c", helperExecScript, pidFile, "sh", "-c", "echo hi; exit 3").Output()
+	var exitErr *exec.ExitError
+	require.ErrorAs(t, err, &exitErr)
+	assert.Equal(t, 3, exitErr.ExitCode())
+	assert.Equal(t, "hi\n", string(out))
+	assert.NoFileExists(t, pidFile)
+}
+
+func TestHelperExecTimeoutInternal_Defaults(t *testing.T) {
+	s := &VolumeService{}
+	assert.Equal(t, timeouts.DefaultHelperExec, s.helperExecTimeoutInternal(helperExecFile))
+	assert.Equal(t, timeouts.DefaultHelperScan, s.helperExecTimeoutInternal(helperExecScan))
+	assert.Equal(t, timeouts.DefaultHelperArchive, s.helperExecTimeoutInternal(helperExecArchive))
+}
+
+func TestExecInContainerInternal_RequestTimeoutOverridesSetting(t *testing.T) {
+	ctx := context.Background()
+	// Every exec takes longer than the one second helper exec timeout.
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := strings.TrimPrefix(r.URL.Path, "/v1.44")
+		switch {
+		case strings.HasSuffix(path, "/exec"):
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{"Id":"exec1"}`)
+		case strings.HasSuffix(path, "/start"):
+			conn, buf, err := w.(http.Hijacker).Hijack()
+			if err != nil {
+				return
+			}
+			defer conn.Close()
+			_, _ = buf.WriteString("HTTP/1.1 101 UPGRADED\r\nContent-Type: application/vnd.docker.raw-stream\r\nConnection: Upgrade\r\nUpgrade: tcp\r\n\r\n")
+			_ = buf.Flush()
+			time.Sleep(1500 * time.Millisecond)
+			frame := make([]byte, 8, 11)
+			frame[0] = 1
+			binary.BigEndian.PutUint32(frame[4:], 3)
+			_, _ = conn.Write(append(frame, "ok\n"...))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer docker.Close()
+
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.SettingVariable{}))
+	settingsService, err := NewSettingsService(ctx, &database.DB{DB: gdb})
+	require.NoError(t, err)
+	require.NoError(t, settingsService.EnsureDefaultSettings(ctx))
+	require.NoError(t, settingsService.SetIntSetting(ctx, "helperExecTimeout", 1))
+
+	s := &VolumeService{dockerService: &DockerClientService{client: cli}, settingsService: settingsService}
+
+	_, _, err = s.execInContainerInternal(ctx, "helper", helperExecFile, []string{"true"})
+	require.ErrorIs(t, err, ErrHelperExecTimeout)
+
+	reqCtx, cancel := timeouts.WithRequestTimeout(ctx, 10*time.Second)
+	defer cancel()
+	stdout, _, err := s.execInContainerInternal(reqCtx, "helper", helperExecFile, []string{"true"})
+	require.NoError(t, err)
+	assert.Equal(t, "ok\n", stdout)
+}
@@ -0,0 +1,296 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/pkg/stdcopy"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/pkg/libarcane"
+)
+
+const (
+	defaultStaleHelperMaxAge = 6 * time.Hour
+	// exitedHelperGrace is how long a stopped helper is left alone, so that
+	// the operation that ran it can still read its logs.
+	exitedHelperGrace = 10 * time.Minute
+	// restoreTmpSweepBatch is how many volumes one sweep container mounts.
+	restoreTmpSweepBatch = 50
+)
+
+// StaleHelperReport lists what ReapStaleHelpers removed.
+type StaleHelperReport struct {
+	// Containers are the IDs of the removed helper containers.
+	Containers []string
+	// RestoreDirs are the removed restore leftovers as volume:/path.
+	RestoreDirs []string
+	// Failed counts the leftovers that could not be removed.
+	Failed int
+}
+
+// Empty reports whether nothing was removed or failed.
+func (r StaleHelperReport) Empty() bool {
+	return len(r.Containers) == 0 && len(r.RestoreDirs) == 0 && r.Failed == 0
+}
+
+// ReapStaleHelpers removes helper containers and restore leftovers that were
+// left behind, usually because Arcane stopped in the middle of an operation:
+//
+//   - stopped containers labeled InternalContainerLabel;
+//   - running ones older than the staleHelperMaxAge setting that this process
+//     does not track;
+//   - .restore_tmp* directories older than that age at the root of volumes.
+//
+// Volumes with a backup or restore in progress are left alone. A report
+// event is recorded when anything was removed.
+func (s *VolumeService) ReapStaleHelpers(ctx context.Context) StaleHelperReport {
+	var report StaleHelperReport
+	maxAge := s.staleHelperMaxAgeInternal()
+	if maxAge <= 0 {
+		return report
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get docker client for stale helper reaping", "error", err)
+		return report
+	}
+
+	busyVolumes := s.busyVolumesInternal()
+	now := time.Now()
+
+	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("label", libarcane.InternalContainerLabel)),
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "failed to list helper containers", "error", err)
+	} else {
+		tracked := s.trackedHelperIDsInternal()
+		for _, c := range containers {
+			if !isStaleHelperInternal(c, tracked, busyVolumes, maxAge, now) {
+				continue
+			}
+			if err := dockerClient.ContainerRemove(ctx, c.ID, container.RemoveOptions{Force: true, RemoveVolumes: true}); err != nil {
+				slog.WarnContext(ctx, "failed to remove stale helper container", "container_id", c.ID, "error", err)
+				report.Failed++
+				continue
+			}
+			report.Containers = append(report.Containers, c.ID)
+		}
+	}
+
+	volumes, err := dockerClient.VolumeList(ctx, volume.ListOptions{})
+	if err != nil {
+		slog.WarnContext(ctx, "failed to list volumes for restore leftovers", "error", err)
+	} else {
+		var candidates []string
+		for _, v := range volumes.Volumes {
+			if v == nil || v.Name == s.backupVolumeName || busyVolumes[v.Name] {
+				continue
+			}
+			candidates = append(candidates, v.Name)
+		}
+		for batch := range slices.Chunk(candidates, restoreTmpSweepBatch) {
+			removed, err := s.sweepRestoreTmpDirsInternal(ctx, batch, maxAge)
+			if err != nil {
+				slog.WarnContext(ctx, "failed to sweep restore leftovers", "volumes", len(batch), "error", err)
+				report.Failed++
+			}
+			report.RestoreDirs = append(report.RestoreDirs, removed...)
+		}
+	}
+
+	if !report.Empty() {
+		s.logStaleHelperReportInternal(ctx, report, maxAge)
+	}
+	return report
+}
+
+// isStaleHelperInternal decides whether an internal container is a leftover.
+func isStaleHelperInternal(c container.Summary, tracked map[string]bool, busyVolumes map[string]bool, maxAge time.Duration, now time.Time) bool {
+	if tracked[c.ID] {
+		return false
+	}
+	for _, m := range c.Mounts {
+		if m.Type == mount.TypeVolume && busyVolumes[m.Name] {
+			return false
+		}
+	}
+	age := now.Sub(time.Unix(c.Created, 0))
+	if c.State == container.StateRunning || c.State == container.StateRestarting || c.State == container.StatePaused {
+		return age >= maxAge
+	}
+	return age >= exitedHelperGrace
+}
+
+// sweepRestoreTmpDirsInternal removes old .restore_tmp* directories from the
+// given volumes with one short-lived helper and returns them as volume:/path.
+func (s *VolumeService) sweepRestoreTmpDirsInternal(ctx context.Context, volumeNames []string, maxAge time.Duration) ([]string, error) {
+	if len(volumeNames) == 0 {
+		return nil, nil
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, err
+	}
+	helperImage, err := s.getHelperImageInternal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	binds := make([]string, 0, len(volumeNames))
+	for i, name := range volumeNames {
+		binds = append(binds, fmt.Sprintf("%s:/volumes/%d", name, i))
+	}
+	minutes := strconv.Itoa(int(maxAge.Minutes()))
+	script := `find /volumes -mindepth 2 -maxdepth 2 -type d -name '.restore_tmp*' -mmin +` + minutes + ` -print -exec rm -rf -- {} +`
+
+	resp, err := dockerClient.ContainerCreate(ctx, &container.Config{
+		Image:           helperImage,
+		Cmd:             []string{"sh", "-c", script},
+		NetworkDisabled: true,
+		Labels: map[string]string{
+			libarcane.InternalContainerLabel: "true",
+		},
+	}, &container.HostConfig{Binds: binds, Resources: s.helperResourcesInternal()}, nil, nil, "")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create sweep container: %w", err)
+	}
+	defer func() {
+		_ = dockerClient.ContainerRemove(context.WithoutCancel(ctx), resp.ID, container.RemoveOptions{Force: true})
+	}()
+
+	if err := dockerClient.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return nil, fmt.Errorf("failed to start sweep container: %w", err)
+	}
+	statusCh, errCh := dockerClient.ContainerWait(ctx, resp.ID, container.WaitConditionNotRunning)
+	var exitCode int64
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return nil, fmt.Errorf("failed to wait for sweep container: %w", err)
+		}
+	case status := <-statusCh:
+		exitCode = status.StatusCode
+	}
+
+	logs, err := dockerClient.ContainerLogs(ctx, resp.ID, container.LogsOptions{ShowStdout: true})
+	if err != nil {
+		return nil, fmt.Errorf("failed to read sweep output: %w", err)
+	}
+	var stdout bytes.Buffer
+	_, _ = stdcopy.StdCopy(&stdout, io.Discard, logs)
+	_ = logs.Close()
+
+	removed := parseRestoreTmpSweepInternal(stdout.String(), volumeNames)
+	if exitCode != 0 {
+		return removed, fmt.Errorf("sweep exited with code %d", exitCode)
+	}
+	return removed, nil
+}
+
+// parseRestoreTmpSweepInternal maps /volumes/<i>/<dir> lines printed by the
+// sweep back to volume:/<dir>.
+func parseRestoreTmpSweepInternal(output string, volumeNames []string) []string {
+	var removed []string
+	for line := range strings.Lines(output) {
+		rest, ok := strings.CutPrefix(strings.TrimSpace(line), "/volumes/")
+		if !ok {
+			continue
+		}
+		idx, dir, ok := strings.Cut(rest, "/")
+		i, err := strconv.Atoi(idx)
+		if !ok || err != nil || i < 0 || i >= len(volumeNames) {
+			continue
+		}
+		removed = append(removed, volumeNames[i]+":/"+dir)
+	}
+	return removed
+}
+
+// busyVolumesInternal returns the volumes with a backup or restore running
+// in this process.
+func (s *VolumeService) busyVolumesInternal() map[string]bool {
+	busy := map[string]bool{}
+	for _, op := range s.operationService.Active() {
+		if op.Kind == models.OperationKindVolumeBackup || op.Kind == models.OperationKindVolumeRestore {
+			busy[op.Resource] = true
+		}
+	}
+	return busy
+}
+
+func (s *VolumeService) trackedHelperIDsInternal() map[string]bool {
+	s.helperMu.Lock()
+	defer s.helperMu.Unlock()
+	tracked := make(map[string]bool, len(s.helperByVolume))
+	for _, helper := range s.helperByVolume {
+		if helper.id != "" {
+			tracked[helper.id] = true
+		}
+	}
+	return tracked
+}
+
+func (s *VolumeService) staleHelperMaxAgeInternal() time.Duration {
+	if s.settingsService == nil {
+		return defaultStaleHelperMaxAge
+	}
+	cfg := s.settingsService.GetSettingsConfig()
+	if cfg == nil {
+		return defaultStaleHelperMaxAge
+	}
+	hours := cfg.StaleHelperMaxAge.AsInt()
+	if hours <= 0 {
+		return 0
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+func (s *VolumeService) logStaleHelperReportInternal(ctx context.Context, report StaleHelperReport, maxAge time.Duration) {
+	if s.eventService == nil {
+		return
+	}
+	severity := models.EventSeverityInfo
+	if report.Failed > 0 {
+		severity = models.EventSeverityWarning
+	}
+
+	resourceType := "system"
+	resourceName := "helper_reaper"
+	environmentID := "0"
+	_, err := s.eventService.CreateEvent(ctx, CreateEventRequest{
+		Type:     models.EventTypeSystemHelperReap,
+		Severity: severity,
+		Title:    "Stale helpers removed",
+		Description: fmt.Sprintf("Removed %d helper containers and %d restore leftovers, %d failed",
+			len(report.Containers), len(report.RestoreDirs), report.Failed),
+		ResourceType:  &resourceType,
+		ResourceName:  &resourceName,
+		EnvironmentID: &environmentID,
+		Metadata: models.JSON{
+			"containers":   report.Containers,
+			"restoreDirs":  report.RestoreDirs,
+			"failed":       report.Failed,
+			"maxAgeHours":  int(maxAge.Hours()),
+			"exitedGraceS": int(exitedHelperGrace.Seconds()),
+		},
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "could not record stale helper report", "error", err)
+	}
+}
@@ -0,0 +1,66 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsStaleHelperInternal(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	created := func(age time.Duration) int64 { return now.Add(-age).Unix() }
+	maxAge := 6 * time.Hour
+
+	tests := []struct {
+		name    string
+		c       container.Summary
+		tracked map[string]bool
+		busy    map[string]bool
+		want    bool
+	}{
+		{
+			name: "recently exited helper is kept",
+			c:    container.Summary{ID: "a", State: container.StateExited, Created: created(time.Minute)},
+		},
+		{
+			name: "old exited helper is removed",
+			c:    container.Summary{ID: "a", State: container.StateExited, Created: created(time.Hour)},
+			want: true,
+		},
+		{
+			name: "running helper under max age is kept",
+			c:    container.Summary{ID: "a", State: container.StateRunning, Created: created(time.Hour)},
+		},
+		{
+			name: "running helper over max age is removed",
+			c:    container.Summary{ID: "a", State: container.StateRunning, Created: created(7 * time.Hour)},
+			want: true,
+		},
+		{
+			name:    "tracked helper is kept",
+			c:       container.Summary{ID: "a", State: container.StateRunning, Created: created(7 * time.Hour)},
+			tracked: map[string]bool{"a": true},
+		},
+		{
+			name: "helper of a busy volume is kept",
+			c: container.Summary{ID: "a", State: container.StateExited, Created: created(time.Hour), Mounts: []container.MountPoint{
+				{Type: mount.TypeVolume, Name: "data"},
+			}},
+			busy: map[string]bool{"data": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isStaleHelperInternal(tt.c, tt.tracked, tt.busy, maxAge, now))
+		})
+	}
+}
+
+func TestParseRestoreTmpSweepInternal(t *testing.T) {
+	output := "/volumes/0/.restore_tmp_123\n/volumes/1/.restore_tmp.AbC\nfind: permission denied\n/volumes/9/.restore_tmp_1\n"
+	got := parseRestoreTmpSweepInternal(output, []string{"data", "cache"})
+	assert.Equal(t, []string{"data:/.restore_tmp_123", "cache:/.restore_tmp.AbC"}, got)
+}
@@ -0,0 +1,104 @@
+package services
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runRestoreSwapInternal runs restoreSwapScript against a volume directory
+// with a staged directory inside it. Moving an entry named "fail-me" fails.
+func runRestoreSwapInternal(t *testing.T, volumeFiles, stagedFiles []string) (root, output string, exitCode int) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skip("sh not available")
+	}
+
+	root = t.TempDir()
+	tmp := filepath.Join(root, ".restore_tmp.test")
+	require.NoError(t, os.Mkdir(tmp, 0o755))
+	for _, name := range volumeFiles {
+		require.NoError(t, os.WriteFile(filepath.Join(root, name), []byte("old "+name), 0o600))
+	}
+	for _, name := range stagedFiles {
+		require.NoError(t, os.WriteFile(filepath.Join(tmp, name), []byte("new "+name), 0o600))
+	}
+
+	bin := t.TempDir()
+	realMv, err := exec.LookPath("mv")
+	require.NoError(t, err)
+	shim := "#!/bin/sh\nfor a in \"$@\"; do case $a in */fail-me) exit 1 ;; esac; done\nexec " + realMv + " \"$@\"\n"
+	require.NoError(t, os.WriteFile(filepath.Join(bin, "mv"), []byte(shim), 0o700)) //nolint:gosec // test shim must be executable
+
+	cmd := exec.Command("sh", "-c", "set -e\nroot=$1\ntmp=$2\n"+restoreSwapScript, "sh", root, tmp)
+	cmd.Env = append(os.Environ(), "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	out, err := cmd.CombinedOutput()
+	if exitErr, ok := err.(*exec.ExitError); ok {
+		return root, string(out), exitErr.ExitCode()
+	}
+	require.NoError(t, err)
+	return root, string(out), 0
+}
+
+func volumeEntriesInternal(t *testing.T, root string) map[string]string {
+	t.Helper()
+	entries, err := os.ReadDir(root)
+	require.NoError(t, err)
+	got := map[string]string{}
+	for _, e := range entries {
+		if e.IsDir() {
+			got[e.Name()] = "dir"
+			continue
+		}
+		b, err := os.ReadFile(filepath.Join(root, e.Name()))
+		require.NoError(t, err)
+		got[e.Name()] = string(b)
+	}
+	return got
+}
+
+func TestRestoreSwapScript_Swaps(t *testing.T) {
+	root, output, code := runRestoreSwapInternal(t, []string{"a", ".hidden"}, []string{"b", ".config"})
+
+	assert.Equal(t, 0, code, output)
+	assert.Equal(t, "swapped", restoreSwapResultInternal(output))
+	assert.Equal(t, map[string]string{"b": "new b", ".config": "new .config"}, volumeEntriesInternal(t, root))
+}
+
+func TestRestoreSwapScript_RollsBack(t *testing.T) {
+	root, output, code := runRestoreSwapInternal(t, []string{"a", "c"}, []string{"b", "fail-me"})
+
+	assert.Equal(t, restoreExitRolledBack, code, output)
+	assert.Equal(t, "rolled_back", restoreSwapResultInternal(output))
+	got := volumeEntriesInternal(t, root)
+	assert.Equal(t, "old a", got["a"])
+	assert.Equal(t, "old c", got["c"])
+	assert.NotContains(t, got, "b")
+	for name := range got {
+		assert.False(t, strings.HasPrefix(name, ".restore_old."), name)
+	}
+}
+
+func TestRestoreSwapScript_RollsBackWhenMovingAsideFails(t *testing.T) {
+	root, output, code := runRestoreSwapInternal(t, []string{"a", "fail-me"}, []string{"b"})
+
+	// Entries that were never moved aside must survive the rollback.
+	assert.Equal(t, restoreExitRolledBack, code, output)
+	assert.Equal(t, map[string]string{"a": "old a", "fail-me": "old fail-me", ".restore_tmp.test": "dir"}, volumeEntriesInternal(t, root))
+}
+
+func TestRestoreExitErrorInternal(t *testing.T) {
+	require.NoError(t, restoreExitErrorInternal(0, "pre"))
+
+	err := restoreExitErrorInternal(1, "pre")
+	require.ErrorIs(t, err, ErrRestoreVolumeUnchanged)
+
+	err = restoreExitErrorInternal(restoreExitIncomplete, "pre")
+	require.ErrorIs(t, err, ErrRestoreIncomplete)
+	assert.Contains(t, err.Error(), "pre")
+}
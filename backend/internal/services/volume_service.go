@@ -2,20 +2,28 @@ package services
 
 import (
 	"archive/tar"
+	"archive/zip"
+	"bufio"
 	"bytes"
+	"cmp"
 	"compress/gzip"
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"log/slog"
 	"net/http"
 	"os"
 	"path"
+	"regexp"
+	"slices"
 	"strconv"
 	"strings"
 	"sync"
 	"time"
 
+	cerrdefs "github.com/containerd/errdefs"
 	"github.com/docker/docker/api/types/container"
 	"github.com/docker/docker/api/types/filters"
 	"github.com/docker/docker/api/types/mount"
@@ -25,11 +33,17 @@ import (
 	"github.com/getarcaneapp/arcane/backend/internal/database"
 	"github.com/getarcaneapp/arcane/backend/internal/models"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/docker"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/filepreview"
+	httputils "github.com/getarcaneapp/arcane/backend/internal/utils/http"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/pagination"
 	"github.com/getarcaneapp/arcane/backend/internal/utils/timeouts"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/volumesnapshot"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/ws"
 	"github.com/getarcaneapp/arcane/backend/pkg/libarcane"
+	"github.com/getarcaneapp/arcane/types/system"
 	volumetypes "github.com/getarcaneapp/arcane/types/volume"
 	"github.com/google/uuid"
+	"gorm.io/gorm"
 )
 
 type VolumeService struct {
@@ -39,28 +53,48 @@ type VolumeService struct {
 	settingsService  *SettingsService
 	containerService *ContainerService
 	imageService     *ImageService
+	operationService *OperationService
+	namespaceService *NamespaceService
 	backupVolumeName string
 	helperMu         sync.Mutex
-	helperByVolume   map[string]string
+	helperByVolume   map[string]*helperContainer
+	validatedHelper  string
+	progressOnce     sync.Once
+	progressHub      *ws.Hub
 }
 
-func NewVolumeService(db *database.DB, dockerService *DockerClientService, eventService *EventService, settingsService *SettingsService, containerService *ContainerService, imageService *ImageService, backupVolumeName string) *VolumeService {
+func NewVolumeService(db *database.DB, dockerService *DockerClientService, eventService *EventService, settingsService *SettingsService, containerService *ContainerService, imageService *ImageService, operationService *OperationService, namespaceService *NamespaceService, backupVolumeName string) *VolumeService {
 	slog.Debug("volume service: new")
 	if strings.TrimSpace(backupVolumeName) == "" {
 		backupVolumeName = "arcane-backups"
 	}
-	return &VolumeService{
+	s := &VolumeService{
 		db:               db,
 		dockerService:    dockerService,
 		eventService:     eventService,
 		settingsService:  settingsService,
 		containerService: containerService,
 		imageService:     imageService,
+		operationService: operationService,
+		namespaceService: namespaceService,
 		backupVolumeName: backupVolumeName,
-		helperByVolume:   make(map[string]string),
+		helperByVolume:   make(map[string]*helperContainer),
 	}
+	operationService.RegisterResumer(models.OperationKindVolumeRestore, s.resumeRestoreInternal)
+	return s
 }
 
+// helperContainer tracks a read-only helper kept running for a volume so
+// repeated browse and backup listing calls reuse it.
+type helperContainer struct {
+	id       string
+	inUse    int
+	lastUsed time.Time
+}
+
+// defaultHelperIdleTTL applies when no settings service is available.
+const defaultHelperIdleTTL = 10 * time.Minute
+
 func (s *VolumeService) GetVolumeByName(ctx context.Context, name string) (*volumetypes.Volume, error) {
 	slog.DebugContext(ctx, "volume service: get volume", "volume", name)
 	dockerClient, err := s.dockerService.GetClient()
@@ -108,6 +142,21 @@ func (s *VolumeService) CreateVolume(ctx context.Context, options volume.CreateO
 		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
 	}
 
+	namespace, err := s.namespaceService.ResolveNamespace(ctx, libarcane.NamespaceFromLabels(options.Labels))
+	if err == nil {
+		err = s.namespaceService.CheckVolumeQuota(ctx, namespace)
+	}
+	if err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeVolumeError, "volume", "", options.Name, user.ID, user.Username, "0", err, models.JSON{"action": "create", "driver": options.Driver, "step": "namespace"})
+		return nil, err
+	}
+	if namespace != "" {
+		if options.Labels == nil {
+			options.Labels = map[string]string{}
+		}
+		options.Labels[libarcane.NamespaceLabel] = namespace
+	}
+
 	created, err := dockerClient.VolumeCreate(ctx, options)
 	if err != nil {
 		s.eventService.LogErrorEvent(ctx, models.EventTypeVolumeError, "volume", "", options.Name, user.ID, user.Username, "0", err, models.JSON{"action": "create", "driver": options.Driver})
@@ -160,9 +209,42 @@ func (s *VolumeService) DeleteVolume(ctx context.Context, name string, force boo
 	return nil
 }
 
-func (s *VolumeService) PruneVolumes(ctx context.Context) (*volumetypes.PruneReport, error) {
+// PruneVolumes removes unused anonymous volumes and records a prune report
+// for user.
+func (s *VolumeService) PruneVolumes(ctx context.Context, user models.User) (*volumetypes.PruneReport, error) {
 	slog.DebugContext(ctx, "volume service: prune volumes")
-	return s.PruneVolumesWithOptions(ctx, false)
+
+	sizes, sizeErr := s.GetVolumeSizes(ctx)
+	if sizeErr != nil {
+		slog.WarnContext(ctx, "failed to read volume sizes before prune; sizes will be missing", "error", sizeErr)
+	}
+
+	report, err := s.PruneVolumesWithOptions(ctx, false)
+	if err != nil {
+		return nil, err
+	}
+
+	recordPruneReportInternal(ctx, s.db, &system.PruneAllResult{
+		Success:              true,
+		Items:                prunedVolumeItemsInternal(report.VolumesDeleted, sizes),
+		SpaceReclaimed:       report.SpaceReclaimed,
+		VolumeSpaceReclaimed: report.SpaceReclaimed,
+	}, models.PruneTriggerManual, user)
+	return report, nil
+}
+
+// prunedVolumeItemsInternal lists pruned volumes with the sizes measured
+// before the prune, when known.
+func prunedVolumeItemsInternal(names []string, sizes map[string]VolumeSizeData) []system.PrunedItem {
+	items := make([]system.PrunedItem, 0, len(names))
+	for _, name := range names {
+		item := system.PrunedItem{Type: system.PrunedItemVolume, ID: name, Name: name}
+		if size, ok := sizes[name]; ok && size.Size > 0 {
+			item.Size = size.Size
+		}
+		items = append(items, item)
+	}
+	return items
 }
 
 func (s *VolumeService) PruneVolumesWithOptions(ctx context.Context, all bool) (*volumetypes.PruneReport, error) {
@@ -213,272 +295,1389 @@ func (s *VolumeService) PruneVolumesWithOptions(ctx context.Context, all bool) (
 	}, nil
 }
 
-// --- Volume Browsing & Backup ---
-
-func (s *VolumeService) ListDirectory(ctx context.Context, volumeName, dirPath string) ([]volumetypes.FileEntry, error) {
-	slog.DebugContext(ctx, "volume service: list directory", "volume", volumeName, "path", dirPath)
-
-	sanitizedPath, err := s.sanitizeBrowsePathInternal(dirPath)
-	if err != nil {
-		return nil, fmt.Errorf("invalid path: %w", err)
-	}
-
-	containerID, cleanup, err := s.createTempContainerInternal(ctx, volumeName, true)
-	if err != nil {
-		return nil, err
-	}
-	defer cleanup()
+// BulkVolumeOperation applies a single action to multiple volumes and reports
+// the outcome for each one. A failure on one volume does not stop the others.
+func (s *VolumeService) BulkVolumeOperation(ctx context.Context, req volumetypes.BulkRequest, user models.User) (*volumetypes.BulkResult, error) {
+	slog.DebugContext(ctx, "volume service: bulk volume operation", "action", req.Action, "count", len(req.Names), "user", user.ID)
 
-	targetPath := path.Join("/volume", sanitizedPath)
-	quotedPath := strconv.Quote(targetPath)
-	cmd := []string{"sh", "-c", fmt.Sprintf("find %s -mindepth 1 -maxdepth 1 -exec sh -c 'for f; do out=$(stat -c \"%%s %%Y %%f %%A\" -- \"$f\" 2>/dev/null) || continue; printf \"%%s\\0%%s\\0\" \"$f\" \"$out\"; done' sh {} + || true", quotedPath)}
-	stdout, _, err := s.execInContainerInternal(ctx, containerID, cmd)
-	if err != nil {
-		return nil, fmt.Errorf("failed to list directory: %w", err)
+	switch req.Action {
+	case volumetypes.BulkActionDelete, volumetypes.BulkActionBackup:
+	case volumetypes.BulkActionLabel:
+		if len(req.Labels) == 0 && len(req.RemoveLabels) == 0 {
+			return nil, fmt.Errorf("label action requires labels or removeLabels")
+		}
+	default:
+		return nil, fmt.Errorf("unsupported bulk action: %q", req.Action)
 	}
 
-	lines := strings.Split(stdout, "\x00")
-	entries := make([]volumetypes.FileEntry, 0)
-	for i := 0; i+1 < len(lines); i += 2 {
-		fullPath := lines[i]
-		meta := strings.Fields(strings.TrimSpace(lines[i+1]))
-		if fullPath == "" || len(meta) < 4 {
+	names := make([]string, 0, len(req.Names))
+	seen := make(map[string]struct{}, len(req.Names))
+	for _, name := range req.Names {
+		name = strings.TrimSpace(name)
+		if name == "" {
 			continue
 		}
-		name := path.Base(fullPath)
-		size, _ := strconv.ParseInt(meta[0], 10, 64)
-		modTimeSec, _ := strconv.ParseInt(meta[1], 10, 64)
-		mode := meta[3]
-
-		isDir := strings.HasPrefix(mode, "d")
-		isSymlink := strings.HasPrefix(mode, "l")
-
-		relPath := strings.TrimPrefix(fullPath, "/volume")
-		if relPath == "" {
-			relPath = "/"
+		if _, ok := seen[name]; ok {
+			continue
 		}
+		seen[name] = struct{}{}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return nil, fmt.Errorf("no volume names provided")
+	}
 
-		entry := volumetypes.FileEntry{
-			Name:        name,
-			Path:        relPath,
-			IsDirectory: isDir,
-			Size:        size,
-			ModTime:     time.Unix(modTimeSec, 0),
-			Mode:        mode,
-			IsSymlink:   isSymlink,
-		}
+	result := &volumetypes.BulkResult{
+		Action:  req.Action,
+		Results: make([]volumetypes.BulkItemResult, 0, len(names)),
+	}
 
-		if isSymlink {
-			// Use readlink without -f to get the raw symlink target (not resolved)
-			// This prevents exposing paths outside the volume
-			target, _, _ := s.execInContainerInternal(ctx, containerID, []string{"readlink", fullPath})
-			target = strings.TrimSpace(target)
-			if target != "" {
-				// If target is relative, it's safe to show
-				// If target is absolute and within /volume, strip the /volume prefix
-				// If target points outside /volume, indicate it's external
-				switch {
-				case strings.HasPrefix(target, "/volume/"):
-					entry.LinkTarget = strings.TrimPrefix(target, "/volume")
-				case strings.HasPrefix(target, "/volume"):
-					entry.LinkTarget = "/"
-				case !strings.HasPrefix(target, "/"):
-					// Relative path - safe to show as-is
-					entry.LinkTarget = target
-				default:
-					// Absolute path outside /volume - indicate it's external
-					entry.LinkTarget = "(external)"
+	for _, name := range names {
+		item := volumetypes.BulkItemResult{Name: name}
+
+		opErr := s.namespaceService.AuthorizeResource(ctx, NamespaceResourceVolume, name)
+		if opErr == nil {
+			switch req.Action {
+			case volumetypes.BulkActionDelete:
+				opErr = s.DeleteVolume(ctx, name, req.Force, user)
+			case volumetypes.BulkActionBackup:
+				var backup *models.VolumeBackup
+				backup, opErr = s.CreateBackup(ctx, name, user)
+				if opErr == nil {
+					item.BackupID = backup.ID
 				}
+			case volumetypes.BulkActionLabel:
+				opErr = s.UpdateVolumeLabels(ctx, name, req.Labels, req.RemoveLabels, user)
 			}
 		}
 
-		entries = append(entries, entry)
+		if opErr != nil {
+			item.Error = opErr.Error()
+			result.Failed++
+		} else {
+			item.Success = true
+			result.Succeeded++
+		}
+		result.Results = append(result.Results, item)
 	}
 
-	return entries, nil
-}
+	docker.InvalidateVolumeUsageCache()
 
-func (s *VolumeService) GetFileContent(ctx context.Context, volumeName, filePath string, maxBytes int64) ([]byte, string, error) {
-	slog.DebugContext(ctx, "volume service: get file content", "volume", volumeName, "path", filePath, "max_bytes", maxBytes)
+	return result, nil
+}
 
-	sanitizedPath, err := s.sanitizeBrowsePathInternal(filePath)
+// UpdateVolumeLabels changes the labels of a volume. Docker does not support
+// updating volume labels in place, so the volume is recreated with the new
+// labels and its data is copied over through a temporary volume. The volume
+// must not be in use by any container.
+func (s *VolumeService) UpdateVolumeLabels(ctx context.Context, name string, set map[string]string, remove []string, user models.User) error {
+	slog.DebugContext(ctx, "volume service: update volume labels", "volume", name, "set", len(set), "remove", len(remove), "user", user.ID)
+	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
-		return nil, "", fmt.Errorf("invalid path: %w", err)
+		return fmt.Errorf("failed to connect to Docker: %w", err)
 	}
 
-	containerID, cleanup, err := s.createTempContainerInternal(ctx, volumeName, true)
+	vol, err := dockerClient.VolumeInspect(ctx, name)
 	if err != nil {
-		return nil, "", err
+		return fmt.Errorf("volume not found: %w", err)
 	}
-	defer cleanup()
 
-	targetPath := path.Join("/volume", sanitizedPath)
-	cmd := []string{"head", "-c", strconv.FormatInt(maxBytes, 10), targetPath}
-	stdout, _, err := s.execInContainerInternal(ctx, containerID, cmd)
+	inUse, _, err := s.GetVolumeUsage(ctx, name)
 	if err != nil {
-		return nil, "", fmt.Errorf("failed to read file: %w", err)
+		return fmt.Errorf("failed to check volume usage: %w", err)
+	}
+	if inUse {
+		return fmt.Errorf("volume %s is in use; stop and remove its containers before changing labels", name)
 	}
 
-	content := []byte(stdout)
-	mimeType := http.DetectContentType(content)
-
-	return content, mimeType, nil
-}
+	labels := make(map[string]string, len(vol.Labels)+len(set))
+	for k, v := range vol.Labels {
+		labels[k] = v
+	}
+	for k, v := range set {
+		labels[k] = v
+	}
+	for _, k := range remove {
+		delete(labels, k)
+	}
 
-func (s *VolumeService) DownloadFile(ctx context.Context, volumeName, filePath string) (io.ReadCloser, int64, error) {
-	slog.DebugContext(ctx, "volume service: download file", "volume", volumeName, "path", filePath)
+	if err := s.recreateVolumeInternal(ctx, name, "relabel", volume.CreateOptions{
+		Name:       name,
+		Driver:     vol.Driver,
+		DriverOpts: vol.Options,
+		Labels:     labels,
+	}); err != nil {
+		return err
+	}
 
-	sanitizedPath, err := s.sanitizeBrowsePathInternal(filePath)
-	if err != nil {
-		return nil, 0, fmt.Errorf("invalid path: %w", err)
+	metadata := models.JSON{
+		"action": "label",
+		"name":   name,
+		"labels": labels,
+	}
+	if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeUpdate, name, name, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log volume label update", "volume", name, "error", logErr.Error())
 	}
 
+	return nil
+}
+
+// MigrateVolume moves a volume's data to a volume with a different name
+// and/or driver. Containers using the source volume must be stopped. When
+// RepointContainers is set they are recreated with the target volume mounted
+// in place of the source; compose projects should also be updated so the next
+// deploy uses the new volume.
+func (s *VolumeService) MigrateVolume(ctx context.Context, sourceName string, req volumetypes.Migrate, user models.User) (*volumetypes.MigrateResult, error) {
+	slog.DebugContext(ctx, "volume service: migrate volume", "source", sourceName, "target", req.Name, "driver", req.Driver, "user", user.ID)
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
-		return nil, 0, err
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
 	}
 
-	containerID, cleanup, err := s.createTempContainerInternal(ctx, volumeName, true)
+	src, err := dockerClient.VolumeInspect(ctx, sourceName)
 	if err != nil {
-		return nil, 0, err
+		return nil, fmt.Errorf("volume not found: %w", err)
 	}
 
-	targetPath := path.Join("/volume", sanitizedPath)
-	reader, _, err := dockerClient.CopyFromContainer(ctx, containerID, targetPath)
-	if err != nil {
-		cleanup()
-		return nil, 0, fmt.Errorf("failed to download: %w", err)
+	targetName := req.Name
+	if targetName == "" {
+		targetName = sourceName
+	}
+	options := volume.CreateOptions{
+		Name:       targetName,
+		Driver:     req.Driver,
+		DriverOpts: req.DriverOpts,
+		Labels:     src.Labels,
+	}
+	if options.Driver == "" {
+		options.Driver = src.Driver
+	}
+	if options.DriverOpts == nil && options.Driver == src.Driver {
+		options.DriverOpts = src.Options
+	}
+	inPlace := targetName == sourceName
+	if inPlace && options.Driver == src.Driver && req.DriverOpts == nil {
+		return nil, fmt.Errorf("nothing to migrate: specify a new name, driver or driver options")
 	}
 
-	tr := tar.NewReader(reader)
-	hdr, err := tr.Next()
+	users, err := dockerClient.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("volume", sourceName)),
+	})
 	if err != nil {
-		reader.Close()
-		cleanup()
-		return nil, 0, fmt.Errorf("failed to read tar stream: %w", err)
+		return nil, fmt.Errorf("failed to list containers using volume: %w", err)
 	}
-	if hdr.FileInfo().IsDir() {
-		reader.Close()
-		cleanup()
-		return nil, 0, fmt.Errorf("path is a directory")
+	for _, c := range users {
+		if c.State == "running" || c.State == "restarting" {
+			return nil, fmt.Errorf("volume %s is used by running container %s; stop it before migrating", sourceName, containerDisplayName(c.Names, c.ID))
+		}
 	}
-	size := hdr.Size
 
-	return &cleanupReadCloser{
-		Reader:  tr,
-		Closer:  reader,
-		cleanup: cleanup,
-	}, size, nil
-}
+	result := &volumetypes.MigrateResult{RepointedContainers: []string{}}
 
-func (s *VolumeService) getHelperImageInternal(ctx context.Context) (string, error) {
-	slog.DebugContext(ctx, "volume service: resolve helper image")
-	dockerClient, err := s.dockerService.GetClient()
-	if err != nil {
-		return "", fmt.Errorf("failed to get docker client: %w", err)
-	}
+	if inPlace {
+		if len(users) > 0 {
+			return nil, fmt.Errorf("volume %s is referenced by %d container(s); remove them or migrate to a new name", sourceName, len(users))
+		}
+		if err := s.recreateVolumeInternal(ctx, sourceName, "migrate", options); err != nil {
+			s.eventService.LogErrorEvent(ctx, models.EventTypeVolumeError, "volume", sourceName, sourceName, user.ID, user.Username, "0", err, models.JSON{"action": "migrate", "driver": options.Driver})
+			return nil, err
+		}
+	} else {
+		if _, err := dockerClient.VolumeInspect(ctx, targetName); err == nil {
+			return nil, fmt.Errorf("volume %s already exists", targetName)
+		}
+		if _, err := dockerClient.VolumeCreate(ctx, options); err != nil {
+			s.eventService.LogErrorEvent(ctx, models.EventTypeVolumeError, "volume", targetName, targetName, user.ID, user.Username, "0", err, models.JSON{"action": "migrate", "source": sourceName})
+			return nil, fmt.Errorf("failed to create target volume: %w", err)
+		}
+		if err := s.copyVolumeDataInternal(ctx, sourceName, targetName); err != nil {
+			if rmErr := dockerClient.VolumeRemove(context.WithoutCancel(ctx), targetName, true); rmErr != nil {
+				slog.WarnContext(ctx, "failed to remove incomplete migration target", "volume", targetName, "error", rmErr.Error())
+			}
+			s.eventService.LogErrorEvent(ctx, models.EventTypeVolumeError, "volume", sourceName, sourceName, user.ID, user.Username, "0", err, models.JSON{"action": "migrate", "target": targetName})
+			return nil, fmt.Errorf("failed to copy volume data: %w", err)
+		}
 
-	// 1. Try to find Arcane container's image (the most prune-proof)
-	// Check hostname first (ID of container if in Docker)
-	hostname, _ := os.Hostname()
-	if hostname != "" {
-		if inspect, err := dockerClient.ContainerInspect(ctx, hostname); err == nil {
-			return inspect.Config.Image, nil
+		if req.RepointContainers {
+			for _, c := range users {
+				name := containerDisplayName(c.Names, c.ID)
+				if err := s.repointContainerVolumeInternal(ctx, c.ID, sourceName, targetName); err != nil {
+					return nil, fmt.Errorf("data copied to %s, but failed to repoint container %s: %w", targetName, name, err)
+				}
+				result.RepointedContainers = append(result.RepointedContainers, name)
+			}
 		}
-	}
 
-	// 2. Search for any container with Arcane label
-	filter := filters.NewArgs()
-	filter.Add("label", "com.getarcaneapp.arcane=true")
-	if containers, err := dockerClient.ContainerList(ctx, container.ListOptions{Filters: filter, All: true}); err == nil && len(containers) > 0 {
-		return containers[0].Image, nil
+		if req.DeleteSource {
+			s.removeHelperEntry(sourceName)
+			if err := dockerClient.VolumeRemove(ctx, sourceName, false); err != nil {
+				return nil, fmt.Errorf("data copied to %s, but failed to delete source volume: %w", targetName, err)
+			}
+			result.SourceDeleted = true
+		}
 	}
 
-	// 3. Try busybox:stable-musl
-	const helperImage = "busybox:stable-musl"
-	if _, err := dockerClient.ImageInspect(ctx, helperImage); err == nil {
-		return helperImage, nil
+	vol, err := dockerClient.VolumeInspect(ctx, targetName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect migrated volume: %w", err)
 	}
+	result.Volume = volumetypes.NewSummary(vol)
 
-	// 4. Default to pulling busybox
-	slog.InfoContext(ctx, "no suitable internal image found, pulling busybox:stable-musl")
-	if s.imageService == nil {
-		return "", fmt.Errorf("helper image %s missing and image service unavailable", helperImage)
+	metadata := models.JSON{
+		"action":        "migrate",
+		"source":        sourceName,
+		"name":          targetName,
+		"driver":        vol.Driver,
+		"repointed":     result.RepointedContainers,
+		"sourceDeleted": result.SourceDeleted,
 	}
-	if err := s.imageService.PullImage(ctx, helperImage, io.Discard, systemUser, nil); err != nil {
-		return "", fmt.Errorf("failed to pull helper image %s: %w", helperImage, err)
+	if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeUpdate, vol.Name, vol.Name, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log volume migration", "volume", vol.Name, "error", logErr.Error())
 	}
 
-	return helperImage, nil
+	docker.InvalidateVolumeUsageCache()
+	return result, nil
 }
 
-func (s *VolumeService) BackupMountWarning(ctx context.Context) string {
+// repointContainerVolumeInternal recreates a stopped container with every
+// mount of oldVolume replaced by newVolume. The container keeps its name,
+// configuration and network attachments. Like RecreateContainer, the old
+// container is renamed aside and only removed once its replacement exists, so
+// a failed create leaves it in place under its original name.
+func (s *VolumeService) repointContainerVolumeInternal(ctx context.Context, containerID, oldVolume, newVolume string) error {
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
-		return ""
-	}
-
-	containerID := s.getArcaneContainerIDInternal(ctx, dockerClient)
-	if containerID == "" {
-		return ""
+		return err
 	}
 
 	inspect, err := dockerClient.ContainerInspect(ctx, containerID)
 	if err != nil {
-		return ""
+		return fmt.Errorf("failed to inspect container: %w", err)
+	}
+	if inspect.State != nil && inspect.State.Running {
+		return fmt.Errorf("container is running")
+	}
+	if inspect.Config == nil || inspect.HostConfig == nil {
+		return fmt.Errorf("failed to inspect container: incomplete configuration for %s", containerID)
 	}
 
-	hasBackups := false
-	hasRestores := false
-	for _, mount := range inspect.Mounts {
-		if mount.Destination == "/backups" {
-			hasBackups = true
+	config, hostConfig := cloneContainerConfigInternal(inspect)
+	hostConfig.Binds = slices.Clone(hostConfig.Binds)
+	for i, bind := range hostConfig.Binds {
+		if src, rest, ok := strings.Cut(bind, ":"); ok && src == oldVolume {
+			hostConfig.Binds[i] = newVolume + ":" + rest
 		}
-		if mount.Destination == "/restores" {
-			hasRestores = true
+	}
+	for i, m := range hostConfig.Mounts {
+		if m.Type == mount.TypeVolume && m.Source == oldVolume {
+			hostConfig.Mounts[i].Source = newVolume
 		}
 	}
+	networkingConfig := recreateNetworkingConfigInternal(inspect, hostConfig)
 
-	if hasBackups || hasRestores {
-		return ""
+	name := strings.TrimPrefix(inspect.Name, "/")
+	asideName := fmt.Sprintf("%s_arcane_old_%d", name, time.Now().Unix())
+	if err := dockerClient.ContainerRename(ctx, inspect.ID, asideName); err != nil {
+		return fmt.Errorf("failed to rename container aside: %w", err)
 	}
 
-	return "No volume is mounted at /backups or /restores in the Arcane container. Backups/restores will only live inside Docker unless you mount a host path."
-}
-
-func (s *VolumeService) getArcaneContainerIDInternal(ctx context.Context, dockerClient *client.Client) string {
-	hostname, _ := os.Hostname()
-	if hostname != "" {
-		if inspect, err := dockerClient.ContainerInspect(ctx, hostname); err == nil {
-			return inspect.ID
+	if _, err := dockerClient.ContainerCreate(ctx, config, hostConfig, networkingConfig, nil, name); err != nil {
+		// Roll back even if the request was canceled midway.
+		if renameErr := dockerClient.ContainerRename(context.WithoutCancel(ctx), inspect.ID, name); renameErr != nil {
+			slog.ErrorContext(ctx, "failed to restore container name after failed repoint", "container", inspect.ID, "name", name, "error", renameErr.Error())
 		}
+		return fmt.Errorf("failed to recreate container (previous container restored): %w", err)
 	}
 
-	filter := filters.NewArgs()
-	filter.Add("label", "com.getarcaneapp.arcane=true")
-	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{Filters: filter, All: true})
-	if err != nil || len(containers) == 0 {
-		return ""
+	if err := dockerClient.ContainerRemove(ctx, inspect.ID, container.RemoveOptions{}); err != nil {
+		slog.WarnContext(ctx, "failed to remove repointed container", "container", asideName, "error", err.Error())
 	}
+	return nil
+}
 
-	for _, c := range containers {
-		if strings.EqualFold(strings.TrimSpace(c.State), "running") {
-			return c.ID
-		}
+func containerDisplayName(names []string, id string) string {
+	if len(names) > 0 {
+		return strings.TrimPrefix(names[0], "/")
 	}
-
-	return containers[0].ID
+	if len(id) > 12 {
+		return id[:12]
+	}
+	return id
 }
 
-type cleanupReadCloser struct {
-	io.Reader
-	io.Closer
-	cleanup func()
-}
+// recreateVolumeInternal recreates a volume with new create options while
+// keeping its data, by copying the data out to a temporary volume and back.
+// The volume must not be referenced by any container. If recreating fails
+// after the original was removed, the temporary volume is kept so no data is
+// lost.
+func (s *VolumeService) recreateVolumeInternal(ctx context.Context, name, purpose string, options volume.CreateOptions) error {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to connect to Docker: %w", err)
+	}
 
-func (c *cleanupReadCloser) Close() error {
-	err := c.Closer.Close()
-	c.cleanup()
+	vol, err := dockerClient.VolumeInspect(ctx, name)
+	if err != nil {
+		return fmt.Errorf("volume not found: %w", err)
+	}
+
+	tempName := fmt.Sprintf("%s-%s-%s", name, purpose, uuid.NewString()[:8])
+	if _, err := dockerClient.VolumeCreate(ctx, volume.CreateOptions{
+		Name:       tempName,
+		Driver:     vol.Driver,
+		DriverOpts: vol.Options,
+	}); err != nil {
+		return fmt.Errorf("failed to create temporary volume: %w", err)
+	}
+	removeTemp := true
+	defer func() {
+		if !removeTemp {
+			return
+		}
+		if rmErr := dockerClient.VolumeRemove(context.WithoutCancel(ctx), tempName, true); rmErr != nil {
+			slog.WarnContext(ctx, "failed to remove temporary volume", "volume", tempName, "error", rmErr.Error())
+		}
+	}()
+
+	if err := s.copyVolumeDataInternal(ctx, name, tempName); err != nil {
+		return fmt.Errorf("failed to copy volume data: %w", err)
+	}
+
+	s.removeHelperEntry(name)
+	if err := dockerClient.VolumeRemove(ctx, name, false); err != nil {
+		return fmt.Errorf("failed to remove original volume: %w", err)
+	}
+
+	options.Name = name
+	if _, err := dockerClient.VolumeCreate(ctx, options); err != nil {
+		// Keep the temporary volume so the data is not lost.
+		removeTemp = false
+		return fmt.Errorf("failed to recreate volume (data preserved in %s): %w", tempName, err)
+	}
+
+	if err := s.copyVolumeDataInternal(ctx, tempName, name); err != nil {
+		removeTemp = false
+		return fmt.Errorf("failed to restore volume data (data preserved in %s): %w", tempName, err)
+	}
+
+	docker.InvalidateVolumeUsageCache()
+	return nil
+}
+
+// CloneVolume creates a new volume and copies the contents of the source
+// volume into it. The source is mounted read-only; clone volumes that are in
+// active use with care, as files may change during the copy.
+func (s *VolumeService) CloneVolume(ctx context.Context, sourceName string, req volumetypes.Clone, user models.User) (*volumetypes.Volume, error) {
+	slog.DebugContext(ctx, "volume service: clone volume", "source", sourceName, "target", req.Name, "user", user.ID)
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	src, err := dockerClient.VolumeInspect(ctx, sourceName)
+	if err != nil {
+		return nil, fmt.Errorf("volume not found: %w", err)
+	}
+	if req.Name == sourceName {
+		return nil, fmt.Errorf("target volume must differ from the source volume")
+	}
+	if _, err := dockerClient.VolumeInspect(ctx, req.Name); err == nil {
+		return nil, fmt.Errorf("volume %s already exists", req.Name)
+	}
+
+	options := volume.CreateOptions{Name: req.Name}
+	if req.PreserveDriverOptions {
+		options.Driver = src.Driver
+		options.DriverOpts = src.Options
+	}
+	labels := map[string]string{}
+	if req.PreserveLabels {
+		for k, v := range src.Labels {
+			labels[k] = v
+		}
+	}
+	for k, v := range req.Labels {
+		labels[k] = v
+	}
+	if len(labels) > 0 {
+		options.Labels = labels
+	}
+
+	if _, err := dockerClient.VolumeCreate(ctx, options); err != nil {
+		s.eventService.LogErrorEvent(ctx, models.EventTypeVolumeError, "volume", req.Name, req.Name, user.ID, user.Username, "0", err, models.JSON{"action": "clone", "source": sourceName})
+		return nil, fmt.Errorf("failed to create volume: %w", err)
+	}
+
+	if err := s.copyVolumeDataInternal(ctx, sourceName, req.Name); err != nil {
+		if rmErr := dockerClient.VolumeRemove(context.WithoutCancel(ctx), req.Name, true); rmErr != nil {
+			slog.WarnContext(ctx, "failed to remove incomplete volume clone", "volume", req.Name, "error", rmErr.Error())
+		}
+		s.eventService.LogErrorEvent(ctx, models.EventTypeVolumeError, "volume", req.Name, req.Name, user.ID, user.Username, "0", err, models.JSON{"action": "clone", "source": sourceName})
+		return nil, fmt.Errorf("failed to copy volume data: %w", err)
+	}
+
+	vol, err := dockerClient.VolumeInspect(ctx, req.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect cloned volume: %w", err)
+	}
+
+	metadata := models.JSON{
+		"action": "clone",
+		"name":   vol.Name,
+		"source": sourceName,
+		"driver": vol.Driver,
+	}
+	if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeCreate, vol.Name, vol.Name, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log volume clone action", "volume", vol.Name, "error", logErr.Error())
+	}
+
+	docker.InvalidateVolumeUsageCache()
+
+	dtoVol := volumetypes.NewSummary(vol)
+	return &dtoVol, nil
+}
+
+// copyVolumeScript pipes a tar stream from $1 into $2. pipefail makes a read
+// error on the creating side fail the copy, and the entry count check catches
+// anything else that leaves the destination short, since callers delete the
+// source after a successful copy.
+const copyVolumeScript = `set -e -o pipefail
+tar -cf - -C "$1" . | tar -xf - -C "$2"
+src=$(find "$1" | wc -l)
+dst=$(find "$2" | wc -l)
+if [ "$dst" -lt "$src" ]; then
+  echo "copied $dst of $src entries" >&2
+  exit 4
+fi`
+
+// copyVolumeDataInternal copies the full contents of one volume into another
+// by piping a tar stream inside a one-shot helper container, which preserves
+// ownership, permissions and symlinks.
+func (s *VolumeService) copyVolumeDataInternal(ctx context.Context, srcVolume, dstVolume string) error {
+	return s.runHelperContainerInternal(ctx, []string{
+		fmt.Sprintf("%s:/src:ro", srcVolume),
+		fmt.Sprintf("%s:/dst", dstVolume),
+	}, copyVolumeScript, "/src", "/dst")
+}
+
+// checkHelperVolumesInternal makes sure every name refers to an existing
+// Docker volume before it is used in a helper bind. Without it a name such as
+// "/etc" would bind-mount that host path into the helper.
+func (s *VolumeService) checkHelperVolumesInternal(ctx context.Context, names ...string) error {
+	for _, name := range names {
+		if name == "" || strings.ContainsAny(name, "/\\:") {
+			return fmt.Errorf("invalid volume name: %q", name)
+		}
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return err
+	}
+	for _, name := range names {
+		if _, err := dockerClient.VolumeInspect(ctx, name); err != nil {
+			return fmt.Errorf("volume not found: %w", err)
+		}
+	}
+	return nil
+}
+
+// helperExitError is returned when a one-shot helper container exits non-zero.
+type helperExitError struct {
+	StatusCode int64
+}
+
+func (e *helperExitError) Error() string {
+	return fmt.Sprintf("helper container exited with status %d", e.StatusCode)
+}
+
+// runHelperContainerInternal runs a shell script in a one-shot helper container
+// with the given binds and waits for it to exit successfully. Extra args are
+// passed to the script as positional parameters.
+func (s *VolumeService) runHelperContainerInternal(ctx context.Context, binds []string, script string, args ...string) error {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return err
+	}
+
+	helperImage, err := s.getHelperImageInternal(ctx)
+	if err != nil {
+		return err
+	}
+
+	config := &container.Config{
+		Image: helperImage,
+		Cmd:   append([]string{"sh", "-c", script, "sh"}, args...),
+		Labels: map[string]string{
+			libarcane.InternalContainerLabel: "true",
+		},
+		NetworkDisabled: true,
+	}
+
+	hostConfig := &container.HostConfig{
+		Binds:      binds,
+		AutoRemove: true,
+		Resources:  s.helperResourcesInternal(),
+	}
+
+	resp, err := dockerClient.ContainerCreate(ctx, config, hostConfig, nil, nil, "")
+	if err != nil {
+		return fmt.Errorf("failed to create helper container: %w", err)
+	}
+
+	if err := dockerClient.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return fmt.Errorf("failed to start helper container: %w", err)
+	}
+
+	statusCh, errCh := dockerClient.ContainerWait(ctx, resp.ID, container.WaitConditionNotRunning)
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return err
+		}
+	case status := <-statusCh:
+		if status.StatusCode != 0 {
+			return &helperExitError{StatusCode: status.StatusCode}
+		}
+	}
+
+	return nil
+}
+
+// --- Volume Browsing & Backup ---
+
+func (s *VolumeService) ListDirectory(ctx context.Context, volumeName, dirPath string) ([]volumetypes.FileEntry, error) {
+	slog.DebugContext(ctx, "volume service: list directory", "volume", volumeName, "path", dirPath)
+
+	sanitizedPath, err := s.sanitizeBrowsePathInternal(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path: %w", err)
+	}
+
+	containerID, cleanup, err := s.createTempContainerInternal(ctx, volumeName, true)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	targetPath := path.Join("/volume", sanitizedPath)
+	quotedPath := strconv.Quote(targetPath)
+	cmd := []string{"sh", "-c", fmt.Sprintf("find %s -mindepth 1 -maxdepth 1 -exec sh -c 'for f; do out=$(stat -c \"%%s %%Y %%f %%A\" -- \"$f\" 2>/dev/null) || continue; printf \"%%s\\0%%s\\0\" \"$f\" \"$out\"; done' sh {} + || true", quotedPath)}
+	stdout, _, err := s.execInContainerInternal(ctx, containerID, helperExecScan, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list directory: %w", err)
+	}
+
+	lines := strings.Split(stdout, "\x00")
+	entries := make([]volumetypes.FileEntry, 0)
+	for i := 0; i+1 < len(lines); i += 2 {
+		fullPath := lines[i]
+		meta := strings.Fields(strings.TrimSpace(lines[i+1]))
+		if fullPath == "" || len(meta) < 4 {
+			continue
+		}
+		name := path.Base(fullPath)
+		size, _ := strconv.ParseInt(meta[0], 10, 64)
+		modTimeSec, _ := strconv.ParseInt(meta[1], 10, 64)
+		mode := meta[3]
+
+		isDir := strings.HasPrefix(mode, "d")
+		isSymlink := strings.HasPrefix(mode, "l")
+
+		relPath := strings.TrimPrefix(fullPath, "/volume")
+		if relPath == "" {
+			relPath = "/"
+		}
+
+		entry := volumetypes.FileEntry{
+			Name:        name,
+			Path:        relPath,
+			IsDirectory: isDir,
+			Size:        size,
+			ModTime:     time.Unix(modTimeSec, 0),
+			Mode:        mode,
+			IsSymlink:   isSymlink,
+		}
+
+		if isSymlink {
+			// Use readlink without -f to get the raw symlink target (not resolved)
+			// This prevents exposing paths outside the volume
+			target := s.readLinkInternal(ctx, containerID, fullPath)
+			if target != "" {
+				// If target is relative, it's safe to show
+				// If target is absolute and within /volume, strip the /volume prefix
+				// If target points outside /volume, indicate it's external
+				switch {
+				case strings.HasPrefix(target, "/volume/"):
+					entry.LinkTarget = strings.TrimPrefix(target, "/volume")
+				case strings.HasPrefix(target, "/volume"):
+					entry.LinkTarget = "/"
+				case !strings.HasPrefix(target, "/"):
+					// Relative path - safe to show as-is
+					entry.LinkTarget = target
+				default:
+					// Absolute path outside /volume - indicate it's external
+					entry.LinkTarget = "(external)"
+				}
+			}
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+// maxFileContentBytes caps a single GetFileContent read.
+const maxFileContentBytes = 10 * 1024 * 1024
+
+// fileContentScript prints the file size on the first line followed by the
+// requested bytes: the last $2 bytes in tail mode, otherwise $2 bytes starting
+// at offset $3.
+const fileContentScript = `set -e
+stat -c %s "$1"
+if [ "$4" = tail ]; then
+	tail -c "$2" "$1"
+elif [ "$3" -eq 0 ]; then
+	head -c "$2" "$1"
+else
+	tail -c +"$(($3 + 1))" "$1" | head -c "$2"
+fi`
+
+func (s *VolumeService) GetFileContent(ctx context.Context, volumeName, filePath string, opts volumetypes.FileContentOptions) (*volumetypes.FileContent, error) {
+	slog.DebugContext(ctx, "volume service: get file content", "volume", volumeName, "path", filePath, "max_bytes", opts.MaxBytes, "offset", opts.Offset, "tail", opts.Tail)
+
+	if opts.Offset < 0 {
+		return nil, fmt.Errorf("offset must not be negative")
+	}
+	if opts.MaxBytes <= 0 || opts.MaxBytes > maxFileContentBytes {
+		opts.MaxBytes = maxFileContentBytes
+	}
+	mode := "range"
+	if opts.Tail {
+		mode = "tail"
+		opts.Offset = 0
+	}
+
+	sanitizedPath, err := s.sanitizeBrowsePathInternal(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path: %w", err)
+	}
+
+	containerID, cleanup, err := s.createTempContainerInternal(ctx, volumeName, true)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	targetPath := path.Join("/volume", sanitizedPath)
+	cmd := []string{"sh", "-c", fileContentScript, "sh", targetPath, strconv.FormatInt(opts.MaxBytes, 10), strconv.FormatInt(opts.Offset, 10), mode}
+	stdout, stderr, err := s.execInContainerInternal(ctx, containerID, helperExecFile, cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+
+	sizeLine, rest, found := strings.Cut(stdout, "\n")
+	size, parseErr := strconv.ParseInt(strings.TrimSpace(sizeLine), 10, 64)
+	if !found || parseErr != nil {
+		return nil, fmt.Errorf("failed to read file: %s", strings.TrimSpace(stderr))
+	}
+
+	offset := opts.Offset
+	if opts.Tail {
+		offset = max(size-int64(len(rest)), 0)
+	}
+
+	content := []byte(rest)
+	result := &volumetypes.FileContent{
+		Content:   content,
+		MimeType:  http.DetectContentType(content),
+		IsBinary:  filepreview.IsBinary(content),
+		Size:      size,
+		Offset:    offset,
+		Truncated: offset > 0 || offset+int64(len(content)) < size,
+	}
+	if offset == 0 {
+		result.Viewer = filepreview.DetectViewer(sanitizedPath, content)
+	}
+	if !result.IsBinary {
+		result.Language = filepreview.DetectLanguage(sanitizedPath)
+		result.LineCount = filepreview.CountLines(content)
+	}
+
+	return result, nil
+}
+
+// maxPreviewFileSize caps the size of files copied out of a volume for
+// server-side previews.
+const maxPreviewFileSize = 256 * 1024 * 1024
+
+// ErrPreviewUnsupported is returned when a file has no server-side viewer.
+var ErrPreviewUnsupported = errors.New("no preview available for this file type")
+
+// PreviewFile renders a listing of a SQLite database or tar/zip archive
+// stored in a volume. The file is copied to a temporary location and opened
+// read-only.
+func (s *VolumeService) PreviewFile(ctx context.Context, volumeName, filePath string) (*volumetypes.FilePreview, error) {
+	slog.DebugContext(ctx, "volume service: preview file", "volume", volumeName, "path", filePath)
+
+	sanitizedPath, err := s.sanitizeBrowsePathInternal(filePath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path: %w", err)
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, err
+	}
+
+	containerID, cleanup, err := s.createTempContainerInternal(ctx, volumeName, true)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	reader, _, err := dockerClient.CopyFromContainer(ctx, containerID, path.Join("/volume", sanitizedPath))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file: %w", err)
+	}
+	defer reader.Close()
+
+	tr := tar.NewReader(reader)
+	hdr, err := tr.Next()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tar stream: %w", err)
+	}
+	if hdr.Typeflag != tar.TypeReg {
+		return nil, ErrPreviewUnsupported
+	}
+	if hdr.Size > maxPreviewFileSize {
+		return nil, fmt.Errorf("file is too large to preview (limit %d MB)", maxPreviewFileSize/1024/1024)
+	}
+
+	tmp, err := os.CreateTemp("", "arcane-preview-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	if _, err := io.Copy(tmp, tr); err != nil {
+		return nil, fmt.Errorf("failed to copy file: %w", err)
+	}
+
+	head := make([]byte, 512)
+	n, _ := tmp.ReadAt(head, 0)
+
+	preview := &volumetypes.FilePreview{Viewer: filepreview.DetectViewer(sanitizedPath, head[:n])}
+	switch preview.Viewer {
+	case filepreview.ViewerSqlite:
+		preview.Tables, preview.Truncated, err = filepreview.ListSqliteTables(tmp.Name())
+	case filepreview.ViewerArchive:
+		preview.Entries, preview.Truncated, err = filepreview.ListArchive(sanitizedPath, tmp, hdr.Size)
+	default:
+		return nil, ErrPreviewUnsupported
+	}
+	if err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
+func (s *VolumeService) DownloadFile(ctx context.Context, volumeName, filePath string) (io.ReadCloser, int64, error) {
+	slog.DebugContext(ctx, "volume service: download file", "volume", volumeName, "path", filePath)
+
+	sanitizedPath, err := s.sanitizeBrowsePathInternal(filePath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid path: %w", err)
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	containerID, cleanup, err := s.createTempContainerInternal(ctx, volumeName, true)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	targetPath := path.Join("/volume", sanitizedPath)
+	reader, _, err := dockerClient.CopyFromContainer(ctx, containerID, targetPath)
+	if err != nil {
+		cleanup()
+		return nil, 0, fmt.Errorf("failed to download: %w", err)
+	}
+
+	tr := tar.NewReader(reader)
+	hdr, err := tr.Next()
+	if err != nil {
+		reader.Close()
+		cleanup()
+		return nil, 0, fmt.Errorf("failed to read tar stream: %w", err)
+	}
+	if hdr.FileInfo().IsDir() {
+		reader.Close()
+		cleanup()
+		return nil, 0, fmt.Errorf("path is a directory")
+	}
+	size := hdr.Size
+
+	return &cleanupReadCloser{
+		Reader:  tr,
+		Closer:  reader,
+		cleanup: cleanup,
+	}, size, nil
+}
+
+// Directory archive formats accepted by DownloadDirectory.
+const (
+	DirectoryArchiveTarGz = "tar.gz"
+	DirectoryArchiveZip   = "zip"
+)
+
+var (
+	// ErrNotDirectory is returned when a directory operation targets a file.
+	ErrNotDirectory = errors.New("path is not a directory")
+	// ErrDirectoryTooLarge is returned when a directory exceeds the download size limit.
+	ErrDirectoryTooLarge = errors.New("directory exceeds the maximum download size")
+)
+
+// DownloadDirectory streams a directory of a volume as a tar.gz or zip
+// archive. The size of the directory is checked against the
+// maxVolumeDownloadSize setting before streaming starts and is returned so
+// clients can show progress; the archive length itself is not known upfront.
+func (s *VolumeService) DownloadDirectory(ctx context.Context, volumeName, dirPath, format string) (io.ReadCloser, int64, error) {
+	slog.DebugContext(ctx, "volume service: download directory", "volume", volumeName, "path", dirPath, "format", format)
+
+	if format != DirectoryArchiveTarGz && format != DirectoryArchiveZip {
+		return nil, 0, fmt.Errorf("unsupported archive format: %s", format)
+	}
+
+	sanitizedPath, err := s.sanitizeBrowsePathInternal(dirPath)
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid path: %w", err)
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	containerID, cleanup, err := s.createTempContainerInternal(ctx, volumeName, true)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	targetPath := path.Join("/volume", sanitizedPath)
+	size, err := s.directorySizeInternal(ctx, containerID, targetPath)
+	if err != nil {
+		cleanup()
+		return nil, 0, err
+	}
+
+	if s.settingsService != nil {
+		maxSizeMB := s.settingsService.GetIntSetting(ctx, "maxVolumeDownloadSize", 2048)
+		if maxSizeMB > 0 && size > int64(maxSizeMB)*1024*1024 {
+			cleanup()
+			return nil, 0, fmt.Errorf("%w of %d MB", ErrDirectoryTooLarge, maxSizeMB)
+		}
+	}
+
+	src, _, err := dockerClient.CopyFromContainer(ctx, containerID, targetPath)
+	if err != nil {
+		cleanup()
+		return nil, 0, fmt.Errorf("failed to download: %w", err)
+	}
+
+	rootName := path.Base(sanitizedPath)
+	if sanitizedPath == "/" || rootName == "." || rootName == "/" {
+		rootName = volumeName
+	}
+
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeDirectoryArchive(pw, src, format, rootName))
+	}()
+
+	return &cleanupReadCloser{
+		Reader: pr,
+		Closer: closerFunc(func() error {
+			_ = pr.Close()
+			return src.Close()
+		}),
+		cleanup: cleanup,
+	}, size, nil
+}
+
+// directorySizeInternal returns the disk usage of a directory inside a helper
+// container, or ErrNotDirectory when the path is not a directory.
+func (s *VolumeService) directorySizeInternal(ctx context.Context, containerID, dirPath string) (int64, error) {
+	stdout, stderr, err := s.execInContainerInternal(ctx, containerID, helperExecScan, []string{
+		"sh", "-c", `if [ -d "$1" ]; then du -sk "$1"; else echo notdir; fi`, "sh", dirPath,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to measure directory: %w", err)
+	}
+
+	fields := strings.Fields(stdout)
+	if len(fields) == 0 {
+		return 0, fmt.Errorf("failed to measure directory: %s", strings.TrimSpace(stderr))
+	}
+	if fields[0] == "notdir" {
+		return 0, ErrNotDirectory
+	}
+	kb, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse directory size: %w", err)
+	}
+	return kb * 1024, nil
+}
+
+// maxDiskUsageDepth limits how deep GetDiskUsage descends.
+const maxDiskUsageDepth = 5
+
+// diskUsageScript prints du output for entries up to depth $2 below $1,
+// followed by a separator line and the directories within the same depth.
+const diskUsageScript = `set -e
+[ -d "$1" ] || { echo notdir; exit 0; }
+du -a -k -d "$2" "$1"
+echo --
+find "$1" -mindepth 1 -maxdepth "$2" -type d`
+
+// GetDiskUsage returns the disk usage of a directory in a volume broken down
+// into its files and subdirectories, descending depth levels (1 to 5).
+func (s *VolumeService) GetDiskUsage(ctx context.Context, volumeName, dirPath string, depth int) (*volumetypes.DiskUsageEntry, error) {
+	sanitizedPath, err := s.sanitizeBrowsePathInternal(dirPath)
+	if err != nil {
+		return nil, fmt.Errorf("invalid path: %w", err)
+	}
+	depth = max(1, min(depth, maxDiskUsageDepth))
+
+	containerID, cleanup, err := s.createTempContainerInternal(ctx, volumeName, true)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	targetPath := path.Join("/volume", sanitizedPath)
+	stdout, stderr, err := s.execInContainerInternal(ctx, containerID, helperExecScan, []string{
+		"sh", "-c", diskUsageScript, "sh", targetPath, strconv.Itoa(depth),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to measure disk usage: %w", err)
+	}
+	if strings.TrimSpace(stdout) == "notdir" {
+		return nil, ErrNotDirectory
+	}
+
+	usage, err := parseDiskUsage(stdout, targetPath)
+	if err != nil {
+		if msg := strings.TrimSpace(stderr); msg != "" {
+			return nil, fmt.Errorf("%w: %s", err, msg)
+		}
+		return nil, err
+	}
+	return usage, nil
+}
+
+// parseDiskUsage builds a usage tree from diskUsageScript output. Paths are
+// reported relative to /volume.
+func parseDiskUsage(output, rootPath string) (*volumetypes.DiskUsageEntry, error) {
+	duPart, dirPart, _ := strings.Cut(output, "\n--\n")
+
+	dirs := map[string]bool{rootPath: true}
+	for _, line := range strings.Split(dirPart, "\n") {
+		if line = strings.TrimRight(line, "\r"); line != "" {
+			dirs[line] = true
+		}
+	}
+
+	relPath := func(p string) string {
+		rel := strings.TrimPrefix(p, "/volume")
+		if rel == "" {
+			return "/"
+		}
+		return rel
+	}
+
+	nodes := map[string]*volumetypes.DiskUsageEntry{}
+	var order []string
+	for _, line := range strings.Split(duPart, "\n") {
+		sizeStr, p, ok := strings.Cut(line, "\t")
+		if !ok {
+			continue
+		}
+		kb, err := strconv.ParseInt(strings.TrimSpace(sizeStr), 10, 64)
+		if err != nil {
+			continue
+		}
+		p = path.Clean(p)
+		nodes[p] = &volumetypes.DiskUsageEntry{
+			Name:        path.Base(p),
+			Path:        relPath(p),
+			Size:        kb * 1024,
+			IsDirectory: dirs[p],
+		}
+		order = append(order, p)
+	}
+
+	root, ok := nodes[rootPath]
+	if !ok {
+		return nil, fmt.Errorf("failed to measure disk usage: no output for %s", relPath(rootPath))
+	}
+	if rootPath == "/volume" {
+		root.Name = "/"
+	}
+
+	// du prints children before their parent, so by the time a directory is
+	// copied into its parent all of its own children are attached.
+	for _, p := range order {
+		if p == rootPath {
+			continue
+		}
+		if parent, ok := nodes[path.Dir(p)]; ok {
+			parent.Children = append(parent.Children, *nodes[p])
+		}
+	}
+
+	var finalize func(e *volumetypes.DiskUsageEntry)
+	finalize = func(e *volumetypes.DiskUsageEntry) {
+		slices.SortFunc(e.Children, func(a, b volumetypes.DiskUsageEntry) int {
+			if a.Size != b.Size {
+				return cmp.Compare(b.Size, a.Size)
+			}
+			return strings.Compare(a.Name, b.Name)
+		})
+		for i := range e.Children {
+			finalize(&e.Children[i])
+		}
+	}
+	finalize(root)
+
+	return root, nil
+}
+
+type closerFunc func() error
+
+func (f closerFunc) Close() error { return f() }
+
+// writeDirectoryArchive re-packs the tar stream returned by the Docker copy
+// API into a tar.gz or zip archive. Docker prefixes every entry with the base
+// name of the copied path; that prefix is replaced with rootName.
+func writeDirectoryArchive(w io.Writer, src io.Reader, format, rootName string) error {
+	rename := func(name string) string {
+		_, rest, found := strings.Cut(strings.TrimPrefix(name, "./"), "/")
+		if !found || rest == "" {
+			return rootName
+		}
+		return path.Join(rootName, rest)
+	}
+
+	tr := tar.NewReader(src)
+
+	if format == DirectoryArchiveZip {
+		zw := zip.NewWriter(w)
+		for {
+			hdr, err := tr.Next()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read tar stream: %w", err)
+			}
+
+			fh, err := zip.FileInfoHeader(hdr.FileInfo())
+			if err != nil {
+				return err
+			}
+			fh.Name = rename(hdr.Name)
+			switch hdr.Typeflag {
+			case tar.TypeDir:
+				fh.Name += "/"
+				fh.Method = zip.Store
+			case tar.TypeReg, tar.TypeSymlink:
+				fh.Method = zip.Deflate
+			default:
+				// Hard links, devices and fifos have no zip equivalent
+				continue
+			}
+
+			fw, err := zw.CreateHeader(fh)
+			if err != nil {
+				return err
+			}
+			switch hdr.Typeflag {
+			case tar.TypeSymlink:
+				if _, err := io.WriteString(fw, hdr.Linkname); err != nil {
+					return err
+				}
+			case tar.TypeReg:
+				if _, err := io.Copy(fw, tr); err != nil {
+					return err
+				}
+			}
+		}
+		return zw.Close()
+	}
+
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read tar stream: %w", err)
+		}
+
+		hdr.Name = rename(hdr.Name)
+		if hdr.Typeflag == tar.TypeDir {
+			hdr.Name += "/"
+		}
+		if hdr.Typeflag == tar.TypeLink {
+			hdr.Linkname = rename(hdr.Linkname)
+		}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if _, err := io.Copy(tw, tr); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func (s *VolumeService) getHelperImageInternal(ctx context.Context) (string, error) {
+	slog.DebugContext(ctx, "volume service: resolve helper image")
+
+	// 0. Use the pinned image when one is configured, validating it once.
+	if pinned := s.pinnedHelperImageInternal(); pinned != "" {
+		s.helperMu.Lock()
+		validated := s.validatedHelper == pinned
+		s.helperMu.Unlock()
+		if !validated {
+			if err := s.ValidateHelperImage(ctx, pinned); err != nil {
+				return "", err
+			}
+			s.helperMu.Lock()
+			s.validatedHelper = pinned
+			s.helperMu.Unlock()
+		}
+		return pinned, nil
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return "", fmt.Errorf("failed to get docker client: %w", err)
+	}
+
+	// 1. Try to find Arcane container's image (the most prune-proof)
+	// Check hostname first (ID of container if in Docker)
+	hostname, _ := os.Hostname()
+	if hostname != "" {
+		if inspect, err := dockerClient.ContainerInspect(ctx, hostname); err == nil {
+			return inspect.Config.Image, nil
+		}
+	}
+
+	// 2. Search for any container with Arcane label
+	filter := filters.NewArgs()
+	filter.Add("label", "com.getarcaneapp.arcane=true")
+	if containers, err := dockerClient.ContainerList(ctx, container.ListOptions{Filters: filter, All: true}); err == nil && len(containers) > 0 {
+		return containers[0].Image, nil
+	}
+
+	// 3. Try busybox:stable-musl
+	const helperImage = "busybox:stable-musl"
+	if _, err := dockerClient.ImageInspect(ctx, helperImage); err == nil {
+		return helperImage, nil
+	}
+
+	// 4. Default to pulling busybox
+	slog.InfoContext(ctx, "no suitable internal image found, pulling busybox:stable-musl")
+	if s.imageService == nil {
+		return "", fmt.Errorf("helper image %s missing and image service unavailable", helperImage)
+	}
+	if err := s.imageService.PullImage(ctx, helperImage, io.Discard, systemUser, nil); err != nil {
+		return "", fmt.Errorf("failed to pull helper image %s: %w", helperImage, err)
+	}
+
+	return helperImage, nil
+}
+
+// ErrInvalidHelperImage is returned when the pinned helper image cannot run
+// the volume helper scripts.
+var ErrInvalidHelperImage = errors.New("helper image is not usable")
+
+// helperImageTools are the commands the volume helper scripts rely on besides
+// sh itself.
+var helperImageTools = []string{"tar", "find", "stat"}
+
+// helperToolCheckScript prints each tool given as an argument that is not on
+// the PATH and fails if any is missing.
+const helperToolCheckScript = `missing=0
+for tool in "$@"; do
+  if ! command -v "$tool" >/dev/null 2>&1; then
+    echo "$tool"
+    missing=1
+  fi
+done
+exit $missing`
+
+// pinnedHelperImageInternal returns the helper image configured through the
+// helperImage setting or HELPER_IMAGE, or "" to detect one.
+func (s *VolumeService) pinnedHelperImageInternal() string {
+	if s.settingsService == nil {
+		return ""
+	}
+	cfg := s.settingsService.GetSettingsConfig()
+	if cfg == nil {
+		return ""
+	}
+	return strings.TrimSpace(cfg.HelperImage.Value)
+}
+
+// ValidateHelperImage checks that image can run the volume helper scripts,
+// pulling it first if needed. The image is run the same way the helpers run
+// it, so an entrypoint that swallows the command fails validation as well.
+func (s *VolumeService) ValidateHelperImage(ctx context.Context, image string) error {
+	image = strings.TrimSpace(image)
+	if image == "" {
+		return nil
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return fmt.Errorf("failed to get docker client: %w", err)
+	}
+
+	if _, err := dockerClient.ImageInspect(ctx, image); err != nil {
+		if s.imageService == nil {
+			return fmt.Errorf("%w: %s is not present and image service unavailable", ErrInvalidHelperImage, image)
+		}
+		if err := s.imageService.PullImage(ctx, image, io.Discard, systemUser, nil); err != nil {
+			return fmt.Errorf("%w: failed to pull %s: %w", ErrInvalidHelperImage, image, err)
+		}
+	}
+
+	config := &container.Config{
+		Image: image,
+		Cmd:   append([]string{"sh", "-c", helperToolCheckScript, "sh"}, helperImageTools...),
+		Labels: map[string]string{
+			libarcane.InternalContainerLabel: "true",
+		},
+	}
+	resp, err := dockerClient.ContainerCreate(ctx, config, &container.HostConfig{Resources: s.helperResourcesInternal()}, nil, nil, "")
+	if err != nil {
+		return fmt.Errorf("%w: failed to create check container from %s: %w", ErrInvalidHelperImage, image, err)
+	}
+	defer func() {
+		_ = dockerClient.ContainerRemove(context.WithoutCancel(ctx), resp.ID, container.RemoveOptions{Force: true})
+	}()
+
+	if err := dockerClient.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return fmt.Errorf("%w: %s cannot run sh: %w", ErrInvalidHelperImage, image, err)
+	}
+
+	var exitCode int64
+	statusCh, errCh := dockerClient.ContainerWait(ctx, resp.ID, container.WaitConditionNotRunning)
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return fmt.Errorf("failed to wait for helper image check: %w", err)
+		}
+	case status := <-statusCh:
+		exitCode = status.StatusCode
+	}
+	if exitCode == 0 {
+		return nil
+	}
+
+	missing := strings.Join(helperImageTools, ", ")
+	if logs, err := dockerClient.ContainerLogs(ctx, resp.ID, container.LogsOptions{ShowStdout: true}); err == nil {
+		var stdout bytes.Buffer
+		_, _ = stdcopy.StdCopy(&stdout, io.Discard, logs)
+		_ = logs.Close()
+		if out := strings.TrimSpace(stdout.String()); out != "" {
+			missing = strings.Join(strings.Fields(out), ", ")
+		}
+	}
+	return fmt.Errorf("%w: %s is missing required tools: %s", ErrInvalidHelperImage, image, missing)
+}
+
+func (s *VolumeService) BackupMountWarning(ctx context.Context) string {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return ""
+	}
+
+	containerID := s.getArcaneContainerIDInternal(ctx, dockerClient)
+	if containerID == "" {
+		return ""
+	}
+
+	inspect, err := dockerClient.ContainerInspect(ctx, containerID)
+	if err != nil {
+		return ""
+	}
+
+	hasBackups := false
+	hasRestores := false
+	for _, mount := range inspect.Mounts {
+		if mount.Destination == "/backups" {
+			hasBackups = true
+		}
+		if mount.Destination == "/restores" {
+			hasRestores = true
+		}
+	}
+
+	if hasBackups || hasRestores {
+		return ""
+	}
+
+	return "No volume is mounted at /backups or /restores in the Arcane container. Backups/restores will only live inside Docker unless you mount a host path."
+}
+
+func (s *VolumeService) getArcaneContainerIDInternal(ctx context.Context, dockerClient *client.Client) string {
+	hostname, _ := os.Hostname()
+	if hostname != "" {
+		if inspect, err := dockerClient.ContainerInspect(ctx, hostname); err == nil {
+			return inspect.ID
+		}
+	}
+
+	filter := filters.NewArgs()
+	filter.Add("label", "com.getarcaneapp.arcane=true")
+	containers, err := dockerClient.ContainerList(ctx, container.ListOptions{Filters: filter, All: true})
+	if err != nil || len(containers) == 0 {
+		return ""
+	}
+
+	for _, c := range containers {
+		if strings.EqualFold(strings.TrimSpace(c.State), "running") {
+			return c.ID
+		}
+	}
+
+	return containers[0].ID
+}
+
+type cleanupReadCloser struct {
+	io.Reader
+	io.Closer
+	cleanup func()
+}
+
+func (c *cleanupReadCloser) Close() error {
+	err := c.Closer.Close()
+	c.cleanup()
 	return err
 }
 
@@ -490,8 +1689,8 @@ func (s *VolumeService) createTempContainerInternal(ctx context.Context, volumeN
 	}
 
 	if readOnly {
-		if containerID, ok := s.getReusableReadOnlyContainerInternal(ctx, dockerClient, volumeName); ok {
-			return containerID, func() {}, nil
+		if containerID, release, ok := s.getReusableReadOnlyContainerInternal(ctx, dockerClient, volumeName); ok {
+			return containerID, release, nil
 		}
 	}
 
@@ -519,6 +1718,7 @@ func (s *VolumeService) createTempContainerInternal(ctx context.Context, volumeN
 			}()),
 		},
 		AutoRemove: true,
+		Resources:  s.helperResourcesInternal(),
 	}
 
 	resp, err := dockerClient.ContainerCreate(ctx, config, hostConfig, nil, nil, "")
@@ -537,31 +1737,140 @@ func (s *VolumeService) createTempContainerInternal(ctx context.Context, volumeN
 
 	if readOnly {
 		s.helperMu.Lock()
-		s.helperByVolume[volumeName] = resp.ID
+		s.helperByVolume[volumeName] = &helperContainer{id: resp.ID, inUse: 1, lastUsed: time.Now()}
 		s.helperMu.Unlock()
-		return resp.ID, func() {}, nil
+		return resp.ID, s.releaseHelperInternal(volumeName, resp.ID), nil
 	}
 
 	return resp.ID, cleanup, nil
 }
 
-func (s *VolumeService) getReusableReadOnlyContainerInternal(ctx context.Context, dockerClient *client.Client, volumeName string) (string, bool) {
+func (s *VolumeService) getReusableReadOnlyContainerInternal(ctx context.Context, dockerClient *client.Client, volumeName string) (string, func(), bool) {
 	s.helperMu.Lock()
-	containerID := s.helperByVolume[volumeName]
-	s.helperMu.Unlock()
-	if containerID == "" {
-		return "", false
+	helper := s.helperByVolume[volumeName]
+	if helper == nil || helper.id == "" {
+		s.helperMu.Unlock()
+		return "", nil, false
 	}
+	// Mark the helper in use before inspecting it so the idle reaper does not
+	// remove it in between.
+	helper.inUse++
+	containerID := helper.id
+	s.helperMu.Unlock()
 
 	inspect, err := dockerClient.ContainerInspect(ctx, containerID)
 	if err != nil || inspect.State == nil || !inspect.State.Running {
 		s.helperMu.Lock()
-		delete(s.helperByVolume, volumeName)
+		if current := s.helperByVolume[volumeName]; current != nil && current.id == containerID {
+			delete(s.helperByVolume, volumeName)
+		}
 		s.helperMu.Unlock()
-		return "", false
+		return "", nil, false
+	}
+
+	return containerID, s.releaseHelperInternal(volumeName, containerID), true
+}
+
+// releaseHelperInternal returns the func that marks a shared helper as no
+// longer used by the caller, starting its idle timer.
+func (s *VolumeService) releaseHelperInternal(volumeName, containerID string) func() {
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			s.helperMu.Lock()
+			defer s.helperMu.Unlock()
+			if helper := s.helperByVolume[volumeName]; helper != nil && helper.id == containerID {
+				if helper.inUse > 0 {
+					helper.inUse--
+				}
+				helper.lastUsed = time.Now()
+			}
+		})
+	}
+}
+
+// helperIdleTTLInternal returns how long an unused read-only helper is kept,
+// or 0 to keep helpers until CleanupHelperContainers runs.
+func (s *VolumeService) helperIdleTTLInternal() time.Duration {
+	if s.settingsService == nil {
+		return defaultHelperIdleTTL
+	}
+	cfg := s.settingsService.GetSettingsConfig()
+	if cfg == nil {
+		return defaultHelperIdleTTL
+	}
+	minutes := cfg.HelperIdleTtl.AsInt()
+	if minutes <= 0 {
+		return 0
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// helperResourcesInternal returns the CPU and memory limits configured for
+// helper containers. Zero values leave the container unlimited.
+func (s *VolumeService) helperResourcesInternal() container.Resources {
+	if s.settingsService == nil {
+		return container.Resources{}
+	}
+	cfg := s.settingsService.GetSettingsConfig()
+	if cfg == nil {
+		return container.Resources{}
+	}
+
+	var resources container.Resources
+	if cpus, err := strconv.ParseFloat(strings.TrimSpace(cfg.HelperCpuLimit.Value), 64); err == nil && cpus > 0 {
+		resources.NanoCPUs = int64(cpus * 1e9)
+	}
+	if mb := cfg.HelperMemoryLimit.AsInt(); mb > 0 {
+		resources.Memory = int64(mb) * 1024 * 1024
+	}
+	return resources
+}
+
+// ReapIdleHelpers removes read-only helper containers that have not been used
+// for the configured idle TTL and returns how many were removed.
+func (s *VolumeService) ReapIdleHelpers(ctx context.Context) int {
+	ttl := s.helperIdleTTLInternal()
+	if ttl <= 0 {
+		return 0
+	}
+
+	now := time.Now()
+	s.helperMu.Lock()
+	var idleIDs []string
+	for volumeName, helper := range s.helperByVolume {
+		if helper.inUse > 0 || now.Sub(helper.lastUsed) < ttl {
+			continue
+		}
+		if helper.id != "" {
+			idleIDs = append(idleIDs, helper.id)
+		}
+		delete(s.helperByVolume, volumeName)
+	}
+	s.helperMu.Unlock()
+
+	if len(idleIDs) == 0 {
+		return 0
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		slog.WarnContext(ctx, "failed to get docker client for helper reaping", "error", err)
+		return 0
 	}
 
-	return containerID, true
+	removed := 0
+	for _, containerID := range idleIDs {
+		if err := dockerClient.ContainerRemove(ctx, containerID, container.RemoveOptions{Force: true}); err != nil {
+			slog.WarnContext(ctx, "failed to remove idle helper container", "container_id", containerID, "error", err.Error())
+			continue
+		}
+		removed++
+	}
+	if removed > 0 {
+		slog.DebugContext(ctx, "removed idle volume helper containers", "count", removed, "idle_ttl", ttl)
+	}
+	return removed
 }
 
 func (s *VolumeService) CleanupHelperContainers(ctx context.Context) {
@@ -573,12 +1882,12 @@ func (s *VolumeService) CleanupHelperContainers(ctx context.Context) {
 
 	s.helperMu.Lock()
 	helperIDs := make([]string, 0, len(s.helperByVolume))
-	for _, containerID := range s.helperByVolume {
-		if containerID != "" {
-			helperIDs = append(helperIDs, containerID)
+	for _, helper := range s.helperByVolume {
+		if helper.id != "" {
+			helperIDs = append(helperIDs, helper.id)
 		}
 	}
-	s.helperByVolume = make(map[string]string)
+	s.helperByVolume = make(map[string]*helperContainer)
 	s.helperMu.Unlock()
 
 	for _, containerID := range helperIDs {
@@ -597,50 +1906,500 @@ func (s *VolumeService) removeHelperEntry(volumeName string) {
 	s.helperMu.Unlock()
 }
 
-func (s *VolumeService) execInContainerInternal(ctx context.Context, containerID string, cmd []string) (string, string, error) {
+// helperExecKind selects the timeout of a command run in a helper container.
+type helperExecKind int
+
+const (
+	// helperExecFile is a quick file operation such as stat, mkdir or rm.
+	helperExecFile helperExecKind = iota
+	// helperExecScan walks a directory tree, such as find, du or tar -t.
+	helperExecScan
+	// helperExecArchive extracts archives or moves volume contents.
+	helperExecArchive
+)
+
+// ErrHelperExecTimeout is returned when a command in a helper container runs
+// longer than its configured timeout. The command is killed.
+var ErrHelperExecTimeout = errors.New("helper command timed out")
+
+// helperExecScript runs "$@" as a child of a shell that writes its PID to $0,
+// so that helperExecKillScript can find and kill it.
+const helperExecScript = `echo $$ > "$0"; "$@"; rc=$?; rm -f "$0"; exit $rc`
+
+// helperExecKillScript kills the command started by helperExecScript with
+// the PID file $1.
+const helperExecKillScript = `pid=$(cat "$1" 2>/dev/null) || exit 0
+pkill -KILL -P "$pid" 2>/dev/null
+kill -KILL "$pid" 2>/dev/null
+rm -f "$1"
+exit 0`
+
+// execInContainerInternal runs cmd in a helper container and returns its
+// output. The command is killed when ctx is canceled or it runs longer than
+// the timeout configured for kind, or the request deadline when the request
+// set one.
+func (s *VolumeService) execInContainerInternal(ctx context.Context, containerID string, kind helperExecKind, cmd []string) (string, string, error) {
 	slog.DebugContext(ctx, "volume service: exec in container", "container_id", containerID, "cmd", cmd)
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
 		return "", "", err
 	}
 
+	release, err := s.dockerService.AcquireExec(ctx)
+	if err != nil {
+		return "", "", err
+	}
+	defer release()
+
+	timeout := s.helperExecTimeoutInternal(kind)
+	execCtx, cancel := timeouts.WithTimeout(ctx, 0, timeout)
+	defer cancel()
+
+	pidFile := "/tmp/.arcane-exec-" + uuid.NewString() + ".pid"
 	execConfig := container.ExecOptions{
 		AttachStdout: true,
 		AttachStderr: true,
-		Cmd:          cmd,
+		Cmd:          append([]string{"sh", "-c", helperExecScript, pidFile}, cmd...),
+	}
+
+	execResp, err := dockerClient.ContainerExecCreate(execCtx, containerID, execConfig)
+	if err != nil {
+		return "", "", err
 	}
 
-	execResp, err := dockerClient.ContainerExecCreate(ctx, containerID, execConfig)
+	resp, err := dockerClient.ContainerExecAttach(execCtx, execResp.ID, container.ExecAttachOptions{})
 	if err != nil {
 		return "", "", err
 	}
+	defer resp.Close()
+	// Closing the connection unblocks the read below; the exec keeps running
+	// until it is killed.
+	stop := context.AfterFunc(execCtx, resp.Close)
+	defer stop()
 
-	resp, err := dockerClient.ContainerExecAttach(ctx, execResp.ID, container.ExecAttachOptions{})
+	var stdout, stderr bytes.Buffer
+	_, err = stdcopy.StdCopy(&stdout, &stderr, resp.Reader)
+	if execCtx.Err() != nil {
+		s.killExecInternal(context.WithoutCancel(ctx), containerID, pidFile)
+		if ctx.Err() != nil {
+			return stdout.String(), stderr.String(), ctx.Err()
+		}
+		return stdout.String(), stderr.String(), fmt.Errorf("%w after %s: %s", ErrHelperExecTimeout, timeout, cmd[0])
+	}
 	if err != nil {
 		return "", "", err
 	}
+
+	return stdout.String(), stderr.String(), nil
+}
+
+// killExecInternal kills a command started by execInContainerInternal.
+func (s *VolumeService) killExecInternal(ctx context.Context, containerID, pidFile string) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return
+	}
+	killCtx, cancel := timeouts.WithTimeout(ctx, 0, timeouts.DefaultDockerAPI)
+	defer cancel()
+
+	execResp, err := dockerClient.ContainerExecCreate(killCtx, containerID, container.ExecOptions{
+		AttachStdout: true,
+		AttachStderr: true,
+		Cmd:          []string{"sh", "-c", helperExecKillScript, "sh", pidFile},
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "failed to kill timed out helper command", "container_id", containerID, "error", err)
+		return
+	}
+	resp, err := dockerClient.ContainerExecAttach(killCtx, execResp.ID, container.ExecAttachOptions{})
+	if err != nil {
+		slog.WarnContext(ctx, "failed to kill timed out helper command", "container_id", containerID, "error", err)
+		return
+	}
 	defer resp.Close()
+	_, _ = io.Copy(io.Discard, resp.Reader)
+}
+
+// helperExecTimeoutInternal returns the configured timeout for kind.
+func (s *VolumeService) helperExecTimeoutInternal(kind helperExecKind) time.Duration {
+	var cfg *models.Settings
+	if s.settingsService != nil {
+		cfg = s.settingsService.GetSettingsConfig()
+	}
+	switch kind {
+	case helperExecScan:
+		if cfg == nil {
+			return timeouts.DefaultHelperScan
+		}
+		return timeouts.GetDuration(cfg.HelperScanTimeout.AsInt(), timeouts.DefaultHelperScan)
+	case helperExecArchive:
+		if cfg == nil {
+			return timeouts.DefaultHelperArchive
+		}
+		return timeouts.GetDuration(cfg.HelperArchiveTimeout.AsInt(), timeouts.DefaultHelperArchive)
+	default:
+		if cfg == nil {
+			return timeouts.DefaultHelperExec
+		}
+		return timeouts.GetDuration(cfg.HelperExecTimeout.AsInt(), timeouts.DefaultHelperExec)
+	}
+}
+
+// readLinkInternal returns the raw, unresolved target of a symlink, or "" when
+// it cannot be read.
+func (s *VolumeService) readLinkInternal(ctx context.Context, containerID, linkPath string) string {
+	if stat, err := s.statPathInternal(ctx, containerID, linkPath); err == nil && stat.LinkTarget != "" {
+		return stat.LinkTarget
+	}
+	target, _, _ := s.execInContainerInternal(ctx, containerID, helperExecFile, []string{"readlink", linkPath})
+	return strings.TrimSpace(target)
+}
+
+// backupFileSizeInternal returns the size of a file in a helper container,
+// falling back to stat when the archive API is unavailable.
+func (s *VolumeService) backupFileSizeInternal(ctx context.Context, containerID, filePath string) (int64, error) {
+	if stat, err := s.statPathInternal(ctx, containerID, filePath); err == nil {
+		return stat.Size, nil
+	}
+	sizeStr, _, err := s.execInContainerInternal(ctx, containerID, helperExecFile, []string{"stat", "-c", "%s", filePath})
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseInt(strings.TrimSpace(sizeStr), 10, 64)
+}
+
+// dirHasEntriesInternal reports whether dirPath in a helper container holds
+// at least one entry. It reads the directory through the archive API and
+// stops after the first entry below it.
+func (s *VolumeService) dirHasEntriesInternal(ctx context.Context, containerID, dirPath string) (bool, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return false, err
+	}
+	reader, _, err := dockerClient.CopyFromContainer(ctx, containerID, dirPath)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = reader.Close() }()
+
+	// The archive starts with the directory itself, named after its base.
+	root := path.Base(dirPath)
+	tr := tar.NewReader(reader)
+	for {
+		header, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("failed to read directory archive: %w", err)
+		}
+		if strings.TrimSuffix(header.Name, "/") != root {
+			return true, nil
+		}
+	}
+}
+
+// statPathInternal stats a path in a helper container through the Docker
+// archive API, which avoids an exec round trip and does not need stat in the
+// helper image.
+func (s *VolumeService) statPathInternal(ctx context.Context, containerID, targetPath string) (container.PathStat, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return container.PathStat{}, err
+	}
+	return dockerClient.ContainerStatPath(ctx, containerID, targetPath)
+}
+
+// mkdirAllInternal creates dirPath and any missing parents in a helper
+// container, like mkdir -p. The missing directories are copied in as an
+// archive below the deepest existing ancestor, so existing entries are never
+// replaced. It falls back to exec when the archive API cannot be used.
+func (s *VolumeService) mkdirAllInternal(ctx context.Context, containerID, dirPath string) error {
+	var missing []string
+	existing := path.Clean(dirPath)
+	for {
+		stat, err := s.statPathInternal(ctx, containerID, existing)
+		if err == nil {
+			if !stat.Mode.IsDir() {
+				return fmt.Errorf("mkdir failed: %s is not a directory", existing)
+			}
+			break
+		}
+		if !cerrdefs.IsNotFound(err) || existing == "/" {
+			return s.mkdirAllExecInternal(ctx, containerID, dirPath)
+		}
+		missing = append([]string{path.Base(existing)}, missing...)
+		existing = path.Dir(existing)
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	now := time.Now()
+	for i := range missing {
+		header := &tar.Header{
+			Typeflag: tar.TypeDir,
+			Name:     path.Join(missing[:i+1]...) + "/",
+			Mode:     0o755,
+			ModTime:  now,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("failed to build directory archive: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("failed to build directory archive: %w", err)
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return err
+	}
+	if err := dockerClient.CopyToContainer(ctx, containerID, existing, &buf, container.CopyToContainerOptions{}); err != nil {
+		slog.DebugContext(ctx, "volume service: archive mkdir failed, falling back to exec", "path", dirPath, "error", err)
+		return s.mkdirAllExecInternal(ctx, containerID, dirPath)
+	}
+	return nil
+}
+
+func (s *VolumeService) mkdirAllExecInternal(ctx context.Context, containerID, dirPath string) error {
+	_, stderr, err := s.execInContainerInternal(ctx, containerID, helperExecFile, []string{"mkdir", "-p", dirPath})
+	if err != nil {
+		return err
+	}
+	if stderr = strings.TrimSpace(stderr); stderr != "" {
+		return fmt.Errorf("mkdir failed: %s", stderr)
+	}
+	return nil
+}
+
+func (s *VolumeService) DeleteFile(ctx context.Context, volumeName, filePath string, user *models.User) error {
+	slog.DebugContext(ctx, "volume service: delete file", "volume", volumeName, "path", filePath)
+
+	sanitizedPath, err := s.sanitizeBrowsePathInternal(filePath)
+	if err != nil {
+		return fmt.Errorf("invalid path: %w", err)
+	}
+	// Prevent deleting root
+	if sanitizedPath == "/" {
+		return fmt.Errorf("cannot delete root directory")
+	}
+
+	containerID, cleanup, err := s.createTempContainerInternal(ctx, volumeName, false)
+	if err != nil {
+		return err
+	}
+	defer cleanup()
+
+	targetPath := path.Join("/volume", sanitizedPath)
+	_, stderr, err := s.execInContainerInternal(ctx, containerID, helperExecScan, []string{"rm", "-rf", targetPath})
+	if err != nil {
+		return err
+	}
+	if stderr != "" {
+		return fmt.Errorf("delete failed: %s", stderr)
+	}
+
+	actingUser := user
+	if actingUser == nil {
+		actingUser = &systemUser
+	}
+	metadata := models.JSON{
+		"action": "file_delete",
+		"path":   filePath,
+	}
+	if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeFileDelete, volumeName, volumeName, actingUser.ID, actingUser.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log volume file delete event", "volume", volumeName, "error", logErr.Error())
+	}
+	return nil
+}
+
+// copyPathScript copies $1 to $2. Exit codes: 2 = source missing,
+// 3 = destination exists and overwrite ($3) is not set.
+const copyPathScript = `set -e
+src="$1"; dst="$2"; overwrite="$3"
+[ -e "$src" ] || [ -L "$src" ] || exit 2
+if [ -e "$dst" ] && [ "$overwrite" != "1" ]; then exit 3; fi
+mkdir -p "$(dirname "$dst")"
+if [ -d "$src" ] && [ ! -L "$src" ]; then
+  mkdir -p "$dst"
+  cp -a "$src"/. "$dst"/
+else
+  rm -rf "$dst"
+  cp -a "$src" "$dst"
+fi`
+
+// CopyPath copies a file or directory from one volume to another, or to a
+// different path within the same volume, using a single helper container with
+// both volumes mounted. With overwrite set, an existing destination file is
+// replaced and an existing destination directory is merged into.
+func (s *VolumeService) CopyPath(ctx context.Context, srcVolume, srcPath, dstVolume, dstPath string, overwrite bool, user *models.User) error {
+	slog.DebugContext(ctx, "volume service: copy path", "src_volume", srcVolume, "src_path", srcPath, "dst_volume", dstVolume, "dst_path", dstPath, "overwrite", overwrite)
+
+	if strings.TrimSpace(dstVolume) == "" {
+		dstVolume = srcVolume
+	}
+
+	cleanSrc, err := s.sanitizeBrowsePathInternal(srcPath)
+	if err != nil {
+		return fmt.Errorf("invalid source path: %w", err)
+	}
+	cleanDst, err := s.sanitizeBrowsePathInternal(dstPath)
+	if err != nil {
+		return fmt.Errorf("invalid destination path: %w", err)
+	}
+	if cleanDst == "/" {
+		return fmt.Errorf("destination must not be the volume root")
+	}
+
+	if srcVolume == dstVolume {
+		if cleanSrc == cleanDst {
+			return fmt.Errorf("source and destination are the same")
+		}
+		if cleanSrc == "/" || strings.HasPrefix(cleanDst, cleanSrc+"/") {
+			return fmt.Errorf("cannot copy a directory into itself")
+		}
+	}
+
+	volumes := []string{srcVolume}
+	if dstVolume != srcVolume {
+		volumes = append(volumes, dstVolume)
+	}
+	if err := s.checkHelperVolumesInternal(ctx, volumes...); err != nil {
+		return err
+	}
+	if err := s.namespaceService.AuthorizeResource(ctx, NamespaceResourceVolume, dstVolume); err != nil {
+		return err
+	}
+
+	var binds []string
+	var srcFull, dstFull string
+	if srcVolume == dstVolume {
+		binds = []string{fmt.Sprintf("%s:/volume", srcVolume)}
+		srcFull = path.Join("/volume", cleanSrc)
+		dstFull = path.Join("/volume", cleanDst)
+	} else {
+		binds = []string{
+			fmt.Sprintf("%s:/src:ro", srcVolume),
+			fmt.Sprintf("%s:/dst", dstVolume),
+		}
+		srcFull = path.Join("/src", cleanSrc)
+		dstFull = path.Join("/dst", cleanDst)
+	}
+
+	overwriteArg := "0"
+	if overwrite {
+		overwriteArg = "1"
+	}
+
+	if err := s.runHelperContainerInternal(ctx, binds, copyPathScript, srcFull, dstFull, overwriteArg); err != nil {
+		var exitErr *helperExitError
+		if errors.As(err, &exitErr) {
+			switch exitErr.StatusCode {
+			case 2:
+				return fmt.Errorf("source path not found: %s", cleanSrc)
+			case 3:
+				return fmt.Errorf("destination already exists: %s", cleanDst)
+			}
+		}
+		return fmt.Errorf("failed to copy path: %w", err)
+	}
+
+	actingUser := user
+	if actingUser == nil {
+		actingUser = &systemUser
+	}
+	metadata := models.JSON{
+		"action":       "file_copy",
+		"sourceVolume": srcVolume,
+		"sourcePath":   cleanSrc,
+		"path":         cleanDst,
+	}
+	if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeFileCreate, dstVolume, dstVolume, actingUser.ID, actingUser.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log volume file copy event", "volume", dstVolume, "error", logErr.Error())
+	}
+	return nil
+}
+
+// ChangeOwnership changes the owner and/or group of a file or directory in a
+// volume. Symlinks are changed themselves rather than their targets.
+func (s *VolumeService) ChangeOwnership(ctx context.Context, volumeName string, req volumetypes.ChangeOwnershipRequest, user *models.User) error {
+	slog.DebugContext(ctx, "volume service: change ownership", "volume", volumeName, "path", req.Path, "recursive", req.Recursive)
+
+	if req.UID == nil && req.GID == nil {
+		return fmt.Errorf("uid or gid is required")
+	}
+	if (req.UID != nil && *req.UID < 0) || (req.GID != nil && *req.GID < 0) {
+		return fmt.Errorf("uid and gid must not be negative")
+	}
+
+	owner := ""
+	if req.UID != nil {
+		owner = strconv.Itoa(*req.UID)
+	}
+	if req.GID != nil {
+		owner += ":" + strconv.Itoa(*req.GID)
+	}
+
+	cmd := []string{"chown", "-h"}
+	if req.Recursive {
+		cmd = append(cmd, "-R")
+	}
+	if err := s.changeFileAttributesInternal(ctx, volumeName, req.Path, append(cmd, owner)); err != nil {
+		return err
+	}
+
+	metadata := models.JSON{
+		"action":    "file_chown",
+		"path":      req.Path,
+		"recursive": req.Recursive,
+	}
+	if req.UID != nil {
+		metadata["uid"] = *req.UID
+	}
+	if req.GID != nil {
+		metadata["gid"] = *req.GID
+	}
+	s.logFileAttributeEventInternal(ctx, models.EventTypeVolumeFileOwnership, volumeName, metadata, user)
+	return nil
+}
+
+// ChangePermissions changes the mode of a file or directory in a volume.
+func (s *VolumeService) ChangePermissions(ctx context.Context, volumeName string, req volumetypes.ChangePermissionsRequest, user *models.User) error {
+	slog.DebugContext(ctx, "volume service: change permissions", "volume", volumeName, "path", req.Path, "mode", req.Mode, "recursive", req.Recursive)
+
+	mode, err := strconv.ParseUint(req.Mode, 8, 32)
+	if err != nil || mode > 0o7777 {
+		return fmt.Errorf("invalid mode %q: expected octal permission bits", req.Mode)
+	}
 
-	var stdout, stderr bytes.Buffer
-	_, err = stdcopy.StdCopy(&stdout, &stderr, resp.Reader)
-	if err != nil {
-		return "", "", err
+	cmd := []string{"chmod"}
+	if req.Recursive {
+		cmd = append(cmd, "-R")
+	}
+	if err := s.changeFileAttributesInternal(ctx, volumeName, req.Path, append(cmd, fmt.Sprintf("%04o", mode))); err != nil {
+		return err
 	}
 
-	return stdout.String(), stderr.String(), nil
+	metadata := models.JSON{
+		"action":    "file_chmod",
+		"path":      req.Path,
+		"mode":      fmt.Sprintf("%04o", mode),
+		"recursive": req.Recursive,
+	}
+	s.logFileAttributeEventInternal(ctx, models.EventTypeVolumeFilePermissions, volumeName, metadata, user)
+	return nil
 }
 
-func (s *VolumeService) DeleteFile(ctx context.Context, volumeName, filePath string, user *models.User) error {
-	slog.DebugContext(ctx, "volume service: delete file", "volume", volumeName, "path", filePath)
-
+// changeFileAttributesInternal runs a chown/chmod style command against a
+// path in a writable helper container. The target path is appended to cmd.
+func (s *VolumeService) changeFileAttributesInternal(ctx context.Context, volumeName, filePath string, cmd []string) error {
 	sanitizedPath, err := s.sanitizeBrowsePathInternal(filePath)
 	if err != nil {
 		return fmt.Errorf("invalid path: %w", err)
 	}
-	// Prevent deleting root
-	if sanitizedPath == "/" {
-		return fmt.Errorf("cannot delete root directory")
-	}
 
 	containerID, cleanup, err := s.createTempContainerInternal(ctx, volumeName, false)
 	if err != nil {
@@ -648,27 +2407,24 @@ func (s *VolumeService) DeleteFile(ctx context.Context, volumeName, filePath str
 	}
 	defer cleanup()
 
-	targetPath := path.Join("/volume", sanitizedPath)
-	_, stderr, err := s.execInContainerInternal(ctx, containerID, []string{"rm", "-rf", targetPath})
+	_, stderr, err := s.execInContainerInternal(ctx, containerID, helperExecScan, append(cmd, "--", path.Join("/volume", sanitizedPath)))
 	if err != nil {
 		return err
 	}
 	if stderr != "" {
-		return fmt.Errorf("delete failed: %s", stderr)
+		return fmt.Errorf("%s failed: %s", cmd[0], strings.TrimSpace(stderr))
 	}
+	return nil
+}
 
+func (s *VolumeService) logFileAttributeEventInternal(ctx context.Context, eventType models.EventType, volumeName string, metadata models.JSON, user *models.User) {
 	actingUser := user
 	if actingUser == nil {
 		actingUser = &systemUser
 	}
-	metadata := models.JSON{
-		"action": "file_delete",
-		"path":   filePath,
-	}
-	if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeFileDelete, volumeName, volumeName, actingUser.ID, actingUser.Username, "0", metadata); logErr != nil {
-		slog.WarnContext(ctx, "could not log volume file delete event", "volume", volumeName, "error", logErr.Error())
+	if logErr := s.eventService.LogVolumeEvent(ctx, eventType, volumeName, volumeName, actingUser.ID, actingUser.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log volume file attribute event", "volume", volumeName, "error", logErr.Error())
 	}
-	return nil
 }
 
 func (s *VolumeService) CreateDirectory(ctx context.Context, volumeName, dirPath string, user *models.User) error {
@@ -685,14 +2441,9 @@ func (s *VolumeService) CreateDirectory(ctx context.Context, volumeName, dirPath
 	}
 	defer cleanup()
 
-	targetPath := path.Join("/volume", sanitizedPath)
-	_, stderr, err := s.execInContainerInternal(ctx, containerID, []string{"mkdir", "-p", targetPath})
-	if err != nil {
+	if err := s.mkdirAllInternal(ctx, containerID, path.Join("/volume", sanitizedPath)); err != nil {
 		return err
 	}
-	if stderr != "" {
-		return fmt.Errorf("mkdir failed: %s", stderr)
-	}
 
 	actingUser := user
 	if actingUser == nil {
@@ -708,8 +2459,12 @@ func (s *VolumeService) CreateDirectory(ctx context.Context, volumeName, dirPath
 	return nil
 }
 
-func (s *VolumeService) UploadFile(ctx context.Context, volumeName, destPath string, content io.Reader, filename string, user *models.User) error {
-	slog.DebugContext(ctx, "volume service: upload file", "volume", volumeName, "dest_path", destPath, "filename", filename)
+func (s *VolumeService) UploadFile(ctx context.Context, volumeName, destPath string, content io.Reader, size int64, filename string, user *models.User) error {
+	slog.DebugContext(ctx, "volume service: upload file", "volume", volumeName, "dest_path", destPath, "filename", filename, "size", size)
+
+	if size < 0 {
+		return fmt.Errorf("invalid upload size: %d", size)
+	}
 
 	sanitizedPath, err := s.sanitizeBrowsePathInternal(destPath)
 	if err != nil {
@@ -727,30 +2482,15 @@ func (s *VolumeService) UploadFile(ctx context.Context, volumeName, destPath str
 	}
 	defer cleanup()
 
-	var buf bytes.Buffer
-	tw := tar.NewWriter(&buf)
-
-	contentBytes, err := io.ReadAll(content)
-	if err != nil {
-		return err
-	}
-
-	hdr := &tar.Header{
-		Name: filename,
-		Mode: 0644,
-		Size: int64(len(contentBytes)),
-	}
-	if err := tw.WriteHeader(hdr); err != nil {
-		return err
-	}
-	if _, err := tw.Write(contentBytes); err != nil {
-		tw.Close()
-		return err
-	}
-	tw.Close()
+	// Stream the tar archive through a pipe so the upload is never held in memory.
+	pr, pw := io.Pipe()
+	go func() {
+		pw.CloseWithError(writeTarFileInternal(pw, filename, size, content))
+	}()
+	defer pr.Close()
 
 	targetDir := path.Join("/volume", sanitizedPath)
-	err = dockerClient.CopyToContainer(ctx, containerID, targetDir, &buf, container.CopyToContainerOptions{})
+	err = dockerClient.CopyToContainer(ctx, containerID, targetDir, pr, container.CopyToContainerOptions{})
 	if err != nil {
 		return fmt.Errorf("failed to upload: %w", err)
 	}
@@ -771,6 +2511,27 @@ func (s *VolumeService) UploadFile(ctx context.Context, volumeName, destPath str
 	return nil
 }
 
+// writeTarFileInternal writes a single-file tar archive to w, copying exactly
+// size bytes from content. It fails if content is shorter or longer than size.
+func writeTarFileInternal(w io.Writer, filename string, size int64, content io.Reader) error {
+	tw := tar.NewWriter(w)
+	hdr := &tar.Header{
+		Name: filename,
+		Mode: 0644,
+		Size: size,
+	}
+	if err := tw.WriteHeader(hdr); err != nil {
+		return err
+	}
+	if _, err := io.CopyN(tw, content, size); err != nil {
+		return fmt.Errorf("failed to read upload content: %w", err)
+	}
+	if n, _ := io.CopyN(io.Discard, content, 1); n > 0 {
+		return fmt.Errorf("upload content exceeds declared size of %d bytes", size)
+	}
+	return tw.Close()
+}
+
 func (s *VolumeService) ensureBackupVolumeInternal(ctx context.Context) error {
 	slog.DebugContext(ctx, "volume service: ensure backup volume", "backup_volume", s.backupVolumeName)
 	dockerClient, err := s.dockerService.GetClient()
@@ -790,8 +2551,54 @@ func (s *VolumeService) ensureBackupVolumeInternal(ctx context.Context) error {
 	return nil
 }
 
+// CreateBackup backs up a volume, broadcasting progress on the backup
+// progress hub while it runs.
 func (s *VolumeService) CreateBackup(ctx context.Context, volumeName string, user models.User) (*models.VolumeBackup, error) {
 	slog.DebugContext(ctx, "volume service: create backup", "volume", volumeName, "user", user.ID)
+	backupID := fmt.Sprintf("%s-%d-%s", volumeName, time.Now().UnixNano(), uuid.NewString()[:8])
+
+	done, err := s.operationService.Begin(ctx, models.OperationKindVolumeBackup, volumeName, models.JSON{"backup_id": backupID})
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	progress := s.newBackupProgressInternal(volumetypes.BackupOperationCreate, volumeName, backupID)
+	backup, err := s.createBackupInternal(ctx, volumeName, backupID, user, progress)
+	progress.finish(err)
+	return backup, err
+}
+
+// createPreRestoreBackupInternal backs up a volume for a restore that already
+// holds the volume's operation lock, so it does not begin a backup operation
+// of its own.
+func (s *VolumeService) createPreRestoreBackupInternal(ctx context.Context, volumeName string, user models.User) (*models.VolumeBackup, error) {
+	backupID := fmt.Sprintf("%s-%d-%s", volumeName, time.Now().UnixNano(), uuid.NewString()[:8])
+	progress := s.newBackupProgressInternal(volumetypes.BackupOperationCreate, volumeName, backupID)
+	backup, err := s.createBackupInternal(ctx, volumeName, backupID, user, progress)
+	progress.finish(err)
+	return backup, err
+}
+
+func (s *VolumeService) createBackupInternal(ctx context.Context, volumeName, backupID string, user models.User, progress *backupProgressReporter) (*models.VolumeBackup, error) {
+	if s.settingsService != nil && s.settingsService.GetStringSetting(ctx, "volumeBackupDriver", models.VolumeBackupDriverTar) == "snapshot" {
+		progress.setPhase(volumetypes.BackupPhaseArchiving)
+		backup, err := s.createSnapshotBackupInternal(ctx, volumeName, backupID)
+		if err != nil {
+			return nil, err
+		}
+		if backup != nil {
+			if err := s.db.WithContext(ctx).Create(backup).Error; err != nil {
+				if delErr := s.deleteSnapshotInternal(ctx, backup); delErr != nil {
+					slog.WarnContext(ctx, "failed to remove snapshot after database error", "backup_id", backupID, "error", delErr)
+				}
+				return nil, err
+			}
+			s.logBackupCreateEventInternal(ctx, backup, *backup.SnapshotRef, user)
+			return backup, nil
+		}
+	}
+
 	if err := s.ensureBackupVolumeInternal(ctx); err != nil {
 		return nil, err
 	}
@@ -801,7 +2608,6 @@ func (s *VolumeService) CreateBackup(ctx context.Context, volumeName string, use
 		return nil, err
 	}
 
-	backupID := fmt.Sprintf("%s-%d-%s", volumeName, time.Now().UnixNano(), uuid.NewString()[:8])
 	filename := fmt.Sprintf("%s.tar.gz", backupID)
 
 	helperImage, err := s.getHelperImageInternal(ctx)
@@ -811,7 +2617,7 @@ func (s *VolumeService) CreateBackup(ctx context.Context, volumeName string, use
 
 	config := &container.Config{
 		Image: helperImage,
-		Cmd:   []string{"sh", "-c", fmt.Sprintf("tar -czf /backups/%s -C /volume .", filename)},
+		Cmd:   []string{"sh", "-c", backupArchiveScript, "sh", filename},
 		Labels: map[string]string{
 			libarcane.InternalContainerLabel: "true",
 		},
@@ -823,6 +2629,7 @@ func (s *VolumeService) CreateBackup(ctx context.Context, volumeName string, use
 			fmt.Sprintf("%s:/backups", s.backupVolumeName),
 		},
 		AutoRemove: true,
+		Resources:  s.helperResourcesInternal(),
 	}
 
 	resp, err := dockerClient.ContainerCreate(ctx, config, hostConfig, nil, nil, "")
@@ -830,20 +2637,12 @@ func (s *VolumeService) CreateBackup(ctx context.Context, volumeName string, use
 		return nil, fmt.Errorf("failed to create backup container: %w", err)
 	}
 
-	if err := dockerClient.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
-		return nil, fmt.Errorf("failed to start backup container: %w", err)
+	status, err := s.runHelperWithProgressInternal(ctx, dockerClient, resp.ID, progress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to run backup container: %w", err)
 	}
-
-	statusCh, errCh := dockerClient.ContainerWait(ctx, resp.ID, container.WaitConditionNotRunning)
-	select {
-	case err := <-errCh:
-		if err != nil {
-			return nil, err
-		}
-	case status := <-statusCh:
-		if status.StatusCode != 0 {
-			return nil, fmt.Errorf("backup container exited with status %d", status.StatusCode)
-		}
+	if status.StatusCode != 0 {
+		return nil, fmt.Errorf("backup container exited with status %d", status.StatusCode)
 	}
 
 	tempContainerID, cleanup, err := s.createTempContainerInternal(ctx, s.backupVolumeName, true)
@@ -852,11 +2651,7 @@ func (s *VolumeService) CreateBackup(ctx context.Context, volumeName string, use
 	}
 	defer cleanup()
 
-	sizeStr, _, err := s.execInContainerInternal(ctx, tempContainerID, []string{"stat", "-c", "%s", path.Join("/volume", filename)})
-	if err != nil {
-		return nil, err
-	}
-	size, err := strconv.ParseInt(strings.TrimSpace(sizeStr), 10, 64)
+	size, err := s.backupFileSizeInternal(ctx, tempContainerID, path.Join("/volume", filename))
 	if err != nil {
 		return nil, err
 	}
@@ -864,6 +2659,7 @@ func (s *VolumeService) CreateBackup(ctx context.Context, volumeName string, use
 	backup := &models.VolumeBackup{
 		VolumeName: volumeName,
 		Size:       size,
+		Driver:     models.VolumeBackupDriverTar,
 		CreatedAt:  time.Now(),
 	}
 	backup.ID = backupID
@@ -872,17 +2668,499 @@ func (s *VolumeService) CreateBackup(ctx context.Context, volumeName string, use
 		return nil, err
 	}
 
+	s.logBackupCreateEventInternal(ctx, backup, filename, user)
+	return backup, nil
+}
+
+func (s *VolumeService) logBackupCreateEventInternal(ctx context.Context, backup *models.VolumeBackup, filename string, user models.User) {
 	metadata := models.JSON{
 		"action":    "backup_create",
 		"backup_id": backup.ID,
+		"driver":    backup.Driver,
 		"filename":  filename,
-		"size":      size,
+		"size":      backup.Size,
+	}
+	if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeBackupCreate, backup.VolumeName, backup.VolumeName, user.ID, user.Username, "0", metadata); logErr != nil {
+		slog.WarnContext(ctx, "could not log volume backup create event", "volume", backup.VolumeName, "error", logErr.Error())
+	}
+}
+
+// backupProgressLoop runs the command in $1 (which must exec the tar
+// process) in the background and prints a
+// "progress <entries> <bytes>" line every second until it exits. Entries are
+// counted from tar's verbose output and bytes from the tar process's read
+// counter; the script exits with the command's status.
+const backupProgressLoop = `: > /tmp/arcane-entries
+: > /tmp/arcane-pid
+(eval "$1" > /tmp/arcane-entries & echo $! > /tmp/arcane-pid; wait $!; echo $? > /tmp/arcane-status.tmp; mv /tmp/arcane-status.tmp /tmp/arcane-status) &
+while [ ! -f /tmp/arcane-status ]; do
+	echo "progress $(wc -l < /tmp/arcane-entries) $(awk '/^rchar/ {print $2}' "/proc/$(cat /tmp/arcane-pid)/io" 2>/dev/null)"
+	sleep 1
+done
+echo "progress $(wc -l < /tmp/arcane-entries)"
+status=$(cat /tmp/arcane-status)
+[ "$status" = 0 ] || exit "$status"
+`
+
+// backupArchiveScript archives /volume into /backups/<archive>, reporting
+// totals and progress on stdout. Usage: sh -c script sh <archive>.
+const backupArchiveScript = `archive=$1
+echo "phase scanning"
+echo "total $(( $(du -sk /volume | cut -f1) * 1024 )) $(find /volume | wc -l)"
+echo "phase archiving"
+set -- 'exec tar -czvf "/backups/$archive" -C /volume .'
+` + backupProgressLoop
+
+// Exit codes of restoreSwapScript when it fails.
+const (
+	restoreExitRolledBack = 74
+	restoreExitIncomplete = 75
+)
+
+// restoreSwapScript replaces the contents of $root with the staged contents
+// of $tmp, a directory inside $root. The current contents are first moved
+// aside into another directory inside $root, so every step is a rename on
+// the same filesystem and nothing is deleted until the new contents are in
+// place. If a move fails, the entries moved so far are put back and the
+// script exits with restoreExitRolledBack; if even that fails it exits with
+// restoreExitIncomplete and the original contents stay in the
+// .restore_old.* directory, which later restores leave alone. The outcome is
+// printed as a "result" line.
+const restoreSwapScript = `echo "phase replacing"
+old=$(mktemp -d "$root/.restore_old.XXXXXX")
+move_entries() {
+	for f in "$1"/* "$1"/.[!.]* "$1"/..?*; do
+		[ -e "$f" ] || [ -L "$f" ] || continue
+		[ "$f" = "$tmp" ] || [ "$f" = "$old" ] && continue
+		case ${f##*/} in .restore_old.*) continue ;; esac
+		mv -- "$f" "$2"/ || return 1
+	done
+	return 0
+}
+failed=
+if ! move_entries "$root" "$old"; then
+	failed=1
+elif ! move_entries "$tmp" "$root"; then
+	failed=1
+	# Everything left in $root came from $tmp
+	for f in "$root"/* "$root"/.[!.]* "$root"/..?*; do
+		[ -e "$f" ] || [ -L "$f" ] || continue
+		[ "$f" = "$tmp" ] || [ "$f" = "$old" ] && continue
+		case ${f##*/} in .restore_old.*) continue ;; esac
+		rm -rf -- "$f" || true
+	done
+fi
+if [ -n "$failed" ]; then
+	if move_entries "$old" "$root" && rmdir "$old"; then
+		echo "result rolled_back"
+		exit 74
+	fi
+	echo "result incomplete $old"
+	exit 75
+fi
+rmdir "$tmp" 2>/dev/null || true
+rm -rf -- "$old" || echo "could not remove $old" >&2
+echo "result swapped"
+`
+
+// restoreArchiveScript verifies /backups/<archive>, extracts it into a
+// staging directory inside the volume, checks that every listed entry was
+// extracted and only then swaps the staged contents in, reporting progress
+// on stdout. Usage: sh -c script sh <archive>.
+const restoreArchiveScript = `set -e
+archive=$1
+root=/volume
+echo "phase verifying"
+tar -tzvf "/backups/$archive" > /tmp/arcane-list
+awk '{ s += $3; n++ } END { printf "total %d %d\n", s, n }' /tmp/arcane-list
+tmp=$(mktemp -d "$root/.restore_tmp.XXXXXX")
+trap 'rm -rf "$tmp"' EXIT
+echo "phase extracting"
+set +e
+set -- 'exec tar -xzvf "/backups/$archive" -C "$tmp"'
+` + backupProgressLoop + `set -e
+listed=$(wc -l < /tmp/arcane-list)
+extracted=$(wc -l < /tmp/arcane-entries)
+if [ "$listed" -ne "$extracted" ]; then
+	echo "only $extracted of $listed archive entries were extracted" >&2
+	exit 1
+fi
+` + restoreSwapScript
+
+// restoreSnapshotScript copies the snapshot data in $1 into a staging
+// directory inside the volume path $2 and swaps it in. It runs on the host.
+const restoreSnapshotScript = `set -e
+[ -d "$1" ]
+root=$2
+tmp=$(mktemp -d "$root/.restore_tmp.XXXXXX")
+trap 'rm -rf "$tmp"' EXIT
+cp -a "$1/." "$tmp/"
+` + restoreSwapScript
+
+// BackupProgressHub returns the hub on which backup and restore progress is
+// broadcast, starting it on first use.
+func (s *VolumeService) BackupProgressHub() *ws.Hub {
+	s.progressOnce.Do(func() {
+		s.progressHub = ws.NewHub(256)
+		go s.progressHub.Run(context.Background())
+	})
+	return s.progressHub
+}
+
+func (s *VolumeService) publishBackupProgressInternal(p volumetypes.BackupProgress) {
+	hub := s.BackupProgressHub()
+	if hub.ClientCount() == 0 {
+		return
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		slog.Warn("failed to encode backup progress", "error", err)
+		return
+	}
+	hub.Broadcast(b)
+}
+
+func (s *VolumeService) newBackupProgressInternal(operation, volumeName, backupID string) *backupProgressReporter {
+	r := &backupProgressReporter{
+		publish: s.publishBackupProgressInternal,
+		state: volumetypes.BackupProgress{
+			OperationID: uuid.NewString(),
+			Operation:   operation,
+			VolumeName:  volumeName,
+			BackupID:    backupID,
+		},
+	}
+	r.setPhase(volumetypes.BackupPhaseStarting)
+	return r
+}
+
+// backupProgressMinInterval throttles byte-level progress updates; phase
+// changes are always published.
+const backupProgressMinInterval = 500 * time.Millisecond
+
+// backupProgressReporter tracks the progress of a single backup or restore
+// and publishes it as it changes.
+type backupProgressReporter struct {
+	mu          sync.Mutex
+	state       volumetypes.BackupProgress
+	lastPublish time.Time
+	publish     func(volumetypes.BackupProgress)
+}
+
+func (r *backupProgressReporter) setPhase(phase string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.state.Phase = phase
+	r.publishLocked(true)
+}
+
+func (r *backupProgressReporter) setTotals(bytes, files int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.state.TotalBytes = bytes
+	r.state.TotalFiles = files
+	r.publishLocked(true)
+}
+
+// setProgress records the bytes and entries processed so far; negative
+// values leave the current value unchanged.
+func (r *backupProgressReporter) setProgress(bytes, files int64) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if bytes >= 0 {
+		if r.state.TotalBytes > 0 && bytes > r.state.TotalBytes {
+			bytes = r.state.TotalBytes
+		}
+		r.state.BytesProcessed = bytes
 	}
-	if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeBackupCreate, volumeName, volumeName, user.ID, user.Username, "0", metadata); logErr != nil {
-		slog.WarnContext(ctx, "could not log volume backup create event", "volume", volumeName, "error", logErr.Error())
+	if files >= 0 {
+		r.state.FilesWritten = files
+	}
+	r.publishLocked(false)
+}
+
+// handleLine applies a line printed by the backup helper scripts:
+// "phase <name>", "total <bytes> <entries>" or "progress <entries> [<bytes>]".
+func (r *backupProgressReporter) handleLine(line string) {
+	fields := strings.Fields(line)
+	if len(fields) < 2 {
+		return
+	}
+	number := func(i int) int64 {
+		if i >= len(fields) {
+			return -1
+		}
+		n, err := strconv.ParseInt(fields[i], 10, 64)
+		if err != nil {
+			return -1
+		}
+		return n
+	}
+
+	switch fields[0] {
+	case "phase":
+		r.setPhase(fields[1])
+	case "total":
+		r.setTotals(max(number(1), 0), max(number(2), 0))
+	case "progress":
+		r.setProgress(number(2), number(1))
+	}
+}
+
+func (r *backupProgressReporter) finish(err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if err != nil {
+		r.state.Phase = volumetypes.BackupPhaseFailed
+		r.state.Error = err.Error()
+		r.state.VolumeUnchanged = errors.Is(err, ErrRestoreVolumeUnchanged) && !errors.Is(err, ErrRestoreIncomplete)
+	} else {
+		r.state.Phase = volumetypes.BackupPhaseCompleted
+		if r.state.TotalBytes > 0 {
+			r.state.BytesProcessed = r.state.TotalBytes
+		}
+	}
+	r.publishLocked(true)
+}
+
+func (r *backupProgressReporter) publishLocked(force bool) {
+	now := time.Now()
+	if !force && now.Sub(r.lastPublish) < backupProgressMinInterval {
+		return
+	}
+	r.lastPublish = now
+	r.state.Timestamp = now.UTC()
+	if r.publish != nil {
+		r.publish(r.state)
+	}
+}
+
+// runHelperWithProgressInternal starts a created helper container, feeds the
+// progress lines it prints on stdout to progress and waits for it to exit.
+// Progress is best effort: if attaching fails the container still runs.
+func (s *VolumeService) runHelperWithProgressInternal(ctx context.Context, dockerClient *client.Client, containerID string, progress *backupProgressReporter) (container.WaitResponse, error) {
+	var outputDone chan struct{}
+	attach, err := dockerClient.ContainerAttach(ctx, containerID, container.AttachOptions{Stream: true, Stdout: true})
+	if err != nil {
+		slog.DebugContext(ctx, "volume service: could not attach to helper for progress", "container", containerID, "error", err)
+	} else {
+		defer attach.Close()
+		outputDone = make(chan struct{})
+		go func() {
+			defer close(outputDone)
+			pr, pw := io.Pipe()
+			go func() {
+				_, err := stdcopy.StdCopy(pw, io.Discard, attach.Reader)
+				pw.CloseWithError(err)
+			}()
+			scanner := bufio.NewScanner(pr)
+			for scanner.Scan() {
+				progress.handleLine(scanner.Text())
+			}
+			_ = pr.Close()
+		}()
+	}
+
+	if err := dockerClient.ContainerStart(ctx, containerID, container.StartOptions{}); err != nil {
+		return container.WaitResponse{}, err
+	}
+
+	statusCh, errCh := dockerClient.ContainerWait(ctx, containerID, container.WaitConditionNotRunning)
+	var waitBody container.WaitResponse
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return waitBody, err
+		}
+	case waitBody = <-statusCh:
+	}
+
+	// Let the final progress lines through before the caller reports completion
+	if outputDone != nil {
+		select {
+		case <-outputDone:
+		case <-time.After(5 * time.Second):
+		}
+	}
+	return waitBody, nil
+}
+
+// ErrSnapshotBackupUnsupported is returned for archive operations on backups
+// taken as filesystem snapshots.
+var ErrSnapshotBackupUnsupported = errors.New("operation not supported for snapshot backups")
+
+// runHostScriptInternal runs a shell script in the Docker host's mount
+// namespace through a privileged helper container and returns its stdout. It
+// is used for filesystem tools such as zfs and btrfs that must act on the
+// host. Extra args are passed to the script as positional parameters.
+func (s *VolumeService) runHostScriptInternal(ctx context.Context, script string, args ...string) (string, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return "", err
+	}
+
+	helperImage, err := s.getHelperImageInternal(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	config := &container.Config{
+		Image: helperImage,
+		Cmd:   append([]string{"nsenter", "-t", "1", "-m", "--", "sh", "-c", script, "sh"}, args...),
+		Labels: map[string]string{
+			libarcane.InternalContainerLabel: "true",
+		},
+	}
+
+	hostConfig := &container.HostConfig{
+		Privileged: true,
+		PidMode:    "host",
+		Resources:  s.helperResourcesInternal(),
+	}
+
+	resp, err := dockerClient.ContainerCreate(ctx, config, hostConfig, nil, nil, "")
+	if err != nil {
+		return "", fmt.Errorf("failed to create host helper container: %w", err)
+	}
+	defer func() {
+		removeCtx, cancel := timeouts.WithTimeout(context.WithoutCancel(ctx), 0, timeouts.DefaultDockerAPI)
+		defer cancel()
+		if err := dockerClient.ContainerRemove(removeCtx, resp.ID, container.RemoveOptions{Force: true}); err != nil {
+			slog.WarnContext(ctx, "failed to remove host helper container", "container", resp.ID, "error", err)
+		}
+	}()
+
+	if err := dockerClient.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		return "", fmt.Errorf("failed to start host helper container: %w", err)
+	}
+
+	statusCh, errCh := dockerClient.ContainerWait(ctx, resp.ID, container.WaitConditionNotRunning)
+	var exitCode int64
+	select {
+	case err := <-errCh:
+		if err != nil {
+			return "", err
+		}
+	case status := <-statusCh:
+		exitCode = status.StatusCode
+	}
+
+	logs, err := dockerClient.ContainerLogs(ctx, resp.ID, container.LogsOptions{ShowStdout: true, ShowStderr: true})
+	if err != nil {
+		return "", fmt.Errorf("failed to read host helper output: %w", err)
+	}
+	defer logs.Close()
+
+	var stdout, stderr bytes.Buffer
+	if _, err := stdcopy.StdCopy(&stdout, &stderr, logs); err != nil {
+		return "", fmt.Errorf("failed to read host helper output: %w", err)
+	}
+
+	if exitCode != 0 {
+		return "", fmt.Errorf("%w: %s", &helperExitError{StatusCode: exitCode}, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.String(), nil
+}
+
+// detectSnapshotTargetInternal returns the snapshot target for a local volume,
+// or nil when its data is not on a ZFS dataset or Btrfs subvolume.
+func (s *VolumeService) detectSnapshotTargetInternal(ctx context.Context, volumeName string) (*volumesnapshot.Target, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, err
+	}
+
+	vol, err := dockerClient.VolumeInspect(ctx, volumeName)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect volume: %w", err)
+	}
+	if vol.Driver != "local" || vol.Mountpoint == "" {
+		return nil, nil
+	}
+
+	out, err := s.runHostScriptInternal(ctx, volumesnapshot.DetectScript, vol.Mountpoint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to detect volume filesystem: %w", err)
+	}
+	return volumesnapshot.ParseDetectOutput(vol.Mountpoint, out)
+}
+
+// createSnapshotBackupInternal snapshots the volume when it lives on ZFS or
+// Btrfs. It returns nil without error when the volume cannot be snapshotted
+// so the caller can fall back to a tar backup.
+func (s *VolumeService) createSnapshotBackupInternal(ctx context.Context, volumeName, backupID string) (*models.VolumeBackup, error) {
+	target, err := s.detectSnapshotTargetInternal(ctx, volumeName)
+	if err != nil {
+		slog.WarnContext(ctx, "snapshot backup unavailable, falling back to tar", "volume", volumeName, "error", err)
+		return nil, nil
+	}
+	if target == nil {
+		slog.InfoContext(ctx, "volume is not on ZFS or Btrfs, falling back to tar backup", "volume", volumeName)
+		return nil, nil
+	}
+
+	script, args := target.CreateCommand(backupID)
+	out, err := s.runHostScriptInternal(ctx, script, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s snapshot: %w", target.Kind, err)
+	}
+	size, _ := strconv.ParseInt(strings.TrimSpace(out), 10, 64)
+
+	ref := target.SnapshotRef(backupID)
+	dataPath := target.SnapshotDataPath(backupID)
+	backup := &models.VolumeBackup{
+		VolumeName:   volumeName,
+		Size:         size,
+		Driver:       string(target.Kind),
+		SnapshotRef:  &ref,
+		SnapshotPath: &dataPath,
+		CreatedAt:    time.Now(),
+	}
+	backup.ID = backupID
+	return backup, nil
+}
+
+// restoreSnapshotBackupInternal replaces the volume contents with the data
+// kept in a snapshot backup.
+func (s *VolumeService) restoreSnapshotBackupInternal(ctx context.Context, volumeName string, backup *models.VolumeBackup) error {
+	if backup.SnapshotPath == nil || *backup.SnapshotPath == "" {
+		return fmt.Errorf("snapshot backup %s has no data path", backup.ID)
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return err
+	}
+	vol, err := dockerClient.VolumeInspect(ctx, volumeName)
+	if err != nil {
+		return fmt.Errorf("failed to inspect volume: %w", err)
+	}
+	if vol.Mountpoint == "" {
+		return fmt.Errorf("volume %s has no host mountpoint", volumeName)
+	}
+
+	if _, err := s.runHostScriptInternal(ctx, restoreSnapshotScript, *backup.SnapshotPath, vol.Mountpoint); err != nil {
+		var exitErr *helperExitError
+		if errors.As(err, &exitErr) && exitErr.StatusCode == restoreExitIncomplete {
+			return fmt.Errorf("%w: failed to restore %s snapshot, the previous contents are kept in a .restore_old directory inside the volume: %w", ErrRestoreIncomplete, backup.Driver, err)
+		}
+		return fmt.Errorf("%w: failed to restore %s snapshot: %w", ErrRestoreVolumeUnchanged, backup.Driver, err)
 	}
+	return nil
+}
 
-	return backup, nil
+// deleteSnapshotInternal removes the ZFS snapshot or Btrfs subvolume that
+// backs a snapshot backup.
+func (s *VolumeService) deleteSnapshotInternal(ctx context.Context, backup *models.VolumeBackup) error {
+	if backup.SnapshotRef == nil || *backup.SnapshotRef == "" {
+		return fmt.Errorf("snapshot backup %s has no snapshot reference", backup.ID)
+	}
+	script, args, err := volumesnapshot.DeleteCommand(volumesnapshot.Kind(backup.Driver), *backup.SnapshotRef)
+	if err != nil {
+		return err
+	}
+	_, err = s.runHostScriptInternal(ctx, script, args...)
+	return err
 }
 
 func (s *VolumeService) ListBackupsPaginated(ctx context.Context, volumeName string, params pagination.QueryParams) ([]models.VolumeBackup, pagination.Response, error) {
@@ -961,12 +3239,147 @@ func (s *VolumeService) ListBackups(ctx context.Context, volumeName string) ([]m
 	return backups, err
 }
 
-func (s *VolumeService) DeleteBackup(ctx context.Context, backupID string, user *models.User) error {
-	slog.DebugContext(ctx, "volume service: delete backup", "backup_id", backupID)
+// backupArchiveNamePattern matches archives named like the ones CreateBackup
+// writes: <volume>-<unix nanoseconds>-<8 hex chars>.tar.gz.
+var backupArchiveNamePattern = regexp.MustCompile(`^([a-zA-Z0-9][a-zA-Z0-9_.-]*)-([0-9]{10,19})-([0-9a-f]{8})\.tar\.gz$`)
+
+// parseBackupArchiveNameInternal extracts the volume name and creation time
+// from a backup archive file name.
+func parseBackupArchiveNameInternal(name string) (string, time.Time, bool) {
+	m := backupArchiveNamePattern.FindStringSubmatch(name)
+	if m == nil {
+		return "", time.Time{}, false
+	}
+	nanos, err := strconv.ParseInt(m[2], 10, 64)
+	if err != nil {
+		return "", time.Time{}, false
+	}
+	return m[1], time.Unix(0, nanos), true
+}
+
+// ScanAndAdoptBackups looks for tar.gz archives in the backup volume that
+// have no backup record, such as archives copied there by hand, and records
+// the ones following the backup naming convention so they can be restored.
+func (s *VolumeService) ScanAndAdoptBackups(ctx context.Context, user models.User) (*volumetypes.BackupAdoptResult, error) {
+	slog.DebugContext(ctx, "volume service: scan and adopt backups", "user", user.ID)
+
+	if err := s.ensureBackupVolumeInternal(ctx); err != nil {
+		return nil, err
+	}
+
+	containerID, cleanup, err := s.createTempContainerInternal(ctx, s.backupVolumeName, true)
+	if err != nil {
+		return nil, err
+	}
+	defer cleanup()
+
+	stdout, stderr, err := s.execInContainerInternal(ctx, containerID, helperExecScan, []string{"find", "/volume", "-mindepth", "1", "-maxdepth", "1", "-type", "f", "-name", "*.tar.gz", "-exec", "stat", "-c", "%s %n", "{}", "+"})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list backup archives: %w", err)
+	}
+	if strings.TrimSpace(stderr) != "" {
+		slog.DebugContext(ctx, "volume service: scan backups stderr", "stderr", strings.TrimSpace(stderr))
+	}
+
+	var knownIDs []string
+	if err := s.db.WithContext(ctx).Model(&models.VolumeBackup{}).Pluck("id", &knownIDs).Error; err != nil {
+		return nil, fmt.Errorf("failed to load backups: %w", err)
+	}
+	known := make(map[string]struct{}, len(knownIDs))
+	for _, id := range knownIDs {
+		known[id] = struct{}{}
+	}
+
+	result := &volumetypes.BackupAdoptResult{
+		Adopted: []volumetypes.BackupEntry{},
+		Skipped: []volumetypes.SkippedBackupArchive{},
+	}
+	for _, line := range strings.Split(strings.TrimSpace(stdout), "\n") {
+		sizeStr, filePath, ok := strings.Cut(line, " ")
+		if !ok {
+			continue
+		}
+		filename := path.Base(filePath)
+		backupID := strings.TrimSuffix(filename, ".tar.gz")
+		if _, exists := known[backupID]; exists {
+			continue
+		}
+
+		volumeName, createdAt, ok := parseBackupArchiveNameInternal(filename)
+		if !ok {
+			result.Skipped = append(result.Skipped, volumetypes.SkippedBackupArchive{
+				Filename: filename,
+				Reason:   "file name does not match <volume>-<timestamp>-<id>.tar.gz",
+			})
+			continue
+		}
+		size, err := strconv.ParseInt(sizeStr, 10, 64)
+		if err != nil {
+			result.Skipped = append(result.Skipped, volumetypes.SkippedBackupArchive{Filename: filename, Reason: "could not read file size"})
+			continue
+		}
+
+		backup := &models.VolumeBackup{
+			VolumeName: volumeName,
+			Size:       size,
+			Driver:     models.VolumeBackupDriverTar,
+			CreatedAt:  createdAt,
+		}
+		backup.ID = backupID
+		if err := s.db.WithContext(ctx).Create(backup).Error; err != nil {
+			return nil, fmt.Errorf("failed to record backup %s: %w", filename, err)
+		}
+		result.Adopted = append(result.Adopted, backup.ToDTO())
+
+		metadata := models.JSON{
+			"action":   "backup_adopt",
+			"backupId": backup.ID,
+			"filename": filename,
+			"size":     size,
+		}
+		if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeBackupAdopt, volumeName, volumeName, user.ID, user.Username, "0", metadata); logErr != nil {
+			slog.WarnContext(ctx, "could not log volume backup adopt event", "volume", volumeName, "error", logErr.Error())
+		}
+	}
+
+	return result, nil
+}
+
+// BackupExists reports whether a backup record with the given ID exists. It
+// fails with ErrNamespaceForbidden when the backup's volume is outside the
+// caller's namespace scope.
+func (s *VolumeService) BackupExists(ctx context.Context, backupID string) (bool, error) {
+	_, err := s.getBackupInternal(ctx, backupID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to look up backup: %w", err)
+	}
+	return true, nil
+}
+
+// getBackupInternal loads a backup record and checks that the volume it was
+// taken from is within the caller's namespace scope. Backups are addressed by
+// ID alone, so the namespace middleware cannot check them by path.
+func (s *VolumeService) getBackupInternal(ctx context.Context, backupID string) (*models.VolumeBackup, error) {
 	var backup models.VolumeBackup
 	if err := s.db.WithContext(ctx).Where("id = ?", backupID).First(&backup).Error; err != nil {
+		return nil, err
+	}
+	if err := s.namespaceService.AuthorizeResource(ctx, NamespaceResourceVolume, backup.VolumeName); err != nil {
+		return nil, err
+	}
+	return &backup, nil
+}
+
+func (s *VolumeService) DeleteBackup(ctx context.Context, backupID string, user *models.User) error {
+	slog.DebugContext(ctx, "volume service: delete backup", "backup_id", backupID)
+	found, err := s.getBackupInternal(ctx, backupID)
+	if err != nil {
 		return err
 	}
+	backup := *found
 
 	// Delete from DB first - if this fails, no changes are made.
 	// If file deletion fails afterward, we just have an orphan file (easier to clean up)
@@ -977,13 +3390,16 @@ func (s *VolumeService) DeleteBackup(ctx context.Context, backupID string, user
 	}
 
 	// Now delete the actual file - best effort since DB record is already gone
-	containerID, cleanup, err := s.createTempContainerInternal(ctx, s.backupVolumeName, false)
-	if err != nil {
+	if backup.IsSnapshot() {
+		if err := s.deleteSnapshotInternal(ctx, &backup); err != nil {
+			slog.WarnContext(ctx, "failed to delete backup snapshot (orphan snapshot may remain)", "backup_id", backupID, "error", err.Error())
+		}
+	} else if containerID, cleanup, err := s.createTempContainerInternal(ctx, s.backupVolumeName, false); err != nil {
 		slog.WarnContext(ctx, "failed to create container for backup file cleanup", "backup_id", backupID, "error", err.Error())
 	} else {
 		defer cleanup()
 		filename := fmt.Sprintf("%s.tar.gz", backupID)
-		if _, _, err = s.execInContainerInternal(ctx, containerID, []string{"rm", "-f", path.Join("/volume", filename)}); err != nil {
+		if _, _, err = s.execInContainerInternal(ctx, containerID, helperExecFile, []string{"rm", "-f", path.Join("/volume", filename)}); err != nil {
 			slog.WarnContext(ctx, "failed to delete backup file (orphan file may remain)", "backup_id", backupID, "error", err.Error())
 		}
 	}
@@ -1003,50 +3419,172 @@ func (s *VolumeService) DeleteBackup(ctx context.Context, backupID string, user
 	return nil
 }
 
-func (s *VolumeService) RestoreBackup(ctx context.Context, volumeName, backupID string, user models.User) error {
-	slog.DebugContext(ctx, "volume service: restore backup", "volume", volumeName, "backup_id", backupID, "user", user.ID)
+// RestoreBackup replaces the volume contents with a backup, broadcasting
+// progress on the backup progress hub while it runs. With stopContainers set,
+// running containers that use the volume are stopped for the restore and
+// started again afterwards, whether or not the restore succeeded; otherwise
+// the restore is refused while the volume is in use.
+func (s *VolumeService) RestoreBackup(ctx context.Context, volumeName, backupID string, stopContainers bool, user models.User) (*volumetypes.RestoreResult, error) {
+	slog.DebugContext(ctx, "volume service: restore backup", "volume", volumeName, "backup_id", backupID, "stop_containers", stopContainers, "user", user.ID)
+
+	done, err := s.operationService.Begin(ctx, models.OperationKindVolumeRestore, volumeName, models.JSON{"volume": volumeName, "backup_id": backupID, "stop_containers": stopContainers})
+	if err != nil {
+		return nil, err
+	}
+	defer done()
+
+	progress := s.newBackupProgressInternal(volumetypes.BackupOperationRestore, volumeName, backupID)
+
+	var stopped []string
+	if stopContainers {
+		stopped, err = s.stopVolumeContainersInternal(ctx, volumeName, user)
+		if err != nil {
+			progress.finish(err)
+			return nil, err
+		}
+	}
+
+	preBackupID, err := s.restoreBackupInternal(ctx, volumeName, backupID, stopContainers, stopped, user, progress)
+	if startErr := s.startVolumeContainersInternal(ctx, volumeName, stopped, user); startErr != nil {
+		err = errors.Join(err, startErr)
+	}
+	progress.finish(err)
+	if err != nil {
+		return nil, err
+	}
+	return &volumetypes.RestoreResult{
+		VolumeName:         volumeName,
+		BackupID:           backupID,
+		PreRestoreBackupID: preBackupID,
+		StoppedContainers:  stopped,
+	}, nil
+}
+
+// resumeRestoreInternal re-runs a restore from a stored backup that was cut
+// off by a restart. The restore replaces the whole volume, so running it again
+// is safe. Uploaded archives are gone once the stream breaks and cannot be
+// resumed; the pre-restore backup taken before the upload is the way back.
+// Containers stopped by the interrupted restore are already stopped by now, so
+// they are not started again afterwards.
+func (s *VolumeService) resumeRestoreInternal(ctx context.Context, op models.OperationState) error {
+	volumeName, _ := op.Metadata["volume"].(string)
+	backupID, _ := op.Metadata["backup_id"].(string)
+	if volumeName == "" || backupID == "" {
+		return ErrOperationNotResumable
+	}
+	stopContainers, _ := op.Metadata["stop_containers"].(bool)
+	_, err := s.RestoreBackup(ctx, volumeName, backupID, stopContainers, systemUser)
+	return err
+}
+
+// stopVolumeContainersInternal stops the running containers that use the
+// volume and returns their IDs. If one fails to stop, the ones already
+// stopped are started again.
+func (s *VolumeService) stopVolumeContainersInternal(ctx context.Context, volumeName string, user models.User) ([]string, error) {
+	if s.containerService == nil {
+		return nil, fmt.Errorf("container service not available")
+	}
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+
+	users, err := dockerClient.ContainerList(ctx, container.ListOptions{
+		All:     true,
+		Filters: filters.NewArgs(filters.Arg("volume", volumeName)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list containers using volume: %w", err)
+	}
+
+	stopped := make([]string, 0, len(users))
+	for _, c := range users {
+		if c.State != "running" && c.State != "restarting" {
+			continue
+		}
+		slog.InfoContext(ctx, "Stopping container for volume restore", "volume", volumeName, "container", containerDisplayName(c.Names, c.ID))
+		if err := s.containerService.StopContainer(ctx, c.ID, 0, user); err != nil {
+			stopErr := fmt.Errorf("failed to stop container %s before restore: %w", containerDisplayName(c.Names, c.ID), err)
+			return nil, errors.Join(stopErr, s.startVolumeContainersInternal(ctx, volumeName, stopped, user))
+		}
+		stopped = append(stopped, c.ID)
+	}
+	return stopped, nil
+}
+
+// startVolumeContainersInternal starts the containers stopped for a restore.
+// It does not stop at the first failure so as many as possible come back.
+func (s *VolumeService) startVolumeContainersInternal(ctx context.Context, volumeName string, containerIDs []string, user models.User) error {
+	if len(containerIDs) == 0 {
+		return nil
+	}
+	// Bring the containers back even if the client went away mid-restore.
+	ctx = context.WithoutCancel(ctx)
+
+	var errs []error
+	for _, id := range containerIDs {
+		slog.InfoContext(ctx, "Starting container after volume restore", "volume", volumeName, "container", id)
+		if err := s.containerService.StartContainer(ctx, id, user); err != nil {
+			errs = append(errs, fmt.Errorf("failed to start container %s after restore: %w", containerDisplayName(nil, id), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// restoreBackupInternal restores the backup into the volume and returns the
+// ID of the pre-restore backup. When the caller has stopped the containers
+// using the volume, skipUsageCheck lets the restore proceed even though
+// stopped containers still reference it.
+func (s *VolumeService) restoreBackupInternal(ctx context.Context, volumeName, backupID string, skipUsageCheck bool, stoppedContainers []string, user models.User, progress *backupProgressReporter) (string, error) {
 	var backup models.VolumeBackup
 	if err := s.db.WithContext(ctx).Where("id = ?", backupID).First(&backup).Error; err != nil {
-		return err
+		return "", err
 	}
 
 	// Validate backup belongs to volume
 	if backup.VolumeName != volumeName {
-		return fmt.Errorf("backup does not belong to volume %s", volumeName)
+		return "", fmt.Errorf("backup does not belong to volume %s", volumeName)
 	}
 
 	// Check if volume is in use by running containers
-	inUse, containerIDs, err := s.GetVolumeUsage(ctx, volumeName)
-	if err != nil {
-		slog.WarnContext(ctx, "could not check volume usage", "volume", volumeName, "error", err.Error())
-	} else if inUse {
-		return fmt.Errorf("volume is in use by %d container(s): restoring while containers are running may cause data corruption. Stop the containers first or use selective file restore", len(containerIDs))
+	if !skipUsageCheck {
+		inUse, containerIDs, err := s.GetVolumeUsage(ctx, volumeName)
+		if err != nil {
+			slog.WarnContext(ctx, "could not check volume usage", "volume", volumeName, "error", err.Error())
+		} else if inUse {
+			return "", fmt.Errorf("volume is in use by %d container(s): restoring while containers are running may cause data corruption. Stop the containers first, restore with stopContainers, or use selective file restore", len(containerIDs))
+		}
 	}
 
-	preBackup, err := s.CreateBackup(ctx, volumeName, user)
+	preBackup, err := s.createPreRestoreBackupInternal(ctx, volumeName, user)
 	if err != nil {
-		return fmt.Errorf("failed to create pre-restore backup: %w", err)
+		return "", fmt.Errorf("failed to create pre-restore backup: %w", err)
+	}
+
+	if backup.IsSnapshot() {
+		progress.setPhase(volumetypes.BackupPhaseExtracting)
+		if err := s.restoreSnapshotBackupInternal(ctx, volumeName, &backup); err != nil {
+			return "", err
+		}
+		s.logBackupRestoreEventInternal(ctx, volumeName, backupID, preBackup.ID, stoppedContainers, user)
+		return preBackup.ID, nil
 	}
 
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	filename := fmt.Sprintf("%s.tar.gz", backupID)
 
 	helperImage, err := s.getHelperImageInternal(ctx)
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	config := &container.Config{
 		Image: helperImage,
-		Cmd: []string{
-			"sh",
-			"-c",
-			fmt.Sprintf("set -e; tmp=$(mktemp -d /volume/.restore_tmp.XXXXXX); tar -tzf /backups/%s >/dev/null; tar -xzf /backups/%s -C \"$tmp\"; find /volume -mindepth 1 -maxdepth 1 -exec rm -rf -- {} +; find \"$tmp\" -mindepth 1 -maxdepth 1 -exec mv -- {} /volume/ \\;; rmdir \"$tmp\"", filename, filename),
-		},
+		Cmd:   []string{"sh", "-c", restoreArchiveScript, "sh", filename},
 		Labels: map[string]string{
 			libarcane.InternalContainerLabel: "true",
 		},
@@ -1058,41 +3596,53 @@ func (s *VolumeService) RestoreBackup(ctx context.Context, volumeName, backupID
 			fmt.Sprintf("%s:/backups:ro", s.backupVolumeName),
 		},
 		AutoRemove: true,
+		Resources:  s.helperResourcesInternal(),
 	}
 
 	resp, err := dockerClient.ContainerCreate(ctx, config, hostConfig, nil, nil, "")
 	if err != nil {
-		return fmt.Errorf("failed to create restore container: %w", err)
+		return "", fmt.Errorf("failed to create restore container: %w", err)
 	}
 
-	if err := dockerClient.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
-		return fmt.Errorf("failed to start restore container: %w", err)
+	waitBody, err := s.runHelperWithProgressInternal(ctx, dockerClient, resp.ID, progress)
+	if err != nil {
+		return "", fmt.Errorf("failed to run restore container: %w", err)
 	}
 
-	statusCh, errCh := dockerClient.ContainerWait(ctx, resp.ID, container.WaitConditionNotRunning)
-	var waitBody container.WaitResponse
-	select {
-	case err := <-errCh:
-		if err != nil {
-			return err
-		}
-	case waitBody = <-statusCh:
+	if err := restoreExitErrorInternal(waitBody.StatusCode, preBackup.ID); err != nil {
+		return "", err
 	}
 
-	if waitBody.StatusCode != 0 {
-		return fmt.Errorf("restore container exited with code %d (volume may be partially wiped)", waitBody.StatusCode)
+	s.logBackupRestoreEventInternal(ctx, volumeName, backupID, preBackup.ID, stoppedContainers, user)
+	return preBackup.ID, nil
+}
+
+// restoreExitErrorInternal maps the exit code of a restore helper to an
+// error. Failures before the swap leave the volume untouched, just like a
+// swap that was rolled back.
+func restoreExitErrorInternal(exitCode int64, preBackupID string) error {
+	switch exitCode {
+	case 0:
+		return nil
+	case restoreExitIncomplete:
+		return fmt.Errorf("%w: the previous contents are kept in a .restore_old directory inside the volume and in pre-restore backup %s", ErrRestoreIncomplete, preBackupID)
+	default:
+		return fmt.Errorf("%w (restore helper exited with code %d)", ErrRestoreVolumeUnchanged, exitCode)
 	}
+}
 
+func (s *VolumeService) logBackupRestoreEventInternal(ctx context.Context, volumeName, backupID, preBackupID string, stoppedContainers []string, user models.User) {
 	metadata := models.JSON{
 		"action":               "backup_restore",
 		"backup_id":            backupID,
-		"pre_restore_backupId": preBackup.ID,
+		"pre_restore_backupId": preBackupID,
+	}
+	if len(stoppedContainers) > 0 {
+		metadata["stopped_containers"] = stoppedContainers
 	}
 	if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeBackupRestore, volumeName, volumeName, user.ID, user.Username, "0", metadata); logErr != nil {
 		slog.WarnContext(ctx, "could not log volume backup restore event", "volume", volumeName, "error", logErr.Error())
 	}
-
-	return nil
 }
 
 func (s *VolumeService) sanitizeBackupPathInternal(input string) (string, error) {
@@ -1147,10 +3697,13 @@ func (s *VolumeService) BackupHasPath(ctx context.Context, backupID string, file
 		return false, err
 	}
 
-	var backup models.VolumeBackup
-	if err := s.db.WithContext(ctx).Where("id = ?", backupID).First(&backup).Error; err != nil {
+	backup, err := s.getBackupInternal(ctx, backupID)
+	if err != nil {
 		return false, err
 	}
+	if backup.IsSnapshot() {
+		return false, ErrSnapshotBackupUnsupported
+	}
 
 	containerID, cleanup, err := s.createTempContainerInternal(ctx, s.backupVolumeName, true)
 	if err != nil {
@@ -1160,7 +3713,7 @@ func (s *VolumeService) BackupHasPath(ctx context.Context, backupID string, file
 
 	archivePath := path.Join("/volume", fmt.Sprintf("%s.tar.gz", backupID))
 	cmd := []string{"tar", "-tzf", archivePath}
-	stdout, stderr, err := s.execInContainerInternal(ctx, containerID, cmd)
+	stdout, stderr, err := s.execInContainerInternal(ctx, containerID, helperExecScan, cmd)
 	if err != nil {
 		return false, err
 	}
@@ -1188,10 +3741,13 @@ func (s *VolumeService) ListBackupFiles(ctx context.Context, backupID string) ([
 		return nil, err
 	}
 
-	var backup models.VolumeBackup
-	if err := s.db.WithContext(ctx).Where("id = ?", backupID).First(&backup).Error; err != nil {
+	backup, err := s.getBackupInternal(ctx, backupID)
+	if err != nil {
 		return nil, err
 	}
+	if backup.IsSnapshot() {
+		return nil, ErrSnapshotBackupUnsupported
+	}
 
 	containerID, cleanup, err := s.createTempContainerInternal(ctx, s.backupVolumeName, true)
 	if err != nil {
@@ -1201,7 +3757,7 @@ func (s *VolumeService) ListBackupFiles(ctx context.Context, backupID string) ([
 
 	archivePath := path.Join("/volume", fmt.Sprintf("%s.tar.gz", backupID))
 	cmd := []string{"tar", "-tzf", archivePath}
-	stdout, _, err := s.execInContainerInternal(ctx, containerID, cmd)
+	stdout, _, err := s.execInContainerInternal(ctx, containerID, helperExecScan, cmd)
 	if err != nil {
 		return nil, err
 	}
@@ -1241,9 +3797,20 @@ func (s *VolumeService) RestoreBackupFiles(ctx context.Context, volumeName, back
 	if backup.VolumeName != volumeName {
 		return fmt.Errorf("backup does not belong to volume")
 	}
+	if backup.IsSnapshot() {
+		return ErrSnapshotBackupUnsupported
+	}
+
+	// Tracked rather than begun: the restore resumer would replay a partial
+	// restore as a full one.
+	done, err := s.operationService.Track(models.OperationKindVolumeRestore, volumeName)
+	if err != nil {
+		return err
+	}
+	defer done()
 
 	// Create pre-restore backup for safety (consistent with RestoreBackup behavior)
-	preBackup, err := s.CreateBackup(ctx, volumeName, user)
+	preBackup, err := s.createPreRestoreBackupInternal(ctx, volumeName, user)
 	if err != nil {
 		return fmt.Errorf("failed to create pre-restore backup: %w", err)
 	}
@@ -1291,6 +3858,7 @@ func (s *VolumeService) RestoreBackupFiles(ctx context.Context, volumeName, back
 			fmt.Sprintf("%s:/backups:ro", s.backupVolumeName),
 		},
 		AutoRemove: true,
+		Resources:  s.helperResourcesInternal(),
 	}
 
 	resp, err := dockerClient.ContainerCreate(ctx, config, hostConfig, nil, nil, "")
@@ -1310,7 +3878,7 @@ func (s *VolumeService) RestoreBackupFiles(ctx context.Context, volumeName, back
 
 	filename := fmt.Sprintf("%s.tar.gz", backupID)
 	cmd := append([]string{"tar", "-xzf", path.Join("/backups", filename), "-C", "/volume", "--"}, tarPaths...)
-	_, stderr, err := s.execInContainerInternal(ctx, resp.ID, cmd)
+	_, stderr, err := s.execInContainerInternal(ctx, resp.ID, helperExecArchive, cmd)
 	if err != nil {
 		return fmt.Errorf("failed to restore files: %w", err)
 	}
@@ -1337,6 +3905,18 @@ func (s *VolumeService) RestoreBackupFiles(ctx context.Context, volumeName, back
 
 func (s *VolumeService) DownloadBackup(ctx context.Context, backupID string, user *models.User) (io.ReadCloser, int64, error) {
 	slog.DebugContext(ctx, "volume service: download backup", "backup_id", backupID)
+	volumeName := ""
+	backup, err := s.getBackupInternal(ctx, backupID)
+	switch {
+	case err == nil:
+		if backup.IsSnapshot() {
+			return nil, 0, ErrSnapshotBackupUnsupported
+		}
+		volumeName = backup.VolumeName
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, 0, err
+	}
+
 	filename := fmt.Sprintf("%s.tar.gz", backupID)
 	reader, size, err := s.DownloadFile(ctx, s.backupVolumeName, filename)
 	if err != nil {
@@ -1347,11 +3927,6 @@ func (s *VolumeService) DownloadBackup(ctx context.Context, backupID string, use
 	if actingUser == nil {
 		actingUser = &systemUser
 	}
-	volumeName := ""
-	var backup models.VolumeBackup
-	if err := s.db.WithContext(ctx).Where("id = ?", backupID).First(&backup).Error; err == nil {
-		volumeName = backup.VolumeName
-	}
 	if volumeName != "" {
 		metadata := models.JSON{
 			"action":    "backup_download",
@@ -1366,89 +3941,193 @@ func (s *VolumeService) DownloadBackup(ctx context.Context, backupID string, use
 	return reader, size, nil
 }
 
-func (s *VolumeService) UploadAndRestore(ctx context.Context, volumeName string, archive io.Reader, filename string, user models.User) error {
-	slog.DebugContext(ctx, "volume service: upload and restore", "volume", volumeName, "filename", filename, "user", user.ID)
+// BackupDownload is an open backup archive, or the requested part of it.
+type BackupDownload struct {
+	Body io.ReadCloser
+	// Size is the size of the whole archive.
+	Size int64
+	// Range is the part being served, or nil for the whole archive.
+	Range *httputils.ByteRange
+	// ETag identifies the archive so a resumed download can check that it
+	// did not change. Backups are never rewritten, so the ID and size suffice.
+	ETag string
+}
 
-	tmpFile, err := os.CreateTemp("", "arcane-restore-*.tar.gz")
+// OpenBackupDownload opens a backup for download, honoring a Range header so
+// interrupted downloads can resume. ifRange, when set, must match the ETag or
+// the whole archive is served. The archive is streamed out of the backup
+// volume, so the skipped prefix is still read but not sent.
+func (s *VolumeService) OpenBackupDownload(ctx context.Context, backupID, rangeHeader, ifRange string, user *models.User) (*BackupDownload, error) {
+	reader, size, err := s.DownloadBackup(ctx, backupID, user)
 	if err != nil {
-		return fmt.Errorf("failed to buffer upload: %w", err)
+		return nil, err
 	}
-	defer func() {
-		_ = tmpFile.Close()
-		_ = os.Remove(tmpFile.Name())
-	}()
-	if _, err := io.Copy(tmpFile, archive); err != nil {
-		return fmt.Errorf("failed to buffer upload: %w", err)
+
+	dl := &BackupDownload{Body: reader, Size: size, ETag: fmt.Sprintf("%q", fmt.Sprintf("%s-%d", backupID, size))}
+	if rangeHeader == "" || (ifRange != "" && ifRange != dl.ETag) {
+		return dl, nil
+	}
+
+	byteRange, err := httputils.ParseRange(rangeHeader, size)
+	if err != nil {
+		_ = reader.Close()
+		return nil, err
+	}
+	if byteRange == nil {
+		return dl, nil
+	}
+
+	if _, err := io.CopyN(io.Discard, reader, byteRange.Start); err != nil {
+		_ = reader.Close()
+		return nil, fmt.Errorf("failed to seek backup archive: %w", err)
 	}
-	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
-		return fmt.Errorf("failed to read buffered upload: %w", err)
+	dl.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.LimitReader(reader, byteRange.Length()), reader}
+	dl.Range = byteRange
+	return dl, nil
+}
+
+var (
+	// ErrBackupUploadTooLarge is returned when an uploaded backup exceeds the upload size limit.
+	ErrBackupUploadTooLarge = errors.New("backup archive exceeds the maximum upload size")
+	// ErrInvalidBackupArchive is returned when an uploaded backup is not a valid tar.gz archive.
+	ErrInvalidBackupArchive = errors.New("invalid archive")
+	// ErrRestoreVolumeUnchanged is returned when a restore failed before the
+	// volume contents were replaced, or while replacing them and the previous
+	// contents were put back.
+	ErrRestoreVolumeUnchanged = errors.New("restore failed, the volume was left unchanged")
+	// ErrRestoreIncomplete is returned when a restore failed while replacing
+	// the volume contents and could not be rolled back.
+	ErrRestoreIncomplete = errors.New("restore failed while replacing the volume contents and could not be rolled back")
+)
+
+// backupPeekSize is how much of an uploaded archive is inspected before the
+// restore starts; it comfortably covers the gzip header and first tar header.
+const backupPeekSize = 64 * 1024
+
+// UploadAndRestore restores a volume from an uploaded tar.gz archive. The
+// archive is streamed straight into the helper container while a tee
+// validates the gzip and tar structure, so it is never written to a host
+// temp file. size is the declared upload size, or -1 when unknown; uploads
+// larger than the maxBackupUploadSize setting are rejected.
+func (s *VolumeService) UploadAndRestore(ctx context.Context, volumeName string, archive io.Reader, size int64, filename string, user models.User) (*volumetypes.RestoreResult, error) {
+	slog.DebugContext(ctx, "volume service: upload and restore", "volume", volumeName, "filename", filename, "size", size, "user", user.ID)
+
+	done, err := s.operationService.Begin(ctx, models.OperationKindVolumeRestore, volumeName, models.JSON{"volume": volumeName, "filename": filename})
+	if err != nil {
+		return nil, err
 	}
-	gzr, err := gzip.NewReader(tmpFile)
+	defer done()
+
+	progress := s.newBackupProgressInternal(volumetypes.BackupOperationRestore, volumeName, "")
+	preBackupID, err := s.uploadAndRestoreInternal(ctx, volumeName, archive, size, filename, user, progress)
+	progress.finish(err)
 	if err != nil {
-		return fmt.Errorf("invalid archive: %w", err)
+		return nil, err
 	}
-	if _, err := tar.NewReader(gzr).Next(); err != nil {
-		_ = gzr.Close()
-		return fmt.Errorf("invalid archive: %w", err)
+	return &volumetypes.RestoreResult{VolumeName: volumeName, PreRestoreBackupID: preBackupID}, nil
+}
+
+func (s *VolumeService) uploadAndRestoreInternal(ctx context.Context, volumeName string, archive io.Reader, size int64, filename string, user models.User, progress *backupProgressReporter) (string, error) {
+	if s.settingsService != nil {
+		maxSizeMB := s.settingsService.GetIntSetting(ctx, "maxBackupUploadSize", 10240)
+		if maxSizeMB > 0 {
+			maxBytes := int64(maxSizeMB) * 1024 * 1024
+			if size > maxBytes {
+				return "", fmt.Errorf("%w of %d MB", ErrBackupUploadTooLarge, maxSizeMB)
+			}
+			archive = &maxSizeReader{r: archive, max: maxBytes}
+		}
 	}
-	_ = gzr.Close()
 
-	preBackup, err := s.CreateBackup(ctx, volumeName, user)
+	// Reject obviously invalid uploads before touching the volume
+	br := bufio.NewReaderSize(archive, backupPeekSize)
+	head, err := br.Peek(backupPeekSize)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, bufio.ErrBufferFull) {
+		return "", fmt.Errorf("failed to read upload: %w", err)
+	}
+	if err := validateBackupArchiveHeaderInternal(head); err != nil {
+		return "", err
+	}
+
+	preBackup, err := s.createPreRestoreBackupInternal(ctx, volumeName, user)
 	if err != nil {
-		return fmt.Errorf("failed to create pre-restore backup: %w", err)
+		return "", fmt.Errorf("failed to create pre-restore backup: %w", err)
 	}
 
 	dockerClient, err := s.dockerService.GetClient()
 	if err != nil {
-		return err
+		return "", err
 	}
 
 	containerID, cleanup, err := s.createTempContainerInternal(ctx, volumeName, false)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer cleanup()
 
 	tmpDir := fmt.Sprintf("/volume/.restore_tmp_%d", time.Now().UnixNano())
-	_, stderr, err := s.execInContainerInternal(ctx, containerID, []string{"mkdir", "-p", tmpDir})
-	if err != nil {
-		return fmt.Errorf("failed to create temp restore dir: %w", err)
+	if err := s.mkdirAllInternal(ctx, containerID, tmpDir); err != nil {
+		return "", fmt.Errorf("failed to create temp restore dir: %w", err)
 	}
-	if strings.TrimSpace(stderr) != "" {
-		slog.DebugContext(ctx, "volume service: restore temp dir stderr", "volume", volumeName, "stderr", strings.TrimSpace(stderr))
+
+	if size > 0 {
+		progress.setTotals(size, 0)
 	}
+	progress.setPhase(volumetypes.BackupPhaseUploading)
+
+	// Docker extracts the gzip stream while the tee validates it in full
+	pr, pw := io.Pipe()
+	validateErr := make(chan error, 1)
+	go func() {
+		counted := &progressCountingReader{r: br, progress: progress}
+		err := validateBackupArchiveInternal(io.TeeReader(counted, pw))
+		pw.CloseWithError(err)
+		validateErr <- err
+	}()
 
-	if _, err := tmpFile.Seek(0, io.SeekStart); err != nil {
-		return fmt.Errorf("failed to read buffered upload: %w", err)
+	err = dockerClient.CopyToContainer(ctx, containerID, tmpDir, pr, container.CopyToContainerOptions{})
+	_ = pr.Close()
+	// Prefer the validation error unless it only reflects Docker hanging up
+	if vErr := <-validateErr; vErr != nil && (err == nil || !errors.Is(vErr, io.ErrClosedPipe)) {
+		err = vErr
 	}
-	err = dockerClient.CopyToContainer(ctx, containerID, tmpDir, tmpFile, container.CopyToContainerOptions{})
 	if err != nil {
-		return fmt.Errorf("failed to restore from uploaded archive: %w", err)
+		s.removeRestoreTmpDirInternal(ctx, containerID, tmpDir)
+		if errors.Is(err, ErrBackupUploadTooLarge) || errors.Is(err, ErrInvalidBackupArchive) {
+			return "", err
+		}
+		return "", fmt.Errorf("%w: failed to restore from uploaded archive: %w", ErrRestoreVolumeUnchanged, err)
 	}
 
-	_, stderr, err = s.execInContainerInternal(ctx, containerID, []string{"sh", "-c", fmt.Sprintf("test -n \"$(find %s -mindepth 1 -maxdepth 1 -print -quit)\"", tmpDir)})
-	if err != nil {
-		return fmt.Errorf("uploaded archive appears empty or invalid: %w", err)
-	}
-	if strings.TrimSpace(stderr) != "" {
-		slog.DebugContext(ctx, "volume service: restore validate stderr", "volume", volumeName, "stderr", strings.TrimSpace(stderr))
+	if hasEntries, err := s.dirHasEntriesInternal(ctx, containerID, tmpDir); err != nil || !hasEntries {
+		s.removeRestoreTmpDirInternal(ctx, containerID, tmpDir)
+		if err == nil {
+			err = errors.New("no files extracted")
+		}
+		return "", fmt.Errorf("uploaded archive appears empty or invalid: %w", err)
 	}
 
-	_, stderr, err = s.execInContainerInternal(ctx, containerID, []string{"sh", "-c", "rm -rf /volume/* /volume/.[!.]* /volume/..?* 2>/dev/null || true"})
-	if err != nil {
-		return fmt.Errorf("failed to clear volume before restore: %w", err)
-	}
+	progress.setPhase(volumetypes.BackupPhaseReplacing)
+	script := "set -e\nroot=/volume\ntmp=$1\n" + restoreSwapScript
+	stdout, stderr, err := s.execInContainerInternal(ctx, containerID, helperExecArchive, []string{"sh", "-c", script, "sh", tmpDir})
 	if strings.TrimSpace(stderr) != "" {
-		slog.DebugContext(ctx, "volume service: restore clear stderr", "volume", volumeName, "stderr", strings.TrimSpace(stderr))
+		slog.DebugContext(ctx, "volume service: restore swap stderr", "volume", volumeName, "stderr", strings.TrimSpace(stderr))
 	}
-
-	moveCmd := fmt.Sprintf("find %s -mindepth 1 -maxdepth 1 -exec mv -- {} /volume/ \\; && rmdir %s", tmpDir, tmpDir)
-	_, stderr, err = s.execInContainerInternal(ctx, containerID, []string{"sh", "-c", moveCmd})
 	if err != nil {
-		return fmt.Errorf("failed to move restored files into place: %w", err)
+		return "", fmt.Errorf("failed to swap restored files into place: %w", err)
 	}
-	if strings.TrimSpace(stderr) != "" {
-		slog.DebugContext(ctx, "volume service: restore move stderr", "volume", volumeName, "stderr", strings.TrimSpace(stderr))
+	switch result := restoreSwapResultInternal(stdout); result {
+	case "swapped":
+	case "rolled_back":
+		s.removeRestoreTmpDirInternal(ctx, containerID, tmpDir)
+		return "", fmt.Errorf("%w: %s", ErrRestoreVolumeUnchanged, strings.TrimSpace(stderr))
+	case "incomplete":
+		return "", restoreExitErrorInternal(restoreExitIncomplete, preBackup.ID)
+	default:
+		return "", fmt.Errorf("failed to swap restored files into place: %s", strings.TrimSpace(stderr))
 	}
 
 	metadata := models.JSON{
@@ -1460,9 +4139,110 @@ func (s *VolumeService) UploadAndRestore(ctx context.Context, volumeName string,
 		slog.WarnContext(ctx, "could not log volume backup upload restore event", "volume", volumeName, "error", logErr.Error())
 	}
 
+	return preBackup.ID, nil
+}
+
+// restoreSwapResultInternal returns the outcome printed by restoreSwapScript,
+// or "" when it printed none.
+func restoreSwapResultInternal(output string) string {
+	result := ""
+	for line := range strings.Lines(output) {
+		if rest, ok := strings.CutPrefix(strings.TrimSpace(line), "result "); ok {
+			result, _, _ = strings.Cut(rest, " ")
+		}
+	}
+	return result
+}
+
+func (s *VolumeService) removeRestoreTmpDirInternal(ctx context.Context, containerID, tmpDir string) {
+	if _, _, err := s.execInContainerInternal(ctx, containerID, helperExecScan, []string{"rm", "-rf", tmpDir}); err != nil {
+		slog.WarnContext(ctx, "volume service: failed to remove restore temp dir", "dir", tmpDir, "error", err)
+	}
+}
+
+// validateBackupArchiveHeaderInternal checks that head, the first bytes of an
+// upload, starts a gzip stream containing a tar header.
+func validateBackupArchiveHeaderInternal(head []byte) error {
+	gzr, err := gzip.NewReader(bytes.NewReader(head))
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBackupArchive, err)
+	}
+	defer gzr.Close()
+	if _, err := tar.NewReader(gzr).Next(); err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBackupArchive, err)
+	}
+	return nil
+}
+
+// validateBackupArchiveInternal reads r to the end, verifying the gzip
+// checksum and every tar entry along the way.
+func validateBackupArchiveInternal(r io.Reader) error {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidBackupArchive, err)
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		_, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return archiveReadErrorInternal(err)
+		}
+		if _, err := io.Copy(io.Discard, tr); err != nil {
+			return archiveReadErrorInternal(err)
+		}
+	}
+
+	// Drain any padding after the tar trailer so the whole upload is consumed
+	if _, err := io.Copy(io.Discard, gzr); err != nil {
+		return archiveReadErrorInternal(err)
+	}
 	return nil
 }
 
+func archiveReadErrorInternal(err error) error {
+	if errors.Is(err, ErrBackupUploadTooLarge) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrInvalidBackupArchive, err)
+}
+
+// progressCountingReader reports the number of bytes read through it as
+// upload progress.
+type progressCountingReader struct {
+	r        io.Reader
+	n        int64
+	progress *backupProgressReporter
+}
+
+func (c *progressCountingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	c.progress.setProgress(c.n, -1)
+	return n, err
+}
+
+// maxSizeReader fails with ErrBackupUploadTooLarge once more than max bytes
+// have been read.
+type maxSizeReader struct {
+	r   io.Reader
+	n   int64
+	max int64
+}
+
+func (m *maxSizeReader) Read(p []byte) (int, error) {
+	n, err := m.r.Read(p)
+	m.n += int64(n)
+	if m.n > m.max {
+		return n, fmt.Errorf("%w of %d MB", ErrBackupUploadTooLarge, m.max/(1024*1024))
+	}
+	return n, err
+}
+
 func (s *VolumeService) GetVolumeUsage(ctx context.Context, name string) (bool, []string, error) {
 	slog.DebugContext(ctx, "volume service: get volume usage", "volume", name)
 	dockerClient, err := s.dockerService.GetClient()
@@ -1757,6 +4537,7 @@ func (s *VolumeService) ListVolumesPaginated(ctx context.Context, params paginat
 	}
 
 	volumes := s.enrichVolumesWithUsageDataInternal(volResult.volumes, usageVolumes)
+	volumes = filterByNamespaceInternal(ctx, s.namespaceService, volumes, func(v volume.Volume) map[string]string { return v.Labels })
 
 	items := make([]volumetypes.Volume, 0, len(volumes))
 	for _, v := range volumes {
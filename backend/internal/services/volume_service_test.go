@@ -0,0 +1,692 @@
+package services
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/client"
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	dockerutil "github.com/getarcaneapp/arcane/backend/internal/utils/docker"
+	volumetypes "github.com/getarcaneapp/arcane/types/volume"
+)
+
+func TestVolumeService_BulkVolumeOperation_ValidatesRequest(t *testing.T) {
+	svc := NewVolumeService(nil, nil, nil, nil, nil, nil, nil, nil, "")
+	ctx := context.Background()
+
+	tests := []struct {
+		name string
+		req  volumetypes.BulkRequest
+	}{
+		{
+			name: "unsupported action",
+			req:  volumetypes.BulkRequest{Action: "explode", Names: []string{"data"}},
+		},
+		{
+			name: "label without labels",
+			req:  volumetypes.BulkRequest{Action: volumetypes.BulkActionLabel, Names: []string{"data"}},
+		},
+		{
+			name: "only blank names",
+			req:  volumetypes.BulkRequest{Action: volumetypes.BulkActionDelete, Names: []string{"", "  "}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := svc.BulkVolumeOperation(ctx, tt.req, systemUser)
+			require.Error(t, err)
+			require.Nil(t, result)
+		})
+	}
+}
+
+func TestVolumeService_CopyPath_RejectsInvalidPaths(t *testing.T) {
+	svc := NewVolumeService(nil, nil, nil, nil, nil, nil, nil, nil, "")
+	ctx := context.Background()
+
+	tests := []struct {
+		name      string
+		srcVolume string
+		srcPath   string
+		dstVolume string
+		dstPath   string
+	}{
+		{name: "same path", srcVolume: "data", srcPath: "/a", dstPath: "/a"},
+		{name: "into itself", srcVolume: "data", srcPath: "/a", dstPath: "/a/b"},
+		{name: "root into subdir", srcVolume: "data", srcPath: "/", dstPath: "/copy"},
+		{name: "destination root", srcVolume: "data", srcPath: "/a", dstVolume: "other", dstPath: "/"},
+		{name: "host path destination", srcVolume: "data", srcPath: "/a", dstVolume: "/etc", dstPath: "/b"},
+		{name: "host root destination", srcVolume: "data", srcPath: "/a", dstVolume: "/", dstPath: "/b"},
+		{name: "bind syntax destination", srcVolume: "data", srcPath: "/a", dstVolume: "other:/x", dstPath: "/b"},
+		{name: "host path source", srcVolume: "/var/lib", srcPath: "/a", dstVolume: "other", dstPath: "/b"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := svc.CopyPath(ctx, tt.srcVolume, tt.srcPath, tt.dstVolume, tt.dstPath, false, nil)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestCopyVolumeScript(t *testing.T) {
+	// busybox ash in the helper image supports pipefail; dash does not.
+	shell, err := exec.LookPath("bash")
+	if err != nil {
+		t.Skip("bash is not available")
+	}
+	realTar, err := exec.LookPath("tar")
+	if err != nil {
+		t.Skip("tar is not available")
+	}
+
+	// The fake tar fails or truncates the creating side of the pipe while the
+	// extracting side succeeds.
+	binDir := t.TempDir()
+	fakeTar := `#!/bin/sh
+if [ "$1" = "-cf" ]; then
+  case "$FAKE_TAR_MODE" in
+    fail) "$REAL_TAR" "$@"; exit 2 ;;
+    partial) exec "$REAL_TAR" -cf - -C "$4" ./a ;;
+  esac
+fi
+exec "$REAL_TAR" "$@"
+`
+	require.NoError(t, os.WriteFile(filepath.Join(binDir, "tar"), []byte(fakeTar), 0o700)) //nolint:gosec // test shim must be executable
+
+	run := func(t *testing.T, mode string) (string, error) {
+		src := t.TempDir()
+		dst := t.TempDir()
+		require.NoError(t, os.WriteFile(filepath.Join(src, "a"), []byte("a"), 0o600))
+		require.NoError(t, os.MkdirAll(filepath.Join(src, "dir"), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(src, "dir", "b"), []byte("b"), 0o600))
+
+		cmd := exec.Command(shell, "-c", copyVolumeScript, "sh", src, dst)
+		cmd.Env = append(os.Environ(),
+			"PATH="+binDir+string(os.PathListSeparator)+os.Getenv("PATH"),
+			"REAL_TAR="+realTar,
+			"FAKE_TAR_MODE="+mode,
+		)
+		return dst, cmd.Run()
+	}
+
+	t.Run("copies everything", func(t *testing.T) {
+		dst, err := run(t, "")
+		require.NoError(t, err)
+		data, err := os.ReadFile(filepath.Join(dst, "dir", "b"))
+		require.NoError(t, err)
+		assert.Equal(t, "b", string(data))
+	})
+
+	t.Run("fails when the source tar fails", func(t *testing.T) {
+		_, err := run(t, "fail")
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 2, exitErr.ExitCode())
+	})
+
+	t.Run("fails when entries are missing", func(t *testing.T) {
+		_, err := run(t, "partial")
+		var exitErr *exec.ExitError
+		require.ErrorAs(t, err, &exitErr)
+		assert.Equal(t, 4, exitErr.ExitCode())
+	})
+}
+
+func TestWriteDirectoryArchive(t *testing.T) {
+	var src bytes.Buffer
+	tw := tar.NewWriter(&src)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "data/", Typeflag: tar.TypeDir, Mode: 0o755}))
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "data/a.txt", Typeflag: tar.TypeReg, Mode: 0o644, Size: 5}))
+	_, err := tw.Write([]byte("hello"))
+	require.NoError(t, err)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "data/link", Typeflag: tar.TypeSymlink, Linkname: "a.txt"}))
+	require.NoError(t, tw.Close())
+
+	t.Run("tar.gz", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, writeDirectoryArchive(&out, bytes.NewReader(src.Bytes()), DirectoryArchiveTarGz, "export"))
+
+		gr, err := gzip.NewReader(&out)
+		require.NoError(t, err)
+		tr := tar.NewReader(gr)
+		var names []string
+		for {
+			hdr, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			require.NoError(t, err)
+			names = append(names, hdr.Name)
+		}
+		require.Equal(t, []string{"export/", "export/a.txt", "export/link"}, names)
+	})
+
+	t.Run("zip", func(t *testing.T) {
+		var out bytes.Buffer
+		require.NoError(t, writeDirectoryArchive(&out, bytes.NewReader(src.Bytes()), DirectoryArchiveZip, "export"))
+
+		zr, err := zip.NewReader(bytes.NewReader(out.Bytes()), int64(out.Len()))
+		require.NoError(t, err)
+		require.Len(t, zr.File, 3)
+		require.Equal(t, "export/", zr.File[0].Name)
+		require.Equal(t, "export/a.txt", zr.File[1].Name)
+
+		rc, err := zr.File[1].Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(rc)
+		require.NoError(t, err)
+		require.NoError(t, rc.Close())
+		require.Equal(t, "hello", string(content))
+	})
+}
+
+func TestVolumeService_FileAttributes_RejectInvalidInput(t *testing.T) {
+	svc := NewVolumeService(nil, nil, nil, nil, nil, nil, nil, nil, "")
+	ctx := context.Background()
+
+	require.Error(t, svc.ChangeOwnership(ctx, "data", volumetypes.ChangeOwnershipRequest{Path: "/app"}, nil))
+
+	negative := -1
+	require.Error(t, svc.ChangeOwnership(ctx, "data", volumetypes.ChangeOwnershipRequest{Path: "/app", UID: &negative}, nil))
+
+	for _, mode := range []string{"", "abc", "999", "17777"} {
+		require.Error(t, svc.ChangePermissions(ctx, "data", volumetypes.ChangePermissionsRequest{Path: "/app", Mode: mode}, nil), mode)
+	}
+}
+
+func TestParseDiskUsage(t *testing.T) {
+	output := "4\t/volume/data/a.txt\n" +
+		"8\t/volume/data/logs/old.log\n" +
+		"12\t/volume/data/logs\n" +
+		"20\t/volume/data\n" +
+		"100\t/volume/big.bin\n" +
+		"124\t/volume\n" +
+		"--\n" +
+		"/volume/data\n" +
+		"/volume/data/logs\n"
+
+	root, err := parseDiskUsage(output, "/volume")
+	require.NoError(t, err)
+
+	assert.Equal(t, "/", root.Path)
+	assert.Equal(t, int64(124*1024), root.Size)
+	require.Len(t, root.Children, 2)
+
+	assert.Equal(t, "big.bin", root.Children[0].Name)
+	assert.False(t, root.Children[0].IsDirectory)
+
+	data := root.Children[1]
+	assert.Equal(t, "/data", data.Path)
+	assert.True(t, data.IsDirectory)
+	require.Len(t, data.Children, 2)
+	assert.Equal(t, "logs", data.Children[0].Name)
+	require.Len(t, data.Children[0].Children, 1)
+	assert.Equal(t, "/data/logs/old.log", data.Children[0].Children[0].Path)
+
+	_, err = parseDiskUsage("--\n", "/volume")
+	require.Error(t, err)
+}
+
+func TestWriteTarFileInternal(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, writeTarFileInternal(&buf, "hello.txt", 5, bytes.NewReader([]byte("hello"))))
+
+	tr := tar.NewReader(&buf)
+	hdr, err := tr.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "hello.txt", hdr.Name)
+	assert.Equal(t, int64(5), hdr.Size)
+	data, err := io.ReadAll(tr)
+	require.NoError(t, err)
+	assert.Equal(t, "hello", string(data))
+	_, err = tr.Next()
+	assert.ErrorIs(t, err, io.EOF)
+
+	err = writeTarFileInternal(io.Discard, "short.txt", 10, bytes.NewReader([]byte("hello")))
+	require.Error(t, err)
+
+	err = writeTarFileInternal(io.Discard, "long.txt", 3, bytes.NewReader([]byte("hello")))
+	require.Error(t, err)
+}
+
+func TestValidateBackupArchive(t *testing.T) {
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	require.NoError(t, tw.WriteHeader(&tar.Header{Name: "data.txt", Mode: 0644, Size: 4}))
+	_, err := tw.Write([]byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gzw.Close())
+	archive := buf.Bytes()
+
+	require.NoError(t, validateBackupArchiveHeaderInternal(archive))
+	require.NoError(t, validateBackupArchiveInternal(bytes.NewReader(archive)))
+
+	assert.ErrorIs(t, validateBackupArchiveHeaderInternal([]byte("not an archive")), ErrInvalidBackupArchive)
+	assert.ErrorIs(t, validateBackupArchiveInternal(bytes.NewReader(archive[:len(archive)-8])), ErrInvalidBackupArchive)
+
+	limited := &maxSizeReader{r: bytes.NewReader(archive), max: int64(len(archive) - 1)}
+	assert.ErrorIs(t, validateBackupArchiveInternal(limited), ErrBackupUploadTooLarge)
+}
+
+func TestBackupProgressReporter_HandleLine(t *testing.T) {
+	var events []volumetypes.BackupProgress
+	r := &backupProgressReporter{
+		publish: func(p volumetypes.BackupProgress) { events = append(events, p) },
+		state:   volumetypes.BackupProgress{Operation: volumetypes.BackupOperationCreate, VolumeName: "data"},
+	}
+
+	r.handleLine("phase scanning")
+	r.handleLine("total 2048 4")
+	r.handleLine("phase archiving")
+	r.handleLine("progress 2 1024")
+	r.handleLine("progress 3")
+	r.handleLine("progress 4 999999")
+	r.handleLine("garbage")
+
+	state := r.state
+	assert.Equal(t, volumetypes.BackupPhaseArchiving, state.Phase)
+	assert.Equal(t, int64(2048), state.TotalBytes)
+	assert.Equal(t, int64(4), state.TotalFiles)
+	assert.Equal(t, int64(2048), state.BytesProcessed, "bytes are clamped to the total")
+	assert.Equal(t, int64(4), state.FilesWritten)
+	require.NotEmpty(t, events)
+	assert.Equal(t, volumetypes.BackupPhaseScanning, events[0].Phase)
+
+	r.finish(errors.New("boom"))
+	last := events[len(events)-1]
+	assert.Equal(t, volumetypes.BackupPhaseFailed, last.Phase)
+	assert.Equal(t, "boom", last.Error)
+}
+
+func TestParseBackupArchiveName(t *testing.T) {
+	volumeName, createdAt, ok := parseBackupArchiveNameInternal("my-app_data-1760659200000000000-1a2b3c4d.tar.gz")
+	require.True(t, ok)
+	assert.Equal(t, "my-app_data", volumeName)
+	assert.Equal(t, int64(1760659200), createdAt.Unix())
+
+	for _, name := range []string{
+		"backup.tar.gz",
+		"data-1760659200000000000.tar.gz",
+		"data-1760659200000000000-1A2B3C4D.tar.gz",
+		"data-1760659200000000000-1a2b3c4d.tar",
+		"-1760659200000000000-1a2b3c4d.tar.gz",
+	} {
+		_, _, ok := parseBackupArchiveNameInternal(name)
+		assert.False(t, ok, name)
+	}
+}
+
+// newFakeDockerVolumeService returns a VolumeService whose Docker client
+// talks to the given test server.
+func newFakeDockerVolumeService(t *testing.T, docker *httptest.Server) *VolumeService {
+	t.Helper()
+	cli, err := client.NewClientWithOpts(client.WithHost("tcp://"+strings.TrimPrefix(docker.URL, "http://")), client.WithVersion("1.44"))
+	require.NoError(t, err)
+
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.Event{}, &models.PruneReport{}))
+	db := &database.DB{DB: gdb}
+
+	dockerService := &DockerClientService{client: cli}
+	eventService := NewEventService(db)
+	containerService := NewContainerService(db, eventService, dockerService, nil, nil, nil)
+	return NewVolumeService(db, dockerService, eventService, nil, containerService, nil, nil, nil, "")
+}
+
+func TestVolumeService_PruneVolumesRecordsReport(t *testing.T) {
+	dockerutil.InvalidateVolumeUsageCache()
+	t.Cleanup(dockerutil.InvalidateVolumeUsageCache)
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch strings.TrimPrefix(r.URL.Path, "/v1.44") {
+		case "/system/df":
+			_, _ = io.WriteString(w, `{"Volumes":[{"Name":"cache","UsageData":{"Size":4096,"RefCount":0}}]}`)
+		case "/volumes/prune":
+			_, _ = io.WriteString(w, `{"VolumesDeleted":["cache"],"SpaceReclaimed":4096}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer docker.Close()
+
+	ctx := context.Background()
+	svc := newFakeDockerVolumeService(t, docker)
+	report, err := svc.PruneVolumes(ctx, models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "alice"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cache"}, report.VolumesDeleted)
+
+	var stored models.PruneReport
+	require.NoError(t, svc.db.WithContext(ctx).First(&stored).Error)
+	assert.Equal(t, models.PruneTriggerManual, stored.Trigger)
+	assert.Equal(t, "alice", stored.Username)
+	assert.Equal(t, int64(4096), stored.SpaceReclaimed)
+	require.Len(t, stored.Items, 1)
+	assert.Equal(t, "cache", stored.Items[0].Name)
+	assert.Equal(t, int64(4096), stored.Items[0].Size)
+}
+
+func TestVolumeService_StopAndStartVolumeContainers(t *testing.T) {
+	var mu sync.Mutex
+	var calls []string
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		switch {
+		case strings.HasSuffix(path, "/containers/json"):
+			assert.Contains(t, r.URL.Query().Get("filters"), "data")
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = io.WriteString(w, `[{"Id":"web","Names":["/web"],"State":"running"},{"Id":"job","Names":["/job"],"State":"exited"}]`)
+		case strings.HasSuffix(path, "/stop"), strings.HasSuffix(path, "/start"):
+			mu.Lock()
+			calls = append(calls, path[strings.Index(path, "/containers/"):])
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer docker.Close()
+
+	svc := newFakeDockerVolumeService(t, docker)
+
+	ctx := context.Background()
+	stopped, err := svc.stopVolumeContainersInternal(ctx, "data", models.User{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"web"}, stopped)
+
+	require.NoError(t, svc.startVolumeContainersInternal(ctx, "data", stopped, models.User{}))
+	assert.Equal(t, []string{"/containers/web/stop", "/containers/web/start"}, calls)
+}
+
+func TestVolumeService_ValidateHelperImage(t *testing.T) {
+	var removed bool
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path := r.URL.Path
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(path, "/images/mirror.local/busybox:1/json"):
+			_, _ = io.WriteString(w, `{"Id":"sha256:abc"}`)
+		case strings.HasSuffix(path, "/containers/create"):
+			var body struct{ Cmd []string }
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, []string{"tar", "find", "stat"}, body.Cmd[len(body.Cmd)-3:])
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{"Id":"check"}`)
+		case strings.HasSuffix(path, "/containers/check/start"):
+			w.WriteHeader(http.StatusNoContent)
+		case strings.HasSuffix(path, "/containers/check/wait"):
+			_, _ = io.WriteString(w, `{"StatusCode":1}`)
+		case strings.HasSuffix(path, "/containers/check/logs"):
+			w.Header().Set("Content-Type", "application/vnd.docker.multiplexed-stream")
+			out := []byte("find\nstat\n")
+			header := []byte{1, 0, 0, 0, 0, 0, 0, byte(len(out))}
+			_, _ = w.Write(append(header, out...))
+		case r.Method == http.MethodDelete && strings.HasSuffix(path, "/containers/check"):
+			removed = true
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer docker.Close()
+
+	svc := newFakeDockerVolumeService(t, docker)
+
+	require.NoError(t, svc.ValidateHelperImage(context.Background(), "  "))
+
+	err := svc.ValidateHelperImage(context.Background(), "mirror.local/busybox:1")
+	require.ErrorIs(t, err, ErrInvalidHelperImage)
+	assert.Contains(t, err.Error(), "find, stat")
+	assert.True(t, removed)
+}
+
+func TestVolumeService_ReapIdleHelpers(t *testing.T) {
+	var mu sync.Mutex
+	var removed []string
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodDelete && strings.Contains(r.URL.Path, "/containers/") {
+			mu.Lock()
+			removed = append(removed, path.Base(r.URL.Path))
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer docker.Close()
+
+	svc := newFakeDockerVolumeService(t, docker)
+	stale := time.Now().Add(-2 * defaultHelperIdleTTL)
+	svc.helperByVolume = map[string]*helperContainer{
+		"idle":   {id: "idle-helper", lastUsed: stale},
+		"busy":   {id: "busy-helper", inUse: 1, lastUsed: stale},
+		"recent": {id: "recent-helper", lastUsed: time.Now()},
+	}
+
+	assert.Equal(t, 1, svc.ReapIdleHelpers(context.Background()))
+	assert.Equal(t, []string{"idle-helper"}, removed)
+	assert.NotContains(t, svc.helperByVolume, "idle")
+	assert.Contains(t, svc.helperByVolume, "busy")
+	assert.Contains(t, svc.helperByVolume, "recent")
+
+	// Releasing the busy helper starts its idle timer instead of removing it.
+	svc.releaseHelperInternal("busy", "busy-helper")()
+	assert.Equal(t, 0, svc.helperByVolume["busy"].inUse)
+	assert.Equal(t, 0, svc.ReapIdleHelpers(context.Background()))
+}
+
+func TestVolumeService_ArchivePathHelpers(t *testing.T) {
+	existing := map[string]bool{"/volume": true}
+	var copiedTo string
+	var copied []string
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/archive") {
+			http.NotFound(w, r)
+			return
+		}
+		target := r.URL.Query().Get("path")
+		switch r.Method {
+		case http.MethodHead:
+			isDir, ok := existing[target]
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			mode := os.FileMode(0o644)
+			if isDir {
+				mode = os.ModeDir | 0o755
+			}
+			stat, _ := json.Marshal(container.PathStat{Name: path.Base(target), Mode: mode})
+			w.Header().Set("X-Docker-Container-Path-Stat", base64.StdEncoding.EncodeToString(stat))
+		case http.MethodPut:
+			copiedTo = target
+			tr := tar.NewReader(r.Body)
+			for {
+				header, err := tr.Next()
+				if err != nil {
+					break
+				}
+				copied = append(copied, header.Name)
+			}
+		case http.MethodGet:
+			stat, _ := json.Marshal(container.PathStat{Name: path.Base(target), Mode: os.ModeDir | 0o755})
+			w.Header().Set("X-Docker-Container-Path-Stat", base64.StdEncoding.EncodeToString(stat))
+			var buf bytes.Buffer
+			tw := tar.NewWriter(&buf)
+			_ = tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: path.Base(target) + "/", Mode: 0o755})
+			if target == "/volume/full" {
+				_ = tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: "full/data.txt", Mode: 0o644})
+			}
+			_ = tw.Close()
+			_, _ = w.Write(buf.Bytes())
+		}
+	}))
+	defer docker.Close()
+
+	svc := newFakeDockerVolumeService(t, docker)
+	ctx := context.Background()
+
+	require.NoError(t, svc.mkdirAllInternal(ctx, "helper", "/volume/a/b"))
+	assert.Equal(t, "/volume", copiedTo)
+	assert.Equal(t, []string{"a/", "a/b/"}, copied)
+
+	existing["/volume/file"] = false
+	err := svc.mkdirAllInternal(ctx, "helper", "/volume/file/sub")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "not a directory")
+
+	hasEntries, err := svc.dirHasEntriesInternal(ctx, "helper", "/volume/full")
+	require.NoError(t, err)
+	assert.True(t, hasEntries)
+
+	hasEntries, err = svc.dirHasEntriesInternal(ctx, "helper", "/volume/empty")
+	require.NoError(t, err)
+	assert.False(t, hasEntries)
+}
+
+// fakeMigrateDockerInternal serves the Docker calls of migrating the "data"
+// volume to "data2" with one stopped container, "job", mounting it. With
+// failCreate set, recreating the container fails.
+func fakeMigrateDockerInternal(t *testing.T, failCreate bool) (*httptest.Server, *[]string) {
+	t.Helper()
+	var mu sync.Mutex
+	var calls []string
+	targetCreated := false
+
+	docker := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Strip the /v1.44 API version prefix.
+		path := r.URL.Path
+		if rest, ok := strings.CutPrefix(path, "/v"); ok {
+			path = rest[strings.Index(rest, "/"):]
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+
+		switch {
+		case r.Method == http.MethodGet && path == "/volumes/data":
+			_, _ = io.WriteString(w, `{"Name":"data","Driver":"local"}`)
+		case r.Method == http.MethodGet && path == "/volumes/data2":
+			if !targetCreated {
+				http.NotFound(w, r)
+				return
+			}
+			_, _ = io.WriteString(w, `{"Name":"data2","Driver":"local"}`)
+		case r.Method == http.MethodPost && path == "/volumes/create":
+			targetCreated = true
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{"Name":"data2","Driver":"local"}`)
+		case r.Method == http.MethodGet && path == "/containers/json":
+			if strings.Contains(r.URL.Query().Get("filters"), "volume") {
+				_, _ = io.WriteString(w, `[{"Id":"job","Names":["/job"],"State":"exited"}]`)
+				return
+			}
+			_, _ = io.WriteString(w, `[]`)
+		case r.Method == http.MethodGet && strings.HasPrefix(path, "/images/"):
+			_, _ = io.WriteString(w, `{"Id":"sha256:busybox"}`)
+		case r.Method == http.MethodGet && path == "/containers/job/json":
+			_, _ = io.WriteString(w, `{"Id":"job","Name":"/job","State":{"Running":false},"Config":{"Image":"app"},"HostConfig":{"Binds":["data:/data"]},"NetworkSettings":{"Networks":{}}}`)
+		case r.Method == http.MethodPost && path == "/containers/create":
+			var body struct {
+				HostConfig struct {
+					Binds []string
+				}
+			}
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			name := r.URL.Query().Get("name")
+			if name == "" {
+				w.WriteHeader(http.StatusCreated)
+				_, _ = io.WriteString(w, `{"Id":"helper"}`)
+				return
+			}
+			calls = append(calls, "create "+name+" "+strings.Join(body.HostConfig.Binds, ","))
+			if failCreate {
+				w.WriteHeader(http.StatusInternalServerError)
+				_, _ = io.WriteString(w, `{"message":"create failed"}`)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = io.WriteString(w, `{"Id":"job2"}`)
+		case path == "/containers/helper/start":
+			w.WriteHeader(http.StatusNoContent)
+		case path == "/containers/helper/wait":
+			_, _ = io.WriteString(w, `{"StatusCode":0}`)
+		case path == "/containers/job/rename":
+			name := r.URL.Query().Get("name")
+			if strings.Contains(name, "_arcane_old_") {
+				name = "aside"
+			}
+			calls = append(calls, "rename "+name)
+			w.WriteHeader(http.StatusNoContent)
+		case r.Method == http.MethodDelete:
+			calls = append(calls, "remove "+path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(docker.Close)
+	return docker, &calls
+}
+
+func TestVolumeService_MigrateVolume(t *testing.T) {
+	req := volumetypes.Migrate{Name: "data2", RepointContainers: true, DeleteSource: true}
+
+	t.Run("repoints containers and deletes the source", func(t *testing.T) {
+		docker, calls := fakeMigrateDockerInternal(t, false)
+		svc := newFakeDockerVolumeService(t, docker)
+
+		result, err := svc.MigrateVolume(context.Background(), "data", req, models.User{})
+		require.NoError(t, err)
+		assert.Equal(t, []string{"job"}, result.RepointedContainers)
+		assert.True(t, result.SourceDeleted)
+		assert.Equal(t, []string{
+			"rename aside",
+			"create job data2:/data",
+			"remove /containers/job",
+			"remove /volumes/data",
+		}, *calls)
+	})
+
+	t.Run("keeps the container and source when recreating fails", func(t *testing.T) {
+		docker, calls := fakeMigrateDockerInternal(t, true)
+		svc := newFakeDockerVolumeService(t, docker)
+
+		_, err := svc.MigrateVolume(context.Background(), "data", req, models.User{})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "previous container restored")
+		assert.Equal(t, []string{
+			"rename aside",
+			"create job data2:/data",
+			"rename job",
+		}, *calls)
+	})
+}
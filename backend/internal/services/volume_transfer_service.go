@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	httputils "github.com/getarcaneapp/arcane/backend/internal/utils/http"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/remenv"
+	volumetypes "github.com/getarcaneapp/arcane/types/volume"
+)
+
+var (
+	ErrTransferSameEnvironment   = errors.New("source and target environments must differ")
+	ErrTransferEdgeUnsupported   = errors.New("edge environments do not support cross-environment restore")
+	ErrTransferRequiresApproval  = errors.New("target environment requires approval for restores")
+	ErrTransferEnvironmentFailed = errors.New("remote environment request failed")
+)
+
+// transferErrorBodyLimit caps how much of a failed agent response is quoted in
+// the returned error.
+const transferErrorBodyLimit = 4 * 1024
+
+// VolumeTransferService moves volume backups between environments. The
+// manager pulls the archive from the source environment and streams it into
+// the target environment's upload-and-restore endpoint without buffering it.
+type VolumeTransferService struct {
+	volumeService      *VolumeService
+	environmentService *EnvironmentService
+	approvalService    *ApprovalService
+	eventService       *EventService
+	httpClient         *http.Client
+}
+
+func NewVolumeTransferService(volumeService *VolumeService, environmentService *EnvironmentService, approvalService *ApprovalService, eventService *EventService) *VolumeTransferService {
+	return &VolumeTransferService{
+		volumeService:      volumeService,
+		environmentService: environmentService,
+		approvalService:    approvalService,
+		eventService:       eventService,
+		// Archives can be large; the request context bounds the transfer instead
+		// of a client timeout.
+		httpClient: httputils.NewHTTPClientWithTimeout(0),
+	}
+}
+
+// RestoreFromEnvironment restores a backup held by the source environment into
+// a volume on the target environment.
+func (s *VolumeTransferService) RestoreFromEnvironment(ctx context.Context, req volumetypes.CrossEnvironmentRestoreRequest, user models.User) error {
+	if req.SourceEnvironmentID == req.TargetEnvironmentID {
+		return ErrTransferSameEnvironment
+	}
+
+	source, err := s.environmentService.GetEnvironmentByID(ctx, req.SourceEnvironmentID)
+	if err != nil {
+		return fmt.Errorf("failed to get source environment: %w", err)
+	}
+	target, err := s.environmentService.GetEnvironmentByID(ctx, req.TargetEnvironmentID)
+	if err != nil {
+		return fmt.Errorf("failed to get target environment: %w", err)
+	}
+	if source.IsEdge || target.IsEdge {
+		// Edge tunnel requests are buffered in memory, which does not suit
+		// multi-gigabyte archives.
+		return ErrTransferEdgeUnsupported
+	}
+	if s.approvalService != nil && s.approvalService.RequiresApproval(ctx, target.ID) {
+		return ErrTransferRequiresApproval
+	}
+
+	slog.InfoContext(ctx, "Restoring volume backup across environments",
+		"source", source.ID, "target", target.ID, "backup_id", req.BackupID, "volume", req.TargetVolume, "user", user.ID)
+
+	archive, size, err := s.openSourceArchiveInternal(ctx, source, req.BackupID, user)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = archive.Close() }()
+
+	filename := req.BackupID + ".tar.gz"
+	if target.ID == "0" {
+		_, err = s.volumeService.UploadAndRestore(ctx, req.TargetVolume, archive, size, filename, user)
+	} else {
+		err = s.uploadToEnvironmentInternal(ctx, target, req.TargetVolume, archive, filename)
+	}
+	if err != nil {
+		return err
+	}
+
+	if s.eventService != nil {
+		metadata := models.JSON{
+			"action":                "backup_transfer",
+			"backup_id":             req.BackupID,
+			"source_environment_id": source.ID,
+			"source_environment":    source.Name,
+			"size":                  size,
+		}
+		if logErr := s.eventService.LogVolumeEvent(ctx, models.EventTypeVolumeBackupTransfer, req.TargetVolume, req.TargetVolume, user.ID, user.Username, target.ID, metadata); logErr != nil {
+			slog.WarnContext(ctx, "could not log volume backup transfer event", "volume", req.TargetVolume, "error", logErr.Error())
+		}
+	}
+
+	return nil
+}
+
+// openSourceArchiveInternal returns the backup archive and its size, or -1
+// when the remote agent did not report one.
+func (s *VolumeTransferService) openSourceArchiveInternal(ctx context.Context, env *models.Environment, backupID string, user models.User) (io.ReadCloser, int64, error) {
+	if env.ID == "0" {
+		return s.volumeService.DownloadBackup(ctx, backupID, &user)
+	}
+
+	targetURL := strings.TrimRight(env.ApiUrl, "/") + "/api/environments/0/volumes/backups/" + url.PathEscape(backupID) + "/download"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, targetURL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create download request: %w", err)
+	}
+	remenv.SetAgentToken(httpReq, env.AccessToken)
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to download backup from %s: %w", env.Name, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		defer func() { _ = resp.Body.Close() }()
+		return nil, 0, transferResponseErrorInternal("download backup from "+env.Name, resp)
+	}
+	return resp.Body, resp.ContentLength, nil
+}
+
+// uploadToEnvironmentInternal streams the archive as a multipart upload to the
+// remote agent's upload-and-restore endpoint.
+func (s *VolumeTransferService) uploadToEnvironmentInternal(ctx context.Context, env *models.Environment, volumeName string, archive io.Reader, filename string) error {
+	body, contentType := streamMultipartFileInternal("file", filename, archive)
+	defer func() { _ = body.Close() }()
+
+	targetURL := strings.TrimRight(env.ApiUrl, "/") + "/api/environments/0/volumes/" + url.PathEscape(volumeName) + "/backups/upload"
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, targetURL, body)
+	if err != nil {
+		return fmt.Errorf("failed to create upload request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", contentType)
+	remenv.SetAgentToken(httpReq, env.AccessToken)
+
+	resp, err := s.httpClient.Do(httpReq)
+	if err != nil {
+		return fmt.Errorf("failed to upload backup to %s: %w", env.Name, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return transferResponseErrorInternal("restore backup on "+env.Name, resp)
+	}
+	return nil
+}
+
+// streamMultipartFileInternal encodes src as a single-file multipart form
+// without buffering it. The returned reader must be closed by the caller so
+// the encoding goroutine exits if the request is abandoned.
+func streamMultipartFileInternal(field, filename string, src io.Reader) (io.ReadCloser, string) {
+	pr, pw := io.Pipe()
+	mw := multipart.NewWriter(pw)
+
+	go func() {
+		part, err := mw.CreateFormFile(field, filename)
+		if err == nil {
+			_, err = io.Copy(part, src)
+		}
+		if err == nil {
+			err = mw.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+
+	return pr, mw.FormDataContentType()
+}
+
+func transferResponseErrorInternal(action string, resp *http.Response) error {
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, transferErrorBodyLimit))
+	return fmt.Errorf("%w: %s: status %d: %s", ErrTransferEnvironmentFailed, action, resp.StatusCode, strings.TrimSpace(string(snippet)))
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	volumetypes "github.com/getarcaneapp/arcane/types/volume"
+)
+
+func setupVolumeTransferTestService(t *testing.T, envs ...models.Environment) *VolumeTransferService {
+	t.Helper()
+	ctx := context.Background()
+
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.SettingVariable{}, &models.Environment{}))
+	db := &database.DB{DB: gdb}
+
+	settingsService, err := NewSettingsService(ctx, db)
+	require.NoError(t, err)
+	require.NoError(t, settingsService.EnsureDefaultSettings(ctx))
+	require.NoError(t, settingsService.SetBoolSetting(ctx, "approvalWorkflowEnabled", true))
+
+	for i := range envs {
+		require.NoError(t, gdb.Create(&envs[i]).Error)
+	}
+
+	environmentService := NewEnvironmentService(db, nil, nil, nil, settingsService)
+	approvalService := NewApprovalService(db, settingsService, environmentService, nil)
+	return NewVolumeTransferService(nil, environmentService, approvalService, nil)
+}
+
+func TestVolumeTransferService_RestoreFromEnvironment_RemoteToRemote(t *testing.T) {
+	archive := []byte("fake-archive-bytes")
+
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/environments/0/volumes/backups/b1/download", r.URL.Path)
+		assert.Equal(t, "source-token", r.Header.Get("X-Arcane-Agent-Token"))
+		_, _ = w.Write(archive)
+	}))
+	defer source.Close()
+
+	var received []byte
+	var receivedName string
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/environments/0/volumes/data/backups/upload", r.URL.Path)
+		assert.Equal(t, "target-token", r.Header.Get("X-API-Key"))
+		file, header, err := r.FormFile("file")
+		if !assert.NoError(t, err) {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		defer file.Close()
+		receivedName = header.Filename
+		received, _ = io.ReadAll(file)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer target.Close()
+
+	sourceToken, targetToken := "source-token", "target-token"
+	svc := setupVolumeTransferTestService(t,
+		models.Environment{BaseModel: models.BaseModel{ID: "1"}, Name: "src", ApiUrl: source.URL, AccessToken: &sourceToken},
+		models.Environment{BaseModel: models.BaseModel{ID: "2"}, Name: "dst", ApiUrl: target.URL, AccessToken: &targetToken},
+	)
+
+	err := svc.RestoreFromEnvironment(context.Background(), volumetypes.CrossEnvironmentRestoreRequest{
+		SourceEnvironmentID: "1",
+		BackupID:            "b1",
+		TargetEnvironmentID: "2",
+		TargetVolume:        "data",
+	}, models.User{BaseModel: models.BaseModel{ID: "u1"}})
+	require.NoError(t, err)
+	assert.Equal(t, archive, received)
+	assert.Equal(t, "b1.tar.gz", receivedName)
+}
+
+func TestVolumeTransferService_RestoreFromEnvironment_SourceFailure(t *testing.T) {
+	source := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "backup not found", http.StatusNotFound)
+	}))
+	defer source.Close()
+
+	svc := setupVolumeTransferTestService(t,
+		models.Environment{BaseModel: models.BaseModel{ID: "1"}, Name: "src", ApiUrl: source.URL},
+		models.Environment{BaseModel: models.BaseModel{ID: "2"}, Name: "dst", ApiUrl: "http://127.0.0.1:1"},
+	)
+
+	err := svc.RestoreFromEnvironment(context.Background(), volumetypes.CrossEnvironmentRestoreRequest{
+		SourceEnvironmentID: "1", BackupID: "b1", TargetEnvironmentID: "2", TargetVolume: "data",
+	}, models.User{})
+	require.ErrorIs(t, err, ErrTransferEnvironmentFailed)
+	assert.Contains(t, err.Error(), "backup not found")
+}
+
+func TestVolumeTransferService_RestoreFromEnvironment_Guards(t *testing.T) {
+	svc := setupVolumeTransferTestService(t,
+		models.Environment{BaseModel: models.BaseModel{ID: "1"}, Name: "src", ApiUrl: "http://src"},
+		models.Environment{BaseModel: models.BaseModel{ID: "2"}, Name: "edge", ApiUrl: "http://edge", IsEdge: true},
+		models.Environment{BaseModel: models.BaseModel{ID: "3"}, Name: "prod", ApiUrl: "http://prod", Production: true},
+	)
+	ctx := context.Background()
+	req := func(src, dst string) volumetypes.CrossEnvironmentRestoreRequest {
+		return volumetypes.CrossEnvironmentRestoreRequest{SourceEnvironmentID: src, BackupID: "b1", TargetEnvironmentID: dst, TargetVolume: "data"}
+	}
+
+	assert.ErrorIs(t, svc.RestoreFromEnvironment(ctx, req("1", "1"), models.User{}), ErrTransferSameEnvironment)
+	assert.ErrorIs(t, svc.RestoreFromEnvironment(ctx, req("1", "2"), models.User{}), ErrTransferEdgeUnsupported)
+	assert.ErrorIs(t, svc.RestoreFromEnvironment(ctx, req("2", "1"), models.User{}), ErrTransferEdgeUnsupported)
+	assert.ErrorIs(t, svc.RestoreFromEnvironment(ctx, req("1", "3"), models.User{}), ErrTransferRequiresApproval)
+}
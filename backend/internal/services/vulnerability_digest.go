@@ -0,0 +1,216 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/types/vulnerability"
+)
+
+const (
+	// vulnerabilityDigestTopImages is how many of the most affected images
+	// a digest lists.
+	vulnerabilityDigestTopImages = 5
+	// vulnerabilityDigestMaxNewCriticals is how many new critical
+	// vulnerabilities a digest lists; the rest are only counted.
+	vulnerabilityDigestMaxNewCriticals = 20
+)
+
+// vulnerabilityDigestThresholdKeys maps each severity to the setting holding
+// the count at which it triggers the digest.
+var vulnerabilityDigestThresholdKeys = map[vulnerability.Severity]string{
+	vulnerability.SeverityCritical: "vulnerabilityDigestCriticalThreshold",
+	vulnerability.SeverityHigh:     "vulnerabilityDigestHighThreshold",
+	vulnerability.SeverityMedium:   "vulnerabilityDigestMediumThreshold",
+	vulnerability.SeverityLow:      "vulnerabilityDigestLowThreshold",
+}
+
+// SendVulnerabilityDigest builds the environment's vulnerability digest and
+// sends it when it reaches one of the configured severity thresholds. A sent
+// digest is recorded so the next one only reports the critical
+// vulnerabilities found since. It returns whether the digest was sent.
+func (s *VulnerabilityService) SendVulnerabilityDigest(ctx context.Context) (bool, error) {
+	if s.notificationService == nil || s.db == nil {
+		return false, nil
+	}
+
+	payload, criticalKeys, err := s.buildVulnerabilityDigestInternal(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !vulnerabilityDigestDueInternal(payload, s.vulnerabilityDigestThresholdsInternal(ctx)) {
+		return false, nil
+	}
+
+	if err := s.notificationService.SendVulnerabilityDigestNotification(ctx, *payload); err != nil {
+		return false, err
+	}
+
+	record := &models.VulnerabilityDigest{
+		CriticalKeys: criticalKeys,
+		Critical:     payload.Critical,
+		High:         payload.High,
+		Medium:       payload.Medium,
+		Low:          payload.Low,
+		Fixable:      payload.Fixable,
+	}
+	if err := s.db.WithContext(ctx).Create(record).Error; err != nil {
+		return true, fmt.Errorf("failed to record vulnerability digest: %w", err)
+	}
+	return true, nil
+}
+
+// buildVulnerabilityDigestInternal aggregates the completed scans into a
+// digest, leaving out ignored vulnerabilities. It also returns the keys of
+// every open critical vulnerability, to be stored with the digest.
+func (s *VulnerabilityService) buildVulnerabilityDigestInternal(ctx context.Context) (*VulnerabilityDigestNotificationPayload, []string, error) {
+	payload := &VulnerabilityDigestNotificationPayload{}
+
+	previous := map[string]struct{}{}
+	var last models.VulnerabilityDigest
+	err := s.db.WithContext(ctx).Order("created_at DESC").First(&last).Error
+	switch {
+	case err == nil:
+		payload.Since = &last.CreatedAt
+		for _, key := range last.CriticalKeys {
+			previous[key] = struct{}{}
+		}
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, nil, fmt.Errorf("failed to load previous vulnerability digest: %w", err)
+	}
+
+	var records []models.VulnerabilityScanRecord
+	if err := s.db.WithContext(ctx).Where("status = ?", models.ScanStatusCompleted).Find(&records).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to load vulnerability scans: %w", err)
+	}
+
+	var ignores []models.VulnerabilityIgnore
+	if err := s.activeIgnoresInternal(ctx).Find(&ignores).Error; err != nil {
+		return nil, nil, fmt.Errorf("failed to load ignored vulnerabilities: %w", err)
+	}
+	ignored := newIgnoreMatcherInternal(ignores)
+
+	criticalKeys := []string{}
+	var newCriticals []VulnerabilityNotificationPayload
+	var images []VulnerabilityDigestImage
+	for i := range records {
+		result, err := s.convertRecordToResult(&records[i])
+		if err != nil {
+			continue
+		}
+		payload.ScannedImages++
+
+		image := VulnerabilityDigestImage{ImageName: result.ImageName}
+		seen := map[string]struct{}{}
+		for _, v := range result.Vulnerabilities {
+			if ignored.matches(result.ImageID, v.VulnerabilityID, v.PkgName, v.InstalledVersion) {
+				continue
+			}
+			key := vulnerabilityKeyInternal(result.ImageID, v.VulnerabilityID, v.PkgName, v.InstalledVersion)
+			if _, ok := seen[key]; ok {
+				continue
+			}
+			seen[key] = struct{}{}
+
+			image.Total++
+			if v.FixedVersion != "" {
+				image.Fixable++
+			}
+			switch v.Severity {
+			case vulnerability.SeverityCritical:
+				payload.Critical++
+				image.Critical++
+				criticalKeys = append(criticalKeys, key)
+				if _, ok := previous[key]; !ok {
+					newCriticals = append(newCriticals, VulnerabilityNotificationPayload{
+						CVEID:            v.VulnerabilityID,
+						CVELink:          cveLink(v.VulnerabilityID),
+						Severity:         string(v.Severity),
+						ImageName:        result.ImageName,
+						FixedVersion:     v.FixedVersion,
+						PkgName:          v.PkgName,
+						InstalledVersion: v.InstalledVersion,
+					})
+				}
+			case vulnerability.SeverityHigh:
+				payload.High++
+				image.High++
+			case vulnerability.SeverityMedium:
+				payload.Medium++
+			case vulnerability.SeverityLow:
+				payload.Low++
+			case vulnerability.SeverityUnknown:
+			}
+		}
+		payload.Fixable += image.Fixable
+		if image.Total > 0 {
+			images = append(images, image)
+		}
+	}
+
+	slices.SortFunc(newCriticals, func(a, b VulnerabilityNotificationPayload) int {
+		return cmp.Or(cmp.Compare(a.ImageName, b.ImageName), cmp.Compare(a.CVEID, b.CVEID), cmp.Compare(a.PkgName, b.PkgName))
+	})
+	payload.NewCriticalCount = len(newCriticals)
+	payload.NewCriticals = newCriticals[:min(len(newCriticals), vulnerabilityDigestMaxNewCriticals)]
+
+	slices.SortFunc(images, func(a, b VulnerabilityDigestImage) int {
+		return cmp.Or(cmp.Compare(b.Critical, a.Critical), cmp.Compare(b.High, a.High), cmp.Compare(b.Total, a.Total), cmp.Compare(a.ImageName, b.ImageName))
+	})
+	payload.TopImages = images[:min(len(images), vulnerabilityDigestTopImages)]
+
+	return payload, criticalKeys, nil
+}
+
+// vulnerabilityDigestThresholdsInternal returns the configured count at
+// which each severity triggers the digest; 0 means the severity never does.
+func (s *VulnerabilityService) vulnerabilityDigestThresholdsInternal(ctx context.Context) map[vulnerability.Severity]int {
+	thresholds := make(map[vulnerability.Severity]int, len(vulnerabilityDigestThresholdKeys))
+	if s.settingsService == nil {
+		thresholds[vulnerability.SeverityCritical] = 1
+		return thresholds
+	}
+	for severity, key := range vulnerabilityDigestThresholdKeys {
+		def := 0
+		if severity == vulnerability.SeverityCritical {
+			def = 1
+		}
+		thresholds[severity] = max(s.settingsService.GetIntSetting(ctx, key, def), 0)
+	}
+	return thresholds
+}
+
+// vulnerabilityDigestDueInternal reports whether payload reaches one of the
+// thresholds. With every threshold at 0 the digest is always due.
+func vulnerabilityDigestDueInternal(payload *VulnerabilityDigestNotificationPayload, thresholds map[vulnerability.Severity]int) bool {
+	counts := map[vulnerability.Severity]int{
+		vulnerability.SeverityCritical: payload.Critical,
+		vulnerability.SeverityHigh:     payload.High,
+		vulnerability.SeverityMedium:   payload.Medium,
+		vulnerability.SeverityLow:      payload.Low,
+	}
+
+	anyThreshold := false
+	for severity, threshold := range thresholds {
+		if threshold <= 0 {
+			continue
+		}
+		anyThreshold = true
+		if counts[severity] >= threshold {
+			return true
+		}
+	}
+	return !anyThreshold
+}
+
+// vulnerabilityKeyInternal identifies a vulnerability of a package in an
+// image, the same way vulnerability ignores do.
+func vulnerabilityKeyInternal(imageID, vulnerabilityID, pkgName, installedVersion string) string {
+	return fmt.Sprintf("%s:%s:%s:%s", imageID, vulnerabilityID, pkgName, installedVersion)
+}
@@ -0,0 +1,129 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/types/vulnerability"
+)
+
+func newDigestTestServiceInternal(t *testing.T) (*VulnerabilityService, *gorm.DB) {
+	t.Helper()
+	ctx := context.Background()
+
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.SettingVariable{}, &models.VulnerabilityScanRecord{}, &models.VulnerabilityIgnore{}, &models.VulnerabilityDigest{}))
+	db := &database.DB{DB: gdb}
+
+	settingsService, err := NewSettingsService(ctx, db)
+	require.NoError(t, err)
+
+	return NewVulnerabilityService(db, nil, nil, settingsService, nil), gdb
+}
+
+func TestVulnerabilityService_BuildVulnerabilityDigest(t *testing.T) {
+	ctx := context.Background()
+	svc, gdb := newDigestTestServiceInternal(t)
+
+	require.NoError(t, svc.saveScanResult(ctx, &vulnerability.ScanResult{
+		ImageID:   "sha256:app",
+		ImageName: "app:v1",
+		ScanTime:  time.Now(),
+		Status:    vulnerability.ScanStatusCompleted,
+		Vulnerabilities: []vulnerability.Vulnerability{
+			{VulnerabilityID: "CVE-2024-0001", PkgName: "openssl", InstalledVersion: "3.0.1", FixedVersion: "3.0.2", Severity: vulnerability.SeverityCritical},
+			{VulnerabilityID: "CVE-2024-0002", PkgName: "zlib", InstalledVersion: "1.2", Severity: vulnerability.SeverityCritical},
+			{VulnerabilityID: "CVE-2024-0003", PkgName: "curl", InstalledVersion: "8.0", FixedVersion: "8.1", Severity: vulnerability.SeverityHigh},
+		},
+	}))
+	require.NoError(t, svc.saveScanResult(ctx, &vulnerability.ScanResult{
+		ImageID:   "sha256:db",
+		ImageName: "db:v1",
+		ScanTime:  time.Now(),
+		Status:    vulnerability.ScanStatusCompleted,
+		Vulnerabilities: []vulnerability.Vulnerability{
+			{VulnerabilityID: "CVE-2024-0004", PkgName: "libc", InstalledVersion: "2.36", Severity: vulnerability.SeverityMedium},
+			{VulnerabilityID: "CVE-2024-0005", PkgName: "bash", InstalledVersion: "5.2", Severity: vulnerability.SeverityLow},
+		},
+	}))
+	require.NoError(t, gdb.Create(&models.VulnerabilityIgnore{
+		ID:               "ignore-1",
+		ImageID:          "sha256:app",
+		VulnerabilityID:  "CVE-2024-0002",
+		PkgName:          "zlib",
+		InstalledVersion: "1.2",
+	}).Error)
+
+	payload, criticalKeys, err := svc.buildVulnerabilityDigestInternal(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, payload.Since)
+	assert.Equal(t, 2, payload.ScannedImages)
+	assert.Equal(t, 1, payload.Critical)
+	assert.Equal(t, 1, payload.High)
+	assert.Equal(t, 1, payload.Medium)
+	assert.Equal(t, 1, payload.Low)
+	assert.Equal(t, 2, payload.Fixable)
+	assert.Equal(t, []string{"sha256:app:CVE-2024-0001:openssl:3.0.1"}, criticalKeys)
+	require.Len(t, payload.NewCriticals, 1)
+	assert.Equal(t, "CVE-2024-0001", payload.NewCriticals[0].CVEID)
+	require.Len(t, payload.TopImages, 2)
+	assert.Equal(t, "app:v1", payload.TopImages[0].ImageName)
+	assert.Equal(t, 2, payload.TopImages[0].Total)
+
+	// Criticals already covered by the previous digest are no longer new.
+	require.NoError(t, gdb.Create(&models.VulnerabilityDigest{CriticalKeys: criticalKeys, Critical: 1}).Error)
+	payload, _, err = svc.buildVulnerabilityDigestInternal(ctx)
+	require.NoError(t, err)
+	require.NotNil(t, payload.Since)
+	assert.Equal(t, 1, payload.Critical)
+	assert.Equal(t, 0, payload.NewCriticalCount)
+	assert.Empty(t, payload.NewCriticals)
+}
+
+func TestVulnerabilityDigestDue(t *testing.T) {
+	payload := &VulnerabilityDigestNotificationPayload{Critical: 0, High: 3, Medium: 10}
+
+	assert.False(t, vulnerabilityDigestDueInternal(payload, map[vulnerability.Severity]int{vulnerability.SeverityCritical: 1}))
+	assert.True(t, vulnerabilityDigestDueInternal(payload, map[vulnerability.Severity]int{vulnerability.SeverityCritical: 1, vulnerability.SeverityHigh: 3}))
+	assert.False(t, vulnerabilityDigestDueInternal(payload, map[vulnerability.Severity]int{vulnerability.SeverityMedium: 11}))
+	// With every threshold off the digest is always sent.
+	assert.True(t, vulnerabilityDigestDueInternal(payload, map[vulnerability.Severity]int{vulnerability.SeverityCritical: 0}))
+}
+
+func TestVulnerabilityService_DigestThresholds(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := newDigestTestServiceInternal(t)
+
+	thresholds := svc.vulnerabilityDigestThresholdsInternal(ctx)
+	assert.Equal(t, 1, thresholds[vulnerability.SeverityCritical])
+	assert.Equal(t, 0, thresholds[vulnerability.SeverityHigh])
+
+	require.NoError(t, svc.settingsService.UpdateSetting(ctx, "vulnerabilityDigestHighThreshold", "5"))
+	require.NoError(t, svc.settingsService.LoadDatabaseSettings(ctx))
+	thresholds = svc.vulnerabilityDigestThresholdsInternal(ctx)
+	assert.Equal(t, 5, thresholds[vulnerability.SeverityHigh])
+}
+
+func TestFormatVulnerabilityDigestMessage(t *testing.T) {
+	message := formatVulnerabilityDigestMessageInternal(VulnerabilityDigestNotificationPayload{
+		ScannedImages:    1,
+		Critical:         3,
+		NewCriticalCount: 3,
+		NewCriticals: []VulnerabilityNotificationPayload{
+			{CVEID: "CVE-2024-0001", ImageName: "app:v1", PkgName: "openssl"},
+		},
+		TopImages: []VulnerabilityDigestImage{{ImageName: "app:v1", Critical: 3, Total: 3}},
+	})
+	assert.Contains(t, message, "CVE-2024-0001")
+	assert.Contains(t, message, "app:v1")
+	assert.Contains(t, message, "and 2 more")
+}
@@ -0,0 +1,196 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/types/vulnerability"
+)
+
+// ErrScanGateBlocked is returned when the vulnerability scan gate stops a
+// container from being created.
+var ErrScanGateBlocked = errors.New("blocked by vulnerability scan gate")
+
+// scanGatePollInterval is how often the gate checks on a scan that is
+// already running.
+const scanGatePollInterval = 2 * time.Second
+
+type scanGateOverrideKey struct{}
+
+// WithScanGateOverride marks ctx as a request that creates containers even
+// when the vulnerability scan gate would block them. Every override is
+// recorded as an event.
+func WithScanGateOverride(ctx context.Context) context.Context {
+	return context.WithValue(ctx, scanGateOverrideKey{}, true)
+}
+
+// IsScanGateOverride reports whether ctx overrides the vulnerability scan
+// gate.
+func IsScanGateOverride(ctx context.Context) bool {
+	override, _ := ctx.Value(scanGateOverrideKey{}).(bool)
+	return override
+}
+
+// scanGateInternal returns the gate's severity and the number of
+// vulnerabilities of that severity or higher an image may have. ok is false
+// when the gate is off.
+func (s *VulnerabilityService) scanGateInternal(ctx context.Context) (severity vulnerability.Severity, threshold int, ok bool) {
+	if s == nil || s.settingsService == nil {
+		return "", 0, false
+	}
+	severity = vulnerability.Severity(strings.ToUpper(s.settingsService.GetStringSetting(ctx, "vulnerabilityScanGateSeverity", "")))
+	if severityRankInternal(severity) == 0 {
+		return "", 0, false
+	}
+	return severity, s.settingsService.GetIntSetting(ctx, "vulnerabilityScanGateThreshold", 0), true
+}
+
+// ScanPulledImage starts a vulnerability scan of a freshly pulled image when
+// scan on pull or the scan gate is enabled, so the result is usually ready by
+// the time a container is created from it.
+func (s *VulnerabilityService) ScanPulledImage(ctx context.Context, imageRef string, user models.User) {
+	if s == nil || s.settingsService == nil {
+		return
+	}
+	_, _, gate := s.scanGateInternal(ctx)
+	if !gate && !s.settingsService.GetBoolSetting(ctx, "vulnerabilityScanOnPull", false) {
+		return
+	}
+
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		slog.WarnContext(ctx, "could not scan pulled image", "image", imageRef, "error", err)
+		return
+	}
+	inspect, err := dockerClient.ImageInspect(ctx, imageRef)
+	if err != nil {
+		slog.WarnContext(ctx, "could not scan pulled image", "image", imageRef, "error", err)
+		return
+	}
+	if _, err := s.ScanImage(ctx, "0", inspect.ID, user); err != nil {
+		slog.WarnContext(ctx, "could not scan pulled image", "image", imageRef, "error", err)
+	}
+}
+
+// CheckScanGate returns ErrScanGateBlocked when imageRef has more
+// vulnerabilities at or above the gate's severity than the gate allows.
+// Ignored vulnerabilities do not count. An image without a completed scan is
+// scanned first, and one that cannot be scanned is blocked. Requests made
+// with WithScanGateOverride pass and are recorded as an override event.
+func (s *VulnerabilityService) CheckScanGate(ctx context.Context, imageRef string, user models.User) error {
+	severity, threshold, ok := s.scanGateInternal(ctx)
+	if !ok {
+		return nil
+	}
+
+	var reason string
+	imageID, vulns, err := s.gateVulnerabilitiesInternal(ctx, imageRef, user)
+	if err != nil {
+		reason = fmt.Sprintf("image %s could not be scanned: %v", imageRef, err)
+	} else {
+		reason = scanGateReasonInternal(imageRef, vulns, severity, threshold)
+	}
+	if reason == "" {
+		return nil
+	}
+
+	if IsScanGateOverride(ctx) {
+		s.logScanGateOverrideInternal(ctx, imageID, imageRef, reason, user)
+		return nil
+	}
+	return fmt.Errorf("%w: %s", ErrScanGateBlocked, reason)
+}
+
+// gateVulnerabilitiesInternal returns the image ID and the vulnerabilities of
+// imageRef that are not ignored, waiting for a running scan or scanning the
+// image when it has no completed scan.
+func (s *VulnerabilityService) gateVulnerabilitiesInternal(ctx context.Context, imageRef string, user models.User) (string, []vulnerability.Vulnerability, error) {
+	dockerClient, err := s.dockerService.GetClient()
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to connect to Docker: %w", err)
+	}
+	inspect, err := dockerClient.ImageInspect(ctx, imageRef)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to inspect image: %w", err)
+	}
+
+	result, err := s.waitForScanInternal(ctx, inspect.ID)
+	if err != nil {
+		return inspect.ID, nil, err
+	}
+	if result == nil || result.Status != vulnerability.ScanStatusCompleted {
+		s.scanImageInBackgroundInternal(ctx, "0", inspect.ID, imageRef, user)
+		if result, err = s.GetScanResult(ctx, inspect.ID); err != nil {
+			return inspect.ID, nil, err
+		}
+	}
+	if result == nil {
+		return inspect.ID, nil, errors.New("no scan result was recorded")
+	}
+	if result.Status != vulnerability.ScanStatusCompleted {
+		if result.Error == "" {
+			return inspect.ID, nil, errors.New("the scan did not complete")
+		}
+		return inspect.ID, nil, errors.New(result.Error)
+	}
+
+	vulns, err := s.filterIgnoredVulnerabilitiesForImage(ctx, inspect.ID, result.Vulnerabilities)
+	if err != nil {
+		return inspect.ID, nil, fmt.Errorf("failed to load ignored vulnerabilities: %w", err)
+	}
+	return inspect.ID, vulns, nil
+}
+
+// waitForScanInternal returns the latest scan result of imageID once it is no
+// longer scanning.
+func (s *VulnerabilityService) waitForScanInternal(ctx context.Context, imageID string) (*vulnerability.ScanResult, error) {
+	ticker := time.NewTicker(scanGatePollInterval)
+	defer ticker.Stop()
+
+	for {
+		result, err := s.GetScanResult(ctx, imageID)
+		if err != nil || result == nil || result.Status != vulnerability.ScanStatusScanning {
+			return result, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *VulnerabilityService) logScanGateOverrideInternal(ctx context.Context, imageID, imageRef, reason string, user models.User) {
+	if s.eventService == nil {
+		return
+	}
+	metadata := models.JSON{
+		"action":    "scan_gate_override",
+		"imageName": imageRef,
+		"reason":    reason,
+	}
+	if err := s.eventService.LogImageEvent(ctx, models.EventTypeImageScanGateOverride, imageID, imageRef, user.ID, user.Username, "0", metadata); err != nil {
+		slog.WarnContext(ctx, "failed to log scan gate override event", "error", err)
+	}
+}
+
+// scanGateReasonInternal explains why vulns block imageRef, or returns "" if
+// they do not.
+func scanGateReasonInternal(imageRef string, vulns []vulnerability.Vulnerability, severity vulnerability.Severity, threshold int) string {
+	minRank := severityRankInternal(severity)
+	count := 0
+	for _, v := range vulns {
+		if severityRankInternal(v.Severity) >= minRank {
+			count++
+		}
+	}
+	if count <= threshold {
+		return ""
+	}
+	return fmt.Sprintf("image %s has %d vulnerabilities of severity %s or higher, more than the %d allowed", imageRef, count, severity, threshold)
+}
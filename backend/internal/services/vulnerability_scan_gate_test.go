@@ -0,0 +1,119 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/config"
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/types/vulnerability"
+)
+
+// newScanGateTestServiceInternal returns a VulnerabilityService backed by an
+// in-memory database and a fake Docker daemon that reports every image as
+// sha256:img.
+func newScanGateTestServiceInternal(t *testing.T) (*VulnerabilityService, *gorm.DB) {
+	t.Helper()
+	ctx := context.Background()
+
+	daemon := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if strings.HasSuffix(r.URL.Path, "/json") && strings.Contains(r.URL.Path, "/images/") {
+			_, _ = w.Write([]byte(`{"Id":"sha256:img","RepoTags":["app:v1"]}`))
+			return
+		}
+		w.Header().Set("Api-Version", "1.45")
+		_, _ = w.Write([]byte(`OK`))
+	}))
+	t.Cleanup(daemon.Close)
+
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.SettingVariable{}, &models.VulnerabilityScanRecord{}, &models.VulnerabilityIgnore{}, &models.Event{}))
+	db := &database.DB{DB: gdb}
+
+	settingsService, err := NewSettingsService(ctx, db)
+	require.NoError(t, err)
+	dockerService := NewDockerClientService(db, &config.Config{DockerHost: "tcp://" + strings.TrimPrefix(daemon.URL, "http://")}, settingsService, nil)
+
+	svc := NewVulnerabilityService(db, dockerService, NewEventService(db), settingsService, nil)
+	return svc, gdb
+}
+
+func setScanGateInternal(t *testing.T, svc *VulnerabilityService, severity, threshold string) {
+	t.Helper()
+	ctx := context.Background()
+	require.NoError(t, svc.settingsService.UpdateSetting(ctx, "vulnerabilityScanGateSeverity", severity))
+	require.NoError(t, svc.settingsService.UpdateSetting(ctx, "vulnerabilityScanGateThreshold", threshold))
+	require.NoError(t, svc.settingsService.LoadDatabaseSettings(ctx))
+}
+
+func TestVulnerabilityService_CheckScanGate(t *testing.T) {
+	ctx := context.Background()
+	svc, gdb := newScanGateTestServiceInternal(t)
+	user := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "admin"}
+
+	require.NoError(t, svc.saveScanResult(ctx, &vulnerability.ScanResult{
+		ImageID:   "sha256:img",
+		ImageName: "app:v1",
+		ScanTime:  time.Now(),
+		Status:    vulnerability.ScanStatusCompleted,
+		Vulnerabilities: []vulnerability.Vulnerability{
+			{VulnerabilityID: "CVE-2024-0001", PkgName: "openssl", InstalledVersion: "3.0.1", Severity: vulnerability.SeverityCritical},
+			{VulnerabilityID: "CVE-2024-0002", PkgName: "zlib", InstalledVersion: "1.2", Severity: vulnerability.SeverityHigh},
+			{VulnerabilityID: "CVE-2024-0003", PkgName: "curl", InstalledVersion: "8.0", Severity: vulnerability.SeverityLow},
+		},
+	}))
+
+	// The gate is off by default.
+	require.NoError(t, svc.CheckScanGate(ctx, "app:v1", user))
+
+	setScanGateInternal(t, svc, "CRITICAL", "0")
+	err := svc.CheckScanGate(ctx, "app:v1", user)
+	require.ErrorIs(t, err, ErrScanGateBlocked)
+	assert.Contains(t, err.Error(), "1 vulnerabilities of severity CRITICAL or higher")
+
+	setScanGateInternal(t, svc, "HIGH", "2")
+	require.NoError(t, svc.CheckScanGate(ctx, "app:v1", user))
+
+	setScanGateInternal(t, svc, "high", "1")
+	require.ErrorIs(t, svc.CheckScanGate(ctx, "app:v1", user), ErrScanGateBlocked)
+
+	// Ignored vulnerabilities do not count.
+	require.NoError(t, gdb.Create(&models.VulnerabilityIgnore{ID: "i1", ImageID: "sha256:img", VulnerabilityID: "CVE-2024-0002", PkgName: "zlib", InstalledVersion: "1.2"}).Error)
+	require.NoError(t, svc.CheckScanGate(ctx, "app:v1", user))
+
+	// An override passes and is recorded.
+	setScanGateInternal(t, svc, "CRITICAL", "0")
+	require.NoError(t, svc.CheckScanGate(WithScanGateOverride(ctx), "app:v1", user))
+
+	var events []models.Event
+	require.NoError(t, gdb.Where("type = ?", models.EventTypeImageScanGateOverride).Find(&events).Error)
+	require.Len(t, events, 1)
+	assert.Equal(t, "admin", *events[0].Username)
+	assert.Contains(t, events[0].Metadata["reason"], "CRITICAL")
+}
+
+func TestScanGateReason(t *testing.T) {
+	vulns := []vulnerability.Vulnerability{
+		{Severity: vulnerability.SeverityCritical},
+		{Severity: vulnerability.SeverityMedium},
+		{Severity: vulnerability.SeverityUnknown},
+	}
+
+	assert.NotEmpty(t, scanGateReasonInternal("app:v1", vulns, vulnerability.SeverityCritical, 0))
+	assert.Empty(t, scanGateReasonInternal("app:v1", vulns, vulnerability.SeverityCritical, 1))
+	assert.Equal(t, "image app:v1 has 2 vulnerabilities of severity MEDIUM or higher, more than the 1 allowed",
+		scanGateReasonInternal("app:v1", vulns, vulnerability.SeverityMedium, 1))
+	assert.Empty(t, scanGateReasonInternal("app:v1", nil, vulnerability.SeverityLow, 0))
+}
@@ -9,6 +9,9 @@ import (
 	"io"
 	"log/slog"
 	"os"
+	"path"
+	"slices"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -38,6 +41,24 @@ const (
 	trivyMaxMemoryBytes   = int64(512 * 1024 * 1024)
 )
 
+// ErrVulnerabilityScanIncomplete is returned when exporting a scan that has
+// not completed.
+var ErrVulnerabilityScanIncomplete = errors.New("vulnerability scan has not completed")
+
+var (
+	// ErrVulnerabilityAlreadyIgnored is returned when an active ignore rule
+	// with the same scope and target already exists.
+	ErrVulnerabilityAlreadyIgnored = errors.New("vulnerability is already ignored")
+	// ErrIgnoreRecordNotFound is returned when removing an unknown ignore rule.
+	ErrIgnoreRecordNotFound = errors.New("ignore record not found")
+	// ErrInvalidIgnoreRule is returned for ignore rules with an unknown scope,
+	// a missing target or an out-of-range expiry.
+	ErrInvalidIgnoreRule = errors.New("invalid ignore rule")
+)
+
+// maxIgnoreExpiryDays bounds how far in the future an ignore rule can expire.
+const maxIgnoreExpiryDays = 3650
+
 // VulnerabilityService handles vulnerability scanning of container images
 type VulnerabilityService struct {
 	db                  *database.DB
@@ -203,6 +224,7 @@ func (s *VulnerabilityService) scanImageInBackgroundInternal(ctx context.Context
 	}
 
 	s.notifyVulnerabilitiesWithFix(ctx, result)
+	s.recordImageLicensesInternal(ctx, result)
 	s.logScanEvent(ctx, envID, imageID, imageName, user, true, "")
 }
 
@@ -226,6 +248,27 @@ func (s *VulnerabilityService) GetScanResult(ctx context.Context, imageID string
 	return s.convertRecordToResult(&record)
 }
 
+// ExportScanSARIF returns the most recent scan result for an image as a
+// SARIF log. Ignored vulnerabilities are left out. It returns nil when the
+// image has not been scanned.
+func (s *VulnerabilityService) ExportScanSARIF(ctx context.Context, imageID string) (*vulnerability.SARIFLog, error) {
+	result, err := s.GetScanResult(ctx, imageID)
+	if err != nil || result == nil {
+		return nil, err
+	}
+	if result.Status != vulnerability.ScanStatusCompleted {
+		return nil, ErrVulnerabilityScanIncomplete
+	}
+
+	vulns, err := s.filterIgnoredVulnerabilitiesForImage(ctx, result.ImageID, result.Vulnerabilities)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load ignored vulnerabilities: %w", err)
+	}
+	result.Vulnerabilities = vulns
+
+	return scanResultToSARIFInternal(result), nil
+}
+
 // GetScanSummary retrieves just the summary for an image (for list views)
 func (s *VulnerabilityService) GetScanSummary(ctx context.Context, imageID string) (*vulnerability.ScanSummary, error) {
 	if s.db == nil {
@@ -702,6 +745,7 @@ func (s *VulnerabilityService) ScanAllImages(ctx context.Context, envID string,
 			slog.WarnContext(ctx, "failed to save scan result", "error", saveErr)
 		}
 		s.notifyVulnerabilitiesWithFix(ctx, result)
+		s.recordImageLicensesInternal(ctx, result)
 		s.logScheduledScanEvent(ctx, envID, imageID, imageName, user, true, "")
 	}
 
@@ -766,8 +810,14 @@ func (s *VulnerabilityService) execTrivyScanInContainer(ctx context.Context, con
 		return nil, fmt.Errorf("failed to connect to Docker: %w", err)
 	}
 
+	release, err := s.dockerService.AcquireExec(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
 	execCfg := containertypes.ExecOptions{
-		Cmd:          []string{"trivy", "image", "--format", "json", "--quiet", imageName},
+		Cmd:          []string{"trivy", "image", "--format", "json", "--quiet", "--scanners", "vuln,license", imageName},
 		AttachStdout: true,
 		AttachStderr: true,
 	}
@@ -1101,7 +1151,7 @@ func (s *VulnerabilityService) buildTrivyCommandArgs(
 	configContent string,
 	ignoreContent string,
 ) ([]string, []string) {
-	cmdArgs := []string{"image", "--format", "json", "--quiet"}
+	cmdArgs := []string{"image", "--format", "json", "--quiet", "--scanners", "vuln,license"}
 	var tempFiles []string
 
 	if strings.TrimSpace(configContent) != "" {
@@ -1390,6 +1440,415 @@ func cveLink(vulnerabilityID string) string {
 	return ""
 }
 
+// scanResultToSARIFInternal converts a scan result the way Trivy's own
+// SARIF output does: one rule per vulnerability ID and one result per
+// affected package. Packages found in a file point at that file within the
+// image; OS packages point at the image repository.
+func scanResultToSARIFInternal(result *vulnerability.ScanResult) *vulnerability.SARIFLog {
+	imageRepo := imageRepositoryInternal(result.ImageName)
+	if imageRepo == "" {
+		imageRepo = result.ImageID
+	}
+
+	run := vulnerability.SARIFRun{
+		Tool: vulnerability.SARIFTool{
+			Driver: vulnerability.SARIFDriver{
+				Name:           "Trivy",
+				InformationURI: "https://github.com/aquasecurity/trivy",
+				Version:        result.ScannerVersion,
+				Rules:          []vulnerability.SARIFRule{},
+			},
+		},
+		Results:    make([]vulnerability.SARIFResult, 0, len(result.Vulnerabilities)),
+		ColumnKind: "utf16CodeUnits",
+		OriginalURIBaseIDs: map[string]vulnerability.SARIFArtifactURI{
+			"ROOTPATH": {URI: "file:///"},
+		},
+		Properties: map[string]any{
+			"imageId":   result.ImageID,
+			"imageName": result.ImageName,
+			"scanTime":  result.ScanTime,
+		},
+	}
+
+	ruleIndexes := map[string]int{}
+	for _, vuln := range result.Vulnerabilities {
+		level := sarifLevelInternal(vuln.Severity)
+		index, ok := ruleIndexes[vuln.VulnerabilityID]
+		if !ok {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndexes[vuln.VulnerabilityID] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRuleInternal(vuln, level))
+		}
+
+		uri := vuln.Location
+		if uri == "" {
+			uri = imageRepo
+		}
+		fixed := vuln.FixedVersion
+		if fixed == "" {
+			fixed = "not fixed"
+		}
+		run.Results = append(run.Results, vulnerability.SARIFResult{
+			RuleID:    vuln.VulnerabilityID,
+			RuleIndex: index,
+			Level:     level,
+			Message: vulnerability.SARIFMessage{
+				Text: fmt.Sprintf("Package: %s\nInstalled Version: %s\nVulnerability: %s\nSeverity: %s\nFixed Version: %s\nImage: %s",
+					vuln.PkgName, vuln.InstalledVersion, vuln.VulnerabilityID, vuln.Severity, fixed, result.ImageName),
+			},
+			Locations: []vulnerability.SARIFLocation{{
+				PhysicalLocation: vulnerability.SARIFPhysicalLocation{
+					ArtifactLocation: vulnerability.SARIFArtifactLocation{URI: uri, URIBaseID: "ROOTPATH"},
+					// Packages have no line information; code scanning
+					// requires a region, so point at the first line.
+					Region: vulnerability.SARIFRegion{StartLine: 1, StartColumn: 1, EndLine: 1, EndColumn: 1},
+				},
+				Message: &vulnerability.SARIFMessage{Text: fmt.Sprintf("%s: %s@%s", result.ImageName, vuln.PkgName, vuln.InstalledVersion)},
+			}},
+		})
+	}
+
+	return &vulnerability.SARIFLog{
+		Version: vulnerability.SARIFVersion,
+		Schema:  vulnerability.SARIFSchema,
+		Runs:    []vulnerability.SARIFRun{run},
+	}
+}
+
+func sarifRuleInternal(vuln vulnerability.Vulnerability, level string) vulnerability.SARIFRule {
+	title := vuln.Title
+	if title == "" {
+		title = vuln.VulnerabilityID
+	}
+	description := vuln.Description
+	if description == "" {
+		description = title
+	}
+	helpURI := cveLink(vuln.VulnerabilityID)
+	if helpURI == "" && len(vuln.References) > 0 {
+		helpURI = vuln.References[0]
+	}
+
+	help := fmt.Sprintf("Vulnerability %s\nSeverity: %s\nPackage: %s\n%s", vuln.VulnerabilityID, vuln.Severity, vuln.PkgName, description)
+	markdown := fmt.Sprintf("**Vulnerability %s**\n| Severity | Package | Fixed Version |\n| --- | --- | --- |\n| %s | %s | %s |\n\n%s",
+		vuln.VulnerabilityID, vuln.Severity, vuln.PkgName, vuln.FixedVersion, description)
+	if helpURI != "" {
+		help += "\n" + helpURI
+		markdown += fmt.Sprintf("\n\n[%s](%s)", vuln.VulnerabilityID, helpURI)
+	}
+	name := "OsPackageVulnerability"
+	if vuln.Location != "" {
+		name = "LanguageSpecificPackageVulnerability"
+	}
+
+	return vulnerability.SARIFRule{
+		ID:                   vuln.VulnerabilityID,
+		Name:                 name,
+		ShortDescription:     vulnerability.SARIFMessage{Text: title},
+		FullDescription:      vulnerability.SARIFMessage{Text: description},
+		HelpURI:              helpURI,
+		Help:                 vulnerability.SARIFMessage{Text: help, Markdown: markdown},
+		DefaultConfiguration: vulnerability.SARIFConfiguration{Level: level},
+		Properties: vulnerability.SARIFRuleProperties{
+			Tags:             []string{"vulnerability", "security", string(vuln.Severity)},
+			Precision:        "very-high",
+			SecuritySeverity: sarifSecuritySeverityInternal(vuln),
+		},
+	}
+}
+
+// sarifLevelInternal maps a severity to a SARIF level the way Trivy does.
+func sarifLevelInternal(severity vulnerability.Severity) string {
+	switch severity {
+	case vulnerability.SeverityCritical, vulnerability.SeverityHigh:
+		return "error"
+	case vulnerability.SeverityMedium:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// sarifSecuritySeverityInternal returns the score GitHub code scanning uses
+// to rank alerts: the CVSS score when known, otherwise a score within the
+// range of the severity.
+func sarifSecuritySeverityInternal(vuln vulnerability.Vulnerability) string {
+	if vuln.CVSS != nil {
+		if vuln.CVSS.V3Score > 0 {
+			return fmt.Sprintf("%.1f", vuln.CVSS.V3Score)
+		}
+		if vuln.CVSS.V2Score > 0 {
+			return fmt.Sprintf("%.1f", vuln.CVSS.V2Score)
+		}
+	}
+	switch vuln.Severity {
+	case vulnerability.SeverityCritical:
+		return "9.5"
+	case vulnerability.SeverityHigh:
+		return "8.0"
+	case vulnerability.SeverityMedium:
+		return "5.5"
+	case vulnerability.SeverityLow:
+		return "2.0"
+	default:
+		return "0.0"
+	}
+}
+
+// imageRepositoryInternal strips the tag and digest from an image reference.
+func imageRepositoryInternal(imageRef string) string {
+	repo, _, _ := strings.Cut(imageRef, "@")
+	if i := strings.LastIndex(repo, ":"); i > strings.LastIndex(repo, "/") {
+		repo = repo[:i]
+	}
+	return repo
+}
+
+// recordImageLicensesInternal replaces the stored licenses of the scanned
+// image with those found by the scan. Denied licenses that were not known
+// for the image or any other tag of its repository are reported through
+// notifications, so a newly pulled image that brings in a denied license is
+// flagged once.
+func (s *VulnerabilityService) recordImageLicensesInternal(ctx context.Context, result *vulnerability.ScanResult) {
+	if s.db == nil {
+		return
+	}
+
+	repo := imageRepositoryInternal(result.ImageName)
+	var known []string
+	if err := s.db.WithContext(ctx).Model(&models.ImageLicense{}).
+		Where("image_id = ? OR repository = ?", result.ImageID, repo).
+		Distinct().Pluck("name", &known).Error; err != nil {
+		slog.WarnContext(ctx, "failed to load known image licenses", "image", result.ImageName, "error", err)
+		return
+	}
+
+	rows := make([]models.ImageLicense, 0, len(result.Licenses))
+	for _, l := range result.Licenses {
+		if l.Name == "" {
+			continue
+		}
+		rows = append(rows, models.ImageLicense{
+			ImageID:    result.ImageID,
+			ImageName:  result.ImageName,
+			Repository: repo,
+			Name:       l.Name,
+			Category:   l.Category,
+			PkgName:    l.PkgName,
+			FilePath:   l.FilePath,
+		})
+	}
+
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// Licenses of removed images only serve to tell whether a license is
+		// new to the repository; drop them once a newer image was recorded.
+		if err := tx.Where("image_id = ? OR (repository = ? AND image_id NOT IN (?))",
+			result.ImageID, repo, tx.Model(&models.VulnerabilityScanRecord{}).Select("id")).
+			Delete(&models.ImageLicense{}).Error; err != nil {
+			return err
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(rows, 200).Error
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "failed to save image licenses", "image", result.ImageName, "error", err)
+		return
+	}
+
+	if s.notificationService == nil {
+		return
+	}
+	denylist := s.licenseDenylistInternal(ctx)
+	if len(denylist) == 0 {
+		return
+	}
+	payload := LicenseNotificationPayload{ImageName: result.ImageName}
+	seen := map[string]bool{}
+	for _, name := range known {
+		seen[name] = true
+	}
+	for _, row := range rows {
+		if !licenseDeniedInternal(row.Name, denylist) {
+			continue
+		}
+		if !seen[row.Name] {
+			seen[row.Name] = true
+			payload.Licenses = append(payload.Licenses, row.Name)
+		}
+		if slices.Contains(payload.Licenses, row.Name) {
+			payload.Packages = append(payload.Packages, fmt.Sprintf("%s (%s)", licensePackageInternal(row), row.Name))
+		}
+	}
+	if len(payload.Licenses) == 0 {
+		return
+	}
+	if err := s.notificationService.SendLicenseNotification(ctx, payload); err != nil {
+		slog.WarnContext(ctx, "failed to send license notification", "image", result.ImageName, "error", err)
+	}
+}
+
+// GetImageLicenseReport returns the licenses found in an image. It returns
+// nil when no licenses were recorded for the image.
+func (s *VulnerabilityService) GetImageLicenseReport(ctx context.Context, imageID string) (*vulnerability.ImageLicenseReport, error) {
+	if s.db == nil {
+		return nil, nil
+	}
+
+	var rows []models.ImageLicense
+	if err := s.db.WithContext(ctx).Where("image_id = ?", imageID).Find(&rows).Error; err != nil {
+		return nil, fmt.Errorf("failed to load image licenses: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	denylist := s.licenseDenylistInternal(ctx)
+	report := &vulnerability.ImageLicenseReport{
+		ImageID:   imageID,
+		ImageName: rows[0].ImageName,
+		ScanTime:  rows[0].CreatedAt,
+	}
+	byName := map[string]*vulnerability.LicenseUsage{}
+	for _, row := range rows {
+		usage, ok := byName[row.Name]
+		if !ok {
+			usage = &vulnerability.LicenseUsage{
+				Name:     row.Name,
+				Category: row.Category,
+				Denied:   licenseDeniedInternal(row.Name, denylist),
+				Packages: []string{},
+			}
+			byName[row.Name] = usage
+			if usage.Denied {
+				report.DeniedCount++
+			}
+		}
+		if pkg := licensePackageInternal(row); !slices.Contains(usage.Packages, pkg) {
+			usage.Packages = append(usage.Packages, pkg)
+		}
+	}
+
+	report.Licenses = make([]vulnerability.LicenseUsage, 0, len(byName))
+	for _, usage := range byName {
+		sort.Strings(usage.Packages)
+		report.Licenses = append(report.Licenses, *usage)
+	}
+	sort.Slice(report.Licenses, func(i, j int) bool {
+		a, b := report.Licenses[i], report.Licenses[j]
+		if a.Denied != b.Denied {
+			return a.Denied
+		}
+		return a.Name < b.Name
+	})
+	return report, nil
+}
+
+// GetEnvironmentLicenseReport returns the licenses found in all images that
+// have a scan result.
+func (s *VulnerabilityService) GetEnvironmentLicenseReport(ctx context.Context) (*vulnerability.EnvironmentLicenseReport, error) {
+	report := &vulnerability.EnvironmentLicenseReport{
+		Denylist: s.licenseDenylistInternal(ctx),
+		Licenses: []vulnerability.LicenseSummary{},
+	}
+	if report.Denylist == nil {
+		report.Denylist = []string{}
+	}
+	if s.db == nil {
+		return report, nil
+	}
+
+	var rows []models.ImageLicense
+	err := s.db.WithContext(ctx).
+		Where("image_id IN (?)", s.db.Model(&models.VulnerabilityScanRecord{}).Select("id")).
+		Find(&rows).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to load image licenses: %w", err)
+	}
+
+	images := map[string]bool{}
+	deniedImages := map[string]bool{}
+	byName := map[string]*vulnerability.LicenseSummary{}
+	for _, row := range rows {
+		images[row.ImageID] = true
+		summary, ok := byName[row.Name]
+		if !ok {
+			summary = &vulnerability.LicenseSummary{
+				Name:     row.Name,
+				Category: row.Category,
+				Denied:   licenseDeniedInternal(row.Name, report.Denylist),
+				Images:   []string{},
+			}
+			byName[row.Name] = summary
+		}
+		summary.PackageCount++
+		if !slices.Contains(summary.Images, row.ImageName) {
+			summary.Images = append(summary.Images, row.ImageName)
+		}
+		if summary.Denied {
+			deniedImages[row.ImageID] = true
+		}
+	}
+
+	report.ScannedImages = len(images)
+	report.DeniedImages = len(deniedImages)
+	for _, summary := range byName {
+		sort.Strings(summary.Images)
+		report.Licenses = append(report.Licenses, *summary)
+	}
+	sort.Slice(report.Licenses, func(i, j int) bool {
+		a, b := report.Licenses[i], report.Licenses[j]
+		if a.Denied != b.Denied {
+			return a.Denied
+		}
+		return a.Name < b.Name
+	})
+	return report, nil
+}
+
+func (s *VulnerabilityService) licenseDenylistInternal(ctx context.Context) []string {
+	if s.settingsService == nil {
+		return nil
+	}
+	return parseLicenseDenylistInternal(s.settingsService.GetStringSetting(ctx, "licenseDenylist", ""))
+}
+
+// parseLicenseDenylistInternal splits a comma or newline separated list of
+// license patterns.
+func parseLicenseDenylistInternal(value string) []string {
+	var patterns []string
+	for _, p := range strings.FieldsFunc(value, func(r rune) bool { return r == ',' || r == '\n' }) {
+		if p = strings.TrimSpace(p); p != "" {
+			patterns = append(patterns, p)
+		}
+	}
+	return patterns
+}
+
+// licenseDeniedInternal reports whether a license matches one of the
+// denylist patterns. Patterns are case-insensitive globs, so "AGPL-*"
+// matches both AGPL-3.0-only and AGPL-3.0-or-later.
+func licenseDeniedInternal(name string, patterns []string) bool {
+	name = strings.ToLower(name)
+	for _, pattern := range patterns {
+		if ok, _ := path.Match(strings.ToLower(pattern), name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// licensePackageInternal names what a license applies to: the package, or
+// the file for licenses found in license files.
+func licensePackageInternal(row models.ImageLicense) string {
+	if row.PkgName != "" {
+		return row.PkgName
+	}
+	return row.FilePath
+}
+
 // notifyVulnerabilitiesWithFix sends a notification for each vulnerability that has a fixed version.
 // It is called after a successful scan save. Notifications are sent asynchronously; errors are logged.
 // Notifications are suppressed for ignored vulnerabilities across all environments.
@@ -1439,23 +1898,19 @@ func (s *VulnerabilityService) filterIgnoredVulnerabilitiesForImage(
 	}
 
 	var ignores []models.VulnerabilityIgnore
-	if err := s.db.WithContext(ctx).Where("image_id = ?", imageID).Find(&ignores).Error; err != nil {
+	if err := s.activeIgnoresInternal(ctx).
+		Where("(image_id = ? OR scope IN ?)", imageID, wildcardIgnoreScopesInternal()).
+		Find(&ignores).Error; err != nil {
 		return nil, err
 	}
 	if len(ignores) == 0 {
 		return vulns, nil
 	}
 
-	ignoredKeys := make(map[string]struct{}, len(ignores))
-	for _, ignore := range ignores {
-		key := fmt.Sprintf("%s:%s:%s:%s", ignore.ImageID, ignore.VulnerabilityID, ignore.PkgName, ignore.InstalledVersion)
-		ignoredKeys[key] = struct{}{}
-	}
-
+	matcher := newIgnoreMatcherInternal(ignores)
 	filtered := make([]vulnerability.Vulnerability, 0, len(vulns))
 	for _, vuln := range vulns {
-		key := fmt.Sprintf("%s:%s:%s:%s", imageID, vuln.VulnerabilityID, vuln.PkgName, vuln.InstalledVersion)
-		if _, isIgnored := ignoredKeys[key]; isIgnored {
+		if matcher.matches(imageID, vuln.VulnerabilityID, vuln.PkgName, vuln.InstalledVersion) {
 			continue
 		}
 		filtered = append(filtered, vuln)
@@ -1596,21 +2051,127 @@ func stringPtrValueOrEmptyInternal(p *string) string {
 	return *p
 }
 
-// IgnoreVulnerability creates a new ignore record for a vulnerability
-func (s *VulnerabilityService) IgnoreVulnerability(ctx context.Context, envID string, payload *vulnerability.IgnorePayload) (*models.VulnerabilityIgnore, error) {
+// ignoreMatcher tests findings against a set of ignore rules.
+type ignoreMatcher struct {
+	exact    map[string]struct{}
+	cves     map[string]struct{}
+	packages map[string]struct{}
+}
+
+func newIgnoreMatcherInternal(ignores []models.VulnerabilityIgnore) *ignoreMatcher {
+	m := &ignoreMatcher{
+		exact:    map[string]struct{}{},
+		cves:     map[string]struct{}{},
+		packages: map[string]struct{}{},
+	}
+	for _, ignore := range ignores {
+		switch vulnerability.IgnoreScope(ignore.Scope) {
+		case vulnerability.IgnoreScopeCVE:
+			m.cves[ignore.VulnerabilityID] = struct{}{}
+		case vulnerability.IgnoreScopePackage:
+			m.packages[ignore.PkgName] = struct{}{}
+		default:
+			m.exact[vulnerabilityKeyInternal(ignore.ImageID, ignore.VulnerabilityID, ignore.PkgName, ignore.InstalledVersion)] = struct{}{}
+		}
+	}
+	return m
+}
+
+// matches reports whether any rule covers the finding.
+func (m *ignoreMatcher) matches(imageID, vulnerabilityID, pkgName, installedVersion string) bool {
+	if _, ok := m.cves[vulnerabilityID]; ok {
+		return true
+	}
+	if _, ok := m.packages[pkgName]; ok {
+		return true
+	}
+	_, ok := m.exact[vulnerabilityKeyInternal(imageID, vulnerabilityID, pkgName, installedVersion)]
+	return ok
+}
+
+func wildcardIgnoreScopesInternal() []string {
+	return []string{string(vulnerability.IgnoreScopeCVE), string(vulnerability.IgnoreScopePackage)}
+}
+
+// activeIgnoresInternal starts a query over ignore rules that have not
+// expired. Expired rules stop applying straight away, before
+// PurgeExpiredIgnores removes them.
+func (s *VulnerabilityService) activeIgnoresInternal(ctx context.Context) *gorm.DB {
+	return s.db.WithContext(ctx).Model(&models.VulnerabilityIgnore{}).
+		Where("(expires_at IS NULL OR expires_at > ?)", time.Now())
+}
+
+// normalizeIgnorePayloadInternal validates payload and clears the fields its
+// scope does not match on.
+func normalizeIgnorePayloadInternal(payload *vulnerability.IgnorePayload) error {
+	payload.ImageID = strings.TrimSpace(payload.ImageID)
+	payload.VulnerabilityID = strings.TrimSpace(payload.VulnerabilityID)
+	payload.PkgName = strings.TrimSpace(payload.PkgName)
+	payload.InstalledVersion = strings.TrimSpace(payload.InstalledVersion)
+
+	switch payload.Scope {
+	case "", vulnerability.IgnoreScopeExact:
+		payload.Scope = vulnerability.IgnoreScopeExact
+		if payload.ImageID == "" || payload.VulnerabilityID == "" || payload.PkgName == "" {
+			return fmt.Errorf("%w: exact rules need an image, a vulnerability and a package", ErrInvalidIgnoreRule)
+		}
+	case vulnerability.IgnoreScopeCVE:
+		if payload.VulnerabilityID == "" {
+			return fmt.Errorf("%w: cve rules need a vulnerability", ErrInvalidIgnoreRule)
+		}
+		payload.ImageID, payload.PkgName, payload.InstalledVersion = "", "", ""
+	case vulnerability.IgnoreScopePackage:
+		if payload.PkgName == "" {
+			return fmt.Errorf("%w: package rules need a package", ErrInvalidIgnoreRule)
+		}
+		payload.ImageID, payload.VulnerabilityID, payload.InstalledVersion = "", "", ""
+	default:
+		return fmt.Errorf("%w: unknown scope %q", ErrInvalidIgnoreRule, payload.Scope)
+	}
+
+	if payload.ExpiresInDays != nil && (*payload.ExpiresInDays < 1 || *payload.ExpiresInDays > maxIgnoreExpiryDays) {
+		return fmt.Errorf("%w: expiry must be between 1 and %d days", ErrInvalidIgnoreRule, maxIgnoreExpiryDays)
+	}
+	return nil
+}
+
+func newIgnoreAuditInternal(ignore *models.VulnerabilityIgnore, action vulnerability.IgnoreAuditAction, userID, username string) *models.VulnerabilityIgnoreAudit {
+	return &models.VulnerabilityIgnoreAudit{
+		IgnoreID:         ignore.ID,
+		EnvironmentID:    ignore.EnvironmentID,
+		Action:           string(action),
+		Scope:            ignore.Scope,
+		ImageID:          ignore.ImageID,
+		VulnerabilityID:  ignore.VulnerabilityID,
+		PkgName:          ignore.PkgName,
+		InstalledVersion: ignore.InstalledVersion,
+		Reason:           ignore.Reason,
+		ExpiresAt:        ignore.ExpiresAt,
+		UserID:           userID,
+		Username:         username,
+	}
+}
+
+// IgnoreVulnerability creates a new ignore rule. Exact rules hide one finding
+// in one image; cve and package rules hide a vulnerability or a package in
+// every image. The change is recorded in the ignore audit.
+func (s *VulnerabilityService) IgnoreVulnerability(ctx context.Context, envID string, payload *vulnerability.IgnorePayload, user models.User) (*models.VulnerabilityIgnore, error) {
 	if s.db == nil {
 		return nil, errors.New("database not available")
 	}
+	if err := normalizeIgnorePayloadInternal(payload); err != nil {
+		return nil, err
+	}
 
-	// Check if already ignored (composite key check)
+	// Check if an active rule already covers the same target
 	var existing models.VulnerabilityIgnore
-	err := s.db.WithContext(ctx).Where(
-		"environment_id = ? AND image_id = ? AND vulnerability_id = ? AND pkg_name = ? AND installed_version = ?",
-		envID, payload.ImageID, payload.VulnerabilityID, payload.PkgName, payload.InstalledVersion,
+	err := s.activeIgnoresInternal(ctx).Where(
+		"environment_id = ? AND scope = ? AND image_id = ? AND vulnerability_id = ? AND pkg_name = ? AND installed_version = ?",
+		envID, string(payload.Scope), payload.ImageID, payload.VulnerabilityID, payload.PkgName, payload.InstalledVersion,
 	).First(&existing).Error
 
 	if err == nil {
-		return nil, errors.New("vulnerability is already ignored")
+		return nil, ErrVulnerabilityAlreadyIgnored
 	}
 
 	if !errors.Is(err, gorm.ErrRecordNotFound) {
@@ -1619,44 +2180,101 @@ func (s *VulnerabilityService) IgnoreVulnerability(ctx context.Context, envID st
 
 	ignore := &models.VulnerabilityIgnore{
 		EnvironmentID:    envID,
+		Scope:            string(payload.Scope),
 		ImageID:          payload.ImageID,
 		VulnerabilityID:  payload.VulnerabilityID,
 		PkgName:          payload.PkgName,
 		InstalledVersion: payload.InstalledVersion,
 		Reason:           payload.Reason,
-		CreatedBy:        payload.CreatedBy,
+		CreatedBy:        user.ID,
+	}
+	if payload.ExpiresInDays != nil {
+		expiresAt := time.Now().AddDate(0, 0, *payload.ExpiresInDays)
+		ignore.ExpiresAt = &expiresAt
 	}
 
-	if err := s.db.WithContext(ctx).Create(ignore).Error; err != nil {
-		return nil, fmt.Errorf("failed to create ignore record: %w", err)
+	err = s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(ignore).Error; err != nil {
+			return fmt.Errorf("failed to create ignore record: %w", err)
+		}
+		if err := tx.Create(newIgnoreAuditInternal(ignore, vulnerability.IgnoreAuditActionCreated, user.ID, user.Username)).Error; err != nil {
+			return fmt.Errorf("failed to record ignore audit: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	slog.InfoContext(ctx, "vulnerability ignored",
-		"vulnerability_id", payload.VulnerabilityID,
-		"image_id", payload.ImageID,
-		"pkg_name", payload.PkgName,
+		"scope", ignore.Scope,
+		"vulnerability_id", ignore.VulnerabilityID,
+		"image_id", ignore.ImageID,
+		"pkg_name", ignore.PkgName,
+		"user", user.Username,
 	)
 
 	return ignore, nil
 }
 
-// UnignoreVulnerability removes an ignore record
-func (s *VulnerabilityService) UnignoreVulnerability(ctx context.Context, envID string, ignoreID string) error {
+// UnignoreVulnerability removes an ignore rule and records who removed it
+func (s *VulnerabilityService) UnignoreVulnerability(ctx context.Context, envID string, ignoreID string, user models.User) error {
 	if s.db == nil {
 		return errors.New("database not available")
 	}
 
-	result := s.db.WithContext(ctx).Where("id = ? AND environment_id = ?", ignoreID, envID).Delete(&models.VulnerabilityIgnore{})
-	if result.Error != nil {
-		return fmt.Errorf("failed to delete ignore record: %w", result.Error)
+	err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var ignore models.VulnerabilityIgnore
+		if err := tx.Where("id = ? AND environment_id = ?", ignoreID, envID).First(&ignore).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrIgnoreRecordNotFound
+			}
+			return fmt.Errorf("failed to load ignore record: %w", err)
+		}
+		if err := tx.Delete(&ignore).Error; err != nil {
+			return fmt.Errorf("failed to delete ignore record: %w", err)
+		}
+		if err := tx.Create(newIgnoreAuditInternal(&ignore, vulnerability.IgnoreAuditActionRemoved, user.ID, user.Username)).Error; err != nil {
+			return fmt.Errorf("failed to record ignore audit: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	if result.RowsAffected == 0 {
-		return errors.New("ignore record not found")
+	slog.InfoContext(ctx, "vulnerability unignored", "ignore_id", ignoreID, "user", user.Username)
+	return nil
+}
+
+// PurgeExpiredIgnores deletes ignore rules past their expiry and records each
+// one in the ignore audit. It returns the number of rules removed.
+func (s *VulnerabilityService) PurgeExpiredIgnores(ctx context.Context) (int, error) {
+	if s.db == nil {
+		return 0, nil
 	}
 
-	slog.InfoContext(ctx, "vulnerability unignored", "ignore_id", ignoreID)
-	return nil
+	var expired []models.VulnerabilityIgnore
+	if err := s.db.WithContext(ctx).Where("expires_at IS NOT NULL AND expires_at <= ?", time.Now()).Find(&expired).Error; err != nil {
+		return 0, fmt.Errorf("failed to load expired ignore records: %w", err)
+	}
+
+	purged := 0
+	for i := range expired {
+		ignore := &expired[i]
+		err := s.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			if err := tx.Delete(ignore).Error; err != nil {
+				return err
+			}
+			return tx.Create(newIgnoreAuditInternal(ignore, vulnerability.IgnoreAuditActionExpired, "", "")).Error
+		})
+		if err != nil {
+			return purged, fmt.Errorf("failed to purge expired ignore record %s: %w", ignore.ID, err)
+		}
+		purged++
+	}
+
+	return purged, nil
 }
 
 func applyIgnoredVulnerabilitiesSort(query *gorm.DB, sort string) *gorm.DB {
@@ -1707,6 +2325,7 @@ func mapIgnoredVulnerabilities(ignores []models.VulnerabilityIgnore) []vulnerabi
 		result[i] = vulnerability.IgnoredVulnerability{
 			ID:               ignore.ID,
 			EnvironmentID:    ignore.EnvironmentID,
+			Scope:            vulnerability.IgnoreScope(ignore.Scope),
 			ImageID:          ignore.ImageID,
 			VulnerabilityID:  ignore.VulnerabilityID,
 			PkgName:          ignore.PkgName,
@@ -1714,6 +2333,7 @@ func mapIgnoredVulnerabilities(ignores []models.VulnerabilityIgnore) []vulnerabi
 			Reason:           ignore.Reason,
 			CreatedBy:        ignore.CreatedBy,
 			CreatedAt:        ignore.CreatedAt,
+			ExpiresAt:        ignore.ExpiresAt,
 		}
 	}
 	return result
@@ -1728,7 +2348,7 @@ func buildIgnoredVulnerabilitiesResponse(total int64, params pagination.QueryPar
 	return pagination.BuildResponseFromFilterResult(filtered, params)
 }
 
-// ListIgnoredVulnerabilities returns a list of ignored vulnerabilities for an environment
+// ListIgnoredVulnerabilities returns a list of active ignore rules for an environment
 func (s *VulnerabilityService) ListIgnoredVulnerabilities(ctx context.Context, envID string, params pagination.QueryParams) ([]vulnerability.IgnoredVulnerability, pagination.Response, error) {
 	if params.Limit == 0 {
 		params.Limit = 20
@@ -1745,7 +2365,7 @@ func (s *VulnerabilityService) ListIgnoredVulnerabilities(ctx context.Context, e
 	}
 
 	var ignores []models.VulnerabilityIgnore
-	query := s.db.WithContext(ctx).Where("environment_id = ?", envID)
+	query := s.activeIgnoresInternal(ctx).Where("environment_id = ?", envID)
 	query = applyIgnoredVulnerabilitiesSort(query, params.Sort)
 
 	// Count total
@@ -1766,14 +2386,72 @@ func (s *VulnerabilityService) ListIgnoredVulnerabilities(ctx context.Context, e
 	return result, response, nil
 }
 
-// GetIgnoreRecordsForImage retrieves all ignore records for a specific image
+// ListIgnoreAudit returns the ignore audit for an environment, newest first
+func (s *VulnerabilityService) ListIgnoreAudit(ctx context.Context, envID string, params pagination.QueryParams) ([]vulnerability.IgnoreAuditEntry, pagination.Response, error) {
+	if params.Limit == 0 {
+		params.Limit = 20
+	}
+
+	if s.db == nil {
+		return []vulnerability.IgnoreAuditEntry{}, pagination.Response{
+			TotalPages:   1,
+			CurrentPage:  1,
+			ItemsPerPage: params.Limit,
+		}, nil
+	}
+
+	query := s.db.WithContext(ctx).Model(&models.VulnerabilityIgnoreAudit{}).Where("environment_id = ?", envID)
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, pagination.Response{}, fmt.Errorf("failed to count ignore audit entries: %w", err)
+	}
+
+	var entries []models.VulnerabilityIgnoreAudit
+	if err := query.Order("created_at DESC").Offset(params.Start).Limit(params.Limit).Find(&entries).Error; err != nil {
+		return nil, pagination.Response{}, fmt.Errorf("failed to list ignore audit entries: %w", err)
+	}
+
+	result := make([]vulnerability.IgnoreAuditEntry, len(entries))
+	for i, entry := range entries {
+		result[i] = vulnerability.IgnoreAuditEntry{
+			ID:               entry.ID,
+			IgnoreID:         entry.IgnoreID,
+			EnvironmentID:    entry.EnvironmentID,
+			Action:           vulnerability.IgnoreAuditAction(entry.Action),
+			Scope:            vulnerability.IgnoreScope(entry.Scope),
+			ImageID:          entry.ImageID,
+			VulnerabilityID:  entry.VulnerabilityID,
+			PkgName:          entry.PkgName,
+			InstalledVersion: entry.InstalledVersion,
+			Reason:           entry.Reason,
+			ExpiresAt:        entry.ExpiresAt,
+			UserID:           entry.UserID,
+			Username:         entry.Username,
+			CreatedAt:        entry.CreatedAt,
+		}
+	}
+
+	response := pagination.BuildResponseFromFilterResult(pagination.FilterResult[models.VulnerabilityIgnoreAudit]{
+		Items:          entries,
+		TotalCount:     total,
+		TotalAvailable: total,
+	}, params)
+
+	return result, response, nil
+}
+
+// GetIgnoreRecordsForImage retrieves the active ignore rules that apply to a
+// specific image, including cve and package rules
 func (s *VulnerabilityService) GetIgnoreRecordsForImage(ctx context.Context, envID string, imageID string) ([]models.VulnerabilityIgnore, error) {
 	if s.db == nil {
 		return nil, nil
 	}
 
 	var ignores []models.VulnerabilityIgnore
-	if err := s.db.WithContext(ctx).Where("environment_id = ? AND image_id = ?", envID, imageID).Find(&ignores).Error; err != nil {
+	if err := s.activeIgnoresInternal(ctx).
+		Where("environment_id = ? AND (image_id = ? OR scope IN ?)", envID, imageID, wildcardIgnoreScopesInternal()).
+		Find(&ignores).Error; err != nil {
 		return nil, fmt.Errorf("failed to get ignore records: %w", err)
 	}
 
@@ -1786,9 +2464,9 @@ func (s *VulnerabilityService) filterIgnoredVulnerabilities(ctx context.Context,
 		return vulns, nil
 	}
 
-	// Get all ignore records for this environment
+	// Get all active ignore rules for this environment
 	var ignores []models.VulnerabilityIgnore
-	if err := s.db.WithContext(ctx).Where("environment_id = ?", envID).Find(&ignores).Error; err != nil {
+	if err := s.activeIgnoresInternal(ctx).Where("environment_id = ?", envID).Find(&ignores).Error; err != nil {
 		return nil, fmt.Errorf("failed to get ignore records: %w", err)
 	}
 
@@ -1796,18 +2474,11 @@ func (s *VulnerabilityService) filterIgnoredVulnerabilities(ctx context.Context,
 		return vulns, nil
 	}
 
-	// Build a set of ignored vulnerability keys
-	ignoredKeys := make(map[string]struct{}, len(ignores))
-	for _, ignore := range ignores {
-		key := fmt.Sprintf("%s:%s:%s:%s", ignore.ImageID, ignore.VulnerabilityID, ignore.PkgName, ignore.InstalledVersion)
-		ignoredKeys[key] = struct{}{}
-	}
-
 	// Filter out ignored vulnerabilities
+	matcher := newIgnoreMatcherInternal(ignores)
 	filtered := make([]vulnerability.VulnerabilityWithImage, 0, len(vulns))
 	for _, vuln := range vulns {
-		key := fmt.Sprintf("%s:%s:%s:%s", vuln.ImageID, vuln.VulnerabilityID, vuln.PkgName, vuln.InstalledVersion)
-		if _, isIgnored := ignoredKeys[key]; !isIgnored {
+		if !matcher.matches(vuln.ImageID, vuln.VulnerabilityID, vuln.PkgName, vuln.InstalledVersion) {
 			filtered = append(filtered, vuln)
 		}
 	}
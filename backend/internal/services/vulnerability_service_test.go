@@ -0,0 +1,281 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/config"
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/pagination"
+	"github.com/getarcaneapp/arcane/types/vulnerability"
+)
+
+func TestImageRepository(t *testing.T) {
+	assert.Equal(t, "nginx", imageRepositoryInternal("nginx:1.27"))
+	assert.Equal(t, "localhost:5000/app", imageRepositoryInternal("localhost:5000/app:v1"))
+	assert.Equal(t, "localhost:5000/app", imageRepositoryInternal("localhost:5000/app"))
+	assert.Equal(t, "ghcr.io/org/app", imageRepositoryInternal("ghcr.io/org/app:v1@sha256:abc"))
+}
+
+func TestVulnerabilityService_ExportScanSARIF(t *testing.T) {
+	ctx := context.Background()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.VulnerabilityScanRecord{}, &models.VulnerabilityIgnore{}))
+	svc := &VulnerabilityService{db: &database.DB{DB: gdb}}
+
+	sarif, err := svc.ExportScanSARIF(ctx, "sha256:missing")
+	require.NoError(t, err)
+	assert.Nil(t, sarif)
+
+	require.NoError(t, svc.saveScanResult(ctx, &vulnerability.ScanResult{
+		ImageID:        "sha256:img",
+		ImageName:      "ghcr.io/org/app:v1",
+		ScanTime:       time.Now(),
+		Status:         vulnerability.ScanStatusCompleted,
+		ScannerVersion: "0.58.0",
+		Vulnerabilities: []vulnerability.Vulnerability{
+			{VulnerabilityID: "CVE-2024-0001", PkgName: "openssl", InstalledVersion: "3.0.1", FixedVersion: "3.0.2",
+				Severity: vulnerability.SeverityCritical, Title: "openssl overflow", CVSS: &vulnerability.CVSSInfo{V3Score: 9.8}},
+			{VulnerabilityID: "CVE-2024-0001", PkgName: "libssl3", InstalledVersion: "3.0.1", Severity: vulnerability.SeverityCritical},
+			{VulnerabilityID: "GHSA-xxxx", PkgName: "lodash", InstalledVersion: "4.17.0", Location: "app/package-lock.json",
+				Severity: vulnerability.SeverityMedium, References: []string{"https://github.com/advisories/GHSA-xxxx"}},
+			{VulnerabilityID: "CVE-2024-0002", PkgName: "zlib", InstalledVersion: "1.2", Severity: vulnerability.SeverityLow},
+		},
+	}))
+	require.NoError(t, gdb.Create(&models.VulnerabilityIgnore{ID: "i1", ImageID: "sha256:img", VulnerabilityID: "CVE-2024-0002", PkgName: "zlib", InstalledVersion: "1.2"}).Error)
+
+	sarif, err = svc.ExportScanSARIF(ctx, "sha256:img")
+	require.NoError(t, err)
+	require.NotNil(t, sarif)
+	assert.Equal(t, "2.1.0", sarif.Version)
+	require.Len(t, sarif.Runs, 1)
+	run := sarif.Runs[0]
+	assert.Equal(t, "0.58.0", run.Tool.Driver.Version)
+
+	// The ignored zlib finding is left out and CVE-2024-0001 shares a rule.
+	require.Len(t, run.Tool.Driver.Rules, 2)
+	require.Len(t, run.Results, 3)
+
+	cve := run.Tool.Driver.Rules[0]
+	assert.Equal(t, "CVE-2024-0001", cve.ID)
+	assert.Equal(t, "OsPackageVulnerability", cve.Name)
+	assert.Equal(t, "error", cve.DefaultConfiguration.Level)
+	assert.Equal(t, "9.8", cve.Properties.SecuritySeverity)
+	assert.Equal(t, "https://nvd.nist.gov/vuln/detail/CVE-2024-0001", cve.HelpURI)
+
+	ghsa := run.Tool.Driver.Rules[1]
+	assert.Equal(t, "LanguageSpecificPackageVulnerability", ghsa.Name)
+	assert.Equal(t, "warning", ghsa.DefaultConfiguration.Level)
+	assert.Equal(t, "5.5", ghsa.Properties.SecuritySeverity)
+	assert.Equal(t, "https://github.com/advisories/GHSA-xxxx", ghsa.HelpURI)
+
+	assert.Equal(t, 0, run.Results[1].RuleIndex)
+	assert.Equal(t, "ghcr.io/org/app", run.Results[0].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, "ghcr.io/org/app:v1: libssl3@3.0.1", run.Results[1].Locations[0].Message.Text)
+	assert.Equal(t, 1, run.Results[2].RuleIndex)
+	assert.Equal(t, "app/package-lock.json", run.Results[2].Locations[0].PhysicalLocation.ArtifactLocation.URI)
+	assert.Equal(t, 1, run.Results[2].Locations[0].PhysicalLocation.Region.StartLine)
+
+	require.NoError(t, gdb.Model(&models.VulnerabilityScanRecord{}).Where("id = ?", "sha256:img").Update("status", models.ScanStatusFailed).Error)
+	_, err = svc.ExportScanSARIF(ctx, "sha256:img")
+	require.ErrorIs(t, err, ErrVulnerabilityScanIncomplete)
+}
+
+func TestLicenseDenied(t *testing.T) {
+	patterns := parseLicenseDenylistInternal(" AGPL-*, SSPL-1.0\nGPL-3.0-only,, ")
+	assert.Equal(t, []string{"AGPL-*", "SSPL-1.0", "GPL-3.0-only"}, patterns)
+
+	assert.True(t, licenseDeniedInternal("AGPL-3.0-or-later", patterns))
+	assert.True(t, licenseDeniedInternal("agpl-3.0-only", patterns))
+	assert.True(t, licenseDeniedInternal("SSPL-1.0", patterns))
+	assert.False(t, licenseDeniedInternal("GPL-3.0-or-later", patterns))
+	assert.False(t, licenseDeniedInternal("MIT", patterns))
+	assert.False(t, licenseDeniedInternal("AGPL-3.0", nil))
+}
+
+func TestVulnerabilityService_LicenseReports(t *testing.T) {
+	ctx := context.Background()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.SettingVariable{}, &models.VulnerabilityScanRecord{}, &models.ImageLicense{},
+		&models.NotificationSettings{}, &models.NotificationLog{}))
+	db := &database.DB{DB: gdb}
+	settingsService, err := NewSettingsService(ctx, db)
+	require.NoError(t, err)
+	require.NoError(t, settingsService.EnsureDefaultSettings(ctx))
+	require.NoError(t, settingsService.SetStringSetting(ctx, "licenseDenylist", "AGPL-*"))
+	// The provider is unreachable, so every notification is logged as failed.
+	require.NoError(t, gdb.Create(&models.NotificationSettings{
+		Provider: models.NotificationProviderGeneric,
+		Enabled:  true,
+		Config:   models.JSON{"webhookUrl": "http://127.0.0.1:1/hook"},
+	}).Error)
+	svc := &VulnerabilityService{db: db, settingsService: settingsService, notificationService: NewNotificationService(db, &config.Config{})}
+
+	scan := func(imageID, imageName string, licenses ...vulnerability.License) {
+		t.Helper()
+		result := &vulnerability.ScanResult{ImageID: imageID, ImageName: imageName, ScanTime: time.Now(),
+			Status: vulnerability.ScanStatusCompleted, Licenses: licenses}
+		require.NoError(t, svc.saveScanResult(ctx, result))
+		svc.recordImageLicensesInternal(ctx, result)
+	}
+	notified := func() []models.NotificationLog {
+		t.Helper()
+		var logs []models.NotificationLog
+		require.NoError(t, gdb.Order("id").Find(&logs).Error)
+		return logs
+	}
+
+	scan("sha256:a1", "app:1",
+		vulnerability.License{Name: "MIT", PkgName: "zlib"},
+		vulnerability.License{Name: "AGPL-3.0-only", Category: "restricted", PkgName: "ghostscript"},
+		vulnerability.License{Name: "MIT", FilePath: "app/LICENSE"})
+	logs := notified()
+	require.Len(t, logs, 1)
+	assert.Equal(t, "app:1", logs[0].ImageRef)
+	assert.Equal(t, string(models.NotificationEventLicenseDenied), logs[0].Metadata["eventType"])
+
+	// Rescans and new tags of the same repository introduce nothing new.
+	scan("sha256:a1", "app:1",
+		vulnerability.License{Name: "AGPL-3.0-only", PkgName: "ghostscript"},
+		vulnerability.License{Name: "MIT", PkgName: "zlib"},
+		vulnerability.License{Name: "MIT", FilePath: "app/LICENSE"})
+	scan("sha256:a2", "app:2", vulnerability.License{Name: "AGPL-3.0-only", PkgName: "ghostscript"})
+	assert.Len(t, notified(), 1)
+
+	// A new image bringing in a denied license is reported.
+	scan("sha256:b1", "other:latest",
+		vulnerability.License{Name: "AGPL-3.0-or-later", PkgName: "mongo"},
+		vulnerability.License{Name: "Apache-2.0", PkgName: "x"})
+	assert.Len(t, notified(), 2)
+
+	report, err := svc.GetImageLicenseReport(ctx, "sha256:a1")
+	require.NoError(t, err)
+	require.NotNil(t, report)
+	assert.Equal(t, "app:1", report.ImageName)
+	assert.Equal(t, 1, report.DeniedCount)
+	require.Len(t, report.Licenses, 2)
+	assert.Equal(t, "AGPL-3.0-only", report.Licenses[0].Name)
+	assert.True(t, report.Licenses[0].Denied)
+	assert.Equal(t, []string{"app/LICENSE", "zlib"}, report.Licenses[1].Packages)
+
+	report, err = svc.GetImageLicenseReport(ctx, "sha256:missing")
+	require.NoError(t, err)
+	assert.Nil(t, report)
+
+	// Images without a scan record are left out of the environment report.
+	require.NoError(t, svc.DeleteScanResult(ctx, "sha256:a2"))
+	env, err := svc.GetEnvironmentLicenseReport(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"AGPL-*"}, env.Denylist)
+	assert.Equal(t, 2, env.ScannedImages)
+	assert.Equal(t, 2, env.DeniedImages)
+	require.Len(t, env.Licenses, 4)
+	assert.Equal(t, "AGPL-3.0-only", env.Licenses[0].Name)
+	assert.Equal(t, []string{"app:1"}, env.Licenses[0].Images)
+	assert.Equal(t, "AGPL-3.0-or-later", env.Licenses[1].Name)
+	assert.Equal(t, "Apache-2.0", env.Licenses[2].Name)
+	assert.False(t, env.Licenses[2].Denied)
+	assert.Equal(t, "MIT", env.Licenses[3].Name)
+	assert.Equal(t, 2, env.Licenses[3].PackageCount)
+}
+
+func TestVulnerabilityService_IgnoreRules(t *testing.T) {
+	ctx := context.Background()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.VulnerabilityIgnore{}, &models.VulnerabilityIgnoreAudit{}))
+	svc := &VulnerabilityService{db: &database.DB{DB: gdb}}
+	user := models.User{BaseModel: models.BaseModel{ID: "u1"}, Username: "admin"}
+
+	vulns := []vulnerability.Vulnerability{
+		{VulnerabilityID: "CVE-2024-0001", PkgName: "openssl", InstalledVersion: "3.0.1"},
+		{VulnerabilityID: "CVE-2024-0002", PkgName: "zlib", InstalledVersion: "1.2"},
+		{VulnerabilityID: "CVE-2024-0003", PkgName: "curl", InstalledVersion: "8.0"},
+		{VulnerabilityID: "CVE-2024-0004", PkgName: "bash", InstalledVersion: "5.2"},
+	}
+	remaining := func(imageID string) []string {
+		t.Helper()
+		filtered, err := svc.filterIgnoredVulnerabilitiesForImage(ctx, imageID, vulns)
+		require.NoError(t, err)
+		ids := make([]string, len(filtered))
+		for i, v := range filtered {
+			ids[i] = v.VulnerabilityID
+		}
+		return ids
+	}
+
+	_, err = svc.IgnoreVulnerability(ctx, "0", &vulnerability.IgnorePayload{Scope: "image"}, user)
+	require.ErrorIs(t, err, ErrInvalidIgnoreRule)
+	_, err = svc.IgnoreVulnerability(ctx, "0", &vulnerability.IgnorePayload{VulnerabilityID: "CVE-2024-0001", PkgName: "openssl"}, user)
+	require.ErrorIs(t, err, ErrInvalidIgnoreRule)
+	days := 0
+	_, err = svc.IgnoreVulnerability(ctx, "0", &vulnerability.IgnorePayload{Scope: vulnerability.IgnoreScopeCVE, VulnerabilityID: "CVE-2024-0001", ExpiresInDays: &days}, user)
+	require.ErrorIs(t, err, ErrInvalidIgnoreRule)
+
+	_, err = svc.IgnoreVulnerability(ctx, "0", &vulnerability.IgnorePayload{ImageID: "sha256:a", VulnerabilityID: "CVE-2024-0001", PkgName: "openssl", InstalledVersion: "3.0.1"}, user)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CVE-2024-0002", "CVE-2024-0003", "CVE-2024-0004"}, remaining("sha256:a"))
+	assert.Len(t, remaining("sha256:b"), 4)
+
+	// A cve rule applies to every image and drops the image and package it was created from.
+	cve, err := svc.IgnoreVulnerability(ctx, "0", &vulnerability.IgnorePayload{Scope: vulnerability.IgnoreScopeCVE, ImageID: "sha256:a", VulnerabilityID: "CVE-2024-0002", PkgName: "zlib"}, user)
+	require.NoError(t, err)
+	assert.Empty(t, cve.ImageID)
+	assert.Empty(t, cve.PkgName)
+	_, err = svc.IgnoreVulnerability(ctx, "0", &vulnerability.IgnorePayload{Scope: vulnerability.IgnoreScopeCVE, VulnerabilityID: "CVE-2024-0002"}, user)
+	require.ErrorIs(t, err, ErrVulnerabilityAlreadyIgnored)
+
+	week := 7
+	pkg, err := svc.IgnoreVulnerability(ctx, "0", &vulnerability.IgnorePayload{Scope: vulnerability.IgnoreScopePackage, PkgName: "curl", ExpiresInDays: &week}, user)
+	require.NoError(t, err)
+	require.NotNil(t, pkg.ExpiresAt)
+	assert.WithinDuration(t, time.Now().AddDate(0, 0, 7), *pkg.ExpiresAt, time.Minute)
+	assert.Equal(t, []string{"CVE-2024-0001", "CVE-2024-0004"}, remaining("sha256:b"))
+
+	// Once expired the package rule stops applying, then the purge removes it.
+	require.NoError(t, gdb.Model(&models.VulnerabilityIgnore{}).Where("id = ?", pkg.ID).Update("expires_at", time.Now().Add(-time.Hour)).Error)
+	assert.Equal(t, []string{"CVE-2024-0001", "CVE-2024-0003", "CVE-2024-0004"}, remaining("sha256:b"))
+	listed, _, err := svc.ListIgnoredVulnerabilities(ctx, "0", pagination.QueryParams{})
+	require.NoError(t, err)
+	assert.Len(t, listed, 2)
+
+	purged, err := svc.PurgeExpiredIgnores(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 1, purged)
+
+	require.ErrorIs(t, svc.UnignoreVulnerability(ctx, "0", "missing", user), ErrIgnoreRecordNotFound)
+	require.NoError(t, svc.UnignoreVulnerability(ctx, "0", cve.ID, user))
+	assert.Len(t, remaining("sha256:b"), 4)
+
+	audit, resp, err := svc.ListIgnoreAudit(ctx, "0", pagination.QueryParams{})
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), resp.TotalItems)
+	actions := map[vulnerability.IgnoreAuditAction]int{}
+	for _, entry := range audit {
+		actions[entry.Action]++
+	}
+	assert.Equal(t, map[vulnerability.IgnoreAuditAction]int{
+		vulnerability.IgnoreAuditActionCreated: 3,
+		vulnerability.IgnoreAuditActionExpired: 1,
+		vulnerability.IgnoreAuditActionRemoved: 1,
+	}, actions)
+	for _, entry := range audit {
+		if entry.Action == vulnerability.IgnoreAuditActionRemoved {
+			assert.Equal(t, cve.ID, entry.IgnoreID)
+			assert.Equal(t, "admin", entry.Username)
+		}
+		if entry.Action == vulnerability.IgnoreAuditActionExpired {
+			assert.Empty(t, entry.UserID)
+			assert.Equal(t, "curl", entry.PkgName)
+		}
+	}
+}
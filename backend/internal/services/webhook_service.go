@@ -0,0 +1,518 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+	"time"
+
+	ref "go.podman.io/image/v5/docker/reference"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	webhooktypes "github.com/getarcaneapp/arcane/types/webhook"
+)
+
+const (
+	WebhookTypeGeneric = "generic"
+	WebhookTypeHarbor  = "harbor"
+	WebhookTypeGitHub  = "github"
+
+	WebhookActionNone     = "none"
+	WebhookActionRedeploy = "redeploy"
+
+	WebhookEventPush  = "push"
+	WebhookEventBuild = "build"
+	WebhookEventOther = "other"
+
+	webhookTokenPrefix    = "awh_"
+	webhookTokenLength    = 32
+	webhookTokenPrefixLen = 8
+)
+
+var (
+	ErrWebhookSourceNotFound = errors.New("webhook source not found")
+	ErrInvalidWebhookSource  = errors.New("invalid webhook source")
+	ErrInvalidWebhookToken   = errors.New("invalid webhook token")
+	ErrInvalidWebhookPayload = errors.New("invalid webhook payload")
+)
+
+// webhookBuildSuccessStates are the build statuses of generic sources that
+// count as a successful build.
+var webhookBuildSuccessStates = map[string]struct{}{"success": {}, "succeeded": {}, "passed": {}, "completed": {}}
+
+// WebhookService accepts events posted by external systems, such as a CI
+// pipeline finishing a build or a registry receiving a push, and records
+// them as Arcane events. Each source authenticates with its own token and
+// can redeploy a project when one of its events matches. Registry pushes
+// also re-check the pushed image for updates right away.
+type WebhookService struct {
+	db               *database.DB
+	eventService     *EventService
+	redeployProject  func(ctx context.Context, projectID string, user models.User) error
+	checkPushedImage func(ctx context.Context, imageRef string) ([]string, error)
+}
+
+func NewWebhookService(db *database.DB, eventService *EventService, projectService *ProjectService, imageUpdateService *ImageUpdateService) *WebhookService {
+	s := &WebhookService{
+		db:           db,
+		eventService: eventService,
+	}
+	if projectService != nil {
+		s.redeployProject = projectService.RedeployProject
+	}
+	if imageUpdateService != nil {
+		s.checkPushedImage = imageUpdateService.CheckPushedImage
+	}
+	return s
+}
+
+// webhookEvent is an incoming payload reduced to what Arcane acts on.
+type webhookEvent struct {
+	Kind    string
+	Name    string
+	Image   string
+	Status  string
+	Success bool
+}
+
+// ListSources returns all webhook sources.
+func (s *WebhookService) ListSources(ctx context.Context) ([]webhooktypes.Source, error) {
+	var sources []models.WebhookSource
+	if err := s.db.WithContext(ctx).Order("name ASC").Find(&sources).Error; err != nil {
+		return nil, fmt.Errorf("failed to list webhook sources: %w", err)
+	}
+
+	out := make([]webhooktypes.Source, 0, len(sources))
+	for _, src := range sources {
+		out = append(out, toWebhookSourceDTO(src))
+	}
+	return out, nil
+}
+
+// GetSource returns a single webhook source.
+func (s *WebhookService) GetSource(ctx context.Context, id string) (*webhooktypes.Source, error) {
+	source, err := s.loadSourceInternal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	out := toWebhookSourceDTO(*source)
+	return &out, nil
+}
+
+// CreateSource adds a webhook source and returns it with its token. The
+// token is not stored and cannot be read back later.
+func (s *WebhookService) CreateSource(ctx context.Context, req webhooktypes.Request, user models.User) (*webhooktypes.SourceWithToken, error) {
+	var source models.WebhookSource
+	if err := s.applyRequestInternal(ctx, &source, req, user); err != nil {
+		return nil, err
+	}
+	token, err := setWebhookTokenInternal(&source)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.db.WithContext(ctx).Create(&source).Error; err != nil {
+		return nil, fmt.Errorf("failed to create webhook source: %w", err)
+	}
+	return &webhooktypes.SourceWithToken{Source: toWebhookSourceDTO(source), Token: token}, nil
+}
+
+// UpdateSource replaces the definition of a webhook source. Its token is
+// kept.
+func (s *WebhookService) UpdateSource(ctx context.Context, id string, req webhooktypes.Request, user models.User) (*webhooktypes.Source, error) {
+	source, err := s.loadSourceInternal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.applyRequestInternal(ctx, source, req, user); err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	source.UpdatedAt = &now
+	if err := s.db.WithContext(ctx).Save(source).Error; err != nil {
+		return nil, fmt.Errorf("failed to update webhook source: %w", err)
+	}
+	out := toWebhookSourceDTO(*source)
+	return &out, nil
+}
+
+// RotateToken replaces the token of a webhook source. The old token stops
+// working immediately.
+func (s *WebhookService) RotateToken(ctx context.Context, id string, user models.User) (*webhooktypes.SourceWithToken, error) {
+	source, err := s.loadSourceInternal(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	token, err := setWebhookTokenInternal(source)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	source.UpdatedBy = user.Username
+	source.UpdatedAt = &now
+	if err := s.db.WithContext(ctx).Save(source).Error; err != nil {
+		return nil, fmt.Errorf("failed to rotate webhook token: %w", err)
+	}
+	return &webhooktypes.SourceWithToken{Source: toWebhookSourceDTO(*source), Token: token}, nil
+}
+
+// DeleteSource removes a webhook source.
+func (s *WebhookService) DeleteSource(ctx context.Context, id string) error {
+	result := s.db.WithContext(ctx).Where("id = ?", id).Delete(&models.WebhookSource{})
+	if result.Error != nil {
+		return fmt.Errorf("failed to delete webhook source: %w", result.Error)
+	}
+	if result.RowsAffected == 0 {
+		return ErrWebhookSourceNotFound
+	}
+	return nil
+}
+
+// Ingest handles an event posted with a source token. githubEvent is the
+// X-GitHub-Event header, used by github sources. The event is logged, local
+// copies of a pushed image are checked for updates, and the source's rule is
+// started when the event is a push or a successful build for an image
+// matching the source's filter. Update checks and rules run in the
+// background.
+func (s *WebhookService) Ingest(ctx context.Context, token, githubEvent string, payload []byte) (*webhooktypes.IngestResult, error) {
+	var source models.WebhookSource
+	err := s.db.WithContext(ctx).Where("token_hash = ?", hashWebhookTokenInternal(token)).First(&source).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidWebhookToken
+		}
+		return nil, fmt.Errorf("failed to load webhook source: %w", err)
+	}
+	if !source.Enabled {
+		return nil, ErrInvalidWebhookToken
+	}
+
+	event, err := parseWebhookPayloadInternal(source.Type, githubEvent, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	err = s.db.WithContext(ctx).Model(&source).
+		Updates(map[string]any{"last_event": event.Kind, "last_received_at": now}).Error
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to save webhook source state", "source", source.Name, "error", err)
+	}
+	s.logEventInternal(ctx, models.EventTypeWebhookReceived, &source, event)
+
+	result := &webhooktypes.IngestResult{Event: event.Kind, Image: event.Image}
+	if event.Kind == WebhookEventPush && event.Image != "" && s.checkPushedImage != nil {
+		result.UpdateCheck = true
+		go func() {
+			bgCtx := context.WithoutCancel(ctx)
+			refs, err := s.checkPushedImage(bgCtx, event.Image)
+			if err != nil {
+				slog.WarnContext(bgCtx, "Webhook update check failed", "source", source.Name, "image", event.Image, "error", err)
+				return
+			}
+			slog.InfoContext(bgCtx, "Checked pushed image for updates", "source", source.Name, "image", event.Image, "localTags", len(refs))
+		}()
+	}
+
+	if !webhookRuleMatchesInternal(&source, event) || s.redeployProject == nil {
+		return result, nil
+	}
+
+	result.Triggered = true
+	result.Action = source.Action
+	s.logEventInternal(ctx, models.EventTypeWebhookTriggered, &source, event)
+
+	projectID := *source.ProjectID
+	go func() {
+		// The sender does not wait for the redeploy to finish.
+		bgCtx := context.WithoutCancel(ctx)
+		if err := s.redeployProject(bgCtx, projectID, systemUser); err != nil {
+			slog.ErrorContext(bgCtx, "Webhook redeploy failed", "source", source.Name, "projectId", projectID, "error", err)
+		}
+	}()
+	return result, nil
+}
+
+func (s *WebhookService) logEventInternal(ctx context.Context, eventType models.EventType, source *models.WebhookSource, event webhookEvent) {
+	if s.eventService == nil {
+		return
+	}
+
+	resourceType := "webhook"
+	metadata := models.JSON{
+		"sourceType": source.Type,
+		"event":      event.Kind,
+	}
+	if event.Name != "" {
+		metadata["name"] = event.Name
+	}
+	if event.Image != "" {
+		metadata["image"] = event.Image
+	}
+	if event.Status != "" {
+		metadata["status"] = event.Status
+	}
+	if eventType == models.EventTypeWebhookTriggered {
+		metadata["action"] = source.Action
+		metadata["projectId"] = *source.ProjectID
+	}
+
+	environmentID := "0"
+	_, err := s.eventService.CreateEvent(ctx, CreateEventRequest{
+		Type:          eventType,
+		Severity:      s.eventService.getEventSeverity(eventType),
+		Title:         s.eventService.generateEventTitle(eventType, source.Name),
+		Description:   s.eventService.generateEventDescription(eventType, resourceType, source.Name),
+		ResourceType:  &resourceType,
+		ResourceID:    &source.ID,
+		ResourceName:  &source.Name,
+		UserID:        &systemUser.ID,
+		Username:      &systemUser.Username,
+		EnvironmentID: &environmentID,
+		Metadata:      metadata,
+	})
+	if err != nil {
+		slog.WarnContext(ctx, "Could not log webhook event", "source", source.Name, "error", err)
+	}
+}
+
+func (s *WebhookService) loadSourceInternal(ctx context.Context, id string) (*models.WebhookSource, error) {
+	var source models.WebhookSource
+	if err := s.db.WithContext(ctx).Where("id = ?", id).First(&source).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrWebhookSourceNotFound
+		}
+		return nil, fmt.Errorf("failed to load webhook source: %w", err)
+	}
+	return &source, nil
+}
+
+// applyRequestInternal validates req and copies it onto source.
+func (s *WebhookService) applyRequestInternal(ctx context.Context, source *models.WebhookSource, req webhooktypes.Request, user models.User) error {
+	req, err := normalizeWebhookRequestInternal(req)
+	if err != nil {
+		return err
+	}
+
+	var projectID *string
+	if req.ProjectID != "" {
+		var count int64
+		if err := s.db.WithContext(ctx).Model(&models.Project{}).Where("id = ?", req.ProjectID).Count(&count).Error; err != nil {
+			return fmt.Errorf("failed to look up project: %w", err)
+		}
+		if count == 0 {
+			return fmt.Errorf("%w: project %s does not exist", ErrInvalidWebhookSource, req.ProjectID)
+		}
+		projectID = &req.ProjectID
+	}
+
+	source.Name = req.Name
+	source.Type = req.Type
+	source.Action = req.Action
+	source.ProjectID = projectID
+	source.ImageFilter = req.ImageFilter
+	source.Enabled = req.Enabled == nil || *req.Enabled
+	source.UpdatedBy = user.Username
+	return nil
+}
+
+// normalizeWebhookRequestInternal trims req, fills in defaults and rejects
+// invalid definitions.
+func normalizeWebhookRequestInternal(req webhooktypes.Request) (webhooktypes.Request, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.Type = strings.ToLower(strings.TrimSpace(req.Type))
+	req.Action = strings.ToLower(strings.TrimSpace(req.Action))
+	req.ProjectID = strings.TrimSpace(req.ProjectID)
+	req.ImageFilter = strings.TrimSpace(req.ImageFilter)
+
+	if req.Name == "" {
+		return req, fmt.Errorf("%w: name is required", ErrInvalidWebhookSource)
+	}
+	switch req.Type {
+	case WebhookTypeGeneric, WebhookTypeHarbor, WebhookTypeGitHub:
+	default:
+		return req, fmt.Errorf("%w: type must be generic, harbor or github", ErrInvalidWebhookSource)
+	}
+	switch req.Action {
+	case "":
+		req.Action = WebhookActionNone
+	case WebhookActionNone:
+	case WebhookActionRedeploy:
+		if req.ProjectID == "" {
+			return req, fmt.Errorf("%w: redeploy needs a project", ErrInvalidWebhookSource)
+		}
+	default:
+		return req, fmt.Errorf("%w: action must be none or redeploy", ErrInvalidWebhookSource)
+	}
+	if _, err := path.Match(req.ImageFilter, ""); err != nil {
+		return req, fmt.Errorf("%w: image filter %q is not a valid pattern", ErrInvalidWebhookSource, req.ImageFilter)
+	}
+	return req, nil
+}
+
+// parseWebhookPayloadInternal reads a payload in the format of the given
+// source type.
+func parseWebhookPayloadInternal(sourceType, githubEvent string, payload []byte) (webhookEvent, error) {
+	switch sourceType {
+	case WebhookTypeHarbor:
+		var body struct {
+			Type      string `json:"type"`
+			EventData struct {
+				Resources []struct {
+					ResourceURL string `json:"resource_url"`
+				} `json:"resources"`
+			} `json:"event_data"`
+		}
+		if err := json.Unmarshal(payload, &body); err != nil {
+			return webhookEvent{}, fmt.Errorf("%w: %w", ErrInvalidWebhookPayload, err)
+		}
+		event := webhookEvent{Kind: WebhookEventOther, Name: body.Type}
+		if len(body.EventData.Resources) > 0 {
+			event.Image = body.EventData.Resources[0].ResourceURL
+		}
+		if strings.EqualFold(body.Type, "PUSH_ARTIFACT") || strings.EqualFold(body.Type, "pushImage") {
+			event.Kind = WebhookEventPush
+		}
+		return event, nil
+
+	case WebhookTypeGitHub:
+		type githubPackage struct {
+			Name           string `json:"name"`
+			PackageType    string `json:"package_type"`
+			PackageVersion struct {
+				PackageURL string `json:"package_url"`
+			} `json:"package_version"`
+		}
+		var body struct {
+			Action          string         `json:"action"`
+			Package         *githubPackage `json:"package"`
+			RegistryPackage *githubPackage `json:"registry_package"`
+			WorkflowRun     *struct {
+				Name       string `json:"name"`
+				Conclusion string `json:"conclusion"`
+			} `json:"workflow_run"`
+		}
+		if err := json.Unmarshal(payload, &body); err != nil {
+			return webhookEvent{}, fmt.Errorf("%w: %w", ErrInvalidWebhookPayload, err)
+		}
+		event := webhookEvent{Kind: WebhookEventOther, Name: githubEvent, Status: body.Action}
+		switch {
+		case (githubEvent == "package" && body.Package != nil) || (githubEvent == "registry_package" && body.RegistryPackage != nil):
+			pkg := body.Package
+			if pkg == nil {
+				pkg = body.RegistryPackage
+			}
+			event.Name = pkg.Name
+			event.Image = pkg.PackageVersion.PackageURL
+			if body.Action == "published" && strings.EqualFold(pkg.PackageType, "container") {
+				event.Kind = WebhookEventPush
+			}
+		case githubEvent == "workflow_run" && body.WorkflowRun != nil:
+			event.Name = body.WorkflowRun.Name
+			if body.Action == "completed" {
+				event.Kind = WebhookEventBuild
+				event.Status = body.WorkflowRun.Conclusion
+				event.Success = body.WorkflowRun.Conclusion == "success"
+			}
+		}
+		return event, nil
+
+	default:
+		var body struct {
+			Event  string `json:"event"`
+			Name   string `json:"name"`
+			Image  string `json:"image"`
+			Status string `json:"status"`
+		}
+		if len(payload) > 0 {
+			if err := json.Unmarshal(payload, &body); err != nil {
+				return webhookEvent{}, fmt.Errorf("%w: %w", ErrInvalidWebhookPayload, err)
+			}
+		}
+		event := webhookEvent{Kind: WebhookEventOther, Name: body.Name, Image: body.Image, Status: body.Status}
+		switch strings.ToLower(body.Event) {
+		case WebhookEventPush:
+			event.Kind = WebhookEventPush
+		case WebhookEventBuild:
+			event.Kind = WebhookEventBuild
+			_, event.Success = webhookBuildSuccessStates[strings.ToLower(body.Status)]
+		}
+		return event, nil
+	}
+}
+
+// webhookRuleMatchesInternal reports whether event should start the rule of
+// source.
+func webhookRuleMatchesInternal(source *models.WebhookSource, event webhookEvent) bool {
+	if source.Action != WebhookActionRedeploy || source.ProjectID == nil {
+		return false
+	}
+	if event.Kind != WebhookEventPush && (event.Kind != WebhookEventBuild || !event.Success) {
+		return false
+	}
+	if source.ImageFilter == "" {
+		return true
+	}
+	if event.Image == "" {
+		return false
+	}
+
+	// Match the repository without its tag or digest, in both its full and
+	// its familiar form, so that nginx and docker.io/library/nginx are the
+	// same image.
+	candidates := []string{event.Image}
+	if named, err := ref.ParseNormalizedNamed(event.Image); err == nil {
+		candidates = []string{named.Name(), ref.FamiliarName(named)}
+	}
+	for _, c := range candidates {
+		if ok, _ := path.Match(source.ImageFilter, c); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// setWebhookTokenInternal gives source a new random token and returns it.
+// Only a SHA-256 hash of the token is stored; tokens are random enough that
+// a slow hash is not needed, and the hash doubles as the lookup key.
+func setWebhookTokenInternal(source *models.WebhookSource) (string, error) {
+	b := make([]byte, webhookTokenLength)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook token: %w", err)
+	}
+	token := webhookTokenPrefix + hex.EncodeToString(b)
+	source.TokenHash = hashWebhookTokenInternal(token)
+	source.TokenPrefix = token[:len(webhookTokenPrefix)+webhookTokenPrefixLen]
+	return token, nil
+}
+
+func hashWebhookTokenInternal(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+func toWebhookSourceDTO(src models.WebhookSource) webhooktypes.Source {
+	return webhooktypes.Source{
+		ID:             src.ID,
+		Name:           src.Name,
+		Type:           src.Type,
+		TokenPrefix:    src.TokenPrefix,
+		Action:         src.Action,
+		ProjectID:      src.ProjectID,
+		ImageFilter:    src.ImageFilter,
+		Enabled:        src.Enabled,
+		LastEvent:      src.LastEvent,
+		LastReceivedAt: src.LastReceivedAt,
+		UpdatedBy:      src.UpdatedBy,
+		CreatedAt:      src.CreatedAt,
+		UpdatedAt:      src.UpdatedAt,
+	}
+}
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+
+	"github.com/getarcaneapp/arcane/backend/internal/database"
+	"github.com/getarcaneapp/arcane/backend/internal/models"
+	webhooktypes "github.com/getarcaneapp/arcane/types/webhook"
+)
+
+func TestParseWebhookPayload(t *testing.T) {
+	event, err := parseWebhookPayloadInternal(WebhookTypeHarbor, "", []byte(`{"type":"PUSH_ARTIFACT","event_data":{
+		"resources":[{"tag":"1.2","resource_url":"harbor.example.com/team/shop:1.2"}],"repository":{"repo_full_name":"team/shop"}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, WebhookEventPush, event.Kind)
+	assert.Equal(t, "harbor.example.com/team/shop:1.2", event.Image)
+
+	event, err = parseWebhookPayloadInternal(WebhookTypeGitHub, "registry_package", []byte(`{"action":"published","registry_package":{
+		"name":"shop","package_type":"CONTAINER","package_version":{"package_url":"ghcr.io/acme/shop:latest"}}}`))
+	require.NoError(t, err)
+	assert.Equal(t, WebhookEventPush, event.Kind)
+	assert.Equal(t, "ghcr.io/acme/shop:latest", event.Image)
+
+	event, err = parseWebhookPayloadInternal(WebhookTypeGitHub, "workflow_run", []byte(`{"action":"completed","workflow_run":{"name":"CI","conclusion":"failure"}}`))
+	require.NoError(t, err)
+	assert.Equal(t, WebhookEventBuild, event.Kind)
+	assert.False(t, event.Success)
+	assert.Equal(t, "failure", event.Status)
+
+	event, err = parseWebhookPayloadInternal(WebhookTypeGitHub, "ping", []byte(`{"zen":"Keep it simple."}`))
+	require.NoError(t, err)
+	assert.Equal(t, WebhookEventOther, event.Kind)
+
+	event, err = parseWebhookPayloadInternal(WebhookTypeGeneric, "", []byte(`{"event":"build","status":"Passed","image":"registry.local/app:7"}`))
+	require.NoError(t, err)
+	assert.Equal(t, WebhookEventBuild, event.Kind)
+	assert.True(t, event.Success)
+
+	_, err = parseWebhookPayloadInternal(WebhookTypeGeneric, "", []byte(`not json`))
+	require.ErrorIs(t, err, ErrInvalidWebhookPayload)
+}
+
+func TestWebhookRuleMatches(t *testing.T) {
+	projectID := "p1"
+	source := &models.WebhookSource{Action: WebhookActionRedeploy, ProjectID: &projectID, ImageFilter: "ghcr.io/acme/*"}
+
+	assert.True(t, webhookRuleMatchesInternal(source, webhookEvent{Kind: WebhookEventPush, Image: "ghcr.io/acme/shop:latest"}))
+	assert.False(t, webhookRuleMatchesInternal(source, webhookEvent{Kind: WebhookEventPush, Image: "ghcr.io/other/shop:latest"}))
+	assert.False(t, webhookRuleMatchesInternal(source, webhookEvent{Kind: WebhookEventPush}))
+	assert.False(t, webhookRuleMatchesInternal(source, webhookEvent{Kind: WebhookEventOther, Image: "ghcr.io/acme/shop"}))
+
+	source.ImageFilter = "nginx"
+	assert.True(t, webhookRuleMatchesInternal(source, webhookEvent{Kind: WebhookEventPush, Image: "docker.io/library/nginx:1.27"}))
+
+	source.ImageFilter = ""
+	assert.True(t, webhookRuleMatchesInternal(source, webhookEvent{Kind: WebhookEventBuild, Success: true}))
+	assert.False(t, webhookRuleMatchesInternal(source, webhookEvent{Kind: WebhookEventBuild}))
+
+	source.Action = WebhookActionNone
+	assert.False(t, webhookRuleMatchesInternal(source, webhookEvent{Kind: WebhookEventPush}))
+}
+
+func TestWebhookService_Ingest(t *testing.T) {
+	ctx := context.Background()
+	gdb, err := gorm.Open(glsqlite.Open(":memory:"), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, gdb.AutoMigrate(&models.WebhookSource{}, &models.Project{}, &models.Event{}))
+	db := &database.DB{DB: gdb}
+	svc := NewWebhookService(db, NewEventService(db), nil, nil)
+	redeployed := make(chan string, 1)
+	svc.redeployProject = func(_ context.Context, projectID string, _ models.User) error {
+		redeployed <- projectID
+		return nil
+	}
+	checked := make(chan string, 2)
+	svc.checkPushedImage = func(_ context.Context, imageRef string) ([]string, error) {
+		checked <- imageRef
+		return []string{imageRef}, nil
+	}
+	user := models.User{Username: "admin"}
+
+	_, err = svc.CreateSource(ctx, webhooktypes.Request{Name: "harbor", Type: "harbor", Action: "redeploy"}, user)
+	require.ErrorIs(t, err, ErrInvalidWebhookSource)
+	_, err = svc.CreateSource(ctx, webhooktypes.Request{Name: "harbor", Type: "harbor", Action: "redeploy", ProjectID: "p1"}, user)
+	require.ErrorIs(t, err, ErrInvalidWebhookSource)
+
+	require.NoError(t, gdb.Create(&models.Project{BaseModel: models.BaseModel{ID: "p1"}, Name: "shop"}).Error)
+	source, err := svc.CreateSource(ctx, webhooktypes.Request{
+		Name: "harbor", Type: "harbor", Action: "redeploy", ProjectID: "p1", ImageFilter: "harbor.example.com/team/shop",
+	}, user)
+	require.NoError(t, err)
+	assert.Regexp(t, `^awh_[0-9a-f]{64}$`, source.Token)
+	assert.Equal(t, source.Token[:12], source.TokenPrefix)
+	assert.True(t, source.Enabled)
+
+	_, err = svc.Ingest(ctx, "awh_wrong", "", []byte(`{}`))
+	require.ErrorIs(t, err, ErrInvalidWebhookToken)
+
+	result, err := svc.Ingest(ctx, source.Token, "", []byte(`{"type":"PUSH_ARTIFACT","event_data":{"resources":[{"resource_url":"harbor.example.com/team/shop:2"}]}}`))
+	require.NoError(t, err)
+	assert.True(t, result.Triggered)
+	assert.True(t, result.UpdateCheck)
+	assert.Equal(t, WebhookActionRedeploy, result.Action)
+	select {
+	case projectID := <-redeployed:
+		assert.Equal(t, "p1", projectID)
+	case <-time.After(5 * time.Second):
+		t.Fatal("redeploy was not started")
+	}
+	select {
+	case imageRef := <-checked:
+		assert.Equal(t, "harbor.example.com/team/shop:2", imageRef)
+	case <-time.After(5 * time.Second):
+		t.Fatal("update check was not started")
+	}
+
+	result, err = svc.Ingest(ctx, source.Token, "", []byte(`{"type":"PUSH_ARTIFACT","event_data":{"resources":[{"resource_url":"harbor.example.com/team/api:2"}]}}`))
+	require.NoError(t, err)
+	assert.False(t, result.Triggered)
+	assert.True(t, result.UpdateCheck)
+	<-checked
+
+	// Only pushes start an update check.
+	result, err = svc.Ingest(ctx, source.Token, "", []byte(`{"type":"SCANNING_COMPLETED","event_data":{"resources":[{"resource_url":"harbor.example.com/team/api:2"}]}}`))
+	require.NoError(t, err)
+	assert.False(t, result.UpdateCheck)
+
+	got, err := svc.GetSource(ctx, source.ID)
+	require.NoError(t, err)
+	assert.Equal(t, WebhookEventOther, got.LastEvent)
+	assert.NotNil(t, got.LastReceivedAt)
+
+	var events []models.Event
+	require.NoError(t, gdb.Order("timestamp ASC").Find(&events).Error)
+	require.Len(t, events, 4)
+	assert.Equal(t, models.EventTypeWebhookReceived, events[0].Type)
+	assert.Equal(t, "harbor.example.com/team/shop:2", events[0].Metadata["image"])
+	assert.Equal(t, models.EventTypeWebhookTriggered, events[1].Type)
+	assert.Equal(t, "p1", events[1].Metadata["projectId"])
+
+	rotated, err := svc.RotateToken(ctx, source.ID, user)
+	require.NoError(t, err)
+	assert.NotEqual(t, source.Token, rotated.Token)
+	_, err = svc.Ingest(ctx, source.Token, "", []byte(`{}`))
+	require.ErrorIs(t, err, ErrInvalidWebhookToken)
+
+	disabled := false
+	_, err = svc.UpdateSource(ctx, source.ID, webhooktypes.Request{Name: "harbor", Type: "harbor", Enabled: &disabled}, user)
+	require.NoError(t, err)
+	_, err = svc.Ingest(ctx, rotated.Token, "", []byte(`{}`))
+	require.ErrorIs(t, err, ErrInvalidWebhookToken)
+
+	require.NoError(t, svc.DeleteSource(ctx, source.ID))
+	require.ErrorIs(t, svc.DeleteSource(ctx, source.ID), ErrWebhookSourceNotFound)
+}
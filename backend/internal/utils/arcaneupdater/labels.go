@@ -1,15 +1,43 @@
 package arcaneupdater
 
-import "strings"
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/internal/utils/registry"
+)
+
+// UpdatePolicy is the kind of update auto-update may apply to a container.
+type UpdatePolicy string
+
+const (
+	// UpdatePolicyPatch allows new digests and newer tags of the same minor version.
+	UpdatePolicyPatch UpdatePolicy = "patch"
+	// UpdatePolicyMinor allows new digests and newer tags of the same major version.
+	UpdatePolicyMinor UpdatePolicy = "minor"
+	// UpdatePolicyMajor allows new digests and any newer tag.
+	UpdatePolicyMajor UpdatePolicy = "major"
+	// UpdatePolicyDigest only allows new digests of the current tag. This is the default.
+	UpdatePolicyDigest UpdatePolicy = "digest"
+	// UpdatePolicyNone disables updates.
+	UpdatePolicyNone UpdatePolicy = "none"
+)
 
 const (
 	// Core labels
 	LabelArcane  = "com.getarcaneapp.arcane"         // Identifies the Arcane container itself
 	LabelUpdater = "com.getarcaneapp.arcane.updater" // Enable/disable updates (true/false)
 
+	// LabelUpdatePolicy limits which updates auto-update applies (patch, minor, major, digest, none)
+	LabelUpdatePolicy = "com.getarcaneapp.arcane.update-policy"
+
 	// Dependency labels
 	LabelDependsOn  = "com.getarcaneapp.arcane.depends-on"  // Comma-separated list of container names this depends on
 	LabelStopSignal = "com.getarcaneapp.arcane.stop-signal" // Custom stop signal (e.g., SIGINT)
+
+	// Lifecycle labels
+	LabelStopTimeout = "com.getarcaneapp.arcane.stop-timeout" // Seconds (or a duration like 2m) to wait before killing on stop
 )
 
 // IsArcaneContainer checks if the container is the Arcane application itself
@@ -59,3 +87,90 @@ func GetStopSignal(labels map[string]string) string {
 	}
 	return ""
 }
+
+// GetStopTimeout returns the stop timeout in seconds if the label is set to a
+// positive number of seconds or a duration such as 2m. ok is false otherwise.
+func GetStopTimeout(labels map[string]string) (seconds int, ok bool) {
+	for k, v := range labels {
+		if !strings.EqualFold(k, LabelStopTimeout) {
+			continue
+		}
+		v = strings.TrimSpace(v)
+		if n, err := strconv.Atoi(v); err == nil {
+			if n <= 0 {
+				return 0, false
+			}
+			return n, true
+		}
+		if d, err := time.ParseDuration(v); err == nil && d >= time.Second {
+			return int(d / time.Second), true
+		}
+		return 0, false
+	}
+	return 0, false
+}
+
+// GetUpdatePolicy returns the update policy set by label. Containers without
+// the label, or with an unknown value, get UpdatePolicyDigest.
+func GetUpdatePolicy(labels map[string]string) UpdatePolicy {
+	for k, v := range labels {
+		if !strings.EqualFold(k, LabelUpdatePolicy) {
+			continue
+		}
+		switch p := UpdatePolicy(strings.TrimSpace(strings.ToLower(v))); p {
+		case UpdatePolicyPatch, UpdatePolicyMinor, UpdatePolicyMajor, UpdatePolicyDigest, UpdatePolicyNone:
+			return p
+		}
+	}
+	return UpdatePolicyDigest
+}
+
+// AllowsTagUpdates reports whether the policy may move a container to a
+// newer version tag.
+func (p UpdatePolicy) AllowsTagUpdates() bool {
+	return p == UpdatePolicyPatch || p == UpdatePolicyMinor || p == UpdatePolicyMajor
+}
+
+// SelectPolicyTag returns the newest of the candidate tags the policy allows
+// and the newest candidate overall. Either is empty when no candidate is
+// newer than current.
+func SelectPolicyTag(policy UpdatePolicy, current string, candidates []string) (allowed, latest string) {
+	latest = registry.LatestVersionTag(current, candidates)
+	if latest == current {
+		latest = ""
+	}
+
+	fixed := -1
+	switch policy {
+	case UpdatePolicyMajor:
+		fixed = 0
+	case UpdatePolicyMinor:
+		fixed = 1
+	case UpdatePolicyPatch:
+		fixed = 2
+	case UpdatePolicyDigest, UpdatePolicyNone:
+	}
+	if fixed >= 0 && latest != "" {
+		allowed = registry.LatestVersionTagWithin(current, candidates, fixed)
+		if allowed == current {
+			allowed = ""
+		}
+	}
+	return allowed, latest
+}
+
+// Allows reports whether the policy permits moving a container from tag
+// current to tag next. Equal tags are a digest update.
+func (p UpdatePolicy) Allows(current, next string) bool {
+	if p == UpdatePolicyNone {
+		return false
+	}
+	if current == next {
+		return true
+	}
+	if !p.AllowsTagUpdates() {
+		return false
+	}
+	allowed, _ := SelectPolicyTag(p, current, []string{next})
+	return allowed == next
+}
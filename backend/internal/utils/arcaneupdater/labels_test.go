@@ -204,3 +204,90 @@ func TestGetStopSignal(t *testing.T) {
 		})
 	}
 }
+
+func TestGetStopTimeout(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   int
+		wantOK bool
+	}{
+		{name: "nil labels", labels: nil},
+		{name: "no stop timeout label", labels: map[string]string{"other": "value"}},
+		{name: "seconds", labels: map[string]string{LabelStopTimeout: "120"}, want: 120, wantOK: true},
+		{name: "duration", labels: map[string]string{LabelStopTimeout: " 2m "}, want: 120, wantOK: true},
+		{name: "case insensitive label key", labels: map[string]string{"COM.GETARCANEAPP.ARCANE.STOP-TIMEOUT": "90"}, want: 90, wantOK: true},
+		{name: "zero", labels: map[string]string{LabelStopTimeout: "0"}},
+		{name: "negative", labels: map[string]string{LabelStopTimeout: "-5"}},
+		{name: "sub-second duration", labels: map[string]string{LabelStopTimeout: "500ms"}},
+		{name: "invalid", labels: map[string]string{LabelStopTimeout: "soon"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := GetStopTimeout(tt.labels)
+			if got != tt.want || ok != tt.wantOK {
+				t.Errorf("GetStopTimeout() = %d, %v, want %d, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestGetUpdatePolicy(t *testing.T) {
+	tests := []struct {
+		name   string
+		labels map[string]string
+		want   UpdatePolicy
+	}{
+		{name: "nil labels", labels: nil, want: UpdatePolicyDigest},
+		{name: "minor", labels: map[string]string{LabelUpdatePolicy: "minor"}, want: UpdatePolicyMinor},
+		{name: "case insensitive", labels: map[string]string{"COM.GETARCANEAPP.ARCANE.UPDATE-POLICY": " PATCH "}, want: UpdatePolicyPatch},
+		{name: "none", labels: map[string]string{LabelUpdatePolicy: "none"}, want: UpdatePolicyNone},
+		{name: "unknown", labels: map[string]string{LabelUpdatePolicy: "latest"}, want: UpdatePolicyDigest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := GetUpdatePolicy(tt.labels); got != tt.want {
+				t.Errorf("GetUpdatePolicy() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSelectPolicyTag(t *testing.T) {
+	candidates := []string{"1.2.3", "1.2.4", "1.3.0", "2.0.0"}
+
+	tests := []struct {
+		policy      UpdatePolicy
+		wantAllowed string
+	}{
+		{policy: UpdatePolicyPatch, wantAllowed: "1.2.4"},
+		{policy: UpdatePolicyMinor, wantAllowed: "1.3.0"},
+		{policy: UpdatePolicyMajor, wantAllowed: "2.0.0"},
+		{policy: UpdatePolicyDigest, wantAllowed: ""},
+		{policy: UpdatePolicyNone, wantAllowed: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.policy), func(t *testing.T) {
+			allowed, latest := SelectPolicyTag(tt.policy, "1.2.3", candidates)
+			if allowed != tt.wantAllowed || latest != "2.0.0" {
+				t.Errorf("SelectPolicyTag() = %q, %q, want %q, %q", allowed, latest, tt.wantAllowed, "2.0.0")
+			}
+			if tt.wantAllowed != "" && !tt.policy.Allows("1.2.3", tt.wantAllowed) {
+				t.Errorf("Allows(%q) = false, want true", tt.wantAllowed)
+			}
+		})
+	}
+
+	if allowed, latest := SelectPolicyTag(UpdatePolicyMajor, "2.0.0", candidates); allowed != "" || latest != "" {
+		t.Errorf("SelectPolicyTag() on newest tag = %q, %q, want none", allowed, latest)
+	}
+	if UpdatePolicyPatch.Allows("1.2.3", "1.3.0") {
+		t.Error("patch policy allows a minor update")
+	}
+	if UpdatePolicyNone.Allows("1.2.3", "1.2.3") {
+		t.Error("none policy allows a digest update")
+	}
+}
@@ -0,0 +1,199 @@
+// Package chaos injects artificial latency and failures into the Docker API
+// and the HTTP API so timeout handling, retries and UI error states can be
+// exercised in development. It is never enabled in production.
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrInjected is the error of a Docker API request failed on purpose.
+var ErrInjected = errors.New("chaos: injected failure")
+
+// Target is a path failures can be injected into.
+type Target int
+
+const (
+	// TargetDocker is the Docker API the backend calls.
+	TargetDocker Target = iota
+	// TargetAPI is the HTTP API the backend serves, which is also what the
+	// manager calls on agents.
+	TargetAPI
+)
+
+// Rule describes the faults injected into one target.
+type Rule struct {
+	// Latency is the maximum delay added to a request. Each request waits a
+	// random duration between zero and Latency.
+	Latency time.Duration
+	// FailureRate is the percentage (0-100) of requests that fail.
+	FailureRate int
+}
+
+// Enabled reports whether the rule injects anything.
+func (r Rule) Enabled() bool {
+	return r.Latency > 0 || r.FailureRate > 0
+}
+
+// Validate checks the rule's values are in range.
+func (r Rule) Validate() error {
+	if r.Latency < 0 {
+		return fmt.Errorf("latency must not be negative")
+	}
+	if r.FailureRate < 0 || r.FailureRate > 100 {
+		return fmt.Errorf("failure rate must be between 0 and 100")
+	}
+	return nil
+}
+
+// Config holds the rules of every target.
+type Config struct {
+	Docker Rule
+	API    Rule
+}
+
+// Enabled reports whether any target injects anything.
+func (c Config) Enabled() bool {
+	return c.Docker.Enabled() || c.API.Enabled()
+}
+
+// Injector decides which requests are delayed or failed. A nil Injector
+// injects nothing, which is what production runs with.
+type Injector struct {
+	mu     sync.Mutex
+	config Config
+	rnd    *rand.Rand
+}
+
+// New returns an Injector with the given rules.
+func New(cfg Config) *Injector {
+	return &Injector{
+		config: cfg,
+		rnd:    rand.New(rand.NewPCG(rand.Uint64(), rand.Uint64())), //nolint:gosec // fault injection does not need a secure source
+	}
+}
+
+// Config returns the current rules.
+func (i *Injector) Config() Config {
+	if i == nil {
+		return Config{}
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.config
+}
+
+// SetConfig replaces the rules. Requests already waiting keep their delay.
+func (i *Injector) SetConfig(cfg Config) error {
+	if i == nil {
+		return errors.New("chaos mode is not available")
+	}
+	if err := cfg.Docker.Validate(); err != nil {
+		return fmt.Errorf("docker: %w", err)
+	}
+	if err := cfg.API.Validate(); err != nil {
+		return fmt.Errorf("api: %w", err)
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	i.config = cfg
+	return nil
+}
+
+// Inject delays the caller according to target's rule and reports whether
+// the request should fail. It returns ctx's error if ctx ends while waiting.
+func (i *Injector) Inject(ctx context.Context, target Target) (bool, error) {
+	if i == nil {
+		return false, nil
+	}
+
+	delay, fail := i.rollInternal(target)
+	if delay > 0 {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return false, ctx.Err()
+		case <-timer.C:
+		}
+	}
+	return fail, nil
+}
+
+func (i *Injector) rollInternal(target Target) (time.Duration, bool) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	rule := i.config.API
+	if target == TargetDocker {
+		rule = i.config.Docker
+	}
+
+	var delay time.Duration
+	if rule.Latency > 0 {
+		delay = time.Duration(i.rnd.Int64N(int64(rule.Latency) + 1))
+	}
+	fail := rule.FailureRate > 0 && i.rnd.IntN(100) < rule.FailureRate
+	return delay, fail
+}
+
+// coinInternal returns true for half of the calls.
+func (i *Injector) coinInternal() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rnd.IntN(2) == 0
+}
+
+// Transport wraps base so Docker API requests are delayed and failed
+// according to the Docker rule. Failed requests either return ErrInjected,
+// as a dropped connection would, or a 500 response in the Docker API's error
+// format. A nil Injector returns base unchanged.
+func (i *Injector) Transport(base http.RoundTripper) http.RoundTripper {
+	if i == nil {
+		return base
+	}
+	return &chaosTransport{injector: i, base: base}
+}
+
+type chaosTransport struct {
+	injector *Injector
+	base     http.RoundTripper
+}
+
+func (t *chaosTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	fail, err := t.injector.Inject(req.Context(), TargetDocker)
+	if err != nil {
+		return nil, err
+	}
+	if !fail {
+		return t.base.RoundTrip(req)
+	}
+
+	if req.Body != nil {
+		_ = req.Body.Close()
+	}
+	if t.injector.coinInternal() {
+		return nil, ErrInjected
+	}
+
+	body := []byte(`{"message":"` + ErrInjected.Error() + `"}`)
+	return &http.Response{
+		Status:        "500 Internal Server Error",
+		StatusCode:    http.StatusInternalServerError,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,103 @@
+package chaos
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInjector_NilInjectsNothing(t *testing.T) {
+	var injector *Injector
+
+	fail, err := injector.Inject(context.Background(), TargetDocker)
+	require.NoError(t, err)
+	assert.False(t, fail)
+	assert.Equal(t, Config{}, injector.Config())
+	require.Error(t, injector.SetConfig(Config{}))
+
+	base := http.DefaultTransport
+	assert.Same(t, base, injector.Transport(base))
+}
+
+func TestInjector_FailureRate(t *testing.T) {
+	injector := New(Config{Docker: Rule{FailureRate: 100}})
+
+	fail, err := injector.Inject(context.Background(), TargetDocker)
+	require.NoError(t, err)
+	assert.True(t, fail)
+
+	fail, err = injector.Inject(context.Background(), TargetAPI)
+	require.NoError(t, err)
+	assert.False(t, fail)
+}
+
+func TestInjector_LatencyHonorsContext(t *testing.T) {
+	injector := New(Config{API: Rule{Latency: time.Hour}})
+
+	// Retry until the random delay is long enough to outlast the context.
+	for range 10 {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		_, err := injector.Inject(ctx, TargetAPI)
+		cancel()
+		if err != nil {
+			require.ErrorIs(t, err, context.DeadlineExceeded)
+			return
+		}
+	}
+	t.Fatal("expected the injected delay to outlast the context")
+}
+
+func TestInjector_SetConfigValidates(t *testing.T) {
+	injector := New(Config{})
+
+	require.Error(t, injector.SetConfig(Config{Docker: Rule{FailureRate: 101}}))
+	require.Error(t, injector.SetConfig(Config{API: Rule{Latency: -time.Second}}))
+
+	cfg := Config{Docker: Rule{Latency: time.Second, FailureRate: 5}}
+	require.NoError(t, injector.SetConfig(cfg))
+	assert.Equal(t, cfg, injector.Config())
+	assert.True(t, injector.Config().Enabled())
+}
+
+func TestInjector_TransportFailsRequests(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	injector := New(Config{Docker: Rule{FailureRate: 100}})
+	client := &http.Client{Transport: injector.Transport(http.DefaultTransport)}
+
+	var sawError, sawResponse bool
+	for range 50 {
+		resp, err := client.Get(server.URL)
+		if err != nil {
+			require.ErrorIs(t, err, ErrInjected)
+			sawError = true
+			continue
+		}
+		body, _ := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+		assert.JSONEq(t, `{"message":"chaos: injected failure"}`, string(body))
+		sawResponse = true
+	}
+	assert.True(t, sawError)
+	assert.True(t, sawResponse)
+	assert.Zero(t, calls)
+
+	require.NoError(t, injector.SetConfig(Config{}))
+	resp, err := client.Get(server.URL)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, 1, calls)
+}
@@ -0,0 +1,201 @@
+package docker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// APILimitCategory groups Docker API calls that are expensive for the daemon
+// and get their own concurrency limit on top of the global one.
+type APILimitCategory string
+
+const (
+	APILimitDiskUsage APILimitCategory = "diskUsage"
+	APILimitExec      APILimitCategory = "exec"
+	APILimitPull      APILimitCategory = "pull"
+)
+
+// APILimits holds the maximum number of concurrent Docker API calls, overall
+// and per category. Zero or less means unlimited.
+type APILimits struct {
+	Requests  int
+	DiskUsage int
+	Exec      int
+	Pulls     int
+}
+
+func (l APILimits) forCategory(category APILimitCategory) int {
+	switch category {
+	case APILimitDiskUsage:
+		return l.DiskUsage
+	case APILimitExec:
+		return l.Exec
+	case APILimitPull:
+		return l.Pulls
+	default:
+		return 0
+	}
+}
+
+// APILimiter bounds concurrent Docker API calls so bursts such as dashboard
+// refreshes do not starve the daemon on small hosts. Limits are read on every
+// acquire, so settings changes apply without recreating the client.
+//
+// The transport counts every request against the global limit until its
+// response headers arrive, and holds disk usage and pull slots until the
+// response body is closed. Exec sessions are attached over hijacked
+// connections that bypass the transport, so callers acquire APILimitExec
+// themselves.
+type APILimiter struct {
+	limits     func() APILimits
+	global     *apiSemaphore
+	categories map[APILimitCategory]*apiSemaphore
+}
+
+func NewAPILimiter(limits func() APILimits) *APILimiter {
+	return &APILimiter{
+		limits: limits,
+		global: newAPISemaphore(),
+		categories: map[APILimitCategory]*apiSemaphore{
+			APILimitDiskUsage: newAPISemaphore(),
+			APILimitExec:      newAPISemaphore(),
+			APILimitPull:      newAPISemaphore(),
+		},
+	}
+}
+
+// Acquire waits for a free slot in category and returns the func that frees
+// it. A nil limiter never blocks.
+func (l *APILimiter) Acquire(ctx context.Context, category APILimitCategory) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+	sem, ok := l.categories[category]
+	if !ok {
+		return func() {}, nil
+	}
+	if err := sem.acquire(ctx, func() int { return l.limits().forCategory(category) }); err != nil {
+		return nil, err
+	}
+	return sem.releaseOnce(), nil
+}
+
+// Transport wraps base so each Docker API request waits for a free slot.
+func (l *APILimiter) Transport(base http.RoundTripper) http.RoundTripper {
+	return &limitedTransport{limiter: l, base: base}
+}
+
+type limitedTransport struct {
+	limiter *APILimiter
+	base    http.RoundTripper
+}
+
+func (t *limitedTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+
+	releaseCategory := func() {}
+	if category, ok := classifyAPIRequestInternal(req); ok {
+		release, err := t.limiter.Acquire(ctx, category)
+		if err != nil {
+			return nil, err
+		}
+		releaseCategory = release
+	}
+
+	if err := t.limiter.global.acquire(ctx, func() int { return t.limiter.limits().Requests }); err != nil {
+		releaseCategory()
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	t.limiter.global.release()
+	if err != nil {
+		releaseCategory()
+		return nil, err
+	}
+
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: releaseCategory}
+	return resp, nil
+}
+
+// classifyAPIRequestInternal maps a request to its limit category. Paths
+// carry an optional /vX.Y version prefix.
+func classifyAPIRequestInternal(req *http.Request) (APILimitCategory, bool) {
+	p := req.URL.Path
+	switch {
+	case req.Method == http.MethodGet && strings.HasSuffix(p, "/system/df"):
+		return APILimitDiskUsage, true
+	case req.Method == http.MethodPost && strings.HasSuffix(p, "/images/create"):
+		return APILimitPull, true
+	default:
+		return "", false
+	}
+}
+
+// releasingBody frees a category slot once the response body is fully read
+// or closed.
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releasingBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	if err == io.EOF {
+		b.once.Do(b.release)
+	}
+	return n, err
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+	return err
+}
+
+// apiSemaphore is a counting semaphore whose size is looked up on each
+// acquire.
+type apiSemaphore struct {
+	mu      sync.Mutex
+	inUse   int
+	changed chan struct{}
+}
+
+func newAPISemaphore() *apiSemaphore {
+	return &apiSemaphore{changed: make(chan struct{})}
+}
+
+func (s *apiSemaphore) acquire(ctx context.Context, limit func() int) error {
+	for {
+		s.mu.Lock()
+		if n := limit(); n <= 0 || s.inUse < n {
+			s.inUse++
+			s.mu.Unlock()
+			return nil
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (s *apiSemaphore) release() {
+	s.mu.Lock()
+	s.inUse--
+	close(s.changed)
+	s.changed = make(chan struct{})
+	s.mu.Unlock()
+}
+
+func (s *apiSemaphore) releaseOnce() func() {
+	var once sync.Once
+	return func() { once.Do(s.release) }
+}
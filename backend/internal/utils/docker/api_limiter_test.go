@@ -0,0 +1,91 @@
+package docker
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAPILimiter_AcquireBlocksAtLimit(t *testing.T) {
+	limiter := NewAPILimiter(func() APILimits { return APILimits{Exec: 1} })
+
+	release, err := limiter.Acquire(context.Background(), APILimitExec)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = limiter.Acquire(ctx, APILimitExec)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release()
+	release() // releasing twice must not free a second slot
+
+	second, err := limiter.Acquire(context.Background(), APILimitExec)
+	require.NoError(t, err)
+	defer second()
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = limiter.Acquire(ctx, APILimitExec)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestAPILimiter_TransportHoldsPullUntilBodyClosed(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, `{"status":"Pulling"}`)
+	}))
+	defer server.Close()
+
+	limiter := NewAPILimiter(func() APILimits { return APILimits{Pulls: 1} })
+	httpClient := &http.Client{Transport: limiter.Transport(http.DefaultTransport)}
+
+	resp, err := httpClient.Post(server.URL+"/v1.44/images/create?fromImage=alpine", "application/json", strings.NewReader(""))
+	require.NoError(t, err)
+
+	// Other requests are not held back by the pull.
+	list, err := httpClient.Get(server.URL + "/v1.44/containers/json")
+	require.NoError(t, err)
+	_ = list.Body.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, server.URL+"/v1.44/images/create?fromImage=busybox", nil)
+	require.NoError(t, err)
+	_, err = httpClient.Do(req)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	_, _ = io.Copy(io.Discard, resp.Body)
+	_ = resp.Body.Close()
+
+	next, err := httpClient.Post(server.URL+"/v1.44/images/create?fromImage=busybox", "application/json", strings.NewReader(""))
+	require.NoError(t, err)
+	_ = next.Body.Close()
+}
+
+func TestClassifyAPIRequest(t *testing.T) {
+	tests := []struct {
+		method   string
+		path     string
+		category APILimitCategory
+		ok       bool
+	}{
+		{http.MethodGet, "/v1.44/system/df", APILimitDiskUsage, true},
+		{http.MethodGet, "/system/df", APILimitDiskUsage, true},
+		{http.MethodPost, "/v1.44/images/create", APILimitPull, true},
+		{http.MethodGet, "/v1.44/images/json", "", false},
+		{http.MethodPost, "/v1.44/containers/abc/exec", "", false},
+	}
+	for _, tt := range tests {
+		req := httptest.NewRequest(tt.method, tt.path, nil)
+		category, ok := classifyAPIRequestInternal(req)
+		assert.Equal(t, tt.ok, ok, tt.path)
+		assert.Equal(t, tt.category, category, tt.path)
+	}
+}
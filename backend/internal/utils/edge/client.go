@@ -325,14 +325,12 @@ func (c *TunnelClient) buildLocalWebSocketURL(msg *TunnelMessage) string {
 }
 
 func (c *TunnelClient) localWebSocketHostInternal() string {
-	listenHost := strings.TrimSpace(c.cfg.Listen)
-	if listenHost == "" {
+	hosts := c.cfg.ListenHosts()
+	if len(hosts) == 0 {
 		return "localhost"
 	}
 
-	trimmed := strings.TrimPrefix(listenHost, "[")
-	trimmed = strings.TrimSuffix(trimmed, "]")
-
+	trimmed := hosts[0]
 	switch trimmed {
 	case "0.0.0.0", "::":
 		return "localhost"
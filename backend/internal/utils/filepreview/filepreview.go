@@ -0,0 +1,271 @@
+// Package filepreview inspects file contents so the volume browser can decide
+// how to display them: as highlighted text, or through a server-side listing
+// of archives and SQLite databases.
+package filepreview
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"path"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/getarcaneapp/arcane/types/volume"
+	glsqlite "github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Viewers that can render a file server-side.
+const (
+	ViewerSqlite  = "sqlite"
+	ViewerArchive = "archive"
+)
+
+// MaxEntries caps archive and table listings.
+const MaxEntries = 1000
+
+var sqliteMagic = []byte("SQLite format 3\x00")
+
+var languageByExtension = map[string]string{
+	".yml":        "yaml",
+	".yaml":       "yaml",
+	".json":       "json",
+	".go":         "go",
+	".py":         "python",
+	".js":         "javascript",
+	".mjs":        "javascript",
+	".cjs":        "javascript",
+	".ts":         "typescript",
+	".sh":         "shell",
+	".bash":       "shell",
+	".toml":       "toml",
+	".ini":        "ini",
+	".conf":       "ini",
+	".cfg":        "ini",
+	".env":        "ini",
+	".properties": "ini",
+	".xml":        "xml",
+	".html":       "html",
+	".htm":        "html",
+	".css":        "css",
+	".md":         "markdown",
+	".sql":        "sql",
+	".php":        "php",
+	".rb":         "ruby",
+	".rs":         "rust",
+	".java":       "java",
+	".lua":        "lua",
+	".log":        "log",
+}
+
+var languageByName = map[string]string{
+	"dockerfile":    "dockerfile",
+	"makefile":      "makefile",
+	"caddyfile":     "caddyfile",
+	"nginx.conf":    "nginx",
+	".gitignore":    "ignore",
+	".dockerignore": "ignore",
+}
+
+// DetectLanguage guesses a syntax highlighting language from a file name. It
+// returns an empty string when the language is unknown.
+func DetectLanguage(name string) string {
+	base := strings.ToLower(path.Base(name))
+	if lang, ok := languageByName[base]; ok {
+		return lang
+	}
+	if strings.HasPrefix(base, "dockerfile.") || strings.HasSuffix(base, ".dockerfile") {
+		return "dockerfile"
+	}
+	return languageByExtension[path.Ext(base)]
+}
+
+// IsBinary reports whether content looks like binary data: it contains a NUL
+// byte or is not valid UTF-8. Multi-byte runes cut off at either end of a
+// partial read are ignored.
+func IsBinary(content []byte) bool {
+	if bytes.IndexByte(content, 0) >= 0 {
+		return true
+	}
+	for i := 0; i < utf8.UTFMax-1 && len(content) > 0 && !utf8.RuneStart(content[0]); i++ {
+		content = content[1:]
+	}
+	if utf8.Valid(content) {
+		return false
+	}
+	for i := 1; i < utf8.UTFMax && i <= len(content); i++ {
+		tail := content[len(content)-i:]
+		if utf8.RuneStart(tail[0]) {
+			if !utf8.FullRune(tail) {
+				return !utf8.Valid(content[:len(content)-i])
+			}
+			break
+		}
+	}
+	return true
+}
+
+// CountLines returns the number of lines in content, counting a final line
+// without a trailing newline.
+func CountLines(content []byte) int {
+	if len(content) == 0 {
+		return 0
+	}
+	n := bytes.Count(content, []byte("\n"))
+	if content[len(content)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
+// DetectViewer returns the server-side viewer for a file based on its name and
+// leading bytes, or an empty string when none applies.
+func DetectViewer(name string, head []byte) string {
+	if bytes.HasPrefix(head, sqliteMagic) {
+		return ViewerSqlite
+	}
+	if archiveKind(name, head) != "" {
+		return ViewerArchive
+	}
+	return ""
+}
+
+func archiveKind(name string, head []byte) string {
+	lower := strings.ToLower(name)
+	switch {
+	case bytes.HasPrefix(head, []byte("PK\x03\x04")), strings.HasSuffix(lower, ".zip"):
+		return "zip"
+	case bytes.HasPrefix(head, []byte{0x1f, 0x8b}) && (strings.HasSuffix(lower, ".tar.gz") || strings.HasSuffix(lower, ".tgz")):
+		return "tar.gz"
+	case strings.HasSuffix(lower, ".tar"):
+		return "tar"
+	case len(head) > 262 && string(head[257:262]) == "ustar":
+		return "tar"
+	default:
+		return ""
+	}
+}
+
+// ListArchive lists up to MaxEntries entries of a tar, tar.gz or zip archive.
+func ListArchive(name string, r io.ReaderAt, size int64) ([]volume.ArchiveEntry, bool, error) {
+	head := make([]byte, 512)
+	n, err := r.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, false, err
+	}
+	head = head[:n]
+
+	switch archiveKind(name, head) {
+	case "zip":
+		return listZip(r, size)
+	case "tar.gz":
+		gr, err := gzip.NewReader(io.NewSectionReader(r, 0, size))
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to open gzip stream: %w", err)
+		}
+		defer gr.Close()
+		return listTar(gr)
+	case "tar":
+		return listTar(io.NewSectionReader(r, 0, size))
+	default:
+		return nil, false, fmt.Errorf("unsupported archive format")
+	}
+}
+
+func listZip(r io.ReaderAt, size int64) ([]volume.ArchiveEntry, bool, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open zip archive: %w", err)
+	}
+
+	entries := make([]volume.ArchiveEntry, 0, min(len(zr.File), MaxEntries))
+	for _, f := range zr.File {
+		if len(entries) == MaxEntries {
+			return entries, true, nil
+		}
+		entries = append(entries, volume.ArchiveEntry{
+			Name:        f.Name,
+			Size:        int64(f.UncompressedSize64),
+			IsDirectory: f.FileInfo().IsDir(),
+			ModTime:     f.Modified,
+		})
+	}
+	return entries, false, nil
+}
+
+func listTar(r io.Reader) ([]volume.ArchiveEntry, bool, error) {
+	tr := tar.NewReader(bufio.NewReader(r))
+	entries := []volume.ArchiveEntry{}
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return entries, false, nil
+		}
+		if err != nil {
+			return entries, false, fmt.Errorf("failed to read tar archive: %w", err)
+		}
+		if len(entries) == MaxEntries {
+			return entries, true, nil
+		}
+		entries = append(entries, volume.ArchiveEntry{
+			Name:        hdr.Name,
+			Size:        hdr.Size,
+			IsDirectory: hdr.Typeflag == tar.TypeDir,
+			ModTime:     hdr.ModTime,
+		})
+	}
+}
+
+// ListSqliteTables opens the SQLite database at dbPath read-only and lists its
+// tables and views with their columns and row counts.
+func ListSqliteTables(dbPath string) ([]volume.SqliteTable, bool, error) {
+	dsn := (&url.URL{Scheme: "file", Path: dbPath, RawQuery: "mode=ro&immutable=1"}).String()
+	db, err := gorm.Open(glsqlite.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to open sqlite database: %w", err)
+	}
+	if sqlDB, err := db.DB(); err == nil {
+		defer sqlDB.Close()
+	}
+
+	var objects []struct {
+		Name string
+		Type string
+	}
+	if err := db.Raw("SELECT name, type FROM sqlite_master WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite_%' ORDER BY name LIMIT ?", MaxEntries+1).Scan(&objects).Error; err != nil {
+		return nil, false, fmt.Errorf("failed to read sqlite schema: %w", err)
+	}
+
+	truncated := len(objects) > MaxEntries
+	if truncated {
+		objects = objects[:MaxEntries]
+	}
+
+	tables := make([]volume.SqliteTable, 0, len(objects))
+	for _, obj := range objects {
+		quoted := `"` + strings.ReplaceAll(obj.Name, `"`, `""`) + `"`
+		table := volume.SqliteTable{Name: obj.Name, Type: obj.Type, Columns: []string{}, RowCount: -1}
+
+		var columns []struct{ Name string }
+		if err := db.Raw("SELECT name FROM pragma_table_info(?)", obj.Name).Scan(&columns).Error; err == nil {
+			for _, c := range columns {
+				table.Columns = append(table.Columns, c.Name)
+			}
+		}
+
+		var count int64
+		if err := db.Raw("SELECT count(*) FROM " + quoted).Scan(&count).Error; err == nil {
+			table.RowCount = count
+		}
+		tables = append(tables, table)
+	}
+	return tables, truncated, nil
+}
@@ -0,0 +1,80 @@
+package filepreview
+
+import (
+	"archive/zip"
+	"bytes"
+	"path/filepath"
+	"testing"
+
+	glsqlite "github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	assert.Equal(t, "yaml", DetectLanguage("/app/compose.YAML"))
+	assert.Equal(t, "dockerfile", DetectLanguage("Dockerfile"))
+	assert.Equal(t, "dockerfile", DetectLanguage("Dockerfile.dev"))
+	assert.Equal(t, "ini", DetectLanguage(".env"))
+	assert.Empty(t, DetectLanguage("data.bin"))
+}
+
+func TestIsBinary(t *testing.T) {
+	assert.False(t, IsBinary([]byte("hello\nworld")))
+	assert.True(t, IsBinary([]byte("a\x00b")))
+	assert.True(t, IsBinary([]byte{0xff, 0xfe, 0x41, 0x42}))
+	// "é" cut in half by a truncated read
+	assert.False(t, IsBinary([]byte("caf\xc3")))
+	// "é" cut in half at the start of a ranged read
+	assert.False(t, IsBinary([]byte("\xa9 ok")))
+}
+
+func TestCountLines(t *testing.T) {
+	assert.Equal(t, 0, CountLines(nil))
+	assert.Equal(t, 1, CountLines([]byte("one")))
+	assert.Equal(t, 2, CountLines([]byte("one\ntwo\n")))
+	assert.Equal(t, 3, CountLines([]byte("one\ntwo\nthree")))
+}
+
+func TestListArchiveZip(t *testing.T) {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	_, err := zw.Create("dir/")
+	require.NoError(t, err)
+	w, err := zw.Create("dir/file.txt")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("content"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	data := buf.Bytes()
+	assert.Equal(t, ViewerArchive, DetectViewer("backup.zip", data))
+
+	entries, truncated, err := ListArchive("backup.zip", bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	require.Len(t, entries, 2)
+	assert.True(t, entries[0].IsDirectory)
+	assert.Equal(t, "dir/file.txt", entries[1].Name)
+	assert.Equal(t, int64(7), entries[1].Size)
+}
+
+func TestListSqliteTables(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "app.db")
+	db, err := gorm.Open(glsqlite.Open(dbPath), &gorm.Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.Exec(`CREATE TABLE users (id INTEGER PRIMARY KEY, "na""me" TEXT)`).Error)
+	require.NoError(t, db.Exec(`INSERT INTO users ("na""me") VALUES ('a'), ('b')`).Error)
+	sqlDB, err := db.DB()
+	require.NoError(t, err)
+	require.NoError(t, sqlDB.Close())
+
+	tables, truncated, err := ListSqliteTables(dbPath)
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	require.Len(t, tables, 1)
+	assert.Equal(t, "users", tables[0].Name)
+	assert.Equal(t, []string{"id", `na"me`}, tables[0].Columns)
+	assert.Equal(t, int64(2), tables[0].RowCount)
+}
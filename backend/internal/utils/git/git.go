@@ -11,6 +11,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/getarcaneapp/arcane/backend/internal/utils/timeouts"
 	"github.com/getarcaneapp/arcane/types/gitops"
 	"github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
@@ -210,7 +211,7 @@ func addHostKey(knownHostsPath, hostname string, key gossh.PublicKey) (err error
 func (c *Client) Clone(ctx context.Context, url, branch string, auth AuthConfig) (string, error) {
 	if _, hasDeadline := ctx.Deadline(); !hasDeadline {
 		var cancel context.CancelFunc
-		ctx, cancel = context.WithTimeout(ctx, 5*time.Minute)
+		ctx, cancel = timeouts.WithTimeout(ctx, 0, timeouts.DefaultGitOperation)
 		defer cancel()
 	}
 
@@ -306,7 +307,7 @@ func (c *Client) ListBranches(ctx context.Context, url string, auth AuthConfig)
 		listOptions.Auth = authMethod
 	}
 
-	listCtx, cancel := context.WithTimeout(ctx, 60*time.Second)
+	listCtx, cancel := timeouts.WithTimeout(ctx, 0, 60*time.Second)
 	defer cancel()
 
 	refs, err := rem.ListContext(listCtx, listOptions)
@@ -0,0 +1,70 @@
+package http
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// ErrRangeNotSatisfiable is returned when a Range header does not overlap the
+// resource.
+var ErrRangeNotSatisfiable = errors.New("requested range not satisfiable")
+
+// ByteRange is an inclusive byte range of a resource.
+type ByteRange struct {
+	Start int64
+	End   int64
+}
+
+// Length returns the number of bytes in the range.
+func (r ByteRange) Length() int64 {
+	return r.End - r.Start + 1
+}
+
+// ContentRange returns the Content-Range header value for the range.
+func (r ByteRange) ContentRange(size int64) string {
+	return fmt.Sprintf("bytes %d-%d/%d", r.Start, r.End, size)
+}
+
+// ParseRange parses a single-range Range header against a resource of the
+// given size. It returns nil when the header is empty, uses a unit other than
+// bytes or asks for several ranges, in which case the whole resource should be
+// served, as RFC 9110 allows.
+func ParseRange(header string, size int64) (*ByteRange, error) {
+	spec, ok := strings.CutPrefix(strings.TrimSpace(header), "bytes=")
+	if !ok || spec == "" || strings.Contains(spec, ",") {
+		return nil, nil
+	}
+
+	startStr, endStr, ok := strings.Cut(strings.TrimSpace(spec), "-")
+	if !ok {
+		return nil, ErrRangeNotSatisfiable
+	}
+
+	var r ByteRange
+	if startStr == "" {
+		// Suffix range: the last n bytes.
+		n, err := strconv.ParseInt(endStr, 10, 64)
+		if err != nil || n <= 0 || size == 0 {
+			return nil, ErrRangeNotSatisfiable
+		}
+		r.Start = max(size-n, 0)
+		r.End = size - 1
+		return &r, nil
+	}
+
+	start, err := strconv.ParseInt(startStr, 10, 64)
+	if err != nil || start < 0 || start >= size {
+		return nil, ErrRangeNotSatisfiable
+	}
+	r.Start, r.End = start, size-1
+	if endStr != "" {
+		end, err := strconv.ParseInt(endStr, 10, 64)
+		if err != nil || end < start {
+			return nil, ErrRangeNotSatisfiable
+		}
+		r.End = min(end, size-1)
+	}
+	return &r, nil
+}
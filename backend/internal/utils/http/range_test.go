@@ -0,0 +1,47 @@
+package http
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		header string
+		want   *ByteRange
+	}{
+		{"", nil},
+		{"items=0-1", nil},
+		{"bytes=0-1,4-5", nil},
+		{"bytes=0-99", &ByteRange{Start: 0, End: 99}},
+		{"bytes=100-", &ByteRange{Start: 100, End: 999}},
+		{"bytes=900-5000", &ByteRange{Start: 900, End: 999}},
+		{"bytes=-100", &ByteRange{Start: 900, End: 999}},
+		{"bytes=-5000", &ByteRange{Start: 0, End: 999}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.header, func(t *testing.T) {
+			got, err := ParseRange(tt.header, 1000)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestParseRange_NotSatisfiable(t *testing.T) {
+	for _, header := range []string{"bytes=1000-", "bytes=5-2", "bytes=abc-", "bytes=-0", "bytes=5"} {
+		t.Run(header, func(t *testing.T) {
+			_, err := ParseRange(header, 1000)
+			assert.ErrorIs(t, err, ErrRangeNotSatisfiable)
+		})
+	}
+}
+
+func TestByteRange_ContentRange(t *testing.T) {
+	r := ByteRange{Start: 100, End: 199}
+	assert.Equal(t, int64(100), r.Length())
+	assert.Equal(t, "bytes 100-199/1000", r.ContentRange(1000))
+}
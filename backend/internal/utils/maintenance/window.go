@@ -0,0 +1,180 @@
+// Package maintenance parses weekly maintenance windows, the times at which
+// disruptive background jobs are allowed to run.
+package maintenance
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const day = 24 * time.Hour
+
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+// Window is a time range on some days of the week. A range whose end is not
+// after its start crosses midnight and belongs to the day it starts on.
+type Window struct {
+	Days  [7]bool
+	Start time.Duration
+	End   time.Duration
+}
+
+// Windows is a set of maintenance windows. No windows means no restriction.
+type Windows []Window
+
+// Parse reads one window per line or per ";", each written as days followed
+// by one or more comma-separated time ranges:
+//
+//	mon-fri 01:00-05:00
+//	sat,sun 00:00-06:00, 22:00-24:00
+//	daily 23:30-01:00
+//
+// Days are day names or ranges, "daily" (or "*"), "weekdays" or "weekends".
+func Parse(spec string) (Windows, error) {
+	var out Windows
+	for _, entry := range strings.FieldsFunc(spec, func(r rune) bool { return r == '\n' || r == ';' }) {
+		entry = strings.TrimSpace(entry)
+		if entry == "" || strings.HasPrefix(entry, "#") {
+			continue
+		}
+
+		daysSpec, rangesSpec, ok := strings.Cut(entry, " ")
+		if !ok {
+			return nil, fmt.Errorf("maintenance window %q: expected days and a time range", entry)
+		}
+		days, err := parseDaysInternal(daysSpec)
+		if err != nil {
+			return nil, fmt.Errorf("maintenance window %q: %w", entry, err)
+		}
+		for _, r := range strings.Split(rangesSpec, ",") {
+			start, end, err := parseRangeInternal(strings.TrimSpace(r))
+			if err != nil {
+				return nil, fmt.Errorf("maintenance window %q: %w", entry, err)
+			}
+			out = append(out, Window{Days: days, Start: start, End: end})
+		}
+	}
+	return out, nil
+}
+
+// Open reports whether t falls inside any window. It is always true when
+// there are no windows.
+func (w Windows) Open(t time.Time) bool {
+	if len(w) == 0 {
+		return true
+	}
+	for _, win := range w {
+		if win.open(t) {
+			return true
+		}
+	}
+	return false
+}
+
+// NextOpen returns when the windows next open at or after t, to the minute.
+// It returns t when a window is open and the zero time when none ever opens.
+func (w Windows) NextOpen(t time.Time) time.Time {
+	t = t.Truncate(time.Minute)
+	for i := 0; i <= 8*24*60; i++ {
+		if candidate := t.Add(time.Duration(i) * time.Minute); w.Open(candidate) {
+			return candidate
+		}
+	}
+	return time.Time{}
+}
+
+func (win Window) open(t time.Time) bool {
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	offset := t.Sub(midnight)
+	today := t.Weekday()
+
+	if win.Start < win.End {
+		return win.Days[today] && offset >= win.Start && offset < win.End
+	}
+	// Crosses midnight: the late part of a listed day or the early part of
+	// the day after one.
+	yesterday := (today + 6) % 7
+	return (win.Days[today] && offset >= win.Start) || (win.Days[yesterday] && offset < win.End)
+}
+
+func parseDaysInternal(spec string) ([7]bool, error) {
+	var days [7]bool
+	switch strings.ToLower(spec) {
+	case "*", "daily":
+		for i := range days {
+			days[i] = true
+		}
+		return days, nil
+	case "weekdays":
+		for d := time.Monday; d <= time.Friday; d++ {
+			days[d] = true
+		}
+		return days, nil
+	case "weekends":
+		days[time.Saturday], days[time.Sunday] = true, true
+		return days, nil
+	}
+
+	for _, part := range strings.Split(strings.ToLower(spec), ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		first, ok := weekdays[from]
+		if !ok {
+			return days, fmt.Errorf("unknown day %q", from)
+		}
+		last := first
+		if isRange {
+			if last, ok = weekdays[to]; !ok {
+				return days, fmt.Errorf("unknown day %q", to)
+			}
+		}
+		for d := first; ; d = (d + 1) % 7 {
+			days[d] = true
+			if d == last {
+				break
+			}
+		}
+	}
+	return days, nil
+}
+
+func parseRangeInternal(spec string) (time.Duration, time.Duration, error) {
+	from, to, ok := strings.Cut(spec, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("time range %q must be written as HH:MM-HH:MM", spec)
+	}
+	start, err := parseClockInternal(from)
+	if err != nil {
+		return 0, 0, err
+	}
+	end, err := parseClockInternal(to)
+	if err != nil {
+		return 0, 0, err
+	}
+	if start == day {
+		return 0, 0, fmt.Errorf("time range %q cannot start at 24:00", spec)
+	}
+	if start == end {
+		return 0, 0, fmt.Errorf("time range %q is empty", spec)
+	}
+	return start, end, nil
+}
+
+func parseClockInternal(spec string) (time.Duration, error) {
+	h, m, ok := strings.Cut(strings.TrimSpace(spec), ":")
+	hours, herr := strconv.Atoi(h)
+	minutes, merr := strconv.Atoi(m)
+	if !ok || herr != nil || merr != nil || hours < 0 || minutes < 0 || minutes > 59 || hours > 24 || (hours == 24 && minutes != 0) {
+		return 0, fmt.Errorf("invalid time %q", spec)
+	}
+	return time.Duration(hours)*time.Hour + time.Duration(minutes)*time.Minute, nil
+}
@@ -0,0 +1,64 @@
+package maintenance
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// 2026-03-02 is a Monday.
+func at(day int, hour, minute int) time.Time {
+	return time.Date(2026, 3, 2+day, hour, minute, 0, 0, time.UTC)
+}
+
+func TestParse(t *testing.T) {
+	windows, err := Parse("mon-fri 01:00-05:00\nweekends 00:00-06:00, 22:00-24:00; # comment")
+	require.NoError(t, err)
+	require.Len(t, windows, 3)
+	assert.True(t, windows[0].Days[time.Monday])
+	assert.True(t, windows[0].Days[time.Friday])
+	assert.False(t, windows[0].Days[time.Saturday])
+
+	windows, err = Parse("fri-mon 02:00-03:00")
+	require.NoError(t, err)
+	assert.True(t, windows[0].Days[time.Sunday])
+	assert.False(t, windows[0].Days[time.Wednesday])
+
+	empty, err := Parse("  ")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+
+	for _, spec := range []string{"mon", "someday 01:00-02:00", "mon 1-2", "mon 25:00-26:00", "mon 03:00-03:00", "mon 24:00-01:00"} {
+		_, err := Parse(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestWindowsOpen(t *testing.T) {
+	assert.True(t, Windows(nil).Open(at(0, 12, 0)))
+
+	windows, err := Parse("mon-fri 01:00-05:00; sat 23:00-02:00")
+	require.NoError(t, err)
+
+	assert.True(t, windows.Open(at(0, 1, 0)))
+	assert.True(t, windows.Open(at(4, 4, 59)))
+	assert.False(t, windows.Open(at(0, 5, 0)))
+	assert.False(t, windows.Open(at(5, 1, 30)))
+
+	// The Saturday window runs into Sunday morning.
+	assert.True(t, windows.Open(at(5, 23, 30)))
+	assert.True(t, windows.Open(at(6, 1, 59)))
+	assert.False(t, windows.Open(at(6, 2, 0)))
+	assert.False(t, windows.Open(at(6, 23, 30)))
+}
+
+func TestWindowsNextOpen(t *testing.T) {
+	windows, err := Parse("wed 03:00-04:00")
+	require.NoError(t, err)
+
+	assert.Equal(t, at(2, 3, 0), windows.NextOpen(at(0, 12, 0)))
+	assert.Equal(t, at(2, 3, 30), windows.NextOpen(at(2, 3, 30)))
+	assert.Equal(t, at(9, 3, 0), windows.NextOpen(at(2, 4, 0)))
+}
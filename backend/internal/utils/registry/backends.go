@@ -0,0 +1,400 @@
+package registry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	dockerHubAPIURL = "https://hub.docker.com/v2"
+	ghcrHost        = "ghcr.io"
+	lscrHost        = "lscr.io"
+	maxTagPages     = 5
+)
+
+// TagInfo describes a remote tag as reported by a registry-specific backend.
+type TagInfo struct {
+	// Digest is the manifest (or index) digest of the tag, if known.
+	Digest string
+	// PublishedAt is when the tag was last pushed, if known.
+	PublishedAt time.Time
+	// LatestTag is the newest tag that is version-compatible with the
+	// requested tag, if one could be determined.
+	LatestTag string
+	// VersionTags are the remote tags that are version-compatible with the
+	// requested tag, so that callers can pick a newer tag by their own rules.
+	VersionTags []string
+}
+
+// UpdateBackend resolves tag metadata using registry-specific APIs that offer
+// more than the generic v2 manifest HEAD request.
+type UpdateBackend interface {
+	// Name identifies the backend in update check results.
+	Name() string
+	// Supports reports whether the backend handles the given registry host.
+	Supports(registryHost string) bool
+	// Resolve returns metadata for repository:tag. The token is the bearer
+	// token already acquired for the registry, which may be empty.
+	Resolve(ctx context.Context, c *Client, registryHost, repository, tag, token string) (*TagInfo, error)
+}
+
+var updateBackends = []UpdateBackend{
+	dockerHubBackend{apiURL: dockerHubAPIURL},
+	ghcrBackend{},
+	lscrBackend{},
+}
+
+// BackendFor returns the update backend for the registry host, or nil when
+// only the generic digest check is available.
+func BackendFor(registryHost string) UpdateBackend {
+	host := normalizeHost(registryHost)
+	for _, b := range updateBackends {
+		if b.Supports(host) {
+			return b
+		}
+	}
+	return nil
+}
+
+// getJSON performs a GET request and decodes a JSON response.
+func (c *Client) getJSON(ctx context.Context, rawURL, authHeader string, accept []string, out any) error {
+	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, rawURL, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("User-Agent", "Arcane")
+	for _, a := range accept {
+		req.Header.Add("Accept", a)
+	}
+	if authHeader != "" {
+		req.Header.Set("Authorization", authHeader)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests {
+		rlErr := &RateLimitedError{Registry: req.URL.Host}
+		if retryAfter := parseRetryAfter(resp.Header, time.Now()); retryAfter > 0 {
+			rlErr.Until = time.Now().Add(retryAfter)
+		}
+		return rlErr
+	}
+	if resp.StatusCode != http.StatusOK {
+		_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+		return fmt.Errorf("request to %s failed with status: %d", req.URL.Host, resp.StatusCode)
+	}
+
+	return json.NewDecoder(resp.Body).Decode(out)
+}
+
+// --- Docker Hub ---
+
+type dockerHubBackend struct {
+	apiURL string
+}
+
+func (dockerHubBackend) Name() string { return "dockerhub" }
+
+func (dockerHubBackend) Supports(registryHost string) bool {
+	return registryHost == "docker.io" || registryHost == DefaultRegistry || registryHost == DefaultRegistryHost
+}
+
+type dockerHubTag struct {
+	Name          string    `json:"name"`
+	Digest        string    `json:"digest"`
+	LastUpdated   time.Time `json:"last_updated"`
+	TagLastPushed time.Time `json:"tag_last_pushed"`
+}
+
+// Resolve looks up the tag and, for version tags, pages through the tag list.
+// The caller has already charged the first request against the registry
+// budget; every further page is charged separately, and a 429 from Docker Hub
+// puts the registry into backoff instead of being retried by the caller.
+func (d dockerHubBackend) Resolve(ctx context.Context, c *Client, registryHost, repository, tag, token string) (*TagInfo, error) {
+	repository = normalizeRepositoryForDockerIO(registryHost, repository)
+
+	var t dockerHubTag
+	tagURL := fmt.Sprintf("%s/repositories/%s/tags/%s", d.apiURL, repository, url.PathEscape(tag))
+	if err := c.getJSON(ctx, tagURL, "", nil, &t); err != nil {
+		return nil, fmt.Errorf("docker hub tag lookup failed: %w", c.recordRateLimit(registryHost, err))
+	}
+
+	info := &TagInfo{Digest: t.Digest, PublishedAt: t.TagLastPushed}
+	if info.PublishedAt.IsZero() {
+		info.PublishedAt = t.LastUpdated
+	}
+
+	if IsVersionTag(tag) {
+		var names []string
+		next := fmt.Sprintf("%s/repositories/%s/tags?page_size=100&ordering=last_updated", d.apiURL, repository)
+		for page := 0; next != "" && page < maxTagPages; page++ {
+			if err := c.waitBudget(ctx, registryHost); err != nil {
+				return nil, fmt.Errorf("docker hub tag listing failed: %w", err)
+			}
+			var resp struct {
+				Next    string         `json:"next"`
+				Results []dockerHubTag `json:"results"`
+			}
+			if err := c.getJSON(ctx, next, "", nil, &resp); err != nil {
+				var rlErr *RateLimitedError
+				if errors.As(err, &rlErr) {
+					return nil, fmt.Errorf("docker hub tag listing failed: %w", c.recordRateLimit(registryHost, err))
+				}
+				break
+			}
+			for _, r := range resp.Results {
+				names = append(names, r.Name)
+			}
+			next = resp.Next
+		}
+		info.LatestTag = LatestVersionTag(tag, names)
+		info.VersionTags = VersionTagsLike(tag, names)
+	}
+
+	return info, nil
+}
+
+// --- GitHub Container Registry ---
+
+type ghcrBackend struct{}
+
+func (ghcrBackend) Name() string { return "ghcr" }
+
+func (ghcrBackend) Supports(registryHost string) bool { return registryHost == ghcrHost }
+
+func (g ghcrBackend) Resolve(ctx context.Context, c *Client, _ string, repository, tag, token string) (*TagInfo, error) {
+	return resolveOCITagInfo(ctx, c, ghcrHost, repository, tag, token)
+}
+
+// --- lscr.io (LinuxServer.io) ---
+
+// lscrBackend handles lscr.io, which redirects to images hosted on GHCR.
+type lscrBackend struct{}
+
+func (lscrBackend) Name() string { return "lscr" }
+
+func (lscrBackend) Supports(registryHost string) bool { return registryHost == lscrHost }
+
+func (l lscrBackend) Resolve(ctx context.Context, c *Client, _ string, repository, tag, _ string) (*TagInfo, error) {
+	// Tokens issued for lscr.io are not valid against ghcr.io directly.
+	return resolveOCITagInfo(ctx, c, ghcrHost, repository, tag, "")
+}
+
+// resolveOCITagInfo uses the standard OCI distribution endpoints to resolve
+// the digest, the publish date (from the image config) and the tag list.
+func resolveOCITagInfo(ctx context.Context, c *Client, host, repository, tag, token string) (*TagInfo, error) {
+	if token == "" {
+		anon, err := c.anonymousGHCRToken(ctx, repository)
+		if err != nil {
+			return nil, err
+		}
+		token = anon
+	}
+	authHeader := buildAuthHeader(token)
+	base := c.GetRegistryURL(host)
+
+	digest, err := c.GetLatestDigest(ctx, host, repository, tag, token)
+	if err != nil {
+		return nil, err
+	}
+	info := &TagInfo{Digest: digest}
+
+	if created, err := c.imageCreated(ctx, base, repository, digest, authHeader); err == nil {
+		info.PublishedAt = created
+	}
+
+	if IsVersionTag(tag) {
+		var list struct {
+			Tags []string `json:"tags"`
+		}
+		if err := c.getJSON(ctx, fmt.Sprintf("%s/v2/%s/tags/list?n=1000", base, repository), authHeader, nil, &list); err == nil {
+			info.LatestTag = LatestVersionTag(tag, list.Tags)
+			info.VersionTags = VersionTagsLike(tag, list.Tags)
+		}
+	}
+
+	return info, nil
+}
+
+func (c *Client) anonymousGHCRToken(ctx context.Context, repository string) (string, error) {
+	var tr struct {
+		Token string `json:"token"`
+	}
+	tokenURL := fmt.Sprintf("https://%s/token?service=%s&scope=%s", ghcrHost, ghcrHost, url.QueryEscape("repository:"+repository+":pull"))
+	if err := c.getJSON(ctx, tokenURL, "", nil, &tr); err != nil {
+		return "", fmt.Errorf("failed to get ghcr token: %w", err)
+	}
+	return tr.Token, nil
+}
+
+// imageCreated reads the "created" timestamp from the image config referenced
+// by the manifest. For multi-platform indexes the linux/amd64 manifest (or the
+// first manifest) is used.
+func (c *Client) imageCreated(ctx context.Context, base, repository, reference, authHeader string) (time.Time, error) {
+	accept := []string{
+		"application/vnd.oci.image.index.v1+json",
+		"application/vnd.docker.distribution.manifest.list.v2+json",
+		"application/vnd.oci.image.manifest.v1+json",
+		"application/vnd.docker.distribution.manifest.v2+json",
+	}
+
+	type descriptor struct {
+		Digest   string `json:"digest"`
+		Platform *struct {
+			Architecture string `json:"architecture"`
+			OS           string `json:"os"`
+		} `json:"platform,omitempty"`
+	}
+	var manifest struct {
+		Config    *descriptor  `json:"config,omitempty"`
+		Manifests []descriptor `json:"manifests,omitempty"`
+	}
+
+	manifestURL := fmt.Sprintf("%s/v2/%s/manifests/%s", base, repository, reference)
+	if err := c.getJSON(ctx, manifestURL, authHeader, accept, &manifest); err != nil {
+		return time.Time{}, err
+	}
+
+	if manifest.Config == nil && len(manifest.Manifests) > 0 {
+		chosen := manifest.Manifests[0].Digest
+		for _, m := range manifest.Manifests {
+			if m.Platform != nil && m.Platform.OS == "linux" && m.Platform.Architecture == "amd64" {
+				chosen = m.Digest
+				break
+			}
+		}
+		manifestURL = fmt.Sprintf("%s/v2/%s/manifests/%s", base, repository, chosen)
+		manifest.Manifests = nil
+		if err := c.getJSON(ctx, manifestURL, authHeader, accept, &manifest); err != nil {
+			return time.Time{}, err
+		}
+	}
+	if manifest.Config == nil || manifest.Config.Digest == "" {
+		return time.Time{}, fmt.Errorf("manifest has no config")
+	}
+
+	var cfg struct {
+		Created time.Time `json:"created"`
+	}
+	blobURL := fmt.Sprintf("%s/v2/%s/blobs/%s", base, repository, manifest.Config.Digest)
+	if err := c.getJSON(ctx, blobURL, authHeader, nil, &cfg); err != nil {
+		return time.Time{}, err
+	}
+	return cfg.Created, nil
+}
+
+// splitVersionTag splits a tag like "v1.25.3-alpine" into its prefix ("v"),
+// numeric components and variant suffix ("-alpine").
+func splitVersionTag(tag string) (prefix string, parts []int, suffix string, ok bool) {
+	rest := tag
+	if strings.HasPrefix(rest, "v") || strings.HasPrefix(rest, "V") {
+		prefix, rest = rest[:1], rest[1:]
+	}
+
+	end := 0
+	for end < len(rest) && (rest[end] == '.' || (rest[end] >= '0' && rest[end] <= '9')) {
+		end++
+	}
+	core := strings.TrimSuffix(rest[:end], ".")
+	suffix = rest[len(core):]
+	if core == "" {
+		return "", nil, "", false
+	}
+
+	for _, p := range strings.Split(core, ".") {
+		if p == "" {
+			return "", nil, "", false
+		}
+		n := 0
+		for _, ch := range p {
+			n = n*10 + int(ch-'0')
+		}
+		parts = append(parts, n)
+	}
+	if len(parts) > 4 {
+		return "", nil, "", false
+	}
+	return prefix, parts, suffix, true
+}
+
+// IsVersionTag reports whether a tag looks like a version number.
+func IsVersionTag(tag string) bool {
+	_, _, _, ok := splitVersionTag(tag)
+	return ok
+}
+
+// LatestVersionTag returns the highest tag from candidates with the same shape
+// as current (prefix, number of components and variant suffix), or "" if none
+// is newer than or equal to current.
+func LatestVersionTag(current string, candidates []string) string {
+	return LatestVersionTagWithin(current, candidates, 0)
+}
+
+// LatestVersionTagWithin is like LatestVersionTag but only considers
+// candidates whose first fixed components equal those of current; fixed 1
+// keeps the major version, fixed 2 the major and minor version.
+func LatestVersionTagWithin(current string, candidates []string, fixed int) string {
+	prefix, curParts, suffix, ok := splitVersionTag(current)
+	if !ok {
+		return ""
+	}
+	fixed = min(max(fixed, 0), len(curParts))
+
+	best, bestParts := "", curParts
+	for _, cand := range candidates {
+		p, parts, s, ok := splitVersionTag(cand)
+		if !ok || p != prefix || s != suffix || len(parts) != len(curParts) {
+			continue
+		}
+		if compareVersionParts(parts[:fixed], curParts[:fixed]) != 0 {
+			continue
+		}
+		if compareVersionParts(parts, bestParts) >= 0 {
+			best, bestParts = cand, parts
+		}
+	}
+	return best
+}
+
+// VersionTagsLike returns the candidates with the same shape as current.
+func VersionTagsLike(current string, candidates []string) []string {
+	prefix, curParts, suffix, ok := splitVersionTag(current)
+	if !ok {
+		return nil
+	}
+
+	var out []string
+	for _, cand := range candidates {
+		p, parts, s, ok := splitVersionTag(cand)
+		if ok && p == prefix && s == suffix && len(parts) == len(curParts) {
+			out = append(out, cand)
+		}
+	}
+	return out
+}
+
+func compareVersionParts(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] > b[i] {
+				return 1
+			}
+			return -1
+		}
+	}
+	return len(a) - len(b)
+}
@@ -1,12 +1,16 @@
 package registry
 
 import (
+	"context"
+	"errors"
 	"net/http"
+	"time"
 )
 
 // Client provides helper methods for Docker/OCI registries.
 type Client struct {
-	http *http.Client
+	http    *http.Client
+	limiter *Limiter
 }
 
 func NewClient() *Client {
@@ -15,3 +19,32 @@ func NewClient() *Client {
 
 	return &Client{http: &http.Client{Transport: transport}}
 }
+
+// WithLimiter returns a copy of the client that charges additional requests
+// made by update backends against the limiter's per-registry budget.
+func (c *Client) WithLimiter(l *Limiter) *Client {
+	cp := *c
+	cp.limiter = l
+	return &cp
+}
+
+// waitBudget blocks until the registry budget allows another request. It is a
+// no-op when the client has no limiter.
+func (c *Client) waitBudget(ctx context.Context, registryHost string) error {
+	if c.limiter == nil {
+		return nil
+	}
+	return c.limiter.Wait(ctx, registryHost)
+}
+
+// recordRateLimit puts the registry into backoff when err is a rate limit
+// response and rewrites the error to carry the backoff expiry.
+func (c *Client) recordRateLimit(registryHost string, err error) error {
+	var rlErr *RateLimitedError
+	if c.limiter == nil || !errors.As(err, &rlErr) {
+		return err
+	}
+	rlErr.Registry = registryHost
+	rlErr.Until = c.limiter.Backoff(registryHost, time.Until(rlErr.Until))
+	return err
+}
@@ -96,6 +96,14 @@ func (c *Client) GetLatestDigest(ctx context.Context, registry, repository, tag,
 	elapsed := time.Since(start)
 	slog.DebugContext(ctx, "manifest request completed", "url", url, "status", resp.StatusCode, "elapsed", elapsed)
 
+	if resp.StatusCode == http.StatusTooManyRequests {
+		rlErr := &RateLimitedError{Registry: registry}
+		if retryAfter := parseRetryAfter(resp.Header, time.Now()); retryAfter > 0 {
+			rlErr.Until = time.Now().Add(retryAfter)
+		}
+		slog.DebugContext(ctx, "manifest request rate limited", "url", url, "until", rlErr.Until)
+		return "", rlErr
+	}
 	if resp.StatusCode == http.StatusUnauthorized {
 		h := getHeaderCI(resp.Header, "WWW-Authenticate")
 		if h != "" {
@@ -0,0 +1,174 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	minRateLimitBackoff = 1 * time.Minute
+	maxRateLimitBackoff = 6 * time.Hour
+)
+
+// RateLimitedError is returned when a registry has rejected requests with
+// HTTP 429 or when the local request budget for that registry is exhausted.
+type RateLimitedError struct {
+	Registry string
+	Until    time.Time
+}
+
+func (e *RateLimitedError) Error() string {
+	if e.Until.IsZero() {
+		return fmt.Sprintf("registry %s is rate limited", e.Registry)
+	}
+	return fmt.Sprintf("registry %s is rate limited until %s", e.Registry, e.Until.UTC().Format(time.RFC3339))
+}
+
+// parseRetryAfter parses a Retry-After header (delay in seconds or HTTP date).
+// It returns zero when the header is missing or invalid.
+func parseRetryAfter(h http.Header, now time.Time) time.Duration {
+	v := strings.TrimSpace(getHeaderCI(h, "Retry-After"))
+	if v == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(v); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+type registryBudget struct {
+	tokens       float64
+	last         time.Time
+	blockedUntil time.Time
+	failures     int
+}
+
+// Limiter enforces a per-registry request budget and tracks backoff after a
+// registry reports that it is rate limiting us.
+type Limiter struct {
+	mu        sync.Mutex
+	perMinute int
+	budgets   map[string]*registryBudget
+	now       func() time.Time
+}
+
+// NewLimiter creates a limiter allowing perMinute requests per registry.
+// A value <= 0 disables the budget; backoff after 429 responses still applies.
+func NewLimiter(perMinute int) *Limiter {
+	return &Limiter{
+		perMinute: perMinute,
+		budgets:   make(map[string]*registryBudget),
+		now:       time.Now,
+	}
+}
+
+// SetRate updates the per-registry request budget.
+func (l *Limiter) SetRate(perMinute int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.perMinute = perMinute
+}
+
+func (l *Limiter) budgetInternal(registry string, now time.Time) *registryBudget {
+	b, ok := l.budgets[registry]
+	if !ok {
+		b = &registryBudget{tokens: float64(l.perMinute), last: now}
+		l.budgets[registry] = b
+	}
+	return b
+}
+
+// Wait blocks until a request to the registry fits in its budget. It returns a
+// RateLimitedError without waiting if the registry is in backoff.
+func (l *Limiter) Wait(ctx context.Context, registry string) error {
+	for {
+		l.mu.Lock()
+		now := l.now()
+		b := l.budgetInternal(registry, now)
+
+		if now.Before(b.blockedUntil) {
+			until := b.blockedUntil
+			l.mu.Unlock()
+			return &RateLimitedError{Registry: registry, Until: until}
+		}
+
+		if l.perMinute <= 0 {
+			l.mu.Unlock()
+			return nil
+		}
+
+		rate := float64(l.perMinute) / float64(time.Minute)
+		b.tokens += float64(now.Sub(b.last)) * rate
+		if b.tokens > float64(l.perMinute) {
+			b.tokens = float64(l.perMinute)
+		}
+		b.last = now
+
+		if b.tokens >= 1 {
+			b.tokens--
+			l.mu.Unlock()
+			return nil
+		}
+
+		wait := time.Duration((1 - b.tokens) / rate)
+		l.mu.Unlock()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// Backoff records a rate limit response from the registry and returns the time
+// until which further requests are refused. When retryAfter is zero an
+// exponential backoff is used.
+func (l *Limiter) Backoff(registry string, retryAfter time.Duration) time.Time {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := l.now()
+	b := l.budgetInternal(registry, now)
+	b.failures++
+
+	if retryAfter <= 0 {
+		retryAfter = minRateLimitBackoff << min(b.failures-1, 10)
+	}
+	retryAfter = min(retryAfter, maxRateLimitBackoff)
+
+	b.blockedUntil = now.Add(retryAfter)
+	return b.blockedUntil
+}
+
+// Success resets the backoff state after a successful request.
+func (l *Limiter) Success(registry string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if b, ok := l.budgets[registry]; ok {
+		b.failures = 0
+		b.blockedUntil = time.Time{}
+	}
+}
+
+// BlockedUntil reports whether the registry is currently in backoff and until when.
+func (l *Limiter) BlockedUntil(registry string) (time.Time, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	b, ok := l.budgets[registry]
+	if !ok || !l.now().Before(b.blockedUntil) {
+		return time.Time{}, false
+	}
+	return b.blockedUntil, true
+}
@@ -3,11 +3,13 @@ package registry
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"testing"
+	"time"
 )
 
 func TestCheckAuthParsesRealmAndService(t *testing.T) {
@@ -86,3 +88,208 @@ func TestGetLatestDigestLowerCaseHeader(t *testing.T) {
 		t.Fatalf("digest %q", d)
 	}
 }
+
+func TestGetLatestDigestRateLimited(t *testing.T) {
+	t.Parallel()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	c := NewClient()
+	_, err := c.GetLatestDigest(context.Background(), srv.URL, "org/repo", "latest", "")
+	var rlErr *RateLimitedError
+	if !errors.As(err, &rlErr) {
+		t.Fatalf("expected RateLimitedError, got %v", err)
+	}
+	if time.Until(rlErr.Until) < 100*time.Second {
+		t.Fatalf("until %v too early", rlErr.Until)
+	}
+}
+
+func TestLimiterBackoffAndBudget(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	l := NewLimiter(2)
+	l.now = func() time.Time { return now }
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		if err := l.Wait(ctx, "docker.io"); err != nil {
+			t.Fatalf("wait %d: %v", i, err)
+		}
+	}
+
+	until := l.Backoff("docker.io", 0)
+	if until != now.Add(minRateLimitBackoff) {
+		t.Fatalf("until %v", until)
+	}
+	var rlErr *RateLimitedError
+	if err := l.Wait(ctx, "docker.io"); !errors.As(err, &rlErr) {
+		t.Fatalf("expected RateLimitedError, got %v", err)
+	}
+	if err := l.Wait(ctx, "ghcr.io"); err != nil {
+		t.Fatalf("other registry should not be limited: %v", err)
+	}
+
+	if second := l.Backoff("docker.io", 0); second != now.Add(2*minRateLimitBackoff) {
+		t.Fatalf("expected exponential backoff, got %v", second)
+	}
+
+	l.Success("docker.io")
+	if _, blocked := l.BlockedUntil("docker.io"); blocked {
+		t.Fatalf("expected backoff to be cleared")
+	}
+}
+
+func TestLatestVersionTag(t *testing.T) {
+	t.Parallel()
+	tags := []string{"latest", "1.25", "1.26", "1.26.1", "1.27-alpine", "1.24", "v1.30", "develop"}
+
+	cases := []struct {
+		current string
+		want    string
+	}{
+		{"1.25", "1.26"},
+		{"1.26.0", "1.26.1"},
+		{"1.25-alpine", "1.27-alpine"},
+		{"v1.2", "v1.30"},
+		{"latest", ""},
+		{"2.0", ""},
+	}
+	for _, tc := range cases {
+		if got := LatestVersionTag(tc.current, tags); got != tc.want {
+			t.Fatalf("LatestVersionTag(%q) = %q, want %q", tc.current, got, tc.want)
+		}
+	}
+}
+
+func TestLatestVersionTagWithin(t *testing.T) {
+	t.Parallel()
+	tags := []string{"1.2.3", "1.2.9", "1.3.0", "1.9.4", "2.0.1", "1.2.10-alpine"}
+
+	cases := []struct {
+		fixed int
+		want  string
+	}{
+		{0, "2.0.1"},
+		{1, "1.9.4"},
+		{2, "1.2.9"},
+		{3, "1.2.3"},
+	}
+	for _, tc := range cases {
+		if got := LatestVersionTagWithin("1.2.3", tags, tc.fixed); got != tc.want {
+			t.Fatalf("LatestVersionTagWithin(fixed=%d) = %q, want %q", tc.fixed, got, tc.want)
+		}
+	}
+
+	if got := VersionTagsLike("1.2.3", tags); len(got) != 5 {
+		t.Fatalf("VersionTagsLike() = %v, want 5 tags", got)
+	}
+}
+
+func TestBackendFor(t *testing.T) {
+	t.Parallel()
+	cases := map[string]string{
+		"docker.io":            "dockerhub",
+		"registry-1.docker.io": "dockerhub",
+		"ghcr.io":              "ghcr",
+		"lscr.io":              "lscr",
+	}
+	for host, want := range cases {
+		b := BackendFor(host)
+		if b == nil || b.Name() != want {
+			t.Fatalf("BackendFor(%q) = %v, want %s", host, b, want)
+		}
+	}
+	if b := BackendFor("registry.example.com"); b != nil {
+		t.Fatalf("expected no backend for generic registry, got %s", b.Name())
+	}
+}
+
+func TestResolveOCITagInfo(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/org/app/manifests/1.0", "/v2/org/app/manifests/sha256:index":
+			w.Header().Set("Docker-Content-Digest", "sha256:index")
+			if r.Method == http.MethodHead {
+				return
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"manifests": []map[string]any{
+					{"digest": "sha256:arm", "platform": map[string]string{"os": "linux", "architecture": "arm64"}},
+					{"digest": "sha256:amd", "platform": map[string]string{"os": "linux", "architecture": "amd64"}},
+				},
+			})
+		case "/v2/org/app/manifests/sha256:amd":
+			_ = json.NewEncoder(w).Encode(map[string]any{"config": map[string]string{"digest": "sha256:cfg"}})
+		case "/v2/org/app/blobs/sha256:cfg":
+			_ = json.NewEncoder(w).Encode(map[string]any{"created": created})
+		case "/v2/org/app/tags/list":
+			_ = json.NewEncoder(w).Encode(map[string]any{"tags": []string{"0.9", "1.0", "1.1", "latest"}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	info, err := resolveOCITagInfo(context.Background(), NewClient(), srv.URL, "org/app", "1.0", "token")
+	if err != nil {
+		t.Fatalf("err: %v", err)
+	}
+	if info.Digest != "sha256:index" {
+		t.Fatalf("digest %q", info.Digest)
+	}
+	if !info.PublishedAt.Equal(created) {
+		t.Fatalf("publishedAt %v", info.PublishedAt)
+	}
+	if info.LatestTag != "1.1" {
+		t.Fatalf("latest tag %q", info.LatestTag)
+	}
+}
+
+func TestDockerHubBackendChargesPagesAndBacksOff(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	var srvURL string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/repositories/library/nginx/tags/1.25":
+			_ = json.NewEncoder(w).Encode(map[string]any{"name": "1.25", "digest": "sha256:abc"})
+		case r.URL.Path == "/repositories/library/nginx/tags" && r.URL.Query().Get("page") == "":
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"next":    srvURL + "/repositories/library/nginx/tags?page=2",
+				"results": []map[string]string{{"name": "1.26"}},
+			})
+		case r.URL.Path == "/repositories/library/nginx/tags":
+			w.Header().Set("Retry-After", "90")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	srvURL = srv.URL
+
+	l := NewLimiter(10)
+	l.now = func() time.Time { return now }
+	c := NewClient().WithLimiter(l)
+
+	_, err := dockerHubBackend{apiURL: srv.URL}.Resolve(context.Background(), c, "docker.io", "nginx", "1.25", "")
+	var rlErr *RateLimitedError
+	if !errors.As(err, &rlErr) {
+		t.Fatalf("expected RateLimitedError, got %v", err)
+	}
+	if rlErr.Registry != "docker.io" || rlErr.Until.Before(now.Add(80*time.Second)) || rlErr.Until.After(now.Add(90*time.Second)) {
+		t.Fatalf("unexpected rate limit error: %+v", rlErr)
+	}
+	if until, blocked := l.BlockedUntil("docker.io"); !blocked || !until.Equal(rlErr.Until) {
+		t.Fatalf("expected docker.io backoff until %v, got %v (%v)", rlErr.Until, until, blocked)
+	}
+	if tokens := l.budgets["docker.io"].tokens; tokens != 8 {
+		t.Fatalf("expected both tag pages to be charged, %v tokens left", tokens)
+	}
+}
@@ -2,18 +2,36 @@ package timeouts
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
 	"time"
 )
 
 const (
-	DefaultDockerAPI       = 30 * time.Second
-	DefaultDockerImagePull = 10 * time.Minute
-	DefaultGitOperation    = 5 * time.Minute
-	DefaultHTTPClient      = 30 * time.Second
-	DefaultRegistry        = 30 * time.Second
-	DefaultProxyRequest    = 60 * time.Second
+	DefaultDockerAPI         = 30 * time.Second
+	DefaultDockerImagePull   = 10 * time.Minute
+	DefaultGitOperation      = 5 * time.Minute
+	DefaultHTTPClient        = 30 * time.Second
+	DefaultRegistry          = 30 * time.Second
+	DefaultProxyRequest      = 60 * time.Second
+	DefaultHelperExec        = 60 * time.Second
+	DefaultHelperScan        = 10 * time.Minute
+	DefaultHelperArchive     = 2 * time.Hour
+	DefaultMaxRequestTimeout = 30 * time.Minute
 )
 
+// HeaderRequestTimeout lets a client set the deadline of a single API
+// request, either as a Go duration ("15s", "5m") or as whole seconds.
+const HeaderRequestTimeout = "X-Arcane-Timeout"
+
+// ErrInvalidRequestTimeout is returned for request timeouts that cannot be
+// parsed or are not positive.
+var ErrInvalidRequestTimeout = errors.New("invalid request timeout")
+
+type requestTimeoutKey struct{}
+
 func GetDuration(settingSeconds int, defaultDuration time.Duration) time.Duration {
 	if settingSeconds > 0 {
 		return time.Duration(settingSeconds) * time.Second
@@ -21,6 +39,52 @@ func GetDuration(settingSeconds int, defaultDuration time.Duration) time.Duratio
 	return defaultDuration
 }
 
+// WithTimeout bounds an operation by its configured timeout. When the request
+// set its own deadline with WithRequestTimeout, that deadline replaces the
+// configured one, so a client can both shorten and lengthen operations. A
+// context detached with context.WithoutCancel keeps the request value but not
+// its deadline, so it falls back to the configured timeout.
 func WithTimeout(ctx context.Context, settingSeconds int, defaultDuration time.Duration) (context.Context, context.CancelFunc) {
+	if _, ok := RequestTimeout(ctx); ok {
+		if _, hasDeadline := ctx.Deadline(); hasDeadline {
+			return context.WithCancel(ctx)
+		}
+	}
 	return context.WithTimeout(ctx, GetDuration(settingSeconds, defaultDuration))
 }
+
+// WithRequestTimeout sets the deadline of the whole request and records it so
+// WithTimeout uses it in place of the configured operation timeouts.
+func WithRequestTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return context.WithValue(ctx, requestTimeoutKey{}, timeout), cancel
+}
+
+// RequestTimeout returns the timeout set with WithRequestTimeout, if any.
+func RequestTimeout(ctx context.Context) (time.Duration, bool) {
+	timeout, ok := ctx.Value(requestTimeoutKey{}).(time.Duration)
+	return timeout, ok
+}
+
+// ParseRequestTimeout parses a HeaderRequestTimeout value and caps it at maxTimeout.
+func ParseRequestTimeout(value string, maxTimeout time.Duration) (time.Duration, error) {
+	value = strings.TrimSpace(value)
+
+	var timeout time.Duration
+	if seconds, err := strconv.Atoi(value); err == nil {
+		timeout = time.Duration(seconds) * time.Second
+	} else {
+		timeout, err = time.ParseDuration(value)
+		if err != nil {
+			return 0, fmt.Errorf("%w: %q is neither a duration nor a number of seconds", ErrInvalidRequestTimeout, value)
+		}
+	}
+
+	if timeout <= 0 {
+		return 0, fmt.Errorf("%w: %q must be positive", ErrInvalidRequestTimeout, value)
+	}
+	if maxTimeout > 0 && timeout > maxTimeout {
+		timeout = maxTimeout
+	}
+	return timeout, nil
+}
@@ -0,0 +1,137 @@
+// Package volumesnapshot builds the host commands used to take, restore and
+// delete ZFS and Btrfs snapshots of local Docker volumes. The scripts run in
+// the host mount namespace and receive paths as positional parameters.
+package volumesnapshot
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+type Kind string
+
+const (
+	KindZFS   Kind = "zfs"
+	KindBtrfs Kind = "btrfs"
+)
+
+// snapshotPrefix is prepended to Arcane-created snapshot names so they are easy
+// to tell apart from snapshots taken by other tools.
+const snapshotPrefix = "arcane-"
+
+// DetectScript prints the filesystem backing the volume path in $1: "zfs"
+// followed by the dataset name and mountpoint, "btrfs" when the path is a
+// subvolume, or "other".
+const DetectScript = `set -e
+fs=$(stat -f -c %T "$1")
+case "$fs" in
+zfs)
+	printf 'zfs\t'
+	zfs list -H -o name,mountpoint "$1" | head -n1
+	;;
+btrfs)
+	if btrfs subvolume show "$1" >/dev/null 2>&1; then echo btrfs; else echo other; fi
+	;;
+*)
+	echo other
+	;;
+esac`
+
+// createZFSScript snapshots the dataset $1 and prints the space it uses.
+const createZFSScript = `set -e
+zfs snapshot "$1"
+zfs get -Hp -o value used "$1"`
+
+// createBtrfsScript takes a read-only snapshot of subvolume $1 at $2.
+const createBtrfsScript = `set -e
+mkdir -p "$(dirname "$2")"
+btrfs subvolume snapshot -r "$1" "$2" >/dev/null
+echo 0`
+
+const deleteZFSScript = `zfs destroy "$1"`
+
+const deleteBtrfsScript = `btrfs subvolume delete "$1"`
+
+// Target is a volume whose data can be snapshotted.
+type Target struct {
+	Kind Kind
+	// Path is the host path of the volume data.
+	Path string
+	// Dataset and DatasetMountpoint are set for ZFS targets.
+	Dataset           string
+	DatasetMountpoint string
+}
+
+// ParseDetectOutput interprets the output of DetectScript for volumePath. It
+// returns nil when the volume is not on a supported filesystem.
+func ParseDetectOutput(volumePath, output string) (*Target, error) {
+	line := strings.TrimSpace(output)
+	kind, rest, _ := strings.Cut(line, "\t")
+
+	switch Kind(kind) {
+	case KindZFS:
+		fields := strings.Split(rest, "\t")
+		if len(fields) != 2 || fields[0] == "" || !strings.HasPrefix(fields[1], "/") {
+			return nil, fmt.Errorf("unexpected zfs dataset output: %q", rest)
+		}
+		mountpoint := path.Clean(fields[1])
+		if volumePath != mountpoint && !strings.HasPrefix(volumePath, strings.TrimSuffix(mountpoint, "/")+"/") {
+			return nil, fmt.Errorf("volume path %s is not under dataset mountpoint %s", volumePath, mountpoint)
+		}
+		return &Target{Kind: KindZFS, Path: volumePath, Dataset: fields[0], DatasetMountpoint: mountpoint}, nil
+	case KindBtrfs:
+		return &Target{Kind: KindBtrfs, Path: volumePath}, nil
+	default:
+		return nil, nil
+	}
+}
+
+// SnapshotRef returns the ZFS snapshot name or Btrfs snapshot path for id.
+// Btrfs snapshots are kept in an arcane-snapshots directory next to Docker's
+// volumes directory so Docker never mistakes them for volumes.
+func (t *Target) SnapshotRef(id string) string {
+	if t.Kind == KindZFS {
+		return t.Dataset + "@" + snapshotPrefix + id
+	}
+	// <docker root>/volumes/<name>/_data -> <docker root>/arcane-snapshots/<id>
+	root := path.Dir(path.Dir(path.Dir(t.Path)))
+	return path.Join(root, "arcane-snapshots", id)
+}
+
+// SnapshotDataPath returns the host path that holds the snapshot's copy of the
+// volume data.
+func (t *Target) SnapshotDataPath(id string) string {
+	if t.Kind == KindZFS {
+		rel := strings.TrimPrefix(strings.TrimPrefix(t.Path, t.DatasetMountpoint), "/")
+		return path.Join(t.DatasetMountpoint, ".zfs", "snapshot", snapshotPrefix+id, rel)
+	}
+	return t.SnapshotRef(id)
+}
+
+// CreateCommand returns the script and arguments that take snapshot id. The
+// script prints the space used by the snapshot in bytes.
+func (t *Target) CreateCommand(id string) (string, []string) {
+	if t.Kind == KindZFS {
+		return createZFSScript, []string{t.SnapshotRef(id)}
+	}
+	return createBtrfsScript, []string{t.Path, t.SnapshotRef(id)}
+}
+
+// DeleteCommand returns the script and arguments that remove the snapshot ref.
+func DeleteCommand(kind Kind, ref string) (string, []string, error) {
+	switch kind {
+	case KindZFS:
+		if !strings.Contains(ref, "@"+snapshotPrefix) {
+			return "", nil, fmt.Errorf("refusing to destroy non-Arcane snapshot %q", ref)
+		}
+		return deleteZFSScript, []string{ref}, nil
+	case KindBtrfs:
+		if path.Base(path.Dir(ref)) != "arcane-snapshots" {
+			return "", nil, fmt.Errorf("refusing to delete non-Arcane subvolume %q", ref)
+		}
+		return deleteBtrfsScript, []string{ref}, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported snapshot kind %q", kind)
+	}
+}
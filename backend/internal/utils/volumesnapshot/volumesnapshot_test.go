@@ -0,0 +1,59 @@
+package volumesnapshot
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const volumePath = "/var/lib/docker/volumes/data/_data"
+
+func TestParseDetectOutput(t *testing.T) {
+	t.Run("zfs", func(t *testing.T) {
+		target, err := ParseDetectOutput(volumePath, "zfs\ttank/docker\t/var/lib/docker\n")
+		require.NoError(t, err)
+		require.NotNil(t, target)
+		assert.Equal(t, KindZFS, target.Kind)
+		assert.Equal(t, "tank/docker@arcane-b1", target.SnapshotRef("b1"))
+		assert.Equal(t, "/var/lib/docker/.zfs/snapshot/arcane-b1/volumes/data/_data", target.SnapshotDataPath("b1"))
+
+		script, args := target.CreateCommand("b1")
+		assert.Equal(t, createZFSScript, script)
+		assert.Equal(t, []string{"tank/docker@arcane-b1"}, args)
+	})
+
+	t.Run("zfs mountpoint mismatch", func(t *testing.T) {
+		_, err := ParseDetectOutput(volumePath, "zfs\ttank/other\t/srv\n")
+		assert.Error(t, err)
+	})
+
+	t.Run("btrfs", func(t *testing.T) {
+		target, err := ParseDetectOutput(volumePath, "btrfs\n")
+		require.NoError(t, err)
+		require.NotNil(t, target)
+		assert.Equal(t, "/var/lib/docker/arcane-snapshots/b1", target.SnapshotRef("b1"))
+		assert.Equal(t, target.SnapshotRef("b1"), target.SnapshotDataPath("b1"))
+
+		_, args := target.CreateCommand("b1")
+		assert.Equal(t, []string{volumePath, "/var/lib/docker/arcane-snapshots/b1"}, args)
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		target, err := ParseDetectOutput(volumePath, "other\n")
+		require.NoError(t, err)
+		assert.Nil(t, target)
+	})
+}
+
+func TestDeleteCommandRejectsForeignSnapshots(t *testing.T) {
+	_, _, err := DeleteCommand(KindZFS, "tank/docker@daily")
+	assert.Error(t, err)
+
+	_, _, err = DeleteCommand(KindBtrfs, "/var/lib/docker/volumes/data/_data")
+	assert.Error(t, err)
+
+	_, args, err := DeleteCommand(KindBtrfs, "/var/lib/docker/arcane-snapshots/b1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"/var/lib/docker/arcane-snapshots/b1"}, args)
+}
@@ -9,10 +9,14 @@ import (
 type LogMessage struct {
 	Seq         uint64 `json:"seq"`
 	Level       string `json:"level,omitempty"`
+	Severity    string `json:"severity,omitempty"`
 	Message     string `json:"message"`
 	Timestamp   string `json:"timestamp"` // RFC3339(9) string
 	Service     string `json:"service,omitempty"`
 	ContainerID string `json:"containerId,omitempty"`
+	Container   string `json:"container,omitempty"`
+	// Fields holds the parsed JSON of the message in structured mode.
+	Fields map[string]any `json:"fields,omitempty"`
 }
 
 // ForwardLines forwards plain text lines to the hub.
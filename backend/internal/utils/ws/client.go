@@ -20,11 +20,13 @@ const (
 	clientSendBuffer = 256
 )
 
-// Client represents a single WebSocket connection.
+// Client represents a single WebSocket connection, or a server-sent events
+// stream when conn is nil.
 type Client struct {
-	conn *websocket.Conn
-	send chan []byte
-	once sync.Once
+	conn    *websocket.Conn
+	send    chan []byte
+	once    sync.Once
+	onClose func()
 }
 
 func NewClient(conn *websocket.Conn, sendBuffer int) *Client {
@@ -46,9 +48,30 @@ func ServeClient(ctx context.Context, hub *Hub, conn *websocket.Conn) {
 	go c.readPump(ctx, hub)
 }
 
+// ServeClientWithOnClose is like ServeClient but calls onClose once the
+// client has disconnected. It is meant for hubs shared between many clients,
+// where the hub's OnEmpty callback cannot track individual connections.
+func ServeClientWithOnClose(ctx context.Context, hub *Hub, conn *websocket.Conn, onClose func()) {
+	c := NewClient(conn, clientSendBuffer)
+	c.onClose = onClose
+	hub.register <- c
+
+	go c.writePump(ctx, hub)
+	go c.readPump(ctx, hub)
+}
+
+func (c *Client) closeConn() {
+	if c.conn != nil {
+		_ = c.conn.Close()
+	}
+}
+
 func (c *Client) safeRemove(hub *Hub) {
 	c.once.Do(func() {
 		hub.remove(c)
+		if c.onClose != nil {
+			c.onClose()
+		}
 	})
 }
 
@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -105,6 +106,44 @@ func TestServeClient_ClientDisconnect(t *testing.T) {
 	}, 5*time.Second, 50*time.Millisecond)
 }
 
+func TestServeClientWithOnClose_CallsOnCloseOnce(t *testing.T) {
+	h := NewHub(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.Run(ctx)
+
+	var closed atomic.Int32
+	upgrader := websocket.Upgrader{CheckOrigin: func(r *http.Request) bool { return true }}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		ServeClientWithOnClose(ctx, h, conn, func() { closed.Add(1) })
+	}))
+	defer server.Close()
+
+	url := "ws" + strings.TrimPrefix(server.URL, "http")
+	clientConn, resp, err := websocket.DefaultDialer.Dial(url, nil)
+	require.NoError(t, err)
+	if resp != nil {
+		resp.Body.Close()
+	}
+
+	require.Eventually(t, func() bool {
+		return h.ClientCount() == 1
+	}, time.Second, 5*time.Millisecond)
+
+	clientConn.Close()
+
+	require.Eventually(t, func() bool {
+		return closed.Load() == 1
+	}, 5*time.Second, 50*time.Millisecond)
+	assert.Equal(t, 0, h.ClientCount())
+	time.Sleep(100 * time.Millisecond)
+	assert.Equal(t, int32(1), closed.Load())
+}
+
 func TestServeClient_ContextCancellation(t *testing.T) {
 	h := NewHub(10)
 	hubCtx, hubCancel := context.WithCancel(context.Background())
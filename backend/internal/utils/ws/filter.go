@@ -0,0 +1,180 @@
+package ws
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// Log severities reported by DetectLogLevel.
+const (
+	LogLevelTrace = "trace"
+	LogLevelDebug = "debug"
+	LogLevelInfo  = "info"
+	LogLevelWarn  = "warn"
+	LogLevelError = "error"
+	LogLevelFatal = "fatal"
+)
+
+// maxLogSearchLength bounds the search text or pattern of a LogFilter.
+const maxLogSearchLength = 512
+
+var ErrInvalidLogFilter = errors.New("invalid log filter")
+
+var (
+	// logLevelPatterns recognise the severity markers of common log formats:
+	// level=warn, "level":"warn", [warn], <warn> in any case, and WARN as a
+	// bare uppercase word.
+	logLevelPatterns = []struct {
+		level string
+		re    *regexp.Regexp
+	}{
+		{LogLevelFatal, logLevelPatternInternal(`fatal|panic|crit(?:ical)?|emerg(?:ency)?`, `FATAL|PANIC|CRIT|CRITICAL|EMERG`)},
+		{LogLevelError, logLevelPatternInternal(`err|error|eror`, `ERR|ERROR|EROR`)},
+		{LogLevelWarn, logLevelPatternInternal(`warn|warning`, `WARN|WARNING`)},
+		{LogLevelInfo, logLevelPatternInternal(`info|notice`, `INFO|NOTICE`)},
+		{LogLevelDebug, logLevelPatternInternal(`debug`, `DEBUG`)},
+		{LogLevelTrace, logLevelPatternInternal(`trace`, `TRACE`)},
+	}
+	logLevelAliases = map[string]string{
+		"warning":  LogLevelWarn,
+		"err":      LogLevelError,
+		"critical": LogLevelFatal,
+		"panic":    LogLevelFatal,
+	}
+)
+
+func logLevelPatternInternal(names, upper string) *regexp.Regexp {
+	return regexp.MustCompile(`(?i:\blevel["']?\s*[=:]\s*["']?(?:` + names + `)\b|[\[<(](?:` + names + `)[\]>)])|\b(?:` + upper + `)\b`)
+}
+
+// DetectLogLevel guesses the severity of a log message from the markers
+// used by common log formats. The leftmost marker wins; it returns "" when
+// the message has none.
+func DetectLogLevel(msg string) string {
+	level, first := "", len(msg)+1
+	for _, p := range logLevelPatterns {
+		if loc := p.re.FindStringIndex(msg); loc != nil && loc[0] < first {
+			level, first = p.level, loc[0]
+		}
+	}
+	return level
+}
+
+// LogFilterOptions are the log filter parameters of a log stream request.
+type LogFilterOptions struct {
+	// Search keeps lines containing the text, or matching it when Regex is set.
+	Search        string
+	Regex         bool
+	CaseSensitive bool
+	// Levels keeps lines whose detected severity is one of these. Lines
+	// without a detectable severity are dropped.
+	Levels []string
+	// Stream keeps only stdout or stderr lines.
+	Stream string
+	// DetectLevel reports the detected severity of JSON log messages.
+	DetectLevel bool
+}
+
+// LogFilter decides which log lines of a stream are sent to clients. A nil
+// filter keeps every line.
+type LogFilter struct {
+	search        string
+	re            *regexp.Regexp
+	caseSensitive bool
+	levels        []string
+	stream        string
+	detectLevel   bool
+}
+
+// NewLogFilter builds a filter from request options. It returns nil when
+// the options neither filter nor detect anything.
+func NewLogFilter(opts LogFilterOptions) (*LogFilter, error) {
+	if len(opts.Search) > maxLogSearchLength {
+		return nil, fmt.Errorf("%w: search must be at most %d characters", ErrInvalidLogFilter, maxLogSearchLength)
+	}
+
+	f := &LogFilter{
+		search:        opts.Search,
+		caseSensitive: opts.CaseSensitive,
+		detectLevel:   opts.DetectLevel,
+	}
+	if opts.Search != "" && opts.Regex {
+		pattern := opts.Search
+		if !opts.CaseSensitive {
+			pattern = "(?i)" + pattern
+		}
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrInvalidLogFilter, err)
+		}
+		f.re = re
+	} else if !opts.CaseSensitive {
+		f.search = strings.ToLower(opts.Search)
+	}
+
+	for _, level := range opts.Levels {
+		level = strings.ToLower(strings.TrimSpace(level))
+		if alias, ok := logLevelAliases[level]; ok {
+			level = alias
+		}
+		if level == "" {
+			continue
+		}
+		if !slices.Contains([]string{LogLevelTrace, LogLevelDebug, LogLevelInfo, LogLevelWarn, LogLevelError, LogLevelFatal}, level) {
+			return nil, fmt.Errorf("%w: unknown level %q", ErrInvalidLogFilter, level)
+		}
+		if !slices.Contains(f.levels, level) {
+			f.levels = append(f.levels, level)
+		}
+	}
+
+	switch stream := strings.ToLower(strings.TrimSpace(opts.Stream)); stream {
+	case "", "all":
+	case "stdout", "stderr":
+		f.stream = stream
+	default:
+		return nil, fmt.Errorf("%w: stream must be stdout or stderr", ErrInvalidLogFilter)
+	}
+
+	if f.search == "" && f.re == nil && len(f.levels) == 0 && f.stream == "" && !f.detectLevel {
+		return nil, nil
+	}
+	return f, nil
+}
+
+// Match reports whether a line from stream ("stdout" or "stderr") with the
+// given message passes the filter, along with its detected severity when
+// level detection or level filtering is on.
+func (f *LogFilter) Match(stream, msg string) (severity string, ok bool) {
+	if f == nil {
+		return "", true
+	}
+	if f.stream != "" && stream != f.stream {
+		return "", false
+	}
+	switch {
+	case f.re != nil:
+		if !f.re.MatchString(msg) {
+			return "", false
+		}
+	case f.search != "":
+		haystack := msg
+		if !f.caseSensitive {
+			haystack = strings.ToLower(msg)
+		}
+		if !strings.Contains(haystack, f.search) {
+			return "", false
+		}
+	}
+	if len(f.levels) == 0 && !f.detectLevel {
+		return "", true
+	}
+	severity = DetectLogLevel(msg)
+	if len(f.levels) > 0 && !slices.Contains(f.levels, severity) {
+		return severity, false
+	}
+	return severity, true
+}
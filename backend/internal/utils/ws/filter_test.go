@@ -0,0 +1,81 @@
+package ws
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLogLevel(t *testing.T) {
+	tests := map[string]string{
+		`time="2024-01-01" level=warning msg="disk almost full"`: LogLevelWarn,
+		`{"level":"error","msg":"connection refused"}`:           LogLevelError,
+		`2024-01-01 12:00:00 [INFO] server started`:              LogLevelInfo,
+		`DEBUG cache miss for key users:1`:                       LogLevelDebug,
+		`<crit> worker died`:                                     LogLevelFatal,
+		`INFO retrying after ERROR from upstream`:                LogLevelInfo,
+		`GET /health 200 - no error here`:                        "",
+		`plain message`:                                          "",
+		`level=Trace entering handler`:                           LogLevelTrace,
+		`[Warn] deprecated option`:                               LogLevelWarn,
+	}
+	for msg, want := range tests {
+		assert.Equal(t, want, DetectLogLevel(msg), msg)
+	}
+}
+
+func TestLogFilter(t *testing.T) {
+	f, err := NewLogFilter(LogFilterOptions{})
+	require.NoError(t, err)
+	assert.Nil(t, f)
+	_, ok := f.Match("stdout", "anything")
+	assert.True(t, ok)
+
+	f, err = NewLogFilter(LogFilterOptions{Search: "Timeout"})
+	require.NoError(t, err)
+	_, ok = f.Match("stdout", "request timeout after 5s")
+	assert.True(t, ok)
+	_, ok = f.Match("stdout", "request ok")
+	assert.False(t, ok)
+
+	f, err = NewLogFilter(LogFilterOptions{Search: "Timeout", CaseSensitive: true})
+	require.NoError(t, err)
+	_, ok = f.Match("stdout", "request timeout after 5s")
+	assert.False(t, ok)
+
+	f, err = NewLogFilter(LogFilterOptions{Search: `status=5\d\d`, Regex: true, Stream: "stderr"})
+	require.NoError(t, err)
+	_, ok = f.Match("stderr", "GET / status=502")
+	assert.True(t, ok)
+	_, ok = f.Match("stdout", "GET / status=502")
+	assert.False(t, ok)
+	_, ok = f.Match("stderr", "GET / status=200")
+	assert.False(t, ok)
+
+	f, err = NewLogFilter(LogFilterOptions{Levels: []string{"warning", "ERROR"}})
+	require.NoError(t, err)
+	severity, ok := f.Match("stdout", "level=warn msg=slow")
+	assert.True(t, ok)
+	assert.Equal(t, LogLevelWarn, severity)
+	_, ok = f.Match("stdout", "level=info msg=ok")
+	assert.False(t, ok)
+	_, ok = f.Match("stdout", "no level at all")
+	assert.False(t, ok)
+
+	f, err = NewLogFilter(LogFilterOptions{DetectLevel: true})
+	require.NoError(t, err)
+	severity, ok = f.Match("stdout", "[ERROR] boom")
+	assert.True(t, ok)
+	assert.Equal(t, LogLevelError, severity)
+
+	for _, bad := range []LogFilterOptions{
+		{Search: "(unclosed", Regex: true},
+		{Levels: []string{"verbose"}},
+		{Stream: "stdin"},
+		{Search: string(make([]byte, maxLogSearchLength+1))},
+	} {
+		_, err := NewLogFilter(bad)
+		require.ErrorIs(t, err, ErrInvalidLogFilter, "%+v", bad)
+	}
+}
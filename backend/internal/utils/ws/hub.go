@@ -4,8 +4,15 @@ import (
 	"context"
 	"log/slog"
 	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
 )
 
+// runningHubs tracks hubs whose Run loop is active so they can be closed
+// together on shutdown.
+var runningHubs sync.Map
+
 type Hub struct {
 	mu         sync.RWMutex
 	clients    map[*Client]struct{}
@@ -37,6 +44,8 @@ func (h *Hub) SetOnEmpty(fn func()) {
 }
 
 func (h *Hub) Run(ctx context.Context) {
+	runningHubs.Store(h, struct{}{})
+	defer runningHubs.Delete(h)
 	defer h.closeAll()
 
 	for {
@@ -87,7 +96,7 @@ func (h *Hub) remove(c *Client) {
 	if exists {
 		delete(h.clients, c)
 		close(c.send)
-		_ = c.conn.Close()
+		c.closeConn()
 	}
 	// Capture onEmpty under the lock so we can call it outside.
 	var onEmpty func()
@@ -107,8 +116,38 @@ func (h *Hub) closeAll() {
 	h.mu.Lock()
 	for c := range h.clients {
 		close(c.send)
-		_ = c.conn.Close()
+		c.closeConn()
 		delete(h.clients, c)
 	}
 	h.mu.Unlock()
 }
+
+// CloseAllHubs sends every client of every running hub a going-away close
+// frame and disconnects it, so browsers reconnect cleanly once the server is
+// back instead of seeing an abnormal closure.
+func CloseAllHubs() {
+	runningHubs.Range(func(key, _ any) bool {
+		key.(*Hub).closeGoingAway()
+		return true
+	})
+}
+
+func (h *Hub) closeGoingAway() {
+	h.mu.RLock()
+	clients := make([]*Client, 0, len(h.clients))
+	for c := range h.clients {
+		clients = append(clients, c)
+	}
+	h.mu.RUnlock()
+
+	msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	for _, c := range clients {
+		// WriteControl is safe to call concurrently with the client's writePump.
+		// SSE clients have no connection of their own; removing them ends
+		// the response.
+		if c.conn != nil {
+			_ = c.conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(pingWriteWait))
+		}
+		h.remove(c)
+	}
+}
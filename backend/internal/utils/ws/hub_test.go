@@ -374,3 +374,26 @@ func TestHub_DoubleUnregister(t *testing.T) {
 	time.Sleep(50 * time.Millisecond)
 	assert.Equal(t, 0, h.ClientCount())
 }
+
+func TestCloseAllHubs_SendsGoingAway(t *testing.T) {
+	h := NewHub(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.Run(ctx)
+
+	clientConn, serverConn, cleanup := newTestWSPair(t)
+	defer cleanup()
+
+	h.register <- NewClient(serverConn, 16)
+	require.Eventually(t, func() bool {
+		return h.ClientCount() == 1
+	}, time.Second, 5*time.Millisecond)
+
+	CloseAllHubs()
+	assert.Equal(t, 0, h.ClientCount())
+
+	_ = clientConn.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err := clientConn.ReadMessage()
+	require.Error(t, err)
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "expected going-away close, got %v", err)
+}
@@ -0,0 +1,155 @@
+package ws
+
+import (
+	"container/heap"
+	"context"
+	"sync"
+	"time"
+)
+
+// LogSource is the raw line stream of one container in a merged log view.
+type LogSource struct {
+	ContainerID string
+	// Container is the label sent with every line, usually the container name.
+	Container string
+	Service   string
+	Lines     <-chan string
+}
+
+// MergeLogSources merges the lines of several containers into one stream
+// ordered by timestamp and closes out when all sources are done.
+//
+// Lines are parsed with NormalizeContainerLine, so sources should be read
+// with Docker timestamps enabled. Every line is held back for window before
+// it is sent, giving slower sources the chance to deliver earlier lines; a
+// line that arrives later than that is sent out of order rather than
+// dropped. Timestamps ahead of the arrival time (clock skew) are clamped to
+// it. Lines rejected by filter are skipped.
+func MergeLogSources(ctx context.Context, sources []LogSource, window time.Duration, filter *LogFilter, out chan<- LogMessage) {
+	defer close(out)
+
+	in := make(chan mergedLogLine, 256)
+	var wg sync.WaitGroup
+	for i, src := range sources {
+		wg.Add(1)
+		go func(source int, src LogSource) {
+			defer wg.Done()
+			readLogSourceInternal(ctx, source, src, filter, in)
+		}(i, src)
+	}
+	go func() {
+		wg.Wait()
+		close(in)
+	}()
+
+	tick := max(window/4, 10*time.Millisecond)
+	ticker := time.NewTicker(tick)
+	defer ticker.Stop()
+
+	var (
+		pending mergedLogHeap
+		seq     uint64
+	)
+	emit := func(until time.Time, all bool) bool {
+		for pending.Len() > 0 {
+			if !all && pending[0].at.After(until) {
+				return true
+			}
+			line := heap.Pop(&pending).(mergedLogLine)
+			seq++
+			line.msg.Seq = seq
+			line.msg.Timestamp = line.at.UTC().Format(time.RFC3339Nano)
+			select {
+			case out <- line.msg:
+			case <-ctx.Done():
+				return false
+			}
+		}
+		return true
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case line, ok := <-in:
+			if !ok {
+				emit(time.Time{}, true)
+				return
+			}
+			heap.Push(&pending, line)
+		case now := <-ticker.C:
+			if !emit(now.Add(-window), false) {
+				return
+			}
+		}
+	}
+}
+
+type mergedLogLine struct {
+	msg LogMessage
+	at  time.Time
+	// source is the index of the line's source and order its position in
+	// that source, so lines with equal timestamps are ordered the same way
+	// regardless of goroutine scheduling.
+	source int
+	order  uint64
+}
+
+func readLogSourceInternal(ctx context.Context, source int, src LogSource, filter *LogFilter, in chan<- mergedLogLine) {
+	var order uint64
+	for raw := range src.Lines {
+		order++
+		level, msg, ts := NormalizeContainerLine(raw)
+		severity, ok := filter.Match(level, msg)
+		if !ok {
+			continue
+		}
+		at := time.Now()
+		if parsed, err := time.Parse(time.RFC3339Nano, ts); err == nil && parsed.Before(at) {
+			at = parsed
+		}
+		line := mergedLogLine{
+			msg: LogMessage{
+				Level:       level,
+				Severity:    severity,
+				Message:     msg,
+				Service:     src.Service,
+				ContainerID: src.ContainerID,
+				Container:   src.Container,
+			},
+			at:     at,
+			source: source,
+			order:  order,
+		}
+		select {
+		case in <- line:
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// mergedLogHeap orders lines by timestamp, then by source, then by their
+// position within the source.
+type mergedLogHeap []mergedLogLine
+
+func (h mergedLogHeap) Len() int { return len(h) }
+func (h mergedLogHeap) Less(i, j int) bool {
+	if !h[i].at.Equal(h[j].at) {
+		return h[i].at.Before(h[j].at)
+	}
+	if h[i].source != h[j].source {
+		return h[i].source < h[j].source
+	}
+	return h[i].order < h[j].order
+}
+func (h mergedLogHeap) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *mergedLogHeap) Push(x any)   { *h = append(*h, x.(mergedLogLine)) }
+func (h *mergedLogHeap) Pop() any {
+	old := *h
+	n := len(old)
+	x := old[n-1]
+	*h = old[:n-1]
+	return x
+}
@@ -0,0 +1,90 @@
+package ws
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func closedLogLines(lines ...string) <-chan string {
+	ch := make(chan string, len(lines))
+	for _, l := range lines {
+		ch <- l
+	}
+	close(ch)
+	return ch
+}
+
+func collectMergedLogs(t *testing.T, sources []LogSource, filter *LogFilter) []LogMessage {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	out := make(chan LogMessage, 16)
+	go MergeLogSources(ctx, sources, 50*time.Millisecond, filter, out)
+
+	var msgs []LogMessage
+	for m := range out {
+		msgs = append(msgs, m)
+	}
+	require.NoError(t, ctx.Err())
+	return msgs
+}
+
+func TestMergeLogSources_OrdersByTimestamp(t *testing.T) {
+	msgs := collectMergedLogs(t, []LogSource{
+		{ContainerID: "a1", Container: "api", Service: "api", Lines: closedLogLines(
+			"2024-01-01T10:00:01.000000000Z request received",
+			"[STDERR] 2024-01-01T10:00:03.000000000Z upstream failed",
+		)},
+		{ContainerID: "d1", Container: "db", Lines: closedLogLines(
+			"2024-01-01T10:00:02.000000000Z query executed",
+			"2024-01-01T10:00:03.000000000Z connection closed",
+		)},
+	}, nil)
+
+	// The two lines at 10:00:03 tie and are ordered by source.
+	require.Len(t, msgs, 4)
+	got := make([]string, len(msgs))
+	for i, m := range msgs {
+		got[i] = m.Container + ": " + m.Message
+		assert.Equal(t, uint64(i+1), m.Seq)
+	}
+	assert.Equal(t, []string{
+		"api: request received",
+		"db: query executed",
+		"api: upstream failed",
+		"db: connection closed",
+	}, got)
+	assert.Equal(t, "stderr", msgs[2].Level)
+	assert.Equal(t, "a1", msgs[0].ContainerID)
+	assert.Equal(t, "api", msgs[0].Service)
+	assert.Equal(t, "2024-01-01T10:00:02Z", msgs[1].Timestamp)
+}
+
+func TestMergeLogSources_AppliesFilter(t *testing.T) {
+	filter, err := NewLogFilter(LogFilterOptions{Stream: "stderr"})
+	require.NoError(t, err)
+
+	msgs := collectMergedLogs(t, []LogSource{
+		{Container: "api", Lines: closedLogLines("2024-01-01T10:00:01Z ok", "[STDERR] 2024-01-01T10:00:02Z boom")},
+		{Container: "db", Lines: closedLogLines("2024-01-01T10:00:03Z ok")},
+	}, filter)
+
+	require.Len(t, msgs, 1)
+	assert.Equal(t, "boom", msgs[0].Message)
+}
+
+func TestMergeLogSources_ClampsFutureTimestamps(t *testing.T) {
+	msgs := collectMergedLogs(t, []LogSource{
+		{Container: "api", Lines: closedLogLines("2999-01-01T00:00:00Z from the future")},
+	}, nil)
+
+	require.Len(t, msgs, 1)
+	ts, err := time.Parse(time.RFC3339Nano, msgs[0].Timestamp)
+	require.NoError(t, err)
+	assert.WithinDuration(t, time.Now(), ts, time.Minute)
+}
@@ -1,19 +1,92 @@
 package ws
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
 	"regexp"
 	"strings"
 	"time"
 )
 
+// Log stream modes.
+const (
+	// LogModeNormalized trims messages. It is the default.
+	LogModeNormalized = "normalized"
+	// LogModeRaw keeps messages as the container wrote them, including ANSI
+	// escape codes and surrounding whitespace.
+	LogModeRaw = "raw"
+	// LogModeStructured normalizes messages and parses JSON object messages
+	// into fields.
+	LogModeStructured = "structured"
+)
+
+// ErrInvalidLogMode is returned for an unknown log stream mode.
+var ErrInvalidLogMode = errors.New("invalid log mode")
+
 var (
 	// Docker's RFC3339 timestamp when timestamps=true
 	dockerTimestamp = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(?:\.\d+)?Z\s+`)
 )
 
+// ParseLogMode validates a log stream mode; empty means LogModeNormalized.
+func ParseLogMode(mode string) (string, error) {
+	switch mode {
+	case "":
+		return LogModeNormalized, nil
+	case LogModeNormalized, LogModeRaw, LogModeStructured:
+		return mode, nil
+	default:
+		return "", fmt.Errorf("%w %q: must be normalized, raw or structured", ErrInvalidLogMode, mode)
+	}
+}
+
+// ParseContainerLine splits a raw container log line like
+// NormalizeContainerLine, keeping the message unchanged in LogModeRaw.
+func ParseContainerLine(raw, mode string) (level, msg, timestamp string) {
+	if mode == LogModeRaw {
+		return splitContainerLineInternal(raw, false)
+	}
+	return NormalizeContainerLine(raw)
+}
+
+// ParseProjectLine splits a raw project log line like NormalizeProjectLine,
+// keeping the message unchanged in LogModeRaw.
+func ParseProjectLine(raw, mode string) (level, service, msg, timestamp string) {
+	if mode != LogModeRaw {
+		return NormalizeProjectLine(raw)
+	}
+	level, base, timestamp := splitContainerLineInternal(raw, false)
+	if svc, rest, ok := strings.Cut(base, " | "); ok {
+		return level, strings.TrimSpace(svc), rest, timestamp
+	}
+	return level, "", base, timestamp
+}
+
+// ParseLogFields returns the fields of a message that is a JSON object, or
+// nil when it is not.
+func ParseLogFields(msg string) map[string]any {
+	msg = strings.TrimSpace(msg)
+	if len(msg) < 2 || msg[0] != '{' || msg[len(msg)-1] != '}' {
+		return nil
+	}
+	var fields map[string]any
+	if err := json.Unmarshal([]byte(msg), &fields); err != nil {
+		return nil
+	}
+	return fields
+}
+
 // NormalizeContainerLine parses a raw container log line into level + cleaned message.
 // It extracts Docker's timestamp if present (when timestamps=true in Docker API).
 func NormalizeContainerLine(raw string) (level string, msg string, timestamp string) {
+	return splitContainerLineInternal(raw, true)
+}
+
+// splitContainerLineInternal strips the stream prefix and Docker timestamp of
+// a line. With trim the message is trimmed; otherwise only the line ending
+// and the single space after the timestamp are removed.
+func splitContainerLineInternal(raw string, trim bool) (level string, msg string, timestamp string) {
 	// Fast trim right for common cases to avoid scanning the whole string
 	end := len(raw)
 	for end > 0 {
@@ -54,10 +127,18 @@ func NormalizeContainerLine(raw string) (level string, msg string, timestamp str
 			}
 
 			// Strip the timestamp from the line
-			line = line[loc[1]:]
+			if trim {
+				line = line[loc[1]:]
+			} else {
+				// Only the separator; indentation belongs to the message
+				line = line[loc[0]+len(trimmed)+1:]
+			}
 		}
 	}
 
+	if !trim {
+		return level, line, timestamp
+	}
 	// Return the message as-is (including any application-level timestamps)
 	return level, strings.TrimSpace(line), timestamp
 }
@@ -209,3 +209,51 @@ func TestNowRFC3339(t *testing.T) {
 	assert.False(t, parsed.After(after.Add(time.Millisecond)),
 		"timestamp should not be after the call")
 }
+
+func TestParseLogMode(t *testing.T) {
+	mode, err := ParseLogMode("")
+	require.NoError(t, err)
+	assert.Equal(t, LogModeNormalized, mode)
+
+	for _, m := range []string{LogModeNormalized, LogModeRaw, LogModeStructured} {
+		mode, err := ParseLogMode(m)
+		require.NoError(t, err)
+		assert.Equal(t, m, mode)
+	}
+
+	_, err = ParseLogMode("pretty")
+	require.ErrorIs(t, err, ErrInvalidLogMode)
+}
+
+func TestParseContainerLine_RawKeepsFormatting(t *testing.T) {
+	level, msg, ts := ParseContainerLine("[STDERR] 2024-01-01T10:00:00.5Z   \x1b[31mindented red\x1b[0m  \r\n", LogModeRaw)
+	assert.Equal(t, "stderr", level)
+	assert.Equal(t, "  \x1b[31mindented red\x1b[0m  ", msg)
+	assert.Equal(t, "2024-01-01T10:00:00.5Z", ts)
+
+	_, msg, _ = ParseContainerLine("  \x1b[32mok\x1b[0m  ", LogModeRaw)
+	assert.Equal(t, "  \x1b[32mok\x1b[0m  ", msg)
+
+	_, msg, _ = ParseContainerLine("  \x1b[32mok\x1b[0m  ", LogModeNormalized)
+	assert.Equal(t, "\x1b[32mok\x1b[0m", msg)
+}
+
+func TestParseProjectLine_Raw(t *testing.T) {
+	level, service, msg, _ := ParseProjectLine("web  |   \x1b[1mbold\x1b[0m", LogModeRaw)
+	assert.Equal(t, "stdout", level)
+	assert.Equal(t, "web", service)
+	assert.Equal(t, "  \x1b[1mbold\x1b[0m", msg)
+
+	_, service, msg, _ = ParseProjectLine("no service here", LogModeRaw)
+	assert.Empty(t, service)
+	assert.Equal(t, "no service here", msg)
+}
+
+func TestParseLogFields(t *testing.T) {
+	fields := ParseLogFields(` {"level":"info","msg":"started","port":8080} `)
+	assert.Equal(t, map[string]any{"level": "info", "msg": "started", "port": float64(8080)}, fields)
+
+	assert.Nil(t, ParseLogFields("plain text"))
+	assert.Nil(t, ParseLogFields(`{"broken":`))
+	assert.Nil(t, ParseLogFields(`["not", "an", "object"]`))
+}
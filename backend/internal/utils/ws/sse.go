@@ -0,0 +1,90 @@
+package ws
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// sseKeepAlive is how often an idle SSE stream gets a comment line, so that
+// proxies do not close it for inactivity.
+const sseKeepAlive = 25 * time.Second
+
+// ServeSSE attaches an HTTP response to the hub as a server-sent events
+// stream, for clients behind proxies that do not pass WebSockets through.
+// Each hub message is sent as one event with the same payload a WebSocket
+// client receives. Unlike ServeClient it blocks until the request context is
+// done or the hub drops the client, and onClose (if set) runs once after the
+// client has been removed.
+func ServeSSE(ctx context.Context, hub *Hub, w http.ResponseWriter, onClose func()) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		if onClose != nil {
+			onClose()
+		}
+		return
+	}
+
+	h := w.Header()
+	h.Set("Content-Type", "text/event-stream")
+	h.Set("Cache-Control", "no-cache")
+	h.Set("Connection", "keep-alive")
+	// Stop nginx from buffering the stream.
+	h.Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	c := &Client{send: make(chan []byte, clientSendBuffer), onClose: onClose}
+	select {
+	case hub.register <- c:
+	case <-ctx.Done():
+		if onClose != nil {
+			onClose()
+		}
+		return
+	}
+	defer c.safeRemove(hub)
+
+	keepAlive := time.NewTicker(sseKeepAlive)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg, ok := <-c.send:
+			if !ok {
+				return
+			}
+			if _, err := w.Write(formatSSEEventInternal(msg)); err != nil {
+				slog.Debug("sse write error", "err", err)
+				return
+			}
+			flusher.Flush()
+		case <-keepAlive.C:
+			if _, err := w.Write([]byte(": keep-alive\n\n")); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// formatSSEEventInternal frames msg as a single event. Messages spanning
+// several lines are split into several data fields, which the browser joins
+// back together with newlines.
+func formatSSEEventInternal(msg []byte) []byte {
+	msg = bytes.TrimSuffix(msg, []byte("\n"))
+	var buf bytes.Buffer
+	buf.Grow(len(msg) + 16)
+	for _, line := range bytes.Split(msg, []byte("\n")) {
+		buf.WriteString("data: ")
+		buf.Write(bytes.TrimSuffix(line, []byte("\r")))
+		buf.WriteByte('\n')
+	}
+	buf.WriteByte('\n')
+	return buf.Bytes()
+}
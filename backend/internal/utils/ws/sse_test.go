@@ -0,0 +1,55 @@
+package ws
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatSSEEvent(t *testing.T) {
+	assert.Equal(t, "data: {\"a\":1}\n\n", string(formatSSEEventInternal([]byte(`{"a":1}`))))
+	assert.Equal(t, "data: first\ndata: second\n\n", string(formatSSEEventInternal([]byte("first\r\nsecond\n"))))
+}
+
+func TestServeSSE(t *testing.T) {
+	h := NewHub(10)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go h.Run(ctx)
+
+	var closed atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeSSE(r.Context(), h, w, func() { closed.Add(1) })
+	}))
+	defer server.Close()
+
+	reqCtx, reqCancel := context.WithCancel(context.Background())
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodGet, server.URL, nil)
+	require.NoError(t, err)
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	require.Eventually(t, func() bool { return h.ClientCount() == 1 }, time.Second, 5*time.Millisecond)
+	h.Broadcast([]byte("line one\nline two"))
+
+	reader := bufio.NewReader(resp.Body)
+	var lines []string
+	for len(lines) < 3 {
+		line, err := reader.ReadString('\n')
+		require.NoError(t, err)
+		lines = append(lines, line)
+	}
+	assert.Equal(t, []string{"data: line one\n", "data: line two\n", "\n"}, lines)
+
+	reqCancel()
+	require.Eventually(t, func() bool { return h.ClientCount() == 0 && closed.Load() == 1 }, time.Second, 5*time.Millisecond)
+}
@@ -0,0 +1,365 @@
+// Package dockertest runs a disposable Docker-in-Docker daemon for
+// integration tests and seeds it with containers, volumes and projects.
+//
+// The daemon is started on the Docker host the test process can reach
+// (DOCKER_HOST or the default socket). Tests are skipped when no host daemon
+// is available, so packages using the harness still pass in plain "go test"
+// runs.
+package dockertest
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/image"
+	"github.com/docker/docker/api/types/volume"
+	"github.com/docker/docker/client"
+	"github.com/docker/go-connections/nat"
+)
+
+const (
+	// DefaultImage is the Docker-in-Docker image started when ImageEnv is not
+	// set.
+	DefaultImage = "docker:28-dind"
+	// ImageEnv overrides the Docker-in-Docker image.
+	ImageEnv = "ARCANE_TEST_DIND_IMAGE"
+	// HelperImage is the small image used to seed and read volumes.
+	HelperImage = "busybox:stable-musl"
+
+	// Label marks everything the harness creates on the host daemon.
+	Label = "com.getarcaneapp.dockertest"
+
+	daemonPort   = "2375/tcp"
+	startTimeout = 90 * time.Second
+)
+
+// Daemon is a running Docker-in-Docker daemon. It is removed together with
+// its storage when the test that started it finishes.
+type Daemon struct {
+	// Host is the daemon address, suitable for DOCKER_HOST.
+	Host string
+	// Client talks to the daemon.
+	Client *client.Client
+
+	host        *client.Client
+	containerID string
+}
+
+// Container describes a container to run on the daemon.
+type Container struct {
+	Name   string
+	Image  string
+	Cmd    []string
+	Env    []string
+	Labels map[string]string
+	// Volumes maps volume names to the paths they are mounted at.
+	Volumes map[string]string
+}
+
+// Start runs a new Docker-in-Docker daemon and waits until it answers. The
+// test is skipped when no host Docker daemon is reachable.
+func Start(t testing.TB) *Daemon {
+	t.Helper()
+	ctx := context.Background()
+
+	host, err := client.NewClientWithOpts(client.FromEnv, client.WithAPIVersionNegotiation())
+	if err != nil {
+		t.Skipf("dockertest: docker client unavailable: %v", err)
+	}
+	pingCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+	if _, err := host.Ping(pingCtx); err != nil {
+		_ = host.Close()
+		t.Skipf("dockertest: docker daemon unavailable: %v", err)
+	}
+
+	img := strings.TrimSpace(os.Getenv(ImageEnv))
+	if img == "" {
+		img = DefaultImage
+	}
+	if err := pullInternal(ctx, host, img); err != nil {
+		_ = host.Close()
+		t.Fatalf("dockertest: pull %s: %v", img, err)
+	}
+
+	resp, err := host.ContainerCreate(ctx,
+		&container.Config{
+			Image:        img,
+			Env:          []string{"DOCKER_TLS_CERTDIR="},
+			Cmd:          []string{"--host=tcp://0.0.0.0:2375", "--tls=false"},
+			ExposedPorts: nat.PortSet{daemonPort: struct{}{}},
+			Labels:       map[string]string{Label: t.Name()},
+		},
+		&container.HostConfig{
+			Privileged:   true,
+			PortBindings: nat.PortMap{daemonPort: {{HostIP: "127.0.0.1"}}},
+		},
+		nil, nil, "")
+	if err != nil {
+		_ = host.Close()
+		t.Fatalf("dockertest: create daemon: %v", err)
+	}
+
+	d := &Daemon{host: host, containerID: resp.ID}
+	t.Cleanup(d.remove)
+
+	if err := host.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		t.Fatalf("dockertest: start daemon: %v", err)
+	}
+
+	d.Host, err = d.addressInternal(ctx)
+	if err != nil {
+		t.Fatalf("dockertest: %v", err)
+	}
+	d.Client, err = client.NewClientWithOpts(client.WithHost(d.Host), client.WithAPIVersionNegotiation())
+	if err != nil {
+		t.Fatalf("dockertest: daemon client: %v", err)
+	}
+
+	if err := d.waitInternal(ctx); err != nil {
+		t.Fatalf("dockertest: %v", err)
+	}
+	return d
+}
+
+// Setenv points DOCKER_HOST at the daemon for the rest of the test, for code
+// that builds its own client from the environment, such as compose.
+func (d *Daemon) Setenv(t testing.TB) {
+	t.Helper()
+	t.Setenv("DOCKER_HOST", d.Host)
+}
+
+// Pull pulls an image into the daemon.
+func (d *Daemon) Pull(t testing.TB, ref string) {
+	t.Helper()
+	if err := pullInternal(context.Background(), d.Client, ref); err != nil {
+		t.Fatalf("dockertest: pull %s: %v", ref, err)
+	}
+}
+
+// Run creates and starts a container and returns its ID. The image is pulled
+// first.
+func (d *Daemon) Run(t testing.TB, spec Container) string {
+	t.Helper()
+	ctx := context.Background()
+	d.Pull(t, spec.Image)
+
+	var binds []string
+	for name, target := range spec.Volumes {
+		binds = append(binds, name+":"+target)
+	}
+	sort.Strings(binds)
+
+	resp, err := d.Client.ContainerCreate(ctx,
+		&container.Config{Image: spec.Image, Cmd: spec.Cmd, Env: spec.Env, Labels: spec.Labels},
+		&container.HostConfig{Binds: binds},
+		nil, nil, spec.Name)
+	if err != nil {
+		t.Fatalf("dockertest: create container %s: %v", spec.Name, err)
+	}
+	if err := d.Client.ContainerStart(ctx, resp.ID, container.StartOptions{}); err != nil {
+		t.Fatalf("dockertest: start container %s: %v", spec.Name, err)
+	}
+	return resp.ID
+}
+
+// CreateVolume creates a volume holding the given files, keyed by their path
+// relative to the volume root. Files written to an existing volume replace
+// the ones with the same path.
+func (d *Daemon) CreateVolume(t testing.TB, name string, files map[string]string) {
+	t.Helper()
+	ctx := context.Background()
+
+	if _, err := d.Client.VolumeCreate(ctx, volume.CreateOptions{Name: name}); err != nil {
+		t.Fatalf("dockertest: create volume %s: %v", name, err)
+	}
+	if len(files) == 0 {
+		return
+	}
+
+	archive, err := tarInternal(files)
+	if err != nil {
+		t.Fatalf("dockertest: archive files for %s: %v", name, err)
+	}
+	id := d.helperInternal(t, name)
+	if err := d.Client.CopyToContainer(ctx, id, "/data", archive, container.CopyToContainerOptions{}); err != nil {
+		t.Fatalf("dockertest: seed volume %s: %v", name, err)
+	}
+}
+
+// ReadVolumeFile returns the content of a file in a volume.
+func (d *Daemon) ReadVolumeFile(t testing.TB, name, path string) string {
+	t.Helper()
+	ctx := context.Background()
+
+	id := d.helperInternal(t, name)
+	rc, _, err := d.Client.CopyFromContainer(ctx, id, "/data/"+strings.TrimPrefix(path, "/"))
+	if err != nil {
+		t.Fatalf("dockertest: read %s from volume %s: %v", path, name, err)
+	}
+	defer func() { _ = rc.Close() }()
+
+	tr := tar.NewReader(rc)
+	if _, err := tr.Next(); err != nil {
+		t.Fatalf("dockertest: read %s from volume %s: %v", path, name, err)
+	}
+	content, err := io.ReadAll(tr)
+	if err != nil {
+		t.Fatalf("dockertest: read %s from volume %s: %v", path, name, err)
+	}
+	return string(content)
+}
+
+// WriteProject writes a compose project named name under root and returns
+// its directory.
+func WriteProject(t testing.TB, root, name, compose string) string {
+	t.Helper()
+	dir := filepath.Join(root, name)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatalf("dockertest: create project %s: %v", name, err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "compose.yaml"), []byte(compose), 0o600); err != nil {
+		t.Fatalf("dockertest: write project %s: %v", name, err)
+	}
+	return dir
+}
+
+// helperInternal creates a stopped container with the volume mounted at
+// /data, which is enough to copy files in and out of it.
+func (d *Daemon) helperInternal(t testing.TB, volumeName string) string {
+	t.Helper()
+	ctx := context.Background()
+	d.Pull(t, HelperImage)
+
+	resp, err := d.Client.ContainerCreate(ctx,
+		&container.Config{Image: HelperImage, Cmd: []string{"true"}},
+		&container.HostConfig{Binds: []string{volumeName + ":/data"}},
+		nil, nil, "")
+	if err != nil {
+		t.Fatalf("dockertest: create helper for %s: %v", volumeName, err)
+	}
+	t.Cleanup(func() {
+		_ = d.Client.ContainerRemove(context.Background(), resp.ID, container.RemoveOptions{Force: true})
+	})
+	return resp.ID
+}
+
+// addressInternal returns the address the daemon's published port is
+// reachable at from the test process.
+func (d *Daemon) addressInternal(ctx context.Context) (string, error) {
+	inspect, err := d.host.ContainerInspect(ctx, d.containerID)
+	if err != nil {
+		return "", fmt.Errorf("inspect daemon: %w", err)
+	}
+	bindings := inspect.NetworkSettings.Ports[daemonPort]
+	if len(bindings) == 0 {
+		return "", fmt.Errorf("daemon port %s is not published", daemonPort)
+	}
+
+	hostname := "127.0.0.1"
+	// A remote host daemon publishes the port on its own address.
+	if u, err := url.Parse(d.host.DaemonHost()); err == nil && u.Scheme == "tcp" && u.Hostname() != "" {
+		hostname = u.Hostname()
+	}
+	return "tcp://" + net.JoinHostPort(hostname, bindings[0].HostPort), nil
+}
+
+// waitInternal waits until the daemon answers pings.
+func (d *Daemon) waitInternal(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, startTimeout)
+	defer cancel()
+
+	var lastErr error
+	for {
+		pingCtx, pingCancel := context.WithTimeout(ctx, 2*time.Second)
+		_, lastErr = d.Client.Ping(pingCtx)
+		pingCancel()
+		if lastErr == nil {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("daemon did not start within %s: %w", startTimeout, lastErr)
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
+
+// remove stops the daemon and deletes its storage.
+func (d *Daemon) remove() {
+	ctx := context.Background()
+	if d.Client != nil {
+		_ = d.Client.Close()
+	}
+	_ = d.host.ContainerRemove(ctx, d.containerID, container.RemoveOptions{Force: true, RemoveVolumes: true})
+	_ = d.host.Close()
+}
+
+func pullInternal(ctx context.Context, cli *client.Client, ref string) error {
+	if _, err := cli.ImageInspect(ctx, ref); err == nil {
+		return nil
+	}
+	rc, err := cli.ImagePull(ctx, ref, image.PullOptions{})
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rc.Close() }()
+	_, err = io.Copy(io.Discard, rc)
+	return err
+}
+
+func tarInternal(files map[string]string) (io.Reader, error) {
+	entries := make(map[string]string, len(files))
+	dirs := map[string]bool{}
+	for name, content := range files {
+		name = strings.TrimPrefix(filepath.ToSlash(name), "/")
+		entries[name] = content
+		for dir := filepath.ToSlash(filepath.Dir(name)); dir != "."; dir = filepath.ToSlash(filepath.Dir(dir)) {
+			dirs[dir] = true
+		}
+	}
+
+	// Sorting puts every directory before the entries inside it.
+	names := make([]string, 0, len(entries)+len(dirs))
+	for name := range entries {
+		names = append(names, name)
+	}
+	for dir := range dirs {
+		names = append(names, dir+"/")
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, name := range names {
+		if strings.HasSuffix(name, "/") {
+			if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: name, Mode: 0o755}); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		content := entries[name]
+		if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0o644, Size: int64(len(content))}); err != nil {
+			return nil, err
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return &buf, nil
+}
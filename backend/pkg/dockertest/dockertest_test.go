@@ -0,0 +1,38 @@
+package dockertest
+
+import (
+	"archive/tar"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTarInternal(t *testing.T) {
+	archive, err := tarInternal(map[string]string{
+		"b/c/file.txt": "nested",
+		"/a.txt":       "top",
+	})
+	require.NoError(t, err)
+
+	tr := tar.NewReader(archive)
+	var names []string
+	contents := map[string]string{}
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		names = append(names, hdr.Name)
+		if hdr.Typeflag == tar.TypeReg {
+			b, err := io.ReadAll(tr)
+			require.NoError(t, err)
+			contents[hdr.Name] = string(b)
+		}
+	}
+
+	assert.Equal(t, []string{"a.txt", "b/", "b/c/", "b/c/file.txt"}, names)
+	assert.Equal(t, map[string]string{"a.txt": "top", "b/c/file.txt": "nested"}, contents)
+}
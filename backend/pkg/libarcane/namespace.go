@@ -0,0 +1,12 @@
+package libarcane
+
+import "strings"
+
+// NamespaceLabel assigns a container or volume to an Arcane namespace.
+const NamespaceLabel = "com.getarcaneapp.namespace"
+
+// NamespaceFromLabels returns the namespace a resource is labelled with, or ""
+// when it has none.
+func NamespaceFromLabels(labels map[string]string) string {
+	return strings.TrimSpace(labels[NamespaceLabel])
+}
@@ -3,7 +3,9 @@ package projects
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"io"
+	"maps"
 	"strings"
 	"time"
 
@@ -42,6 +44,65 @@ func ComposeRestart(ctx context.Context, proj *types.Project, services []string)
 	return c.svc.Restart(ctx, proj.Name, api.RestartOptions{Services: services})
 }
 
+// ComposeScaleService converges a single service to the given number of
+// replicas without touching the other services. With api.RecreateNever,
+// existing containers are kept as they are: scaling up adds replicas built
+// from the current configuration and scaling down removes the highest
+// numbered ones. With api.RecreateDiverged, outdated replicas are removed
+// first when scaling down and the rest are recreated.
+func ComposeScaleService(ctx context.Context, proj *types.Project, service string, replicas int, recreate string) error {
+	svc, ok := proj.Services[service]
+	if !ok {
+		return fmt.Errorf("service %q not found in project", service)
+	}
+
+	// Scale a copy so the caller's project keeps its configured replicas.
+	if svc.Deploy != nil {
+		deploy := *svc.Deploy
+		svc.Deploy = &deploy
+	}
+	svc.SetScale(replicas)
+	scaled := *proj
+	scaled.Services = maps.Clone(proj.Services)
+	scaled.Services[service] = svc
+
+	c, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	return c.svc.Up(ctx, &scaled, api.UpOptions{
+		Create: api.CreateOptions{
+			Services:             []string{service},
+			Recreate:             recreate,
+			RecreateDependencies: api.RecreateNever,
+		},
+		Start: api.StartOptions{
+			Project:  &scaled,
+			Services: []string{service},
+		},
+	})
+}
+
+// ComposeWatch watches the develop.watch paths of the given services (all
+// services when empty) and syncs, restarts or rebuilds them as files change,
+// until ctx is canceled. Watch messages and build output go to consumer.
+func ComposeWatch(ctx context.Context, proj *types.Project, services []string, consumer api.LogConsumer) error {
+	c, err := NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	defer c.Close()
+
+	return c.svc.Watch(ctx, proj, api.WatchOptions{
+		Build:    &api.BuildOptions{Services: services},
+		LogTo:    consumer,
+		Prune:    true,
+		Services: services,
+	})
+}
+
 func ComposeUp(ctx context.Context, proj *types.Project, services []string, removeOrphans bool) error {
 	c, err := NewClient(ctx)
 	if err != nil {
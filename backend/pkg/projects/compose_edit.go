@@ -0,0 +1,205 @@
+package projects
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/goccy/go-yaml/ast"
+	"github.com/goccy/go-yaml/parser"
+	"github.com/goccy/go-yaml/token"
+)
+
+// ComposeEditor applies programmatic edits to compose file content. It edits
+// the parsed syntax tree instead of decoding and re-encoding the file, so
+// comments, anchors, aliases, key order and x- extension blocks outside the
+// edited values are kept as the user wrote them.
+type ComposeEditor struct {
+	file    *ast.File
+	changed bool
+}
+
+// NewComposeEditor parses compose file content for editing.
+func NewComposeEditor(content []byte) (*ComposeEditor, error) {
+	file, err := parser.ParseBytes(content, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse compose file: %w", err)
+	}
+	return &ComposeEditor{file: file}, nil
+}
+
+// Bytes returns the edited compose file content.
+func (e *ComposeEditor) Bytes() []byte {
+	out := e.file.String()
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	return []byte(out)
+}
+
+// ServiceImage returns the image of service as written in the file, before
+// variable interpolation, or "" when the service does not set one itself.
+func (e *ComposeEditor) ServiceImage(service string) (string, error) {
+	svc, err := e.serviceInternal(service)
+	if err != nil {
+		return "", err
+	}
+	entry := mapValueInternal(svc, "image")
+	if entry == nil {
+		return "", nil
+	}
+	if str, ok := entry.Value.(*ast.StringNode); ok {
+		return str.Value, nil
+	}
+	return entry.Value.GetToken().Value, nil
+}
+
+// SetServiceImage sets the image of service, e.g. to pin it to a digest.
+func (e *ComposeEditor) SetServiceImage(service, image string) error {
+	svc, err := e.serviceInternal(service)
+	if err != nil {
+		return err
+	}
+	if err := e.setKeyInternal(svc, servicePathInternal(service).Build(), "image", image); err != nil {
+		return err
+	}
+	e.changed = true
+	return nil
+}
+
+// EditComposeFile applies edit to the compose file at path and writes the
+// result back with the file's existing permissions. The file is left
+// untouched when edit changes nothing.
+func EditComposeFile(path string, edit func(*ComposeEditor) error) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return fmt.Errorf("stat compose file: %w", err)
+	}
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read compose file: %w", err)
+	}
+
+	editor, err := NewComposeEditor(content)
+	if err != nil {
+		return err
+	}
+	if err := edit(editor); err != nil {
+		return err
+	}
+	if !editor.changed {
+		return nil
+	}
+
+	if err := os.WriteFile(path, editor.Bytes(), info.Mode().Perm()); err != nil {
+		return fmt.Errorf("write compose file: %w", err)
+	}
+	return nil
+}
+
+func servicePathInternal(service string) *yaml.PathBuilder {
+	return (&yaml.PathBuilder{}).Root().Child("services").Child(service)
+}
+
+// serviceInternal returns the mapping node of service.
+func (e *ComposeEditor) serviceInternal(service string) (ast.Node, error) {
+	var services *ast.MappingValueNode
+	for _, doc := range e.file.Docs {
+		if services = mapValueInternal(doc.Body, "services"); services != nil {
+			break
+		}
+	}
+	if services == nil {
+		return nil, fmt.Errorf("compose file has no services")
+	}
+
+	svc := mapValueInternal(services.Value, service)
+	if svc == nil {
+		return nil, fmt.Errorf("service %s not found in compose file", service)
+	}
+	if _, ok := svc.Value.(ast.MapNode); !ok {
+		return nil, fmt.Errorf("service %s is not a mapping", service)
+	}
+	return svc.Value, nil
+}
+
+// mapValueInternal returns the entry for key in the mapping node, or nil.
+func mapValueInternal(node ast.Node, key string) *ast.MappingValueNode {
+	var values []*ast.MappingValueNode
+	switch n := node.(type) {
+	case *ast.MappingNode:
+		values = n.Values
+	case *ast.MappingValueNode:
+		values = []*ast.MappingValueNode{n}
+	}
+	for _, v := range values {
+		name := v.Key.GetToken().Value
+		if str, ok := v.Key.(*ast.StringNode); ok {
+			name = str.Value
+		}
+		if name == key {
+			return v
+		}
+	}
+	return nil
+}
+
+// setKeyInternal replaces the value of key in mapping, keeping its comment
+// and quoting, or adds key to the mapping at path when it is missing.
+func (e *ComposeEditor) setKeyInternal(mapping ast.Node, path *yaml.Path, key, value string) error {
+	entry := mapValueInternal(mapping, key)
+	if entry == nil {
+		return e.mergeInternal(path, map[string]string{key: value})
+	}
+
+	node, err := scalarNodeInternal(entry.Value, value)
+	if err != nil {
+		return err
+	}
+	if comment := entry.Value.GetComment(); comment != nil {
+		if err := node.SetComment(comment); err != nil {
+			return fmt.Errorf("keep comment of %s: %w", key, err)
+		}
+	}
+	if err := entry.Replace(node); err != nil {
+		return fmt.Errorf("replace %s: %w", key, err)
+	}
+	return nil
+}
+
+// scalarNodeInternal builds a string node for value, quoted the same way as
+// current.
+func scalarNodeInternal(current ast.Node, value string) (ast.Node, error) {
+	var text string
+	switch current.GetToken().Type {
+	case token.DoubleQuoteType:
+		text = strconv.Quote(value)
+	case token.SingleQuoteType:
+		text = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+	default:
+		return yaml.ValueToNode(value)
+	}
+
+	file, err := parser.ParseBytes([]byte(text), 0)
+	if err != nil || len(file.Docs) == 0 || file.Docs[0].Body == nil {
+		return yaml.ValueToNode(value)
+	}
+	return file.Docs[0].Body, nil
+}
+
+func (e *ComposeEditor) mergeInternal(path *yaml.Path, value any) error {
+	node, err := yaml.ValueToNode(value)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", path, err)
+	}
+	if err := path.MergeFromNode(e.file, node); err != nil {
+		if errors.Is(err, yaml.ErrNotFoundNode) {
+			return fmt.Errorf("%s not found in compose file", path)
+		}
+		return fmt.Errorf("update %s: %w", path, err)
+	}
+	return nil
+}
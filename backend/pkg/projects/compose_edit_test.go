@@ -0,0 +1,93 @@
+package projects
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const editableComposeInternal = `# Media stack
+x-common: &common
+  restart: unless-stopped # always back up
+  logging:
+    driver: json-file
+
+services:
+  web:
+    <<: *common
+    image: nginx:1.25 # pinned by hand
+    labels:
+      traefik.enable: "true"
+  worker:
+    <<: *common
+    image: example/worker:2
+    labels:
+      - "com.example.role=worker"
+  db:
+    image: postgres:16
+`
+
+func TestComposeEditor(t *testing.T) {
+	editor, err := NewComposeEditor([]byte(editableComposeInternal))
+	require.NoError(t, err)
+
+	image, err := editor.ServiceImage("web")
+	require.NoError(t, err)
+	assert.Equal(t, "nginx:1.25", image)
+
+	require.NoError(t, editor.SetServiceImage("web", "nginx:1.25@sha256:abc"))
+	require.NoError(t, editor.SetServiceImage("worker", "example/worker:3"))
+	require.Error(t, editor.SetServiceImage("missing", "busybox"))
+
+	assert.Equal(t, `# Media stack
+x-common: &common
+  restart: unless-stopped # always back up
+  logging:
+    driver: json-file
+
+services:
+  web:
+    <<: *common
+    image: nginx:1.25@sha256:abc # pinned by hand
+    labels:
+      traefik.enable: "true"
+  worker:
+    <<: *common
+    image: example/worker:3
+    labels:
+      - "com.example.role=worker"
+  db:
+    image: postgres:16
+`, string(editor.Bytes()))
+}
+
+func TestEditComposeFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "compose.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(editableComposeInternal), 0o600))
+
+	require.NoError(t, EditComposeFile(path, func(e *ComposeEditor) error {
+		return e.SetServiceImage("db", "postgres@sha256:def")
+	}))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(content), "image: postgres@sha256:def\n")
+	assert.Contains(t, string(content), "x-common: &common\n")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	// Edits that change nothing leave the file as it is.
+	require.NoError(t, os.WriteFile(path, []byte("services:\n  db:   {image: postgres}\n"), 0o600))
+	require.NoError(t, EditComposeFile(path, func(e *ComposeEditor) error {
+		_, err := e.ServiceImage("db")
+		return err
+	}))
+	content, err = os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "services:\n  db:   {image: postgres}\n", string(content))
+}
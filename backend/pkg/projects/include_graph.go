@@ -0,0 +1,127 @@
+package projects
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/getarcaneapp/arcane/types/project"
+)
+
+// BuildIncludeGraph follows the include directives of a compose file and
+// its included files. Unlike the metadata parser, which silently stops at a
+// file it has already seen, it reports every cycle and every missing file.
+func BuildIncludeGraph(composeFilePath string) (project.IncludeGraph, error) {
+	rootPath, err := filepath.Abs(composeFilePath)
+	if err != nil {
+		return project.IncludeGraph{}, err
+	}
+
+	b := &includeGraphBuilder{
+		projectDir: filepath.Dir(rootPath),
+		state:      map[string]int{},
+		nodeIndex:  map[string]int{},
+		graph: project.IncludeGraph{
+			Nodes:   []project.IncludeNode{},
+			Edges:   []project.IncludeEdge{},
+			Cycles:  [][]string{},
+			Missing: []string{},
+		},
+	}
+	b.graph.Root = b.displayPath(rootPath)
+
+	if _, err := os.Stat(rootPath); err != nil {
+		return project.IncludeGraph{}, err
+	}
+	b.addNode(rootPath, true)
+	b.visit(rootPath)
+
+	b.graph.Valid = len(b.graph.Cycles) == 0 && len(b.graph.Missing) == 0 &&
+		!slices.ContainsFunc(b.graph.Nodes, func(n project.IncludeNode) bool { return n.Error != "" })
+	return b.graph, nil
+}
+
+const (
+	includeNodeUnvisited = iota
+	includeNodeInProgress
+	includeNodeDone
+)
+
+type includeGraphBuilder struct {
+	projectDir string
+	state      map[string]int
+	nodeIndex  map[string]int
+	stack      []string
+	graph      project.IncludeGraph
+}
+
+func (b *includeGraphBuilder) visit(path string) {
+	b.state[path] = includeNodeInProgress
+	b.stack = append(b.stack, path)
+	defer func() {
+		b.stack = b.stack[:len(b.stack)-1]
+		b.state[path] = includeNodeDone
+	}()
+
+	declared, err := parseIncludePaths(path)
+	if err != nil {
+		b.graph.Nodes[b.nodeIndex[path]].Error = err.Error()
+		return
+	}
+
+	dir := filepath.Dir(path)
+	for _, d := range declared {
+		if strings.TrimSpace(d) == "" {
+			continue
+		}
+		target := d
+		if !filepath.IsAbs(target) {
+			target = filepath.Join(dir, target)
+		}
+		target = filepath.Clean(target)
+
+		b.graph.Edges = append(b.graph.Edges, project.IncludeEdge{
+			From:     b.displayPath(path),
+			To:       b.displayPath(target),
+			Declared: d,
+		})
+
+		switch b.state[target] {
+		case includeNodeInProgress:
+			start := slices.Index(b.stack, target)
+			cycle := make([]string, 0, len(b.stack)-start+1)
+			for _, p := range b.stack[start:] {
+				cycle = append(cycle, b.displayPath(p))
+			}
+			b.graph.Cycles = append(b.graph.Cycles, append(cycle, b.displayPath(target)))
+		case includeNodeUnvisited:
+			if _, seen := b.nodeIndex[target]; seen {
+				// A missing file that is included more than once.
+				continue
+			}
+			if _, statErr := os.Stat(target); errors.Is(statErr, os.ErrNotExist) {
+				b.addNode(target, false)
+				b.graph.Missing = append(b.graph.Missing, b.displayPath(target))
+				continue
+			}
+			b.addNode(target, true)
+			b.visit(target)
+		}
+	}
+}
+
+func (b *includeGraphBuilder) addNode(path string, exists bool) {
+	b.nodeIndex[path] = len(b.graph.Nodes)
+	b.graph.Nodes = append(b.graph.Nodes, project.IncludeNode{Path: b.displayPath(path), Exists: exists})
+}
+
+// displayPath makes paths inside the project directory relative to it.
+func (b *includeGraphBuilder) displayPath(path string) string {
+	rel, err := filepath.Rel(b.projectDir, path)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return path
+	}
+	return rel
+}
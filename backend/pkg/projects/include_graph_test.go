@@ -0,0 +1,67 @@
+package projects
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/getarcaneapp/arcane/types/project"
+)
+
+func writeComposeFilesInternal(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o755))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	}
+}
+
+func TestBuildIncludeGraph(t *testing.T) {
+	dir := t.TempDir()
+	writeComposeFilesInternal(t, dir, map[string]string{
+		"compose.yaml":     "include:\n  - db/compose.yaml\n  - path: [web.yaml, missing.yaml]\nservices: {}\n",
+		"db/compose.yaml":  "include:\n  - ../web.yaml\nservices: {}\n",
+		"web.yaml":         "services: {}\n",
+		"cycle/a.yaml":     "include:\n  - b.yaml\n",
+		"cycle/b.yaml":     "include:\n  - a.yaml\n",
+		"broken/main.yaml": "include:\n  - bad.yaml\n",
+		"broken/bad.yaml":  "include: [\n",
+	})
+
+	graph, err := BuildIncludeGraph(filepath.Join(dir, "compose.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "compose.yaml", graph.Root)
+	assert.Equal(t, []project.IncludeNode{
+		{Path: "compose.yaml", Exists: true},
+		{Path: filepath.Join("db", "compose.yaml"), Exists: true},
+		{Path: "web.yaml", Exists: true},
+		{Path: "missing.yaml", Exists: false},
+	}, graph.Nodes)
+	assert.Len(t, graph.Edges, 4)
+	assert.Equal(t, "../web.yaml", graph.Edges[1].Declared)
+	assert.Empty(t, graph.Cycles, "a file included twice is not a cycle")
+	assert.Equal(t, []string{"missing.yaml"}, graph.Missing)
+	assert.False(t, graph.Valid)
+
+	graph, err = BuildIncludeGraph(filepath.Join(dir, "cycle", "a.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"a.yaml", "b.yaml", "a.yaml"}}, graph.Cycles)
+	assert.False(t, graph.Valid)
+
+	graph, err = BuildIncludeGraph(filepath.Join(dir, "broken", "main.yaml"))
+	require.NoError(t, err)
+	require.Len(t, graph.Nodes, 2)
+	assert.NotEmpty(t, graph.Nodes[1].Error)
+	assert.False(t, graph.Valid)
+
+	graph, err = BuildIncludeGraph(filepath.Join(dir, "web.yaml"))
+	require.NoError(t, err)
+	assert.True(t, graph.Valid)
+
+	_, err = BuildIncludeGraph(filepath.Join(dir, "nope.yaml"))
+	require.Error(t, err)
+}
@@ -0,0 +1,213 @@
+package projects
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/getarcaneapp/arcane/backend/pkg/utils"
+	"github.com/getarcaneapp/arcane/types/project"
+	"github.com/goccy/go-yaml"
+)
+
+const arcaneInputsKey = "inputs"
+
+var (
+	ErrInvalidInputDefinition = errors.New("invalid x-arcane input")
+	ErrInvalidInputValue      = errors.New("invalid input value")
+
+	inputNameRe     = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	envLineKeyRe    = regexp.MustCompile(`^\s*(?:export\s+)?([A-Za-z_][A-Za-z0-9_.-]*)\s*=`)
+	envPlainValueRe = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]*$`)
+)
+
+type rawComposeInput struct {
+	Name        string `yaml:"name"`
+	Type        string `yaml:"type"`
+	Label       string `yaml:"label"`
+	Description string `yaml:"description"`
+	Default     any    `yaml:"default"`
+	Required    bool   `yaml:"required"`
+	Options     []any  `yaml:"options"`
+	Min         *int   `yaml:"min"`
+	Max         *int   `yaml:"max"`
+}
+
+// ParseComposeInputs reads the typed inputs declared in the x-arcane.inputs
+// block of a compose file, in declaration order:
+//
+//	x-arcane:
+//	  inputs:
+//	    - name: DB_PASSWORD
+//	      type: secret
+//	      required: true
+//	    - name: HTTP_PORT
+//	      type: port
+//	      default: 8080
+func ParseComposeInputs(composeContent string) ([]project.Input, error) {
+	var doc map[string]any
+	if err := yaml.Unmarshal([]byte(composeContent), &doc); err != nil {
+		return nil, fmt.Errorf("parse compose file: %w", err)
+	}
+	block, ok := utils.AsStringMap(doc[arcaneBlockKey])
+	if !ok || block[arcaneInputsKey] == nil {
+		return nil, nil
+	}
+
+	raw, err := yaml.Marshal(block[arcaneInputsKey])
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalidInputDefinition, err)
+	}
+	var rawInputs []rawComposeInput
+	if err := yaml.Unmarshal(raw, &rawInputs); err != nil {
+		return nil, fmt.Errorf("%w: inputs must be a list: %w", ErrInvalidInputDefinition, err)
+	}
+
+	inputs := make([]project.Input, 0, len(rawInputs))
+	seen := map[string]struct{}{}
+	for _, r := range rawInputs {
+		in := project.Input{
+			Name:        strings.TrimSpace(r.Name),
+			Type:        strings.ToLower(strings.TrimSpace(r.Type)),
+			Label:       r.Label,
+			Description: r.Description,
+			Default:     utils.ToString(r.Default),
+			Required:    r.Required,
+			Options:     utils.Collect(r.Options, utils.ToString),
+			Min:         r.Min,
+			Max:         r.Max,
+		}
+		if in.Type == "" {
+			in.Type = project.InputTypeString
+		}
+		if err := validateInputDefinitionInternal(in); err != nil {
+			return nil, err
+		}
+		if _, dup := seen[in.Name]; dup {
+			return nil, fmt.Errorf("%w: %s is declared twice", ErrInvalidInputDefinition, in.Name)
+		}
+		seen[in.Name] = struct{}{}
+		inputs = append(inputs, in)
+	}
+	return inputs, nil
+}
+
+func validateInputDefinitionInternal(in project.Input) error {
+	if !inputNameRe.MatchString(in.Name) {
+		return fmt.Errorf("%w: %q is not a valid variable name", ErrInvalidInputDefinition, in.Name)
+	}
+	switch in.Type {
+	case project.InputTypeString, project.InputTypeSecret, project.InputTypeInt, project.InputTypePort:
+	case project.InputTypeEnum:
+		if len(in.Options) == 0 {
+			return fmt.Errorf("%w: enum input %s has no options", ErrInvalidInputDefinition, in.Name)
+		}
+	default:
+		return fmt.Errorf("%w: input %s has unknown type %q", ErrInvalidInputDefinition, in.Name, in.Type)
+	}
+	if in.Min != nil && in.Max != nil && *in.Min > *in.Max {
+		return fmt.Errorf("%w: input %s has min greater than max", ErrInvalidInputDefinition, in.Name)
+	}
+	if in.Default != "" {
+		if err := ValidateInputValue(in, in.Default); err != nil {
+			return fmt.Errorf("%w: default of %s: %w", ErrInvalidInputDefinition, in.Name, err)
+		}
+	}
+	return nil
+}
+
+// ValidateInputValue checks a value against the type of an input.
+func ValidateInputValue(in project.Input, value string) error {
+	if strings.ContainsAny(value, "\r\n") {
+		return fmt.Errorf("%w: %s must be a single line", ErrInvalidInputValue, in.Name)
+	}
+	if value == "" {
+		if in.Required {
+			return fmt.Errorf("%w: %s is required", ErrInvalidInputValue, in.Name)
+		}
+		return nil
+	}
+
+	switch in.Type {
+	case project.InputTypeInt:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return fmt.Errorf("%w: %s must be an integer", ErrInvalidInputValue, in.Name)
+		}
+		if in.Min != nil && n < *in.Min {
+			return fmt.Errorf("%w: %s must be at least %d", ErrInvalidInputValue, in.Name, *in.Min)
+		}
+		if in.Max != nil && n > *in.Max {
+			return fmt.Errorf("%w: %s must be at most %d", ErrInvalidInputValue, in.Name, *in.Max)
+		}
+	case project.InputTypePort:
+		if n, err := strconv.Atoi(value); err != nil || n < 1 || n > 65535 {
+			return fmt.Errorf("%w: %s must be a port between 1 and 65535", ErrInvalidInputValue, in.Name)
+		}
+	case project.InputTypeEnum:
+		if !slices.Contains(in.Options, value) {
+			return fmt.Errorf("%w: %s must be one of %s", ErrInvalidInputValue, in.Name, strings.Join(in.Options, ", "))
+		}
+	}
+	return nil
+}
+
+// InputFingerprint identifies the definition of an input. It changes when
+// the input is redeclared with another type, default or constraint.
+func InputFingerprint(in project.Input) string {
+	b, _ := json.Marshal(in)
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// SetEnvValues sets variables in the content of a .env file. Existing
+// assignments are replaced in place; new ones are appended. Comments and
+// other lines are kept.
+func SetEnvValues(content string, values map[string]string) string {
+	var b strings.Builder
+	written := map[string]bool{}
+	scanner := bufio.NewScanner(strings.NewReader(content))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if m := envLineKeyRe.FindStringSubmatch(line); m != nil {
+			if value, ok := values[m[1]]; ok {
+				if written[m[1]] {
+					continue
+				}
+				line = m[1] + "=" + quoteEnvValueInternal(value)
+				written[m[1]] = true
+			}
+		}
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		if !written[key] {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		b.WriteString(key + "=" + quoteEnvValueInternal(values[key]) + "\n")
+	}
+	return b.String()
+}
+
+// quoteEnvValueInternal double quotes values that would not survive
+// unquoted, escaping what compose would otherwise interpret.
+func quoteEnvValueInternal(value string) string {
+	if envPlainValueRe.MatchString(value) {
+		return value
+	}
+	escaped := strings.NewReplacer(`\`, `\\`, `"`, `\"`, `$`, `\$`).Replace(value)
+	return `"` + escaped + `"`
+}
@@ -0,0 +1,103 @@
+package projects
+
+import (
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/dotenv"
+	"github.com/getarcaneapp/arcane/types/project"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseComposeInputs(t *testing.T) {
+	inputs, err := ParseComposeInputs(`services:
+  db:
+    image: postgres
+x-arcane:
+  icon: https://example.com/icon.png
+  inputs:
+    - name: DB_PASSWORD
+      type: secret
+      required: true
+    - name: HTTP_PORT
+      type: port
+      default: 8080
+    - name: LOG_LEVEL
+      type: enum
+      options: [debug, info, warn]
+      default: info
+    - name: WORKERS
+      type: int
+      min: 1
+      max: 16
+    - name: SITE_NAME
+`)
+	require.NoError(t, err)
+	require.Len(t, inputs, 5)
+	assert.Equal(t, "DB_PASSWORD", inputs[0].Name)
+	assert.Equal(t, project.InputTypeSecret, inputs[0].Type)
+	assert.True(t, inputs[0].Required)
+	assert.Equal(t, "8080", inputs[1].Default)
+	assert.Equal(t, []string{"debug", "info", "warn"}, inputs[2].Options)
+	assert.Equal(t, 16, *inputs[3].Max)
+	assert.Equal(t, project.InputTypeString, inputs[4].Type)
+
+	none, err := ParseComposeInputs("services:\n  web:\n    image: nginx\n")
+	require.NoError(t, err)
+	assert.Empty(t, none)
+
+	for _, bad := range []string{
+		"x-arcane:\n  inputs:\n    - name: 1BAD\n",
+		"x-arcane:\n  inputs:\n    - name: A\n      type: float\n",
+		"x-arcane:\n  inputs:\n    - name: A\n      type: enum\n",
+		"x-arcane:\n  inputs:\n    - name: A\n      type: port\n      default: 70000\n",
+		"x-arcane:\n  inputs:\n    - name: A\n    - name: A\n",
+	} {
+		_, err := ParseComposeInputs(bad)
+		require.ErrorIs(t, err, ErrInvalidInputDefinition, bad)
+	}
+}
+
+func TestValidateInputValue(t *testing.T) {
+	minVal, maxVal := 1, 4
+	workers := project.Input{Name: "WORKERS", Type: project.InputTypeInt, Min: &minVal, Max: &maxVal}
+	require.NoError(t, ValidateInputValue(workers, "3"))
+	require.NoError(t, ValidateInputValue(workers, ""))
+	require.ErrorIs(t, ValidateInputValue(workers, "5"), ErrInvalidInputValue)
+	require.ErrorIs(t, ValidateInputValue(workers, "three"), ErrInvalidInputValue)
+
+	port := project.Input{Name: "PORT", Type: project.InputTypePort, Required: true}
+	require.NoError(t, ValidateInputValue(port, "443"))
+	require.ErrorIs(t, ValidateInputValue(port, "0"), ErrInvalidInputValue)
+	require.ErrorIs(t, ValidateInputValue(port, ""), ErrInvalidInputValue)
+
+	level := project.Input{Name: "LEVEL", Type: project.InputTypeEnum, Options: []string{"a", "b"}}
+	require.ErrorIs(t, ValidateInputValue(level, "c"), ErrInvalidInputValue)
+
+	secret := project.Input{Name: "TOKEN", Type: project.InputTypeSecret}
+	require.ErrorIs(t, ValidateInputValue(secret, "a\nb"), ErrInvalidInputValue)
+}
+
+func TestSetEnvValues(t *testing.T) {
+	content := "# database\nDB_USER=app\nexport DB_PASSWORD=old\nOTHER=1\n"
+	out := SetEnvValues(content, map[string]string{
+		"DB_PASSWORD": `p@ss "word" $HOME \x`,
+		"NEW_KEY":     "plain",
+	})
+	assert.Equal(t, "# database\nDB_USER=app\nDB_PASSWORD=\"p@ss \\\"word\\\" \\$HOME \\\\x\"\nOTHER=1\nNEW_KEY=plain\n", out)
+
+	parsed, err := dotenv.UnmarshalWithLookup(out, nil)
+	require.NoError(t, err)
+	assert.Equal(t, `p@ss "word" $HOME \x`, parsed["DB_PASSWORD"])
+	assert.Equal(t, "app", parsed["DB_USER"])
+	assert.Equal(t, "plain", parsed["NEW_KEY"])
+}
+
+func TestInputFingerprint(t *testing.T) {
+	in := project.Input{Name: "PORT", Type: project.InputTypePort, Default: "80"}
+	same := in
+	changed := in
+	changed.Default = "8080"
+	assert.Equal(t, InputFingerprint(in), InputFingerprint(same))
+	assert.NotEqual(t, InputFingerprint(in), InputFingerprint(changed))
+}
@@ -0,0 +1,90 @@
+package scheduler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/internal/config"
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+)
+
+const (
+	AgentHeartbeatJobName     = "agent-heartbeat"
+	agentHeartbeatSchedule    = "0 * * * * *"
+	agentHeartbeatPath        = "/api/environments/agent-heartbeat"
+	agentHeartbeatHTTPTimeout = 15 * time.Second
+)
+
+// AgentHeartbeatJob pushes a heartbeat with lightweight metrics from an agent
+// to its manager, so the environment list can show them without polling.
+type AgentHeartbeatJob struct {
+	systemService *services.SystemService
+	httpClient    *http.Client
+	cfg           *config.Config
+}
+
+func NewAgentHeartbeatJob(systemService *services.SystemService, httpClient *http.Client, cfg *config.Config) *AgentHeartbeatJob {
+	if httpClient == nil {
+		httpClient = &http.Client{Timeout: agentHeartbeatHTTPTimeout}
+	}
+	return &AgentHeartbeatJob{
+		systemService: systemService,
+		httpClient:    httpClient,
+		cfg:           cfg,
+	}
+}
+
+func (j *AgentHeartbeatJob) Name() string {
+	return AgentHeartbeatJobName
+}
+
+func (j *AgentHeartbeatJob) Schedule(ctx context.Context) string {
+	return agentHeartbeatSchedule
+}
+
+func (j *AgentHeartbeatJob) Run(ctx context.Context) {
+	if err := j.send(ctx); err != nil {
+		slog.WarnContext(ctx, "agent heartbeat failed", "jobName", AgentHeartbeatJobName, "error", err)
+	}
+}
+
+func (j *AgentHeartbeatJob) send(ctx context.Context) error {
+	managerURL := j.cfg.GetManagerBaseURL()
+	if managerURL == "" || j.cfg.AgentToken == "" {
+		return nil
+	}
+
+	body, err := json.Marshal(j.systemService.CollectHeartbeatMetrics(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to marshal heartbeat: %w", err)
+	}
+
+	reqCtx, cancel := context.WithTimeout(ctx, agentHeartbeatHTTPTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(reqCtx, http.MethodPost, strings.TrimRight(managerURL, "/")+agentHeartbeatPath, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-API-Key", j.cfg.AgentToken)
+
+	resp, err := j.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		respBody, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("manager returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(respBody)))
+	}
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strconv"
+	"time"
 
 	"github.com/getarcaneapp/arcane/backend/internal/services"
 )
@@ -12,12 +13,14 @@ import (
 type AutoUpdateJob struct {
 	updaterService  *services.UpdaterService
 	settingsService *services.SettingsService
+	rolloutService  *services.UpdateRolloutService
 }
 
-func NewAutoUpdateJob(updaterService *services.UpdaterService, settingsService *services.SettingsService) *AutoUpdateJob {
+func NewAutoUpdateJob(updaterService *services.UpdaterService, settingsService *services.SettingsService, rolloutService *services.UpdateRolloutService) *AutoUpdateJob {
 	return &AutoUpdateJob{
 		updaterService:  updaterService,
 		settingsService: settingsService,
+		rolloutService:  rolloutService,
 	}
 }
 
@@ -56,6 +59,24 @@ func (j *AutoUpdateJob) Run(ctx context.Context) {
 			"autoUpdate", enabled, "pollingEnabled", pollingEnabled)
 		return
 	}
+	if outsideMaintenanceWindow(ctx, j.settingsService, j.Name(), time.Now()) {
+		return
+	}
+
+	// With a canary environment the run updates the canary only; the
+	// update-rollout job takes the updates to the other environments.
+	if canary := j.rolloutService.CanaryEnvironment(ctx); canary != "" {
+		slog.InfoContext(ctx, "auto-update run started on canary environment", "environmentID", canary)
+		rollout, err := j.rolloutService.Start(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "auto-update rollout not started", "err", err)
+			return
+		}
+		if rollout != nil {
+			slog.InfoContext(ctx, "auto-update rollout started", "rolloutID", rollout.ID, "status", rollout.Status, "images", len(rollout.Images))
+		}
+		return
+	}
 
 	slog.InfoContext(ctx, "auto-update run started")
 
@@ -0,0 +1,83 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/robfig/cron/v3"
+)
+
+const BootVerificationJobName = "boot-verification"
+
+// BootVerificationJob periodically snapshots which containers are running and,
+// after a Docker daemon restart or host reboot, starts the ones that did not
+// come back. It is opt-in via the "bootVerificationEnabled" setting.
+type BootVerificationJob struct {
+	bootVerificationService *services.BootVerificationService
+	settingsService         *services.SettingsService
+	notificationService     *services.NotificationService
+}
+
+func NewBootVerificationJob(bootVerificationService *services.BootVerificationService, settingsService *services.SettingsService, notificationService *services.NotificationService) *BootVerificationJob {
+	return &BootVerificationJob{
+		bootVerificationService: bootVerificationService,
+		settingsService:         settingsService,
+		notificationService:     notificationService,
+	}
+}
+
+func (j *BootVerificationJob) Name() string {
+	return BootVerificationJobName
+}
+
+// Schedule returns the cron expression for the job. Defaults to every 5 minutes.
+func (j *BootVerificationJob) Schedule(ctx context.Context) string {
+	schedule := j.settingsService.GetStringSetting(ctx, "bootVerificationInterval", "0 */5 * * * *")
+	if schedule == "" {
+		return "0 */5 * * * *"
+	}
+
+	parser := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+	if _, err := parser.Parse(schedule); err != nil {
+		slog.WarnContext(ctx, "Invalid cron expression for boot-verification, using default", "invalid_schedule", schedule, "error", err)
+		return "0 */5 * * * *"
+	}
+
+	return schedule
+}
+
+func (j *BootVerificationJob) Run(ctx context.Context) {
+	if !j.settingsService.GetBoolSetting(ctx, "bootVerificationEnabled", false) {
+		slog.DebugContext(ctx, "boot verification disabled; skipping run")
+		return
+	}
+
+	report, err := j.bootVerificationService.VerifyAndSnapshot(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "boot verification run failed", "jobName", BootVerificationJobName, "error", err)
+	}
+	if report == nil {
+		return
+	}
+
+	slog.InfoContext(ctx, "boot verification completed",
+		"reason", report.Reason,
+		"expected", report.Expected,
+		"running", report.Running,
+		"started", len(report.Started),
+		"redeployed", len(report.Redeployed),
+		"failed", len(report.Failed),
+	)
+
+	if len(report.Failed) > 0 && j.notificationService != nil {
+		if err := j.notificationService.SendBootVerificationNotification(ctx, report); err != nil {
+			slog.WarnContext(ctx, "failed to send boot verification notification", "error", err)
+		}
+	}
+}
+
+func (j *BootVerificationJob) Reschedule(ctx context.Context) error {
+	slog.InfoContext(ctx, "rescheduling boot verification job in new scheduler; currently requires restart")
+	return nil
+}
@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+)
+
+const (
+	ContainerHealthcheckJobName     = "container-healthcheck"
+	containerHealthcheckJobSchedule = "*/5 * * * * *"
+)
+
+// ContainerHealthcheckJob runs the healthchecks defined in Arcane. It ticks
+// often and each healthcheck is probed once its own interval has elapsed.
+type ContainerHealthcheckJob struct {
+	healthcheckService *services.ContainerHealthcheckService
+}
+
+func NewContainerHealthcheckJob(healthcheckService *services.ContainerHealthcheckService) *ContainerHealthcheckJob {
+	return &ContainerHealthcheckJob{healthcheckService: healthcheckService}
+}
+
+func (j *ContainerHealthcheckJob) Name() string {
+	return ContainerHealthcheckJobName
+}
+
+func (j *ContainerHealthcheckJob) Schedule(ctx context.Context) string {
+	return containerHealthcheckJobSchedule
+}
+
+func (j *ContainerHealthcheckJob) Run(ctx context.Context) {
+	if probed := j.healthcheckService.RunDueChecks(ctx); probed > 0 {
+		slog.DebugContext(ctx, "Ran container healthchecks", "jobName", ContainerHealthcheckJobName, "count", probed)
+	}
+}
@@ -0,0 +1,42 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+)
+
+const (
+	ContainerTrashPurgeJobName     = "container-trash-purge"
+	containerTrashPurgeJobSchedule = "0 5 * * * *"
+)
+
+// ContainerTrashPurgeJob removes trashed containers once their retention
+// has passed.
+type ContainerTrashPurgeJob struct {
+	containerService *services.ContainerService
+}
+
+func NewContainerTrashPurgeJob(containerService *services.ContainerService) *ContainerTrashPurgeJob {
+	return &ContainerTrashPurgeJob{containerService: containerService}
+}
+
+func (j *ContainerTrashPurgeJob) Name() string {
+	return ContainerTrashPurgeJobName
+}
+
+func (j *ContainerTrashPurgeJob) Schedule(ctx context.Context) string {
+	return containerTrashPurgeJobSchedule
+}
+
+func (j *ContainerTrashPurgeJob) Run(ctx context.Context) {
+	purged, err := j.containerService.PurgeExpiredTrash(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "Failed to purge some trashed containers", "jobName", ContainerTrashPurgeJobName, "purged", purged, "error", err)
+		return
+	}
+	if purged > 0 {
+		slog.InfoContext(ctx, "Purged trashed containers", "jobName", ContainerTrashPurgeJobName, "purged", purged)
+	}
+}
@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+)
+
+const (
+	EndpointMonitorJobName     = "endpoint-monitor"
+	endpointMonitorJobSchedule = "*/5 * * * * *"
+)
+
+// EndpointMonitorJob runs the endpoint monitors. It ticks often and each
+// monitor is probed once its own interval has elapsed.
+type EndpointMonitorJob struct {
+	monitorService *services.EndpointMonitorService
+}
+
+func NewEndpointMonitorJob(monitorService *services.EndpointMonitorService) *EndpointMonitorJob {
+	return &EndpointMonitorJob{monitorService: monitorService}
+}
+
+func (j *EndpointMonitorJob) Name() string {
+	return EndpointMonitorJobName
+}
+
+func (j *EndpointMonitorJob) Schedule(ctx context.Context) string {
+	return endpointMonitorJobSchedule
+}
+
+func (j *EndpointMonitorJob) Run(ctx context.Context) {
+	if probed := j.monitorService.RunDueChecks(ctx); probed > 0 {
+		slog.DebugContext(ctx, "Ran endpoint monitors", "jobName", EndpointMonitorJobName, "count", probed)
+	}
+}
@@ -0,0 +1,45 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+)
+
+const (
+	EnvironmentPolicyJobName  = "environment-policy"
+	environmentPolicySchedule = "30 * * * * *"
+)
+
+// EnvironmentPolicyJob keeps the local environment policy in force. On an
+// agent it first syncs the policy and queued reports with the manager; a
+// failed sync leaves the cached policy in place, so the policy's prune
+// schedule keeps running while the manager is unreachable. A prune that falls
+// outside the maintenance windows waits for the next window.
+type EnvironmentPolicyJob struct {
+	policyService   *services.EnvironmentPolicyService
+	settingsService *services.SettingsService
+}
+
+func NewEnvironmentPolicyJob(policyService *services.EnvironmentPolicyService, settingsService *services.SettingsService) *EnvironmentPolicyJob {
+	return &EnvironmentPolicyJob{policyService: policyService, settingsService: settingsService}
+}
+
+func (j *EnvironmentPolicyJob) Name() string {
+	return EnvironmentPolicyJobName
+}
+
+func (j *EnvironmentPolicyJob) Schedule(ctx context.Context) string {
+	return environmentPolicySchedule
+}
+
+func (j *EnvironmentPolicyJob) Run(ctx context.Context) {
+	if err := j.policyService.Sync(ctx); err != nil {
+		slog.WarnContext(ctx, "environment policy sync failed; enforcing cached policy", "jobName", EnvironmentPolicyJobName, "error", err)
+	}
+	if j.policyService.PruneDue(ctx) && !outsideMaintenanceWindow(ctx, j.settingsService, j.Name(), time.Now()) {
+		j.policyService.RunScheduledPrune(ctx)
+	}
+}
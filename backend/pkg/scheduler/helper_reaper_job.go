@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+)
+
+const (
+	HelperReaperJobName     = "helper-container-reaper"
+	helperReaperJobSchedule = "0 * * * * *"
+)
+
+// HelperReaperJob removes read-only volume helper containers that have been
+// idle for longer than the helperIdleTtl setting.
+type HelperReaperJob struct {
+	volumeService *services.VolumeService
+}
+
+func NewHelperReaperJob(volumeService *services.VolumeService) *HelperReaperJob {
+	return &HelperReaperJob{volumeService: volumeService}
+}
+
+func (j *HelperReaperJob) Name() string {
+	return HelperReaperJobName
+}
+
+func (j *HelperReaperJob) Schedule(ctx context.Context) string {
+	return helperReaperJobSchedule
+}
+
+func (j *HelperReaperJob) Run(ctx context.Context) {
+	if removed := j.volumeService.ReapIdleHelpers(ctx); removed > 0 {
+		slog.InfoContext(ctx, "Removed idle volume helper containers", "jobName", HelperReaperJobName, "count", removed)
+	}
+}
@@ -10,16 +10,18 @@ import (
 )
 
 type ImagePollingJob struct {
-	imageUpdateService *services.ImageUpdateService
-	settingsService    *services.SettingsService
-	environmentService *services.EnvironmentService
+	imageUpdateService     *services.ImageUpdateService
+	containerDigestService *services.ContainerDigestService
+	settingsService        *services.SettingsService
+	environmentService     *services.EnvironmentService
 }
 
-func NewImagePollingJob(imageUpdateService *services.ImageUpdateService, settingsService *services.SettingsService, environmentService *services.EnvironmentService) *ImagePollingJob {
+func NewImagePollingJob(imageUpdateService *services.ImageUpdateService, containerDigestService *services.ContainerDigestService, settingsService *services.SettingsService, environmentService *services.EnvironmentService) *ImagePollingJob {
 	return &ImagePollingJob{
-		imageUpdateService: imageUpdateService,
-		settingsService:    settingsService,
-		environmentService: environmentService,
+		imageUpdateService:     imageUpdateService,
+		containerDigestService: containerDigestService,
+		settingsService:        settingsService,
+		environmentService:     environmentService,
 	}
 }
 
@@ -85,6 +87,15 @@ func (j *ImagePollingJob) Run(ctx context.Context) {
 	}
 
 	slog.InfoContext(ctx, "image scan run completed", "checked", total, "updates", updates, "errors", errors)
+
+	if j.containerDigestService != nil && j.settingsService.GetBoolSetting(ctx, "tagDriftDetectionEnabled", false) {
+		drift, err := j.containerDigestService.CheckTagDrift(ctx)
+		if err != nil {
+			slog.ErrorContext(ctx, "tag drift check failed", "err", err)
+			return
+		}
+		slog.InfoContext(ctx, "tag drift check completed", "checked", drift.Checked, "drifted", drift.Drifted, "errors", drift.Failed)
+	}
 }
 
 func (j *ImagePollingJob) Reschedule(ctx context.Context) error {
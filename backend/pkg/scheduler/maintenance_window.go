@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/backend/internal/utils/maintenance"
+)
+
+// outsideMaintenanceWindow reports whether a scheduled run of jobName has to
+// be skipped because the environment is outside its maintenance windows.
+// Runs started by a user and the maintenanceWindowOverride setting bypass the
+// windows. Invalid windows are rejected when saved, so one that still fails to
+// parse does not hold jobs back.
+func outsideMaintenanceWindow(ctx context.Context, settingsService *services.SettingsService, jobName string, now time.Time) bool {
+	if services.IsManualJobRun(ctx) || settingsService.GetBoolSetting(ctx, "maintenanceWindowOverride", false) {
+		return false
+	}
+
+	windows, err := maintenance.Parse(settingsService.GetStringSetting(ctx, "maintenanceWindows", ""))
+	if err != nil {
+		slog.WarnContext(ctx, "ignoring invalid maintenance windows", "jobName", jobName, "error", err)
+		return false
+	}
+	if windows.Open(now) {
+		return false
+	}
+
+	attrs := []any{"jobName", jobName}
+	if next := windows.NextOpen(now); !next.IsZero() {
+		attrs = append(attrs, "nextWindow", next)
+	}
+	slog.InfoContext(ctx, "outside maintenance window; skipping run", attrs...)
+	return true
+}
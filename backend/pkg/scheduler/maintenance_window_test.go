@@ -0,0 +1,29 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+)
+
+func TestOutsideMaintenanceWindow(t *testing.T) {
+	ctx := context.Background()
+	settingsService := setupAnalyticsSettingsService(t)
+	monday := time.Date(2026, 3, 2, 12, 0, 0, 0, time.Local)
+
+	assert.False(t, outsideMaintenanceWindow(ctx, settingsService, "auto-update", monday))
+
+	require.NoError(t, settingsService.SetStringSetting(ctx, "maintenanceWindows", "sat,sun 01:00-05:00"))
+	assert.True(t, outsideMaintenanceWindow(ctx, settingsService, "auto-update", monday))
+	assert.False(t, outsideMaintenanceWindow(ctx, settingsService, "auto-update", monday.AddDate(0, 0, 5).Add(-9*time.Hour)))
+
+	assert.False(t, outsideMaintenanceWindow(services.WithManualJobRun(ctx), settingsService, "auto-update", monday))
+
+	require.NoError(t, settingsService.SetBoolSetting(ctx, "maintenanceWindowOverride", true))
+	assert.False(t, outsideMaintenanceWindow(ctx, settingsService, "auto-update", monday))
+}
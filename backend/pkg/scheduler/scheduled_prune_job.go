@@ -5,25 +5,35 @@ import (
 	"fmt"
 	"log/slog"
 	"strconv"
+	"time"
 
+	"github.com/getarcaneapp/arcane/backend/internal/models"
 	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/getarcaneapp/arcane/types/environment"
 	"github.com/getarcaneapp/arcane/types/system"
 	"github.com/robfig/cron/v3"
 )
 
 const ScheduledPruneJobName = "scheduled-prune"
 
+// scheduledPruneSystemUser is recorded as the actor of scheduled prunes.
+var scheduledPruneSystemUser = models.User{
+	Username: "System",
+}
+
 type ScheduledPruneJob struct {
 	systemService       *services.SystemService
 	settingsService     *services.SettingsService
 	notificationService *services.NotificationService
+	featureFlagService  *services.FeatureFlagService
 }
 
-func NewScheduledPruneJob(systemService *services.SystemService, settingsService *services.SettingsService, notificationService *services.NotificationService) *ScheduledPruneJob {
+func NewScheduledPruneJob(systemService *services.SystemService, settingsService *services.SettingsService, notificationService *services.NotificationService, featureFlagService *services.FeatureFlagService) *ScheduledPruneJob {
 	return &ScheduledPruneJob{
 		systemService:       systemService,
 		settingsService:     settingsService,
 		notificationService: notificationService,
+		featureFlagService:  featureFlagService,
 	}
 }
 
@@ -67,6 +77,13 @@ func (j *ScheduledPruneJob) Run(ctx context.Context) {
 		slog.DebugContext(ctx, "scheduled prune disabled; skipping run")
 		return
 	}
+	if j.featureFlagService != nil && !j.featureFlagService.IsEnabled(ctx, "0", environment.FeaturePrune) {
+		slog.DebugContext(ctx, "prune feature disabled for local environment; skipping run")
+		return
+	}
+	if outsideMaintenanceWindow(ctx, j.settingsService, j.Name(), time.Now()) {
+		return
+	}
 
 	pruneMode := j.settingsService.GetStringSetting(ctx, "dockerPruneMode", "dangling")
 	danglingOnly := pruneMode != "all"
@@ -94,7 +111,7 @@ func (j *ScheduledPruneJob) Run(ctx context.Context) {
 		"dangling_only", req.Dangling,
 	)
 
-	result, err := j.systemService.PruneAll(ctx, req)
+	result, err := j.systemService.PruneAll(ctx, req, models.PruneTriggerScheduled, scheduledPruneSystemUser)
 	if err != nil {
 		slog.ErrorContext(ctx, "scheduled prune run failed", "error", err)
 		return
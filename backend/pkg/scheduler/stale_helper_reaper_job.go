@@ -0,0 +1,39 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+)
+
+const (
+	StaleHelperReaperJobName     = "stale-helper-reaper"
+	staleHelperReaperJobSchedule = "0 15 * * * *"
+)
+
+// StaleHelperReaperJob removes helper containers and restore leftovers that
+// outlived the operation that created them, e.g. after a crash.
+type StaleHelperReaperJob struct {
+	volumeService *services.VolumeService
+}
+
+func NewStaleHelperReaperJob(volumeService *services.VolumeService) *StaleHelperReaperJob {
+	return &StaleHelperReaperJob{volumeService: volumeService}
+}
+
+func (j *StaleHelperReaperJob) Name() string {
+	return StaleHelperReaperJobName
+}
+
+func (j *StaleHelperReaperJob) Schedule(ctx context.Context) string {
+	return staleHelperReaperJobSchedule
+}
+
+func (j *StaleHelperReaperJob) Run(ctx context.Context) {
+	report := j.volumeService.ReapStaleHelpers(ctx)
+	if !report.Empty() {
+		slog.InfoContext(ctx, "Removed stale helpers", "jobName", StaleHelperReaperJobName,
+			"containers", len(report.Containers), "restoreDirs", len(report.RestoreDirs), "failed", report.Failed)
+	}
+}
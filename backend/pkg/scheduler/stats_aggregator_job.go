@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+)
+
+const (
+	StatsAggregatorJobName     = "stats-aggregator"
+	statsAggregatorJobSchedule = "*/10 * * * * *"
+)
+
+// StatsAggregatorJob refreshes the aggregated container stats every ten
+// seconds while they are being requested.
+type StatsAggregatorJob struct {
+	statsService *services.StatsAggregatorService
+}
+
+func NewStatsAggregatorJob(statsService *services.StatsAggregatorService) *StatsAggregatorJob {
+	return &StatsAggregatorJob{statsService: statsService}
+}
+
+func (j *StatsAggregatorJob) Name() string {
+	return StatsAggregatorJobName
+}
+
+func (j *StatsAggregatorJob) Schedule(ctx context.Context) string {
+	return statsAggregatorJobSchedule
+}
+
+func (j *StatsAggregatorJob) Run(ctx context.Context) {
+	if _, err := j.statsService.SampleIfRequested(ctx); err != nil {
+		slog.WarnContext(ctx, "Failed to sample container stats", "jobName", StatsAggregatorJobName, "error", err)
+	}
+}
@@ -0,0 +1,50 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+)
+
+const (
+	UpdateRolloutJobName  = "update-rollout"
+	updateRolloutSchedule = "15 * * * * *"
+)
+
+// UpdateRolloutJob watches the canary of a staged auto-update rollout and,
+// once it has stayed healthy for the soak period, rolls the updates out to
+// the other environments. A rollout that is due outside the maintenance
+// windows waits for the next window; the canary is watched meanwhile.
+type UpdateRolloutJob struct {
+	rolloutService  *services.UpdateRolloutService
+	settingsService *services.SettingsService
+}
+
+func NewUpdateRolloutJob(rolloutService *services.UpdateRolloutService, settingsService *services.SettingsService) *UpdateRolloutJob {
+	return &UpdateRolloutJob{rolloutService: rolloutService, settingsService: settingsService}
+}
+
+func (j *UpdateRolloutJob) Name() string {
+	return UpdateRolloutJobName
+}
+
+func (j *UpdateRolloutJob) Schedule(ctx context.Context) string {
+	return updateRolloutSchedule
+}
+
+func (j *UpdateRolloutJob) Run(ctx context.Context) {
+	now := time.Now()
+	due, err := j.rolloutService.CheckCanary(ctx, now)
+	if err != nil {
+		slog.ErrorContext(ctx, "update rollout check failed", "jobName", UpdateRolloutJobName, "error", err)
+		return
+	}
+	if !due || outsideMaintenanceWindow(ctx, j.settingsService, j.Name(), now) {
+		return
+	}
+	if err := j.rolloutService.Promote(ctx); err != nil {
+		slog.ErrorContext(ctx, "update rollout failed", "jobName", UpdateRolloutJobName, "error", err)
+	}
+}
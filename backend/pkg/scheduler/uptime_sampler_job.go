@@ -0,0 +1,37 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+)
+
+const (
+	UptimeSamplerJobName     = "uptime-sampler"
+	uptimeSamplerJobSchedule = "30 * * * * *"
+)
+
+// UptimeSamplerJob records container and project state changes once a
+// minute for uptime tracking.
+type UptimeSamplerJob struct {
+	uptimeService *services.UptimeService
+}
+
+func NewUptimeSamplerJob(uptimeService *services.UptimeService) *UptimeSamplerJob {
+	return &UptimeSamplerJob{uptimeService: uptimeService}
+}
+
+func (j *UptimeSamplerJob) Name() string {
+	return UptimeSamplerJobName
+}
+
+func (j *UptimeSamplerJob) Schedule(ctx context.Context) string {
+	return uptimeSamplerJobSchedule
+}
+
+func (j *UptimeSamplerJob) Run(ctx context.Context) {
+	if err := j.uptimeService.RecordSample(ctx); err != nil {
+		slog.WarnContext(ctx, "Failed to record uptime sample", "jobName", UptimeSamplerJobName, "error", err)
+	}
+}
@@ -0,0 +1,69 @@
+package scheduler
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/getarcaneapp/arcane/backend/internal/services"
+	"github.com/robfig/cron/v3"
+)
+
+const VulnerabilityDigestJobName = "vulnerability-digest"
+
+// VulnerabilityDigestJob periodically sends a digest of the environment's
+// vulnerability scans through the notification providers. It is opt-in via the
+// "vulnerabilityDigestEnabled" setting.
+type VulnerabilityDigestJob struct {
+	vulnerabilityService *services.VulnerabilityService
+	settingsService      *services.SettingsService
+}
+
+func NewVulnerabilityDigestJob(vulnerabilityService *services.VulnerabilityService, settingsService *services.SettingsService) *VulnerabilityDigestJob {
+	return &VulnerabilityDigestJob{
+		vulnerabilityService: vulnerabilityService,
+		settingsService:      settingsService,
+	}
+}
+
+func (j *VulnerabilityDigestJob) Name() string {
+	return VulnerabilityDigestJobName
+}
+
+// Schedule returns the cron expression for the job. Defaults to Mondays at 08:00.
+func (j *VulnerabilityDigestJob) Schedule(ctx context.Context) string {
+	schedule := j.settingsService.GetStringSetting(ctx, "vulnerabilityDigestInterval", "0 0 8 * * 1")
+	if schedule == "" {
+		return "0 0 8 * * 1"
+	}
+
+	parser := cron.NewParser(cron.Second | cron.Minute | cron.Hour | cron.Dom | cron.Month | cron.Dow)
+	if _, err := parser.Parse(schedule); err != nil {
+		slog.WarnContext(ctx, "Invalid cron expression for vulnerability-digest, using default", "invalid_schedule", schedule, "error", err)
+		return "0 0 8 * * 1"
+	}
+
+	return schedule
+}
+
+func (j *VulnerabilityDigestJob) Run(ctx context.Context) {
+	if !j.settingsService.GetBoolSetting(ctx, "vulnerabilityDigestEnabled", false) {
+		slog.DebugContext(ctx, "vulnerability digest disabled; skipping run")
+		return
+	}
+
+	sent, err := j.vulnerabilityService.SendVulnerabilityDigest(ctx)
+	if err != nil {
+		slog.ErrorContext(ctx, "vulnerability digest run failed", "jobName", VulnerabilityDigestJobName, "error", err)
+		return
+	}
+	if !sent {
+		slog.DebugContext(ctx, "vulnerability digest below thresholds; nothing sent")
+		return
+	}
+	slog.InfoContext(ctx, "vulnerability digest sent", "jobName", VulnerabilityDigestJobName)
+}
+
+func (j *VulnerabilityDigestJob) Reschedule(ctx context.Context) error {
+	slog.InfoContext(ctx, "rescheduling vulnerability digest job in new scheduler; currently requires restart")
+	return nil
+}
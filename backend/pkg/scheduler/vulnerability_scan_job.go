@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"log/slog"
 	"strconv"
+	"time"
 
 	"github.com/getarcaneapp/arcane/backend/internal/models"
 	"github.com/getarcaneapp/arcane/backend/internal/services"
@@ -70,11 +71,24 @@ func (j *VulnerabilityScanJob) Schedule(ctx context.Context) string {
 }
 
 func (j *VulnerabilityScanJob) Run(ctx context.Context) {
+	// Expired ignore rules already stop applying; purging them records the
+	// expiry in the ignore audit, so it runs even when scanning is disabled.
+	purged, err := j.vulnerabilityService.PurgeExpiredIgnores(ctx)
+	if err != nil {
+		slog.WarnContext(ctx, "expired vulnerability ignore cleanup failed", "error", err)
+	}
+	if purged > 0 {
+		slog.InfoContext(ctx, "removed expired vulnerability ignore rules", "purged", purged)
+	}
+
 	enabled := j.settingsService.GetBoolSetting(ctx, "vulnerabilityScanEnabled", false)
 	if !enabled {
 		slog.DebugContext(ctx, "scheduled vulnerability scan disabled; skipping run")
 		return
 	}
+	if outsideMaintenanceWindow(ctx, j.settingsService, j.Name(), time.Now()) {
+		return
+	}
 
 	slog.InfoContext(ctx, "scheduled vulnerability scan started")
 
@@ -0,0 +1,7 @@
+-- Add runtime_state_snapshots table for post-boot verification of running containers
+CREATE TABLE IF NOT EXISTS runtime_state_snapshots (
+    id TEXT PRIMARY KEY,
+    host_boot_time TIMESTAMP,
+    containers TEXT NOT NULL DEFAULT '[]',
+    taken_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+-- Drop approval_requests table
+DROP TABLE IF EXISTS approval_requests;
+ALTER TABLE environments DROP COLUMN require_approval;
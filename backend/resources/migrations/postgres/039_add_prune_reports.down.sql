@@ -0,0 +1,2 @@
+-- Drop prune_reports table
+DROP TABLE IF EXISTS prune_reports;
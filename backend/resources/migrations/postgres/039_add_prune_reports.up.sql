@@ -0,0 +1,17 @@
+-- Add prune_reports table recording the resources removed by each prune
+CREATE TABLE IF NOT EXISTS prune_reports (
+    id TEXT PRIMARY KEY,
+    environment_id TEXT NOT NULL DEFAULT '0',
+    trigger_type TEXT NOT NULL,
+    user_id TEXT,
+    username TEXT NOT NULL DEFAULT '',
+    success BOOLEAN NOT NULL DEFAULT TRUE,
+    space_reclaimed BIGINT NOT NULL DEFAULT 0,
+    item_count INTEGER NOT NULL DEFAULT 0,
+    items TEXT NOT NULL DEFAULT '[]',
+    errors TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_prune_reports_created_at ON prune_reports(created_at);
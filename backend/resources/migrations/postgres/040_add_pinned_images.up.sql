@@ -0,0 +1,10 @@
+-- Add pinned_images table protecting images from prune and auto-update
+CREATE TABLE IF NOT EXISTS pinned_images (
+    id TEXT PRIMARY KEY,
+    digest TEXT NOT NULL UNIQUE,
+    image_ref TEXT NOT NULL DEFAULT '',
+    reason TEXT,
+    pinned_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
@@ -0,0 +1,2 @@
+-- Drop container_overrides table
+DROP TABLE IF EXISTS container_overrides;
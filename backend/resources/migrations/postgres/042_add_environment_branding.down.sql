@@ -0,0 +1,4 @@
+ALTER TABLE environments DROP COLUMN production;
+ALTER TABLE environments DROP COLUMN description;
+ALTER TABLE environments DROP COLUMN icon;
+ALTER TABLE environments DROP COLUMN color;
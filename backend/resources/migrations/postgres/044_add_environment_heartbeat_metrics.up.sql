@@ -0,0 +1,2 @@
+-- Metrics pushed by agents with their heartbeat, stored as JSON
+ALTER TABLE environments ADD COLUMN heartbeat_metrics TEXT;
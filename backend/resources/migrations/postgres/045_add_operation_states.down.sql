@@ -0,0 +1,2 @@
+-- Drop operation_states table
+DROP TABLE IF EXISTS operation_states;
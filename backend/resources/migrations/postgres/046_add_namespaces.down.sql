@@ -0,0 +1,4 @@
+ALTER TABLE projects DROP COLUMN namespace;
+DROP INDEX IF EXISTS idx_namespace_members_user_id;
+DROP TABLE IF EXISTS namespace_members;
+DROP TABLE IF EXISTS namespaces;
@@ -0,0 +1,21 @@
+-- Add namespaces that own projects, volumes and containers, with their members and quotas
+CREATE TABLE IF NOT EXISTS namespaces (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL UNIQUE,
+    description TEXT,
+    max_containers INTEGER NOT NULL DEFAULT 0,
+    max_volume_gb INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE TABLE IF NOT EXISTS namespace_members (
+    namespace_id TEXT NOT NULL REFERENCES namespaces(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (namespace_id, user_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_namespace_members_user_id ON namespace_members(user_id);
+
+ALTER TABLE projects ADD COLUMN namespace TEXT;
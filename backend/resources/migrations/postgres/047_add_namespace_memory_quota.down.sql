@@ -0,0 +1 @@
+ALTER TABLE namespaces DROP COLUMN IF EXISTS max_memory_mb;
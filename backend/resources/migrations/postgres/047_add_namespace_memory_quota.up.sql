@@ -0,0 +1,2 @@
+-- Add a total memory reservation quota to namespaces
+ALTER TABLE namespaces ADD COLUMN max_memory_mb INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,2 @@
+-- Drop container_healthchecks table
+DROP TABLE IF EXISTS container_healthchecks;
@@ -0,0 +1,12 @@
+-- Add uptime_transitions table recording when containers and projects go up or down
+CREATE TABLE IF NOT EXISTS uptime_transitions (
+    id TEXT PRIMARY KEY,
+    resource_type TEXT NOT NULL,
+    resource_key TEXT NOT NULL,
+    up BOOLEAN NOT NULL,
+    at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_uptime_transitions_resource ON uptime_transitions (resource_type, resource_key, at);
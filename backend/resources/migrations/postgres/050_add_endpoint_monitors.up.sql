@@ -0,0 +1,40 @@
+-- Add endpoint_monitors and their result history for external HTTP/TCP checks
+CREATE TABLE IF NOT EXISTS endpoint_monitors (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    target TEXT NOT NULL,
+    expected_status INTEGER NOT NULL DEFAULT 0,
+    interval_seconds INTEGER NOT NULL DEFAULT 60,
+    timeout_seconds INTEGER NOT NULL DEFAULT 10,
+    retries INTEGER NOT NULL DEFAULT 2,
+    project_id TEXT,
+    container_key TEXT,
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    notify BOOLEAN NOT NULL DEFAULT true,
+    status TEXT NOT NULL DEFAULT 'pending',
+    failing_streak INTEGER NOT NULL DEFAULT 0,
+    last_status_code INTEGER NOT NULL DEFAULT 0,
+    last_latency_ms BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    last_checked_at TIMESTAMP,
+    updated_by TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_endpoint_monitors_project_id ON endpoint_monitors (project_id);
+
+CREATE TABLE IF NOT EXISTS endpoint_monitor_results (
+    id TEXT PRIMARY KEY,
+    monitor_id TEXT NOT NULL REFERENCES endpoint_monitors(id) ON DELETE CASCADE,
+    up BOOLEAN NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0,
+    latency_ms BIGINT NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    checked_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_endpoint_monitor_results_monitor_checked ON endpoint_monitor_results (monitor_id, checked_at);
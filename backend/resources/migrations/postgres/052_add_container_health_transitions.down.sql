@@ -0,0 +1,3 @@
+-- Drop container health transitions table
+DROP INDEX IF EXISTS idx_container_health_transitions_name_at;
+DROP TABLE IF EXISTS container_health_transitions;
@@ -0,0 +1,12 @@
+-- Add container_health_transitions to keep a history of container health states
+CREATE TABLE IF NOT EXISTS container_health_transitions (
+    id TEXT PRIMARY KEY,
+    container_id TEXT NOT NULL,
+    container_name TEXT NOT NULL,
+    status TEXT NOT NULL,
+    at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_container_health_transitions_name_at ON container_health_transitions (container_name, at);
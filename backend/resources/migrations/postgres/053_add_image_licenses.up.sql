@@ -0,0 +1,16 @@
+-- Add image_licenses to keep the package licenses found by image scans
+CREATE TABLE IF NOT EXISTS image_licenses (
+    id TEXT PRIMARY KEY,
+    image_id TEXT NOT NULL,
+    image_name TEXT NOT NULL,
+    repository TEXT NOT NULL,
+    name TEXT NOT NULL,
+    category TEXT,
+    pkg_name TEXT,
+    file_path TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_image_licenses_image_id ON image_licenses (image_id);
+CREATE INDEX IF NOT EXISTS idx_image_licenses_repository ON image_licenses (repository);
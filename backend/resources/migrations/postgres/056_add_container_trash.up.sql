@@ -0,0 +1,18 @@
+-- Add container_trash to hold deleted containers until their grace period ends
+CREATE TABLE IF NOT EXISTS container_trash (
+    id TEXT PRIMARY KEY,
+    container_id TEXT NOT NULL,
+    original_name TEXT NOT NULL,
+    trash_name TEXT NOT NULL,
+    restart_policy TEXT NOT NULL DEFAULT '',
+    restart_retries INTEGER NOT NULL DEFAULT 0,
+    was_running BOOLEAN NOT NULL DEFAULT false,
+    remove_volumes BOOLEAN NOT NULL DEFAULT false,
+    deleted_by TEXT NOT NULL DEFAULT '',
+    purge_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_container_trash_container_id ON container_trash (container_id);
+CREATE INDEX IF NOT EXISTS idx_container_trash_purge_at ON container_trash (purge_at);
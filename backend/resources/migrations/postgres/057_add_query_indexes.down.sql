@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_events_environment_id_timestamp;
+DROP INDEX IF EXISTS idx_events_type_timestamp;
+DROP INDEX IF EXISTS idx_volume_backups_volume_name_created_at;
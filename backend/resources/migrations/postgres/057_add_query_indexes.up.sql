@@ -0,0 +1,7 @@
+-- Composite indexes for the most frequent filtered and sorted queries.
+-- events.timestamp, events.type, volume_backups.volume_name and
+-- environments.status already have single-column indexes (002, 027, 032);
+-- these cover the filter plus the sort order so no separate sort is needed.
+CREATE INDEX IF NOT EXISTS idx_events_environment_id_timestamp ON events(environment_id, timestamp);
+CREATE INDEX IF NOT EXISTS idx_events_type_timestamp ON events(type, timestamp);
+CREATE INDEX IF NOT EXISTS idx_volume_backups_volume_name_created_at ON volume_backups(volume_name, created_at);
@@ -0,0 +1,5 @@
+-- Drop container digests table
+DROP INDEX IF EXISTS idx_container_digests_pinned;
+DROP INDEX IF EXISTS idx_container_digests_drifted;
+DROP INDEX IF EXISTS idx_container_digests_container_name;
+DROP TABLE IF EXISTS container_digests;
@@ -0,0 +1,23 @@
+-- Add container_digests to record the digest each container was deployed from
+CREATE TABLE IF NOT EXISTS container_digests (
+    id TEXT PRIMARY KEY,
+    container_name TEXT NOT NULL,
+    container_id TEXT NOT NULL DEFAULT '',
+    image_ref TEXT NOT NULL DEFAULT '',
+    image_id TEXT NOT NULL DEFAULT '',
+    digest TEXT NOT NULL DEFAULT '',
+    recorded_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    remote_digest TEXT,
+    drifted BOOLEAN NOT NULL DEFAULT false,
+    drift_checked_at TIMESTAMP,
+    last_error TEXT,
+    pinned BOOLEAN NOT NULL DEFAULT false,
+    pin_reason TEXT,
+    pinned_by TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_container_digests_container_name ON container_digests (container_name);
+CREATE INDEX IF NOT EXISTS idx_container_digests_drifted ON container_digests (drifted);
+CREATE INDEX IF NOT EXISTS idx_container_digests_pinned ON container_digests (pinned);
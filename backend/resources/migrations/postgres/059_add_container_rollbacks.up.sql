@@ -0,0 +1,20 @@
+-- Add container_rollbacks to keep what auto-update replaced so it can be rolled back
+CREATE TABLE IF NOT EXISTS container_rollbacks (
+    id TEXT PRIMARY KEY,
+    container_name TEXT NOT NULL,
+    container_id TEXT NOT NULL DEFAULT '',
+    previous_container_id TEXT NOT NULL DEFAULT '',
+    previous_image_ref TEXT NOT NULL DEFAULT '',
+    previous_image_id TEXT NOT NULL DEFAULT '',
+    new_image_ref TEXT NOT NULL DEFAULT '',
+    snapshot TEXT NOT NULL DEFAULT '',
+    applied_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    observe_until TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    rolled_back_at TIMESTAMP,
+    rolled_back_by TEXT,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_container_rollbacks_container_name ON container_rollbacks (container_name);
+CREATE INDEX IF NOT EXISTS idx_container_rollbacks_observe_until ON container_rollbacks (observe_until);
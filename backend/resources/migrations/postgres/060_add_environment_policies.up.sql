@@ -0,0 +1,32 @@
+-- Add environment_policies for manager-issued policies and the agent's local copy,
+-- and environment_policy_reports for reports an agent has not yet delivered
+CREATE TABLE IF NOT EXISTS environment_policies (
+    id TEXT PRIMARY KEY,
+    environment_id TEXT NOT NULL,
+    revision BIGINT NOT NULL DEFAULT 0,
+    allowed_images TEXT,
+    deny_privileged BOOLEAN NOT NULL DEFAULT false,
+    deny_host_network BOOLEAN NOT NULL DEFAULT false,
+    prune_schedule TEXT NOT NULL DEFAULT '',
+    prune_mode TEXT NOT NULL DEFAULT '',
+    updated_by TEXT NOT NULL DEFAULT '',
+    synced_at TIMESTAMP,
+    last_prune_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_environment_policies_environment_id ON environment_policies (environment_id);
+
+CREATE TABLE IF NOT EXISTS environment_policy_reports (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    revision BIGINT NOT NULL DEFAULT 0,
+    message TEXT NOT NULL DEFAULT '',
+    details TEXT,
+    occurred_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_environment_policy_reports_occurred_at ON environment_policy_reports (occurred_at);
@@ -0,0 +1,16 @@
+-- Add update_rollouts for staged auto-updates that start on a canary environment
+CREATE TABLE IF NOT EXISTS update_rollouts (
+    id TEXT PRIMARY KEY,
+    status TEXT NOT NULL,
+    canary_environment_id TEXT NOT NULL,
+    images TEXT,
+    canary_containers TEXT,
+    soak_until TIMESTAMP,
+    targets TEXT,
+    error TEXT NOT NULL DEFAULT '',
+    completed_at TIMESTAMP,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_update_rollouts_status ON update_rollouts (status);
@@ -0,0 +1,14 @@
+-- Add vulnerability_digests recording each scheduled vulnerability digest that was sent
+CREATE TABLE IF NOT EXISTS vulnerability_digests (
+    id TEXT PRIMARY KEY,
+    critical_keys TEXT,
+    critical INTEGER NOT NULL DEFAULT 0,
+    high INTEGER NOT NULL DEFAULT 0,
+    medium INTEGER NOT NULL DEFAULT 0,
+    low INTEGER NOT NULL DEFAULT 0,
+    fixable INTEGER NOT NULL DEFAULT 0,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_vulnerability_digests_created_at ON vulnerability_digests (created_at);
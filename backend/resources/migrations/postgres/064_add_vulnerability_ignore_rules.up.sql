@@ -0,0 +1,26 @@
+-- Wildcard scopes and expiry for vulnerability ignore rules
+ALTER TABLE vulnerability_ignores ADD COLUMN scope TEXT NOT NULL DEFAULT 'exact';
+ALTER TABLE vulnerability_ignores ADD COLUMN expires_at TIMESTAMP;
+
+CREATE INDEX IF NOT EXISTS idx_vulnerability_ignores_expires_at ON vulnerability_ignores(expires_at);
+
+-- Audit trail of created, removed and expired ignore rules
+CREATE TABLE IF NOT EXISTS vulnerability_ignore_audits (
+    id TEXT PRIMARY KEY,
+    ignore_id TEXT NOT NULL,
+    environment_id TEXT NOT NULL,
+    action TEXT NOT NULL,
+    scope TEXT NOT NULL DEFAULT 'exact',
+    image_id TEXT NOT NULL DEFAULT '',
+    vulnerability_id TEXT NOT NULL DEFAULT '',
+    pkg_name TEXT NOT NULL DEFAULT '',
+    installed_version TEXT NOT NULL DEFAULT '',
+    reason TEXT,
+    expires_at TIMESTAMP,
+    user_id TEXT NOT NULL DEFAULT '',
+    username TEXT NOT NULL DEFAULT '',
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_vulnerability_ignore_audits_env ON vulnerability_ignore_audits(environment_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_vulnerability_ignore_audits_ignore ON vulnerability_ignore_audits(ignore_id);
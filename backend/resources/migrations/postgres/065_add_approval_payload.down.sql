@@ -0,0 +1,3 @@
+ALTER TABLE approval_requests DROP COLUMN payload_hash;
+ALTER TABLE approval_requests DROP COLUMN body;
+ALTER TABLE approval_requests DROP COLUMN query;
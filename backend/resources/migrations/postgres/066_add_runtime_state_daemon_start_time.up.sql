@@ -0,0 +1,2 @@
+-- Record the Docker daemon start time so daemon-only restarts can be detected
+ALTER TABLE runtime_state_snapshots ADD COLUMN daemon_start_time TIMESTAMP;
@@ -0,0 +1,2 @@
+-- Drop runtime_state_snapshots table
+DROP TABLE IF EXISTS runtime_state_snapshots;
@@ -0,0 +1,7 @@
+-- Add runtime_state_snapshots table for post-boot verification of running containers
+CREATE TABLE IF NOT EXISTS runtime_state_snapshots (
+    id TEXT PRIMARY KEY,
+    host_boot_time DATETIME,
+    containers TEXT NOT NULL DEFAULT '[]',
+    taken_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
@@ -0,0 +1,3 @@
+ALTER TABLE volume_backups DROP COLUMN snapshot_path;
+ALTER TABLE volume_backups DROP COLUMN snapshot_ref;
+ALTER TABLE volume_backups DROP COLUMN driver;
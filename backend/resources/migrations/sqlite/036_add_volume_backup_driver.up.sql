@@ -0,0 +1,4 @@
+-- Track how each volume backup was taken so snapshot backups can be restored and removed
+ALTER TABLE volume_backups ADD COLUMN driver TEXT NOT NULL DEFAULT 'tar';
+ALTER TABLE volume_backups ADD COLUMN snapshot_ref TEXT;
+ALTER TABLE volume_backups ADD COLUMN snapshot_path TEXT;
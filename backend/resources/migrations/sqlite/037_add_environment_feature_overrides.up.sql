@@ -0,0 +1,2 @@
+-- Per-environment feature flag overrides (JSON object of feature name to enabled)
+ALTER TABLE environments ADD COLUMN feature_overrides TEXT;
@@ -0,0 +1,25 @@
+-- Add approval_requests table for the two-person approval workflow
+CREATE TABLE IF NOT EXISTS approval_requests (
+    id TEXT PRIMARY KEY,
+    action TEXT NOT NULL,
+    environment_id TEXT NOT NULL,
+    method TEXT NOT NULL,
+    path TEXT NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending',
+    requested_by TEXT NOT NULL,
+    requested_by_name TEXT NOT NULL,
+    decided_by TEXT,
+    decided_by_name TEXT,
+    decided_at DATETIME,
+    reason TEXT,
+    expires_at DATETIME NOT NULL,
+    consumed_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_approval_requests_status ON approval_requests(status);
+CREATE INDEX IF NOT EXISTS idx_approval_requests_env ON approval_requests(environment_id);
+
+-- Environments that require approval for destructive actions
+ALTER TABLE environments ADD COLUMN require_approval BOOLEAN NOT NULL DEFAULT FALSE;
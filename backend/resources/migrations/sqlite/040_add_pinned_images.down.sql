@@ -0,0 +1,2 @@
+-- Drop pinned_images table
+DROP TABLE IF EXISTS pinned_images;
@@ -0,0 +1,10 @@
+-- Add container_overrides table for per-container display names and icons
+CREATE TABLE IF NOT EXISTS container_overrides (
+    id TEXT PRIMARY KEY,
+    container_key TEXT NOT NULL UNIQUE,
+    display_name TEXT,
+    icon_url TEXT,
+    updated_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
@@ -0,0 +1,5 @@
+-- Per-environment display metadata and production flag
+ALTER TABLE environments ADD COLUMN color TEXT NOT NULL DEFAULT '';
+ALTER TABLE environments ADD COLUMN icon TEXT NOT NULL DEFAULT '';
+ALTER TABLE environments ADD COLUMN description TEXT NOT NULL DEFAULT '';
+ALTER TABLE environments ADD COLUMN production BOOLEAN NOT NULL DEFAULT FALSE;
@@ -0,0 +1,2 @@
+-- Free-form environment tags stored as a JSON array
+ALTER TABLE environments ADD COLUMN tags TEXT;
@@ -0,0 +1,9 @@
+-- Add operation_states table recording in-flight operations so ones cut off by a restart can be reported or resumed
+CREATE TABLE IF NOT EXISTS operation_states (
+    id TEXT PRIMARY KEY,
+    kind TEXT NOT NULL,
+    resource TEXT NOT NULL,
+    metadata TEXT,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
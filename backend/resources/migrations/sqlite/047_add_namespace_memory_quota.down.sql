@@ -0,0 +1 @@
+ALTER TABLE namespaces DROP COLUMN max_memory_mb;
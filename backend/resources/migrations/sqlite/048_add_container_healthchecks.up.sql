@@ -0,0 +1,20 @@
+-- Add container_healthchecks table for probes run by Arcane instead of Docker
+CREATE TABLE IF NOT EXISTS container_healthchecks (
+    id TEXT PRIMARY KEY,
+    container_key TEXT NOT NULL UNIQUE,
+    type TEXT NOT NULL,
+    port INTEGER NOT NULL DEFAULT 0,
+    path TEXT NOT NULL DEFAULT '',
+    command TEXT,
+    interval_seconds INTEGER NOT NULL DEFAULT 30,
+    timeout_seconds INTEGER NOT NULL DEFAULT 5,
+    retries INTEGER NOT NULL DEFAULT 3,
+    auto_restart BOOLEAN NOT NULL DEFAULT false,
+    status TEXT NOT NULL DEFAULT 'starting',
+    failing_streak INTEGER NOT NULL DEFAULT 0,
+    last_output TEXT NOT NULL DEFAULT '',
+    last_checked_at DATETIME,
+    updated_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
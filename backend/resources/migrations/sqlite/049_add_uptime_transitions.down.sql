@@ -0,0 +1,3 @@
+-- Drop uptime_transitions table
+DROP INDEX IF EXISTS idx_uptime_transitions_resource;
+DROP TABLE IF EXISTS uptime_transitions;
@@ -0,0 +1,5 @@
+-- Drop endpoint monitor tables
+DROP INDEX IF EXISTS idx_endpoint_monitor_results_monitor_checked;
+DROP TABLE IF EXISTS endpoint_monitor_results;
+DROP INDEX IF EXISTS idx_endpoint_monitors_project_id;
+DROP TABLE IF EXISTS endpoint_monitors;
@@ -0,0 +1,3 @@
+-- Drop webhook sources table
+DROP INDEX IF EXISTS idx_webhook_sources_token_hash;
+DROP TABLE IF EXISTS webhook_sources;
@@ -0,0 +1,19 @@
+-- Add webhook_sources for inbound webhooks from CI systems and registries
+CREATE TABLE IF NOT EXISTS webhook_sources (
+    id TEXT PRIMARY KEY,
+    name TEXT NOT NULL,
+    type TEXT NOT NULL,
+    token_hash TEXT NOT NULL,
+    token_prefix TEXT NOT NULL,
+    action TEXT NOT NULL DEFAULT 'none',
+    project_id TEXT,
+    image_filter TEXT NOT NULL DEFAULT '',
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    last_event TEXT NOT NULL DEFAULT '',
+    last_received_at DATETIME,
+    updated_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_webhook_sources_token_hash ON webhook_sources (token_hash);
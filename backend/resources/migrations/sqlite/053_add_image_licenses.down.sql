@@ -0,0 +1,4 @@
+-- Drop image licenses table
+DROP INDEX IF EXISTS idx_image_licenses_repository;
+DROP INDEX IF EXISTS idx_image_licenses_image_id;
+DROP TABLE IF EXISTS image_licenses;
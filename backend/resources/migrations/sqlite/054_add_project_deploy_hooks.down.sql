@@ -0,0 +1,3 @@
+-- Drop project deploy hooks table
+DROP INDEX IF EXISTS idx_project_deploy_hooks_project;
+DROP TABLE IF EXISTS project_deploy_hooks;
@@ -0,0 +1,20 @@
+-- Add project_deploy_hooks for commands and webhooks run around project deploys
+CREATE TABLE IF NOT EXISTS project_deploy_hooks (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    phase TEXT NOT NULL,
+    position INTEGER NOT NULL DEFAULT 0,
+    type TEXT NOT NULL,
+    service TEXT NOT NULL DEFAULT '',
+    image TEXT NOT NULL DEFAULT '',
+    command TEXT NOT NULL DEFAULT '',
+    url TEXT NOT NULL DEFAULT '',
+    timeout_seconds INTEGER NOT NULL DEFAULT 60,
+    on_failure TEXT NOT NULL DEFAULT 'abort',
+    enabled BOOLEAN NOT NULL DEFAULT true,
+    updated_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_project_deploy_hooks_project ON project_deploy_hooks (project_id, phase, position);
@@ -0,0 +1,3 @@
+-- Drop project inputs table
+DROP INDEX IF EXISTS idx_project_inputs_project_name;
+DROP TABLE IF EXISTS project_inputs;
@@ -0,0 +1,12 @@
+-- Add project_inputs to remember which x-arcane input definitions have values
+CREATE TABLE IF NOT EXISTS project_inputs (
+    id TEXT PRIMARY KEY,
+    project_id TEXT NOT NULL,
+    name TEXT NOT NULL,
+    fingerprint TEXT NOT NULL DEFAULT '',
+    updated_by TEXT NOT NULL DEFAULT '',
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_project_inputs_project_name ON project_inputs (project_id, name);
@@ -0,0 +1,4 @@
+-- Drop container trash table
+DROP INDEX IF EXISTS idx_container_trash_purge_at;
+DROP INDEX IF EXISTS idx_container_trash_container_id;
+DROP TABLE IF EXISTS container_trash;
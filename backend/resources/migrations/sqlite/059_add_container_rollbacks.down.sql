@@ -0,0 +1,4 @@
+-- Drop container rollbacks table
+DROP INDEX IF EXISTS idx_container_rollbacks_observe_until;
+DROP INDEX IF EXISTS idx_container_rollbacks_container_name;
+DROP TABLE IF EXISTS container_rollbacks;
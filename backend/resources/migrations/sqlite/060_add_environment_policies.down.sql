@@ -0,0 +1,5 @@
+-- Drop environment policy tables
+DROP INDEX IF EXISTS idx_environment_policy_reports_occurred_at;
+DROP TABLE IF EXISTS environment_policy_reports;
+DROP INDEX IF EXISTS idx_environment_policies_environment_id;
+DROP TABLE IF EXISTS environment_policies;
@@ -0,0 +1,3 @@
+-- Drop update rollouts table
+DROP INDEX IF EXISTS idx_update_rollouts_status;
+DROP TABLE IF EXISTS update_rollouts;
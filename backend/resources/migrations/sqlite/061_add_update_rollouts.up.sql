@@ -0,0 +1,16 @@
+-- Add update_rollouts for staged auto-updates that start on a canary environment
+CREATE TABLE IF NOT EXISTS update_rollouts (
+    id TEXT PRIMARY KEY,
+    status TEXT NOT NULL,
+    canary_environment_id TEXT NOT NULL,
+    images TEXT,
+    canary_containers TEXT,
+    soak_until DATETIME,
+    targets TEXT,
+    error TEXT NOT NULL DEFAULT '',
+    completed_at DATETIME,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at DATETIME
+);
+
+CREATE INDEX IF NOT EXISTS idx_update_rollouts_status ON update_rollouts (status);
@@ -0,0 +1,3 @@
+-- Drop vulnerability digests table
+DROP INDEX IF EXISTS idx_vulnerability_digests_created_at;
+DROP TABLE IF EXISTS vulnerability_digests;
@@ -0,0 +1,3 @@
+ALTER TABLE environments DROP COLUMN wake_relay_id;
+ALTER TABLE environments DROP COLUMN wake_broadcast;
+ALTER TABLE environments DROP COLUMN mac_address;
@@ -0,0 +1,4 @@
+-- Wake-on-LAN target and relay for environments on physical hosts
+ALTER TABLE environments ADD COLUMN mac_address TEXT NOT NULL DEFAULT '';
+ALTER TABLE environments ADD COLUMN wake_broadcast TEXT NOT NULL DEFAULT '';
+ALTER TABLE environments ADD COLUMN wake_relay_id TEXT;
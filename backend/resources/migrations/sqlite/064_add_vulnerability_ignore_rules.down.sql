@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS vulnerability_ignore_audits;
+DROP INDEX IF EXISTS idx_vulnerability_ignores_expires_at;
+ALTER TABLE vulnerability_ignores DROP COLUMN expires_at;
+ALTER TABLE vulnerability_ignores DROP COLUMN scope;
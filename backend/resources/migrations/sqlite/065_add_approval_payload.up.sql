@@ -0,0 +1,4 @@
+-- Bind approvals to the exact query and body that was approved
+ALTER TABLE approval_requests ADD COLUMN query TEXT NOT NULL DEFAULT '';
+ALTER TABLE approval_requests ADD COLUMN body TEXT NOT NULL DEFAULT '';
+ALTER TABLE approval_requests ADD COLUMN payload_hash TEXT NOT NULL DEFAULT '';
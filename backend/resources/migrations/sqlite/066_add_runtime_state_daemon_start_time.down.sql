@@ -0,0 +1 @@
+ALTER TABLE runtime_state_snapshots DROP COLUMN daemon_start_time;
@@ -0,0 +1,20 @@
+import BaseAPIService from './api-service';
+import type { ApprovalRequest, ApprovalStatus } from '$lib/types/approval.type';
+
+export default class ApprovalAPIService extends BaseAPIService {
+	async list(status?: ApprovalStatus): Promise<ApprovalRequest[]> {
+		return this.handleResponse(this.api.get('/approvals', { params: status ? { status } : undefined })) as Promise<
+			ApprovalRequest[]
+		>;
+	}
+
+	async approve(id: string, reason?: string): Promise<ApprovalRequest> {
+		return this.handleResponse(this.api.post(`/approvals/${id}/approve`, { reason })) as Promise<ApprovalRequest>;
+	}
+
+	async reject(id: string, reason?: string): Promise<ApprovalRequest> {
+		return this.handleResponse(this.api.post(`/approvals/${id}/reject`, { reason })) as Promise<ApprovalRequest>;
+	}
+}
+
+export const approvalService = new ApprovalAPIService();
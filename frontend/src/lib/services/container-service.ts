@@ -4,7 +4,31 @@ import type {
 	ContainerStatusCounts,
 	ContainerSummaryDto,
 	ContainerStats,
-	ContainerCreateRequest
+	ContainerCreateRequest,
+	ContainerOverride,
+	ContainerOverrideRequest,
+	ContainerHealthcheck,
+	ContainerProcessList,
+	ContainerHealthcheckRequest,
+	ContainerHealthHistory,
+	ContainerRecreateRequest,
+	ContainerResourcesUpdate,
+	ContainerResourcesUpdateResult,
+	ContainerSnapshotInfo,
+	ContainerDiff,
+	ContainerDigestStatus,
+	ContainerDriftCheckResult,
+	ContainerRollbackStatus,
+	ContainerCheckpointSupport,
+	ContainerCheckpoint,
+	ContainerCheckpointCreateRequest,
+	ContainerCommitRequest,
+	ContainerCommitResult,
+	ContainerLogDownloadOptions,
+	ContainerBulkActionRequest,
+	ContainerBulkActionResult,
+	AggregateContainerStats,
+	ContainerTrashEntry
 } from '$lib/types/container.type';
 import type { SearchPaginationSortRequest, Paginated } from '$lib/types/pagination.type';
 import { transformPaginationParams } from '$lib/utils/params.util';
@@ -41,29 +65,249 @@ export class ContainerService extends BaseAPIService {
 		return this.handleResponse(this.api.post(`/environments/${envId}/containers`, options));
 	}
 
-	async stopContainer(containerId: string): Promise<any> {
+	async stopContainer(containerId: string, timeout?: number): Promise<any> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
-		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/stop`));
+		const params = timeout ? { timeout } : undefined;
+		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/stop`, undefined, { params }));
 	}
 
-	async restartContainer(containerId: string): Promise<any> {
+	async restartContainer(containerId: string, timeout?: number): Promise<any> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
-		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/restart`));
+		const params = timeout ? { timeout } : undefined;
+		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/restart`, undefined, { params }));
 	}
 
-	async deleteContainer(containerId: string, opts?: { force?: boolean; volumes?: boolean }): Promise<any> {
+	async resizeExec(containerId: string, execId: string, rows: number, cols: number): Promise<any> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(
+			this.api.post(`/environments/${envId}/containers/${containerId}/exec/${execId}/resize`, { rows, cols })
+		);
+	}
+
+	async renameContainer(containerId: string, name: string): Promise<any> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/rename`, { name }));
+	}
+
+	async recreateContainer(containerId: string, changes: ContainerRecreateRequest): Promise<any> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/recreate`, changes));
+	}
+
+	async setContainerLabels(containerId: string, labels: Record<string, string>): Promise<any> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.put(`/environments/${envId}/containers/${containerId}/labels`, { labels }));
+	}
+
+	async updateContainerResources(containerId: string, update: ContainerResourcesUpdate): Promise<ContainerResourcesUpdateResult> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.put(`/environments/${envId}/containers/${containerId}/resources`, update));
+	}
+
+	async deleteContainer(containerId: string, opts?: { force?: boolean; volumes?: boolean; permanent?: boolean }): Promise<any> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		const params: Record<string, string> = {};
 		if (opts?.force !== undefined) params.force = String(!!opts.force);
 		if (opts?.volumes !== undefined) params.volumes = String(!!opts.volumes);
+		if (opts?.permanent !== undefined) params.permanent = String(!!opts.permanent);
 
 		return this.handleResponse(this.api.delete(`/environments/${envId}/containers/${containerId}`, { params }));
 	}
 
+	async getContainerTrash(): Promise<ContainerTrashEntry[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/containers/trash`);
+		return res.data.data;
+	}
+
+	async restoreContainerFromTrash(containerId: string): Promise<any> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/containers/trash/${containerId}/restore`));
+	}
+
+	async purgeContainerFromTrash(containerId: string): Promise<any> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.delete(`/environments/${envId}/containers/trash/${containerId}`));
+	}
+
 	async updateContainer(containerId: string): Promise<any> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/update`));
 	}
+
+	async getContainerProcesses(containerId: string, psArgs?: string): Promise<ContainerProcessList> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const params = psArgs ? { psArgs } : undefined;
+		return this.handleResponse(this.api.get(`/environments/${envId}/containers/${containerId}/processes`, { params }));
+	}
+
+	async bulkContainerAction(request: ContainerBulkActionRequest): Promise<ContainerBulkActionResult> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/containers/bulk`, request));
+	}
+
+	async getContainerSnapshotInfo(containerId: string): Promise<ContainerSnapshotInfo> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/containers/${containerId}/snapshot`));
+	}
+
+	async diffContainers(left: string, right: string): Promise<ContainerDiff> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/containers/diff`, { params: { left, right } }));
+	}
+
+	async commitContainer(containerId: string, request: ContainerCommitRequest): Promise<ContainerCommitResult> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${containerId}/commit`, request));
+	}
+
+	async exportContainer(containerId: string, fileName: string, onProgress?: (loaded: number) => void): Promise<void> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/containers/${containerId}/export`, {
+			responseType: 'blob',
+			onDownloadProgress: (event) => onProgress?.(event.loaded)
+		});
+
+		const url = window.URL.createObjectURL(new Blob([res.data]));
+		const link = document.createElement('a');
+		link.href = url;
+		link.setAttribute('download', `${fileName}.tar`);
+		document.body.appendChild(link);
+		link.click();
+		link.remove();
+		window.URL.revokeObjectURL(url);
+	}
+
+	async downloadContainerLogs(containerId: string, fileName: string, options: ContainerLogDownloadOptions = {}): Promise<void> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/containers/${containerId}/logs/download`, {
+			params: options,
+			responseType: 'blob'
+		});
+
+		const url = window.URL.createObjectURL(new Blob([res.data]));
+		const link = document.createElement('a');
+		link.href = url;
+		link.setAttribute('download', options.format === 'gzip' ? `${fileName}.log.gz` : `${fileName}.log`);
+		document.body.appendChild(link);
+		link.click();
+		link.remove();
+		window.URL.revokeObjectURL(url);
+	}
+
+	async getContainerOverrides(): Promise<ContainerOverride[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/containers/overrides`);
+		return res.data.data;
+	}
+
+	async setContainerOverride(container: string, override: ContainerOverrideRequest): Promise<ContainerOverride> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.put(`/environments/${envId}/containers/${encodeURIComponent(container)}/override`, override);
+		return res.data.data;
+	}
+
+	async deleteContainerOverride(container: string): Promise<any> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.delete(`/environments/${envId}/containers/${encodeURIComponent(container)}/override`));
+	}
+
+	async getContainerHealthchecks(): Promise<ContainerHealthcheck[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/containers/healthchecks`);
+		return res.data.data;
+	}
+
+	async setContainerHealthcheck(container: string, healthcheck: ContainerHealthcheckRequest): Promise<ContainerHealthcheck> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.put(`/environments/${envId}/containers/${encodeURIComponent(container)}/healthcheck`, healthcheck);
+		return res.data.data;
+	}
+
+	async deleteContainerHealthcheck(container: string): Promise<any> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.delete(`/environments/${envId}/containers/${encodeURIComponent(container)}/healthcheck`));
+	}
+
+	async getContainerDigests(): Promise<ContainerDigestStatus[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/containers/digests`));
+	}
+
+	async checkTagDrift(): Promise<ContainerDriftCheckResult> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/containers/digests/check`));
+	}
+
+	async getContainerDigest(container: string): Promise<ContainerDigestStatus> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/containers/${encodeURIComponent(container)}/digest`));
+	}
+
+	async pinContainer(container: string, reason?: string): Promise<ContainerDigestStatus> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${encodeURIComponent(container)}/pin`, { reason }));
+	}
+
+	async unpinContainer(container: string): Promise<any> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.delete(`/environments/${envId}/containers/${encodeURIComponent(container)}/pin`));
+	}
+
+	async getContainerRollback(container: string): Promise<ContainerRollbackStatus> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/containers/${encodeURIComponent(container)}/rollback`));
+	}
+
+	async rollbackContainer(container: string, force = false): Promise<ContainerRollbackStatus> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${encodeURIComponent(container)}/rollback`, { force }));
+	}
+
+	async getCheckpointSupport(): Promise<ContainerCheckpointSupport> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/containers/checkpoint-support`));
+	}
+
+	async listCheckpoints(container: string): Promise<ContainerCheckpoint[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/containers/${encodeURIComponent(container)}/checkpoints`));
+	}
+
+	async createCheckpoint(container: string, request: ContainerCheckpointCreateRequest): Promise<ContainerCheckpoint> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/containers/${encodeURIComponent(container)}/checkpoints`, request));
+	}
+
+	async restoreCheckpoint(container: string, checkpoint: string): Promise<any> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(
+			this.api.post(
+				`/environments/${envId}/containers/${encodeURIComponent(container)}/checkpoints/${encodeURIComponent(checkpoint)}/restore`
+			)
+		);
+	}
+
+	async deleteCheckpoint(container: string, checkpoint: string): Promise<any> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(
+			this.api.delete(`/environments/${envId}/containers/${encodeURIComponent(container)}/checkpoints/${encodeURIComponent(checkpoint)}`)
+		);
+	}
+
+	async getContainerHealthHistory(container: string, hours?: number): Promise<ContainerHealthHistory> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/containers/${encodeURIComponent(container)}/health-history`, {
+			params: { hours }
+		});
+		return res.data.data;
+	}
+
+	async getAggregateStats(): Promise<AggregateContainerStats> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/containers/stats`);
+		return res.data.data;
+	}
 }
 
 export const containerService = new ContainerService();
@@ -1,6 +1,17 @@
 import BaseAPIService from './api-service';
 import type { Environment } from '$lib/types/environment.type';
-import type { CreateEnvironmentDTO, UpdateEnvironmentDTO } from '$lib/types/environment.type';
+import type {
+	CreateEnvironmentDTO,
+	UpdateEnvironmentDTO,
+	ImportEnvironmentsDTO,
+	EnvironmentImportResult,
+	EnvironmentFeature,
+	EnvironmentFeatureFlag,
+	EnvironmentPolicy,
+	EnvironmentPolicyUpdate,
+	EnvironmentPolicyStatus,
+	PowerCapabilities
+} from '$lib/types/environment.type';
 import type { Paginated, SearchPaginationSortRequest } from '$lib/types/pagination.type';
 import type { AppVersionInformation } from '$lib/types/application-configuration';
 import { transformPaginationParams } from '$lib/utils/params.util';
@@ -11,6 +22,11 @@ export default class EnvironmentManagementService extends BaseAPIService {
 		return res.data.data as Environment;
 	}
 
+	async importEnvironments(dto: ImportEnvironmentsDTO): Promise<EnvironmentImportResult> {
+		const res = await this.api.post('/environments/import', dto);
+		return res.data.data as EnvironmentImportResult;
+	}
+
 	async getEnvironments(options: SearchPaginationSortRequest): Promise<Paginated<Environment>> {
 		const params = transformPaginationParams(options);
 		const res = await this.api.get('/environments', { params });
@@ -49,6 +65,47 @@ export default class EnvironmentManagementService extends BaseAPIService {
 		const res = await this.api.get(`/environments/${environmentId}/version`);
 		return res.data.data as AppVersionInformation;
 	}
+
+	async getFeatureFlags(environmentId: string): Promise<EnvironmentFeatureFlag[]> {
+		const res = await this.api.get(`/environments/${environmentId}/features`);
+		return res.data.data as EnvironmentFeatureFlag[];
+	}
+
+	async updateFeatureFlags(
+		environmentId: string,
+		overrides: Partial<Record<EnvironmentFeature, boolean | null>>
+	): Promise<EnvironmentFeatureFlag[]> {
+		const res = await this.api.put(`/environments/${environmentId}/features`, { overrides });
+		return res.data.data as EnvironmentFeatureFlag[];
+	}
+
+	async getPolicy(environmentId: string): Promise<EnvironmentPolicy> {
+		const res = await this.api.get(`/environments/${environmentId}/policy`);
+		return res.data.data as EnvironmentPolicy;
+	}
+
+	async updatePolicy(environmentId: string, dto: EnvironmentPolicyUpdate): Promise<EnvironmentPolicy> {
+		const res = await this.api.put(`/environments/${environmentId}/policy`, dto);
+		return res.data.data as EnvironmentPolicy;
+	}
+
+	async getPolicyStatus(environmentId: string): Promise<EnvironmentPolicyStatus> {
+		const res = await this.api.get(`/environments/${environmentId}/policy/status`);
+		return res.data.data as EnvironmentPolicyStatus;
+	}
+
+	async wake(environmentId: string): Promise<void> {
+		await this.api.post(`/environments/${environmentId}/wake`);
+	}
+
+	async getPowerCapabilities(environmentId: string): Promise<PowerCapabilities> {
+		const res = await this.api.get(`/environments/${environmentId}/system/power`);
+		return res.data.data as PowerCapabilities;
+	}
+
+	async rebootHost(environmentId: string): Promise<void> {
+		await this.api.post(`/environments/${environmentId}/system/power/reboot`, { confirm: true });
+	}
 }
 
 export const environmentManagementService = new EnvironmentManagementService();
@@ -1,8 +1,18 @@
 import BaseAPIService from './api-service';
 import { environmentStore } from '$lib/stores/environment.store.svelte';
-import type { ImageSummaryDto, ImageUsageCounts, ImageUpdateInfoDto } from '$lib/types/image.type';
+import type {
+	ImageSummaryDto,
+	ImageUsageCounts,
+	ImageUpdateInfoDto,
+	PinnedImage,
+	ImageBuildRequest,
+	ImageBuildResult,
+	ImageTagResult,
+	ImageHistory,
+	ImageDiff
+} from '$lib/types/image.type';
 import type { SearchPaginationSortRequest, Paginated } from '$lib/types/pagination.type';
-import type { AutoUpdateCheck, AutoUpdateResult } from '$lib/types/auto-update.type';
+import type { AutoUpdateCheck, AutoUpdateResult, UpdateRollout } from '$lib/types/auto-update.type';
 import { transformPaginationParams } from '$lib/utils/params.util';
 
 export class ImageService extends BaseAPIService {
@@ -40,6 +50,41 @@ export class ImageService extends BaseAPIService {
 		return this.handleResponse(this.api.post(`/environments/${envId}/images/prune`, body));
 	}
 
+	async getPinnedImages(): Promise<PinnedImage[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/images/pinned`));
+	}
+
+	async pinImage(imageId: string, reason?: string): Promise<PinnedImage> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.put(`/environments/${envId}/images/${imageId}/pin`, { reason }));
+	}
+
+	async unpinImage(imageId: string): Promise<void> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		await this.handleResponse(this.api.delete(`/environments/${envId}/images/${imageId}/pin`));
+	}
+
+	async getImageHistory(imageId: string): Promise<ImageHistory> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/images/${imageId}/history`));
+	}
+
+	async diffImages(left: string, right: string): Promise<ImageDiff> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/images/diff`, { params: { left, right } }));
+	}
+
+	async tagImage(imageId: string, tag: string, force = false): Promise<ImageTagResult> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/images/${imageId}/tags`, { tag, force }));
+	}
+
+	async untagImage(imageId: string, tag: string): Promise<ImageTagResult> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.delete(`/environments/${envId}/images/${imageId}/tags`, { params: { tag } }));
+	}
+
 	async checkImageUpdateByID(imageId: string): Promise<ImageUpdateInfoDto> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.handleResponse(this.api.post(`/environments/${envId}/image-updates/check/${imageId}`, {}));
@@ -55,10 +100,23 @@ export class ImageService extends BaseAPIService {
 		return this.handleResponse(this.api.post(`/environments/${envId}/updater/run`, options));
 	}
 
-	async uploadImage(file: File): Promise<any> {
+	async getUpdateRollouts(limit?: number): Promise<UpdateRollout[]> {
+		return this.handleResponse(this.api.get('/updater/rollouts', { params: { limit } }));
+	}
+
+	async promoteUpdateRollout(rolloutId: string): Promise<UpdateRollout> {
+		return this.handleResponse(this.api.post(`/updater/rollouts/${rolloutId}/promote`));
+	}
+
+	async haltUpdateRollout(rolloutId: string): Promise<UpdateRollout> {
+		return this.handleResponse(this.api.post(`/updater/rollouts/${rolloutId}/halt`));
+	}
+
+	async uploadImage(file: File, transferId?: string): Promise<any> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		const formData = new FormData();
 		formData.append('file', file);
+		if (transferId) formData.append('transferId', transferId);
 		return this.handleResponse(
 			this.api.post(`/environments/${envId}/images/upload`, formData, {
 				headers: {
@@ -67,6 +125,57 @@ export class ImageService extends BaseAPIService {
 			})
 		);
 	}
+
+	async saveImages(images: string[], transferId?: string, onProgress?: (loaded: number) => void): Promise<void> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.post(
+			`/environments/${envId}/images/save`,
+			{ images, transferId },
+			{
+				responseType: 'blob',
+				onDownloadProgress: (event) => onProgress?.(event.loaded)
+			}
+		);
+
+		const disposition: string = res.headers['content-disposition'] ?? '';
+		const fileName = disposition.match(/filename=([^;]+)/)?.[1] ?? 'images.tar';
+		const url = window.URL.createObjectURL(new Blob([res.data]));
+		const link = document.createElement('a');
+		link.href = url;
+		link.setAttribute('download', fileName);
+		document.body.appendChild(link);
+		link.click();
+		link.remove();
+		window.URL.revokeObjectURL(url);
+	}
+
+	async buildImage(request: ImageBuildRequest): Promise<ImageBuildResult> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const formData = new FormData();
+		formData.append('tags', request.tags.join(','));
+		if (request.context) formData.append('context', request.context);
+		if (request.dockerfile) formData.append('dockerfile', request.dockerfile);
+		if (request.gitUrl) formData.append('gitUrl', request.gitUrl);
+		if (request.dockerfilePath) formData.append('dockerfilePath', request.dockerfilePath);
+		if (request.buildArgs) {
+			formData.append(
+				'buildArgs',
+				Object.entries(request.buildArgs)
+					.map(([key, value]) => `${key}=${value}`)
+					.join('\n')
+			);
+		}
+		if (request.noCache) formData.append('noCache', 'true');
+		if (request.pull) formData.append('pull', 'true');
+		if (request.buildId) formData.append('buildId', request.buildId);
+		return this.handleResponse(
+			this.api.post(`/environments/${envId}/images/build`, formData, {
+				headers: {
+					'Content-Type': 'multipart/form-data'
+				}
+			})
+		);
+	}
 }
 
 export const imageService = new ImageService();
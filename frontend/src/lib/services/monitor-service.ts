@@ -0,0 +1,38 @@
+import BaseAPIService from './api-service';
+import { environmentStore } from '$lib/stores/environment.store.svelte';
+import type { Monitor, MonitorRequest, MonitorResult } from '$lib/types/monitor.type';
+
+export default class MonitorAPIService extends BaseAPIService {
+	async list(filter?: { projectId?: string; containerKey?: string }): Promise<Monitor[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/monitors`, { params: filter })) as Promise<Monitor[]>;
+	}
+
+	async get(id: string): Promise<Monitor> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/monitors/${id}`)) as Promise<Monitor>;
+	}
+
+	async create(monitor: MonitorRequest): Promise<Monitor> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/monitors`, monitor)) as Promise<Monitor>;
+	}
+
+	async update(id: string, monitor: MonitorRequest): Promise<Monitor> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.put(`/environments/${envId}/monitors/${id}`, monitor)) as Promise<Monitor>;
+	}
+
+	async delete(id: string): Promise<void> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.delete(`/environments/${envId}/monitors/${id}`)) as Promise<void>;
+	}
+
+	async listResults(id: string, limit?: number): Promise<MonitorResult[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/monitors/${id}/results`, { params: { limit } });
+		return res.data.data;
+	}
+}
+
+export const monitorService = new MonitorAPIService();
@@ -0,0 +1,42 @@
+import BaseAPIService from './api-service';
+import type { Namespace, NamespaceCreate, NamespaceUpdate, NamespaceUsage } from '$lib/types/namespace.type';
+
+export default class NamespaceAPIService extends BaseAPIService {
+	async list(): Promise<Namespace[]> {
+		return this.handleResponse(this.api.get('/namespaces')) as Promise<Namespace[]>;
+	}
+
+	async get(id: string): Promise<Namespace> {
+		return this.handleResponse(this.api.get(`/namespaces/${id}`)) as Promise<Namespace>;
+	}
+
+	async create(namespace: NamespaceCreate): Promise<Namespace> {
+		return this.handleResponse(this.api.post('/namespaces', namespace)) as Promise<Namespace>;
+	}
+
+	async update(id: string, namespace: NamespaceUpdate): Promise<Namespace> {
+		return this.handleResponse(this.api.put(`/namespaces/${id}`, namespace)) as Promise<Namespace>;
+	}
+
+	async delete(id: string): Promise<void> {
+		return this.handleResponse(this.api.delete(`/namespaces/${id}`)) as Promise<void>;
+	}
+
+	async setMembers(id: string, userIds: string[]): Promise<Namespace> {
+		return this.handleResponse(this.api.put(`/namespaces/${id}/members`, { userIds })) as Promise<Namespace>;
+	}
+
+	async setProjects(id: string, projectIds: string[]): Promise<Namespace> {
+		return this.handleResponse(this.api.put(`/namespaces/${id}/projects`, { projectIds })) as Promise<Namespace>;
+	}
+
+	async listUsage(): Promise<NamespaceUsage[]> {
+		return this.handleResponse(this.api.get('/namespaces/usage')) as Promise<NamespaceUsage[]>;
+	}
+
+	async getUsage(id: string): Promise<NamespaceUsage> {
+		return this.handleResponse(this.api.get(`/namespaces/${id}/usage`)) as Promise<NamespaceUsage>;
+	}
+}
+
+export const namespaceService = new NamespaceAPIService();
@@ -1,6 +1,16 @@
 import BaseAPIService from './api-service';
 import { environmentStore } from '$lib/stores/environment.store.svelte';
-import type { Project, ProjectStatusCounts } from '$lib/types/project.type';
+import type {
+	CanaryUpdateRequest,
+	CanaryUpdateResult,
+	DeployHook,
+	DeployHookRequest,
+	IncludeGraph,
+	Project,
+	ProjectInputState,
+	ProjectStatusCounts,
+	ProjectWatchStatus
+} from '$lib/types/project.type';
 import type { SearchPaginationSortRequest, Paginated } from '$lib/types/pagination.type';
 import { transformPaginationParams } from '$lib/utils/params.util';
 import { m } from '$lib/paraglide/messages';
@@ -81,6 +91,60 @@ export class ProjectService extends BaseAPIService {
 		return response.project ? response.project : (response as Project);
 	}
 
+	async getProjectHooks(projectId: string): Promise<DeployHook[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/projects/${projectId}/hooks`);
+		return res.data.data ?? [];
+	}
+
+	async setProjectHooks(projectId: string, hooks: DeployHookRequest[]): Promise<DeployHook[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.put(`/environments/${envId}/projects/${projectId}/hooks`, { hooks });
+		return res.data.data ?? [];
+	}
+
+	async canaryUpdateService(projectId: string, request: CanaryUpdateRequest): Promise<CanaryUpdateResult> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.post(`/environments/${envId}/projects/${projectId}/canary`, request);
+		return res.data.data;
+	}
+
+	async getProjectWatch(projectId: string): Promise<ProjectWatchStatus> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/projects/${projectId}/watch`);
+		return res.data.data;
+	}
+
+	async startProjectWatch(projectId: string, services?: string[]): Promise<ProjectWatchStatus> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.post(`/environments/${envId}/projects/${projectId}/watch/start`, { services });
+		return res.data.data;
+	}
+
+	async stopProjectWatch(projectId: string): Promise<ProjectWatchStatus> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.post(`/environments/${envId}/projects/${projectId}/watch/stop`);
+		return res.data.data;
+	}
+
+	async getProjectInputs(projectId: string): Promise<ProjectInputState[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/projects/${projectId}/inputs`);
+		return res.data.data;
+	}
+
+	async setProjectInputs(projectId: string, values: Record<string, string>): Promise<ProjectInputState[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.put(`/environments/${envId}/projects/${projectId}/inputs`, { values });
+		return res.data.data;
+	}
+
+	async getProjectIncludeGraph(projectId: string): Promise<IncludeGraph> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/projects/${projectId}/includes/graph`);
+		return res.data.data;
+	}
+
 	async getProjectStatusCounts(): Promise<ProjectStatusCounts> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 
@@ -1,6 +1,10 @@
 import BaseAPIService from './api-service';
 import { environmentStore } from '$lib/stores/environment.store.svelte';
 import type { DockerInfo } from '$lib/types/docker-info.type';
+import type { PruneReport } from '$lib/types/prune-report.type';
+import type { RunningOperation } from '$lib/types/operation.type';
+import type { SlowQueryReport } from '$lib/types/slow-query.type';
+import type { ChaosConfig } from '$lib/types/chaos.type';
 
 export class SystemService extends BaseAPIService {
 	async pruneAll(options: {
@@ -16,6 +20,36 @@ export class SystemService extends BaseAPIService {
 		return this.handleResponse(this.api.post(`/environments/${envId}/system/prune`, options));
 	}
 
+	async getPruneReports(limit = 50): Promise<PruneReport[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/system/prune/reports`, { params: { limit } }));
+	}
+
+	async getPruneReport(reportId: string): Promise<PruneReport> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/system/prune/reports/${reportId}`));
+	}
+
+	async getRunningOperations(): Promise<RunningOperation[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/system/operations`));
+	}
+
+	async getSlowQueries(): Promise<SlowQueryReport> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/system/debug/slow-queries`));
+	}
+
+	async getChaos(): Promise<ChaosConfig> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/system/debug/chaos`));
+	}
+
+	async updateChaos(config: ChaosConfig): Promise<ChaosConfig> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.put(`/environments/${envId}/system/debug/chaos`, config));
+	}
+
 	async startAllStoppedContainers() {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.handleResponse(this.api.post(`/environments/${envId}/system/containers/start-stopped`));
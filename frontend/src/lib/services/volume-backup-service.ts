@@ -1,6 +1,12 @@
 import BaseAPIService from './api-service';
 import { environmentStore } from '$lib/stores/environment.store.svelte';
-import type { BackupEntry } from '$lib/types/file-browser.type';
+import type {
+	BackupAdoptResult,
+	BackupDownloadToken,
+	BackupEntry,
+	CrossEnvironmentRestoreRequest,
+	RestoreResult
+} from '$lib/types/file-browser.type';
 import type { SearchPaginationSortRequest, Paginated } from '$lib/types/pagination.type';
 import { transformPaginationParams } from '$lib/utils/params.util';
 
@@ -20,9 +26,13 @@ export class VolumeBackupService extends BaseAPIService {
 		return res.data;
 	}
 
-	async restoreBackup(volumeName: string, backupId: string): Promise<void> {
+	async restoreBackup(volumeName: string, backupId: string, stopContainers = false): Promise<RestoreResult> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
-		return this.handleResponse(this.api.post(`/environments/${envId}/volumes/${volumeName}/backups/${backupId}/restore`));
+		return this.handleResponse(
+			this.api.post(`/environments/${envId}/volumes/${volumeName}/backups/${backupId}/restore`, null, {
+				params: stopContainers ? { stopContainers: true } : undefined
+			})
+		);
 	}
 
 	async restoreBackupFiles(volumeName: string, backupId: string, paths: string[]): Promise<void> {
@@ -48,27 +58,40 @@ export class VolumeBackupService extends BaseAPIService {
 		return res.data.data ?? [];
 	}
 
+	async scanAndAdoptBackups(): Promise<BackupAdoptResult> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.post(`/environments/${envId}/volumes/backups/scan`);
+		return res.data.data;
+	}
+
+	async restoreFromEnvironment(request: CrossEnvironmentRestoreRequest): Promise<void> {
+		return this.handleResponse(this.api.post('/volume-backups/cross-environment-restore', request));
+	}
+
 	async deleteBackup(backupId: string): Promise<void> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.handleResponse(this.api.delete(`/environments/${envId}/volumes/backups/${backupId}`));
 	}
 
-	async downloadBackup(backupId: string): Promise<void> {
-		const envId = await environmentStore.getCurrentEnvironmentId();
-		const res = await this.api.get(`/environments/${envId}/volumes/backups/${backupId}/download`, {
-			responseType: 'blob'
-		});
+	async createDownloadLink(backupId: string): Promise<BackupDownloadToken> {
+		const environmentId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.post('/volume-backups/download-tokens', { environmentId, backupId });
+		return res.data.data;
+	}
 
-		const url = window.URL.createObjectURL(new Blob([res.data]));
+	// Downloads through a link are handled by the browser, so large backups
+	// stream to disk and interrupted downloads can be resumed.
+	async downloadBackup(backupId: string): Promise<void> {
+		const { url } = await this.createDownloadLink(backupId);
 		const link = document.createElement('a');
-		link.href = url;
+		link.href = `${this.api.defaults.baseURL ?? '/api'}${url}`;
 		link.setAttribute('download', `${backupId}.tar.gz`);
 		document.body.appendChild(link);
 		link.click();
 		link.remove();
 	}
 
-	async uploadAndRestore(volumeName: string, file: File): Promise<void> {
+	async uploadAndRestore(volumeName: string, file: File): Promise<RestoreResult> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		const formData = new FormData();
 		formData.append('file', file);
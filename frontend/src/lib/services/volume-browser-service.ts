@@ -1,6 +1,13 @@
 import BaseAPIService from './api-service';
 import { environmentStore } from '$lib/stores/environment.store.svelte';
-import type { FileEntry, FileContentResponse } from '$lib/types/file-browser.type';
+import type {
+	FileEntry,
+	FileContentOptions,
+	FileContentResponse,
+	FilePreviewResponse,
+	DiskUsageEntry,
+	PermissionChanges
+} from '$lib/types/file-browser.type';
 
 export class VolumeBrowserService extends BaseAPIService {
 	async listDirectory(volumeName: string, path: string = '/'): Promise<FileEntry[]> {
@@ -11,14 +18,30 @@ export class VolumeBrowserService extends BaseAPIService {
 		return res.data.data;
 	}
 
-	async getFileContent(volumeName: string, path: string): Promise<FileContentResponse> {
+	async getFileContent(volumeName: string, path: string, options: FileContentOptions = {}): Promise<FileContentResponse> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		const res = await this.api.get(`/environments/${envId}/volumes/${volumeName}/browse/content`, {
+			params: { path, ...options }
+		});
+		return res.data.data;
+	}
+
+	async previewFile(volumeName: string, path: string): Promise<FilePreviewResponse> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/volumes/${volumeName}/browse/preview`, {
 			params: { path }
 		});
 		return res.data.data;
 	}
 
+	async getDiskUsage(volumeName: string, path: string = '/', depth = 1): Promise<DiskUsageEntry> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/volumes/${volumeName}/browse/usage`, {
+			params: { path, depth }
+		});
+		return res.data.data;
+	}
+
 	async downloadFile(volumeName: string, path: string): Promise<void> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		const res = await this.api.get(`/environments/${envId}/volumes/${volumeName}/browse/download`, {
@@ -37,6 +60,30 @@ export class VolumeBrowserService extends BaseAPIService {
 		link.remove();
 	}
 
+	async downloadDirectory(
+		volumeName: string,
+		path: string,
+		format: 'tar.gz' | 'zip' = 'tar.gz',
+		onProgress?: (loadedBytes: number) => void
+	): Promise<void> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/volumes/${volumeName}/browse/download-directory`, {
+			params: { path, format },
+			responseType: 'blob',
+			onDownloadProgress: (event) => onProgress?.(event.loaded)
+		});
+
+		const url = window.URL.createObjectURL(new Blob([res.data]));
+		const link = document.createElement('a');
+		link.href = url;
+		const dirName = path.split('/').filter(Boolean).pop() || volumeName;
+		link.setAttribute('download', `${dirName}.${format}`);
+		document.body.appendChild(link);
+		link.click();
+		link.remove();
+		window.URL.revokeObjectURL(url);
+	}
+
 	async uploadFile(volumeName: string, path: string, file: File): Promise<void> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		const formData = new FormData();
@@ -51,6 +98,29 @@ export class VolumeBrowserService extends BaseAPIService {
 		);
 	}
 
+	async changeOwnership(volumeName: string, path: string, uid?: number, gid?: number, recursive = false): Promise<void> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(
+			this.api.post(`/environments/${envId}/volumes/${volumeName}/browse/chown`, { path, uid, gid, recursive })
+		);
+	}
+
+	async changePermissions(volumeName: string, path: string, mode: string, recursive = false): Promise<void> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(
+			this.api.post(`/environments/${envId}/volumes/${volumeName}/browse/chmod`, { path, mode, recursive })
+		);
+	}
+
+	async setPermissions(volumeName: string, path: string, changes: PermissionChanges): Promise<void> {
+		if (changes.uid !== undefined || changes.gid !== undefined) {
+			await this.changeOwnership(volumeName, path, changes.uid, changes.gid, changes.recursive);
+		}
+		if (changes.mode) {
+			await this.changePermissions(volumeName, path, changes.mode, changes.recursive);
+		}
+	}
+
 	async createDirectory(volumeName: string, path: string): Promise<void> {
 		const envId = await environmentStore.getCurrentEnvironmentId();
 		return this.handleResponse(
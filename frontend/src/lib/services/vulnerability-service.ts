@@ -9,7 +9,10 @@ import type {
 	VulnerabilityWithImage,
 	EnvironmentVulnerabilitySummary,
 	IgnoredVulnerability,
-	IgnoreVulnerabilityPayload
+	IgnoreVulnerabilityPayload,
+	IgnoreAuditEntry,
+	ImageLicenseReport,
+	EnvironmentLicenseReport
 } from '$lib/types/vulnerability.type';
 import type { Paginated, SearchPaginationSortRequest } from '$lib/types/pagination.type';
 import { transformPaginationParams } from '$lib/utils/params.util';
@@ -31,6 +34,25 @@ export class VulnerabilityService extends BaseAPIService {
 		return this.handleResponse(this.api.get(`/environments/${envId}/images/${imageId}/vulnerabilities`));
 	}
 
+	/**
+	 * Download the scan result for an image as a SARIF file
+	 */
+	async downloadScanSarif(imageId: string, fileName: string): Promise<void> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const res = await this.api.get(`/environments/${envId}/images/${imageId}/vulnerabilities/sarif`, {
+			responseType: 'blob'
+		});
+
+		const url = window.URL.createObjectURL(new Blob([res.data], { type: 'application/sarif+json' }));
+		const link = document.createElement('a');
+		link.href = url;
+		link.setAttribute('download', `${fileName}.sarif`);
+		document.body.appendChild(link);
+		link.click();
+		link.remove();
+		window.URL.revokeObjectURL(url);
+	}
+
 	/**
 	 * Get a paginated list of vulnerabilities for an image
 	 */
@@ -112,6 +134,32 @@ export class VulnerabilityService extends BaseAPIService {
 		const res = await this.api.get(`/environments/${envId}/vulnerabilities/ignored`, { params });
 		return res.data;
 	}
+
+	/**
+	 * Get who created and removed ignore rules, and which rules expired
+	 */
+	async getIgnoreAudit(options?: SearchPaginationSortRequest): Promise<Paginated<IgnoreAuditEntry>> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		const params = transformPaginationParams(options);
+		const res = await this.api.get(`/environments/${envId}/vulnerabilities/ignored/audit`, { params });
+		return res.data;
+	}
+
+	/**
+	 * Get the licenses found in an image
+	 */
+	async getImageLicenses(imageId: string): Promise<ImageLicenseReport> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/images/${imageId}/licenses`));
+	}
+
+	/**
+	 * Get the licenses found in all scanned images
+	 */
+	async getEnvironmentLicenses(): Promise<EnvironmentLicenseReport> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/licenses`));
+	}
 }
 
 export const vulnerabilityService = new VulnerabilityService();
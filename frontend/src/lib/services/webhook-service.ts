@@ -0,0 +1,37 @@
+import BaseAPIService from './api-service';
+import { environmentStore } from '$lib/stores/environment.store.svelte';
+import type { WebhookSource, WebhookSourceRequest, WebhookSourceWithToken } from '$lib/types/webhook.type';
+
+export default class WebhookAPIService extends BaseAPIService {
+	async list(): Promise<WebhookSource[]> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/webhooks`)) as Promise<WebhookSource[]>;
+	}
+
+	async get(id: string): Promise<WebhookSource> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.get(`/environments/${envId}/webhooks/${id}`)) as Promise<WebhookSource>;
+	}
+
+	async create(source: WebhookSourceRequest): Promise<WebhookSourceWithToken> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/webhooks`, source)) as Promise<WebhookSourceWithToken>;
+	}
+
+	async update(id: string, source: WebhookSourceRequest): Promise<WebhookSource> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.put(`/environments/${envId}/webhooks/${id}`, source)) as Promise<WebhookSource>;
+	}
+
+	async rotateToken(id: string): Promise<WebhookSourceWithToken> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.post(`/environments/${envId}/webhooks/${id}/rotate`)) as Promise<WebhookSourceWithToken>;
+	}
+
+	async delete(id: string): Promise<void> {
+		const envId = await environmentStore.getCurrentEnvironmentId();
+		return this.handleResponse(this.api.delete(`/environments/${envId}/webhooks/${id}`)) as Promise<void>;
+	}
+}
+
+export const webhookService = new WebhookAPIService();
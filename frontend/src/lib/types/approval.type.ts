@@ -0,0 +1,23 @@
+export type ApprovalStatus = 'pending' | 'approved' | 'rejected' | 'expired' | 'consumed';
+
+export const APPROVAL_HEADER = 'X-Arcane-Approval-Id';
+
+export interface ApprovalRequest {
+	id: string;
+	action: 'prune' | 'volume_restore' | 'host_reboot';
+	environmentId: string;
+	method: string;
+	path: string;
+	query?: string;
+	body?: string;
+	status: ApprovalStatus;
+	requestedBy: string;
+	requestedByName: string;
+	decidedBy?: string;
+	decidedByName?: string;
+	decidedAt?: string;
+	reason?: string;
+	expiresAt: string;
+	consumedAt?: string;
+	createdAt: string;
+}
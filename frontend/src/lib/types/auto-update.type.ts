@@ -5,6 +5,7 @@ export interface AutoUpdateCheck {
 	resourceIds?: string[];
 	forceUpdate?: boolean;
 	dryRun?: boolean;
+	images?: string[];
 	resourceType?: AutoUpdateResourceType;
 }
 
@@ -29,3 +30,27 @@ export interface AutoUpdateResourceResult {
 	error?: string;
 	details?: Record<string, any>;
 }
+
+export type UpdateRolloutStatus = 'soaking' | 'rolling_out' | 'completed' | 'halted';
+
+export interface UpdateRolloutTarget {
+	environmentId: string;
+	environmentName?: string;
+	status: 'updated' | 'failed';
+	updated: number;
+	failed: number;
+	error?: string;
+}
+
+export interface UpdateRollout {
+	id: string;
+	status: UpdateRolloutStatus;
+	canaryEnvironmentId: string;
+	images: string[];
+	canaryContainers: string[];
+	soakUntil?: string;
+	targets?: UpdateRolloutTarget[];
+	error?: string;
+	startedAt: string;
+	completedAt?: string;
+}
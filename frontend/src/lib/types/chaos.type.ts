@@ -0,0 +1,9 @@
+export interface ChaosRule {
+	latencyMs: number;
+	failureRate: number;
+}
+
+export interface ChaosConfig {
+	docker: ChaosRule;
+	api: ChaosRule;
+}
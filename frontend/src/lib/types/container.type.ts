@@ -1,6 +1,8 @@
 // Base Container Types
 
 import type { ImageUpdateInfoDto } from './image.type';
+import type { UptimeStats } from './uptime.type';
+import type { ConfigChange } from './diff.type';
 
 export interface BaseContainer {
 	id: string;
@@ -38,6 +40,9 @@ export interface HostConfigCreate {
 	memorySwap?: number;
 	nanoCpus?: number;
 	cpuShares?: number;
+	dns?: string[];
+	dnsSearch?: string[];
+	extraHosts?: string[];
 }
 
 export interface NetworkingConfig {
@@ -73,6 +78,292 @@ export interface ContainerSummaryDto extends BaseContainer {
 	networkSettings: ContainerNetworkSettings;
 	mounts: ContainerMounts[];
 	updateInfo?: ImageUpdateInfoDto;
+	displayName?: string;
+	iconUrl?: string;
+	health?: ContainerHealthStatus;
+	uptime?: UptimeStats;
+}
+
+export interface ContainerOverrideRequest {
+	displayName?: string;
+	iconUrl?: string;
+}
+
+export interface ContainerRecreateRequest {
+	image?: string;
+	tag?: string;
+	env?: Record<string, string | null>;
+	restartPolicy?: RestartPolicy['name'];
+	maximumRetryCount?: number;
+	portBindings?: Record<string, PortBinding[]>;
+	labels?: Record<string, string | null>;
+	dns?: string[];
+	dnsSearch?: string[];
+	extraHosts?: string[];
+}
+
+export interface ContainerResourcesUpdate {
+	cpuShares?: number;
+	memoryBytes?: number;
+	memoryReservationBytes?: number;
+	restartPolicy?: RestartPolicy['name'];
+	maximumRetryCount?: number;
+}
+
+export interface ContainerResourceSettings {
+	cpuShares: number;
+	memoryBytes: number;
+	memoryReservationBytes: number;
+	restartPolicy: string;
+	maximumRetryCount?: number;
+}
+
+export interface ContainerResourcesUpdateResult {
+	before: ContainerResourceSettings;
+	after: ContainerResourceSettings;
+	warnings?: string[];
+}
+
+export interface ContainerProcessList {
+	containerId: string;
+	titles: string[];
+	processes: string[][];
+	timestamp: string;
+}
+
+export interface ContainerSnapshotInfo {
+	containerId: string;
+	sizeRootFs: number;
+	sizeRw: number;
+	volumes?: string[];
+	warnings?: string[];
+}
+
+export interface ContainerDiffSubject {
+	id: string;
+	name: string;
+	image: string;
+	imageId: string;
+}
+
+export interface ContainerDiff {
+	left: ContainerDiffSubject;
+	right: ContainerDiffSubject;
+	identical: boolean;
+	changes: ConfigChange[];
+}
+
+export interface ContainerTrashEntry {
+	containerId: string;
+	originalName: string;
+	trashName: string;
+	wasRunning: boolean;
+	removeVolumes: boolean;
+	deletedBy: string;
+	deletedAt: string;
+	purgeAt: string;
+}
+
+export interface ContainerCommitRequest {
+	repository: string;
+	tag?: string;
+	comment?: string;
+	author?: string;
+	pause?: boolean;
+	changes?: string[];
+}
+
+export interface ContainerCommitResult {
+	imageId: string;
+	reference: string;
+	size: number;
+	warnings?: string[];
+}
+
+export interface ContainerLogDownloadOptions {
+	since?: string;
+	until?: string;
+	stdout?: boolean;
+	stderr?: boolean;
+	timestamps?: boolean;
+	format?: 'text' | 'gzip';
+	maxBytes?: number;
+}
+
+// normalized trims messages, raw keeps ANSI codes and formatting, structured
+// additionally parses JSON messages into fields.
+export type LogStreamMode = 'normalized' | 'raw' | 'structured';
+
+export interface LogStreamFilter {
+	search?: string;
+	regex?: boolean;
+	caseSensitive?: boolean;
+	levels?: Array<'trace' | 'debug' | 'info' | 'warn' | 'error' | 'fatal'>;
+	stream?: 'stdout' | 'stderr';
+	detectLevel?: boolean;
+}
+
+export type ContainerBulkAction = 'start' | 'stop' | 'restart' | 'delete';
+
+export interface ContainerBulkActionRequest {
+	action: ContainerBulkAction;
+	containerIds: string[];
+	force?: boolean;
+	removeVolumes?: boolean;
+	timeout?: number;
+	concurrency?: number;
+}
+
+export interface ContainerBulkActionItem {
+	containerId: string;
+	success: boolean;
+	error?: string;
+}
+
+export interface ContainerBulkActionResult {
+	action: ContainerBulkAction;
+	succeeded: number;
+	failed: number;
+	results: ContainerBulkActionItem[];
+}
+
+export type ContainerHealthStatus = 'starting' | 'healthy' | 'unhealthy';
+
+export interface ContainerHealthcheckRequest {
+	type: 'http' | 'tcp' | 'command';
+	port?: number;
+	path?: string;
+	command?: string[];
+	intervalSeconds?: number;
+	timeoutSeconds?: number;
+	retries?: number;
+	autoRestart?: boolean;
+}
+
+export interface ContainerHealthcheck {
+	containerKey: string;
+	type: ContainerHealthcheckRequest['type'];
+	port?: number;
+	path?: string;
+	command?: string[];
+	intervalSeconds: number;
+	timeoutSeconds: number;
+	retries: number;
+	autoRestart: boolean;
+	status: ContainerHealthStatus;
+	failingStreak: number;
+	lastOutput?: string;
+	lastCheckedAt?: string;
+	updatedBy: string;
+	createdAt: string;
+	updatedAt?: string;
+}
+
+export interface ContainerHealthTransition {
+	containerId: string;
+	status: ContainerHealthStatus;
+	at: string;
+}
+
+export interface ContainerDigestStatus {
+	containerName: string;
+	containerId: string;
+	imageRef: string;
+	imageId: string;
+	digest?: string;
+	remoteDigest?: string;
+	drifted: boolean;
+	driftCheckedAt?: string;
+	lastError?: string;
+	pinned: boolean;
+	pinReason?: string;
+	pinnedBy?: string;
+	recordedAt: string;
+}
+
+export interface ContainerDriftCheckResult {
+	checked: number;
+	drifted: number;
+	failed: number;
+	items: ContainerDigestStatus[];
+}
+
+export interface ContainerRollbackStatus {
+	containerName: string;
+	containerId: string;
+	previousImageRef: string;
+	previousImageId: string;
+	newImageRef: string;
+	appliedAt: string;
+	observeUntil: string;
+	health?: 'healthy' | 'starting' | 'unhealthy';
+	available: boolean;
+	rolledBackAt?: string;
+	rolledBackBy?: string;
+}
+
+export interface ContainerCheckpointSupport {
+	supported: boolean;
+	experimental: boolean;
+	osType: string;
+	reason?: string;
+}
+
+export interface ContainerCheckpoint {
+	name: string;
+}
+
+export interface ContainerCheckpointCreateRequest {
+	name?: string;
+	leaveRunning?: boolean;
+}
+
+export interface ContainerHealthHistory {
+	containerName: string;
+	transitions: ContainerHealthTransition[];
+	transitionsLastHour: number;
+	flapping: boolean;
+}
+
+export interface ContainerStatsSample {
+	id: string;
+	name: string;
+	cpuPercent: number;
+	memoryUsage: number;
+	memoryLimit: number;
+	memoryPercent: number;
+	networkRx: number;
+	networkTx: number;
+	blockRead: number;
+	blockWrite: number;
+	pids: number;
+}
+
+export interface HostContainerStats {
+	running: number;
+	cpuCount: number;
+	memoryTotal: number;
+	cpuPercent: number;
+	memoryUsage: number;
+	networkRx: number;
+	networkTx: number;
+	blockRead: number;
+	blockWrite: number;
+}
+
+export interface AggregateContainerStats {
+	sampledAt: string;
+	host: HostContainerStats;
+	containers: ContainerStatsSample[];
+}
+
+export interface ContainerOverride {
+	containerKey: string;
+	displayName?: string;
+	iconUrl?: string;
+	updatedBy: string;
+	createdAt: string;
+	updatedAt?: string;
 }
 
 export interface ContainerPorts {
@@ -89,6 +380,9 @@ export interface ContainerHostConfig {
 	autoRemove?: boolean;
 	nanoCpus?: number;
 	memory?: number;
+	dns?: string[];
+	dnsSearch?: string[];
+	extraHosts?: string[];
 }
 
 export interface ContainerNetworkSettings {
@@ -139,6 +433,7 @@ export interface ContainerStateDto {
 	finishedAt: string;
 	health?: {
 		status: string;
+		failingStreak?: number;
 		log?: Array<{
 			start?: string;
 			Start?: string;
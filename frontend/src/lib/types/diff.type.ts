@@ -0,0 +1,8 @@
+export type ConfigChangeKind = 'added' | 'removed' | 'modified';
+
+export interface ConfigChange {
+	path: string;
+	kind: ConfigChangeKind;
+	old?: unknown;
+	new?: unknown;
+}
@@ -1,5 +1,14 @@
 export type EnvironmentStatus = 'online' | 'offline' | 'error' | 'pending';
 
+export interface EnvironmentMetrics {
+	agentVersion?: string;
+	containersRunning: number;
+	containersTotal: number;
+	pendingUpdates: number;
+	diskFreeBytes?: number;
+	reportedAt?: string;
+}
+
 export type Environment = {
 	id: string;
 	name: string;
@@ -7,7 +16,18 @@ export type Environment = {
 	status: EnvironmentStatus;
 	enabled: boolean;
 	isEdge: boolean;
+	requireApproval?: boolean;
+	color?: string;
+	icon?: string;
+	description?: string;
+	production?: boolean;
+	productionGuardrails?: boolean;
+	tags?: string[];
+	macAddress?: string;
+	wakeBroadcast?: string;
+	wakeRelayId?: string;
 	lastSeen?: string;
+	metrics?: EnvironmentMetrics;
 	apiKey?: string;
 };
 
@@ -17,6 +37,7 @@ export interface CreateEnvironmentDTO {
 	bootstrapToken?: string;
 	useApiKey?: boolean;
 	isEdge?: boolean;
+	tags?: string[];
 }
 
 export interface UpdateEnvironmentDTO {
@@ -26,9 +47,81 @@ export interface UpdateEnvironmentDTO {
 	isEdge?: boolean;
 	bootstrapToken?: string;
 	regenerateApiKey?: boolean;
+	requireApproval?: boolean;
+	color?: string;
+	icon?: string;
+	description?: string;
+	production?: boolean;
+	tags?: string[];
+	macAddress?: string;
+	wakeBroadcast?: string;
+	wakeRelayId?: string;
+}
+
+export interface PowerCapabilities {
+	rebootEnabled: boolean;
+}
+
+export interface ImportEnvironmentsDTO {
+	format?: 'yaml' | 'csv';
+	manifest: string;
+}
+
+export interface EnvironmentImportResultEntry {
+	index: number;
+	name?: string;
+	apiUrl: string;
+	status: 'created' | 'skipped' | 'failed';
+	environmentId?: string;
+	apiKey?: string;
+	error?: string;
+}
+
+export interface EnvironmentImportResult {
+	created: number;
+	skipped: number;
+	failed: number;
+	results: EnvironmentImportResultEntry[];
 }
 
 export interface DeploymentSnippets {
 	dockerRun: string;
 	dockerCompose: string;
 }
+
+export type EnvironmentFeature = 'volumeBrowser' | 'containerExec' | 'prune' | 'containerCheckpoint';
+
+export interface EnvironmentFeatureFlag {
+	name: EnvironmentFeature;
+	enabled: boolean;
+	globalEnabled: boolean;
+	override?: boolean;
+}
+
+export type EnvironmentPruneMode = 'dangling' | 'all';
+
+export interface EnvironmentPolicy {
+	revision: number;
+	allowedImages?: string[];
+	denyPrivileged: boolean;
+	denyHostNetwork: boolean;
+	pruneSchedule?: string;
+	pruneMode?: EnvironmentPruneMode;
+	updatedAt?: string;
+}
+
+export interface EnvironmentPolicyUpdate {
+	allowedImages?: string[];
+	denyPrivileged?: boolean;
+	denyHostNetwork?: boolean;
+	pruneSchedule?: string;
+	pruneMode?: EnvironmentPruneMode;
+}
+
+export interface EnvironmentPolicyStatus {
+	policy: EnvironmentPolicy;
+	syncedAt?: string;
+	lastSyncError?: string;
+	lastPruneAt?: string;
+	pendingReports: number;
+}
@@ -12,11 +12,117 @@ export interface FileEntry {
 export interface FileContentResponse {
 	content: string; // Base64 encoded bytes from Go
 	mimeType: string;
+	language?: string;
+	lineCount?: number;
+	isBinary?: boolean;
+	size?: number;
+	offset?: number;
+	truncated?: boolean;
+	viewer?: 'sqlite' | 'archive';
+}
+
+export interface FileContentOptions {
+	maxBytes?: number;
+	offset?: number;
+	tail?: boolean;
+}
+
+export interface ArchiveEntry {
+	name: string;
+	size: number;
+	isDirectory: boolean;
+	modTime: string;
+}
+
+export interface SqliteTable {
+	name: string;
+	type: 'table' | 'view';
+	columns: string[];
+	rowCount: number;
+}
+
+export interface FilePreviewResponse {
+	viewer: 'sqlite' | 'archive';
+	entries?: ArchiveEntry[];
+	tables?: SqliteTable[];
+	truncated: boolean;
 }
 
 export interface BackupEntry {
 	id: string;
 	volumeName: string;
 	size: number;
+	driver?: 'tar' | 'zfs' | 'btrfs';
 	createdAt: string;
 }
+
+export interface SkippedBackupArchive {
+	filename: string;
+	reason: string;
+}
+
+export interface BackupAdoptResult {
+	adopted: BackupEntry[];
+	skipped: SkippedBackupArchive[];
+}
+
+export interface RestoreResult {
+	volumeName: string;
+	backupId?: string;
+	preRestoreBackupId: string;
+	stoppedContainers?: string[];
+}
+
+export interface BackupDownloadToken {
+	token: string;
+	url: string;
+	expiresAt: string;
+}
+
+export interface CrossEnvironmentRestoreRequest {
+	sourceEnvironmentId: string;
+	backupId: string;
+	targetEnvironmentId: string;
+	targetVolume: string;
+}
+
+export type BackupProgressPhase =
+	| 'starting'
+	| 'scanning'
+	| 'archiving'
+	| 'uploading'
+	| 'verifying'
+	| 'extracting'
+	| 'replacing'
+	| 'completed'
+	| 'failed';
+
+export interface BackupProgress {
+	operationId: string;
+	operation: 'backup' | 'restore';
+	volumeName: string;
+	backupId?: string;
+	phase: BackupProgressPhase;
+	bytesProcessed: number;
+	totalBytes: number;
+	filesWritten: number;
+	totalFiles: number;
+	error?: string;
+	volumeUnchanged?: boolean;
+	timestamp: string;
+}
+
+export interface PermissionChanges {
+	mode?: string;
+	uid?: number;
+	gid?: number;
+	recursive: boolean;
+}
+
+export interface DiskUsageEntry {
+	name: string;
+	path: string;
+	size: number;
+	isDirectory: boolean;
+	children?: DiskUsageEntry[];
+}
@@ -1,3 +1,4 @@
+import type { ConfigChange } from './diff.type';
 import type { VulnerabilityScanSummary } from './vulnerability.type';
 
 export interface ImageUpdateInfoDto {
@@ -32,12 +33,21 @@ export interface ImageSummaryDto {
 	virtualSize: number;
 	labels: Record<string, unknown> | null;
 	inUse: boolean;
+	pinned: boolean;
 	repo: string;
 	tag: string;
 	updateInfo?: ImageUpdateInfoDto;
 	vulnerabilityScan?: VulnerabilityScanSummary;
 }
 
+export interface PinnedImage {
+	digest: string;
+	imageRef: string;
+	reason?: string;
+	pinnedBy: string;
+	createdAt: string;
+}
+
 export interface ImageDetailSummaryDto {
 	id: string;
 	repoTags: string[];
@@ -74,6 +84,109 @@ export interface ImageDetailSummaryDto {
 		digest: string;
 		size: number;
 	};
+	baseImage?: BaseImage;
+}
+
+export interface BaseImage {
+	name: string;
+	digest?: string;
+	source: 'annotation' | 'history' | 'labels';
+	distribution?: string;
+	release?: string;
+	outdated: boolean;
+	latestRelease?: string;
+	recommendation?: string;
 }
 
 export type ImageUpdateData = ImageUpdateInfoDto;
+
+export interface ImageBuildRequest {
+	tags: string[];
+	context?: File;
+	dockerfile?: File;
+	gitUrl?: string;
+	dockerfilePath?: string;
+	buildArgs?: Record<string, string>;
+	noCache?: boolean;
+	pull?: boolean;
+	buildId?: string;
+}
+
+export interface ImageBuildResult {
+	buildId: string;
+	imageId: string;
+	tags: string[];
+}
+
+export interface ImageHistoryLayer {
+	id: string;
+	created: string;
+	createdBy: string;
+	size: number;
+	comment?: string;
+	tags?: string[];
+}
+
+export interface ImageConfigDetails {
+	user?: string;
+	entrypoint?: string[];
+	cmd?: string[];
+	workingDir?: string;
+	env?: string[];
+	exposedPorts?: string[];
+	volumes?: string[];
+	labels?: Record<string, string>;
+	stopSignal?: string;
+	healthcheck?: string[];
+}
+
+export interface ImageHistory {
+	imageId: string;
+	repoTags?: string[];
+	layers: ImageHistoryLayer[];
+	layerDigests: string[];
+	size: number;
+	config: ImageConfigDetails;
+}
+
+export interface ImageDiffSubject {
+	id: string;
+	repoTags?: string[];
+	size: number;
+}
+
+export interface ImageDiff {
+	left: ImageDiffSubject;
+	right: ImageDiffSubject;
+	identical: boolean;
+	changes: ConfigChange[];
+	sharedLayers: number;
+	addedLayers: string[];
+	removedLayers: string[];
+	addedSteps: ImageHistoryLayer[];
+	removedSteps: ImageHistoryLayer[];
+}
+
+export interface ImageTagResult {
+	imageId: string;
+	tag: string;
+	repoTags: string[];
+}
+
+export interface ImageTransferProgress {
+	transferId: string;
+	operation: 'save' | 'load';
+	status: 'running' | 'succeeded' | 'failed';
+	bytes: number;
+	totalBytes?: number;
+	images?: string[];
+	error?: string;
+}
+
+export interface ImageBuildProgress {
+	buildId: string;
+	status: 'running' | 'succeeded' | 'failed';
+	output?: string;
+	imageId?: string;
+	error?: string;
+}
@@ -7,6 +7,8 @@ export type JobSchedules = {
 	scheduledPruneInterval: string;
 	gitopsSyncInterval: string;
 	vulnerabilityScanInterval: string;
+	bootVerificationInterval: string;
+	vulnerabilityDigestInterval: string;
 };
 
 export type JobSchedulesUpdate = Partial<JobSchedules>;
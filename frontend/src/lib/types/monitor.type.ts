@@ -0,0 +1,48 @@
+export type MonitorType = 'http' | 'tcp';
+export type MonitorStatus = 'pending' | 'up' | 'down';
+
+export interface MonitorRequest {
+	name: string;
+	type: MonitorType;
+	target: string;
+	expectedStatus?: number;
+	intervalSeconds?: number;
+	timeoutSeconds?: number;
+	retries?: number;
+	projectId?: string;
+	containerKey?: string;
+	enabled?: boolean;
+	notify?: boolean;
+}
+
+export interface Monitor {
+	id: string;
+	name: string;
+	type: MonitorType;
+	target: string;
+	expectedStatus: number;
+	intervalSeconds: number;
+	timeoutSeconds: number;
+	retries: number;
+	projectId?: string;
+	containerKey?: string;
+	enabled: boolean;
+	notify: boolean;
+	status: MonitorStatus;
+	failingStreak: number;
+	lastStatusCode?: number;
+	lastLatencyMs?: number;
+	lastError?: string;
+	lastCheckedAt?: string;
+	updatedBy: string;
+	createdAt: string;
+	updatedAt?: string;
+}
+
+export interface MonitorResult {
+	up: boolean;
+	statusCode?: number;
+	latencyMs: number;
+	error?: string;
+	checkedAt: string;
+}
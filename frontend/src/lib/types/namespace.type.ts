@@ -0,0 +1,40 @@
+export const NAMESPACE_LABEL = 'com.getarcaneapp.namespace';
+
+export interface Namespace {
+	id: string;
+	name: string;
+	description?: string;
+	maxContainers: number;
+	maxMemoryMb: number;
+	maxVolumeGb: number;
+	memberIds: string[];
+	projects: string[];
+	createdAt: string;
+	updatedAt?: string;
+}
+
+export interface NamespaceCreate {
+	name: string;
+	description?: string;
+	maxContainers?: number;
+	maxMemoryMb?: number;
+	maxVolumeGb?: number;
+}
+
+export interface NamespaceUpdate {
+	description?: string;
+	maxContainers?: number;
+	maxMemoryMb?: number;
+	maxVolumeGb?: number;
+}
+
+export interface NamespaceUsage {
+	namespaceId: string;
+	name: string;
+	containers: number;
+	maxContainers: number;
+	memoryBytes: number;
+	maxMemoryMb: number;
+	volumeBytes: number;
+	maxVolumeGb: number;
+}
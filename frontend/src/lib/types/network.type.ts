@@ -18,6 +18,7 @@ export interface NetworkCreateOptions {
 	attachable?: boolean;
 	ingress?: boolean;
 	ipam?: IPAM;
+	enableIPv4?: boolean;
 	enableIPv6?: boolean;
 	options?: Record<string, string>;
 	labels?: Record<string, string>;
@@ -0,0 +1,6 @@
+export interface RunningOperation {
+	id: string;
+	kind: string;
+	resource: string;
+	startedAt: string;
+}
@@ -1,3 +1,5 @@
+import type { UptimeStats } from './uptime.type';
+
 export interface NetworkSettings {
 	Networks: Record<
 		string,
@@ -78,6 +80,7 @@ export interface Project {
 	gitOpsManagedBy?: string;
 	lastSyncCommit?: string;
 	gitRepositoryURL?: string;
+	uptime?: UptimeStats;
 	services?: ProjectService[];
 	runtimeServices?: RuntimeService[];
 	composeContent?: string;
@@ -90,3 +93,100 @@ export interface ProjectStatusCounts {
 	stoppedProjects: number;
 	totalProjects: number;
 }
+
+export type DeployHookPhase = 'pre' | 'post';
+export type DeployHookType = 'exec' | 'helper' | 'webhook';
+
+export interface DeployHookRequest {
+	phase: DeployHookPhase;
+	type: DeployHookType;
+	service?: string;
+	image?: string;
+	command?: string;
+	url?: string;
+	timeoutSeconds?: number;
+	onFailure?: 'abort' | 'warn';
+	disabled?: boolean;
+}
+
+export interface DeployHook extends DeployHookRequest {
+	id: string;
+	projectId: string;
+	updatedBy: string;
+}
+
+export interface CanaryUpdateRequest {
+	service: string;
+	bakeSeconds?: number;
+	pull?: boolean;
+}
+
+export interface CanaryUpdateResult {
+	service: string;
+	replicas: number;
+	canaryContainerId: string;
+	outcome: 'promoted' | 'rolled_back';
+	reason?: string;
+	bakeSeconds: number;
+	startedAt: string;
+	finishedAt: string;
+}
+
+export interface ProjectWatchStatus {
+	projectId: string;
+	running: boolean;
+	services?: string[];
+	startedAt?: string;
+	startedBy?: string;
+	lastMessage?: string;
+	error?: string;
+}
+
+export interface ProjectWatchEvent {
+	projectId: string;
+	type: 'started' | 'stopped' | 'watch' | 'log' | 'error';
+	service?: string;
+	message?: string;
+	time: string;
+}
+
+export type ProjectInputType = 'string' | 'int' | 'enum' | 'secret' | 'port';
+
+export interface ProjectInput {
+	name: string;
+	type: ProjectInputType;
+	label?: string;
+	description?: string;
+	default?: string;
+	required?: boolean;
+	options?: string[];
+	min?: number;
+	max?: number;
+}
+
+export interface ProjectInputState extends ProjectInput {
+	value?: string;
+	hasValue: boolean;
+	pending: boolean;
+}
+
+export interface IncludeNode {
+	path: string;
+	exists: boolean;
+	error?: string;
+}
+
+export interface IncludeEdge {
+	from: string;
+	to: string;
+	declared: string;
+}
+
+export interface IncludeGraph {
+	root: string;
+	nodes: IncludeNode[];
+	edges: IncludeEdge[];
+	cycles: string[][];
+	missing: string[];
+	valid: boolean;
+}
@@ -0,0 +1,20 @@
+export type PrunedItemType = 'container' | 'image' | 'volume' | 'network' | 'buildCache';
+
+export interface PrunedItem {
+	type: PrunedItemType;
+	id: string;
+	name?: string;
+	size: number;
+}
+
+export interface PruneReport {
+	id: string;
+	trigger: 'manual' | 'scheduled';
+	username?: string;
+	success: boolean;
+	spaceReclaimed: number;
+	itemCount: number;
+	items?: PrunedItem[];
+	errors?: string[];
+	createdAt: string;
+}
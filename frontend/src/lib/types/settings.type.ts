@@ -6,9 +6,24 @@ export type Settings = {
 	autoUpdate: boolean;
 	autoUpdateInterval: number;
 	autoUpdateExcludedContainers?: string;
+	autoUpdateRollbackWindow?: number;
+	autoUpdateCanaryEnvironment?: string;
+	autoUpdateSoakPeriod?: number;
 	pollingEnabled: boolean;
 	pollingInterval: number;
+	updateCheckCacheTtl?: number;
+	tagDriftDetectionEnabled?: boolean;
+	registryRequestBudget?: number;
+	registryMirrors?: string;
 	environmentHealthInterval: number;
+	featureVolumeBrowserEnabled?: boolean;
+	featureContainerExecEnabled?: boolean;
+	featurePruneEnabled?: boolean;
+	featureCheckpointEnabled?: boolean;
+	approvalWorkflowEnabled?: boolean;
+	approvalRequestTtl?: number;
+	execAuditTranscriptEnabled?: boolean;
+	execAuditTranscriptMaxKb?: number;
 	dockerPruneMode: 'all' | 'dangling';
 	scheduledPruneEnabled?: boolean;
 	scheduledPruneInterval?: number;
@@ -17,9 +32,32 @@ export type Settings = {
 	scheduledPruneVolumes?: boolean;
 	scheduledPruneNetworks?: boolean;
 	scheduledPruneBuildCache?: boolean;
+	maintenanceWindows?: string;
+	maintenanceWindowOverride?: boolean;
+	bootVerificationEnabled?: boolean;
+	bootVerificationInterval?: string;
+	healthFlapThreshold?: number;
+	crashLoopThreshold?: number;
+	crashLoopWindow?: number;
+	containerTrashRetention?: number;
+	containerDnsServers?: string;
+	containerDnsSearch?: string;
+	containerExtraHosts?: string;
+	volumeBackupDriver?: 'tar' | 'snapshot';
+	helperImage?: string;
+	helperCpuLimit?: number;
+	helperMemoryLimit?: number;
+	helperIdleTtl?: number;
+	staleHelperMaxAge?: number;
+	dockerMaxConcurrentRequests?: number;
+	dockerMaxConcurrentDiskUsage?: number;
+	dockerMaxConcurrentExecs?: number;
+	dockerMaxConcurrentPulls?: number;
 	vulnerabilityScanEnabled?: boolean;
 	vulnerabilityScanInterval?: number;
 	maxImageUploadSize: number;
+	maxVolumeDownloadSize?: number;
+	maxBackupUploadSize?: number;
 	baseServerUrl: string;
 	enableGravatar: boolean;
 	uiConfigDisabled: boolean;
@@ -33,6 +71,16 @@ export type Settings = {
 	authSessionTimeout: number;
 	authPasswordPolicy: 'basic' | 'standard' | 'strong';
 	trivyImage: string;
+	licenseDenylist?: string;
+	vulnerabilityScanOnPull?: boolean;
+	vulnerabilityScanGateSeverity?: string;
+	vulnerabilityScanGateThreshold?: number;
+	vulnerabilityDigestEnabled?: boolean;
+	vulnerabilityDigestInterval?: string;
+	vulnerabilityDigestCriticalThreshold?: number;
+	vulnerabilityDigestHighThreshold?: number;
+	vulnerabilityDigestMediumThreshold?: number;
+	vulnerabilityDigestLowThreshold?: number;
 	oidcEnabled: boolean;
 	oidcClientId: string;
 	oidcClientSecret?: string;
@@ -57,6 +105,9 @@ export type Settings = {
 	httpClientTimeout: number;
 	registryTimeout: number;
 	proxyRequestTimeout: number;
+	helperExecTimeout: number;
+	helperScanTimeout: number;
+	helperArchiveTimeout: number;
 
 	registryCredentials: RegistryCredential[];
 	templateRegistries: TemplateRegistryConfig[];
@@ -0,0 +1,12 @@
+export interface SlowQuery {
+	sql: string;
+	rows: number;
+	durationMs: number;
+	timestamp: string;
+	error?: string;
+}
+
+export interface SlowQueryReport {
+	thresholdMs: number;
+	queries: SlowQuery[];
+}
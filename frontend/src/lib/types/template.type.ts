@@ -1,3 +1,5 @@
+import type { ProjectInput } from './project.type';
+
 export interface TemplateRegistry {
 	id: string;
 	name: string;
@@ -42,6 +44,7 @@ export interface TemplateContentData {
 	envContent: string;
 	services: string[];
 	envVariables: EnvVariable[];
+	inputs?: ProjectInput[];
 }
 
 export interface RemoteTemplate {
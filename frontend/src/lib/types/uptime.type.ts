@@ -0,0 +1,6 @@
+export interface UptimeStats {
+	up: boolean;
+	last24h?: number;
+	last7d?: number;
+	last30d?: number;
+}
@@ -13,6 +13,7 @@ export interface Vulnerability {
 	vulnerabilityId: string;
 	pkgName: string;
 	installedVersion: string;
+	location?: string;
 	fixedVersion?: string;
 	severity: VulnerabilitySeverity;
 	title?: string;
@@ -76,22 +77,75 @@ export interface ScannerStatus {
 	version?: string;
 }
 
+export type IgnoreScope = 'exact' | 'cve' | 'package';
+
 export interface IgnoreVulnerabilityPayload {
+	scope?: IgnoreScope;
+	imageId?: string;
+	vulnerabilityId?: string;
+	pkgName?: string;
+	installedVersion?: string;
+	reason?: string;
+	expiresInDays?: number;
+}
+
+export interface IgnoredVulnerability {
+	id: string;
+	environmentId: string;
+	scope: IgnoreScope;
 	imageId: string;
 	vulnerabilityId: string;
 	pkgName: string;
 	installedVersion: string;
 	reason?: string;
+	createdBy: string;
+	createdAt: string;
+	expiresAt?: string;
 }
 
-export interface IgnoredVulnerability {
+export interface IgnoreAuditEntry {
 	id: string;
+	ignoreId: string;
 	environmentId: string;
+	action: 'created' | 'removed' | 'expired';
+	scope: IgnoreScope;
 	imageId: string;
 	vulnerabilityId: string;
 	pkgName: string;
 	installedVersion: string;
 	reason?: string;
-	createdBy: string;
+	expiresAt?: string;
+	userId: string;
+	username: string;
 	createdAt: string;
 }
+
+export interface LicenseUsage {
+	name: string;
+	category?: string;
+	denied: boolean;
+	packages: string[];
+}
+
+export interface ImageLicenseReport {
+	imageId: string;
+	imageName: string;
+	scanTime: string;
+	licenses: LicenseUsage[];
+	deniedCount: number;
+}
+
+export interface LicenseSummary {
+	name: string;
+	category?: string;
+	denied: boolean;
+	images: string[];
+	packageCount: number;
+}
+
+export interface EnvironmentLicenseReport {
+	scannedImages: number;
+	deniedImages: number;
+	denylist: string[];
+	licenses: LicenseSummary[];
+}
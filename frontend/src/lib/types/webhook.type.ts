@@ -0,0 +1,32 @@
+export type WebhookSourceType = 'generic' | 'harbor' | 'github';
+export type WebhookAction = 'none' | 'redeploy';
+export type WebhookEventKind = 'push' | 'build' | 'other';
+
+export interface WebhookSourceRequest {
+	name: string;
+	type: WebhookSourceType;
+	action?: WebhookAction;
+	projectId?: string;
+	imageFilter?: string;
+	enabled?: boolean;
+}
+
+export interface WebhookSource {
+	id: string;
+	name: string;
+	type: WebhookSourceType;
+	tokenPrefix: string;
+	action: WebhookAction;
+	projectId?: string;
+	imageFilter?: string;
+	enabled: boolean;
+	lastEvent?: WebhookEventKind;
+	lastReceivedAt?: string;
+	updatedBy: string;
+	createdAt: string;
+	updatedAt?: string;
+}
+
+export interface WebhookSourceWithToken extends WebhookSource {
+	token: string;
+}
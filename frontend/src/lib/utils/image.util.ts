@@ -37,6 +37,13 @@ export function getDefaultProfilePicture(): string {
 	return getCachedImageUrl('/api/app-images/profile');
 }
 
+// Remote icons are fetched through the backend so the browser never contacts
+// the icon host directly. Relative and data URLs are returned unchanged.
+export function getProxiedIconUrl(src: string): string {
+	if (!/^https?:\/\//i.test(src)) return src;
+	return `/api/icons?url=${encodeURIComponent(src)}`;
+}
+
 function getCachedImageUrl(url: string) {
 	const skipCacheUntil = getSkipCacheUntil(url);
 	const skipCache = skipCacheUntil > Date.now();
@@ -1,4 +1,7 @@
 import type { SystemStats } from '$lib/types/system-stats.type';
+import type { BackupProgress } from '$lib/types/file-browser.type';
+import type { ContainerProcessList } from '$lib/types/container.type';
+import type { ImageBuildProgress, ImageTransferProgress } from '$lib/types/image.type';
 
 export interface ReconnectWSOptions<T> {
 	buildUrl: () => string | Promise<string>;
@@ -10,10 +13,41 @@ export interface ReconnectWSOptions<T> {
 	maxBackoff?: number;
 	autoConnect?: boolean;
 	shouldReconnect?: () => boolean;
+	// Fall back to the server-sent events variant of the stream (the same
+	// URL under /sse/ instead of /ws/) when WebSocket connections cannot be
+	// opened, e.g. behind a proxy that does not pass them through.
+	sseFallback?: boolean;
+}
+
+const STREAM_TRANSPORT_KEY = 'arcane:stream-transport';
+// WebSocket handshakes that have to fail in a row before switching to SSE.
+const SSE_FALLBACK_AFTER = 2;
+
+function prefersSSE(): boolean {
+	try {
+		return sessionStorage.getItem(STREAM_TRANSPORT_KEY) === 'sse';
+	} catch {
+		return false;
+	}
+}
+
+function rememberSSE() {
+	try {
+		sessionStorage.setItem(STREAM_TRANSPORT_KEY, 'sse');
+	} catch {}
+}
+
+export function toSSEUrl(wsUrl: string): string {
+	const url = new URL(wsUrl, location.href);
+	url.protocol = url.protocol === 'wss:' ? 'https:' : 'http:';
+	url.pathname = url.pathname.replace('/ws/', '/sse/');
+	return url.toString();
 }
 
 export class ReconnectingWebSocket<T = unknown> {
 	private ws: WebSocket | null = null;
+	private es: EventSource | null = null;
+	private failedHandshakes = 0;
 	private closed = true;
 	private attempt = 0;
 	private readonly maxBackoff: number;
@@ -28,7 +62,7 @@ export class ReconnectingWebSocket<T = unknown> {
 	}
 
 	async connect() {
-		if (this.ws && !this.closed) {
+		if ((this.ws || this.es) && !this.closed) {
 			this.close();
 		}
 		this.closed = false;
@@ -45,6 +79,10 @@ export class ReconnectingWebSocket<T = unknown> {
 			} catch {}
 			this.ws = null;
 		}
+		if (this.es) {
+			this.es.close();
+			this.es = null;
+		}
 
 		this.connecting = true;
 		let url: string;
@@ -57,7 +95,13 @@ export class ReconnectingWebSocket<T = unknown> {
 			return;
 		}
 
+		if (this.opts.sseFallback && prefersSSE()) {
+			this.connectSSE(url);
+			return;
+		}
+
 		let socket: WebSocket;
+		let opened = false;
 		try {
 			socket = new WebSocket(url);
 		} catch (err) {
@@ -71,6 +115,8 @@ export class ReconnectingWebSocket<T = unknown> {
 
 		socket.onopen = () => {
 			if (socket !== this.ws) return;
+			opened = true;
+			this.failedHandshakes = 0;
 			this.attempt = 0;
 			this.connecting = false;
 			this.opts.onOpen?.();
@@ -102,12 +148,59 @@ export class ReconnectingWebSocket<T = unknown> {
 			this.opts.onClose?.();
 			this.ws = null;
 			this.connecting = false;
+			if (!opened && this.opts.sseFallback && ++this.failedHandshakes >= SSE_FALLBACK_AFTER) {
+				rememberSSE();
+				this.attempt = 0;
+			}
 			if (!this.closed) this.scheduleReconnect();
 		};
 
 		return;
 	}
 
+	private connectSSE(wsUrl: string) {
+		let source: EventSource;
+		try {
+			source = new EventSource(toSSEUrl(wsUrl), { withCredentials: true });
+		} catch (err) {
+			this.connecting = false;
+			this.scheduleReconnect();
+			this.opts.onError?.(err as Error);
+			return;
+		}
+
+		this.es = source;
+
+		source.onopen = () => {
+			if (source !== this.es) return;
+			this.attempt = 0;
+			this.connecting = false;
+			this.opts.onOpen?.();
+		};
+
+		source.onmessage = (evt) => {
+			if (source !== this.es) return;
+			try {
+				const parser = this.opts.parseMessage ?? ((e: MessageEvent) => JSON.parse(e.data) as unknown as T);
+				this.opts.onMessage?.(parser(evt));
+			} catch (err) {
+				this.opts.onError?.(err as Error);
+			}
+		};
+
+		// EventSource retries on its own; close it and use the same backoff
+		// as WebSocket reconnects instead.
+		source.onerror = (e) => {
+			if (source !== this.es) return;
+			this.opts.onError?.(e);
+			source.close();
+			this.es = null;
+			this.connecting = false;
+			this.opts.onClose?.();
+			if (!this.closed) this.scheduleReconnect();
+		};
+	}
+
 	private scheduleReconnect() {
 		if (this.opts.shouldReconnect && !this.opts.shouldReconnect()) {
 			return;
@@ -152,6 +245,8 @@ export class ReconnectingWebSocket<T = unknown> {
 		try {
 			this.ws?.close();
 		} catch {}
+		this.es?.close();
+		this.es = null;
 		this.connecting = false;
 	}
 
@@ -164,6 +259,11 @@ export class ReconnectingWebSocket<T = unknown> {
 			this.reconnectTimer = null;
 		}
 
+		if (this.es) {
+			this.es.close();
+			this.es = null;
+		}
+
 		const socket = this.ws;
 		if (!socket || socket.readyState === WebSocket.CLOSED) {
 			this.ws = null;
@@ -198,6 +298,7 @@ export class ReconnectingWebSocket<T = unknown> {
 	}
 
 	isConnected() {
+		if (this.es) return this.es.readyState === EventSource.OPEN;
 		return !!this.ws && this.ws.readyState === WebSocket.OPEN;
 	}
 }
@@ -255,3 +356,116 @@ export function createContainerStatsWebSocket(opts: {
 		shouldReconnect: opts.shouldReconnect
 	});
 }
+
+export function createContainerProcessesWebSocket(opts: {
+	getEnvId: () => string;
+	containerId: string;
+	interval?: number;
+	psArgs?: string;
+	onMessage: (data: ContainerProcessList | { error: string }) => void;
+	onOpen?: () => void;
+	onClose?: () => void;
+	onError?: (err: Event | Error) => void;
+	maxBackoff?: number;
+	shouldReconnect?: () => boolean;
+}) {
+	const buildUrl = () => {
+		const envId = opts.getEnvId() || '0';
+		const protocol = location.protocol === 'https:' ? 'wss' : 'ws';
+		const params = new URLSearchParams();
+		if (opts.interval) params.set('interval', String(opts.interval));
+		if (opts.psArgs) params.set('psArgs', opts.psArgs);
+		const query = params.toString();
+		return `${protocol}://${location.host}/api/environments/${envId}/ws/containers/${opts.containerId}/top${query ? `?${query}` : ''}`;
+	};
+
+	return new ReconnectingWebSocket<ContainerProcessList | { error: string }>({
+		buildUrl,
+		parseMessage: (evt) => JSON.parse(evt.data as string),
+		onMessage: opts.onMessage,
+		onOpen: opts.onOpen,
+		onClose: opts.onClose,
+		onError: opts.onError,
+		maxBackoff: opts.maxBackoff,
+		autoConnect: false,
+		shouldReconnect: opts.shouldReconnect
+	});
+}
+
+export function createBackupProgressWebSocket(opts: {
+	getEnvId: () => string;
+	onMessage: (data: BackupProgress) => void;
+	onOpen?: () => void;
+	onClose?: () => void;
+	onError?: (err: Event | Error) => void;
+	maxBackoff?: number;
+}) {
+	const buildUrl = () => {
+		const envId = opts.getEnvId() || '0';
+		const protocol = location.protocol === 'https:' ? 'wss' : 'ws';
+		return `${protocol}://${location.host}/api/environments/${envId}/ws/volumes/backups/progress`;
+	};
+
+	return new ReconnectingWebSocket<BackupProgress>({
+		buildUrl,
+		parseMessage: (evt) => JSON.parse(evt.data as string) as BackupProgress,
+		onMessage: opts.onMessage,
+		onOpen: opts.onOpen,
+		onClose: opts.onClose,
+		onError: opts.onError,
+		maxBackoff: opts.maxBackoff,
+		sseFallback: true
+	});
+}
+
+export function createImageBuildProgressWebSocket(opts: {
+	getEnvId: () => string;
+	onMessage: (data: ImageBuildProgress) => void;
+	onOpen?: () => void;
+	onClose?: () => void;
+	onError?: (err: Event | Error) => void;
+	maxBackoff?: number;
+}) {
+	const buildUrl = () => {
+		const envId = opts.getEnvId() || '0';
+		const protocol = location.protocol === 'https:' ? 'wss' : 'ws';
+		return `${protocol}://${location.host}/api/environments/${envId}/ws/images/build/progress`;
+	};
+
+	return new ReconnectingWebSocket<ImageBuildProgress>({
+		buildUrl,
+		parseMessage: (evt) => JSON.parse(evt.data as string) as ImageBuildProgress,
+		onMessage: opts.onMessage,
+		onOpen: opts.onOpen,
+		onClose: opts.onClose,
+		onError: opts.onError,
+		maxBackoff: opts.maxBackoff,
+		sseFallback: true
+	});
+}
+
+export function createImageTransferProgressWebSocket(opts: {
+	getEnvId: () => string;
+	onMessage: (data: ImageTransferProgress) => void;
+	onOpen?: () => void;
+	onClose?: () => void;
+	onError?: (err: Event | Error) => void;
+	maxBackoff?: number;
+}) {
+	const buildUrl = () => {
+		const envId = opts.getEnvId() || '0';
+		const protocol = location.protocol === 'https:' ? 'wss' : 'ws';
+		return `${protocol}://${location.host}/api/environments/${envId}/ws/images/transfer/progress`;
+	};
+
+	return new ReconnectingWebSocket<ImageTransferProgress>({
+		buildUrl,
+		parseMessage: (evt) => JSON.parse(evt.data as string) as ImageTransferProgress,
+		onMessage: opts.onMessage,
+		onOpen: opts.onOpen,
+		onClose: opts.onClose,
+		onError: opts.onError,
+		maxBackoff: opts.maxBackoff,
+		sseFallback: true
+	});
+}
@@ -20,6 +20,9 @@ export function parseImageRef(imageRef: string): { repo: string; tag: string } {
 }
 
 export function getContainerDisplayName(container: ContainerSummaryDto): string {
+	if (container.displayName) {
+		return container.displayName;
+	}
 	if (container.names && container.names.length > 0) {
 		return container.names[0].replace(/^\//, '');
 	}
@@ -52,6 +55,11 @@ export function getContainerIpAddress(container: ContainerSummaryDto): string |
 		const network = networks[networkName];
 		if (network?.ipAddress) return network.ipAddress;
 	}
+	// IPv6-only networks have no IPv4 address; fall back to the global IPv6 one.
+	for (const networkName in networks) {
+		const network = networks[networkName];
+		if (network?.globalIPv6Address) return network.globalIPv6Address;
+	}
 	return null;
 }
 
@@ -0,0 +1,105 @@
+package approval
+
+import "time"
+
+// HeaderApprovalID is the request header carrying an approved approval
+// request ID when re-submitting an action that requires approval.
+const HeaderApprovalID = "X-Arcane-Approval-Id"
+
+// Request is a request to run a destructive action that needs a second
+// admin's approval.
+type Request struct {
+	// ID of the approval request.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Action is the kind of action, e.g. "prune" or "volume_restore".
+	//
+	// Required: true
+	Action string `json:"action"`
+
+	// EnvironmentID is the environment the action targets.
+	//
+	// Required: true
+	EnvironmentID string `json:"environmentId"`
+
+	// Method is the HTTP method of the gated request.
+	//
+	// Required: true
+	Method string `json:"method"`
+
+	// Path is the API path of the gated request.
+	//
+	// Required: true
+	Path string `json:"path"`
+
+	// Query is the raw query string of the gated request.
+	//
+	// Required: false
+	Query string `json:"query,omitempty"`
+
+	// Body is the body of the gated request, so approvers see the exact
+	// options they approve.
+	//
+	// Required: false
+	Body string `json:"body,omitempty"`
+
+	// Status is one of pending, approved, rejected, expired or consumed.
+	//
+	// Required: true
+	Status string `json:"status"`
+
+	// RequestedBy is the ID of the user who requested the action.
+	//
+	// Required: true
+	RequestedBy string `json:"requestedBy"`
+
+	// RequestedByName is the username of the requester.
+	//
+	// Required: true
+	RequestedByName string `json:"requestedByName"`
+
+	// DecidedBy is the ID of the admin who approved or rejected the request.
+	//
+	// Required: false
+	DecidedBy *string `json:"decidedBy,omitempty"`
+
+	// DecidedByName is the username of the deciding admin.
+	//
+	// Required: false
+	DecidedByName *string `json:"decidedByName,omitempty"`
+
+	// DecidedAt is when the request was approved or rejected.
+	//
+	// Required: false
+	DecidedAt *time.Time `json:"decidedAt,omitempty"`
+
+	// Reason is an optional comment given with the decision.
+	//
+	// Required: false
+	Reason *string `json:"reason,omitempty"`
+
+	// ExpiresAt is the deadline for approving or executing the request.
+	//
+	// Required: true
+	ExpiresAt time.Time `json:"expiresAt"`
+
+	// ConsumedAt is when the approved action was executed.
+	//
+	// Required: false
+	ConsumedAt *time.Time `json:"consumedAt,omitempty"`
+
+	// CreatedAt is when the request was created.
+	//
+	// Required: true
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// Decision is the body for approving or rejecting a request.
+type Decision struct {
+	// Reason is an optional comment recorded with the decision.
+	//
+	// Required: false
+	Reason *string `json:"reason,omitempty"`
+}
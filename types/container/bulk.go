@@ -0,0 +1,79 @@
+package container
+
+// BulkActionRequest is the request body for running the same action on
+// several containers at once.
+type BulkActionRequest struct {
+	// Action is the action to run on every container.
+	//
+	// Required: true
+	Action string `json:"action" enum:"start,stop,restart,delete"`
+
+	// ContainerIDs are the containers to act on, by ID or name.
+	//
+	// Required: true
+	ContainerIDs []string `json:"containerIds" minItems:"1" maxItems:"200"`
+
+	// Force removes running containers. Only used by delete.
+	//
+	// Required: false
+	Force bool `json:"force,omitempty"`
+
+	// RemoveVolumes removes the named volumes of deleted containers. Only
+	// used by delete.
+	//
+	// Required: false
+	RemoveVolumes bool `json:"removeVolumes,omitempty"`
+
+	// Timeout is how many seconds to wait before killing a container. Only
+	// used by stop and restart; 0 uses the container's own stop timeout.
+	//
+	// Required: false
+	Timeout int `json:"timeout,omitempty" minimum:"0" maximum:"3600"`
+
+	// Concurrency is how many containers are acted on at the same time.
+	// Defaults to 5.
+	//
+	// Required: false
+	Concurrency int `json:"concurrency,omitempty" minimum:"0" maximum:"20"`
+}
+
+// BulkActionItem is the outcome of a bulk action for one container.
+type BulkActionItem struct {
+	// ContainerID is the container as given in the request.
+	//
+	// Required: true
+	ContainerID string `json:"containerId"`
+
+	// Success reports whether the action succeeded.
+	//
+	// Required: true
+	Success bool `json:"success"`
+
+	// Error describes why the action failed.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+}
+
+// BulkActionResult is the outcome of a bulk action.
+type BulkActionResult struct {
+	// Action is the action that was run.
+	//
+	// Required: true
+	Action string `json:"action"`
+
+	// Succeeded is the number of containers the action succeeded on.
+	//
+	// Required: true
+	Succeeded int `json:"succeeded"`
+
+	// Failed is the number of containers the action failed on.
+	//
+	// Required: true
+	Failed int `json:"failed"`
+
+	// Results holds one entry per container, in request order.
+	//
+	// Required: true
+	Results []BulkActionItem `json:"results"`
+}
@@ -0,0 +1,50 @@
+package container
+
+// CheckpointSupport reports whether the Docker daemon can checkpoint and
+// restore containers. Checkpointing needs an experimental daemon on Linux
+// with CRIU installed.
+type CheckpointSupport struct {
+	// Supported is true when the daemon exposes the checkpoint API. Whether
+	// CRIU is installed only shows when a checkpoint is created.
+	//
+	// Required: true
+	Supported bool `json:"supported"`
+
+	// Experimental is true when the daemon runs with experimental features.
+	//
+	// Required: true
+	Experimental bool `json:"experimental"`
+
+	// OSType is the operating system of the daemon.
+	//
+	// Required: true
+	OSType string `json:"osType"`
+
+	// Reason explains why checkpointing is not supported.
+	//
+	// Required: false
+	Reason string `json:"reason,omitempty"`
+}
+
+// Checkpoint is a saved CRIU checkpoint of a container.
+type Checkpoint struct {
+	// Name of the checkpoint.
+	//
+	// Required: true
+	Name string `json:"name"`
+}
+
+// CheckpointCreateRequest is the request body for checkpointing a container.
+type CheckpointCreateRequest struct {
+	// Name of the checkpoint. Defaults to "checkpoint-" followed by the
+	// current time.
+	//
+	// Required: false
+	Name string `json:"name,omitempty" maxLength:"128"`
+
+	// LeaveRunning keeps the container running after the checkpoint is
+	// taken. By default the container stops, ready to be restored.
+	//
+	// Required: false
+	LeaveRunning bool `json:"leaveRunning,omitempty"`
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/docker/docker/api/types/network"
 	containerregistry "github.com/getarcaneapp/arcane/types/containerregistry"
 	imagetypes "github.com/getarcaneapp/arcane/types/image"
+	uptimetypes "github.com/getarcaneapp/arcane/types/uptime"
 )
 
 // RestartPolicyCreate represents restart policy options for container creation.
@@ -97,6 +98,24 @@ type HostConfigCreate struct {
 	//
 	// Required: false
 	PublishAllPorts *bool `json:"publishAllPorts,omitempty"`
+
+	// DNS is a list of DNS servers. When empty, the environment's default
+	// DNS servers are used.
+	//
+	// Required: false
+	DNS []string `json:"dns,omitempty"`
+
+	// DNSSearch is a list of DNS search domains. When empty, the
+	// environment's default search domains are used.
+	//
+	// Required: false
+	DNSSearch []string `json:"dnsSearch,omitempty"`
+
+	// ExtraHosts adds "hostname:IP" entries to /etc/hosts, on top of the
+	// environment's default extra hosts.
+	//
+	// Required: false
+	ExtraHosts []string `json:"extraHosts,omitempty"`
 }
 
 // EndpointSettingsCreate represents network endpoint settings for container creation.
@@ -497,6 +516,25 @@ type State struct {
 	//
 	// Required: false
 	FinishedAt string `json:"finishedAt,omitempty"`
+
+	// Health is the state of the container's Docker healthcheck, if it has
+	// one.
+	//
+	// Required: false
+	Health *HealthState `json:"health,omitempty"`
+}
+
+// HealthState is the state of a container's Docker healthcheck.
+type HealthState struct {
+	// Status is starting, healthy or unhealthy.
+	//
+	// Required: true
+	Status string `json:"status"`
+
+	// FailingStreak is the number of consecutive failed checks.
+	//
+	// Required: false
+	FailingStreak int `json:"failingStreak,omitempty"`
 }
 
 // Config represents configuration details for a container.
@@ -558,6 +596,21 @@ type HostConfig struct {
 	//
 	// Required: false
 	Memory int64 `json:"memory,omitempty"`
+
+	// DNS is the list of DNS servers.
+	//
+	// Required: false
+	DNS []string `json:"dns,omitempty"`
+
+	// DNSSearch is the list of DNS search domains.
+	//
+	// Required: false
+	DNSSearch []string `json:"dnsSearch,omitempty"`
+
+	// ExtraHosts are the extra /etc/hosts entries.
+	//
+	// Required: false
+	ExtraHosts []string `json:"extraHosts,omitempty"`
 }
 
 // Summary represents a container summary.
@@ -631,6 +684,28 @@ type Summary struct {
 	//
 	// Required: false
 	UpdateInfo *imagetypes.UpdateInfo `json:"updateInfo,omitempty"`
+
+	// DisplayName is the display name override set for this container, if any.
+	//
+	// Required: false
+	DisplayName string `json:"displayName,omitempty"`
+
+	// IconURL is the icon override set for this container, if any.
+	//
+	// Required: false
+	IconURL string `json:"iconUrl,omitempty"`
+
+	// Health is the status of the Arcane-managed healthcheck for this
+	// container, if one is defined: starting, healthy or unhealthy.
+	//
+	// Required: false
+	Health string `json:"health,omitempty"`
+
+	// Uptime is the availability of the container over the last 24 hours,
+	// 7 days and 30 days, tracked by container name.
+	//
+	// Required: false
+	Uptime *uptimetypes.Stats `json:"uptime,omitempty"`
 }
 
 // Details represents detailed container information.
@@ -840,6 +915,9 @@ func NewDetails(c *container.InspectResponse) Details {
 			AutoRemove:    c.HostConfig.AutoRemove,
 			NanoCPUs:      c.HostConfig.NanoCPUs,
 			Memory:        c.HostConfig.Memory,
+			DNS:           c.HostConfig.DNS,
+			DNSSearch:     c.HostConfig.DNSSearch,
+			ExtraHosts:    c.HostConfig.ExtraHosts,
 		}
 	}
 
@@ -873,6 +951,9 @@ func NewDetails(c *container.InspectResponse) Details {
 			StartedAt:  c.State.StartedAt,
 			FinishedAt: c.State.FinishedAt,
 		}
+		if c.State.Health != nil {
+			state.Health = &HealthState{Status: c.State.Health.Status, FailingStreak: c.State.Health.FailingStreak}
+		}
 	}
 
 	return Details{
@@ -0,0 +1,52 @@
+package container
+
+import "github.com/getarcaneapp/arcane/types/diff"
+
+// DiffSubject identifies one side of a container diff.
+type DiffSubject struct {
+	// ID is the ID of the container.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Name is the name of the container.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Image is the image reference the container was created from.
+	//
+	// Required: true
+	Image string `json:"image"`
+
+	// ImageID is the ID of the image the container runs.
+	//
+	// Required: true
+	ImageID string `json:"imageId"`
+}
+
+// Diff lists the configuration differences between two containers, for
+// example the old and new container of a service after an update.
+type Diff struct {
+	// Left is the container the changes are relative to.
+	//
+	// Required: true
+	Left DiffSubject `json:"left"`
+
+	// Right is the container compared against Left.
+	//
+	// Required: true
+	Right DiffSubject `json:"right"`
+
+	// Identical is true when no configuration differences were found.
+	//
+	// Required: true
+	Identical bool `json:"identical"`
+
+	// Changes are the differences, sorted by path. Environment variables,
+	// labels, ports, mounts and networks are keyed by name, so reordering
+	// them is not reported as a change.
+	//
+	// Required: true
+	Changes []diff.Change `json:"changes"`
+}
@@ -0,0 +1,109 @@
+package container
+
+import "time"
+
+// DigestStatus is the digest a container was deployed from and whether its
+// tag has since moved to a different digest in the registry.
+type DigestStatus struct {
+	// ContainerName is the name of the container. Records are keyed by name
+	// so they survive the container being recreated.
+	//
+	// Required: true
+	ContainerName string `json:"containerName"`
+
+	// ContainerID is the ID of the container when the record was last
+	// refreshed.
+	//
+	// Required: true
+	ContainerID string `json:"containerId"`
+
+	// ImageRef is the image reference the container was created from.
+	//
+	// Required: true
+	ImageRef string `json:"imageRef"`
+
+	// ImageID is the ID of the image the container runs.
+	//
+	// Required: true
+	ImageID string `json:"imageId"`
+
+	// Digest is the registry digest of the image the container runs. Empty
+	// for images that were built locally and never pushed or pulled.
+	//
+	// Required: false
+	Digest string `json:"digest,omitempty"`
+
+	// RemoteDigest is the digest the tag pointed to at the last drift check.
+	//
+	// Required: false
+	RemoteDigest string `json:"remoteDigest,omitempty"`
+
+	// Drifted is true when the tag now points to a different digest than the
+	// one the container runs.
+	//
+	// Required: true
+	Drifted bool `json:"drifted"`
+
+	// DriftCheckedAt is when the tag was last checked.
+	//
+	// Required: false
+	DriftCheckedAt *time.Time `json:"driftCheckedAt,omitempty"`
+
+	// LastError is why the last drift check failed, if it did.
+	//
+	// Required: false
+	LastError string `json:"lastError,omitempty"`
+
+	// Pinned is true when auto-update must leave the container on its
+	// current digest.
+	//
+	// Required: true
+	Pinned bool `json:"pinned"`
+
+	// PinReason is the optional note explaining why the container is pinned.
+	//
+	// Required: false
+	PinReason string `json:"pinReason,omitempty"`
+
+	// PinnedBy is the username that pinned the container.
+	//
+	// Required: false
+	PinnedBy string `json:"pinnedBy,omitempty"`
+
+	// RecordedAt is when the deployed digest was recorded.
+	//
+	// Required: true
+	RecordedAt time.Time `json:"recordedAt"`
+}
+
+// DigestPinRequest is the request body for pinning a container to the
+// digest it runs.
+type DigestPinRequest struct {
+	// Reason is an optional note explaining why the container is pinned.
+	//
+	// Required: false
+	Reason string `json:"reason,omitempty" maxLength:"500"`
+}
+
+// DriftCheckResult summarises a tag drift check over all containers.
+type DriftCheckResult struct {
+	// Checked is the number of containers whose tag was looked up.
+	//
+	// Required: true
+	Checked int `json:"checked"`
+
+	// Drifted is the number of containers whose tag has moved.
+	//
+	// Required: true
+	Drifted int `json:"drifted"`
+
+	// Failed is the number of containers whose tag could not be looked up.
+	//
+	// Required: true
+	Failed int `json:"failed"`
+
+	// Items holds the status of every tracked container.
+	//
+	// Required: true
+	Items []DigestStatus `json:"items"`
+}
@@ -0,0 +1,14 @@
+package container
+
+// ExecResizeRequest is the terminal size of an exec session.
+type ExecResizeRequest struct {
+	// Rows is the number of terminal rows.
+	//
+	// Required: true
+	Rows uint `json:"rows" minimum:"1" maximum:"1000"`
+
+	// Cols is the number of terminal columns.
+	//
+	// Required: true
+	Cols uint `json:"cols" minimum:"1" maximum:"1000"`
+}
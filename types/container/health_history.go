@@ -0,0 +1,45 @@
+package container
+
+import "time"
+
+// HealthTransition is a change of a container's health status.
+type HealthTransition struct {
+	// ContainerID is the ID of the container at the time of the change.
+	//
+	// Required: true
+	ContainerID string `json:"containerId"`
+
+	// Status is the new health status: starting, healthy or unhealthy.
+	//
+	// Required: true
+	Status string `json:"status"`
+
+	// At is when the status changed.
+	//
+	// Required: true
+	At time.Time `json:"at"`
+}
+
+// HealthHistory is the recorded health history of a container.
+type HealthHistory struct {
+	// ContainerName is the name the history is recorded under.
+	//
+	// Required: true
+	ContainerName string `json:"containerName"`
+
+	// Transitions are the recorded status changes, newest first.
+	//
+	// Required: true
+	Transitions []HealthTransition `json:"transitions"`
+
+	// TransitionsLastHour is the number of status changes in the last hour.
+	//
+	// Required: true
+	TransitionsLastHour int `json:"transitionsLastHour"`
+
+	// Flapping reports whether TransitionsLastHour is above the configured
+	// flap threshold.
+	//
+	// Required: true
+	Flapping bool `json:"flapping"`
+}
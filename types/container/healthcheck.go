@@ -0,0 +1,134 @@
+package container
+
+import "time"
+
+// HealthcheckRequest is the request body for defining a healthcheck that
+// Arcane runs against a container, for images that do not ship one.
+type HealthcheckRequest struct {
+	// Type is the kind of probe. http sends a GET request to the container,
+	// tcp opens a connection and command runs a command inside it.
+	//
+	// Required: true
+	Type string `json:"type" enum:"http,tcp,command"`
+
+	// Port is the container port probed by http and tcp checks.
+	//
+	// Required: false
+	Port int `json:"port,omitempty" minimum:"0" maximum:"65535"`
+
+	// Path is the request path of http checks. Defaults to "/".
+	//
+	// Required: false
+	Path string `json:"path,omitempty" maxLength:"2048"`
+
+	// Command is run inside the container by command checks. Exit code 0
+	// means healthy.
+	//
+	// Required: false
+	Command []string `json:"command,omitempty"`
+
+	// IntervalSeconds is the time between probes. Defaults to 30.
+	//
+	// Required: false
+	IntervalSeconds int `json:"intervalSeconds,omitempty" minimum:"0" maximum:"86400"`
+
+	// TimeoutSeconds is how long a probe may take. Defaults to 5.
+	//
+	// Required: false
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty" minimum:"0" maximum:"300"`
+
+	// Retries is the number of consecutive failures before the container is
+	// unhealthy. Defaults to 3.
+	//
+	// Required: false
+	Retries int `json:"retries,omitempty" minimum:"0" maximum:"100"`
+
+	// AutoRestart restarts the container when it becomes unhealthy.
+	//
+	// Required: false
+	AutoRestart bool `json:"autoRestart,omitempty"`
+}
+
+// Healthcheck describes an Arcane-managed healthcheck and its last result.
+type Healthcheck struct {
+	// ContainerKey is the container name or ID the healthcheck applies to.
+	//
+	// Required: true
+	ContainerKey string `json:"containerKey"`
+
+	// Type is the kind of probe: http, tcp or command.
+	//
+	// Required: true
+	Type string `json:"type"`
+
+	// Port is the container port probed by http and tcp checks.
+	//
+	// Required: false
+	Port int `json:"port,omitempty"`
+
+	// Path is the request path of http checks.
+	//
+	// Required: false
+	Path string `json:"path,omitempty"`
+
+	// Command is run inside the container by command checks.
+	//
+	// Required: false
+	Command []string `json:"command,omitempty"`
+
+	// IntervalSeconds is the time between probes.
+	//
+	// Required: true
+	IntervalSeconds int `json:"intervalSeconds"`
+
+	// TimeoutSeconds is how long a probe may take.
+	//
+	// Required: true
+	TimeoutSeconds int `json:"timeoutSeconds"`
+
+	// Retries is the number of consecutive failures before the container is
+	// unhealthy.
+	//
+	// Required: true
+	Retries int `json:"retries"`
+
+	// AutoRestart restarts the container when it becomes unhealthy.
+	//
+	// Required: true
+	AutoRestart bool `json:"autoRestart"`
+
+	// Status is starting, healthy or unhealthy.
+	//
+	// Required: true
+	Status string `json:"status"`
+
+	// FailingStreak is the number of consecutive failed probes.
+	//
+	// Required: true
+	FailingStreak int `json:"failingStreak"`
+
+	// LastOutput is the result of the last probe.
+	//
+	// Required: false
+	LastOutput string `json:"lastOutput,omitempty"`
+
+	// LastCheckedAt is when the container was last probed.
+	//
+	// Required: false
+	LastCheckedAt *time.Time `json:"lastCheckedAt,omitempty"`
+
+	// UpdatedBy is the username that last changed the healthcheck.
+	//
+	// Required: true
+	UpdatedBy string `json:"updatedBy"`
+
+	// CreatedAt is when the healthcheck was created.
+	//
+	// Required: true
+	CreatedAt time.Time `json:"createdAt"`
+
+	// UpdatedAt is when the healthcheck was last changed.
+	//
+	// Required: false
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
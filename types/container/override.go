@@ -0,0 +1,50 @@
+package container
+
+import "time"
+
+// OverrideRequest is the request body for setting a container's display
+// overrides. Empty fields clear the corresponding override.
+type OverrideRequest struct {
+	// DisplayName replaces the container name in the UI.
+	//
+	// Required: false
+	DisplayName string `json:"displayName,omitempty" maxLength:"255"`
+
+	// IconURL is an http(s) URL of the icon shown for the container.
+	//
+	// Required: false
+	IconURL string `json:"iconUrl,omitempty" maxLength:"2048"`
+}
+
+// Override describes the display overrides stored for a container.
+type Override struct {
+	// ContainerKey is the container name or ID the override applies to.
+	//
+	// Required: true
+	ContainerKey string `json:"containerKey"`
+
+	// DisplayName replaces the container name in the UI.
+	//
+	// Required: false
+	DisplayName string `json:"displayName,omitempty"`
+
+	// IconURL is the URL of the icon shown for the container.
+	//
+	// Required: false
+	IconURL string `json:"iconUrl,omitempty"`
+
+	// UpdatedBy is the username that last changed the override.
+	//
+	// Required: true
+	UpdatedBy string `json:"updatedBy"`
+
+	// CreatedAt is when the override was created.
+	//
+	// Required: true
+	CreatedAt time.Time `json:"createdAt"`
+
+	// UpdatedAt is when the override was last changed.
+	//
+	// Required: false
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
@@ -0,0 +1,27 @@
+package container
+
+import "time"
+
+// ProcessList is the process table of a running container, as reported by
+// ps on the Docker host.
+type ProcessList struct {
+	// ContainerID is the ID of the container.
+	//
+	// Required: true
+	ContainerID string `json:"containerId"`
+
+	// Titles are the column names of the process table, such as PID and CMD.
+	//
+	// Required: true
+	Titles []string `json:"titles"`
+
+	// Processes holds one row per process, in the same order as Titles.
+	//
+	// Required: true
+	Processes [][]string `json:"processes"`
+
+	// Timestamp is when the process table was read.
+	//
+	// Required: true
+	Timestamp time.Time `json:"timestamp"`
+}
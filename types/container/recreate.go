@@ -0,0 +1,68 @@
+package container
+
+// RecreateRequest is the request body for recreating a container with a
+// changed configuration. Omitted fields keep the container's current value.
+type RecreateRequest struct {
+	// Image replaces the image reference, for example "nginx:1.27".
+	//
+	// Required: false
+	Image *string `json:"image,omitempty" minLength:"1"`
+
+	// Tag replaces only the tag of the current image, keeping its repository.
+	// It cannot be combined with Image.
+	//
+	// Required: false
+	Tag *string `json:"tag,omitempty" minLength:"1" maxLength:"128"`
+
+	// Env sets environment variables by name. A null value removes the
+	// variable.
+	//
+	// Required: false
+	Env map[string]*string `json:"env,omitempty"`
+
+	// RestartPolicy replaces the restart policy.
+	//
+	// Required: false
+	RestartPolicy *string `json:"restartPolicy,omitempty" enum:"no,always,unless-stopped,on-failure"`
+
+	// MaximumRetryCount limits restarts when RestartPolicy is on-failure.
+	//
+	// Required: false
+	MaximumRetryCount *int `json:"maximumRetryCount,omitempty" minimum:"0"`
+
+	// PortBindings replaces all published ports, keyed by container port
+	// such as "80/tcp". An empty object removes every published port.
+	//
+	// Required: false
+	PortBindings map[string][]PortBindingCreate `json:"portBindings,omitempty"`
+
+	// Labels sets labels by key. A null value removes the label.
+	//
+	// Required: false
+	Labels map[string]*string `json:"labels,omitempty"`
+
+	// DNS replaces the DNS servers. An empty list removes them.
+	//
+	// Required: false
+	DNS []string `json:"dns,omitempty"`
+
+	// DNSSearch replaces the DNS search domains. An empty list removes them.
+	//
+	// Required: false
+	DNSSearch []string `json:"dnsSearch,omitempty"`
+
+	// ExtraHosts replaces the extra /etc/hosts entries, each
+	// "hostname:IP". An empty list removes them.
+	//
+	// Required: false
+	ExtraHosts []string `json:"extraHosts,omitempty"`
+}
+
+// LabelsRequest is the request body for replacing a container's labels.
+type LabelsRequest struct {
+	// Labels is the complete new label set. Labels not listed are removed,
+	// except those baked into the image, which Docker always applies.
+	//
+	// Required: true
+	Labels map[string]string `json:"labels"`
+}
@@ -0,0 +1,10 @@
+package container
+
+// RenameRequest is the request body for renaming a container.
+type RenameRequest struct {
+	// Name is the new container name. It must follow Docker's naming rules:
+	// letters, digits, '_', '.' and '-', starting with a letter or digit.
+	//
+	// Required: true
+	Name string `json:"name" minLength:"2" maxLength:"255"`
+}
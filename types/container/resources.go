@@ -0,0 +1,78 @@
+package container
+
+// ResourcesUpdate changes the resource limits and restart policy of a
+// container in place, without recreating it. Omitted fields are unchanged.
+type ResourcesUpdate struct {
+	// CPUShares is the relative CPU weight.
+	//
+	// Required: false
+	CPUShares *int64 `json:"cpuShares,omitempty" minimum:"2" doc:"Relative CPU weight"`
+
+	// MemoryBytes is the hard memory limit in bytes. Docker cannot remove a
+	// limit from a running container, so it must be at least 6 MB.
+	//
+	// Required: false
+	MemoryBytes *int64 `json:"memoryBytes,omitempty" minimum:"6291456" doc:"Hard memory limit in bytes"`
+
+	// MemoryReservationBytes is the soft memory limit in bytes. 0 removes it.
+	//
+	// Required: false
+	MemoryReservationBytes *int64 `json:"memoryReservationBytes,omitempty" minimum:"0" doc:"Soft memory limit in bytes"`
+
+	// RestartPolicy is the restart policy to apply.
+	//
+	// Required: false
+	RestartPolicy *string `json:"restartPolicy,omitempty" enum:"no,always,unless-stopped,on-failure" doc:"Restart policy to apply"`
+
+	// MaximumRetryCount is only used when RestartPolicy is on-failure.
+	//
+	// Required: false
+	MaximumRetryCount int `json:"maximumRetryCount,omitempty" minimum:"0" doc:"Maximum retries (on-failure only)"`
+}
+
+// ResourceSettings are the resource limits and restart policy of a
+// container.
+type ResourceSettings struct {
+	// CPUShares is the relative CPU weight. 0 means the Docker default.
+	//
+	// Required: true
+	CPUShares int64 `json:"cpuShares"`
+
+	// MemoryBytes is the hard memory limit in bytes. 0 means unlimited.
+	//
+	// Required: true
+	MemoryBytes int64 `json:"memoryBytes"`
+
+	// MemoryReservationBytes is the soft memory limit in bytes. 0 means none.
+	//
+	// Required: true
+	MemoryReservationBytes int64 `json:"memoryReservationBytes"`
+
+	// RestartPolicy is the restart policy.
+	//
+	// Required: true
+	RestartPolicy string `json:"restartPolicy"`
+
+	// MaximumRetryCount is the retry limit of the on-failure policy.
+	//
+	// Required: false
+	MaximumRetryCount int `json:"maximumRetryCount,omitempty"`
+}
+
+// ResourcesUpdateResult is the outcome of a live resource update.
+type ResourcesUpdateResult struct {
+	// Before holds the settings before the update.
+	//
+	// Required: true
+	Before ResourceSettings `json:"before"`
+
+	// After holds the settings after the update.
+	//
+	// Required: true
+	After ResourceSettings `json:"after"`
+
+	// Warnings reported by the Docker daemon.
+	//
+	// Required: false
+	Warnings []string `json:"warnings,omitempty"`
+}
@@ -0,0 +1,122 @@
+package container
+
+// RestartPolicyIssue identifies why a container was flagged by the restart policy audit.
+type RestartPolicyIssue string
+
+const (
+	// RestartPolicyIssueNone means the container will not be restarted after a reboot or crash.
+	RestartPolicyIssueNone RestartPolicyIssue = "no_restart"
+	// RestartPolicyIssueAlways means the container is restarted even after it was stopped manually.
+	RestartPolicyIssueAlways RestartPolicyIssue = "always"
+)
+
+// RestartPolicyAuditEntry describes a container flagged by the restart policy audit.
+type RestartPolicyAuditEntry struct {
+	// ID is the container ID.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Name is the container name.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Image is the image the container runs.
+	//
+	// Required: true
+	Image string `json:"image"`
+
+	// State is the current container state.
+	//
+	// Required: true
+	State string `json:"state"`
+
+	// RestartPolicy is the current restart policy name.
+	//
+	// Required: true
+	RestartPolicy string `json:"restartPolicy"`
+
+	// Project is the compose project the container belongs to, if any. Changes
+	// to project containers should also be made in the compose file.
+	//
+	// Required: false
+	Project string `json:"project,omitempty"`
+
+	// Issue describes why the container was flagged.
+	//
+	// Required: true
+	Issue RestartPolicyIssue `json:"issue"`
+
+	// Recommended is the suggested restart policy.
+	//
+	// Required: true
+	Recommended string `json:"recommended"`
+}
+
+// RestartPolicyAudit is the result of a restart policy audit.
+type RestartPolicyAudit struct {
+	// TotalContainers is the number of containers that were audited.
+	//
+	// Required: true
+	TotalContainers int `json:"totalContainers"`
+
+	// Flagged contains the containers with a questionable restart policy.
+	//
+	// Required: true
+	Flagged []RestartPolicyAuditEntry `json:"flagged"`
+}
+
+// RestartPolicyUpdate is used to change the restart policy of multiple containers.
+type RestartPolicyUpdate struct {
+	// ContainerIDs are the containers to update.
+	//
+	// Required: true
+	ContainerIDs []string `json:"containerIds" minItems:"1" doc:"IDs or names of the containers to update"`
+
+	// Policy is the restart policy to apply.
+	//
+	// Required: true
+	Policy string `json:"policy" enum:"no,always,unless-stopped,on-failure" doc:"Restart policy to apply"`
+
+	// MaximumRetryCount is only used when policy is on-failure.
+	//
+	// Required: false
+	MaximumRetryCount int `json:"maximumRetryCount,omitempty" minimum:"0" doc:"Maximum retries (on-failure only)"`
+}
+
+// RestartPolicyUpdateItem is the outcome of a restart policy change for one container.
+type RestartPolicyUpdateItem struct {
+	// ContainerID is the container the change was applied to.
+	//
+	// Required: true
+	ContainerID string `json:"containerId"`
+
+	// Success indicates whether the change succeeded.
+	//
+	// Required: true
+	Success bool `json:"success"`
+
+	// Error contains the failure reason when Success is false.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+}
+
+// RestartPolicyUpdateResult is the result of a bulk restart policy change.
+type RestartPolicyUpdateResult struct {
+	// Succeeded is the number of containers updated.
+	//
+	// Required: true
+	Succeeded int `json:"succeeded"`
+
+	// Failed is the number of containers that could not be updated.
+	//
+	// Required: true
+	Failed int `json:"failed"`
+
+	// Results contains the per-container outcome, in request order.
+	//
+	// Required: true
+	Results []RestartPolicyUpdateItem `json:"results"`
+}
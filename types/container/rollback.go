@@ -0,0 +1,74 @@
+package container
+
+import "time"
+
+// RollbackStatus describes the update a container can be rolled back from.
+type RollbackStatus struct {
+	// ContainerName is the name of the container.
+	//
+	// Required: true
+	ContainerName string `json:"containerName"`
+
+	// ContainerID is the ID of the container auto-update created.
+	//
+	// Required: true
+	ContainerID string `json:"containerId"`
+
+	// PreviousImageRef is the image reference the container ran before the
+	// update.
+	//
+	// Required: true
+	PreviousImageRef string `json:"previousImageRef"`
+
+	// PreviousImageID is the ID of the image the container ran before the
+	// update. A rollback recreates the container on this image.
+	//
+	// Required: true
+	PreviousImageID string `json:"previousImageId"`
+
+	// NewImageRef is the image reference the update moved the container to.
+	//
+	// Required: true
+	NewImageRef string `json:"newImageRef"`
+
+	// AppliedAt is when the update was applied.
+	//
+	// Required: true
+	AppliedAt time.Time `json:"appliedAt"`
+
+	// ObserveUntil is the end of the window in which the update can be
+	// rolled back.
+	//
+	// Required: true
+	ObserveUntil time.Time `json:"observeUntil"`
+
+	// Health is the state of the updated container: "healthy", "starting"
+	// or "unhealthy". A container that is not running counts as unhealthy.
+	//
+	// Required: false
+	Health string `json:"health,omitempty"`
+
+	// Available is true when the container can be rolled back now.
+	//
+	// Required: true
+	Available bool `json:"available"`
+
+	// RolledBackAt is when the container was rolled back, if it was.
+	//
+	// Required: false
+	RolledBackAt *time.Time `json:"rolledBackAt,omitempty"`
+
+	// RolledBackBy is the username that rolled the container back.
+	//
+	// Required: false
+	RolledBackBy string `json:"rolledBackBy,omitempty"`
+}
+
+// RollbackRequest is the request body for rolling a container back to the
+// image it ran before its last update.
+type RollbackRequest struct {
+	// Force rolls back a container that is still healthy.
+	//
+	// Required: false
+	Force bool `json:"force,omitempty"`
+}
@@ -0,0 +1,95 @@
+package container
+
+// CommitRequest is the request body for snapshotting a container into a new
+// image.
+type CommitRequest struct {
+	// Repository is the repository of the new image, for example
+	// "registry.local/web".
+	//
+	// Required: true
+	Repository string `json:"repository" minLength:"1" maxLength:"255"`
+
+	// Tag is the tag of the new image. Defaults to "latest".
+	//
+	// Required: false
+	Tag string `json:"tag,omitempty" maxLength:"128"`
+
+	// Comment is stored as the commit message of the image.
+	//
+	// Required: false
+	Comment string `json:"comment,omitempty" maxLength:"1024"`
+
+	// Author is stored as the author of the image. Defaults to the current
+	// user.
+	//
+	// Required: false
+	Author string `json:"author,omitempty" maxLength:"255"`
+
+	// Pause pauses the container while it is committed. Defaults to true.
+	//
+	// Required: false
+	Pause *bool `json:"pause,omitempty"`
+
+	// Changes are Dockerfile instructions applied to the image, such as
+	// "ENV DEBUG=1" or "EXPOSE 8080". Only CMD, ENTRYPOINT, ENV, EXPOSE,
+	// LABEL, ONBUILD, USER, VOLUME and WORKDIR are allowed.
+	//
+	// Required: false
+	Changes []string `json:"changes,omitempty"`
+}
+
+// CommitResult describes the image created from a container.
+type CommitResult struct {
+	// ImageID is the ID of the new image.
+	//
+	// Required: true
+	ImageID string `json:"imageId"`
+
+	// Reference is the repository:tag of the new image.
+	//
+	// Required: true
+	Reference string `json:"reference"`
+
+	// Size is the size of the new image in bytes.
+	//
+	// Required: true
+	Size int64 `json:"size"`
+
+	// Warnings point out what the snapshot may be missing or that it is
+	// large.
+	//
+	// Required: false
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// SnapshotInfo estimates the size of a container snapshot before it is
+// committed or exported.
+type SnapshotInfo struct {
+	// ContainerID is the ID of the container.
+	//
+	// Required: true
+	ContainerID string `json:"containerId"`
+
+	// SizeRootFs is the size of the container's filesystem in bytes, which
+	// is roughly the size of an export.
+	//
+	// Required: true
+	SizeRootFs int64 `json:"sizeRootFs"`
+
+	// SizeRw is the size of the files the container changed in bytes, which
+	// is roughly what a commit adds to its image.
+	//
+	// Required: true
+	SizeRw int64 `json:"sizeRw"`
+
+	// Volumes are the mount destinations whose data is not part of a
+	// snapshot.
+	//
+	// Required: false
+	Volumes []string `json:"volumes,omitempty"`
+
+	// Warnings point out what a snapshot may be missing or that it is large.
+	//
+	// Required: false
+	Warnings []string `json:"warnings,omitempty"`
+}
@@ -0,0 +1,128 @@
+package container
+
+import "time"
+
+// StatsSample is a resource usage sample of a single running container.
+type StatsSample struct {
+	// ID is the ID of the container.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Name is the name of the container.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// CPUPercent is the CPU usage, where 100 is one full core.
+	//
+	// Required: true
+	CPUPercent float64 `json:"cpuPercent"`
+
+	// MemoryUsage is the used memory without the page cache, in bytes.
+	//
+	// Required: true
+	MemoryUsage uint64 `json:"memoryUsage"`
+
+	// MemoryLimit is the memory limit of the container, in bytes.
+	//
+	// Required: true
+	MemoryLimit uint64 `json:"memoryLimit"`
+
+	// MemoryPercent is MemoryUsage as a percentage of MemoryLimit.
+	//
+	// Required: true
+	MemoryPercent float64 `json:"memoryPercent"`
+
+	// NetworkRx is the number of bytes received on all networks.
+	//
+	// Required: true
+	NetworkRx uint64 `json:"networkRx"`
+
+	// NetworkTx is the number of bytes sent on all networks.
+	//
+	// Required: true
+	NetworkTx uint64 `json:"networkTx"`
+
+	// BlockRead is the number of bytes read from block devices.
+	//
+	// Required: true
+	BlockRead uint64 `json:"blockRead"`
+
+	// BlockWrite is the number of bytes written to block devices.
+	//
+	// Required: true
+	BlockWrite uint64 `json:"blockWrite"`
+
+	// PIDs is the number of processes in the container.
+	//
+	// Required: true
+	PIDs uint64 `json:"pids"`
+}
+
+// HostStats sums the usage of all running containers on a host.
+type HostStats struct {
+	// Running is the number of sampled containers.
+	//
+	// Required: true
+	Running int `json:"running"`
+
+	// CPUCount is the number of CPUs of the Docker host.
+	//
+	// Required: true
+	CPUCount int `json:"cpuCount"`
+
+	// MemoryTotal is the memory of the Docker host, in bytes.
+	//
+	// Required: true
+	MemoryTotal uint64 `json:"memoryTotal"`
+
+	// CPUPercent is the summed CPU usage, where 100 is one full core.
+	//
+	// Required: true
+	CPUPercent float64 `json:"cpuPercent"`
+
+	// MemoryUsage is the summed memory usage, in bytes.
+	//
+	// Required: true
+	MemoryUsage uint64 `json:"memoryUsage"`
+
+	// NetworkRx is the summed number of bytes received.
+	//
+	// Required: true
+	NetworkRx uint64 `json:"networkRx"`
+
+	// NetworkTx is the summed number of bytes sent.
+	//
+	// Required: true
+	NetworkTx uint64 `json:"networkTx"`
+
+	// BlockRead is the summed number of bytes read from block devices.
+	//
+	// Required: true
+	BlockRead uint64 `json:"blockRead"`
+
+	// BlockWrite is the summed number of bytes written to block devices.
+	//
+	// Required: true
+	BlockWrite uint64 `json:"blockWrite"`
+}
+
+// AggregateStats is a snapshot of the resource usage of all running
+// containers of an environment.
+type AggregateStats struct {
+	// SampledAt is when the snapshot was taken.
+	//
+	// Required: true
+	SampledAt time.Time `json:"sampledAt"`
+
+	// Host holds the totals over all containers.
+	//
+	// Required: true
+	Host HostStats `json:"host"`
+
+	// Containers holds one sample per running container, sorted by name.
+	//
+	// Required: true
+	Containers []StatsSample `json:"containers"`
+}
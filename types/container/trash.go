@@ -0,0 +1,50 @@
+package container
+
+import "time"
+
+// TrashEntry is a deleted container that is kept, stopped and renamed, until
+// its grace period ends.
+type TrashEntry struct {
+	// ContainerID is the ID of the trashed container.
+	//
+	// Required: true
+	ContainerID string `json:"containerId"`
+
+	// OriginalName is the name the container had before it was deleted and
+	// gets back when it is restored.
+	//
+	// Required: true
+	OriginalName string `json:"originalName"`
+
+	// TrashName is the name the container has while it is in the trash.
+	//
+	// Required: true
+	TrashName string `json:"trashName"`
+
+	// WasRunning reports whether the container was running when it was
+	// deleted; it is started again when restored.
+	//
+	// Required: true
+	WasRunning bool `json:"wasRunning"`
+
+	// RemoveVolumes reports whether the container's named volumes are removed
+	// with it when it is purged.
+	//
+	// Required: true
+	RemoveVolumes bool `json:"removeVolumes"`
+
+	// DeletedBy is the user who deleted the container.
+	//
+	// Required: true
+	DeletedBy string `json:"deletedBy"`
+
+	// DeletedAt is when the container was deleted.
+	//
+	// Required: true
+	DeletedAt time.Time `json:"deletedAt"`
+
+	// PurgeAt is when the container is removed for good.
+	//
+	// Required: true
+	PurgeAt time.Time `json:"purgeAt"`
+}
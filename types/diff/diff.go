@@ -0,0 +1,38 @@
+package diff
+
+// ChangeKind describes how a field differs between the two sides of a diff.
+type ChangeKind string
+
+const (
+	// ChangeAdded means the field is only set on the right side.
+	ChangeAdded ChangeKind = "added"
+	// ChangeRemoved means the field is only set on the left side.
+	ChangeRemoved ChangeKind = "removed"
+	// ChangeModified means the field is set on both sides with different
+	// values.
+	ChangeModified ChangeKind = "modified"
+)
+
+// Change is a single difference between two configurations.
+type Change struct {
+	// Path is the dotted path of the field, such as "env.DEBUG" or
+	// "hostConfig.memory".
+	//
+	// Required: true
+	Path string `json:"path"`
+
+	// Kind is how the field changed.
+	//
+	// Required: true
+	Kind ChangeKind `json:"kind"`
+
+	// Old is the value on the left side. Absent for added fields.
+	//
+	// Required: false
+	Old any `json:"old,omitempty"`
+
+	// New is the value on the right side. Absent for removed fields.
+	//
+	// Required: false
+	New any `json:"new,omitempty"`
+}
@@ -35,6 +35,11 @@ type Create struct {
 	//
 	// Required: false
 	IsEdge *bool `json:"isEdge,omitempty"`
+
+	// Tags are free-form labels used to group and filter environments.
+	//
+	// Required: false
+	Tags []string `json:"tags,omitempty" maxItems:"50"`
 }
 
 type Update struct {
@@ -67,6 +72,61 @@ type Update struct {
 	//
 	// Required: false
 	RegenerateApiKey *bool `json:"regenerateApiKey,omitempty"`
+
+	// RequireApproval indicates if destructive actions need a second admin's
+	// approval.
+	//
+	// Required: false
+	RequireApproval *bool `json:"requireApproval,omitempty"`
+
+	// Color is a hex color (#rgb or #rrggbb) used to tint the environment in
+	// the UI. An empty string clears it.
+	//
+	// Required: false
+	Color *string `json:"color,omitempty" pattern:"^(#[0-9a-fA-F]{3}|#[0-9a-fA-F]{6})?$"`
+
+	// Icon is an emoji or image URL shown next to the environment name. An
+	// empty string clears it.
+	//
+	// Required: false
+	Icon *string `json:"icon,omitempty" maxLength:"512"`
+
+	// Description is a short note about the environment. An empty string
+	// clears it.
+	//
+	// Required: false
+	Description *string `json:"description,omitempty" maxLength:"500"`
+
+	// Production marks the environment as production. Production
+	// environments are highlighted in the UI and their destructive actions
+	// go through the approval workflow when it is enabled.
+	//
+	// Required: false
+	Production *bool `json:"production,omitempty"`
+
+	// MacAddress is the MAC address of the host NIC to wake with
+	// Wake-on-LAN. An empty string turns Wake-on-LAN off.
+	//
+	// Required: false
+	MacAddress *string `json:"macAddress,omitempty" maxLength:"32"`
+
+	// WakeBroadcast is the host or host:port the magic packet is sent to.
+	// Defaults to 255.255.255.255:9 when empty.
+	//
+	// Required: false
+	WakeBroadcast *string `json:"wakeBroadcast,omitempty" maxLength:"255"`
+
+	// WakeRelayID is an environment on the same LAN that sends the magic
+	// packet instead of the manager. An empty string clears it.
+	//
+	// Required: false
+	WakeRelayID *string `json:"wakeRelayId,omitempty"`
+
+	// Tags replaces the environment's tags when set. An empty list clears
+	// them.
+	//
+	// Required: false
+	Tags []string `json:"tags,omitempty" maxItems:"50"`
 }
 
 type Test struct {
@@ -121,6 +181,65 @@ type Environment struct {
 	// Required: false
 	IsEdge bool `json:"isEdge"`
 
+	// RequireApproval indicates if destructive actions need a second admin's
+	// approval.
+	//
+	// Required: false
+	RequireApproval bool `json:"requireApproval"`
+
+	// Color is the hex color used to tint the environment in the UI.
+	//
+	// Required: false
+	Color string `json:"color,omitempty"`
+
+	// Icon is an emoji or image URL shown next to the environment name.
+	//
+	// Required: false
+	Icon string `json:"icon,omitempty"`
+
+	// Description is a short note about the environment.
+	//
+	// Required: false
+	Description string `json:"description,omitempty"`
+
+	// Production marks the environment as production.
+	//
+	// Required: false
+	Production bool `json:"production"`
+
+	// ProductionGuardrails indicates that destructive actions on this
+	// environment are held for approval, either because it requires approval
+	// or because it is marked as production.
+	//
+	// Required: false
+	ProductionGuardrails bool `json:"productionGuardrails"`
+
+	// MacAddress is the MAC address woken by Wake-on-LAN.
+	//
+	// Required: false
+	MacAddress string `json:"macAddress,omitempty"`
+
+	// WakeBroadcast is the address the Wake-on-LAN packet is sent to.
+	//
+	// Required: false
+	WakeBroadcast string `json:"wakeBroadcast,omitempty"`
+
+	// WakeRelayID is the environment that sends the Wake-on-LAN packet, if
+	// not the manager.
+	//
+	// Required: false
+	WakeRelayID *string `json:"wakeRelayId,omitempty"`
+
+	// Tags are free-form labels used to group and filter environments.
+	//
+	// Required: false
+	Tags []string `json:"tags,omitempty"`
+
+	// Metrics is the status last pushed by the environment's agent.
+	//
+	// Required: false
+	Metrics *HeartbeatMetrics `json:"metrics,omitempty"`
+
 	// ApiKey is returned only when creating or regenerating
 	//
 	// Required: false
@@ -0,0 +1,50 @@
+package environment
+
+// Feature flag names. Each flag gates a capability that admins may want to
+// turn off on hosts with stricter security requirements.
+const (
+	// FeatureVolumeBrowser gates the volume file browser.
+	FeatureVolumeBrowser = "volumeBrowser"
+	// FeatureContainerExec gates the container exec terminal.
+	FeatureContainerExec = "containerExec"
+	// FeaturePrune gates manual and scheduled pruning of Docker resources.
+	FeaturePrune = "prune"
+	// FeatureContainerCheckpoint gates the experimental CRIU checkpoint and
+	// restore of containers.
+	FeatureContainerCheckpoint = "containerCheckpoint"
+)
+
+// Features lists all known feature flags.
+var Features = []string{FeatureVolumeBrowser, FeatureContainerExec, FeaturePrune, FeatureContainerCheckpoint}
+
+// FeatureFlag is the effective state of a feature for an environment.
+type FeatureFlag struct {
+	// Name of the feature.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Enabled is the effective state after applying the environment override.
+	//
+	// Required: true
+	Enabled bool `json:"enabled"`
+
+	// GlobalEnabled is the state configured in the global settings.
+	//
+	// Required: true
+	GlobalEnabled bool `json:"globalEnabled"`
+
+	// Override is the environment-specific state, if any.
+	//
+	// Required: false
+	Override *bool `json:"override,omitempty"`
+}
+
+// FeatureFlagsUpdate sets environment-specific feature overrides.
+type FeatureFlagsUpdate struct {
+	// Overrides maps feature names to their state for the environment. A null
+	// value removes the override so the global setting applies.
+	//
+	// Required: true
+	Overrides map[string]*bool `json:"overrides"`
+}
@@ -0,0 +1,39 @@
+package environment
+
+import "time"
+
+// HeartbeatMetrics is the lightweight status an agent pushes to the manager
+// with each heartbeat.
+type HeartbeatMetrics struct {
+	// AgentVersion is the Arcane version the agent runs.
+	//
+	// Required: false
+	AgentVersion string `json:"agentVersion,omitempty" maxLength:"64"`
+
+	// ContainersRunning is the number of running containers.
+	//
+	// Required: true
+	ContainersRunning int `json:"containersRunning" minimum:"0"`
+
+	// ContainersTotal is the number of containers in any state.
+	//
+	// Required: true
+	ContainersTotal int `json:"containersTotal" minimum:"0"`
+
+	// PendingUpdates is the number of images with an available update.
+	//
+	// Required: true
+	PendingUpdates int `json:"pendingUpdates" minimum:"0"`
+
+	// DiskFreeBytes is the free space on the agent's disk usage path, when
+	// known.
+	//
+	// Required: false
+	DiskFreeBytes *uint64 `json:"diskFreeBytes,omitempty"`
+
+	// ReportedAt is when the manager received the heartbeat. It is set by the
+	// manager and ignored on input.
+	//
+	// Required: false
+	ReportedAt *time.Time `json:"reportedAt,omitempty"`
+}
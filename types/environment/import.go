@@ -0,0 +1,126 @@
+package environment
+
+// Manifest formats accepted by the environment import endpoint.
+const (
+	ManifestFormatYAML = "yaml"
+	ManifestFormatCSV  = "csv"
+)
+
+// Import result statuses.
+const (
+	ImportStatusCreated = "created"
+	ImportStatusSkipped = "skipped"
+	ImportStatusFailed  = "failed"
+)
+
+// ImportRequest is the request body for importing environments from a
+// manifest.
+type ImportRequest struct {
+	// Format of the manifest, yaml or csv. Detected from the content when
+	// empty.
+	//
+	// Required: false
+	Format string `json:"format,omitempty" enum:"yaml,csv,"`
+
+	// Manifest is the YAML or CSV document describing the environments.
+	//
+	// YAML manifests hold a list of entries, either at the top level or
+	// under an "environments" key. CSV manifests start with a header row
+	// naming the columns name, apiUrl, accessToken, bootstrapToken and tags;
+	// tags are separated by ";".
+	//
+	// Required: true
+	Manifest string `json:"manifest" minLength:"1" maxLength:"1048576"`
+}
+
+// ManifestEntry describes one environment in an import manifest.
+type ManifestEntry struct {
+	// Name of the environment.
+	//
+	// Required: false
+	Name string `json:"name,omitempty" yaml:"name"`
+
+	// ApiUrl is the URL of the agent API.
+	//
+	// Required: true
+	ApiUrl string `json:"apiUrl" yaml:"apiUrl"`
+
+	// AccessToken is an agent token to use as is.
+	//
+	// Required: false
+	AccessToken string `json:"accessToken,omitempty" yaml:"accessToken"`
+
+	// BootstrapToken is exchanged with the agent for an access token. When
+	// neither token is given an API key is generated for the agent instead.
+	//
+	// Required: false
+	BootstrapToken string `json:"bootstrapToken,omitempty" yaml:"bootstrapToken"`
+
+	// Tags are free-form labels for the environment.
+	//
+	// Required: false
+	Tags []string `json:"tags,omitempty" yaml:"tags"`
+}
+
+// ImportResultEntry is the outcome of importing one manifest entry.
+type ImportResultEntry struct {
+	// Index is the position of the entry in the manifest, starting at 0.
+	//
+	// Required: true
+	Index int `json:"index"`
+
+	// Name of the environment.
+	//
+	// Required: false
+	Name string `json:"name,omitempty"`
+
+	// ApiUrl of the environment.
+	//
+	// Required: true
+	ApiUrl string `json:"apiUrl"`
+
+	// Status is created, skipped or failed.
+	//
+	// Required: true
+	Status string `json:"status"`
+
+	// EnvironmentID is the ID of the created environment, or of the existing
+	// environment a skipped entry matched.
+	//
+	// Required: false
+	EnvironmentID string `json:"environmentId,omitempty"`
+
+	// ApiKey is the generated agent API key for entries without a token. It
+	// is only returned once.
+	//
+	// Required: false
+	ApiKey *string `json:"apiKey,omitempty"`
+
+	// Error explains why the entry was skipped or failed.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+}
+
+// ImportResult summarizes an environment import.
+type ImportResult struct {
+	// Created is the number of environments created.
+	//
+	// Required: true
+	Created int `json:"created"`
+
+	// Skipped is the number of entries that matched an existing environment.
+	//
+	// Required: true
+	Skipped int `json:"skipped"`
+
+	// Failed is the number of entries that could not be imported.
+	//
+	// Required: true
+	Failed int `json:"failed"`
+
+	// Results holds the outcome of each entry in manifest order.
+	//
+	// Required: true
+	Results []ImportResultEntry `json:"results"`
+}
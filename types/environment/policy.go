@@ -0,0 +1,157 @@
+package environment
+
+import "time"
+
+// Policy report kinds.
+const (
+	// PolicyReportViolation is reported when an action was blocked by the policy.
+	PolicyReportViolation = "violation"
+	// PolicyReportPrune is reported when the policy's prune schedule ran.
+	PolicyReportPrune = "prune"
+)
+
+// Policy is the security and maintenance policy the manager issues to an
+// environment. Agents keep a local copy and enforce it even while the manager
+// is unreachable.
+type Policy struct {
+	// Revision increases each time the policy is changed. Zero means no policy
+	// has been set.
+	//
+	// Required: true
+	Revision int64 `json:"revision"`
+
+	// AllowedImages lists the image repositories that may be pulled or run,
+	// e.g. "nginx", "ghcr.io/acme/*" or "ghcr.io/acme/**". "*" does not cross
+	// a "/", a trailing "/**" matches everything below the prefix. An empty
+	// list allows every image.
+	//
+	// Required: false
+	AllowedImages []string `json:"allowedImages,omitempty"`
+
+	// DenyPrivileged blocks privileged containers.
+	//
+	// Required: true
+	DenyPrivileged bool `json:"denyPrivileged"`
+
+	// DenyHostNetwork blocks containers on the host network.
+	//
+	// Required: true
+	DenyHostNetwork bool `json:"denyHostNetwork"`
+
+	// PruneSchedule is a cron expression with seconds for a local prune of
+	// containers, images and networks. Empty disables it.
+	//
+	// Required: false
+	PruneSchedule string `json:"pruneSchedule,omitempty"`
+
+	// PruneMode is dangling or all.
+	//
+	// Required: false
+	PruneMode string `json:"pruneMode,omitempty" enum:"dangling,all,"`
+
+	// UpdatedAt is when the policy was last changed on the manager.
+	//
+	// Required: false
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// PolicyUpdate replaces the policy of an environment.
+type PolicyUpdate struct {
+	// AllowedImages lists the image repositories that may be pulled or run.
+	//
+	// Required: false
+	AllowedImages []string `json:"allowedImages,omitempty"`
+
+	// DenyPrivileged blocks privileged containers.
+	//
+	// Required: false
+	DenyPrivileged bool `json:"denyPrivileged,omitempty"`
+
+	// DenyHostNetwork blocks containers on the host network.
+	//
+	// Required: false
+	DenyHostNetwork bool `json:"denyHostNetwork,omitempty"`
+
+	// PruneSchedule is a cron expression with seconds; empty disables it.
+	//
+	// Required: false
+	PruneSchedule string `json:"pruneSchedule,omitempty"`
+
+	// PruneMode is dangling or all.
+	//
+	// Required: false
+	PruneMode string `json:"pruneMode,omitempty" enum:"dangling,all,"`
+}
+
+// PolicyStatus is the policy an environment enforces and how recently it
+// reconciled with the manager.
+type PolicyStatus struct {
+	// Policy is the locally cached policy.
+	//
+	// Required: true
+	Policy Policy `json:"policy"`
+
+	// SyncedAt is when the policy was last confirmed with the manager. It is
+	// empty on the manager itself.
+	//
+	// Required: false
+	SyncedAt *time.Time `json:"syncedAt,omitempty"`
+
+	// LastSyncError is the error of the last failed sync, if the last sync
+	// failed.
+	//
+	// Required: false
+	LastSyncError string `json:"lastSyncError,omitempty"`
+
+	// LastPruneAt is when the policy's prune schedule last ran.
+	//
+	// Required: false
+	LastPruneAt *time.Time `json:"lastPruneAt,omitempty"`
+
+	// PendingReports is the number of reports waiting to be delivered to the
+	// manager.
+	//
+	// Required: true
+	PendingReports int64 `json:"pendingReports"`
+}
+
+// PolicyReport is something an agent did or blocked under its policy.
+type PolicyReport struct {
+	// ID identifies the report so the agent can drop it once delivered.
+	//
+	// Required: true
+	ID string `json:"id" maxLength:"64"`
+
+	// Kind is violation or prune.
+	//
+	// Required: true
+	Kind string `json:"kind" enum:"violation,prune"`
+
+	// Revision is the policy revision in force.
+	//
+	// Required: true
+	Revision int64 `json:"revision"`
+
+	// Message describes what happened.
+	//
+	// Required: true
+	Message string `json:"message" maxLength:"1024"`
+
+	// Details holds kind-specific data.
+	//
+	// Required: false
+	Details map[string]any `json:"details,omitempty"`
+
+	// OccurredAt is when it happened on the agent.
+	//
+	// Required: true
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+// PolicyReportBatch is the set of reports an agent delivers at once.
+type PolicyReportBatch struct {
+	// Reports to record, oldest first.
+	//
+	// Required: true
+	Reports []PolicyReport `json:"reports" maxItems:"500"`
+}
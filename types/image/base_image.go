@@ -0,0 +1,34 @@
+package image
+
+// BaseImage describes the image an image was built from.
+type BaseImage struct {
+	// Name is the base image reference (e.g., debian:bookworm-slim).
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Digest is the digest of the base image, when the image records it.
+	Digest string `json:"digest,omitempty"`
+
+	// Source is where the base image was detected: annotation, labels or history.
+	//
+	// Required: true
+	Source string `json:"source"`
+
+	// Distribution is the Linux distribution of the base image, when known.
+	Distribution string `json:"distribution,omitempty"`
+
+	// Release is the distribution release of the base image (e.g., bookworm, 22.04).
+	Release string `json:"release,omitempty"`
+
+	// Outdated reports whether a newer release of the distribution exists.
+	//
+	// Required: true
+	Outdated bool `json:"outdated"`
+
+	// LatestRelease is the newest known release of the distribution.
+	LatestRelease string `json:"latestRelease,omitempty"`
+
+	// Recommendation is the suggested base image to rebuild on when Outdated is set.
+	Recommendation string `json:"recommendation,omitempty"`
+}
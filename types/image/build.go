@@ -0,0 +1,107 @@
+package image
+
+// BuildRequest describes an image build. The build context is either
+// uploaded as a tar archive, given as a single Dockerfile, or fetched by
+// Docker from a Git repository.
+type BuildRequest struct {
+	// BuildID identifies the build in progress messages. A new ID is
+	// generated when it is empty; clients that want to follow the build as it
+	// runs pass their own.
+	//
+	// Required: false
+	BuildID string `json:"buildId,omitempty"`
+
+	// Tags are the references the built image is tagged with, e.g.
+	// "myapp:1.0".
+	//
+	// Required: true
+	Tags []string `json:"tags"`
+
+	// Dockerfile is the path of the Dockerfile within the build context.
+	// Defaults to "Dockerfile".
+	//
+	// Required: false
+	Dockerfile string `json:"dockerfile,omitempty"`
+
+	// DockerfileContent builds from this Dockerfile alone, with no other
+	// files in the context.
+	//
+	// Required: false
+	DockerfileContent string `json:"dockerfileContent,omitempty"`
+
+	// GitURL is a Git repository Docker clones as the build context, e.g.
+	// "https://github.com/user/repo.git#main:subdir".
+	//
+	// Required: false
+	GitURL string `json:"gitUrl,omitempty"`
+
+	// BuildArgs are passed to the build as ARG values.
+	//
+	// Required: false
+	BuildArgs map[string]string `json:"buildArgs,omitempty"`
+
+	// NoCache builds every step without using the layer cache.
+	//
+	// Required: false
+	NoCache bool `json:"noCache,omitempty"`
+
+	// Pull always pulls a newer version of the base images.
+	//
+	// Required: false
+	Pull bool `json:"pull,omitempty"`
+}
+
+// BuildResult is the outcome of a successful image build.
+type BuildResult struct {
+	// BuildID identifies the build in progress messages.
+	//
+	// Required: true
+	BuildID string `json:"buildId"`
+
+	// ImageID is the ID of the built image.
+	//
+	// Required: true
+	ImageID string `json:"imageId"`
+
+	// Tags are the references the image was tagged with.
+	//
+	// Required: true
+	Tags []string `json:"tags"`
+}
+
+// BuildStatus is the state of an image build.
+type BuildStatus string
+
+const (
+	BuildStatusRunning   BuildStatus = "running"
+	BuildStatusSucceeded BuildStatus = "succeeded"
+	BuildStatusFailed    BuildStatus = "failed"
+)
+
+// BuildProgress is broadcast over WebSocket while an image build runs.
+type BuildProgress struct {
+	// BuildID identifies the build.
+	//
+	// Required: true
+	BuildID string `json:"buildId"`
+
+	// Status is the state of the build.
+	//
+	// Required: true
+	Status BuildStatus `json:"status"`
+
+	// Output is a chunk of build output.
+	//
+	// Required: false
+	Output string `json:"output,omitempty"`
+
+	// ImageID is the ID of the built image, set once the build succeeded.
+	//
+	// Required: false
+	ImageID string `json:"imageId,omitempty"`
+
+	// Error is why the build failed.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+}
@@ -0,0 +1,73 @@
+package image
+
+import "github.com/getarcaneapp/arcane/types/diff"
+
+// DiffSubject identifies one side of an image diff.
+type DiffSubject struct {
+	// ID is the ID of the image.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// RepoTags are the tags of the image.
+	//
+	// Required: false
+	RepoTags []string `json:"repoTags,omitempty"`
+
+	// Size is the size of the image in bytes.
+	//
+	// Required: true
+	Size int64 `json:"size"`
+}
+
+// Diff lists the differences between two images: their default
+// configuration, their layers and the build steps that produced them.
+type Diff struct {
+	// Left is the image the changes are relative to.
+	//
+	// Required: true
+	Left DiffSubject `json:"left"`
+
+	// Right is the image compared against Left.
+	//
+	// Required: true
+	Right DiffSubject `json:"right"`
+
+	// Identical is true when the configuration, layers and build steps all
+	// match.
+	//
+	// Required: true
+	Identical bool `json:"identical"`
+
+	// Changes are the configuration differences, sorted by path.
+	//
+	// Required: true
+	Changes []diff.Change `json:"changes"`
+
+	// SharedLayers is the number of layers both images have in common.
+	//
+	// Required: true
+	SharedLayers int `json:"sharedLayers"`
+
+	// AddedLayers are the digests of layers only the right image has.
+	//
+	// Required: true
+	AddedLayers []string `json:"addedLayers"`
+
+	// RemovedLayers are the digests of layers only the left image has.
+	//
+	// Required: true
+	RemovedLayers []string `json:"removedLayers"`
+
+	// AddedSteps are build steps, oldest first, only found in the history
+	// of the right image.
+	//
+	// Required: true
+	AddedSteps []HistoryLayer `json:"addedSteps"`
+
+	// RemovedSteps are build steps, oldest first, only found in the history
+	// of the left image.
+	//
+	// Required: true
+	RemovedSteps []HistoryLayer `json:"removedSteps"`
+}
@@ -0,0 +1,125 @@
+package image
+
+import "time"
+
+// History describes how an image was built: its layers, newest first, and
+// the configuration a container started from it gets by default.
+type History struct {
+	// ImageID is the ID of the image.
+	//
+	// Required: true
+	ImageID string `json:"imageId"`
+
+	// RepoTags are the tags of the image.
+	//
+	// Required: false
+	RepoTags []string `json:"repoTags,omitempty"`
+
+	// Layers are the build steps of the image, newest first. Steps that only
+	// changed metadata have a size of 0.
+	//
+	// Required: true
+	Layers []HistoryLayer `json:"layers"`
+
+	// LayerDigests are the digests of the filesystem layers, oldest first.
+	//
+	// Required: true
+	LayerDigests []string `json:"layerDigests"`
+
+	// Size is the total size of the image in bytes.
+	//
+	// Required: true
+	Size int64 `json:"size"`
+
+	// Config is the image configuration.
+	//
+	// Required: true
+	Config ConfigDetails `json:"config"`
+}
+
+// HistoryLayer is one build step of an image.
+type HistoryLayer struct {
+	// ID is the ID of the image this step produced, or "<missing>" when the
+	// step comes from an image that is not present locally.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Created is when the step ran.
+	//
+	// Required: true
+	Created time.Time `json:"created"`
+
+	// CreatedBy is the command of the step, e.g. the Dockerfile instruction.
+	//
+	// Required: true
+	CreatedBy string `json:"createdBy"`
+
+	// Size is how many bytes the step added.
+	//
+	// Required: true
+	Size int64 `json:"size"`
+
+	// Comment is the comment recorded for the step.
+	//
+	// Required: false
+	Comment string `json:"comment,omitempty"`
+
+	// Tags are the tags of the image this step produced.
+	//
+	// Required: false
+	Tags []string `json:"tags,omitempty"`
+}
+
+// ConfigDetails is the configuration an image gives containers by default.
+type ConfigDetails struct {
+	// User is the user the container runs as.
+	//
+	// Required: false
+	User string `json:"user,omitempty"`
+
+	// Entrypoint is the entrypoint of the container.
+	//
+	// Required: false
+	Entrypoint []string `json:"entrypoint,omitempty"`
+
+	// Cmd is the default command, passed to the entrypoint as arguments.
+	//
+	// Required: false
+	Cmd []string `json:"cmd,omitempty"`
+
+	// WorkingDir is the working directory of the container.
+	//
+	// Required: false
+	WorkingDir string `json:"workingDir,omitempty"`
+
+	// Env are the environment variables set in the image, as KEY=value.
+	//
+	// Required: false
+	Env []string `json:"env,omitempty"`
+
+	// ExposedPorts are the ports the image exposes, e.g. "80/tcp", sorted.
+	//
+	// Required: false
+	ExposedPorts []string `json:"exposedPorts,omitempty"`
+
+	// Volumes are the paths the image declares as volumes, sorted.
+	//
+	// Required: false
+	Volumes []string `json:"volumes,omitempty"`
+
+	// Labels are the labels of the image.
+	//
+	// Required: false
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// StopSignal is the signal sent to stop the container.
+	//
+	// Required: false
+	StopSignal string `json:"stopSignal,omitempty"`
+
+	// Healthcheck is the health check command of the image.
+	//
+	// Required: false
+	Healthcheck []string `json:"healthcheck,omitempty"`
+}
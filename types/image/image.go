@@ -118,6 +118,11 @@ type Summary struct {
 	// Required: true
 	InUse bool `json:"inUse" sortable:"true"`
 
+	// Pinned indicates if the image is protected from prune and auto-update.
+	//
+	// Required: true
+	Pinned bool `json:"pinned" sortable:"true"`
+
 	// Repo is the repository name of the image.
 	//
 	// Required: true
@@ -202,6 +207,11 @@ type LoadResult struct {
 	//
 	// Required: true
 	Stream string `json:"stream"`
+
+	// Images are the tags, or IDs for untagged images, that were loaded.
+	//
+	// Required: false
+	Images []string `json:"images,omitempty"`
 }
 
 type DetailSummary struct {
@@ -307,6 +317,11 @@ type DetailSummary struct {
 		// Size is the size of the descriptor.
 		Size int64 `json:"size"`
 	} `json:"descriptor"`
+
+	// BaseImage is the detected base image, if it could be determined.
+	//
+	// Required: false
+	BaseImage *BaseImage `json:"baseImage,omitempty"`
 }
 
 // PullOptions contains options for pulling an image.
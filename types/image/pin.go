@@ -0,0 +1,39 @@
+package image
+
+import "time"
+
+// PinRequest is the request body for pinning an image.
+type PinRequest struct {
+	// Reason is an optional note explaining why the image is pinned.
+	//
+	// Required: false
+	Reason string `json:"reason,omitempty" maxLength:"500"`
+}
+
+// PinnedImage describes an image protected from prune and auto-update.
+type PinnedImage struct {
+	// Digest is the image ID (content digest) the pin applies to.
+	//
+	// Required: true
+	Digest string `json:"digest"`
+
+	// ImageRef is the image reference at the time it was pinned.
+	//
+	// Required: true
+	ImageRef string `json:"imageRef"`
+
+	// Reason is the optional note explaining why the image is pinned.
+	//
+	// Required: false
+	Reason string `json:"reason,omitempty"`
+
+	// PinnedBy is the username that pinned the image.
+	//
+	// Required: true
+	PinnedBy string `json:"pinnedBy"`
+
+	// CreatedAt is when the image was pinned.
+	//
+	// Required: true
+	CreatedAt time.Time `json:"createdAt"`
+}
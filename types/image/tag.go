@@ -0,0 +1,33 @@
+package image
+
+// TagRequest is the request body for adding a tag to an image.
+type TagRequest struct {
+	// Tag is the reference to add, e.g. "registry.example.com/team/app:1.0".
+	// The tag defaults to "latest" when it is omitted.
+	//
+	// Required: true
+	Tag string `json:"tag" minLength:"1" maxLength:"512"`
+
+	// Force moves the tag when it already points at a different image.
+	//
+	// Required: false
+	Force bool `json:"force,omitempty"`
+}
+
+// TagResult describes an image after one of its tags was added or removed.
+type TagResult struct {
+	// ImageID is the ID of the image.
+	//
+	// Required: true
+	ImageID string `json:"imageId"`
+
+	// Tag is the normalized reference that was added or removed.
+	//
+	// Required: true
+	Tag string `json:"tag"`
+
+	// RepoTags are the tags the image has now.
+	//
+	// Required: true
+	RepoTags []string `json:"repoTags"`
+}
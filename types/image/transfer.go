@@ -0,0 +1,74 @@
+package image
+
+// SaveRequest selects the images to export as a single tar archive.
+type SaveRequest struct {
+	// Images are the IDs or references of the images to export. Tags are
+	// kept in the archive so the images can be loaded elsewhere under the
+	// same names.
+	//
+	// Required: true
+	Images []string `json:"images" minItems:"1"`
+
+	// TransferID identifies the export in progress messages. Clients that
+	// want to follow the export as it runs pass their own.
+	//
+	// Required: false
+	TransferID string `json:"transferId,omitempty"`
+}
+
+// TransferOperation is the direction of an image transfer.
+type TransferOperation string
+
+const (
+	TransferOperationSave TransferOperation = "save"
+	TransferOperationLoad TransferOperation = "load"
+)
+
+// TransferStatus is the state of an image transfer.
+type TransferStatus string
+
+const (
+	TransferStatusRunning   TransferStatus = "running"
+	TransferStatusSucceeded TransferStatus = "succeeded"
+	TransferStatusFailed    TransferStatus = "failed"
+)
+
+// TransferProgress is broadcast over WebSocket while images are exported or
+// imported.
+type TransferProgress struct {
+	// TransferID identifies the transfer.
+	//
+	// Required: true
+	TransferID string `json:"transferId"`
+
+	// Operation is whether images are being saved or loaded.
+	//
+	// Required: true
+	Operation TransferOperation `json:"operation"`
+
+	// Status is the state of the transfer.
+	//
+	// Required: true
+	Status TransferStatus `json:"status"`
+
+	// Bytes is how much of the archive has been transferred so far.
+	//
+	// Required: true
+	Bytes int64 `json:"bytes"`
+
+	// TotalBytes is the size of the archive when known. For exports it is
+	// estimated from the image sizes.
+	//
+	// Required: false
+	TotalBytes int64 `json:"totalBytes,omitempty"`
+
+	// Images are the images that were loaded, set once a load succeeded.
+	//
+	// Required: false
+	Images []string `json:"images,omitempty"`
+
+	// Error is why the transfer failed.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+}
@@ -71,6 +71,28 @@ type Response struct {
 	//
 	// Required: false
 	UsedCredential bool `json:"usedCredential,omitempty"`
+
+	// Cached indicates the remote digest was served from the update check cache.
+	//
+	// Required: false
+	Cached bool `json:"cached,omitempty"`
+
+	// PublishedAt is when the remote tag was last published, if the registry reports it.
+	//
+	// Required: false
+	PublishedAt *time.Time `json:"publishedAt,omitempty"`
+
+	// Backend is the update check backend that resolved the remote tag
+	// ("dockerhub" | "ghcr" | "lscr" | "registry").
+	//
+	// Required: false
+	Backend string `json:"backend,omitempty"`
+
+	// RateLimitedUntil is set when the registry is rate limiting update checks
+	// and indicates when checks will be attempted again.
+	//
+	// Required: false
+	RateLimitedUntil *time.Time `json:"rateLimitedUntil,omitempty"`
 }
 
 type Summary struct {
@@ -153,6 +153,24 @@ var jobMetadataRegistry = map[string]JobMetadata{
 		CanRunManually: false,
 		Prerequisites:  []JobPrerequisiteMetadata{},
 	},
+	"boot-verification": {
+		ID:             "boot-verification",
+		Name:           "Post-Restart Verification",
+		Description:    "Snapshots running containers and starts any that did not come back after a Docker restart or host reboot",
+		Category:       "maintenance",
+		SettingsKey:    "bootVerificationInterval",
+		EnabledKey:     "bootVerificationEnabled",
+		ManagerOnly:    false,
+		IsContinuous:   false,
+		CanRunManually: true,
+		Prerequisites: []JobPrerequisiteMetadata{
+			{
+				SettingKey:  "bootVerificationEnabled",
+				Label:       "Post-restart verification enabled",
+				SettingsURL: "/settings/docker",
+			},
+		},
+	},
 	"vulnerability-scan": {
 		ID:             "vulnerability-scan",
 		Name:           "Vulnerability Scan",
@@ -171,6 +189,24 @@ var jobMetadataRegistry = map[string]JobMetadata{
 			},
 		},
 	},
+	"vulnerability-digest": {
+		ID:             "vulnerability-digest",
+		Name:           "Vulnerability Digest",
+		Description:    "Sends a digest of new critical vulnerabilities, the most affected images and fixable counts through the notification providers",
+		Category:       "security",
+		SettingsKey:    "vulnerabilityDigestInterval",
+		EnabledKey:     "vulnerabilityDigestEnabled",
+		ManagerOnly:    false,
+		IsContinuous:   false,
+		CanRunManually: true,
+		Prerequisites: []JobPrerequisiteMetadata{
+			{
+				SettingKey:  "vulnerabilityDigestEnabled",
+				Label:       "Vulnerability digest enabled",
+				SettingsURL: "/settings/security",
+			},
+		},
+	},
 }
 
 func GetJobMetadata(jobID string) (JobMetadata, bool) {
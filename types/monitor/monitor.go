@@ -0,0 +1,205 @@
+package monitor
+
+import "time"
+
+// Request is the request body for creating or replacing an endpoint monitor.
+type Request struct {
+	// Name is a display name for the monitor.
+	//
+	// Required: true
+	Name string `json:"name" minLength:"1" maxLength:"255"`
+
+	// Type is the kind of probe. http sends a GET request to Target, tcp
+	// opens a connection to it.
+	//
+	// Required: true
+	Type string `json:"type" enum:"http,tcp"`
+
+	// Target is an http(s) URL for http monitors and a host:port address
+	// for tcp monitors.
+	//
+	// Required: true
+	Target string `json:"target" minLength:"1" maxLength:"2048"`
+
+	// ExpectedStatus is the HTTP status code that counts as up. When 0, any
+	// 2xx or 3xx status is up.
+	//
+	// Required: false
+	ExpectedStatus int `json:"expectedStatus,omitempty" minimum:"0" maximum:"599"`
+
+	// IntervalSeconds is the time between probes. Defaults to 60.
+	//
+	// Required: false
+	IntervalSeconds int `json:"intervalSeconds,omitempty" minimum:"0" maximum:"86400"`
+
+	// TimeoutSeconds is how long a probe may take. Defaults to 10.
+	//
+	// Required: false
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty" minimum:"0" maximum:"300"`
+
+	// Retries is the number of consecutive failures before the monitor is
+	// down. Defaults to 2.
+	//
+	// Required: false
+	Retries int `json:"retries,omitempty" minimum:"0" maximum:"100"`
+
+	// ProjectID attaches the monitor to a project.
+	//
+	// Required: false
+	ProjectID string `json:"projectId,omitempty"`
+
+	// ContainerKey attaches the monitor to a container by name or ID.
+	//
+	// Required: false
+	ContainerKey string `json:"containerKey,omitempty"`
+
+	// Enabled controls whether the monitor is probed. Defaults to true.
+	//
+	// Required: false
+	Enabled *bool `json:"enabled,omitempty"`
+
+	// Notify sends a notification when the monitor goes down. Defaults to
+	// true.
+	//
+	// Required: false
+	Notify *bool `json:"notify,omitempty"`
+}
+
+// Monitor describes an endpoint monitor and its last result.
+type Monitor struct {
+	// ID is the unique identifier of the monitor.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Name is the display name of the monitor.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Type is the kind of probe: http or tcp.
+	//
+	// Required: true
+	Type string `json:"type"`
+
+	// Target is the URL or host:port address that is probed.
+	//
+	// Required: true
+	Target string `json:"target"`
+
+	// ExpectedStatus is the HTTP status code that counts as up, or 0 for any
+	// 2xx or 3xx status.
+	//
+	// Required: true
+	ExpectedStatus int `json:"expectedStatus"`
+
+	// IntervalSeconds is the time between probes.
+	//
+	// Required: true
+	IntervalSeconds int `json:"intervalSeconds"`
+
+	// TimeoutSeconds is how long a probe may take.
+	//
+	// Required: true
+	TimeoutSeconds int `json:"timeoutSeconds"`
+
+	// Retries is the number of consecutive failures before the monitor is
+	// down.
+	//
+	// Required: true
+	Retries int `json:"retries"`
+
+	// ProjectID is the project the monitor is attached to.
+	//
+	// Required: false
+	ProjectID *string `json:"projectId,omitempty"`
+
+	// ContainerKey is the container name or ID the monitor is attached to.
+	//
+	// Required: false
+	ContainerKey *string `json:"containerKey,omitempty"`
+
+	// Enabled reports whether the monitor is probed.
+	//
+	// Required: true
+	Enabled bool `json:"enabled"`
+
+	// Notify reports whether a notification is sent when the monitor goes
+	// down.
+	//
+	// Required: true
+	Notify bool `json:"notify"`
+
+	// Status is pending, up or down.
+	//
+	// Required: true
+	Status string `json:"status"`
+
+	// FailingStreak is the number of consecutive failed probes.
+	//
+	// Required: true
+	FailingStreak int `json:"failingStreak"`
+
+	// LastStatusCode is the HTTP status of the last probe, or 0.
+	//
+	// Required: false
+	LastStatusCode int `json:"lastStatusCode,omitempty"`
+
+	// LastLatencyMs is the duration of the last probe in milliseconds.
+	//
+	// Required: false
+	LastLatencyMs int64 `json:"lastLatencyMs,omitempty"`
+
+	// LastError is the error of the last failed probe.
+	//
+	// Required: false
+	LastError string `json:"lastError,omitempty"`
+
+	// LastCheckedAt is when the endpoint was last probed.
+	//
+	// Required: false
+	LastCheckedAt *time.Time `json:"lastCheckedAt,omitempty"`
+
+	// UpdatedBy is the username that last changed the monitor.
+	//
+	// Required: true
+	UpdatedBy string `json:"updatedBy"`
+
+	// CreatedAt is when the monitor was created.
+	//
+	// Required: true
+	CreatedAt time.Time `json:"createdAt"`
+
+	// UpdatedAt is when the monitor was last changed.
+	//
+	// Required: false
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// Result is the outcome of a single monitor probe.
+type Result struct {
+	// Up reports whether the probe succeeded.
+	//
+	// Required: true
+	Up bool `json:"up"`
+
+	// StatusCode is the HTTP status returned, or 0.
+	//
+	// Required: false
+	StatusCode int `json:"statusCode,omitempty"`
+
+	// LatencyMs is the duration of the probe in milliseconds.
+	//
+	// Required: true
+	LatencyMs int64 `json:"latencyMs"`
+
+	// Error describes why the probe failed.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+
+	// CheckedAt is when the probe ran.
+	//
+	// Required: true
+	CheckedAt time.Time `json:"checkedAt"`
+}
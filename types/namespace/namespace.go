@@ -0,0 +1,181 @@
+package namespace
+
+import "time"
+
+// Namespace is a team that owns projects, volumes and containers.
+type Namespace struct {
+	// ID of the namespace.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Name is the value of the com.getarcaneapp.namespace label on resources
+	// owned by the namespace.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Description of the namespace.
+	//
+	// Required: false
+	Description string `json:"description,omitempty"`
+
+	// MaxContainers caps the containers the namespace may own. 0 means
+	// unlimited.
+	//
+	// Required: true
+	MaxContainers int `json:"maxContainers"`
+
+	// MaxMemoryMB caps the memory the namespace's running containers may
+	// reserve in MB. 0 means unlimited.
+	//
+	// Required: true
+	MaxMemoryMB int `json:"maxMemoryMb"`
+
+	// MaxVolumeGB caps the total size of the namespace's volumes in GB. 0
+	// means unlimited.
+	//
+	// Required: true
+	MaxVolumeGB int `json:"maxVolumeGb"`
+
+	// MemberIDs are the IDs of the users in the namespace.
+	//
+	// Required: true
+	MemberIDs []string `json:"memberIds"`
+
+	// Projects are the names of the projects the namespace owns.
+	//
+	// Required: true
+	Projects []string `json:"projects"`
+
+	// CreatedAt is when the namespace was created.
+	//
+	// Required: true
+	CreatedAt time.Time `json:"createdAt"`
+
+	// UpdatedAt is when the namespace was last changed.
+	//
+	// Required: false
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// Create is the request body for creating a namespace.
+type Create struct {
+	// Name must be a valid label value: letters, digits, '_', '.' and '-'. It
+	// cannot be changed later because resources reference it by label.
+	//
+	// Required: true
+	Name string `json:"name" minLength:"1" maxLength:"63"`
+
+	// Description of the namespace.
+	//
+	// Required: false
+	Description string `json:"description,omitempty" maxLength:"1024"`
+
+	// MaxContainers caps the containers the namespace may own. 0 means
+	// unlimited.
+	//
+	// Required: false
+	MaxContainers int `json:"maxContainers,omitempty" minimum:"0"`
+
+	// MaxMemoryMB caps the memory the namespace's running containers may
+	// reserve in MB. 0 means unlimited. When set, containers must declare a
+	// memory reservation or limit.
+	//
+	// Required: false
+	MaxMemoryMB int `json:"maxMemoryMb,omitempty" minimum:"0"`
+
+	// MaxVolumeGB caps the total size of the namespace's volumes in GB. 0
+	// means unlimited.
+	//
+	// Required: false
+	MaxVolumeGB int `json:"maxVolumeGb,omitempty" minimum:"0"`
+}
+
+// Update is the request body for changing a namespace's description or
+// quotas. Omitted fields are left unchanged.
+type Update struct {
+	// Description of the namespace.
+	//
+	// Required: false
+	Description *string `json:"description,omitempty" maxLength:"1024"`
+
+	// MaxContainers caps the containers the namespace may own. 0 means
+	// unlimited.
+	//
+	// Required: false
+	MaxContainers *int `json:"maxContainers,omitempty" minimum:"0"`
+
+	// MaxMemoryMB caps the memory the namespace's running containers may
+	// reserve in MB. 0 means unlimited.
+	//
+	// Required: false
+	MaxMemoryMB *int `json:"maxMemoryMb,omitempty" minimum:"0"`
+
+	// MaxVolumeGB caps the total size of the namespace's volumes in GB. 0
+	// means unlimited.
+	//
+	// Required: false
+	MaxVolumeGB *int `json:"maxVolumeGb,omitempty" minimum:"0"`
+}
+
+// SetMembers replaces the members of a namespace.
+type SetMembers struct {
+	// UserIDs are the IDs of the users in the namespace.
+	//
+	// Required: true
+	UserIDs []string `json:"userIds"`
+}
+
+// SetProjects replaces the projects owned by a namespace.
+type SetProjects struct {
+	// ProjectIDs are the IDs of the projects the namespace owns.
+	//
+	// Required: true
+	ProjectIDs []string `json:"projectIds"`
+}
+
+// Usage reports how much of its quotas a namespace uses on the local
+// environment.
+type Usage struct {
+	// NamespaceID is the ID of the namespace.
+	//
+	// Required: true
+	NamespaceID string `json:"namespaceId"`
+
+	// Name of the namespace.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Containers is the number of containers the namespace owns.
+	//
+	// Required: true
+	Containers int `json:"containers"`
+
+	// MaxContainers is the container quota. 0 means unlimited.
+	//
+	// Required: true
+	MaxContainers int `json:"maxContainers"`
+
+	// MemoryBytes is the memory reserved by the namespace's running
+	// containers, using their memory limit where no reservation is set.
+	//
+	// Required: true
+	MemoryBytes int64 `json:"memoryBytes"`
+
+	// MaxMemoryMB is the memory quota in MB. 0 means unlimited.
+	//
+	// Required: true
+	MaxMemoryMB int `json:"maxMemoryMb"`
+
+	// VolumeBytes is the total size of the namespace's volumes.
+	//
+	// Required: true
+	VolumeBytes int64 `json:"volumeBytes"`
+
+	// MaxVolumeGB is the volume size quota in GB. 0 means unlimited.
+	//
+	// Required: true
+	MaxVolumeGB int `json:"maxVolumeGb"`
+}
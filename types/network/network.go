@@ -247,6 +247,9 @@ type CreateOptions struct {
 	// IPAM configuration for the network.
 	IPAM *IPAM `json:"ipam,omitempty" doc:"IP Address Management configuration"`
 
+	// EnableIPv4 enables IPv4 networking. Nil leaves the daemon default.
+	EnableIPv4 *bool `json:"enableIPv4,omitempty" doc:"Enable IPv4 networking (defaults to the daemon setting)"`
+
 	// EnableIPv6 enables IPv6 networking.
 	EnableIPv6 bool `json:"enableIPv6,omitempty" doc:"Enable IPv6 networking"`
 
@@ -269,6 +272,7 @@ func (o CreateOptions) ToDockerCreateOptions() network.CreateOptions {
 		Internal:   o.Internal,
 		Attachable: o.Attachable,
 		Ingress:    o.Ingress,
+		EnableIPv4: o.EnableIPv4,
 		EnableIPv6: enableIPv6,
 		Options:    o.Options,
 		Labels:     o.Labels,
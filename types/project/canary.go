@@ -0,0 +1,67 @@
+package project
+
+import "time"
+
+// CanaryUpdateRequest starts a canary update of one service of a project.
+type CanaryUpdateRequest struct {
+	// Service is the compose service to update. It must run at least two
+	// replicas.
+	//
+	// Required: true
+	Service string `json:"service" minLength:"1" maxLength:"255"`
+
+	// BakeSeconds is how long the canary replica must stay healthy before
+	// the remaining replicas are updated. Defaults to 60.
+	//
+	// Required: false
+	BakeSeconds int `json:"bakeSeconds,omitempty" minimum:"0" maximum:"3600"`
+
+	// Pull pulls the image of the service before the canary is created.
+	//
+	// Required: false
+	Pull bool `json:"pull,omitempty"`
+}
+
+// CanaryUpdateResult is the outcome of a canary update.
+type CanaryUpdateResult struct {
+	// Service is the updated compose service.
+	//
+	// Required: true
+	Service string `json:"service"`
+
+	// Replicas is the number of replicas of the service.
+	//
+	// Required: true
+	Replicas int `json:"replicas"`
+
+	// CanaryContainerID is the ID of the container created as the canary.
+	//
+	// Required: true
+	CanaryContainerID string `json:"canaryContainerId"`
+
+	// Outcome is promoted when all replicas were updated, or rolled_back
+	// when the canary failed and was removed.
+	//
+	// Required: true
+	Outcome string `json:"outcome" enum:"promoted,rolled_back"`
+
+	// Reason explains why the canary was rolled back.
+	//
+	// Required: false
+	Reason string `json:"reason,omitempty"`
+
+	// BakeSeconds is the bake time the canary was checked for.
+	//
+	// Required: true
+	BakeSeconds int `json:"bakeSeconds"`
+
+	// StartedAt is when the canary update started.
+	//
+	// Required: true
+	StartedAt time.Time `json:"startedAt"`
+
+	// FinishedAt is when the canary update finished.
+	//
+	// Required: true
+	FinishedAt time.Time `json:"finishedAt"`
+}
@@ -0,0 +1,83 @@
+package project
+
+// DeployHookRequest defines a hook run before or after a project is deployed.
+type DeployHookRequest struct {
+	// Phase is when the hook runs: pre (before compose up) or post (after a
+	// successful compose up).
+	//
+	// Required: true
+	Phase string `json:"phase" enum:"pre,post"`
+
+	// Type is the kind of hook. exec runs Command in a running container of
+	// Service, helper runs Command in a one-off container of Image and
+	// webhook sends a POST request to URL.
+	//
+	// Required: true
+	Type string `json:"type" enum:"exec,helper,webhook"`
+
+	// Service is the compose service whose container exec hooks run in.
+	//
+	// Required: false
+	Service string `json:"service,omitempty" maxLength:"255"`
+
+	// Image is the image of the one-off container helper hooks run in.
+	//
+	// Required: false
+	Image string `json:"image,omitempty" maxLength:"512"`
+
+	// Command is the shell command run by exec and helper hooks.
+	//
+	// Required: false
+	Command string `json:"command,omitempty" maxLength:"4096"`
+
+	// URL is the endpoint webhook hooks send the deploy to.
+	//
+	// Required: false
+	URL string `json:"url,omitempty" maxLength:"2048"`
+
+	// TimeoutSeconds is how long the hook may take. Defaults to 60.
+	//
+	// Required: false
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty" minimum:"0" maximum:"3600"`
+
+	// OnFailure is what a failing hook does: abort stops the deploy (or, for
+	// post hooks, fails it), warn records the failure and continues. Defaults
+	// to abort.
+	//
+	// Required: false
+	OnFailure string `json:"onFailure,omitempty" enum:"abort,warn,"`
+
+	// Disabled keeps the hook without running it.
+	//
+	// Required: false
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// DeployHooksRequest replaces all deploy hooks of a project.
+type DeployHooksRequest struct {
+	// Hooks are the hooks of the project. Hooks of a phase run in the order
+	// given.
+	//
+	// Required: true
+	Hooks []DeployHookRequest `json:"hooks" maxItems:"50"`
+}
+
+// DeployHook is a deploy hook of a project.
+type DeployHook struct {
+	DeployHookRequest
+
+	// ID is the unique identifier of the hook.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// ProjectID is the project the hook belongs to.
+	//
+	// Required: true
+	ProjectID string `json:"projectId"`
+
+	// UpdatedBy is the user who last changed the project's hooks.
+	//
+	// Required: true
+	UpdatedBy string `json:"updatedBy"`
+}
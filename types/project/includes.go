@@ -0,0 +1,76 @@
+package project
+
+// IncludeGraph describes how the compose files of a project include each
+// other.
+type IncludeGraph struct {
+	// Root is the path of the project's main compose file, relative to the
+	// project directory.
+	//
+	// Required: true
+	Root string `json:"root"`
+
+	// Nodes are the compose files reachable from the root, in the order
+	// they were first reached.
+	//
+	// Required: true
+	Nodes []IncludeNode `json:"nodes"`
+
+	// Edges are the include directives between the files.
+	//
+	// Required: true
+	Edges []IncludeEdge `json:"edges"`
+
+	// Cycles lists every include cycle as the chain of files that leads
+	// back to its first file.
+	//
+	// Required: true
+	Cycles [][]string `json:"cycles"`
+
+	// Missing lists the included files that do not exist.
+	//
+	// Required: true
+	Missing []string `json:"missing"`
+
+	// Valid is true when the graph has no cycles, missing files or files
+	// that fail to parse.
+	//
+	// Required: true
+	Valid bool `json:"valid"`
+}
+
+// IncludeNode is a compose file in an include graph.
+type IncludeNode struct {
+	// Path is the path of the file relative to the project directory, or
+	// absolute when the file is outside of it.
+	//
+	// Required: true
+	Path string `json:"path"`
+
+	// Exists is false when the file is included but does not exist.
+	//
+	// Required: true
+	Exists bool `json:"exists"`
+
+	// Error is set when the file could not be read or parsed.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+}
+
+// IncludeEdge is an include directive from one compose file to another.
+type IncludeEdge struct {
+	// From is the including file.
+	//
+	// Required: true
+	From string `json:"from"`
+
+	// To is the included file.
+	//
+	// Required: true
+	To string `json:"to"`
+
+	// Declared is the path as written in the include directive.
+	//
+	// Required: true
+	Declared string `json:"declared"`
+}
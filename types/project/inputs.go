@@ -0,0 +1,90 @@
+package project
+
+// Input types of x-arcane.inputs.
+const (
+	InputTypeString = "string"
+	InputTypeInt    = "int"
+	InputTypeEnum   = "enum"
+	InputTypeSecret = "secret"
+	InputTypePort   = "port"
+)
+
+// Input is a typed variable declared in the x-arcane.inputs block of a
+// compose file. Its value is rendered into the project's .env file.
+type Input struct {
+	// Name is the environment variable the value is written to.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Type is the type of the value.
+	//
+	// Required: true
+	Type string `json:"type" enum:"string,int,enum,secret,port"`
+
+	// Label is the human readable name of the input.
+	//
+	// Required: false
+	Label string `json:"label,omitempty"`
+
+	// Description explains what the input is for.
+	//
+	// Required: false
+	Description string `json:"description,omitempty"`
+
+	// Default is used when no value is given.
+	//
+	// Required: false
+	Default string `json:"default,omitempty"`
+
+	// Required rejects an empty value.
+	//
+	// Required: false
+	Required bool `json:"required,omitempty"`
+
+	// Options are the allowed values of an enum input.
+	//
+	// Required: false
+	Options []string `json:"options,omitempty"`
+
+	// Min is the smallest allowed value of an int input.
+	//
+	// Required: false
+	Min *int `json:"min,omitempty"`
+
+	// Max is the largest allowed value of an int input.
+	//
+	// Required: false
+	Max *int `json:"max,omitempty"`
+}
+
+// InputState is an input of a project together with its current value.
+type InputState struct {
+	Input
+
+	// Value is the current value. It is never returned for secret inputs.
+	//
+	// Required: false
+	Value string `json:"value,omitempty"`
+
+	// HasValue reports whether the .env file holds a value for the input.
+	//
+	// Required: true
+	HasValue bool `json:"hasValue"`
+
+	// Pending reports whether the user must be asked for the input: it is
+	// new, its definition changed since values were last saved, or it is
+	// required and has no value.
+	//
+	// Required: true
+	Pending bool `json:"pending"`
+}
+
+// InputsRequest sets input values of a project.
+type InputsRequest struct {
+	// Values maps input names to values. Inputs left out keep their current
+	// value, or get their default when they have none.
+	//
+	// Required: true
+	Values map[string]string `json:"values"`
+}
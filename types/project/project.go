@@ -3,6 +3,7 @@ package project
 import (
 	composetypes "github.com/compose-spec/compose-go/v2/types"
 	"github.com/getarcaneapp/arcane/types/containerregistry"
+	uptimetypes "github.com/getarcaneapp/arcane/types/uptime"
 )
 
 // IncludeFile represents an included file within a project.
@@ -279,6 +280,13 @@ type Details struct {
 	//
 	// Required: false
 	GitRepositoryURL string `json:"gitRepositoryURL,omitempty"`
+
+	// Uptime is the availability of the project over the last 24 hours,
+	// 7 days and 30 days. A project is up while none of its containers is
+	// stopped.
+	//
+	// Required: false
+	Uptime *uptimetypes.Stats `json:"uptime,omitempty"`
 }
 
 // Destroy is used to destroy a project.
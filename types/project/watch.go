@@ -0,0 +1,88 @@
+package project
+
+import "time"
+
+// Watch event types.
+const (
+	WatchEventStarted = "started"
+	WatchEventStopped = "stopped"
+	WatchEventWatch   = "watch"
+	WatchEventLog     = "log"
+	WatchEventError   = "error"
+)
+
+// WatchStartRequest starts watching a project for file changes.
+type WatchStartRequest struct {
+	// Services limits watching to these services. Empty watches every service
+	// with a develop.watch section.
+	//
+	// Required: false
+	Services []string `json:"services,omitempty" maxItems:"100"`
+}
+
+// WatchStatus is the watch state of a project.
+type WatchStatus struct {
+	// ProjectID is the ID of the project.
+	//
+	// Required: true
+	ProjectID string `json:"projectId"`
+
+	// Running reports whether the project is being watched.
+	//
+	// Required: true
+	Running bool `json:"running"`
+
+	// Services are the services being watched; empty means all.
+	//
+	// Required: false
+	Services []string `json:"services,omitempty"`
+
+	// StartedAt is when watching started.
+	//
+	// Required: false
+	StartedAt *time.Time `json:"startedAt,omitempty"`
+
+	// StartedBy is the user who started watching.
+	//
+	// Required: false
+	StartedBy string `json:"startedBy,omitempty"`
+
+	// LastMessage is the latest message reported by the watch.
+	//
+	// Required: false
+	LastMessage string `json:"lastMessage,omitempty"`
+
+	// Error is why the last watch stopped, if it failed.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+}
+
+// WatchEvent is a watch update broadcast on the project watch WebSocket.
+type WatchEvent struct {
+	// ProjectID is the ID of the watched project.
+	//
+	// Required: true
+	ProjectID string `json:"projectId"`
+
+	// Type is one of started, stopped, watch (sync, restart and rebuild
+	// progress), log (build output of a service) or error.
+	//
+	// Required: true
+	Type string `json:"type"`
+
+	// Service is the service or container the message is about, if any.
+	//
+	// Required: false
+	Service string `json:"service,omitempty"`
+
+	// Message is the message text.
+	//
+	// Required: false
+	Message string `json:"message,omitempty"`
+
+	// Time is when the event happened.
+	//
+	// Required: true
+	Time time.Time `json:"time"`
+}
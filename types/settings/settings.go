@@ -62,6 +62,26 @@ type Update struct {
 	// Required: false
 	PollingInterval *string `json:"pollingInterval,omitempty"`
 
+	// TagDriftDetectionEnabled indicates if image polling also checks running containers for tag drift.
+	//
+	// Required: false
+	TagDriftDetectionEnabled *string `json:"tagDriftDetectionEnabled,omitempty"`
+
+	// UpdateCheckCacheTTL is how long registry digest lookups are cached in minutes.
+	//
+	// Required: false
+	UpdateCheckCacheTTL *string `json:"updateCheckCacheTtl,omitempty"`
+
+	// RegistryRequestBudget is the maximum number of update check requests per minute to each registry.
+	//
+	// Required: false
+	RegistryRequestBudget *string `json:"registryRequestBudget,omitempty"`
+
+	// RegistryMirrors lists the mirrors image pulls fall back to, as registry=mirror pairs.
+	//
+	// Required: false
+	RegistryMirrors *string `json:"registryMirrors,omitempty"`
+
 	// AutoInjectEnv indicates if project .env variables should be automatically injected into all containers.
 	//
 	// Required: false
@@ -112,6 +132,16 @@ type Update struct {
 	// Required: false
 	ScheduledPruneBuildCache *string `json:"scheduledPruneBuildCache,omitempty"`
 
+	// MaintenanceWindows lists when auto-update, scheduled prune and vulnerability scans may run, one window per line.
+	//
+	// Required: false
+	MaintenanceWindows *string `json:"maintenanceWindows,omitempty"`
+
+	// MaintenanceWindowOverride lets scheduled jobs run outside the maintenance windows.
+	//
+	// Required: false
+	MaintenanceWindowOverride *string `json:"maintenanceWindowOverride,omitempty"`
+
 	// VulnerabilityScanEnabled indicates if scheduled vulnerability scanning is enabled.
 	//
 	// Required: false
@@ -122,11 +152,139 @@ type Update struct {
 	// Required: false
 	VulnerabilityScanInterval *string `json:"vulnerabilityScanInterval,omitempty"`
 
+	// BootVerificationEnabled indicates if containers should be verified and
+	// restarted after a Docker daemon restart or host reboot.
+	//
+	// Required: false
+	BootVerificationEnabled *string `json:"bootVerificationEnabled,omitempty"`
+
+	// BootVerificationInterval is the cron expression for the runtime state
+	// snapshot and restart check.
+	//
+	// Required: false
+	BootVerificationInterval *string `json:"bootVerificationInterval,omitempty"`
+
+	// HealthFlapThreshold is the number of health status changes per hour
+	// above which a container is reported as flapping. 0 disables alerts.
+	//
+	// Required: false
+	HealthFlapThreshold *string `json:"healthFlapThreshold,omitempty"`
+
+	// CrashLoopThreshold is the number of failed exits within the crash loop
+	// window above which a container is reported as crash looping. 0
+	// disables alerts.
+	//
+	// Required: false
+	CrashLoopThreshold *string `json:"crashLoopThreshold,omitempty"`
+
+	// CrashLoopWindow is the window in minutes in which failed exits are
+	// counted for crash loop alerts.
+	//
+	// Required: false
+	CrashLoopWindow *string `json:"crashLoopWindow,omitempty"`
+
+	// ContainerTrashRetention is the number of hours a deleted container
+	// stays in the trash before it is removed. 0 removes containers right
+	// away.
+	//
+	// Required: false
+	ContainerTrashRetention *string `json:"containerTrashRetention,omitempty"`
+
+	// ContainerDNSServers are the DNS servers given to new containers that
+	// set none of their own, separated by commas.
+	//
+	// Required: false
+	ContainerDNSServers *string `json:"containerDnsServers,omitempty"`
+
+	// ContainerDNSSearch are the DNS search domains given to new containers
+	// that set none of their own, separated by commas.
+	//
+	// Required: false
+	ContainerDNSSearch *string `json:"containerDnsSearch,omitempty"`
+
+	// ContainerExtraHosts are hostname:IP entries added to /etc/hosts of new
+	// containers, one per line.
+	//
+	// Required: false
+	ContainerExtraHosts *string `json:"containerExtraHosts,omitempty"`
+
+	// VolumeBackupDriver selects how volume backups are taken: "tar" or
+	// "snapshot" (ZFS/Btrfs, falling back to tar).
+	//
+	// Required: false
+	VolumeBackupDriver *string `json:"volumeBackupDriver,omitempty"`
+
+	// HelperImage pins the image used for volume helper containers. Empty
+	// means the image is detected automatically.
+	//
+	// Required: false
+	HelperImage *string `json:"helperImage,omitempty"`
+
+	// HelperCpuLimit caps the CPU cores a volume helper container may use.
+	// 0 means unlimited.
+	//
+	// Required: false
+	HelperCpuLimit *string `json:"helperCpuLimit,omitempty"`
+
+	// HelperMemoryLimit caps the memory in MB a volume helper container may
+	// use. 0 means unlimited.
+	//
+	// Required: false
+	HelperMemoryLimit *string `json:"helperMemoryLimit,omitempty"`
+
+	// HelperIdleTtl is how many minutes an unused read-only helper container
+	// is kept before it is removed. 0 keeps it until shutdown.
+	//
+	// Required: false
+	HelperIdleTtl *string `json:"helperIdleTtl,omitempty"`
+
+	// StaleHelperMaxAge is how many hours a running helper container or a
+	// restore leftover that Arcane no longer tracks is kept before it is
+	// removed. 0 disables the cleanup.
+	//
+	// Required: false
+	StaleHelperMaxAge *string `json:"staleHelperMaxAge,omitempty"`
+
+	// DockerMaxConcurrentRequests caps concurrent Docker API requests. 0 means
+	// unlimited.
+	//
+	// Required: false
+	DockerMaxConcurrentRequests *string `json:"dockerMaxConcurrentRequests,omitempty"`
+
+	// DockerMaxConcurrentDiskUsage caps concurrent Docker disk usage calls.
+	// 0 means unlimited.
+	//
+	// Required: false
+	DockerMaxConcurrentDiskUsage *string `json:"dockerMaxConcurrentDiskUsage,omitempty"`
+
+	// DockerMaxConcurrentExecs caps concurrent background exec sessions. 0
+	// means unlimited.
+	//
+	// Required: false
+	DockerMaxConcurrentExecs *string `json:"dockerMaxConcurrentExecs,omitempty"`
+
+	// DockerMaxConcurrentPulls caps concurrent image pulls. 0 means unlimited.
+	//
+	// Required: false
+	DockerMaxConcurrentPulls *string `json:"dockerMaxConcurrentPulls,omitempty"`
+
 	// MaxImageUploadSize is the maximum size for image uploads.
 	//
 	// Required: false
 	MaxImageUploadSize *string `json:"maxImageUploadSize,omitempty"`
 
+	// MaxVolumeDownloadSize is the maximum size in MB of a volume directory
+	// download.
+	//
+	// Required: false
+	MaxVolumeDownloadSize *string `json:"maxVolumeDownloadSize,omitempty"`
+
+	// MaxBackupUploadSize is the maximum size in MB of an uploaded volume
+	// backup archive.
+	//
+	// Required: false
+	MaxBackupUploadSize *string `json:"maxBackupUploadSize,omitempty"`
+
 	// BaseServerURL is the base URL of the server.
 	//
 	// Required: false
@@ -182,6 +340,65 @@ type Update struct {
 	// Required: false
 	TrivyImage *string `json:"trivyImage,omitempty"`
 
+	// LicenseDenylist is a comma-separated list of license patterns flagged
+	// in license reports.
+	//
+	// Required: false
+	LicenseDenylist *string `json:"licenseDenylist,omitempty"`
+
+	// VulnerabilityScanOnPull scans every image after it is pulled.
+	//
+	// Required: false
+	VulnerabilityScanOnPull *string `json:"vulnerabilityScanOnPull,omitempty"`
+
+	// VulnerabilityScanGateSeverity is the lowest severity counted by the
+	// scan gate (CRITICAL, HIGH, MEDIUM or LOW). Empty turns the gate off.
+	//
+	// Required: false
+	VulnerabilityScanGateSeverity *string `json:"vulnerabilityScanGateSeverity,omitempty"`
+
+	// VulnerabilityScanGateThreshold is how many vulnerabilities at or above
+	// the gate severity an image may have before containers are blocked.
+	//
+	// Required: false
+	VulnerabilityScanGateThreshold *string `json:"vulnerabilityScanGateThreshold,omitempty"`
+
+	// VulnerabilityDigestEnabled turns the scheduled vulnerability digest
+	// notification on.
+	//
+	// Required: false
+	VulnerabilityDigestEnabled *string `json:"vulnerabilityDigestEnabled,omitempty"`
+
+	// VulnerabilityDigestInterval is the cron expression for the digest.
+	//
+	// Required: false
+	VulnerabilityDigestInterval *string `json:"vulnerabilityDigestInterval,omitempty"`
+
+	// VulnerabilityDigestCriticalThreshold is how many critical
+	// vulnerabilities must be open for the digest to be sent. 0 ignores
+	// critical counts.
+	//
+	// Required: false
+	VulnerabilityDigestCriticalThreshold *string `json:"vulnerabilityDigestCriticalThreshold,omitempty"`
+
+	// VulnerabilityDigestHighThreshold is the same threshold for high
+	// vulnerabilities.
+	//
+	// Required: false
+	VulnerabilityDigestHighThreshold *string `json:"vulnerabilityDigestHighThreshold,omitempty"`
+
+	// VulnerabilityDigestMediumThreshold is the same threshold for medium
+	// vulnerabilities.
+	//
+	// Required: false
+	VulnerabilityDigestMediumThreshold *string `json:"vulnerabilityDigestMediumThreshold,omitempty"`
+
+	// VulnerabilityDigestLowThreshold is the same threshold for low
+	// vulnerabilities.
+	//
+	// Required: false
+	VulnerabilityDigestLowThreshold *string `json:"vulnerabilityDigestLowThreshold,omitempty"`
+
 	// AuthOidcConfig is deprecated and will be removed in a future release.
 	//
 	// Required: false
@@ -237,6 +454,53 @@ type Update struct {
 	// Required: false
 	OidcProviderLogoUrl *string `json:"oidcProviderLogoUrl,omitempty"`
 
+	// FeatureVolumeBrowserEnabled indicates if the volume file browser is
+	// enabled globally.
+	//
+	// Required: false
+	FeatureVolumeBrowserEnabled *string `json:"featureVolumeBrowserEnabled,omitempty"`
+
+	// FeatureContainerExecEnabled indicates if container exec terminals are
+	// enabled globally.
+	//
+	// Required: false
+	FeatureContainerExecEnabled *string `json:"featureContainerExecEnabled,omitempty"`
+
+	// FeaturePruneEnabled indicates if pruning is enabled globally.
+	//
+	// Required: false
+	FeaturePruneEnabled *string `json:"featurePruneEnabled,omitempty"`
+
+	// FeatureCheckpointEnabled indicates if the experimental
+	// container checkpoint and restore is enabled globally.
+	//
+	// Required: false
+	FeatureCheckpointEnabled *string `json:"featureCheckpointEnabled,omitempty"`
+
+	// ApprovalWorkflowEnabled indicates if destructive actions on
+	// environments that require approval need a second admin's approval.
+	//
+	// Required: false
+	ApprovalWorkflowEnabled *string `json:"approvalWorkflowEnabled,omitempty"`
+
+	// ApprovalRequestTTL is the number of minutes an approval request stays
+	// valid.
+	//
+	// Required: false
+	ApprovalRequestTTL *string `json:"approvalRequestTtl,omitempty"`
+
+	// ExecAuditTranscriptEnabled indicates if the keystrokes and output of
+	// exec terminal sessions are recorded in their audit events.
+	//
+	// Required: false
+	ExecAuditTranscriptEnabled *string `json:"execAuditTranscriptEnabled,omitempty"`
+
+	// ExecAuditTranscriptMaxKB is the maximum size of a recorded terminal
+	// transcript in KB.
+	//
+	// Required: false
+	ExecAuditTranscriptMaxKB *string `json:"execAuditTranscriptMaxKb,omitempty"`
+
 	// MobileNavigationMode is the navigation mode for mobile devices.
 	//
 	// Required: false
@@ -287,8 +551,38 @@ type Update struct {
 	// Required: false
 	ProxyRequestTimeout *string `json:"proxyRequestTimeout,omitempty"`
 
+	// HelperExecTimeout is the timeout for quick file operations in volume helper containers in seconds.
+	//
+	// Required: false
+	HelperExecTimeout *string `json:"helperExecTimeout,omitempty"`
+
+	// HelperScanTimeout is the timeout for directory listings and disk usage scans in volume helper containers in seconds.
+	//
+	// Required: false
+	HelperScanTimeout *string `json:"helperScanTimeout,omitempty"`
+
+	// HelperArchiveTimeout is the timeout for archive extraction and restores in volume helper containers in seconds.
+	//
+	// Required: false
+	HelperArchiveTimeout *string `json:"helperArchiveTimeout,omitempty"`
+
 	// AutoUpdateExcludedContainers is a comma-separated list of container names to exclude from auto-update.
 	//
 	// Required: false
 	AutoUpdateExcludedContainers *string `json:"autoUpdateExcludedContainers,omitempty"`
+
+	// AutoUpdateRollbackWindow is how many minutes after an auto-update a container can be rolled back.
+	//
+	// Required: false
+	AutoUpdateRollbackWindow *string `json:"autoUpdateRollbackWindow,omitempty"`
+
+	// AutoUpdateCanaryEnvironment is the environment a staged auto-update rollout updates first.
+	//
+	// Required: false
+	AutoUpdateCanaryEnvironment *string `json:"autoUpdateCanaryEnvironment,omitempty"`
+
+	// AutoUpdateSoakPeriod is how many minutes the canary must stay healthy before the rollout continues.
+	//
+	// Required: false
+	AutoUpdateSoakPeriod *string `json:"autoUpdateSoakPeriod,omitempty"`
 }
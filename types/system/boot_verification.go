@@ -0,0 +1,62 @@
+package system
+
+import "time"
+
+// BootVerificationFailure describes a container that could not be brought back
+// after a Docker daemon restart.
+type BootVerificationFailure struct {
+	// Name is the container name.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Project is the compose project the container belongs to, if any.
+	//
+	// Required: false
+	Project string `json:"project,omitempty"`
+
+	// Error is the reason the container could not be started.
+	//
+	// Required: true
+	Error string `json:"error"`
+}
+
+// BootVerificationReport is the result of verifying the runtime state after a
+// Docker daemon restart or host reboot.
+type BootVerificationReport struct {
+	// Reason explains why a restart was detected.
+	//
+	// Required: true
+	Reason string `json:"reason"`
+
+	// SnapshotTakenAt is when the expected state was recorded.
+	//
+	// Required: true
+	SnapshotTakenAt time.Time `json:"snapshotTakenAt"`
+
+	// Expected is the number of containers that were running in the snapshot.
+	//
+	// Required: true
+	Expected int `json:"expected"`
+
+	// Running is the number of expected containers that came back on their own.
+	//
+	// Required: true
+	Running int `json:"running"`
+
+	// Started lists containers that were started by the verification.
+	//
+	// Required: true
+	Started []string `json:"started"`
+
+	// Redeployed lists compose projects that were redeployed because their
+	// containers no longer existed.
+	//
+	// Required: true
+	Redeployed []string `json:"redeployed"`
+
+	// Failed lists containers that could not be brought back.
+	//
+	// Required: true
+	Failed []BootVerificationFailure `json:"failed"`
+}
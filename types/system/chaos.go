@@ -0,0 +1,31 @@
+package system
+
+// ChaosRule describes the faults chaos mode injects into one path.
+type ChaosRule struct {
+	// LatencyMs is the maximum delay added to each request, in milliseconds.
+	// Each request waits a random duration up to this value.
+	//
+	// Required: true
+	LatencyMs int `json:"latencyMs" minimum:"0"`
+
+	// FailureRate is the percentage of requests that fail.
+	//
+	// Required: true
+	FailureRate int `json:"failureRate" minimum:"0" maximum:"100"`
+}
+
+// ChaosConfig is the fault injection of chaos mode, a development aid for
+// testing timeouts, retries and error states. It is never available in
+// production.
+type ChaosConfig struct {
+	// Docker applies to the requests Arcane sends to the Docker API.
+	//
+	// Required: true
+	Docker ChaosRule `json:"docker"`
+
+	// Api applies to the requests Arcane's HTTP API serves, including those
+	// a manager sends to an agent.
+	//
+	// Required: true
+	Api ChaosRule `json:"api"`
+}
@@ -0,0 +1,28 @@
+package system
+
+import "time"
+
+// Operation is a long-running operation currently in progress, such as a
+// volume backup or restore.
+type Operation struct {
+	// ID is the unique identifier of the operation.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Kind is the operation type, e.g. "volume.backup" or "volume.restore".
+	//
+	// Required: true
+	Kind string `json:"kind"`
+
+	// Resource is the name or ID of the resource the operation works on.
+	// Conflicting operations on the same resource are refused while it runs.
+	//
+	// Required: true
+	Resource string `json:"resource"`
+
+	// StartedAt is when the operation started.
+	//
+	// Required: true
+	StartedAt time.Time `json:"startedAt"`
+}
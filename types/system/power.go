@@ -0,0 +1,32 @@
+package system
+
+// PowerCapabilities describes the power actions a host supports.
+type PowerCapabilities struct {
+	// RebootEnabled indicates that a reboot hook is configured on the host
+	// through HOST_REBOOT_COMMAND.
+	//
+	// Required: true
+	RebootEnabled bool `json:"rebootEnabled"`
+}
+
+// WakeOnLanRequest asks a host to send a Wake-on-LAN magic packet on its LAN.
+type WakeOnLanRequest struct {
+	// MacAddress is the MAC address of the machine to wake.
+	//
+	// Required: true
+	MacAddress string `json:"macAddress" maxLength:"32"`
+
+	// BroadcastAddress is the host or host:port the packet is sent to.
+	// Defaults to 255.255.255.255:9.
+	//
+	// Required: false
+	BroadcastAddress string `json:"broadcastAddress,omitempty" maxLength:"255"`
+}
+
+// RebootRequest asks a host to run its reboot hook.
+type RebootRequest struct {
+	// Confirm must be true; it guards against accidental reboots.
+	//
+	// Required: true
+	Confirm bool `json:"confirm"`
+}
@@ -1,5 +1,7 @@
 package system
 
+import "time"
+
 // PruneAllRequest is used to request pruning of Docker system resources.
 type PruneAllRequest struct {
 	// Containers indicates if containers should be pruned.
@@ -89,4 +91,95 @@ type PruneAllResult struct {
 	//
 	// Required: false
 	Errors []string `json:"errors,omitempty"`
+
+	// Items lists every removed resource with its reclaimed size.
+	//
+	// Required: false
+	Items []PrunedItem `json:"items,omitempty"`
+
+	// ReportID is the ID of the stored prune report.
+	//
+	// Required: false
+	ReportID string `json:"reportId,omitempty"`
+}
+
+// Prune item types.
+const (
+	PrunedItemContainer  = "container"
+	PrunedItemImage      = "image"
+	PrunedItemVolume     = "volume"
+	PrunedItemNetwork    = "network"
+	PrunedItemBuildCache = "buildCache"
+)
+
+// PrunedItem is a single resource removed by a prune.
+type PrunedItem struct {
+	// Type is one of container, image, volume, network or buildCache.
+	//
+	// Required: true
+	Type string `json:"type"`
+
+	// ID of the removed resource.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Name is a human readable name, e.g. the container name or image tag.
+	//
+	// Required: false
+	Name string `json:"name,omitempty"`
+
+	// Size is the space reclaimed by removing this resource in bytes. Image
+	// sizes include layers shared with other images and are an upper bound.
+	//
+	// Required: true
+	Size int64 `json:"size"`
+}
+
+// PruneReport is a stored record of a prune operation.
+type PruneReport struct {
+	// ID of the report.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Trigger is what started the prune, either "manual" or "scheduled".
+	//
+	// Required: true
+	Trigger string `json:"trigger"`
+
+	// Username of the user who started the prune.
+	//
+	// Required: false
+	Username string `json:"username,omitempty"`
+
+	// Success indicates if all prune steps succeeded.
+	//
+	// Required: true
+	Success bool `json:"success"`
+
+	// SpaceReclaimed is the total amount of space reclaimed in bytes.
+	//
+	// Required: true
+	SpaceReclaimed int64 `json:"spaceReclaimed"`
+
+	// ItemCount is the number of removed resources.
+	//
+	// Required: true
+	ItemCount int `json:"itemCount"`
+
+	// Items lists every removed resource. Omitted in list responses.
+	//
+	// Required: false
+	Items []PrunedItem `json:"items,omitempty"`
+
+	// Errors encountered during the prune.
+	//
+	// Required: false
+	Errors []string `json:"errors,omitempty"`
+
+	// CreatedAt is when the prune ran.
+	//
+	// Required: true
+	CreatedAt time.Time `json:"createdAt"`
 }
@@ -0,0 +1,46 @@
+package system
+
+import "time"
+
+// SlowQuery is a database query that ran for longer than the slow query
+// threshold.
+type SlowQuery struct {
+	// SQL is the query with its parameters filled in, truncated when long.
+	//
+	// Required: true
+	SQL string `json:"sql"`
+
+	// Rows is the number of rows the query returned or affected.
+	//
+	// Required: true
+	Rows int64 `json:"rows"`
+
+	// DurationMs is how long the query ran, in milliseconds.
+	//
+	// Required: true
+	DurationMs float64 `json:"durationMs"`
+
+	// Timestamp is when the query started.
+	//
+	// Required: true
+	Timestamp time.Time `json:"timestamp"`
+
+	// Error is the error the query failed with, if any.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+}
+
+// SlowQueryReport lists the most recent slow database queries.
+type SlowQueryReport struct {
+	// ThresholdMs is the slow query threshold in milliseconds, set with
+	// DB_SLOW_QUERY_THRESHOLD. 0 means slow queries are not recorded.
+	//
+	// Required: true
+	ThresholdMs int64 `json:"thresholdMs"`
+
+	// Queries are the most recent slow queries, newest first.
+	//
+	// Required: true
+	Queries []SlowQuery `json:"queries"`
+}
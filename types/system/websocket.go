@@ -4,11 +4,23 @@ import "time"
 
 // WebSocket connection kind constants.
 const (
-	WSKindProjectLogs    = "project_logs"
-	WSKindContainerLogs  = "container_logs"
-	WSKindContainerStats = "container_stats"
-	WSKindContainerExec  = "container_exec"
-	WSKindSystemStats    = "system_stats"
+	WSKindProjectLogs        = "project_logs"
+	WSKindContainerLogs      = "container_logs"
+	WSKindContainerStats     = "container_stats"
+	WSKindContainerProcesses = "container_processes"
+	WSKindContainerExec      = "container_exec"
+	WSKindSystemStats        = "system_stats"
+	WSKindBackupProgress     = "backup_progress"
+	WSKindProjectWatch       = "project_watch"
+	WSKindMergedLogs         = "merged_logs"
+	WSKindImageBuild         = "image_build"
+	WSKindImageTransfer      = "image_transfer"
+)
+
+// Stream transports a log or progress stream can be served over.
+const (
+	StreamTransportWebSocket = "websocket"
+	StreamTransportSSE       = "sse"
 )
 
 // WebSocketConnectionInfo describes a single active WebSocket connection.
@@ -31,6 +43,9 @@ type WebSocketConnectionInfo struct {
 	UserID string `json:"userId,omitempty"`
 	// UserAgent is the HTTP User-Agent header from the client.
 	UserAgent string `json:"userAgent,omitempty"`
+	// Transport is how the stream is served: websocket, or sse for
+	// server-sent events.
+	Transport string `json:"transport,omitempty"`
 	// StartedAt is when the connection was established.
 	//
 	// Required: true
@@ -46,8 +61,20 @@ type WebSocketMetricsSnapshot struct {
 	ContainerLogsActive int64 `json:"containerLogsActive"`
 	// ContainerStats is the number of active container-stats streams.
 	ContainerStats int64 `json:"containerStats"`
+	// ContainerProcesses is the number of active container process-list streams.
+	ContainerProcesses int64 `json:"containerProcesses"`
 	// ContainerExec is the number of active container-exec sessions.
 	ContainerExec int64 `json:"containerExec"`
 	// SystemStats is the number of active system-stats streams.
 	SystemStats int64 `json:"systemStats"`
+	// BackupProgress is the number of active backup-progress streams.
+	BackupProgress int64 `json:"backupProgress"`
+	// ProjectWatch is the number of active project-watch streams.
+	ProjectWatch int64 `json:"projectWatch"`
+	// MergedLogs is the number of active multi-container log streams.
+	MergedLogs int64 `json:"mergedLogs"`
+	// ImageBuild is the number of active image-build progress streams.
+	ImageBuild int64 `json:"imageBuild"`
+	// ImageTransfer is the number of active image save/load progress streams.
+	ImageTransfer int64 `json:"imageTransfer"`
 }
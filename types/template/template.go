@@ -3,6 +3,7 @@ package template
 import (
 	"github.com/getarcaneapp/arcane/types/env"
 	"github.com/getarcaneapp/arcane/types/meta"
+	"github.com/getarcaneapp/arcane/types/project"
 )
 
 // BaseTemplate contains common fields shared by all template types.
@@ -142,6 +143,12 @@ type TemplateContent struct {
 	//
 	// Required: true
 	EnvVariables []env.Variable `json:"envVariables"`
+
+	// Inputs are the typed inputs declared in the x-arcane.inputs block of
+	// the compose file.
+	//
+	// Required: false
+	Inputs []project.Input `json:"inputs,omitempty"`
 }
 
 // Template represents a Docker Compose template.
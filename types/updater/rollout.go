@@ -0,0 +1,108 @@
+package updater
+
+import "time"
+
+// Rollout statuses.
+const (
+	RolloutStatusSoaking    = "soaking"
+	RolloutStatusRollingOut = "rolling_out"
+	RolloutStatusCompleted  = "completed"
+	RolloutStatusHalted     = "halted"
+)
+
+// Rollout target statuses.
+const (
+	RolloutTargetUpdated = "updated"
+	RolloutTargetFailed  = "failed"
+)
+
+// RolloutTarget is the outcome of a rollout on one environment after the
+// canary.
+type RolloutTarget struct {
+	// EnvironmentID is the ID of the environment.
+	//
+	// Required: true
+	EnvironmentID string `json:"environmentId"`
+
+	// EnvironmentName is the name of the environment.
+	//
+	// Required: false
+	EnvironmentName string `json:"environmentName,omitempty"`
+
+	// Status is "updated" or "failed".
+	//
+	// Required: true
+	Status string `json:"status"`
+
+	// Updated is the number of resources updated.
+	//
+	// Required: true
+	Updated int `json:"updated"`
+
+	// Failed is the number of resources that failed to update.
+	//
+	// Required: true
+	Failed int `json:"failed"`
+
+	// Error contains the error that stopped the update, if any.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+}
+
+// Rollout is a staged auto-update: the canary environment is updated first
+// and the same image updates reach the other environments once its updated
+// containers have stayed healthy for the soak period.
+type Rollout struct {
+	// ID is the unique identifier of the rollout.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Status is "soaking", "rolling_out", "completed" or "halted".
+	//
+	// Required: true
+	Status string `json:"status"`
+
+	// CanaryEnvironmentID is the environment updated first.
+	//
+	// Required: true
+	CanaryEnvironmentID string `json:"canaryEnvironmentId"`
+
+	// Images are the image references the canary updated, as they were
+	// before the update.
+	//
+	// Required: true
+	Images []string `json:"images"`
+
+	// CanaryContainers are the canary containers whose health is watched
+	// during the soak period.
+	//
+	// Required: true
+	CanaryContainers []string `json:"canaryContainers"`
+
+	// SoakUntil is when the soak period ends.
+	//
+	// Required: false
+	SoakUntil *time.Time `json:"soakUntil,omitempty"`
+
+	// Targets are the outcomes on the other environments.
+	//
+	// Required: false
+	Targets []RolloutTarget `json:"targets,omitempty"`
+
+	// Error explains why the rollout halted.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+
+	// StartedAt is when the canary was updated.
+	//
+	// Required: true
+	StartedAt time.Time `json:"startedAt"`
+
+	// CompletedAt is when the rollout completed or halted.
+	//
+	// Required: false
+	CompletedAt *time.Time `json:"completedAt,omitempty"`
+}
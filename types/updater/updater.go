@@ -12,6 +12,9 @@ type Options struct {
 
 	// DryRun performs a dry run without applying updates
 	DryRun bool `json:"dryRun,omitempty"`
+
+	// Images limits updates to pending updates of these image references
+	Images []string `json:"images,omitempty"`
 }
 
 // ResourceResult represents the result of an update operation on a single resource.
@@ -0,0 +1,26 @@
+package uptime
+
+// Stats are the uptime percentages of a container or project. A percentage
+// only covers the part of its window that Arcane was tracking the resource,
+// and is omitted when there is no tracked time in the window.
+type Stats struct {
+	// Up is the last recorded state.
+	//
+	// Required: true
+	Up bool `json:"up"`
+
+	// Last24h is the uptime percentage over the last 24 hours.
+	//
+	// Required: false
+	Last24h *float64 `json:"last24h,omitempty"`
+
+	// Last7d is the uptime percentage over the last 7 days.
+	//
+	// Required: false
+	Last7d *float64 `json:"last7d,omitempty"`
+
+	// Last30d is the uptime percentage over the last 30 days.
+	//
+	// Required: false
+	Last30d *float64 `json:"last30d,omitempty"`
+}
@@ -4,5 +4,31 @@ type BackupEntry struct {
 	ID         string `json:"id" doc:"Unique identifier of the backup"`
 	VolumeName string `json:"volumeName" doc:"Name of the volume"`
 	Size       int64  `json:"size" doc:"Size of the backup archive in bytes"`
+	Driver     string `json:"driver" doc:"How the backup was taken: tar, zfs or btrfs"`
 	CreatedAt  string `json:"createdAt" doc:"When the backup was created"`
 }
+
+// SkippedBackupArchive is an archive found in the backup volume that could
+// not be adopted.
+type SkippedBackupArchive struct {
+	Filename string `json:"filename" doc:"Name of the archive in the backup volume"`
+	Reason   string `json:"reason" doc:"Why the archive was not adopted"`
+}
+
+// BackupAdoptResult is the outcome of scanning the backup volume for archives
+// without a backup record.
+type BackupAdoptResult struct {
+	Adopted []BackupEntry          `json:"adopted" doc:"Backups recorded for previously unknown archives"`
+	Skipped []SkippedBackupArchive `json:"skipped" doc:"Unknown archives that could not be adopted"`
+}
+
+// RestoreResult is the outcome of a full volume restore. The backup is
+// extracted and verified in a staging directory inside the volume before the
+// current contents are swapped out, so a failed restore leaves the volume as
+// it was unless the swap itself could not be undone.
+type RestoreResult struct {
+	VolumeName         string   `json:"volumeName" doc:"Name of the restored volume"`
+	BackupID           string   `json:"backupId,omitempty" doc:"Backup that was restored; empty for uploaded archives"`
+	PreRestoreBackupID string   `json:"preRestoreBackupId" doc:"Backup of the previous contents taken before the restore"`
+	StoppedContainers  []string `json:"stoppedContainers,omitempty" doc:"Containers stopped for the restore and started again"`
+}
@@ -0,0 +1,38 @@
+package volume
+
+import "time"
+
+// Backup progress operations.
+const (
+	BackupOperationCreate  = "backup"
+	BackupOperationRestore = "restore"
+)
+
+// Backup progress phases.
+const (
+	BackupPhaseStarting   = "starting"
+	BackupPhaseScanning   = "scanning"
+	BackupPhaseArchiving  = "archiving"
+	BackupPhaseUploading  = "uploading"
+	BackupPhaseVerifying  = "verifying"
+	BackupPhaseExtracting = "extracting"
+	BackupPhaseReplacing  = "replacing"
+	BackupPhaseCompleted  = "completed"
+	BackupPhaseFailed     = "failed"
+)
+
+// BackupProgress is broadcast over WebSocket while a backup or restore runs.
+type BackupProgress struct {
+	OperationID     string    `json:"operationId" doc:"Identifier shared by all events of one backup or restore"`
+	Operation       string    `json:"operation" doc:"backup or restore"`
+	VolumeName      string    `json:"volumeName" doc:"Name of the volume"`
+	BackupID        string    `json:"backupId,omitempty" doc:"Backup being created or restored, when known"`
+	Phase           string    `json:"phase" doc:"Current phase of the operation"`
+	BytesProcessed  int64     `json:"bytesProcessed" doc:"Bytes read so far"`
+	TotalBytes      int64     `json:"totalBytes" doc:"Expected total bytes, 0 when unknown"`
+	FilesWritten    int64     `json:"filesWritten" doc:"Archive entries processed so far"`
+	TotalFiles      int64     `json:"totalFiles" doc:"Expected number of archive entries, 0 when unknown"`
+	Error           string    `json:"error,omitempty" doc:"Error message when the operation failed"`
+	VolumeUnchanged bool      `json:"volumeUnchanged,omitempty" doc:"Set when a restore failed without changing the volume"`
+	Timestamp       time.Time `json:"timestamp" doc:"When the event was emitted"`
+}
@@ -19,3 +19,83 @@ type FileMetadata struct {
 	IsText   bool   `json:"isText" doc:"Whether the file is a text file"`
 	IsBinary bool   `json:"isBinary" doc:"Whether the file is a binary file"`
 }
+
+// FileContent is the leading part of a file together with metadata that helps
+// the browser choose a safe way to display it.
+type FileContent struct {
+	Content   []byte `json:"content" doc:"File content, truncated to the requested maximum"`
+	MimeType  string `json:"mimeType" doc:"Detected MIME type"`
+	Language  string `json:"language,omitempty" doc:"Detected language for syntax highlighting"`
+	LineCount int    `json:"lineCount" doc:"Number of lines in the returned content"`
+	IsBinary  bool   `json:"isBinary" doc:"Whether the content looks binary"`
+	Size      int64  `json:"size" doc:"Full size of the file in bytes"`
+	Offset    int64  `json:"offset" doc:"Byte offset of the returned content within the file"`
+	Truncated bool   `json:"truncated" doc:"Whether the content covers only part of the file"`
+	Viewer    string `json:"viewer,omitempty" doc:"Server-side viewer available for the file (sqlite or archive)"`
+}
+
+// FileContentOptions selects which part of a file GetFileContent returns.
+type FileContentOptions struct {
+	// MaxBytes is the maximum number of bytes to return.
+	MaxBytes int64
+	// Offset is the byte offset to start reading from. Ignored in tail mode.
+	Offset int64
+	// Tail returns the last MaxBytes of the file instead of reading from Offset.
+	Tail bool
+}
+
+// ArchiveEntry is a file inside a tar or zip archive.
+type ArchiveEntry struct {
+	Name        string    `json:"name" doc:"Path of the entry inside the archive"`
+	Size        int64     `json:"size" doc:"Uncompressed size in bytes"`
+	IsDirectory bool      `json:"isDirectory" doc:"Whether the entry is a directory"`
+	ModTime     time.Time `json:"modTime" doc:"Modification time recorded in the archive"`
+}
+
+// SqliteTable describes a table or view in a SQLite database.
+type SqliteTable struct {
+	Name     string   `json:"name" doc:"Table name"`
+	Type     string   `json:"type" doc:"Either table or view"`
+	Columns  []string `json:"columns" doc:"Column names"`
+	RowCount int64    `json:"rowCount" doc:"Number of rows, or -1 if it could not be counted"`
+}
+
+// FilePreview is the server-side rendering of a file that cannot be shown as text.
+type FilePreview struct {
+	Viewer    string         `json:"viewer" doc:"Viewer used to render the file (sqlite or archive)"`
+	Entries   []ArchiveEntry `json:"entries,omitempty" doc:"Archive entries"`
+	Tables    []SqliteTable  `json:"tables,omitempty" doc:"SQLite tables and views"`
+	Truncated bool           `json:"truncated" doc:"Whether the listing was cut short"`
+}
+
+type CopyPathRequest struct {
+	Path         string `json:"path" minLength:"1" doc:"Source file or directory path in the volume"`
+	TargetVolume string `json:"targetVolume,omitempty" doc:"Destination volume (defaults to the source volume)"`
+	TargetPath   string `json:"targetPath" minLength:"1" doc:"Destination path, including the new file or directory name"`
+	Overwrite    bool   `json:"overwrite,omitempty" doc:"Overwrite the destination if it already exists"`
+}
+
+// ChangeOwnershipRequest sets the owner and/or group of a file or directory.
+type ChangeOwnershipRequest struct {
+	Path      string `json:"path" minLength:"1" doc:"File or directory path in the volume"`
+	UID       *int   `json:"uid,omitempty" minimum:"0" doc:"New owner user ID; unchanged if omitted"`
+	GID       *int   `json:"gid,omitempty" minimum:"0" doc:"New group ID; unchanged if omitted"`
+	Recursive bool   `json:"recursive,omitempty" doc:"Apply to all files below a directory"`
+}
+
+// ChangePermissionsRequest sets the mode of a file or directory.
+type ChangePermissionsRequest struct {
+	Path      string `json:"path" minLength:"1" doc:"File or directory path in the volume"`
+	Mode      string `json:"mode" pattern:"^[0-7]{3,4}$" doc:"Octal permission bits, e.g. 644 or 0755"`
+	Recursive bool   `json:"recursive,omitempty" doc:"Apply to all files below a directory"`
+}
+
+// DiskUsageEntry is a file or directory with its disk usage. Directories
+// include their children up to the requested depth, largest first.
+type DiskUsageEntry struct {
+	Name        string           `json:"name" doc:"Name of the file or directory"`
+	Path        string           `json:"path" doc:"Path relative to the volume root"`
+	Size        int64            `json:"size" doc:"Disk usage in bytes, including all descendants"`
+	IsDirectory bool             `json:"isDirectory" doc:"Whether this entry is a directory"`
+	Children    []DiskUsageEntry `json:"children,omitempty" doc:"Entries inside this directory, largest first"`
+}
@@ -0,0 +1,84 @@
+package volume
+
+// BulkAction is an operation that can be applied to multiple volumes at once.
+type BulkAction string
+
+const (
+	BulkActionDelete BulkAction = "delete"
+	BulkActionBackup BulkAction = "backup"
+	BulkActionLabel  BulkAction = "label"
+)
+
+// BulkRequest is used to apply a single action to multiple volumes.
+type BulkRequest struct {
+	// Action to apply to every volume in Names.
+	//
+	// Required: true
+	Action BulkAction `json:"action" enum:"delete,backup,label" doc:"Action to apply (delete, backup, label)"`
+
+	// Names of the volumes to operate on.
+	//
+	// Required: true
+	Names []string `json:"names" minItems:"1" doc:"Names of the volumes to operate on"`
+
+	// Force removal of volumes that are in use. Only used by the delete action.
+	//
+	// Required: false
+	Force bool `json:"force,omitempty" doc:"Force removal (delete action only)"`
+
+	// Labels to add or overwrite. Only used by the label action.
+	//
+	// Required: false
+	Labels map[string]string `json:"labels,omitempty" doc:"Labels to add or overwrite (label action only)"`
+
+	// RemoveLabels lists label keys to remove. Only used by the label action.
+	//
+	// Required: false
+	RemoveLabels []string `json:"removeLabels,omitempty" doc:"Label keys to remove (label action only)"`
+}
+
+// BulkItemResult is the outcome of a bulk action for a single volume.
+type BulkItemResult struct {
+	// Name of the volume.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Success indicates whether the action succeeded for this volume.
+	//
+	// Required: true
+	Success bool `json:"success"`
+
+	// Error contains the failure reason when Success is false.
+	//
+	// Required: false
+	Error string `json:"error,omitempty"`
+
+	// BackupID is the ID of the created backup. Only set by the backup action.
+	//
+	// Required: false
+	BackupID string `json:"backupId,omitempty"`
+}
+
+// BulkResult is the result of a bulk volume operation.
+type BulkResult struct {
+	// Action that was applied.
+	//
+	// Required: true
+	Action BulkAction `json:"action"`
+
+	// Succeeded is the number of volumes the action succeeded for.
+	//
+	// Required: true
+	Succeeded int `json:"succeeded"`
+
+	// Failed is the number of volumes the action failed for.
+	//
+	// Required: true
+	Failed int `json:"failed"`
+
+	// Results contains the per-volume outcome, in request order.
+	//
+	// Required: true
+	Results []BulkItemResult `json:"results"`
+}
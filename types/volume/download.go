@@ -0,0 +1,19 @@
+package volume
+
+import "time"
+
+// BackupDownloadTokenRequest asks for a download link for a backup.
+type BackupDownloadTokenRequest struct {
+	EnvironmentID string `json:"environmentId" minLength:"1" doc:"Environment that holds the backup"`
+	BackupID      string `json:"backupId" minLength:"1" doc:"ID of the backup"`
+}
+
+// BackupDownloadToken is a short-lived link that downloads a backup without
+// other credentials. Downloads through it can be resumed with Range requests,
+// so download managers and tools such as curl -C can pick up where they left
+// off.
+type BackupDownloadToken struct {
+	Token     string    `json:"token" doc:"Opaque download token"`
+	URL       string    `json:"url" doc:"API path that downloads the backup, relative to the API base"`
+	ExpiresAt time.Time `json:"expiresAt" doc:"When the link stops working"`
+}
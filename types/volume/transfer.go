@@ -0,0 +1,10 @@
+package volume
+
+// CrossEnvironmentRestoreRequest restores a backup taken on one environment
+// into a volume on another environment.
+type CrossEnvironmentRestoreRequest struct {
+	SourceEnvironmentID string `json:"sourceEnvironmentId" minLength:"1" doc:"Environment that holds the backup"`
+	BackupID            string `json:"backupId" minLength:"1" doc:"ID of the backup on the source environment"`
+	TargetEnvironmentID string `json:"targetEnvironmentId" minLength:"1" doc:"Environment to restore the backup into"`
+	TargetVolume        string `json:"targetVolume" minLength:"1" doc:"Name of the volume on the target environment"`
+}
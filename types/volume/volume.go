@@ -144,3 +144,77 @@ func NewSummary(v volume.Volume) Volume {
 
 	return dto
 }
+
+// Clone is used to create a new volume with a copy of an existing volume's data.
+type Clone struct {
+	// Name of the new volume.
+	//
+	// Required: true
+	Name string `json:"name" minLength:"1" doc:"Name of the new volume"`
+
+	// PreserveDriverOptions creates the new volume with the source volume's
+	// driver and driver options. Otherwise the local driver is used.
+	//
+	// Required: false
+	PreserveDriverOptions bool `json:"preserveDriverOptions,omitempty" doc:"Use the source volume's driver and driver options"`
+
+	// PreserveLabels copies the source volume's labels to the new volume.
+	//
+	// Required: false
+	PreserveLabels bool `json:"preserveLabels,omitempty" doc:"Copy the source volume's labels"`
+
+	// Labels to add to the new volume. These override preserved labels.
+	//
+	// Required: false
+	Labels map[string]string `json:"labels,omitempty" doc:"Additional labels for the new volume"`
+}
+
+// Migrate is used to move a volume's data to a volume with a different name
+// and/or driver.
+type Migrate struct {
+	// Name of the target volume. When empty or equal to the source name the
+	// volume is recreated in place with the new driver.
+	//
+	// Required: false
+	Name string `json:"name,omitempty" doc:"Name of the target volume (defaults to the source name)"`
+
+	// Driver for the target volume. When empty the source driver is used.
+	//
+	// Required: false
+	Driver string `json:"driver,omitempty" doc:"Driver for the target volume (defaults to the source driver)"`
+
+	// DriverOpts for the target volume. When nil the source options are used
+	// if the driver is unchanged.
+	//
+	// Required: false
+	DriverOpts map[string]string `json:"driverOpts,omitempty" doc:"Driver options for the target volume"`
+
+	// RepointContainers recreates stopped containers that use the source
+	// volume so they mount the target volume instead.
+	//
+	// Required: false
+	RepointContainers bool `json:"repointContainers,omitempty" doc:"Recreate stopped containers to use the new volume"`
+
+	// DeleteSource removes the source volume after a successful migration.
+	//
+	// Required: false
+	DeleteSource bool `json:"deleteSource,omitempty" doc:"Delete the source volume after migrating"`
+}
+
+// MigrateResult is the result of a volume migration.
+type MigrateResult struct {
+	// Volume is the target volume.
+	//
+	// Required: true
+	Volume Volume `json:"volume"`
+
+	// RepointedContainers lists the containers that now use the target volume.
+	//
+	// Required: true
+	RepointedContainers []string `json:"repointedContainers"`
+
+	// SourceDeleted indicates whether the source volume was removed.
+	//
+	// Required: true
+	SourceDeleted bool `json:"sourceDeleted"`
+}
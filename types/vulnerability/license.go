@@ -0,0 +1,139 @@
+package vulnerability
+
+import "time"
+
+// TrivyLicense represents a license detected by Trivy's license scanner
+type TrivyLicense struct {
+	Severity   string  `json:"Severity"`
+	Category   string  `json:"Category"`
+	PkgName    string  `json:"PkgName"`
+	FilePath   string  `json:"FilePath"`
+	Name       string  `json:"Name"`
+	Confidence float64 `json:"Confidence"`
+	Link       string  `json:"Link"`
+}
+
+// License is a license of a package found in an image
+type License struct {
+	// Name is the license name or SPDX identifier (e.g., AGPL-3.0-only)
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Category is Trivy's classification of the license (e.g., restricted, notice)
+	//
+	// Required: false
+	Category string `json:"category,omitempty"`
+
+	// PkgName is the package the license applies to (empty for license files)
+	//
+	// Required: false
+	PkgName string `json:"pkgName,omitempty"`
+
+	// FilePath is the file the license was found in (empty for OS packages)
+	//
+	// Required: false
+	FilePath string `json:"filePath,omitempty"`
+}
+
+// LicenseUsage is a license found in an image and the packages using it
+type LicenseUsage struct {
+	// Name is the license name or SPDX identifier
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Category is Trivy's classification of the license
+	//
+	// Required: false
+	Category string `json:"category,omitempty"`
+
+	// Denied reports whether the license matches the license denylist
+	//
+	// Required: true
+	Denied bool `json:"denied"`
+
+	// Packages are the packages using the license
+	//
+	// Required: true
+	Packages []string `json:"packages"`
+}
+
+// ImageLicenseReport lists the licenses found in an image
+type ImageLicenseReport struct {
+	// ImageID is the Docker image ID
+	//
+	// Required: true
+	ImageID string `json:"imageId"`
+
+	// ImageName is the image name with tag (e.g., nginx:latest)
+	//
+	// Required: true
+	ImageName string `json:"imageName"`
+
+	// ScanTime is when the licenses were recorded
+	//
+	// Required: true
+	ScanTime time.Time `json:"scanTime"`
+
+	// Licenses are the licenses found, denied licenses first
+	//
+	// Required: true
+	Licenses []LicenseUsage `json:"licenses"`
+
+	// DeniedCount is the number of denied licenses found
+	//
+	// Required: true
+	DeniedCount int `json:"deniedCount"`
+}
+
+// LicenseSummary is a license found in the images of an environment
+type LicenseSummary struct {
+	// Name is the license name or SPDX identifier
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Category is Trivy's classification of the license
+	//
+	// Required: false
+	Category string `json:"category,omitempty"`
+
+	// Denied reports whether the license matches the license denylist
+	//
+	// Required: true
+	Denied bool `json:"denied"`
+
+	// Images are the names of the images using the license
+	//
+	// Required: true
+	Images []string `json:"images"`
+
+	// PackageCount is the number of packages using the license across all images
+	//
+	// Required: true
+	PackageCount int `json:"packageCount"`
+}
+
+// EnvironmentLicenseReport lists the licenses found in all scanned images
+type EnvironmentLicenseReport struct {
+	// ScannedImages is the number of images with recorded licenses
+	//
+	// Required: true
+	ScannedImages int `json:"scannedImages"`
+
+	// DeniedImages is the number of images using a denied license
+	//
+	// Required: true
+	DeniedImages int `json:"deniedImages"`
+
+	// Denylist is the configured license denylist
+	//
+	// Required: true
+	Denylist []string `json:"denylist"`
+
+	// Licenses are the licenses found, denied licenses first
+	//
+	// Required: true
+	Licenses []LicenseSummary `json:"licenses"`
+}
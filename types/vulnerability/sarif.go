@@ -0,0 +1,107 @@
+package vulnerability
+
+// SARIFVersion is the SARIF version of exported scan results.
+const SARIFVersion = "2.1.0"
+
+// SARIFSchema is the JSON schema of SARIFVersion.
+const SARIFSchema = "https://json.schemastore.org/sarif-2.1.0.json"
+
+// SARIFLog is a SARIF 2.1.0 log, the format accepted by GitHub code scanning
+// and most other static analysis tooling.
+type SARIFLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []SARIFRun `json:"runs"`
+}
+
+// SARIFRun is a single run of a tool.
+type SARIFRun struct {
+	Tool               SARIFTool                   `json:"tool"`
+	Results            []SARIFResult               `json:"results"`
+	ColumnKind         string                      `json:"columnKind,omitempty"`
+	OriginalURIBaseIDs map[string]SARIFArtifactURI `json:"originalUriBaseIds,omitempty"`
+	Properties         map[string]any              `json:"properties,omitempty"`
+}
+
+// SARIFTool describes the tool that produced a run.
+type SARIFTool struct {
+	Driver SARIFDriver `json:"driver"`
+}
+
+// SARIFDriver is the component of a tool that holds the rules.
+type SARIFDriver struct {
+	Name           string      `json:"name"`
+	InformationURI string      `json:"informationUri,omitempty"`
+	Version        string      `json:"version,omitempty"`
+	Rules          []SARIFRule `json:"rules"`
+}
+
+// SARIFRule describes one vulnerability reported by the run.
+type SARIFRule struct {
+	ID                   string              `json:"id"`
+	Name                 string              `json:"name,omitempty"`
+	ShortDescription     SARIFMessage        `json:"shortDescription"`
+	FullDescription      SARIFMessage        `json:"fullDescription"`
+	HelpURI              string              `json:"helpUri,omitempty"`
+	Help                 SARIFMessage        `json:"help"`
+	DefaultConfiguration SARIFConfiguration  `json:"defaultConfiguration"`
+	Properties           SARIFRuleProperties `json:"properties"`
+}
+
+// SARIFConfiguration is the default configuration of a rule.
+type SARIFConfiguration struct {
+	Level string `json:"level"`
+}
+
+// SARIFRuleProperties holds the rule properties GitHub code scanning reads.
+type SARIFRuleProperties struct {
+	Tags             []string `json:"tags"`
+	Precision        string   `json:"precision"`
+	SecuritySeverity string   `json:"security-severity"`
+}
+
+// SARIFMessage is a plain text message with an optional Markdown variant.
+type SARIFMessage struct {
+	Text     string `json:"text"`
+	Markdown string `json:"markdown,omitempty"`
+}
+
+// SARIFResult is a single finding: one vulnerability in one package.
+type SARIFResult struct {
+	RuleID    string          `json:"ruleId"`
+	RuleIndex int             `json:"ruleIndex"`
+	Level     string          `json:"level"`
+	Message   SARIFMessage    `json:"message"`
+	Locations []SARIFLocation `json:"locations"`
+}
+
+// SARIFLocation is where a finding was made.
+type SARIFLocation struct {
+	PhysicalLocation SARIFPhysicalLocation `json:"physicalLocation"`
+	Message          *SARIFMessage         `json:"message,omitempty"`
+}
+
+// SARIFPhysicalLocation points at a file and a region within it.
+type SARIFPhysicalLocation struct {
+	ArtifactLocation SARIFArtifactLocation `json:"artifactLocation"`
+	Region           SARIFRegion           `json:"region"`
+}
+
+// SARIFArtifactLocation is the URI of a file, relative to a base URI.
+type SARIFArtifactLocation struct {
+	URI       string `json:"uri"`
+	URIBaseID string `json:"uriBaseId,omitempty"`
+}
+
+// SARIFArtifactURI is an absolute URI that relative locations resolve against.
+type SARIFArtifactURI struct {
+	URI string `json:"uri"`
+}
+
+// SARIFRegion is a line range within a file.
+type SARIFRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+	EndLine     int `json:"endLine"`
+	EndColumn   int `json:"endColumn"`
+}
@@ -32,6 +32,12 @@ type Vulnerability struct {
 	// Required: true
 	InstalledVersion string `json:"installedVersion"`
 
+	// Location is the path within the image of the file the package was
+	// found in (empty for OS packages)
+	//
+	// Required: false
+	Location string `json:"location,omitempty"`
+
 	// FixedVersion is the version where the vulnerability is fixed (empty if not fixed)
 	//
 	// Required: false
@@ -208,6 +214,10 @@ type ScanResult struct {
 	//
 	// Required: false
 	ScannerVersion string `json:"scannerVersion,omitempty"`
+
+	// Licenses are the package licenses found by the scan. They are stored
+	// separately and served by the license reports.
+	Licenses []License `json:"-"`
 }
 
 // ScanStatus represents the status of a vulnerability scan
@@ -294,6 +304,7 @@ type TrivyResults struct {
 	Class           string               `json:"Class"`
 	Type            string               `json:"Type"`
 	Vulnerabilities []TrivyVulnerability `json:"Vulnerabilities"`
+	Licenses        []TrivyLicense       `json:"Licenses"`
 }
 
 // TrivyVulnerability represents a vulnerability in Trivy output
@@ -301,6 +312,7 @@ type TrivyVulnerability struct {
 	VulnerabilityID  string               `json:"VulnerabilityID"`
 	PkgID            string               `json:"PkgID"`
 	PkgName          string               `json:"PkgName"`
+	PkgPath          string               `json:"PkgPath"`
 	InstalledVersion string               `json:"InstalledVersion"`
 	FixedVersion     string               `json:"FixedVersion"`
 	Status           string               `json:"Status"`
@@ -358,10 +370,21 @@ func ConvertTrivyReportToScanResult(report *TrivyReport, imageID string, scanTim
 		Vulnerabilities: []Vulnerability{},
 	}
 
-	// Collect all vulnerabilities from all results
+	// Collect all vulnerabilities and licenses from all results
 	for _, trivyResult := range report.Results {
+		for _, tl := range trivyResult.Licenses {
+			result.Licenses = append(result.Licenses, License{
+				Name:     tl.Name,
+				Category: tl.Category,
+				PkgName:  tl.PkgName,
+				FilePath: tl.FilePath,
+			})
+		}
 		for _, tv := range trivyResult.Vulnerabilities {
 			vuln := convertTrivyVulnerability(&tv)
+			if vuln.Location == "" && trivyResult.Class != "os-pkgs" {
+				vuln.Location = trivyResult.Target
+			}
 			result.Vulnerabilities = append(result.Vulnerabilities, vuln)
 
 			// Update severity summary
@@ -391,6 +414,7 @@ func convertTrivyVulnerability(tv *TrivyVulnerability) Vulnerability {
 		VulnerabilityID:  tv.VulnerabilityID,
 		PkgName:          tv.PkgName,
 		InstalledVersion: tv.InstalledVersion,
+		Location:         tv.PkgPath,
 		FixedVersion:     tv.FixedVersion,
 		Severity:         parseSeverity(tv.Severity),
 		Title:            tv.Title,
@@ -446,22 +470,52 @@ func parseSeverity(s string) Severity {
 	}
 }
 
+// IgnoreScope controls which findings an ignore rule matches.
+type IgnoreScope string
+
+const (
+	// IgnoreScopeExact matches one finding: a vulnerability in a package
+	// version of a single image.
+	IgnoreScopeExact IgnoreScope = "exact"
+	// IgnoreScopeCVE matches a vulnerability in every image and package.
+	IgnoreScopeCVE IgnoreScope = "cve"
+	// IgnoreScopePackage matches every vulnerability of a package in every
+	// image.
+	IgnoreScopePackage IgnoreScope = "package"
+)
+
+// IgnoreAuditAction is the change recorded by an ignore audit entry.
+type IgnoreAuditAction string
+
+const (
+	IgnoreAuditActionCreated IgnoreAuditAction = "created"
+	IgnoreAuditActionRemoved IgnoreAuditAction = "removed"
+	IgnoreAuditActionExpired IgnoreAuditAction = "expired"
+)
+
 // IgnorePayload represents the request to ignore a vulnerability
 type IgnorePayload struct {
-	// ImageID is the Docker image ID
+	// Scope is the kind of rule: exact (default), cve or package
 	//
-	// Required: true
-	ImageID string `json:"imageId" example:"sha256:abc123"`
+	// Required: false
+	Scope IgnoreScope `json:"scope,omitempty" enum:"exact,cve,package" example:"exact"`
 
-	// VulnerabilityID is the CVE or vulnerability identifier
+	// ImageID is the Docker image ID, required for exact rules
 	//
-	// Required: true
-	VulnerabilityID string `json:"vulnerabilityId" example:"CVE-2023-1234"`
+	// Required: false
+	ImageID string `json:"imageId,omitempty" example:"sha256:abc123"`
 
-	// PkgName is the package name containing the vulnerability
+	// VulnerabilityID is the CVE or vulnerability identifier, required for
+	// exact and cve rules
 	//
-	// Required: true
-	PkgName string `json:"pkgName" example:"openssl"`
+	// Required: false
+	VulnerabilityID string `json:"vulnerabilityId,omitempty" example:"CVE-2023-1234"`
+
+	// PkgName is the package name containing the vulnerability, required for
+	// exact and package rules
+	//
+	// Required: false
+	PkgName string `json:"pkgName,omitempty" example:"openssl"`
 
 	// InstalledVersion is the version of the package with the vulnerability
 	//
@@ -473,6 +527,11 @@ type IgnorePayload struct {
 	// Required: false
 	Reason *string `json:"reason,omitempty" example:"False positive - not exploitable"`
 
+	// ExpiresInDays removes the rule automatically after this many days
+	//
+	// Required: false
+	ExpiresInDays *int `json:"expiresInDays,omitempty" minimum:"1" maximum:"3650" example:"30"`
+
 	// CreatedBy is the user ID who created this ignore record (set by server from auth; do not send from client)
 	//
 	// Required: false
@@ -487,7 +546,10 @@ type IgnoredVulnerability struct {
 	// EnvironmentID is the environment where this ignore applies
 	EnvironmentID string `json:"environmentId"`
 
-	// ImageID is the Docker image ID
+	// Scope is the kind of rule: exact, cve or package
+	Scope IgnoreScope `json:"scope"`
+
+	// ImageID is the Docker image ID, empty for cve and package rules
 	ImageID string `json:"imageId"`
 
 	// VulnerabilityID is the CVE or vulnerability identifier
@@ -507,4 +569,53 @@ type IgnoredVulnerability struct {
 
 	// CreatedAt is when this ignore record was created
 	CreatedAt time.Time `json:"createdAt"`
+
+	// ExpiresAt is when the rule is removed automatically, if ever
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+}
+
+// IgnoreAuditEntry records who created or removed an ignore rule, or when it
+// expired
+type IgnoreAuditEntry struct {
+	// ID is the unique identifier for this audit entry
+	ID string `json:"id"`
+
+	// IgnoreID is the ignore rule the entry is about
+	IgnoreID string `json:"ignoreId"`
+
+	// EnvironmentID is the environment the rule applies to
+	EnvironmentID string `json:"environmentId"`
+
+	// Action is created, removed or expired
+	Action IgnoreAuditAction `json:"action"`
+
+	// Scope is the kind of rule: exact, cve or package
+	Scope IgnoreScope `json:"scope"`
+
+	// ImageID is the Docker image ID, empty for cve and package rules
+	ImageID string `json:"imageId"`
+
+	// VulnerabilityID is the CVE or vulnerability identifier
+	VulnerabilityID string `json:"vulnerabilityId"`
+
+	// PkgName is the package name
+	PkgName string `json:"pkgName"`
+
+	// InstalledVersion is the package version
+	InstalledVersion string `json:"installedVersion"`
+
+	// Reason is the reason the rule was created
+	Reason *string `json:"reason,omitempty"`
+
+	// ExpiresAt is when the rule was set to expire
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+
+	// UserID is the user who made the change, empty for expiry
+	UserID string `json:"userId"`
+
+	// Username is the name of the user who made the change
+	Username string `json:"username"`
+
+	// CreatedAt is when the change happened
+	CreatedAt time.Time `json:"createdAt"`
 }
@@ -0,0 +1,152 @@
+package webhook
+
+import "time"
+
+// Request is the request body for creating or replacing a webhook source.
+type Request struct {
+	// Name is a display name for the source.
+	//
+	// Required: true
+	Name string `json:"name" minLength:"1" maxLength:"255"`
+
+	// Type selects how incoming payloads are read: harbor for Harbor
+	// registry webhooks, github for GitHub package (GHCR) and workflow run
+	// webhooks, and generic for any other system.
+	//
+	// Required: true
+	Type string `json:"type" enum:"generic,harbor,github"`
+
+	// Action is the rule run when a push or a successful build is received.
+	// none only records the event, redeploy pulls and redeploys ProjectID.
+	// Defaults to none.
+	//
+	// Required: false
+	Action string `json:"action,omitempty" enum:"none,redeploy"`
+
+	// ProjectID is the project the rule acts on. Required when Action is
+	// redeploy.
+	//
+	// Required: false
+	ProjectID string `json:"projectId,omitempty"`
+
+	// ImageFilter limits the rule to events for matching images, for
+	// example ghcr.io/acme/shop or harbor.example.com/team/*. An empty filter
+	// matches every event.
+	//
+	// Required: false
+	ImageFilter string `json:"imageFilter,omitempty" maxLength:"512"`
+
+	// Enabled controls whether events are accepted. Defaults to true.
+	//
+	// Required: false
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// Source describes a webhook source.
+type Source struct {
+	// ID is the unique identifier of the source.
+	//
+	// Required: true
+	ID string `json:"id"`
+
+	// Name is the display name of the source.
+	//
+	// Required: true
+	Name string `json:"name"`
+
+	// Type is generic, harbor or github.
+	//
+	// Required: true
+	Type string `json:"type"`
+
+	// TokenPrefix is the first characters of the token, to tell tokens
+	// apart.
+	//
+	// Required: true
+	TokenPrefix string `json:"tokenPrefix"`
+
+	// Action is the rule run for matching events: none or redeploy.
+	//
+	// Required: true
+	Action string `json:"action"`
+
+	// ProjectID is the project the rule acts on.
+	//
+	// Required: false
+	ProjectID *string `json:"projectId,omitempty"`
+
+	// ImageFilter limits the rule to events for matching images.
+	//
+	// Required: false
+	ImageFilter string `json:"imageFilter,omitempty"`
+
+	// Enabled reports whether events are accepted.
+	//
+	// Required: true
+	Enabled bool `json:"enabled"`
+
+	// LastEvent is the kind of the last event received.
+	//
+	// Required: false
+	LastEvent string `json:"lastEvent,omitempty"`
+
+	// LastReceivedAt is when the last event was received.
+	//
+	// Required: false
+	LastReceivedAt *time.Time `json:"lastReceivedAt,omitempty"`
+
+	// UpdatedBy is the username that last changed the source.
+	//
+	// Required: true
+	UpdatedBy string `json:"updatedBy"`
+
+	// CreatedAt is when the source was created.
+	//
+	// Required: true
+	CreatedAt time.Time `json:"createdAt"`
+
+	// UpdatedAt is when the source was last changed.
+	//
+	// Required: false
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
+}
+
+// SourceWithToken is a webhook source together with its token. The token is
+// only returned when the source is created or its token is rotated.
+type SourceWithToken struct {
+	Source
+
+	// Token authenticates the source. Post events to /api/webhooks/{token}.
+	//
+	// Required: true
+	Token string `json:"token"`
+}
+
+// IngestResult describes how an incoming webhook event was handled.
+type IngestResult struct {
+	// Event is the kind of event that was received: push, build or other.
+	//
+	// Required: true
+	Event string `json:"event"`
+
+	// Image is the image reference named by the event, if any.
+	//
+	// Required: false
+	Image string `json:"image,omitempty"`
+
+	// UpdateCheck reports whether local copies of the pushed image are being
+	// checked for updates.
+	//
+	// Required: true
+	UpdateCheck bool `json:"updateCheck"`
+
+	// Triggered reports whether the source's rule was started.
+	//
+	// Required: true
+	Triggered bool `json:"triggered"`
+
+	// Action is the rule that was started.
+	//
+	// Required: false
+	Action string `json:"action,omitempty"`
+}